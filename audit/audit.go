@@ -0,0 +1,39 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	auditRepository "github.com/levensspel/go-gin-template/repository/audit"
+)
+
+// Record mencatat satu perubahan. repo harus terikat ke transaksi
+// perubahannya (lihat repository.UnitOfWork), sehingga audit log ikut
+// di-rollback jika perubahan gagal. Request id diambil dari context gin.
+func Record(
+	ctx context.Context,
+	repo auditRepository.AuditRepositoryInterface,
+	actorId, action, entityType, entityId string,
+	before, after any,
+) error {
+	changes, err := Diff(before, after)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	requestId, _ := ctx.Value(helper.ContextKeyRequestID).(string)
+	return repo.Add(ctx, entity.AuditLog{
+		ActorId:    actorId,
+		Action:     action,
+		EntityType: entityType,
+		EntityId:   entityId,
+		Changes:    data,
+		RequestId:  requestId,
+	})
+}
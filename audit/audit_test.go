@@ -0,0 +1,69 @@
+package audit_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/levensspel/go-gin-template/audit"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/mocks"
+)
+
+func TestRecord(t *testing.T) {
+	var logs []entity.AuditLog
+	repo := &mocks.AuditRepository{AddFunc: func(ctx context.Context, log entity.AuditLog) error {
+		logs = append(logs, log)
+		return nil
+	}}
+	ctx := context.WithValue(context.Background(), helper.ContextKeyRequestID, "req-1")
+
+	err := audit.Record(ctx, repo, "manager-1", entity.AuditActionUpdate, entity.AuditEntityEmployee, "EMP-1",
+		map[string]any{"name": "Budi", "gender": "male"},
+		map[string]any{"name": "Budi Santoso", "gender": "male"},
+	)
+	if err != nil {
+		t.Fatalf("Record error = %v", err)
+	}
+	if len(logs) != 1 {
+		t.Fatalf("Record wrote %d audit logs, want 1", len(logs))
+	}
+	log := logs[0]
+	if log.ActorId != "manager-1" || log.Action != entity.AuditActionUpdate || log.EntityType != entity.AuditEntityEmployee ||
+		log.EntityId != "EMP-1" || log.RequestId != "req-1" {
+		t.Fatalf("audit log = %+v", log)
+	}
+	var changes map[string]dto.AuditChange
+	if err := json.Unmarshal(log.Changes, &changes); err != nil {
+		t.Fatalf("unmarshal changes: %v", err)
+	}
+	if len(changes) != 1 || changes["name"].Before != "Budi" || changes["name"].After != "Budi Santoso" {
+		t.Fatalf("changes = %+v, want only name", changes)
+	}
+}
+
+func TestRecordWithoutRequestID(t *testing.T) {
+	var requestId = "unset"
+	repo := &mocks.AuditRepository{AddFunc: func(ctx context.Context, log entity.AuditLog) error {
+		requestId = log.RequestId
+		return nil
+	}}
+	if err := audit.Record(context.Background(), repo, "manager-1", entity.AuditActionCreate, entity.AuditEntityEmployee, "EMP-1", nil, map[string]any{}); err != nil {
+		t.Fatalf("Record error = %v", err)
+	}
+	if requestId != "" {
+		t.Fatalf("RequestId = %q, want empty", requestId)
+	}
+}
+
+func TestRecordReturnsRepositoryError(t *testing.T) {
+	errWrite := errors.New("write failed")
+	repo := &mocks.AuditRepository{AddFunc: func(ctx context.Context, log entity.AuditLog) error { return errWrite }}
+	err := audit.Record(context.Background(), repo, "manager-1", entity.AuditActionDelete, entity.AuditEntityEmployee, "EMP-1", map[string]any{}, nil)
+	if !errors.Is(err, errWrite) {
+		t.Fatalf("Record error = %v, want %v", err, errWrite)
+	}
+}
@@ -0,0 +1,79 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/levensspel/go-gin-template/dto"
+)
+
+// ignoredFields tidak pernah masuk diff: timestamp berubah di setiap update
+// dan tidak menjelaskan apa yang diubah user.
+var ignoredFields = map[string]bool{
+	"createdat":  true,
+	"updatedat":  true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+// isSensitive menandai field yang tidak boleh tersimpan di audit log sama
+// sekali, termasuk hash-nya.
+func isSensitive(field string) bool {
+	field = strings.ToLower(field)
+	return strings.Contains(field, "password") || strings.Contains(field, "secret") || strings.Contains(field, "token")
+}
+
+// Diff membandingkan representasi JSON before dan after dan mengembalikan
+// field yang berubah saja. before nil berarti create, after nil berarti
+// delete. Field sensitif dan timestamp selalu dilewati.
+func Diff(before, after any) (map[string]dto.AuditChange, error) {
+	beforeFields, err := toFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := toFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]dto.AuditChange)
+	for field, value := range afterFields {
+		if skip(field) {
+			continue
+		}
+		previous, ok := beforeFields[field]
+		if ok && reflect.DeepEqual(previous, value) {
+			continue
+		}
+		changes[field] = dto.AuditChange{Before: previous, After: value}
+	}
+	for field, previous := range beforeFields {
+		if skip(field) {
+			continue
+		}
+		if _, ok := afterFields[field]; !ok {
+			changes[field] = dto.AuditChange{Before: previous}
+		}
+	}
+	return changes, nil
+}
+
+func skip(field string) bool {
+	return ignoredFields[strings.ToLower(field)] || isSensitive(field)
+}
+
+func toFields(value any) (map[string]any, error) {
+	if value == nil || reflect.ValueOf(value).Kind() == reflect.Pointer && reflect.ValueOf(value).IsNil() {
+		return map[string]any{}, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]any{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
@@ -0,0 +1,124 @@
+package audit
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/dto"
+)
+
+type auditedUser struct {
+	Email        string    `json:"email"`
+	Name         *string   `json:"name"`
+	PasswordHash string    `json:"passwordHash"`
+	ApiSecret    string    `json:"apiSecret"`
+	RefreshToken string    `json:"refresh_token"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func TestDiff(t *testing.T) {
+	name := "Budi"
+	renamed := "Budi Santoso"
+	before := auditedUser{
+		Email:        "budi@example.com",
+		Name:         &name,
+		PasswordHash: "$2a$old",
+		ApiSecret:    "old-secret",
+		RefreshToken: "old-token",
+		UpdatedAt:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	after := before
+	after.Name = &renamed
+	after.PasswordHash = "$2a$new"
+	after.ApiSecret = "new-secret"
+	after.RefreshToken = "new-token"
+	after.UpdatedAt = before.UpdatedAt.Add(time.Hour)
+
+	for _, tt := range []struct {
+		name   string
+		before any
+		after  any
+		want   map[string]dto.AuditChange
+	}{
+		{
+			name:   "update keeps only changed fields",
+			before: before,
+			after:  after,
+			want:   map[string]dto.AuditChange{"name": {Before: "Budi", After: "Budi Santoso"}},
+		},
+		{
+			name:   "create",
+			before: nil,
+			after:  before,
+			want: map[string]dto.AuditChange{
+				"email": {After: "budi@example.com"},
+				"name":  {After: "Budi"},
+			},
+		},
+		{
+			name:   "delete",
+			before: &before,
+			after:  (*auditedUser)(nil),
+			want: map[string]dto.AuditChange{
+				"email": {Before: "budi@example.com"},
+				"name":  {Before: "Budi"},
+			},
+		},
+		{
+			name:   "field set to null",
+			before: before,
+			after:  auditedUser{Email: before.Email},
+			want:   map[string]dto.AuditChange{"name": {Before: "Budi"}},
+		},
+		{
+			name:   "no changes",
+			before: before,
+			after:  before,
+			want:   map[string]dto.AuditChange{},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Diff(tt.before, tt.after)
+			if err != nil {
+				t.Fatalf("Diff error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Diff = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffNeverContainsSensitiveFields(t *testing.T) {
+	got, err := Diff(map[string]any{
+		"password":     "old",
+		"Password":     "old",
+		"passwordHash": "old",
+		"clientSecret": "old",
+		"accessToken":  "old",
+		"email":        "old@example.com",
+	}, map[string]any{
+		"password":     "new",
+		"Password":     "new",
+		"passwordHash": "new",
+		"clientSecret": "new",
+		"accessToken":  "new",
+		"email":        "new@example.com",
+	})
+	if err != nil {
+		t.Fatalf("Diff error = %v", err)
+	}
+	if fields := slices.Collect(maps.Keys(got)); !slices.Equal(fields, []string{"email"}) {
+		t.Fatalf("Diff fields = %v, want [email]", fields)
+	}
+}
+
+func TestDiffRejectsNonObjects(t *testing.T) {
+	if _, err := Diff([]string{"a"}, nil); err == nil {
+		t.Fatal("Diff of a JSON array error = nil")
+	}
+}
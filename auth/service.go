@@ -1,23 +1,67 @@
 package auth
 
 import (
+	"context"
 	"errors"
 	"os"
+	"sync"
+	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/joho/godotenv"
+	"github.com/levensspel/go-gin-template/clock"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/lru"
+	"github.com/levensspel/go-gin-template/metrics"
+	userRepository "github.com/levensspel/go-gin-template/repository/user"
+	"github.com/samber/do/v2"
 )
 
 type Service interface {
 	GenerateToken(userID string) (string, error)
-	ValidateToken(encodedToken string) (*jwt.Token, error)
+	// ParseToken memvalidasi token dan mengembalikan id user di dalamnya.
+	ParseToken(ctx context.Context, encodedToken string) (string, error)
+}
+
+// Revocations mengembalikan waktu terakhir semua sesi user dicabut, atau
+// zero time jika belum pernah. Diimplementasikan
+// userRepository.UserRepository.
+type Revocations interface {
+	SessionsRevokedAt(ctx context.Context, userID string) (time.Time, error)
 }
 
 type jwtService struct {
+	clock clock.Clock
+	// ttl 0 berarti token tidak kedaluwarsa
+	ttl time.Duration
+	// revocations nil berarti pencabutan sesi tidak dicek
+	revocations Revocations
+	revoked     *lru.Cache[string, time.Time]
 }
 
-func NewJWTService() *jwtService {
-	return &jwtService{}
+func NewJWTService(clock clock.Clock, ttl time.Duration, revocations Revocations, revoked *lru.Cache[string, time.Time]) *jwtService {
+	return &jwtService{clock: clock, ttl: ttl, revocations: revocations, revoked: revoked}
+}
+
+// NewJWTServiceInject juga memasang service sebagai default untuk
+// ParseToken yang dipakai middleware.
+func NewJWTServiceInject(i do.Injector) (Service, error) {
+	authConfig := config.LoadAuthConfig()
+	_metrics := do.MustInvoke[*metrics.Metrics](i)
+	_clock := do.MustInvoke[clock.Clock](i)
+	revoked := lru.New[string, time.Time](lru.Settings{
+		Name:       "sessions_revoked_at",
+		MaxEntries: config.LoadCacheConfig().MaxEntries,
+		TTL:        authConfig.RevocationCacheTTL,
+		OnHit:      _metrics.CountCacheHit,
+		OnMiss:     _metrics.CountCacheMiss,
+		OnEvict:    _metrics.CountCacheEvictions,
+		Clock:      _clock,
+	})
+	_revocations := do.MustInvoke[userRepository.UserRepositoryInterface](i)
+	service := NewJWTService(_clock, authConfig.TokenTTL, _revocations, revoked)
+	SetDefault(service)
+	return service, nil
 }
 
 const ENV_PATH = ".env"
@@ -25,9 +69,32 @@ const ENV_PATH = ".env"
 var _ = godotenv.Load(ENV_PATH)
 var SECRET_KEY = os.Getenv("JWT_SECRET_KEY")
 
+var (
+	defaultMu      sync.RWMutex
+	defaultService Service = NewJWTService(clock.Real(), 0, nil, nil)
+)
+
+// SetDefault mengganti service yang dipakai oleh ParseToken.
+func SetDefault(s Service) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultService = s
+}
+
+func Default() Service {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultService
+}
+
 func (s *jwtService) GenerateToken(userID string) (string, error) {
+	now := s.clock.Now()
 	claim := jwt.MapClaims{}
 	claim["user_id"] = userID
+	claim["iat"] = now.Unix()
+	if s.ttl > 0 {
+		claim["exp"] = now.Add(s.ttl).Unix()
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claim)
 
@@ -39,8 +106,10 @@ func (s *jwtService) GenerateToken(userID string) (string, error) {
 	return signedToken, nil
 }
 
-func ParseToken(tokeString string) (id string, err error) {
-	token, err := jwt.Parse(tokeString, func(t *jwt.Token) (interface{}, error) {
+func (s *jwtService) ParseToken(ctx context.Context, tokeString string) (id string, err error) {
+	// Claim waktu divalidasi di bawah dengan s.clock, bukan jam sistem
+	parser := jwt.NewParser(jwt.WithoutClaimsValidation())
+	token, err := parser.Parse(tokeString, func(t *jwt.Token) (interface{}, error) {
 		if t.Method.Alg() != jwt.SigningMethodHS256.Alg() {
 			return nil, errors.New("invalid token signing method")
 		}
@@ -60,8 +129,48 @@ func ParseToken(tokeString string) (id string, err error) {
 	if !ok {
 		return "", errors.New("invalid token")
 	}
+	if !claims.VerifyExpiresAt(s.clock.Now().Unix(), false) {
+		return "", errors.New("token is expired")
+	}
 
-	userID := claims["user_id"].(string)
+	userID, ok := claims["user_id"].(string)
+	if !ok {
+		return "", errors.New("invalid token")
+	}
+	if err := s.verifyNotRevoked(ctx, userID, claims); err != nil {
+		return "", err
+	}
 
 	return userID, nil
 }
+
+// verifyNotRevoked menolak token yang diterbitkan sebelum atau pada detik
+// yang sama dengan pencabutan sesi terakhir user. Token tanpa iat dianggap
+// diterbitkan sebelum pencabutan.
+func (s *jwtService) verifyNotRevoked(ctx context.Context, userID string, claims jwt.MapClaims) error {
+	if s.revocations == nil {
+		return nil
+	}
+	// Hasil load dipakai bersama request lain yang menunggu, jadi
+	// pembatalan request ini tidak ikut diteruskan. Repository memasang
+	// timeout sendiri.
+	revokedAt, err := s.revoked.GetOrLoad(userID, func() (time.Time, error) {
+		return s.revocations.SessionsRevokedAt(context.WithoutCancel(ctx), userID)
+	})
+	if err != nil {
+		return err
+	}
+	if revokedAt.IsZero() {
+		return nil
+	}
+	issuedAt, _ := claims["iat"].(float64)
+	if int64(issuedAt) <= revokedAt.Unix() {
+		return errors.New("token has been revoked")
+	}
+	return nil
+}
+
+// ParseToken memvalidasi token dengan service default, lihat SetDefault.
+func ParseToken(ctx context.Context, tokeString string) (id string, err error) {
+	return Default().ParseToken(ctx, tokeString)
+}
@@ -0,0 +1,231 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/levensspel/go-gin-template/clock/clocktest"
+	"github.com/levensspel/go-gin-template/lru"
+)
+
+var testNow = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// fakeRevocations mengembalikan revokedAt untuk semua user dan menghitung
+// berapa kali dibaca.
+type fakeRevocations struct {
+	revokedAt time.Time
+	err       error
+	calls     int
+	ctx       context.Context
+}
+
+func (f *fakeRevocations) SessionsRevokedAt(ctx context.Context, userID string) (time.Time, error) {
+	f.calls++
+	f.ctx = ctx
+	return f.revokedAt, f.err
+}
+
+func newRevokedCache() *lru.Cache[string, time.Time] {
+	return lru.New[string, time.Time](lru.Settings{Name: "sessions_revoked_at", MaxEntries: 10, TTL: time.Hour})
+}
+
+func TestTokenExpiresOnTheClock(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	fake := clocktest.NewFake(testNow)
+	service := NewJWTService(fake, time.Hour, nil, nil)
+
+	token, err := service.GenerateToken("user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake.Advance(time.Hour - time.Second)
+	if id, err := service.ParseToken(context.Background(), token); err != nil || id != "user-1" {
+		t.Fatalf("ParseToken before expiry = %q, %v, want user-1", id, err)
+	}
+
+	fake.Advance(2 * time.Second)
+	if _, err := service.ParseToken(context.Background(), token); err == nil {
+		t.Fatal("ParseToken accepted a token after its exp")
+	}
+}
+
+func TestTokenClaims(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	for _, tt := range []struct {
+		name    string
+		ttl     time.Duration
+		wantExp bool
+	}{
+		{name: "with ttl", ttl: 15 * time.Minute, wantExp: true},
+		{name: "without ttl", ttl: 0, wantExp: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := NewJWTService(clocktest.NewFake(testNow), tt.ttl, nil, nil).GenerateToken("user-1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			claims := jwt.MapClaims{}
+			if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil {
+				t.Fatal(err)
+			}
+
+			if iat, _ := claims["iat"].(float64); int64(iat) != testNow.Unix() {
+				t.Errorf("iat = %v, want %d", claims["iat"], testNow.Unix())
+			}
+			exp, ok := claims["exp"].(float64)
+			if ok != tt.wantExp {
+				t.Fatalf("exp present = %t, want %t", ok, tt.wantExp)
+			}
+			if ok && int64(exp) != testNow.Add(tt.ttl).Unix() {
+				t.Errorf("exp = %d, want %d", int64(exp), testNow.Add(tt.ttl).Unix())
+			}
+		})
+	}
+}
+
+func TestTokenWithoutTTLNeverExpires(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	fake := clocktest.NewFake(testNow)
+	service := NewJWTService(fake, 0, nil, nil)
+
+	token, err := service.GenerateToken("user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake.Advance(10 * 365 * 24 * time.Hour)
+	if _, err := service.ParseToken(context.Background(), token); err != nil {
+		t.Fatalf("ParseToken error = %v", err)
+	}
+}
+
+func TestParseTokenRejectsInvalidTokens(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	service := NewJWTService(clocktest.NewFake(testNow), time.Hour, nil, nil)
+
+	sign := func(method jwt.SigningMethod, key interface{}, claims jwt.MapClaims) string {
+		token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	for _, tt := range []struct {
+		name  string
+		token string
+	}{
+		{name: "malformed", token: "not-a-token"},
+		{name: "wrong secret", token: sign(jwt.SigningMethodHS256, []byte("other-secret"), jwt.MapClaims{"user_id": "user-1"})},
+		{name: "wrong signing method", token: sign(jwt.SigningMethodHS512, []byte("test-secret"), jwt.MapClaims{"user_id": "user-1"})},
+		{name: "unsigned", token: sign(jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, jwt.MapClaims{"user_id": "user-1"})},
+		{name: "missing user_id", token: sign(jwt.SigningMethodHS256, []byte("test-secret"), jwt.MapClaims{"iat": testNow.Unix()})},
+		{name: "user_id not a string", token: sign(jwt.SigningMethodHS256, []byte("test-secret"), jwt.MapClaims{"user_id": 1})},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if id, err := service.ParseToken(context.Background(), tt.token); err == nil {
+				t.Fatalf("ParseToken = %q, want error", id)
+			}
+		})
+	}
+}
+
+func TestParseTokenRejectsRevokedSessions(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	errLookup := errors.New("lookup failed")
+	for _, tt := range []struct {
+		name        string
+		revocations *fakeRevocations
+		wantErr     bool
+	}{
+		{name: "never revoked", revocations: &fakeRevocations{}},
+		{name: "revoked before issue", revocations: &fakeRevocations{revokedAt: testNow.Add(-time.Second)}},
+		// Pencabutan pada detik yang sama ikut mencabut token tersebut
+		{name: "revoked in the same second", revocations: &fakeRevocations{revokedAt: testNow.Add(500 * time.Millisecond)}, wantErr: true},
+		{name: "revoked after issue", revocations: &fakeRevocations{revokedAt: testNow.Add(time.Minute)}, wantErr: true},
+		{name: "lookup error", revocations: &fakeRevocations{err: errLookup}, wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := clocktest.NewFake(testNow)
+			service := NewJWTService(fake, time.Hour, tt.revocations, newRevokedCache())
+			token, err := service.GenerateToken("user-1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			fake.Advance(2 * time.Minute)
+
+			_, err = service.ParseToken(context.Background(), token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseToken error = %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRevocationLookupIsCached(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	revocations := &fakeRevocations{}
+	service := NewJWTService(clocktest.NewFake(testNow), time.Hour, revocations, newRevokedCache())
+	token, err := service.GenerateToken("user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 3 {
+		if _, err := service.ParseToken(context.Background(), token); err != nil {
+			t.Fatalf("ParseToken error = %v", err)
+		}
+	}
+	if revocations.calls != 1 {
+		t.Fatalf("SessionsRevokedAt called %d times, want 1", revocations.calls)
+	}
+}
+
+type requestKey struct{}
+
+func TestRevocationLookupUsesRequestContext(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	revocations := &fakeRevocations{}
+	service := NewJWTService(clocktest.NewFake(testNow), time.Hour, revocations, newRevokedCache())
+	token, err := service.GenerateToken("user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), requestKey{}, "req-1"))
+	cancel()
+	if _, err := service.ParseToken(ctx, token); err != nil {
+		t.Fatalf("ParseToken error = %v", err)
+	}
+	if got := revocations.ctx.Value(requestKey{}); got != "req-1" {
+		t.Fatalf("SessionsRevokedAt ctx value = %v, want the request context", got)
+	}
+	if err := revocations.ctx.Err(); err != nil {
+		t.Fatalf("SessionsRevokedAt ctx error = %v, want cancellation not propagated to the shared load", err)
+	}
+}
+
+func TestTokenIssuedAfterRevocationIsAccepted(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	fake := clocktest.NewFake(testNow)
+	service := NewJWTService(fake, time.Hour, &fakeRevocations{revokedAt: testNow}, newRevokedCache())
+
+	old, err := service.GenerateToken("user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake.Advance(time.Second)
+	fresh, err := service.GenerateToken("user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.ParseToken(context.Background(), old); err == nil {
+		t.Fatal("ParseToken accepted a token issued before the revocation")
+	}
+	if _, err := service.ParseToken(context.Background(), fresh); err != nil {
+		t.Fatalf("ParseToken rejected a token issued after the revocation: %v", err)
+	}
+}
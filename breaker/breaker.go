@@ -0,0 +1,177 @@
+package breaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/levensspel/go-gin-template/clock"
+)
+
+type State int
+
+const (
+	StateClosed State = iota
+	StateHalfOpen
+	StateOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateHalfOpen:
+		return "half_open"
+	case StateOpen:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrOpen dikembalikan Allow ketika breaker sedang open, atau half-open dan
+// probe sedang berjalan.
+var ErrOpen = errors.New("circuit breaker is open")
+
+type Settings struct {
+	// Name dipakai sebagai label metric dan nama check di /readyz.
+	Name string
+	// FailureThreshold adalah jumlah kegagalan berturut-turut sebelum open.
+	FailureThreshold int
+	// Cooldown adalah lama breaker open sebelum satu probe diizinkan.
+	Cooldown time.Duration
+	// IsSuccessful menentukan apakah error dihitung sebagai kegagalan.
+	// Default: hanya nil yang dianggap berhasil.
+	IsSuccessful func(err error) bool
+	// OnStateChange dipanggil setiap kali state berubah, di luar lock.
+	OnStateChange func(name string, from, to State)
+	// Clock adalah sumber waktu cooldown. Default: clock.Real().
+	Clock clock.Clock
+}
+
+// Breaker adalah circuit breaker dua langkah: Allow sebelum operasi, lalu
+// panggil fungsi done dengan hasilnya.
+type Breaker struct {
+	settings Settings
+	now      func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool
+	// generation naik setiap state berubah, agar hasil operasi yang dimulai
+	// pada state sebelumnya tidak ikut dihitung.
+	generation uint64
+}
+
+func New(settings Settings) *Breaker {
+	if settings.FailureThreshold < 1 {
+		settings.FailureThreshold = 1
+	}
+	if settings.IsSuccessful == nil {
+		settings.IsSuccessful = func(err error) bool { return err == nil }
+	}
+	if settings.Clock == nil {
+		settings.Clock = clock.Real()
+	}
+	return &Breaker{settings: settings, now: settings.Clock.Now}
+}
+
+func (b *Breaker) Name() string {
+	return b.settings.Name
+}
+
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	state, changed := b.currentState()
+	b.mu.Unlock()
+	b.notify(changed)
+	return state
+}
+
+// Allow mengembalikan ErrOpen jika operasi harus langsung ditolak. Jika
+// diizinkan, done wajib dipanggil tepat satu kali dengan error operasi.
+func (b *Breaker) Allow() (done func(err error), err error) {
+	b.mu.Lock()
+	state, changed := b.currentState()
+	if state == StateOpen || (state == StateHalfOpen && b.probing) {
+		b.mu.Unlock()
+		b.notify(changed)
+		return nil, ErrOpen
+	}
+	if state == StateHalfOpen {
+		b.probing = true
+	}
+	generation := b.generation
+	b.mu.Unlock()
+	b.notify(changed)
+
+	var once sync.Once
+	return func(err error) {
+		once.Do(func() {
+			b.record(generation, b.settings.IsSuccessful(err))
+		})
+	}, nil
+}
+
+func (b *Breaker) record(generation uint64, success bool) {
+	b.mu.Lock()
+	if generation != b.generation {
+		b.mu.Unlock()
+		return
+	}
+
+	var changed *transition
+	switch b.state {
+	case StateClosed:
+		if success {
+			b.failures = 0
+		} else {
+			b.failures++
+			if b.failures >= b.settings.FailureThreshold {
+				changed = b.setState(StateOpen)
+			}
+		}
+	case StateHalfOpen:
+		if success {
+			changed = b.setState(StateClosed)
+		} else {
+			changed = b.setState(StateOpen)
+		}
+	}
+	b.mu.Unlock()
+	b.notify(changed)
+}
+
+type transition struct {
+	from, to State
+}
+
+// currentState memindahkan open ke half-open setelah cooldown lewat.
+// Harus dipanggil dengan lock.
+func (b *Breaker) currentState() (State, *transition) {
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.settings.Cooldown {
+		return StateHalfOpen, b.setState(StateHalfOpen)
+	}
+	return b.state, nil
+}
+
+// setState harus dipanggil dengan lock.
+func (b *Breaker) setState(state State) *transition {
+	from := b.state
+	b.state = state
+	b.failures = 0
+	b.probing = false
+	b.generation++
+	if state == StateOpen {
+		b.openedAt = b.now()
+	}
+	return &transition{from: from, to: state}
+}
+
+func (b *Breaker) notify(changed *transition) {
+	if changed != nil && b.settings.OnStateChange != nil {
+		b.settings.OnStateChange(b.settings.Name, changed.from, changed.to)
+	}
+}
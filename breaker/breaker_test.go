@@ -0,0 +1,165 @@
+package breaker_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/breaker"
+	"github.com/levensspel/go-gin-template/clock/clocktest"
+)
+
+var errDown = errors.New("connection refused")
+
+type fixture struct {
+	clock       *clocktest.Fake
+	breaker     *breaker.Breaker
+	transitions []string
+}
+
+func newFixture(threshold int) *fixture {
+	f := &fixture{clock: clocktest.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))}
+	f.breaker = breaker.New(breaker.Settings{
+		Name:             "postgres",
+		FailureThreshold: threshold,
+		Cooldown:         10 * time.Second,
+		Clock:            f.clock,
+		OnStateChange: func(name string, from, to breaker.State) {
+			f.transitions = append(f.transitions, from.String()+"->"+to.String())
+		},
+	})
+	return f
+}
+
+// run menjalankan satu operasi lewat breaker dan mengembalikan error Allow.
+func (f *fixture) run(t *testing.T, err error) error {
+	t.Helper()
+	done, allowErr := f.breaker.Allow()
+	if allowErr != nil {
+		return allowErr
+	}
+	done(err)
+	return nil
+}
+
+func TestBreakerCycle(t *testing.T) {
+	f := newFixture(3)
+
+	// Kegagalan di bawah threshold belum membuka breaker
+	for range 2 {
+		if err := f.run(t, errDown); err != nil {
+			t.Fatalf("Allow error = %v while closed", err)
+		}
+	}
+	if f.breaker.State() != breaker.StateClosed {
+		t.Fatalf("state = %s after 2 failures, want closed", f.breaker.State())
+	}
+	if err := f.run(t, errDown); err != nil {
+		t.Fatal(err)
+	}
+	if f.breaker.State() != breaker.StateOpen {
+		t.Fatalf("state = %s after 3 failures, want open", f.breaker.State())
+	}
+	if err := f.run(t, nil); !errors.Is(err, breaker.ErrOpen) {
+		t.Fatalf("Allow error = %v while open, want ErrOpen", err)
+	}
+
+	f.clock.Advance(9 * time.Second)
+	if err := f.run(t, nil); !errors.Is(err, breaker.ErrOpen) {
+		t.Fatalf("Allow error = %v before cooldown, want ErrOpen", err)
+	}
+
+	f.clock.Advance(time.Second)
+	if f.breaker.State() != breaker.StateHalfOpen {
+		t.Fatalf("state = %s after cooldown, want half_open", f.breaker.State())
+	}
+	probe, err := f.breaker.Allow()
+	if err != nil {
+		t.Fatalf("probe Allow error = %v", err)
+	}
+	// Hanya satu probe yang boleh berjalan
+	if err := f.run(t, nil); !errors.Is(err, breaker.ErrOpen) {
+		t.Fatalf("second Allow error = %v while probing, want ErrOpen", err)
+	}
+	probe(nil)
+	if f.breaker.State() != breaker.StateClosed {
+		t.Fatalf("state = %s after a successful probe, want closed", f.breaker.State())
+	}
+
+	want := []string{"closed->open", "open->half_open", "half_open->closed"}
+	if !slices.Equal(f.transitions, want) {
+		t.Fatalf("transitions = %v, want %v", f.transitions, want)
+	}
+}
+
+func TestBreakerFailedProbeReopens(t *testing.T) {
+	f := newFixture(1)
+	if err := f.run(t, errDown); err != nil {
+		t.Fatal(err)
+	}
+	f.clock.Advance(10 * time.Second)
+	if err := f.run(t, errDown); err != nil {
+		t.Fatalf("probe Allow error = %v", err)
+	}
+	if f.breaker.State() != breaker.StateOpen {
+		t.Fatalf("state = %s after a failed probe, want open", f.breaker.State())
+	}
+	// Cooldown dihitung ulang dari probe yang gagal
+	f.clock.Advance(9 * time.Second)
+	if err := f.run(t, nil); !errors.Is(err, breaker.ErrOpen) {
+		t.Fatalf("Allow error = %v, want ErrOpen until the new cooldown ends", err)
+	}
+	want := []string{"closed->open", "open->half_open", "half_open->open"}
+	if !slices.Equal(f.transitions, want) {
+		t.Fatalf("transitions = %v, want %v", f.transitions, want)
+	}
+}
+
+func TestBreakerSuccessResetsFailures(t *testing.T) {
+	f := newFixture(2)
+	for _, err := range []error{errDown, nil, errDown, nil, errDown} {
+		if allowErr := f.run(t, err); allowErr != nil {
+			t.Fatal(allowErr)
+		}
+	}
+	if f.breaker.State() != breaker.StateClosed {
+		t.Fatalf("state = %s, want closed when failures are not consecutive", f.breaker.State())
+	}
+}
+
+// TestBreakerIgnoresStaleResults memastikan operasi yang dimulai sebelum
+// breaker open tidak menutupnya lagi saat selesai.
+func TestBreakerIgnoresStaleResults(t *testing.T) {
+	f := newFixture(1)
+	slow, err := f.breaker.Allow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.run(t, errDown); err != nil {
+		t.Fatal(err)
+	}
+	slow(nil)
+	if f.breaker.State() != breaker.StateOpen {
+		t.Fatalf("state = %s, want open", f.breaker.State())
+	}
+}
+
+func TestBreakerIsSuccessful(t *testing.T) {
+	errIgnored := errors.New("no rows")
+	b := breaker.New(breaker.Settings{
+		FailureThreshold: 1,
+		Cooldown:         time.Minute,
+		IsSuccessful: func(err error) bool {
+			return err == nil || errors.Is(err, errIgnored)
+		},
+	})
+	done, err := b.Allow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	done(errIgnored)
+	if b.State() != breaker.StateClosed {
+		t.Fatalf("state = %s, want closed for an error IsSuccessful accepts", b.State())
+	}
+}
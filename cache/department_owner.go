@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/levensspel/go-gin-template/clock"
+)
+
+// DepartmentOwnerTtl adalah batas umur entry kepemilikan department. Entry
+// juga dihapus lewat NOTIFY department_changed, TTL hanya pengaman jika
+// notifikasi terlewat.
+const DepartmentOwnerTtl = 5 * time.Minute
+
+type departmentOwner struct {
+	managerId string
+	expiresAt time.Time
+}
+
+// DepartmentOwners menyimpan departmentId -> managerId. Hanya kepemilikan
+// yang valid yang disimpan, sehingga department yang tidak ditemukan selalu
+// dicek ulang ke database.
+//
+// Aplikasi memakai satu cache per proses lewat fungsi package ini; instance
+// terpisah dari NewDepartmentOwners dipakai test untuk mensimulasikan
+// beberapa instance aplikasi.
+type DepartmentOwners struct {
+	mu      sync.RWMutex
+	entries map[string]departmentOwner
+	clock   clock.Clock
+}
+
+// NewDepartmentOwners membuat cache kosong dengan clock sebagai acuan TTL.
+func NewDepartmentOwners(clock clock.Clock) *DepartmentOwners {
+	return &DepartmentOwners{entries: make(map[string]departmentOwner), clock: clock}
+}
+
+var departmentOwners = NewDepartmentOwners(clock.Real())
+
+func (c *DepartmentOwners) Get(departmentId string) (string, bool) {
+	c.mu.RLock()
+	entry, found := c.entries[departmentId]
+	c.mu.RUnlock()
+	if !found || c.clock.Now().After(entry.expiresAt) {
+		return "", false
+	}
+	return entry.managerId, true
+}
+
+func (c *DepartmentOwners) Set(departmentId, managerId string) {
+	c.mu.Lock()
+	c.entries[departmentId] = departmentOwner{
+		managerId: managerId,
+		expiresAt: c.clock.Now().Add(DepartmentOwnerTtl),
+	}
+	c.mu.Unlock()
+}
+
+func (c *DepartmentOwners) Delete(departmentId string) {
+	c.mu.Lock()
+	delete(c.entries, departmentId)
+	c.mu.Unlock()
+}
+
+// Reset menghapus semua entry, dipakai setelah listener tersambung ulang
+// karena notifikasi selama terputus tidak diterima.
+func (c *DepartmentOwners) Reset() {
+	c.mu.Lock()
+	c.entries = make(map[string]departmentOwner)
+	c.mu.Unlock()
+}
+
+func GetDepartmentOwner(departmentId string) (string, bool) {
+	return departmentOwners.Get(departmentId)
+}
+
+func SetDepartmentOwner(departmentId, managerId string) {
+	departmentOwners.Set(departmentId, managerId)
+}
+
+func DeleteDepartmentOwner(departmentId string) {
+	departmentOwners.Delete(departmentId)
+}
+
+// ResetDepartmentOwners menghapus semua entry cache proses ini, lihat
+// DepartmentOwners.Reset.
+func ResetDepartmentOwners() {
+	departmentOwners.Reset()
+}
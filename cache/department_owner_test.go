@@ -0,0 +1,30 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/clock/clocktest"
+)
+
+func TestDepartmentOwnersExpire(t *testing.T) {
+	fake := clocktest.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	owners := NewDepartmentOwners(fake)
+	owners.Set("engineering", "manager-1")
+
+	fake.Advance(DepartmentOwnerTtl)
+	if owner, found := owners.Get("engineering"); !found || owner != "manager-1" {
+		t.Fatalf("Get at the TTL = %q, %t, want manager-1", owner, found)
+	}
+	fake.Advance(time.Nanosecond)
+	if owner, found := owners.Get("engineering"); found {
+		t.Fatalf("Get after the TTL = %q, want expired", owner)
+	}
+
+	// Set ulang memperpanjang umur entry
+	owners.Set("engineering", "manager-2")
+	fake.Advance(DepartmentOwnerTtl / 2)
+	if owner, found := owners.Get("engineering"); !found || owner != "manager-2" {
+		t.Fatalf("Get after Set again = %q, %t, want manager-2", owner, found)
+	}
+}
@@ -0,0 +1,158 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/infrastructure"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/redis/go-redis/v9"
+	"github.com/samber/do/v2"
+)
+
+const (
+	employeeFirstPageCacheName = "employee_first_page"
+	employeeFirstPageCacheKey  = "employee:first_page:%s"
+	// employeeFirstPageTotalKey menyimpan total employee aktif untuk header
+	// Link dan X-Total-Count halaman pertama
+	employeeFirstPageTotalKey = "employee:first_page_total:%s"
+)
+
+// EmployeeFirstPage menyimpan halaman pertama GET /v1/employee tanpa filter
+// per manager di Redis. Redis bersifat opsional: client nil membuat cache
+// selalu miss, dan error Redis hanya dicatat sebagai warning sehingga
+// request tetap dilayani dari database. Service lain yang mengubah employee
+// di luar EmployeeService, mis. saat memindahkan department, memanggil
+// Invalidate.
+type EmployeeFirstPage struct {
+	client  *redis.Client
+	ttl     time.Duration
+	logger  logger.Logger
+	metrics *metrics.Metrics
+}
+
+func NewEmployeeFirstPage(client *redis.Client, ttl time.Duration, logger logger.Logger, metrics *metrics.Metrics) *EmployeeFirstPage {
+	return &EmployeeFirstPage{client: client, ttl: ttl, logger: logger, metrics: metrics}
+}
+
+func NewEmployeeFirstPageInject(i do.Injector) (*EmployeeFirstPage, error) {
+	_redis := do.MustInvoke[*infrastructure.RedisClient](i).Client
+	_logger := do.MustInvoke[logger.LogHandler](i)
+	_metrics := do.MustInvoke[*metrics.Metrics](i)
+	return NewEmployeeFirstPage(_redis, config.LoadCacheConfig().EmployeeFirstPageTTL, &_logger, _metrics), nil
+}
+
+// IsEmployeeFirstPage mengembalikan true jika request tanpa filter dan
+// memakai limit/offset default.
+func IsEmployeeFirstPage(input dto.GetEmployeesRequest) bool {
+	return input.ManagerID != "" &&
+		input.IdentityNumber == "" &&
+		len(input.IdentityNumbers) == 0 &&
+		input.Name == "" &&
+		input.Query == "" &&
+		!input.Fuzzy &&
+		input.Gender == "" &&
+		input.DepartmentID == "" &&
+		input.SortBy == "" &&
+		input.Status == dto.EmployeeStatusActive &&
+		input.HiredFrom == "" &&
+		input.HiredTo == "" &&
+		input.Limit == dto.DefaultLimit &&
+		input.Offset == dto.DefaultOffset
+}
+
+func (c *EmployeeFirstPage) enabled() bool {
+	return c.client != nil && c.ttl > 0
+}
+
+func (c *EmployeeFirstPage) Get(ctx context.Context, managerId string) ([]dto.EmployeeResponse, bool) {
+	if !c.enabled() {
+		return nil, false
+	}
+
+	data, err := c.client.Get(ctx, fmt.Sprintf(employeeFirstPageCacheKey, managerId)).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			c.logger.Warn(fmt.Sprintf("Redis get failed, falling back to database: %v", err), helper.EmployeeFirstPage)
+		}
+		c.metrics.CountCacheMiss(employeeFirstPageCacheName)
+		return nil, false
+	}
+
+	var employees []dto.EmployeeResponse
+	if err := json.Unmarshal(data, &employees); err != nil {
+		c.logger.Warn(fmt.Sprintf("Invalid cached page, falling back to database: %v", err), helper.EmployeeFirstPage)
+		c.metrics.CountCacheMiss(employeeFirstPageCacheName)
+		return nil, false
+	}
+	c.metrics.CountCacheHit(employeeFirstPageCacheName)
+	return employees, true
+}
+
+func (c *EmployeeFirstPage) Set(ctx context.Context, managerId string, employees []dto.EmployeeResponse) {
+	if !c.enabled() {
+		return
+	}
+
+	data, err := json.Marshal(employees)
+	if err != nil {
+		return
+	}
+	if err := c.client.Set(ctx, fmt.Sprintf(employeeFirstPageCacheKey, managerId), data, c.ttl).Err(); err != nil {
+		c.logger.Warn(fmt.Sprintf("Redis set failed: %v", err), helper.EmployeeFirstPage)
+	}
+}
+
+// GetTotal mengembalikan total employee yang disimpan SetTotal, sehingga
+// halaman pertama dari cache tidak perlu query Count.
+func (c *EmployeeFirstPage) GetTotal(ctx context.Context, managerId string) (int64, bool) {
+	if !c.enabled() {
+		return 0, false
+	}
+
+	total, err := c.client.Get(ctx, fmt.Sprintf(employeeFirstPageTotalKey, managerId)).Int64()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			c.logger.Warn(fmt.Sprintf("Redis get failed, falling back to database: %v", err), helper.EmployeeFirstPage)
+		}
+		c.metrics.CountCacheMiss(employeeFirstPageCacheName)
+		return 0, false
+	}
+	c.metrics.CountCacheHit(employeeFirstPageCacheName)
+	return total, true
+}
+
+func (c *EmployeeFirstPage) SetTotal(ctx context.Context, managerId string, total int64) {
+	if !c.enabled() {
+		return
+	}
+
+	if err := c.client.Set(ctx, fmt.Sprintf(employeeFirstPageTotalKey, managerId), total, c.ttl).Err(); err != nil {
+		c.logger.Warn(fmt.Sprintf("Redis set failed: %v", err), helper.EmployeeFirstPage)
+	}
+}
+
+// Invalidate menghapus halaman dan totalnya, dipanggil setelah setiap write
+// employee milik manager. Tetap dijalankan walau request sudah dibatalkan, karena write-nya sudah terjadi.
+// Jika gagal, data lama paling lama terlihat selama TTL.
+func (c *EmployeeFirstPage) Invalidate(ctx context.Context, managerId string) {
+	if !c.enabled() {
+		return
+	}
+
+	err := c.client.Del(
+		context.WithoutCancel(ctx),
+		fmt.Sprintf(employeeFirstPageCacheKey, managerId),
+		fmt.Sprintf(employeeFirstPageTotalKey, managerId),
+	).Err()
+	if err != nil {
+		c.logger.Warn(fmt.Sprintf("Redis invalidation failed: %v", err), helper.EmployeeFirstPage)
+	}
+}
@@ -0,0 +1,67 @@
+//go:build integration
+
+package cache_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/levensspel/go-gin-template/cache"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/mocks"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestFirstPage memakai Redis dari TEST_REDIS_URL, mis.
+//
+//	TEST_REDIS_URL=redis://localhost:6379/15 go test -tags integration ./cache/
+func newTestFirstPage(t *testing.T) *cache.EmployeeFirstPage {
+	t.Helper()
+	redisURL := os.Getenv("TEST_REDIS_URL")
+	if redisURL == "" {
+		t.Skip("TEST_REDIS_URL is not set")
+	}
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		t.Fatalf("parse TEST_REDIS_URL: %v", err)
+	}
+	client := redis.NewClient(opts)
+	t.Cleanup(func() { client.Close() })
+	appMetrics := metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{})
+	return cache.NewEmployeeFirstPage(client, time.Minute, mocks.Logger{}, appMetrics)
+}
+
+func TestEmployeeFirstPageTotal(t *testing.T) {
+	c := newTestFirstPage(t)
+	ctx := context.Background()
+	managerId := uuid.NewString()
+	t.Cleanup(func() { c.Invalidate(ctx, managerId) })
+
+	if _, ok := c.GetTotal(ctx, managerId); ok {
+		t.Fatal("GetTotal() hit before SetTotal")
+	}
+	c.Set(ctx, managerId, []dto.EmployeeResponse{{EmployeePayload: dto.EmployeePayload{IdentityNumber: "EMP-1"}}})
+	c.SetTotal(ctx, managerId, 12)
+
+	if total, ok := c.GetTotal(ctx, managerId); !ok || total != 12 {
+		t.Fatalf("GetTotal() = %d, %v, want 12", total, ok)
+	}
+	if page, ok := c.Get(ctx, managerId); !ok || len(page) != 1 {
+		t.Fatalf("Get() = %v, %v, want the cached page", page, ok)
+	}
+
+	// Halaman dan total selalu dihapus bersama
+	c.Invalidate(ctx, managerId)
+	if _, ok := c.GetTotal(ctx, managerId); ok {
+		t.Fatal("GetTotal() hit after Invalidate")
+	}
+	if _, ok := c.Get(ctx, managerId); ok {
+		t.Fatal("Get() hit after Invalidate")
+	}
+}
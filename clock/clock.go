@@ -0,0 +1,57 @@
+package clock
+
+import (
+	"time"
+
+	"github.com/samber/do/v2"
+)
+
+// Clock adalah sumber waktu yang bisa diganti, agar kode yang bergantung
+// pada waktu (expiry token, jadwal purge) bisa diuji tanpa sleep. Lihat
+// clocktest.Fake.
+type Clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) Ticker
+	After(d time.Duration) <-chan time.Time
+}
+
+// Ticker adalah bagian time.Ticker yang dipakai aplikasi.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+type realClock struct{}
+
+// Real mengembalikan Clock berdasarkan jam sistem.
+func Real() Clock {
+	return realClock{}
+}
+
+func NewInject(i do.Injector) (Clock, error) {
+	return Real(), nil
+}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{ticker: time.NewTicker(d)}
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+type realTicker struct {
+	ticker *time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.ticker.C
+}
+
+func (t realTicker) Stop() {
+	t.ticker.Stop()
+}
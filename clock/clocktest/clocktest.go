@@ -0,0 +1,150 @@
+// Package clocktest berisi clock.Clock palsu yang hanya bergerak saat
+// dimajukan secara eksplisit.
+package clocktest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/levensspel/go-gin-template/clock"
+)
+
+// Fake adalah clock.Clock yang waktunya hanya berubah lewat Advance atau
+// Set. Ticker dan After milik Fake berbunyi saat waktunya terlewati; seperti
+// time.Ticker, tick yang tidak sempat dibaca dibuang.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*waiter
+	// changed ditutup setiap kali jumlah waiter berubah, lihat BlockUntil
+	changed chan struct{}
+}
+
+type waiter struct {
+	at     time.Time
+	period time.Duration
+	c      chan time.Time
+}
+
+var _ clock.Clock = (*Fake)(nil)
+
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now, changed: make(chan struct{})}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// NewTicker panic untuk d <= 0, sama seperti time.NewTicker.
+func (f *Fake) NewTicker(d time.Duration) clock.Ticker {
+	if d <= 0 {
+		panic("clocktest: non-positive interval for NewTicker")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w := &waiter{at: f.now.Add(d), period: d, c: make(chan time.Time, 1)}
+	f.addLocked(w)
+	return &ticker{clock: f, waiter: w}
+}
+
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w := &waiter{at: f.now.Add(d), c: make(chan time.Time, 1)}
+	if d <= 0 {
+		w.c <- f.now
+		return w.c
+	}
+	f.addLocked(w)
+	return w.c
+}
+
+// Advance memajukan waktu sebesar d dan membunyikan ticker/After yang
+// jatuh temponya terlewati.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.setLocked(f.now.Add(d))
+}
+
+// Set memindahkan waktu ke t. Waktu mundur tidak membunyikan apa pun.
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.setLocked(t)
+}
+
+// BlockUntil menunggu sampai ada paling sedikit n ticker/After yang aktif,
+// agar test bisa memastikan goroutine sudah menunggu sebelum Advance.
+func (f *Fake) BlockUntil(n int) {
+	for {
+		f.mu.Lock()
+		if len(f.waiters) >= n {
+			f.mu.Unlock()
+			return
+		}
+		changed := f.changed
+		f.mu.Unlock()
+		<-changed
+	}
+}
+
+func (f *Fake) setLocked(now time.Time) {
+	f.now = now
+	remaining := f.waiters[:0]
+	for _, w := range f.waiters {
+		for !w.at.After(now) {
+			select {
+			case w.c <- w.at:
+			default:
+			}
+			if w.period == 0 {
+				break
+			}
+			w.at = w.at.Add(w.period)
+		}
+		if w.period > 0 || w.at.After(now) {
+			remaining = append(remaining, w)
+		}
+	}
+	f.waiters = remaining
+	f.notifyLocked()
+}
+
+func (f *Fake) addLocked(w *waiter) {
+	f.waiters = append(f.waiters, w)
+	f.notifyLocked()
+}
+
+func (f *Fake) removeLocked(target *waiter) {
+	for i, w := range f.waiters {
+		if w == target {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			f.notifyLocked()
+			return
+		}
+	}
+}
+
+func (f *Fake) notifyLocked() {
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+type ticker struct {
+	clock  *Fake
+	waiter *waiter
+}
+
+func (t *ticker) C() <-chan time.Time {
+	return t.waiter.c
+}
+
+func (t *ticker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.clock.removeLocked(t.waiter)
+}
@@ -0,0 +1,111 @@
+package clocktest
+
+import (
+	"testing"
+	"time"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func received(c <-chan time.Time) (time.Time, bool) {
+	select {
+	case t := <-c:
+		return t, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestAfterFiresOnceWhenDue(t *testing.T) {
+	fake := NewFake(start)
+	c := fake.After(time.Minute)
+
+	fake.Advance(59 * time.Second)
+	if _, ok := received(c); ok {
+		t.Fatal("After fired before its deadline")
+	}
+	fake.Advance(time.Second)
+	if got, ok := received(c); !ok || !got.Equal(start.Add(time.Minute)) {
+		t.Fatalf("After = %v, %t, want %v", got, ok, start.Add(time.Minute))
+	}
+	fake.Advance(time.Hour)
+	if _, ok := received(c); ok {
+		t.Fatal("After fired twice")
+	}
+}
+
+func TestAfterNonPositiveFiresImmediately(t *testing.T) {
+	fake := NewFake(start)
+	if got, ok := received(fake.After(0)); !ok || !got.Equal(start) {
+		t.Fatalf("After(0) = %v, %t, want %v", got, ok, start)
+	}
+}
+
+func TestTickerDropsMissedTicks(t *testing.T) {
+	fake := NewFake(start)
+	ticker := fake.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	fake.Advance(time.Minute)
+	if got, ok := received(ticker.C()); !ok || !got.Equal(start.Add(time.Minute)) {
+		t.Fatalf("first tick = %v, %t", got, ok)
+	}
+
+	// Seperti time.Ticker, hanya satu tick yang ditampung
+	fake.Advance(5 * time.Minute)
+	if _, ok := received(ticker.C()); !ok {
+		t.Fatal("ticker did not fire after Advance")
+	}
+	if _, ok := received(ticker.C()); ok {
+		t.Fatal("ticker buffered more than one missed tick")
+	}
+
+	// Jadwal tetap mengikuti periode awal
+	fake.Advance(time.Minute)
+	if got, ok := received(ticker.C()); !ok || !got.Equal(start.Add(7*time.Minute)) {
+		t.Fatalf("tick after catching up = %v, %t, want %v", got, ok, start.Add(7*time.Minute))
+	}
+}
+
+func TestTickerStop(t *testing.T) {
+	fake := NewFake(start)
+	ticker := fake.NewTicker(time.Minute)
+	ticker.Stop()
+
+	fake.Advance(time.Hour)
+	if _, ok := received(ticker.C()); ok {
+		t.Fatal("stopped ticker fired")
+	}
+}
+
+func TestSetBackwardsDoesNotFire(t *testing.T) {
+	fake := NewFake(start)
+	c := fake.After(time.Minute)
+
+	fake.Set(start.Add(-time.Hour))
+	if _, ok := received(c); ok {
+		t.Fatal("After fired when time moved backwards")
+	}
+	if got := fake.Now(); !got.Equal(start.Add(-time.Hour)) {
+		t.Fatalf("Now = %v, want %v", got, start.Add(-time.Hour))
+	}
+}
+
+func TestBlockUntilWaitsForWaiters(t *testing.T) {
+	fake := NewFake(start)
+	fired := make(chan time.Time)
+	go func() {
+		fired <- <-fake.After(time.Second)
+	}()
+
+	fake.BlockUntil(1)
+	fake.Advance(time.Second)
+	select {
+	case got := <-fired:
+		if !got.Equal(start.Add(time.Second)) {
+			t.Fatalf("After = %v, want %v", got, start.Add(time.Second))
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("goroutine did not receive from After")
+	}
+}
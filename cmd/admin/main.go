@@ -0,0 +1,261 @@
+// Command admin menjalankan tugas operasional langsung ke database dengan
+// konfigurasi yang sama dengan server (.env dan env DATABASE_*):
+//
+//	admin user create --email EMAIL --password PASSWORD
+//	admin user reset-password --email EMAIL
+//	admin sessions revoke --email EMAIL
+//	admin department reassign --id DEPARTMENT_ID --to-manager MANAGER_ID
+//
+// Setiap perintah membutuhkan --operator (atau env ADMIN_OPERATOR), yaitu
+// nama orang yang menjalankannya, karena semua perubahan dicatat di audit
+// log dengan actor "cli:<operator>". Dengan --json hasil dan error ditulis
+// sebagai JSON ke stdout.
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	_ "github.com/joho/godotenv/autoload"
+	"github.com/levensspel/go-gin-template/di"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	adminService "github.com/levensspel/go-gin-template/service/admin"
+	"github.com/samber/do/v2"
+)
+
+// Exit code: 1 untuk perintah yang gagal, 2 untuk pemakaian yang salah.
+const (
+	exitFailed = 1
+	exitUsage  = 2
+)
+
+const usage = `Usage: admin <command> [flags]
+
+Commands:
+  user create --email EMAIL (--password PASSWORD | --password-stdin)
+  user reset-password --email EMAIL
+  sessions revoke --email EMAIL
+  department reassign --id DEPARTMENT_ID --to-manager MANAGER_ID
+
+Common flags:
+  --operator NAME  name recorded in the audit log (default $ADMIN_OPERATOR)
+  --json           write the result as JSON
+`
+
+// errUsage berarti argumen tidak valid; pesan sudah ditulis ke stderr.
+var errUsage = errors.New("usage")
+
+// command adalah satu subcommand. run dipanggil setelah flag di-parse dan
+// mengembalikan hasil yang ditulis sebagai teks atau JSON.
+type command struct {
+	flags *flag.FlagSet
+	run   func(ctx context.Context, service adminService.AdminService) (result, error)
+}
+
+// result adalah hasil perintah, text dipakai tanpa --json.
+type result struct {
+	value any
+	text  string
+}
+
+func main() {
+	// Dependensi dibuat lazy, jadi hanya database dan repository yang
+	// dipakai perintah ini yang dibuka
+	code := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr, func() adminService.AdminService {
+		return do.MustInvoke[adminService.AdminService](di.Injector)
+	})
+	shutdown()
+	os.Exit(code)
+}
+
+// run menjalankan perintah dari args dan mengembalikan exit code. service
+// hanya dipanggil setelah argumen valid.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer, service func() adminService.AdminService) int {
+	if len(args) < 2 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	var operator string
+	var asJSON bool
+	cmd, ok := newCommand(args[0]+" "+args[1], stdin, &operator)
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s", strings.Join(args[:2], " "), usage)
+		return exitUsage
+	}
+	cmd.flags.SetOutput(stderr)
+	cmd.flags.StringVar(&operator, "operator", os.Getenv("ADMIN_OPERATOR"), "name recorded in the audit log")
+	cmd.flags.BoolVar(&asJSON, "json", false, "write the result as JSON")
+	if err := cmd.flags.Parse(args[2:]); err != nil {
+		return exitUsage
+	}
+	if cmd.flags.NArg() > 0 {
+		fmt.Fprintf(stderr, "unexpected arguments: %s\n", strings.Join(cmd.flags.Args(), " "))
+		return exitUsage
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// Semua entry audit log dari satu perintah memakai request id yang sama
+	ctx = context.WithValue(ctx, helper.ContextKeyRequestID, newRequestID())
+
+	out, err := cmd.run(ctx, service())
+	if errors.Is(err, errUsage) {
+		return exitUsage
+	}
+	if err != nil {
+		writeError(stdout, stderr, asJSON, err)
+		return exitFailed
+	}
+	if asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		_ = encoder.Encode(out.value)
+		return 0
+	}
+	fmt.Fprintln(stdout, out.text)
+	return 0
+}
+
+// newCommand membuat subcommand untuk "<group> <action>". operator diisi
+// flag --operator sebelum run dipanggil.
+func newCommand(name string, stdin io.Reader, operator *string) (*command, bool) {
+	flags := flag.NewFlagSet("admin "+name, flag.ContinueOnError)
+	cmd := &command{flags: flags}
+
+	switch name {
+	case "user create":
+		email := flags.String("email", "", "manager email")
+		password := flags.String("password", "", "manager password (visible in the process list, prefer --password-stdin)")
+		passwordStdin := flags.Bool("password-stdin", false, "read the password from the first line of stdin")
+		cmd.run = func(ctx context.Context, service adminService.AdminService) (result, error) {
+			if *passwordStdin {
+				if *password != "" {
+					fmt.Fprintln(flags.Output(), "--password and --password-stdin cannot be used together")
+					return result{}, errUsage
+				}
+				line, err := bufio.NewReader(stdin).ReadString('\n')
+				if err != nil && !errors.Is(err, io.EOF) {
+					return result{}, err
+				}
+				*password = strings.TrimRight(line, "\r\n")
+			}
+			response, err := service.CreateUser(ctx, dto.AdminUserCreateRequest{
+				Operator: *operator,
+				Email:    *email,
+				Password: *password,
+			})
+			return result{
+				value: response,
+				text:  fmt.Sprintf("created manager %s (%s)", response.ManagerID, response.Email),
+			}, err
+		}
+	case "user reset-password":
+		email := flags.String("email", "", "manager email")
+		cmd.run = func(ctx context.Context, service adminService.AdminService) (result, error) {
+			response, err := service.ResetPassword(ctx, dto.AdminUserRequest{Operator: *operator, Email: *email})
+			return result{
+				value: response,
+				text: fmt.Sprintf(
+					"reset password of manager %s (%s), sessions revoked at %s\nnew password: %s",
+					response.ManagerID, response.Email, response.SessionsRevokedAt.Format(time.RFC3339), response.Password,
+				),
+			}, err
+		}
+	case "sessions revoke":
+		email := flags.String("email", "", "manager email")
+		cmd.run = func(ctx context.Context, service adminService.AdminService) (result, error) {
+			response, err := service.RevokeSessions(ctx, dto.AdminUserRequest{Operator: *operator, Email: *email})
+			return result{
+				value: response,
+				text: fmt.Sprintf(
+					"revoked sessions of manager %s (%s) issued until %s",
+					response.ManagerID, response.Email, response.SessionsRevokedAt.Format(time.RFC3339),
+				),
+			}, err
+		}
+	case "department reassign":
+		id := flags.String("id", "", "department id")
+		toManager := flags.String("to-manager", "", "id of the new manager")
+		cmd.run = func(ctx context.Context, service adminService.AdminService) (result, error) {
+			response, err := service.ReassignDepartment(ctx, dto.AdminDepartmentReassignRequest{
+				Operator:     *operator,
+				DepartmentID: *id,
+				ToManagerID:  *toManager,
+			})
+			text := fmt.Sprintf(
+				"reassigned department %s (%s) from manager %s to %s",
+				response.DepartmentID, response.DepartmentName, response.FromManagerID, response.ToManagerID,
+			)
+			if response.FromManagerID == response.ToManagerID {
+				text = fmt.Sprintf("department %s (%s) already belongs to manager %s", response.DepartmentID, response.DepartmentName, response.ToManagerID)
+			}
+			return result{value: response, text: text}, err
+		}
+	default:
+		return nil, false
+	}
+	return cmd, true
+}
+
+// errorOutput adalah error dengan --json, fields diisi untuk error validasi.
+type errorOutput struct {
+	Error  string            `json:"error"`
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+type fieldErrorer interface {
+	FieldErrors() map[string]string
+}
+
+func writeError(stdout, stderr io.Writer, asJSON bool, err error) {
+	output := errorOutput{Error: err.Error()}
+	var fieldErrors fieldErrorer
+	if errors.As(err, &fieldErrors) {
+		output.Error = "invalid arguments"
+		output.Fields = fieldErrors.FieldErrors()
+	}
+
+	if asJSON {
+		_ = json.NewEncoder(stdout).Encode(output)
+		return
+	}
+	fmt.Fprintf(stderr, "error: %s\n", output.Error)
+	fields := make([]string, 0, len(output.Fields))
+	for field := range output.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		fmt.Fprintf(stderr, "  %s: %s\n", field, output.Fields[field])
+	}
+}
+
+// shutdown menutup pool database dan dependensi lain yang sudah dibuat.
+func shutdown() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if errs := di.Injector.ShutdownWithContext(ctx); errs != nil && errs.Len() > 0 {
+		fmt.Fprintln(os.Stderr, errs.Error())
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return "cli-" + hex.EncodeToString(b)
+}
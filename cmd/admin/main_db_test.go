@@ -0,0 +1,185 @@
+//go:build integration
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/auth"
+	"github.com/levensspel/go-gin-template/cache"
+	"github.com/levensspel/go-gin-template/clock"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/lru"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/mocks"
+	"github.com/levensspel/go-gin-template/repository"
+	userRepository "github.com/levensspel/go-gin-template/repository/user"
+	adminService "github.com/levensspel/go-gin-template/service/admin"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// newDBService menyusun AdminService di atas database sekali pakai, sama
+// dengan di.Injector tanpa Redis.
+func newDBService(t *testing.T) (*pgxpool.Pool, adminService.AdminService) {
+	t.Helper()
+	pool := dbtest.New(t)
+	appMetrics := metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{})
+	uow := repository.NewUnitOfWork(pool, config.LoadQueryTimeoutConfig(), &config.SearchConfig{})
+	return pool, adminService.New(uow, clock.Real(), cache.NewEmployeeFirstPage(nil, 0, mocks.Logger{}, appMetrics))
+}
+
+// runJSON menjalankan perintah dengan --json, memastikan exit code 0, dan
+// men-decode hasilnya ke out.
+func runJSON(t *testing.T, service adminService.AdminService, out any, args ...string) {
+	t.Helper()
+	code, stdout, stderr := runCommand(t, service, "", append(args, "--operator", "alice", "--json")...)
+	if code != 0 {
+		t.Fatalf("admin %v: exit %d, stdout %q, stderr %q", args, code, stdout, stderr)
+	}
+	if err := json.Unmarshal([]byte(stdout), out); err != nil {
+		t.Fatalf("admin %v: stdout %q is not JSON: %v", args, stdout, err)
+	}
+}
+
+// auditActors mengembalikan actor semua audit log entity tersebut, urut
+// dari yang pertama dicatat.
+func auditActors(t *testing.T, pool *pgxpool.Pool, entityType, entityId string) []string {
+	t.Helper()
+	rows, err := pool.Query(context.Background(), "SELECT actor_id FROM audit_log WHERE entity_type = $1 AND entity_id = $2 ORDER BY id;", entityType, entityId)
+	if err != nil {
+		t.Fatalf("query audit log: %v", err)
+	}
+	defer rows.Close()
+	var actors []string
+	for rows.Next() {
+		var actor string
+		if err := rows.Scan(&actor); err != nil {
+			t.Fatal(err)
+		}
+		actors = append(actors, actor)
+	}
+	return actors
+}
+
+func TestUserCommandsOnDatabase(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	pool, service := newDBService(t)
+	ctx := context.Background()
+
+	var created dto.AdminUserResponse
+	runJSON(t, service, &created, "user", "create", "--email", "cli@example.com", "--password", "correct-horse")
+	if created.ManagerID == "" || created.Email != "cli@example.com" {
+		t.Fatalf("created = %+v", created)
+	}
+	// Email yang sama ditolak tanpa menulis audit log kedua
+	if code, _, _ := runCommand(t, service, "", "user", "create", "--email", "cli@example.com", "--password", "correct-horse", "--operator", "alice"); code != exitFailed {
+		t.Fatalf("duplicate user create exit %d, want %d", code, exitFailed)
+	}
+
+	repo := userRepository.NewUserRepository(pool, pool, config.LoadQueryTimeoutConfig())
+	revoked := lru.New[string, time.Time](lru.Settings{Name: "sessions_revoked_at", MaxEntries: 10})
+	tokens := auth.NewJWTService(clock.Real(), time.Hour, &repo, revoked)
+	oldToken, err := tokens.GenerateToken(created.ManagerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tokens.ParseToken(ctx, oldToken); err != nil {
+		t.Fatalf("ParseToken before revoke error = %v", err)
+	}
+	var reset dto.AdminPasswordResetResponse
+	runJSON(t, service, &reset, "user", "reset-password", "--email", "cli@example.com")
+	var hash string
+	if err := pool.QueryRow(ctx, "SELECT password FROM manager WHERE managerid = $1;", created.ManagerID).Scan(&hash); err != nil {
+		t.Fatal(err)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(reset.Password)) != nil {
+		t.Fatalf("stored password is not the hash of the generated password %q", reset.Password)
+	}
+
+	var revokedSessions dto.AdminSessionsRevokedResponse
+	runJSON(t, service, &revokedSessions, "sessions", "revoke", "--email", "cli@example.com")
+	revokedAt, err := repo.SessionsRevokedAt(ctx, created.ManagerID)
+	if err != nil || !revokedAt.Equal(revokedSessions.SessionsRevokedAt) {
+		t.Fatalf("SessionsRevokedAt = %v, %v, want %v", revokedAt, err, revokedSessions.SessionsRevokedAt)
+	}
+	// Cache pencabutan berisi nilai sebelum revoke, jadi dibuat ulang
+	tokens = auth.NewJWTService(clock.Real(), time.Hour, &repo, lru.New[string, time.Time](lru.Settings{Name: "sessions_revoked_at", MaxEntries: 10}))
+	if _, err := tokens.ParseToken(ctx, oldToken); err == nil {
+		t.Fatal("ParseToken accepted a token issued before sessions revoke")
+	}
+
+	want := []string{"cli:alice", "cli:alice", "cli:alice"}
+	if got := auditActors(t, pool, entity.AuditEntityUser, created.ManagerID); !slices.Equal(got, want) {
+		t.Fatalf("audit actors = %v, want %v (create, reset-password, sessions revoke)", got, want)
+	}
+
+	if code, _, stderr := runCommand(t, service, "", "sessions", "revoke", "--email", "unknown@example.com", "--operator", "alice"); code != exitFailed {
+		t.Fatalf("revoke unknown manager exit %d, stderr %q, want %d", code, stderr, exitFailed)
+	}
+}
+
+func TestDepartmentReassignOnDatabase(t *testing.T) {
+	pool, service := newDBService(t)
+	ctx := context.Background()
+	from := dbtest.CreateManager(t, pool, "from@example.com")
+	to := dbtest.CreateManager(t, pool, "to@example.com")
+	department := dbtest.CreateDepartment(t, pool, from, "Engineering")
+	dbtest.CreateEmployee(t, pool, dbtest.Employee{IdentityNumber: "EMP-1", Name: "Employee 1", Gender: "male", DepartmentID: department})
+
+	var response dto.AdminDepartmentReassignResponse
+	runJSON(t, service, &response, "department", "reassign", "--id", department, "--to-manager", to)
+	want := dto.AdminDepartmentReassignResponse{DepartmentID: department, DepartmentName: "Engineering", FromManagerID: from, ToManagerID: to}
+	if response != want {
+		t.Fatalf("response = %+v, want %+v", response, want)
+	}
+	var owner string
+	if err := pool.QueryRow(ctx, "SELECT managerid FROM department WHERE departmentid = $1;", department).Scan(&owner); err != nil || owner != to {
+		t.Fatalf("department manager = %q, %v, want %s", owner, err, to)
+	}
+	for entityType, entityId := range map[string]string{entity.AuditEntityDepartment: department, entity.AuditEntityEmployee: "EMP-1"} {
+		if got := auditActors(t, pool, entityType, entityId); len(got) != 1 || got[0] != "cli:alice" {
+			t.Fatalf("%s audit actors = %v, want [cli:alice]", entityType, got)
+		}
+	}
+
+	// Manager lama menerima event delete dan manager baru event create
+	var deleted, createdEvents int
+	err := pool.QueryRow(ctx, `
+		SELECT
+			COUNT(*) FILTER (WHERE event_type IN ($1, $2) AND payload->>'managerId' = $5),
+			COUNT(*) FILTER (WHERE event_type IN ($3, $4) AND payload->>'managerId' = $6)
+		FROM outbox;`,
+		entity.EventDepartmentDeleted, entity.EventEmployeeDeleted, entity.EventDepartmentCreated, entity.EventEmployeeCreated, from, to,
+	).Scan(&deleted, &createdEvents)
+	if err != nil || deleted != 2 || createdEvents != 2 {
+		t.Fatalf("outbox deleted = %d, created = %d, %v, want 2 each", deleted, createdEvents, err)
+	}
+
+	// Menjalankan ulang tidak mencatat apa pun lagi
+	runJSON(t, service, &response, "department", "reassign", "--id", department, "--to-manager", to)
+	if response.FromManagerID != to {
+		t.Fatalf("second reassign from = %s, want %s", response.FromManagerID, to)
+	}
+	if got := auditActors(t, pool, entity.AuditEntityDepartment, department); len(got) != 1 {
+		t.Fatalf("department audit actors after a no-op reassign = %v", got)
+	}
+
+	const unknown = "5d1f0c7a-2b3e-4c4d-8e9f-0a1b2c3d4e5f"
+	for _, args := range [][]string{
+		{"department", "reassign", "--id", unknown, "--to-manager", to},
+		{"department", "reassign", "--id", department, "--to-manager", unknown},
+	} {
+		if code, _, _ := runCommand(t, service, "", append(args, "--operator", "alice")...); code != exitFailed {
+			t.Fatalf("admin %v exit %d, want %d", args, code, exitFailed)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	adminService "github.com/levensspel/go-gin-template/service/admin"
+	"github.com/levensspel/go-gin-template/validation"
+)
+
+const testManagerID = "0b7c2d2e-4f4a-4b8e-9c59-2f1d8f6a3e10"
+
+var testRevokedAt = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// fakeService mencatat input terakhir dan mengembalikan err untuk semua
+// perintah. Department yang dipindahkan sebelumnya milik fromManagerID.
+type fakeService struct {
+	err           error
+	fromManagerID string
+	requestID     any
+	create        dto.AdminUserCreateRequest
+	user          dto.AdminUserRequest
+	reassign      dto.AdminDepartmentReassignRequest
+}
+
+func (f *fakeService) CreateUser(ctx context.Context, input dto.AdminUserCreateRequest) (dto.AdminUserResponse, error) {
+	f.create, f.requestID = input, ctx.Value(helper.ContextKeyRequestID)
+	return dto.AdminUserResponse{ManagerID: testManagerID, Email: input.Email}, f.err
+}
+
+func (f *fakeService) ResetPassword(ctx context.Context, input dto.AdminUserRequest) (dto.AdminPasswordResetResponse, error) {
+	f.user = input
+	return dto.AdminPasswordResetResponse{ManagerID: testManagerID, Email: input.Email, Password: "generated", SessionsRevokedAt: testRevokedAt}, f.err
+}
+
+func (f *fakeService) RevokeSessions(ctx context.Context, input dto.AdminUserRequest) (dto.AdminSessionsRevokedResponse, error) {
+	f.user = input
+	return dto.AdminSessionsRevokedResponse{ManagerID: testManagerID, Email: input.Email, SessionsRevokedAt: testRevokedAt}, f.err
+}
+
+func (f *fakeService) ReassignDepartment(ctx context.Context, input dto.AdminDepartmentReassignRequest) (dto.AdminDepartmentReassignResponse, error) {
+	f.reassign = input
+	return dto.AdminDepartmentReassignResponse{
+		DepartmentID:   input.DepartmentID,
+		DepartmentName: "Engineering",
+		FromManagerID:  f.fromManagerID,
+		ToManagerID:    input.ToManagerID,
+	}, f.err
+}
+
+// runCommand menjalankan perintah dengan service dan stdin tersebut.
+// service nil berarti perintah tidak boleh sampai membuat service.
+func runCommand(t *testing.T, service adminService.AdminService, stdin string, args ...string) (code int, stdout, stderr string) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	code = run(args, strings.NewReader(stdin), &out, &errOut, func() adminService.AdminService {
+		if service == nil {
+			t.Fatal("service was created for an invalid command")
+		}
+		return service
+	})
+	return code, out.String(), errOut.String()
+}
+
+func TestRunCommands(t *testing.T) {
+	t.Setenv("ADMIN_OPERATOR", "")
+	for _, tt := range []struct {
+		name     string
+		args     []string
+		stdin    string
+		wantText string
+		check    func(t *testing.T, service *fakeService)
+	}{
+		{
+			name:     "user create",
+			args:     []string{"user", "create", "--email", "new@example.com", "--password", "correct-horse", "--operator", "alice"},
+			wantText: "created manager " + testManagerID + " (new@example.com)\n",
+			check: func(t *testing.T, service *fakeService) {
+				want := dto.AdminUserCreateRequest{Operator: "alice", Email: "new@example.com", Password: "correct-horse"}
+				if service.create != want {
+					t.Fatalf("CreateUser input = %+v, want %+v", service.create, want)
+				}
+				// Semua audit log satu perintah memakai request id yang sama
+				if id, _ := service.requestID.(string); !strings.HasPrefix(id, "cli-") {
+					t.Fatalf("request id = %v, want a cli- id", service.requestID)
+				}
+			},
+		},
+		{
+			name:     "password from stdin",
+			args:     []string{"user", "create", "--email", "new@example.com", "--password-stdin", "--operator", "alice"},
+			stdin:    "from-stdin\r\nignored\n",
+			wantText: "created manager " + testManagerID + " (new@example.com)\n",
+			check: func(t *testing.T, service *fakeService) {
+				if service.create.Password != "from-stdin" {
+					t.Fatalf("password = %q, want the first stdin line", service.create.Password)
+				}
+			},
+		},
+		{
+			name:     "reset password",
+			args:     []string{"user", "reset-password", "--email", "manager@example.com", "--operator", "alice"},
+			wantText: "reset password of manager " + testManagerID + " (manager@example.com), sessions revoked at 2026-01-02T03:04:05Z\nnew password: generated\n",
+		},
+		{
+			name:     "revoke sessions",
+			args:     []string{"sessions", "revoke", "--email", "manager@example.com", "--operator", "alice"},
+			wantText: "revoked sessions of manager " + testManagerID + " (manager@example.com) issued until 2026-01-02T03:04:05Z\n",
+			check: func(t *testing.T, service *fakeService) {
+				if want := (dto.AdminUserRequest{Operator: "alice", Email: "manager@example.com"}); service.user != want {
+					t.Fatalf("RevokeSessions input = %+v, want %+v", service.user, want)
+				}
+			},
+		},
+		{
+			name:     "department reassign",
+			args:     []string{"department", "reassign", "--id", "dept-1", "--to-manager", testManagerID, "--operator", "alice"},
+			wantText: "reassigned department dept-1 (Engineering) from manager old-manager to " + testManagerID + "\n",
+			check: func(t *testing.T, service *fakeService) {
+				want := dto.AdminDepartmentReassignRequest{Operator: "alice", DepartmentID: "dept-1", ToManagerID: testManagerID}
+				if service.reassign != want {
+					t.Fatalf("ReassignDepartment input = %+v, want %+v", service.reassign, want)
+				}
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeService{fromManagerID: "old-manager"}
+			code, stdout, stderr := runCommand(t, service, tt.stdin, tt.args...)
+			if code != 0 || stdout != tt.wantText || stderr != "" {
+				t.Fatalf("exit %d, stdout %q, stderr %q, want 0 and %q", code, stdout, stderr, tt.wantText)
+			}
+			if tt.check != nil {
+				tt.check(t, service)
+			}
+		})
+	}
+}
+
+func TestRunOperatorFromEnv(t *testing.T) {
+	t.Setenv("ADMIN_OPERATOR", "bob")
+	service := &fakeService{}
+	if code, _, stderr := runCommand(t, service, "", "sessions", "revoke", "--email", "manager@example.com"); code != 0 {
+		t.Fatalf("exit %d, stderr %q", code, stderr)
+	}
+	if service.user.Operator != "bob" {
+		t.Fatalf("operator = %q, want ADMIN_OPERATOR", service.user.Operator)
+	}
+
+	// --operator menimpa ADMIN_OPERATOR
+	if code, _, _ := runCommand(t, service, "", "sessions", "revoke", "--email", "manager@example.com", "--operator", "alice"); code != 0 || service.user.Operator != "alice" {
+		t.Fatalf("exit %d, operator = %q, want alice", code, service.user.Operator)
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	service := &fakeService{}
+	code, stdout, _ := runCommand(t, service, "", "sessions", "revoke", "--email", "manager@example.com", "--operator", "alice", "--json")
+	if code != 0 {
+		t.Fatalf("exit %d", code)
+	}
+	var response dto.AdminSessionsRevokedResponse
+	if err := json.Unmarshal([]byte(stdout), &response); err != nil {
+		t.Fatalf("stdout %q is not JSON: %v", stdout, err)
+	}
+	want := dto.AdminSessionsRevokedResponse{ManagerID: testManagerID, Email: "manager@example.com", SessionsRevokedAt: testRevokedAt}
+	if response != want {
+		t.Fatalf("response = %+v, want %+v", response, want)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	invalid := validation.FieldErrors{"operator": "operator is a required field", "email": "email must be a valid email address"}
+	for _, tt := range []struct {
+		name       string
+		err        error
+		asJSON     bool
+		wantStdout string
+		wantStderr string
+	}{
+		{name: "validation", err: invalid, wantStderr: "error: invalid arguments\n  email: email must be a valid email address\n  operator: operator is a required field\n"},
+		{
+			name:       "validation as JSON",
+			err:        invalid,
+			asJSON:     true,
+			wantStdout: `{"error":"invalid arguments","fields":{"email":"email must be a valid email address","operator":"operator is a required field"}}` + "\n",
+		},
+		{name: "not found", err: helper.ErrNotFound, wantStderr: "error: " + helper.ErrNotFound.Error() + "\n"},
+		{name: "not found as JSON", err: helper.ErrNotFound, asJSON: true, wantStdout: `{"error":"` + helper.ErrNotFound.Error() + `"}` + "\n"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			args := []string{"sessions", "revoke", "--email", "manager@example.com"}
+			if tt.asJSON {
+				args = append(args, "--json")
+			}
+			code, stdout, stderr := runCommand(t, &fakeService{err: tt.err}, "", args...)
+			if code != exitFailed || stdout != tt.wantStdout || stderr != tt.wantStderr {
+				t.Fatalf("exit %d, stdout %q, stderr %q, want %d, %q, %q", code, stdout, stderr, exitFailed, tt.wantStdout, tt.wantStderr)
+			}
+		})
+	}
+}
+
+func TestRunUsageErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		args       []string
+		wantStderr string
+	}{
+		{name: "without command", args: []string{"user"}, wantStderr: "Usage: admin"},
+		{name: "unknown command", args: []string{"user", "delete"}, wantStderr: `unknown command "user delete"`},
+		{name: "unknown flag", args: []string{"user", "create", "--name", "x"}, wantStderr: "flag provided but not defined: -name"},
+		{name: "positional arguments", args: []string{"sessions", "revoke", "manager@example.com"}, wantStderr: "unexpected arguments: manager@example.com"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCommand(t, nil, "", tt.args...)
+			if code != exitUsage || stdout != "" || !strings.Contains(stderr, tt.wantStderr) {
+				t.Fatalf("exit %d, stdout %q, stderr %q, want %d and %q", code, stdout, stderr, exitUsage, tt.wantStderr)
+			}
+		})
+	}
+
+	// --password dan --password-stdin baru dicek saat perintah dijalankan
+	service := &fakeService{}
+	code, _, stderr := runCommand(t, service, "correct-horse\n", "user", "create", "--email", "new@example.com", "--password", "x", "--password-stdin")
+	if code != exitUsage || !strings.Contains(stderr, "cannot be used together") || service.create != (dto.AdminUserCreateRequest{}) {
+		t.Fatalf("exit %d, stderr %q, CreateUser input = %+v", code, stderr, service.create)
+	}
+}
+
+func TestRunReassignToSameManager(t *testing.T) {
+	service := &fakeService{fromManagerID: testManagerID}
+	code, stdout, _ := runCommand(t, service, "", "department", "reassign", "--id", "dept-1", "--to-manager", testManagerID, "--operator", "alice")
+	if want := "department dept-1 (Engineering) already belongs to manager " + testManagerID + "\n"; code != 0 || stdout != want {
+		t.Fatalf("exit %d, stdout %q, want %q", code, stdout, want)
+	}
+}
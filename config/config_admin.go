@@ -0,0 +1,29 @@
+package config
+
+type AdminConfig struct {
+	// ManagerIDs berisi id manager yang memiliki role admin.
+	ManagerIDs []string
+	// IPAllowlist berisi daftar CIDR (atau IP tunggal) yang boleh mengakses
+	// route admin. Kosong berarti tanpa pembatasan.
+	IPAllowlist []string
+	// TrustedProxyDepth adalah jumlah proxy di depan service yang dipercaya
+	// untuk menambahkan entry X-Forwarded-For, dan X-Forwarded-Proto serta
+	// X-Forwarded-Host untuk URL absolut di header Link.
+	TrustedProxyDepth int
+
+	// PprofEnabled memasang endpoint /debug/pprof.
+	PprofEnabled bool
+	// PprofToken adalah token statis alternatif (header X-Debug-Token)
+	// untuk mengakses /debug/pprof tanpa bearer token admin.
+	PprofToken string
+}
+
+func LoadAdminConfig() *AdminConfig {
+	return &AdminConfig{
+		ManagerIDs:        getEnvList("ADMIN_MANAGER_IDS"),
+		IPAllowlist:       getEnvList("ADMIN_IP_ALLOWLIST"),
+		TrustedProxyDepth: getEnvInt("TRUSTED_PROXY_DEPTH", 0),
+		PprofEnabled:      getEnvBool("PPROF_ENABLED", false),
+		PprofToken:        getEnv("PPROF_TOKEN", ""),
+	}
+}
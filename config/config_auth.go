@@ -0,0 +1,20 @@
+package config
+
+import "time"
+
+type AuthConfig struct {
+	// TokenTTL adalah umur JWT sejak diterbitkan, 0 berarti token tidak
+	// kedaluwarsa. Harus lebih lama dari cache token login (30 menit).
+	TokenTTL time.Duration
+	// RevocationCacheTTL adalah berapa lama waktu pencabutan sesi per user
+	// disimpan di memori. Sesi yang dicabut dari instance lain (atau dari
+	// cmd/admin) paling lama ditolak setelah TTL ini.
+	RevocationCacheTTL time.Duration
+}
+
+func LoadAuthConfig() *AuthConfig {
+	return &AuthConfig{
+		TokenTTL:           getEnvDuration("JWT_TTL", 0),
+		RevocationCacheTTL: getEnvDuration("JWT_REVOCATION_CACHE_TTL", 30*time.Second),
+	}
+}
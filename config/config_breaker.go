@@ -0,0 +1,18 @@
+package config
+
+import "time"
+
+type BreakerConfig struct {
+	// FailureThreshold adalah jumlah kegagalan koneksi berturut-turut
+	// sebelum request ke database langsung ditolak.
+	FailureThreshold int
+	// Cooldown adalah jeda sebelum satu request percobaan diizinkan lagi.
+	Cooldown time.Duration
+}
+
+func LoadBreakerConfig() *BreakerConfig {
+	return &BreakerConfig{
+		FailureThreshold: getEnvInt("DB_BREAKER_FAILURE_THRESHOLD", 5),
+		Cooldown:         getEnvDuration("DB_BREAKER_COOLDOWN", 10*time.Second),
+	}
+}
@@ -0,0 +1,25 @@
+package config
+
+import "time"
+
+type CacheConfig struct {
+	// ProfileTTL dan DepartmentListTTL membatasi berapa lama data lama
+	// terlihat di instance lain, karena invalidasi hanya berlaku di instance
+	// yang melakukan write.
+	ProfileTTL        time.Duration
+	DepartmentListTTL time.Duration
+	// EmployeeFirstPageTTL adalah umur halaman pertama GET /v1/employee
+	// tanpa filter di Redis. Dipakai hanya jika REDIS_URL diset.
+	EmployeeFirstPageTTL time.Duration
+	// MaxEntries adalah jumlah entry maksimum per cache.
+	MaxEntries int
+}
+
+func LoadCacheConfig() *CacheConfig {
+	return &CacheConfig{
+		ProfileTTL:           getEnvDuration("CACHE_PROFILE_TTL", time.Minute),
+		DepartmentListTTL:    getEnvDuration("CACHE_DEPARTMENT_LIST_TTL", 30*time.Second),
+		MaxEntries:           getEnvInt("CACHE_MAX_ENTRIES", 10000),
+		EmployeeFirstPageTTL: getEnvDuration("CACHE_EMPLOYEE_FIRST_PAGE_TTL", 10*time.Second),
+	}
+}
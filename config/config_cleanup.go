@@ -0,0 +1,54 @@
+package config
+
+import "time"
+
+// CleanupConfig mengatur penghapusan baris yang sudah tidak dipakai (event
+// outbox terkirim, delivery webhook selesai, upload presign yang tidak
+// dikonfirmasi, file yang tidak dirujuk, API key kedaluwarsa, callback
+// integrasi yang sudah diproses), lihat purge.Cleaner. Interval 0 mematikan cleanup terjadwal
+// tabel tersebut; cleanup manual lewat route admin tetap bisa dipakai.
+type CleanupConfig struct {
+	OutboxInterval           time.Duration
+	OutboxRetention          time.Duration
+	WebhookDeliveryInterval  time.Duration
+	WebhookDeliveryRetention time.Duration
+	// FileUploadRetention dihitung dari waktu kedaluwarsa presign, bukan
+	// waktu dibuat.
+	FileUploadInterval  time.Duration
+	FileUploadRetention time.Duration
+	// FileGracePeriod dihitung sejak file terakhir kali tidak dirujuk
+	// employee atau profil manager, atau sejak di-upload.
+	FileInterval    time.Duration
+	FileGracePeriod time.Duration
+	// APIKeyRetention dihitung dari expires_at, agar key yang baru
+	// kedaluwarsa masih terlihat di daftar key manager.
+	APIKeyInterval  time.Duration
+	APIKeyRetention time.Duration
+	// IntegrationCallbackRetention dihitung dari signed_at dan tidak pernah
+	// lebih pendek dari INTEGRATION_SIGNATURE_TOLERANCE, lihat
+	// purge.NewCleanerInject.
+	IntegrationCallbackInterval  time.Duration
+	IntegrationCallbackRetention time.Duration
+	// BatchSize dan BatchPause membatasi lama lock seperti PurgeConfig.
+	BatchSize  int
+	BatchPause time.Duration
+}
+
+func LoadCleanupConfig() *CleanupConfig {
+	return &CleanupConfig{
+		OutboxInterval:               getEnvDuration("CLEANUP_OUTBOX_INTERVAL", time.Hour),
+		OutboxRetention:              getEnvDuration("CLEANUP_OUTBOX_RETENTION", 7*24*time.Hour),
+		WebhookDeliveryInterval:      getEnvDuration("CLEANUP_WEBHOOK_DELIVERY_INTERVAL", time.Hour),
+		WebhookDeliveryRetention:     getEnvDuration("CLEANUP_WEBHOOK_DELIVERY_RETENTION", 30*24*time.Hour),
+		FileUploadInterval:           getEnvDuration("CLEANUP_FILE_UPLOAD_INTERVAL", time.Hour),
+		FileUploadRetention:          getEnvDuration("CLEANUP_FILE_UPLOAD_RETENTION", time.Hour),
+		FileInterval:                 getEnvDuration("CLEANUP_FILE_INTERVAL", time.Hour),
+		FileGracePeriod:              getEnvDuration("CLEANUP_FILE_GRACE_PERIOD", 24*time.Hour),
+		APIKeyInterval:               getEnvDuration("CLEANUP_API_KEY_INTERVAL", 24*time.Hour),
+		APIKeyRetention:              getEnvDuration("CLEANUP_API_KEY_RETENTION", 30*24*time.Hour),
+		IntegrationCallbackInterval:  getEnvDuration("CLEANUP_INTEGRATION_CALLBACK_INTERVAL", time.Hour),
+		IntegrationCallbackRetention: getEnvDuration("CLEANUP_INTEGRATION_CALLBACK_RETENTION", 7*24*time.Hour),
+		BatchSize:                    getEnvInt("CLEANUP_BATCH_SIZE", 500),
+		BatchPause:                   getEnvDuration("CLEANUP_BATCH_PAUSE", 100*time.Millisecond),
+	}
+}
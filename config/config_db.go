@@ -4,13 +4,22 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
 	DatabaseURL string
-	Port        string
+	// ReplicaDatabaseURL adalah DSN read replica (opsional). Kosong berarti
+	// semua query dijalankan di primary.
+	ReplicaDatabaseURL string
+	Port               string
+	// AutoMigrate menjalankan migrasi database/migrations saat startup.
+	// Matikan jika migrasi dijalankan terpisah sebelum deploy.
+	AutoMigrate bool
 }
 
 func LoadConfig() *Config {
@@ -31,8 +40,10 @@ func LoadConfig() *Config {
 	)
 
 	return &Config{
-		DatabaseURL: databaseURL,
-		Port:        port,
+		DatabaseURL:        databaseURL,
+		ReplicaDatabaseURL: getEnv("REPLICA_DATABASE_URL", ""),
+		Port:               port,
+		AutoMigrate:        getEnvBool("DB_AUTO_MIGRATE", true),
 	}
 }
 
@@ -42,3 +53,67 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getEnvInt(key string, fallback int) int {
+	value, exists := os.LookupEnv(key)
+	if !exists || value == "" {
+		return fallback
+	}
+	result, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Invalid value for %s: %q, using default %d", key, value, fallback)
+		return fallback
+	}
+	return result
+}
+
+// getEnvList membaca env berisi nilai yang dipisahkan koma.
+func getEnvList(key string) []string {
+	var result []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, exists := os.LookupEnv(key)
+	if !exists || value == "" {
+		return fallback
+	}
+	result, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Invalid duration for %s: %q, using default %s", key, value, fallback)
+		return fallback
+	}
+	return result
+}
+
+func getEnvFloat(key string, fallback float64) float64 {
+	value, exists := os.LookupEnv(key)
+	if !exists || value == "" {
+		return fallback
+	}
+	result, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Printf("Invalid number for %s: %q, using default %g", key, value, fallback)
+		return fallback
+	}
+	return result
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	value, exists := os.LookupEnv(key)
+	if !exists || value == "" {
+		return fallback
+	}
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Invalid boolean for %s: %q, using default %t", key, value, fallback)
+		return fallback
+	}
+	return result
+}
@@ -0,0 +1,48 @@
+package config
+
+import "time"
+
+// Nilai EventPublisherConfig.Publisher
+const (
+	EventPublisherNone = "none"
+	EventPublisherLog  = "log"
+	EventPublisherNATS = "nats"
+)
+
+type EventPublisherConfig struct {
+	// Publisher adalah tujuan event outbox: none (dibuang), log, atau nats.
+	// Webhook tetap menerima event apa pun pilihannya.
+	Publisher string
+
+	NATSURL string
+	// NATSCredsFile (file .creds) atau NATSUser/NATSPassword, kosong jika
+	// server tidak memakai auth.
+	NATSCredsFile string
+	NATSUser      string
+	NATSPassword  string
+	// NATSStream dibuat dengan subject "<NATSSubjectPrefix>.>" jika belum
+	// ada. Stream yang sudah ada tidak diubah.
+	NATSStream         string
+	NATSSubjectPrefix  string
+	NATSConnectTimeout time.Duration
+	// NATSReconnectWait dan NATSMaxReconnectWait mengatur jeda reconnect:
+	// NATSReconnectWait * 2^attempts, paling lama NATSMaxReconnectWait.
+	// Reconnect dicoba terus, termasuk saat koneksi pertama gagal.
+	NATSReconnectWait    time.Duration
+	NATSMaxReconnectWait time.Duration
+}
+
+func LoadEventPublisherConfig() *EventPublisherConfig {
+	return &EventPublisherConfig{
+		Publisher:            getEnv("EVENT_PUBLISHER", EventPublisherNone),
+		NATSURL:              getEnv("NATS_URL", "nats://localhost:4222"),
+		NATSCredsFile:        getEnv("NATS_CREDS_FILE", ""),
+		NATSUser:             getEnv("NATS_USER", ""),
+		NATSPassword:         getEnv("NATS_PASSWORD", ""),
+		NATSStream:           getEnv("NATS_STREAM", "EVENTS"),
+		NATSSubjectPrefix:    getEnv("NATS_SUBJECT_PREFIX", "events"),
+		NATSConnectTimeout:   getEnvDuration("NATS_CONNECT_TIMEOUT", 5*time.Second),
+		NATSReconnectWait:    getEnvDuration("NATS_RECONNECT_WAIT", time.Second),
+		NATSMaxReconnectWait: getEnvDuration("NATS_MAX_RECONNECT_WAIT", 30*time.Second),
+	}
+}
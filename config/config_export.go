@@ -0,0 +1,14 @@
+package config
+
+type ExportConfig struct {
+	// XLSXMaxRows adalah jumlah employee maksimum dalam satu export XLSX.
+	// Workbook baru bisa dikirim setelah selesai dibuat, sehingga export
+	// yang lebih besar harus memakai CSV atau NDJSON yang di-stream.
+	XLSXMaxRows int
+}
+
+func LoadExportConfig() *ExportConfig {
+	return &ExportConfig{
+		XLSXMaxRows: getEnvInt("EXPORT_XLSX_MAX_ROWS", 100000),
+	}
+}
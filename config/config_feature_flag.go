@@ -0,0 +1,27 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+type FeatureFlagConfig struct {
+	// Enabled adalah daftar flag yang aktif secara default. Flag lain mati
+	// kecuali punya override untuk manager tertentu.
+	Enabled []string
+	// CacheTTL membatasi berapa lama override lama terlihat di instance lain,
+	// karena invalidasi hanya berlaku di instance yang mengubahnya.
+	CacheTTL time.Duration
+}
+
+func LoadFeatureFlagConfig() *FeatureFlagConfig {
+	enabled := getEnvList("FEATURE_FLAGS")
+	// fuzzy_search sudah tersedia sebelum ada feature flag
+	if _, exists := os.LookupEnv("FEATURE_FLAGS"); !exists {
+		enabled = []string{"fuzzy_search"}
+	}
+	return &FeatureFlagConfig{
+		Enabled:  enabled,
+		CacheTTL: getEnvDuration("FEATURE_FLAG_CACHE_TTL", 30*time.Second),
+	}
+}
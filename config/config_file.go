@@ -0,0 +1,35 @@
+package config
+
+import "time"
+
+type FileConfig struct {
+	// MaxUploadBytes adalah ukuran maksimum satu file yang di-upload.
+	MaxUploadBytes int64
+	// BaseURL adalah awal URL server ini, mis. https://api.example.com. Jika
+	// diisi, uri file adalah <BaseURL>/files/<fileId> yang dilayani
+	// GET /files/:id, bukan URL langsung ke storage.
+	BaseURL string
+	// PresignExpiry adalah masa berlaku URL upload langsung ke storage dari
+	// POST /v1/file/presign. Upload harus dikonfirmasi sebelum waktu ini.
+	PresignExpiry time.Duration
+	// RestrictEmployeeImages mewajibkan employeeImageUri berupa uri file
+	// hasil upload milik manager yang sama. Jika false, semua URL diterima.
+	RestrictEmployeeImages bool
+	// MaxImageDimension dan MaxImagePixels membatasi lebar/tinggi dan
+	// lebar x tinggi gambar yang di-upload, dibaca dari header gambar tanpa
+	// men-decode pixel-nya, agar file kecil yang mengembang menjadi gambar
+	// raksasa ditolak sejak awal.
+	MaxImageDimension int
+	MaxImagePixels    int
+}
+
+func LoadFileConfig() *FileConfig {
+	return &FileConfig{
+		MaxUploadBytes:         int64(getEnvInt("FILE_MAX_UPLOAD_BYTES", 2<<20)),
+		BaseURL:                getEnv("FILE_BASE_URL", ""),
+		PresignExpiry:          getEnvDuration("FILE_PRESIGN_EXPIRY", 15*time.Minute),
+		RestrictEmployeeImages: getEnvBool("FILE_RESTRICT_EMPLOYEE_IMAGES", false),
+		MaxImageDimension:      getEnvInt("FILE_MAX_IMAGE_DIMENSION", 10_000),
+		MaxImagePixels:         getEnvInt("FILE_MAX_IMAGE_PIXELS", 40_000_000),
+	}
+}
@@ -0,0 +1,20 @@
+package config
+
+// GraphQLConfig mengatur /v1/graphql, lihat package graph.
+type GraphQLConfig struct {
+	// MaxDepth adalah kedalaman field bersarang terbesar dalam satu query
+	MaxDepth int
+	// MaxComplexity adalah kompleksitas query terbesar. Field list dihitung
+	// sebanyak limit dikali kompleksitas field di dalamnya.
+	MaxComplexity int
+	// Introspection mengizinkan query __schema dan __type
+	Introspection bool
+}
+
+func LoadGraphQLConfig() *GraphQLConfig {
+	return &GraphQLConfig{
+		MaxDepth:      getEnvInt("GRAPHQL_MAX_DEPTH", 6),
+		MaxComplexity: getEnvInt("GRAPHQL_MAX_COMPLEXITY", 1000),
+		Introspection: getEnvBool("GRAPHQL_INTROSPECTION", true),
+	}
+}
@@ -0,0 +1,16 @@
+package config
+
+// GRPCConfig mengatur listener gRPC untuk EmployeeService dan
+// DepartmentService, lihat package grpcserver.
+type GRPCConfig struct {
+	Enabled bool
+	// Port terpisah dari PORT HTTP; host dan TLS mengikuti ServerConfig
+	Port string
+}
+
+func LoadGRPCConfig() *GRPCConfig {
+	return &GRPCConfig{
+		Enabled: getEnvBool("GRPC_ENABLED", false),
+		Port:    getEnv("GRPC_PORT", "9090"),
+	}
+}
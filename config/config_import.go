@@ -0,0 +1,27 @@
+package config
+
+import "time"
+
+type ImportConfig struct {
+	// MaxUploadBytes membatasi ukuran body multipart sebelum dibaca.
+	MaxUploadBytes int64
+	// BatchSize adalah jumlah baris yang di-insert per transaksi.
+	BatchSize int
+	// MaxRows adalah jumlah baris data maksimum dalam satu file.
+	MaxRows int
+	// Deadline adalah batas waktu total satu proses import.
+	Deadline time.Duration
+	// CopyThreshold adalah jumlah baris valid setelahnya sisa file dikirim
+	// lewat COPY, bukan batch insert. 0 menonaktifkan COPY.
+	CopyThreshold int
+}
+
+func LoadImportConfig() *ImportConfig {
+	return &ImportConfig{
+		MaxUploadBytes: int64(getEnvInt("IMPORT_MAX_UPLOAD_BYTES", 10<<20)),
+		BatchSize:      getEnvInt("IMPORT_BATCH_SIZE", 500),
+		MaxRows:        getEnvInt("IMPORT_MAX_ROWS", 10000),
+		Deadline:       getEnvDuration("IMPORT_DEADLINE", 30*time.Second),
+		CopyThreshold:  getEnvInt("IMPORT_COPY_THRESHOLD", 5000),
+	}
+}
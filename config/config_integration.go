@@ -0,0 +1,37 @@
+package config
+
+import (
+	"log"
+	"strings"
+	"time"
+)
+
+type IntegrationConfig struct {
+	// Secrets memetakan id integrasi partner ke secret HMAC-nya, dari
+	// INTEGRATION_SECRETS berformat "partner-a:secret,partner-b:secret".
+	Secrets map[string]string
+	// SignatureTolerance adalah selisih maksimum timestamp callback dengan
+	// waktu server, ke masa lalu maupun masa depan.
+	SignatureTolerance time.Duration
+	// MaxBodyBytes membatasi ukuran body callback.
+	MaxBodyBytes int64
+}
+
+func LoadIntegrationConfig() *IntegrationConfig {
+	secrets := map[string]string{}
+	for i, entry := range getEnvList("INTEGRATION_SECRETS") {
+		id, secret, ok := strings.Cut(entry, ":")
+		id, secret = strings.TrimSpace(id), strings.TrimSpace(secret)
+		if !ok || id == "" || secret == "" {
+			// Entry tidak ikut dicetak karena bisa berisi secret
+			log.Printf("Ignoring invalid INTEGRATION_SECRETS entry %d, expected id:secret", i+1)
+			continue
+		}
+		secrets[id] = secret
+	}
+	return &IntegrationConfig{
+		Secrets:            secrets,
+		SignatureTolerance: getEnvDuration("INTEGRATION_SIGNATURE_TOLERANCE", 5*time.Minute),
+		MaxBodyBytes:       int64(getEnvInt("INTEGRATION_MAX_BODY_BYTES", 1<<20)),
+	}
+}
@@ -0,0 +1,37 @@
+package config
+
+import "time"
+
+type LoggerConfig struct {
+	// Async menulis log lewat antrian dan goroutine writer, sehingga request
+	// tidak menunggu penulisan file.
+	Async bool
+	// BufferSize adalah kapasitas antrian. Jika penuh, entry tertua dibuang
+	// dan dihitung di metric logs_dropped_total.
+	BufferSize int
+	// SyncErrors menulis entry level Error ke atas langsung tanpa antrian,
+	// agar tidak ikut hilang jika proses mati mendadak.
+	SyncErrors bool
+
+	// Sampling membuang entry dengan level dan pesan yang sama setelah
+	// SamplingPerSecond kali dalam satu detik (SamplingErrorPerSecond untuk
+	// level Error ke atas). Jumlah yang dibuang ditulis setiap
+	// SamplingSummaryInterval.
+	Sampling                bool
+	SamplingPerSecond       int
+	SamplingErrorPerSecond  int
+	SamplingSummaryInterval time.Duration
+}
+
+func LoadLoggerConfig() *LoggerConfig {
+	return &LoggerConfig{
+		Async:      getEnvBool("LOG_ASYNC", false),
+		BufferSize: getEnvInt("LOG_BUFFER_SIZE", 4096),
+		SyncErrors: getEnvBool("LOG_ASYNC_SYNC_ERRORS", true),
+
+		Sampling:                getEnvBool("LOG_SAMPLING", true),
+		SamplingPerSecond:       getEnvInt("LOG_SAMPLING_PER_SECOND", 100),
+		SamplingErrorPerSecond:  getEnvInt("LOG_SAMPLING_ERROR_PER_SECOND", 1000),
+		SamplingSummaryInterval: getEnvDuration("LOG_SAMPLING_SUMMARY_INTERVAL", 10*time.Second),
+	}
+}
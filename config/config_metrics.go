@@ -0,0 +1,14 @@
+package config
+
+type MetricsConfig struct {
+	// PerManager memberi label manager id pada metric bisnis (employee dan
+	// department). Kardinalitasnya sebanding jumlah manager, sehingga mati
+	// secara default dan semua manager digabung di label "all".
+	PerManager bool
+}
+
+func LoadMetricsConfig() *MetricsConfig {
+	return &MetricsConfig{
+		PerManager: getEnvBool("METRICS_PER_MANAGER", false),
+	}
+}
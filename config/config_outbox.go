@@ -0,0 +1,26 @@
+package config
+
+import "time"
+
+type OutboxConfig struct {
+	// PollInterval adalah jeda antar pengecekan event yang belum terkirim
+	// ketika batch terakhir tidak penuh.
+	PollInterval time.Duration
+	BatchSize    int
+	// RetryBackoff dan MaxRetryBackoff mengatur jeda pengiriman ulang:
+	// RetryBackoff * 2^attempts, paling lama MaxRetryBackoff.
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+	// PublishTimeout membatasi satu pemanggilan Publisher.Publish.
+	PublishTimeout time.Duration
+}
+
+func LoadOutboxConfig() *OutboxConfig {
+	return &OutboxConfig{
+		PollInterval:    getEnvDuration("OUTBOX_POLL_INTERVAL", time.Second),
+		BatchSize:       getEnvInt("OUTBOX_BATCH_SIZE", 100),
+		RetryBackoff:    getEnvDuration("OUTBOX_RETRY_BACKOFF", time.Second),
+		MaxRetryBackoff: getEnvDuration("OUTBOX_MAX_RETRY_BACKOFF", 5*time.Minute),
+		PublishTimeout:  getEnvDuration("OUTBOX_PUBLISH_TIMEOUT", 5*time.Second),
+	}
+}
@@ -0,0 +1,13 @@
+package config
+
+type PaginationConfig struct {
+	// CursorSecret dipakai untuk menandatangani cursor pagination agar client
+	// tidak bisa membuat posisi sembarang. Default memakai JWT_SECRET_KEY.
+	CursorSecret []byte
+}
+
+func LoadPaginationConfig() *PaginationConfig {
+	return &PaginationConfig{
+		CursorSecret: []byte(getEnv("CURSOR_SECRET", getEnv("JWT_SECRET_KEY", ""))),
+	}
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+type PoolConfig struct {
+	MaxConns          int32
+	MinConns          int32
+	MaxConnLifetime   time.Duration
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+	// ConnectTimeout membatasi waktu membuka satu koneksi baru, sehingga
+	// request tidak menunggu lama saat database tidak bisa dijangkau.
+	ConnectTimeout time.Duration
+
+	// QueryExecMode adalah nama pgx.QueryExecMode: cache_statement (default
+	// pgx), cache_describe, describe_exec, exec, atau simple_protocol.
+	// Untuk pgbouncer mode transaction pakai simple_protocol, karena mode
+	// cache_statement membuat prepared statement bernama per koneksi.
+	QueryExecMode string
+	// StatementCacheCapacity dipakai oleh cache_statement.
+	StatementCacheCapacity int
+	// DescriptionCacheCapacity dipakai oleh cache_describe.
+	DescriptionCacheCapacity int
+}
+
+const (
+	QueryExecModeCacheStatement = "cache_statement"
+	QueryExecModeCacheDescribe  = "cache_describe"
+	QueryExecModeDescribeExec   = "describe_exec"
+	QueryExecModeExec           = "exec"
+	QueryExecModeSimpleProtocol = "simple_protocol"
+)
+
+// LoadPoolConfig membaca pengaturan pgxpool dari env. Berbeda dengan config
+// lain, nilai yang tidak valid tidak diganti default melainkan dikembalikan
+// sebagai error agar salah konfigurasi langsung terlihat saat startup.
+func LoadPoolConfig() (*PoolConfig, error) {
+	var errs []error
+
+	maxConns := parsePoolInt("DB_MAX_CONNS", int32(max(4, runtime.NumCPU())), &errs)
+	minConns := parsePoolInt("DB_MIN_CONNS", 0, &errs)
+
+	poolConfig := &PoolConfig{
+		MaxConns:          maxConns,
+		MinConns:          minConns,
+		MaxConnLifetime:   parsePoolDuration("DB_MAX_CONN_LIFETIME", time.Hour, &errs),
+		MaxConnIdleTime:   parsePoolDuration("DB_MAX_CONN_IDLE_TIME", 30*time.Minute, &errs),
+		HealthCheckPeriod: parsePoolDuration("DB_HEALTH_CHECK_PERIOD", time.Minute, &errs),
+		ConnectTimeout:    parsePoolDuration("DB_CONNECT_TIMEOUT", 5*time.Second, &errs),
+
+		QueryExecMode:            getEnv("DB_QUERY_EXEC_MODE", QueryExecModeCacheStatement),
+		StatementCacheCapacity:   int(parsePoolInt("DB_STATEMENT_CACHE_CAPACITY", 512, &errs)),
+		DescriptionCacheCapacity: int(parsePoolInt("DB_DESCRIPTION_CACHE_CAPACITY", 512, &errs)),
+	}
+
+	if maxConns < 1 {
+		errs = append(errs, fmt.Errorf("DB_MAX_CONNS must be at least 1, got %d", maxConns))
+	}
+	if minConns < 0 {
+		errs = append(errs, fmt.Errorf("DB_MIN_CONNS must not be negative, got %d", minConns))
+	}
+	if minConns > maxConns {
+		errs = append(errs, fmt.Errorf("DB_MIN_CONNS (%d) must not be greater than DB_MAX_CONNS (%d)", minConns, maxConns))
+	}
+
+	if poolConfig.StatementCacheCapacity < 0 {
+		errs = append(errs, fmt.Errorf("DB_STATEMENT_CACHE_CAPACITY must not be negative, got %d", poolConfig.StatementCacheCapacity))
+	}
+	if poolConfig.DescriptionCacheCapacity < 0 {
+		errs = append(errs, fmt.Errorf("DB_DESCRIPTION_CACHE_CAPACITY must not be negative, got %d", poolConfig.DescriptionCacheCapacity))
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid database pool configuration: %w", errors.Join(errs...))
+	}
+	return poolConfig, nil
+}
+
+func parsePoolInt(key string, fallback int32, errs *[]error) int32 {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	result, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s must be an integer, got %q", key, value))
+		return fallback
+	}
+	return int32(result)
+}
+
+func parsePoolDuration(key string, fallback time.Duration, errs *[]error) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	result, err := time.ParseDuration(value)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("%s must be a duration (e.g. 30s, 5m), got %q", key, value))
+		return fallback
+	}
+	if result <= 0 {
+		*errs = append(*errs, fmt.Errorf("%s must be positive, got %s", key, result))
+		return fallback
+	}
+	return result
+}
@@ -0,0 +1,21 @@
+package config
+
+import "time"
+
+type PoolStatsConfig struct {
+	// Interval adalah jarak pembacaan pool.Stat() untuk metric.
+	Interval time.Duration
+	// EmptyAcquireWarnThreshold adalah jumlah acquire yang harus menunggu
+	// koneksi (pool kosong) dalam satu EmptyAcquireWindow sebelum warning
+	// dicatat. 0 mematikan warning.
+	EmptyAcquireWarnThreshold int
+	EmptyAcquireWindow        time.Duration
+}
+
+func LoadPoolStatsConfig() *PoolStatsConfig {
+	return &PoolStatsConfig{
+		Interval:                  getEnvDuration("DB_POOL_STATS_INTERVAL", 15*time.Second),
+		EmptyAcquireWarnThreshold: getEnvInt("DB_POOL_EMPTY_ACQUIRE_WARN_THRESHOLD", 100),
+		EmptyAcquireWindow:        getEnvDuration("DB_POOL_EMPTY_ACQUIRE_WINDOW", time.Minute),
+	}
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// poolEnv adalah semua env yang dibaca LoadPoolConfig, dihapus di setiap
+// test agar env mesin tidak ikut terbaca.
+var poolEnv = []string{
+	"DB_MAX_CONNS",
+	"DB_MIN_CONNS",
+	"DB_MAX_CONN_LIFETIME",
+	"DB_MAX_CONN_IDLE_TIME",
+	"DB_HEALTH_CHECK_PERIOD",
+	"DB_CONNECT_TIMEOUT",
+	"DB_QUERY_EXEC_MODE",
+	"DB_STATEMENT_CACHE_CAPACITY",
+	"DB_DESCRIPTION_CACHE_CAPACITY",
+}
+
+func setPoolEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+	for _, key := range poolEnv {
+		// t.Setenv mengembalikan nilai asli setelah test selesai
+		t.Setenv(key, env[key])
+		if env[key] == "" {
+			os.Unsetenv(key)
+		}
+	}
+}
+
+func TestLoadPoolConfigDefaults(t *testing.T) {
+	setPoolEnv(t, nil)
+	poolConfig, err := LoadPoolConfig()
+	if err != nil {
+		t.Fatalf("LoadPoolConfig error = %v", err)
+	}
+	want := PoolConfig{
+		MaxConns:                 int32(max(4, runtime.NumCPU())),
+		MaxConnLifetime:          time.Hour,
+		MaxConnIdleTime:          30 * time.Minute,
+		HealthCheckPeriod:        time.Minute,
+		ConnectTimeout:           5 * time.Second,
+		QueryExecMode:            QueryExecModeCacheStatement,
+		StatementCacheCapacity:   512,
+		DescriptionCacheCapacity: 512,
+	}
+	if *poolConfig != want {
+		t.Fatalf("LoadPoolConfig = %+v, want %+v", *poolConfig, want)
+	}
+}
+
+func TestLoadPoolConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		// wantErrs kosong berarti konfigurasi valid
+		wantErrs []string
+		check    func(t *testing.T, poolConfig *PoolConfig)
+	}{
+		{
+			name: "all settings",
+			env: map[string]string{
+				"DB_MAX_CONNS":           "20",
+				"DB_MIN_CONNS":           "5",
+				"DB_MAX_CONN_LIFETIME":   "2h",
+				"DB_MAX_CONN_IDLE_TIME":  "10m",
+				"DB_HEALTH_CHECK_PERIOD": "30s",
+				"DB_CONNECT_TIMEOUT":     "3s",
+			},
+			check: func(t *testing.T, poolConfig *PoolConfig) {
+				if poolConfig.MaxConns != 20 || poolConfig.MinConns != 5 {
+					t.Errorf("conns = %d/%d, want 20/5", poolConfig.MaxConns, poolConfig.MinConns)
+				}
+				if poolConfig.MaxConnLifetime != 2*time.Hour || poolConfig.MaxConnIdleTime != 10*time.Minute {
+					t.Errorf("lifetime = %s, idle = %s", poolConfig.MaxConnLifetime, poolConfig.MaxConnIdleTime)
+				}
+				if poolConfig.HealthCheckPeriod != 30*time.Second || poolConfig.ConnectTimeout != 3*time.Second {
+					t.Errorf("health check = %s, connect timeout = %s", poolConfig.HealthCheckPeriod, poolConfig.ConnectTimeout)
+				}
+			},
+		},
+		{
+			name: "min equals max",
+			env:  map[string]string{"DB_MAX_CONNS": "4", "DB_MIN_CONNS": "4"},
+		},
+		{
+			name:     "min greater than max",
+			env:      map[string]string{"DB_MAX_CONNS": "4", "DB_MIN_CONNS": "5"},
+			wantErrs: []string{"DB_MIN_CONNS (5) must not be greater than DB_MAX_CONNS (4)"},
+		},
+		{
+			name:     "zero max conns",
+			env:      map[string]string{"DB_MAX_CONNS": "0"},
+			wantErrs: []string{"DB_MAX_CONNS must be at least 1"},
+		},
+		{
+			name:     "negative min conns",
+			env:      map[string]string{"DB_MIN_CONNS": "-1"},
+			wantErrs: []string{"DB_MIN_CONNS must not be negative"},
+		},
+		{
+			name:     "not an integer",
+			env:      map[string]string{"DB_MAX_CONNS": "ten"},
+			wantErrs: []string{`DB_MAX_CONNS must be an integer, got "ten"`},
+		},
+		{
+			name:     "not a duration",
+			env:      map[string]string{"DB_MAX_CONN_LIFETIME": "1 hour"},
+			wantErrs: []string{`DB_MAX_CONN_LIFETIME must be a duration`},
+		},
+		{
+			name:     "zero duration",
+			env:      map[string]string{"DB_CONNECT_TIMEOUT": "0s"},
+			wantErrs: []string{"DB_CONNECT_TIMEOUT must be positive"},
+		},
+		{
+			name:     "negative duration",
+			env:      map[string]string{"DB_HEALTH_CHECK_PERIOD": "-1m"},
+			wantErrs: []string{"DB_HEALTH_CHECK_PERIOD must be positive"},
+		},
+		{
+			// Semua kesalahan dilaporkan sekaligus, bukan hanya yang pertama
+			name: "several bad values",
+			env: map[string]string{
+				"DB_MAX_CONNS":          "2",
+				"DB_MIN_CONNS":          "3",
+				"DB_MAX_CONN_IDLE_TIME": "soon",
+			},
+			wantErrs: []string{"DB_MIN_CONNS (3)", "DB_MAX_CONN_IDLE_TIME must be a duration"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setPoolEnv(t, tt.env)
+			poolConfig, err := LoadPoolConfig()
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Fatalf("LoadPoolConfig error = %v", err)
+				}
+				if tt.check != nil {
+					tt.check(t, poolConfig)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("LoadPoolConfig = %+v, want an error", poolConfig)
+			}
+			for _, want := range tt.wantErrs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error = %q, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package config
+
+import "time"
+
+type PurgeConfig struct {
+	// Interval adalah jeda antar purge terjadwal. 0 mematikan purge
+	// terjadwal; purge manual lewat route admin tetap bisa dipakai.
+	Interval time.Duration
+	// Retention adalah lama baris soft-delete disimpan sebelum dihapus
+	// permanen.
+	Retention time.Duration
+	// BatchSize dan BatchPause membatasi lama lock: setiap DELETE paling
+	// banyak BatchSize baris, dengan jeda BatchPause antar batch.
+	BatchSize  int
+	BatchPause time.Duration
+}
+
+func LoadPurgeConfig() *PurgeConfig {
+	return &PurgeConfig{
+		Interval:   getEnvDuration("PURGE_INTERVAL", time.Hour),
+		Retention:  getEnvDuration("PURGE_RETENTION", 30*24*time.Hour),
+		BatchSize:  getEnvInt("PURGE_BATCH_SIZE", 500),
+		BatchPause: getEnvDuration("PURGE_BATCH_PAUSE", 100*time.Millisecond),
+	}
+}
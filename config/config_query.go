@@ -0,0 +1,26 @@
+package config
+
+import "time"
+
+// QueryTimeoutConfig adalah batas waktu per jenis operasi repository.
+// Context timeout selalu diturunkan dari context request, sehingga client
+// yang membatalkan request tetap menghentikan query lebih awal.
+type QueryTimeoutConfig struct {
+	// Lookup untuk query titik seperti pengecekan kepemilikan department.
+	Lookup time.Duration
+	// Write untuk insert/update/delete satu baris.
+	Write time.Duration
+	// List untuk query daftar dengan filter dan batch insert.
+	List time.Duration
+	// Export untuk streaming seluruh employee, lihat GET /v1/employee/export.
+	Export time.Duration
+}
+
+func LoadQueryTimeoutConfig() *QueryTimeoutConfig {
+	return &QueryTimeoutConfig{
+		Lookup: getEnvDuration("QUERY_TIMEOUT_LOOKUP", 2*time.Second),
+		Write:  getEnvDuration("QUERY_TIMEOUT_WRITE", 5*time.Second),
+		List:   getEnvDuration("QUERY_TIMEOUT_LIST", 10*time.Second),
+		Export: getEnvDuration("QUERY_TIMEOUT_EXPORT", 5*time.Minute),
+	}
+}
@@ -0,0 +1,31 @@
+package config
+
+type QueryTraceConfig struct {
+	// Enabled memasang tracer log dan metric per query.
+	Enabled bool
+	// LogSampleRate adalah porsi query sukses yang di-log (0..1). Query
+	// yang error selalu di-log.
+	LogSampleRate float64
+
+	// ExplainSlowQueries me-log plan EXPLAIN setiap query lambat di level
+	// Debug. Jika mati, hanya request admin dengan header X-Debug-Explain.
+	ExplainSlowQueries bool
+	// ExplainMaxBytes memotong plan yang di-log (0 = tanpa batas).
+	ExplainMaxBytes int
+
+	// LimitPerRequest adalah batas query per request HTTP (0 = mati).
+	// Request yang melewatinya tetap dilayani, tetapi di-log di level Warn.
+	LimitPerRequest int
+}
+
+func LoadQueryTraceConfig() *QueryTraceConfig {
+	return &QueryTraceConfig{
+		Enabled:       getEnvBool("QUERY_TRACE_ENABLED", true),
+		LogSampleRate: getEnvFloat("QUERY_LOG_SAMPLE_RATE", 0.01),
+
+		ExplainSlowQueries: getEnvBool("QUERY_EXPLAIN_SLOW", false),
+		ExplainMaxBytes:    getEnvInt("QUERY_EXPLAIN_MAX_BYTES", 16384),
+
+		LimitPerRequest: getEnvInt("QUERY_LIMIT_PER_REQUEST", 0),
+	}
+}
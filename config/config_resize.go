@@ -0,0 +1,29 @@
+package config
+
+import "time"
+
+// ResizeConfig mengatur GET /files/:id/resize.
+type ResizeConfig struct {
+	// Sizes adalah ukuran WxH yang boleh diminta. Ukuran lain ditolak agar
+	// client tidak bisa membuat varian tanpa batas di storage.
+	Sizes []string
+	// InlineMaxBytes adalah ukuran file asli terbesar yang di-resize
+	// langsung di goroutine request; file yang lebih besar di-resize di
+	// worker.Pool agar jumlah decode bersamaan terbatas.
+	InlineMaxBytes int64
+	// Timeout membatasi satu resize, termasuk waktu menunggu di antrean
+	// worker.Pool.
+	Timeout time.Duration
+}
+
+func LoadResizeConfig() *ResizeConfig {
+	sizes := getEnvList("IMAGE_RESIZE_SIZES")
+	if len(sizes) == 0 {
+		sizes = []string{"64x64", "128x128", "256x256", "512x512"}
+	}
+	return &ResizeConfig{
+		Sizes:          sizes,
+		InlineMaxBytes: int64(getEnvInt("IMAGE_RESIZE_INLINE_MAX_BYTES", 256<<10)),
+		Timeout:        getEnvDuration("IMAGE_RESIZE_TIMEOUT", 10*time.Second),
+	}
+}
@@ -0,0 +1,27 @@
+package config
+
+import "log"
+
+// Bentuk response daftar, lihat ResponseConfig.
+const (
+	ListResponseEnvelope = "envelope"
+	ListResponseArray    = "array"
+)
+
+type ResponseConfig struct {
+	// EmployeeList adalah bentuk response GET /v1/employee.
+	// ListResponseEnvelope membungkus daftar dengan helper.Response,
+	// ListResponseArray mengirim array JSON di top level sesuai kontrak
+	// project-sprint, dengan total di header X-Total-Count. Keduanya
+	// mengirim header Link.
+	EmployeeList string
+}
+
+func LoadResponseConfig() *ResponseConfig {
+	employeeList := getEnv("EMPLOYEE_LIST_RESPONSE", ListResponseEnvelope)
+	if employeeList != ListResponseEnvelope && employeeList != ListResponseArray {
+		log.Printf("Invalid value for EMPLOYEE_LIST_RESPONSE: %q, using default %s", employeeList, ListResponseEnvelope)
+		employeeList = ListResponseEnvelope
+	}
+	return &ResponseConfig{EmployeeList: employeeList}
+}
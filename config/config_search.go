@@ -0,0 +1,18 @@
+package config
+
+type SearchConfig struct {
+	// FuzzyThreshold adalah similarity minimum (0..1) untuk pencarian nama
+	// dengan fuzzy=true. Nilai di bawah pg_trgm.similarity_threshold
+	// (default 0.3) tidak berpengaruh karena operator % sudah menyaring lebih
+	// dulu.
+	FuzzyThreshold float64
+	// TrigramAvailable diisi saat startup oleh database.NewSearchConfigInject
+	// berdasarkan ada tidaknya extension pg_trgm.
+	TrigramAvailable bool
+}
+
+func LoadSearchConfig() *SearchConfig {
+	return &SearchConfig{
+		FuzzyThreshold: getEnvFloat("SEARCH_FUZZY_THRESHOLD", 0.3),
+	}
+}
@@ -0,0 +1,66 @@
+package config
+
+import "time"
+
+type ServerConfig struct {
+	Host string
+	Port string
+	Mode string
+
+	SSLCertPath string
+	SSLKeyPath  string
+	// AutocertDomains mengaktifkan sertifikat Let's Encrypt otomatis untuk
+	// domain-domain ini, sebagai pengganti SSLCertPath/SSLKeyPath.
+	AutocertDomains []string
+	// AutocertCacheDir menyimpan sertifikat hasil autocert agar tidak
+	// diminta ulang setiap restart.
+	AutocertCacheDir string
+
+	// ReadHeaderTimeout dan IdleTimeout mencegah koneksi lambat/menganggur
+	// (slowloris) menahan goroutine server selamanya.
+	ReadHeaderTimeout time.Duration
+	IdleTimeout       time.Duration
+
+	// ShutdownGracePeriod adalah batas waktu menunggu request yang sedang
+	// berjalan selesai ketika menerima SIGTERM/SIGINT.
+	ShutdownGracePeriod time.Duration
+	// ReadinessDrainDelay adalah jeda antara /readyz berubah 503 dan server
+	// berhenti menerima koneksi, memberi waktu load balancer mengeluarkan
+	// instance ini dari rotasi.
+	ReadinessDrainDelay time.Duration
+
+	// RetryAfter dikirim sebagai header Retry-After pada response 503, mis.
+	// saat pool database kehabisan koneksi.
+	RetryAfter time.Duration
+}
+
+const ModeProduction = "PRODUCTION"
+
+// TLSEnabled bernilai true jika sertifikat file atau autocert dikonfigurasi.
+func (c *ServerConfig) TLSEnabled() bool {
+	return c.SSLCertPath != "" || c.SSLKeyPath != "" || len(c.AutocertDomains) > 0
+}
+
+func LoadServerConfig() *ServerConfig {
+	mode := getEnv("MODE", "")
+
+	host := getEnv("DEBUG_HOST", "")
+	if mode == ModeProduction {
+		host = getEnv("PROD_HOST", "")
+	}
+
+	return &ServerConfig{
+		Host:                host,
+		Port:                getEnv("PORT", "8080"),
+		Mode:                mode,
+		SSLCertPath:         getEnv("SSL_CERT_PATH", ""),
+		SSLKeyPath:          getEnv("SSL_KEY_PATH", ""),
+		AutocertDomains:     getEnvList("AUTOCERT_DOMAINS"),
+		AutocertCacheDir:    getEnv("AUTOCERT_CACHE_DIR", "./.autocert"),
+		ReadHeaderTimeout:   getEnvDuration("READ_HEADER_TIMEOUT", 5*time.Second),
+		IdleTimeout:         getEnvDuration("IDLE_TIMEOUT", 120*time.Second),
+		ShutdownGracePeriod: getEnvDuration("SHUTDOWN_GRACE_PERIOD", 15*time.Second),
+		ReadinessDrainDelay: getEnvDuration("READINESS_DRAIN_DELAY", 5*time.Second),
+		RetryAfter:          getEnvDuration("RETRY_AFTER", 2*time.Second),
+	}
+}
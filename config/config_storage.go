@@ -0,0 +1,67 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// Nilai StorageConfig.Driver
+const (
+	StorageDriverS3     = "s3"
+	StorageDriverMemory = "memory"
+	StorageDriverLocal  = "local"
+)
+
+type StorageConfig struct {
+	// Driver adalah s3 (AWS S3 atau storage S3-compatible seperti MinIO),
+	// local (file di LocalDir), atau memory (hilang saat restart, hanya untuk
+	// development dan test). Default memory jika MODE=DEBUG.
+	Driver string
+
+	// LocalDir adalah direktori file untuk driver local.
+	LocalDir string
+
+	// Endpoint mengganti endpoint AWS S3, mis. http://localhost:9000 untuk
+	// MinIO. Kosong berarti AWS S3 di Region.
+	Endpoint string
+	Region   string
+	Bucket   string
+	// Prefix ditambahkan di depan setiap key, sehingga beberapa environment
+	// bisa memakai bucket yang sama.
+	Prefix string
+	// AccessKeyID dan SecretAccessKey kosong berarti credential diambil dari
+	// default chain AWS (env, shared config, atau IAM role).
+	AccessKeyID     string
+	SecretAccessKey string
+	// UsePathStyle membuat URL berbentuk endpoint/bucket/key, dibutuhkan
+	// MinIO.
+	UsePathStyle bool
+	// PublicBaseURL mengganti awal URL object public, mis. domain CDN.
+	// Kosong berarti URL endpoint bucket.
+	PublicBaseURL string
+	// SignedURLExpiry adalah masa berlaku default URL yang ditandatangani.
+	SignedURLExpiry time.Duration
+}
+
+func LoadStorageConfig() *StorageConfig {
+	driver := getEnv("STORAGE_DRIVER", "")
+	if driver == "" {
+		driver = StorageDriverS3
+		if os.Getenv("MODE") == "DEBUG" {
+			driver = StorageDriverMemory
+		}
+	}
+	return &StorageConfig{
+		Driver:          driver,
+		LocalDir:        getEnv("STORAGE_LOCAL_DIR", "./.uploads"),
+		Endpoint:        getEnv("STORAGE_ENDPOINT", ""),
+		Region:          getEnv("AWS_REGION", "us-east-1"),
+		Bucket:          getEnv("AWS_BUCKET", ""),
+		Prefix:          getEnv("STORAGE_PREFIX", ""),
+		AccessKeyID:     getEnv("AWS_ACCESS_KEY_ID", ""),
+		SecretAccessKey: getEnv("AWS_SECRET_ACCESS_KEY", ""),
+		UsePathStyle:    getEnvBool("STORAGE_USE_PATH_STYLE", false),
+		PublicBaseURL:   getEnv("STORAGE_PUBLIC_BASE_URL", ""),
+		SignedURLExpiry: getEnvDuration("STORAGE_SIGNED_URL_EXPIRY", 15*time.Minute),
+	}
+}
@@ -0,0 +1,23 @@
+package config
+
+import "time"
+
+type StreamConfig struct {
+	// HeartbeatInterval adalah jeda komentar heartbeat di stream SSE, agar
+	// proxy tidak menutup koneksi yang sedang sepi.
+	HeartbeatInterval time.Duration
+	// BufferSize adalah jumlah event terakhir yang disimpan di memori untuk
+	// resume dengan Last-Event-ID.
+	BufferSize int
+	// SubscriberBuffer adalah antrian event per client. Client yang
+	// tertinggal lebih jauh diputus dan harus resume dengan Last-Event-ID.
+	SubscriberBuffer int
+}
+
+func LoadStreamConfig() *StreamConfig {
+	return &StreamConfig{
+		HeartbeatInterval: getEnvDuration("STREAM_HEARTBEAT_INTERVAL", 15*time.Second),
+		BufferSize:        getEnvInt("STREAM_BUFFER_SIZE", 1000),
+		SubscriberBuffer:  getEnvInt("STREAM_SUBSCRIBER_BUFFER", 64),
+	}
+}
@@ -0,0 +1,42 @@
+package config
+
+import "time"
+
+type ThumbnailConfig struct {
+	// Size adalah sisi terpanjang thumbnail dalam pixel. 0 mematikan
+	// pembuatan thumbnail untuk upload baru.
+	Size int
+	// MaxPixels membatasi ukuran gambar asli (lebar x tinggi) yang di-decode,
+	// agar gambar kecil yang sangat terkompresi tidak menghabiskan memori.
+	MaxPixels int
+	// Timeout membatasi satu pembuatan thumbnail.
+	Timeout time.Duration
+	// MaxAttempts adalah jumlah percobaan sebelum status thumbnail menjadi
+	// failed. Jeda antar percobaan RetryBackoff * 2^attempts, paling lama
+	// MaxRetryBackoff.
+	MaxAttempts     int
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+	// Lease adalah waktu sebelum thumbnail yang sedang diproses dianggap
+	// hilang (mis. instance mati atau antrean penuh) dan dicoba lagi. Harus
+	// lebih lama dari Timeout.
+	Lease time.Duration
+	// PollInterval adalah jeda antar pengecekan thumbnail yang perlu dicoba
+	// lagi.
+	PollInterval time.Duration
+	BatchSize    int
+}
+
+func LoadThumbnailConfig() *ThumbnailConfig {
+	return &ThumbnailConfig{
+		Size:            getEnvInt("THUMBNAIL_SIZE", 128),
+		MaxPixels:       getEnvInt("THUMBNAIL_MAX_PIXELS", 40_000_000),
+		Timeout:         getEnvDuration("THUMBNAIL_TIMEOUT", 30*time.Second),
+		MaxAttempts:     getEnvInt("THUMBNAIL_MAX_ATTEMPTS", 5),
+		RetryBackoff:    getEnvDuration("THUMBNAIL_RETRY_BACKOFF", 30*time.Second),
+		MaxRetryBackoff: getEnvDuration("THUMBNAIL_MAX_RETRY_BACKOFF", 30*time.Minute),
+		Lease:           getEnvDuration("THUMBNAIL_LEASE", 5*time.Minute),
+		PollInterval:    getEnvDuration("THUMBNAIL_POLL_INTERVAL", time.Minute),
+		BatchSize:       getEnvInt("THUMBNAIL_BATCH_SIZE", 20),
+	}
+}
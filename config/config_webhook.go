@@ -0,0 +1,33 @@
+package config
+
+import "time"
+
+type WebhookConfig struct {
+	// Timeout membatasi satu request POST ke URL webhook.
+	Timeout time.Duration
+	// MaxAttempts adalah jumlah percobaan sebelum delivery ditandai dead.
+	MaxAttempts int
+	// RetryBackoff dan MaxRetryBackoff mengatur jeda pengiriman ulang:
+	// RetryBackoff * 2^attempts, paling lama MaxRetryBackoff.
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+	// PollInterval adalah jeda antar pengecekan delivery yang belum terkirim
+	// ketika batch terakhir tidak penuh.
+	PollInterval time.Duration
+	BatchSize    int
+	// AllowPrivateURLs mengizinkan URL ke alamat private/loopback. Hanya
+	// untuk development; di production membuka celah SSRF.
+	AllowPrivateURLs bool
+}
+
+func LoadWebhookConfig() *WebhookConfig {
+	return &WebhookConfig{
+		Timeout:          getEnvDuration("WEBHOOK_TIMEOUT", 5*time.Second),
+		MaxAttempts:      getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		RetryBackoff:     getEnvDuration("WEBHOOK_RETRY_BACKOFF", 30*time.Second),
+		MaxRetryBackoff:  getEnvDuration("WEBHOOK_MAX_RETRY_BACKOFF", time.Hour),
+		PollInterval:     getEnvDuration("WEBHOOK_POLL_INTERVAL", time.Second),
+		BatchSize:        getEnvInt("WEBHOOK_BATCH_SIZE", 20),
+		AllowPrivateURLs: getEnvBool("WEBHOOK_ALLOW_PRIVATE_URLS", false),
+	}
+}
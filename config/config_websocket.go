@@ -0,0 +1,25 @@
+package config
+
+import "time"
+
+type WebSocketConfig struct {
+	// PingInterval adalah jeda ping ke client. Koneksi yang tidak membalas
+	// pong dalam dua kali PingInterval ditutup.
+	PingInterval time.Duration
+	// WriteTimeout membatasi satu penulisan pesan ke client.
+	WriteTimeout time.Duration
+	// QueueSize adalah antrian pesan per koneksi. Event yang tidak muat
+	// dibuang dan client diberi tahu lewat pesan dropped.
+	QueueSize int
+	// MaxMessageBytes membatasi ukuran pesan dari client.
+	MaxMessageBytes int64
+}
+
+func LoadWebSocketConfig() *WebSocketConfig {
+	return &WebSocketConfig{
+		PingInterval:    getEnvDuration("WS_PING_INTERVAL", 30*time.Second),
+		WriteTimeout:    getEnvDuration("WS_WRITE_TIMEOUT", 10*time.Second),
+		QueueSize:       getEnvInt("WS_QUEUE_SIZE", 64),
+		MaxMessageBytes: int64(getEnvInt("WS_MAX_MESSAGE_BYTES", 4096)),
+	}
+}
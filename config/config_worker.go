@@ -0,0 +1,21 @@
+package config
+
+import "time"
+
+type WorkerConfig struct {
+	// Size adalah jumlah task yang berjalan bersamaan.
+	Size int
+	// QueueDepth adalah jumlah task yang boleh menunggu. Submit ke antrean
+	// penuh langsung gagal.
+	QueueDepth int
+	// TaskTimeout membatasi satu task jika Task.Timeout tidak diisi.
+	TaskTimeout time.Duration
+}
+
+func LoadWorkerConfig() *WorkerConfig {
+	return &WorkerConfig{
+		Size:        getEnvInt("WORKER_POOL_SIZE", 4),
+		QueueDepth:  getEnvInt("WORKER_QUEUE_DEPTH", 100),
+		TaskTimeout: getEnvDuration("WORKER_TASK_TIMEOUT", 30*time.Second),
+	}
+}
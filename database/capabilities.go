@@ -0,0 +1,39 @@
+package database
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/samber/do/v2"
+)
+
+// NewSearchConfigInject memuat SearchConfig dan mengecek extension pg_trgm
+// sekali saat startup. Tanpa pg_trgm, pencarian fuzzy kembali ke pencarian
+// contains biasa.
+func NewSearchConfigInject(i do.Injector) (*config.SearchConfig, error) {
+	db := do.MustInvoke[*Cluster](i).Writer()
+	searchConfig := config.LoadSearchConfig()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	available, err := HasExtension(ctx, db, "pg_trgm")
+	if err != nil {
+		log.Printf("Failed to check pg_trgm extension, fuzzy search disabled: %v", err)
+	} else if !available {
+		log.Println("pg_trgm extension is not installed, fuzzy search falls back to contains search")
+	}
+	searchConfig.TrigramAvailable = available
+	return searchConfig, nil
+}
+
+func HasExtension(ctx context.Context, db DB, name string) (bool, error) {
+	var exists bool
+	err := db.QueryRow(
+		WithQueryName(ctx, "database.has_extension"),
+		"SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = $1);",
+		name,
+	).Scan(&exists)
+	return exists, err
+}
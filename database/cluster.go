@@ -0,0 +1,184 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/breaker"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/samber/do/v2"
+)
+
+// Cluster menyimpan pool primary dan read replica (opsional). Repository
+// memakai Writer untuk write dan read-after-write, Reader untuk query baca
+// yang boleh sedikit tertinggal dari primary.
+type Cluster struct {
+	writer DB
+	reader DB
+
+	replicaPool *pgxpool.Pool
+}
+
+// WriterOnly membuat Cluster tanpa replica, Reader mengarah ke primary.
+func WriterOnly(writer DB) *Cluster {
+	return &Cluster{writer: writer, reader: writer}
+}
+
+// WithReplica membuat Cluster dengan replica. Query di replica yang gagal
+// karena replica tidak bisa dijangkau diulang di primary.
+func WithReplica(writer DB, replica DB, logger logger.Logger) *Cluster {
+	return &Cluster{
+		writer: writer,
+		reader: &fallbackDB{replica: replica, primary: writer, logger: logger},
+	}
+}
+
+func NewClusterInject(i do.Injector) (*Cluster, error) {
+	writer := do.MustInvoke[DB](i)
+
+	replicaURL := config.LoadConfig().ReplicaDatabaseURL
+	if replicaURL == "" {
+		return WriterOnly(writer), nil
+	}
+
+	appLogger := do.MustInvoke[logger.LogHandler](i)
+	appMetrics := do.MustInvoke[*metrics.Metrics](i)
+
+	poolConfig, err := config.LoadPoolConfig()
+	if err != nil {
+		return nil, err
+	}
+	explain := newExplainTracer(&appLogger)
+	replicaPool, err := NewPool(context.Background(), replicaURL, poolConfig, newQueryTracer(&appLogger, appMetrics, explain))
+	if err != nil {
+		return nil, fmt.Errorf("replica: %w", err)
+	}
+	explain.SetPool(replicaPool)
+
+	// Breaker terpisah, agar replica yang mati langsung dialihkan ke primary
+	// tanpa menunggu connect timeout di setiap request.
+	breakerConfig := config.LoadBreakerConfig()
+	replicaBreaker := breaker.New(breaker.Settings{
+		Name:             "postgres_replica",
+		FailureThreshold: breakerConfig.FailureThreshold,
+		Cooldown:         breakerConfig.Cooldown,
+		IsSuccessful:     isDatabaseAvailable,
+		OnStateChange: func(name string, _, to breaker.State) {
+			appMetrics.SetBreakerState(name, int(to))
+		},
+	})
+	appMetrics.SetBreakerState(replicaBreaker.Name(), int(breaker.StateClosed))
+
+	cluster := WithReplica(writer, NewGuardedPool(replicaPool, replicaBreaker), &appLogger)
+	cluster.replicaPool = replicaPool
+	log.Println("Read replica configured")
+	return cluster, nil
+}
+
+func (c *Cluster) Writer() DB {
+	return c.writer
+}
+
+func (c *Cluster) Reader() DB {
+	return c.reader
+}
+
+// Shutdown menutup pool replica. Pool primary ditutup lewat database.Pool.
+func (c *Cluster) Shutdown(context.Context) error {
+	if c.replicaPool != nil {
+		c.replicaPool.Close()
+	}
+	return nil
+}
+
+// fallbackDB menjalankan query di replica dan mengulanginya di primary jika
+// replica tidak bisa dijangkau. Error dari query itu sendiri (constraint,
+// no rows, timeout request) dikembalikan apa adanya.
+type fallbackDB struct {
+	replica DB
+	primary DB
+	logger  logger.Logger
+}
+
+func (f *fallbackDB) shouldFallback(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	if errors.Is(err, helper.ErrServiceUnavailable) || !isDatabaseAvailable(err) {
+		f.logger.Warn(fmt.Sprintf("Read replica unavailable, falling back to primary: %v", err), helper.DatabaseReplica)
+		return true
+	}
+	return false
+}
+
+func (f *fallbackDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	tag, err := f.replica.Exec(ctx, sql, args...)
+	if f.shouldFallback(ctx, err) {
+		return f.primary.Exec(ctx, sql, args...)
+	}
+	return tag, err
+}
+
+func (f *fallbackDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	rows, err := f.replica.Query(ctx, sql, args...)
+	if f.shouldFallback(ctx, err) {
+		return f.primary.Query(ctx, sql, args...)
+	}
+	return rows, err
+}
+
+func (f *fallbackDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return fallbackRow{db: f, ctx: ctx, sql: sql, args: args}
+}
+
+func (f *fallbackDB) Begin(ctx context.Context) (pgx.Tx, error) {
+	tx, err := f.replica.Begin(ctx)
+	if f.shouldFallback(ctx, err) {
+		return f.primary.Begin(ctx)
+	}
+	return tx, err
+}
+
+func (f *fallbackDB) BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
+	tx, err := f.replica.BeginTx(ctx, txOptions)
+	if f.shouldFallback(ctx, err) {
+		return f.primary.BeginTx(ctx, txOptions)
+	}
+	return tx, err
+}
+
+// SendBatch tidak bisa diulang setelah dikirim, sehingga batch selalu
+// dijalankan di primary.
+func (f *fallbackDB) SendBatch(ctx context.Context, batch *pgx.Batch) pgx.BatchResults {
+	return f.primary.SendBatch(ctx, batch)
+}
+
+// CopyFrom menulis data, sehingga selalu dijalankan di primary.
+func (f *fallbackDB) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	return f.primary.CopyFrom(ctx, tableName, columnNames, rowSrc)
+}
+
+// fallbackRow menunda eksekusi sampai Scan, karena error QueryRow baru
+// diketahui saat Scan.
+type fallbackRow struct {
+	db   *fallbackDB
+	ctx  context.Context
+	sql  string
+	args []any
+}
+
+func (r fallbackRow) Scan(dest ...any) error {
+	err := r.db.replica.QueryRow(r.ctx, r.sql, r.args...).Scan(dest...)
+	if r.db.shouldFallback(r.ctx, err) {
+		return r.db.primary.QueryRow(r.ctx, r.sql, r.args...).Scan(dest...)
+	}
+	return err
+}
@@ -0,0 +1,156 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+)
+
+// replicaLogger menghitung warning fallback ke primary.
+type replicaLogger struct {
+	warnings int
+}
+
+var _ logger.Logger = (*replicaLogger)(nil)
+
+func (l *replicaLogger) Info(msg string, function helper.FunctionCaller, data ...interface{})  {}
+func (l *replicaLogger) Error(msg string, function helper.FunctionCaller, data ...interface{}) {}
+func (l *replicaLogger) Debug(msg string, function helper.FunctionCaller, data ...interface{}) {}
+
+func (l *replicaLogger) Warn(msg string, function helper.FunctionCaller, data ...interface{}) {
+	if function == helper.DatabaseReplica {
+		l.warnings++
+	}
+}
+
+func (l *replicaLogger) With(fields map[string]any) logger.Logger {
+	return l
+}
+
+func (l *replicaLogger) WithContext(ctx context.Context) logger.Logger {
+	return l
+}
+
+func TestWriterOnlyReadsFromPrimary(t *testing.T) {
+	primary := &stubDB{}
+	cluster := WriterOnly(primary)
+	if cluster.Reader() != DB(primary) || cluster.Writer() != DB(primary) {
+		t.Fatal("WriterOnly cluster does not route reads and writes to the primary")
+	}
+	// Tanpa replica tidak ada pool tambahan yang perlu ditutup
+	if err := cluster.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown error = %v", err)
+	}
+}
+
+func TestReplicaRouting(t *testing.T) {
+	ctx := context.Background()
+	operations := []struct {
+		name string
+		run  func(db DB) error
+	}{
+		{name: "Exec", run: func(db DB) error {
+			_, err := db.Exec(ctx, "SELECT 1;")
+			return err
+		}},
+		{name: "Query", run: func(db DB) error {
+			_, err := db.Query(ctx, "SELECT 1;")
+			return err
+		}},
+		{name: "QueryRow", run: func(db DB) error {
+			return db.QueryRow(ctx, "SELECT 1;").Scan()
+		}},
+		{name: "Begin", run: func(db DB) error {
+			_, err := db.Begin(ctx)
+			return err
+		}},
+		{name: "BeginTx", run: func(db DB) error {
+			_, err := db.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+			return err
+		}},
+	}
+	tests := []struct {
+		name        string
+		replicaErr  error
+		wantPrimary bool
+	}{
+		{name: "replica healthy", wantPrimary: false},
+		{name: "replica refused", replicaErr: errRefused, wantPrimary: true},
+		{name: "replica breaker open", replicaErr: helper.ErrServiceUnavailable, wantPrimary: true},
+		{name: "replica shutting down", replicaErr: &pgconn.PgError{Code: "57P01"}, wantPrimary: true},
+		// Error dari query itu sendiri akan sama di primary
+		{name: "no rows", replicaErr: pgx.ErrNoRows, wantPrimary: false},
+		{name: "unique violation", replicaErr: &pgconn.PgError{Code: "23505"}, wantPrimary: false},
+		{name: "statement timeout", replicaErr: &pgconn.PgError{Code: "57014"}, wantPrimary: false},
+	}
+	for _, op := range operations {
+		for _, tt := range tests {
+			t.Run(op.name+"/"+tt.name, func(t *testing.T) {
+				primary := &stubDB{}
+				replica := &stubDB{err: tt.replicaErr}
+				log := &replicaLogger{}
+				cluster := WithReplica(primary, replica, log)
+				if cluster.Writer() != DB(primary) {
+					t.Fatal("Writer is not the primary")
+				}
+
+				err := op.run(cluster.Reader())
+				if replica.calls != 1 {
+					t.Fatalf("replica calls = %d, want 1", replica.calls)
+				}
+				if tt.wantPrimary {
+					if primary.calls != 1 || err != nil {
+						t.Fatalf("primary calls = %d with error %v, want one successful fallback", primary.calls, err)
+					}
+					if log.warnings != 1 {
+						t.Fatalf("warnings = %d, want 1", log.warnings)
+					}
+					return
+				}
+				if primary.calls != 0 || log.warnings != 0 {
+					t.Fatalf("primary calls = %d, warnings = %d, want the replica result only", primary.calls, log.warnings)
+				}
+				if err != tt.replicaErr {
+					t.Fatalf("error = %v, want %v", err, tt.replicaErr)
+				}
+			})
+		}
+	}
+}
+
+// TestReplicaFallbackSkipsCancelledRequest memastikan request yang sudah
+// dibatalkan tidak diulang di primary.
+func TestReplicaFallbackSkipsCancelledRequest(t *testing.T) {
+	primary := &stubDB{}
+	replica := &stubDB{err: errRefused}
+	cluster := WithReplica(primary, replica, &replicaLogger{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := cluster.Reader().Query(ctx, "SELECT 1;"); err != errRefused {
+		t.Fatalf("Query error = %v, want %v", err, errRefused)
+	}
+	if primary.calls != 0 {
+		t.Fatalf("primary calls = %d, want 0", primary.calls)
+	}
+}
+
+// TestReplicaWritesGoToPrimary memastikan batch dan COPY lewat Reader tetap
+// dijalankan di primary tanpa menyentuh replica.
+func TestReplicaWritesGoToPrimary(t *testing.T) {
+	primary := &stubDB{}
+	replica := &stubDB{}
+	reader := WithReplica(primary, replica, &replicaLogger{}).Reader()
+
+	reader.SendBatch(context.Background(), &pgx.Batch{})
+	if _, err := reader.CopyFrom(context.Background(), pgx.Identifier{"employees"}, nil, pgx.CopyFromRows(nil)); err != nil {
+		t.Fatalf("CopyFrom error = %v", err)
+	}
+	if replica.calls != 0 || primary.calls != 2 {
+		t.Fatalf("replica calls = %d, primary calls = %d, want 0 and 2", replica.calls, primary.calls)
+	}
+}
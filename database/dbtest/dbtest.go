@@ -0,0 +1,275 @@
+//go:build integration
+
+// Package dbtest menyiapkan database Postgres sekali pakai untuk integration
+// test repository. Hanya ikut di-build dengan tag integration:
+//
+//	go test -tags integration ./...
+//
+// Secara default satu container Postgres dijalankan lewat testcontainers-go
+// per test binary, dengan image yang sama dengan docker-compose. Untuk
+// memakai Postgres yang sudah jalan, isi TEST_DATABASE_URL dengan URL yang
+// boleh dipakai untuk CREATE DATABASE, mis. service postgres dari
+// docker-compose:
+//
+//	TEST_DATABASE_URL=postgres://user:pw@localhost:5432/postgres go test -tags integration ./...
+package dbtest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/cache"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+)
+
+// postgresImage sama dengan service postgres di docker-compose.yml.
+const postgresImage = "postgres:17.2"
+
+var (
+	containerOnce sync.Once
+	containerURL  string
+	containerErr  error
+)
+
+// serverURL mengembalikan URL Postgres untuk CREATE DATABASE: TEST_DATABASE_URL
+// jika diisi, atau container yang dijalankan sekali per test binary.
+// Container dihapus oleh reaper testcontainers (Ryuk) setelah proses test
+// selesai.
+func serverURL(t testing.TB) string {
+	t.Helper()
+	if url := os.Getenv("TEST_DATABASE_URL"); url != "" {
+		return url
+	}
+
+	containerOnce.Do(func() {
+		containerURL, containerErr = startContainer()
+	})
+	if containerErr != nil {
+		// Tanpa Docker, integration test di-skip seperti tanpa TEST_DATABASE_URL
+		t.Skipf("TEST_DATABASE_URL is not set and the postgres container did not start: %v", containerErr)
+	}
+	return containerURL
+}
+
+// startContainer menjalankan container Postgres dan mengembalikan URL-nya.
+// testcontainers-go panic jika Docker tidak ditemukan, jadi panic tersebut
+// diubah menjadi error.
+func startContainer() (url string, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("%v", recovered)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	container, err := postgres.Run(ctx, postgresImage,
+		postgres.WithDatabase("postgres"),
+		postgres.WithUsername("postgres"),
+		postgres.WithPassword("postgres"),
+		postgres.BasicWaitStrategies(),
+	)
+	if err != nil {
+		return "", err
+	}
+	return container.ConnectionString(ctx, "sslmode=disable")
+}
+
+// New membuat database baru, menjalankan semua migrasi, dan menghapus
+// database tersebut setelah test selesai. Test di-skip jika
+// TEST_DATABASE_URL tidak diisi dan container Postgres tidak bisa
+// dijalankan. Pool memakai QueryCountTracer, lihat MaxQueries.
+func New(t testing.TB) *pgxpool.Pool {
+	t.Helper()
+	pool := NewEmpty(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := database.Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate %s: %v", pool.Config().ConnConfig.Database, err)
+	}
+	return pool
+}
+
+// NewEmpty seperti New tetapi tanpa menjalankan migrasi, untuk menguji
+// database.Migrate sendiri.
+func NewEmpty(t testing.TB) *pgxpool.Pool {
+	t.Helper()
+
+	baseURL := serverURL(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	admin, err := pgx.Connect(ctx, baseURL)
+	if err != nil {
+		t.Fatalf("connect to test postgres: %v", err)
+	}
+	defer admin.Close(ctx)
+
+	name := "test_" + randomSuffix(t)
+	if _, err := admin.Exec(ctx, "CREATE DATABASE "+name); err != nil {
+		t.Fatalf("create database %s: %v", name, err)
+	}
+
+	poolConfig, err := pgxpool.ParseConfig(baseURL)
+	if err != nil {
+		t.Fatalf("parse test postgres url: %v", err)
+	}
+	poolConfig.ConnConfig.Database = name
+	poolConfig.ConnConfig.Tracer = database.NewQueryCountTracer(discardLogger{})
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		t.Fatalf("connect to %s: %v", name, err)
+	}
+
+	t.Cleanup(func() {
+		pool.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		admin, err := pgx.Connect(ctx, baseURL)
+		if err != nil {
+			t.Logf("connect to drop %s: %v", name, err)
+			return
+		}
+		defer admin.Close(ctx)
+		if _, err := admin.Exec(ctx, "DROP DATABASE IF EXISTS "+name+" WITH (FORCE)"); err != nil {
+			t.Logf("drop database %s: %v", name, err)
+		}
+	})
+
+	return pool
+}
+
+// MaxQueries menjalankan run dengan ctx yang menghitung query ke pool dari
+// New, termasuk query di dalam transaksi, seperti middleware.AccessLog
+// menghitung query satu request. Test gagal jika run menjalankan lebih dari
+// max query. Mengembalikan jumlah query.
+func MaxQueries(t testing.TB, max int, run func(ctx context.Context)) int {
+	t.Helper()
+	ctx, counter := database.WithQueryCounter(context.Background(), 0)
+	run(ctx)
+	if counter.Count() > max {
+		t.Fatalf("ran %d queries, want at most %d", counter.Count(), max)
+	}
+	return counter.Count()
+}
+
+// discardLogger membuang log QueryCountTracer. Counter dari MaxQueries tidak
+// punya limit, sehingga tracer tidak pernah me-log.
+type discardLogger struct{}
+
+func (discardLogger) Info(msg string, function helper.FunctionCaller, data ...interface{})  {}
+func (discardLogger) Error(msg string, function helper.FunctionCaller, data ...interface{}) {}
+func (discardLogger) Debug(msg string, function helper.FunctionCaller, data ...interface{}) {}
+func (discardLogger) Warn(msg string, function helper.FunctionCaller, data ...interface{})  {}
+
+func (l discardLogger) With(fields map[string]any) logger.Logger {
+	return l
+}
+
+func (l discardLogger) WithContext(ctx context.Context) logger.Logger {
+	return l
+}
+
+func randomSuffix(t testing.TB) string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatalf("random database name: %v", err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// CreateManager meng-insert manager dan mengembalikan managerId.
+func CreateManager(t testing.TB, db database.DB, email string) string {
+	t.Helper()
+	var managerId string
+	err := db.QueryRow(
+		context.Background(),
+		"INSERT INTO manager (email, password) VALUES ($1, 'password') RETURNING managerid;",
+		email,
+	).Scan(&managerId)
+	if err != nil {
+		t.Fatalf("create manager %s: %v", email, err)
+	}
+	return managerId
+}
+
+// CreateDepartment meng-insert department milik manager dan mengembalikan
+// departmentId.
+func CreateDepartment(t testing.TB, db database.DB, managerId, name string) string {
+	t.Helper()
+	var departmentId string
+	err := db.QueryRow(
+		context.Background(),
+		"INSERT INTO department (departmentname, managerid) VALUES ($1, $2) RETURNING departmentid;",
+		name,
+		managerId,
+	).Scan(&departmentId)
+	if err != nil {
+		t.Fatalf("create department %s: %v", name, err)
+	}
+	return departmentId
+}
+
+// DeleteDepartment menandai department terhapus seperti
+// DepartmentRepository.Delete, termasuk menghapus cache kepemilikannya.
+func DeleteDepartment(t testing.TB, db database.DB, departmentId string) {
+	t.Helper()
+	_, err := db.Exec(
+		context.Background(),
+		"UPDATE department SET isdeleted = TRUE, deletedon = CURRENT_TIMESTAMP WHERE departmentid = $1;",
+		departmentId,
+	)
+	if err != nil {
+		t.Fatalf("delete department %s: %v", departmentId, err)
+	}
+	cache.DeleteDepartmentOwner(departmentId)
+}
+
+type Employee struct {
+	IdentityNumber string
+	Name           string
+	Gender         string
+	DepartmentID   string
+	// CreatedAt opsional, dipakai untuk menguji urutan pagination
+	CreatedAt time.Time
+	// Status opsional, default dto.EmployeeStatusActive
+	Status string
+	// HiredAt opsional dengan format dto.DateLayout, kosong berarti NULL
+	HiredAt string
+}
+
+// CreateEmployee meng-insert employee langsung tanpa lewat repository.
+func CreateEmployee(t testing.TB, db database.DB, e Employee) {
+	t.Helper()
+	createdAt := e.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now().UTC()
+	}
+	_, err := db.Exec(
+		context.Background(),
+		`INSERT INTO employees (identitynumber, name, employeeimageuri, gender, departmentid, created_at, updated_at, status, hired_at)
+		VALUES ($1, $2, 'https://example.com/image.png', $3, $4, $5, $5, COALESCE(NULLIF($6, ''), 'active'), NULLIF($7, '')::date);`,
+		e.IdentityNumber,
+		e.Name,
+		e.Gender,
+		e.DepartmentID,
+		createdAt,
+		e.Status,
+		e.HiredAt,
+	)
+	if err != nil {
+		t.Fatalf("create employee %s: %v", e.IdentityNumber, err)
+	}
+}
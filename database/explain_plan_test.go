@@ -0,0 +1,170 @@
+//go:build integration
+
+package database_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+)
+
+// planLogger menyimpan entry log Debug dari ExplainTracer.
+type planLogger struct {
+	mu      sync.Mutex
+	entries []map[string]any
+	logged  chan struct{}
+}
+
+var _ logger.Logger = (*planLogger)(nil)
+
+func newPlanLogger() *planLogger {
+	return &planLogger{logged: make(chan struct{}, 10)}
+}
+
+func (l *planLogger) Info(msg string, function helper.FunctionCaller, data ...interface{})  {}
+func (l *planLogger) Error(msg string, function helper.FunctionCaller, data ...interface{}) {}
+func (l *planLogger) Warn(msg string, function helper.FunctionCaller, data ...interface{})  {}
+
+func (l *planLogger) Debug(msg string, function helper.FunctionCaller, data ...interface{}) {
+	if function != helper.ExplainQuery || len(data) == 0 {
+		return
+	}
+	entry, _ := data[0].(map[string]any)
+	l.mu.Lock()
+	l.entries = append(l.entries, entry)
+	l.mu.Unlock()
+	l.logged <- struct{}{}
+}
+
+func (l *planLogger) With(fields map[string]any) logger.Logger {
+	return l
+}
+
+func (l *planLogger) WithContext(ctx context.Context) logger.Logger {
+	return l
+}
+
+func (l *planLogger) Entries() []map[string]any {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]map[string]any(nil), l.entries...)
+}
+
+// newExplainPool membuat pool dengan ExplainTracer di atas database dari
+// dbtest, dengan slow threshold 50ms selama test.
+func newExplainPool(t *testing.T, enabled bool, maxBytes int) (*pgxpool.Pool, *planLogger) {
+	t.Helper()
+	threshold := logger.SlowQueryThreshold()
+	logger.SetSlowQueryThreshold(50 * time.Millisecond)
+	t.Cleanup(func() { logger.SetSlowQueryThreshold(threshold) })
+
+	plans := newPlanLogger()
+	tracer := database.NewExplainTracer(plans, enabled, maxBytes)
+	poolConfig := dbtest.New(t).Config()
+	poolConfig.ConnConfig.Tracer = tracer
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+	tracer.SetPool(pool)
+	return pool, plans
+}
+
+const slowQuery = "SELECT pg_sleep(0.2), $1::text AS name;"
+
+func runSlowQuery(t *testing.T, ctx context.Context, pool *pgxpool.Pool) {
+	t.Helper()
+	ctx = database.WithQueryName(ctx, "test_slow")
+	if _, err := pool.Exec(ctx, slowQuery, "Budi"); err != nil {
+		t.Fatalf("slow query: %v", err)
+	}
+}
+
+func waitForPlan(t *testing.T, plans *planLogger) map[string]any {
+	t.Helper()
+	select {
+	case <-plans.logged:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no plan was logged for the slow query")
+	}
+	entries := plans.Entries()
+	return entries[len(entries)-1]
+}
+
+// noPlan memastikan tidak ada plan yang di-log. EXPLAIN berjalan di
+// goroutine, jadi test memberi waktu yang jauh lebih lama dari EXPLAIN biasa.
+func noPlan(t *testing.T, plans *planLogger) {
+	t.Helper()
+	select {
+	case <-plans.logged:
+		t.Fatalf("plan logged = %+v, want none", plans.Entries())
+	case <-time.After(500 * time.Millisecond):
+	}
+}
+
+func TestExplainLogsPlanOfSlowQuery(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		enabled bool
+		ctx     context.Context
+	}{
+		{name: "deployment flag", enabled: true, ctx: context.Background()},
+		{name: "requested", ctx: database.WithExplain(context.Background())},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			pool, plans := newExplainPool(t, tt.enabled, 0)
+			runSlowQuery(t, tt.ctx, pool)
+
+			entry := waitForPlan(t, plans)
+			if entry["query"] != "test_slow" {
+				t.Fatalf("plan query = %v, want test_slow", entry["query"])
+			}
+			if entry["error"] != nil {
+				t.Fatalf("explain failed: %v", entry["error"])
+			}
+			plan, _ := entry["plan"].(string)
+			var parsed []map[string]any
+			if err := json.Unmarshal([]byte(plan), &parsed); err != nil || len(parsed) != 1 || parsed[0]["Plan"] == nil {
+				t.Fatalf("plan = %q is not an EXPLAIN (FORMAT JSON) result: %v", plan, err)
+			}
+			// EXPLAIN dari tracer sendiri tidak ikut di-explain
+			noPlan(t, plans)
+		})
+	}
+}
+
+func TestExplainNeverRunsWhenOff(t *testing.T) {
+	pool, plans := newExplainPool(t, false, 0)
+	runSlowQuery(t, context.Background(), pool)
+	noPlan(t, plans)
+}
+
+func TestExplainSkipsFastQueries(t *testing.T) {
+	pool, plans := newExplainPool(t, true, 0)
+	if _, err := pool.Exec(context.Background(), "SELECT $1::text;", "Budi"); err != nil {
+		t.Fatal(err)
+	}
+	noPlan(t, plans)
+}
+
+func TestExplainCapsPlanSize(t *testing.T) {
+	pool, plans := newExplainPool(t, true, 16)
+	runSlowQuery(t, context.Background(), pool)
+
+	entry := waitForPlan(t, plans)
+	if plan, _ := entry["plan"].(string); len(plan) != 16 {
+		t.Fatalf("plan length = %d, want 16", len(plan))
+	}
+	if entry["plan_truncated"] != true {
+		t.Fatalf("plan_truncated = %v, want true", entry["plan_truncated"])
+	}
+}
@@ -0,0 +1,161 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+)
+
+// Nama query EXPLAIN itu sendiri, lihat ExplainTracer
+const queryExplain QueryName = "explain"
+
+// explainTimeout membatasi EXPLAIN agar tidak menahan koneksi pool terlalu
+// lama. EXPLAIN tanpa ANALYZE hanya merencanakan query, biasanya cepat.
+const explainTimeout = 5 * time.Second
+
+type explainStartKey struct{}
+
+type explainStart struct {
+	at   time.Time
+	sql  string
+	args []any
+}
+
+type explainRequestedKey struct{}
+
+type explainingKey struct{}
+
+// WithExplain meminta plan query lambat yang dijalankan dengan ctx ini
+// di-log walaupun QUERY_EXPLAIN_SLOW mati, mis. untuk request debug admin.
+func WithExplain(ctx context.Context) context.Context {
+	return context.WithValue(ctx, explainRequestedKey{}, true)
+}
+
+func explainRequested(ctx context.Context) bool {
+	requested, _ := ctx.Value(explainRequestedKey{}).(bool)
+	return requested
+}
+
+// ExplainTracer menjalankan ulang EXPLAIN (FORMAT JSON) untuk query yang
+// lebih lama dari logger.SlowQueryThreshold, dengan statement dan parameter
+// yang sama, lalu me-log plan-nya di level Debug. EXPLAIN berjalan di
+// goroutine terpisah dalam transaksi read-only yang selalu di-rollback;
+// paling banyak satu EXPLAIN sekaligus, sisanya dilewati.
+type ExplainTracer struct {
+	logger logger.Logger
+	// enabled berlaku untuk semua query, selain itu hanya ctx WithExplain
+	enabled  bool
+	maxBytes int
+
+	pool atomic.Pointer[pgxpool.Pool]
+	busy chan struct{}
+}
+
+func NewExplainTracer(logger logger.Logger, enabled bool, maxBytes int) *ExplainTracer {
+	return &ExplainTracer{
+		logger:   logger,
+		enabled:  enabled,
+		maxBytes: maxBytes,
+		busy:     make(chan struct{}, 1),
+	}
+}
+
+// SetPool memasang pool tempat EXPLAIN dijalankan. Tracer dibuat sebelum
+// pool-nya, sehingga query sebelum SetPool tidak pernah di-explain.
+func (t *ExplainTracer) SetPool(pool *pgxpool.Pool) {
+	t.pool.Store(pool)
+}
+
+func (t *ExplainTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	// EXPLAIN dari tracer ini sendiri tidak di-explain lagi
+	if ctx.Value(explainingKey{}) != nil || !(t.enabled || explainRequested(ctx)) || !explainable(data.SQL) {
+		return ctx
+	}
+	return context.WithValue(ctx, explainStartKey{}, explainStart{at: time.Now(), sql: data.SQL, args: data.Args})
+}
+
+func (t *ExplainTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(explainStartKey{}).(explainStart)
+	if !ok || helper.IsConnectionError(data.Err) {
+		return
+	}
+	duration := time.Since(start.at)
+	if duration <= logger.SlowQueryThreshold() {
+		return
+	}
+	pool := t.pool.Load()
+	if pool == nil {
+		return
+	}
+
+	select {
+	case t.busy <- struct{}{}:
+	default:
+		return
+	}
+	name := queryName(ctx)
+	// Bukan turunan ctx query: ctx bisa berupa gin.Context yang dipakai ulang
+	// setelah request selesai.
+	ctx = context.WithValue(context.Background(), explainingKey{}, true)
+	go func() {
+		defer func() { <-t.busy }()
+		t.explain(ctx, pool, name, start, duration)
+	}()
+}
+
+func (t *ExplainTracer) explain(ctx context.Context, pool *pgxpool.Pool, name QueryName, start explainStart, duration time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, explainTimeout)
+	defer cancel()
+	ctx = WithQueryName(ctx, queryExplain)
+
+	entry := map[string]any{
+		"query":       string(name),
+		"duration_ms": duration.Milliseconds(),
+	}
+
+	var plan string
+	err := WithTx(ctx, pool, pgx.TxOptions{AccessMode: pgx.ReadOnly}, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, "EXPLAIN (FORMAT JSON) "+start.sql, start.args...).Scan(&plan); err != nil {
+			return err
+		}
+		// Transaksi tidak pernah di-commit
+		return errExplainDone
+	})
+	if !errors.Is(err, errExplainDone) {
+		entry["error"] = err.Error()
+		t.logger.Debug("explain failed", helper.ExplainQuery, entry)
+		return
+	}
+
+	if t.maxBytes > 0 && len(plan) > t.maxBytes {
+		plan = plan[:t.maxBytes]
+		entry["plan_truncated"] = true
+	}
+	entry["plan"] = plan
+	t.logger.Debug("query plan", helper.ExplainQuery, entry)
+}
+
+// errExplainDone membuat WithTx me-rollback transaksi EXPLAIN.
+var errExplainDone = errors.New("explain rollback")
+
+// explainable bernilai true untuk statement yang bisa di-EXPLAIN. Statement
+// lain (BEGIN, COMMIT, LISTEN, SET, ...) dilewati.
+func explainable(sql string) bool {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return false
+	}
+	switch strings.ToUpper(fields[0]) {
+	case "SELECT", "WITH", "INSERT", "UPDATE", "DELETE", "VALUES":
+		return true
+	default:
+		return false
+	}
+}
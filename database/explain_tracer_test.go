@@ -0,0 +1,75 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func TestExplainable(t *testing.T) {
+	for sql, want := range map[string]bool{
+		"SELECT 1;":                            true,
+		"  select * FROM employees":            true,
+		"WITH x AS (SELECT 1) SELECT * FROM x": true,
+		"INSERT INTO employees VALUES ($1)":    true,
+		"UPDATE employees SET name = $1":       true,
+		"DELETE FROM employees":                true,
+		"VALUES (1)":                           true,
+		"BEGIN":                                false,
+		"COMMIT":                               false,
+		"LISTEN outbox":                        false,
+		"SET search_path TO public":            false,
+		"EXPLAIN SELECT 1":                     false,
+		"":                                     false,
+	} {
+		if got := explainable(sql); got != want {
+			t.Errorf("explainable(%q) = %t, want %t", sql, got, want)
+		}
+	}
+}
+
+// TestTraceQueryStartMarksOnlyRequestedQueries memastikan hanya query yang
+// diminta (flag deployment atau WithExplain) yang dicatat untuk di-explain,
+// dan EXPLAIN dari tracer sendiri tidak pernah dicatat.
+func TestTraceQueryStartMarksOnlyRequestedQueries(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		ctx     context.Context
+		sql     string
+		want    bool
+	}{
+		{name: "flag off", ctx: context.Background(), sql: "SELECT 1;", want: false},
+		{name: "flag on", enabled: true, ctx: context.Background(), sql: "SELECT 1;", want: true},
+		{name: "requested", ctx: WithExplain(context.Background()), sql: "SELECT 1;", want: true},
+		{name: "not explainable", enabled: true, ctx: context.Background(), sql: "BEGIN", want: false},
+		{
+			name:    "explain itself",
+			enabled: true,
+			ctx:     context.WithValue(WithExplain(context.Background()), explainingKey{}, true),
+			sql:     "EXPLAIN (FORMAT JSON) SELECT 1;",
+			want:    false,
+		},
+		{
+			name:    "statement of the explain transaction",
+			enabled: true,
+			ctx:     context.WithValue(context.Background(), explainingKey{}, true),
+			sql:     "SELECT 1;",
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracer := NewExplainTracer(nil, tt.enabled, 0)
+			ctx := tracer.TraceQueryStart(tt.ctx, nil, pgx.TraceQueryStartData{SQL: tt.sql, Args: []any{1}})
+			start, marked := ctx.Value(explainStartKey{}).(explainStart)
+			if marked != tt.want {
+				t.Fatalf("query marked for explain = %t, want %t", marked, tt.want)
+			}
+			if marked && (start.sql != tt.sql || len(start.args) != 1) {
+				t.Fatalf("explainStart = %+v, want the query's SQL and args", start)
+			}
+		})
+	}
+}
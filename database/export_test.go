@@ -0,0 +1,6 @@
+package database
+
+// MigrationLockID membuka migrationLockID untuk test di package
+// database_test, yang tidak bisa berada di package ini karena memakai
+// dbtest.
+const MigrationLockID = migrationLockID
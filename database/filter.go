@@ -0,0 +1,44 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Filter menyusun klausa WHERE dinamis beserta argumennya, sehingga query
+// daftar dan query count yang memakai filter yang sama tidak bisa berbeda.
+type Filter struct {
+	conditions []string
+	args       []any
+}
+
+// Where menambahkan satu kondisi. Setiap "$%d" pada format diganti dengan
+// nomor placeholder args sesuai urutan, mis. f.Where("e.gender = $%d", gender).
+func (f *Filter) Where(format string, args ...any) {
+	placeholders := make([]any, len(args))
+	for i, arg := range args {
+		placeholders[i] = f.Arg(arg)
+	}
+	f.conditions = append(f.conditions, fmt.Sprintf(format, placeholders...))
+}
+
+// Arg menambahkan argumen tanpa kondisi dan mengembalikan nomor
+// placeholder-nya, mis. untuk LIMIT dan OFFSET.
+func (f *Filter) Arg(arg any) int {
+	f.args = append(f.args, arg)
+	return len(f.args)
+}
+
+// SQL mengembalikan "WHERE ..." atau string kosong jika tidak ada kondisi.
+func (f *Filter) SQL() string {
+	if len(f.conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(f.conditions, " AND ")
+}
+
+// Args mengembalikan salinan argumen, agar query turunan (mis. dengan LIMIT)
+// tidak mengubah argumen filter asal.
+func (f *Filter) Args() []any {
+	return append([]any(nil), f.args...)
+}
@@ -0,0 +1,218 @@
+package database
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/levensspel/go-gin-template/breaker"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/samber/do/v2"
+)
+
+// Querier adalah method yang dimiliki pgxpool.Pool maupun pgx.Tx, sehingga
+// repository yang memakainya bisa berjalan di pool atau di dalam transaksi.
+type Querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	SendBatch(ctx context.Context, batch *pgx.Batch) pgx.BatchResults
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+}
+
+// DB adalah method pgxpool.Pool yang dipakai repository dan service.
+type DB interface {
+	Querier
+	Begin(ctx context.Context) (pgx.Tx, error)
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+}
+
+// GuardedPool membungkus pgxpool dengan circuit breaker. Saat database
+// mati, request langsung gagal dengan helper.ErrServiceUnavailable alih-alih
+// menunggu connect timeout. Query di dalam transaksi (pgx.Tx) tidak melewati
+// breaker, karena koneksinya sudah didapat lewat Begin.
+type GuardedPool struct {
+	pool    DB
+	breaker *breaker.Breaker
+}
+
+func NewGuardedPool(pool DB, breaker *breaker.Breaker) *GuardedPool {
+	return &GuardedPool{pool: pool, breaker: breaker}
+}
+
+func NewGuardedPoolInject(i do.Injector) (DB, error) {
+	pool := do.MustInvoke[*Pool](i).Pool
+	return NewGuardedPool(pool, do.MustInvoke[*breaker.Breaker](i)), nil
+}
+
+// NewBreakerInject membuat circuit breaker database dan menghubungkan
+// perubahan state-nya ke metric.
+func NewBreakerInject(i do.Injector) (*breaker.Breaker, error) {
+	appMetrics := do.MustInvoke[*metrics.Metrics](i)
+	breakerConfig := config.LoadBreakerConfig()
+
+	b := breaker.New(breaker.Settings{
+		Name:             "postgres",
+		FailureThreshold: breakerConfig.FailureThreshold,
+		Cooldown:         breakerConfig.Cooldown,
+		IsSuccessful:     isDatabaseAvailable,
+		OnStateChange: func(name string, _, to breaker.State) {
+			appMetrics.SetBreakerState(name, int(to))
+		},
+	})
+	appMetrics.SetBreakerState(b.Name(), int(breaker.StateClosed))
+	return b, nil
+}
+
+func (p *GuardedPool) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	done, err := p.breaker.Allow()
+	if err != nil {
+		return pgconn.CommandTag{}, helper.ErrServiceUnavailable
+	}
+	tag, err := p.pool.Exec(ctx, sql, args...)
+	err = acquireError(err)
+	done(err)
+	return tag, err
+}
+
+func (p *GuardedPool) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	done, err := p.breaker.Allow()
+	if err != nil {
+		return nil, helper.ErrServiceUnavailable
+	}
+	rows, err := p.pool.Query(ctx, sql, args...)
+	err = acquireError(err)
+	done(err)
+	return rows, err
+}
+
+func (p *GuardedPool) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	done, err := p.breaker.Allow()
+	if err != nil {
+		return errRow{err: helper.ErrServiceUnavailable}
+	}
+	return guardedRow{row: p.pool.QueryRow(ctx, sql, args...), done: done}
+}
+
+func (p *GuardedPool) Begin(ctx context.Context) (pgx.Tx, error) {
+	done, err := p.breaker.Allow()
+	if err != nil {
+		return nil, helper.ErrServiceUnavailable
+	}
+	tx, err := p.pool.Begin(ctx)
+	err = acquireError(err)
+	done(err)
+	return tx, err
+}
+
+func (p *GuardedPool) BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
+	done, err := p.breaker.Allow()
+	if err != nil {
+		return nil, helper.ErrServiceUnavailable
+	}
+	tx, err := p.pool.BeginTx(ctx, txOptions)
+	err = acquireError(err)
+	done(err)
+	return tx, err
+}
+
+func (p *GuardedPool) SendBatch(ctx context.Context, batch *pgx.Batch) pgx.BatchResults {
+	done, err := p.breaker.Allow()
+	if err != nil {
+		return errBatchResults{err: helper.ErrServiceUnavailable}
+	}
+	return guardedBatchResults{BatchResults: p.pool.SendBatch(ctx, batch), done: done}
+}
+
+func (p *GuardedPool) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	done, err := p.breaker.Allow()
+	if err != nil {
+		return 0, helper.ErrServiceUnavailable
+	}
+	copied, err := p.pool.CopyFrom(ctx, tableName, columnNames, rowSrc)
+	err = acquireError(err)
+	done(err)
+	return copied, err
+}
+
+// acquireError menandai error dari pool yang gagal mendapat koneksi sebelum
+// context habis. pgxpool mengembalikan ctx.Err() apa adanya hanya dari
+// Acquire; timeout setelah koneksi didapat selalu dibungkus pgconn, sehingga
+// tidak ikut ditandai.
+func acquireError(err error) error {
+	if err == context.DeadlineExceeded {
+		return &helper.AcquireTimeoutError{Err: err}
+	}
+	return err
+}
+
+// isDatabaseAvailable menentukan apakah error berarti database tidak bisa
+// dijangkau, lihat helper.IsConnectionError. Error lain, termasuk query yang
+// timeout, gagal di-scan, atau ditolak server (constraint, syntax, no rows),
+// membuktikan database hidup sehingga tidak membuka breaker.
+func isDatabaseAvailable(err error) bool {
+	return !helper.IsConnectionError(err)
+}
+
+type guardedRow struct {
+	row  pgx.Row
+	done func(err error)
+}
+
+func (r guardedRow) Scan(dest ...any) error {
+	err := acquireError(r.row.Scan(dest...))
+	r.done(err)
+	return err
+}
+
+type errRow struct {
+	err error
+}
+
+func (r errRow) Scan(...any) error {
+	return r.err
+}
+
+type guardedBatchResults struct {
+	pgx.BatchResults
+	done func(err error)
+}
+
+func (r guardedBatchResults) Exec() (pgconn.CommandTag, error) {
+	tag, err := r.BatchResults.Exec()
+	return tag, acquireError(err)
+}
+
+func (r guardedBatchResults) Query() (pgx.Rows, error) {
+	rows, err := r.BatchResults.Query()
+	return rows, acquireError(err)
+}
+
+func (r guardedBatchResults) QueryRow() pgx.Row {
+	return acquireRow{row: r.BatchResults.QueryRow()}
+}
+
+func (r guardedBatchResults) Close() error {
+	err := acquireError(r.BatchResults.Close())
+	r.done(err)
+	return err
+}
+
+type acquireRow struct {
+	row pgx.Row
+}
+
+func (r acquireRow) Scan(dest ...any) error {
+	return acquireError(r.row.Scan(dest...))
+}
+
+type errBatchResults struct {
+	err error
+}
+
+func (r errBatchResults) Exec() (pgconn.CommandTag, error) { return pgconn.CommandTag{}, r.err }
+func (r errBatchResults) Query() (pgx.Rows, error)         { return nil, r.err }
+func (r errBatchResults) QueryRow() pgx.Row                { return errRow{err: r.err} }
+func (r errBatchResults) Close() error                     { return r.err }
@@ -0,0 +1,229 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/levensspel/go-gin-template/breaker"
+	"github.com/levensspel/go-gin-template/clock/clocktest"
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+// stubDB adalah DB yang setiap operasinya mengembalikan err dan menghitung
+// berapa kali dipanggil.
+type stubDB struct {
+	err   error
+	calls int
+}
+
+var _ DB = (*stubDB)(nil)
+
+func (s *stubDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	s.calls++
+	return pgconn.CommandTag{}, s.err
+}
+
+func (s *stubDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	s.calls++
+	return nil, s.err
+}
+
+func (s *stubDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	s.calls++
+	return errRow{err: s.err}
+}
+
+func (s *stubDB) SendBatch(ctx context.Context, batch *pgx.Batch) pgx.BatchResults {
+	s.calls++
+	return errBatchResults{err: s.err}
+}
+
+func (s *stubDB) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	s.calls++
+	return 0, s.err
+}
+
+func (s *stubDB) Begin(ctx context.Context) (pgx.Tx, error) {
+	s.calls++
+	return nil, s.err
+}
+
+func (s *stubDB) BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
+	s.calls++
+	return nil, s.err
+}
+
+var errRefused = &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+
+func TestIsDatabaseAvailable(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", want: true},
+		{name: "no rows", err: pgx.ErrNoRows, want: true},
+		{name: "canceled", err: context.Canceled, want: true},
+		{name: "unique violation", err: &pgconn.PgError{Code: "23505"}, want: true},
+		{name: "statement timeout", err: &pgconn.PgError{Code: "57014"}, want: true},
+		{name: "deadline during query", err: fmt.Errorf("timeout: %w", context.DeadlineExceeded), want: true},
+		{name: "scan error", err: errors.New("can't scan into dest[0]: cannot scan int4 into *string"), want: true},
+		{name: "connection failure", err: &pgconn.PgError{Code: "08006"}, want: false},
+		{name: "admin shutdown", err: &pgconn.PgError{Code: "57P01"}, want: false},
+		{name: "connection refused", err: errRefused, want: false},
+		{name: "wrapped connection refused", err: fmt.Errorf("query: %w", errRefused), want: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isDatabaseAvailable(tt.err); got != tt.want {
+				t.Fatalf("isDatabaseAvailable(%v) = %t, want %t", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func newTestGuardedPool(db DB, fake *clocktest.Fake) (*GuardedPool, *breaker.Breaker) {
+	b := breaker.New(breaker.Settings{
+		Name:             "postgres",
+		FailureThreshold: 2,
+		Cooldown:         10 * time.Second,
+		IsSuccessful:     isDatabaseAvailable,
+		Clock:            fake,
+	})
+	return NewGuardedPool(db, b), b
+}
+
+// TestGuardedPoolCycle menjalankan closed -> open -> half-open -> closed
+// terhadap pool yang mati lalu pulih.
+func TestGuardedPoolCycle(t *testing.T) {
+	ctx := context.Background()
+	fake := clocktest.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	db := &stubDB{err: errRefused}
+	pool, b := newTestGuardedPool(db, fake)
+
+	if _, err := pool.Exec(ctx, "SELECT 1"); !errors.Is(err, errRefused) {
+		t.Fatalf("Exec error = %v, want the connection error", err)
+	}
+	if err := pool.QueryRow(ctx, "SELECT 1").Scan(); !errors.Is(err, errRefused) {
+		t.Fatalf("QueryRow error = %v, want the connection error", err)
+	}
+	if b.State() != breaker.StateOpen {
+		t.Fatalf("state = %s after 2 connection errors, want open", b.State())
+	}
+
+	// Saat open, pool tidak dipanggil sama sekali
+	calls := db.calls
+	if _, err := pool.Query(ctx, "SELECT 1"); !errors.Is(err, helper.ErrServiceUnavailable) {
+		t.Fatalf("Query error = %v while open, want ErrServiceUnavailable", err)
+	}
+	if _, err := pool.BeginTx(ctx, pgx.TxOptions{}); !errors.Is(err, helper.ErrServiceUnavailable) {
+		t.Fatalf("BeginTx error = %v while open, want ErrServiceUnavailable", err)
+	}
+	if err := pool.SendBatch(ctx, &pgx.Batch{}).Close(); !errors.Is(err, helper.ErrServiceUnavailable) {
+		t.Fatalf("SendBatch error = %v while open, want ErrServiceUnavailable", err)
+	}
+	if db.calls != calls {
+		t.Fatalf("pool called %d times while open", db.calls-calls)
+	}
+
+	fake.Advance(10 * time.Second)
+	if b.State() != breaker.StateHalfOpen {
+		t.Fatalf("state = %s after cooldown, want half_open", b.State())
+	}
+	db.err = nil
+	if _, err := pool.CopyFrom(ctx, pgx.Identifier{"employees"}, nil, pgx.CopyFromRows(nil)); err != nil {
+		t.Fatalf("probe error = %v", err)
+	}
+	if b.State() != breaker.StateClosed {
+		t.Fatalf("state = %s after a successful probe, want closed", b.State())
+	}
+}
+
+// TestGuardedPoolQueryErrorsKeepBreakerClosed memastikan query yang lambat
+// atau salah tidak membuat seluruh database menjawab 503.
+func TestGuardedPoolQueryErrorsKeepBreakerClosed(t *testing.T) {
+	ctx := context.Background()
+	fake := clocktest.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	for _, err := range []error{
+		&pgconn.PgError{Code: "57014"},
+		fmt.Errorf("timeout: %w", context.DeadlineExceeded),
+		errors.New("can't scan into dest[0]"),
+		&pgconn.PgError{Code: "23505"},
+	} {
+		db := &stubDB{err: err}
+		pool, b := newTestGuardedPool(db, fake)
+		for range 5 {
+			_, _ = pool.Exec(ctx, "SELECT pg_sleep(10)")
+			_ = pool.QueryRow(ctx, "SELECT 1").Scan()
+		}
+		if b.State() != breaker.StateClosed || db.calls != 10 {
+			t.Fatalf("%v: state = %s, calls = %d, want closed with every call reaching the pool", err, b.State(), db.calls)
+		}
+	}
+}
+
+// TestGuardedPoolMarksAcquireTimeout memastikan hanya ctx.Err() apa adanya
+// dari pgxpool, yang berarti gagal mendapat koneksi, ditandai sebagai
+// helper.AcquireTimeoutError. Timeout setelah query terkirim tetap bukan
+// error koneksi.
+func TestGuardedPoolMarksAcquireTimeout(t *testing.T) {
+	ctx := context.Background()
+	fake := clocktest.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	calls := map[string]func(pool *GuardedPool) error{
+		"Exec": func(pool *GuardedPool) error {
+			_, err := pool.Exec(ctx, "SELECT 1")
+			return err
+		},
+		"Query": func(pool *GuardedPool) error {
+			_, err := pool.Query(ctx, "SELECT 1")
+			return err
+		},
+		"QueryRow": func(pool *GuardedPool) error {
+			return pool.QueryRow(ctx, "SELECT 1").Scan()
+		},
+		"BeginTx": func(pool *GuardedPool) error {
+			_, err := pool.BeginTx(ctx, pgx.TxOptions{})
+			return err
+		},
+		"CopyFrom": func(pool *GuardedPool) error {
+			_, err := pool.CopyFrom(ctx, pgx.Identifier{"employees"}, nil, pgx.CopyFromRows(nil))
+			return err
+		},
+		"SendBatch.Exec": func(pool *GuardedPool) error {
+			_, err := pool.SendBatch(ctx, &pgx.Batch{}).Exec()
+			return err
+		},
+		"SendBatch.Close": func(pool *GuardedPool) error {
+			return pool.SendBatch(ctx, &pgx.Batch{}).Close()
+		},
+	}
+	for _, tt := range []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "acquire timeout", err: context.DeadlineExceeded, want: true},
+		{name: "query timeout", err: fmt.Errorf("timeout: %w", context.DeadlineExceeded), want: false},
+		{name: "canceled", err: context.Canceled, want: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, call := range calls {
+				// Pool baru per method, agar breaker tidak terbuka oleh method sebelumnya
+				pool, _ := newTestGuardedPool(&stubDB{err: tt.err}, fake)
+				err := call(pool)
+				var acquireErr *helper.AcquireTimeoutError
+				if got := errors.As(err, &acquireErr); got != tt.want {
+					t.Errorf("%s error = %v, AcquireTimeoutError = %t, want %t", name, err, got, tt.want)
+				}
+				if !errors.Is(err, tt.err) {
+					t.Errorf("%s error = %v, want it to wrap %v", name, err, tt.err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+)
+
+const (
+	listenerMinBackoff = 500 * time.Millisecond
+	listenerMaxBackoff = 30 * time.Second
+)
+
+// Listener menjalankan LISTEN pada satu channel Postgres dengan koneksi
+// khusus dari pool, dan memanggil handler untuk setiap NOTIFY. Koneksi yang
+// putus dibuka ulang dengan exponential backoff.
+//
+// Notifikasi yang dikirim selama koneksi putus hilang, sehingga onReconnect
+// dipanggil setiap kali LISTEN aktif kembali agar pemakai bisa membuang
+// state yang mungkin sudah basi.
+type Listener struct {
+	pool        *pgxpool.Pool
+	channel     string
+	handler     func(payload string)
+	onReconnect func()
+	logger      logger.Logger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewListener(
+	pool *pgxpool.Pool,
+	channel string,
+	handler func(payload string),
+	onReconnect func(),
+	logger logger.Logger,
+) *Listener {
+	return &Listener{
+		pool:        pool,
+		channel:     channel,
+		handler:     handler,
+		onReconnect: onReconnect,
+		logger:      logger,
+	}
+}
+
+// Start menjalankan listener di goroutine terpisah sampai Shutdown atau
+// ctx dibatalkan.
+func (l *Listener) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	l.cancel = cancel
+	l.done = make(chan struct{})
+
+	go func() {
+		defer close(l.done)
+		backoff := listenerMinBackoff
+		for {
+			connected, err := l.listen(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			if connected {
+				backoff = listenerMinBackoff
+			}
+			l.logger.Warn(fmt.Sprintf("LISTEN %s stopped, retrying in %s: %v", l.channel, backoff, err), helper.DatabaseListener)
+
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return
+			}
+			backoff = min(backoff*2, listenerMaxBackoff)
+		}
+	}()
+}
+
+// listen mengembalikan connected=true jika LISTEN sempat aktif sebelum
+// error, agar backoff di-reset.
+func (l *Listener) listen(ctx context.Context) (connected bool, err error) {
+	poolConn, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+	// Koneksi yang sedang LISTEN tidak boleh kembali ke pool
+	conn := poolConn.Hijack()
+	defer conn.Close(context.WithoutCancel(ctx))
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{l.channel}.Sanitize()); err != nil {
+		return false, err
+	}
+	if l.onReconnect != nil {
+		l.onReconnect()
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return true, err
+		}
+		l.handler(notification.Payload)
+	}
+}
+
+// Shutdown menghentikan listener dan menunggu goroutine-nya selesai.
+func (l *Listener) Shutdown(ctx context.Context) error {
+	if l.cancel == nil {
+		return nil
+	}
+	l.cancel()
+	select {
+	case <-l.done:
+		return nil
+	case <-ctx.Done():
+		return errors.Join(errors.New("listener did not stop in time"), ctx.Err())
+	}
+}
@@ -0,0 +1,128 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockID adalah key pg_advisory_lock agar instance yang start
+// bersamaan tidak menjalankan migrasi yang sama dua kali.
+const migrationLockID int64 = 7_351_208_640
+
+type migration struct {
+	version string
+	sql     string
+}
+
+// Migrate menjalankan file di database/migrations yang belum tercatat di
+// schema_migrations, berurutan sesuai nama file. Setiap file berjalan dalam
+// transaksinya sendiri, sehingga file yang gagal tidak tercatat dan akan
+// dicoba lagi saat startup berikutnya.
+func Migrate(ctx context.Context, db *pgxpool.Pool) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	conn, err := db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	// Advisory lock terikat ke koneksi, jadi semua langkah memakai conn
+	// yang sama.
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1);", migrationLockID); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer conn.Exec(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1);", migrationLockID)
+
+	_, err = conn.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version varchar(255) PRIMARY KEY,
+			applied_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+	`)
+	if err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	rows, err := conn.Query(ctx, "SELECT version FROM schema_migrations;")
+	if err != nil {
+		return err
+	}
+	applied := make(map[string]bool)
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			rows.Close()
+			return err
+		}
+		applied[version] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if applied[m.version] {
+			continue
+		}
+		if err := applyMigration(ctx, conn, m); err != nil {
+			return fmt.Errorf("migration %s: %w", m.version, err)
+		}
+		log.Printf("Applied migration %s", m.version)
+	}
+	return nil
+}
+
+func applyMigration(ctx context.Context, conn *pgxpool.Conn, m migration) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.WithoutCancel(ctx))
+
+	// Exec tanpa argumen memakai simple protocol, sehingga satu file boleh
+	// berisi beberapa statement.
+	if _, err := tx.Exec(ctx, m.sql); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version) VALUES ($1);", m.version); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func loadMigrations() ([]migration, error) {
+	names, err := fs.Glob(migrationFiles, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	migrations := make([]migration, 0, len(names))
+	for _, name := range names {
+		content, err := migrationFiles.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, migration{
+			version: strings.TrimSuffix(path.Base(name), ".sql"),
+			sql:     string(content),
+		})
+	}
+	return migrations, nil
+}
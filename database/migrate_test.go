@@ -0,0 +1,213 @@
+//go:build integration
+
+package database_test
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+)
+
+// migrationFiles membaca isi file di database/migrations per versi.
+func migrationFiles(t *testing.T) map[string]string {
+	t.Helper()
+	entries, err := os.ReadDir("migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string)
+	for _, entry := range entries {
+		if path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+		content, err := os.ReadFile(path.Join("migrations", entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[strings.TrimSuffix(entry.Name(), ".sql")] = string(content)
+	}
+	return files
+}
+
+// appliedMigrations mengembalikan isi schema_migrations, versi ke
+// applied_at.
+func appliedMigrations(t *testing.T, pool *pgxpool.Pool) map[string]time.Time {
+	t.Helper()
+	rows, err := pool.Query(context.Background(), "SELECT version, applied_at FROM schema_migrations;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	applied := make(map[string]time.Time)
+	var version string
+	var appliedAt time.Time
+	_, err = pgx.ForEachRow(rows, []any{&version, &appliedAt}, func() error {
+		applied[version] = appliedAt
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return applied
+}
+
+// sqlRecorder mencatat SQL setiap query yang dijalankan pool.
+type sqlRecorder struct {
+	mu  sync.Mutex
+	sql []string
+}
+
+func (r *sqlRecorder) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sql = append(r.sql, data.SQL)
+	return ctx
+}
+
+func (r *sqlRecorder) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+}
+
+func TestMigrateFullChain(t *testing.T) {
+	pool := dbtest.NewEmpty(t)
+	ctx := context.Background()
+
+	if err := database.Migrate(ctx, pool); err != nil {
+		t.Fatalf("Migrate error = %v", err)
+	}
+
+	files := migrationFiles(t)
+	versions := slices.Sorted(maps.Keys(files))
+	if versions[0] != "0000_initial_schema" || !strings.HasPrefix(versions[len(versions)-1], "0023_") {
+		t.Fatalf("migrations = %v, want 0000 to 0023", versions)
+	}
+	if applied := slices.Sorted(maps.Keys(appliedMigrations(t, pool))); !slices.Equal(applied, versions) {
+		t.Fatalf("schema_migrations = %v, want %v", applied, versions)
+	}
+
+	for _, table := range []string{"manager", "department", "employees", "employee_versions", "outbox", "audit_log", "webhook_delivery", "file", "api_key"} {
+		var exists bool
+		if err := pool.QueryRow(ctx, "SELECT to_regclass('public.' || $1) IS NOT NULL;", table).Scan(&exists); err != nil {
+			t.Fatal(err)
+		}
+		if !exists {
+			t.Errorf("table %s does not exist after the full chain", table)
+		}
+	}
+}
+
+// TestMigrateRerunIsNoop memastikan Migrate kedua tidak menjalankan file
+// migrasi apa pun dan tidak mengubah schema_migrations.
+func TestMigrateRerunIsNoop(t *testing.T) {
+	migrated := dbtest.New(t)
+	before := appliedMigrations(t, migrated)
+
+	recorder := &sqlRecorder{}
+	poolConfig := migrated.Config()
+	poolConfig.ConnConfig.Tracer = recorder
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	if err := database.Migrate(context.Background(), pool); err != nil {
+		t.Fatalf("second Migrate error = %v", err)
+	}
+
+	if after := appliedMigrations(t, migrated); !maps.Equal(after, before) {
+		t.Fatalf("schema_migrations changed from %v to %v", before, after)
+	}
+	files := migrationFiles(t)
+	for _, sql := range recorder.sql {
+		if strings.EqualFold(strings.TrimSpace(sql), "begin") || strings.Contains(sql, "INSERT INTO schema_migrations") {
+			t.Errorf("second run executed %q", sql)
+		}
+		for version, content := range files {
+			if sql == content {
+				t.Errorf("second run re-applied %s", version)
+			}
+		}
+	}
+}
+
+// TestMigrateConcurrentRunners menjalankan dua Migrate bersamaan pada
+// database kosong. Keduanya harus antre di advisory lock, lalu berhasil
+// tanpa menerapkan migrasi yang sama dua kali.
+func TestMigrateConcurrentRunners(t *testing.T) {
+	const runners = 2
+	pool := dbtest.NewEmpty(t)
+	ctx := context.Background()
+
+	// Lock ditahan dulu agar kedua runner pasti antre bersamaan
+	holder, err := pool.Acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer holder.Release()
+	if _, err := holder.Exec(ctx, "SELECT pg_advisory_lock($1);", database.MigrationLockID); err != nil {
+		t.Fatal(err)
+	}
+
+	errs := make(chan error, runners)
+	for range runners {
+		go func() {
+			// Pool sendiri per runner, seperti dua instance yang start bersamaan
+			runnerPool, err := pgxpool.NewWithConfig(ctx, pool.Config())
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer runnerPool.Close()
+			errs <- database.Migrate(ctx, runnerPool)
+		}()
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		var waiting int
+		err := pool.QueryRow(ctx, `
+			SELECT COUNT(*) FROM pg_locks l
+			JOIN pg_database d ON d.oid = l.database
+			WHERE l.locktype = 'advisory' AND NOT l.granted AND d.datname = current_database();
+		`).Scan(&waiting)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if waiting == runners {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d runners waiting on the migration lock, want %d", waiting, runners)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	select {
+	case err := <-errs:
+		t.Fatalf("a runner finished while the migration lock was held: %v", err)
+	default:
+	}
+
+	if _, err := holder.Exec(ctx, "SELECT pg_advisory_unlock($1);", database.MigrationLockID); err != nil {
+		t.Fatal(err)
+	}
+	for range runners {
+		if err := <-errs; err != nil {
+			t.Fatalf("Migrate error = %v", err)
+		}
+	}
+
+	versions := slices.Sorted(maps.Keys(migrationFiles(t)))
+	if applied := slices.Sorted(maps.Keys(appliedMigrations(t, pool))); !slices.Equal(applied, versions) {
+		t.Fatalf("schema_migrations = %v, want %v", applied, versions)
+	}
+}
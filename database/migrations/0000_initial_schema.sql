@@ -0,0 +1,47 @@
+-- Skema awal, sama dengan table_definitions_dll. IF NOT EXISTS agar database
+-- yang sebelumnya dibuat manual dari file DDL tetap bisa dimigrasi.
+CREATE TABLE IF NOT EXISTS public.manager (
+	managerid varchar(255) NOT NULL DEFAULT gen_random_uuid(),
+	"name" varchar(255) NULL,
+	email varchar(255) NULL,
+	"password" varchar(255) NULL,
+	userimageuri varchar(255) NULL,
+	companyname varchar(255) NULL,
+	companyimageuri varchar(255) NULL,
+	isdeleted bool NOT NULL DEFAULT false,
+	updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT manager_email_key1 UNIQUE (email),
+	CONSTRAINT manager_pkey1 PRIMARY KEY (managerid)
+);
+
+CREATE TABLE IF NOT EXISTS public.department (
+	departmentid varchar(255) NOT NULL DEFAULT gen_random_uuid(),
+	departmentname varchar(255) NOT NULL,
+	isdeleted bool NOT NULL DEFAULT false,
+	createdon timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updatedon timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	managerid varchar(255) NULL,
+	CONSTRAINT department_pkey1 PRIMARY KEY (departmentid),
+	CONSTRAINT fk_manager FOREIGN KEY (managerid) REFERENCES public.manager(managerid)
+);
+
+CREATE TABLE IF NOT EXISTS public.employees (
+	id varchar(255) NOT NULL DEFAULT gen_random_uuid(),
+	"name" varchar(255) NOT NULL,
+	identitynumber varchar(255) NOT NULL UNIQUE,
+	employeeimageuri varchar(255) NOT NULL,
+	gender varchar(6) NOT NULL,
+	departmentid varchar NOT NULL,
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT employees_pkey PRIMARY KEY (id),
+	CONSTRAINT fk_manager FOREIGN KEY (departmentid) REFERENCES public.department(departmentid)
+);
+
+CREATE TABLE IF NOT EXISTS public.file (
+	fileid varchar(255) NOT NULL DEFAULT gen_random_uuid(),
+	filename varchar(255) NULL,
+	fileuri varchar(255) NULL,
+	CONSTRAINT file_pkey PRIMARY KEY (fileid)
+);
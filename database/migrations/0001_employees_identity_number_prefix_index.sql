@@ -0,0 +1,11 @@
+-- Pencarian prefix identityNumber (GET /v1/employee?identityNumber=) memakai
+-- kolom lowercase ini agar bisa memakai index, alih-alih LOWER(...) ILIKE
+-- yang selalu seq scan.
+ALTER TABLE public.employees
+	ADD COLUMN IF NOT EXISTS identitynumber_lower varchar(255)
+	GENERATED ALWAYS AS (lower(identitynumber)) STORED;
+
+-- text_pattern_ops agar LIKE 'prefix%' bisa memakai index apa pun collation
+-- database-nya.
+CREATE INDEX IF NOT EXISTS employees_identitynumber_lower_pattern_idx
+	ON public.employees (identitynumber_lower text_pattern_ops);
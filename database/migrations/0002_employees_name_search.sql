@@ -0,0 +1,8 @@
+-- Full-text search nama employee (GET /v1/employee?q=). Konfigurasi 'simple'
+-- dipakai karena nama tidak perlu stemming bahasa tertentu.
+ALTER TABLE public.employees
+	ADD COLUMN IF NOT EXISTS name_tsv tsvector
+	GENERATED ALWAYS AS (to_tsvector('simple', name)) STORED;
+
+CREATE INDEX IF NOT EXISTS employees_name_tsv_idx
+	ON public.employees USING GIN (name_tsv);
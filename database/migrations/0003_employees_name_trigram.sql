@@ -0,0 +1,19 @@
+-- Pencarian nama dengan fuzzy=true (typo tolerant). Aplikasi tetap jalan
+-- tanpa extension ini (mis. user database tidak punya hak CREATE EXTENSION);
+-- pencarian fuzzy kembali ke contains biasa.
+DO $$
+BEGIN
+	CREATE EXTENSION IF NOT EXISTS pg_trgm;
+EXCEPTION WHEN OTHERS THEN
+	RAISE NOTICE 'pg_trgm is not available: %', SQLERRM;
+END
+$$;
+
+DO $$
+BEGIN
+	IF EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'pg_trgm') THEN
+		CREATE INDEX IF NOT EXISTS employees_name_trgm_idx
+			ON public.employees USING GIN (name gin_trgm_ops);
+	END IF;
+END
+$$;
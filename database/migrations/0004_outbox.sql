@@ -0,0 +1,20 @@
+-- Event domain ditulis di transaksi yang sama dengan perubahan datanya, lalu
+-- dikirim oleh outbox.Dispatcher. Baris dengan sent_at NULL belum terkirim.
+CREATE TABLE IF NOT EXISTS public.outbox (
+	id bigserial PRIMARY KEY,
+	event_type varchar(100) NOT NULL,
+	aggregate_type varchar(50) NOT NULL,
+	aggregate_id varchar(255) NOT NULL,
+	payload jsonb NOT NULL,
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	sent_at timestamp NULL,
+	attempts int NOT NULL DEFAULT 0,
+	next_attempt_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	last_error text NULL
+);
+
+-- Dipakai dispatcher untuk mencari event terlama yang belum terkirim per
+-- aggregate.
+CREATE INDEX IF NOT EXISTS outbox_unsent_aggregate_idx
+	ON public.outbox (aggregate_type, aggregate_id, id)
+	WHERE sent_at IS NULL;
@@ -0,0 +1,18 @@
+-- Catatan siapa mengubah apa dan kapan. Ditulis service di transaksi yang
+-- sama dengan perubahannya; changes hanya berisi field yang berubah.
+CREATE TABLE IF NOT EXISTS public.audit_log (
+	id bigserial PRIMARY KEY,
+	actor_id varchar(255) NOT NULL,
+	action varchar(20) NOT NULL,
+	entity_type varchar(50) NOT NULL,
+	entity_id varchar(255) NOT NULL,
+	changes jsonb NOT NULL,
+	request_id varchar(128) NULL,
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_actor_created_at_idx
+	ON public.audit_log (actor_id, created_at DESC, id DESC);
+
+CREATE INDEX IF NOT EXISTS audit_log_entity_idx
+	ON public.audit_log (entity_type, entity_id, created_at DESC);
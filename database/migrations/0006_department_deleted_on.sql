@@ -0,0 +1,12 @@
+-- Waktu soft-delete department, dipakai purge job untuk menghapus permanen
+-- department yang sudah melewati masa retensi. Department yang sudah
+-- terhapus sebelum kolom ini ada memakai updatedon sebagai perkiraan.
+ALTER TABLE public.department ADD COLUMN IF NOT EXISTS deletedon timestamp NULL;
+
+UPDATE public.department
+SET deletedon = updatedon
+WHERE isdeleted = TRUE AND deletedon IS NULL;
+
+CREATE INDEX IF NOT EXISTS department_deletedon_idx
+	ON public.department (deletedon)
+	WHERE isdeleted = TRUE;
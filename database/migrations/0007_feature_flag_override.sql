@@ -0,0 +1,9 @@
+-- Override feature flag per manager. Flag yang tidak punya baris di sini
+-- memakai default dari config (FEATURE_FLAGS).
+CREATE TABLE IF NOT EXISTS public.feature_flag_override (
+	flag varchar(100) NOT NULL,
+	manager_id varchar(255) NOT NULL,
+	enabled bool NOT NULL,
+	updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT feature_flag_override_pkey PRIMARY KEY (manager_id, flag)
+);
@@ -0,0 +1,41 @@
+-- Langganan webhook milik manager. events kosong berarti semua event.
+CREATE TABLE IF NOT EXISTS public.webhook (
+	id varchar(36) PRIMARY KEY,
+	manager_id varchar(255) NOT NULL,
+	url text NOT NULL,
+	secret varchar(128) NOT NULL,
+	events text[] NOT NULL DEFAULT '{}',
+	active bool NOT NULL DEFAULT true,
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS webhook_manager_idx
+	ON public.webhook (manager_id)
+	WHERE active;
+
+-- Satu baris per event per webhook, dikirim oleh webhook.Deliverer. Baris
+-- dengan status dead sudah melewati batas percobaan dan tidak dikirim lagi.
+-- event_id NULL untuk pengiriman test.
+CREATE TABLE IF NOT EXISTS public.webhook_delivery (
+	id bigserial PRIMARY KEY,
+	webhook_id varchar(36) NOT NULL REFERENCES public.webhook (id) ON DELETE CASCADE,
+	event_id bigint NULL,
+	event_type varchar(100) NOT NULL,
+	payload jsonb NOT NULL,
+	status varchar(20) NOT NULL DEFAULT 'pending',
+	attempts int NOT NULL DEFAULT 0,
+	next_attempt_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	last_status_code int NULL,
+	last_error text NULL,
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	delivered_at timestamp NULL,
+	-- Event outbox bisa di-publish lebih dari sekali, lihat outbox.Publisher
+	CONSTRAINT webhook_delivery_event_key UNIQUE (webhook_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS webhook_delivery_pending_idx
+	ON public.webhook_delivery (next_attempt_at)
+	WHERE status = 'pending';
+
+CREATE INDEX IF NOT EXISTS webhook_delivery_webhook_idx
+	ON public.webhook_delivery (webhook_id, id DESC);
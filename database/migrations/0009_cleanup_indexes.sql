@@ -0,0 +1,9 @@
+-- Dipakai purge.Cleaner untuk mencari baris yang sudah melewati retention
+-- tanpa scan seluruh tabel.
+CREATE INDEX IF NOT EXISTS outbox_sent_idx
+	ON public.outbox (sent_at)
+	WHERE sent_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS webhook_delivery_finished_idx
+	ON public.webhook_delivery (created_at)
+	WHERE status <> 'pending';
@@ -0,0 +1,9 @@
+-- Pemilik dan metadata file yang di-upload lewat POST /v1/file. Baris lama
+-- tanpa pemilik tetap ada dengan managerid NULL.
+ALTER TABLE public.file ADD COLUMN IF NOT EXISTS managerid varchar(255) NULL;
+ALTER TABLE public.file ADD COLUMN IF NOT EXISTS filesize bigint NOT NULL DEFAULT 0;
+ALTER TABLE public.file ADD COLUMN IF NOT EXISTS contenttype varchar(100) NULL;
+ALTER TABLE public.file ADD COLUMN IF NOT EXISTS createdon timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS file_managerid_idx
+	ON public.file (managerid);
@@ -0,0 +1,3 @@
+-- Key object di storage, dipakai GET /files/:id untuk membaca isi file.
+-- Baris lama tanpa key tidak bisa dilayani endpoint tersebut.
+ALTER TABLE public.file ADD COLUMN IF NOT EXISTS filekey varchar(512) NULL;
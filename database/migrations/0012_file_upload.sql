@@ -0,0 +1,16 @@
+-- Upload presign yang belum dikonfirmasi lewat POST /v1/file/:id/complete.
+-- Baris dihapus saat dikonfirmasi, atau oleh purge.Cleaner (beserta
+-- object-nya di storage) setelah expires_at lewat.
+CREATE TABLE IF NOT EXISTS public.file_upload (
+	id varchar(36) PRIMARY KEY,
+	manager_id varchar(255) NOT NULL,
+	file_key varchar(512) NOT NULL,
+	file_name varchar(255) NOT NULL DEFAULT '',
+	content_type varchar(100) NOT NULL,
+	size bigint NOT NULL,
+	expires_at timestamp NOT NULL,
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS file_upload_expires_at_idx
+	ON public.file_upload (expires_at);
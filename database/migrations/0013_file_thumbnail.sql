@@ -0,0 +1,16 @@
+-- Thumbnail gambar yang dibuat di background setelah upload, lihat
+-- thumbnail.Generator. thumbnailstatus NULL berarti file tidak punya
+-- thumbnail (dibuat sebelum kolom ini ada atau thumbnail dimatikan).
+ALTER TABLE public.file ADD COLUMN IF NOT EXISTS thumbnailkey varchar(512) NULL;
+ALTER TABLE public.file ADD COLUMN IF NOT EXISTS thumbnailuri varchar(255) NULL;
+ALTER TABLE public.file ADD COLUMN IF NOT EXISTS thumbnailstatus varchar(16) NULL;
+ALTER TABLE public.file ADD COLUMN IF NOT EXISTS thumbnailattempts int NOT NULL DEFAULT 0;
+ALTER TABLE public.file ADD COLUMN IF NOT EXISTS thumbnailnextattemptat timestamp NULL;
+
+CREATE INDEX IF NOT EXISTS file_thumbnail_pending_idx
+	ON public.file (thumbnailnextattemptat)
+	WHERE thumbnailstatus = 'pending';
+
+-- GET /v1/employee?expand=images mencari file dari employeeImageUri
+CREATE INDEX IF NOT EXISTS file_fileuri_idx
+	ON public.file (fileuri);
@@ -0,0 +1,34 @@
+-- Jumlah employee dan profil manager yang merujuk file lewat fileuri, lihat
+-- FileRepository.AdjustReferences. File yang tidak dirujuk sejak
+-- unreferencedsince lebih lama dari CLEANUP_FILE_GRACE_PERIOD dihapus
+-- purge.Cleaner beserta object-nya. File baru dihitung tidak dirujuk sejak
+-- di-upload.
+ALTER TABLE public.file ADD COLUMN IF NOT EXISTS referencecount int NOT NULL DEFAULT 0;
+ALTER TABLE public.file ADD COLUMN IF NOT EXISTS unreferencedsince timestamp NULL DEFAULT CURRENT_TIMESTAMP;
+
+-- Rujukan yang sudah ada sebelum kolom ini dibuat
+UPDATE public.file f
+SET referencecount = refs.count
+FROM (
+	SELECT uri, count(*) AS count
+	FROM (
+		SELECT employeeimageuri AS uri FROM public.employees
+		UNION ALL
+		SELECT userimageuri FROM public.manager
+		UNION ALL
+		SELECT companyimageuri FROM public.manager
+	) AS uris
+	GROUP BY uri
+) AS refs
+WHERE f.fileuri = refs.uri;
+
+UPDATE public.file SET unreferencedsince = NULL WHERE referencecount > 0;
+
+CREATE INDEX IF NOT EXISTS file_unreferenced_idx
+	ON public.file (unreferencedsince)
+	WHERE referencecount = 0;
+
+-- Dipakai purge.Cleaner untuk memastikan file benar-benar tidak dirujuk
+-- employee sebelum dihapus
+CREATE INDEX IF NOT EXISTS employees_employeeimageuri_idx
+	ON public.employees (employeeimageuri);
@@ -0,0 +1,4 @@
+-- Kegunaan file: gambar employee (POST /v1/file) atau gambar profil manager
+-- (PUT /v1/user/avatar dan /v1/user/company-logo). File lama dianggap
+-- gambar employee, cara upload satu-satunya sebelum kolom ini ada.
+ALTER TABLE public.file ADD COLUMN IF NOT EXISTS purpose varchar(16) NOT NULL DEFAULT 'employee';
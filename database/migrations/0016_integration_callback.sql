@@ -0,0 +1,19 @@
+-- Callback dari partner yang signature-nya sudah diverifikasi, lihat
+-- POST /v1/integrations/callback. Baris dengan processed_at NULL adalah
+-- antrian yang belum diproses. Payload disimpan apa adanya (bytea) karena
+-- signature dihitung dari byte body asli.
+CREATE TABLE IF NOT EXISTS public.integration_callback (
+	id bigserial PRIMARY KEY,
+	integration varchar(64) NOT NULL,
+	signature varchar(100) NOT NULL,
+	signed_at timestamp NOT NULL,
+	payload bytea NOT NULL,
+	received_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	processed_at timestamp NULL,
+	-- Signature yang sama dalam tolerance timestamp adalah replay
+	CONSTRAINT integration_callback_signature_key UNIQUE (integration, signature)
+);
+
+CREATE INDEX IF NOT EXISTS integration_callback_pending_idx
+	ON public.integration_callback (id)
+	WHERE processed_at IS NULL;
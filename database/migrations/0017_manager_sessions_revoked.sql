@@ -0,0 +1,3 @@
+-- Waktu terakhir semua sesi manager dicabut (admin sessions revoke). JWT
+-- dengan iat sebelum atau sama dengan waktu ini ditolak, lihat auth.Revocations.
+ALTER TABLE public.manager ADD COLUMN IF NOT EXISTS sessions_revoked_at timestamp NULL;
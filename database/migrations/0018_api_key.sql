@@ -0,0 +1,17 @@
+-- API key milik manager untuk tool eksternal, lihat middleware.APIKey. Key
+-- hanya disimpan sebagai SHA-256, prefix dipakai untuk mengenali key di
+-- daftar. expires_at NULL berarti tidak kedaluwarsa.
+CREATE TABLE IF NOT EXISTS public.api_key (
+	id varchar(36) PRIMARY KEY,
+	manager_id varchar(255) NOT NULL,
+	name varchar(100) NOT NULL,
+	key_prefix varchar(16) NOT NULL,
+	key_hash char(64) NOT NULL,
+	scopes text[] NOT NULL,
+	expires_at timestamp NULL,
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT api_key_hash_key UNIQUE (key_hash)
+);
+
+CREATE INDEX IF NOT EXISTS api_key_manager_idx
+	ON public.api_key (manager_id);
@@ -0,0 +1,7 @@
+-- GET /v1/employee memfilter employee lewat EXISTS ke department milik
+-- manager. Tanpa index ini kedua sisi EXISTS selalu seq scan.
+CREATE INDEX IF NOT EXISTS department_managerid_idx
+	ON public.department (managerid);
+
+CREATE INDEX IF NOT EXISTS employees_departmentid_idx
+	ON public.employees (departmentid);
@@ -0,0 +1,5 @@
+-- Employee yang diarsipkan (mis. cuti panjang) tidak muncul di daftar dan
+-- headcount default, tetapi tidak dihapus dan tetap memakai identityNumber-nya.
+ALTER TABLE public.employees
+	ADD COLUMN IF NOT EXISTS status varchar(8) NOT NULL DEFAULT 'active'
+	CONSTRAINT employees_status_check CHECK (status IN ('active', 'archived'));
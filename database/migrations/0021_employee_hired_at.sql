@@ -0,0 +1,4 @@
+-- Tanggal mulai bekerja untuk laporan masa kerja. Employee yang sudah ada
+-- tidak punya tanggal ini, sehingga kolomnya boleh NULL.
+ALTER TABLE public.employees
+	ADD COLUMN IF NOT EXISTS hired_at date NULL;
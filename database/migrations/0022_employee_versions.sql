@@ -0,0 +1,19 @@
+-- Salinan lengkap employee setiap kali dibuat, diubah, atau dihapus, untuk
+-- riwayat per employee. Ditulis service di transaksi yang sama dengan
+-- perubahannya. employee_id sengaja tanpa foreign key agar riwayat tidak
+-- ikut hilang saat employee dihapus.
+CREATE TABLE IF NOT EXISTS public.employee_versions (
+	employee_id varchar(255) NOT NULL,
+	version integer NOT NULL,
+	action varchar(20) NOT NULL,
+	identitynumber varchar(255) NOT NULL,
+	"name" varchar(255) NOT NULL,
+	employeeimageuri varchar(255) NOT NULL,
+	gender varchar(6) NOT NULL,
+	departmentid varchar NOT NULL,
+	hired_at date NULL,
+	status varchar(8) NOT NULL,
+	changed_by varchar(255) NOT NULL,
+	changed_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT employee_versions_pkey PRIMARY KEY (employee_id, version)
+);
@@ -0,0 +1,9 @@
+-- Dipakai purge.Cleaner untuk mencari API key yang sudah lama kedaluwarsa
+-- dan callback integrasi yang sudah diproses tanpa scan seluruh tabel.
+CREATE INDEX IF NOT EXISTS api_key_expires_at_idx
+	ON public.api_key (expires_at)
+	WHERE expires_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS integration_callback_processed_idx
+	ON public.integration_callback (signed_at)
+	WHERE processed_at IS NOT NULL;
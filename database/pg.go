@@ -2,24 +2,149 @@ package database
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/telemetry"
 	"github.com/samber/do/v2"
 )
 
-func NewUserRepositoryInject(i do.Injector) (*pgxpool.Pool, error) {
-	config := config.LoadConfig()
-	databaseURL := config.DatabaseURL
+// NewPool membuat pgxpool dengan pengaturan dari PoolConfig. Koneksi
+// dibuka secara lazy, jadi error di sini hanya berarti konfigurasi salah.
+func NewPool(
+	ctx context.Context,
+	databaseURL string,
+	poolConfig *config.PoolConfig,
+	tracer pgx.QueryTracer,
+) (*pgxpool.Pool, error) {
+	pgxConfig, err := pgxpool.ParseConfig(databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse database config: %w", err)
+	}
 
-	db, err := pgxpool.New(context.Background(), databaseURL)
+	pgxConfig.MaxConns = poolConfig.MaxConns
+	pgxConfig.MinConns = poolConfig.MinConns
+	pgxConfig.MaxConnLifetime = poolConfig.MaxConnLifetime
+	pgxConfig.MaxConnIdleTime = poolConfig.MaxConnIdleTime
+	pgxConfig.HealthCheckPeriod = poolConfig.HealthCheckPeriod
+	pgxConfig.ConnConfig.ConnectTimeout = poolConfig.ConnectTimeout
+	pgxConfig.ConnConfig.Tracer = tracer
+
+	execMode, err := queryExecMode(poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database pool configuration: %w", err)
+	}
+	pgxConfig.ConnConfig.DefaultQueryExecMode = execMode
+	pgxConfig.ConnConfig.StatementCacheCapacity = poolConfig.StatementCacheCapacity
+	pgxConfig.ConnConfig.DescriptionCacheCapacity = poolConfig.DescriptionCacheCapacity
+
+	db, err := pgxpool.NewWithConfig(ctx, pgxConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create database pool: %w", err)
+	}
+
+	log.Printf(
+		"Database pool: max_conns=%d min_conns=%d max_conn_lifetime=%s max_conn_idle_time=%s health_check_period=%s connect_timeout=%s query_exec_mode=%s statement_cache_capacity=%d description_cache_capacity=%d",
+		pgxConfig.MaxConns,
+		pgxConfig.MinConns,
+		pgxConfig.MaxConnLifetime,
+		pgxConfig.MaxConnIdleTime,
+		pgxConfig.HealthCheckPeriod,
+		pgxConfig.ConnConfig.ConnectTimeout,
+		pgxConfig.ConnConfig.DefaultQueryExecMode,
+		pgxConfig.ConnConfig.StatementCacheCapacity,
+		pgxConfig.ConnConfig.DescriptionCacheCapacity,
+	)
+	return db, nil
+}
+
+// queryExecMode menerjemahkan nama mode dari config dan menolak kombinasi
+// mode dengan kapasitas cache yang membuat mode tersebut tidak berguna.
+func queryExecMode(poolConfig *config.PoolConfig) (pgx.QueryExecMode, error) {
+	switch poolConfig.QueryExecMode {
+	case config.QueryExecModeCacheStatement:
+		if poolConfig.StatementCacheCapacity == 0 {
+			return 0, errors.New("DB_QUERY_EXEC_MODE=cache_statement requires DB_STATEMENT_CACHE_CAPACITY greater than 0")
+		}
+		return pgx.QueryExecModeCacheStatement, nil
+	case config.QueryExecModeCacheDescribe:
+		if poolConfig.DescriptionCacheCapacity == 0 {
+			return 0, errors.New("DB_QUERY_EXEC_MODE=cache_describe requires DB_DESCRIPTION_CACHE_CAPACITY greater than 0")
+		}
+		return pgx.QueryExecModeCacheDescribe, nil
+	case config.QueryExecModeDescribeExec:
+		return pgx.QueryExecModeDescribeExec, nil
+	case config.QueryExecModeExec:
+		return pgx.QueryExecModeExec, nil
+	case config.QueryExecModeSimpleProtocol:
+		return pgx.QueryExecModeSimpleProtocol, nil
+	default:
+		return 0, fmt.Errorf("unknown DB_QUERY_EXEC_MODE %q", poolConfig.QueryExecMode)
+	}
+}
+
+// Pool membungkus pgxpool.Pool agar ditutup oleh injector saat shutdown.
+// Injector mematikan service yang memakai pool lebih dulu, sehingga pool
+// baru ditutup setelah semua consumer-nya berhenti.
+type Pool struct {
+	*pgxpool.Pool
+}
+
+// Shutdown menutup semua koneksi pool, lihat do.Shutdowner.
+func (p *Pool) Shutdown() {
+	p.Close()
+}
+
+// HealthCheck memastikan database bisa dijangkau, lihat do.HealthcheckerWithContext.
+func (p *Pool) HealthCheck(ctx context.Context) error {
+	return p.Ping(ctx)
+}
+
+func NewPoolInject(i do.Injector) (*Pool, error) {
+	// Tracer provider harus siap sebelum query pertama dijalankan
+	do.MustInvoke[*telemetry.Tracing](i)
+	appLogger := do.MustInvoke[logger.LogHandler](i)
+	appMetrics := do.MustInvoke[*metrics.Metrics](i)
+
+	poolConfig, err := config.LoadPoolConfig()
 	if err != nil {
-		log.Fatalf("Unable to connect to database: %v", err)
 		return nil, err
 	}
+
+	explain := newExplainTracer(&appLogger)
+	db, err := NewPool(context.Background(), config.LoadConfig().DatabaseURL, poolConfig, newQueryTracer(&appLogger, appMetrics, explain))
+	if err != nil {
+		return nil, err
+	}
+	explain.SetPool(db)
 	log.Println("Connected to the database successfully")
-	return db, nil
+	return &Pool{Pool: db}, nil
+}
+
+func newQueryTracer(appLogger logger.Logger, appMetrics *metrics.Metrics, explain *ExplainTracer) pgx.QueryTracer {
+	tracers := multiQueryTracer{
+		telemetry.NewPgxTracer(),
+		NewSlowQueryTracer(appLogger),
+		NewQueryCountTracer(appLogger),
+		explain,
+	}
+	if traceConfig := config.LoadQueryTraceConfig(); traceConfig.Enabled {
+		tracers = append(tracers, NewQueryLogTracer(appLogger, appMetrics, traceConfig.LogSampleRate))
+	}
+	return tracers
+}
+
+// newExplainTracer membuat ExplainTracer untuk satu pool, pool-nya dipasang
+// dengan SetPool setelah pool dibuat.
+func newExplainTracer(appLogger logger.Logger) *ExplainTracer {
+	traceConfig := config.LoadQueryTraceConfig()
+	return NewExplainTracer(appLogger, traceConfig.ExplainSlowQueries, traceConfig.ExplainMaxBytes)
 }
 
 func Connect(databaseURL string) *pgxpool.Pool {
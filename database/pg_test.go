@@ -0,0 +1,168 @@
+package database_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/samber/do/v2"
+)
+
+// unreachableURL menunjuk port yang tertutup; pgxpool membuka koneksi
+// secara lazy, jadi pool tetap bisa dibuat tanpa database.
+const unreachableURL = "postgres://user@127.0.0.1:1/db?connect_timeout=1"
+
+// closed bernilai true jika pool sudah ditutup: Acquire langsung gagal
+// dengan error closed pool alih-alih mencoba connect.
+func closed(pool *pgxpool.Pool) bool {
+	_, err := pool.Acquire(context.Background())
+	return err != nil && strings.Contains(err.Error(), "closed pool")
+}
+
+// poolConsumer memakai pool seperti outbox dispatcher atau purger, dan
+// mencatat apakah pool masih terbuka saat consumer di-shutdown.
+type poolConsumer struct {
+	pool       *database.Pool
+	openAtStop bool
+	stopped    bool
+}
+
+func (c *poolConsumer) Shutdown() {
+	c.stopped = true
+	c.openAtStop = !closed(c.pool.Pool)
+}
+
+func TestInjectorShutdownClosesPoolAfterConsumers(t *testing.T) {
+	pgxPool, err := pgxpool.New(context.Background(), unreachableURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := &database.Pool{Pool: pgxPool}
+
+	injector := do.New()
+	do.ProvideValue(injector, pool)
+	do.Provide(injector, func(i do.Injector) (*poolConsumer, error) {
+		return &poolConsumer{pool: do.MustInvoke[*database.Pool](i)}, nil
+	})
+	consumer := do.MustInvoke[*poolConsumer](injector)
+	if closed(pgxPool) {
+		t.Fatal("pool is closed before shutdown")
+	}
+
+	if errs := injector.Shutdown(); errs != nil && errs.Len() > 0 {
+		t.Fatalf("Shutdown errors = %v", errs)
+	}
+	if !consumer.stopped || !consumer.openAtStop {
+		t.Fatalf("consumer stopped = %t with pool open = %t, want it stopped before the pool closed", consumer.stopped, consumer.openAtStop)
+	}
+	if !closed(pgxPool) {
+		t.Fatal("pool is still open after injector shutdown")
+	}
+	if err := pool.HealthCheck(context.Background()); err == nil {
+		t.Fatal("HealthCheck on a closed pool succeeded")
+	}
+}
+
+// testPoolConfig mengembalikan PoolConfig valid yang semua nilainya
+// berbeda dari default pgxpool.
+func testPoolConfig() config.PoolConfig {
+	return config.PoolConfig{
+		MaxConns:                 7,
+		MinConns:                 2,
+		MaxConnLifetime:          2 * time.Hour,
+		MaxConnIdleTime:          10 * time.Minute,
+		HealthCheckPeriod:        30 * time.Second,
+		ConnectTimeout:           3 * time.Second,
+		QueryExecMode:            config.QueryExecModeCacheStatement,
+		StatementCacheCapacity:   64,
+		DescriptionCacheCapacity: 32,
+	}
+}
+
+func TestNewPoolAppliesSettings(t *testing.T) {
+	poolConfig := testPoolConfig()
+	pool, err := database.NewPool(context.Background(), unreachableURL, &poolConfig, nil)
+	if err != nil {
+		t.Fatalf("NewPool error = %v", err)
+	}
+	defer pool.Close()
+
+	got := pool.Config()
+	if got.MaxConns != 7 || got.MinConns != 2 {
+		t.Errorf("conns = %d/%d, want 7/2", got.MaxConns, got.MinConns)
+	}
+	if got.MaxConnLifetime != 2*time.Hour || got.MaxConnIdleTime != 10*time.Minute || got.HealthCheckPeriod != 30*time.Second {
+		t.Errorf("lifetime = %s, idle = %s, health check = %s", got.MaxConnLifetime, got.MaxConnIdleTime, got.HealthCheckPeriod)
+	}
+	// connect_timeout=1 di URL ditimpa pengaturan pool
+	if got.ConnConfig.ConnectTimeout != 3*time.Second {
+		t.Errorf("connect timeout = %s, want 3s", got.ConnConfig.ConnectTimeout)
+	}
+}
+
+func TestNewPoolRejectsInvalidURL(t *testing.T) {
+	poolConfig := testPoolConfig()
+	if _, err := database.NewPool(context.Background(), "postgres://%zz", &poolConfig, nil); err == nil || !strings.Contains(err.Error(), "unable to parse database config") {
+		t.Fatalf("NewPool error = %v, want a parse error", err)
+	}
+}
+
+func TestNewPoolQueryExecMode(t *testing.T) {
+	tests := []struct {
+		name             string
+		mode             string
+		statementCache   int
+		descriptionCache int
+		want             pgx.QueryExecMode
+		// wantErr adalah potongan pesan error, kosong berarti NewPool sukses
+		wantErr string
+	}{
+		{name: "cache statement", mode: config.QueryExecModeCacheStatement, statementCache: 64, want: pgx.QueryExecModeCacheStatement},
+		{name: "cache statement without capacity", mode: config.QueryExecModeCacheStatement, descriptionCache: 32, wantErr: "DB_STATEMENT_CACHE_CAPACITY"},
+		{name: "cache describe", mode: config.QueryExecModeCacheDescribe, descriptionCache: 32, want: pgx.QueryExecModeCacheDescribe},
+		{name: "cache describe without capacity", mode: config.QueryExecModeCacheDescribe, statementCache: 64, wantErr: "DB_DESCRIPTION_CACHE_CAPACITY"},
+		// Mode tanpa cache tidak peduli kapasitas cache, termasuk 0 untuk PgBouncer
+		{name: "describe exec", mode: config.QueryExecModeDescribeExec, want: pgx.QueryExecModeDescribeExec},
+		{name: "exec", mode: config.QueryExecModeExec, statementCache: 64, descriptionCache: 32, want: pgx.QueryExecModeExec},
+		{name: "simple protocol", mode: config.QueryExecModeSimpleProtocol, want: pgx.QueryExecModeSimpleProtocol},
+		{name: "unknown", mode: "prepared", statementCache: 64, descriptionCache: 32, wantErr: `unknown DB_QUERY_EXEC_MODE "prepared"`},
+		{name: "empty", statementCache: 64, descriptionCache: 32, wantErr: "unknown DB_QUERY_EXEC_MODE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			poolConfig := testPoolConfig()
+			poolConfig.QueryExecMode = tt.mode
+			poolConfig.StatementCacheCapacity = tt.statementCache
+			poolConfig.DescriptionCacheCapacity = tt.descriptionCache
+
+			pool, err := database.NewPool(context.Background(), unreachableURL, &poolConfig, nil)
+			if tt.wantErr != "" {
+				if err == nil {
+					pool.Close()
+					t.Fatalf("NewPool succeeded, want error containing %q", tt.wantErr)
+				}
+				if !strings.Contains(err.Error(), tt.wantErr) || !strings.Contains(err.Error(), "invalid database pool configuration") {
+					t.Fatalf("NewPool error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewPool error = %v", err)
+			}
+			defer pool.Close()
+
+			got := pool.Config().ConnConfig
+			if got.DefaultQueryExecMode != tt.want {
+				t.Errorf("exec mode = %s, want %s", got.DefaultQueryExecMode, tt.want)
+			}
+			if got.StatementCacheCapacity != tt.statementCache || got.DescriptionCacheCapacity != tt.descriptionCache {
+				t.Errorf("cache capacity = %d/%d, want %d/%d", got.StatementCacheCapacity, got.DescriptionCacheCapacity, tt.statementCache, tt.descriptionCache)
+			}
+		})
+	}
+}
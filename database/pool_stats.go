@@ -0,0 +1,150 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/samber/do/v2"
+)
+
+// PoolStatsCollector membaca pool.Stat() secara berkala dan mengekspornya ke
+// metric, sehingga pool yang kehabisan koneksi terlihat sebagai penyebab
+// latency. Warning dicatat jika jumlah acquire yang harus menunggu koneksi
+// dalam satu window melebihi batas.
+type PoolStatsCollector struct {
+	pools   map[string]*pgxpool.Pool
+	config  *config.PoolStatsConfig
+	metrics *metrics.Metrics
+	logger  logger.Logger
+
+	previous map[string]*pgxpool.Stat
+	// windowStart dan windowEmpty adalah awal window warning dan
+	// EmptyAcquireCount pada saat itu, per pool.
+	windowStart map[string]time.Time
+	windowEmpty map[string]int64
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+func NewPoolStatsCollector(
+	pools map[string]*pgxpool.Pool,
+	config *config.PoolStatsConfig,
+	metrics *metrics.Metrics,
+	logger logger.Logger,
+) *PoolStatsCollector {
+	return &PoolStatsCollector{
+		pools:       pools,
+		config:      config,
+		metrics:     metrics,
+		logger:      logger,
+		previous:    make(map[string]*pgxpool.Stat, len(pools)),
+		windowStart: make(map[string]time.Time, len(pools)),
+		windowEmpty: make(map[string]int64, len(pools)),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// NewPoolStatsCollectorInject langsung menjalankan collector. Collector
+// dihentikan saat injector di-shutdown.
+func NewPoolStatsCollectorInject(i do.Injector) (*PoolStatsCollector, error) {
+	pools := map[string]*pgxpool.Pool{"primary": do.MustInvoke[*Pool](i).Pool}
+	if replica := do.MustInvoke[*Cluster](i).replicaPool; replica != nil {
+		pools["replica"] = replica
+	}
+
+	appLogger := do.MustInvoke[logger.LogHandler](i)
+	collector := NewPoolStatsCollector(pools, config.LoadPoolStatsConfig(), do.MustInvoke[*metrics.Metrics](i), &appLogger)
+	collector.Start()
+	return collector, nil
+}
+
+func (c *PoolStatsCollector) Start() {
+	go c.run()
+}
+
+func (c *PoolStatsCollector) run() {
+	defer close(c.done)
+
+	ticker := time.NewTicker(c.config.Interval)
+	defer ticker.Stop()
+
+	c.Collect(time.Now())
+	for {
+		select {
+		case <-c.stop:
+			return
+		case now := <-ticker.C:
+			c.Collect(now)
+		}
+	}
+}
+
+// Collect membaca statistik semua pool satu kali.
+func (c *PoolStatsCollector) Collect(now time.Time) {
+	for name, pool := range c.pools {
+		stat := pool.Stat()
+
+		c.metrics.PoolAcquiredConns.WithLabelValues(name).Set(float64(stat.AcquiredConns()))
+		c.metrics.PoolIdleConns.WithLabelValues(name).Set(float64(stat.IdleConns()))
+		c.metrics.PoolTotalConns.WithLabelValues(name).Set(float64(stat.TotalConns()))
+
+		// Statistik pgxpool kumulatif, counter dinaikkan sebesar selisihnya
+		var previousEmpty int64
+		var previousDuration time.Duration
+		if previous, ok := c.previous[name]; ok {
+			previousEmpty = previous.EmptyAcquireCount()
+			previousDuration = previous.AcquireDuration()
+		}
+		c.metrics.PoolEmptyAcquires.WithLabelValues(name).Add(float64(stat.EmptyAcquireCount() - previousEmpty))
+		c.metrics.PoolAcquireDuration.WithLabelValues(name).Add((stat.AcquireDuration() - previousDuration).Seconds())
+		c.previous[name] = stat
+
+		c.checkEmptyAcquires(name, stat, now)
+	}
+}
+
+func (c *PoolStatsCollector) checkEmptyAcquires(name string, stat *pgxpool.Stat, now time.Time) {
+	start, ok := c.windowStart[name]
+	if !ok {
+		c.windowStart[name] = now
+		c.windowEmpty[name] = stat.EmptyAcquireCount()
+		return
+	}
+	if now.Sub(start) < c.config.EmptyAcquireWindow {
+		return
+	}
+
+	emptyAcquires := stat.EmptyAcquireCount() - c.windowEmpty[name]
+	if c.config.EmptyAcquireWarnThreshold > 0 && emptyAcquires > int64(c.config.EmptyAcquireWarnThreshold) {
+		c.logger.Warn(
+			fmt.Sprintf(
+				"Pool %s: %d acquires waited for a connection in the last %s (acquired %d, max %d)",
+				name, emptyAcquires, now.Sub(start).Round(time.Second), stat.AcquiredConns(), stat.MaxConns(),
+			),
+			helper.DatabasePoolStats,
+		)
+	}
+	c.windowStart[name] = now
+	c.windowEmpty[name] = stat.EmptyAcquireCount()
+}
+
+// Shutdown menghentikan collector dan menunggu goroutine-nya selesai.
+func (c *PoolStatsCollector) Shutdown(ctx context.Context) error {
+	c.once.Do(func() { close(c.stop) })
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,126 @@
+//go:build integration
+
+package database_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// poolStatsLogger menyimpan warning dari PoolStatsCollector.
+type poolStatsLogger struct {
+	mu    sync.Mutex
+	warns []string
+}
+
+func (l *poolStatsLogger) Info(msg string, function helper.FunctionCaller, data ...interface{})  {}
+func (l *poolStatsLogger) Error(msg string, function helper.FunctionCaller, data ...interface{}) {}
+func (l *poolStatsLogger) Debug(msg string, function helper.FunctionCaller, data ...interface{}) {}
+
+func (l *poolStatsLogger) Warn(msg string, function helper.FunctionCaller, data ...interface{}) {
+	if function != helper.DatabasePoolStats {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.warns = append(l.warns, msg)
+}
+
+func (l *poolStatsLogger) With(fields map[string]any) logger.Logger {
+	return l
+}
+
+func (l *poolStatsLogger) WithContext(ctx context.Context) logger.Logger {
+	return l
+}
+
+// TestPoolStatsTinyPool menjalankan query bersamaan di pool berukuran 1,
+// sehingga hampir semua acquire harus menunggu. Metric harus mengikuti
+// pool.Stat() dan warning empty acquire harus tercatat.
+func TestPoolStatsTinyPool(t *testing.T) {
+	const queries = 10
+	const window = time.Minute
+	shared := dbtest.New(t)
+
+	poolConfig := shared.Config()
+	poolConfig.MaxConns = 1
+	poolConfig.MinConns = 0
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	appMetrics := metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{})
+	log := &poolStatsLogger{}
+	collector := database.NewPoolStatsCollector(
+		map[string]*pgxpool.Pool{"tiny": pool},
+		&config.PoolStatsConfig{Interval: 10 * time.Millisecond, EmptyAcquireWarnThreshold: queries / 2, EmptyAcquireWindow: window},
+		appMetrics,
+		log,
+	)
+
+	// Collect dipanggil langsung dengan waktu buatan agar window warning
+	// tidak bergantung pada jam
+	start := time.Now()
+	collector.Collect(start)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, queries)
+	for range queries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := pool.Exec(context.Background(), "SELECT pg_sleep(0.02);")
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("query error = %v", err)
+		}
+	}
+
+	collector.Collect(start.Add(window))
+	stat := pool.Stat()
+	if stat.EmptyAcquireCount() < queries-1 {
+		t.Fatalf("empty acquires = %d, want at least %d on a pool of one connection", stat.EmptyAcquireCount(), queries-1)
+	}
+	if got := testutil.ToFloat64(appMetrics.PoolEmptyAcquires.WithLabelValues("tiny")); got != float64(stat.EmptyAcquireCount()) {
+		t.Errorf("empty acquires metric = %v, want %d", got, stat.EmptyAcquireCount())
+	}
+	if got := testutil.ToFloat64(appMetrics.PoolAcquireDuration.WithLabelValues("tiny")); got != stat.AcquireDuration().Seconds() || got <= 0 {
+		t.Errorf("acquire duration metric = %v, want %v", got, stat.AcquireDuration().Seconds())
+	}
+	if got := testutil.ToFloat64(appMetrics.PoolTotalConns.WithLabelValues("tiny")); got != 1 {
+		t.Errorf("total conns = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(appMetrics.PoolAcquiredConns.WithLabelValues("tiny")); got != 0 {
+		t.Errorf("acquired conns = %v, want 0 after the load", got)
+	}
+	if len(log.warns) != 1 {
+		t.Fatalf("warnings = %q, want one empty acquire warning", log.warns)
+	}
+
+	// Collector yang dijalankan berhenti bersih saat shutdown
+	collector.Start()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := collector.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown error = %v", err)
+	}
+}
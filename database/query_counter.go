@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+)
+
+type queryCounterKey struct{}
+
+// QueryCounter menghitung query yang dijalankan dengan ctx satu request,
+// untuk menemukan pola N+1. Aman dipakai beberapa goroutine sekaligus.
+type QueryCounter struct {
+	count atomic.Int64
+	// limit adalah batas query per request (0 = tanpa batas). Melewati batas
+	// hanya di-log, query tetap dijalankan.
+	limit int64
+}
+
+// WithQueryCounter memasang counter baru di ctx. Query dari context turunan
+// ctx ikut dihitung, termasuk yang berjalan di dalam transaksi.
+func WithQueryCounter(ctx context.Context, limit int) (context.Context, *QueryCounter) {
+	counter := &QueryCounter{limit: int64(limit)}
+	return context.WithValue(ctx, queryCounterKey{}, counter), counter
+}
+
+// QueryCounterFromContext mengembalikan counter request, atau nil jika ctx
+// tidak berasal dari request HTTP (mis. worker di background).
+func QueryCounterFromContext(ctx context.Context) *QueryCounter {
+	counter, _ := ctx.Value(queryCounterKey{}).(*QueryCounter)
+	return counter
+}
+
+// Count mengembalikan jumlah query sejauh ini.
+func (c *QueryCounter) Count() int {
+	return int(c.count.Load())
+}
+
+// Limit mengembalikan batas query per request, 0 jika tidak dibatasi.
+func (c *QueryCounter) Limit() int {
+	return int(c.limit)
+}
+
+// Exceeded bernilai true jika jumlah query melewati batas.
+func (c *QueryCounter) Exceeded() bool {
+	return c.limit > 0 && c.count.Load() > c.limit
+}
+
+// QueryCountTracer menambah QueryCounter di ctx setiap query dimulai, dan
+// me-log query pertama yang melewati batas beserta namanya, karena query
+// itu biasanya yang dijalankan per baris.
+type QueryCountTracer struct {
+	logger logger.Logger
+}
+
+func NewQueryCountTracer(logger logger.Logger) *QueryCountTracer {
+	return &QueryCountTracer{logger: logger}
+}
+
+func (t *QueryCountTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	counter := QueryCounterFromContext(ctx)
+	if counter == nil {
+		return ctx
+	}
+	if count := counter.count.Add(1); counter.limit > 0 && count == counter.limit+1 {
+		entry := map[string]any{
+			"query": string(queryName(ctx)),
+			"limit": counter.limit,
+		}
+		if requestID, ok := ctx.Value(helper.ContextKeyRequestID).(string); ok {
+			entry["request_id"] = requestID
+		}
+		t.logger.Warn("query limit per request exceeded", helper.QueryCount, entry)
+	}
+	return ctx
+}
+
+func (t *QueryCountTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+}
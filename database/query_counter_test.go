@@ -0,0 +1,120 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+)
+
+// warnLogger menyimpan entry log Warn dari QueryCountTracer.
+type warnLogger struct {
+	mu      sync.Mutex
+	entries []map[string]any
+}
+
+var _ logger.Logger = (*warnLogger)(nil)
+
+func (l *warnLogger) Info(msg string, function helper.FunctionCaller, data ...interface{})  {}
+func (l *warnLogger) Error(msg string, function helper.FunctionCaller, data ...interface{}) {}
+func (l *warnLogger) Debug(msg string, function helper.FunctionCaller, data ...interface{}) {}
+
+func (l *warnLogger) Warn(msg string, function helper.FunctionCaller, data ...interface{}) {
+	if function != helper.QueryCount || len(data) == 0 {
+		return
+	}
+	entry, _ := data[0].(map[string]any)
+	l.mu.Lock()
+	l.entries = append(l.entries, entry)
+	l.mu.Unlock()
+}
+
+func (l *warnLogger) With(fields map[string]any) logger.Logger {
+	return l
+}
+
+func (l *warnLogger) WithContext(ctx context.Context) logger.Logger {
+	return l
+}
+
+func TestQueryCountTracer(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		limit   int
+		queries int
+		// wantWarn berarti satu log Warn untuk query pertama di atas limit
+		wantWarn     bool
+		wantExceeded bool
+	}{
+		{name: "no limit", queries: 50},
+		{name: "under limit", limit: 5, queries: 4},
+		{name: "at limit", limit: 5, queries: 5},
+		{name: "over limit", limit: 5, queries: 6, wantWarn: true, wantExceeded: true},
+		{name: "far over limit", limit: 5, queries: 20, wantWarn: true, wantExceeded: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			log := &warnLogger{}
+			tracer := NewQueryCountTracer(log)
+			ctx, counter := WithQueryCounter(context.Background(), tt.limit)
+			ctx = context.WithValue(ctx, helper.ContextKeyRequestID, "req-1")
+			for i := range tt.queries {
+				name := QueryName("employee.get_all")
+				if i == tt.limit {
+					name = "employee.per_row"
+				}
+				tracer.TraceQueryStart(WithQueryName(ctx, name), nil, pgx.TraceQueryStartData{SQL: "SELECT 1;"})
+			}
+
+			if counter.Count() != tt.queries || counter.Limit() != tt.limit || counter.Exceeded() != tt.wantExceeded {
+				t.Fatalf("Count = %d, Limit = %d, Exceeded = %t", counter.Count(), counter.Limit(), counter.Exceeded())
+			}
+			if !tt.wantWarn {
+				if len(log.entries) != 0 {
+					t.Fatalf("warns = %v, want none", log.entries)
+				}
+				return
+			}
+			// Hanya query pertama yang melewati limit yang di-log
+			if len(log.entries) != 1 {
+				t.Fatalf("warns = %v, want one", log.entries)
+			}
+			entry := log.entries[0]
+			if entry["query"] != "employee.per_row" || entry["limit"] != int64(tt.limit) || entry["request_id"] != "req-1" {
+				t.Fatalf("warn = %v", entry)
+			}
+		})
+	}
+}
+
+// TestQueryCountTracerWithoutCounter memastikan query di luar request,
+// mis. dari worker, tidak dihitung dan tidak di-log.
+func TestQueryCountTracerWithoutCounter(t *testing.T) {
+	log := &warnLogger{}
+	tracer := NewQueryCountTracer(log)
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "SELECT 1;"})
+	if QueryCounterFromContext(ctx) != nil || len(log.entries) != 0 {
+		t.Fatalf("counter = %v, warns = %v", QueryCounterFromContext(ctx), log.entries)
+	}
+}
+
+// TestQueryCounterConcurrent memastikan query dari beberapa goroutine dalam
+// satu request dihitung semua.
+func TestQueryCounterConcurrent(t *testing.T) {
+	tracer := NewQueryCountTracer(&warnLogger{})
+	ctx, counter := WithQueryCounter(context.Background(), 0)
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT 1;"})
+		}()
+	}
+	wg.Wait()
+	if counter.Count() != 50 {
+		t.Fatalf("Count = %d, want 50", counter.Count())
+	}
+}
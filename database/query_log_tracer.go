@@ -0,0 +1,93 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/metrics"
+)
+
+type queryLogStartKey struct{}
+
+type queryLogStart struct {
+	at   time.Time
+	name QueryName
+	sql  string
+}
+
+// QueryLogTracer mencatat durasi setiap query ke histogram per nama query,
+// menghitung error per SQLSTATE, dan me-log sebagian query. Parameter query
+// tidak pernah ikut di-log.
+type QueryLogTracer struct {
+	logger     logger.Logger
+	metrics    *metrics.Metrics
+	sampleRate float64
+}
+
+func NewQueryLogTracer(logger logger.Logger, metrics *metrics.Metrics, sampleRate float64) *QueryLogTracer {
+	return &QueryLogTracer{logger: logger, metrics: metrics, sampleRate: sampleRate}
+}
+
+func (t *QueryLogTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	name, ok := QueryNameFromContext(ctx)
+	if !ok {
+		name = UnnamedQuery
+	}
+	return context.WithValue(ctx, queryLogStartKey{}, queryLogStart{at: time.Now(), name: name, sql: data.SQL})
+}
+
+func (t *QueryLogTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(queryLogStartKey{}).(queryLogStart)
+	if !ok {
+		return
+	}
+
+	duration := time.Since(start.at)
+	t.metrics.QueryDuration.WithLabelValues(string(start.name)).Observe(duration.Seconds())
+	if data.Err != nil && !errors.Is(data.Err, pgx.ErrNoRows) {
+		t.metrics.QueryErrors.WithLabelValues(string(start.name), sqlState(data.Err)).Inc()
+	}
+
+	if data.Err == nil && rand.Float64() >= t.sampleRate {
+		return
+	}
+
+	entry := map[string]any{
+		"query":       string(start.name),
+		"duration_ms": duration.Milliseconds(),
+	}
+	// SQL mentah hanya di-log untuk query yang belum punya nama
+	if start.name == UnnamedQuery {
+		entry["sql"] = compactSQL(start.sql)
+	}
+	if data.Err != nil {
+		entry["error"] = data.Err.Error()
+		entry["sqlstate"] = sqlState(data.Err)
+		t.logger.Warn("query failed", helper.QueryLog, entry)
+		return
+	}
+	entry["rows_affected"] = data.CommandTag.RowsAffected()
+	t.logger.Debug("query", helper.QueryLog, entry)
+}
+
+// sqlState mengembalikan kode SQLSTATE, atau kategori singkat untuk error
+// yang tidak berasal dari server.
+func sqlState(err error) string {
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.As(err, &pgErr):
+		return pgErr.Code
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	default:
+		return "connection"
+	}
+}
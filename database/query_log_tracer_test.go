@@ -0,0 +1,166 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	clientmodel "github.com/prometheus/client_model/go"
+)
+
+// queryLogEntry adalah satu log dari QueryLogTracer.
+type queryLogEntry struct {
+	level string
+	entry map[string]any
+}
+
+// queryLogLogger menyimpan log Debug dan Warn dari QueryLogTracer.
+type queryLogLogger struct {
+	entries []queryLogEntry
+}
+
+var _ logger.Logger = (*queryLogLogger)(nil)
+
+func (l *queryLogLogger) Info(msg string, function helper.FunctionCaller, data ...interface{})  {}
+func (l *queryLogLogger) Error(msg string, function helper.FunctionCaller, data ...interface{}) {}
+
+func (l *queryLogLogger) Debug(msg string, function helper.FunctionCaller, data ...interface{}) {
+	l.record("debug", function, data)
+}
+
+func (l *queryLogLogger) Warn(msg string, function helper.FunctionCaller, data ...interface{}) {
+	l.record("warn", function, data)
+}
+
+func (l *queryLogLogger) record(level string, function helper.FunctionCaller, data []interface{}) {
+	if function != helper.QueryLog || len(data) == 0 {
+		return
+	}
+	entry, _ := data[0].(map[string]any)
+	l.entries = append(l.entries, queryLogEntry{level: level, entry: entry})
+}
+
+func (l *queryLogLogger) With(fields map[string]any) logger.Logger {
+	return l
+}
+
+func (l *queryLogLogger) WithContext(ctx context.Context) logger.Logger {
+	return l
+}
+
+// traceQuery menjalankan satu query lewat tracer dengan nama dan error
+// tertentu. Nama kosong berarti query tanpa nama.
+func traceQuery(tracer *QueryLogTracer, name QueryName, queryErr error) {
+	ctx := context.Background()
+	if name != "" {
+		ctx = WithQueryName(ctx, name)
+	}
+	ctx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{
+		SQL:  "SELECT *\n\t\tFROM employees\n\t\tWHERE identityNumber = $1;",
+		Args: []any{"secret-identity"},
+	})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 1"), Err: queryErr})
+}
+
+func querySamples(t *testing.T, m *metrics.Metrics, name QueryName) uint64 {
+	t.Helper()
+	var metric clientmodel.Metric
+	if err := m.QueryDuration.WithLabelValues(string(name)).(prometheus.Metric).Write(&metric); err != nil {
+		t.Fatal(err)
+	}
+	return metric.GetHistogram().GetSampleCount()
+}
+
+func TestQueryLogTracer(t *testing.T) {
+	tests := []struct {
+		name       string
+		queryName  QueryName
+		sampleRate float64
+		err        error
+		wantLevel  string
+		wantState  string
+	}{
+		{name: "sampled", queryName: "employee.get_all", sampleRate: 1, wantLevel: "debug"},
+		{name: "not sampled", queryName: "employee.get_all", sampleRate: 0},
+		{name: "unnamed", sampleRate: 1, wantLevel: "debug"},
+		// Error selalu di-log walau tidak masuk sampel
+		{name: "unique violation", queryName: "employee.create", err: &pgconn.PgError{Code: "23505"}, wantLevel: "warn", wantState: "23505"},
+		{name: "timeout", queryName: "employee.get_all", err: context.DeadlineExceeded, wantLevel: "warn", wantState: "timeout"},
+		{name: "canceled", queryName: "employee.get_all", err: context.Canceled, wantLevel: "warn", wantState: "canceled"},
+		{name: "connection", queryName: "employee.get_all", err: errors.New("unexpected EOF"), wantLevel: "warn", wantState: "connection"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{})
+			log := &queryLogLogger{}
+			traceQuery(NewQueryLogTracer(log, m, tt.sampleRate), tt.queryName, tt.err)
+
+			wantName := tt.queryName
+			if wantName == "" {
+				wantName = UnnamedQuery
+			}
+			if got := querySamples(t, m, wantName); got != 1 {
+				t.Errorf("duration samples for %s = %d, want 1", wantName, got)
+			}
+			if tt.wantState == "" {
+				if count := testutil.CollectAndCount(m.QueryErrors); count != 0 {
+					t.Errorf("query errors has %d series, want 0", count)
+				}
+			} else if got := testutil.ToFloat64(m.QueryErrors.WithLabelValues(string(wantName), tt.wantState)); got != 1 {
+				t.Errorf("query errors %s/%s = %v, want 1", wantName, tt.wantState, got)
+			}
+
+			if tt.wantLevel == "" {
+				if len(log.entries) != 0 {
+					t.Fatalf("entries = %+v, want none", log.entries)
+				}
+				return
+			}
+			if len(log.entries) != 1 || log.entries[0].level != tt.wantLevel {
+				t.Fatalf("entries = %+v, want one %s entry", log.entries, tt.wantLevel)
+			}
+			entry := log.entries[0].entry
+			if entry["query"] != string(wantName) {
+				t.Errorf("query = %v, want %s", entry["query"], wantName)
+			}
+			// SQL mentah hanya untuk query tanpa nama, parameter tidak pernah
+			sql, hasSQL := entry["sql"].(string)
+			if hasSQL != (wantName == UnnamedQuery) {
+				t.Errorf("sql = %q, want it only for unnamed queries", sql)
+			}
+			for key, value := range entry {
+				if s, ok := value.(string); ok && strings.Contains(s, "secret-identity") {
+					t.Errorf("%s = %q leaks a query parameter", key, s)
+				}
+			}
+		})
+	}
+}
+
+// TestQueryLogTracerSampling memastikan kira-kira sampleRate query sukses
+// yang di-log.
+func TestQueryLogTracerSampling(t *testing.T) {
+	const queries = 2000
+	m := metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{})
+	log := &queryLogLogger{}
+	tracer := NewQueryLogTracer(log, m, 0.25)
+	for range queries {
+		traceQuery(tracer, "employee.get_all", nil)
+	}
+
+	if got := querySamples(t, m, "employee.get_all"); got != queries {
+		t.Fatalf("duration samples = %d, want every query recorded", got)
+	}
+	if logged := len(log.entries); logged < queries/8 || logged > queries*3/8 {
+		t.Fatalf("logged %d of %d queries, want about 25%%", logged, queries)
+	}
+}
@@ -0,0 +1,22 @@
+package database
+
+import "context"
+
+// QueryName adalah nama stabil sebuah statement repository, dipakai di log
+// dan label metric sebagai pengganti SQL mentah.
+type QueryName string
+
+// UnnamedQuery dipakai untuk statement yang belum diberi nama.
+const UnnamedQuery QueryName = "unnamed"
+
+type queryNameKey struct{}
+
+// WithQueryName menandai query yang dijalankan dengan ctx ini.
+func WithQueryName(ctx context.Context, name QueryName) context.Context {
+	return context.WithValue(ctx, queryNameKey{}, name)
+}
+
+func QueryNameFromContext(ctx context.Context) (QueryName, bool) {
+	name, ok := ctx.Value(queryNameKey{}).(QueryName)
+	return name, ok
+}
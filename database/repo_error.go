@@ -0,0 +1,46 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+// RepoError menandai error yang keluar dari repository dengan nama query
+// yang gagal, sehingga log berisi mis. "employee.get_all: query timeout"
+// alih-alih hanya pesan pgx. errors.Is/As tetap melihat error aslinya,
+// sehingga pemetaan status HTTP tidak berubah.
+type RepoError struct {
+	Query QueryName
+	Err   error
+}
+
+func (e *RepoError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Query, e.Err)
+}
+
+func (e *RepoError) Unwrap() error {
+	return e.Err
+}
+
+// QueryError memetakan err seperti helper.QueryError lalu membungkusnya
+// dengan nama query dari ctx, lihat WithQueryName. Error yang sudah
+// dibungkus tidak dibungkus lagi.
+func QueryError(ctx context.Context, err error) error {
+	err = helper.QueryError(ctx, err)
+	if err == nil {
+		return nil
+	}
+
+	var repoErr *RepoError
+	if errors.As(err, &repoErr) {
+		return err
+	}
+	name, ok := QueryNameFromContext(ctx)
+	if !ok {
+		name = UnnamedQuery
+	}
+	return &RepoError{Query: name, Err: err}
+}
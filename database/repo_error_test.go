@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+func TestQueryErrorAddsQueryName(t *testing.T) {
+	ctx := WithQueryName(context.Background(), "employee.get_all")
+	tests := []struct {
+		name        string
+		err         error
+		wantMessage string
+		wantIs      error
+		wantStatus  int
+	}{
+		{name: "sentinel", err: helper.ErrNotFound, wantMessage: "employee.get_all: record not found", wantIs: helper.ErrNotFound, wantStatus: http.StatusNotFound},
+		{name: "wrapped sentinel", err: fmt.Errorf("%w: EMP-1", helper.ErrConflictIdentityNumber), wantMessage: "employee.get_all: identity number conflict: EMP-1", wantIs: helper.ErrConflictIdentityNumber, wantStatus: http.StatusConflict},
+		{name: "pgx error", err: pgx.ErrNoRows, wantMessage: "employee.get_all: no rows in result set", wantIs: pgx.ErrNoRows, wantStatus: http.StatusInternalServerError},
+		{name: "cancelled", err: context.Canceled, wantMessage: "employee.get_all: context canceled", wantIs: context.Canceled, wantStatus: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := QueryError(ctx, tt.err)
+			if err.Error() != tt.wantMessage {
+				t.Errorf("Error() = %q, want %q", err.Error(), tt.wantMessage)
+			}
+			if !errors.Is(err, tt.wantIs) {
+				t.Errorf("errors.Is(%v, %v) = false", err, tt.wantIs)
+			}
+			var repoErr *RepoError
+			if !errors.As(err, &repoErr) || repoErr.Query != "employee.get_all" {
+				t.Errorf("errors.As RepoError = %+v", repoErr)
+			}
+			if got := helper.GetErrorStatusCode(err); got != tt.wantStatus {
+				t.Errorf("GetErrorStatusCode = %d, want %d", got, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestQueryErrorNil(t *testing.T) {
+	if err := QueryError(WithQueryName(context.Background(), "employee.get_all"), nil); err != nil {
+		t.Fatalf("QueryError(nil) = %v, want nil", err)
+	}
+}
+
+func TestQueryErrorWithoutName(t *testing.T) {
+	err := QueryError(context.Background(), helper.ErrNotFound)
+	if want := "unnamed: record not found"; err.Error() != want {
+		t.Fatalf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestQueryErrorWrapsOnce(t *testing.T) {
+	inner := QueryError(WithQueryName(context.Background(), "department.delete_find"), helper.ErrNotFound)
+	// Error dari query lain di dalam repository yang sama tetap memakai
+	// nama query yang gagal
+	err := QueryError(WithQueryName(context.Background(), "department.delete"), fmt.Errorf("delete: %w", inner))
+	if want := "delete: department.delete_find: record not found"; err.Error() != want {
+		t.Fatalf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestQueryErrorDeadlineIsQueryTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(WithQueryName(context.Background(), "employee.count"), 0)
+	defer cancel()
+	<-ctx.Done()
+
+	err := QueryError(ctx, errors.New("scan failed"))
+	if !errors.Is(err, helper.ErrQueryTimeout) || err.Error() != "employee.count: query timeout" {
+		t.Fatalf("QueryError = %v, want employee.count: query timeout", err)
+	}
+	if got := helper.GetErrorStatusCode(err); got != http.StatusGatewayTimeout {
+		t.Fatalf("GetErrorStatusCode = %d, want 504", got)
+	}
+}
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+)
+
+type slowQueryStartKey struct{}
+
+type slowQueryStart struct {
+	at  time.Time
+	sql string
+}
+
+// SlowQueryTracer mencatat statement yang lebih lama dari
+// logger.SlowQueryThreshold. Parameter query tidak pernah ikut di-log.
+type SlowQueryTracer struct {
+	logger logger.Logger
+}
+
+func NewSlowQueryTracer(logger logger.Logger) *SlowQueryTracer {
+	return &SlowQueryTracer{logger: logger}
+}
+
+func (t *SlowQueryTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, slowQueryStartKey{}, slowQueryStart{at: time.Now(), sql: data.SQL})
+}
+
+func (t *SlowQueryTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(slowQueryStartKey{}).(slowQueryStart)
+	if !ok {
+		return
+	}
+
+	duration := time.Since(start.at)
+	threshold := logger.SlowQueryThreshold()
+	if duration <= threshold {
+		return
+	}
+
+	entry := map[string]any{
+		"query":        string(queryName(ctx)),
+		"sql":          compactSQL(start.sql),
+		"duration_ms":  duration.Milliseconds(),
+		"threshold_ms": threshold.Milliseconds(),
+	}
+	if data.Err != nil {
+		entry["error"] = data.Err.Error()
+	}
+	t.logger.Warn("slow query", helper.SlowQuery, entry)
+}
+
+func queryName(ctx context.Context) QueryName {
+	if name, ok := QueryNameFromContext(ctx); ok {
+		return name
+	}
+	return UnnamedQuery
+}
+
+// compactSQL merapikan whitespace agar SQL multi-baris enak dibaca di log.
+func compactSQL(sql string) string {
+	return strings.Join(strings.Fields(sql), " ")
+}
@@ -0,0 +1,96 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+)
+
+// slowQueryLogger menyimpan entry Warn dari SlowQueryTracer.
+type slowQueryLogger struct {
+	entries []map[string]any
+}
+
+var _ logger.Logger = (*slowQueryLogger)(nil)
+
+func (l *slowQueryLogger) Info(msg string, function helper.FunctionCaller, data ...interface{})  {}
+func (l *slowQueryLogger) Error(msg string, function helper.FunctionCaller, data ...interface{}) {}
+func (l *slowQueryLogger) Debug(msg string, function helper.FunctionCaller, data ...interface{}) {}
+
+func (l *slowQueryLogger) Warn(msg string, function helper.FunctionCaller, data ...interface{}) {
+	if function != helper.SlowQuery || len(data) == 0 {
+		return
+	}
+	entry, _ := data[0].(map[string]any)
+	l.entries = append(l.entries, entry)
+}
+
+func (l *slowQueryLogger) With(fields map[string]any) logger.Logger {
+	return l
+}
+
+func (l *slowQueryLogger) WithContext(ctx context.Context) logger.Logger {
+	return l
+}
+
+func TestSlowQueryTracer(t *testing.T) {
+	previous := logger.SlowQueryThreshold()
+	t.Cleanup(func() { logger.SetSlowQueryThreshold(previous) })
+	logger.SetSlowQueryThreshold(10 * time.Millisecond)
+
+	tests := []struct {
+		name    string
+		delay   time.Duration
+		err     error
+		wantLog bool
+	}{
+		{name: "fast", delay: 0, wantLog: false},
+		{name: "slow", delay: 30 * time.Millisecond, wantLog: true},
+		{name: "slow failure", delay: 30 * time.Millisecond, err: errors.New("canceling statement due to statement timeout"), wantLog: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := &slowQueryLogger{}
+			tracer := NewSlowQueryTracer(log)
+			ctx := WithQueryName(context.Background(), "employee.get_all")
+			ctx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{
+				SQL:  "SELECT *\n\t\tFROM employees\n\t\tWHERE name ILIKE $1;",
+				Args: []any{"secret-name"},
+			})
+			time.Sleep(tt.delay)
+			tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: tt.err})
+
+			if !tt.wantLog {
+				if len(log.entries) != 0 {
+					t.Fatalf("entries = %v, want none", log.entries)
+				}
+				return
+			}
+			if len(log.entries) != 1 {
+				t.Fatalf("logged %d entries, want 1", len(log.entries))
+			}
+			entry := log.entries[0]
+			if entry["query"] != "employee.get_all" || entry["sql"] != "SELECT * FROM employees WHERE name ILIKE $1;" || entry["threshold_ms"] != int64(10) {
+				t.Fatalf("entry = %v", entry)
+			}
+			if duration, _ := entry["duration_ms"].(int64); duration < 30 {
+				t.Fatalf("duration_ms = %v, want at least 30", entry["duration_ms"])
+			}
+			if _, ok := entry["error"]; ok != (tt.err != nil) {
+				t.Fatalf("error = %v, want it only for failed queries", entry["error"])
+			}
+			// Parameter tidak pernah ikut di-log
+			for key, value := range entry {
+				if s, ok := value.(string); ok && strings.Contains(s, "secret-name") {
+					t.Fatalf("entry %s leaks a parameter: %q", key, s)
+				}
+			}
+		})
+	}
+}
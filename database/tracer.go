@@ -0,0 +1,25 @@
+package database
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// multiQueryTracer meneruskan event query ke beberapa tracer sekaligus,
+// karena pgx hanya menerima satu Tracer per koneksi.
+type multiQueryTracer []pgx.QueryTracer
+
+func (m multiQueryTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	for _, tracer := range m {
+		ctx = tracer.TraceQueryStart(ctx, conn, data)
+	}
+	return ctx
+}
+
+func (m multiQueryTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	// Urutan dibalik agar span/timer yang dibuka pertama ditutup terakhir
+	for i := len(m) - 1; i >= 0; i-- {
+		m[i].TraceQueryEnd(ctx, conn, data)
+	}
+}
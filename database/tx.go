@@ -0,0 +1,67 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+// sqlStateSerializationFailure dikembalikan Postgres jika transaksi
+// serializable bentrok dengan transaksi lain dan harus diulang.
+const sqlStateSerializationFailure = "40001"
+
+// WithTx menjalankan fn dalam satu transaksi dengan opsi tertentu (isolation
+// level, read-only, deferrable). Transaksi di-commit jika fn mengembalikan
+// nil, selain itu di-rollback, termasuk saat fn panic.
+//
+// Untuk pgx.Serializable, transaksi yang gagal karena serialization failure
+// (SQLSTATE 40001) diulang satu kali dari awal, sehingga fn harus aman
+// dijalankan ulang.
+func WithTx(ctx context.Context, db DB, txOptions pgx.TxOptions, fn func(tx pgx.Tx) error) error {
+	err := runTx(ctx, db, txOptions, fn)
+	if txOptions.IsoLevel == pgx.Serializable && isSerializationFailure(err) {
+		err = runTx(ctx, db, txOptions, fn)
+	}
+	return err
+}
+
+// WithReadOnlyTx menjalankan beberapa query baca dalam satu snapshot, mis.
+// daftar dan total yang harus konsisten satu sama lain.
+func WithReadOnlyTx(ctx context.Context, db DB, fn func(tx pgx.Tx) error) error {
+	return WithTx(ctx, db, pgx.TxOptions{
+		IsoLevel:   pgx.RepeatableRead,
+		AccessMode: pgx.ReadOnly,
+	}, fn)
+}
+
+func runTx(ctx context.Context, db DB, txOptions pgx.TxOptions, fn func(tx pgx.Tx) error) (err error) {
+	tx, err := db.BeginTx(ctx, txOptions)
+	if err != nil {
+		// Termasuk pool yang kehabisan koneksi, lihat helper.QueryError
+		return helper.QueryError(ctx, err)
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			_ = tx.Rollback(context.WithoutCancel(ctx))
+			panic(recovered)
+		}
+		if err != nil {
+			// Rollback tetap dijalankan walau context request sudah habis
+			_ = tx.Rollback(context.WithoutCancel(ctx))
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return helper.QueryError(ctx, tx.Commit(ctx))
+}
+
+func isSerializationFailure(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == sqlStateSerializationFailure
+}
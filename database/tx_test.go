@@ -0,0 +1,148 @@
+//go:build integration
+
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+)
+
+// newTxTable membuat tabel kosong untuk test transaksi.
+func newTxTable(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	pool := dbtest.New(t)
+	if _, err := pool.Exec(context.Background(), "CREATE TABLE tx_shift (id serial PRIMARY KEY, on_call int NOT NULL);"); err != nil {
+		t.Fatal(err)
+	}
+	return pool
+}
+
+func countRows(t *testing.T, pool *pgxpool.Pool) int {
+	t.Helper()
+	var rows int
+	if err := pool.QueryRow(context.Background(), "SELECT COUNT(*) FROM tx_shift;").Scan(&rows); err != nil {
+		t.Fatal(err)
+	}
+	return rows
+}
+
+// insertIfFree adalah write skew klasik: baca jumlah baris lalu insert
+// berdasarkan hasilnya. Dua transaksi serializable yang menjalankannya
+// bersamaan tidak bisa sama-sama commit.
+func insertIfFree(ctx context.Context, tx pgx.Tx) error {
+	var onCall int
+	if err := tx.QueryRow(ctx, "SELECT COALESCE(SUM(on_call), 0) FROM tx_shift;").Scan(&onCall); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx, "INSERT INTO tx_shift (on_call) VALUES ($1);", onCall+1)
+	return err
+}
+
+// TestWithTxRetriesSerializationFailure menjalankan transaksi pesaing di
+// tengah percobaan pertama. Percobaan pertama gagal dengan 40001 dan
+// diulang satu kali, yang kemudian berhasil.
+func TestWithTxRetriesSerializationFailure(t *testing.T) {
+	pool := newTxTable(t)
+	ctx := context.Background()
+	serializable := pgx.TxOptions{IsoLevel: pgx.Serializable}
+
+	attempts := 0
+	err := database.WithTx(ctx, pool, serializable, func(tx pgx.Tx) error {
+		attempts++
+		var onCall int
+		if err := tx.QueryRow(ctx, "SELECT COALESCE(SUM(on_call), 0) FROM tx_shift;").Scan(&onCall); err != nil {
+			return err
+		}
+		if attempts == 1 {
+			// Transaksi pesaing di koneksi lain membaca dan menulis lalu
+			// commit lebih dulu
+			competing := database.WithTx(ctx, pool, serializable, func(tx pgx.Tx) error {
+				return insertIfFree(ctx, tx)
+			})
+			if competing != nil {
+				t.Errorf("competing transaction error = %v", competing)
+			}
+		}
+		_, err := tx.Exec(ctx, "INSERT INTO tx_shift (on_call) VALUES ($1);", onCall+1)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("WithTx error = %v, want the retry to succeed", err)
+	}
+	if attempts != 2 {
+		t.Fatalf("attempts = %d, want 2", attempts)
+	}
+	if rows := countRows(t, pool); rows != 2 {
+		t.Fatalf("rows = %d, want the competing and the retried insert", rows)
+	}
+}
+
+func TestWithTxRetriesOnlySerializableOnce(t *testing.T) {
+	pool := newTxTable(t)
+	ctx := context.Background()
+	serializationFailure := &pgconn.PgError{Code: "40001"}
+
+	tests := []struct {
+		name         string
+		isoLevel     pgx.TxIsoLevel
+		wantAttempts int
+	}{
+		{name: "serializable", isoLevel: pgx.Serializable, wantAttempts: 2},
+		{name: "repeatable read", isoLevel: pgx.RepeatableRead, wantAttempts: 1},
+		{name: "read committed", isoLevel: pgx.ReadCommitted, wantAttempts: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			err := database.WithTx(ctx, pool, pgx.TxOptions{IsoLevel: tt.isoLevel}, func(tx pgx.Tx) error {
+				attempts++
+				if err := insertIfFree(ctx, tx); err != nil {
+					return err
+				}
+				return serializationFailure
+			})
+			if !errors.Is(err, serializationFailure) {
+				t.Fatalf("WithTx error = %v, want the serialization failure", err)
+			}
+			if attempts != tt.wantAttempts {
+				t.Fatalf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+			if rows := countRows(t, pool); rows != 0 {
+				t.Fatalf("rows = %d, want every attempt rolled back", rows)
+			}
+		})
+	}
+}
+
+func TestWithReadOnlyTxRejectsWrites(t *testing.T) {
+	pool := newTxTable(t)
+	ctx := context.Background()
+
+	err := database.WithReadOnlyTx(ctx, pool, func(tx pgx.Tx) error {
+		return insertIfFree(ctx, tx)
+	})
+	var pgErr *pgconn.PgError
+	// 25006 read_only_sql_transaction
+	if !errors.As(err, &pgErr) || pgErr.Code != "25006" {
+		t.Fatalf("WithReadOnlyTx error = %v, want SQLSTATE 25006", err)
+	}
+	if rows := countRows(t, pool); rows != 0 {
+		t.Fatalf("rows = %d, want nothing written", rows)
+	}
+
+	// Baca tetap bisa
+	err = database.WithReadOnlyTx(ctx, pool, func(tx pgx.Tx) error {
+		var rows int
+		return tx.QueryRow(ctx, "SELECT COUNT(*) FROM tx_shift;").Scan(&rows)
+	})
+	if err != nil {
+		t.Fatalf("WithReadOnlyTx read error = %v", err)
+	}
+}
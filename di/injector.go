@@ -1,26 +1,69 @@
 package di
 
 import (
-	"os"
-
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/auth"
+	"github.com/levensspel/go-gin-template/breaker"
+	"github.com/levensspel/go-gin-template/cache"
+	"github.com/levensspel/go-gin-template/clock"
+	"github.com/levensspel/go-gin-template/config"
 	"github.com/levensspel/go-gin-template/database"
 	"github.com/levensspel/go-gin-template/domain"
+	"github.com/levensspel/go-gin-template/eventbus"
+	"github.com/levensspel/go-gin-template/featureflag"
+	"github.com/levensspel/go-gin-template/graph"
+	adminHandler "github.com/levensspel/go-gin-template/handler/admin"
+	apiKeyHandler "github.com/levensspel/go-gin-template/handler/apikey"
+	auditHandler "github.com/levensspel/go-gin-template/handler/audit"
 	authHandler "github.com/levensspel/go-gin-template/handler/auth"
 	departmentHandler "github.com/levensspel/go-gin-template/handler/department"
 	employeeHandler "github.com/levensspel/go-gin-template/handler/employee"
+	fileHandler "github.com/levensspel/go-gin-template/handler/file"
+	graphqlHandler "github.com/levensspel/go-gin-template/handler/graphql"
+	healthHandler "github.com/levensspel/go-gin-template/handler/health"
+	integrationHandler "github.com/levensspel/go-gin-template/handler/integration"
+	streamHandler "github.com/levensspel/go-gin-template/handler/stream"
 	userHandler "github.com/levensspel/go-gin-template/handler/user"
+	webhookHandler "github.com/levensspel/go-gin-template/handler/webhook"
+	websocketHandler "github.com/levensspel/go-gin-template/handler/websocket"
+	"github.com/levensspel/go-gin-template/health"
+	"github.com/levensspel/go-gin-template/idgen"
+	"github.com/levensspel/go-gin-template/infrastructure"
 	"github.com/levensspel/go-gin-template/infrastructure/storage"
+	"github.com/levensspel/go-gin-template/lifecycle"
 	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/metrics"
+	adminService "github.com/levensspel/go-gin-template/service/admin"
+	apiKeyService "github.com/levensspel/go-gin-template/service/apikey"
+	auditService "github.com/levensspel/go-gin-template/service/audit"
 	departmentService "github.com/levensspel/go-gin-template/service/department"
 	user_service "github.com/levensspel/go-gin-template/service/employee"
+	fileService "github.com/levensspel/go-gin-template/service/file"
+	integrationService "github.com/levensspel/go-gin-template/service/integration"
 	userService "github.com/levensspel/go-gin-template/service/user"
+	webhookService "github.com/levensspel/go-gin-template/service/webhook"
 
+	"github.com/levensspel/go-gin-template/outbox"
+	"github.com/levensspel/go-gin-template/purge"
+	"github.com/levensspel/go-gin-template/realtime"
+	"github.com/levensspel/go-gin-template/repository"
+	apiKeyRepository "github.com/levensspel/go-gin-template/repository/apikey"
+	auditRepository "github.com/levensspel/go-gin-template/repository/audit"
 	departmentRepository "github.com/levensspel/go-gin-template/repository/department"
 	repositories "github.com/levensspel/go-gin-template/repository/employee"
+	featureFlagRepository "github.com/levensspel/go-gin-template/repository/featureflag"
+	fileRepository "github.com/levensspel/go-gin-template/repository/file"
+	integrationRepository "github.com/levensspel/go-gin-template/repository/integration"
 	userRepository "github.com/levensspel/go-gin-template/repository/user"
+	webhookRepository "github.com/levensspel/go-gin-template/repository/webhook"
 
+	"github.com/levensspel/go-gin-template/reporting"
+	"github.com/levensspel/go-gin-template/telemetry"
+	"github.com/levensspel/go-gin-template/thumbnail"
+	"github.com/levensspel/go-gin-template/validation"
+	"github.com/levensspel/go-gin-template/webhook"
+	"github.com/levensspel/go-gin-template/worker"
 	"github.com/samber/do/v2"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var Injector *do.RootScope
@@ -32,33 +75,108 @@ func init() {
 
 	// Setup dependensi-depenensi dasar sebuah service
 
+	// Context server, dibatalkan saat shutdown sebelum dependensi ditutup
+	do.Provide[*lifecycle.Lifetime](Injector, lifecycle.NewLifetimeInject)
+	// Sumber waktu, diganti clocktest.Fake di test
+	do.Provide[clock.Clock](Injector, clock.NewInject)
+	// Generator id (UUIDv7) untuk department dan file
+	do.Provide[idgen.IDGenerator](Injector, idgen.NewInject)
+	// Validator bersama untuk tag validate di DTO
+	do.Provide[*validation.Validator](Injector, validation.NewInject)
+	// Setup tracing (no-op jika env OTEL_* tidak diset)
+	do.Provide[*telemetry.Tracing](Injector, telemetry.NewTracingInject)
+	// Tracer untuk span di service, no-op jika tracing tidak aktif
+	do.Provide[trace.Tracer](Injector, telemetry.NewTracerInject)
 	// Setup database connection
-	do.Provide[*pgxpool.Pool](Injector, database.NewUserRepositoryInject)
+	do.Provide[*database.Pool](Injector, database.NewPoolInject)
+	// Setup redis connection (nil jika REDIS_URL tidak diset)
+	do.Provide[*infrastructure.RedisClient](Injector, infrastructure.NewRedisClientInject)
+	// Setup readiness state, dikendalikan oleh lifecycle server
+	do.Provide[*health.Readiness](Injector, health.NewReadinessInject)
+	// Setup error reporting (Sentry jika SENTRY_DSN diset)
+	do.Provide[reporting.Reporter](Injector, reporting.NewReporterInject)
+	// Level log aktif, bisa diubah admin saat runtime
+	do.Provide[*logger.Level](Injector, logger.NewLevelInject)
 	// setup logger
 	do.Provide[logger.LogHandler](Injector, logger.NewlogHandlerInject)
 
+	// Setup metrics registry
+	do.Provide[*metrics.Metrics](Injector, metrics.NewInject)
+	// Setup circuit breaker database, dipakai repository lewat database.DB
+	do.Provide[*breaker.Breaker](Injector, database.NewBreakerInject)
+	do.Provide[database.DB](Injector, database.NewGuardedPoolInject)
+	// Setup read replica (opsional), fallback ke primary jika tidak diset
+	do.Provide[*database.Cluster](Injector, database.NewClusterInject)
+	do.Provide[*config.SearchConfig](Injector, database.NewSearchConfigInject)
+	do.Provide[*database.PoolStatsCollector](Injector, database.NewPoolStatsCollectorInject)
+	// Halaman pertama daftar employee di Redis, di-invalidate semua service
+	// yang mengubah employee
+	do.Provide[*cache.EmployeeFirstPage](Injector, cache.NewEmployeeFirstPageInject)
+
 	// Setup repositories
 	// UserRepository
-	do.Provide[userRepository.UserRepository](Injector, userRepository.NewUserRepositoryInject)
-	do.Provide[departmentRepository.DepartmentRepository](Injector, departmentRepository.NewInject)
-	do.Provide[repositories.EmployeeRepository](Injector, repositories.NewEmployeeRepositoryInject)
+	do.Provide[userRepository.UserRepositoryInterface](Injector, userRepository.NewUserRepositoryInject)
+	do.Provide[departmentRepository.DepartmentRepositoryInterface](Injector, departmentRepository.NewInject)
+	do.Provide[*departmentRepository.OwnershipListener](Injector, departmentRepository.NewOwnershipListenerInject)
+	do.Provide[repositories.EmployeeRepositoryInterface](Injector, repositories.NewEmployeeRepositoryInject)
+	do.Provide[auditRepository.AuditRepositoryInterface](Injector, auditRepository.NewInject)
+	do.Provide[featureFlagRepository.FeatureFlagRepositoryInterface](Injector, featureFlagRepository.NewInject)
+	do.Provide[repository.UnitOfWork](Injector, repository.NewUnitOfWorkInject)
+	do.Provide[webhookRepository.WebhookRepositoryInterface](Injector, webhookRepository.NewInject)
+	do.Provide[apiKeyRepository.APIKeyRepositoryInterface](Injector, apiKeyRepository.NewInject)
+	do.Provide[fileRepository.FileRepositoryInterface](Injector, fileRepository.NewInject)
+	do.Provide[integrationRepository.IntegrationRepositoryInterface](Injector, integrationRepository.NewInject)
+	do.Provide[*webhook.Publisher](Injector, webhook.NewPublisherInject)
+	do.Provide[*webhook.Deliverer](Injector, webhook.NewDelivererInject)
+	// Event outbox untuk stream SSE di instance ini
+	do.Provide[*eventbus.Bus](Injector, eventbus.NewInject)
+	// Koneksi WebSocket, ditunggu selesai saat shutdown
+	do.Provide[*realtime.Hub](Injector, realtime.NewHubInject)
+	// Koneksi NATS, hanya dibuat jika EVENT_PUBLISHER=nats
+	do.Provide[*outbox.NATSPublisher](Injector, outbox.NewNATSPublisherInject)
+	// Event outbox dikirim ke EVENT_PUBLISHER, diantrikan ke webhook yang melanggan, dan diteruskan ke stream SSE
+	do.Provide[outbox.Publisher](Injector, webhook.NewOutboxPublisherInject)
+	do.Provide[*outbox.Dispatcher](Injector, outbox.NewDispatcherInject)
+	do.Provide[*purge.Purger](Injector, purge.NewPurgerInject)
+	do.Provide[*purge.Cleaner](Injector, purge.NewCleanerInject)
+	// Worker pool untuk pekerjaan background yang boleh hilang saat restart
+	do.Provide[*worker.Pool](Injector, worker.NewInject)
+	// Thumbnail gambar upload, dibuat di worker pool
+	do.Provide[*thumbnail.Generator](Injector, thumbnail.NewGeneratorInject)
 
 	// Setup Services
-	do.Provide[userService.UserService](Injector, userService.NewUserServiceInject)
+	do.Provide[auth.Service](Injector, auth.NewJWTServiceInject)
+	do.Provide[userService.IUserService](Injector, userService.NewUserServiceInject)
 	do.Provide[departmentService.DepartmentService](Injector, departmentService.NewInject)
 	do.Provide[user_service.EmployeeService](Injector, user_service.NewEmployeeServiceInject)
+	do.Provide[auditService.AuditService](Injector, auditService.NewInject)
+	do.Provide[featureflag.FeatureFlags](Injector, featureflag.NewInject)
+	do.Provide[webhookService.WebhookService](Injector, webhookService.NewInject)
+	do.Provide[apiKeyService.APIKeyService](Injector, apiKeyService.NewInject)
+	do.Provide[fileService.FileService](Injector, fileService.NewFileServiceInject)
+	do.Provide[integrationService.IntegrationService](Injector, integrationService.NewInject)
+	// Tugas operasional cmd/admin, tidak dipakai server
+	do.Provide[adminService.AdminService](Injector, adminService.NewInject)
 
 	// Setup Handlers
 	do.Provide[userHandler.UserHandler](Injector, userHandler.NewUserHandlerInject)
 	do.Provide[authHandler.AuthorizationHandler](Injector, authHandler.NewHandlerInject)
 	do.Provide[departmentHandler.DepartmentHandler](Injector, departmentHandler.NewInject)
 	do.Provide[employeeHandler.EmployeeHandler](Injector, employeeHandler.NewEmployeeHandlerInject)
+	do.Provide[healthHandler.HealthHandler](Injector, healthHandler.NewHealthHandlerInject)
+	do.Provide[auditHandler.AuditHandler](Injector, auditHandler.NewInject)
+	do.Provide[adminHandler.AdminHandler](Injector, adminHandler.NewInject)
+	do.Provide[webhookHandler.WebhookHandler](Injector, webhookHandler.NewInject)
+	do.Provide[apiKeyHandler.APIKeyHandler](Injector, apiKeyHandler.NewInject)
+	do.Provide[fileHandler.FileHandler](Injector, fileHandler.NewHandlerInject)
+	do.Provide[integrationHandler.IntegrationHandler](Injector, integrationHandler.NewInject)
+	do.Provide[streamHandler.StreamHandler](Injector, streamHandler.NewInject)
+	do.Provide[websocketHandler.WebSocketHandler](Injector, websocketHandler.NewInject)
+	// Resolver GraphQL memakai service yang sama dengan handler REST
+	do.Provide[*graph.Resolver](Injector, graph.NewResolverInject)
+	do.Provide[graphqlHandler.GraphQLHandler](Injector, graphqlHandler.NewGraphQLHandlerInject)
 
 	// Setup client
-	envMode := os.Getenv("MODE")
-	if envMode == "DEBUG" {
-		do.Provide[domain.StorageClient](Injector, storage.NewMockStorageClientInject)
-	} else {
-		do.Provide[domain.StorageClient](Injector, storage.NewS3StorageClientInject)
-	}
+	// Storage file, S3/MinIO atau memory sesuai STORAGE_DRIVER
+	do.Provide[domain.Storage](Injector, storage.NewInject)
 }
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Storage menyimpan object (file) berdasarkan key, lihat
+// infrastructure/storage. Error dari driver sudah dipetakan ke error
+// terdaftar: helper.ErrNotFound jika key tidak ada, storage.ErrUnavailable
+// jika storage tidak bisa dihubungi, dan storage.ErrFailed untuk penolakan
+// lain dari storage.
+type Storage interface {
+	// Put menyimpan body dengan key dan contentType, dibaca sampai habis
+	// tanpa ditampung seluruhnya di memori. size adalah panjang body, atau
+	// -1 jika tidak diketahui. isPublic membuat object bisa dibaca lewat
+	// URL tanpa signature. Put mengembalikan URL object, lihat URL.
+	Put(
+		ctx context.Context,
+		key string,
+		contentType string,
+		body io.Reader,
+		size int64,
+		isPublic bool,
+	) (string, error)
+	// Get membuka object untuk dibaca. Pemanggil wajib menutup Body.
+	Get(ctx context.Context, key string) (*Object, error)
+	// Stat membaca metadata object tanpa isinya.
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	// Delete menghapus object. Key yang tidak ada tidak dianggap error.
+	Delete(ctx context.Context, key string) error
+	// SignedURL membuat URL sementara untuk membaca object private, berlaku
+	// selama expiry.
+	SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+	// URL mengembalikan URL permanen object, yang hanya bisa dibaca jika
+	// object di-Put dengan isPublic.
+	URL(key string) string
+}
+
+// Object adalah isi dan metadata object yang dibaca dari Storage.
+type Object struct {
+	// Body juga mengimplementasikan io.Seeker jika driver mendukungnya,
+	// sehingga bisa dilayani dengan Range request, lihat http.ServeContent.
+	Body io.ReadCloser
+	ObjectInfo
+}
+
+type ObjectInfo struct {
+	ContentType string
+	// Size adalah panjang object, atau -1 jika tidak diketahui.
+	Size int64
+	// ETag berubah setiap kali isi object berubah, sudah dengan tanda kutip.
+	ETag    string
+	ModTime time.Time
+}
+
+// PresignedUploader diimplementasikan driver Storage yang bisa menerima
+// upload langsung dari client tanpa melewati API. Driver lain hanya bisa
+// di-upload lewat Put.
+type PresignedUploader interface {
+	// SignedPutURL membuat request PUT untuk menyimpan tepat size byte
+	// dengan contentType ke key, berlaku selama expiry. Object yang di-upload
+	// dengan request ini sama dengan Put dengan isPublic.
+	SignedPutURL(
+		ctx context.Context,
+		key string,
+		contentType string,
+		size int64,
+		expiry time.Duration,
+	) (*PresignedRequest, error)
+}
+
+// PresignedRequest adalah request yang harus dikirim client apa adanya.
+type PresignedRequest struct {
+	Method string
+	URL    string
+	// Headers wajib dikirim dengan nilai yang sama, karena ikut
+	// ditandatangani.
+	Headers map[string]string
+}
@@ -0,0 +1,50 @@
+package dto
+
+import "time"
+
+// Request dan response perintah cmd/admin. Operator adalah nama orang yang
+// menjalankan perintah, dicatat sebagai actor audit log.
+
+type AdminUserCreateRequest struct {
+	Operator string `json:"operator" validate:"required,max=64"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+type AdminUserRequest struct {
+	Operator string `json:"operator" validate:"required,max=64"`
+	Email    string `json:"email" validate:"required,email"`
+}
+
+type AdminDepartmentReassignRequest struct {
+	Operator     string `json:"operator" validate:"required,max=64"`
+	DepartmentID string `json:"departmentId" validate:"required,uuid"`
+	ToManagerID  string `json:"toManagerId" validate:"required,uuid"`
+}
+
+type AdminUserResponse struct {
+	ManagerID string `json:"managerId"`
+	Email     string `json:"email"`
+}
+
+type AdminPasswordResetResponse struct {
+	ManagerID string `json:"managerId"`
+	Email     string `json:"email"`
+	// Password adalah password baru yang dibuat acak, hanya ditampilkan
+	// sekali
+	Password          string    `json:"password"`
+	SessionsRevokedAt time.Time `json:"sessionsRevokedAt"`
+}
+
+type AdminSessionsRevokedResponse struct {
+	ManagerID         string    `json:"managerId"`
+	Email             string    `json:"email"`
+	SessionsRevokedAt time.Time `json:"sessionsRevokedAt"`
+}
+
+type AdminDepartmentReassignResponse struct {
+	DepartmentID   string `json:"departmentId"`
+	DepartmentName string `json:"name"`
+	FromManagerID  string `json:"fromManagerId"`
+	ToManagerID    string `json:"toManagerId"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+type APIKeyRequest struct {
+	Name   string   `json:"name" validate:"required,min=1,max=100"`
+	Scopes []string `json:"scopes" validate:"required,min=1,unique,dive,apikeyscope"`
+	// ExpiresAt kosong berarti key tidak kedaluwarsa
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// APIKeyResponse hanya berisi Key pada response create.
+type APIKeyResponse struct {
+	Id     string   `json:"id"`
+	Name   string   `json:"name"`
+	Prefix string   `json:"prefix"`
+	Key    string   `json:"key,omitempty"`
+	Scopes []string `json:"scopes"`
+	// ExpiresAt null jika key tidak kedaluwarsa
+	ExpiresAt *time.Time `json:"expiresAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
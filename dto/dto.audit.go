@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditChange adalah nilai satu field sebelum dan sesudah perubahan. Before
+// null pada create, After null pada delete.
+type AuditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+type GetAuditLogsRequest struct {
+	// ActorID kosong hanya boleh dari route admin, berarti semua manager
+	ActorID    string     `query:"managerId" validate:"omitempty,uuid"`
+	EntityType string     `query:"entityType" validate:"omitempty,oneof=employee department user"`
+	EntityID   string     `query:"entityId" validate:"omitempty,max=255"`
+	From       *time.Time `query:"from"`
+	To         *time.Time `query:"to"`
+	Limit      int        `query:"limit" validate:"gte=0,lte=100"`
+	Offset     int        `query:"offset" validate:"gte=0"`
+}
+
+type AuditLogResponse struct {
+	Id         int64           `json:"id"`
+	ActorID    string          `json:"actorId"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entityType"`
+	EntityID   string          `json:"entityId"`
+	Changes    json.RawMessage `json:"changes" swaggertype:"object"`
+	RequestID  string          `json:"requestId,omitempty"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
@@ -1,5 +1,12 @@
 package dto
 
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"time"
+)
+
 const (
 	GenderMale   = "male"
 	GenderFemale = "female"
@@ -8,20 +15,278 @@ const (
 	DefaultOffset = 0
 )
 
+// Status employee. Employee yang diarsipkan tidak ikut daftar dan headcount
+// default, tetapi tetap memakai identityNumber-nya.
+const (
+	EmployeeStatusActive   = "active"
+	EmployeeStatusArchived = "archived"
+	// EmployeeStatusAll adalah filter status GET /v1/employee tanpa batasan
+	// status
+	EmployeeStatusAll = "all"
+)
+
+// EmployeeSortFields adalah nilai sortBy yang diterima GET /v1/employee.
+var EmployeeSortFields = []string{"identityNumber", "name", "createdAt", "hiredAt"}
+
+// DateLayout adalah format tanggal tanpa jam (RFC 3339 full-date), mis.
+// hiredAt.
+const DateLayout = "2006-01-02"
+
 type EmployeePayload struct {
-	IdentityNumber   string `json:"identityNumber" validate:"required,min=5,max=33"`
+	IdentityNumber   string `json:"identityNumber" validate:"required,min=5,max=33,identitynumber"`
 	Name             string `json:"name" validate:"required,min=4,max=33"`
-	EmployeeImageUri string `json:"employeeImageUri" validate:"required,uri"`
-	Gender           string `json:"gender" validate:"required"`
+	EmployeeImageUri string `json:"employeeImageUri" validate:"required,url"`
+	Gender           string `json:"gender" validate:"required,oneof=male female"`
 	DepartmentID     string `json:"departmentId" validate:"required,uuid"`
+	// HiredAt adalah tanggal mulai bekerja dengan format DateLayout, tidak
+	// boleh setelah hari ini. Employee tanpa tanggal ini dikirim sebagai null
+	HiredAt *string `json:"hiredAt" validate:"omitnil,datetime=2006-01-02,notfuture"`
+}
+
+// Equal membandingkan isi payload, termasuk nilai HiredAt alih-alih
+// pointer-nya.
+func (p EmployeePayload) Equal(other EmployeePayload) bool {
+	hiredAt, otherHiredAt := p.HiredAt, other.HiredAt
+	p.HiredAt, other.HiredAt = nil, nil
+	if p != other || (hiredAt == nil) != (otherHiredAt == nil) {
+		return false
+	}
+	return hiredAt == nil || *hiredAt == *otherHiredAt
+}
+
+// EmployeeUpdatePayload hanya mengubah field yang dikirim. Field yang
+// tidak dikirim bernilai nil dan tidak diubah; field yang dikirim, termasuk
+// string kosong, divalidasi penuh. Field yang dikirim sebagai null juga nil,
+// sehingga dicatat terpisah di Nulls, lihat validation.ValidateEmployeePatch.
+type EmployeeUpdatePayload struct {
+	IdentityNumber   *string `json:"identityNumber" validate:"omitempty,min=5,max=33,identitynumber"`
+	Name             *string `json:"name" validate:"omitempty,min=4,max=33"`
+	EmployeeImageUri *string `json:"employeeImageUri" validate:"omitempty,url"`
+	Gender           *string `json:"gender" validate:"omitempty,oneof=male female"`
+	DepartmentID     *string `json:"departmentId" validate:"omitempty,uuid"`
+	HiredAt          *string `json:"hiredAt" validate:"omitnil,datetime=2006-01-02,notfuture"`
+	// Nulls adalah nama field JSON yang dikirim dengan nilai null, diisi
+	// UnmarshalJSON
+	Nulls []string `json:"-"`
+}
+
+// UnmarshalJSON mencatat field yang bernilai null di Nulls, karena
+// encoding/json tidak membedakannya dengan field yang tidak dikirim.
+func (p *EmployeeUpdatePayload) UnmarshalJSON(data []byte) error {
+	type payload EmployeeUpdatePayload
+	if err := json.Unmarshal(data, (*payload)(p)); err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	p.Nulls = nil
+	for field, value := range fields {
+		if bytes.Equal(value, []byte("null")) {
+			p.Nulls = append(p.Nulls, field)
+		}
+	}
+	slices.Sort(p.Nulls)
+	return nil
+}
+
+// IsNull bernilai true jika field dikirim sebagai null.
+func (p *EmployeeUpdatePayload) IsNull(field string) bool {
+	return slices.Contains(p.Nulls, field)
+}
+
+type EmployeeResponse struct {
+	EmployeePayload
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	// EmployeeImage hanya diisi GET /v1/employee?expand=images
+	EmployeeImage *EmployeeImage `json:"employeeImage,omitempty"`
+	// Department hanya diisi GET /v1/employee?expand=department
+	Department *ResponseSingleDepartment `json:"department,omitempty"`
+	// Status adalah EmployeeStatusActive atau EmployeeStatusArchived
+	Status string `json:"status,omitempty"`
+}
+
+// ExpandImages adalah nilai expand GET /v1/employee yang menambahkan
+// employeeImage ke setiap employee.
+const ExpandImages = "images"
+
+// ExpandDepartment adalah nilai expand GET /v1/employee yang menambahkan
+// department (id dan nama) ke setiap employee.
+const ExpandDepartment = "department"
+
+type EmployeeImage struct {
+	// Uri adalah thumbnail jika sudah siap, atau employeeImageUri
+	Uri          string `json:"uri"`
+	OriginalUri  string `json:"originalUri"`
+	ThumbnailUri string `json:"thumbnailUri,omitempty"`
+	// ThumbnailStatus adalah pending, ready, atau failed; kosong jika
+	// employeeImageUri bukan file hasil upload atau tidak punya thumbnail
+	ThumbnailStatus string `json:"thumbnailStatus,omitempty"`
 }
 
 type GetEmployeesRequest struct {
-	Limit          int    `query:"limit" validate:"gte=0"`
+	Limit          int    `query:"limit" validate:"gte=1"`
 	Offset         int    `query:"offset" validate:"gte=0"`
 	IdentityNumber string `query:"identityNumber" validate:""` // validate is not set to `uuid` due to it allows wildcard
+	// IdentityNumbers hanya mengambil employee dengan identityNumber persis
+	// salah satu nilai ini, maksimal 100 nilai. Tidak bisa
+	// digabung dengan IdentityNumber
+	IdentityNumbers []string `query:"identityNumbers" validate:"omitempty,max=100,dive,min=5,max=33,identitynumber"`
+	// IncludeMissing menambahkan IdentityNumbers yang tidak ditemukan ke
+	// response, lihat EmployeeListMeta
+	IncludeMissing bool   `query:"includeMissing"`
 	Name           string `query:"name" validate:""`
-	Gender         string `query:"gender" validate:""`
-	DepartmentID   string `query:"departmentId" validate:"omitempty,uuid"`
-	ManagerID      string `query:"managerId" validate:"omitempty,uuid"`
+	// Fuzzy mencari nama yang mirip (typo tolerant) alih-alih contains
+	Fuzzy bool `query:"fuzzy"`
+	// Query adalah full-text search nama, hasil diurutkan berdasarkan relevansi
+	Query  string `query:"q" validate:"omitempty,max=100"`
+	Gender string `query:"gender" validate:"omitempty,oneof=male female"`
+	// DepartmentID dibandingkan persis; uuid huruf besar lolos validasi,
+	// tetapi tidak cocok dengan id yang dibuat server
+	DepartmentID string `query:"departmentId" validate:"omitempty,uuidanycase"`
+	ManagerID    string `query:"managerId" validate:"omitempty,uuid"`
+	// SortBy mengurutkan hasil berdasarkan salah satu EmployeeSortFields,
+	// menggantikan urutan relevansi q/fuzzy
+	SortBy string `query:"sortBy" validate:"omitempty,employeesort"`
+	// Expand menambahkan data terkait ke response, lihat ExpandImages dan
+	// ExpandDepartment
+	Expand string `query:"expand" validate:"omitempty,oneof=images department"`
+	// Status adalah EmployeeStatusActive (default dari ValidateEmployeeGet),
+	// EmployeeStatusArchived atau EmployeeStatusAll. Kosong berarti tanpa
+	// batasan status, hanya untuk pemanggil internal.
+	Status string `query:"status" validate:"omitempty,oneof=active archived all"`
+	// HiredFrom dan HiredTo membatasi hiredAt (inklusif) dengan format
+	// DateLayout; employee tanpa hiredAt tidak ikut jika salah satunya diisi
+	HiredFrom string `query:"hiredFrom" validate:"omitempty,datetime=2006-01-02"`
+	HiredTo   string `query:"hiredTo" validate:"omitempty,datetime=2006-01-02"`
+	// EmptyFilters adalah nama query filter yang dikirim tanpa nilai (mis.
+	// "gender="), berbeda dengan filter yang tidak dikirim. Lihat
+	// validation.ValidateEmployeeGet
+	EmptyFilters []string `json:"-" query:"-" validate:"-"`
+}
+
+type IdentityNumberAvailability struct {
+	IdentityNumber string `json:"identityNumber"`
+	Available      bool   `json:"available"`
+}
+
+// MaxBulkEmployees adalah jumlah employee maksimum per request bulk create.
+const MaxBulkEmployees = 100
+
+// EmployeeListMeta adalah meta GET /v1/employee dengan includeMissing=true.
+type EmployeeListMeta struct {
+	// Missing adalah identityNumbers yang tidak cocok dengan filter, dengan
+	// urutan seperti di request
+	Missing []string `json:"missing"`
+}
+
+type EmployeeBulkResult struct {
+	Index  int    `json:"index"`
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// EmployeeDryRunResult adalah hasil create dengan dryRun=true. Fields berisi
+// semua error per field dengan pesan yang sama dengan create yang gagal.
+type EmployeeDryRunResult struct {
+	Valid  bool              `json:"valid"`
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// EmployeeBulkDryRunResult adalah hasil bulk create dengan dryRun=true per
+// index.
+type EmployeeBulkDryRunResult struct {
+	Index int `json:"index"`
+	EmployeeDryRunResult
+}
+
+// MaxImportFailures membatasi jumlah detail baris gagal di laporan import
+// agar ukuran response tidak ikut membesar bersama ukuran file.
+const MaxImportFailures = 100
+
+type EmployeeImportFailure struct {
+	Row    int    `json:"row"`
+	Reason string `json:"reason"`
+}
+
+type EmployeeImportReport struct {
+	ProcessedRows int `json:"processedRows"`
+	// ImportedRows adalah baris yang tersimpan, yaitu CreatedRows ditambah
+	// UpdatedRows.
+	ImportedRows int `json:"importedRows"`
+	CreatedRows  int `json:"createdRows"`
+	// UpdatedRows dan UnchangedRows hanya diisi import JSONL, yang
+	// meng-update employee dengan identityNumber yang sudah ada. Employee
+	// yang datanya sama dengan baris import tidak diubah.
+	UpdatedRows   int `json:"updatedRows"`
+	UnchangedRows int `json:"unchangedRows"`
+	// FailedRows adalah baris yang tidak lolos parsing atau validasi.
+	FailedRows int `json:"failedRows"`
+	// SkippedRows adalah baris valid yang ditolak database karena
+	// department bukan milik manager atau identity number sudah terpakai.
+	SkippedRows int `json:"skippedRows"`
+	// CopiedRows adalah baris yang dikirim lewat COPY ke tabel staging.
+	CopiedRows  int                     `json:"copiedRows"`
+	Failures    []EmployeeImportFailure `json:"failures"`
+	Aborted     bool                    `json:"aborted"`
+	AbortReason string                  `json:"abortReason,omitempty"`
+	// DryRun berarti tidak ada yang disimpan; jumlah baris adalah hasil
+	// yang akan terjadi jika import dijalankan.
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// AddFailure mencatat baris yang gagal, detailnya hanya disimpan sampai
+// MaxImportFailures.
+func (r *EmployeeImportReport) AddFailure(row int, reason string) {
+	r.FailedRows++
+	r.addDetail(row, reason)
+}
+
+// AddSkipped mencatat baris valid yang ditolak database.
+func (r *EmployeeImportReport) AddSkipped(row int, reason string) {
+	r.SkippedRows++
+	r.addDetail(row, reason)
+}
+
+func (r *EmployeeImportReport) addDetail(row int, reason string) {
+	if len(r.Failures) < MaxImportFailures {
+		r.Failures = append(r.Failures, EmployeeImportFailure{Row: row, Reason: reason})
+	}
+}
+
+// EmployeeStats adalah jumlah employee aktif dan yang diarsipkan, total dan
+// per department.
+type EmployeeStats struct {
+	Active      int64                     `json:"active"`
+	Archived    int64                     `json:"archived"`
+	Departments []DepartmentEmployeeStats `json:"departments"`
+}
+
+type DepartmentEmployeeStats struct {
+	DepartmentID string `json:"departmentId"`
+	Active       int64  `json:"active"`
+	Archived     int64  `json:"archived"`
+}
+
+// EmployeeVersion adalah salinan employee setelah satu perubahan. Versi
+// delete berisi data terakhir sebelum employee dihapus.
+type EmployeeVersion struct {
+	Version int `json:"version"`
+	// Action adalah create, update, atau delete
+	Action string `json:"action"`
+	EmployeePayload
+	Status    string    `json:"status"`
+	ChangedBy string    `json:"changedBy"`
+	ChangedAt time.Time `json:"changedAt"`
+}
+
+// GetEmployeeHistoryRequest adalah request GET
+// /v1/employee/:identityNumber/history.
+type GetEmployeeHistoryRequest struct {
+	IdentityNumber string
+	ManagerID      string
+	Limit          int `query:"limit" validate:"gte=1,lte=100"`
+	Offset         int `query:"offset" validate:"gte=0"`
 }
@@ -0,0 +1,12 @@
+package dto
+
+type FeatureFlagOverrideRequest struct {
+	Enabled *bool `json:"enabled" validate:"required"`
+}
+
+type FeatureFlagOverrideResponse struct {
+	Flag      string `json:"flag"`
+	ManagerID string `json:"managerId"`
+	// Enabled adalah nilai flag untuk manager setelah perubahan
+	Enabled bool `json:"enabled"`
+}
@@ -1,11 +1,61 @@
 package dto
 
-import "mime/multipart"
+import "time"
 
-type FileUploadRequestPayload struct {
-	File *multipart.FileHeader `form:"file" binding:"required"`
+type FileUploadRespondPayload struct {
+	FileId string `json:"fileId"`
+	Uri    string `json:"uri"`
 }
 
-type FileUploadRespondPayload struct {
-	Uri string `form:"uri"`
+type FilePresignRequest struct {
+	ContentType string `json:"contentType" validate:"required,oneof=image/jpeg image/png"`
+	// Size adalah ukuran file dalam byte, upload dengan ukuran lain ditolak
+	// storage.
+	Size     int64  `json:"size" validate:"required,min=1"`
+	FileName string `json:"fileName" validate:"omitempty,max=255"`
+}
+
+// FilePresignResponse berisi request upload yang harus dikirim client. Jika
+// Presigned false, storage tidak mendukung upload langsung dan file harus
+// di-upload lewat UploadUrl (POST /v1/file) seperti biasa.
+type FilePresignResponse struct {
+	Presigned bool `json:"presigned"`
+	// FileId dipakai untuk POST /v1/file/:id/complete setelah upload selesai.
+	FileId    string            `json:"fileId,omitempty"`
+	Method    string            `json:"method"`
+	UploadUrl string            `json:"uploadUrl"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	ExpiresAt *time.Time        `json:"expiresAt,omitempty"`
+}
+
+type GetFilesRequest struct {
+	Limit  int `query:"limit" validate:"gte=0,lte=100"`
+	Offset int `query:"offset" validate:"gte=0"`
+}
+
+type FileResponse struct {
+	FileId          string `json:"fileId"`
+	Uri             string `json:"uri"`
+	FileName        string `json:"fileName"`
+	Size            int64  `json:"size"`
+	ContentType     string `json:"contentType"`
+	Purpose         string `json:"purpose"`
+	ThumbnailUri    string `json:"thumbnailUri,omitempty"`
+	ThumbnailStatus string `json:"thumbnailStatus,omitempty"`
+	// ReferenceCount adalah jumlah employee dan profil manager yang memakai
+	// file. File yang tidak dipakai dihapus otomatis setelah
+	// CLEANUP_FILE_GRACE_PERIOD.
+	ReferenceCount int       `json:"referenceCount"`
+	CreatedAt      time.Time `json:"createdAt"`
+	// ReferencedBy hanya ada pada GET /v1/file/:id
+	ReferencedBy *FileReferencesResponse `json:"referencedBy,omitempty"`
+}
+
+type FileReferencesResponse struct {
+	// IdentityNumbers adalah employee manager yang memakai file sebagai
+	// employeeImageUri, paling banyak 100
+	IdentityNumbers []string `json:"identityNumbers"`
+	// ProfileFields adalah field profil manager yang memakai file,
+	// userImageUri dan/atau companyImageUri
+	ProfileFields []string `json:"profileFields"`
 }
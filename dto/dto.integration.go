@@ -0,0 +1,6 @@
+package dto
+
+type IntegrationCallbackResponse struct {
+	// Id adalah id callback di antrian
+	Id int64 `json:"id"`
+}
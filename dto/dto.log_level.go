@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+type LogLevelRequest struct {
+	Level string `json:"level" validate:"required,oneof=debug info warn error"`
+	// RevertAfter adalah durasi Go (mis. "15m") sebelum level kembali ke
+	// default. Kosong berarti level tetap sampai diubah lagi.
+	RevertAfter string `json:"revertAfter" validate:"omitempty"`
+	// SlowRequestThreshold dan SlowQueryThreshold adalah durasi Go (mis.
+	// "2s") yang menggantikan SLOW_REQUEST_THRESHOLD dan
+	// SLOW_QUERY_THRESHOLD. Kosong berarti threshold tidak diubah.
+	SlowRequestThreshold string `json:"slowRequestThreshold" validate:"omitempty"`
+	SlowQueryThreshold   string `json:"slowQueryThreshold" validate:"omitempty"`
+}
+
+type LogLevelResponse struct {
+	Level        string `json:"level"`
+	DefaultLevel string `json:"defaultLevel"`
+	// RevertAt hanya diisi jika revert ke level default sudah dijadwalkan
+	RevertAt *time.Time `json:"revertAt,omitempty"`
+	// SlowRequestThreshold dan SlowQueryThreshold adalah threshold yang
+	// sedang dipakai, dalam format durasi Go
+	SlowRequestThreshold string `json:"slowRequestThreshold"`
+	SlowQueryThreshold   string `json:"slowQueryThreshold"`
+}
@@ -0,0 +1,39 @@
+package dto
+
+import "time"
+
+type WebhookRequest struct {
+	Url string `json:"url" validate:"required,url,max=2048"`
+	// Events kosong berarti semua event
+	Events []string `json:"events" validate:"omitempty,unique,dive,webhookevent"`
+	// Secret kosong akan dibuatkan server
+	Secret string `json:"secret" validate:"omitempty,min=16,max=128"`
+}
+
+// WebhookResponse hanya berisi Secret pada response create.
+type WebhookResponse struct {
+	Id        string    `json:"id"`
+	Url       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"secret,omitempty"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type GetWebhookDeliveriesRequest struct {
+	Limit  int `query:"limit" validate:"gte=0,lte=100"`
+	Offset int `query:"offset" validate:"gte=0"`
+}
+
+type WebhookDeliveryResponse struct {
+	Id             int64      `json:"id"`
+	EventId        *int64     `json:"eventId"`
+	EventType      string     `json:"eventType"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"nextAttemptAt,omitempty"`
+	LastStatusCode *int       `json:"lastStatusCode"`
+	LastError      *string    `json:"lastError"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	DeliveredAt    *time.Time `json:"deliveredAt"`
+}
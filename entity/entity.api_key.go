@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+// Scope API key. Saat ini hanya ada scope baca, API key tidak pernah bisa
+// mengubah data.
+const (
+	APIKeyScopeEmployeesRead   = "employees:read"
+	APIKeyScopeDepartmentsRead = "departments:read"
+)
+
+// APIKeyScopes adalah scope yang bisa dipilih saat membuat API key.
+var APIKeyScopes = []string{
+	APIKeyScopeEmployeesRead,
+	APIKeyScopeDepartmentsRead,
+}
+
+type APIKey struct {
+	Id        string     `json:"id"`
+	ManagerId string     `json:"managerId"`
+	Name      string     `json:"name"`
+	Prefix    string     `json:"prefix"`
+	Hash      string     `json:"-"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+
+	AuditEntityEmployee   = "employee"
+	AuditEntityDepartment = "department"
+	AuditEntityUser       = "user"
+)
+
+type AuditLog struct {
+	Id         int64     `json:"id"`
+	ActorId    string    `json:"actorId"`
+	Action     string    `json:"action"`
+	EntityType string    `json:"entityType"`
+	EntityId   string    `json:"entityId"`
+	Changes    []byte    `json:"-"`
+	RequestId  string    `json:"requestId"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
@@ -1,9 +1,63 @@
 package entity
 
-import "database/sql"
+import "time"
+
+// Status thumbnail file, lihat thumbnail.Generator. File tanpa thumbnail
+// memiliki ThumbnailStatus kosong.
+const (
+	ThumbnailPending = "pending"
+	ThumbnailReady   = "ready"
+	ThumbnailFailed  = "failed"
+)
+
+// Kegunaan file, lihat File.Purpose. FILE_RESTRICT_EMPLOYEE_IMAGES hanya
+// menerima file FilePurposeEmployee sebagai employeeImageUri.
+const (
+	FilePurposeEmployee = "employee"
+	FilePurposeProfile  = "profile"
+)
 
 type File struct {
-	FileId   string         `json:"fileid"`
-	FileURI  sql.NullString `json:"fileuri"`
-	FileName sql.NullString `json:"filename"`
+	FileId      string    `json:"fileid"`
+	ManagerId   string    `json:"managerid"`
+	FileName    string    `json:"filename"`
+	FileURI     string    `json:"fileuri"`
+	FileKey     string    `json:"filekey"`
+	Size        int64     `json:"filesize"`
+	ContentType string    `json:"contenttype"`
+	Purpose     string    `json:"purpose"`
+	CreatedOn   time.Time `json:"createdon"`
+	// ReferenceCount adalah kolom referencecount, lihat
+	// FileRepository.AdjustReferences
+	ReferenceCount int `json:"referencecount"`
+
+	ThumbnailKey      string `json:"thumbnailkey"`
+	ThumbnailURI      string `json:"thumbnailuri"`
+	ThumbnailStatus   string `json:"thumbnailstatus"`
+	ThumbnailAttempts int    `json:"thumbnailattempts"`
+}
+
+// FileReferences adalah employee dan profil manager yang memakai file.
+type FileReferences struct {
+	// Count adalah semua rujukan, termasuk employee manager lain
+	Count int
+	// IdentityNumbers adalah employee milik manager pemilik file
+	IdentityNumbers []string
+	// ProfileFields adalah field profil pemilik file yang memakai file:
+	// userImageUri dan/atau companyImageUri
+	ProfileFields []string
+}
+
+// FileUpload adalah upload presign yang belum dikonfirmasi. Expired diisi
+// dari jam database saat dibaca.
+type FileUpload struct {
+	Id          string
+	ManagerId   string
+	FileKey     string
+	FileName    string
+	ContentType string
+	Size        int64
+	ExpiresAt   time.Time
+	Expired     bool
+	CreatedAt   time.Time
 }
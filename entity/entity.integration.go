@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// IntegrationCallback adalah callback partner yang sudah diverifikasi dan
+// menunggu diproses.
+type IntegrationCallback struct {
+	Id          int64      `json:"id"`
+	Integration string     `json:"integration"`
+	Signature   string     `json:"-"`
+	SignedAt    time.Time  `json:"signedAt"`
+	Payload     []byte     `json:"-"`
+	ReceivedAt  time.Time  `json:"receivedAt"`
+	ProcessedAt *time.Time `json:"processedAt"`
+}
@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+const (
+	AggregateEmployee   = "employee"
+	AggregateDepartment = "department"
+
+	EventEmployeeCreated   = "employee.created"
+	EventEmployeeUpdated   = "employee.updated"
+	EventEmployeeDeleted   = "employee.deleted"
+	EventDepartmentCreated = "department.created"
+	EventDepartmentUpdated = "department.updated"
+	EventDepartmentDeleted = "department.deleted"
+)
+
+type OutboxEvent struct {
+	Id            int64     `json:"id"`
+	EventType     string    `json:"type"`
+	AggregateType string    `json:"aggregateType"`
+	AggregateId   string    `json:"aggregateId"`
+	Payload       []byte    `json:"-"`
+	CreatedAt     time.Time `json:"createdAt"`
+	Attempts      int       `json:"-"`
+}
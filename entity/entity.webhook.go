@@ -0,0 +1,51 @@
+package entity
+
+import "time"
+
+const (
+	// EventWebhookTest dikirim oleh endpoint test, tidak pernah lewat outbox
+	EventWebhookTest = "webhook.test"
+
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead"
+)
+
+// WebhookEvents adalah event outbox yang boleh dilanggan webhook.
+var WebhookEvents = []string{
+	EventEmployeeCreated,
+	EventEmployeeUpdated,
+	EventEmployeeDeleted,
+	EventDepartmentCreated,
+	EventDepartmentUpdated,
+	EventDepartmentDeleted,
+}
+
+type Webhook struct {
+	Id        string    `json:"id"`
+	ManagerId string    `json:"managerId"`
+	Url       string    `json:"url"`
+	Secret    string    `json:"-"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type WebhookDelivery struct {
+	Id             int64      `json:"id"`
+	WebhookId      string     `json:"webhookId"`
+	EventId        *int64     `json:"eventId"`
+	EventType      string     `json:"eventType"`
+	Payload        []byte     `json:"-"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  time.Time  `json:"nextAttemptAt"`
+	LastStatusCode *int       `json:"lastStatusCode"`
+	LastError      *string    `json:"lastError"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	DeliveredAt    *time.Time `json:"deliveredAt"`
+
+	// Url dan Secret hanya diisi oleh ClaimDeliveries
+	Url    string `json:"-"`
+	Secret string `json:"-"`
+}
@@ -0,0 +1,207 @@
+// Package eventbus meneruskan event outbox ke subscriber di proses yang
+// sama, mis. stream SSE GET /v1/employee/stream dan WebSocket GET /v1/ws.
+//
+// Bus dipasang sebagai salah satu outbox.Publisher, sehingga hanya menerima
+// event yang dikirim dispatcher di instance ini. Dengan beberapa instance,
+// client hanya menerima sebagian event dan sebaiknya tetap memuat ulang
+// data secara berkala atau saat menerima event reset.
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/events"
+	"github.com/samber/do/v2"
+)
+
+// Event adalah satu event outbox untuk manager tertentu.
+type Event struct {
+	ID        int64
+	Type      string
+	ManagerID string
+	// DepartmentID adalah department employee (setelah perubahan) atau
+	// department yang berubah
+	DepartmentID string
+	// Payload adalah events.Envelope dalam JSON, sama dengan body webhook
+	Payload []byte
+}
+
+// Bus menyimpan BufferSize event terakhir di ring buffer untuk resume dan
+// mengirim event baru ke subscriber manager-nya. Subscriber yang antriannya
+// penuh diputus agar Publish tidak pernah menunggu client yang lambat.
+type Bus struct {
+	mu sync.Mutex
+	// ring berisi count event terakhir mulai dari start, dari yang terlama
+	ring  []Event
+	start int
+	count int
+	// ids berisi id event di ring, karena outbox bisa mengirim event yang
+	// sama lebih dari sekali
+	ids              map[int64]struct{}
+	subscribers      map[string]map[*Subscription]struct{}
+	subscriberBuffer int
+	closed           bool
+}
+
+func New(config *config.StreamConfig) *Bus {
+	return &Bus{
+		ring:             make([]Event, max(config.BufferSize, 1)),
+		ids:              map[int64]struct{}{},
+		subscribers:      map[string]map[*Subscription]struct{}{},
+		subscriberBuffer: max(config.SubscriberBuffer, 1),
+	}
+}
+
+func NewInject(i do.Injector) (*Bus, error) {
+	return New(config.LoadStreamConfig()), nil
+}
+
+// Publish menyimpan event dan meneruskannya ke subscriber manager-nya.
+// Event tanpa managerId diabaikan.
+func (b *Bus) Publish(ctx context.Context, subject string, payload []byte) error {
+	var message events.Envelope
+	if err := json.Unmarshal(payload, &message); err != nil {
+		return err
+	}
+	// Semua event employee.* dan department.* membawa managerId, lihat
+	// events.Employee dan events.Department
+	var data struct {
+		ManagerID    string `json:"managerId"`
+		DepartmentID string `json:"departmentId"`
+		Employee     struct {
+			DepartmentID string `json:"departmentId"`
+		} `json:"employee"`
+	}
+	if err := json.Unmarshal(message.Data, &data); err != nil || data.ManagerID == "" {
+		return nil
+	}
+	event := Event{
+		ID:           message.ID,
+		Type:         subject,
+		ManagerID:    data.ManagerID,
+		DepartmentID: data.DepartmentID,
+		Payload:      payload,
+	}
+	if event.DepartmentID == "" {
+		event.DepartmentID = data.Employee.DepartmentID
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil
+	}
+	if _, ok := b.ids[event.ID]; ok {
+		return nil
+	}
+	b.append(event)
+
+	for subscription := range b.subscribers[event.ManagerID] {
+		select {
+		case subscription.events <- event:
+		default:
+			// Client tertinggal; sisa event bisa diambil lagi dengan resume
+			b.remove(subscription)
+		}
+	}
+	return nil
+}
+
+func (b *Bus) append(event Event) {
+	if b.count == len(b.ring) {
+		delete(b.ids, b.ring[b.start].ID)
+		b.ring[b.start] = event
+		b.start = (b.start + 1) % len(b.ring)
+	} else {
+		b.ring[(b.start+b.count)%len(b.ring)] = event
+		b.count++
+	}
+	b.ids[event.ID] = struct{}{}
+}
+
+// Subscribe mendaftarkan subscriber untuk event managerID. Jika
+// lastEventID bukan 0, event manager tersebut yang diterima Bus setelah
+// lastEventID diisi ke Backlog; Missed bernilai true jika lastEventID sudah
+// tidak ada di buffer. Subscription harus ditutup dengan Close.
+func (b *Bus) Subscribe(managerID string, lastEventID int64) *Subscription {
+	subscription := &Subscription{bus: b, events: make(chan Event, b.subscriberBuffer)}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(subscription.events)
+		return subscription
+	}
+
+	if lastEventID != 0 {
+		found := false
+		for i := 0; i < b.count; i++ {
+			event := b.ring[(b.start+i)%len(b.ring)]
+			if found && event.ManagerID == managerID {
+				subscription.Backlog = append(subscription.Backlog, event)
+			}
+			if event.ID == lastEventID {
+				found = true
+			}
+		}
+		subscription.Missed = !found
+	}
+
+	subscription.managerID = managerID
+	if b.subscribers[managerID] == nil {
+		b.subscribers[managerID] = map[*Subscription]struct{}{}
+	}
+	b.subscribers[managerID][subscription] = struct{}{}
+	return subscription
+}
+
+// remove melepas subscriber dan menutup channel-nya. Dipanggil dengan mu
+// terkunci, satu-satunya tempat channel ditutup.
+func (b *Bus) remove(subscription *Subscription) {
+	subscribers := b.subscribers[subscription.managerID]
+	if _, ok := subscribers[subscription]; !ok {
+		return
+	}
+	delete(subscribers, subscription)
+	if len(subscribers) == 0 {
+		delete(b.subscribers, subscription.managerID)
+	}
+	close(subscription.events)
+}
+
+// Shutdown menutup semua subscriber, sehingga stream yang terbuka selesai
+// dan tidak menahan http.Server.Shutdown. Event berikutnya diabaikan.
+func (b *Bus) Shutdown() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for _, subscribers := range b.subscribers {
+		for subscription := range subscribers {
+			b.remove(subscription)
+		}
+	}
+}
+
+type Subscription struct {
+	bus       *Bus
+	managerID string
+	events    chan Event
+
+	Backlog []Event
+	Missed  bool
+}
+
+// Events ditutup setelah Close, saat Bus di-shutdown, atau jika subscriber
+// tertinggal terlalu jauh.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	s.bus.remove(s)
+}
@@ -0,0 +1,212 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/events"
+)
+
+const (
+	managerA = "0b7c2d2e-4f4a-4b8e-9c59-2f1d8f6a3e10"
+	managerB = "5d1f0c7a-2b3e-4c4d-8e9f-0a1b2c3d4e5f"
+)
+
+// publish mengirim event employee milik managerID ke b seperti dispatcher
+// outbox.
+func publish(t *testing.T, b *Bus, id int64, eventType, managerID string) {
+	t.Helper()
+	data, err := json.Marshal(events.Employee{
+		ManagerID: managerID,
+		Employee:  dto.EmployeeResponse{EmployeePayload: dto.EmployeePayload{IdentityNumber: "EMP-1", DepartmentID: "dept-1"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := json.Marshal(events.Envelope{ID: id, Type: eventType, Data: data})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Publish(context.Background(), eventType, payload); err != nil {
+		t.Fatalf("Publish(%d) error = %v", id, err)
+	}
+}
+
+// received mengambil event yang sudah ada di antrian tanpa menunggu.
+func received(s *Subscription) (ids []int64, open bool) {
+	for {
+		select {
+		case event, ok := <-s.Events():
+			if !ok {
+				return ids, false
+			}
+			ids = append(ids, event.ID)
+		default:
+			return ids, true
+		}
+	}
+}
+
+func eventIDs(events []Event) []int64 {
+	ids := make([]int64, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+	return ids
+}
+
+func TestPublishDeliversToManager(t *testing.T) {
+	b := New(&config.StreamConfig{BufferSize: 10, SubscriberBuffer: 10})
+	a := b.Subscribe(managerA, 0)
+	defer a.Close()
+	other := b.Subscribe(managerB, 0)
+	defer other.Close()
+
+	publish(t, b, 1, entity.EventEmployeeCreated, managerA)
+	publish(t, b, 2, entity.EventEmployeeUpdated, managerB)
+	// Outbox at-least-once: event yang sama hanya diteruskan sekali
+	publish(t, b, 1, entity.EventEmployeeCreated, managerA)
+
+	select {
+	case event := <-a.Events():
+		if event.ID != 1 || event.Type != entity.EventEmployeeCreated || event.ManagerID != managerA || event.DepartmentID != "dept-1" {
+			t.Fatalf("event = %+v", event)
+		}
+		var envelope events.Envelope
+		if err := json.Unmarshal(event.Payload, &envelope); err != nil || envelope.ID != 1 {
+			t.Fatalf("payload = %s, %v", event.Payload, err)
+		}
+	default:
+		t.Fatal("manager A did not receive its event")
+	}
+	if ids, _ := received(a); len(ids) != 0 {
+		t.Fatalf("manager A received %v after the first event, want nothing", ids)
+	}
+	if ids, _ := received(other); len(ids) != 1 || ids[0] != 2 {
+		t.Fatalf("manager B received %v, want [2]", ids)
+	}
+}
+
+func TestPublishIgnoresEventsWithoutManager(t *testing.T) {
+	b := New(&config.StreamConfig{BufferSize: 10, SubscriberBuffer: 10})
+	payload, _ := json.Marshal(events.Envelope{ID: 1, Type: "user.updated", Data: json.RawMessage(`{"userId":"u-1"}`)})
+	if err := b.Publish(context.Background(), "user.updated", payload); err != nil {
+		t.Fatalf("Publish error = %v", err)
+	}
+	if b.count != 0 {
+		t.Fatalf("buffered %d events without a manager", b.count)
+	}
+	if err := b.Publish(context.Background(), "employee.created", []byte("not json")); err == nil {
+		t.Fatal("Publish accepted a payload that is not an envelope")
+	}
+}
+
+func TestSubscribeResume(t *testing.T) {
+	b := New(&config.StreamConfig{BufferSize: 3, SubscriberBuffer: 10})
+	publish(t, b, 1, entity.EventEmployeeCreated, managerA)
+	publish(t, b, 2, entity.EventEmployeeCreated, managerB)
+	publish(t, b, 3, entity.EventEmployeeUpdated, managerA)
+	publish(t, b, 4, entity.EventEmployeeDeleted, managerA)
+
+	for _, tt := range []struct {
+		name        string
+		lastEventID int64
+		wantBacklog []int64
+		wantMissed  bool
+	}{
+		{name: "without resume", lastEventID: 0},
+		// Event manager lain setelah id 2 tidak ikut
+		{name: "from other manager event", lastEventID: 2, wantBacklog: []int64{3, 4}},
+		{name: "from latest", lastEventID: 4},
+		// Event 1 sudah tergeser dari ring buffer berukuran 3
+		{name: "evicted", lastEventID: 1, wantMissed: true},
+		{name: "unknown", lastEventID: 99, wantMissed: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s := b.Subscribe(managerA, tt.lastEventID)
+			defer s.Close()
+			if got := eventIDs(s.Backlog); !slices.Equal(got, tt.wantBacklog) {
+				t.Fatalf("Backlog = %v, want %v", got, tt.wantBacklog)
+			}
+			if s.Missed != tt.wantMissed {
+				t.Fatalf("Missed = %v, want %v", s.Missed, tt.wantMissed)
+			}
+		})
+	}
+
+	// Id yang tergeser bisa di-publish lagi, karena ids ikut dibersihkan
+	publish(t, b, 1, entity.EventEmployeeCreated, managerA)
+	if b.count != 3 || len(b.ids) != 3 {
+		t.Fatalf("ring count = %d, ids = %d, want 3", b.count, len(b.ids))
+	}
+}
+
+func TestSlowSubscriberIsDisconnected(t *testing.T) {
+	b := New(&config.StreamConfig{BufferSize: 10, SubscriberBuffer: 2})
+	slow := b.Subscribe(managerA, 0)
+	defer slow.Close()
+
+	for id := range int64(3) {
+		publish(t, b, id+1, entity.EventEmployeeCreated, managerA)
+	}
+	// Dua event masuk antrian, event ketiga memutus subscriber
+	if ids, open := received(slow); open || len(ids) != 2 {
+		t.Fatalf("received %v, open = %v, want 2 events and a closed channel", ids, open)
+	}
+	if len(b.subscribers) != 0 {
+		t.Fatalf("subscribers = %v after disconnecting the slow client", b.subscribers)
+	}
+	// Client melanjutkan dengan resume dari event terakhir yang diterima
+	resumed := b.Subscribe(managerA, 2)
+	defer resumed.Close()
+	if got := eventIDs(resumed.Backlog); len(got) != 1 || got[0] != 3 {
+		t.Fatalf("resumed Backlog = %v, want [3]", got)
+	}
+}
+
+func TestCloseReleasesSubscription(t *testing.T) {
+	b := New(&config.StreamConfig{BufferSize: 10, SubscriberBuffer: 10})
+	first := b.Subscribe(managerA, 0)
+	second := b.Subscribe(managerA, 0)
+
+	first.Close()
+	first.Close()
+	if _, open := received(first); open {
+		t.Fatal("Events is still open after Close")
+	}
+	publish(t, b, 1, entity.EventEmployeeCreated, managerA)
+	if ids, open := received(second); !open || len(ids) != 1 {
+		t.Fatalf("second subscriber received %v, open = %v", ids, open)
+	}
+	second.Close()
+	if len(b.subscribers) != 0 {
+		t.Fatalf("subscribers = %v after every subscription was closed", b.subscribers)
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	b := New(&config.StreamConfig{BufferSize: 10, SubscriberBuffer: 10})
+	open := b.Subscribe(managerA, 0)
+	b.Shutdown()
+
+	if _, ok := received(open); ok {
+		t.Fatal("Events is still open after Shutdown")
+	}
+	// Close setelah Shutdown tidak menutup channel dua kali
+	open.Close()
+
+	late := b.Subscribe(managerA, 0)
+	if _, ok := received(late); ok {
+		t.Fatal("Subscribe after Shutdown returned an open subscription")
+	}
+	late.Close()
+	publish(t, b, 1, entity.EventEmployeeCreated, managerA)
+	if b.count != 0 {
+		t.Fatalf("buffered %d events after Shutdown", b.count)
+	}
+}
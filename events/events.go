@@ -0,0 +1,74 @@
+// Package events berisi skema event domain yang dikirim outbox ke Publisher
+// (log, NATS, webhook).
+//
+// Setiap event dikirim sebagai Envelope dalam JSON:
+//
+//	{
+//	  "id": 42,                       // id outbox, naik per event; dedupe dengan ini
+//	  "type": "employee.updated",     // lihat entity.Event*
+//	  "version": 1,                   // versi skema Envelope dan Data
+//	  "aggregateType": "employee",    // employee atau department
+//	  "aggregateId": "EMP-001",       // identityNumber atau departmentId
+//	  "createdAt": "2024-01-02T03:04:05Z",
+//	  "data": { ... }                 // Employee atau Department
+//	}
+//
+// Event dengan aggregate yang sama dikirim berurutan sesuai id. Perubahan
+// yang tidak kompatibel (field dihapus atau berganti arti) menaikkan
+// Version; field baru boleh ditambahkan tanpa menaikkan Version, sehingga
+// consumer harus mengabaikan field yang tidak dikenal.
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+)
+
+// Version adalah versi skema event yang sedang dikirim.
+const Version = 1
+
+type Envelope struct {
+	ID            int64           `json:"id"`
+	Type          string          `json:"type"`
+	Version       int             `json:"version"`
+	AggregateType string          `json:"aggregateType"`
+	AggregateID   string          `json:"aggregateId"`
+	CreatedAt     time.Time       `json:"createdAt"`
+	Data          json.RawMessage `json:"data"`
+}
+
+func NewEnvelope(event entity.OutboxEvent) Envelope {
+	return Envelope{
+		ID:            event.Id,
+		Type:          event.EventType,
+		Version:       Version,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateId,
+		CreatedAt:     event.CreatedAt,
+		Data:          event.Payload,
+	}
+}
+
+// Employee adalah Data event employee.created, employee.updated, dan
+// employee.deleted. Pada employee.deleted, Employee berisi data terakhir
+// sebelum dihapus.
+type Employee struct {
+	ManagerID string `json:"managerId"`
+	// PreviousIdentityNumber diisi pada employee.updated jika identityNumber
+	// ikut berubah. Aggregate id event adalah identityNumber sebelum update.
+	PreviousIdentityNumber string               `json:"previousIdentityNumber,omitempty"`
+	Employee               dto.EmployeeResponse `json:"employee"`
+}
+
+// Department adalah Data event department.created, department.updated, dan
+// department.deleted.
+type Department struct {
+	ManagerID      string `json:"managerId"`
+	DepartmentID   string `json:"departmentId"`
+	DepartmentName string `json:"name,omitempty"`
+	// MovedTo diisi pada department.deleted jika employee-nya dipindahkan
+	MovedTo string `json:"movedTo,omitempty"`
+}
@@ -0,0 +1,128 @@
+package featureflag
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/levensspel/go-gin-template/clock"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/lru"
+	"github.com/levensspel/go-gin-template/metrics"
+	featureFlagRepository "github.com/levensspel/go-gin-template/repository/featureflag"
+	"github.com/samber/do/v2"
+)
+
+type Flag string
+
+// Flag yang dikenal. Flag baru harus ditambahkan ke Known agar bisa
+// di-override lewat endpoint admin.
+const (
+	CursorPagination Flag = "cursor_pagination"
+	FuzzySearch      Flag = "fuzzy_search"
+	ResponseV2       Flag = "response_v2"
+)
+
+var Known = []Flag{CursorPagination, FuzzySearch, ResponseV2}
+
+func IsKnown(flag Flag) bool {
+	return slices.Contains(Known, flag)
+}
+
+// FeatureFlags menentukan apakah sebuah fitur aktif untuk manager tertentu:
+// override per manager jika ada, selain itu default dari config.
+type FeatureFlags interface {
+	IsEnabled(ctx context.Context, flag Flag, managerId string) bool
+	// SetOverride menyimpan override manager; enabled nil menghapusnya
+	// sehingga manager kembali memakai default.
+	SetOverride(ctx context.Context, flag Flag, managerId string, enabled *bool) error
+}
+
+type flags struct {
+	repo     featureFlagRepository.FeatureFlagRepositoryInterface
+	defaults map[Flag]bool
+	logger   logger.Logger
+	// overrides menyimpan semua override satu manager, per manager
+	overrides *lru.Cache[string, map[string]bool]
+	// unknown mencatat flag tidak dikenal yang sudah di-log
+	unknown sync.Map
+}
+
+func New(
+	repo featureFlagRepository.FeatureFlagRepositoryInterface,
+	config *config.FeatureFlagConfig,
+	overrides *lru.Cache[string, map[string]bool],
+	logger logger.Logger,
+) FeatureFlags {
+	defaults := make(map[Flag]bool, len(config.Enabled))
+	for _, name := range config.Enabled {
+		defaults[Flag(name)] = true
+	}
+	return &flags{repo: repo, defaults: defaults, logger: logger, overrides: overrides}
+}
+
+func NewInject(i do.Injector) (FeatureFlags, error) {
+	_repo := do.MustInvoke[featureFlagRepository.FeatureFlagRepositoryInterface](i)
+	_logger := do.MustInvoke[logger.LogHandler](i)
+	_metrics := do.MustInvoke[*metrics.Metrics](i)
+	flagConfig := config.LoadFeatureFlagConfig()
+	overrides := lru.New[string, map[string]bool](lru.Settings{
+		Name:       "feature_flag_override",
+		MaxEntries: config.LoadCacheConfig().MaxEntries,
+		TTL:        flagConfig.CacheTTL,
+		OnHit:      _metrics.CountCacheHit,
+		OnMiss:     _metrics.CountCacheMiss,
+		OnEvict:    _metrics.CountCacheEvictions,
+		Clock:      do.MustInvoke[clock.Clock](i),
+	})
+	return New(_repo, flagConfig, overrides, &_logger), nil
+}
+
+// IsEnabled tidak pernah gagal: flag tidak dikenal bernilai false, dan jika
+// override tidak bisa dibaca dipakai default dari config.
+func (f *flags) IsEnabled(ctx context.Context, flag Flag, managerId string) bool {
+	if !IsKnown(flag) {
+		if _, logged := f.unknown.LoadOrStore(flag, struct{}{}); !logged {
+			f.logger.Warn(fmt.Sprintf("Unknown feature flag %q, treated as disabled", flag), helper.FeatureFlags)
+		}
+		return false
+	}
+	if managerId == "" {
+		return f.defaults[flag]
+	}
+
+	overrides, err := f.overrides.GetOrLoad(managerId, func() (map[string]bool, error) {
+		// Hasilnya di-cache untuk pemanggil lain, tidak ikut batal bersama request ini
+		return f.repo.GetOverrides(context.WithoutCancel(ctx), managerId)
+	})
+	if err != nil {
+		f.logger.Warn(fmt.Sprintf("Failed to load feature flag overrides: %v", err), helper.FeatureFlags, managerId)
+		return f.defaults[flag]
+	}
+	if enabled, ok := overrides[string(flag)]; ok {
+		return enabled
+	}
+	return f.defaults[flag]
+}
+
+func (f *flags) SetOverride(ctx context.Context, flag Flag, managerId string, enabled *bool) error {
+	if !IsKnown(flag) {
+		return helper.ErrNotFound
+	}
+
+	var err error
+	if enabled == nil {
+		err = f.repo.DeleteOverride(ctx, string(flag), managerId)
+	} else {
+		err = f.repo.SetOverride(ctx, string(flag), managerId, *enabled)
+	}
+	if err != nil {
+		return err
+	}
+	// Instance lain melihat perubahan setelah FEATURE_FLAG_CACHE_TTL
+	f.overrides.Invalidate(managerId)
+	return nil
+}
@@ -0,0 +1,235 @@
+package featureflag_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/clock/clocktest"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/featureflag"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/lru"
+	"github.com/levensspel/go-gin-template/mocks"
+)
+
+const (
+	managerA = "0b7c2d2e-4f4a-4b8e-9c59-2f1d8f6a3e10"
+	managerB = "7d3e9a61-2c4b-4f1e-8a5d-6b0c1e2f3a44"
+)
+
+// overrideStore adalah tabel override di memori. loads menghitung
+// GetOverrides ke repository.
+type overrideStore struct {
+	mu        sync.Mutex
+	overrides map[string]map[string]bool
+	loads     int
+	err       error
+}
+
+func (s *overrideStore) repository() *mocks.FeatureFlagRepository {
+	return &mocks.FeatureFlagRepository{
+		GetOverridesFunc: func(ctx context.Context, managerId string) (map[string]bool, error) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.loads++
+			if s.err != nil {
+				return nil, s.err
+			}
+			overrides := map[string]bool{}
+			for flag, enabled := range s.overrides[managerId] {
+				overrides[flag] = enabled
+			}
+			return overrides, nil
+		},
+		SetOverrideFunc: func(ctx context.Context, flag, managerId string, enabled bool) error {
+			s.set(flag, managerId, enabled)
+			return nil
+		},
+		DeleteOverrideFunc: func(ctx context.Context, flag, managerId string) error {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			delete(s.overrides[managerId], flag)
+			return nil
+		},
+	}
+}
+
+// set mengubah tabel langsung, seperti instance lain yang mengubah override.
+func (s *overrideStore) set(flag, managerId string, enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.overrides == nil {
+		s.overrides = map[string]map[string]bool{}
+	}
+	if s.overrides[managerId] == nil {
+		s.overrides[managerId] = map[string]bool{}
+	}
+	s.overrides[managerId][flag] = enabled
+}
+
+func (s *overrideStore) Loads() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.loads
+}
+
+// warnLogger menghitung log Warn.
+type warnLogger struct {
+	mocks.Logger
+	mu    sync.Mutex
+	warns int
+}
+
+func (l *warnLogger) Warn(msg string, function helper.FunctionCaller, data ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.warns++
+}
+
+func (l *warnLogger) With(fields map[string]any) logger.Logger {
+	return l
+}
+
+func (l *warnLogger) WithContext(ctx context.Context) logger.Logger {
+	return l
+}
+
+func newFlags(store *overrideStore, log logger.Logger, ttl time.Duration, enabled ...string) featureflag.FeatureFlags {
+	overrides := lru.New[string, map[string]bool](lru.Settings{Name: "feature_flag_override", MaxEntries: 10, TTL: ttl})
+	return featureflag.New(store.repository(), &config.FeatureFlagConfig{Enabled: enabled, CacheTTL: ttl}, overrides, log)
+}
+
+func TestIsEnabledDefaults(t *testing.T) {
+	flags := newFlags(&overrideStore{}, mocks.Logger{}, time.Minute, string(featureflag.FuzzySearch))
+	for _, tt := range []struct {
+		flag      featureflag.Flag
+		managerId string
+		want      bool
+	}{
+		{flag: featureflag.FuzzySearch, managerId: managerA, want: true},
+		{flag: featureflag.CursorPagination, managerId: managerA, want: false},
+		// Tanpa manager tidak ada override yang dibaca
+		{flag: featureflag.FuzzySearch, managerId: "", want: true},
+		{flag: featureflag.ResponseV2, managerId: "", want: false},
+	} {
+		if got := flags.IsEnabled(context.Background(), tt.flag, tt.managerId); got != tt.want {
+			t.Errorf("IsEnabled(%s, %q) = %t, want %t", tt.flag, tt.managerId, got, tt.want)
+		}
+	}
+}
+
+func TestIsEnabledOverride(t *testing.T) {
+	store := &overrideStore{}
+	store.set(string(featureflag.CursorPagination), managerA, true)
+	store.set(string(featureflag.FuzzySearch), managerA, false)
+	flags := newFlags(store, mocks.Logger{}, time.Minute, string(featureflag.FuzzySearch))
+
+	for _, tt := range []struct {
+		flag      featureflag.Flag
+		managerId string
+		want      bool
+	}{
+		{flag: featureflag.CursorPagination, managerId: managerA, want: true},
+		{flag: featureflag.FuzzySearch, managerId: managerA, want: false},
+		// Manager lain tetap memakai default
+		{flag: featureflag.CursorPagination, managerId: managerB, want: false},
+		{flag: featureflag.FuzzySearch, managerId: managerB, want: true},
+	} {
+		if got := flags.IsEnabled(context.Background(), tt.flag, tt.managerId); got != tt.want {
+			t.Errorf("IsEnabled(%s, %s) = %t, want %t", tt.flag, tt.managerId, got, tt.want)
+		}
+	}
+	// Semua override satu manager dibaca sekali
+	if got := store.Loads(); got != 2 {
+		t.Fatalf("GetOverrides called %d times, want once per manager", got)
+	}
+}
+
+func TestIsEnabledCacheExpiry(t *testing.T) {
+	store := &overrideStore{}
+	const ttl = time.Minute
+	fake := clocktest.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	overrides := lru.New[string, map[string]bool](lru.Settings{Name: "feature_flag_override", MaxEntries: 10, TTL: ttl, Clock: fake})
+	flags := featureflag.New(store.repository(), &config.FeatureFlagConfig{CacheTTL: ttl}, overrides, mocks.Logger{})
+
+	if flags.IsEnabled(context.Background(), featureflag.ResponseV2, managerA) {
+		t.Fatal("IsEnabled = true before any override")
+	}
+	// Override dari instance lain belum terlihat selama cache masih berlaku
+	store.set(string(featureflag.ResponseV2), managerA, true)
+	if flags.IsEnabled(context.Background(), featureflag.ResponseV2, managerA) {
+		t.Fatal("IsEnabled saw a new override before the cache expired")
+	}
+	fake.Advance(ttl)
+	if !flags.IsEnabled(context.Background(), featureflag.ResponseV2, managerA) {
+		t.Fatal("IsEnabled did not see the override after the cache expired")
+	}
+	if got := store.Loads(); got != 2 {
+		t.Fatalf("GetOverrides called %d times, want 2", got)
+	}
+}
+
+func TestIsEnabledUnknownFlagLogsOnce(t *testing.T) {
+	store := &overrideStore{}
+	log := &warnLogger{}
+	flags := newFlags(store, log, time.Minute, "typo_flag")
+
+	for range 3 {
+		if flags.IsEnabled(context.Background(), "typo_flag", managerA) {
+			t.Fatal("unknown flag is enabled")
+		}
+	}
+	if log.warns != 1 {
+		t.Fatalf("unknown flag logged %d times, want 1", log.warns)
+	}
+	if store.Loads() != 0 {
+		t.Fatal("overrides were loaded for an unknown flag")
+	}
+}
+
+func TestIsEnabledFallsBackToDefaultOnError(t *testing.T) {
+	store := &overrideStore{err: errors.New("database unavailable")}
+	flags := newFlags(store, mocks.Logger{}, time.Minute, string(featureflag.FuzzySearch))
+	if !flags.IsEnabled(context.Background(), featureflag.FuzzySearch, managerA) {
+		t.Fatal("IsEnabled = false, want the default when overrides cannot be loaded")
+	}
+	// Error tidak di-cache, jadi percobaan berikutnya membaca lagi
+	store.mu.Lock()
+	store.err = nil
+	store.mu.Unlock()
+	store.set(string(featureflag.FuzzySearch), managerA, false)
+	if flags.IsEnabled(context.Background(), featureflag.FuzzySearch, managerA) {
+		t.Fatal("IsEnabled kept the default after overrides became readable")
+	}
+}
+
+func TestSetOverride(t *testing.T) {
+	store := &overrideStore{}
+	flags := newFlags(store, mocks.Logger{}, time.Minute)
+	ctx := context.Background()
+
+	if flags.IsEnabled(ctx, featureflag.CursorPagination, managerA) {
+		t.Fatal("IsEnabled = true before any override")
+	}
+	enabled := true
+	if err := flags.SetOverride(ctx, featureflag.CursorPagination, managerA, &enabled); err != nil {
+		t.Fatalf("SetOverride error = %v", err)
+	}
+	// Cache instance ini langsung di-invalidate
+	if !flags.IsEnabled(ctx, featureflag.CursorPagination, managerA) {
+		t.Fatal("IsEnabled = false right after enabling the override")
+	}
+	if err := flags.SetOverride(ctx, featureflag.CursorPagination, managerA, nil); err != nil {
+		t.Fatalf("SetOverride(nil) error = %v", err)
+	}
+	if flags.IsEnabled(ctx, featureflag.CursorPagination, managerA) {
+		t.Fatal("IsEnabled = true after removing the override")
+	}
+	if err := flags.SetOverride(ctx, "typo_flag", managerA, &enabled); !errors.Is(err, helper.ErrNotFound) {
+		t.Fatalf("SetOverride unknown flag error = %v, want %v", err, helper.ErrNotFound)
+	}
+}
@@ -0,0 +1,89 @@
+package graph
+
+import (
+	"context"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/levensspel/go-gin-template/graph/model"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const errDepthLimit = "DEPTH_LIMIT_EXCEEDED"
+
+// DepthLimit menolak operasi yang field-nya bersarang lebih dalam dari
+// Limit, melengkapi extension.ComplexityLimit yang tidak membatasi
+// kedalaman. Field introspection tidak dihitung.
+type DepthLimit struct {
+	Limit int
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = DepthLimit{}
+
+func (DepthLimit) ExtensionName() string {
+	return "DepthLimit"
+}
+
+func (DepthLimit) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (d DepthLimit) MutateOperationContext(ctx context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	if depth := selectionDepth(opCtx.Operation.SelectionSet); depth > d.Limit {
+		err := gqlerror.Errorf("operation has depth %d, which exceeds the limit of %d", depth, d.Limit)
+		errcode.Set(err, errDepthLimit)
+		return err
+	}
+	return nil
+}
+
+// selectionDepth menghitung kedalaman terbesar selections. Fragment
+// dihitung di level tempat ia dipakai; siklus fragment sudah ditolak
+// validasi sebelum extension dijalankan.
+func selectionDepth(selections ast.SelectionSet) int {
+	depth := 0
+	for _, selection := range selections {
+		var d int
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(selection.Name, "__") {
+				continue
+			}
+			d = 1 + selectionDepth(selection.SelectionSet)
+		case *ast.InlineFragment:
+			d = selectionDepth(selection.SelectionSet)
+		case *ast.FragmentSpread:
+			d = selectionDepth(selection.Definition.SelectionSet)
+		}
+		depth = max(depth, d)
+	}
+	return depth
+}
+
+// complexity menghitung field list sebagai limit dikali kompleksitas
+// field di dalamnya, sehingga limit besar ikut dibatasi MaxComplexity.
+func complexity() ComplexityRoot {
+	var root ComplexityRoot
+	root.Query.Departments = func(childComplexity int, limit int, offset int, name *string) int {
+		return pageComplexity(childComplexity, limit)
+	}
+	root.Query.Employees = func(childComplexity int, filter *model.EmployeeFilter, limit int, offset int) int {
+		return pageComplexity(childComplexity, limit)
+	}
+	root.Manager.Departments = func(childComplexity int, limit int, offset int, name *string) int {
+		return pageComplexity(childComplexity, limit)
+	}
+	root.Department.Employees = func(childComplexity int, limit int, offset int) int {
+		return pageComplexity(childComplexity, limit)
+	}
+	return root
+}
+
+func pageComplexity(childComplexity int, limit int) int {
+	return 1 + max(limit, 1)*childComplexity
+}
@@ -0,0 +1,112 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/graph/model"
+	"github.com/vikstrous/dataloadgen"
+)
+
+// loaderWait adalah jeda mengumpulkan key sebelum satu batch dijalankan.
+// Resolver satu level dijalankan bersamaan, sehingga jeda singkat cukup
+// untuk menggabungkan semuanya.
+const loaderWait = 2 * time.Millisecond
+
+type loadersKey struct{}
+
+// Loaders menggabungkan lookup per department dari resolver yang berjalan
+// bersamaan menjadi satu query, sehingga 50 employee dengan department-nya
+// hanya butuh satu query department. Dibuat baru untuk setiap request agar
+// cache-nya tidak bocor antar manager.
+type Loaders struct {
+	department    *dataloadgen.Loader[string, *model.Department]
+	employeeCount *dataloadgen.Loader[string, int]
+	employees     *dataloadgen.Loader[employeePage, []*dto.EmployeeResponse]
+}
+
+// employeePage adalah satu halaman employee sebuah department.
+type employeePage struct {
+	departmentID string
+	limit        int
+	offset       int
+}
+
+// NewLoaders membuat Loaders untuk satu request milik managerID.
+func (r *Resolver) NewLoaders(managerID string) *Loaders {
+	return &Loaders{
+		department: dataloadgen.NewLoader(func(ctx context.Context, ids []string) ([]*model.Department, []error) {
+			departments, err := r.departments.GetByIDs(ctx, managerID, ids)
+			if err != nil {
+				return nil, []error{err}
+			}
+			byID := make(map[string]*model.Department, len(departments))
+			for _, department := range departments {
+				byID[department.DepartmentID] = toDepartment(department)
+			}
+			results := make([]*model.Department, len(ids))
+			for i, id := range ids {
+				results[i] = byID[id]
+			}
+			return results, nil
+		}, dataloadgen.WithWait(loaderWait)),
+
+		employeeCount: dataloadgen.NewLoader(func(ctx context.Context, ids []string) ([]int, []error) {
+			counts, err := r.departments.CountEmployees(ctx, managerID, ids)
+			if err != nil {
+				return nil, []error{err}
+			}
+			results := make([]int, len(ids))
+			for i, id := range ids {
+				results[i] = counts[id]
+			}
+			return results, nil
+		}, dataloadgen.WithWait(loaderWait)),
+
+		employees: dataloadgen.NewLoader(func(ctx context.Context, pages []employeePage) ([][]*dto.EmployeeResponse, []error) {
+			return r.loadEmployeePages(ctx, managerID, pages)
+		}, dataloadgen.WithWait(loaderWait)),
+	}
+}
+
+// loadEmployeePages menjalankan satu query per kombinasi limit dan offset;
+// biasanya semua halaman dalam satu batch memakai argumen yang sama.
+func (r *Resolver) loadEmployeePages(ctx context.Context, managerID string, pages []employeePage) ([][]*dto.EmployeeResponse, []error) {
+	type pageArgs struct{ limit, offset int }
+	departmentIDs := map[pageArgs][]string{}
+	for _, page := range pages {
+		args := pageArgs{page.limit, page.offset}
+		departmentIDs[args] = append(departmentIDs[args], page.departmentID)
+	}
+
+	byPage := make(map[employeePage][]*dto.EmployeeResponse, len(pages))
+	for args, ids := range departmentIDs {
+		employees, err := r.employees.GetAllByDepartments(ctx, managerID, ids, args.limit, args.offset)
+		if err != nil {
+			return nil, []error{err}
+		}
+		for i := range employees {
+			page := employeePage{employees[i].DepartmentID, args.limit, args.offset}
+			byPage[page] = append(byPage[page], &employees[i])
+		}
+	}
+
+	results := make([][]*dto.EmployeeResponse, len(pages))
+	for i, page := range pages {
+		results[i] = byPage[page]
+		if results[i] == nil {
+			results[i] = []*dto.EmployeeResponse{}
+		}
+	}
+	return results, nil
+}
+
+// WithLoaders menyimpan loaders di context request.
+func WithLoaders(ctx context.Context, loaders *Loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, loaders)
+}
+
+func loadersFor(ctx context.Context) *Loaders {
+	return ctx.Value(loadersKey{}).(*Loaders)
+}
@@ -0,0 +1,83 @@
+package graph
+
+// Jalankan ulang setelah mengubah schema.graphqls atau gqlgen.yml.
+//go:generate go run github.com/99designs/gqlgen generate
+
+import (
+	"context"
+
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/graph/model"
+	"github.com/levensspel/go-gin-template/helper"
+	departmentService "github.com/levensspel/go-gin-template/service/department"
+	employeeService "github.com/levensspel/go-gin-template/service/employee"
+	userService "github.com/levensspel/go-gin-template/service/user"
+	"github.com/samber/do/v2"
+)
+
+// Resolver menjawab query GraphQL lewat service yang sama dengan handler
+// REST. Semua query dibatasi ke manager dari context, lihat managerID.
+type Resolver struct {
+	employees   employeeService.EmployeeService
+	departments departmentService.DepartmentService
+	users       userService.IUserService
+}
+
+func NewResolver(
+	employees employeeService.EmployeeService,
+	departments departmentService.DepartmentService,
+	users userService.IUserService,
+) *Resolver {
+	return &Resolver{employees: employees, departments: departments, users: users}
+}
+
+func NewResolverInject(i do.Injector) (*Resolver, error) {
+	return NewResolver(
+		do.MustInvoke[employeeService.EmployeeService](i),
+		do.MustInvoke[departmentService.DepartmentService](i),
+		do.MustInvoke[userService.IUserService](i),
+	), nil
+}
+
+// managerID mengembalikan id manager yang diisi handler GraphQL dari
+// middleware.Authorization.
+func managerID(ctx context.Context) (string, error) {
+	id, ok := ctx.Value(helper.ContextKeyUserID).(string)
+	if !ok || id == "" {
+		return "", helper.ErrUnauthorized
+	}
+	return id, nil
+}
+
+// checkPage menolak limit atau offset negatif, yang juga akan membuat
+// kompleksitas query negatif.
+func checkPage(limit int, offset int) error {
+	if limit < 0 || offset < 0 {
+		return helper.ErrBadRequest
+	}
+	return nil
+}
+
+func toDepartment(department dto.ResponseSingleDepartment) *model.Department {
+	return &model.Department{ID: department.DepartmentID, Name: department.DepartmentName}
+}
+
+// emptyFilters mengembalikan nama filter yang dikirim sebagai string
+// kosong, untuk dto.GetEmployeesRequest.EmptyFilters.
+func emptyFilters(filters map[string]*string) []string {
+	var names []string
+	for name, s := range filters {
+		if s != nil && *s == "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// value mengembalikan isi argumen opsional, atau string kosong.
+func value(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
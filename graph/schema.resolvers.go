@@ -0,0 +1,170 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.66
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/graph/model"
+	"github.com/levensspel/go-gin-template/validation"
+)
+
+// EmployeeCount is the resolver for the employeeCount field.
+func (r *departmentResolver) EmployeeCount(ctx context.Context, obj *model.Department) (int, error) {
+	return loadersFor(ctx).employeeCount.Load(ctx, obj.ID)
+}
+
+// Employees is the resolver for the employees field.
+func (r *departmentResolver) Employees(ctx context.Context, obj *model.Department, limit int, offset int) (*model.EmployeeConnection, error) {
+	if err := checkPage(limit, offset); err != nil {
+		return nil, err
+	}
+	loaders := loadersFor(ctx)
+	// Kedua loader di-load bersamaan agar ikut batch yang sama dengan
+	// department lain di level ini
+	nodes := loaders.employees.LoadThunk(ctx, employeePage{departmentID: obj.ID, limit: limit, offset: offset})
+	totalCount := loaders.employeeCount.LoadThunk(ctx, obj.ID)
+
+	connection := &model.EmployeeConnection{}
+	var err error
+	if connection.Nodes, err = nodes(); err != nil {
+		return nil, err
+	}
+	if connection.TotalCount, err = totalCount(); err != nil {
+		return nil, err
+	}
+	return connection, nil
+}
+
+// Department is the resolver for the department field.
+func (r *employeeResolver) Department(ctx context.Context, obj *dto.EmployeeResponse) (*model.Department, error) {
+	return loadersFor(ctx).department.Load(ctx, obj.DepartmentID)
+}
+
+// Departments is the resolver for the departments field.
+func (r *managerResolver) Departments(ctx context.Context, obj *model.Manager, limit int, offset int, name *string) ([]*model.Department, error) {
+	return r.Query().Departments(ctx, limit, offset, name)
+}
+
+// Me is the resolver for the me field.
+func (r *queryResolver) Me(ctx context.Context) (*model.Manager, error) {
+	id, err := managerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := r.users.GetProfile(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Manager{
+		Email:           profile.Email,
+		Name:            profile.Name,
+		UserImageURI:    profile.UserImageUri,
+		CompanyName:     profile.CompanyName,
+		CompanyImageURI: profile.CompanyImageUri,
+	}, nil
+}
+
+// Departments is the resolver for the departments field.
+func (r *queryResolver) Departments(ctx context.Context, limit int, offset int, name *string) ([]*model.Department, error) {
+	id, err := managerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPage(limit, offset); err != nil {
+		return nil, err
+	}
+	// Sama dengan GET /v1/department: name dicari sebagai bagian nama
+	input := dto.RequestDepartment{DepartmentName: "%", Limit: limit, Offset: offset}
+	if name != nil && *name != "" {
+		input.DepartmentName = fmt.Sprintf("%%%s%%", *name)
+	}
+	departments, err := r.departments.GetAll(ctx, id, input)
+	if err != nil {
+		return nil, err
+	}
+
+	loaders := loadersFor(ctx)
+	results := make([]*model.Department, len(departments))
+	for i, department := range departments {
+		results[i] = toDepartment(department)
+		loaders.department.Prime(department.DepartmentID, results[i])
+	}
+	return results, nil
+}
+
+// Department is the resolver for the department field.
+func (r *queryResolver) Department(ctx context.Context, id string) (*model.Department, error) {
+	if _, err := managerID(ctx); err != nil {
+		return nil, err
+	}
+	return loadersFor(ctx).department.Load(ctx, id)
+}
+
+// Employees is the resolver for the employees field.
+func (r *queryResolver) Employees(ctx context.Context, filter *model.EmployeeFilter, limit int, offset int) ([]*dto.EmployeeResponse, error) {
+	id, err := managerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPage(limit, offset); err != nil {
+		return nil, err
+	}
+	if filter == nil {
+		filter = &model.EmployeeFilter{}
+	}
+	// Filter dinormalisasi ValidateEmployeeGet, sama dengan GET /v1/employee
+	input := dto.GetEmployeesRequest{
+		Limit:          limit,
+		Offset:         offset,
+		IdentityNumber: value(filter.IdentityNumber),
+		Name:           value(filter.Name),
+		Query:          value(filter.Query),
+		Gender:         value(filter.Gender),
+		DepartmentID:   value(filter.DepartmentID),
+		ManagerID:      id,
+		SortBy:         value(filter.SortBy),
+		EmptyFilters: emptyFilters(map[string]*string{
+			"gender":         filter.Gender,
+			"identityNumber": filter.IdentityNumber,
+			"name":           filter.Name,
+			"q":              filter.Query,
+			"departmentId":   filter.DepartmentID,
+			"sortBy":         filter.SortBy,
+		}),
+	}
+	if err := validation.ValidateEmployeeGet(&input); err != nil {
+		return nil, err
+	}
+
+	employees, err := r.employees.GetAll(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*dto.EmployeeResponse, len(employees))
+	for i := range employees {
+		results[i] = &employees[i]
+	}
+	return results, nil
+}
+
+// Department returns DepartmentResolver implementation.
+func (r *Resolver) Department() DepartmentResolver { return &departmentResolver{r} }
+
+// Employee returns EmployeeResolver implementation.
+func (r *Resolver) Employee() EmployeeResolver { return &employeeResolver{r} }
+
+// Manager returns ManagerResolver implementation.
+func (r *Resolver) Manager() ManagerResolver { return &managerResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+type departmentResolver struct{ *Resolver }
+type employeeResolver struct{ *Resolver }
+type managerResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
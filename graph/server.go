@@ -0,0 +1,81 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/reporting"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// NewServer membuat handler GraphQL query-only (GET dan POST) dengan batas
+// kedalaman dan kompleksitas dari config. Context request harus berisi
+// manager id dan Loaders, lihat WithLoaders.
+func NewServer(resolver *Resolver, config *config.GraphQLConfig, log logger.Logger) *handler.Server {
+	srv := handler.New(NewExecutableSchema(Config{
+		Resolvers:  resolver,
+		Complexity: complexity(),
+	}))
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+
+	if config.Introspection {
+		srv.Use(extension.Introspection{})
+	}
+	srv.Use(DepthLimit{Limit: config.MaxDepth})
+	srv.Use(extension.FixedComplexityLimit(config.MaxComplexity))
+
+	srv.SetErrorPresenter(func(ctx context.Context, err error) *gqlerror.Error {
+		return presentError(ctx, err, log)
+	})
+	srv.SetRecoverFunc(func(ctx context.Context, recovered any) error {
+		err, ok := recovered.(error)
+		if !ok {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+		fields := logger.RequestFields(ctx, helper.GraphQLHandler)
+		fields["panic"] = true
+		if fieldCtx := graphql.GetFieldContext(ctx); fieldCtx != nil {
+			fields["path"] = fieldCtx.Path().String()
+		}
+		reporting.Default().Report(ctx, err, fields)
+		return helper.ErrInternalServer
+	})
+	return srv
+}
+
+// presentError memakai pesan dan status yang sama dengan response REST,
+// lihat helper.FromError: status ada di extensions.code dan pesan per
+// field di extensions.fields. Error dari gqlgen sendiri (parse, validasi,
+// batas query) dikirim apa adanya.
+func presentError(ctx context.Context, err error, log logger.Logger) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+	cause := gqlErr.Unwrap()
+	var nested *gqlerror.Error
+	if cause == nil || errors.As(cause, &nested) {
+		return gqlErr
+	}
+
+	status, response := helper.FromError(cause)
+	if status >= http.StatusInternalServerError {
+		log.With(logger.RequestFields(ctx, helper.GraphQLHandler)).WithContext(ctx).Error(cause.Error(), logger.Bound)
+	}
+	gqlErr.Message = response.Errors.Message
+	gqlErr.Extensions = map[string]any{"code": status}
+	if response.Errors.Fields != nil {
+		gqlErr.Extensions["fields"] = response.Errors.Fields
+	}
+	return gqlErr
+}
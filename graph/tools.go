@@ -0,0 +1,6 @@
+//go:build tools
+
+// Mengunci versi gqlgen di go.mod, lihat go:generate di resolver.go.
+package graph
+
+import _ "github.com/99designs/gqlgen"
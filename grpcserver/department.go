@@ -0,0 +1,81 @@
+package grpcserver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/levensspel/go-gin-template/dto"
+	ps3tv1 "github.com/levensspel/go-gin-template/proto/ps3t/v1"
+	service "github.com/levensspel/go-gin-template/service/department"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// defaultDepartmentLimit sama dengan limit default GET /v1/department.
+const defaultDepartmentLimit = 5
+
+type departmentServer struct {
+	ps3tv1.UnimplementedDepartmentServiceServer
+	service service.DepartmentService
+}
+
+func (s *departmentServer) CreateDepartment(ctx context.Context, req *ps3tv1.CreateDepartmentRequest) (*ps3tv1.Department, error) {
+	department, err := s.service.Create(ctx, managerID(ctx), dto.RequestDepartment{DepartmentName: req.GetName()})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toDepartment(department), nil
+}
+
+func (s *departmentServer) ListDepartments(ctx context.Context, req *ps3tv1.ListDepartmentsRequest) (*ps3tv1.ListDepartmentsResponse, error) {
+	input := dto.RequestDepartment{
+		DepartmentName: "%",
+		Limit:          int(req.GetLimit()),
+		Offset:         int(req.GetOffset()),
+	}
+	if req.GetName() != "" {
+		input.DepartmentName = fmt.Sprintf("%%%s%%", req.GetName())
+	}
+	if input.Limit <= 0 {
+		input.Limit = defaultDepartmentLimit
+	}
+	if input.Offset < 0 {
+		input.Offset = 0
+	}
+
+	departments, err := s.service.GetAll(ctx, managerID(ctx), input)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	response := &ps3tv1.ListDepartmentsResponse{Departments: make([]*ps3tv1.Department, len(departments))}
+	for i, department := range departments {
+		response.Departments[i] = toDepartment(department)
+	}
+	return response, nil
+}
+
+func (s *departmentServer) UpdateDepartment(ctx context.Context, req *ps3tv1.UpdateDepartmentRequest) (*ps3tv1.Department, error) {
+	department, err := s.service.Update(ctx, req.GetName(), req.GetDepartmentId(), managerID(ctx))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toDepartment(department), nil
+}
+
+func (s *departmentServer) DeleteDepartment(ctx context.Context, req *ps3tv1.DeleteDepartmentRequest) (*emptypb.Empty, error) {
+	if req.GetDepartmentId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "department_id is required")
+	}
+	if err := s.service.Delete(ctx, req.GetDepartmentId(), managerID(ctx), req.GetMoveTo()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func toDepartment(department dto.ResponseSingleDepartment) *ps3tv1.Department {
+	return &ps3tv1.Department{
+		DepartmentId: department.DepartmentID,
+		Name:         department.DepartmentName,
+	}
+}
@@ -0,0 +1,178 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/featureflag"
+	"github.com/levensspel/go-gin-template/helper"
+	ps3tv1 "github.com/levensspel/go-gin-template/proto/ps3t/v1"
+	service "github.com/levensspel/go-gin-template/service/employee"
+	"github.com/levensspel/go-gin-template/validation"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// getEmployeeLimit adalah jumlah employee dengan prefix identity number yang
+// diperiksa GetEmployee untuk mencari yang sama persis.
+const getEmployeeLimit = 100
+
+type employeeServer struct {
+	ps3tv1.UnimplementedEmployeeServiceServer
+	service service.EmployeeService
+	flags   featureflag.FeatureFlags
+}
+
+func (s *employeeServer) CreateEmployee(ctx context.Context, req *ps3tv1.CreateEmployeeRequest) (*ps3tv1.Employee, error) {
+	input := dto.EmployeePayload{
+		IdentityNumber:   req.GetIdentityNumber(),
+		Name:             req.GetName(),
+		EmployeeImageUri: req.GetEmployeeImageUri(),
+		Gender:           req.GetGender(),
+		DepartmentID:     req.GetDepartmentId(),
+	}
+	if err := validation.ValidateEmployeeCreate(&input); err != nil {
+		return nil, invalidArgument(err)
+	}
+	employee, err := s.service.Create(ctx, input, managerID(ctx))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toEmployee(employee), nil
+}
+
+// GetEmployee memakai filter prefix identityNumber GET /v1/employee, lalu
+// mengambil employee yang identity number-nya sama persis.
+func (s *employeeServer) GetEmployee(ctx context.Context, req *ps3tv1.GetEmployeeRequest) (*ps3tv1.Employee, error) {
+	if req.GetIdentityNumber() == "" {
+		return nil, status.Error(codes.InvalidArgument, "identity_number is required")
+	}
+	employees, err := s.service.GetAll(ctx, dto.GetEmployeesRequest{
+		Limit:          getEmployeeLimit,
+		IdentityNumber: strings.ToLower(req.GetIdentityNumber()),
+		ManagerID:      managerID(ctx),
+		SortBy:         "identityNumber",
+		Status:         dto.EmployeeStatusAll,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	for _, employee := range employees {
+		if employee.IdentityNumber == req.GetIdentityNumber() {
+			return toEmployee(employee), nil
+		}
+	}
+	return nil, toStatus(helper.ErrNotFound)
+}
+
+// ListEmployees menerima filter yang sama dengan GET /v1/employee, yang
+// dinormalisasi ValidateEmployeeGet.
+func (s *employeeServer) ListEmployees(ctx context.Context, req *ps3tv1.ListEmployeesRequest) (*ps3tv1.ListEmployeesResponse, error) {
+	input := dto.GetEmployeesRequest{
+		Limit:          int(req.GetLimit()),
+		Offset:         int(req.GetOffset()),
+		IdentityNumber: req.GetIdentityNumber(),
+		Name:           req.GetName(),
+		Query:          req.GetQuery(),
+		Fuzzy:          req.GetFuzzy(),
+		Gender:         req.GetGender(),
+		DepartmentID:   req.GetDepartmentId(),
+		ManagerID:      managerID(ctx),
+		SortBy:         req.GetSortBy(),
+	}
+	// proto3 tidak membedakan field yang tidak dikirim dengan nilai 0 atau
+	// string kosong, jadi limit 0 tetap berarti default dan EmptyFilters
+	// tidak diisi
+	if input.Limit <= 0 {
+		input.Limit = dto.DefaultLimit
+	}
+	if input.Offset < 0 {
+		input.Offset = dto.DefaultOffset
+	}
+	// fuzzy tanpa flag diperlakukan sebagai pencarian biasa
+	if input.Fuzzy && !s.flags.IsEnabled(ctx, featureflag.FuzzySearch, input.ManagerID) {
+		input.Fuzzy = false
+	}
+	if err := validation.ValidateEmployeeGet(&input); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	employees, err := s.service.GetAll(ctx, input)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	response := &ps3tv1.ListEmployeesResponse{Employees: make([]*ps3tv1.Employee, len(employees))}
+	for i, employee := range employees {
+		response.Employees[i] = toEmployee(employee)
+	}
+	return response, nil
+}
+
+// UpdateEmployee mengubah field yang disebut update_mask seperti PATCH
+// /v1/employee/:identityNumber; mask kosong mengubah semua field.
+func (s *employeeServer) UpdateEmployee(ctx context.Context, req *ps3tv1.UpdateEmployeeRequest) (*ps3tv1.Employee, error) {
+	input, err := toUpdatePayload(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := validation.ValidateEmployeePatch(&input); err != nil {
+		return nil, invalidArgument(err)
+	}
+	employee, err := s.service.Update(ctx, req.GetIdentityNumber(), input, managerID(ctx))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toEmployee(employee), nil
+}
+
+func (s *employeeServer) DeleteEmployee(ctx context.Context, req *ps3tv1.DeleteEmployeeRequest) (*ps3tv1.Employee, error) {
+	employee, err := s.service.Delete(ctx, req.GetIdentityNumber(), managerID(ctx))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toEmployee(employee), nil
+}
+
+func toUpdatePayload(req *ps3tv1.UpdateEmployeeRequest) (dto.EmployeeUpdatePayload, error) {
+	employee := req.GetEmployee()
+	if employee == nil {
+		employee = &ps3tv1.Employee{}
+	}
+	paths := req.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		paths = []string{"identity_number", "name", "employee_image_uri", "gender", "department_id"}
+	}
+
+	var input dto.EmployeeUpdatePayload
+	for _, path := range paths {
+		switch path {
+		case "identity_number":
+			input.IdentityNumber = &employee.IdentityNumber
+		case "name":
+			input.Name = &employee.Name
+		case "employee_image_uri":
+			input.EmployeeImageUri = &employee.EmployeeImageUri
+		case "gender":
+			input.Gender = &employee.Gender
+		case "department_id":
+			input.DepartmentID = &employee.DepartmentId
+		default:
+			return input, status.Errorf(codes.InvalidArgument, "update_mask: unknown field %q", path)
+		}
+	}
+	return input, nil
+}
+
+func toEmployee(employee dto.EmployeeResponse) *ps3tv1.Employee {
+	return &ps3tv1.Employee{
+		IdentityNumber:   employee.IdentityNumber,
+		Name:             employee.Name,
+		EmployeeImageUri: employee.EmployeeImageUri,
+		Gender:           employee.Gender,
+		DepartmentId:     employee.DepartmentID,
+		CreatedAt:        timestamppb.New(employee.CreatedAt),
+		UpdatedAt:        timestamppb.New(employee.UpdatedAt),
+	}
+}
@@ -0,0 +1,85 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/levensspel/go-gin-template/helper"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fieldErrorer sama dengan yang dipakai helper.Error, diimplementasikan
+// validation.FieldErrors.
+type fieldErrorer interface {
+	FieldErrors() map[string]string
+}
+
+// codesByStatus memetakan status HTTP error terdaftar (lihat
+// helper.Register) ke kode gRPC. Status lain menjadi codes.Internal.
+var codesByStatus = map[int]codes.Code{
+	http.StatusBadRequest:            codes.InvalidArgument,
+	http.StatusUnauthorized:          codes.Unauthenticated,
+	http.StatusForbidden:             codes.PermissionDenied,
+	http.StatusNotFound:              codes.NotFound,
+	http.StatusRequestTimeout:        codes.DeadlineExceeded,
+	http.StatusConflict:              codes.AlreadyExists,
+	http.StatusGone:                  codes.FailedPrecondition,
+	http.StatusRequestEntityTooLarge: codes.ResourceExhausted,
+	http.StatusServiceUnavailable:    codes.Unavailable,
+	http.StatusGatewayTimeout:        codes.DeadlineExceeded,
+}
+
+// toStatus memetakan error dari service ke status gRPC dengan pesan dari
+// katalog pesan, sehingga pesan error asli tidak pernah dikirim ke client
+// seperti helper.Error pada HTTP.
+func toStatus(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	var fieldErrors fieldErrorer
+	if errors.As(err, &fieldErrors) {
+		return invalidArgument(err)
+	}
+	code, ok := codesByStatus[helper.GetErrorStatusCode(err)]
+	if !ok {
+		// Client membatalkan request atau deadline-nya habis
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return status.FromContextError(err).Err()
+		}
+		code = codes.Internal
+	}
+	return status.Error(code, helper.GetErrorMessage(err))
+}
+
+// invalidArgument membuat status InvalidArgument untuk error validasi
+// input. Pesan per field dikirim sebagai errdetails.BadRequest.
+func invalidArgument(err error) error {
+	var fieldErrors fieldErrorer
+	if !errors.As(err, &fieldErrors) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	fields := fieldErrors.FieldErrors()
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	details := &errdetails.BadRequest{}
+	for _, name := range names {
+		details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       name,
+			Description: fields[name],
+		})
+	}
+
+	st, detailErr := status.New(codes.InvalidArgument, err.Error()).WithDetails(details)
+	if detailErr != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return st.Err()
+}
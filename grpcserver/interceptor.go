@@ -0,0 +1,175 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/levensspel/go-gin-template/auth"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/reporting"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDKey adalah metadata request id, padanan header
+// middleware.RequestIDHeader.
+const requestIDKey = "x-request-id"
+
+// callKey menyimpan *call di context request.
+type callKey struct{}
+
+// call diisi interceptor selama request berjalan. AccessLog membacanya
+// setelah handler selesai, seperti middleware.AccessLog membaca gin.Context,
+// karena manager id baru diketahui di Authorization yang berjalan lebih
+// dalam.
+type call struct {
+	requestID string
+	managerID string
+}
+
+func callFromContext(ctx context.Context) *call {
+	if c, ok := ctx.Value(callKey{}).(*call); ok {
+		return c
+	}
+	return &call{}
+}
+
+// managerID mengembalikan id manager yang diisi Authorization.
+func managerID(ctx context.Context) string {
+	id, _ := ctx.Value(helper.ContextKeyUserID).(string)
+	return id
+}
+
+// RequestID memakai metadata x-request-id dari client (atau membuat yang
+// baru), menyimpannya di context dengan key yang sama dengan
+// middleware.RequestID dan mengembalikannya di header response.
+func RequestID(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	var requestID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestIDKey); len(values) > 0 {
+			requestID = values[0]
+		}
+	}
+	if requestID == "" || len(requestID) > 128 {
+		requestID = newRequestID()
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, requestID))
+
+	ctx = context.WithValue(ctx, callKey{}, &call{requestID: requestID})
+	ctx = context.WithValue(ctx, helper.ContextKeyRequestID, requestID)
+	return handler(ctx, req)
+}
+
+// AccessLog mencatat setiap request gRPC dengan field yang sama dengan
+// middleware.AccessLog; status berisi kode gRPC.
+func AccessLog(log logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		latency := time.Since(start)
+
+		c := callFromContext(ctx)
+		entry := map[string]any{
+			"method":     info.FullMethod,
+			"status":     status.Code(err).String(),
+			"latency_ms": latency.Milliseconds(),
+			"manager_id": c.managerID,
+			"request_id": c.requestID,
+		}
+		if p, ok := peer.FromContext(ctx); ok {
+			entry["client_ip"] = p.Addr.String()
+		}
+		if err != nil {
+			entry["error"] = status.Convert(err).Message()
+		}
+
+		if threshold := logger.SlowRequestThreshold(); latency > threshold {
+			entry["threshold_ms"] = threshold.Milliseconds()
+			log.Warn("slow grpc request", helper.GRPCAccessLog, entry)
+			return resp, err
+		}
+		log.Info("grpc request", helper.GRPCAccessLog, entry)
+		return resp, err
+	}
+}
+
+// Metrics mencatat jumlah dan durasi request per method dan kode gRPC.
+func Metrics(m *metrics.Metrics) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.ObserveGRPCRequest(info.FullMethod, status.Code(err).String(), time.Since(start))
+		return resp, err
+	}
+}
+
+// Recovery mengubah panic di handler menjadi codes.Internal dan
+// mengirimnya ke error reporter, seperti helper.ReportPanic.
+func Recovery(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+		panicErr, ok := recovered.(error)
+		if !ok {
+			panicErr = fmt.Errorf("panic: %v", recovered)
+		}
+		c := callFromContext(ctx)
+		reporting.Default().Report(ctx, panicErr, map[string]any{
+			"method":     info.FullMethod,
+			"manager_id": c.managerID,
+			"request_id": c.requestID,
+			"panic":      true,
+		})
+		resp, err = nil, toStatus(helper.ErrInternalServer)
+	}()
+	return handler(ctx, req)
+}
+
+// Authorization memverifikasi bearer token pada metadata authorization
+// dengan auth.Service, sama dengan middleware.Authorization, lalu menyimpan
+// id manager di context dengan key helper.ContextKeyUserID.
+func Authorization(tokens auth.Service) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		id, err := authenticate(ctx, tokens)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		callFromContext(ctx).managerID = id
+		return handler(context.WithValue(ctx, helper.ContextKeyUserID, id), req)
+	}
+}
+
+func authenticate(ctx context.Context, tokens auth.Service) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", errors.New("the request is allowed for logged in")
+	}
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok || token == "" {
+		return "", errors.New("the request is allowed for logged in")
+	}
+	id, err := tokens.ParseToken(ctx, token)
+	if err != nil {
+		return "", helper.ErrUnauthorized
+	}
+	return id, nil
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,55 @@
+// Package grpcserver menyediakan EmployeeService dan DepartmentService dari
+// proto/ps3t/v1 di atas service layer yang sama dengan handler HTTP.
+package grpcserver
+
+import (
+	"github.com/levensspel/go-gin-template/auth"
+	"github.com/levensspel/go-gin-template/featureflag"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/metrics"
+	ps3tv1 "github.com/levensspel/go-gin-template/proto/ps3t/v1"
+	departmentService "github.com/levensspel/go-gin-template/service/department"
+	employeeService "github.com/levensspel/go-gin-template/service/employee"
+	"github.com/samber/do/v2"
+	"google.golang.org/grpc"
+)
+
+// New membuat server gRPC dengan interceptor yang sama urutannya dengan
+// middleware HTTP: request id, access log, metrics, recovery, lalu
+// autentikasi token.
+func New(
+	employees employeeService.EmployeeService,
+	departments departmentService.DepartmentService,
+	flags featureflag.FeatureFlags,
+	tokens auth.Service,
+	log logger.Logger,
+	metrics *metrics.Metrics,
+	opts ...grpc.ServerOption,
+) *grpc.Server {
+	opts = append(opts, grpc.ChainUnaryInterceptor(
+		RequestID,
+		AccessLog(log),
+		Metrics(metrics),
+		Recovery,
+		Authorization(tokens),
+	))
+	srv := grpc.NewServer(opts...)
+	ps3tv1.RegisterEmployeeServiceServer(srv, &employeeServer{service: employees, flags: flags})
+	ps3tv1.RegisterDepartmentServiceServer(srv, &departmentServer{service: departments})
+	return srv
+}
+
+// NewInject membuat server gRPC dari dependency di injector. opts dipakai
+// untuk TLS, lihat server.Start.
+func NewInject(i do.Injector, opts ...grpc.ServerOption) *grpc.Server {
+	_logger := do.MustInvoke[logger.LogHandler](i)
+	return New(
+		do.MustInvoke[employeeService.EmployeeService](i),
+		do.MustInvoke[departmentService.DepartmentService](i),
+		do.MustInvoke[featureflag.FeatureFlags](i),
+		do.MustInvoke[auth.Service](i),
+		&_logger,
+		do.MustInvoke[*metrics.Metrics](i),
+		opts...,
+	)
+}
@@ -0,0 +1,418 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/featureflag"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/mocks"
+	ps3tv1 "github.com/levensspel/go-gin-template/proto/ps3t/v1"
+	"github.com/levensspel/go-gin-template/reporting"
+	departmentService "github.com/levensspel/go-gin-template/service/department"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+const testManagerID = "0b7c2d2e-4f4a-4b8e-9c59-2f1d8f6a3e10"
+
+// fakeTokens menerima token "token:<id>", seperti fakeTokens di test
+// service user.
+type fakeTokens struct{}
+
+func (fakeTokens) GenerateToken(userID string) (string, error) {
+	return "token:" + userID, nil
+}
+
+func (fakeTokens) ParseToken(ctx context.Context, encodedToken string) (string, error) {
+	id, ok := strings.CutPrefix(encodedToken, "token:")
+	if !ok {
+		return "", errors.New("invalid token")
+	}
+	return id, nil
+}
+
+// fakeFlags mengaktifkan flag di enabled untuk semua manager.
+type fakeFlags struct {
+	featureflag.FeatureFlags
+	enabled map[featureflag.Flag]bool
+}
+
+func (f fakeFlags) IsEnabled(ctx context.Context, flag featureflag.Flag, managerId string) bool {
+	return f.enabled[flag]
+}
+
+// fakeDepartments menjalankan fungsi yang diisi test; method lain panic
+// lewat interface yang kosong.
+type fakeDepartments struct {
+	departmentService.DepartmentService
+	create func(ctx context.Context, managerID string, input dto.RequestDepartment) (dto.ResponseSingleDepartment, error)
+	delete func(ctx context.Context, id string, managerID string, moveTo string) error
+}
+
+func (f *fakeDepartments) Create(ctx context.Context, managerID string, input dto.RequestDepartment) (dto.ResponseSingleDepartment, error) {
+	return f.create(ctx, managerID, input)
+}
+
+func (f *fakeDepartments) Delete(ctx context.Context, id string, managerID string, moveTo string) error {
+	return f.delete(ctx, id, managerID, moveTo)
+}
+
+// grpcFixture menjalankan server di atas bufconn dan menyediakan client
+// untuk kedua service.
+type grpcFixture struct {
+	employees   *mocks.EmployeeService
+	departments *fakeDepartments
+	flags       fakeFlags
+	metrics     *metrics.Metrics
+
+	employeeClient   ps3tv1.EmployeeServiceClient
+	departmentClient ps3tv1.DepartmentServiceClient
+}
+
+func newGRPCFixture(t *testing.T) *grpcFixture {
+	t.Helper()
+	f := &grpcFixture{
+		employees:   &mocks.EmployeeService{},
+		departments: &fakeDepartments{},
+		flags:       fakeFlags{enabled: map[featureflag.Flag]bool{}},
+		metrics:     metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{}),
+	}
+	listener := bufconn.Listen(1 << 20)
+	srv := New(f.employees, f.departments, f.flags, fakeTokens{}, mocks.Logger{}, f.metrics)
+	go srv.Serve(listener)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	f.employeeClient = ps3tv1.NewEmployeeServiceClient(conn)
+	f.departmentClient = ps3tv1.NewDepartmentServiceClient(conn)
+	return f
+}
+
+// authorized adalah context dengan bearer token testManagerID.
+func authorized(t *testing.T) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer token:"+testManagerID)
+}
+
+func validCreateRequest() *ps3tv1.CreateEmployeeRequest {
+	return &ps3tv1.CreateEmployeeRequest{
+		IdentityNumber:   "EMP-12345",
+		Name:             "Budi",
+		EmployeeImageUri: "https://example.com/budi.png",
+		Gender:           "male",
+		DepartmentId:     "1c8d3e3f-5a5b-4c9f-8d6a-3a2e9a7b4f21",
+	}
+}
+
+func employeeResponse(identityNumber string) dto.EmployeeResponse {
+	return dto.EmployeeResponse{EmployeePayload: dto.EmployeePayload{IdentityNumber: identityNumber}}
+}
+
+func TestCreateEmployee(t *testing.T) {
+	f := newGRPCFixture(t)
+	var gotManager string
+	var gotInput dto.EmployeePayload
+	f.employees.CreateFunc = func(ctx context.Context, input dto.EmployeePayload, managerId string) (dto.EmployeeResponse, error) {
+		gotInput, gotManager = input, managerId
+		return dto.EmployeeResponse{EmployeePayload: input}, nil
+	}
+
+	employee, err := f.employeeClient.CreateEmployee(authorized(t), validCreateRequest())
+	if err != nil {
+		t.Fatalf("CreateEmployee error = %v", err)
+	}
+	if gotManager != testManagerID || gotInput.IdentityNumber != "EMP-12345" || gotInput.Gender != "male" {
+		t.Fatalf("service Create(%+v, %s)", gotInput, gotManager)
+	}
+	if employee.GetIdentityNumber() != "EMP-12345" || employee.GetName() != "Budi" {
+		t.Fatalf("CreateEmployee = %+v", employee)
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		authorization string
+	}{
+		{name: "missing token"},
+		{name: "not bearer", authorization: "token:" + testManagerID},
+		{name: "invalid token", authorization: "Bearer forged"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newGRPCFixture(t)
+			ctx := context.Background()
+			if tt.authorization != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", tt.authorization)
+			}
+
+			_, err := f.employeeClient.CreateEmployee(ctx, validCreateRequest())
+			if status.Code(err) != codes.Unauthenticated {
+				t.Fatalf("CreateEmployee error = %v, want Unauthenticated", err)
+			}
+		})
+	}
+}
+
+func TestErrorCodes(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{name: "conflict", err: helper.ErrConflictIdentityNumber, want: codes.AlreadyExists},
+		{name: "bad request", err: helper.ErrInvalidDepartmentId, want: codes.InvalidArgument},
+		{name: "not found", err: helper.ErrNotFound, want: codes.NotFound},
+		{name: "forbidden", err: helper.ErrForbidden, want: codes.PermissionDenied},
+		{name: "query timeout", err: helper.ErrQueryTimeout, want: codes.DeadlineExceeded},
+		{name: "unavailable", err: helper.ErrServiceUnavailable, want: codes.Unavailable},
+		{name: "unregistered", err: errors.New("pq: connection reset"), want: codes.Internal},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newGRPCFixture(t)
+			f.employees.CreateFunc = func(ctx context.Context, input dto.EmployeePayload, managerId string) (dto.EmployeeResponse, error) {
+				return dto.EmployeeResponse{}, tt.err
+			}
+
+			_, err := f.employeeClient.CreateEmployee(authorized(t), validCreateRequest())
+			st := status.Convert(err)
+			if st.Code() != tt.want {
+				t.Fatalf("CreateEmployee error = %v, want %s", err, tt.want)
+			}
+			// Pesan dari katalog, error asli tidak dikirim ke client
+			if st.Message() != helper.GetErrorMessage(tt.err) {
+				t.Fatalf("message = %q, want %q", st.Message(), helper.GetErrorMessage(tt.err))
+			}
+		})
+	}
+}
+
+func TestCreateEmployeeFieldViolations(t *testing.T) {
+	f := newGRPCFixture(t)
+	request := validCreateRequest()
+	request.Gender = "unknown"
+	request.DepartmentId = ""
+
+	_, err := f.employeeClient.CreateEmployee(authorized(t), request)
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("CreateEmployee error = %v, want InvalidArgument", err)
+	}
+	var fields []string
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.GetFieldViolations() {
+				fields = append(fields, violation.GetField())
+			}
+		}
+	}
+	if len(fields) != 2 || fields[0] != "departmentId" || fields[1] != "gender" {
+		t.Fatalf("field violations = %v, want departmentId and gender", fields)
+	}
+}
+
+func TestGetEmployeeExactMatch(t *testing.T) {
+	f := newGRPCFixture(t)
+	var gotInput dto.GetEmployeesRequest
+	f.employees.GetAllFunc = func(ctx context.Context, input dto.GetEmployeesRequest) ([]dto.EmployeeResponse, error) {
+		gotInput = input
+		return []dto.EmployeeResponse{employeeResponse("EMP-1"), employeeResponse("EMP-12")}, nil
+	}
+
+	employee, err := f.employeeClient.GetEmployee(authorized(t), &ps3tv1.GetEmployeeRequest{IdentityNumber: "EMP-12"})
+	if err != nil {
+		t.Fatalf("GetEmployee error = %v", err)
+	}
+	if employee.GetIdentityNumber() != "EMP-12" {
+		t.Fatalf("GetEmployee = %+v, want EMP-12", employee)
+	}
+	if gotInput.IdentityNumber != "emp-12" || gotInput.ManagerID != testManagerID || gotInput.Status != dto.EmployeeStatusAll {
+		t.Fatalf("service GetAll(%+v)", gotInput)
+	}
+
+	if _, err := f.employeeClient.GetEmployee(authorized(t), &ps3tv1.GetEmployeeRequest{IdentityNumber: "EMP-123"}); status.Code(err) != codes.NotFound {
+		t.Fatalf("GetEmployee without exact match error = %v, want NotFound", err)
+	}
+	if _, err := f.employeeClient.GetEmployee(authorized(t), &ps3tv1.GetEmployeeRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("GetEmployee without identity_number error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestListEmployeesDefaults(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		fuzzy     bool
+		wantFuzzy bool
+	}{
+		{name: "fuzzy enabled", fuzzy: true, wantFuzzy: true},
+		{name: "fuzzy disabled"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newGRPCFixture(t)
+			f.flags.enabled[featureflag.FuzzySearch] = tt.fuzzy
+			var gotInput dto.GetEmployeesRequest
+			f.employees.GetAllFunc = func(ctx context.Context, input dto.GetEmployeesRequest) ([]dto.EmployeeResponse, error) {
+				gotInput = input
+				return []dto.EmployeeResponse{employeeResponse("EMP-1")}, nil
+			}
+
+			response, err := f.employeeClient.ListEmployees(authorized(t), &ps3tv1.ListEmployeesRequest{Offset: -1, Gender: "MALE", Fuzzy: true})
+			if err != nil {
+				t.Fatalf("ListEmployees error = %v", err)
+			}
+			if len(response.GetEmployees()) != 1 {
+				t.Fatalf("ListEmployees = %+v", response)
+			}
+			if gotInput.Limit != dto.DefaultLimit || gotInput.Offset != dto.DefaultOffset || gotInput.Gender != "male" ||
+				gotInput.Status != dto.EmployeeStatusActive || gotInput.Fuzzy != tt.wantFuzzy || gotInput.ManagerID != testManagerID {
+				t.Fatalf("service GetAll(%+v)", gotInput)
+			}
+		})
+	}
+}
+
+func TestUpdateEmployeeMask(t *testing.T) {
+	f := newGRPCFixture(t)
+	var gotInput dto.EmployeeUpdatePayload
+	f.employees.UpdateFunc = func(ctx context.Context, identityNumber string, input dto.EmployeeUpdatePayload, managerId string) (dto.EmployeeResponse, error) {
+		gotInput = input
+		return dto.EmployeeResponse{EmployeePayload: dto.EmployeePayload{IdentityNumber: identityNumber, Name: *input.Name}}, nil
+	}
+
+	request := &ps3tv1.UpdateEmployeeRequest{
+		IdentityNumber: "EMP-12345",
+		Employee:       &ps3tv1.Employee{Name: "Sari", Gender: "female"},
+		UpdateMask:     &fieldmaskpb.FieldMask{Paths: []string{"name"}},
+	}
+	employee, err := f.employeeClient.UpdateEmployee(authorized(t), request)
+	if err != nil {
+		t.Fatalf("UpdateEmployee error = %v", err)
+	}
+	// Hanya field di update_mask yang dikirim ke service
+	if gotInput.Name == nil || *gotInput.Name != "Sari" || gotInput.Gender != nil || gotInput.IdentityNumber != nil || employee.GetName() != "Sari" {
+		t.Fatalf("service Update(%+v), response %+v", gotInput, employee)
+	}
+
+	request.UpdateMask.Paths = []string{"salary"}
+	if _, err := f.employeeClient.UpdateEmployee(authorized(t), request); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("UpdateEmployee with unknown path error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestDepartments(t *testing.T) {
+	f := newGRPCFixture(t)
+	f.departments.create = func(ctx context.Context, managerID string, input dto.RequestDepartment) (dto.ResponseSingleDepartment, error) {
+		if managerID != testManagerID {
+			t.Errorf("managerID = %q, want %q", managerID, testManagerID)
+		}
+		return dto.ResponseSingleDepartment{DepartmentID: "department-1", DepartmentName: input.DepartmentName}, nil
+	}
+	f.departments.delete = func(ctx context.Context, id string, managerID string, moveTo string) error {
+		return helper.ErrConflict
+	}
+
+	department, err := f.departmentClient.CreateDepartment(authorized(t), &ps3tv1.CreateDepartmentRequest{Name: "Finance"})
+	if err != nil || department.GetDepartmentId() != "department-1" || department.GetName() != "Finance" {
+		t.Fatalf("CreateDepartment = %+v, %v", department, err)
+	}
+	if _, err := f.departmentClient.DeleteDepartment(authorized(t), &ps3tv1.DeleteDepartmentRequest{DepartmentId: "department-1"}); status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("DeleteDepartment error = %v, want AlreadyExists", err)
+	}
+	if _, err := f.departmentClient.DeleteDepartment(authorized(t), &ps3tv1.DeleteDepartmentRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("DeleteDepartment without department_id error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestRequestIDAndMetrics(t *testing.T) {
+	f := newGRPCFixture(t)
+	f.employees.DeleteFunc = func(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error) {
+		if got, _ := ctx.Value(helper.ContextKeyRequestID).(string); got != "request-1" {
+			t.Errorf("request id in context = %q, want request-1", got)
+		}
+		return dto.EmployeeResponse{}, helper.ErrNotFound
+	}
+
+	var header metadata.MD
+	ctx := metadata.AppendToOutgoingContext(authorized(t), requestIDKey, "request-1")
+	_, err := f.employeeClient.DeleteEmployee(ctx, &ps3tv1.DeleteEmployeeRequest{IdentityNumber: "EMP-1"}, grpc.Header(&header))
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("DeleteEmployee error = %v, want NotFound", err)
+	}
+	if got := header.Get(requestIDKey); len(got) != 1 || got[0] != "request-1" {
+		t.Fatalf("response %s = %v, want request-1", requestIDKey, got)
+	}
+	method := ps3tv1.EmployeeService_DeleteEmployee_FullMethodName
+	if got := testutil.ToFloat64(f.metrics.GRPCRequests.WithLabelValues(method, codes.NotFound.String())); got != 1 {
+		t.Fatalf("grpc_requests_total{%s, NotFound} = %v, want 1", method, got)
+	}
+
+	// Tanpa x-request-id dari client, server membuat yang baru
+	f.employees.DeleteFunc = func(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error) {
+		return dto.EmployeeResponse{}, nil
+	}
+	if _, err := f.employeeClient.DeleteEmployee(authorized(t), &ps3tv1.DeleteEmployeeRequest{IdentityNumber: "EMP-1"}, grpc.Header(&header)); err != nil {
+		t.Fatal(err)
+	}
+	if got := header.Get(requestIDKey); len(got) != 1 || len(got[0]) != 32 {
+		t.Fatalf("response %s = %v, want a generated id", requestIDKey, got)
+	}
+}
+
+// recordingReporter menyimpan error yang dilaporkan.
+type recordingReporter struct {
+	mu     sync.Mutex
+	fields []map[string]any
+}
+
+func (r *recordingReporter) Report(ctx context.Context, err error, fields map[string]any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fields = append(r.fields, fields)
+}
+
+func TestRecovery(t *testing.T) {
+	reporter := &recordingReporter{}
+	previous := reporting.Default()
+	reporting.SetDefault(reporter)
+	t.Cleanup(func() { reporting.SetDefault(previous) })
+	f := newGRPCFixture(t)
+	f.employees.CreateFunc = func(ctx context.Context, input dto.EmployeePayload, managerId string) (dto.EmployeeResponse, error) {
+		panic("boom")
+	}
+
+	_, err := f.employeeClient.CreateEmployee(authorized(t), validCreateRequest())
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("CreateEmployee error = %v, want Internal", err)
+	}
+	reporter.mu.Lock()
+	defer reporter.mu.Unlock()
+	if len(reporter.fields) != 1 || reporter.fields[0]["panic"] != true || reporter.fields[0]["manager_id"] != testManagerID {
+		t.Fatalf("reported %v, want one panic from %s", reporter.fields, testManagerID)
+	}
+}
@@ -0,0 +1,257 @@
+package adminHandler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/featureflag"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/purge"
+	"github.com/levensspel/go-gin-template/validation"
+	"github.com/samber/do/v2"
+	"go.uber.org/zap/zapcore"
+)
+
+type AdminHandler interface {
+	Purge(ctx *gin.Context)
+	Cleanup(ctx *gin.Context)
+	SetFeatureFlag(ctx *gin.Context)
+	DeleteFeatureFlag(ctx *gin.Context)
+	GetLogLevel(ctx *gin.Context)
+	SetLogLevel(ctx *gin.Context)
+}
+
+type handler struct {
+	purger   *purge.Purger
+	cleaner  *purge.Cleaner
+	flags    featureflag.FeatureFlags
+	logLevel *logger.Level
+	logger   logger.Logger
+}
+
+func New(purger *purge.Purger, cleaner *purge.Cleaner, flags featureflag.FeatureFlags, logLevel *logger.Level, logger logger.Logger) AdminHandler {
+	return &handler{purger: purger, cleaner: cleaner, flags: flags, logLevel: logLevel, logger: logger}
+}
+
+func NewInject(i do.Injector) (AdminHandler, error) {
+	_purger := do.MustInvoke[*purge.Purger](i)
+	_cleaner := do.MustInvoke[*purge.Cleaner](i)
+	_flags := do.MustInvoke[featureflag.FeatureFlags](i)
+	_logLevel := do.MustInvoke[*logger.Level](i)
+	_logger := do.MustInvoke[logger.LogHandler](i)
+	return New(_purger, _cleaner, _flags, _logLevel, &_logger), nil
+}
+
+// Purge soft-deleted records
+// @Tags admin
+// @Summary Purge soft-deleted records
+// @Description Hard-delete soft-deleted departments older than the retention period now, instead of waiting for the scheduled purge. Admin only.
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Success 200 {object} helper.Response{data=purge.Report} "OK"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} helper.Response{errors=helper.ErrorResponse} "Forbidden"
+// @Failure 409 {object} helper.Response{errors=helper.ErrorResponse} "Purge already running"
+// @Failure 500 {object} helper.Response{data=purge.Report} "Server Error"
+// @Router /v1/admin/purge [POST]
+func (h *handler) Purge(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+
+	report, err := h.purger.RunOnce(ctx)
+	if errors.Is(err, purge.ErrNotLeader) {
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+	if err != nil {
+		h.logger.Error(err.Error(), helper.AdminHandlerPurge)
+		status, response := helper.FromError(err)
+		ctx.JSON(status, response.WithData(report))
+		return
+	}
+	ctx.JSON(http.StatusOK, helper.OK(report))
+}
+
+// Clean up expired rows
+// @Tags admin
+// @Summary Clean up expired rows
+// @Description Delete sent outbox events, finished webhook deliveries, expired presigned uploads, unreferenced files, expired API keys and processed integration callbacks older than their retention now, instead of waiting for the scheduled cleanup. Admin only.
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param table query string false "Only this table" Enums(outbox, webhook_delivery, file_upload, file, api_key, integration_callback)
+// @Success 200 {object} helper.Response{data=purge.CleanupReport} "OK"
+// @Failure 400 {object} helper.Response{errors=helper.ErrorResponse} "Unknown table"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} helper.Response{errors=helper.ErrorResponse} "Forbidden"
+// @Failure 409 {object} helper.Response{data=purge.CleanupReport} "Cleanup already running"
+// @Failure 500 {object} helper.Response{data=purge.CleanupReport} "Server Error"
+// @Router /v1/admin/cleanup [POST]
+func (h *handler) Cleanup(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+
+	report, err := h.cleaner.RunOnce(ctx, ctx.Query("table"))
+	if errors.Is(err, purge.ErrUnknownCleanupTable) {
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+	if err != nil {
+		if !errors.Is(err, purge.ErrNotLeader) {
+			h.logger.Error(err.Error(), helper.AdminHandlerCleanup)
+		}
+		status, response := helper.FromError(err)
+		ctx.JSON(status, response.WithData(report))
+		return
+	}
+	ctx.JSON(http.StatusOK, helper.OK(report))
+}
+
+// Set a feature flag override
+// @Tags admin
+// @Summary Set a feature flag override
+// @Description Enable or disable a feature flag for one manager, overriding the configured default. Other instances pick up the change after FEATURE_FLAG_CACHE_TTL. Admin only.
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param flag path string true "Feature flag" Enums(cursor_pagination, fuzzy_search, response_v2)
+// @Param managerId path string true "Manager id"
+// @Param data body dto.FeatureFlagOverrideRequest true "data"
+// @Success 200 {object} helper.Response{data=dto.FeatureFlagOverrideResponse} "OK"
+// @Failure 400 {object} helper.Response{errors=helper.ErrorResponse} "Bad Request"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} helper.Response{errors=helper.ErrorResponse} "Forbidden"
+// @Failure 404 {object} helper.Response{errors=helper.ErrorResponse} "Unknown flag"
+// @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
+// @Router /v1/admin/feature-flags/{flag}/overrides/{managerId} [PUT]
+func (h *handler) SetFeatureFlag(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+
+	input := new(dto.FeatureFlagOverrideRequest)
+	if err := ctx.ShouldBindJSON(input); err != nil || input.Enabled == nil {
+		ctx.JSON(helper.FromError(helper.ErrBadRequest))
+		return
+	}
+
+	flag := featureflag.Flag(ctx.Param("flag"))
+	managerID := ctx.Param("managerId")
+	if err := h.flags.SetOverride(ctx, flag, managerID, input.Enabled); err != nil {
+		h.featureFlagError(ctx, err, helper.AdminHandlerSetFeatureFlag)
+		return
+	}
+	ctx.JSON(http.StatusOK, helper.OK(dto.FeatureFlagOverrideResponse{
+		Flag:      string(flag),
+		ManagerID: managerID,
+		Enabled:   *input.Enabled,
+	}))
+}
+
+// Delete a feature flag override
+// @Tags admin
+// @Summary Delete a feature flag override
+// @Description Remove a manager's override so the flag falls back to the configured default. Admin only.
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param flag path string true "Feature flag" Enums(cursor_pagination, fuzzy_search, response_v2)
+// @Param managerId path string true "Manager id"
+// @Success 200 {object} helper.Response{data=dto.FeatureFlagOverrideResponse} "OK"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} helper.Response{errors=helper.ErrorResponse} "Forbidden"
+// @Failure 404 {object} helper.Response{errors=helper.ErrorResponse} "Unknown flag"
+// @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
+// @Router /v1/admin/feature-flags/{flag}/overrides/{managerId} [DELETE]
+func (h *handler) DeleteFeatureFlag(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+
+	flag := featureflag.Flag(ctx.Param("flag"))
+	managerID := ctx.Param("managerId")
+	if err := h.flags.SetOverride(ctx, flag, managerID, nil); err != nil {
+		h.featureFlagError(ctx, err, helper.AdminHandlerDeleteFeatureFlag)
+		return
+	}
+	ctx.JSON(http.StatusOK, helper.OK(dto.FeatureFlagOverrideResponse{
+		Flag:      string(flag),
+		ManagerID: managerID,
+		Enabled:   h.flags.IsEnabled(ctx, flag, managerID),
+	}))
+}
+
+// Get the active log level
+// @Tags admin
+// @Summary Get the active log level
+// @Description Report the active log level, the configured default (LOG_LEVEL), when the level reverts to the default, if scheduled, and the slow-request and slow-query thresholds in use. Admin only.
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Success 200 {object} helper.Response{data=dto.LogLevelResponse} "OK"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} helper.Response{errors=helper.ErrorResponse} "Forbidden"
+// @Router /v1/admin/log-level [GET]
+func (h *handler) GetLogLevel(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, helper.OK(h.logLevelResponse()))
+}
+
+// Change the active log level
+// @Tags admin
+// @Summary Change the active log level
+// @Description Change the log level of this instance without a restart. When revertAfter is set (e.g. "15m") the level returns to the configured default after that duration. slowRequestThreshold and slowQueryThreshold (e.g. "2s", "200ms") replace SLOW_REQUEST_THRESHOLD and SLOW_QUERY_THRESHOLD until the next change or restart; they do not revert with revertAfter. Admin only.
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param data body dto.LogLevelRequest true "data"
+// @Success 200 {object} helper.Response{data=dto.LogLevelResponse} "OK"
+// @Failure 400 {object} helper.Response{errors=helper.ErrorResponse} "Bad Request"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} helper.Response{errors=helper.ErrorResponse} "Forbidden"
+// @Router /v1/admin/log-level [PUT]
+func (h *handler) SetLogLevel(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+
+	input := new(dto.LogLevelRequest)
+	if err := ctx.ShouldBindJSON(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.Error(http.StatusBadRequest, err))
+		return
+	}
+	change, err := validation.ValidateLogLevel(input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.Error(http.StatusBadRequest, err))
+		return
+	}
+
+	level, err := zapcore.ParseLevel(input.Level)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.Error(http.StatusBadRequest, err))
+		return
+	}
+	h.logLevel.Set(level, change.RevertAfter)
+	if change.SlowRequestThreshold > 0 {
+		logger.SetSlowRequestThreshold(change.SlowRequestThreshold)
+	}
+	if change.SlowQueryThreshold > 0 {
+		logger.SetSlowQueryThreshold(change.SlowQueryThreshold)
+	}
+
+	response := h.logLevelResponse()
+	h.logger.Warn("Log level changed", helper.AdminHandlerSetLogLevel, response)
+	ctx.JSON(http.StatusOK, helper.OK(response))
+}
+
+func (h *handler) logLevelResponse() dto.LogLevelResponse {
+	response := dto.LogLevelResponse{
+		Level:        h.logLevel.Current().String(),
+		DefaultLevel: h.logLevel.Default().String(),
+		// Threshold berlaku untuk seluruh proses, lihat logger.SlowRequestThreshold
+		SlowRequestThreshold: logger.SlowRequestThreshold().String(),
+		SlowQueryThreshold:   logger.SlowQueryThreshold().String(),
+	}
+	if revertAt := h.logLevel.RevertAt(); !revertAt.IsZero() {
+		response.RevertAt = &revertAt
+	}
+	return response
+}
+
+func (h *handler) featureFlagError(ctx *gin.Context, err error, caller helper.FunctionCaller) {
+	if !errors.Is(err, helper.ErrNotFound) {
+		h.logger.Error(err.Error(), caller)
+	}
+	ctx.JSON(helper.FromError(err))
+}
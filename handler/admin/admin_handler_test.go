@@ -0,0 +1,128 @@
+package adminHandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/featureflag"
+	"github.com/levensspel/go-gin-template/lru"
+	"github.com/levensspel/go-gin-template/mocks"
+)
+
+const testManagerID = "0b7c2d2e-4f4a-4b8e-9c59-2f1d8f6a3e10"
+
+// newFlagRouter memasang endpoint feature flag admin di atas FeatureFlags
+// asli dengan tabel override di memori. errWrite membuat setiap penulisan
+// override gagal.
+func newFlagRouter(errWrite error) (*gin.Engine, featureflag.FeatureFlags) {
+	overrides := map[string]bool{}
+	repo := &mocks.FeatureFlagRepository{
+		GetOverridesFunc: func(ctx context.Context, managerId string) (map[string]bool, error) {
+			copied := map[string]bool{}
+			for flag, enabled := range overrides {
+				copied[flag] = enabled
+			}
+			return copied, nil
+		},
+		SetOverrideFunc: func(ctx context.Context, flag, managerId string, enabled bool) error {
+			if errWrite != nil {
+				return errWrite
+			}
+			overrides[flag] = enabled
+			return nil
+		},
+		DeleteOverrideFunc: func(ctx context.Context, flag, managerId string) error {
+			if errWrite != nil {
+				return errWrite
+			}
+			delete(overrides, flag)
+			return nil
+		},
+	}
+	cache := lru.New[string, map[string]bool](lru.Settings{Name: "feature_flag_override", MaxEntries: 10, TTL: time.Hour})
+	flags := featureflag.New(repo, &config.FeatureFlagConfig{Enabled: []string{string(featureflag.FuzzySearch)}}, cache, mocks.Logger{})
+
+	gin.SetMode(gin.TestMode)
+	h := New(nil, nil, flags, nil, mocks.Logger{})
+	router := gin.New()
+	router.PUT("/v1/admin/feature-flags/:flag/overrides/:managerId", h.SetFeatureFlag)
+	router.DELETE("/v1/admin/feature-flags/:flag/overrides/:managerId", h.DeleteFeatureFlag)
+	return router, flags
+}
+
+func toggle(router *gin.Engine, method string, flag featureflag.Flag, body string) (*httptest.ResponseRecorder, dto.FeatureFlagOverrideResponse) {
+	w := httptest.NewRecorder()
+	target := "/v1/admin/feature-flags/" + string(flag) + "/overrides/" + testManagerID
+	router.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+	var response struct {
+		Data dto.FeatureFlagOverrideResponse `json:"data"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	return w, response.Data
+}
+
+func TestFeatureFlagToggle(t *testing.T) {
+	router, flags := newFlagRouter(nil)
+	ctx := context.Background()
+
+	// Matikan flag yang aktif secara default untuk satu manager
+	w, data := toggle(router, http.MethodPut, featureflag.FuzzySearch, `{"enabled": false}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PUT status = %d, body = %s", w.Code, w.Body)
+	}
+	if data.Flag != string(featureflag.FuzzySearch) || data.ManagerID != testManagerID || data.Enabled {
+		t.Fatalf("PUT data = %+v", data)
+	}
+	if flags.IsEnabled(ctx, featureflag.FuzzySearch, testManagerID) {
+		t.Fatal("flag still enabled after the override was set")
+	}
+
+	// Menghapus override mengembalikan default
+	w, data = toggle(router, http.MethodDelete, featureflag.FuzzySearch, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("DELETE status = %d, body = %s", w.Code, w.Body)
+	}
+	if !data.Enabled || !flags.IsEnabled(ctx, featureflag.FuzzySearch, testManagerID) {
+		t.Fatalf("DELETE data = %+v, want the default (enabled)", data)
+	}
+}
+
+func TestFeatureFlagToggleErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		method   string
+		flag     featureflag.Flag
+		body     string
+		errWrite error
+		want     int
+	}{
+		{name: "unknown flag", method: http.MethodPut, flag: "typo_flag", body: `{"enabled": true}`, want: http.StatusNotFound},
+		{name: "delete unknown flag", method: http.MethodDelete, flag: "typo_flag", want: http.StatusNotFound},
+		{name: "missing enabled", method: http.MethodPut, flag: featureflag.CursorPagination, body: `{}`, want: http.StatusBadRequest},
+		{name: "invalid json", method: http.MethodPut, flag: featureflag.CursorPagination, body: `{"enabled":`, want: http.StatusBadRequest},
+		{
+			name:     "repository error",
+			method:   http.MethodPut,
+			flag:     featureflag.CursorPagination,
+			body:     `{"enabled": true}`,
+			errWrite: errors.New("write failed"),
+			want:     http.StatusInternalServerError,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			router, _ := newFlagRouter(tt.errWrite)
+			if w, _ := toggle(router, tt.method, tt.flag, tt.body); w.Code != tt.want {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, tt.want, w.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package adminHandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/clock/clocktest"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/mocks"
+	"github.com/levensspel/go-gin-template/purge"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TestCleanupUnknownTable tidak butuh database: table yang tidak dikenal
+// ditolak sebelum lock cleanup diambil. Jalur 200 dan 409 diuji di
+// purge/cleaner_test.go dengan database asli.
+func TestCleanupUnknownTable(t *testing.T) {
+	deleted := false
+	cleaner := purge.NewCleaner(
+		context.Background(),
+		nil,
+		[]purge.CleanupTarget{{
+			Table: "outbox",
+			Delete: func(ctx context.Context, db database.Querier, retention time.Duration, limit int) (int64, error) {
+				deleted = true
+				return 0, nil
+			},
+		}},
+		&config.CleanupConfig{BatchSize: 10},
+		metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{}),
+		mocks.Logger{},
+		clocktest.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+	)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/v1/admin/cleanup", New(nil, cleaner, nil, nil, mocks.Logger{}).Cleanup)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/admin/cleanup?table=employees", nil))
+
+	var response struct {
+		Errors *helper.ErrorResponse `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode body %s: %v", w.Body, err)
+	}
+	if w.Code != http.StatusBadRequest || response.Errors == nil || response.Errors.Code != http.StatusBadRequest {
+		t.Fatalf("POST status = %d, body = %s, want 400", w.Code, w.Body)
+	}
+	if deleted {
+		t.Fatal("cleanup ran for an unknown table")
+	}
+}
@@ -0,0 +1,225 @@
+package adminHandler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/levensspel/go-gin-template/clock/clocktest"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/middleware"
+	"github.com/levensspel/go-gin-template/mocks"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap/zapcore"
+)
+
+var logLevelStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func newLogLevelRouter(level *logger.Level) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := New(nil, nil, nil, level, mocks.Logger{})
+	router := gin.New()
+	router.GET("/v1/admin/log-level", h.GetLogLevel)
+	router.PUT("/v1/admin/log-level", h.SetLogLevel)
+	return router
+}
+
+func logLevelRequest(t *testing.T, router *gin.Engine, method, body string) (*httptest.ResponseRecorder, dto.LogLevelResponse) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, "/v1/admin/log-level", strings.NewReader(body)))
+	var response struct {
+		Data   dto.LogLevelResponse  `json:"data"`
+		Errors *helper.ErrorResponse `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode body %s: %v", w.Body, err)
+	}
+	return w, response.Data
+}
+
+func TestSetLogLevelWithRevert(t *testing.T) {
+	fake := clocktest.NewFake(logLevelStart)
+	level := logger.NewLevel(zapcore.InfoLevel, fake)
+	router := newLogLevelRouter(level)
+
+	w, got := logLevelRequest(t, router, http.MethodPut, `{"level":"debug","revertAfter":"15m"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PUT status = %d, body = %s", w.Code, w.Body)
+	}
+	wantRevert := logLevelStart.Add(15 * time.Minute)
+	if got.Level != "debug" || got.DefaultLevel != "info" || got.RevertAt == nil || !got.RevertAt.Equal(wantRevert) {
+		t.Fatalf("PUT = %+v, want debug reverting at %v", got, wantRevert)
+	}
+	if !level.Enabled(zapcore.DebugLevel) {
+		t.Fatal("debug entries are still suppressed after PUT")
+	}
+
+	fake.BlockUntil(1)
+	fake.Advance(15 * time.Minute)
+	deadline := time.Now().Add(5 * time.Second)
+	for level.Enabled(zapcore.DebugLevel) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if level.Enabled(zapcore.DebugLevel) {
+		t.Fatal("debug entries still pass after revertAfter elapsed")
+	}
+
+	w, got = logLevelRequest(t, router, http.MethodGet, "")
+	if w.Code != http.StatusOK || got.Level != "info" || got.DefaultLevel != "info" || got.RevertAt != nil {
+		t.Fatalf("GET after revert = %d %+v, want info without revertAt", w.Code, got)
+	}
+}
+
+func TestSetLogLevelWithoutRevert(t *testing.T) {
+	level := logger.NewLevel(zapcore.InfoLevel, clocktest.NewFake(logLevelStart))
+	router := newLogLevelRouter(level)
+
+	w, got := logLevelRequest(t, router, http.MethodPut, `{"level":"error"}`)
+	if w.Code != http.StatusOK || got.Level != "error" || got.RevertAt != nil {
+		t.Fatalf("PUT = %d %+v, want error without revertAt", w.Code, got)
+	}
+	if level.Enabled(zapcore.WarnLevel) {
+		t.Fatal("warn entries still pass at error level")
+	}
+}
+
+// slowLogger menyimpan pesan Warn dari AccessLog dan SlowQueryTracer
+// beserta threshold_ms-nya.
+type slowLogger struct {
+	mocks.Logger
+	mu       sync.Mutex
+	warnings []string
+}
+
+func (l *slowLogger) Warn(msg string, function helper.FunctionCaller, data ...interface{}) {
+	entry, _ := data[0].(map[string]any)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.warnings = append(l.warnings, fmt.Sprintf("%s %v", msg, entry["threshold_ms"]))
+}
+
+func (l *slowLogger) With(fields map[string]any) logger.Logger {
+	return l
+}
+
+func (l *slowLogger) WithContext(ctx context.Context) logger.Logger {
+	return l
+}
+
+func (l *slowLogger) take() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	warnings := l.warnings
+	l.warnings = nil
+	return warnings
+}
+
+// TestSetLogLevelThresholds memastikan threshold dari PUT langsung dipakai
+// log slow request (middleware.AccessLog) dan slow query
+// (database.SlowQueryTracer), lalu dilaporkan GET.
+func TestSetLogLevelThresholds(t *testing.T) {
+	previousRequest, previousQuery := logger.SlowRequestThreshold(), logger.SlowQueryThreshold()
+	t.Cleanup(func() {
+		logger.SetSlowRequestThreshold(previousRequest)
+		logger.SetSlowQueryThreshold(previousQuery)
+	})
+	logger.SetSlowRequestThreshold(time.Minute)
+	logger.SetSlowQueryThreshold(time.Minute)
+
+	log := &slowLogger{}
+	router := newLogLevelRouter(logger.NewLevel(zapcore.InfoLevel, clocktest.NewFake(logLevelStart)))
+	slow := gin.New()
+	slow.Use(middleware.AccessLog(log, metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{}), nil, 0))
+	slow.GET("/v1/employee", func(c *gin.Context) {
+		time.Sleep(30 * time.Millisecond)
+		c.Status(http.StatusOK)
+	})
+	tracer := database.NewSlowQueryTracer(log)
+	run := func() []string {
+		slow.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/employee", nil))
+		ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "SELECT 1;"})
+		time.Sleep(30 * time.Millisecond)
+		tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+		return log.take()
+	}
+
+	if got := run(); len(got) != 0 {
+		t.Fatalf("warnings before PUT = %v, want none", got)
+	}
+
+	w, got := logLevelRequest(t, router, http.MethodPut, `{"level":"info","slowRequestThreshold":"10ms","slowQueryThreshold":"5ms"}`)
+	if w.Code != http.StatusOK || got.SlowRequestThreshold != "10ms" || got.SlowQueryThreshold != "5ms" {
+		t.Fatalf("PUT = %d %+v, want thresholds 10ms and 5ms", w.Code, got)
+	}
+	if got, want := run(), []string{"slow request 10", "slow query 5"}; !slices.Equal(got, want) {
+		t.Fatalf("warnings after PUT = %v, want %v", got, want)
+	}
+
+	// Tanpa threshold di body, threshold sebelumnya tetap dipakai
+	if w, got := logLevelRequest(t, router, http.MethodPut, `{"level":"warn"}`); w.Code != http.StatusOK || got.SlowRequestThreshold != "10ms" || got.SlowQueryThreshold != "5ms" {
+		t.Fatalf("PUT without thresholds = %d %+v, want them unchanged", w.Code, got)
+	}
+	if w, got := logLevelRequest(t, router, http.MethodGet, ""); w.Code != http.StatusOK || got.SlowRequestThreshold != "10ms" || got.SlowQueryThreshold != "5ms" {
+		t.Fatalf("GET = %d %+v, want thresholds 10ms and 5ms", w.Code, got)
+	}
+}
+
+func TestSetLogLevelInvalid(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		body      string
+		wantField string
+	}{
+		{name: "invalid json", body: `{"level":`},
+		{name: "missing level", body: `{}`, wantField: "level"},
+		{name: "unknown level", body: `{"level":"verbose"}`, wantField: "level"},
+		{name: "zap only level", body: `{"level":"fatal"}`, wantField: "level"},
+		{name: "invalid duration", body: `{"level":"debug","revertAfter":"soon"}`, wantField: "revertAfter"},
+		{name: "negative duration", body: `{"level":"debug","revertAfter":"-5m"}`, wantField: "revertAfter"},
+		{name: "invalid slow request threshold", body: `{"level":"debug","slowRequestThreshold":"fast"}`, wantField: "slowRequestThreshold"},
+		{name: "zero slow query threshold", body: `{"level":"debug","slowQueryThreshold":"0s"}`, wantField: "slowQueryThreshold"},
+		{name: "negative slow query threshold", body: `{"level":"debug","slowQueryThreshold":"-1s"}`, wantField: "slowQueryThreshold"},
+		// Threshold yang valid tidak dipakai jika field lain ditolak
+		{name: "valid threshold with invalid one", body: `{"level":"debug","slowRequestThreshold":"5ms","slowQueryThreshold":"soon"}`, wantField: "slowQueryThreshold"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			level := logger.NewLevel(zapcore.InfoLevel, clocktest.NewFake(logLevelStart))
+			router := newLogLevelRouter(level)
+			slowRequest, slowQuery := logger.SlowRequestThreshold(), logger.SlowQueryThreshold()
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/v1/admin/log-level", strings.NewReader(tt.body)))
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400, body = %s", w.Code, w.Body)
+			}
+			var response helper.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantField != "" && response.Errors.Fields[tt.wantField] == "" {
+				t.Fatalf("fields = %v, want %s", response.Errors.Fields, tt.wantField)
+			}
+			if level.Current() != zapcore.InfoLevel {
+				t.Fatalf("level = %s after a rejected request, want info", level.Current())
+			}
+			if logger.SlowRequestThreshold() != slowRequest || logger.SlowQueryThreshold() != slowQuery {
+				t.Fatalf("thresholds = %s, %s after a rejected request, want %s, %s", logger.SlowRequestThreshold(), logger.SlowQueryThreshold(), slowRequest, slowQuery)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package apiKeyHandler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/middleware"
+	service "github.com/levensspel/go-gin-template/service/apikey"
+	"github.com/levensspel/go-gin-template/validation"
+	"github.com/samber/do/v2"
+)
+
+type APIKeyHandler interface {
+	Create(ctx *gin.Context)
+	GetAll(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+}
+
+type handler struct {
+	service service.APIKeyService
+	logger  logger.Logger
+}
+
+func New(service service.APIKeyService, logger logger.Logger) APIKeyHandler {
+	return &handler{service: service, logger: logger}
+}
+
+func NewInject(i do.Injector) (APIKeyHandler, error) {
+	_service := do.MustInvoke[service.APIKeyService](i)
+	_logger := do.MustInvoke[logger.LogHandler](i)
+	return New(_service, &_logger), nil
+}
+
+// Create an API key
+// @Tags api-key
+// @Summary Create an API key
+// @Description Create a read-only API key for external tools. Send it in the X-API-Key header instead of a bearer token. A key can only read the route groups of its scopes (employees:read for /v1/employee, departments:read for /v1/department); every other request with the key returns 403. The key is only returned here.
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param data body dto.APIKeyRequest true "data"
+// @Success 201 {object} helper.Response{data=dto.APIKeyResponse} "Created"
+// @Failure 400 {object} helper.Response{errors=helper.ErrorResponse} "Bad Request"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
+// @Router /v1/api-keys [POST]
+func (h *handler) Create(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+
+	managerID, err := middleware.GetIdUserFromContext(ctx)
+	if err != nil {
+		h.logger.Warn(err.Error(), helper.APIKeyHandlerCreate)
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
+		return
+	}
+
+	var input dto.APIKeyRequest
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		h.logger.Warn(err.Error(), helper.APIKeyHandlerCreate)
+		ctx.JSON(http.StatusBadRequest, helper.Error(http.StatusBadRequest, err))
+		return
+	}
+	if err := validation.ValidateAPIKeyCreate(&input); err != nil {
+		h.logger.Warn(err.Error(), helper.APIKeyHandlerCreate, input.Name)
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+
+	response, err := h.service.Create(ctx, managerID, input)
+	if err != nil {
+		h.logger.Warn(err.Error(), helper.APIKeyHandlerCreate, input.Name)
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+	ctx.JSON(http.StatusCreated, helper.Created(response))
+}
+
+// List API keys
+// @Tags api-key
+// @Summary List API keys
+// @Description List the API keys of the current manager with their granted scopes. The keys themselves are not returned, only their prefix.
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Success 200 {object} helper.Response{data=[]dto.APIKeyResponse} "OK"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
+// @Router /v1/api-keys [GET]
+func (h *handler) GetAll(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+
+	managerID, err := middleware.GetIdUserFromContext(ctx)
+	if err != nil {
+		h.logger.Warn(err.Error(), helper.APIKeyHandlerGetAll)
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
+		return
+	}
+
+	keys, err := h.service.GetAll(ctx, managerID)
+	if err != nil {
+		h.logger.Error(err.Error(), helper.APIKeyHandlerGetAll, err)
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, helper.OK(keys))
+}
+
+// Delete an API key
+// @Tags api-key
+// @Summary Delete an API key
+// @Description Revoke an API key. Requests with the key are rejected right away.
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param id path string true "API key ID"
+// @Success 200 {object} helper.Response "OK"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 404 {object} helper.Response{errors=helper.ErrorResponse} "Not Found"
+// @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
+// @Router /v1/api-keys/{id} [DELETE]
+func (h *handler) Delete(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+
+	managerID, err := middleware.GetIdUserFromContext(ctx)
+	if err != nil {
+		h.logger.Warn(err.Error(), helper.APIKeyHandlerDelete)
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
+		return
+	}
+
+	if err := h.service.Delete(ctx, ctx.Param("id"), managerID); err != nil {
+		h.logger.Warn(err.Error(), helper.APIKeyHandlerDelete, ctx.Param("id"))
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, helper.OK(nil))
+}
@@ -0,0 +1,157 @@
+package apiKeyHandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/mocks"
+	service "github.com/levensspel/go-gin-template/service/apikey"
+)
+
+const testManagerID = "0b7c2d2e-4f4a-4b8e-9c59-2f1d8f6a3e10"
+
+// fakeService mencatat input Create dan Delete, Authenticate panic karena
+// interface-nya nil.
+type fakeService struct {
+	service.APIKeyService
+	created   []dto.APIKeyRequest
+	deletedID string
+	keys      []dto.APIKeyResponse
+	err       error
+}
+
+func (f *fakeService) Create(ctx context.Context, managerID string, input dto.APIKeyRequest) (dto.APIKeyResponse, error) {
+	f.created = append(f.created, input)
+	return dto.APIKeyResponse{Id: "key-1", Name: input.Name, Prefix: "psk_abcdefgh", Key: "psk_abcdefgh-secret", Scopes: input.Scopes}, f.err
+}
+
+func (f *fakeService) GetAll(ctx context.Context, managerID string) ([]dto.APIKeyResponse, error) {
+	return f.keys, f.err
+}
+
+func (f *fakeService) Delete(ctx context.Context, id string, managerID string) error {
+	f.deletedID = id
+	return f.err
+}
+
+func serve(f *fakeService, method, target, body, managerID string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	h := New(f, mocks.Logger{})
+	router := gin.New()
+	router.Use(func(ctx *gin.Context) {
+		if managerID != "" {
+			ctx.Set(helper.ContextKeyUserID, managerID)
+		}
+	})
+	router.POST("/v1/api-keys", h.Create)
+	router.GET("/v1/api-keys", h.GetAll)
+	router.DELETE("/v1/api-keys/:id", h.Delete)
+
+	w := httptest.NewRecorder()
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, request)
+	return w
+}
+
+func decodeResponse(t *testing.T, w *httptest.ResponseRecorder, data any) helper.Response {
+	t.Helper()
+	response := helper.Response{Data: data}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode body %s: %v", w.Body, err)
+	}
+	return response
+}
+
+func TestCreate(t *testing.T) {
+	f := &fakeService{}
+	w := serve(f, http.MethodPost, "/v1/api-keys", `{"name":"warehouse","scopes":["employees:read","departments:read"]}`, testManagerID)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d, body = %s", w.Code, http.StatusCreated, w.Body)
+	}
+	var created dto.APIKeyResponse
+	decodeResponse(t, w, &created)
+	if created.Key == "" || !slices.Equal(created.Scopes, []string{entity.APIKeyScopeEmployeesRead, entity.APIKeyScopeDepartmentsRead}) {
+		t.Fatalf("created = %+v", created)
+	}
+}
+
+func TestCreateRejects(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		body      string
+		managerID string
+		want      int
+		wantField string
+	}{
+		{name: "without user", body: `{"name":"warehouse","scopes":["employees:read"]}`, want: http.StatusUnauthorized},
+		{name: "not json", body: `{`, managerID: testManagerID, want: http.StatusBadRequest},
+		{name: "without scopes", body: `{"name":"warehouse","scopes":[]}`, managerID: testManagerID, want: http.StatusBadRequest, wantField: "scopes"},
+		{name: "unknown scope", body: `{"name":"warehouse","scopes":["employees:write"]}`, managerID: testManagerID, want: http.StatusBadRequest, wantField: "scopes[0]"},
+		{name: "duplicate scope", body: `{"name":"warehouse","scopes":["employees:read","employees:read"]}`, managerID: testManagerID, want: http.StatusBadRequest, wantField: "scopes"},
+		{name: "without name", body: `{"scopes":["employees:read"]}`, managerID: testManagerID, want: http.StatusBadRequest, wantField: "name"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeService{}
+			w := serve(f, http.MethodPost, "/v1/api-keys", tt.body, tt.managerID)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, tt.want, w.Body)
+			}
+			if len(f.created) != 0 {
+				t.Fatalf("service was called with %+v", f.created)
+			}
+			if tt.wantField != "" {
+				if fields := decodeResponse(t, w, nil).Errors.Fields; fields[tt.wantField] == "" {
+					t.Fatalf("field errors = %v, want %s", fields, tt.wantField)
+				}
+			}
+		})
+	}
+}
+
+func TestGetAll(t *testing.T) {
+	f := &fakeService{keys: []dto.APIKeyResponse{{Id: "key-1", Prefix: "psk_abcdefgh", Scopes: []string{entity.APIKeyScopeDepartmentsRead}}}}
+	w := serve(f, http.MethodGet, "/v1/api-keys", "", testManagerID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+	if strings.Contains(w.Body.String(), `"key"`) {
+		t.Fatalf("body = %s, want keys without the key", w.Body)
+	}
+	var keys []dto.APIKeyResponse
+	decodeResponse(t, w, &keys)
+	if len(keys) != 1 || !slices.Equal(keys[0].Scopes, []string{entity.APIKeyScopeDepartmentsRead}) {
+		t.Fatalf("keys = %+v", keys)
+	}
+
+	if w := serve(f, http.MethodGet, "/v1/api-keys", "", ""); w.Code != http.StatusUnauthorized {
+		t.Fatalf("status without user = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	f := &fakeService{}
+	if w := serve(f, http.MethodDelete, "/v1/api-keys/key-1", "", testManagerID); w.Code != http.StatusOK || f.deletedID != "key-1" {
+		t.Fatalf("status = %d, deleted %q, body = %s", w.Code, f.deletedID, w.Body)
+	}
+
+	// Key manager lain tidak ditemukan
+	f = &fakeService{err: helper.ErrNotFound}
+	if w := serve(f, http.MethodDelete, "/v1/api-keys/key-2", "", testManagerID); w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d, body = %s", w.Code, http.StatusNotFound, w.Body)
+	}
+
+	f = &fakeService{}
+	if w := serve(f, http.MethodDelete, "/v1/api-keys/key-1", "", ""); w.Code != http.StatusUnauthorized || f.deletedID != "" {
+		t.Fatalf("status without user = %d, deleted %q", w.Code, f.deletedID)
+	}
+}
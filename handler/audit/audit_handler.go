@@ -0,0 +1,150 @@
+package auditHandler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/middleware"
+	service "github.com/levensspel/go-gin-template/service/audit"
+	"github.com/levensspel/go-gin-template/validation"
+	"github.com/samber/do/v2"
+)
+
+const defaultAuditLimit = 20
+
+type AuditHandler interface {
+	GetAll(ctx *gin.Context)
+	GetAllAdmin(ctx *gin.Context)
+}
+
+type handler struct {
+	service service.AuditService
+	logger  logger.Logger
+}
+
+func New(service service.AuditService, logger logger.Logger) AuditHandler {
+	return &handler{service: service, logger: logger}
+}
+
+func NewInject(i do.Injector) (AuditHandler, error) {
+	_service := do.MustInvoke[service.AuditService](i)
+	_logger := do.MustInvoke[logger.LogHandler](i)
+	return New(_service, &_logger), nil
+}
+
+// Get audit log
+// @Tags audit
+// @Summary Get audit log
+// @Description Get the audit log of the current manager, newest first. changes only contains the fields that changed.
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param entityType query string false "employee, department, or user"
+// @Param entityId query string false "Entity ID"
+// @Param from query string false "Start time (RFC3339, inclusive)"
+// @Param to query string false "End time (RFC3339, exclusive)"
+// @Param limit query int false "Limit (max 100)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} helper.Response{data=[]dto.AuditLogResponse} "OK"
+// @Failure 400 {object} helper.Response{errors=helper.ErrorResponse} "Bad Request"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
+// @Router /v1/audit [GET]
+func (h *handler) GetAll(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+
+	managerID, err := middleware.GetIdUserFromContext(ctx)
+	if err != nil {
+		h.logger.Warn(err.Error(), helper.AuditHandlerGetAll)
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
+		return
+	}
+
+	input, err := getAuditLogsRequest(ctx)
+	if err != nil {
+		h.logger.Warn(err.Error(), helper.AuditHandlerGetAll, input)
+		ctx.JSON(http.StatusBadRequest, helper.Error(http.StatusBadRequest, err))
+		return
+	}
+
+	logs, err := h.service.GetAll(ctx, managerID, input)
+	h.respond(ctx, logs, err, helper.AuditHandlerGetAll)
+}
+
+// Get audit log of all managers
+// @Tags admin
+// @Summary Get audit log of all managers
+// @Description Get the audit log across all managers, newest first. Admin only.
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param managerId query string false "Only this manager"
+// @Param entityType query string false "employee, department, or user"
+// @Param entityId query string false "Entity ID"
+// @Param from query string false "Start time (RFC3339, inclusive)"
+// @Param to query string false "End time (RFC3339, exclusive)"
+// @Param limit query int false "Limit (max 100)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} helper.Response{data=[]dto.AuditLogResponse} "OK"
+// @Failure 400 {object} helper.Response{errors=helper.ErrorResponse} "Bad Request"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 403 {object} helper.Response{errors=helper.ErrorResponse} "Forbidden"
+// @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
+// @Router /v1/admin/audit [GET]
+func (h *handler) GetAllAdmin(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+
+	input, err := getAuditLogsRequest(ctx)
+	if err != nil {
+		h.logger.Warn(err.Error(), helper.AuditHandlerGetAllAdmin, input)
+		ctx.JSON(http.StatusBadRequest, helper.Error(http.StatusBadRequest, err))
+		return
+	}
+
+	logs, err := h.service.GetAllAdmin(ctx, input)
+	h.respond(ctx, logs, err, helper.AuditHandlerGetAllAdmin)
+}
+
+func (h *handler) respond(ctx *gin.Context, logs []dto.AuditLogResponse, err error, caller helper.FunctionCaller) {
+	if err != nil {
+		h.logger.Error(err.Error(), caller)
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, helper.OK(logs))
+}
+
+func getAuditLogsRequest(ctx *gin.Context) (dto.GetAuditLogsRequest, error) {
+	input := dto.GetAuditLogsRequest{
+		ActorID:    ctx.Query("managerId"),
+		EntityType: ctx.Query("entityType"),
+		EntityID:   ctx.Query("entityId"),
+		Limit:      defaultAuditLimit,
+	}
+
+	for name, target := range map[string]**time.Time{"from": &input.From, "to": &input.To} {
+		value := ctx.Query(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return input, errors.New(name + " must be an RFC3339 time")
+		}
+		// Kolom created_at tanpa zona waktu dan diisi dengan jam database (UTC)
+		parsed = parsed.UTC()
+		*target = &parsed
+	}
+	if limit, err := strconv.Atoi(ctx.Query("limit")); err == nil {
+		input.Limit = limit
+	}
+	if offset, err := strconv.Atoi(ctx.Query("offset")); err == nil {
+		input.Offset = offset
+	}
+
+	return input, validation.ValidateAuditGet(&input)
+}
@@ -1,7 +1,7 @@
 package authHandler
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
 	"strings"
 
@@ -18,16 +18,16 @@ type AuthorizationHandler interface {
 }
 
 type handler struct {
-	service service.UserService
+	service service.IUserService
 	logger  logger.Logger
 }
 
-func NewHandler(service service.UserService, logger logger.Logger) AuthorizationHandler {
+func NewHandler(service service.IUserService, logger logger.Logger) AuthorizationHandler {
 	return &handler{service: service, logger: logger}
 }
 
 func NewHandlerInject(i do.Injector) (AuthorizationHandler, error) {
-	_service := do.MustInvoke[service.UserService](i)
+	_service := do.MustInvoke[service.IUserService](i)
 	_logger := do.MustInvoke[logger.LogHandler](i)
 	return NewHandler(_service, &_logger), nil
 }
@@ -44,11 +44,12 @@ func NewHandlerInject(i do.Injector) (AuthorizationHandler, error) {
 // @Failure 400 {object} helper.Response{errors=helper.ErrorResponse} "Bad Request"
 // @Router /v1/auth [POST]
 func (h handler) Post(ctx *gin.Context) {
+	log := h.logger.With(logger.RequestFields(ctx, helper.AuthHandlerPost)).WithContext(ctx.Request.Context())
 	input := new(dto.UserRequestPayload)
 
 	if err := ctx.ShouldBindJSON(&input); err != nil {
-		h.logger.Warn(err.Error(), helper.FunctionCaller("AuthHandler.Post"), &input)
-		ctx.JSON(http.StatusBadRequest, helper.NewResponse(nil, err))
+		log.Warn(err.Error(), logger.Bound)
+		ctx.JSON(http.StatusBadRequest, helper.Error(http.StatusBadRequest, err))
 		return
 	}
 
@@ -57,63 +58,37 @@ func (h handler) Post(ctx *gin.Context) {
 		// do register/create new user
 		modelState := make(map[string]string)
 		if input.Email == "" {
-			modelState["Email"] = "do not left Email empty"
+			modelState["email"] = "email is a required field"
 		}
 		if input.Password == "" {
-			modelState["Password"] = "do not left Password emtpy"
+			modelState["password"] = "password is a required field"
 		}
 		if len(modelState) == 0 {
-			h.logger.Info("Register", helper.FunctionCaller("AuthHander.Post"), input)
-			response, err := h.service.RegisterUser(*input)
-			h.logger.Info("After Register", helper.FunctionCaller("AuthHander.Post"), input)
+			log.Debug("Register", logger.Bound, input.Email)
+			response, err := h.service.RegisterUser(ctx, *input)
 			if err != nil {
-				ctx.JSON(
-					helper.GetErrorStatusCode(err),
-					helper.NewResponse(
-						helper.ErrorResponse{
-							Code:    helper.GetErrorStatusCode(err),
-							Message: "Either username, email, or choosen password has been selected",
-						},
-						err,
-					),
-				)
+				log.Warn(err.Error(), logger.Bound, input.Email)
+				ctx.JSON(helper.FromError(err))
 				return
 			}
-			h.logger.Info("Created", helper.FunctionCaller("AuthHander.Post"), response)
-			ctx.JSON(http.StatusCreated, helper.NewResponse(response, err))
+			log.Info("Created", logger.Bound, input.Email)
+			ctx.JSON(http.StatusCreated, helper.Created(response))
 		} else {
-			h.logger.Error("BadRequest", helper.FunctionCaller("AuthHander.Post"), modelState)
-			ctx.JSON(http.StatusBadRequest, helper.NewResponse(modelState, nil))
+			log.Warn("BadRequest", logger.Bound, modelState)
+			ctx.JSON(http.StatusBadRequest, helper.FieldErrors(modelState))
 		}
 	case dto.Login:
 		// do login
-		fmt.Printf("input %s", *input)
-		response, err := h.service.Login(*input)
+		log.Debug("Login", logger.Bound, input.Email)
+		response, err := h.service.Login(ctx, *input)
 
 		if err != nil {
-			ctx.JSON(
-				helper.GetErrorStatusCode(err),
-				helper.NewResponse(
-					helper.ErrorResponse{
-						Code:    helper.GetErrorStatusCode(err),
-						Message: err.Error(),
-					},
-					err,
-				),
-			)
+			log.Warn(err.Error(), logger.Bound, input.Email)
+			ctx.JSON(helper.FromError(err))
 			return
 		}
-		ctx.JSON(http.StatusOK, helper.Response{Data: response, Error: err})
+		ctx.JSON(http.StatusOK, helper.OK(response))
 	default:
-		ctx.JSON(
-			http.StatusBadRequest,
-			helper.NewResponse(
-				helper.ErrorResponse{
-					Code:    http.StatusBadRequest,
-					Message: "Action not found",
-				},
-				nil,
-			),
-		)
+		ctx.JSON(http.StatusBadRequest, helper.Error(http.StatusBadRequest, errors.New("Action not found")))
 	}
 }
@@ -0,0 +1,98 @@
+package authHandler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/mocks"
+)
+
+// recordingLogger menyimpan setiap pesan, data, dan field yang di-bind.
+type recordingLogger struct {
+	mu      *sync.Mutex
+	entries *[]string
+	fields  map[string]any
+}
+
+func newRecordingLogger() recordingLogger {
+	return recordingLogger{mu: &sync.Mutex{}, entries: &[]string{}, fields: map[string]any{}}
+}
+
+func (l recordingLogger) record(msg string, function helper.FunctionCaller, data []interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	*l.entries = append(*l.entries, fmt.Sprint(msg, function, data, l.fields))
+}
+
+func (l recordingLogger) Info(msg string, function helper.FunctionCaller, data ...interface{}) {
+	l.record(msg, function, data)
+}
+func (l recordingLogger) Error(msg string, function helper.FunctionCaller, data ...interface{}) {
+	l.record(msg, function, data)
+}
+func (l recordingLogger) Debug(msg string, function helper.FunctionCaller, data ...interface{}) {
+	l.record(msg, function, data)
+}
+func (l recordingLogger) Warn(msg string, function helper.FunctionCaller, data ...interface{}) {
+	l.record(msg, function, data)
+}
+
+func (l recordingLogger) With(fields map[string]any) logger.Logger {
+	child := recordingLogger{mu: l.mu, entries: l.entries, fields: map[string]any{}}
+	for key, value := range l.fields {
+		child.fields[key] = value
+	}
+	for key, value := range fields {
+		child.fields[key] = value
+	}
+	return child
+}
+
+func (l recordingLogger) WithContext(ctx context.Context) logger.Logger {
+	return l
+}
+
+func TestPostLogsWithoutPassword(t *testing.T) {
+	const password = "s3cret-password"
+	for _, tt := range []struct {
+		name string
+		body string
+	}{
+		{name: "register", body: `{"email":"a@example.com","password":"` + password + `","action":"create"}`},
+		{name: "login", body: `{"email":"a@example.com","password":"` + password + `","action":"login"}`},
+		{name: "invalid payload", body: `{"email":"not-an-email","password":"` + password + `","action":"create"}`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.UserService{
+				RegisterUserFunc: func(ctx context.Context, input dto.UserRequestPayload) (dto.ResponseRegister, error) {
+					return dto.ResponseRegister{}, errors.New("insert failed")
+				},
+				LoginFunc: func(ctx context.Context, input dto.UserRequestPayload) (dto.ResponseLogin, error) {
+					return dto.ResponseLogin{}, helper.ErrNotFound
+				},
+			}
+			log := newRecordingLogger()
+			post(NewHandler(service, log), tt.body)
+
+			if len(*log.entries) == 0 {
+				t.Fatal("handler logged nothing")
+			}
+			for _, entry := range *log.entries {
+				if strings.Contains(entry, password) {
+					t.Fatalf("log entry contains the password: %s", entry)
+				}
+				// called_by di-bind sekali lewat With
+				if !strings.Contains(entry, string(helper.AuthHandlerPost)) {
+					t.Fatalf("log entry without called_by: %s", entry)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package authHandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/mocks"
+)
+
+func post(h AuthorizationHandler, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/auth", strings.NewReader(body))
+	h.Post(ctx)
+	return w
+}
+
+func TestPost(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		serviceErr error
+		want       int
+		// wantCall adalah method service yang harus dipanggil
+		wantCall string
+		// wantFields adalah field yang harus ada di errors.fields
+		wantFields []string
+	}{
+		{name: "register", body: `{"email":"a@example.com","password":"password1","action":"create"}`, want: http.StatusCreated, wantCall: "RegisterUser"},
+		{name: "action is case insensitive", body: `{"email":"a@example.com","password":"password1","action":"CREATE"}`, want: http.StatusCreated, wantCall: "RegisterUser"},
+		{name: "login", body: `{"email":"a@example.com","password":"password1","action":"login"}`, want: http.StatusOK, wantCall: "Login"},
+		{name: "invalid json", body: `{"email":`, want: http.StatusBadRequest},
+		{name: "unknown action", body: `{"email":"a@example.com","password":"password1","action":"delete"}`, want: http.StatusBadRequest},
+		{
+			name:       "register without email and password",
+			body:       `{"action":"create"}`,
+			want:       http.StatusBadRequest,
+			wantFields: []string{"email", "password"},
+		},
+		{
+			name:       "email already registered",
+			body:       `{"email":"a@example.com","password":"password1","action":"create"}`,
+			serviceErr: helper.ErrConflict,
+			want:       http.StatusConflict,
+			wantCall:   "RegisterUser",
+		},
+		{
+			name:       "register fails",
+			body:       `{"email":"a@example.com","password":"password1","action":"create"}`,
+			serviceErr: errors.New("insert failed"),
+			want:       http.StatusInternalServerError,
+			wantCall:   "RegisterUser",
+		},
+		{
+			name:       "unknown user",
+			body:       `{"email":"a@example.com","password":"password1","action":"login"}`,
+			serviceErr: helper.ErrNotFound,
+			want:       http.StatusNotFound,
+			wantCall:   "Login",
+		},
+		{
+			name:       "login fails",
+			body:       `{"email":"a@example.com","password":"password1","action":"login"}`,
+			serviceErr: errors.New("lookup failed"),
+			want:       http.StatusInternalServerError,
+			wantCall:   "Login",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called string
+			service := &mocks.UserService{
+				RegisterUserFunc: func(ctx context.Context, input dto.UserRequestPayload) (dto.ResponseRegister, error) {
+					called = "RegisterUser"
+					return dto.ResponseRegister{Email: input.Email, Token: "token"}, tt.serviceErr
+				},
+				LoginFunc: func(ctx context.Context, input dto.UserRequestPayload) (dto.ResponseLogin, error) {
+					called = "Login"
+					return dto.ResponseLogin{Email: input.Email, Token: "token"}, tt.serviceErr
+				},
+			}
+			w := post(NewHandler(service, mocks.Logger{}), tt.body)
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, tt.want, w.Body)
+			}
+			if called != tt.wantCall {
+				t.Fatalf("service method called = %q, want %q", called, tt.wantCall)
+			}
+			var response struct {
+				Data   dto.ResponseLogin     `json:"data"`
+				Errors *helper.ErrorResponse `json:"errors"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("decode body %s: %v", w.Body, err)
+			}
+			if tt.want >= http.StatusBadRequest {
+				if response.Errors == nil || response.Errors.Code != tt.want {
+					t.Fatalf("errors = %+v, want code %d", response.Errors, tt.want)
+				}
+				for _, field := range tt.wantFields {
+					if response.Errors.Fields[field] == "" {
+						t.Fatalf("errors.fields = %v, want %s", response.Errors.Fields, field)
+					}
+				}
+				return
+			}
+			if response.Data.Email != "a@example.com" || response.Data.Token != "token" {
+				t.Fatalf("data = %+v", response.Data)
+			}
+		})
+	}
+}
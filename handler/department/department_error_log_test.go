@@ -0,0 +1,95 @@
+package departmentHandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/mocks"
+)
+
+// recordingLogger menyimpan pesan Error dan Warn.
+type recordingLogger struct {
+	mocks.Logger
+	mu       sync.Mutex
+	messages []string
+}
+
+func (l *recordingLogger) Error(msg string, function helper.FunctionCaller, data ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.messages = append(l.messages, msg)
+}
+
+func (l *recordingLogger) Warn(msg string, function helper.FunctionCaller, data ...interface{}) {
+	l.Error(msg, function, data...)
+}
+
+func (l *recordingLogger) With(fields map[string]any) logger.Logger {
+	return l
+}
+
+func (l *recordingLogger) WithContext(ctx context.Context) logger.Logger {
+	return l
+}
+
+// TestRepositoryErrorIsLoggedWithQueryName memastikan nama query ada di log
+// tetapi tidak mengubah status dan pesan response.
+func TestRepositoryErrorIsLoggedWithQueryName(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantLog     string
+		wantMessage string
+	}{
+		{
+			name:        "conflict",
+			err:         &database.RepoError{Query: "department.delete_count_employees", Err: helper.ErrConflict},
+			wantStatus:  http.StatusConflict,
+			wantLog:     "department.delete_count_employees: data conflict",
+			wantMessage: "still contain employee(s)",
+		},
+		{
+			name:        "query timeout",
+			err:         &database.RepoError{Query: "department.delete", Err: helper.ErrQueryTimeout},
+			wantStatus:  http.StatusGatewayTimeout,
+			wantLog:     "department.delete: query timeout",
+			wantMessage: helper.GetErrorMessage(helper.ErrQueryTimeout),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			log := &recordingLogger{}
+			h := New(writeService{err: tt.err}, log, 0)
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request = httptest.NewRequest(http.MethodDelete, "/v1/department/"+testDeptID, nil)
+			ctx.Params = gin.Params{{Key: "id", Value: testDeptID}}
+			ctx.Set(helper.ContextKeyUserID, testManagerID)
+			h.Delete(ctx)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if len(log.messages) != 1 || log.messages[0] != tt.wantLog {
+				t.Fatalf("logged %q, want [%q]", log.messages, tt.wantLog)
+			}
+			var response helper.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			if response.Errors.Message != tt.wantMessage {
+				t.Fatalf("message = %q, want %q", response.Errors.Message, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -7,14 +7,20 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/config"
 	"github.com/levensspel/go-gin-template/dto"
 	"github.com/levensspel/go-gin-template/helper"
 	"github.com/levensspel/go-gin-template/logger"
 	"github.com/levensspel/go-gin-template/middleware"
 	service "github.com/levensspel/go-gin-template/service/department"
+	"github.com/levensspel/go-gin-template/validation"
 	"github.com/samber/do/v2"
 )
 
+// departmentListParams adalah semua query GET /v1/department, tidak ada
+// yang boleh diulang.
+var departmentListParams = validation.QueryParams{Scalars: []string{"limit", "offset", "name"}}
+
 type DepartmentHandler interface {
 	Create(ctx *gin.Context)
 	GetAll(ctx *gin.Context)
@@ -25,19 +31,23 @@ type DepartmentHandler interface {
 type handler struct {
 	service service.DepartmentService
 	logger  logger.Logger
+	// trustedProxyDepth dipakai untuk scheme dan host header Link, lihat
+	// middleware.ResolveBaseURL
+	trustedProxyDepth int
 }
 
 func New(
 	service service.DepartmentService,
 	logger logger.Logger,
+	trustedProxyDepth int,
 ) DepartmentHandler {
-	return &handler{service: service, logger: logger}
+	return &handler{service: service, logger: logger, trustedProxyDepth: trustedProxyDepth}
 }
 
 func NewInject(i do.Injector) (DepartmentHandler, error) {
 	_service := do.MustInvoke[service.DepartmentService](i)
 	_logger := do.MustInvoke[logger.LogHandler](i)
-	return New(_service, &_logger), nil
+	return New(_service, &_logger, config.LoadAdminConfig().TrustedProxyDepth), nil
 }
 
 // Create a new department
@@ -57,32 +67,32 @@ func (h *handler) Create(ctx *gin.Context) {
 	managerID, err := middleware.GetIdUserFromContext(ctx)
 	if err != nil {
 		h.logger.Warn(err.Error(), helper.DepartmentHandlerCreate)
-		ctx.JSON(helper.GetErrorStatusCode(err), helper.NewResponse(nil, err))
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
 		return
 	}
 	input := new(dto.RequestDepartment)
 	if err := ctx.ShouldBindJSON(&input); err != nil {
 		h.logger.Warn(err.Error(), helper.DepartmentHandlerCreate, input)
-		ctx.JSON(helper.GetErrorStatusCode(err), helper.NewResponse(nil, err))
+		ctx.JSON(http.StatusBadRequest, helper.Error(http.StatusBadRequest, err))
 		return
 	}
-	response, err := h.service.Create(managerID, *input)
+	response, err := h.service.Create(ctx, managerID, *input)
 	if errors.Is(err, helper.ErrBadRequest) {
 		h.logger.Error(err.Error(), helper.DepartmentHandlerCreate)
-		ctx.JSON(helper.GetErrorStatusCode(err), helper.NewResponse(nil, err))
+		ctx.JSON(helper.FromError(err))
 		return
 	} else if err != nil {
 		h.logger.Error(err.Error(), helper.DepartmentHandlerCreate, err)
-		ctx.JSON(http.StatusInternalServerError, helper.NewResponse(nil, err))
+		ctx.JSON(http.StatusInternalServerError, helper.Error(http.StatusInternalServerError, err))
 		return
 	}
-	ctx.JSON(http.StatusOK, response)
+	ctx.JSON(http.StatusCreated, helper.Created(response))
 }
 
 // List all available departments
 // @Tags department
 // @Summary Fetch a list of all departments
-// @Description List all available departments
+// @Description List all available departments. Unknown or repeated query parameters return 400. The Link header (RFC 8288) holds absolute first, prev, next and last page URLs.
 // @Accept json
 // @Produce json
 // @Param limit query int false "limit query param"
@@ -90,6 +100,8 @@ func (h *handler) Create(ctx *gin.Context) {
 // @Param name query string false "department name"
 // @Param Authorization header string true "Bearer JWT token"
 // @Success 200 {object} helper.Response{data=helper.Response} "Created"
+// @Header 200 {string} Link "first, prev, next and last page URLs"
+// @Failure 400 {object} helper.Response{errors=helper.ErrorResponse} "Bad Request"
 // @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
 // @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
 // @Router /v1/department [GET]
@@ -97,7 +109,11 @@ func (h *handler) GetAll(ctx *gin.Context) {
 	managerID, err := middleware.GetIdUserFromContext(ctx)
 	if err != nil {
 		h.logger.Warn(err.Error(), helper.DepartmentHandlerCreate)
-		ctx.JSON(helper.GetErrorStatusCode(err), helper.NewResponse(nil, err))
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
+		return
+	}
+	if err := validation.ValidateQuery(ctx.Request.URL.Query(), departmentListParams); err != nil {
+		ctx.JSON(helper.FromError(err))
 		return
 	}
 	limit := h.getQueryInt(ctx, "limit", 5)
@@ -113,13 +129,24 @@ func (h *handler) GetAll(ctx *gin.Context) {
 	input.DepartmentName = name
 	input.Limit = limit
 	input.Offset = offset
-	response, err := h.service.GetAll(managerID, input)
+	response, err := h.service.GetAll(ctx, managerID, input)
 	if err != nil {
 		h.logger.Error(err.Error(), helper.FunctionCaller("handler.GetAll"))
-		ctx.JSON(http.StatusBadGateway, helper.NewResponse(nil, err))
+		ctx.JSON(http.StatusBadGateway, helper.Error(http.StatusBadGateway, err))
 		return
 	}
-	ctx.JSON(http.StatusOK, response)
+	// limit=0 tidak punya halaman lain, sehingga tidak ada header Link
+	if limit > 0 {
+		total, err := h.service.Count(ctx, managerID, input)
+		if err != nil {
+			h.logger.Error(err.Error(), helper.DepartmentHandlerGetAll)
+			ctx.JSON(http.StatusBadGateway, helper.Error(http.StatusBadGateway, err))
+			return
+		}
+		base := middleware.ResolveBaseURL(ctx.Request, h.trustedProxyDepth)
+		ctx.Writer.Header().Set("Link", helper.PageLinks(base, ctx.Request.URL, limit, offset, total))
+	}
+	ctx.JSON(http.StatusOK, helper.OK(response))
 }
 
 // Update a single record of department
@@ -130,72 +157,81 @@ func (h *handler) GetAll(ctx *gin.Context) {
 // @Produce json
 // @Param Authorization header string true "Bearer + user token"
 // @Param data body dto.RequestDepartment true "data"
-// @Param id path string true "department ID"
+// @Param id path string true "department ID (UUID)"
 // @Success 200 {object} helper.Response{data=helper.Response} "Created"
+// @Failure 400 {object} helper.Response{errors=helper.ErrorResponse} "Invalid department ID"
 // @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 404 {object} helper.Response{errors=helper.ErrorResponse} "Department not found"
 // @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
 // @Router /v1/department/{id} [PATCH]
 func (h *handler) Update(ctx *gin.Context) {
 	managerID, err := middleware.GetIdUserFromContext(ctx)
 	if err != nil {
 		h.logger.Warn(err.Error(), helper.DepartmentHandlerPatch)
-		ctx.JSON(helper.GetErrorStatusCode(err), helper.NewResponse(nil, err))
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
 		return
 	}
 	deptID := ctx.Param("id")
 	input := new(dto.RequestDepartment)
 	if err := ctx.ShouldBindJSON(&input); err != nil {
 		h.logger.Warn(err.Error(), helper.DepartmentHandlerPatch, input)
-		ctx.JSON(helper.GetErrorStatusCode(err), helper.NewResponse(nil, err))
+		ctx.JSON(http.StatusBadRequest, helper.Error(http.StatusBadRequest, err))
 		return
 	}
-	response, err := h.service.Update(input.DepartmentName, deptID, managerID)
+	response, err := h.service.Update(ctx, input.DepartmentName, deptID, managerID)
 	if err != nil {
 		h.logger.Error(err.Error(), helper.DepartmentHandlerPatch)
-		ctx.JSON(http.StatusBadGateway, helper.NewResponse(nil, err))
+		// Department manager lain dijawab sama dengan department yang tidak ada
+		if errors.Is(err, helper.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, helper.Error(http.StatusNotFound, fmt.Errorf("%s is not found", deptID)))
+			return
+		}
+		ctx.JSON(helper.FromError(err))
 		return
 	}
-	ctx.JSON(http.StatusOK, response)
+	ctx.JSON(http.StatusOK, helper.OK(response))
 }
 
 // Delete a department
 // @Tags department
 // @Summary Delete a department
-// @Description Delete a department
+// @Description Delete a department. With moveTo, the department's employees are moved to that department first in the same transaction.
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer + user token"
 // @Param id path string true "department ID"
+// @Param moveTo query string false "department ID to move the employees to"
 // @Success 200 {object} helper.Response{data=helper.Response} "Created"
 // @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 404 {object} helper.Response{errors=helper.ErrorResponse} "Department not found"
 // @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
 // @Router /v1/department/{id} [DELETE]
 func (h *handler) Delete(ctx *gin.Context) {
 	managerID, err := middleware.GetIdUserFromContext(ctx)
 	if err != nil {
 		h.logger.Error(err.Error(), helper.DepartmentHandlerDelete)
-		ctx.JSON(helper.GetErrorStatusCode(err), helper.NewResponse(nil, err))
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
 		return
 	}
 	deptID := ctx.Param("id")
 	if deptID == "" {
 		h.logger.Error(err.Error(), helper.DepartmentHandlerDelete)
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID is required"})
+		ctx.JSON(http.StatusBadRequest, helper.Error(http.StatusBadRequest, errors.New("ID is required")))
 		return
 	}
-	err = h.service.Delete(deptID, managerID)
+	err = h.service.Delete(ctx, deptID, managerID, ctx.Query("moveTo"))
 	if err != nil {
 		if errors.Is(err, helper.ErrNotFound) {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("%s is not found", deptID)})
+			ctx.JSON(http.StatusNotFound, helper.Error(http.StatusNotFound, fmt.Errorf("%s is not found", deptID)))
 		} else if errors.Is(err, helper.ErrConflict) {
-			ctx.JSON(http.StatusConflict, gin.H{"error": "still contain employee(s)"})
+			ctx.JSON(http.StatusConflict, helper.Error(http.StatusConflict, errors.New("still contain employee(s)")))
 		} else {
-			ctx.JSON(helper.GetErrorStatusCode(err), helper.NewResponse(nil, err))
+			ctx.JSON(helper.FromError(err))
 		}
 		h.logger.Error(err.Error(), helper.DepartmentHandlerDelete, err)
 		return
 	}
-	ctx.JSON(http.StatusOK, gin.H{"result": "the department has been successfully deleted"})
+	ctx.JSON(http.StatusOK, helper.OK(map[string]interface{}{"message": "the department has been successfully deleted"}))
 }
 
 func (h *handler) getQueryInt(ctx *gin.Context, key string, defaultValue int) int {
@@ -0,0 +1,136 @@
+package departmentHandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/mocks"
+	service "github.com/levensspel/go-gin-template/service/department"
+)
+
+const testManagerID = "0b7c2d2e-4f4a-4b8e-9c59-2f1d8f6a3e10"
+
+// fakeService hanya mengimplementasikan GetAll dan Count, method lain
+// panic karena interface-nya nil.
+type fakeService struct {
+	service.DepartmentService
+	total    int64
+	countErr error
+	counted  int
+}
+
+func (f *fakeService) GetAll(ctx context.Context, managerID string, input dto.RequestDepartment) ([]dto.ResponseSingleDepartment, error) {
+	return []dto.ResponseSingleDepartment{}, nil
+}
+
+func (f *fakeService) Count(ctx context.Context, managerID string, input dto.RequestDepartment) (int64, error) {
+	f.counted++
+	return f.total, f.countErr
+}
+
+func getAll(h DepartmentHandler, target string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodGet, target, nil)
+	ctx.Request.Host = "api.example.com"
+	ctx.Set(helper.ContextKeyUserID, testManagerID)
+	h.GetAll(ctx)
+	return w
+}
+
+func TestGetAllLinkHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		total  int64
+		want   string
+	}{
+		{
+			name:   "first page",
+			target: "/v1/department",
+			total:  7,
+			want: `<http://api.example.com/v1/department?limit=5&offset=0>; rel="first", ` +
+				`<http://api.example.com/v1/department?limit=5&offset=5>; rel="next", ` +
+				`<http://api.example.com/v1/department?limit=5&offset=5>; rel="last"`,
+		},
+		{
+			name:   "name filter on the last page",
+			target: "/v1/department?name=eng&limit=2&offset=4",
+			total:  5,
+			want: `<http://api.example.com/v1/department?limit=2&name=eng&offset=0>; rel="first", ` +
+				`<http://api.example.com/v1/department?limit=2&name=eng&offset=2>; rel="prev", ` +
+				`<http://api.example.com/v1/department?limit=2&name=eng&offset=4>; rel="last"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeService{total: tt.total}
+			w := getAll(New(fake, mocks.Logger{}, 0), tt.target)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+			}
+			if got := w.Header().Get("Link"); got != tt.want {
+				t.Fatalf("Link =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetAllWithoutLimitSkipsCount(t *testing.T) {
+	fake := &fakeService{}
+	w := getAll(New(fake, mocks.Logger{}, 0), "/v1/department?limit=0")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+	if fake.counted != 0 || w.Header().Get("Link") != "" {
+		t.Fatalf("counted = %d, Link = %q, want no count and no Link", fake.counted, w.Header().Get("Link"))
+	}
+}
+
+func TestGetAllCountError(t *testing.T) {
+	fake := &fakeService{countErr: errors.New("count failed")}
+	w := getAll(New(fake, mocks.Logger{}, 0), "/v1/department")
+	if w.Code != http.StatusBadGateway {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusBadGateway)
+	}
+	if w.Header().Get("Link") != "" {
+		t.Fatalf("Link = %q, want none on error", w.Header().Get("Link"))
+	}
+}
+
+func TestGetAllQueryParams(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		target string
+		field  string
+	}{
+		{name: "typo", target: "/v1/department?nama=eng", field: "nama"},
+		{name: "duplicate", target: "/v1/department?limit=1&limit=2", field: "limit"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeService{}
+			w := getAll(New(fake, mocks.Logger{}, 0), tt.target)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, http.StatusBadRequest, w.Body)
+			}
+			var response helper.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			if response.Errors == nil || len(response.Errors.Fields) != 1 || response.Errors.Fields[tt.field] == "" {
+				t.Fatalf("body = %s, want a %s field error", w.Body, tt.field)
+			}
+			if fake.counted != 0 {
+				t.Fatalf("counted = %d, want no service call", fake.counted)
+			}
+		})
+	}
+}
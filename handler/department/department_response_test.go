@@ -0,0 +1,110 @@
+package departmentHandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/mocks"
+	service "github.com/levensspel/go-gin-template/service/department"
+)
+
+const testDeptID = "1c8d3e3f-5a5b-4c9f-8d6a-3a2e9a7b4f21"
+
+// writeService mengembalikan err dari Create, Update dan Delete.
+type writeService struct {
+	service.DepartmentService
+	err error
+}
+
+func (f writeService) Create(ctx context.Context, managerID string, input dto.RequestDepartment) (dto.ResponseSingleDepartment, error) {
+	return dto.ResponseSingleDepartment{DepartmentID: testDeptID, DepartmentName: input.DepartmentName}, f.err
+}
+
+func (f writeService) Update(ctx context.Context, name string, id string, managerID string) (dto.ResponseSingleDepartment, error) {
+	return dto.ResponseSingleDepartment{DepartmentID: id, DepartmentName: name}, f.err
+}
+
+func (f writeService) Delete(ctx context.Context, id string, managerID string, moveTo string) error {
+	return f.err
+}
+
+// TestResponseEnvelope memastikan setiap response berbentuk
+// { data, errors, meta }: sukses hanya berisi data, gagal berisi errors
+// dengan code yang sama dengan status HTTP.
+func TestResponseEnvelope(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		target    string
+		body      string
+		managerID string
+		err       error
+		want      int
+	}{
+		{name: "create", method: http.MethodPost, target: "/v1/department", body: `{"name":"Engineering"}`, managerID: testManagerID, want: http.StatusCreated},
+		{name: "create unauthorized", method: http.MethodPost, target: "/v1/department", body: `{"name":"Engineering"}`, want: http.StatusUnauthorized},
+		{name: "create invalid json", method: http.MethodPost, target: "/v1/department", body: `{"name":`, managerID: testManagerID, want: http.StatusBadRequest},
+		{name: "create invalid name", method: http.MethodPost, target: "/v1/department", body: `{"name":"X"}`, managerID: testManagerID, err: helper.ErrBadRequest, want: http.StatusBadRequest},
+		{name: "create fails", method: http.MethodPost, target: "/v1/department", body: `{"name":"Engineering"}`, managerID: testManagerID, err: errors.New("insert failed"), want: http.StatusInternalServerError},
+		{name: "update", method: http.MethodPatch, target: "/v1/department/" + testDeptID, body: `{"name":"Finance"}`, managerID: testManagerID, want: http.StatusOK},
+		{name: "update not found", method: http.MethodPatch, target: "/v1/department/" + testDeptID, body: `{"name":"Finance"}`, managerID: testManagerID, err: helper.ErrNotFound, want: http.StatusNotFound},
+		{name: "update invalid id", method: http.MethodPatch, target: "/v1/department/42", body: `{"name":"Finance"}`, managerID: testManagerID, err: helper.ErrInvalidDepartmentId, want: http.StatusBadRequest},
+		{name: "delete", method: http.MethodDelete, target: "/v1/department/" + testDeptID, managerID: testManagerID, want: http.StatusOK},
+		{name: "delete unauthorized", method: http.MethodDelete, target: "/v1/department/" + testDeptID, want: http.StatusUnauthorized},
+		{name: "delete with employees", method: http.MethodDelete, target: "/v1/department/" + testDeptID, managerID: testManagerID, err: helper.ErrConflict, want: http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			h := New(writeService{err: tt.err}, mocks.Logger{}, 0)
+			router := gin.New()
+			router.Use(func(ctx *gin.Context) {
+				if tt.managerID != "" {
+					ctx.Set(helper.ContextKeyUserID, tt.managerID)
+				}
+			})
+			router.POST("/v1/department", h.Create)
+			router.PATCH("/v1/department/:id", h.Update)
+			router.DELETE("/v1/department/:id", h.Delete)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, tt.want, w.Body)
+			}
+
+			var envelope map[string]json.RawMessage
+			if err := json.Unmarshal(w.Body.Bytes(), &envelope); err != nil {
+				t.Fatalf("decode body %s: %v", w.Body, err)
+			}
+			for key := range envelope {
+				if key != "data" && key != "errors" && key != "meta" {
+					t.Errorf("unexpected top-level key %q in %s", key, w.Body)
+				}
+			}
+			_, hasData := envelope["data"]
+			rawErrors, hasErrors := envelope["errors"]
+			if success := tt.want < http.StatusBadRequest; hasData != success || hasErrors == success {
+				t.Fatalf("body = %s, want data only on success and errors only on failure", w.Body)
+			}
+			if !hasErrors {
+				return
+			}
+			var errorResponse helper.ErrorResponse
+			if err := json.Unmarshal(rawErrors, &errorResponse); err != nil {
+				t.Fatal(err)
+			}
+			if errorResponse.Code != tt.want || errorResponse.Message == "" {
+				t.Fatalf("errors = %+v, want code %d and a message", errorResponse, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package employeeHandler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/featureflag"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/middleware"
+	"github.com/levensspel/go-gin-template/validation"
+)
+
+const (
+	exportFormatCSV    = "csv"
+	exportFormatNDJSON = "ndjson"
+	exportFormatXLSX   = "xlsx"
+
+	ndjsonContentType = "application/x-ndjson"
+	xlsxContentType   = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+	// exportFlushRows adalah jumlah baris yang ditulis sebelum response
+	// di-flush ke client.
+	exportFlushRows = 100
+)
+
+// exportColumns sama dengan header import, ditambah waktu dibuat dan
+// diubah, sehingga hasil export CSV bisa di-import kembali.
+var exportColumns = []string{"identityNumber", "name", "employeeImageUri", "gender", "departmentId", "createdAt", "updatedAt"}
+
+// Export employees
+// @Tags employee
+// @Summary Export employees
+// @Description Stream every employee matching the GET /v1/employee filters (limit and offset are ignored), sorted by sortBy or identityNumber. Unknown and repeated query parameters are rejected as in GET /v1/employee. The format is CSV with the import header plus createdAt and updatedAt, or newline-delimited JSON (one employee per line) with format=ndjson or Accept: application/x-ndjson.
+// @Description With format=xlsx the response is an Excel workbook with an Employees sheet (identityNumber stored as text) and a Departments sheet with the employee count per department. XLSX exports are limited to EXPORT_XLSX_MAX_ROWS employees; larger exports return 413 and should use CSV.
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param Authorization header string true "Bearer JWT token"
+// @Param format query string false "csv (default), ndjson or xlsx"
+// @Success 200 {string} string "CSV, NDJSON or XLSX file"
+// @Failure 400 {object} helper.Response{errors=helper.ErrorResponse} "Bad Request"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 413 {object} helper.Response{errors=helper.ErrorResponse} "Too many employees for an XLSX export"
+// @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
+// @Router /v1/employee/export [GET]
+func (h *handler) Export(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+	log := h.logger.With(logger.RequestFields(ctx, helper.EmployeeHandlerExport)).WithContext(ctx.Request.Context())
+
+	format, err := exportFormat(ctx.Request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.Error(http.StatusBadRequest, err))
+		return
+	}
+
+	managerID, err := middleware.GetIdUserFromContext(ctx)
+	if err != nil {
+		log.Warn(err.Error(), logger.Bound)
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, helper.ErrUnauthorized))
+		return
+	}
+
+	input := &dto.GetEmployeesRequest{ManagerID: managerID}
+	if err := setGetEmployeeRequest(ctx, input, employeeListParams.With("format")); err != nil {
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+	if input.Fuzzy && !h.flags.IsEnabled(ctx, featureflag.FuzzySearch, input.ManagerID) {
+		input.Fuzzy = false
+	}
+	if err := validation.ValidateEmployeeGet(input); err != nil {
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+
+	var export employeeWriter
+	if format == exportFormatXLSX {
+		export, err = newXLSXExport(ctx.Writer, h.exportConfig.XLSXMaxRows, func(ids []string) ([]dto.ResponseSingleDepartment, error) {
+			return h.departments.GetByIDs(ctx, input.ManagerID, ids)
+		})
+		if err != nil {
+			ctx.JSON(helper.FromError(err))
+			return
+		}
+	} else {
+		export = newStreamExport(ctx.Writer, format)
+	}
+	defer export.release()
+
+	err = h.service.Export(ctx, *input, export.write)
+	if err == nil {
+		err = export.close()
+	}
+	switch {
+	case err == nil:
+	case ctx.Request.Context().Err() != nil:
+		// Client memutus koneksi, cursor sudah ditutup repository
+		log.Info("employee export canceled by client", logger.Bound, map[string]any{"rows": export.rows()})
+	case !export.started():
+		// Belum ada yang ditulis, error masih bisa dikirim sebagai JSON
+		ctx.JSON(helper.FromError(err))
+	default:
+		// Status 200 sudah terkirim; client mendapat file yang terpotong
+		log.Error(err.Error(), logger.Bound, map[string]any{"rows": export.rows()})
+	}
+}
+
+// exportFormat memakai query format, lalu header Accept. Tanpa keduanya
+// export berupa CSV.
+func exportFormat(r *http.Request) (string, error) {
+	switch format := r.URL.Query().Get("format"); format {
+	case exportFormatCSV, exportFormatNDJSON, exportFormatXLSX:
+		return format, nil
+	case "":
+	default:
+		return "", validation.FieldErrors{"format": fmt.Sprintf("format must be one of [%s %s %s]", exportFormatCSV, exportFormatNDJSON, exportFormatXLSX)}
+	}
+	accept := r.Header.Get("Accept")
+	switch {
+	case strings.Contains(accept, ndjsonContentType):
+		return exportFormatNDJSON, nil
+	case strings.Contains(accept, xlsxContentType):
+		return exportFormatXLSX, nil
+	}
+	return exportFormatCSV, nil
+}
+
+// employeeWriter menulis hasil export ke response. close dipanggil
+// setelah semua baris berhasil ditulis, release selalu dipanggil di akhir.
+type employeeWriter interface {
+	write(employee dto.EmployeeResponse) error
+	close() error
+	release()
+	// started bernilai true setelah status dan header response dikirim
+	started() bool
+	rows() int
+}
+
+// streamExport menulis employee ke response dalam format CSV atau NDJSON
+// selama cursor dibaca. Header response baru ditulis pada baris pertama
+// (atau saat close jika tidak ada baris), sehingga error sebelum itu masih
+// bisa dikirim sebagai JSON biasa.
+type streamExport struct {
+	w         gin.ResponseWriter
+	format    string
+	csv       *csv.Writer
+	json      *json.Encoder
+	isStarted bool
+	count     int
+}
+
+func newStreamExport(w gin.ResponseWriter, format string) *streamExport {
+	export := &streamExport{w: w, format: format}
+	if format == exportFormatNDJSON {
+		export.json = json.NewEncoder(w)
+	} else {
+		export.csv = csv.NewWriter(w)
+	}
+	return export
+}
+
+func (e *streamExport) start() error {
+	if e.isStarted {
+		return nil
+	}
+	e.isStarted = true
+
+	header := e.w.Header()
+	contentType, fileName := "text/csv; charset=utf-8", "employees.csv"
+	if e.format == exportFormatNDJSON {
+		contentType, fileName = ndjsonContentType, "employees.ndjson"
+	}
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	header.Set("X-Content-Type-Options", "nosniff")
+	e.w.WriteHeader(http.StatusOK)
+
+	if e.csv != nil {
+		return e.csv.Write(exportColumns)
+	}
+	return nil
+}
+
+func (e *streamExport) write(employee dto.EmployeeResponse) error {
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	var err error
+	if e.json != nil {
+		err = e.json.Encode(employee)
+	} else {
+		err = e.csv.Write([]string{
+			employee.IdentityNumber,
+			employee.Name,
+			employee.EmployeeImageUri,
+			employee.Gender,
+			employee.DepartmentID,
+			employee.CreatedAt.Format(time.RFC3339),
+			employee.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	e.count++
+	if e.count%exportFlushRows == 0 {
+		return e.flush()
+	}
+	return nil
+}
+
+// close menulis header untuk export kosong dan mengirim sisa buffer.
+func (e *streamExport) close() error {
+	if err := e.start(); err != nil {
+		return err
+	}
+	return e.flush()
+}
+
+func (e *streamExport) flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	}
+	// Flush gin tidak mengembalikan error; koneksi yang putus terlihat dari
+	// error tulis berikutnya atau dari context request.
+	e.w.Flush()
+	return nil
+}
+
+func (e *streamExport) release() {}
+
+func (e *streamExport) started() bool {
+	return e.isStarted
+}
+
+func (e *streamExport) rows() int {
+	return e.count
+}
@@ -0,0 +1,224 @@
+package employeeHandler
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/mocks"
+)
+
+func exportEmployee(i int) dto.EmployeeResponse {
+	return dto.EmployeeResponse{
+		EmployeePayload: dto.EmployeePayload{
+			IdentityNumber: fmt.Sprintf("EMP-%05d", i),
+			Name:           "Budi, \"Santoso\"",
+			Gender:         "male",
+			DepartmentID:   testDepartmentID,
+		},
+		CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// exportServer menjalankan GET /v1/employee/export di server HTTP sungguhan,
+// sehingga flush dan koneksi yang diputus client terlihat oleh handler.
+func exportServer(t *testing.T, service *mocks.EmployeeService) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(ctx *gin.Context) { ctx.Set(helper.ContextKeyUserID, testManagerID) })
+	router.GET("/v1/employee/export", newTestHandler(service, "").Export)
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// TestExportNDJSONStreams membaca baris yang sudah di-flush sebelum service selesai,
+// lalu memutus koneksi dan memastikan export berhenti.
+func TestExportNDJSONStreams(t *testing.T) {
+	flushed := make(chan struct{})
+	stopped := make(chan error, 1)
+	service := &mocks.EmployeeService{
+		ExportFunc: func(ctx context.Context, input dto.GetEmployeesRequest, fn func(dto.EmployeeResponse) error) error {
+			err := func() error {
+				for i := 0; ; i++ {
+					if i == exportFlushRows {
+						close(flushed)
+					}
+					// Seperti cursor repository: berhenti saat ctx dibatalkan
+					if err := ctx.Err(); err != nil {
+						return err
+					}
+					if err := fn(exportEmployee(i)); err != nil {
+						return err
+					}
+					if i >= exportFlushRows {
+						time.Sleep(time.Millisecond)
+					}
+				}
+			}()
+			stopped <- err
+			return err
+		},
+	}
+	srv := exportServer(t, service)
+
+	response, err := http.Get(srv.URL + "/v1/employee/export?format=ndjson")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	if got := response.Header.Get("Content-Type"); got != ndjsonContentType {
+		t.Fatalf("Content-Type = %q, want %s", got, ndjsonContentType)
+	}
+
+	<-flushed
+	scanner := bufio.NewScanner(response.Body)
+	for i := range exportFlushRows {
+		if !scanner.Scan() {
+			t.Fatalf("stream ended after %d rows: %v", i, scanner.Err())
+		}
+		var employee dto.EmployeeResponse
+		if err := json.Unmarshal(scanner.Bytes(), &employee); err != nil {
+			t.Fatalf("line %d = %s: %v", i, scanner.Bytes(), err)
+		}
+		if employee.IdentityNumber != exportEmployee(i).IdentityNumber || !employee.CreatedAt.Equal(exportEmployee(i).CreatedAt) {
+			t.Fatalf("line %d = %+v", i, employee)
+		}
+	}
+
+	response.Body.Close()
+	select {
+	case err := <-stopped:
+		if err == nil {
+			t.Fatal("export finished without an error after the client disconnected")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("export kept running after the client disconnected")
+	}
+}
+
+func TestExportFormats(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		target      string
+		accept      string
+		contentType string
+	}{
+		{name: "default csv", target: "/v1/employee/export", contentType: "text/csv; charset=utf-8"},
+		{name: "format ndjson", target: "/v1/employee/export?format=ndjson", contentType: ndjsonContentType},
+		{name: "accept ndjson", target: "/v1/employee/export", accept: ndjsonContentType, contentType: ndjsonContentType},
+		{name: "format wins over accept", target: "/v1/employee/export?format=csv", accept: ndjsonContentType, contentType: "text/csv; charset=utf-8"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mocks.EmployeeService{
+				ExportFunc: func(ctx context.Context, input dto.GetEmployeesRequest, fn func(dto.EmployeeResponse) error) error {
+					for i := range 2 {
+						if err := fn(exportEmployee(i)); err != nil {
+							return err
+						}
+					}
+					return nil
+				},
+			}
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request = httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.accept != "" {
+				ctx.Request.Header.Set("Accept", tt.accept)
+			}
+			ctx.Set(helper.ContextKeyUserID, testManagerID)
+			newTestHandler(service, "").Export(ctx)
+
+			if w.Code != http.StatusOK || w.Header().Get("Content-Type") != tt.contentType {
+				t.Fatalf("status = %d, Content-Type = %q, want 200 %s", w.Code, w.Header().Get("Content-Type"), tt.contentType)
+			}
+			if tt.contentType == ndjsonContentType {
+				if lines := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n"); len(lines) != 2 {
+					t.Fatalf("body = %q, want 2 lines", w.Body)
+				}
+				return
+			}
+			records, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(records) != 3 || strings.Join(records[0], ",") != strings.Join(exportColumns, ",") ||
+				records[1][1] != `Budi, "Santoso"` || records[2][5] != "2024-01-01T00:00:00Z" {
+				t.Fatalf("records = %q", records)
+			}
+		})
+	}
+}
+
+func TestExportEmpty(t *testing.T) {
+	service := &mocks.EmployeeService{
+		ExportFunc: func(ctx context.Context, input dto.GetEmployeesRequest, fn func(dto.EmployeeResponse) error) error {
+			return nil
+		},
+	}
+	w := serve(newTestHandler(service, "").Export, http.MethodGet, "/v1/employee/export", "", testManagerID)
+	// Export kosong tetap berisi header CSV
+	if w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != strings.Join(exportColumns, ",") {
+		t.Fatalf("status = %d, body = %q", w.Code, w.Body)
+	}
+}
+
+func TestExportErrors(t *testing.T) {
+	errQuery := errors.New("connection reset")
+	for _, tt := range []struct {
+		name      string
+		target    string
+		managerID string
+		exportErr error
+		afterRows int
+		want      int
+	}{
+		{name: "unknown format", target: "/v1/employee/export?format=xml", managerID: testManagerID, want: http.StatusBadRequest},
+		{name: "without user", target: "/v1/employee/export", want: http.StatusUnauthorized},
+		{name: "invalid filter", target: "/v1/employee/export?gender=other", managerID: testManagerID, want: http.StatusBadRequest},
+		{name: "error before first row", target: "/v1/employee/export?format=ndjson", managerID: testManagerID, exportErr: errQuery, want: http.StatusInternalServerError},
+		// Status 200 sudah terkirim, client mendapat file yang terpotong
+		{name: "error after first row", target: "/v1/employee/export?format=ndjson", managerID: testManagerID, exportErr: errQuery, afterRows: 1, want: http.StatusOK},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			service := &mocks.EmployeeService{
+				ExportFunc: func(ctx context.Context, input dto.GetEmployeesRequest, fn func(dto.EmployeeResponse) error) error {
+					called = true
+					for i := range tt.afterRows {
+						if err := fn(exportEmployee(i)); err != nil {
+							return err
+						}
+					}
+					return tt.exportErr
+				},
+			}
+			w := serve(newTestHandler(service, "").Export, http.MethodGet, tt.target, "", tt.managerID)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, body = %s, want %d", w.Code, w.Body, tt.want)
+			}
+			if called != (tt.exportErr != nil) {
+				t.Fatalf("service called = %v", called)
+			}
+			if tt.want == http.StatusOK {
+				if lines := strings.Count(w.Body.String(), "\n"); lines != tt.afterRows {
+					t.Fatalf("body = %q, want %d complete lines", w.Body, tt.afterRows)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package employeeHandler
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	xlsxEmployeeSheet   = "Employees"
+	xlsxDepartmentSheet = "Departments"
+
+	// xlsxNumFmtText adalah format angka bawaan Excel "@" (text), agar
+	// identityNumber dengan nol di depan tidak diubah menjadi angka.
+	xlsxNumFmtText = 49
+)
+
+var xlsxDateFormat = "yyyy-mm-dd hh:mm:ss"
+
+var xlsxDepartmentColumns = []string{"departmentId", "departmentName", "employees"}
+
+// xlsxExport menulis employee ke workbook lewat StreamWriter excelize,
+// yang memindahkan baris ke file sementara saat workbook membesar, sehingga
+// memori tetap terbatas. Workbook baru bisa dikirim setelah selesai, jadi
+// jumlah barisnya dibatasi maxRows dan error apa pun sebelum close masih
+// dikirim sebagai JSON.
+type xlsxExport struct {
+	w           gin.ResponseWriter
+	file        *excelize.File
+	sheet       *excelize.StreamWriter
+	maxRows     int
+	departments func(ids []string) ([]dto.ResponseSingleDepartment, error)
+
+	headerStyle int
+	textStyle   int
+	dateStyle   int
+
+	perDepartment map[string]int
+	isStarted     bool
+	count         int
+}
+
+// newXLSXExport membuat workbook dengan sheet Employees. departments
+// dipakai close untuk mengambil nama department di sheet ringkasan.
+func newXLSXExport(
+	w gin.ResponseWriter,
+	maxRows int,
+	departments func(ids []string) ([]dto.ResponseSingleDepartment, error),
+) (_ *xlsxExport, err error) {
+	export := &xlsxExport{
+		w:             w,
+		file:          excelize.NewFile(),
+		maxRows:       maxRows,
+		departments:   departments,
+		perDepartment: map[string]int{},
+	}
+	defer func() {
+		if err != nil {
+			export.release()
+		}
+	}()
+
+	f := export.file
+	if err := f.SetSheetName("Sheet1", xlsxEmployeeSheet); err != nil {
+		return nil, err
+	}
+	if export.headerStyle, err = f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}}); err != nil {
+		return nil, err
+	}
+	if export.textStyle, err = f.NewStyle(&excelize.Style{NumFmt: xlsxNumFmtText}); err != nil {
+		return nil, err
+	}
+	if export.dateStyle, err = f.NewStyle(&excelize.Style{CustomNumFmt: &xlsxDateFormat}); err != nil {
+		return nil, err
+	}
+
+	// Pane dan lebar kolom harus diatur sebelum baris pertama ditulis
+	if export.sheet, err = f.NewStreamWriter(xlsxEmployeeSheet); err != nil {
+		return nil, err
+	}
+	if err := export.sheet.SetPanes(&excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	}); err != nil {
+		return nil, err
+	}
+	if err := export.sheet.SetColWidth(1, len(exportColumns), 20); err != nil {
+		return nil, err
+	}
+	if err := export.sheet.SetRow("A1", export.headerRow(exportColumns)); err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+func (e *xlsxExport) headerRow(columns []string) []interface{} {
+	row := make([]interface{}, len(columns))
+	for i, column := range columns {
+		row[i] = excelize.Cell{StyleID: e.headerStyle, Value: column}
+	}
+	return row
+}
+
+func (e *xlsxExport) write(employee dto.EmployeeResponse) error {
+	if e.count >= e.maxRows {
+		return helper.ErrExportRowLimit
+	}
+	e.count++
+	e.perDepartment[employee.DepartmentID]++
+
+	cell, err := excelize.CoordinatesToCellName(1, e.count+1)
+	if err != nil {
+		return err
+	}
+	return e.sheet.SetRow(cell, []interface{}{
+		excelize.Cell{StyleID: e.textStyle, Value: employee.IdentityNumber},
+		employee.Name,
+		employee.EmployeeImageUri,
+		employee.Gender,
+		employee.DepartmentID,
+		excelize.Cell{StyleID: e.dateStyle, Value: employee.CreatedAt.UTC()},
+		excelize.Cell{StyleID: e.dateStyle, Value: employee.UpdatedAt.UTC()},
+	})
+}
+
+// close menyelesaikan sheet Employees, membuat sheet Departments lalu
+// mengirim workbook.
+func (e *xlsxExport) close() error {
+	if err := e.sheet.Flush(); err != nil {
+		return err
+	}
+	if err := e.writeDepartments(); err != nil {
+		return err
+	}
+
+	e.isStarted = true
+	header := e.w.Header()
+	header.Set("Content-Type", xlsxContentType)
+	header.Set("Content-Disposition", `attachment; filename="employees.xlsx"`)
+	header.Set("X-Content-Type-Options", "nosniff")
+	e.w.WriteHeader(http.StatusOK)
+	return e.file.Write(e.w)
+}
+
+// writeDepartments menulis jumlah employee yang diexport per department,
+// diurutkan berdasarkan nama department.
+func (e *xlsxExport) writeDepartments() error {
+	ids := make([]string, 0, len(e.perDepartment))
+	for id := range e.perDepartment {
+		ids = append(ids, id)
+	}
+	names := make(map[string]string, len(ids))
+	if len(ids) > 0 {
+		departments, err := e.departments(ids)
+		if err != nil {
+			return err
+		}
+		for _, department := range departments {
+			names[department.DepartmentID] = department.DepartmentName
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if names[ids[i]] != names[ids[j]] {
+			return names[ids[i]] < names[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+
+	if _, err := e.file.NewSheet(xlsxDepartmentSheet); err != nil {
+		return err
+	}
+	sheet, err := e.file.NewStreamWriter(xlsxDepartmentSheet)
+	if err != nil {
+		return err
+	}
+	if err := sheet.SetColWidth(1, len(xlsxDepartmentColumns), 20); err != nil {
+		return err
+	}
+	if err := sheet.SetRow("A1", e.headerRow(xlsxDepartmentColumns)); err != nil {
+		return err
+	}
+	for i, id := range ids {
+		cell, err := excelize.CoordinatesToCellName(1, i+2)
+		if err != nil {
+			return err
+		}
+		if err := sheet.SetRow(cell, []interface{}{id, names[id], e.perDepartment[id]}); err != nil {
+			return err
+		}
+	}
+	return sheet.Flush()
+}
+
+// release menghapus file sementara StreamWriter.
+func (e *xlsxExport) release() {
+	_ = e.file.Close()
+}
+
+func (e *xlsxExport) started() bool {
+	return e.isStarted
+}
+
+func (e *xlsxExport) rows() int {
+	return e.count
+}
@@ -0,0 +1,228 @@
+package employeeHandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/mocks"
+	departmentService "github.com/levensspel/go-gin-template/service/department"
+	"github.com/xuri/excelize/v2"
+)
+
+const otherDepartmentID = "7b2a1e1f-0d2c-4d4f-9a1b-6c3e8f5d0b22"
+
+// xlsxDepartments hanya mengimplementasikan GetByIDs; method lain panic
+// karena interface-nya nil.
+type xlsxDepartments struct {
+	departmentService.DepartmentService
+	err   error
+	calls [][]string
+}
+
+func (d *xlsxDepartments) GetByIDs(ctx context.Context, managerID string, ids []string) ([]dto.ResponseSingleDepartment, error) {
+	d.calls = append(d.calls, ids)
+	if d.err != nil {
+		return nil, d.err
+	}
+	return []dto.ResponseSingleDepartment{
+		{DepartmentID: testDepartmentID, DepartmentName: "Finance"},
+		{DepartmentID: otherDepartmentID, DepartmentName: "Engineering"},
+	}, nil
+}
+
+// xlsxEmployees mengekspor count employee; employee ketiga dan seterusnya
+// ada di otherDepartmentID.
+func xlsxEmployees(count int) *mocks.EmployeeService {
+	return &mocks.EmployeeService{
+		ExportFunc: func(ctx context.Context, input dto.GetEmployeesRequest, fn func(dto.EmployeeResponse) error) error {
+			for i := range count {
+				employee := exportEmployee(i)
+				employee.IdentityNumber = fmt.Sprintf("%05d", i)
+				if i >= 2 {
+					employee.DepartmentID = otherDepartmentID
+				}
+				if err := fn(employee); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func serveXLSX(service *mocks.EmployeeService, departments *xlsxDepartments, maxRows int, target string) *httptest.ResponseRecorder {
+	h := NewEmployeeHandler(
+		service,
+		departments,
+		disabledFlags{},
+		mocks.Logger{},
+		&config.ImportConfig{},
+		&config.ExportConfig{XLSXMaxRows: maxRows},
+		&config.ResponseConfig{},
+		0,
+	)
+	return serve(h.Export, http.MethodGet, target, "", testManagerID)
+}
+
+func TestExportXLSX(t *testing.T) {
+	departments := &xlsxDepartments{}
+	w := serveXLSX(xlsxEmployees(5), departments, 10, "/v1/employee/export?format=xlsx")
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != xlsxContentType {
+		t.Fatalf("status = %d, Content-Type = %q, body = %.200s", w.Code, w.Header().Get("Content-Type"), w.Body)
+	}
+
+	f, err := excelize.OpenReader(bytes.NewReader(w.Body.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if sheets := f.GetSheetList(); !slices.Equal(sheets, []string{xlsxEmployeeSheet, xlsxDepartmentSheet}) {
+		t.Fatalf("sheets = %v", sheets)
+	}
+
+	rows, err := f.GetRows(xlsxEmployeeSheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 6 || !slices.Equal(rows[0], exportColumns) {
+		t.Fatalf("employee rows = %d, header = %v", len(rows), rows[0])
+	}
+	// Nol di depan identityNumber tetap ada karena disimpan sebagai teks
+	if rows[1][0] != "00000" || rows[5][0] != "00004" || rows[1][1] != `Budi, "Santoso"` {
+		t.Fatalf("rows = %q", rows[1:])
+	}
+	panes, err := f.GetPanes(xlsxEmployeeSheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !panes.Freeze || panes.YSplit != 1 || panes.TopLeftCell != "A2" {
+		t.Fatalf("panes = %+v, want header row frozen", panes)
+	}
+
+	for _, tt := range []struct {
+		cell     string
+		wantNum  int
+		wantType excelize.CellType
+	}{
+		{cell: "A2", wantNum: xlsxNumFmtText, wantType: excelize.CellTypeInlineString},
+		// Timestamp disimpan sebagai angka tanggal Excel, bukan teks
+		{cell: "F2", wantType: excelize.CellTypeUnset},
+	} {
+		cellType, err := f.GetCellType(xlsxEmployeeSheet, tt.cell)
+		if err != nil {
+			t.Fatal(err)
+		}
+		styleID, err := f.GetCellStyle(xlsxEmployeeSheet, tt.cell)
+		if err != nil {
+			t.Fatal(err)
+		}
+		style, err := f.GetStyle(styleID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cellType != tt.wantType || style.NumFmt != tt.wantNum {
+			t.Errorf("%s type = %v, numFmt = %d, want %v, %d", tt.cell, cellType, style.NumFmt, tt.wantType, tt.wantNum)
+		}
+		if tt.cell == "F2" && (style.CustomNumFmt == nil || *style.CustomNumFmt != xlsxDateFormat) {
+			t.Errorf("F2 custom format = %v, want %s", style.CustomNumFmt, xlsxDateFormat)
+		}
+	}
+	raw, err := f.GetCellValue(xlsxEmployeeSheet, "F2", excelize.Options{RawCellValue: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 2024-01-01 adalah serial date 45292
+	if raw != "45292" {
+		t.Fatalf("createdAt raw value = %q, want 45292", raw)
+	}
+
+	// Sheet ringkasan diurutkan berdasarkan nama department
+	summary, err := f.GetRows(xlsxDepartmentSheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		xlsxDepartmentColumns,
+		{otherDepartmentID, "Engineering", "3"},
+		{testDepartmentID, "Finance", "2"},
+	}
+	if fmt.Sprint(summary) != fmt.Sprint(want) {
+		t.Fatalf("departments = %q, want %q", summary, want)
+	}
+	if len(departments.calls) != 1 || len(departments.calls[0]) != 2 {
+		t.Fatalf("GetByIDs calls = %v, want one call with both departments", departments.calls)
+	}
+}
+
+func TestExportXLSXEmpty(t *testing.T) {
+	departments := &xlsxDepartments{}
+	w := serveXLSX(xlsxEmployees(0), departments, 10, "/v1/employee/export?format=xlsx")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(w.Body.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, _ := f.GetRows(xlsxEmployeeSheet)
+	summary, _ := f.GetRows(xlsxDepartmentSheet)
+	if len(rows) != 1 || len(summary) != 1 || len(departments.calls) != 0 {
+		t.Fatalf("rows = %q, departments = %q, GetByIDs calls = %v", rows, summary, departments.calls)
+	}
+}
+
+func TestExportXLSXAccept(t *testing.T) {
+	h := NewEmployeeHandler(xlsxEmployees(1), &xlsxDepartments{}, disabledFlags{}, mocks.Logger{},
+		&config.ImportConfig{}, &config.ExportConfig{XLSXMaxRows: 10}, &config.ResponseConfig{}, 0)
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/employee/export", nil)
+	ctx.Request.Header.Set("Accept", xlsxContentType)
+	ctx.Set(helper.ContextKeyUserID, testManagerID)
+	h.Export(ctx)
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != xlsxContentType {
+		t.Fatalf("status = %d, Content-Type = %q", w.Code, w.Header().Get("Content-Type"))
+	}
+}
+
+func TestExportXLSXErrors(t *testing.T) {
+	errLookup := errors.New("department lookup failed")
+	for _, tt := range []struct {
+		name        string
+		count       int
+		departments *xlsxDepartments
+		want        int
+		wantErr     error
+	}{
+		{name: "row limit", count: 4, departments: &xlsxDepartments{}, want: http.StatusRequestEntityTooLarge, wantErr: helper.ErrExportRowLimit},
+		{name: "department lookup", count: 2, departments: &xlsxDepartments{err: errLookup}, want: http.StatusInternalServerError},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveXLSX(xlsxEmployees(tt.count), tt.departments, 3, "/v1/employee/export?format=xlsx")
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, body = %.200s, want %d", w.Code, w.Body, tt.want)
+			}
+			// Workbook belum dikirim, error masih berupa JSON biasa
+			var response helper.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || response.Errors == nil {
+				t.Fatalf("body = %.200s is not a JSON error: %v", w.Body, err)
+			}
+			if tt.wantErr != nil && response.Errors.Message != helper.GetErrorMessage(tt.wantErr) {
+				t.Fatalf("message = %q, want %q", response.Errors.Message, helper.GetErrorMessage(tt.wantErr))
+			}
+		})
+	}
+}
@@ -1,16 +1,25 @@
 package employeeHandler
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"mime/multipart"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/config"
 	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/featureflag"
 	"github.com/levensspel/go-gin-template/helper"
 	"github.com/levensspel/go-gin-template/logger"
 	"github.com/levensspel/go-gin-template/middleware"
+	departmentService "github.com/levensspel/go-gin-template/service/department"
 	service "github.com/levensspel/go-gin-template/service/employee"
 	"github.com/levensspel/go-gin-template/validation"
 	"github.com/samber/do/v2"
@@ -18,33 +27,71 @@ import (
 
 type EmployeeHandler interface {
 	Create(ctx *gin.Context)
+	Update(ctx *gin.Context)
+	Delete(ctx *gin.Context)
 	GetAll(ctx *gin.Context)
+	Import(ctx *gin.Context)
+	BulkCreate(ctx *gin.Context)
+	CheckIdentityNumber(ctx *gin.Context)
+	Export(ctx *gin.Context)
+	Archive(ctx *gin.Context)
+	Restore(ctx *gin.Context)
+	Stats(ctx *gin.Context)
+	History(ctx *gin.Context)
+	RestoreVersion(ctx *gin.Context)
 }
 
 type handler struct {
-	service service.EmployeeService
-	logger  logger.Logger
+	service      service.EmployeeService
+	departments  departmentService.DepartmentService
+	flags        featureflag.FeatureFlags
+	logger       logger.Logger
+	importConfig *config.ImportConfig
+	exportConfig *config.ExportConfig
+	listResponse employeeListResponse
 }
 
-func NewEmployeeHandler(service service.EmployeeService, logger logger.Logger) EmployeeHandler {
-	return &handler{service: service, logger: logger}
+func NewEmployeeHandler(
+	service service.EmployeeService,
+	departments departmentService.DepartmentService,
+	flags featureflag.FeatureFlags,
+	logger logger.Logger,
+	importConfig *config.ImportConfig,
+	exportConfig *config.ExportConfig,
+	responseConfig *config.ResponseConfig,
+	trustedProxyDepth int,
+) EmployeeHandler {
+	return &handler{
+		service:      service,
+		departments:  departments,
+		flags:        flags,
+		logger:       logger,
+		importConfig: importConfig,
+		exportConfig: exportConfig,
+		listResponse: newEmployeeListResponse(responseConfig.EmployeeList, service, trustedProxyDepth),
+	}
 }
 
 func NewEmployeeHandlerInject(i do.Injector) (EmployeeHandler, error) {
 	_service := do.MustInvoke[service.EmployeeService](i)
+	_departments := do.MustInvoke[departmentService.DepartmentService](i)
+	_flags := do.MustInvoke[featureflag.FeatureFlags](i)
 	_logger := do.MustInvoke[logger.LogHandler](i)
-	return NewEmployeeHandler(_service, &_logger), nil
+	return NewEmployeeHandler(_service, _departments, _flags, &_logger, config.LoadImportConfig(), config.LoadExportConfig(), config.LoadResponseConfig(), config.LoadAdminConfig().TrustedProxyDepth), nil
 }
 
 // Create a new employee
 // @Tags employee
 // @Summary Create a new employee
-// @Description Create a new employee
+// @Description Create a new employee. When FILE_RESTRICT_EMPLOYEE_IMAGES is enabled, employeeImageUri must be the uri of a file the manager uploaded through POST /v1/file.
+// @Description With dryRun=true nothing is created: the body is validated and checked against the database (image, department ownership and identityNumber availability) in a read-only transaction that is always rolled back. The response is 200 with valid, and with fields listing every failing field and the message a real create would return for it.
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer JWT token"
+// @Param dryRun query bool false "Validate without creating (any other value than a boolean returns 400)"
 // @Param data body dto.EmployeePayload true "data"
-// @Success 201 {object} helper.Response{data=helper.Response} "Created"
+// @Success 201 {object} helper.Response{data=dto.EmployeeResponse} "Created"
+// @Success 200 {object} helper.Response{data=dto.EmployeeDryRunResult} "OK (dryRun=true)"
 // @Failure 400 {object} helper.Response{errors=helper.ErrorResponse} "Bad Request"
 // @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
 // @Failure 409 {object} helper.Response{errors=helper.ErrorResponse} "Conflict"
@@ -52,126 +99,584 @@ func NewEmployeeHandlerInject(i do.Injector) (EmployeeHandler, error) {
 // @Router /v1/employee [POST]
 func (h *handler) Create(ctx *gin.Context) {
 	defer helper.FallbackResponse(ctx)
+	log := h.logger.With(logger.RequestFields(ctx, helper.EmployeeHandlerCreate)).WithContext(ctx.Request.Context())
 
 	managerID, err := middleware.GetIdUserFromContext(ctx)
 	if err != nil {
-		h.logger.Warn(err.Error(), helper.EmployeeHandlerCreate)
-		ctx.JSON(helper.GetErrorStatusCode(helper.ErrUnauthorized), helper.NewResponse(nil, err))
+		log.Warn(err.Error(), logger.Bound)
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
 		return
 	}
 
 	input := new(dto.EmployeePayload)
 
-	if err := ctx.ShouldBindJSON(&input); err != nil {
-		h.logger.Warn(err.Error(), helper.EmployeeHandlerCreate, input)
-		ctx.JSON(helper.GetErrorStatusCode(helper.ErrBadRequest), helper.NewResponse(nil, err))
+	if err := ctx.ShouldBindJSON(input); err != nil {
+		log.Warn(err.Error(), logger.Bound, input)
+		ctx.JSON(http.StatusBadRequest, helper.Error(http.StatusBadRequest, err))
+		return
+	}
+
+	dryRun, err := parseDryRun(ctx.Request)
+	if err != nil {
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+	if dryRun {
+		fieldErrors, err := h.service.DryRunCreate(ctx, *input, managerID)
+		if err != nil {
+			log.Error(err.Error(), logger.Bound)
+			ctx.JSON(helper.FromError(err))
+			return
+		}
+		ctx.JSON(http.StatusOK, helper.OK(dto.EmployeeDryRunResult{Valid: len(fieldErrors) == 0, Fields: fieldErrors}))
 		return
 	}
 
 	err = validation.ValidateEmployeeCreate(input)
 	if err != nil {
-		h.logger.Warn(err.Error(), helper.EmployeeHandlerCreate, input)
-		ctx.JSON(helper.GetErrorStatusCode(helper.ErrBadRequest), helper.NewResponse(nil, err))
+		log.Warn(err.Error(), logger.Bound, input)
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+
+	// Response berisi employee hasil insert (RETURNING), bukan body request
+	employee, err := h.service.Create(ctx, *input, managerID)
+	if err != nil {
+		log.Error(err.Error(), logger.Bound)
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, helper.Created(employee))
+}
+
+// Update an employee
+// @Tags employee
+// @Summary Update an employee
+// @Description Update the given fields of an employee. updatedAt is refreshed on every update. employeeImageUri follows the same rule as in create.
+// @Description Fields that are left out are unchanged, while empty strings are validated and rejected. employeeImageUri: null clears the image; null is rejected for every other field.
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param identityNumber path string true "Identity number"
+// @Param data body dto.EmployeeUpdatePayload true "data"
+// @Success 200 {object} helper.Response{data=dto.EmployeeResponse} "OK"
+// @Failure 400 {object} helper.Response{errors=helper.ErrorResponse} "Bad Request"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 404 {object} helper.Response{errors=helper.ErrorResponse} "Not Found"
+// @Failure 409 {object} helper.Response{errors=helper.ErrorResponse} "Conflict"
+// @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
+// @Router /v1/employee/{identityNumber} [PATCH]
+func (h *handler) Update(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+	log := h.logger.With(logger.RequestFields(ctx, helper.EmployeeHandlerUpdate)).WithContext(ctx.Request.Context())
+
+	managerID, err := middleware.GetIdUserFromContext(ctx)
+	if err != nil {
+		log.Warn(err.Error(), logger.Bound)
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
+		return
+	}
+
+	input := new(dto.EmployeeUpdatePayload)
+	if err := ctx.ShouldBindJSON(input); err != nil {
+		log.Warn(err.Error(), logger.Bound, input)
+		ctx.JSON(http.StatusBadRequest, helper.Error(http.StatusBadRequest, err))
+		return
+	}
+
+	if err := validation.ValidateEmployeePatch(input); err != nil {
+		log.Warn(err.Error(), logger.Bound, input)
+		ctx.JSON(http.StatusBadRequest, helper.Error(http.StatusBadRequest, err))
+		return
+	}
+
+	employee, err := h.service.Update(ctx, ctx.Param("identityNumber"), *input, managerID)
+	if err != nil {
+		log.Error(err.Error(), logger.Bound)
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.OK(employee))
+}
+
+// Delete an employee
+// @Tags employee
+// @Summary Delete an employee
+// @Description Delete an employee of the current manager and return the deleted employee.
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param identityNumber path string true "Identity number"
+// @Success 200 {object} helper.Response{data=dto.EmployeeResponse} "OK"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 404 {object} helper.Response{errors=helper.ErrorResponse} "Not Found"
+// @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
+// @Router /v1/employee/{identityNumber} [DELETE]
+func (h *handler) Delete(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+	log := h.logger.With(logger.RequestFields(ctx, helper.EmployeeHandlerDelete)).WithContext(ctx.Request.Context())
+
+	managerID, err := middleware.GetIdUserFromContext(ctx)
+	if err != nil {
+		log.Warn(err.Error(), logger.Bound)
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
+		return
+	}
+
+	employee, err := h.service.Delete(ctx, ctx.Param("identityNumber"), managerID)
+	if err != nil {
+		log.Error(err.Error(), logger.Bound)
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.OK(employee))
+}
+
+// Create employees in bulk
+// @Tags employee
+// @Summary Create employees in bulk
+// @Description Create up to 100 employees in one transaction. Each item is validated independently; the response lists the status of every item by index.
+// @Description With dryRun=true nothing is created and the response lists valid and the failing fields of every item by index, as in POST /v1/employee?dryRun=true. An item is also rejected if an earlier valid item uses the same identityNumber.
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param dryRun query bool false "Validate without creating (any other value than a boolean returns 400)"
+// @Param data body []dto.EmployeePayload true "data"
+// @Success 200 {object} helper.Response{data=[]dto.EmployeeBulkResult} "OK"
+// @Success 200 {object} helper.Response{data=[]dto.EmployeeBulkDryRunResult} "OK (dryRun=true)"
+// @Failure 400 {object} helper.Response{errors=helper.ErrorResponse} "Bad Request"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
+// @Router /v1/employee/bulk [POST]
+func (h *handler) BulkCreate(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+	log := h.logger.With(logger.RequestFields(ctx, helper.EmployeeHandlerBulkCreate)).WithContext(ctx.Request.Context())
+
+	managerID, err := middleware.GetIdUserFromContext(ctx)
+	if err != nil {
+		log.Warn(err.Error(), logger.Bound)
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
+		return
+	}
+
+	var inputs []dto.EmployeePayload
+	if err := ctx.ShouldBindJSON(&inputs); err != nil {
+		log.Warn(err.Error(), logger.Bound)
+		ctx.JSON(http.StatusBadRequest, helper.Error(http.StatusBadRequest, err))
+		return
+	}
+	if len(inputs) == 0 || len(inputs) > dto.MaxBulkEmployees {
+		err := fmt.Errorf("number of employees must be between 1 and %d", dto.MaxBulkEmployees)
+		ctx.JSON(http.StatusBadRequest, helper.Error(http.StatusBadRequest, err))
+		return
+	}
+
+	dryRun, err := parseDryRun(ctx.Request)
+	if err != nil {
+		ctx.JSON(helper.FromError(err))
 		return
 	}
+	if dryRun {
+		fieldErrors, err := h.service.DryRunCreateMany(ctx, inputs, managerID)
+		if err != nil {
+			log.Error(err.Error(), logger.Bound)
+			ctx.JSON(helper.FromError(err))
+			return
+		}
+		results := make([]dto.EmployeeBulkDryRunResult, len(inputs))
+		for i := range inputs {
+			results[i] = dto.EmployeeBulkDryRunResult{
+				Index:                i,
+				EmployeeDryRunResult: dto.EmployeeDryRunResult{Valid: len(fieldErrors[i]) == 0, Fields: fieldErrors[i]},
+			}
+		}
+		ctx.JSON(http.StatusOK, helper.OK(results))
+		return
+	}
+
+	results := make([]dto.EmployeeBulkResult, len(inputs))
+	valid := make([]dto.EmployeePayload, 0, len(inputs))
+	validIndexes := make([]int, 0, len(inputs))
+	for i := range inputs {
+		results[i] = dto.EmployeeBulkResult{Index: i, Status: http.StatusCreated}
+		if err := validation.ValidateEmployeeCreate(&inputs[i]); err != nil {
+			results[i].Status = http.StatusBadRequest
+			results[i].Error = err.Error()
+			continue
+		}
+		valid = append(valid, inputs[i])
+		validIndexes = append(validIndexes, i)
+	}
+
+	if len(valid) > 0 {
+		errs, err := h.service.CreateMany(ctx, valid, managerID)
+		if err != nil {
+			log.Error(err.Error(), logger.Bound)
+			ctx.JSON(helper.FromError(err))
+			return
+		}
+		for i, err := range errs {
+			if err != nil {
+				results[validIndexes[i]].Status = helper.GetErrorStatusCode(err)
+				results[validIndexes[i]].Error = helper.GetErrorMessage(err)
+			}
+		}
+	}
 
-	err = h.service.Create(ctx, *input, managerID)
+	ctx.JSON(http.StatusOK, helper.OK(results))
+}
+
+// Check identity number availability
+// @Tags employee
+// @Summary Check identity number availability
+// @Description Check whether an identity number is still unused. The result is informational only; creating an employee may still return 409 if the number is taken in the meantime.
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param identityNumber path string true "Identity number"
+// @Success 200 {object} helper.Response{data=dto.IdentityNumberAvailability} "OK"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
+// @Router /v1/employee/identity-number/{identityNumber} [GET]
+func (h *handler) CheckIdentityNumber(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+	log := h.logger.With(logger.RequestFields(ctx, helper.EmployeeHandlerIdentityNumber)).WithContext(ctx.Request.Context())
+
+	identityNumber := ctx.Param("identityNumber")
+	available, err := h.service.IsIdentityNumberAvailable(ctx, identityNumber)
 	if err != nil {
-		h.logger.Error(err.Error(), helper.EmployeeHandlerCreate)
-		ctx.JSON(
-			helper.GetErrorStatusCode(err),
-			helper.NewResponse(
-				nil,
-				errors.New((helper.GetErrorMessage(err)))),
-		)
+		log.Error(err.Error(), logger.Bound)
+		ctx.JSON(helper.FromError(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, input)
-	return
+	ctx.JSON(http.StatusOK, helper.OK(dto.IdentityNumberAvailability{
+		IdentityNumber: identityNumber,
+		Available:      available,
+	}))
 }
 
 // Get employee
 // @Tags employee
 // @Summary Get employee
-// @Description Get employee
+// @Description Get employee. With expand=images each employee includes employeeImage, whose uri is the thumbnail once it is ready and the original employeeImageUri otherwise.
+// @Description With expand=department each employee includes its department (id and name), loaded for the whole page in one query.
+// @Description status is active (default), archived or all; archived employees are left out unless requested.
+// @Description With EMPLOYEE_LIST_RESPONSE=array the body is a bare JSON array of employees and the total moves to the X-Total-Count header; the default envelope wraps the list in data.
+// @Description Both shapes send an RFC 8288 Link header with absolute first, prev, next and last URLs that keep every other query parameter. prev is left out on the first page and next on the last page. Behind TRUSTED_PROXY_DEPTH proxies, the scheme and host come from X-Forwarded-Proto and X-Forwarded-Host.
+// @Description hiredFrom and hiredTo (YYYY-MM-DD, inclusive) filter by hiredAt and leave out employees without one; sortBy=hiredAt puts employees without hiredAt last. Without sortBy, q or fuzzy, employees are ordered by createdAt, then identityNumber.
+// @Description identityNumbers (comma separated or repeated, at most 100) returns only employees with exactly those identity numbers and cannot be combined with identityNumber. With includeMissing=true the identityNumbers that matched no employee are listed in meta.missing (or the X-Missing-Identity-Numbers header with EMPLOYEE_LIST_RESPONSE=array).
+// @Description limit (default 5) must be at least 1 and offset (default 0) at least 0; non-numeric values return 400. includeMissing and fuzzy accept the values of strconv.ParseBool (true, false, 1, 0, ...); anything else returns 400. gender, departmentId, sortBy, expand, status, hiredFrom and hiredTo must not be sent empty, while an empty name, identityNumber or q means no filter.
+// @Description Unknown query parameters (e.g. a misspelled departmentId) return 400 listing each of them, as does repeating any parameter other than identityNumbers.
 // @Accept  json
 // @Produce  json
 // @Param Authorization header string true "Bearer + user token"
 // @Param data body dto.GetEmployeesRequest true "data"
-// @Success 200 {object} helper.Response{data=helper.Response} "OK"
+// @Success 200 {object} helper.Response{data=[]dto.EmployeeResponse} "OK (EMPLOYEE_LIST_RESPONSE=envelope)"
+// @Success 200 {array} dto.EmployeeResponse "OK (EMPLOYEE_LIST_RESPONSE=array)"
+// @Header 200 {integer} X-Total-Count "Total matching employees (EMPLOYEE_LIST_RESPONSE=array)"
+// @Header 200 {string} Link "first, prev, next and last pages"
 // @Failure 400 {object} helper.Response{errors=helper.ErrorResponse} "Bad Request"
 // @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorization"
 // @Router /v1/employee [GET]
 func (h handler) GetAll(ctx *gin.Context) {
 	defer helper.FallbackResponse(ctx)
+	log := h.logger.With(logger.RequestFields(ctx, helper.EmployeeHandlerGetEmployees)).WithContext(ctx.Request.Context())
 
-	input := new(dto.GetEmployeesRequest)
+	// Tanpa manager, request berhenti sebelum query dijalankan
+	managerID, err := middleware.GetIdUserFromContext(ctx)
+	if err != nil {
+		log.Warn(err.Error(), logger.Bound)
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, helper.ErrUnauthorized))
+		return
+	}
 
-	setGetEmployeeRequest(ctx, input)
+	input := &dto.GetEmployeesRequest{ManagerID: managerID}
+	if err := setGetEmployeeRequest(ctx, input, employeeListParams); err != nil {
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+	// ?fuzzy=true tanpa flag diperlakukan sebagai pencarian biasa
+	if input.Fuzzy && !h.flags.IsEnabled(ctx, featureflag.FuzzySearch, input.ManagerID) {
+		input.Fuzzy = false
+	}
 
-	err := validation.ValidateEmployeeGet(input)
+	err = validation.ValidateEmployeeGet(input)
 	if err != nil {
-		ctx.JSON(
-			http.StatusBadRequest,
-			helper.NewResponse(
-				helper.ErrorResponse{
-					Code:    helper.GetErrorStatusCode(err),
-					Message: err.Error(),
-				},
-				err,
-			),
-		)
+		ctx.JSON(helper.FromError(err))
 		return
 	}
 
 	response, err := h.service.GetAll(ctx, *input)
-
+	if err == nil && input.Expand == dto.ExpandDepartment {
+		response, err = h.expandDepartments(ctx, input.ManagerID, response)
+	}
+	var missing []string
+	if err == nil && input.IncludeMissing && len(input.IdentityNumbers) > 0 {
+		missing, err = h.service.MissingIdentityNumbers(ctx, *input)
+	}
+	if err == nil {
+		err = h.listResponse.write(ctx, input, response, missing)
+	}
 	if err != nil {
-		ctx.JSON(
-			helper.GetErrorStatusCode(err),
-			helper.NewResponse(
-				nil,
-				errors.New((helper.GetErrorMessage(err)))),
-		)
+		ctx.JSON(helper.FromError(err))
 		return
 	}
-	ctx.JSON(http.StatusOK, helper.NewResponse(response, nil))
 }
 
-func setGetEmployeeRequest(ctx *gin.Context, input *dto.GetEmployeesRequest) {
-	managerId, err := middleware.GetIdUserFromContext(ctx)
+// expandDepartments mengisi Department setiap employee dengan satu query
+// untuk semua department di halaman. Hasilnya slice baru, karena employees
+// bisa berasal dari cache halaman pertama yang dipakai bersama.
+func (h handler) expandDepartments(ctx context.Context, managerID string, employees []dto.EmployeeResponse) ([]dto.EmployeeResponse, error) {
+	if len(employees) == 0 {
+		return employees, nil
+	}
+
+	ids := make([]string, 0, len(employees))
+	for _, employee := range employees {
+		if !slices.Contains(ids, employee.DepartmentID) {
+			ids = append(ids, employee.DepartmentID)
+		}
+	}
+	departments, err := h.departments.GetByIDs(ctx, managerID, ids)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, helper.NewResponse(nil, err))
-		return
+		return nil, err
+	}
+	byID := make(map[string]dto.ResponseSingleDepartment, len(departments))
+	for _, department := range departments {
+		byID[department.DepartmentID] = department
 	}
-	input.ManagerID = managerId
 
-	gender := ctx.Request.URL.Query().Get("gender")
-	input.Gender = strings.ToLower(gender)
+	expanded := make([]dto.EmployeeResponse, len(employees))
+	for i, employee := range employees {
+		if department, ok := byID[employee.DepartmentID]; ok {
+			employee.Department = &department
+		}
+		expanded[i] = employee
+	}
+	return expanded, nil
+}
 
-	idNumber := ctx.Request.URL.Query().Get("identityNumber")
-	input.IdentityNumber = strings.ToLower(idNumber)
+// Import employees from CSV or JSON Lines
+// @Tags employee
+// @Summary Import employees from CSV or JSON Lines
+// @Description Import employees from a CSV file with header identityNumber,name,employeeImageUri,gender,departmentId. Rows are inserted in batches; when the import is aborted the report shows the progress of committed batches.
+// @Description With format=jsonl the file has one employee object per line (the POST /v1/employee body) and rows are upserted by identityNumber: new employees are created and existing employees of the manager are updated. dryRun=true (jsonl only) validates every line and reports the would-be outcome without saving anything.
+// @Accept multipart/form-data
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param format query string false "csv (default) or jsonl"
+// @Param dryRun query bool false "Validate and report without saving (jsonl only)"
+// @Param file formData file true "CSV or JSON Lines file"
+// @Success 200 {object} helper.Response{data=dto.EmployeeImportReport} "OK"
+// @Failure 400 {object} helper.Response{errors=helper.ErrorResponse} "Bad Request"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 408 {object} helper.Response{data=dto.EmployeeImportReport} "Import deadline exceeded"
+// @Failure 413 {object} helper.Response{data=dto.EmployeeImportReport} "File or row limit exceeded"
+// @Failure 500 {object} helper.Response{data=dto.EmployeeImportReport} "Server Error"
+// @Router /v1/employee/import [POST]
+func (h *handler) Import(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+	log := h.logger.With(logger.RequestFields(ctx, helper.EmployeeHandlerImport)).WithContext(ctx.Request.Context())
 
-	name := ctx.Request.URL.Query().Get("name")
-	input.Name = strings.ToLower(name)
+	managerID, err := middleware.GetIdUserFromContext(ctx)
+	if err != nil {
+		log.Warn(err.Error(), logger.Bound)
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
+		return
+	}
 
-	departmentId := ctx.Request.URL.Query().Get("departmentId")
-	input.DepartmentID = strings.ToLower(departmentId)
+	format, dryRun, err := importOptions(ctx.Request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.Error(http.StatusBadRequest, err))
+		return
+	}
 
-	limitParam := ctx.Request.URL.Query().Get("limit")
-	limit, err := strconv.Atoi(limitParam)
-	if err != nil || limit < 0 {
-		input.Limit = dto.DefaultLimit
-	} else {
-		input.Limit = limit
+	if ctx.Request.ContentLength > h.importConfig.MaxUploadBytes {
+		ctx.JSON(http.StatusRequestEntityTooLarge, helper.Error(http.StatusRequestEntityTooLarge, helper.ErrPayloadTooLarge))
+		return
+	}
+	// Batas ukuran dipasang sebelum body dibaca, dan file dibaca langsung
+	// dari part multipart tanpa disimpan ke memori atau disk.
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, h.importConfig.MaxUploadBytes)
+	_ = http.NewResponseController(ctx.Writer).SetReadDeadline(time.Now().Add(h.importConfig.Deadline))
+
+	file, err := importFilePart(ctx.Request)
+	if err != nil {
+		log.Warn(err.Error(), logger.Bound)
+		ctx.JSON(helper.FromError(err))
+		return
 	}
+	defer file.Close()
 
-	offsetParam := ctx.Request.URL.Query().Get("offset")
-	offset, err := strconv.Atoi(offsetParam)
-	if err != nil || offset < 0 {
-		input.Offset = dto.DefaultOffset
+	var report dto.EmployeeImportReport
+	if format == importFormatJSONL {
+		report, err = h.service.ImportJSONL(ctx, file, managerID, dryRun)
 	} else {
+		report, err = h.service.Import(ctx, file, managerID)
+	}
+	if err != nil {
+		log.Warn(err.Error(), logger.Bound, report)
+		status, response := helper.FromError(err)
+		ctx.JSON(status, response.WithData(report))
+		return
+	}
+	ctx.JSON(http.StatusOK, helper.OK(report))
+}
+
+const (
+	importFormatCSV   = "csv"
+	importFormatJSONL = "jsonl"
+)
+
+// importOptions membaca query format dan dryRun. dryRun hanya didukung
+// import JSONL, karena import CSV tidak mencocokkan baris dengan employee
+// yang sudah ada.
+func importOptions(r *http.Request) (format string, dryRun bool, err error) {
+	query := r.URL.Query()
+	switch format = query.Get("format"); format {
+	case "":
+		format = importFormatCSV
+	case importFormatCSV, importFormatJSONL:
+	default:
+		return "", false, validation.FieldErrors{"format": fmt.Sprintf("format must be one of [%s %s]", importFormatCSV, importFormatJSONL)}
+	}
+	if dryRun, err = parseDryRun(r); err != nil {
+		return "", false, err
+	}
+	if dryRun && format != importFormatJSONL {
+		return "", false, validation.FieldErrors{"dryRun": "dryRun is only supported with format=" + importFormatJSONL}
+	}
+	return format, dryRun, nil
+}
+
+// parseDryRun membaca query dryRun dengan strconv.ParseBool. Nilai lain
+// ditolak, karena dryRun yang salah ketik tidak boleh menjadi penulisan
+// sungguhan.
+func parseDryRun(r *http.Request) (bool, error) {
+	values, ok := r.URL.Query()["dryRun"]
+	if !ok {
+		return false, nil
+	}
+	dryRun, err := strconv.ParseBool(values[0])
+	if err != nil {
+		return false, validation.FieldErrors{"dryRun": "dryRun must be a boolean"}
+	}
+	return dryRun, nil
+}
+
+// importFilePart mencari part "file" pada body multipart secara streaming.
+func importFilePart(r *http.Request) (*multipart.Part, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, helper.ErrBadRequest
+	}
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return nil, helper.ErrPayloadTooLarge
+			}
+			return nil, helper.ErrBadRequest
+		}
+		if part.FormName() == "file" {
+			return part, nil
+		}
+		part.Close()
+	}
+}
+
+// employeeFilterParams adalah query filter GET /v1/employee, dicatat di
+// EmptyFilters jika dikirim tanpa nilai.
+var employeeFilterParams = []string{"gender", "identityNumber", "identityNumbers", "name", "q", "departmentId", "sortBy", "expand", "status", "hiredFrom", "hiredTo"}
+
+// employeeListParams adalah semua query GET /v1/employee. Hanya
+// identityNumbers yang boleh diulang.
+var employeeListParams = validation.QueryParams{
+	Scalars: []string{
+		"limit", "offset", "gender", "identityNumber", "includeMissing", "name", "q", "fuzzy",
+		"departmentId", "sortBy", "expand", "status", "hiredFrom", "hiredTo",
+	},
+	Lists: []string{"identityNumbers"},
+}
+
+// setGetEmployeeRequest mengisi input dari query string. Parameter di luar
+// params dan parameter scalar yang diulang ditolak. limit dan offset yang
+// tidak dikirim memakai default, sedangkan nilai yang bukan angka (termasuk
+// kosong) ditolak; batas nilainya dicek ValidateEmployeeGet.
+func setGetEmployeeRequest(ctx *gin.Context, input *dto.GetEmployeesRequest, params validation.QueryParams) error {
+	query := ctx.Request.URL.Query()
+	if err := validation.ValidateQuery(query, params); err != nil {
+		return err
+	}
+
+	// Nilai filter dikirim apa adanya, normalisasinya ada di
+	// validation.ValidateEmployeeGet
+	input.Gender = query.Get("gender")
+	input.IdentityNumber = query.Get("identityNumber")
+	// identityNumbers boleh dipisah koma, diulang, atau keduanya
+	for _, value := range query["identityNumbers"] {
+		if value == "" {
+			continue
+		}
+		for _, identityNumber := range strings.Split(value, ",") {
+			input.IdentityNumbers = append(input.IdentityNumbers, strings.TrimSpace(identityNumber))
+		}
+	}
+	input.Name = query.Get("name")
+	input.Query = query.Get("q")
+	input.DepartmentID = query.Get("departmentId")
+
+	input.SortBy = query.Get("sortBy")
+	input.Expand = query.Get("expand")
+	input.Status = query.Get("status")
+	input.HiredFrom = query.Get("hiredFrom")
+	input.HiredTo = query.Get("hiredTo")
+
+	for _, param := range employeeFilterParams {
+		if values, ok := query[param]; ok && values[0] == "" {
+			input.EmptyFilters = append(input.EmptyFilters, param)
+		}
+	}
+
+	fieldErrors := validation.FieldErrors{}
+	input.Limit = dto.DefaultLimit
+	if values, ok := query["limit"]; ok {
+		limit, err := strconv.Atoi(values[0])
+		if err != nil {
+			fieldErrors["limit"] = "limit must be a number"
+		}
+		input.Limit = limit
+	}
+	input.Offset = dto.DefaultOffset
+	if values, ok := query["offset"]; ok {
+		offset, err := strconv.Atoi(values[0])
+		if err != nil {
+			fieldErrors["offset"] = "offset must be a number"
+		}
 		input.Offset = offset
 	}
+	input.IncludeMissing = parseBoolParam(query, "includeMissing", fieldErrors)
+	input.Fuzzy = parseBoolParam(query, "fuzzy", fieldErrors)
+	if len(fieldErrors) > 0 {
+		return fieldErrors
+	}
+	return nil
+}
+
+// parseBoolParam membaca query boolean name dengan strconv.ParseBool seperti
+// parseDryRun. Nilai lain dicatat di fieldErrors alih-alih dianggap false.
+func parseBoolParam(query url.Values, name string, fieldErrors validation.FieldErrors) bool {
+	values, ok := query[name]
+	if !ok {
+		return false
+	}
+	value, err := strconv.ParseBool(values[0])
+	if err != nil {
+		fieldErrors[name] = name + " must be a boolean"
+	}
+	return value
 }
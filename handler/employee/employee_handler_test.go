@@ -0,0 +1,845 @@
+package employeeHandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/featureflag"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/middleware"
+	"github.com/levensspel/go-gin-template/mocks"
+	"github.com/levensspel/go-gin-template/validation"
+)
+
+const (
+	testManagerID    = "0b7c2d2e-4f4a-4b8e-9c59-2f1d8f6a3e10"
+	testDepartmentID = "6a1f0d0e-9c1b-4c3e-8f0a-5b2d7e4c9a11"
+)
+
+// disabledFlags mematikan semua feature flag.
+type disabledFlags struct {
+	featureflag.FeatureFlags
+}
+
+func (disabledFlags) IsEnabled(ctx context.Context, flag featureflag.Flag, managerId string) bool {
+	return false
+}
+
+func newTestHandler(service *mocks.EmployeeService, listShape string) EmployeeHandler {
+	return NewEmployeeHandler(
+		service,
+		nil,
+		disabledFlags{},
+		mocks.Logger{},
+		&config.ImportConfig{},
+		&config.ExportConfig{},
+		&config.ResponseConfig{EmployeeList: listShape},
+		0,
+	)
+}
+
+// serve menjalankan handle untuk satu request. managerID kosong berarti
+// request tanpa user dari middleware Authorization.
+func serve(handle gin.HandlerFunc, method, target, body, managerID string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(method, target, strings.NewReader(body))
+	ctx.Request.Host = "api.example.com"
+	if managerID != "" {
+		ctx.Set(helper.ContextKeyUserID, managerID)
+	}
+	handle(ctx)
+	return w
+}
+
+func decodeResponse(t *testing.T, w *httptest.ResponseRecorder) helper.Response {
+	t.Helper()
+	var response helper.Response
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode body %s: %v", w.Body, err)
+	}
+	return response
+}
+
+const validEmployee = `{
+	"identityNumber": "EMP-00001",
+	"name": "Budi Santoso",
+	"employeeImageUri": "https://example.com/budi.png",
+	"gender": "Male",
+	"departmentId": "` + testDepartmentID + `"
+}`
+
+func TestCreateStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		target    string
+		body      string
+		managerID string
+		createErr error
+		want      int
+		// wantCreate berarti service.Create harus dipanggil
+		wantCreate bool
+	}{
+		{name: "created", body: validEmployee, managerID: testManagerID, want: http.StatusCreated, wantCreate: true},
+		{name: "no manager", body: validEmployee, want: http.StatusUnauthorized},
+		{name: "invalid json", body: `{"name":`, managerID: testManagerID, want: http.StatusBadRequest},
+		{name: "validation error", body: `{"identityNumber": "EMP-00001"}`, managerID: testManagerID, want: http.StatusBadRequest},
+		{
+			name:      "invalid gender",
+			body:      strings.Replace(validEmployee, `"Male"`, `"other"`, 1),
+			managerID: testManagerID,
+			want:      http.StatusBadRequest,
+		},
+		{name: "invalid dryRun", target: "?dryRun=yes please", body: validEmployee, managerID: testManagerID, want: http.StatusBadRequest},
+		{
+			name:       "department of another manager",
+			body:       validEmployee,
+			managerID:  testManagerID,
+			createErr:  helper.ErrInvalidDepartmentId,
+			want:       http.StatusBadRequest,
+			wantCreate: true,
+		},
+		{
+			name:       "identity number conflict",
+			body:       validEmployee,
+			managerID:  testManagerID,
+			createErr:  helper.ErrConflictIdentityNumber,
+			want:       http.StatusConflict,
+			wantCreate: true,
+		},
+		{
+			name:       "service error",
+			body:       validEmployee,
+			managerID:  testManagerID,
+			createErr:  errors.New("insert failed"),
+			want:       http.StatusInternalServerError,
+			wantCreate: true,
+		},
+		{
+			name:       "database unavailable",
+			body:       validEmployee,
+			managerID:  testManagerID,
+			createErr:  helper.ErrServiceUnavailable,
+			want:       http.StatusServiceUnavailable,
+			wantCreate: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created := false
+			service := &mocks.EmployeeService{
+				CreateFunc: func(ctx context.Context, input dto.EmployeePayload, managerId string) (dto.EmployeeResponse, error) {
+					created = true
+					if managerId != testManagerID {
+						t.Errorf("Create managerId = %q, want %q", managerId, testManagerID)
+					}
+					if input.Gender != "male" {
+						t.Errorf("Create gender = %q, want it lowercased", input.Gender)
+					}
+					if tt.createErr != nil {
+						return dto.EmployeeResponse{}, tt.createErr
+					}
+					return dto.EmployeeResponse{EmployeePayload: input, Status: dto.EmployeeStatusActive}, nil
+				},
+			}
+			h := newTestHandler(service, config.ListResponseEnvelope)
+			w := serve(h.Create, http.MethodPost, "/v1/employee"+strings.ReplaceAll(tt.target, " ", "%20"), tt.body, tt.managerID)
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, tt.want, w.Body)
+			}
+			if created != tt.wantCreate {
+				t.Fatalf("service.Create called = %t, want %t", created, tt.wantCreate)
+			}
+			response := decodeResponse(t, w)
+			if tt.want >= http.StatusBadRequest && (response.Errors == nil || response.Errors.Code != tt.want) {
+				t.Fatalf("errors = %+v, want code %d", response.Errors, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateReturnsInsertedEmployee(t *testing.T) {
+	service := &mocks.EmployeeService{
+		CreateFunc: func(ctx context.Context, input dto.EmployeePayload, managerId string) (dto.EmployeeResponse, error) {
+			return dto.EmployeeResponse{EmployeePayload: input, Status: dto.EmployeeStatusActive}, nil
+		},
+	}
+	w := serve(newTestHandler(service, config.ListResponseEnvelope).Create, http.MethodPost, "/v1/employee", validEmployee, testManagerID)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+	var body struct {
+		Data dto.EmployeeResponse `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Data.IdentityNumber != "EMP-00001" || body.Data.Status != dto.EmployeeStatusActive {
+		t.Fatalf("data = %+v, want the employee returned by the service", body.Data)
+	}
+}
+
+// TestCreateUnexpectedError memastikan error repository yang tidak
+// terdaftar dijawab 500 dengan pesan generik, tanpa membocorkan error asli
+// ke client.
+func TestCreateUnexpectedError(t *testing.T) {
+	repositoryErr := fmt.Errorf("insert employee: %w", &pgconn.PgError{
+		Code:    "XX000",
+		Message: `relation "employees_history" does not exist`,
+	})
+	service := &mocks.EmployeeService{
+		CreateFunc: func(ctx context.Context, input dto.EmployeePayload, managerId string) (dto.EmployeeResponse, error) {
+			return dto.EmployeeResponse{}, repositoryErr
+		},
+	}
+	w := serve(newTestHandler(service, config.ListResponseEnvelope).Create, http.MethodPost, "/v1/employee", validEmployee, testManagerID)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d, body = %s", w.Code, http.StatusInternalServerError, w.Body)
+	}
+	response := decodeResponse(t, w)
+	if response.Errors == nil || response.Errors.Code != http.StatusInternalServerError {
+		t.Fatalf("errors = %+v, want code %d", response.Errors, http.StatusInternalServerError)
+	}
+	if response.Errors.Message != helper.GetErrorMessage(helper.ErrInternalServer) {
+		t.Fatalf("message = %q, want %q", response.Errors.Message, helper.GetErrorMessage(helper.ErrInternalServer))
+	}
+	if response.Data != nil || len(response.Errors.Fields) != 0 {
+		t.Fatalf("response = %+v, want only the error", response)
+	}
+	if body := w.Body.String(); strings.Contains(body, "employees_history") || strings.Contains(body, "XX000") {
+		t.Fatalf("body = %s leaks the repository error", body)
+	}
+}
+
+func TestCreateResponseShape(t *testing.T) {
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	service := &mocks.EmployeeService{
+		CreateFunc: func(ctx context.Context, input dto.EmployeePayload, managerId string) (dto.EmployeeResponse, error) {
+			return dto.EmployeeResponse{EmployeePayload: input, CreatedAt: createdAt, UpdatedAt: createdAt, Status: dto.EmployeeStatusActive}, nil
+		},
+	}
+	h := newTestHandler(service, config.ListResponseEnvelope)
+
+	tests := []struct {
+		name string
+		body string
+		want int
+		// wantKeys adalah key level atas body
+		wantKeys []string
+	}{
+		{name: "created", body: validEmployee, want: http.StatusCreated, wantKeys: []string{"data"}},
+		{name: "validation error", body: `{"identityNumber": "EMP-00001"}`, want: http.StatusBadRequest, wantKeys: []string{"errors"}},
+		{name: "invalid json", body: `{"name":`, want: http.StatusBadRequest, wantKeys: []string{"errors"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(h.Create, http.MethodPost, "/v1/employee", tt.body, testManagerID)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, tt.want, w.Body)
+			}
+			var body map[string]json.RawMessage
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode body %s: %v", w.Body, err)
+			}
+			keys := make([]string, 0, len(body))
+			for key := range body {
+				keys = append(keys, key)
+			}
+			if !slices.Equal(keys, tt.wantKeys) {
+				t.Fatalf("body keys = %v, want %v, body = %s", keys, tt.wantKeys, w.Body)
+			}
+		})
+	}
+
+	// Body berisi employee hasil insert, bukan input yang dikirim client
+	w := serve(h.Create, http.MethodPost, "/v1/employee", validEmployee, testManagerID)
+	var body struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Data["gender"] != "male" || body.Data["createdAt"] != createdAt.Format(time.RFC3339) || body.Data["status"] != dto.EmployeeStatusActive {
+		t.Fatalf("data = %v, want the normalized employee with createdAt and status", body.Data)
+	}
+
+	// Error validasi memakai envelope yang sama dengan endpoint tulis lain
+	w = serve(h.Create, http.MethodPost, "/v1/employee", `{"identityNumber": "EMP-00001"}`, testManagerID)
+	if fields := decodeResponse(t, w).Errors.Fields; fields["name"] == "" || fields["departmentId"] == "" {
+		t.Fatalf("field errors = %v, want name and departmentId", fields)
+	}
+}
+
+func TestCreateDryRun(t *testing.T) {
+	service := &mocks.EmployeeService{
+		DryRunCreateFunc: func(ctx context.Context, input dto.EmployeePayload, managerId string) (validation.FieldErrors, error) {
+			return validation.FieldErrors{"identityNumber": "identityNumber is already used"}, nil
+		},
+	}
+	w := serve(newTestHandler(service, config.ListResponseEnvelope).Create, http.MethodPost, "/v1/employee?dryRun=true", validEmployee, testManagerID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+	var body struct {
+		Data dto.EmployeeDryRunResult `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Data.Valid || body.Data.Fields["identityNumber"] == "" {
+		t.Fatalf("dry run result = %+v, want invalid identityNumber", body.Data)
+	}
+}
+
+// TestDryRunValues memastikan dryRun dibaca dengan strconv.ParseBool di
+// Create dan BulkCreate, dan nilai lain ditolak tanpa menulis apa pun.
+func TestDryRunValues(t *testing.T) {
+	tests := []struct {
+		query string
+		want  int
+		// wantDryRun berarti service dry run yang dipanggil
+		wantDryRun bool
+	}{
+		{query: "", want: http.StatusCreated},
+		{query: "?dryRun=true", want: http.StatusOK, wantDryRun: true},
+		{query: "?dryRun=1", want: http.StatusOK, wantDryRun: true},
+		{query: "?dryRun=TRUE", want: http.StatusOK, wantDryRun: true},
+		{query: "?dryRun=false", want: http.StatusCreated},
+		{query: "?dryRun=0", want: http.StatusCreated},
+		{query: "?dryRun=", want: http.StatusBadRequest},
+		{query: "?dryRun=yes", want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		for _, endpoint := range []string{"create", "bulk"} {
+			t.Run(endpoint+tt.query, func(t *testing.T) {
+				var calls []string
+				service := &mocks.EmployeeService{
+					CreateFunc: func(ctx context.Context, input dto.EmployeePayload, managerId string) (dto.EmployeeResponse, error) {
+						calls = append(calls, "create")
+						return dto.EmployeeResponse{EmployeePayload: input}, nil
+					},
+					CreateManyFunc: func(ctx context.Context, inputs []dto.EmployeePayload, managerId string) ([]error, error) {
+						calls = append(calls, "create")
+						return make([]error, len(inputs)), nil
+					},
+					DryRunCreateFunc: func(ctx context.Context, input dto.EmployeePayload, managerId string) (validation.FieldErrors, error) {
+						calls = append(calls, "dry run")
+						return nil, nil
+					},
+					DryRunCreateManyFunc: func(ctx context.Context, inputs []dto.EmployeePayload, managerId string) ([]validation.FieldErrors, error) {
+						calls = append(calls, "dry run")
+						return make([]validation.FieldErrors, len(inputs)), nil
+					},
+				}
+				h := newTestHandler(service, config.ListResponseEnvelope)
+				handle, target, body, want := h.Create, "/v1/employee"+tt.query, validEmployee, tt.want
+				if endpoint == "bulk" {
+					handle, target, body = h.BulkCreate, "/v1/employee/bulk"+tt.query, "["+validEmployee+"]"
+					// Bulk create menjawab 200 dengan status per item
+					if want == http.StatusCreated {
+						want = http.StatusOK
+					}
+				}
+
+				w := serve(handle, http.MethodPost, target, body, testManagerID)
+				if w.Code != want {
+					t.Fatalf("status = %d, want %d, body = %s", w.Code, want, w.Body)
+				}
+				switch {
+				case tt.want == http.StatusBadRequest:
+					if len(calls) != 0 || decodeResponse(t, w).Errors.Fields["dryRun"] == "" {
+						t.Fatalf("calls = %v, body = %s, want a dryRun field error and no service call", calls, w.Body)
+					}
+				case tt.wantDryRun:
+					if !slices.Equal(calls, []string{"dry run"}) {
+						t.Fatalf("calls = %v, want only the dry run", calls)
+					}
+				default:
+					if !slices.Equal(calls, []string{"create"}) {
+						t.Fatalf("calls = %v, want only the create", calls)
+					}
+				}
+			})
+		}
+	}
+}
+
+// listService mengembalikan employees untuk GetAll dan total untuk Count,
+// serta menyimpan input GetAll terakhir.
+func listService(employees []dto.EmployeeResponse, total int64, listErr, countErr error) (*mocks.EmployeeService, *dto.GetEmployeesRequest) {
+	var got dto.GetEmployeesRequest
+	return &mocks.EmployeeService{
+		GetAllFunc: func(ctx context.Context, input dto.GetEmployeesRequest) ([]dto.EmployeeResponse, error) {
+			got = input
+			return employees, listErr
+		},
+		CountFunc: func(ctx context.Context, input dto.GetEmployeesRequest) (int64, error) {
+			return total, countErr
+		},
+	}, &got
+}
+
+func TestGetAllStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		target    string
+		managerID string
+		listErr   error
+		countErr  error
+		want      int
+		wantQuery bool
+	}{
+		{name: "ok", target: "/v1/employee", managerID: testManagerID, want: http.StatusOK, wantQuery: true},
+		{name: "no manager", target: "/v1/employee", want: http.StatusUnauthorized},
+		{name: "unknown parameter", target: "/v1/employee?departmentID=" + testDepartmentID, managerID: testManagerID, want: http.StatusBadRequest},
+		{name: "repeated parameter", target: "/v1/employee?gender=male&gender=female", managerID: testManagerID, want: http.StatusBadRequest},
+		{name: "limit not a number", target: "/v1/employee?limit=ten", managerID: testManagerID, want: http.StatusBadRequest},
+		{name: "limit too small", target: "/v1/employee?limit=0", managerID: testManagerID, want: http.StatusBadRequest},
+		{name: "includeMissing not a boolean", target: "/v1/employee?identityNumbers=EMP-00001&includeMissing=yes", managerID: testManagerID, want: http.StatusBadRequest},
+		{name: "empty includeMissing", target: "/v1/employee?includeMissing=", managerID: testManagerID, want: http.StatusBadRequest},
+		{name: "fuzzy not a boolean", target: "/v1/employee?name=budi&fuzzy=on", managerID: testManagerID, want: http.StatusBadRequest},
+		{name: "invalid gender", target: "/v1/employee?gender=other", managerID: testManagerID, want: http.StatusBadRequest},
+		{name: "empty departmentId", target: "/v1/employee?departmentId=", managerID: testManagerID, want: http.StatusBadRequest},
+		{
+			name:      "query error",
+			target:    "/v1/employee",
+			managerID: testManagerID,
+			listErr:   errors.New("query failed"),
+			want:      http.StatusInternalServerError,
+			wantQuery: true,
+		},
+		{
+			name:      "query timeout",
+			target:    "/v1/employee",
+			managerID: testManagerID,
+			listErr:   helper.ErrQueryTimeout,
+			want:      http.StatusGatewayTimeout,
+			wantQuery: true,
+		},
+		{
+			name:      "count error",
+			target:    "/v1/employee",
+			managerID: testManagerID,
+			countErr:  errors.New("count failed"),
+			want:      http.StatusInternalServerError,
+			wantQuery: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, got := listService([]dto.EmployeeResponse{}, 0, tt.listErr, tt.countErr)
+			w := serve(newTestHandler(service, config.ListResponseEnvelope).GetAll, http.MethodGet, tt.target, "", tt.managerID)
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, tt.want, w.Body)
+			}
+			if queried := got.ManagerID != ""; queried != tt.wantQuery {
+				t.Fatalf("service.GetAll called = %t, want %t", queried, tt.wantQuery)
+			}
+			if tt.want >= http.StatusBadRequest {
+				if response := decodeResponse(t, w); response.Errors == nil || response.Errors.Code != tt.want {
+					t.Fatalf("errors = %+v, want code %d", response.Errors, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestListWithoutManager(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	calls := 0
+	service := &mocks.EmployeeService{
+		GetAllFunc: func(ctx context.Context, input dto.GetEmployeesRequest) ([]dto.EmployeeResponse, error) {
+			calls++
+			return nil, nil
+		},
+		CountFunc: func(ctx context.Context, input dto.GetEmployeesRequest) (int64, error) {
+			calls++
+			return 0, nil
+		},
+		ExportFunc: func(ctx context.Context, input dto.GetEmployeesRequest, fn func(dto.EmployeeResponse) error) error {
+			calls++
+			return nil
+		},
+	}
+	h := newTestHandler(service, config.ListResponseEnvelope)
+
+	for _, tt := range []struct {
+		name          string
+		target        string
+		handle        gin.HandlerFunc
+		authorization string
+		// withoutAuthorization memanggil handler tanpa middleware
+		// Authorization, sehingga tidak ada user di context
+		withoutAuthorization bool
+	}{
+		{name: "list without token", target: "/v1/employee", handle: h.GetAll},
+		{name: "list with invalid token", target: "/v1/employee", handle: h.GetAll, authorization: "Bearer not-a-token"},
+		{name: "list without user", target: "/v1/employee?limit=5", handle: h.GetAll, withoutAuthorization: true},
+		{name: "export without token", target: "/v1/employee/export", handle: h.Export},
+		{name: "export without user", target: "/v1/employee/export?format=ndjson", handle: h.Export, withoutAuthorization: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = 0
+			var w *httptest.ResponseRecorder
+			if tt.withoutAuthorization {
+				w = serve(tt.handle, http.MethodGet, tt.target, "", "")
+			} else {
+				router := gin.New()
+				router.GET("/v1/employee", middleware.Authorization, tt.handle)
+				router.GET("/v1/employee/export", middleware.Authorization, tt.handle)
+				request := httptest.NewRequest(http.MethodGet, tt.target, nil)
+				if tt.authorization != "" {
+					request.Header.Set("Authorization", tt.authorization)
+				}
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, request)
+			}
+
+			if w.Code != http.StatusUnauthorized {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, http.StatusUnauthorized, w.Body)
+			}
+			if calls != 0 {
+				t.Fatalf("service was called %d times", calls)
+			}
+			// json.Unmarshal menolak body berisi dua response
+			if response := decodeResponse(t, w); response.Data != nil || response.Errors == nil || response.Errors.Code != http.StatusUnauthorized {
+				t.Fatalf("body = %s, want a single 401 errors envelope", w.Body)
+			}
+		})
+	}
+}
+
+func TestGetAllPassesFilters(t *testing.T) {
+	service, got := listService([]dto.EmployeeResponse{}, 0, nil, nil)
+	target := "/v1/employee?limit=2&offset=4&gender=FEMALE&name=budi&departmentId=" + testDepartmentID + "&sortBy=name&fuzzy=true"
+	w := serve(newTestHandler(service, config.ListResponseEnvelope).GetAll, http.MethodGet, target, "", testManagerID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+	want := dto.GetEmployeesRequest{
+		Limit:        2,
+		Offset:       4,
+		Gender:       "female",
+		Name:         "budi",
+		DepartmentID: testDepartmentID,
+		SortBy:       "name",
+		Status:       dto.EmployeeStatusActive,
+		ManagerID:    testManagerID,
+	}
+	// fuzzy tanpa feature flag menjadi pencarian biasa
+	if got.Limit != want.Limit || got.Offset != want.Offset || got.Gender != want.Gender || got.Name != want.Name ||
+		got.DepartmentID != want.DepartmentID || got.SortBy != want.SortBy || got.Status != want.Status ||
+		got.ManagerID != want.ManagerID || got.Fuzzy {
+		t.Fatalf("GetAll input = %+v, want %+v", *got, want)
+	}
+}
+
+func TestGetAllIdentityNumbers(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		want   int
+		// wantIdentityNumbers adalah IdentityNumbers yang diteruskan ke service
+		wantIdentityNumbers []string
+		// wantMissing berarti MissingIdentityNumbers dipanggil
+		wantMissing bool
+	}{
+		{name: "comma separated", target: "/v1/employee?identityNumbers=EMP-00001,EMP-00002", want: http.StatusOK, wantIdentityNumbers: []string{"EMP-00001", "EMP-00002"}},
+		{name: "repeated", target: "/v1/employee?identityNumbers=EMP-00001&identityNumbers=EMP-00002", want: http.StatusOK, wantIdentityNumbers: []string{"EMP-00001", "EMP-00002"}},
+		{name: "both with spaces", target: "/v1/employee?identityNumbers=EMP-00001,%20EMP-00002&identityNumbers=EMP-00003", want: http.StatusOK, wantIdentityNumbers: []string{"EMP-00001", "EMP-00002", "EMP-00003"}},
+		{name: "include missing", target: "/v1/employee?identityNumbers=EMP-00001&includeMissing=true", want: http.StatusOK, wantIdentityNumbers: []string{"EMP-00001"}, wantMissing: true},
+		// Tanpa identityNumbers tidak ada yang perlu dicari
+		{name: "include missing without identityNumbers", target: "/v1/employee?includeMissing=true", want: http.StatusOK},
+		{name: "include missing as 1", target: "/v1/employee?identityNumbers=EMP-00001&includeMissing=1", want: http.StatusOK, wantIdentityNumbers: []string{"EMP-00001"}, wantMissing: true},
+		{name: "include missing false", target: "/v1/employee?identityNumbers=EMP-00001&includeMissing=false", want: http.StatusOK, wantIdentityNumbers: []string{"EMP-00001"}},
+		{name: "include missing not a boolean", target: "/v1/employee?identityNumbers=EMP-00001&includeMissing=yes", want: http.StatusBadRequest},
+		{name: "empty", target: "/v1/employee?identityNumbers=", want: http.StatusBadRequest},
+		{name: "empty value in list", target: "/v1/employee?identityNumbers=EMP-00001,", want: http.StatusBadRequest},
+		{name: "with identityNumber", target: "/v1/employee?identityNumbers=EMP-00001&identityNumber=EMP", want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, got := listService([]dto.EmployeeResponse{}, 0, nil, nil)
+			var missingCalls int
+			service.MissingIdentityNumbersFunc = func(ctx context.Context, input dto.GetEmployeesRequest) ([]string, error) {
+				missingCalls++
+				return []string{}, nil
+			}
+			w := serve(newTestHandler(service, config.ListResponseEnvelope).GetAll, http.MethodGet, tt.target, "", testManagerID)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, tt.want, w.Body)
+			}
+			if tt.want != http.StatusOK {
+				if fields := decodeResponse(t, w).Errors.Fields; len(fields) == 0 {
+					t.Fatalf("body = %s, want field errors", w.Body)
+				}
+				return
+			}
+			if !slices.Equal(got.IdentityNumbers, tt.wantIdentityNumbers) {
+				t.Fatalf("IdentityNumbers = %q, want %q", got.IdentityNumbers, tt.wantIdentityNumbers)
+			}
+			if (missingCalls == 1) != tt.wantMissing || missingCalls > 1 {
+				t.Fatalf("MissingIdentityNumbers calls = %d, want %t", missingCalls, tt.wantMissing)
+			}
+		})
+	}
+}
+
+func TestGetAllMissingIdentityNumbersError(t *testing.T) {
+	service, _ := listService([]dto.EmployeeResponse{}, 0, nil, nil)
+	service.MissingIdentityNumbersFunc = func(ctx context.Context, input dto.GetEmployeesRequest) ([]string, error) {
+		return nil, helper.ErrInternalServer
+	}
+	w := serve(newTestHandler(service, config.ListResponseEnvelope).GetAll, http.MethodGet, "/v1/employee?identityNumbers=EMP-00001&includeMissing=true", "", testManagerID)
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d, body = %s", w.Code, http.StatusInternalServerError, w.Body)
+	}
+	// Tidak ada halaman setengah jadi, hanya error
+	if response := decodeResponse(t, w); response.Data != nil || response.Errors == nil {
+		t.Fatalf("body = %s, want only errors", w.Body)
+	}
+}
+
+// TestGetAllQueryParams memastikan parameter yang salah ketik atau diulang
+// ditolak bersama-sama, bukan diabaikan diam-diam.
+func TestGetAllQueryParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		export bool
+		target string
+		// wantFields adalah field error yang diharapkan; nil berarti 200
+		wantFields []string
+	}{
+		{name: "typo", target: "/v1/employee?departmenId=" + testDepartmentID, wantFields: []string{"departmenId"}},
+		{name: "duplicate limit", target: "/v1/employee?limit=1&limit=2", wantFields: []string{"limit"}},
+		{name: "repeated identityNumbers", target: "/v1/employee?identityNumbers=EMP-00001&identityNumbers=EMP-00002"},
+		{name: "mixed valid and invalid", target: "/v1/employee?limit=5&name=budi&name=ani&departmenId=x&cache=1", wantFields: []string{"name", "departmenId", "cache"}},
+		{name: "format on the list", target: "/v1/employee?format=csv", wantFields: []string{"format"}},
+		{name: "format on export", export: true, target: "/v1/employee/export?format=ndjson"},
+		{name: "typo on export", export: true, target: "/v1/employee/export?format=ndjson&departmenId=x", wantFields: []string{"departmenId"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, got := listService([]dto.EmployeeResponse{}, 0, nil, nil)
+			exported := false
+			service.ExportFunc = func(ctx context.Context, input dto.GetEmployeesRequest, fn func(dto.EmployeeResponse) error) error {
+				exported = true
+				return nil
+			}
+			h := newTestHandler(service, config.ListResponseEnvelope)
+			handle := h.GetAll
+			if tt.export {
+				handle = h.Export
+			}
+			w := serve(handle, http.MethodGet, tt.target, "", testManagerID)
+
+			called := got.ManagerID != "" || exported
+			if tt.wantFields == nil {
+				if w.Code != http.StatusOK || !called {
+					t.Fatalf("status = %d, called = %t, body = %s", w.Code, called, w.Body)
+				}
+				return
+			}
+			if w.Code != http.StatusBadRequest || called {
+				t.Fatalf("status = %d, called = %t, want 400 without a service call", w.Code, called)
+			}
+			fields := decodeResponse(t, w).Errors.Fields
+			if len(fields) != len(tt.wantFields) {
+				t.Fatalf("fields = %v, want %q", fields, tt.wantFields)
+			}
+			for _, field := range tt.wantFields {
+				if fields[field] == "" {
+					t.Fatalf("fields = %v, want %q", fields, tt.wantFields)
+				}
+			}
+		})
+	}
+}
+
+func TestGetAllLimitsAndEmptyFilters(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantLimit  int
+		wantOffset int
+		// wantFields kosong berarti request diteruskan ke service
+		wantFields []string
+	}{
+		{name: "absent", query: "", wantLimit: dto.DefaultLimit, wantOffset: dto.DefaultOffset},
+		{name: "explicit", query: "?limit=1&offset=0", wantLimit: 1, wantOffset: 0},
+		{name: "limit 0", query: "?limit=0", wantFields: []string{"limit"}},
+		{name: "empty limit", query: "?limit=", wantFields: []string{"limit"}},
+		{name: "limit not a number", query: "?limit=abc", wantFields: []string{"limit"}},
+		{name: "negative offset", query: "?offset=-1", wantFields: []string{"offset"}},
+		{name: "empty offset", query: "?offset=", wantFields: []string{"offset"}},
+		{name: "offset not a number", query: "?offset=abc", wantFields: []string{"offset"}},
+		{name: "empty limit and offset", query: "?limit=&offset=", wantFields: []string{"limit", "offset"}},
+		{name: "empty gender", query: "?gender=", wantFields: []string{"gender"}},
+		{name: "empty departmentId and sortBy", query: "?departmentId=&sortBy=", wantFields: []string{"departmentId", "sortBy"}},
+		{name: "empty status", query: "?status=", wantFields: []string{"status"}},
+		// Pencarian teks kosong berarti tanpa filter
+		{name: "empty text search", query: "?name=&identityNumber=&q=", wantLimit: dto.DefaultLimit, wantOffset: dto.DefaultOffset},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, got := listService([]dto.EmployeeResponse{}, 0, nil, nil)
+			w := serve(newTestHandler(service, config.ListResponseEnvelope).GetAll, http.MethodGet, "/v1/employee"+tt.query, "", testManagerID)
+			if len(tt.wantFields) == 0 {
+				if w.Code != http.StatusOK {
+					t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+				}
+				if got.Limit != tt.wantLimit || got.Offset != tt.wantOffset {
+					t.Fatalf("limit, offset = %d, %d, want %d, %d", got.Limit, got.Offset, tt.wantLimit, tt.wantOffset)
+				}
+				return
+			}
+			if w.Code != http.StatusBadRequest || got.ManagerID != "" {
+				t.Fatalf("status = %d, service called = %t, want 400 without a query", w.Code, got.ManagerID != "")
+			}
+			fields := decodeResponse(t, w).Errors.Fields
+			if len(fields) != len(tt.wantFields) {
+				t.Fatalf("field errors = %v, want %v", fields, tt.wantFields)
+			}
+			for _, field := range tt.wantFields {
+				if fields[field] == "" {
+					t.Fatalf("field errors = %v, want %s", fields, field)
+				}
+			}
+		})
+	}
+}
+
+func TestGetAllResponseShapes(t *testing.T) {
+	employees := []dto.EmployeeResponse{
+		{EmployeePayload: dto.EmployeePayload{IdentityNumber: "EMP-00001"}},
+		{EmployeePayload: dto.EmployeePayload{IdentityNumber: "EMP-00002"}},
+	}
+	wantLink := `<http://api.example.com/v1/employee?limit=2&offset=0>; rel="first", ` +
+		`<http://api.example.com/v1/employee?limit=2&offset=2>; rel="next", ` +
+		`<http://api.example.com/v1/employee?limit=2&offset=2>; rel="last"`
+
+	t.Run("envelope", func(t *testing.T) {
+		service, _ := listService(employees, 3, nil, nil)
+		w := serve(newTestHandler(service, config.ListResponseEnvelope).GetAll, http.MethodGet, "/v1/employee?limit=2", "", testManagerID)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+		}
+		var body struct {
+			Data []dto.EmployeeResponse `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		if len(body.Data) != 2 || body.Data[1].IdentityNumber != "EMP-00002" {
+			t.Fatalf("data = %+v", body.Data)
+		}
+		if got := w.Header().Get("Link"); got != wantLink {
+			t.Fatalf("Link =\n%s\nwant\n%s", got, wantLink)
+		}
+	})
+	t.Run("array", func(t *testing.T) {
+		service, _ := listService(employees, 3, nil, nil)
+		w := serve(newTestHandler(service, config.ListResponseArray).GetAll, http.MethodGet, "/v1/employee?limit=2", "", testManagerID)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+		}
+		var body []dto.EmployeeResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		if len(body) != 2 || w.Header().Get("X-Total-Count") != "3" || w.Header().Get("Link") != wantLink {
+			t.Fatalf("body = %+v, X-Total-Count = %q, Link = %q", body, w.Header().Get("X-Total-Count"), w.Header().Get("Link"))
+		}
+	})
+	t.Run("empty array", func(t *testing.T) {
+		service, _ := listService(nil, 0, nil, nil)
+		w := serve(newTestHandler(service, config.ListResponseArray).GetAll, http.MethodGet, "/v1/employee", "", testManagerID)
+		if body := strings.TrimSpace(w.Body.String()); body != "[]" {
+			t.Fatalf("body = %s, want []", body)
+		}
+	})
+}
+
+func TestUpdateStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		managerID string
+		updateErr error
+		want      int
+		// wantField adalah field error yang diharapkan pada 400
+		wantField string
+		// wantNulls adalah Nulls input yang diteruskan ke service.Update
+		wantNulls []string
+	}{
+		{name: "one field", body: `{"name": "Budi Santoso"}`, managerID: testManagerID, want: http.StatusOK},
+		{name: "clear image", body: `{"employeeImageUri": null}`, managerID: testManagerID, want: http.StatusOK, wantNulls: []string{"employeeImageUri"}},
+		{name: "clear hiredAt", body: `{"hiredAt": null, "name": "Budi Santoso"}`, managerID: testManagerID, want: http.StatusOK, wantNulls: []string{"hiredAt"}},
+		{name: "no manager", body: `{"name": "Budi Santoso"}`, want: http.StatusUnauthorized},
+		{name: "invalid json", body: `{"name":`, managerID: testManagerID, want: http.StatusBadRequest},
+		{name: "no field", body: `{}`, managerID: testManagerID, want: http.StatusBadRequest},
+		{name: "null name", body: `{"name": null}`, managerID: testManagerID, want: http.StatusBadRequest, wantField: "name"},
+		{name: "empty name", body: `{"name": ""}`, managerID: testManagerID, want: http.StatusBadRequest, wantField: "name"},
+		{name: "empty image", body: `{"employeeImageUri": ""}`, managerID: testManagerID, want: http.StatusBadRequest, wantField: "employeeImageUri"},
+		{name: "invalid gender", body: `{"gender": "other"}`, managerID: testManagerID, want: http.StatusBadRequest, wantField: "gender"},
+		{name: "not found", body: `{"name": "Budi Santoso"}`, managerID: testManagerID, updateErr: helper.ErrNotFound, want: http.StatusNotFound},
+		{name: "identity number conflict", body: `{"identityNumber": "EMP-00002"}`, managerID: testManagerID, updateErr: helper.ErrConflictIdentityNumber, want: http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated *dto.EmployeeUpdatePayload
+			service := &mocks.EmployeeService{
+				UpdateFunc: func(ctx context.Context, identityNumber string, input dto.EmployeeUpdatePayload, managerId string) (dto.EmployeeResponse, error) {
+					updated = &input
+					if identityNumber != "EMP-00001" || managerId != testManagerID {
+						t.Errorf("Update(%q, %q)", identityNumber, managerId)
+					}
+					return dto.EmployeeResponse{}, tt.updateErr
+				},
+			}
+			h := newTestHandler(service, config.ListResponseEnvelope)
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request = httptest.NewRequest(http.MethodPatch, "/v1/employee/EMP-00001", strings.NewReader(tt.body))
+			ctx.Params = gin.Params{{Key: "identityNumber", Value: "EMP-00001"}}
+			if tt.managerID != "" {
+				ctx.Set(helper.ContextKeyUserID, tt.managerID)
+			}
+			h.Update(ctx)
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, tt.want, w.Body)
+			}
+			wantUpdate := tt.want == http.StatusOK || tt.updateErr != nil
+			if (updated != nil) != wantUpdate {
+				t.Fatalf("service.Update called = %t, want %t", updated != nil, wantUpdate)
+			}
+			if updated != nil && !slices.Equal(updated.Nulls, tt.wantNulls) {
+				t.Fatalf("Nulls = %v, want %v", updated.Nulls, tt.wantNulls)
+			}
+			if tt.wantField != "" {
+				if fields := decodeResponse(t, w).Errors.Fields; len(fields) != 1 || fields[tt.wantField] == "" {
+					t.Fatalf("field errors = %v, want only %s", fields, tt.wantField)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package employeeHandler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/middleware"
+	"github.com/levensspel/go-gin-template/validation"
+)
+
+const defaultHistoryLimit = 20
+
+// Employee history
+// @Tags employee
+// @Summary Get the change history of an employee
+// @Description List the versions of an employee, newest first. Every create, update (including archive, restore and imports) and delete stores a full copy of the employee as the next version, together with who changed it and when. The history follows the employee across identityNumber changes. Employees created before history was recorded may have no versions.
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param identityNumber path string true "Identity number"
+// @Param limit query int false "Limit (default 20, max 100)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} helper.Response{data=[]dto.EmployeeVersion} "OK"
+// @Failure 400 {object} helper.Response{errors=helper.ErrorResponse} "Bad Request"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 404 {object} helper.Response{errors=helper.ErrorResponse} "Not Found"
+// @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
+// @Router /v1/employee/{identityNumber}/history [GET]
+func (h *handler) History(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+	log := h.logger.With(logger.RequestFields(ctx, helper.EmployeeHandlerHistory)).WithContext(ctx.Request.Context())
+
+	managerID, err := middleware.GetIdUserFromContext(ctx)
+	if err != nil {
+		log.Warn(err.Error(), logger.Bound)
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, helper.ErrUnauthorized))
+		return
+	}
+
+	input := dto.GetEmployeeHistoryRequest{
+		IdentityNumber: ctx.Param("identityNumber"),
+		ManagerID:      managerID,
+		Limit:          defaultHistoryLimit,
+	}
+	fieldErrors := validation.FieldErrors{}
+	for name, target := range map[string]*int{"limit": &input.Limit, "offset": &input.Offset} {
+		if values, ok := ctx.Request.URL.Query()[name]; ok {
+			value, err := strconv.Atoi(values[0])
+			if err != nil {
+				fieldErrors[name] = name + " must be a number"
+			}
+			*target = value
+		}
+	}
+	if len(fieldErrors) > 0 {
+		ctx.JSON(helper.FromError(fieldErrors))
+		return
+	}
+	if err := validation.ValidateEmployeeHistoryGet(&input); err != nil {
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+
+	versions, err := h.service.History(ctx, input)
+	if err != nil {
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, helper.OK(versions))
+}
+
+// Restore an employee version
+// @Tags employee
+// @Summary Restore a previous version of an employee
+// @Description Apply the fields of a version from GET /v1/employee/{identityNumber}/history as an update, which stores a new version. The status is not restored; use archive and restore. The version must still pass the PATCH rules: a taken identityNumber returns 409 and a department the manager no longer owns returns 400.
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param identityNumber path string true "Identity number"
+// @Param version path int true "Version"
+// @Success 200 {object} helper.Response{data=dto.EmployeeResponse} "OK"
+// @Failure 400 {object} helper.Response{errors=helper.ErrorResponse} "Bad Request"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 404 {object} helper.Response{errors=helper.ErrorResponse} "Not Found"
+// @Failure 409 {object} helper.Response{errors=helper.ErrorResponse} "Conflict"
+// @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
+// @Router /v1/employee/{identityNumber}/history/{version}/restore [POST]
+func (h *handler) RestoreVersion(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+	log := h.logger.With(logger.RequestFields(ctx, helper.EmployeeHandlerRestoreVersion)).WithContext(ctx.Request.Context())
+
+	managerID, err := middleware.GetIdUserFromContext(ctx)
+	if err != nil {
+		log.Warn(err.Error(), logger.Bound)
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
+		return
+	}
+
+	version, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil || version < 1 {
+		ctx.JSON(helper.FromError(validation.FieldErrors{"version": "version must be a positive number"}))
+		return
+	}
+
+	employee, err := h.service.RestoreVersion(ctx, ctx.Param("identityNumber"), version, managerID)
+	if err != nil {
+		log.Error(err.Error(), logger.Bound)
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.OK(employee))
+}
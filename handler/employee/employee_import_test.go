@@ -0,0 +1,161 @@
+package employeeHandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/mocks"
+)
+
+// importCall adalah argumen terakhir Import atau ImportJSONL.
+type importCall struct {
+	format string
+	body   string
+	dryRun bool
+}
+
+// importService mencatat pemanggilan import dan mengembalikan report dan
+// err.
+func importService(call *importCall, report dto.EmployeeImportReport, err error) *mocks.EmployeeService {
+	return &mocks.EmployeeService{
+		ImportFunc: func(ctx context.Context, file io.Reader, managerId string) (dto.EmployeeImportReport, error) {
+			body, _ := io.ReadAll(file)
+			*call = importCall{format: importFormatCSV, body: string(body)}
+			return report, err
+		},
+		ImportJSONLFunc: func(ctx context.Context, file io.Reader, managerId string, dryRun bool) (dto.EmployeeImportReport, error) {
+			body, _ := io.ReadAll(file)
+			*call = importCall{format: importFormatJSONL, body: string(body), dryRun: dryRun}
+			return report, err
+		},
+	}
+}
+
+// serveImport mengirim file sebagai part "file" ke POST /v1/employee/import.
+func serveImport(t *testing.T, service *mocks.EmployeeService, query, file, managerID string) *httptest.ResponseRecorder {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	// Part lain sebelum file dilewati
+	if err := form.WriteField("note", "nightly sync"); err != nil {
+		t.Fatal(err)
+	}
+	part, err := form.CreateFormFile("file", "employees.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = part.Write([]byte(file))
+	_ = form.Close()
+
+	h := NewEmployeeHandler(service, nil, disabledFlags{}, mocks.Logger{}, &config.ImportConfig{
+		MaxUploadBytes: 1 << 20,
+		Deadline:       time.Minute,
+	}, &config.ExportConfig{}, &config.ResponseConfig{}, 0)
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/employee/import"+query, &body)
+	ctx.Request.Header.Set("Content-Type", form.FormDataContentType())
+	if managerID != "" {
+		ctx.Set(helper.ContextKeyUserID, managerID)
+	}
+	h.Import(ctx)
+	return w
+}
+
+func decodeImportReport(t *testing.T, w *httptest.ResponseRecorder) dto.EmployeeImportReport {
+	t.Helper()
+	var response struct {
+		Data dto.EmployeeImportReport `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode body %s: %v", w.Body, err)
+	}
+	return response.Data
+}
+
+func TestImportFormats(t *testing.T) {
+	const file = `{"identityNumber":"EMP-1"}` + "\n"
+	for _, tt := range []struct {
+		query string
+		want  importCall
+	}{
+		{query: "", want: importCall{format: importFormatCSV, body: file}},
+		{query: "?format=csv", want: importCall{format: importFormatCSV, body: file}},
+		{query: "?format=jsonl", want: importCall{format: importFormatJSONL, body: file}},
+		{query: "?format=jsonl&dryRun=true", want: importCall{format: importFormatJSONL, body: file, dryRun: true}},
+		{query: "?format=jsonl&dryRun=0", want: importCall{format: importFormatJSONL, body: file}},
+	} {
+		t.Run(tt.query, func(t *testing.T) {
+			var call importCall
+			report := dto.EmployeeImportReport{ProcessedRows: 1, CreatedRows: 1, ImportedRows: 1, DryRun: tt.want.dryRun}
+			w := serveImport(t, importService(&call, report, nil), tt.query, file, testManagerID)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body)
+			}
+			if call != tt.want {
+				t.Fatalf("service call = %+v, want %+v", call, tt.want)
+			}
+			if got := decodeImportReport(t, w); got.CreatedRows != 1 || got.DryRun != tt.want.dryRun {
+				t.Fatalf("report = %+v", got)
+			}
+		})
+	}
+}
+
+func TestImportRejects(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		query     string
+		managerID string
+		want      int
+		wantField string
+	}{
+		{name: "without user", query: "?format=jsonl", want: http.StatusUnauthorized},
+		{name: "unknown format", query: "?format=xml", managerID: testManagerID, want: http.StatusBadRequest, wantField: "format"},
+		// Import CSV tidak mencocokkan baris dengan employee yang sudah ada
+		{name: "dry run with csv", query: "?dryRun=true", managerID: testManagerID, want: http.StatusBadRequest, wantField: "dryRun"},
+		{name: "dry run typo", query: "?format=jsonl&dryRun=yes", managerID: testManagerID, want: http.StatusBadRequest, wantField: "dryRun"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var call importCall
+			w := serveImport(t, importService(&call, dto.EmployeeImportReport{}, nil), tt.query, "{}\n", tt.managerID)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.want, w.Body)
+			}
+			if call != (importCall{}) {
+				t.Fatalf("service was called with %+v", call)
+			}
+			if tt.wantField != "" {
+				if fields := decodeResponse(t, w).Errors.Fields; fields[tt.wantField] == "" {
+					t.Fatalf("field errors = %v, want %s", fields, tt.wantField)
+				}
+			}
+		})
+	}
+}
+
+func TestImportErrorKeepsReport(t *testing.T) {
+	var call importCall
+	report := dto.EmployeeImportReport{ProcessedRows: 3, CreatedRows: 2, ImportedRows: 2, Aborted: true, AbortReason: helper.GetErrorMessage(helper.ErrImportRowLimit)}
+	w := serveImport(t, importService(&call, report, helper.ErrImportRowLimit), "?format=jsonl", "{}\n", testManagerID)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusRequestEntityTooLarge)
+	}
+	// Batch yang sudah tersimpan tetap dilaporkan
+	if got := decodeImportReport(t, w); got.ImportedRows != 2 || !got.Aborted {
+		t.Fatalf("report = %+v, want the rows imported before the limit", got)
+	}
+}
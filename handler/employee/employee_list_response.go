@@ -0,0 +1,97 @@
+package employeeHandler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/middleware"
+	service "github.com/levensspel/go-gin-template/service/employee"
+)
+
+// employeeListResponse menulis satu halaman hasil GET /v1/employee sesuai
+// config.ResponseConfig.EmployeeList. Error dikembalikan sebelum apa pun
+// ditulis, sehingga handler masih bisa mengirim response error. missing
+// nil berarti includeMissing tidak diminta.
+type employeeListResponse interface {
+	write(ctx *gin.Context, input *dto.GetEmployeesRequest, employees []dto.EmployeeResponse, missing []string) error
+}
+
+// newEmployeeListResponse membuat response sesuai shape. Kedua bentuk
+// mengirim header Link (RFC 8288) dengan URL absolut, yang scheme dan
+// host-nya dibaca dengan middleware.ResolveBaseURL.
+func newEmployeeListResponse(shape string, service service.EmployeeService, trustedProxyDepth int) employeeListResponse {
+	links := pageLinker{service: service, trustedProxyDepth: trustedProxyDepth}
+	if shape == config.ListResponseArray {
+		return &arrayListResponse{links: links}
+	}
+	return envelopeListResponse{links: links}
+}
+
+// envelopeListResponse adalah response bawaan, daftar dibungkus
+// helper.Response.
+type envelopeListResponse struct {
+	links pageLinker
+}
+
+func (r envelopeListResponse) write(ctx *gin.Context, input *dto.GetEmployeesRequest, employees []dto.EmployeeResponse, missing []string) error {
+	if _, err := r.links.set(ctx, input); err != nil {
+		return err
+	}
+
+	response := helper.OK(employees)
+	if missing != nil {
+		response.WithMeta(dto.EmployeeListMeta{Missing: missing})
+	}
+	ctx.JSON(http.StatusOK, response)
+	return nil
+}
+
+// arrayListResponse mengirim array JSON di top level. Jumlah total dikirim
+// lewat header X-Total-Count di samping header Link. identityNumbers yang
+// tidak ditemukan dikirim dipisah koma di header
+// X-Missing-Identity-Numbers.
+type arrayListResponse struct {
+	links pageLinker
+}
+
+func (r *arrayListResponse) write(ctx *gin.Context, input *dto.GetEmployeesRequest, employees []dto.EmployeeResponse, missing []string) error {
+	total, err := r.links.set(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	header := ctx.Writer.Header()
+	header.Set("X-Total-Count", strconv.FormatInt(total, 10))
+	if missing != nil {
+		header.Set("X-Missing-Identity-Numbers", strings.Join(missing, ","))
+	}
+	// Halaman kosong tetap berupa [] alih-alih null
+	if employees == nil {
+		employees = []dto.EmployeeResponse{}
+	}
+	ctx.JSON(http.StatusOK, employees)
+	return nil
+}
+
+// pageLinker menghitung total employee dengan EmployeeService.Count, lalu
+// memasang header Link halaman lain dari request ini. Total halaman pertama
+// tanpa filter diambil dari cache yang sama dengan halamannya.
+type pageLinker struct {
+	service           service.EmployeeService
+	trustedProxyDepth int
+}
+
+func (l pageLinker) set(ctx *gin.Context, input *dto.GetEmployeesRequest) (int64, error) {
+	total, err := l.service.Count(ctx, *input)
+	if err != nil {
+		return 0, err
+	}
+	base := middleware.ResolveBaseURL(ctx.Request, l.trustedProxyDepth)
+	ctx.Writer.Header().Set("Link", helper.PageLinks(base, ctx.Request.URL, input.Limit, input.Offset, total))
+	return total, nil
+}
@@ -0,0 +1,161 @@
+package employeeHandler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/dto"
+)
+
+// contractEmployees adalah dua employee dengan semua field terisi, kecuali
+// hiredAt employee kedua.
+func contractEmployees() []dto.EmployeeResponse {
+	hiredAt := "2024-03-01"
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	return []dto.EmployeeResponse{
+		{
+			EmployeePayload: dto.EmployeePayload{
+				IdentityNumber:   "EMP-00001",
+				Name:             "Budi Santoso",
+				EmployeeImageUri: "https://example.com/budi.png",
+				Gender:           "male",
+				DepartmentID:     testDepartmentID,
+				HiredAt:          &hiredAt,
+			},
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+			Status:    dto.EmployeeStatusActive,
+		},
+		{
+			EmployeePayload: dto.EmployeePayload{
+				IdentityNumber:   "EMP-00002",
+				Name:             "Sari Dewi",
+				EmployeeImageUri: "https://example.com/sari.png",
+				Gender:           "female",
+				DepartmentID:     testDepartmentID,
+			},
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt.Add(time.Hour),
+			Status:    dto.EmployeeStatusActive,
+		},
+	}
+}
+
+const (
+	contractEmployee1 = `{"identityNumber":"EMP-00001","name":"Budi Santoso","employeeImageUri":"https://example.com/budi.png","gender":"male","departmentId":"` + testDepartmentID + `","hiredAt":"2024-03-01","createdAt":"2026-01-02T03:04:05Z","updatedAt":"2026-01-02T03:04:05Z","status":"active"}`
+	contractEmployee2 = `{"identityNumber":"EMP-00002","name":"Sari Dewi","employeeImageUri":"https://example.com/sari.png","gender":"female","departmentId":"` + testDepartmentID + `","hiredAt":null,"createdAt":"2026-01-02T03:04:05Z","updatedAt":"2026-01-02T04:04:05Z","status":"active"}`
+)
+
+// TestGetAllContract membandingkan body dan header kedua bentuk response
+// byte per byte.
+func TestGetAllContract(t *testing.T) {
+	const secondPageLink = `<http://api.example.com/v1/employee?gender=male&limit=2&offset=0>; rel="first", ` +
+		`<http://api.example.com/v1/employee?gender=male&limit=2&offset=0>; rel="prev", ` +
+		`<http://api.example.com/v1/employee?gender=male&limit=2&offset=4>; rel="next", ` +
+		`<http://api.example.com/v1/employee?gender=male&limit=2&offset=4>; rel="last"`
+	tests := []struct {
+		name      string
+		shape     string
+		target    string
+		employees []dto.EmployeeResponse
+		missing   []string
+		total     int64
+		wantBody  string
+		// wantHeaders berisi header yang harus sama persis; "" berarti
+		// header tidak boleh dikirim
+		wantHeaders map[string]string
+	}{
+		{
+			name:      "envelope",
+			shape:     config.ListResponseEnvelope,
+			target:    "/v1/employee?limit=2&offset=2&gender=male",
+			employees: contractEmployees(),
+			total:     5,
+			wantBody:  `{"data":[` + contractEmployee1 + `,` + contractEmployee2 + `]}`,
+			wantHeaders: map[string]string{
+				"Link":          secondPageLink,
+				"X-Total-Count": "",
+			},
+		},
+		{
+			name:      "array",
+			shape:     config.ListResponseArray,
+			target:    "/v1/employee?limit=2&offset=2&gender=male",
+			employees: contractEmployees(),
+			total:     5,
+			wantBody:  `[` + contractEmployee1 + `,` + contractEmployee2 + `]`,
+			wantHeaders: map[string]string{
+				"Link":                       secondPageLink,
+				"X-Total-Count":              "5",
+				"X-Missing-Identity-Numbers": "",
+			},
+		},
+		{
+			name:   "envelope empty page",
+			shape:  config.ListResponseEnvelope,
+			target: "/v1/employee",
+			// EmployeeService.GetAll tidak pernah mengembalikan nil
+			employees: []dto.EmployeeResponse{},
+			wantBody:  `{"data":[]}`,
+			wantHeaders: map[string]string{
+				"Link": `<http://api.example.com/v1/employee?limit=5&offset=0>; rel="first", <http://api.example.com/v1/employee?limit=5&offset=0>; rel="last"`,
+			},
+		},
+		{
+			name:   "array empty page",
+			shape:  config.ListResponseArray,
+			target: "/v1/employee",
+			// Halaman nil tetap dikirim sebagai []
+			wantBody: `[]`,
+			wantHeaders: map[string]string{
+				"Link":          `<http://api.example.com/v1/employee?limit=5&offset=0>; rel="first", <http://api.example.com/v1/employee?limit=5&offset=0>; rel="last"`,
+				"X-Total-Count": "0",
+			},
+		},
+		{
+			name:      "envelope with missing",
+			shape:     config.ListResponseEnvelope,
+			target:    "/v1/employee?identityNumbers=EMP-00001,EMP-09999&includeMissing=true",
+			employees: contractEmployees()[:1],
+			missing:   []string{"EMP-09999"},
+			total:     1,
+			wantBody:  `{"data":[` + contractEmployee1 + `],"meta":{"missing":["EMP-09999"]}}`,
+		},
+		{
+			name:      "array with missing",
+			shape:     config.ListResponseArray,
+			target:    "/v1/employee?identityNumbers=EMP-00001,EMP-09999&includeMissing=true",
+			employees: contractEmployees()[:1],
+			missing:   []string{"EMP-09999"},
+			total:     1,
+			wantBody:  `[` + contractEmployee1 + `]`,
+			wantHeaders: map[string]string{
+				"X-Total-Count":              "1",
+				"X-Missing-Identity-Numbers": "EMP-09999",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := listService(tt.employees, tt.total, nil, nil)
+			service.MissingIdentityNumbersFunc = func(ctx context.Context, input dto.GetEmployeesRequest) ([]string, error) {
+				return tt.missing, nil
+			}
+			w := serve(newTestHandler(service, tt.shape).GetAll, http.MethodGet, tt.target, "", testManagerID)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+			}
+			if got := w.Body.String(); got != tt.wantBody {
+				t.Fatalf("body =\n%s\nwant\n%s", got, tt.wantBody)
+			}
+			for name, want := range tt.wantHeaders {
+				if got := w.Header().Get(name); got != want {
+					t.Fatalf("%s =\n%s\nwant\n%s", name, got, want)
+				}
+			}
+		})
+	}
+}
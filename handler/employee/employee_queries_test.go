@@ -0,0 +1,111 @@
+//go:build integration
+
+package employeeHandler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/cache"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/mocks"
+	departmentRepository "github.com/levensspel/go-gin-template/repository/department"
+	employeeRepository "github.com/levensspel/go-gin-template/repository/employee"
+	fileRepository "github.com/levensspel/go-gin-template/repository/file"
+	departmentService "github.com/levensspel/go-gin-template/service/department"
+	service "github.com/levensspel/go-gin-template/service/employee"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// TestGetAllExpandQueryCount memastikan GET /v1/employee dengan
+// expand=images dan expand=department menjalankan jumlah query yang sama
+// berapa pun ukuran halamannya: daftar employee, file atau department untuk
+// semua employee sekaligus, dan count untuk header Link.
+func TestGetAllExpandQueryCount(t *testing.T) {
+	pool := dbtest.New(t)
+	timeouts := config.LoadQueryTimeoutConfig()
+	employees := employeeRepository.NewEmployeeRepository(pool, pool, timeouts, &config.SearchConfig{})
+	files := fileRepository.New(pool, timeouts)
+	appMetrics := metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{})
+	firstPage := cache.NewEmployeeFirstPage(nil, 0, mocks.Logger{}, appMetrics)
+	employeeService := service.NewEmployeeService(
+		pool, &employees, &files, nil, mocks.Logger{}, appMetrics, noop.NewTracerProvider().Tracer(""),
+		&config.ImportConfig{}, &config.FileConfig{}, firstPage,
+	)
+	departmentRepo := departmentRepository.New(pool, pool, timeouts)
+	departments := departmentService.New(&departmentRepo, nil, nil, mocks.Logger{}, appMetrics, nil, nil, firstPage)
+	h := NewEmployeeHandler(employeeService, departments, disabledFlags{}, mocks.Logger{}, &config.ImportConfig{}, &config.ExportConfig{}, &config.ResponseConfig{EmployeeList: config.ListResponseEnvelope}, 0)
+
+	manager := dbtest.CreateManager(t, pool, "queries@example.com")
+	departmentNames := map[string]string{}
+	for d := range 2 {
+		name := fmt.Sprintf("Department %d", d)
+		department := dbtest.CreateDepartment(t, pool, manager, name)
+		departmentNames[department] = name
+		for i := range 30 {
+			dbtest.CreateEmployee(t, pool, dbtest.Employee{
+				IdentityNumber: fmt.Sprintf("EMP-%d%04d", d, i),
+				Name:           fmt.Sprintf("Employee %d", i),
+				Gender:         "male",
+				DepartmentID:   department,
+			})
+		}
+	}
+
+	const maxQueries = 3
+	for _, expand := range []string{dto.ExpandImages, dto.ExpandDepartment} {
+		t.Run(expand, func(t *testing.T) {
+			var counts []int
+			for _, limit := range []int{1, 5, 50} {
+				var body struct {
+					Data []dto.EmployeeResponse `json:"data"`
+				}
+				count := dbtest.MaxQueries(t, maxQueries, func(ctx context.Context) {
+					w := httptest.NewRecorder()
+					c, engine := gin.CreateTestContext(w)
+					// Seperti server.go, agar service membaca counter dari request
+					engine.ContextWithFallback = true
+					target := fmt.Sprintf("/v1/employee?expand=%s&limit=%d", expand, limit)
+					c.Request = httptest.NewRequest(http.MethodGet, target, nil).WithContext(ctx)
+					c.Request.Host = "api.example.com"
+					c.Set(helper.ContextKeyUserID, manager)
+					h.GetAll(c)
+					if w.Code != http.StatusOK {
+						t.Fatalf("limit %d: status = %d, body = %s", limit, w.Code, w.Body)
+					}
+					if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+						t.Fatalf("limit %d: decode body: %v", limit, err)
+					}
+				})
+				counts = append(counts, count)
+
+				if len(body.Data) != limit {
+					t.Fatalf("limit %d: %d employees", limit, len(body.Data))
+				}
+				for _, employee := range body.Data {
+					if expand == dto.ExpandDepartment && (employee.Department == nil || employee.Department.DepartmentName != departmentNames[employee.DepartmentID]) {
+						t.Fatalf("limit %d: %s department = %+v, want %q", limit, employee.IdentityNumber, employee.Department, departmentNames[employee.DepartmentID])
+					}
+					if expand == dto.ExpandImages && employee.EmployeeImage == nil {
+						t.Fatalf("limit %d: %s has no employeeImage", limit, employee.IdentityNumber)
+					}
+				}
+			}
+			for _, count := range counts {
+				if count != counts[0] {
+					t.Fatalf("queries per page size 1, 5, 50 = %v, want the same count", counts)
+				}
+			}
+		})
+	}
+}
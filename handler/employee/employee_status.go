@@ -0,0 +1,98 @@
+package employeeHandler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/middleware"
+)
+
+// Archive an employee
+// @Tags employee
+// @Summary Archive an employee
+// @Description Mark an employee as archived, e.g. during long leave. Archived employees are left out of GET /v1/employee and headcounts unless status=archived or status=all, keep their identityNumber and can still be updated. Archiving an archived employee changes nothing.
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param identityNumber path string true "Identity number"
+// @Success 200 {object} helper.Response{data=dto.EmployeeResponse} "OK"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 404 {object} helper.Response{errors=helper.ErrorResponse} "Not Found"
+// @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
+// @Router /v1/employee/{identityNumber}/archive [POST]
+func (h *handler) Archive(ctx *gin.Context) {
+	h.setStatus(ctx, helper.EmployeeHandlerArchive, h.service.Archive)
+}
+
+// Restore an archived employee
+// @Tags employee
+// @Summary Restore an archived employee
+// @Description Mark an archived employee as active again. Restoring an active employee changes nothing.
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param identityNumber path string true "Identity number"
+// @Success 200 {object} helper.Response{data=dto.EmployeeResponse} "OK"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 404 {object} helper.Response{errors=helper.ErrorResponse} "Not Found"
+// @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
+// @Router /v1/employee/{identityNumber}/restore [POST]
+func (h *handler) Restore(ctx *gin.Context) {
+	h.setStatus(ctx, helper.EmployeeHandlerRestore, h.service.Restore)
+}
+
+func (h *handler) setStatus(
+	ctx *gin.Context,
+	caller helper.FunctionCaller,
+	set func(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error),
+) {
+	defer helper.FallbackResponse(ctx)
+	log := h.logger.With(logger.RequestFields(ctx, caller)).WithContext(ctx.Request.Context())
+
+	managerID, err := middleware.GetIdUserFromContext(ctx)
+	if err != nil {
+		log.Warn(err.Error(), logger.Bound)
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
+		return
+	}
+
+	employee, err := set(ctx, ctx.Param("identityNumber"), managerID)
+	if err != nil {
+		log.Error(err.Error(), logger.Bound)
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helper.OK(employee))
+}
+
+// Employee stats
+// @Tags employee
+// @Summary Count active and archived employees
+// @Description Count the manager's active and archived employees, in total and per department. Departments without employees are listed with zero counts.
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Success 200 {object} helper.Response{data=dto.EmployeeStats} "OK"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
+// @Router /v1/employee/stats [GET]
+func (h *handler) Stats(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+	log := h.logger.With(logger.RequestFields(ctx, helper.EmployeeHandlerStats)).WithContext(ctx.Request.Context())
+
+	managerID, err := middleware.GetIdUserFromContext(ctx)
+	if err != nil {
+		log.Warn(err.Error(), logger.Bound)
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, helper.ErrUnauthorized))
+		return
+	}
+
+	stats, err := h.service.Stats(ctx, managerID)
+	if err != nil {
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, helper.OK(stats))
+}
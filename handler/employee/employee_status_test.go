@@ -0,0 +1,133 @@
+package employeeHandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/mocks"
+)
+
+// serveStatus menjalankan request lewat route archive, restore dan stats
+// agar parameter path terisi.
+func serveStatus(service *mocks.EmployeeService, method, target, managerID string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	h := newTestHandler(service, config.ListResponseEnvelope)
+	router := gin.New()
+	router.Use(func(ctx *gin.Context) {
+		if managerID != "" {
+			ctx.Set(helper.ContextKeyUserID, managerID)
+		}
+	})
+	router.GET("/v1/employee/stats", h.Stats)
+	router.POST("/v1/employee/:identityNumber/archive", h.Archive)
+	router.POST("/v1/employee/:identityNumber/restore", h.Restore)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+	return w
+}
+
+func TestArchiveAndRestore(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		target    string
+		managerID string
+		err       error
+		want      int
+		// wantStatus adalah status employee di response 200
+		wantStatus string
+	}{
+		{name: "archive", target: "/v1/employee/EMP-00001/archive", managerID: testManagerID, want: http.StatusOK, wantStatus: dto.EmployeeStatusArchived},
+		{name: "restore", target: "/v1/employee/EMP-00001/restore", managerID: testManagerID, want: http.StatusOK, wantStatus: dto.EmployeeStatusActive},
+		{name: "archive without manager", target: "/v1/employee/EMP-00001/archive", want: http.StatusUnauthorized},
+		{name: "restore without manager", target: "/v1/employee/EMP-00001/restore", want: http.StatusUnauthorized},
+		{name: "archive not found", target: "/v1/employee/EMP-00001/archive", managerID: testManagerID, err: helper.ErrNotFound, want: http.StatusNotFound},
+		{name: "restore not found", target: "/v1/employee/EMP-00001/restore", managerID: testManagerID, err: helper.ErrNotFound, want: http.StatusNotFound},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			set := func(status string) func(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error) {
+				return func(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error) {
+					calls = append(calls, status)
+					if identityNumber != "EMP-00001" || managerId != testManagerID {
+						t.Errorf("set %s (%q, %q)", status, identityNumber, managerId)
+					}
+					employee := dto.EmployeeResponse{EmployeePayload: dto.EmployeePayload{IdentityNumber: identityNumber}, Status: status}
+					return employee, tt.err
+				}
+			}
+			service := &mocks.EmployeeService{
+				ArchiveFunc: set(dto.EmployeeStatusArchived),
+				RestoreFunc: set(dto.EmployeeStatusActive),
+			}
+			w := serveStatus(service, http.MethodPost, tt.target, tt.managerID)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, tt.want, w.Body)
+			}
+			if tt.managerID == "" {
+				if len(calls) != 0 {
+					t.Fatalf("service was called: %v", calls)
+				}
+				return
+			}
+			if len(calls) != 1 {
+				t.Fatalf("calls = %v, want one", calls)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			var employee dto.EmployeeResponse
+			response := decodeResponse(t, w)
+			data, _ := json.Marshal(response.Data)
+			if err := json.Unmarshal(data, &employee); err != nil {
+				t.Fatal(err)
+			}
+			if employee.Status != tt.wantStatus || calls[0] != tt.wantStatus {
+				t.Fatalf("employee = %+v, calls = %v, want status %s", employee, calls, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestStats(t *testing.T) {
+	stats := dto.EmployeeStats{
+		Active:   3,
+		Archived: 1,
+		Departments: []dto.DepartmentEmployeeStats{
+			{DepartmentID: testDepartmentID, Active: 3, Archived: 1},
+		},
+	}
+	var called int
+	service := &mocks.EmployeeService{
+		StatsFunc: func(ctx context.Context, managerId string) (dto.EmployeeStats, error) {
+			called++
+			return stats, nil
+		},
+	}
+	w := serveStatus(service, http.MethodGet, "/v1/employee/stats", testManagerID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+	want := `{"data":{"active":3,"archived":1,"departments":[{"departmentId":"` + testDepartmentID + `","active":3,"archived":1}]}}`
+	if got := w.Body.String(); got != want {
+		t.Fatalf("body =\n%s\nwant\n%s", got, want)
+	}
+
+	if w := serveStatus(service, http.MethodGet, "/v1/employee/stats", ""); w.Code != http.StatusUnauthorized || called != 1 {
+		t.Fatalf("status without manager = %d, service calls = %d", w.Code, called)
+	}
+
+	service.StatsFunc = func(ctx context.Context, managerId string) (dto.EmployeeStats, error) {
+		return dto.EmployeeStats{}, helper.ErrInternalServer
+	}
+	if w := serveStatus(service, http.MethodGet, "/v1/employee/stats", testManagerID); w.Code != http.StatusInternalServerError {
+		t.Fatalf("status on service error = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,37 @@
+package employeeHandler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/levensspel/go-gin-template/mocks"
+)
+
+// TestCreateValidationFields memastikan nama field di response 400 sama
+// dengan nama field JSON yang dikirim client.
+func TestCreateValidationFields(t *testing.T) {
+	h := newTestHandler(&mocks.EmployeeService{}, "")
+	body := `{"identityNumber": "EMP 1", "name": "Bu", "employeeImageUri": "budi.png", "gender": "other", "departmentId": "engineering"}`
+
+	w := serve(h.Create, http.MethodPost, "/v1/employee", body, testManagerID)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+	response := decodeResponse(t, w)
+	want := map[string]string{
+		"identityNumber":   "identityNumber may only contain letters, digits and dashes",
+		"name":             "name must be at least 4 characters in length",
+		"employeeImageUri": "employeeImageUri must be a valid URL",
+		"gender":           "gender must be one of [male female]",
+		"departmentId":     "departmentId must be a valid UUID",
+	}
+	fields := response.Errors.Fields
+	if len(fields) != len(want) {
+		t.Fatalf("fields = %v, want %v", fields, want)
+	}
+	for field, message := range want {
+		if fields[field] != message {
+			t.Errorf("%s = %q, want %q", field, fields[field], message)
+		}
+	}
+}
@@ -1,92 +1,477 @@
 package fileHandler
 
 import (
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
 	"net/http"
-	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/domain"
 	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
 	"github.com/levensspel/go-gin-template/helper"
 	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/middleware"
 	fileService "github.com/levensspel/go-gin-template/service/file"
+	"github.com/levensspel/go-gin-template/validation"
+	"github.com/samber/do/v2"
+)
+
+// multipartOverhead adalah kelonggaran ukuran body untuk boundary dan header
+// part di luar isi file.
+const multipartOverhead = 64 << 10
+
+// defaultFileLimit adalah limit GET /v1/file jika tidak diisi.
+const defaultFileLimit = 20
+
+const (
+	// cacheControl untuk file dan thumbnail: isinya tidak pernah berubah
+	// untuk fileId yang sama
+	cacheControl = "public, max-age=86400"
+	// resizedCacheControl untuk hasil resize, yang URL-nya sudah memuat
+	// ukuran sehingga bisa di-cache selamanya oleh browser dan CDN
+	resizedCacheControl = "public, max-age=31536000, immutable"
 )
 
 type FileHandler interface {
 	Upload(ctx *gin.Context)
+	Presign(ctx *gin.Context)
+	Complete(ctx *gin.Context)
+	GetAll(ctx *gin.Context)
+	Get(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+	Serve(ctx *gin.Context)
+	ServeThumbnail(ctx *gin.Context)
+	ServeResized(ctx *gin.Context)
 }
 
 type handler struct {
 	service fileService.FileService
+	config  *config.FileConfig
 	logger  logger.Logger
 }
 
-func NewHandler(service fileService.FileService, logger logger.Logger) FileHandler {
-	return &handler{service: service, logger: logger}
+func NewHandler(service fileService.FileService, config *config.FileConfig, logger logger.Logger) FileHandler {
+	return &handler{service: service, config: config, logger: logger}
+}
+
+func NewHandlerInject(i do.Injector) (FileHandler, error) {
+	_service := do.MustInvoke[fileService.FileService](i)
+	_logger := do.MustInvoke[logger.LogHandler](i)
+	return NewHandler(_service, config.LoadFileConfig(), &_logger), nil
 }
 
 // Upload godoc
 // @Tags file
-// @Summary Upload an file
-// @Description Upload an file
+// @Summary Upload a file
+// @Description Upload a jpeg or png image, for example for employeeImageUri. The type is detected from the file content; a file name extension or part Content-Type that names another type is rejected. The maximum size is FILE_MAX_UPLOAD_BYTES (default 2MiB) and the maximum image size is FILE_MAX_IMAGE_DIMENSION px per side (default 10000) and FILE_MAX_IMAGE_PIXELS in total (default 40000000).
+// @Accept multipart/form-data
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param file formData file true "jpeg or png image"
+// @Success 201 {object} helper.Response{data=dto.FileUploadRespondPayload} "File uploaded"
+// @Failure 400 {object} helper.Response{errors=helper.ErrorResponse} "Missing file, not a jpeg/png image (ErrInvalidFileType), name or Content-Type does not match the content (ErrFileTypeMismatch), unreadable image header (ErrInvalidImage) or image dimensions too large (ErrImageTooLarge)"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized - Missing or invalid token"
+// @Failure 413 {object} helper.Response{errors=helper.ErrorResponse} "File too large"
+// @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
+// @Router /v1/file [POST]
+func (h *handler) Upload(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+
+	managerID, err := middleware.GetIdUserFromContext(ctx)
+	if err != nil {
+		h.logger.Warn(err.Error(), helper.FileHandlerUpload)
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
+		return
+	}
+
+	part, err := FilePart(ctx, h.config.MaxUploadBytes)
+	if err != nil {
+		h.logger.Warn(err.Error(), helper.FileHandlerUpload)
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+	defer part.Close()
+
+	// Part dikirim langsung ke storage; ukurannya dibatasi service selama
+	// dibaca
+	response, err := h.service.Upload(ctx, managerID, entity.FilePurposeEmployee, part.FileName(), part.Header.Get("Content-Type"), part)
+	if err != nil {
+		h.logger.Warn(err.Error(), helper.FileHandlerUpload, part.FileName())
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+	ctx.JSON(http.StatusCreated, helper.Created(response))
+}
+
+// Presign godoc
+// @Tags file
+// @Summary Request a presigned upload URL
+// @Description Get a URL to upload a jpeg or png image straight to storage, then confirm it with POST /v1/file/{id}/complete before expiresAt (FILE_PRESIGN_EXPIRY, default 15m). Send the returned headers unchanged with the PUT. When the storage driver cannot presign, presigned is false and the file must be sent to uploadUrl as in POST /v1/file.
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer JWT token"
-// @Param File formData file true "Body with file zip"
-// @Success 200 {object} helper.Response{data=dto.FileUploadRespondPayload} "File uploaded successfully"
-// @Success 201 {object} helper.Response{data=dto.FileUploadRespondPayload} "File created successfully"
+// @Param data body dto.FilePresignRequest true "data"
+// @Success 201 {object} helper.Response{data=dto.FilePresignResponse} "Upload URL created"
+// @Success 200 {object} helper.Response{data=dto.FilePresignResponse} "Presigning not supported, use POST /v1/file"
 // @Failure 400 {object} helper.Response{errors=helper.ErrorResponse} "Bad Request"
-// @Failure 415 {object} helper.Response{errors=helper.ErrorResponse} "Unsupported Media Type"
-// @Failure 413 {object} helper.Response{errors=helper.ErrorResponse} "Payload Too Large"
 // @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized - Missing or invalid token"
-// @Router /v1/file [POST]
-func (h handler) Upload(ctx *gin.Context) {
-	// Verify JWT token (pseudo-code, adjust as per your implementation)
-	token := ctx.GetHeader("Authorization")
-	if token == "" {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization token is required"})
+// @Failure 413 {object} helper.Response{errors=helper.ErrorResponse} "File too large"
+// @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
+// @Router /v1/file/presign [POST]
+func (h *handler) Presign(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+
+	managerID, err := middleware.GetIdUserFromContext(ctx)
+	if err != nil {
+		h.logger.Warn(err.Error(), helper.FileHandlerPresign)
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
+		return
+	}
+
+	var input dto.FilePresignRequest
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		h.logger.Warn(err.Error(), helper.FileHandlerPresign)
+		ctx.JSON(http.StatusBadRequest, helper.Error(http.StatusBadRequest, err))
+		return
+	}
+	if err := validation.ValidateFilePresign(&input); err != nil {
+		h.logger.Warn(err.Error(), helper.FileHandlerPresign, input.ContentType)
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+
+	response, err := h.service.Presign(ctx, managerID, input)
+	if err != nil {
+		h.logger.Warn(err.Error(), helper.FileHandlerPresign, input.ContentType)
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+	if !response.Presigned {
+		ctx.JSON(http.StatusOK, helper.OK(response))
+		return
+	}
+	ctx.JSON(http.StatusCreated, helper.Created(response))
+}
+
+// Complete godoc
+// @Tags file
+// @Summary Confirm a presigned upload
+// @Description Check the object uploaded to a presigned URL and record it as a file. The content is checked as in POST /v1/file, with the presigned contentType and fileName as the claimed type; an object that is rejected or does not have the requested size is deleted and must be uploaded again with a new URL.
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param id path string true "fileId from POST /v1/file/presign"
+// @Success 201 {object} helper.Response{data=dto.FileUploadRespondPayload} "File uploaded"
+// @Failure 400 {object} helper.Response{errors=helper.ErrorResponse} "Rejected as in POST /v1/file"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized - Missing or invalid token"
+// @Failure 404 {object} helper.Response{errors=helper.ErrorResponse} "Not Found"
+// @Failure 409 {object} helper.Response{errors=helper.ErrorResponse} "Object has not been uploaded yet"
+// @Failure 410 {object} helper.Response{errors=helper.ErrorResponse} "Upload URL expired"
+// @Failure 413 {object} helper.Response{errors=helper.ErrorResponse} "Uploaded size does not match"
+// @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
+// @Router /v1/file/{id}/complete [POST]
+func (h *handler) Complete(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+
+	managerID, err := middleware.GetIdUserFromContext(ctx)
+	if err != nil {
+		h.logger.Warn(err.Error(), helper.FileHandlerComplete)
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
+		return
+	}
+
+	response, err := h.service.Complete(ctx, managerID, ctx.Param("id"))
+	if err != nil {
+		h.logger.Warn(err.Error(), helper.FileHandlerComplete, ctx.Param("id"))
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+	ctx.JSON(http.StatusCreated, helper.Created(response))
+}
+
+// GetAll godoc
+// @Tags file
+// @Summary List uploaded files
+// @Description List the files uploaded by the manager, newest first. referenceCount is the number of employees and manager profiles using the file; files that stay unused are deleted after CLEANUP_FILE_GRACE_PERIOD.
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param limit query int false "Limit (max 100)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} helper.Response{data=[]dto.FileResponse} "OK"
+// @Failure 400 {object} helper.Response{errors=helper.ErrorResponse} "Bad Request"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized - Missing or invalid token"
+// @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
+// @Router /v1/file [GET]
+func (h *handler) GetAll(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+
+	managerID, err := middleware.GetIdUserFromContext(ctx)
+	if err != nil {
+		h.logger.Warn(err.Error(), helper.FileHandlerGetAll)
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
 		return
 	}
 
-	file, header, err := ctx.Request.FormFile("File")
+	input := dto.GetFilesRequest{Limit: defaultFileLimit}
+	if limit, err := strconv.Atoi(ctx.Query("limit")); err == nil {
+		input.Limit = limit
+	}
+	if offset, err := strconv.Atoi(ctx.Query("offset")); err == nil {
+		input.Offset = offset
+	}
+	if err := validation.ValidateFilesGet(&input); err != nil {
+		h.logger.Warn(err.Error(), helper.FileHandlerGetAll, input)
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+
+	files, err := h.service.GetAll(ctx, managerID, input)
+	if err != nil {
+		h.logger.Warn(err.Error(), helper.FileHandlerGetAll, input)
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, helper.OK(files))
+}
+
+// Get godoc
+// @Tags file
+// @Summary Get file metadata
+// @Description Get an uploaded file with the employees (identityNumber, at most 100) and profile fields of the manager that use it. referenceCount also counts employees of other managers.
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param id path string true "fileId"
+// @Success 200 {object} helper.Response{data=dto.FileResponse} "OK"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized - Missing or invalid token"
+// @Failure 404 {object} helper.Response{errors=helper.ErrorResponse} "Not Found or uploaded by another manager"
+// @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
+// @Router /v1/file/{id} [GET]
+func (h *handler) Get(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+
+	managerID, err := middleware.GetIdUserFromContext(ctx)
+	if err != nil {
+		h.logger.Warn(err.Error(), helper.FileHandlerGet)
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
+		return
+	}
+
+	file, err := h.service.Get(ctx, managerID, ctx.Param("id"))
+	if err != nil {
+		h.logger.Warn(err.Error(), helper.FileHandlerGet, ctx.Param("id"))
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, helper.OK(file))
+}
+
+// Delete godoc
+// @Tags file
+// @Summary Delete a file
+// @Description Delete an uploaded file together with its thumbnail and resized images. A file still used as an employeeImageUri, userImageUri or companyImageUri is not deleted.
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param id path string true "fileId"
+// @Success 200 {object} helper.Response "OK"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized - Missing or invalid token"
+// @Failure 404 {object} helper.Response{errors=helper.ErrorResponse} "Not Found or uploaded by another manager"
+// @Failure 409 {object} helper.Response{errors=helper.ErrorResponse} "File is still used (ErrFileReferenced)"
+// @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
+// @Router /v1/file/{id} [DELETE]
+func (h *handler) Delete(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+
+	managerID, err := middleware.GetIdUserFromContext(ctx)
 	if err != nil {
-		h.logger.Warn(err.Error(), helper.FunctionCaller("FileHandler.Upload"), header)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to handle file."})
+		h.logger.Warn(err.Error(), helper.FileHandlerDelete)
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
+		return
+	}
+
+	if err := h.service.Delete(ctx, managerID, ctx.Param("id")); err != nil {
+		h.logger.Warn(err.Error(), helper.FileHandlerDelete, ctx.Param("id"))
+		ctx.JSON(helper.FromError(err))
 		return
 	}
+	ctx.JSON(http.StatusOK, helper.OK(nil))
+}
+
+// Serve godoc
+// @Tags file
+// @Summary Download a file
+// @Description Stream an uploaded file. No token is needed so the URL can be used directly in an img tag. Supports ETag/If-None-Match and, when the storage driver allows seeking, Range requests.
+// @Produce image/jpeg,image/png
+// @Param id path string true "fileId"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Param Range header string false "Byte range, e.g. bytes=0-1023"
+// @Success 200 {file} file "File content"
+// @Success 206 {file} file "Partial content"
+// @Success 304 "Not modified"
+// @Failure 404 {object} helper.Response{errors=helper.ErrorResponse} "Not Found"
+// @Failure 416 "Range not satisfiable"
+// @Failure 502 {object} helper.Response{errors=helper.ErrorResponse} "Storage Error"
+// @Failure 503 {object} helper.Response{errors=helper.ErrorResponse} "Storage Unavailable"
+// @Router /files/{id} [GET]
+func (h *handler) Serve(ctx *gin.Context) {
+	h.serve(ctx, h.service.Open, cacheControl, helper.FileHandlerServe)
+}
+
+// ServeThumbnail godoc
+// @Tags file
+// @Summary Download a file thumbnail
+// @Description Stream the thumbnail of an uploaded image (longest side THUMBNAIL_SIZE, default 128px). Thumbnails are generated in the background after upload, so this returns 404 until GET /v1/employee?expand=images reports thumbnailStatus ready. Caching and Range behave as in GET /files/{id}.
+// @Produce image/jpeg,image/png
+// @Param id path string true "fileId"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Param Range header string false "Byte range, e.g. bytes=0-1023"
+// @Success 200 {file} file "Thumbnail content"
+// @Success 206 {file} file "Partial content"
+// @Success 304 "Not modified"
+// @Failure 404 {object} helper.Response{errors=helper.ErrorResponse} "Not Found or thumbnail not ready"
+// @Failure 416 "Range not satisfiable"
+// @Failure 502 {object} helper.Response{errors=helper.ErrorResponse} "Storage Error"
+// @Failure 503 {object} helper.Response{errors=helper.ErrorResponse} "Storage Unavailable"
+// @Router /files/{id}/thumbnail [GET]
+func (h *handler) ServeThumbnail(ctx *gin.Context) {
+	h.serve(ctx, h.service.OpenThumbnail, cacheControl, helper.FileHandlerServeThumbnail)
+}
+
+// ServeResized godoc
+// @Tags file
+// @Summary Download a resized image
+// @Description Stream an uploaded image scaled down to fit inside w x h, keeping its aspect ratio; smaller images are not enlarged. w x h must be one of IMAGE_RESIZE_SIZES (default 64x64, 128x128, 256x256, 512x512). The first request for a size generates it, later requests are served from storage. Caching and Range behave as in GET /files/{id}, but the response may be cached for a year.
+// @Produce image/jpeg,image/png
+// @Param id path string true "fileId"
+// @Param w query int true "Maximum width in px"
+// @Param h query int true "Maximum height in px"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Param Range header string false "Byte range, e.g. bytes=0-1023"
+// @Success 200 {file} file "Resized image"
+// @Success 206 {file} file "Partial content"
+// @Success 304 "Not modified"
+// @Failure 400 {object} helper.Response{errors=helper.ErrorResponse} "Size not allowed or file is not a readable image"
+// @Failure 404 {object} helper.Response{errors=helper.ErrorResponse} "Not Found"
+// @Failure 416 "Range not satisfiable"
+// @Failure 502 {object} helper.Response{errors=helper.ErrorResponse} "Storage Error"
+// @Failure 503 {object} helper.Response{errors=helper.ErrorResponse} "Resize queue full, resize timed out, or storage unavailable"
+// @Router /files/{id}/resize [GET]
+func (h *handler) ServeResized(ctx *gin.Context) {
+	width, errWidth := strconv.Atoi(ctx.Query("w"))
+	height, errHeight := strconv.Atoi(ctx.Query("h"))
+	if errWidth != nil || errHeight != nil {
+		h.logger.Warn(helper.ErrInvalidImageSize.Error(), helper.FileHandlerServeResized, ctx.Param("id"))
+		ctx.JSON(helper.FromError(helper.ErrInvalidImageSize))
+		return
+	}
+	open := func(c context.Context, fileID string) (entity.File, *domain.Object, error) {
+		return h.service.OpenResized(c, fileID, width, height)
+	}
+	h.serve(ctx, open, resizedCacheControl, helper.FileHandlerServeResized)
+}
+
+func (h *handler) serve(
+	ctx *gin.Context,
+	open func(ctx context.Context, fileID string) (entity.File, *domain.Object, error),
+	cacheControl string,
+	function helper.FunctionCaller,
+) {
+	defer helper.FallbackResponse(ctx)
 
-	// note filename
-	// fileExt := filepath.Ext(header.Filename)
-	// originalFileName := strings.TrimSuffix(filepath.Base(header.Filename), filepath.Ext(header.Filename))
-	// now := time.Now()
-	// filename := strings.ReplaceAll(strings.ToLower(originalFileName), " ", "-") + "-" + fmt.Sprintf("%v", now.Unix()) + fileExt
-	// note;end
+	file, object, err := open(ctx, ctx.Param("id"))
+	if err != nil {
+		h.logger.Warn(err.Error(), function, ctx.Param("id"))
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+	defer object.Body.Close()
 
-	// Validasi ekstensi file
-	validExtensions := map[string]bool{
-		"image/jpeg": true,
-		"image/jpg":  true,
-		"image/png":  true,
+	header := ctx.Writer.Header()
+	contentType := file.ContentType
+	if contentType == "" {
+		contentType = object.ContentType
+	}
+	header.Set("Content-Type", contentType)
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("Cache-Control", cacheControl)
+	if object.ETag != "" {
+		header.Set("ETag", object.ETag)
 	}
 
-	if !validExtensions[header.Header.Get("Content-Type")] {
-		h.logger.Warn("Invalid file type. Only jpeg, jpg, or png are allowed.", helper.FunctionCaller("FileHandler.Upload"), filepath.Ext(header.Filename))
-		ctx.JSON(http.StatusBadRequest, gin.H{"code": 415, "error": "Invalid file type. Only jpeg, jpg, or png are allowed."})
+	// ServeContent menangani Range, If-None-Match, dan If-Modified-Since
+	if body, ok := object.Body.(io.ReadSeeker); ok {
+		http.ServeContent(ctx.Writer, ctx.Request, "", object.ModTime, body)
 		return
 	}
 
-	// Validasi ukuran file (max 100 KiB)
-	if header.Size > 1024*100 { // 100 KiB
-		h.logger.Warn("File size exceeds 100KiB.", helper.FunctionCaller("FileHandler.Upload"), header.Size)
-		ctx.JSON(http.StatusBadRequest, gin.H{"code": 413, "error": "File size exceeds 100KiB."})
+	if object.ETag != "" && etagMatches(ctx.GetHeader("If-None-Match"), object.ETag) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+	if object.Size >= 0 {
+		header.Set("Content-Length", strconv.FormatInt(object.Size, 10))
+	}
+	ctx.Status(http.StatusOK)
+	if ctx.Request.Method == http.MethodHead {
 		return
 	}
+	if _, err := io.Copy(ctx.Writer, object.Body); err != nil {
+		h.logger.Warn(err.Error(), function, file.FileId)
+	}
+}
+
+// etagMatches melaporkan apakah If-None-Match berisi etag, dengan
+// perbandingan weak seperti RFC 9110.
+func etagMatches(ifNoneMatch string, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
 
-	// Simpan file atau proses lebih lanjut
-	// Misalnya, simpan file ke server atau S3
-	h.service.Upload(file, header)
+// FilePart membatasi body request untuk file paling besar maxUploadBytes lalu
+// mengembalikan part multipart bernama file, untuk setiap endpoint upload
+// seperti POST /v1/file. Body yang Content-Length-nya sudah terlalu besar
+// ditolak dengan helper.ErrPayloadTooLarge tanpa dibaca.
+func FilePart(ctx *gin.Context, maxUploadBytes int64) (*multipart.Part, error) {
+	maxBodyBytes := maxUploadBytes + multipartOverhead
+	if ctx.Request.ContentLength > maxBodyBytes {
+		return nil, helper.ErrPayloadTooLarge
+	}
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxBodyBytes)
+	return filePart(ctx.Request)
+}
 
-	// return file URI kembali dari service
-	ctx.JSON(http.StatusOK, helper.NewResponse(dto.FileUploadRespondPayload{
-		Uri: "cuy",
-	}, nil))
+// filePart mengembalikan part multipart bernama file tanpa menyimpan body ke
+// memori atau disk seperti ParseMultipartForm.
+func filePart(r *http.Request) (*multipart.Part, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, helper.ErrBadRequest
+	}
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return nil, helper.ErrPayloadTooLarge
+			}
+			return nil, helper.ErrBadRequest
+		}
+		if part.FormName() == "file" {
+			return part, nil
+		}
+		part.Close()
+	}
 }
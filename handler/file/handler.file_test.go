@@ -0,0 +1,237 @@
+package fileHandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/clock"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/domain"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/idgen"
+	"github.com/levensspel/go-gin-template/infrastructure/storage"
+	"github.com/levensspel/go-gin-template/mocks"
+	fileService "github.com/levensspel/go-gin-template/service/file"
+	"github.com/levensspel/go-gin-template/thumbnail"
+)
+
+const testManagerID = "0b7c2d2e-4f4a-4b8e-9c59-2f1d8f6a3e10"
+
+// handlerFixture memasang FileService asli di atas storage tersebut;
+// repository menyimpan file yang dibuat di files.
+type handlerFixture struct {
+	handler FileHandler
+	storage domain.Storage
+	repo    *mocks.FileRepository
+	files   map[string]entity.File
+	created *[]entity.File
+}
+
+func newHandlerFixture(t *testing.T, fileConfig *config.FileConfig, s domain.Storage) *handlerFixture {
+	t.Helper()
+	return newHandlerFixtureWithResize(t, fileConfig, s, &config.ResizeConfig{})
+}
+
+// newHandlerFixtureWithResize seperti newHandlerFixture dengan ukuran
+// GET /files/:id/resize dari resizeConfig. Tanpa worker.Pool, sehingga
+// semua resize dijalankan langsung.
+func newHandlerFixtureWithResize(t *testing.T, fileConfig *config.FileConfig, s domain.Storage, resizeConfig *config.ResizeConfig) *handlerFixture {
+	t.Helper()
+	f := &handlerFixture{storage: s, files: map[string]entity.File{}, created: &[]entity.File{}}
+	f.repo = &mocks.FileRepository{
+		CreateFunc: func(ctx context.Context, file entity.File) (entity.File, error) {
+			*f.created = append(*f.created, file)
+			f.files[file.FileId] = file
+			return file, nil
+		},
+		GetFunc: func(ctx context.Context, fileID string) (entity.File, error) {
+			file, ok := f.files[fileID]
+			if !ok {
+				return entity.File{}, helper.ErrNotFound
+			}
+			return file, nil
+		},
+	}
+	thumbnails := thumbnail.NewGenerator(context.Background(), f.repo, s, nil, &config.ThumbnailConfig{}, fileConfig, mocks.Logger{})
+	service := fileService.NewFileService(f.repo, nil, s, thumbnails, nil, idgen.NewUUIDv7(), clock.Real(), fileConfig, resizeConfig, &config.QueryTimeoutConfig{}, mocks.Logger{})
+	f.handler = NewHandler(service, fileConfig, mocks.Logger{})
+	return f
+}
+
+func newTestHandler(t *testing.T, fileConfig *config.FileConfig) (FileHandler, *[]entity.File) {
+	t.Helper()
+	f := newHandlerFixture(t, fileConfig, storage.NewMemoryStorage(""))
+	return f.handler, f.created
+}
+
+func testFileConfig() *config.FileConfig {
+	return &config.FileConfig{MaxUploadBytes: 2 << 20, MaxImageDimension: 10_000, MaxImagePixels: 40_000_000}
+}
+
+func pngImage(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// multipartBody membuat body multipart dengan satu part bernama field.
+func multipartBody(t *testing.T, field, fileName string, content []byte) (*bytes.Buffer, string) {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile(field, fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := part.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &body, writer.FormDataContentType()
+}
+
+// upload menjalankan Upload untuk satu request. managerID kosong berarti
+// request tanpa user dari middleware Authorization.
+func upload(h FileHandler, body *bytes.Buffer, contentType, managerID string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/file", body)
+	ctx.Request.Header.Set("Content-Type", contentType)
+	if managerID != "" {
+		ctx.Set(helper.ContextKeyUserID, managerID)
+	}
+	h.Upload(ctx)
+	return w
+}
+
+func TestUploadCreated(t *testing.T) {
+	h, created := newTestHandler(t, testFileConfig())
+	body, contentType := multipartBody(t, "file", "avatar.png", pngImage(t, 8, 8))
+
+	w := upload(h, body, contentType, testManagerID)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, body = %s, want 201", w.Code, w.Body)
+	}
+	var response struct {
+		Data dto.FileUploadRespondPayload `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode body %s: %v", w.Body, err)
+	}
+	if len(*created) != 1 {
+		t.Fatalf("Create called %d times, want 1", len(*created))
+	}
+	file := (*created)[0]
+	if response.Data.FileId != file.FileId || response.Data.Uri != file.FileURI || response.Data.Uri == "" {
+		t.Fatalf("response = %+v, want the recorded file %+v", response.Data, file)
+	}
+	if file.ManagerId != testManagerID {
+		t.Fatalf("recorded owner = %q, want %q", file.ManagerId, testManagerID)
+	}
+}
+
+func TestUploadStatus(t *testing.T) {
+	content := pngImage(t, 8, 8)
+	for _, tt := range []struct {
+		name      string
+		maxBytes  int64
+		field     string
+		content   []byte
+		managerID string
+		want      int
+	}{
+		{name: "unauthorized", field: "file", content: content, want: http.StatusUnauthorized},
+		{name: "wrong type", field: "file", content: []byte("GIF89a not really"), managerID: testManagerID, want: http.StatusBadRequest},
+		{name: "missing file part", field: "avatar", content: content, managerID: testManagerID, want: http.StatusBadRequest},
+		{name: "too large", maxBytes: int64(len(content)) - 1, field: "file", content: content, managerID: testManagerID, want: http.StatusRequestEntityTooLarge},
+		{
+			// Content-Length sudah melebihi batas body, ditolak tanpa dibaca
+			name:      "body too large",
+			maxBytes:  1 << 10,
+			field:     "file",
+			content:   bytes.Repeat(content, (multipartOverhead/len(content))+64),
+			managerID: testManagerID,
+			want:      http.StatusRequestEntityTooLarge,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fileConfig := testFileConfig()
+			if tt.maxBytes > 0 {
+				fileConfig.MaxUploadBytes = tt.maxBytes
+			}
+			h, created := newTestHandler(t, fileConfig)
+			body, contentType := multipartBody(t, tt.field, "avatar.png", tt.content)
+
+			w := upload(h, body, contentType, tt.managerID)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, body = %s, want %d", w.Code, w.Body, tt.want)
+			}
+			if len(*created) != 0 {
+				t.Fatalf("rejected upload was recorded: %+v", *created)
+			}
+		})
+	}
+}
+
+func TestUploadNotMultipart(t *testing.T) {
+	h, _ := newTestHandler(t, testFileConfig())
+	w := upload(h, bytes.NewBufferString(`{"file":"x"}`), "application/json", testManagerID)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, body = %s, want 400", w.Code, w.Body)
+	}
+}
+
+// TestUploadRejectionMessages memastikan setiap alasan penolakan isi file
+// dikembalikan sebagai 400 dengan pesannya sendiri.
+func TestUploadRejectionMessages(t *testing.T) {
+	content := pngImage(t, 8, 8)
+	for _, tt := range []struct {
+		name     string
+		fileName string
+		content  []byte
+		want     error
+	}{
+		{name: "html named png", fileName: "avatar.png", content: []byte("<html><body>hi</body></html>"), want: helper.ErrInvalidFileType},
+		{name: "png named jpg", fileName: "avatar.jpg", content: content, want: helper.ErrFileTypeMismatch},
+		{name: "truncated png", fileName: "avatar.png", content: content[:12], want: helper.ErrInvalidImage},
+		{name: "too large", fileName: "avatar.png", content: pngImage(t, 65, 1), want: helper.ErrImageTooLarge},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fileConfig := testFileConfig()
+			fileConfig.MaxImageDimension = 64
+			h, created := newTestHandler(t, fileConfig)
+			body, contentType := multipartBody(t, "file", tt.fileName, tt.content)
+
+			w := upload(h, body, contentType, testManagerID)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, body = %s, want 400", w.Code, w.Body)
+			}
+			var response helper.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			if want := helper.GetErrorMessage(tt.want); response.Errors.Message != want {
+				t.Fatalf("message = %q, want %q", response.Errors.Message, want)
+			}
+			if len(*created) != 0 {
+				t.Fatalf("rejected upload was recorded: %+v", *created)
+			}
+		})
+	}
+}
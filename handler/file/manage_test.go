@@ -0,0 +1,139 @@
+package fileHandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/infrastructure/storage"
+)
+
+const otherManagerID = "5d1f0c7a-2b3e-4c4d-8e9f-0a1b2c3d4e5f"
+
+// newManageHandlerFixture berisi file-1 milik testManagerID yang dipakai
+// sebanyak referenceCount kali.
+func newManageHandlerFixture(t *testing.T, referenceCount int) *handlerFixture {
+	t.Helper()
+	f := newHandlerFixture(t, testFileConfig(), storage.NewMemoryStorage(""))
+	f.files["file-1"] = entity.File{
+		FileId:    "file-1",
+		ManagerId: testManagerID,
+		FileURI:   "https://api.example.com/files/file-1",
+		FileKey:   "file/" + testManagerID + "/file-1.png",
+	}
+	f.repo.GetAllFunc = func(ctx context.Context, managerID string, limit int, offset int) ([]entity.File, error) {
+		return []entity.File{f.files["file-1"]}, nil
+	}
+	f.repo.FindReferencesFunc = func(ctx context.Context, fileURI string, managerID string, limit int) (entity.FileReferences, error) {
+		return entity.FileReferences{Count: referenceCount}, nil
+	}
+	f.repo.DeleteFunc = func(ctx context.Context, fileID string, managerID string) (entity.File, error) {
+		file := f.files[fileID]
+		delete(f.files, fileID)
+		return file, nil
+	}
+	return f
+}
+
+// manage menjalankan satu request ke GET/DELETE /v1/file. managerID kosong
+// berarti request tanpa user dari middleware Authorization.
+func (f *handlerFixture) manage(method, target, managerID string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(ctx *gin.Context) {
+		if managerID != "" {
+			ctx.Set(helper.ContextKeyUserID, managerID)
+		}
+	})
+	router.GET("/v1/file", f.handler.GetAll)
+	router.GET("/v1/file/:id", f.handler.Get)
+	router.DELETE("/v1/file/:id", f.handler.Delete)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+	return w
+}
+
+func TestGetFile(t *testing.T) {
+	f := newManageHandlerFixture(t, 2)
+
+	w := f.manage(http.MethodGet, "/v1/file/file-1", testManagerID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s, want 200", w.Code, w.Body)
+	}
+	var response struct {
+		Data dto.FileResponse `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Data.FileId != "file-1" || response.Data.ReferenceCount != 2 || response.Data.ReferencedBy == nil {
+		t.Fatalf("response = %s", w.Body)
+	}
+}
+
+func TestGetAllFiles(t *testing.T) {
+	f := newManageHandlerFixture(t, 0)
+
+	w := f.manage(http.MethodGet, "/v1/file?limit=5&offset=0", testManagerID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s, want 200", w.Code, w.Body)
+	}
+	var response struct {
+		Data []dto.FileResponse `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Data) != 1 || response.Data[0].FileId != "file-1" {
+		t.Fatalf("response = %s", w.Body)
+	}
+}
+
+func TestManageFilesStatus(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		method         string
+		target         string
+		managerID      string
+		referenceCount int
+		want           int
+		wantErr        error
+	}{
+		{name: "delete", method: http.MethodDelete, target: "/v1/file/file-1", managerID: testManagerID, want: http.StatusOK},
+		{name: "delete referenced", method: http.MethodDelete, target: "/v1/file/file-1", managerID: testManagerID, referenceCount: 1, want: http.StatusConflict, wantErr: helper.ErrFileReferenced},
+		{name: "delete other manager", method: http.MethodDelete, target: "/v1/file/file-1", managerID: otherManagerID, want: http.StatusNotFound, wantErr: helper.ErrNotFound},
+		{name: "get other manager", method: http.MethodGet, target: "/v1/file/file-1", managerID: otherManagerID, want: http.StatusNotFound, wantErr: helper.ErrNotFound},
+		{name: "get unknown file", method: http.MethodGet, target: "/v1/file/missing", managerID: testManagerID, want: http.StatusNotFound, wantErr: helper.ErrNotFound},
+		{name: "list limit too large", method: http.MethodGet, target: "/v1/file?limit=101", managerID: testManagerID, want: http.StatusBadRequest},
+		{name: "list without user", method: http.MethodGet, target: "/v1/file", want: http.StatusUnauthorized},
+		{name: "delete without user", method: http.MethodDelete, target: "/v1/file/file-1", want: http.StatusUnauthorized},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newManageHandlerFixture(t, tt.referenceCount)
+
+			w := f.manage(tt.method, tt.target, tt.managerID)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, body = %s, want %d", w.Code, w.Body, tt.want)
+			}
+			if tt.wantErr != nil {
+				var response helper.Response
+				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+					t.Fatal(err)
+				}
+				if response.Errors == nil || response.Errors.Message != helper.GetErrorMessage(tt.wantErr) {
+					t.Fatalf("response = %s, want %q", w.Body, helper.GetErrorMessage(tt.wantErr))
+				}
+			}
+			// File hanya hilang setelah DELETE yang berhasil
+			if _, ok := f.files["file-1"]; ok != (tt.method != http.MethodDelete || tt.want != http.StatusOK) {
+				t.Fatalf("file-1 exists = %v after %s", ok, tt.name)
+			}
+		})
+	}
+}
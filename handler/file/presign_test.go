@@ -0,0 +1,85 @@
+package fileHandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/domain"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/infrastructure/storage"
+)
+
+// signingStorage adalah MemoryStorage yang bisa membuat URL upload
+// presign, seperti S3Storage.
+type signingStorage struct {
+	*storage.MemoryStorage
+}
+
+func (s signingStorage) SignedPutURL(ctx context.Context, key string, contentType string, size int64, expiry time.Duration) (*domain.PresignedRequest, error) {
+	return &domain.PresignedRequest{Method: http.MethodPut, URL: "https://storage.example.com/" + key, Headers: map[string]string{"Content-Type": contentType}}, nil
+}
+
+func presign(h FileHandler, body, managerID string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/file/presign", strings.NewReader(body))
+	if managerID != "" {
+		ctx.Set(helper.ContextKeyUserID, managerID)
+	}
+	h.Presign(ctx)
+	return w
+}
+
+func TestPresignStatus(t *testing.T) {
+	fileConfig := testFileConfig()
+	fileConfig.PresignExpiry = 15 * time.Minute
+	for _, tt := range []struct {
+		name          string
+		storage       domain.Storage
+		body          string
+		managerID     string
+		want          int
+		wantPresigned bool
+	}{
+		{name: "signed", storage: signingStorage{storage.NewMemoryStorage("")}, body: `{"contentType":"image/png","size":1024}`, managerID: testManagerID, want: http.StatusCreated, wantPresigned: true},
+		{name: "driver cannot presign", storage: storage.NewMemoryStorage(""), body: `{"contentType":"image/png","size":1024}`, managerID: testManagerID, want: http.StatusOK},
+		{name: "unauthorized", storage: signingStorage{storage.NewMemoryStorage("")}, body: `{"contentType":"image/png","size":1024}`, want: http.StatusUnauthorized},
+		{name: "malformed body", storage: signingStorage{storage.NewMemoryStorage("")}, body: `{"contentType":`, managerID: testManagerID, want: http.StatusBadRequest},
+		{name: "gif", storage: signingStorage{storage.NewMemoryStorage("")}, body: `{"contentType":"image/gif","size":1024}`, managerID: testManagerID, want: http.StatusBadRequest},
+		{name: "missing size", storage: signingStorage{storage.NewMemoryStorage("")}, body: `{"contentType":"image/png"}`, managerID: testManagerID, want: http.StatusBadRequest},
+		{name: "too large", storage: signingStorage{storage.NewMemoryStorage("")}, body: `{"contentType":"image/png","size":2097153}`, managerID: testManagerID, want: http.StatusRequestEntityTooLarge},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newHandlerFixture(t, fileConfig, tt.storage)
+			f.repo.CreateUploadFunc = func(ctx context.Context, upload entity.FileUpload, expiry time.Duration) error {
+				return nil
+			}
+
+			w := presign(f.handler, tt.body, tt.managerID)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, body = %s, want %d", w.Code, w.Body, tt.want)
+			}
+			if w.Code >= http.StatusBadRequest {
+				return
+			}
+			var response struct {
+				Data dto.FilePresignResponse `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("decode body %s: %v", w.Body, err)
+			}
+			if response.Data.Presigned != tt.wantPresigned || response.Data.UploadUrl == "" {
+				t.Fatalf("response = %+v, want presigned %t", response.Data, tt.wantPresigned)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package fileHandler
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/jpeg"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/domain"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/infrastructure/storage"
+)
+
+// originalReads menghitung Get ke object asli, yaitu berapa kali gambar
+// di-decode untuk resize.
+type originalReads struct {
+	domain.Storage
+	key   string
+	reads int
+}
+
+func (s *originalReads) Get(ctx context.Context, key string) (*domain.Object, error) {
+	if key == s.key {
+		s.reads++
+	}
+	return s.Storage.Get(ctx, key)
+}
+
+func newResizeHandlerFixture(t *testing.T, s domain.Storage) *handlerFixture {
+	t.Helper()
+	return newHandlerFixtureWithResize(t, testFileConfig(), s, &config.ResizeConfig{Sizes: []string{"64x64"}, InlineMaxBytes: 1 << 20})
+}
+
+func (f *handlerFixture) serveResized(target string, header http.Header) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/files/:id/resize", f.handler.ServeResized)
+	request := httptest.NewRequest(http.MethodGet, target, nil)
+	for name, values := range header {
+		request.Header[name] = values
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, request)
+	return w
+}
+
+func TestServeResized(t *testing.T) {
+	reads := &originalReads{Storage: storage.NewMemoryStorage(""), key: "file/" + testManagerID + "/file-1.png"}
+	f := newResizeHandlerFixture(t, reads)
+	f.put(t, "file-1", pngImage(t, 200, 100))
+
+	w := f.serveResized("/files/file-1/resize?w=64&h=64", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s, want 200", w.Code, w.Body)
+	}
+	if got := w.Header().Get("Content-Type"); got != "image/png" {
+		t.Errorf("Content-Type = %q, want image/png", got)
+	}
+	if got := w.Header().Get("Cache-Control"); got != resizedCacheControl {
+		t.Errorf("Cache-Control = %q, want %q", got, resizedCacheControl)
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("response has no ETag")
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(w.Body.Bytes()))
+	if err != nil || config.Width != 64 || config.Height != 32 {
+		t.Fatalf("body = %dx%d, %v, want a 64x32 image", config.Width, config.Height, err)
+	}
+
+	// Request berikutnya dilayani dari variant yang tersimpan
+	second := f.serveResized("/files/file-1/resize?w=64&h=64", nil)
+	if second.Code != http.StatusOK || !bytes.Equal(second.Body.Bytes(), w.Body.Bytes()) || second.Header().Get("ETag") != etag {
+		t.Fatalf("second status = %d, ETag %q, want the same variant", second.Code, second.Header().Get("ETag"))
+	}
+	notModified := f.serveResized("/files/file-1/resize?w=64&h=64", http.Header{"If-None-Match": {etag}})
+	if notModified.Code != http.StatusNotModified {
+		t.Fatalf("If-None-Match status = %d, want 304", notModified.Code)
+	}
+	if reads.reads != 1 {
+		t.Fatalf("original read %d times, want 1", reads.reads)
+	}
+}
+
+func TestServeResizedJPEG(t *testing.T) {
+	f := newResizeHandlerFixture(t, storage.NewMemoryStorage(""))
+	var content bytes.Buffer
+	if err := jpeg.Encode(&content, image.NewGray(image.Rect(0, 0, 100, 200)), nil); err != nil {
+		t.Fatal(err)
+	}
+	key := "file/" + testManagerID + "/file-1.jpg"
+	if _, err := f.storage.Put(context.Background(), key, "image/jpeg", bytes.NewReader(content.Bytes()), int64(content.Len()), true); err != nil {
+		t.Fatal(err)
+	}
+	f.files["file-1"] = entity.File{FileId: "file-1", FileKey: key, Size: int64(content.Len()), ContentType: "image/jpeg"}
+
+	w := f.serveResized("/files/file-1/resize?w=64&h=64", nil)
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "image/jpeg" {
+		t.Fatalf("status = %d, Content-Type = %q, want 200 image/jpeg", w.Code, w.Header().Get("Content-Type"))
+	}
+	config, format, err := image.DecodeConfig(bytes.NewReader(w.Body.Bytes()))
+	if err != nil || format != "jpeg" || config.Width != 32 || config.Height != 64 {
+		t.Fatalf("body = %s %dx%d, %v, want jpeg 32x64", format, config.Width, config.Height, err)
+	}
+}
+
+func TestServeResizedStatus(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		target string
+		want   int
+	}{
+		{name: "size not allowed", target: "/files/file-1/resize?w=1000&h=1000", want: http.StatusBadRequest},
+		{name: "missing height", target: "/files/file-1/resize?w=64", want: http.StatusBadRequest},
+		{name: "not a number", target: "/files/file-1/resize?w=64px&h=64", want: http.StatusBadRequest},
+		{name: "unknown file", target: "/files/missing/resize?w=64&h=64", want: http.StatusNotFound},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			memory := storage.NewMemoryStorage("")
+			f := newResizeHandlerFixture(t, memory)
+			f.put(t, "file-1", pngImage(t, 200, 100))
+
+			if w := f.serveResized(tt.target, nil); w.Code != tt.want {
+				t.Fatalf("status = %d, body = %s, want %d", w.Code, w.Body, tt.want)
+			}
+			// Ukuran yang ditolak tidak membuat variant baru di storage
+			if _, err := memory.Stat(context.Background(), "file/"+testManagerID+"/file-1_1000x1000.png"); err == nil {
+				t.Fatal("rejected size was stored")
+			}
+		})
+	}
+}
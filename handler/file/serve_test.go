@@ -0,0 +1,176 @@
+package fileHandler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/domain"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/infrastructure/storage"
+)
+
+// streamingStorage menyembunyikan Seek dari body object, seperti body S3.
+type streamingStorage struct {
+	domain.Storage
+}
+
+func (s streamingStorage) Get(ctx context.Context, key string) (*domain.Object, error) {
+	object, err := s.Storage.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	object.Body = struct {
+		io.Reader
+		io.Closer
+	}{object.Body, object.Body}
+	return object, nil
+}
+
+// put menyimpan content sebagai file fileID lewat storage fixture.
+func (f *handlerFixture) put(t *testing.T, fileID string, content []byte) {
+	t.Helper()
+	key := "file/" + testManagerID + "/" + fileID + ".png"
+	if _, err := f.storage.Put(context.Background(), key, "image/png", bytes.NewReader(content), int64(len(content)), true); err != nil {
+		t.Fatal(err)
+	}
+	f.files[fileID] = entity.File{FileId: fileID, ManagerId: testManagerID, FileKey: key, Size: int64(len(content)), ContentType: "image/png"}
+}
+
+func (f *handlerFixture) serve(method, target string, header http.Header) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/files/:id", f.handler.Serve)
+	router.HEAD("/files/:id", f.handler.Serve)
+	request := httptest.NewRequest(method, target, nil)
+	for name, values := range header {
+		request.Header[name] = values
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, request)
+	return w
+}
+
+// drivers adalah storage yang dipakai GET /files/:id: body yang bisa
+// di-seek (memory, local) dan body stream seperti S3.
+func drivers(t *testing.T) map[string]domain.Storage {
+	local, err := storage.NewLocalStorage(t.TempDir(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return map[string]domain.Storage{
+		"memory":    storage.NewMemoryStorage(""),
+		"local":     local,
+		"streaming": streamingStorage{storage.NewMemoryStorage("")},
+	}
+}
+
+func TestServe(t *testing.T) {
+	content := pngImage(t, 8, 8)
+	for name, s := range drivers(t) {
+		t.Run(name, func(t *testing.T) {
+			f := newHandlerFixture(t, testFileConfig(), s)
+			f.put(t, "file-1", content)
+
+			w := f.serve(http.MethodGet, "/files/file-1", nil)
+			if w.Code != http.StatusOK || !bytes.Equal(w.Body.Bytes(), content) {
+				t.Fatalf("GET = %d with %d bytes, want 200 with %d bytes", w.Code, w.Body.Len(), len(content))
+			}
+			for header, want := range map[string]string{
+				"Content-Type":           "image/png",
+				"X-Content-Type-Options": "nosniff",
+				"Cache-Control":          cacheControl,
+				"Content-Length":         fmt.Sprint(len(content)),
+			} {
+				if got := w.Header().Get(header); got != want {
+					t.Errorf("%s = %q, want %q", header, got, want)
+				}
+			}
+			etag := w.Header().Get("ETag")
+			if etag == "" {
+				t.Fatal("GET did not return an ETag")
+			}
+
+			for _, ifNoneMatch := range []string{etag, `"other", ` + etag, "W/" + etag, "*"} {
+				w = f.serve(http.MethodGet, "/files/file-1", http.Header{"If-None-Match": {ifNoneMatch}})
+				if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+					t.Errorf("GET If-None-Match %s = %d with %d bytes, want 304 without body", ifNoneMatch, w.Code, w.Body.Len())
+				}
+			}
+			w = f.serve(http.MethodGet, "/files/file-1", http.Header{"If-None-Match": {`"other"`}})
+			if w.Code != http.StatusOK {
+				t.Errorf("GET with a stale ETag = %d, want 200", w.Code)
+			}
+
+			w = f.serve(http.MethodHead, "/files/file-1", nil)
+			if w.Code != http.StatusOK || w.Body.Len() != 0 || w.Header().Get("Content-Length") != fmt.Sprint(len(content)) {
+				t.Errorf("HEAD = %d with %d bytes and Content-Length %q, want 200 without body", w.Code, w.Body.Len(), w.Header().Get("Content-Length"))
+			}
+		})
+	}
+}
+
+func TestServeRange(t *testing.T) {
+	content := pngImage(t, 8, 8)
+	size := len(content)
+	for _, name := range []string{"memory", "local"} {
+		t.Run(name, func(t *testing.T) {
+			f := newHandlerFixture(t, testFileConfig(), drivers(t)[name])
+			f.put(t, "file-1", content)
+
+			for _, tt := range []struct {
+				rangeHeader  string
+				want         int
+				wantBody     []byte
+				contentRange string
+			}{
+				{rangeHeader: "bytes=0-3", want: http.StatusPartialContent, wantBody: content[:4], contentRange: fmt.Sprintf("bytes 0-3/%d", size)},
+				{rangeHeader: "bytes=-4", want: http.StatusPartialContent, wantBody: content[size-4:], contentRange: fmt.Sprintf("bytes %d-%d/%d", size-4, size-1, size)},
+				{rangeHeader: fmt.Sprintf("bytes=%d-", size-1), want: http.StatusPartialContent, wantBody: content[size-1:], contentRange: fmt.Sprintf("bytes %d-%d/%d", size-1, size-1, size)},
+				{rangeHeader: fmt.Sprintf("bytes=%d-", size), want: http.StatusRequestedRangeNotSatisfiable, contentRange: fmt.Sprintf("bytes */%d", size)},
+			} {
+				w := f.serve(http.MethodGet, "/files/file-1", http.Header{"Range": {tt.rangeHeader}})
+				if w.Code != tt.want {
+					t.Fatalf("Range %s = %d, want %d", tt.rangeHeader, w.Code, tt.want)
+				}
+				if got := w.Header().Get("Content-Range"); got != tt.contentRange {
+					t.Errorf("Range %s Content-Range = %q, want %q", tt.rangeHeader, got, tt.contentRange)
+				}
+				if tt.wantBody != nil && !bytes.Equal(w.Body.Bytes(), tt.wantBody) {
+					t.Errorf("Range %s body = %x, want %x", tt.rangeHeader, w.Body.Bytes(), tt.wantBody)
+				}
+			}
+		})
+	}
+}
+
+// TestServeStreamingIgnoresRange memastikan body yang tidak bisa di-seek
+// tetap dikirim utuh, bukan dipotong tanpa Content-Range.
+func TestServeStreamingIgnoresRange(t *testing.T) {
+	content := pngImage(t, 8, 8)
+	f := newHandlerFixture(t, testFileConfig(), drivers(t)["streaming"])
+	f.put(t, "file-1", content)
+
+	w := f.serve(http.MethodGet, "/files/file-1", http.Header{"Range": {"bytes=0-3"}})
+	if w.Code != http.StatusOK || !bytes.Equal(w.Body.Bytes(), content) {
+		t.Fatalf("GET Range = %d with %d bytes, want 200 with the whole file", w.Code, w.Body.Len())
+	}
+}
+
+func TestServeNotFound(t *testing.T) {
+	f := newHandlerFixture(t, testFileConfig(), storage.NewMemoryStorage(""))
+	if w := f.serve(http.MethodGet, "/files/missing", nil); w.Code != http.StatusNotFound {
+		t.Fatalf("GET unknown file = %d, want 404", w.Code)
+	}
+
+	// Baris file ada tetapi object-nya sudah hilang dari storage
+	f.files["orphan"] = entity.File{FileId: "orphan", FileKey: "file/orphan.png", ContentType: "image/png"}
+	if w := f.serve(http.MethodGet, "/files/orphan", nil); w.Code != http.StatusNotFound {
+		t.Fatalf("GET file without object = %d, want 404", w.Code)
+	}
+}
@@ -0,0 +1,69 @@
+package graphqlHandler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/graph"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/middleware"
+	"github.com/samber/do/v2"
+)
+
+type GraphQLHandler interface {
+	Query(ctx *gin.Context)
+}
+
+type graphqlHandler struct {
+	resolver *graph.Resolver
+	server   *handler.Server
+	logger   logger.Logger
+}
+
+func NewGraphQLHandler(resolver *graph.Resolver, config *config.GraphQLConfig, logger logger.Logger) GraphQLHandler {
+	return &graphqlHandler{
+		resolver: resolver,
+		server:   graph.NewServer(resolver, config, logger),
+		logger:   logger,
+	}
+}
+
+func NewGraphQLHandlerInject(i do.Injector) (GraphQLHandler, error) {
+	_resolver := do.MustInvoke[*graph.Resolver](i)
+	_logger := do.MustInvoke[logger.LogHandler](i)
+	return NewGraphQLHandler(_resolver, config.LoadGraphQLConfig(), &_logger), nil
+}
+
+// Query menjalankan query GraphQL
+// @Tags graphql
+// @Summary Run a GraphQL query
+// @Description Query-only GraphQL endpoint for the manager, departments and employees. See graph/schema.graphqls for the schema. Resolver errors carry the REST status code in errors[].extensions.code; depth and complexity limit errors are returned with status 200.
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param data body object true "GraphQL request: query, operationName, variables"
+// @Success 200 {object} object "GraphQL response"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 422 {object} object "Query cannot be parsed or is invalid"
+// @Router /v1/graphql [POST]
+func (h *graphqlHandler) Query(ctx *gin.Context) {
+	managerID, err := middleware.GetIdUserFromContext(ctx)
+	if err != nil {
+		h.logger.Warn(err.Error(), helper.GraphQLHandler)
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
+		return
+	}
+
+	// Resolver membaca id manager dan request id dari context request,
+	// bukan dari gin.Context. Loaders dibuat per request agar cache tidak
+	// terbagi antar manager.
+	requestCtx := context.WithValue(ctx.Request.Context(), helper.ContextKeyUserID, managerID)
+	requestCtx = context.WithValue(requestCtx, helper.ContextKeyRequestID, ctx.GetString(helper.ContextKeyRequestID))
+	requestCtx = graph.WithLoaders(requestCtx, h.resolver.NewLoaders(managerID))
+
+	h.server.ServeHTTP(ctx.Writer, ctx.Request.WithContext(requestCtx))
+}
@@ -0,0 +1,352 @@
+package graphqlHandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/graph"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/mocks"
+	departmentService "github.com/levensspel/go-gin-template/service/department"
+)
+
+const testManagerID = "0b7c2d2e-4f4a-4b8e-9c59-2f1d8f6a3e10"
+
+// fakeDepartments menyimpan department di departments dan mencatat setiap
+// panggilan batch; method lain panic karena interface-nya nil.
+type fakeDepartments struct {
+	departmentService.DepartmentService
+	departments []dto.ResponseSingleDepartment
+	counts      map[string]int
+
+	mu         sync.Mutex
+	getByIDs   [][]string
+	countCalls [][]string
+}
+
+func (f *fakeDepartments) GetAll(ctx context.Context, managerID string, input dto.RequestDepartment) ([]dto.ResponseSingleDepartment, error) {
+	return f.departments, nil
+}
+
+func (f *fakeDepartments) GetByIDs(ctx context.Context, managerID string, ids []string) ([]dto.ResponseSingleDepartment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.getByIDs = append(f.getByIDs, ids)
+	var results []dto.ResponseSingleDepartment
+	for _, department := range f.departments {
+		if slices.Contains(ids, department.DepartmentID) {
+			results = append(results, department)
+		}
+	}
+	return results, nil
+}
+
+func (f *fakeDepartments) CountEmployees(ctx context.Context, managerID string, ids []string) (map[string]int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.countCalls = append(f.countCalls, ids)
+	return f.counts, nil
+}
+
+// graphqlFixture berisi dua department dan 50 employee yang dibagi rata ke
+// keduanya.
+type graphqlFixture struct {
+	departments *fakeDepartments
+	employees   *mocks.EmployeeService
+	config      *config.GraphQLConfig
+
+	mu          sync.Mutex
+	pageBatches [][]string
+}
+
+func newGraphQLFixture() *graphqlFixture {
+	f := &graphqlFixture{
+		departments: &fakeDepartments{
+			departments: []dto.ResponseSingleDepartment{
+				{DepartmentID: "department-1", DepartmentName: "Engineering"},
+				{DepartmentID: "department-2", DepartmentName: "Finance"},
+			},
+			counts: map[string]int{"department-1": 25, "department-2": 25},
+		},
+		config: &config.GraphQLConfig{MaxDepth: 6, MaxComplexity: 1000, Introspection: true},
+	}
+	var employees []dto.EmployeeResponse
+	for i := range 50 {
+		employees = append(employees, dto.EmployeeResponse{EmployeePayload: dto.EmployeePayload{
+			IdentityNumber: fmt.Sprintf("EMP-%05d", i),
+			Name:           "Employee",
+			Gender:         "male",
+			DepartmentID:   fmt.Sprintf("department-%d", i%2+1),
+		}})
+	}
+	f.employees = &mocks.EmployeeService{
+		GetAllFunc: func(ctx context.Context, input dto.GetEmployeesRequest) ([]dto.EmployeeResponse, error) {
+			return employees[:min(input.Limit, len(employees))], nil
+		},
+		GetAllByDepartmentsFunc: func(ctx context.Context, managerId string, departmentIds []string, limit int, offset int) ([]dto.EmployeeResponse, error) {
+			f.mu.Lock()
+			f.pageBatches = append(f.pageBatches, departmentIds)
+			f.mu.Unlock()
+			var results []dto.EmployeeResponse
+			taken := map[string]int{}
+			for _, employee := range employees {
+				id := employee.DepartmentID
+				if slices.Contains(departmentIds, id) && taken[id] < limit {
+					taken[id]++
+					results = append(results, employee)
+				}
+			}
+			return results, nil
+		},
+	}
+	return f
+}
+
+// graphqlResponse adalah response GraphQL dengan data mentah dan errors.
+type graphqlResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+// query menjalankan POST /v1/graphql. managerID kosong berarti request
+// tanpa user dari middleware Authorization.
+func (f *graphqlFixture) query(t *testing.T, query string, managerID string) (*httptest.ResponseRecorder, graphqlResponse) {
+	t.Helper()
+	users := &mocks.UserService{
+		GetProfileFunc: func(ctx context.Context, managerid string) (*dto.ResposneGetProfile, error) {
+			return &dto.ResposneGetProfile{Email: "manager@example.com", Name: "Manager"}, nil
+		},
+	}
+	resolver := graph.NewResolver(f.employees, f.departments, users)
+	h := NewGraphQLHandler(resolver, f.config, mocks.Logger{})
+
+	body, err := json.Marshal(map[string]string{"query": query})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/graphql", bytes.NewReader(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	if managerID != "" {
+		ctx.Set(helper.ContextKeyUserID, managerID)
+	}
+	h.Query(ctx)
+
+	var response graphqlResponse
+	if w.Code == http.StatusOK {
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("decode %s: %v", w.Body, err)
+		}
+	}
+	return w, response
+}
+
+// TestNestedQueryIsBatched memastikan 50 employee dengan department-nya
+// hanya membuat satu lookup department dan satu hitungan employee.
+func TestNestedQueryIsBatched(t *testing.T) {
+	f := newGraphQLFixture()
+	_, response := f.query(t, `{
+		employees(limit: 50) {
+			identityNumber
+			department { id name employeeCount }
+		}
+	}`, testManagerID)
+	if len(response.Errors) != 0 {
+		t.Fatalf("errors = %+v", response.Errors)
+	}
+	var data struct {
+		Employees []struct {
+			IdentityNumber string
+			Department     struct {
+				ID            string
+				Name          string
+				EmployeeCount int
+			}
+		}
+	}
+	if err := json.Unmarshal(response.Data, &data); err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Employees) != 50 || data.Employees[1].Department.Name != "Finance" || data.Employees[0].Department.EmployeeCount != 25 {
+		t.Fatalf("data = %s", response.Data)
+	}
+	if len(f.departments.getByIDs) != 1 || len(f.departments.getByIDs[0]) != 2 {
+		t.Fatalf("GetByIDs calls = %v, want one call with both departments", f.departments.getByIDs)
+	}
+	if len(f.departments.countCalls) != 1 {
+		t.Fatalf("CountEmployees calls = %v, want one", f.departments.countCalls)
+	}
+}
+
+func TestDepartmentEmployeesAreBatched(t *testing.T) {
+	f := newGraphQLFixture()
+	_, response := f.query(t, `{
+		me {
+			email
+			departments(limit: 2) {
+				name
+				employees(limit: 3) { totalCount nodes { identityNumber departmentId } }
+			}
+		}
+	}`, testManagerID)
+	if len(response.Errors) != 0 {
+		t.Fatalf("errors = %+v", response.Errors)
+	}
+	var data struct {
+		Me struct {
+			Email       string
+			Departments []struct {
+				Name      string
+				Employees struct {
+					TotalCount int
+					Nodes      []struct{ IdentityNumber, DepartmentID string }
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(response.Data, &data); err != nil {
+		t.Fatal(err)
+	}
+	departments := data.Me.Departments
+	if data.Me.Email != "manager@example.com" || len(departments) != 2 {
+		t.Fatalf("data = %s", response.Data)
+	}
+	for _, department := range departments {
+		if department.Employees.TotalCount != 25 || len(department.Employees.Nodes) != 3 {
+			t.Fatalf("department %s employees = %+v", department.Name, department.Employees)
+		}
+	}
+	if len(f.pageBatches) != 1 || len(f.pageBatches[0]) != 2 {
+		t.Fatalf("GetAllByDepartments calls = %v, want one call with both departments", f.pageBatches)
+	}
+	// Department dari daftar sudah di-prime, employeeCount tetap satu batch
+	if len(f.departments.getByIDs) != 0 || len(f.departments.countCalls) != 1 {
+		t.Fatalf("GetByIDs calls = %v, CountEmployees calls = %v", f.departments.getByIDs, f.departments.countCalls)
+	}
+}
+
+func TestQueryLimits(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		query    string
+		wantCode string
+	}{
+		{
+			name:     "depth",
+			query:    `{ me { departments { employees { nodes { department { name } } } } } }`,
+			wantCode: "DEPTH_LIMIT_EXCEEDED",
+		},
+		{
+			name:     "depth through fragment",
+			query:    `{ me { ...deep } } fragment deep on Manager { departments { employees { nodes { department { name } } } } }`,
+			wantCode: "DEPTH_LIMIT_EXCEEDED",
+		},
+		{
+			// 1 + 100 * (1 + 100 * 1) melewati batas 1000
+			name:     "complexity",
+			query:    `{ departments(limit: 100) { employees(limit: 100) { totalCount } } }`,
+			wantCode: "COMPLEXITY_LIMIT_EXCEEDED",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newGraphQLFixture()
+			f.config.MaxDepth = 4
+
+			_, response := f.query(t, tt.query, testManagerID)
+			if len(response.Errors) != 1 || response.Errors[0].Extensions["code"] != tt.wantCode {
+				t.Fatalf("errors = %+v, want %s", response.Errors, tt.wantCode)
+			}
+			if string(response.Data) != "" && string(response.Data) != "null" {
+				t.Fatalf("data = %s, want none", response.Data)
+			}
+			if len(f.pageBatches) != 0 || len(f.departments.countCalls) != 0 {
+				t.Fatal("rejected query reached the services")
+			}
+		})
+	}
+
+	// Field introspection tidak dihitung kedalamannya
+	f := newGraphQLFixture()
+	f.config.MaxDepth = 1
+	if _, response := f.query(t, `{ __schema { queryType { fields { name type { name } } } } }`, testManagerID); len(response.Errors) != 0 {
+		t.Fatalf("introspection errors = %+v", response.Errors)
+	}
+}
+
+func TestResolverErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		query      string
+		wantCode   float64
+		wantErr    error
+		wantFields bool
+	}{
+		{name: "negative limit", query: `{ employees(limit: -1) { name } }`, wantCode: http.StatusBadRequest, wantErr: helper.ErrBadRequest},
+		{name: "empty filter", query: `{ employees(filter: {gender: ""}) { name } }`, wantCode: http.StatusBadRequest, wantFields: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newGraphQLFixture()
+
+			_, response := f.query(t, tt.query, testManagerID)
+			if len(response.Errors) != 1 || response.Errors[0].Extensions["code"] != tt.wantCode {
+				t.Fatalf("errors = %+v, want code %v", response.Errors, tt.wantCode)
+			}
+			if tt.wantErr != nil && response.Errors[0].Message != helper.GetErrorMessage(tt.wantErr) {
+				t.Fatalf("message = %q, want %q", response.Errors[0].Message, helper.GetErrorMessage(tt.wantErr))
+			}
+			if fields, _ := response.Errors[0].Extensions["fields"].(map[string]any); tt.wantFields && fields["gender"] == nil {
+				t.Fatalf("extensions = %v, want a gender field error", response.Errors[0].Extensions)
+			}
+		})
+	}
+}
+
+// TestEmptyTextFilter memastikan name dan query kosong berarti tanpa filter,
+// berbeda dengan gender kosong yang ditolak.
+func TestEmptyTextFilter(t *testing.T) {
+	f := newGraphQLFixture()
+	var got dto.GetEmployeesRequest
+	getAll := f.employees.GetAllFunc
+	f.employees.GetAllFunc = func(ctx context.Context, input dto.GetEmployeesRequest) ([]dto.EmployeeResponse, error) {
+		got = input
+		return getAll(ctx, input)
+	}
+
+	w, response := f.query(t, `{ employees(filter: {name: "", query: ""}, limit: 2) { name } }`, testManagerID)
+	if w.Code != http.StatusOK || len(response.Errors) != 0 {
+		t.Fatalf("status = %d, errors = %+v", w.Code, response.Errors)
+	}
+	if got.Name != "" || got.Query != "" || got.Limit != 2 || !slices.Contains(got.EmptyFilters, "name") || !slices.Contains(got.EmptyFilters, "q") {
+		t.Fatalf("GetAll input = %+v, want name and q in EmptyFilters", got)
+	}
+}
+
+func TestQueryWithoutUser(t *testing.T) {
+	f := newGraphQLFixture()
+	if w, _ := f.query(t, `{ me { email } }`, ""); w.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", w.Code)
+	}
+}
+
+func TestIntrospectionDisabled(t *testing.T) {
+	f := newGraphQLFixture()
+	f.config.Introspection = false
+	if _, response := f.query(t, `{ __schema { queryType { name } } }`, testManagerID); len(response.Errors) == 0 {
+		t.Fatal("introspection query succeeded with GRAPHQL_INTROSPECTION=false")
+	}
+}
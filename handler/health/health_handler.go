@@ -0,0 +1,137 @@
+package healthHandler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/breaker"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/health"
+	"github.com/levensspel/go-gin-template/infrastructure"
+	"github.com/redis/go-redis/v9"
+	"github.com/samber/do/v2"
+)
+
+const checkTimeout = time.Second
+
+const StatusNotReady = "not_ready"
+
+type HealthHandler interface {
+	Healthz(ctx *gin.Context)
+	Livez(ctx *gin.Context)
+	Readyz(ctx *gin.Context)
+}
+
+// Pinger adalah bagian pgxpool.Pool yang dipakai health check, sehingga
+// handler bisa dites dengan pool yang gagal.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+type handler struct {
+	checks    []health.Check
+	readiness *health.Readiness
+}
+
+func NewHealthHandler(
+	db Pinger,
+	redisClient *redis.Client,
+	dbBreaker *breaker.Breaker,
+	readiness *health.Readiness,
+) HealthHandler {
+	checks := []health.Check{
+		{
+			Name:     "postgres",
+			Critical: true,
+			Run:      db.Ping,
+		},
+		{
+			// Ping di atas tidak melewati breaker, sehingga pemulihan
+			// database tetap terlihat walau breaker masih open.
+			Name:     "postgres_circuit_breaker",
+			Critical: true,
+			Run: func(context.Context) error {
+				if state := dbBreaker.State(); state == breaker.StateOpen {
+					return fmt.Errorf("circuit breaker is %s", state)
+				}
+				return nil
+			},
+		},
+	}
+	if redisClient != nil {
+		// Redis hanya cache; saat tidak bisa dijangkau service tetap
+		// melayani dari Postgres, jadi statusnya dilaporkan tanpa membuat
+		// probe 503
+		checks = append(checks, health.Check{
+			Name:     "redis",
+			Critical: false,
+			Run: func(ctx context.Context) error {
+				return redisClient.Ping(ctx).Err()
+			},
+		})
+	}
+	return &handler{checks: checks, readiness: readiness}
+}
+
+func NewHealthHandlerInject(i do.Injector) (HealthHandler, error) {
+	_db := do.MustInvoke[*database.Pool](i).Pool
+	_redis := do.MustInvoke[*infrastructure.RedisClient](i).Client
+	_breaker := do.MustInvoke[*breaker.Breaker](i)
+	_readiness := do.MustInvoke[*health.Readiness](i)
+	return NewHealthHandler(_db, _redis, _breaker, _readiness), nil
+}
+
+type healthResponse struct {
+	Status       string                   `json:"status"`
+	Dependencies map[string]health.Result `json:"dependencies,omitempty"`
+}
+
+// Health check
+// @Tags health
+// @Summary Health check with dependency status
+// @Description Ping the database (and Redis when configured) within a bounded deadline. Redis is reported but not critical: when it is down the status stays 200.
+// @Produce json
+// @Success 200 {object} healthResponse "OK"
+// @Failure 503 {object} healthResponse "Service Unavailable"
+// @Router /healthz [GET]
+func (h *handler) Healthz(ctx *gin.Context) {
+	results, healthy := health.RunChecks(ctx.Request.Context(), checkTimeout, h.checks)
+
+	status := http.StatusOK
+	response := healthResponse{Status: health.StatusUp, Dependencies: results}
+	if !healthy {
+		status = http.StatusServiceUnavailable
+		response.Status = health.StatusDown
+	}
+	ctx.JSON(status, response)
+}
+
+// Liveness probe
+// @Tags health
+// @Summary Liveness probe
+// @Description Always 200 while the process is able to serve HTTP
+// @Produce json
+// @Success 200 {object} healthResponse "OK"
+// @Router /livez [GET]
+func (h *handler) Livez(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, healthResponse{Status: health.StatusUp})
+}
+
+// Readiness probe
+// @Tags health
+// @Summary Readiness probe
+// @Description 503 during startup, while draining, or when a critical dependency (Postgres or its circuit breaker) is down. A Redis outage is reported in dependencies but keeps the instance ready.
+// @Produce json
+// @Success 200 {object} healthResponse "OK"
+// @Failure 503 {object} healthResponse "Service Unavailable"
+// @Router /readyz [GET]
+func (h *handler) Readyz(ctx *gin.Context) {
+	if !h.readiness.IsReady() {
+		ctx.JSON(http.StatusServiceUnavailable, healthResponse{Status: StatusNotReady})
+		return
+	}
+	h.Healthz(ctx)
+}
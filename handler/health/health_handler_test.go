@@ -0,0 +1,233 @@
+package healthHandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/breaker"
+	"github.com/levensspel/go-gin-template/health"
+	"github.com/redis/go-redis/v9"
+)
+
+// stubPool menggantikan pgxpool.Pool; err dikembalikan oleh setiap Ping.
+type stubPool struct {
+	err error
+}
+
+func (p stubPool) Ping(ctx context.Context) error {
+	return p.err
+}
+
+// openBreaker mengembalikan breaker yang langsung open setelah satu
+// kegagalan.
+func openBreaker(t *testing.T) *breaker.Breaker {
+	t.Helper()
+	b := breaker.New(breaker.Settings{Name: "postgres", FailureThreshold: 1, Cooldown: time.Hour})
+	done, err := b.Allow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	done(errors.New("connection refused"))
+	if b.State() != breaker.StateOpen {
+		t.Fatalf("breaker state = %s, want open", b.State())
+	}
+	return b
+}
+
+// unreachableRedis mengarah ke port yang tertutup, sehingga PING langsung
+// gagal.
+func unreachableRedis(t *testing.T) *redis.Client {
+	t.Helper()
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 100 * time.Millisecond, MaxRetries: -1})
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func probe(h HealthHandler, path string) (*httptest.ResponseRecorder, healthResponse) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/healthz", h.Healthz)
+	router.GET("/livez", h.Livez)
+	router.GET("/readyz", h.Readyz)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	var response healthResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	return w, response
+}
+
+func TestHealthz(t *testing.T) {
+	tests := []struct {
+		name       string
+		db         Pinger
+		redis      func(t *testing.T) *redis.Client
+		breaker    func(t *testing.T) *breaker.Breaker
+		wantStatus int
+		wantDeps   map[string]string
+	}{
+		{
+			name:       "healthy without redis",
+			db:         stubPool{},
+			wantStatus: http.StatusOK,
+			wantDeps:   map[string]string{"postgres": health.StatusUp, "postgres_circuit_breaker": health.StatusUp},
+		},
+		{
+			name:       "failing pool",
+			db:         stubPool{err: errors.New("failed to connect")},
+			wantStatus: http.StatusServiceUnavailable,
+			wantDeps:   map[string]string{"postgres": health.StatusDown, "postgres_circuit_breaker": health.StatusUp},
+		},
+		{
+			name:       "open breaker",
+			db:         stubPool{},
+			breaker:    openBreaker,
+			wantStatus: http.StatusServiceUnavailable,
+			wantDeps:   map[string]string{"postgres": health.StatusUp, "postgres_circuit_breaker": health.StatusDown},
+		},
+		{
+			// Redis tidak critical, kegagalannya hanya dilaporkan
+			name:       "failing redis",
+			db:         stubPool{},
+			redis:      unreachableRedis,
+			wantStatus: http.StatusOK,
+			wantDeps: map[string]string{
+				"postgres":                 health.StatusUp,
+				"postgres_circuit_breaker": health.StatusUp,
+				"redis":                    health.StatusDown,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var redisClient *redis.Client
+			if tt.redis != nil {
+				redisClient = tt.redis(t)
+			}
+			dbBreaker := breaker.New(breaker.Settings{Name: "postgres"})
+			if tt.breaker != nil {
+				dbBreaker = tt.breaker(t)
+			}
+			readiness := health.NewReadiness()
+			h := NewHealthHandler(tt.db, redisClient, dbBreaker, readiness)
+
+			w, response := probe(h, "/healthz")
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, tt.wantStatus, w.Body)
+			}
+			wantOverall := health.StatusUp
+			if tt.wantStatus != http.StatusOK {
+				wantOverall = health.StatusDown
+			}
+			if response.Status != wantOverall {
+				t.Fatalf("status field = %q, want %q", response.Status, wantOverall)
+			}
+			if len(response.Dependencies) != len(tt.wantDeps) {
+				t.Fatalf("dependencies = %+v, want %v", response.Dependencies, tt.wantDeps)
+			}
+			for name, status := range tt.wantDeps {
+				if got := response.Dependencies[name].Status; got != status {
+					t.Fatalf("%s = %q, want %q", name, got, status)
+				}
+			}
+		})
+	}
+}
+
+// hungPool meniru database yang tidak menjawab dan tidak menghormati
+// context.
+type hungPool struct {
+	release chan struct{}
+}
+
+func (p hungPool) Ping(ctx context.Context) error {
+	<-p.release
+	return nil
+}
+
+func TestHealthzBoundsHungDatabase(t *testing.T) {
+	db := hungPool{release: make(chan struct{})}
+	defer close(db.release)
+	h := NewHealthHandler(db, nil, breaker.New(breaker.Settings{Name: "postgres"}), health.NewReadiness())
+
+	start := time.Now()
+	w, response := probe(h, "/healthz")
+	if elapsed := time.Since(start); elapsed > checkTimeout+time.Second {
+		t.Fatalf("probe took %s, want it bounded by %s", elapsed, checkTimeout)
+	}
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+	if got := response.Dependencies["postgres"]; got.Status != health.StatusDown {
+		t.Fatalf("postgres = %+v, want down", got)
+	}
+}
+
+// TestReadinessTransitions mengikuti lifecycle server: belum siap saat
+// startup, siap setelah warm-up, lalu tidak siap begitu drain dimulai.
+// /livez tetap 200 di semua tahap.
+func TestReadinessTransitions(t *testing.T) {
+	readiness := health.NewReadiness()
+	h := NewHealthHandler(stubPool{}, nil, breaker.New(breaker.Settings{Name: "postgres"}), readiness)
+
+	steps := []struct {
+		name       string
+		transition func()
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "startup", transition: func() {}, wantStatus: http.StatusServiceUnavailable, wantBody: StatusNotReady},
+		{name: "steady state", transition: readiness.MarkReady, wantStatus: http.StatusOK, wantBody: health.StatusUp},
+		{name: "draining", transition: readiness.MarkNotReady, wantStatus: http.StatusServiceUnavailable, wantBody: StatusNotReady},
+	}
+	for _, step := range steps {
+		step.transition()
+
+		w, response := probe(h, "/readyz")
+		if w.Code != step.wantStatus || response.Status != step.wantBody {
+			t.Fatalf("%s: /readyz = %d %q, want %d %q", step.name, w.Code, response.Status, step.wantStatus, step.wantBody)
+		}
+		w, response = probe(h, "/livez")
+		if w.Code != http.StatusOK || response.Status != health.StatusUp {
+			t.Fatalf("%s: /livez = %d %q, want 200 %q", step.name, w.Code, response.Status, health.StatusUp)
+		}
+	}
+}
+
+// TestReadyzDependencyDown memastikan instance yang siap tetap keluar dari
+// rotasi saat database tidak bisa dijangkau, tanpa membuat /livez gagal.
+func TestReadyzDependencyDown(t *testing.T) {
+	readiness := health.NewReadiness()
+	readiness.MarkReady()
+	h := NewHealthHandler(stubPool{err: errors.New("failed to connect")}, nil, breaker.New(breaker.Settings{Name: "postgres"}), readiness)
+
+	if w, response := probe(h, "/readyz"); w.Code != http.StatusServiceUnavailable || response.Status != health.StatusDown {
+		t.Fatalf("/readyz = %d %q, want 503 %q", w.Code, response.Status, health.StatusDown)
+	}
+	if w, _ := probe(h, "/livez"); w.Code != http.StatusOK {
+		t.Fatalf("/livez = %d, want 200", w.Code)
+	}
+}
+
+// TestReadyzRedisDown memastikan cache Redis yang tidak bisa dijangkau
+// tidak mengeluarkan instance dari rotasi, karena service tetap bisa
+// melayani dari Postgres. Status Redis tetap terlihat di dependencies.
+func TestReadyzRedisDown(t *testing.T) {
+	readiness := health.NewReadiness()
+	readiness.MarkReady()
+	h := NewHealthHandler(stubPool{}, unreachableRedis(t), breaker.New(breaker.Settings{Name: "postgres"}), readiness)
+
+	w, response := probe(h, "/readyz")
+	if w.Code != http.StatusOK || response.Status != health.StatusUp {
+		t.Fatalf("/readyz = %d %q, want 200 %q, body = %s", w.Code, response.Status, health.StatusUp, w.Body)
+	}
+	if got := response.Dependencies["redis"]; got.Status != health.StatusDown || got.Error == "" {
+		t.Fatalf("redis = %+v, want down with an error", got)
+	}
+}
@@ -0,0 +1,103 @@
+package integrationHandler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	service "github.com/levensspel/go-gin-template/service/integration"
+	"github.com/samber/do/v2"
+)
+
+// Header callback partner, lihat package signing
+const (
+	IntegrationIDHeader = "X-Integration-Id"
+	SignatureHeader     = "X-Signature"
+	TimestampHeader     = "X-Timestamp"
+)
+
+type IntegrationHandler interface {
+	Callback(ctx *gin.Context)
+}
+
+type handler struct {
+	service service.IntegrationService
+	config  *config.IntegrationConfig
+	logger  logger.Logger
+}
+
+func New(service service.IntegrationService, config *config.IntegrationConfig, logger logger.Logger) IntegrationHandler {
+	return &handler{service: service, config: config, logger: logger}
+}
+
+func NewInject(i do.Injector) (IntegrationHandler, error) {
+	_service := do.MustInvoke[service.IntegrationService](i)
+	_logger := do.MustInvoke[logger.LogHandler](i)
+	return New(_service, config.LoadIntegrationConfig(), &_logger), nil
+}
+
+// Receive a partner callback
+// @Tags integration
+// @Summary Receive a partner callback
+// @Description Accept a signed callback from a partner integration and queue it for processing. The request must carry X-Integration-Id, X-Timestamp (Unix seconds) and X-Signature: sha256=HMAC-SHA256(secret, "<X-Timestamp>.<body>") with the secret configured for the integration in INTEGRATION_SECRETS.
+// @Description Timestamps further than INTEGRATION_SIGNATURE_TOLERANCE from the server clock are rejected, and a callback with a signature that was already received returns 409.
+// @Accept json
+// @Produce json
+// @Param X-Integration-Id header string true "Integration ID"
+// @Param X-Timestamp header string true "Unix timestamp in seconds"
+// @Param X-Signature header string true "sha256=<hex HMAC-SHA256>"
+// @Param data body object true "Callback payload"
+// @Success 202 {object} helper.Response{data=dto.IntegrationCallbackResponse} "Accepted"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Missing, invalid or expired signature"
+// @Failure 409 {object} helper.Response{errors=helper.ErrorResponse} "Callback already received"
+// @Failure 413 {object} helper.Response{errors=helper.ErrorResponse} "Payload Too Large"
+// @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
+// @Router /v1/integrations/callback [POST]
+func (h *handler) Callback(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+	log := h.logger.With(logger.RequestFields(ctx, helper.IntegrationHandlerCallback)).WithContext(ctx.Request.Context())
+
+	integration := ctx.GetHeader(IntegrationIDHeader)
+	body, err := h.readBody(ctx)
+	if err != nil {
+		log.Warn(err.Error(), logger.Bound, integration)
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+
+	id, err := h.service.ReceiveCallback(ctx, integration, ctx.GetHeader(SignatureHeader), ctx.GetHeader(TimestampHeader), body)
+	if err != nil {
+		status, response := helper.FromError(err)
+		if status >= http.StatusInternalServerError {
+			log.Error(err.Error(), logger.Bound, integration)
+		} else {
+			log.Warn(err.Error(), logger.Bound, integration)
+		}
+		ctx.JSON(status, response)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, helper.OK(dto.IntegrationCallbackResponse{Id: id}))
+}
+
+// readBody membaca body callback paling besar MaxBodyBytes. Signature
+// dihitung dari byte body apa adanya, jadi body tidak di-bind ke struct.
+func (h *handler) readBody(ctx *gin.Context) ([]byte, error) {
+	if ctx.Request.ContentLength > h.config.MaxBodyBytes {
+		return nil, helper.ErrPayloadTooLarge
+	}
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, h.config.MaxBodyBytes)
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, helper.ErrPayloadTooLarge
+		}
+		return nil, helper.ErrBadRequest
+	}
+	return body, nil
+}
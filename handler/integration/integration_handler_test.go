@@ -0,0 +1,117 @@
+package integrationHandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/mocks"
+	service "github.com/levensspel/go-gin-template/service/integration"
+	"github.com/levensspel/go-gin-template/signing"
+)
+
+// fakeService mencatat argumen ReceiveCallback terakhir.
+type fakeService struct {
+	err                               error
+	calls                             int
+	integration, signature, timestamp string
+	body                              []byte
+}
+
+func (s *fakeService) ReceiveCallback(ctx context.Context, integration, signature, timestamp string, body []byte) (int64, error) {
+	s.calls++
+	s.integration, s.signature, s.timestamp, s.body = integration, signature, timestamp, body
+	if s.err != nil {
+		return 0, s.err
+	}
+	return 42, nil
+}
+
+// callback menjalankan POST /v1/integrations/callback dengan header
+// partner. body dibungkus io.NopCloser agar ContentLength tidak diketahui,
+// seperti request chunked, jika chunked bernilai true.
+func callback(svc *fakeService, body string, chunked bool) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	h := New(svc, &config.IntegrationConfig{MaxBodyBytes: 64}, mocks.Logger{})
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	var reader io.Reader = strings.NewReader(body)
+	if chunked {
+		reader = io.NopCloser(reader)
+	}
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/integrations/callback", reader)
+	ctx.Request.Header.Set(IntegrationIDHeader, "partner-a")
+	ctx.Request.Header.Set(SignatureHeader, "sha256=ab12")
+	ctx.Request.Header.Set(TimestampHeader, "1700000000")
+	h.Callback(ctx)
+	return w
+}
+
+func TestCallback(t *testing.T) {
+	svc := &fakeService{}
+	// Spasi dan urutan key ikut ditandatangani, jadi body harus apa adanya
+	body := `{ "b": 1, "a": 2 }`
+	w := callback(svc, body, false)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, body = %s, want 202", w.Code, w.Body)
+	}
+	var response struct {
+		Data struct {
+			Id int64 `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || response.Data.Id != 42 {
+		t.Fatalf("response = %s, %v", w.Body, err)
+	}
+	if svc.integration != "partner-a" || svc.signature != "sha256=ab12" || svc.timestamp != "1700000000" || string(svc.body) != body {
+		t.Fatalf("ReceiveCallback(%q, %q, %q, %q)", svc.integration, svc.signature, svc.timestamp, svc.body)
+	}
+}
+
+func TestCallbackErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		body    string
+		chunked bool
+		err     error
+		want    int
+		wantErr error
+	}{
+		{name: "bad signature", body: `{}`, err: signing.ErrSignatureMismatch, want: http.StatusUnauthorized, wantErr: signing.ErrSignatureMismatch},
+		{name: "missing signature", body: `{}`, err: signing.ErrMissingSignature, want: http.StatusUnauthorized, wantErr: signing.ErrMissingSignature},
+		{name: "expired", body: `{}`, err: signing.ErrTimestampOutOfRange, want: http.StatusUnauthorized, wantErr: signing.ErrTimestampOutOfRange},
+		{name: "replayed", body: `{}`, err: service.ErrCallbackReplayed, want: http.StatusConflict, wantErr: service.ErrCallbackReplayed},
+		{name: "repository error", body: `{}`, err: errors.New("insert failed"), want: http.StatusInternalServerError},
+		{name: "too large", body: strings.Repeat("a", 65), want: http.StatusRequestEntityTooLarge, wantErr: helper.ErrPayloadTooLarge},
+		{name: "too large without length", body: strings.Repeat("a", 65), chunked: true, want: http.StatusRequestEntityTooLarge, wantErr: helper.ErrPayloadTooLarge},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &fakeService{err: tt.err}
+			w := callback(svc, tt.body, tt.chunked)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, body = %s, want %d", w.Code, w.Body, tt.want)
+			}
+			if tt.wantErr != nil {
+				var response helper.Response
+				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+					t.Fatal(err)
+				}
+				if response.Errors == nil || response.Errors.Message != helper.GetErrorMessage(tt.wantErr) {
+					t.Fatalf("response = %s, want %q", w.Body, helper.GetErrorMessage(tt.wantErr))
+				}
+			}
+			// Body yang terlalu besar ditolak sebelum signature diperiksa
+			if tooLarge := tt.want == http.StatusRequestEntityTooLarge; (svc.calls == 0) != tooLarge {
+				t.Fatalf("service calls = %d", svc.calls)
+			}
+		})
+	}
+}
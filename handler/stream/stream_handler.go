@@ -0,0 +1,164 @@
+package streamHandler
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/clock"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/eventbus"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/middleware"
+	"github.com/levensspel/go-gin-template/validation"
+	"github.com/samber/do/v2"
+)
+
+// LastEventIDHeader dikirim ulang otomatis oleh EventSource saat reconnect.
+const LastEventIDHeader = "Last-Event-ID"
+
+// eventReset memberi tahu client bahwa sebagian event terlewat dan data
+// harus dimuat ulang lewat GET /v1/employee.
+const eventReset = "reset"
+
+type StreamHandler interface {
+	Employees(ctx *gin.Context)
+}
+
+type handler struct {
+	bus    *eventbus.Bus
+	clock  clock.Clock
+	config *config.StreamConfig
+	logger logger.Logger
+}
+
+func New(bus *eventbus.Bus, clock clock.Clock, config *config.StreamConfig, logger logger.Logger) StreamHandler {
+	return &handler{bus: bus, clock: clock, config: config, logger: logger}
+}
+
+func NewInject(i do.Injector) (StreamHandler, error) {
+	_bus := do.MustInvoke[*eventbus.Bus](i)
+	_clock := do.MustInvoke[clock.Clock](i)
+	_logger := do.MustInvoke[logger.LogHandler](i)
+	return New(_bus, _clock, config.LoadStreamConfig(), &_logger), nil
+}
+
+// Stream employee changes
+// @Tags employee
+// @Summary Stream employee changes
+// @Description Server-Sent Events stream of employee.created, employee.updated and employee.deleted events of the current manager. Each event has the outbox id as its id, the event type as its event name and the webhook envelope as its data. A comment is sent every STREAM_HEARTBEAT_INTERVAL to keep the connection open.
+// @Description To resume, send the last received id in Last-Event-ID (EventSource does this on reconnect) or lastEventId. If that event is no longer buffered the stream starts with a reset event and the client should reload GET /v1/employee. Events are only delivered by the instance that dispatched them, so clients should still reload periodically when running more than one instance.
+// @Produce text/event-stream
+// @Param Authorization header string true "Bearer JWT token"
+// @Param Last-Event-ID header int false "Id of the last received event"
+// @Param lastEventId query int false "Id of the last received event, for clients that cannot set headers"
+// @Success 200 {string} string "Event stream"
+// @Failure 400 {object} helper.Response{errors=helper.ErrorResponse} "Bad Request"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Router /v1/employee/stream [GET]
+func (h *handler) Employees(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+	log := h.logger.With(logger.RequestFields(ctx, helper.StreamHandlerEmployees)).WithContext(ctx.Request.Context())
+
+	managerID, err := middleware.GetIdUserFromContext(ctx)
+	if err != nil {
+		log.Warn(err.Error(), logger.Bound)
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
+		return
+	}
+	lastEventID, err := lastEventID(ctx.Request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, helper.Error(http.StatusBadRequest, err))
+		return
+	}
+
+	subscription := h.bus.Subscribe(managerID, lastEventID)
+	defer subscription.Close()
+
+	header := ctx.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	// Nginx tidak boleh menahan event di buffer
+	header.Set("X-Accel-Buffering", "no")
+	ctx.Writer.WriteHeader(http.StatusOK)
+
+	if subscription.Missed {
+		if err := writeEvent(ctx.Writer, "", eventReset, []byte("{}")); err != nil {
+			return
+		}
+	}
+	for _, event := range subscription.Backlog {
+		if err := writeEmployeeEvent(ctx.Writer, event); err != nil {
+			return
+		}
+	}
+	ctx.Writer.Flush()
+
+	heartbeat := h.clock.NewTicker(h.config.HeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		var err error
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case event, ok := <-subscription.Events():
+			if !ok {
+				// Bus di-shutdown atau client tertinggal; client melanjutkan
+				// dengan Last-Event-ID
+				return
+			}
+			err = writeEmployeeEvent(ctx.Writer, event)
+		case <-heartbeat.C():
+			_, err = io.WriteString(ctx.Writer, ": heartbeat\n\n")
+		}
+		if err != nil {
+			return
+		}
+		ctx.Writer.Flush()
+	}
+}
+
+// lastEventID memakai header Last-Event-ID, lalu query lastEventId. 0
+// berarti tanpa resume.
+func lastEventID(r *http.Request) (int64, error) {
+	value := r.Header.Get(LastEventIDHeader)
+	if value == "" {
+		value = r.URL.Query().Get("lastEventId")
+	}
+	if value == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id < 0 {
+		return 0, validation.FieldErrors{"lastEventId": "lastEventId must be an event id"}
+	}
+	return id, nil
+}
+
+func writeEmployeeEvent(w io.Writer, event eventbus.Event) error {
+	if !strings.HasPrefix(event.Type, "employee.") {
+		return nil
+	}
+	return writeEvent(w, strconv.FormatInt(event.ID, 10), event.Type, event.Payload)
+}
+
+// writeEvent menulis satu event SSE. Setiap baris data ditulis sebagai
+// field data tersendiri sesuai format text/event-stream.
+func writeEvent(w io.Writer, id, name string, data []byte) error {
+	var b bytes.Buffer
+	if id != "" {
+		fmt.Fprintf(&b, "id: %s\n", id)
+	}
+	fmt.Fprintf(&b, "event: %s\n", name)
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteString("\n")
+	_, err := w.Write(b.Bytes())
+	return err
+}
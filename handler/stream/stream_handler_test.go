@@ -0,0 +1,341 @@
+package streamHandler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/clock/clocktest"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/eventbus"
+	"github.com/levensspel/go-gin-template/events"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/mocks"
+)
+
+const (
+	testManagerID  = "0b7c2d2e-4f4a-4b8e-9c59-2f1d8f6a3e10"
+	otherManagerID = "5d1f0c7a-2b3e-4c4d-8e9f-0a1b2c3d4e5f"
+
+	heartbeatInterval = 15 * time.Second
+
+	// anonymousHeader membuat request test tanpa user
+	anonymousHeader = "X-Test-Anonymous"
+)
+
+// streamFixture menjalankan GET /v1/employee/stream di server HTTP
+// sungguhan. done menerima sinyal setiap kali handler selesai, setelah
+// subscription-nya ditutup.
+type streamFixture struct {
+	bus   *eventbus.Bus
+	clock *clocktest.Fake
+	srv   *httptest.Server
+	done  chan struct{}
+}
+
+func newStreamFixture(t *testing.T, bufferSize int) *streamFixture {
+	t.Helper()
+	f := &streamFixture{
+		bus:   eventbus.New(&config.StreamConfig{BufferSize: bufferSize, SubscriberBuffer: 10}),
+		clock: clocktest.NewFake(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)),
+		done:  make(chan struct{}, 10),
+	}
+	h := New(f.bus, f.clock, &config.StreamConfig{HeartbeatInterval: heartbeatInterval}, mocks.Logger{})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/v1/employee/stream", func(ctx *gin.Context) {
+		// Pengganti middleware Authorization
+		if ctx.GetHeader(anonymousHeader) == "" {
+			ctx.Set(helper.ContextKeyUserID, testManagerID)
+		}
+		h.Employees(ctx)
+		f.done <- struct{}{}
+	})
+	f.srv = httptest.NewServer(router)
+	t.Cleanup(f.srv.Close)
+	return f
+}
+
+func (f *streamFixture) publish(t *testing.T, id int64, eventType, managerID string) {
+	t.Helper()
+	data, _ := json.Marshal(events.Employee{ManagerID: managerID})
+	payload, _ := json.Marshal(events.Envelope{ID: id, Type: eventType, Data: data})
+	if err := f.bus.Publish(context.Background(), eventType, payload); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// waitDone menunggu handler selesai setelah client atau server menutup
+// stream.
+func (f *streamFixture) waitDone(t *testing.T) {
+	t.Helper()
+	select {
+	case <-f.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream handler did not return")
+	}
+}
+
+// sseEvent adalah satu event atau komentar dari stream.
+type sseEvent struct {
+	id, name, data, comment string
+}
+
+// sseReader membaca stream sambil event dikirim, bukan setelah response
+// selesai.
+type sseReader struct {
+	response *http.Response
+	events   chan sseEvent
+}
+
+func (f *streamFixture) open(t *testing.T, target string, header http.Header) *sseReader {
+	t.Helper()
+	request, err := http.NewRequest(http.MethodGet, f.srv.URL+target, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header != nil {
+		request.Header = header
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { response.Body.Close() })
+
+	r := &sseReader{response: response, events: make(chan sseEvent, 10)}
+	go func() {
+		defer close(r.events)
+		scanner := bufio.NewScanner(response.Body)
+		var event sseEvent
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case line == "":
+				r.events <- event
+				event = sseEvent{}
+			case strings.HasPrefix(line, ": "):
+				event.comment = strings.TrimPrefix(line, ": ")
+			case strings.HasPrefix(line, "id: "):
+				event.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				event.name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				event.data += strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}()
+	return r
+}
+
+func (r *sseReader) next(t *testing.T) sseEvent {
+	t.Helper()
+	select {
+	case event, ok := <-r.events:
+		if !ok {
+			t.Fatal("stream closed while waiting for an event")
+		}
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event received")
+	}
+	return sseEvent{}
+}
+
+func (r *sseReader) wantClosed(t *testing.T) {
+	t.Helper()
+	select {
+	case event, ok := <-r.events:
+		if ok {
+			t.Fatalf("received %+v, want the stream to be closed", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream was not closed")
+	}
+}
+
+func TestStreamDeliversManagerEvents(t *testing.T) {
+	f := newStreamFixture(t, 10)
+	r := f.open(t, "/v1/employee/stream", nil)
+	if r.response.StatusCode != http.StatusOK || r.response.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("status = %d, Content-Type = %q", r.response.StatusCode, r.response.Header.Get("Content-Type"))
+	}
+	// Ticker heartbeat terdaftar setelah backlog dikirim
+	f.clock.BlockUntil(1)
+
+	f.publish(t, 1, entity.EventEmployeeCreated, otherManagerID)
+	// Event department tidak dikirim di stream employee
+	f.publish(t, 2, entity.EventDepartmentCreated, testManagerID)
+	f.publish(t, 3, entity.EventEmployeeUpdated, testManagerID)
+
+	event := r.next(t)
+	if event.id != "3" || event.name != entity.EventEmployeeUpdated {
+		t.Fatalf("event = %+v, want id 3 %s", event, entity.EventEmployeeUpdated)
+	}
+	var envelope events.Envelope
+	if err := json.Unmarshal([]byte(event.data), &envelope); err != nil || envelope.ID != 3 {
+		t.Fatalf("data = %s, %v", event.data, err)
+	}
+
+	f.clock.Advance(heartbeatInterval)
+	if event := r.next(t); event.comment != "heartbeat" {
+		t.Fatalf("event = %+v, want a heartbeat comment", event)
+	}
+}
+
+// label adalah id event, atau nama event untuk event reset yang tidak
+// punya id.
+func (e sseEvent) label() string {
+	if e.name == eventReset {
+		return e.name
+	}
+	return e.id
+}
+
+func TestStreamResume(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		target string
+		header string
+		want   []string
+	}{
+		{name: "Last-Event-ID", target: "/v1/employee/stream", header: "2", want: []string{"3", "4"}},
+		{name: "query", target: "/v1/employee/stream?lastEventId=3", want: []string{"4"}},
+		{name: "header wins over query", target: "/v1/employee/stream?lastEventId=2", header: "3", want: []string{"4"}},
+		{name: "latest", target: "/v1/employee/stream?lastEventId=4"},
+		// Event 1 sudah tergeser dari buffer berukuran 3
+		{name: "evicted", target: "/v1/employee/stream", header: "1", want: []string{eventReset}},
+		{name: "unknown", target: "/v1/employee/stream?lastEventId=99", want: []string{eventReset}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newStreamFixture(t, 3)
+			f.publish(t, 1, entity.EventEmployeeCreated, testManagerID)
+			f.publish(t, 2, entity.EventEmployeeCreated, otherManagerID)
+			f.publish(t, 3, entity.EventEmployeeUpdated, testManagerID)
+			f.publish(t, 4, entity.EventEmployeeDeleted, testManagerID)
+
+			header := http.Header{}
+			if tt.header != "" {
+				header.Set(LastEventIDHeader, tt.header)
+			}
+			r := f.open(t, tt.target, header)
+			for _, want := range tt.want {
+				if event := r.next(t); event.label() != want {
+					t.Fatalf("event = %+v, want %s", event, want)
+				}
+			}
+
+			// Event baru dikirim setelah backlog
+			f.clock.BlockUntil(1)
+			f.publish(t, 5, entity.EventEmployeeCreated, testManagerID)
+			if event := r.next(t); event.id != "5" {
+				t.Fatalf("event after backlog = %+v, want id 5", event)
+			}
+		})
+	}
+}
+
+func TestStreamRejects(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		target    string
+		anonymous bool
+		want      int
+	}{
+		{name: "without user", target: "/v1/employee/stream", anonymous: true, want: http.StatusUnauthorized},
+		{name: "invalid last event id", target: "/v1/employee/stream?lastEventId=abc", want: http.StatusBadRequest},
+		{name: "negative last event id", target: "/v1/employee/stream?lastEventId=-1", want: http.StatusBadRequest},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newStreamFixture(t, 10)
+			header := http.Header{}
+			if tt.anonymous {
+				header.Set(anonymousHeader, "true")
+			}
+			r := f.open(t, tt.target, header)
+			if r.response.StatusCode != tt.want || r.response.Header.Get("Content-Type") == "text/event-stream" {
+				t.Fatalf("status = %d, Content-Type = %q, want %d JSON", r.response.StatusCode, r.response.Header.Get("Content-Type"), tt.want)
+			}
+			f.waitDone(t)
+		})
+	}
+}
+
+// TestStreamReleasesOnDisconnect memastikan handler selesai dan
+// subscription dilepas setelah client menutup koneksi.
+func TestStreamReleasesOnDisconnect(t *testing.T) {
+	f := newStreamFixture(t, 10)
+	r := f.open(t, "/v1/employee/stream", nil)
+	f.clock.BlockUntil(1)
+
+	r.response.Body.Close()
+	// handler hanya selesai setelah subscription ditutup dan ticker
+	// dihentikan lewat defer
+	f.waitDone(t)
+
+	// Client yang reconnect melanjutkan dari event terakhirnya
+	f.publish(t, 1, entity.EventEmployeeCreated, testManagerID)
+	resumed := f.open(t, "/v1/employee/stream?lastEventId=1", nil)
+	f.clock.BlockUntil(1)
+	f.publish(t, 2, entity.EventEmployeeCreated, testManagerID)
+	if event := resumed.next(t); event.id != "2" {
+		t.Fatalf("event = %+v, want id 2", event)
+	}
+}
+
+func TestStreamEndsOnShutdown(t *testing.T) {
+	f := newStreamFixture(t, 10)
+	r := f.open(t, "/v1/employee/stream", nil)
+	f.clock.BlockUntil(1)
+
+	f.bus.Shutdown()
+	r.wantClosed(t)
+	f.waitDone(t)
+}
+
+func TestStreamDisconnectsSlowClient(t *testing.T) {
+	const count = 2000
+	f := newStreamFixture(t, count)
+	r := f.open(t, "/v1/employee/stream", nil)
+	f.clock.BlockUntil(1)
+
+	// Client berhenti membaca setelah antrian sseReader penuh. Setelah
+	// buffer socket juga penuh, handler tertahan di Write, antrian
+	// subscriber penuh dan Bus memutus stream tanpa menunggu.
+	data, _ := json.Marshal(events.Employee{ManagerID: testManagerID, Employee: dto.EmployeeResponse{
+		EmployeePayload: dto.EmployeePayload{Name: strings.Repeat("a", 4096)},
+	}})
+	published := make(chan struct{})
+	go func() {
+		defer close(published)
+		for id := range int64(count) {
+			payload, _ := json.Marshal(events.Envelope{ID: id + 1, Type: entity.EventEmployeeCreated, Data: data})
+			_ = f.bus.Publish(context.Background(), entity.EventEmployeeCreated, payload)
+		}
+	}()
+	select {
+	case <-published:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Publish blocked on a slow client")
+	}
+	f.waitDone(t)
+
+	// Client membaca event yang sudah terkirim lalu stream selesai
+	last := ""
+	for event := range r.events {
+		last = event.id
+	}
+	if id, err := strconv.Atoi(last); err != nil || id >= count {
+		t.Fatalf("last event = %q, want the stream to end before every event", last)
+	}
+}
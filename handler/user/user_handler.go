@@ -4,7 +4,9 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/config"
 	"github.com/levensspel/go-gin-template/dto"
+	fileHandler "github.com/levensspel/go-gin-template/handler/file"
 	"github.com/levensspel/go-gin-template/helper"
 	"github.com/levensspel/go-gin-template/logger"
 	"github.com/levensspel/go-gin-template/middleware"
@@ -18,21 +20,24 @@ type UserHandler interface {
 	Delete(ctx *gin.Context)
 	GetProfile(ctx *gin.Context)
 	UpdateProfile(ctx *gin.Context)
+	ReplaceAvatar(ctx *gin.Context)
+	ReplaceCompanyLogo(ctx *gin.Context)
 }
 
 type handler struct {
-	service service.UserService
-	logger  logger.Logger
+	service    service.IUserService
+	fileConfig *config.FileConfig
+	logger     logger.Logger
 }
 
-func NewUserHandler(service service.UserService, logger logger.Logger) UserHandler {
-	return &handler{service: service, logger: logger}
+func NewUserHandler(service service.IUserService, fileConfig *config.FileConfig, logger logger.Logger) UserHandler {
+	return &handler{service: service, fileConfig: fileConfig, logger: logger}
 }
 
 func NewUserHandlerInject(i do.Injector) (UserHandler, error) {
-	_service := do.MustInvoke[service.UserService](i)
+	_service := do.MustInvoke[service.IUserService](i)
 	_logger := do.MustInvoke[logger.LogHandler](i)
-	return NewUserHandler(_service, &_logger), nil
+	return NewUserHandler(_service, config.LoadFileConfig(), &_logger), nil
 }
 
 // Update user
@@ -51,18 +56,18 @@ func (h handler) Update(ctx *gin.Context) {
 	input := new(dto.RequestRegister)
 
 	if err := ctx.ShouldBind(input); err != nil {
-		ctx.JSON(http.StatusUnprocessableEntity, helper.NewResponse(nil, err))
+		ctx.JSON(http.StatusUnprocessableEntity, helper.Error(http.StatusUnprocessableEntity, err))
 		return
 	}
 	id := ctx.MustGet("user_id")
 	input.Id = id.(string)
-	response, err := h.service.Update(*input)
+	response, err := h.service.Update(ctx, *input)
 
 	if err != nil {
-		ctx.JSON(helper.GetErrorStatusCode(err), helper.NewResponse(nil, err))
+		ctx.JSON(helper.FromError(err))
 		return
 	}
-	ctx.JSON(http.StatusOK, helper.NewResponse(response, nil))
+	ctx.JSON(http.StatusOK, helper.OK(response))
 }
 
 // DeleteByID user
@@ -80,15 +85,15 @@ func (h handler) Update(ctx *gin.Context) {
 func (h handler) Delete(ctx *gin.Context) {
 	id := ctx.MustGet("user_id")
 
-	err := h.service.DeleteByID(id.(string))
+	err := h.service.DeleteByID(ctx, id.(string))
 
 	if err != nil {
-		ctx.JSON(helper.GetErrorStatusCode(err), helper.NewResponse(nil, err))
+		ctx.JSON(helper.FromError(err))
 		return
 	}
 
 	message := map[string]interface{}{"message": "your account has been successfully deleted"}
-	ctx.JSON(http.StatusOK, helper.NewResponse(message, nil))
+	ctx.JSON(http.StatusOK, helper.OK(message))
 }
 
 // Get Profile user
@@ -105,16 +110,16 @@ func (h handler) Delete(ctx *gin.Context) {
 func (h handler) GetProfile(ctx *gin.Context) {
 	id, err := middleware.GetIdUserFromContext(ctx)
 	if err != nil {
-		ctx.JSON(helper.GetErrorStatusCode(err), helper.NewResponse(nil, err))
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
 		return
 	}
 
-	response, err := h.service.GetProfile(id)
+	response, err := h.service.GetProfile(ctx, id)
 	if err != nil {
-		ctx.JSON(helper.GetErrorStatusCode(err), helper.NewResponse(nil, err))
+		ctx.JSON(helper.FromError(err))
 		return
 	}
-	ctx.JSON(http.StatusOK, response)
+	ctx.JSON(http.StatusOK, helper.OK(response))
 }
 
 // Update profile
@@ -132,27 +137,87 @@ func (h handler) GetProfile(ctx *gin.Context) {
 func (h handler) UpdateProfile(ctx *gin.Context) {
 	id, err := middleware.GetIdUserFromContext(ctx)
 	if err != nil {
-		ctx.JSON(helper.GetErrorStatusCode(err), helper.NewResponse(nil, err))
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
 		return
 	}
 
 	req := new(dto.RequestUpdateProfile)
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		h.logger.Warn(err.Error(), helper.UserHandler, &req)
-		ctx.JSON(helper.GetErrorStatusCode(err), helper.NewResponse(nil, err))
+		ctx.JSON(http.StatusBadRequest, helper.Error(http.StatusBadRequest, err))
 		return
 	}
 
 	err = validation.ValidateUpdateProfile(*req)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, helper.NewResponse(nil, err))
+		ctx.JSON(http.StatusBadRequest, helper.Error(http.StatusBadRequest, err))
 		return
 	}
 
-	response, err := h.service.UpdateProfile(id, *req)
+	response, err := h.service.UpdateProfile(ctx, id, *req)
 	if err != nil {
-		ctx.JSON(helper.GetErrorStatusCode(err), helper.NewResponse(nil, err))
+		ctx.JSON(helper.FromError(err))
 		return
 	}
-	ctx.JSON(http.StatusOK, response)
+	ctx.JSON(http.StatusOK, helper.OK(response))
+}
+
+// Replace avatar
+// @Tags users
+// @Summary Upload avatar
+// @Description Upload a jpeg or png image and set it as userImageUri in one step. The image is validated as in POST /v1/file. The previous avatar is no longer referenced and is deleted after CLEANUP_FILE_GRACE_PERIOD.
+// @Accept multipart/form-data
+// @Produce json
+// @Param Authorization header string true "Bearer + user token"
+// @Param file formData file true "jpeg or png image"
+// @Success 200 {object} helper.Response{data=dto.ResposneGetProfile} "Updated profile"
+// @Failure 400 {object} helper.Response{errors=helper.ErrorResponse} "Missing file or invalid image, see POST /v1/file"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorization"
+// @Failure 413 {object} helper.Response{errors=helper.ErrorResponse} "File too large"
+// @Router /v1/user/avatar [PUT]
+func (h handler) ReplaceAvatar(ctx *gin.Context) {
+	h.replaceProfileImage(ctx, service.ProfileImageAvatar, helper.UserHandlerReplaceAvatar)
+}
+
+// Replace company logo
+// @Tags users
+// @Summary Upload company logo
+// @Description Upload a jpeg or png image and set it as companyImageUri in one step. The image is validated as in POST /v1/file. The previous logo is no longer referenced and is deleted after CLEANUP_FILE_GRACE_PERIOD.
+// @Accept multipart/form-data
+// @Produce json
+// @Param Authorization header string true "Bearer + user token"
+// @Param file formData file true "jpeg or png image"
+// @Success 200 {object} helper.Response{data=dto.ResposneGetProfile} "Updated profile"
+// @Failure 400 {object} helper.Response{errors=helper.ErrorResponse} "Missing file or invalid image, see POST /v1/file"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorization"
+// @Failure 413 {object} helper.Response{errors=helper.ErrorResponse} "File too large"
+// @Router /v1/user/company-logo [PUT]
+func (h handler) ReplaceCompanyLogo(ctx *gin.Context) {
+	h.replaceProfileImage(ctx, service.ProfileImageCompanyLogo, helper.UserHandlerReplaceCompanyLogo)
+}
+
+func (h handler) replaceProfileImage(ctx *gin.Context, image service.ProfileImage, function helper.FunctionCaller) {
+	defer helper.FallbackResponse(ctx)
+
+	id, err := middleware.GetIdUserFromContext(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
+		return
+	}
+
+	part, err := fileHandler.FilePart(ctx, h.fileConfig.MaxUploadBytes)
+	if err != nil {
+		h.logger.Warn(err.Error(), function)
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+	defer part.Close()
+
+	response, err := h.service.ReplaceProfileImage(ctx, id, image, part.FileName(), part.Header.Get("Content-Type"), part)
+	if err != nil {
+		h.logger.Warn(err.Error(), function, part.FileName())
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, helper.OK(response))
 }
@@ -0,0 +1,141 @@
+package userHandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/mocks"
+	service "github.com/levensspel/go-gin-template/service/user"
+)
+
+const testManagerID = "0b7c2d2e-4f4a-4b8e-9c59-2f1d8f6a3e10"
+
+// multipartBody membuat body multipart dengan satu part bernama field.
+func multipartBody(t *testing.T, field, fileName string, content []byte) (*bytes.Buffer, string) {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile(field, fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := part.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &body, writer.FormDataContentType()
+}
+
+// replace menjalankan action untuk satu request. managerID kosong berarti
+// request tanpa user dari middleware Authorization.
+func replace(action gin.HandlerFunc, body *bytes.Buffer, contentType, managerID string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/user/avatar", body)
+	ctx.Request.Header.Set("Content-Type", contentType)
+	if managerID != "" {
+		ctx.Set(helper.ContextKeyUserID, managerID)
+	}
+	action(ctx)
+	return w
+}
+
+func TestReplaceProfileImage(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		action func(h UserHandler) gin.HandlerFunc
+		want   service.ProfileImage
+	}{
+		{name: "avatar", action: func(h UserHandler) gin.HandlerFunc { return h.ReplaceAvatar }, want: service.ProfileImageAvatar},
+		{name: "company logo", action: func(h UserHandler) gin.HandlerFunc { return h.ReplaceCompanyLogo }, want: service.ProfileImageCompanyLogo},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotImage service.ProfileImage
+			var gotName string
+			var gotContent []byte
+			users := &mocks.UserService{
+				ReplaceProfileImageFunc: func(ctx context.Context, managerid string, image service.ProfileImage, fileName string, claimedType string, body io.Reader) (*dto.ResposneGetProfile, error) {
+					if managerid != testManagerID {
+						t.Errorf("managerid = %q, want %q", managerid, testManagerID)
+					}
+					gotImage, gotName = image, fileName
+					content, err := io.ReadAll(body)
+					if err != nil {
+						return nil, err
+					}
+					gotContent = content
+					return &dto.ResposneGetProfile{Email: "manager@example.com", UserImageUri: "https://api.example.com/files/new-image"}, nil
+				},
+			}
+			h := NewUserHandler(users, &config.FileConfig{MaxUploadBytes: 1 << 20}, mocks.Logger{})
+			body, contentType := multipartBody(t, "file", "image.png", []byte("image bytes"))
+
+			w := replace(tt.action(h), body, contentType, testManagerID)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body = %s, want 200", w.Code, w.Body)
+			}
+			if gotImage != tt.want || gotName != "image.png" || string(gotContent) != "image bytes" {
+				t.Fatalf("ReplaceProfileImage got %s %q %q", gotImage, gotName, gotContent)
+			}
+			var response struct {
+				Data dto.ResposneGetProfile `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			if response.Data.UserImageUri != "https://api.example.com/files/new-image" {
+				t.Fatalf("response = %s, want the updated profile", w.Body)
+			}
+		})
+	}
+}
+
+func TestReplaceProfileImageErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		field      string
+		content    []byte
+		managerID  string
+		serviceErr error
+		want       int
+	}{
+		{name: "no user", field: "file", content: []byte("image"), want: http.StatusUnauthorized},
+		{name: "missing file part", field: "other", content: []byte("image"), managerID: testManagerID, want: http.StatusBadRequest},
+		{name: "too large", field: "file", content: make([]byte, 128<<10), managerID: testManagerID, want: http.StatusRequestEntityTooLarge},
+		{name: "not an image", field: "file", content: []byte("image"), managerID: testManagerID, serviceErr: helper.ErrInvalidFileType, want: http.StatusBadRequest},
+		{name: "manager not found", field: "file", content: []byte("image"), managerID: testManagerID, serviceErr: helper.ErrNotFound, want: http.StatusNotFound},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			users := &mocks.UserService{
+				ReplaceProfileImageFunc: func(ctx context.Context, managerid string, image service.ProfileImage, fileName string, claimedType string, body io.Reader) (*dto.ResposneGetProfile, error) {
+					called = true
+					return nil, tt.serviceErr
+				},
+			}
+			h := NewUserHandler(users, &config.FileConfig{MaxUploadBytes: 1 << 10}, mocks.Logger{})
+			body, contentType := multipartBody(t, tt.field, "image.png", tt.content)
+
+			w := replace(h.ReplaceAvatar, body, contentType, tt.managerID)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, body = %s, want %d", w.Code, w.Body, tt.want)
+			}
+			if called != (tt.serviceErr != nil) {
+				t.Fatalf("service called = %v", called)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package webhookHandler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/middleware"
+	service "github.com/levensspel/go-gin-template/service/webhook"
+	"github.com/levensspel/go-gin-template/validation"
+	"github.com/samber/do/v2"
+)
+
+const defaultDeliveryLimit = 20
+
+type WebhookHandler interface {
+	Create(ctx *gin.Context)
+	GetAll(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+	Test(ctx *gin.Context)
+	GetDeliveries(ctx *gin.Context)
+}
+
+type handler struct {
+	service service.WebhookService
+	logger  logger.Logger
+}
+
+func New(service service.WebhookService, logger logger.Logger) WebhookHandler {
+	return &handler{service: service, logger: logger}
+}
+
+func NewInject(i do.Injector) (WebhookHandler, error) {
+	_service := do.MustInvoke[service.WebhookService](i)
+	_logger := do.MustInvoke[logger.LogHandler](i)
+	return New(_service, &_logger), nil
+}
+
+// Create a webhook
+// @Tags webhook
+// @Summary Create a webhook
+// @Description Subscribe a URL to employee and department events. Each delivery is a JSON POST signed with X-Webhook-Signature: sha256=HMAC-SHA256(secret, "<X-Webhook-Timestamp>.<body>"). Empty events subscribes to all events. The secret is only returned here.
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param data body dto.WebhookRequest true "data"
+// @Success 201 {object} helper.Response{data=dto.WebhookResponse} "Created"
+// @Failure 400 {object} helper.Response{errors=helper.ErrorResponse} "Bad Request"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
+// @Router /v1/webhooks [POST]
+func (h *handler) Create(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+
+	managerID, err := middleware.GetIdUserFromContext(ctx)
+	if err != nil {
+		h.logger.Warn(err.Error(), helper.WebhookHandlerCreate)
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
+		return
+	}
+
+	var input dto.WebhookRequest
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		h.logger.Warn(err.Error(), helper.WebhookHandlerCreate)
+		ctx.JSON(http.StatusBadRequest, helper.Error(http.StatusBadRequest, err))
+		return
+	}
+	if err := validation.ValidateWebhookCreate(&input); err != nil {
+		h.logger.Warn(err.Error(), helper.WebhookHandlerCreate, input.Url)
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+
+	response, err := h.service.Create(ctx, managerID, input)
+	if err != nil {
+		h.logger.Warn(err.Error(), helper.WebhookHandlerCreate, input.Url)
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+	ctx.JSON(http.StatusCreated, helper.Created(response))
+}
+
+// List webhooks
+// @Tags webhook
+// @Summary List webhooks
+// @Description List the webhooks of the current manager
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Success 200 {object} helper.Response{data=[]dto.WebhookResponse} "OK"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
+// @Router /v1/webhooks [GET]
+func (h *handler) GetAll(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+
+	managerID, err := middleware.GetIdUserFromContext(ctx)
+	if err != nil {
+		h.logger.Warn(err.Error(), helper.WebhookHandlerGetAll)
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
+		return
+	}
+
+	webhooks, err := h.service.GetAll(ctx, managerID)
+	if err != nil {
+		h.logger.Error(err.Error(), helper.WebhookHandlerGetAll, err)
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, helper.OK(webhooks))
+}
+
+// Delete a webhook
+// @Tags webhook
+// @Summary Delete a webhook
+// @Description Delete a webhook and its delivery history. Pending deliveries are not sent.
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} helper.Response "OK"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 404 {object} helper.Response{errors=helper.ErrorResponse} "Not Found"
+// @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
+// @Router /v1/webhooks/{id} [DELETE]
+func (h *handler) Delete(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+
+	managerID, err := middleware.GetIdUserFromContext(ctx)
+	if err != nil {
+		h.logger.Warn(err.Error(), helper.WebhookHandlerDelete)
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
+		return
+	}
+
+	if err := h.service.Delete(ctx, ctx.Param("id"), managerID); err != nil {
+		h.logger.Warn(err.Error(), helper.WebhookHandlerDelete, ctx.Param("id"))
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, helper.OK(nil))
+}
+
+// Send a test event
+// @Tags webhook
+// @Summary Send a test event
+// @Description Send a webhook.test event to the webhook right away and return the result. A failed test is not retried.
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} helper.Response{data=dto.WebhookDeliveryResponse} "OK"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 404 {object} helper.Response{errors=helper.ErrorResponse} "Not Found"
+// @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
+// @Router /v1/webhooks/{id}/test [POST]
+func (h *handler) Test(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+
+	managerID, err := middleware.GetIdUserFromContext(ctx)
+	if err != nil {
+		h.logger.Warn(err.Error(), helper.WebhookHandlerTest)
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
+		return
+	}
+
+	delivery, err := h.service.Test(ctx, ctx.Param("id"), managerID)
+	if err != nil {
+		h.logger.Warn(err.Error(), helper.WebhookHandlerTest, ctx.Param("id"))
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, helper.OK(delivery))
+}
+
+// List webhook deliveries
+// @Tags webhook
+// @Summary List webhook deliveries
+// @Description List the deliveries of a webhook, newest first. Deliveries with status dead have used up their retries and are not sent again.
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param id path string true "Webhook ID"
+// @Param limit query int false "Limit (max 100)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} helper.Response{data=[]dto.WebhookDeliveryResponse} "OK"
+// @Failure 400 {object} helper.Response{errors=helper.ErrorResponse} "Bad Request"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Failure 404 {object} helper.Response{errors=helper.ErrorResponse} "Not Found"
+// @Failure 500 {object} helper.Response{errors=helper.ErrorResponse} "Server Error"
+// @Router /v1/webhooks/{id}/deliveries [GET]
+func (h *handler) GetDeliveries(ctx *gin.Context) {
+	defer helper.FallbackResponse(ctx)
+
+	managerID, err := middleware.GetIdUserFromContext(ctx)
+	if err != nil {
+		h.logger.Warn(err.Error(), helper.WebhookHandlerGetDeliveries)
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
+		return
+	}
+
+	input := dto.GetWebhookDeliveriesRequest{Limit: defaultDeliveryLimit}
+	if limit, err := strconv.Atoi(ctx.Query("limit")); err == nil {
+		input.Limit = limit
+	}
+	if offset, err := strconv.Atoi(ctx.Query("offset")); err == nil {
+		input.Offset = offset
+	}
+	if err := validation.ValidateWebhookDeliveriesGet(&input); err != nil {
+		h.logger.Warn(err.Error(), helper.WebhookHandlerGetDeliveries, input)
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+
+	deliveries, err := h.service.GetDeliveries(ctx, ctx.Param("id"), managerID, input)
+	if err != nil {
+		h.logger.Warn(err.Error(), helper.WebhookHandlerGetDeliveries, ctx.Param("id"))
+		ctx.JSON(helper.FromError(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, helper.OK(deliveries))
+}
@@ -0,0 +1,73 @@
+package websocketHandler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/middleware"
+	"github.com/levensspel/go-gin-template/realtime"
+	"github.com/samber/do/v2"
+)
+
+type WebSocketHandler interface {
+	Connect(ctx *gin.Context)
+}
+
+type handler struct {
+	hub      *realtime.Hub
+	upgrader websocket.Upgrader
+	logger   logger.Logger
+}
+
+func New(hub *realtime.Hub, logger logger.Logger) WebSocketHandler {
+	return &handler{
+		hub: hub,
+		upgrader: websocket.Upgrader{
+			// Dibalas agar browser yang mengirim token lewat subprotocol
+			// menerima handshake, lihat middleware.WebSocketToken
+			Subprotocols: []string{middleware.WebSocketProtocolBearer},
+			// Autentikasi memakai bearer token, bukan cookie, sehingga origin
+			// lain tidak bisa memakai sesi user; sama dengan CORS "*"
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		logger: logger,
+	}
+}
+
+func NewInject(i do.Injector) (WebSocketHandler, error) {
+	_hub := do.MustInvoke[*realtime.Hub](i)
+	_logger := do.MustInvoke[logger.LogHandler](i)
+	return New(_hub, &_logger), nil
+}
+
+// Connect to the notification WebSocket
+// @Tags employee
+// @Summary Connect to the notification WebSocket
+// @Description Upgrade to a WebSocket that delivers employee.created, employee.updated and employee.deleted events of the current manager. Authenticate with the Authorization header, or from a browser with the subprotocols "bearer, <token>".
+// @Description Send {"type": "subscribe", "departments": ["<departmentId>"]} to start receiving events ({"type": "event", "event": <webhook envelope>}) of those departments; an empty list subscribes to all departments and a new subscribe replaces the previous one. When the connection falls behind, events are dropped and a {"type": "dropped", "count": n} message is sent before the next event. The server pings every WS_PING_INTERVAL and closes connections that stop answering.
+// @Param Authorization header string false "Bearer JWT token"
+// @Success 101 {string} string "Switching Protocols"
+// @Failure 400 {string} string "Not a WebSocket handshake"
+// @Failure 401 {object} helper.Response{errors=helper.ErrorResponse} "Unauthorized"
+// @Router /v1/ws [GET]
+func (h *handler) Connect(ctx *gin.Context) {
+	log := h.logger.With(logger.RequestFields(ctx, helper.WebSocketHandlerConnect)).WithContext(ctx.Request.Context())
+
+	managerID, err := middleware.GetIdUserFromContext(ctx)
+	if err != nil {
+		log.Warn(err.Error(), logger.Bound)
+		ctx.JSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
+		return
+	}
+
+	// Upgrade sudah menulis response error jika handshake tidak valid
+	ws, err := h.upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		log.Debug(err.Error(), logger.Bound)
+		return
+	}
+	h.hub.Serve(ws, managerID)
+}
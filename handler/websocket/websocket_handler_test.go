@@ -0,0 +1,137 @@
+package websocketHandler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/levensspel/go-gin-template/clock/clocktest"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/eventbus"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/middleware"
+	"github.com/levensspel/go-gin-template/mocks"
+	"github.com/levensspel/go-gin-template/realtime"
+)
+
+const testManagerID = "0b7c2d2e-4f4a-4b8e-9c59-2f1d8f6a3e10"
+
+// newTestServer menjalankan GET /v1/ws dengan WebSocketToken seperti
+// route aslinya. Token "valid" menggantikan middleware Authorization.
+func newTestServer(t *testing.T) (*httptest.Server, *eventbus.Bus, *realtime.Hub) {
+	t.Helper()
+	bus := eventbus.New(&config.StreamConfig{BufferSize: 10, SubscriberBuffer: 10})
+	hub := realtime.NewHub(bus, clocktest.NewFake(time.Now()), &config.WebSocketConfig{
+		PingInterval: time.Minute, WriteTimeout: time.Second, QueueSize: 4, MaxMessageBytes: 4096,
+	}, mocks.Logger{})
+	h := New(hub, mocks.Logger{})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/v1/ws", middleware.WebSocketToken, func(ctx *gin.Context) {
+		if ctx.GetHeader("Authorization") == "Bearer valid" {
+			ctx.Set(helper.ContextKeyUserID, testManagerID)
+		}
+	}, h.Connect)
+	srv := httptest.NewServer(router)
+	t.Cleanup(func() {
+		bus.Shutdown()
+		srv.Close()
+	})
+	return srv, bus, hub
+}
+
+func wsURL(srv *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(srv.URL, "http") + "/v1/ws"
+}
+
+func TestConnect(t *testing.T) {
+	for _, tt := range []struct {
+		name         string
+		header       http.Header
+		wantProtocol string
+	}{
+		{name: "authorization header", header: http.Header{"Authorization": {"Bearer valid"}}},
+		// Browser mengirim token lewat subprotocol dan harus menerima
+		// subprotocol bearer kembali
+		{name: "bearer subprotocol", header: http.Header{"Sec-WebSocket-Protocol": {"bearer, valid"}}, wantProtocol: middleware.WebSocketProtocolBearer},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, bus, hub := newTestServer(t)
+			ws, response, err := websocket.DefaultDialer.Dial(wsURL(srv), tt.header)
+			if err != nil {
+				t.Fatalf("Dial error = %v", err)
+			}
+			defer ws.Close()
+			if response.StatusCode != http.StatusSwitchingProtocols || ws.Subprotocol() != tt.wantProtocol {
+				t.Fatalf("status = %d, subprotocol = %q, want 101 %q", response.StatusCode, ws.Subprotocol(), tt.wantProtocol)
+			}
+
+			if err := ws.WriteJSON(realtime.ClientMessage{Type: realtime.MessageSubscribe}); err != nil {
+				t.Fatal(err)
+			}
+			var reply realtime.ServerMessage
+			_ = ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+			if err := ws.ReadJSON(&reply); err != nil || reply.Type != realtime.MessageSubscribed {
+				t.Fatalf("reply = %+v, %v", reply, err)
+			}
+
+			// Connect selesai setelah Bus di-shutdown dan client membalas
+			// close frame
+			bus.Shutdown()
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			go func() {
+				for {
+					if _, _, err := ws.ReadMessage(); err != nil {
+						return
+					}
+				}
+			}()
+			if err := hub.Shutdown(ctx); err != nil {
+				t.Fatalf("Shutdown error = %v", err)
+			}
+		})
+	}
+}
+
+func TestConnectRejects(t *testing.T) {
+	srv, _, _ := newTestServer(t)
+	for _, tt := range []struct {
+		name   string
+		header http.Header
+		plain  bool
+		want   int
+	}{
+		{name: "without token", want: http.StatusUnauthorized},
+		{name: "invalid subprotocol token", header: http.Header{"Sec-WebSocket-Protocol": {"bearer, expired"}}, want: http.StatusUnauthorized},
+		{name: "not a websocket handshake", header: http.Header{"Authorization": {"Bearer valid"}}, plain: true, want: http.StatusBadRequest},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var response *http.Response
+			if tt.plain {
+				request, _ := http.NewRequest(http.MethodGet, srv.URL+"/v1/ws", nil)
+				request.Header = tt.header
+				var err error
+				if response, err = http.DefaultClient.Do(request); err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				var err error
+				// Dial gagal dengan ErrBadHandshake dan response aslinya
+				if _, response, err = websocket.DefaultDialer.Dial(wsURL(srv), tt.header); err == nil {
+					t.Fatal("Dial succeeded")
+				}
+			}
+			defer response.Body.Close()
+			if response.StatusCode != tt.want {
+				t.Fatalf("status = %d, want %d", response.StatusCode, tt.want)
+			}
+		})
+	}
+}
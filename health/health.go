@@ -0,0 +1,83 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// Check adalah satu pemeriksaan dependensi. Check yang Critical akan
+// membuat probe mengembalikan 503 ketika gagal.
+type Check struct {
+	Name     string
+	Critical bool
+	Run      func(ctx context.Context) error
+}
+
+type Result struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// RunChecks menjalankan semua check secara paralel dengan batas waktu total
+// timeout, sehingga dependensi yang hang tidak membuat probe ikut hang.
+// Nilai kembalian kedua bernilai false jika ada check Critical yang gagal.
+func RunChecks(ctx context.Context, timeout time.Duration, checks []Check) (map[string]Result, bool) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	results := make(map[string]Result, len(checks))
+	healthy := true
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range checks {
+		wg.Add(1)
+		go func(check Check) {
+			defer wg.Done()
+
+			start := time.Now()
+			err := runWithContext(ctx, check.Run)
+			result := Result{
+				Status:    StatusUp,
+				LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if err != nil {
+				result.Status = StatusDown
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[check.Name] = result
+			if err != nil && check.Critical {
+				healthy = false
+			}
+		}(check)
+	}
+	wg.Wait()
+
+	return results, healthy
+}
+
+// runWithContext memastikan check tetap berhenti saat deadline tercapai
+// walaupun implementasinya tidak menghormati context.
+func runWithContext(ctx context.Context, run func(ctx context.Context) error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- run(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
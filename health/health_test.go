@@ -0,0 +1,88 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunChecks(t *testing.T) {
+	up := func(context.Context) error { return nil }
+	down := func(context.Context) error { return errors.New("connection refused") }
+
+	tests := []struct {
+		name        string
+		checks      []Check
+		wantHealthy bool
+		wantStatus  map[string]string
+	}{
+		{
+			name:        "all up",
+			checks:      []Check{{Name: "postgres", Critical: true, Run: up}, {Name: "redis", Critical: true, Run: up}},
+			wantHealthy: true,
+			wantStatus:  map[string]string{"postgres": StatusUp, "redis": StatusUp},
+		},
+		{
+			name:        "critical down",
+			checks:      []Check{{Name: "postgres", Critical: true, Run: down}, {Name: "redis", Critical: true, Run: up}},
+			wantHealthy: false,
+			wantStatus:  map[string]string{"postgres": StatusDown, "redis": StatusUp},
+		},
+		{
+			name:        "non-critical down",
+			checks:      []Check{{Name: "postgres", Critical: true, Run: up}, {Name: "cache", Run: down}},
+			wantHealthy: true,
+			wantStatus:  map[string]string{"postgres": StatusUp, "cache": StatusDown},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, healthy := RunChecks(context.Background(), time.Second, tt.checks)
+			if healthy != tt.wantHealthy {
+				t.Fatalf("healthy = %t, want %t", healthy, tt.wantHealthy)
+			}
+			if len(results) != len(tt.wantStatus) {
+				t.Fatalf("results = %+v, want %d entries", results, len(tt.wantStatus))
+			}
+			for name, status := range tt.wantStatus {
+				result := results[name]
+				if result.Status != status {
+					t.Fatalf("%s status = %q, want %q", name, result.Status, status)
+				}
+				if (result.Error != "") != (status == StatusDown) {
+					t.Fatalf("%s error = %q with status %q", name, result.Error, status)
+				}
+			}
+		})
+	}
+}
+
+// TestRunChecksBoundsHungCheck memastikan check yang tidak menghormati
+// context tetap dihentikan saat timeout, seperti database yang hang.
+func TestRunChecksBoundsHungCheck(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	hung := func(context.Context) error {
+		<-release
+		return nil
+	}
+
+	start := time.Now()
+	results, healthy := RunChecks(context.Background(), 50*time.Millisecond, []Check{
+		{Name: "postgres", Critical: true, Run: hung},
+		{Name: "redis", Critical: true, Run: func(context.Context) error { return nil }},
+	})
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("RunChecks took %s, want it bounded by the timeout", elapsed)
+	}
+	if healthy {
+		t.Fatal("healthy = true with a hung critical check")
+	}
+	if got := results["postgres"]; got.Status != StatusDown || got.Error != context.DeadlineExceeded.Error() {
+		t.Fatalf("postgres = %+v, want down with %v", got, context.DeadlineExceeded)
+	}
+	if got := results["redis"]; got.Status != StatusUp {
+		t.Fatalf("redis = %+v, want up", got)
+	}
+}
@@ -0,0 +1,34 @@
+package health
+
+import (
+	"sync/atomic"
+
+	"github.com/samber/do/v2"
+)
+
+// Readiness menyimpan status siap-tidaknya instance menerima traffic.
+// Status hanya diubah oleh lifecycle server: siap setelah startup selesai
+// dan kembali tidak siap begitu graceful shutdown dimulai.
+type Readiness struct {
+	ready atomic.Bool
+}
+
+func NewReadiness() *Readiness {
+	return &Readiness{}
+}
+
+func NewReadinessInject(i do.Injector) (*Readiness, error) {
+	return NewReadiness(), nil
+}
+
+func (r *Readiness) MarkReady() {
+	r.ready.Store(true)
+}
+
+func (r *Readiness) MarkNotReady() {
+	r.ready.Store(false)
+}
+
+func (r *Readiness) IsReady() bool {
+	return r.ready.Load()
+}
@@ -10,21 +10,88 @@ const (
 	UserRepoCreate FunctionCaller = "userRepo.Create"
 	DbTrxRepoBegin FunctionCaller = "dbTrxRepo.Begin"
 
-	UserServiceRegister   FunctionCaller = "userService.RegisterUser"
-	UserServiceLogin      FunctionCaller = "userService.Login"
-	UserServiceUpdate     FunctionCaller = "userService.Update"
-	UserServiceDeleteByID FunctionCaller = "userService.DeleteById"
-	UserServiceGetProfile FunctionCaller = "userService.GetProfile"
+	UserServiceRegister            FunctionCaller = "userService.RegisterUser"
+	UserServiceLogin               FunctionCaller = "userService.Login"
+	UserServiceUpdate              FunctionCaller = "userService.Update"
+	UserServiceDeleteByID          FunctionCaller = "userService.DeleteById"
+	UserServiceGetProfile          FunctionCaller = "userService.GetProfile"
+	UserServiceReplaceProfileImage FunctionCaller = "userService.ReplaceProfileImage"
 
-	EmployeeHandlerCreate       FunctionCaller = "EmployeeHandler.Create"
-	EmployeeHandlerGetEmployees FunctionCaller = "EmployeeHandler.GetEmployees"
+	AuthHandlerPost FunctionCaller = "AuthHandler.Post"
 
-	EmployeeServiceCreate FunctionCaller = "employeeService.Create"
-	EmployeeServiceGet    FunctionCaller = "employeeService.Get"
+	EmployeeHandlerCreate         FunctionCaller = "EmployeeHandler.Create"
+	EmployeeHandlerUpdate         FunctionCaller = "EmployeeHandler.Update"
+	EmployeeHandlerDelete         FunctionCaller = "EmployeeHandler.Delete"
+	EmployeeHandlerGetEmployees   FunctionCaller = "EmployeeHandler.GetEmployees"
+	EmployeeHandlerImport         FunctionCaller = "EmployeeHandler.Import"
+	EmployeeHandlerBulkCreate     FunctionCaller = "EmployeeHandler.BulkCreate"
+	EmployeeHandlerIdentityNumber FunctionCaller = "EmployeeHandler.CheckIdentityNumber"
+	EmployeeHandlerExport         FunctionCaller = "EmployeeHandler.Export"
+	EmployeeHandlerArchive        FunctionCaller = "EmployeeHandler.Archive"
+	EmployeeHandlerRestore        FunctionCaller = "EmployeeHandler.Restore"
+	EmployeeHandlerStats          FunctionCaller = "EmployeeHandler.Stats"
+	EmployeeHandlerHistory        FunctionCaller = "EmployeeHandler.History"
+	EmployeeHandlerRestoreVersion FunctionCaller = "EmployeeHandler.RestoreVersion"
+
+	EmployeeServiceCreate           FunctionCaller = "employeeService.Create"
+	EmployeeServiceUpdate           FunctionCaller = "employeeService.Update"
+	EmployeeServiceDelete           FunctionCaller = "employeeService.Delete"
+	EmployeeServiceGet              FunctionCaller = "employeeService.Get"
+	EmployeeServiceCount            FunctionCaller = "employeeService.Count"
+	EmployeeServiceMissing          FunctionCaller = "employeeService.MissingIdentityNumbers"
+	EmployeeServiceSetStatus        FunctionCaller = "employeeService.SetStatus"
+	EmployeeServiceStats            FunctionCaller = "employeeService.Stats"
+	EmployeeServiceHistory          FunctionCaller = "employeeService.History"
+	EmployeeServiceRestoreVersion   FunctionCaller = "employeeService.RestoreVersion"
+	EmployeeServiceImport           FunctionCaller = "employeeService.Import"
+	EmployeeServiceCreateMany       FunctionCaller = "employeeService.CreateMany"
+	EmployeeServiceDryRunCreate     FunctionCaller = "employeeService.DryRunCreate"
+	EmployeeServiceDryRunCreateMany FunctionCaller = "employeeService.DryRunCreateMany"
+	EmployeeServiceIdentityNumber   FunctionCaller = "employeeService.IsIdentityNumberAvailable"
+	EmployeeServiceByDepartments    FunctionCaller = "employeeService.GetAllByDepartments"
+	EmployeeServiceExport           FunctionCaller = "employeeService.Export"
+
+	FileHandlerUpload         FunctionCaller = "FileHandler.Upload"
+	FileHandlerServe          FunctionCaller = "FileHandler.Serve"
+	FileHandlerServeThumbnail FunctionCaller = "FileHandler.ServeThumbnail"
+	FileHandlerServeResized   FunctionCaller = "FileHandler.ServeResized"
+	FileHandlerPresign        FunctionCaller = "FileHandler.Presign"
+	FileHandlerComplete       FunctionCaller = "FileHandler.Complete"
+	FileHandlerGet            FunctionCaller = "FileHandler.Get"
+	FileHandlerGetAll         FunctionCaller = "FileHandler.GetAll"
+	FileHandlerDelete         FunctionCaller = "FileHandler.Delete"
+	FileServiceUpload         FunctionCaller = "fileService.Upload"
+	FileServiceComplete       FunctionCaller = "fileService.Complete"
+	FileServiceOpenResized    FunctionCaller = "fileService.OpenResized"
+	FileServiceDelete         FunctionCaller = "fileService.Delete"
 
 	GenerateFromPassword FunctionCaller = "GenerateFromPassword"
 
-	UserHandler FunctionCaller = "UserHandler"
+	AccessLog           FunctionCaller = "middleware.AccessLog"
+	GRPCAccessLog       FunctionCaller = "grpcserver.AccessLog"
+	SlowQuery           FunctionCaller = "database.SlowQueryTracer"
+	DatabaseReplica     FunctionCaller = "database.Cluster.Reader"
+	DatabaseListener    FunctionCaller = "database.Listener"
+	DatabasePoolStats   FunctionCaller = "database.PoolStatsCollector"
+	OutboxDispatcher    FunctionCaller = "outbox.Dispatcher"
+	OutboxNATSPublisher FunctionCaller = "outbox.NATSPublisher"
+	PurgeJob            FunctionCaller = "purge.Purger"
+	CleanupJob          FunctionCaller = "purge.Cleaner"
+	WebhookDeliverer    FunctionCaller = "webhook.Deliverer"
+	WebSocketHub        FunctionCaller = "realtime.Hub"
+	WorkerPool          FunctionCaller = "worker.Pool"
+	ThumbnailGenerator  FunctionCaller = "thumbnail.Generator"
+	FeatureFlags        FunctionCaller = "featureflag.FeatureFlags"
+	QueryLog            FunctionCaller = "database.QueryLogTracer"
+	QueryCount          FunctionCaller = "database.QueryCountTracer"
+	EmployeeFirstPage   FunctionCaller = "cache.EmployeeFirstPage"
+	ExplainQuery        FunctionCaller = "database.ExplainTracer"
+
+	GraphQLHandler FunctionCaller = "GraphQLHandler"
+
+	UserHandler                   FunctionCaller = "UserHandler"
+	UserHandlerReplaceAvatar      FunctionCaller = "UserHandler.ReplaceAvatar"
+	UserHandlerReplaceCompanyLogo FunctionCaller = "UserHandler.ReplaceCompanyLogo"
 
 	DepartmentHandlerCreate FunctionCaller = "DepartmentHandler.Create"
 	DepartmentHandlerGetAll FunctionCaller = "DepartmentHandler.GetAll"
@@ -33,8 +100,38 @@ const (
 
 	DepartmentServiceCreate FunctionCaller = "DepartmentService.Create"
 	DepartmentServiceGetAll FunctionCaller = "DepartmentService.GetAll"
+	DepartmentServiceCount  FunctionCaller = "DepartmentService.Count"
 	DepartmentServicePatch  FunctionCaller = "DepartmentService.Patch"
 	DepartmentServiceDelete FunctionCaller = "DepartmentService.Delete"
+
+	DepartmentServiceGetByIDs       FunctionCaller = "DepartmentService.GetByIDs"
+	DepartmentServiceCountEmployees FunctionCaller = "DepartmentService.CountEmployees"
+
+	AuditHandlerGetAll      FunctionCaller = "AuditHandler.GetAll"
+	AuditHandlerGetAllAdmin FunctionCaller = "AuditHandler.GetAllAdmin"
+	AuditServiceGetAll      FunctionCaller = "AuditService.GetAll"
+
+	AdminHandlerPurge             FunctionCaller = "AdminHandler.Purge"
+	AdminHandlerCleanup           FunctionCaller = "AdminHandler.Cleanup"
+	AdminHandlerSetFeatureFlag    FunctionCaller = "AdminHandler.SetFeatureFlag"
+	AdminHandlerDeleteFeatureFlag FunctionCaller = "AdminHandler.DeleteFeatureFlag"
+	AdminHandlerSetLogLevel       FunctionCaller = "AdminHandler.SetLogLevel"
+
+	WebhookHandlerCreate        FunctionCaller = "WebhookHandler.Create"
+	WebhookHandlerGetAll        FunctionCaller = "WebhookHandler.GetAll"
+	WebhookHandlerDelete        FunctionCaller = "WebhookHandler.Delete"
+	WebhookHandlerTest          FunctionCaller = "WebhookHandler.Test"
+	WebhookHandlerGetDeliveries FunctionCaller = "WebhookHandler.GetDeliveries"
+
+	IntegrationHandlerCallback FunctionCaller = "IntegrationHandler.Callback"
+
+	StreamHandlerEmployees FunctionCaller = "StreamHandler.Employees"
+
+	WebSocketHandlerConnect FunctionCaller = "WebSocketHandler.Connect"
+
+	APIKeyHandlerCreate FunctionCaller = "APIKeyHandler.Create"
+	APIKeyHandlerGetAll FunctionCaller = "APIKeyHandler.GetAll"
+	APIKeyHandlerDelete FunctionCaller = "APIKeyHandler.Delete"
 )
 
 var ErrorBadRequest = errors.New("invalid request format")
@@ -46,3 +143,13 @@ var ErrorUsernameRegistered = errors.New("username is already registered")
 var ErrorInvalidLogin = errors.New("invalid email or password")
 
 var WORK_DIR string
+
+// Key context gin yang diisi oleh middleware
+const (
+	ContextKeyUserID    = "user_id"
+	ContextKeyRequestID = "request_id"
+	// ContextKeyAPIKeyID dan ContextKeyAPIKeyScopes hanya diisi jika request
+	// diautentikasi dengan API key, lihat middleware.APIKey
+	ContextKeyAPIKeyID     = "api_key_id"
+	ContextKeyAPIKeyScopes = "api_key_scopes"
+)
@@ -0,0 +1,72 @@
+package helper
+
+import (
+	"errors"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// SQLSTATE Postgres yang berarti koneksi ditolak atau diputus, bukan query
+// yang salah: class 08 (connection exception), 53300 (too_many_connections)
+// dan 57P01-57P03 (server shutdown/restart).
+const (
+	sqlStateClassConnection   = "08"
+	sqlStateTooManyConnection = "53300"
+	sqlStatePrefixShutdown    = "57P0"
+)
+
+// IsConnectionError bernilai true jika err berasal dari pool atau koneksi
+// database, bukan dari query-nya: pool kehabisan koneksi sampai context
+// habis, gagal connect, koneksi terputus, atau Postgres menolak koneksi.
+// Error seperti ini adalah masalah kapasitas database (503), bukan bug (500).
+func IsConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrServiceUnavailable) || isAcquireTimeout(err) {
+		return true
+	}
+
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		return true
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return strings.HasPrefix(pgErr.Code, sqlStateClassConnection) ||
+			strings.HasPrefix(pgErr.Code, sqlStatePrefixShutdown) ||
+			pgErr.Code == sqlStateTooManyConnection
+	}
+
+	// Timeout saat query berjalan tetap ErrQueryTimeout, lihat QueryError
+	if pgconn.Timeout(err) {
+		return false
+	}
+	var netErr *net.OpError
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// AcquireTimeoutError menandai context yang habis saat menunggu koneksi dari
+// pool, sebelum query dikirim. Error ini dibuat di tempat Acquire dipanggil
+// (database.GuardedPool), karena context.DeadlineExceeded saja tidak cukup
+// untuk membedakannya dari timeout lain.
+type AcquireTimeoutError struct {
+	Err error
+}
+
+func (e *AcquireTimeoutError) Error() string {
+	return "acquire connection: " + e.Err.Error()
+}
+
+func (e *AcquireTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+func isAcquireTimeout(err error) bool {
+	var acquireErr *AcquireTimeoutError
+	return errors.As(err, &acquireErr)
+}
@@ -0,0 +1,175 @@
+package helper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// unreachableURL menunjuk port yang tidak pernah menerima koneksi, sehingga
+// connect langsung ditolak.
+const unreachableURL = "postgres://user@127.0.0.1:1/db?connect_timeout=1"
+
+// pipeConn membuat PgConn di atas net.Pipe. Server membaca semua pesan tanpa
+// pernah menjawab, sehingga query menunggu sampai context-nya habis.
+func pipeConn(t *testing.T) (*pgconn.PgConn, net.Conn) {
+	t.Helper()
+	client, server := net.Pipe()
+	go io.Copy(io.Discard, server)
+	t.Cleanup(func() { server.Close() })
+
+	config, err := pgconn.ParseConfig(unreachableURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := pgconn.Construct(&pgconn.HijackedConn{Conn: client, Config: config, TxStatus: 'I'})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn, server
+}
+
+func expiredContext(t *testing.T) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	t.Cleanup(cancel)
+	return ctx
+}
+
+// connectRefused mengembalikan *pgconn.ConnectError asli dari connect ke
+// port yang tertutup.
+func connectRefused(t *testing.T) error {
+	t.Helper()
+	_, err := pgconn.Connect(context.Background(), unreachableURL)
+	if err == nil {
+		t.Fatal("connect to a closed port succeeded")
+	}
+	return err
+}
+
+// acquireTimeout mengembalikan error pgxpool saat context habis sebelum
+// koneksi didapat, ditandai seperti yang dilakukan database.GuardedPool.
+func acquireTimeout(t *testing.T) error {
+	t.Helper()
+	pool, err := pgxpool.New(context.Background(), unreachableURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	_, err = pool.Acquire(expiredContext(t))
+	return &AcquireTimeoutError{Err: err}
+}
+
+// queryTimeout mengembalikan error pgconn saat context habis ketika query
+// sudah terkirim lewat koneksi yang sehat.
+func queryTimeout(t *testing.T) error {
+	t.Helper()
+	conn, _ := pipeConn(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := conn.Exec(ctx, "SELECT pg_sleep(10);").ReadAll()
+	return err
+}
+
+// connectionDropped mengembalikan error pgconn saat server menutup koneksi
+// di tengah query.
+func connectionDropped(t *testing.T) error {
+	t.Helper()
+	conn, server := pipeConn(t)
+	time.AfterFunc(20*time.Millisecond, func() { server.Close() })
+	_, err := conn.Exec(context.Background(), "SELECT pg_sleep(10);").ReadAll()
+	return err
+}
+
+func TestIsConnectionError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  func(t *testing.T) error
+		want bool
+	}{
+		{name: "nil", err: func(t *testing.T) error { return nil }, want: false},
+		{name: "ErrServiceUnavailable", err: func(t *testing.T) error { return fmt.Errorf("guarded: %w", ErrServiceUnavailable) }, want: true},
+		{name: "pool acquire timeout", err: acquireTimeout, want: true},
+		{name: "connect refused", err: connectRefused, want: true},
+		{
+			name: "connect timeout",
+			err: func(t *testing.T) error {
+				_, err := pgconn.Connect(expiredContext(t), unreachableURL)
+				return err
+			},
+			want: true,
+		},
+		{name: "connection dropped", err: connectionDropped, want: true},
+		{name: "unexpected EOF", err: func(t *testing.T) error { return fmt.Errorf("read: %w", io.ErrUnexpectedEOF) }, want: true},
+		{name: "admin shutdown", err: func(t *testing.T) error { return &pgconn.PgError{Code: "57P01"} }, want: true},
+		{name: "connection failure", err: func(t *testing.T) error { return &pgconn.PgError{Code: "08006"} }, want: true},
+		{name: "too many connections", err: func(t *testing.T) error { return &pgconn.PgError{Code: "53300"} }, want: true},
+		{name: "query timeout", err: queryTimeout, want: false},
+		{name: "wrapped deadline", err: func(t *testing.T) error { return fmt.Errorf("query: %w", context.DeadlineExceeded) }, want: false},
+		// Tanpa AcquireTimeoutError, deadline bisa berasal dari mana saja
+		{name: "bare deadline", err: func(t *testing.T) error { return context.DeadlineExceeded }, want: false},
+		{name: "client cancelled", err: func(t *testing.T) error { return context.Canceled }, want: false},
+		{name: "unique violation", err: func(t *testing.T) error { return &pgconn.PgError{Code: "23505"} }, want: false},
+		{name: "syntax error", err: func(t *testing.T) error { return &pgconn.PgError{Code: "42601"} }, want: false},
+		{name: "other error", err: func(t *testing.T) error { return errors.New("scan failed") }, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.err(t)
+			if got := IsConnectionError(err); got != tt.want {
+				t.Fatalf("IsConnectionError(%v) = %t, want %t", err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryError(t *testing.T) {
+	errQuery := &pgconn.PgError{Code: "23505"}
+	tests := []struct {
+		name string
+		ctx  func(t *testing.T) context.Context
+		err  func(t *testing.T) error
+		want error
+	}{
+		{name: "nil", err: func(t *testing.T) error { return nil }, want: nil},
+		{name: "pool acquire timeout", err: acquireTimeout, want: ErrServiceUnavailable},
+		{name: "connect refused", err: connectRefused, want: ErrServiceUnavailable},
+		// Sudah ErrServiceUnavailable (breaker terbuka), tidak dibungkus lagi
+		{name: "breaker open", err: func(t *testing.T) error { return ErrServiceUnavailable }, want: ErrServiceUnavailable},
+		{name: "query timeout", err: queryTimeout, want: ErrQueryTimeout},
+		{name: "bare deadline", err: func(t *testing.T) error { return context.DeadlineExceeded }, want: ErrQueryTimeout},
+		{
+			// Context repository sudah habis walau error-nya tidak dibungkus
+			name: "scan after deadline",
+			ctx:  expiredContext,
+			err:  func(t *testing.T) error { return errors.New("scan failed") },
+			want: ErrQueryTimeout,
+		},
+		{name: "client cancelled", err: func(t *testing.T) error { return context.Canceled }, want: context.Canceled},
+		{name: "query error", err: func(t *testing.T) error { return errQuery }, want: errQuery},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.ctx != nil {
+				ctx = tt.ctx(t)
+			}
+			err := tt.err(t)
+			got := QueryError(ctx, err)
+			if !errors.Is(got, tt.want) || (tt.want == nil && got != nil) {
+				t.Fatalf("QueryError = %v, want %v", got, tt.want)
+			}
+			// Error koneksi tetap membawa penyebabnya
+			if tt.want == ErrServiceUnavailable && !errors.Is(got, err) {
+				t.Fatalf("QueryError = %v, want it to wrap %v", got, err)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package helper
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -20,3 +22,43 @@ func RollbackOrCommit(ctx context.Context, tx *pgxpool.Tx) {
 		}
 	}
 }
+
+// RollbackOnError seperti RollbackOrCommit, tetapi juga me-rollback jika
+// fungsi pemanggil mengembalikan error. Dipakai dengan named return:
+//
+//	defer helper.RollbackOnError(ctx, tx, &err)
+func RollbackOnError(ctx context.Context, tx *pgxpool.Tx, err *error) {
+	if recovered := recover(); recovered != nil {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+		panic(recovered)
+	}
+	if *err != nil {
+		// Rollback tetap dijalankan walau context request sudah habis
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+		return
+	}
+	if errCommit := tx.Commit(ctx); errCommit != nil {
+		*err = QueryError(ctx, errCommit)
+	}
+}
+
+// QueryError menerjemahkan error query karena timeout repository menjadi
+// ErrQueryTimeout dan error pool/koneksi menjadi ErrServiceUnavailable, lihat
+// IsConnectionError. Error koneksi tetap dibungkus agar penyebabnya bisa
+// dilog dan dicek dengan errors.Is/As. Pembatalan dari client
+// (context.Canceled) dan error query lainnya tidak diubah.
+func QueryError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if IsConnectionError(err) {
+		if errors.Is(err, ErrServiceUnavailable) {
+			return err
+		}
+		return fmt.Errorf("%w: %w", ErrServiceUnavailable, err)
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return ErrQueryTimeout
+	}
+	return err
+}
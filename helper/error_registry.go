@@ -0,0 +1,59 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// errorKeyInternalServer adalah messageKey untuk error yang tidak terdaftar.
+const errorKeyInternalServer = "ErrInternalServer"
+
+type registeredError struct {
+	err        error
+	code       int
+	messageKey string
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []registeredError
+)
+
+// Register mendaftarkan sentinel error beserta status HTTP dan messageKey-nya
+// di katalog pesan. Dipanggil dari init package pemilik error, mis.
+//
+//	func init() {
+//		helper.Register(ErrNotLeader, http.StatusConflict, "ErrNotLeader")
+//	}
+//
+// Lookup memakai errors.Is, sehingga error yang dibungkus fmt.Errorf("%w")
+// tetap dipetakan. Sentinel yang didaftarkan lebih dulu menang jika rantai
+// error berisi lebih dari satu sentinel.
+func Register(err error, code int, messageKey string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for _, entry := range registry {
+		if entry.err == err {
+			panic(fmt.Sprintf("helper: error %q registered twice", err))
+		}
+	}
+	registry = append(registry, registeredError{err: err, code: code, messageKey: messageKey})
+}
+
+func lookupError(err error) (registeredError, bool) {
+	if err == nil {
+		return registeredError{}, false
+	}
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for _, entry := range registry {
+		if errors.Is(err, entry.err) {
+			return entry, true
+		}
+	}
+	return registeredError{}, false
+}
@@ -0,0 +1,120 @@
+package helper
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRegisteredErrorsSurviveWrapping(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+		key  string
+	}{
+		{err: ErrNotFound, want: http.StatusNotFound, key: "ErrNotFound"},
+		{err: ErrConflictIdentityNumber, want: http.StatusConflict, key: "ErrConflictIdentityNumber"},
+		{err: ErrInvalidDepartmentId, want: http.StatusBadRequest, key: "ErrInvalidDepartmentId"},
+		{err: ErrPayloadTooLarge, want: http.StatusRequestEntityTooLarge, key: "ErrPayloadTooLarge"},
+		{err: ErrUploadExpired, want: http.StatusGone, key: "ErrUploadExpired"},
+		{err: ErrQueryTimeout, want: http.StatusGatewayTimeout, key: "ErrQueryTimeout"},
+		{err: ErrServiceUnavailable, want: http.StatusServiceUnavailable, key: "ErrServiceUnavailable"},
+	}
+	for _, tt := range tests {
+		wrappings := map[string]error{
+			"bare":         tt.err,
+			"wrapped":      fmt.Errorf("get employee EMP-1: %w", tt.err),
+			"wrapped deep": fmt.Errorf("handler: %w", fmt.Errorf("service: %w", fmt.Errorf("repository: %w", tt.err))),
+			"joined":       errors.Join(errors.New("rollback failed"), tt.err),
+		}
+		for name, err := range wrappings {
+			t.Run(tt.key+"/"+name, func(t *testing.T) {
+				if got := GetErrorStatusCode(err); got != tt.want {
+					t.Errorf("GetErrorStatusCode = %d, want %d", got, tt.want)
+				}
+				if got := GetErrorKey(err); got != tt.key {
+					t.Errorf("GetErrorKey = %q, want %q", got, tt.key)
+				}
+				// Konteks dari pembungkus tidak pernah ikut ke pesan client
+				if got, want := GetErrorMessage(err), Message(tt.key, ""); got != want || strings.Contains(got, "EMP-1") {
+					t.Errorf("GetErrorMessage = %q, want %q", got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestUnregisteredErrorIs500AndLogged(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	err := fmt.Errorf("scan employee: %w", errors.New("unexpected column"))
+	if got := GetErrorStatusCode(err); got != http.StatusInternalServerError {
+		t.Fatalf("GetErrorStatusCode = %d, want 500", got)
+	}
+	if !strings.Contains(logs.String(), "unregistered error") || !strings.Contains(logs.String(), "unexpected column") {
+		t.Fatalf("log = %q, want an unregistered error warning", logs.String())
+	}
+	if got := GetErrorKey(err); got != "ErrUnknown" {
+		t.Errorf("GetErrorKey = %q, want ErrUnknown", got)
+	}
+	if got := GetErrorMessage(err); got != Message("ErrInternalServer", "") {
+		t.Errorf("GetErrorMessage = %q, want the internal server error message", got)
+	}
+}
+
+func TestRegisterFirstSentinelWins(t *testing.T) {
+	errOuter := errors.New("test outer")
+	errInner := errors.New("test inner")
+	Register(errInner, http.StatusTeapot, "ErrTestInner")
+	Register(errOuter, http.StatusLocked, "ErrTestOuter")
+
+	// errInner didaftarkan lebih dulu, walau errOuter lebih luar di rantai
+	err := fmt.Errorf("%w: %w", errOuter, errInner)
+	if got := GetErrorStatusCode(err); got != http.StatusTeapot {
+		t.Fatalf("GetErrorStatusCode = %d, want %d", got, http.StatusTeapot)
+	}
+	// Key tanpa pesan di katalog memakai teks sentinel-nya
+	if got := GetErrorMessage(errOuter); got != "test outer" {
+		t.Fatalf("GetErrorMessage = %q, want the sentinel text", got)
+	}
+}
+
+func TestRegisterTwicePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("registering ErrNotFound twice did not panic")
+		}
+	}()
+	Register(ErrNotFound, http.StatusGone, "ErrNotFound")
+}
+
+func TestRegisteredErrorsHaveMessages(t *testing.T) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	for _, entry := range registry {
+		if strings.HasPrefix(entry.messageKey, "ErrTest") {
+			continue
+		}
+		if Message(entry.messageKey, "") == "" {
+			t.Errorf("%s has no message in the catalog", entry.messageKey)
+		}
+	}
+}
+
+func TestRegisterMessage(t *testing.T) {
+	RegisterMessage("ErrTestMessage", "first")
+	RegisterMessage("ErrTestMessage", "second")
+	if got := Message("ErrTestMessage", "fallback"); got != "second" {
+		t.Fatalf("Message = %q, want second", got)
+	}
+	if got := Message("ErrTestMissing", "fallback"); got != "fallback" {
+		t.Fatalf("Message = %q, want fallback", got)
+	}
+}
@@ -2,6 +2,7 @@ package helper
 
 import (
 	"errors"
+	"log"
 	"net/http"
 )
 
@@ -9,11 +10,32 @@ import (
 var (
 	ErrNotFound     = errors.New("record not found")
 	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
 	ErrBadRequest   = errors.New("bad request")
 	ErrConflict     = errors.New("data conflict")
 
 	ErrInvalidDepartmentId    = errors.New("invalid department id")
 	ErrConflictIdentityNumber = errors.New("identity number conflict")
+	ErrInvalidEmployeeImage   = errors.New("invalid employee image")
+
+	ErrPayloadTooLarge   = errors.New("payload too large")
+	ErrImportRowLimit    = errors.New("import row limit exceeded")
+	ErrImportDeadline    = errors.New("import deadline exceeded")
+	ErrInvalidImportFile = errors.New("invalid import file")
+	ErrExportRowLimit    = errors.New("export row limit exceeded")
+	ErrInvalidFileType   = errors.New("invalid file type")
+	ErrFileTypeMismatch  = errors.New("file type mismatch")
+	ErrInvalidImage      = errors.New("invalid image")
+	ErrImageTooLarge     = errors.New("image too large")
+	ErrInvalidImageSize  = errors.New("invalid image size")
+	ErrUploadExpired     = errors.New("upload expired")
+	ErrUploadIncomplete  = errors.New("upload incomplete")
+	ErrFileReferenced    = errors.New("file referenced")
+
+	ErrInvalidCursor = errors.New("invalid cursor")
+
+	ErrQueryTimeout       = errors.New("query timeout")
+	ErrServiceUnavailable = errors.New("service unavailable")
 
 	ErrInternalServer = errors.New("internal server error")
 )
@@ -22,42 +44,66 @@ var (
 type ErrorResponse struct {
 	Code    int    `json:"code"`
 	Message string `json:"message"`
+	// Fields berisi pesan per field untuk error validasi
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+func init() {
+	Register(ErrNotFound, http.StatusNotFound, "ErrNotFound")
+	Register(ErrUnauthorized, http.StatusUnauthorized, "ErrUnauthorized")
+	Register(ErrForbidden, http.StatusForbidden, "ErrForbidden")
+	Register(ErrBadRequest, http.StatusBadRequest, "ErrBadRequest")
+	Register(ErrConflict, http.StatusConflict, "ErrConflict")
+	Register(ErrInvalidDepartmentId, http.StatusBadRequest, "ErrInvalidDepartmentId")
+	Register(ErrConflictIdentityNumber, http.StatusConflict, "ErrConflictIdentityNumber")
+	Register(ErrInvalidEmployeeImage, http.StatusBadRequest, "ErrInvalidEmployeeImage")
+	Register(ErrPayloadTooLarge, http.StatusRequestEntityTooLarge, "ErrPayloadTooLarge")
+	Register(ErrImportRowLimit, http.StatusRequestEntityTooLarge, "ErrImportRowLimit")
+	Register(ErrImportDeadline, http.StatusRequestTimeout, "ErrImportDeadline")
+	Register(ErrInvalidImportFile, http.StatusBadRequest, "ErrInvalidImportFile")
+	Register(ErrExportRowLimit, http.StatusRequestEntityTooLarge, "ErrExportRowLimit")
+	Register(ErrInvalidFileType, http.StatusBadRequest, "ErrInvalidFileType")
+	Register(ErrFileTypeMismatch, http.StatusBadRequest, "ErrFileTypeMismatch")
+	Register(ErrInvalidImage, http.StatusBadRequest, "ErrInvalidImage")
+	Register(ErrImageTooLarge, http.StatusBadRequest, "ErrImageTooLarge")
+	Register(ErrInvalidImageSize, http.StatusBadRequest, "ErrInvalidImageSize")
+	Register(ErrUploadExpired, http.StatusGone, "ErrUploadExpired")
+	Register(ErrUploadIncomplete, http.StatusConflict, "ErrUploadIncomplete")
+	Register(ErrFileReferenced, http.StatusConflict, "ErrFileReferenced")
+	Register(ErrInvalidCursor, http.StatusBadRequest, "ErrInvalidCursor")
+	Register(ErrQueryTimeout, http.StatusGatewayTimeout, "ErrQueryTimeout")
+	Register(ErrServiceUnavailable, http.StatusServiceUnavailable, "ErrServiceUnavailable")
+	Register(ErrInternalServer, http.StatusInternalServerError, "ErrInternalServer")
 }
 
+// GetErrorStatusCode mengembalikan status HTTP dari error terdaftar di
+// rantai err, lihat Register. Error yang tidak terdaftar menjadi 500 dan
+// dicatat sebagai warning.
 func GetErrorStatusCode(err error) int {
-	switch err {
-	case ErrNotFound:
-		return http.StatusNotFound
-	case ErrUnauthorized:
-		return http.StatusUnauthorized
-	case ErrBadRequest:
-		return http.StatusBadRequest
-	case ErrConflict:
-		return http.StatusConflict
-	case ErrInvalidDepartmentId:
-		return http.StatusBadRequest
-	case ErrConflictIdentityNumber:
-		return http.StatusBadRequest
-	default:
+	entry, ok := lookupError(err)
+	if !ok {
+		log.Printf("unregistered error, responding 500: %v", err)
 		return http.StatusInternalServerError
 	}
+	return entry.code
 }
 
+// GetErrorMessage mengembalikan pesan error terdaftar dari katalog pesan.
+// Error yang tidak terdaftar tidak pernah dikirim apa adanya ke client.
 func GetErrorMessage(err error) string {
-	switch err {
-	case ErrNotFound:
-		return ErrNotFound.Error()
-	case ErrUnauthorized:
-		return ErrUnauthorized.Error()
-	case ErrBadRequest:
-		return ErrBadRequest.Error()
-	case ErrConflict:
-		return ErrConflict.Error()
-	case ErrInvalidDepartmentId:
-		return ErrInvalidDepartmentId.Error()
-	case ErrConflictIdentityNumber:
-		return ErrConflictIdentityNumber.Error()
-	default:
-		return ErrInternalServer.Error()
+	entry, ok := lookupError(err)
+	if !ok {
+		return Message(errorKeyInternalServer, ErrInternalServer.Error())
+	}
+	return Message(entry.messageKey, entry.err.Error())
+}
+
+// GetErrorKey mengembalikan messageKey error terdaftar untuk label metric,
+// atau "ErrUnknown" untuk error yang tidak dikenal.
+func GetErrorKey(err error) string {
+	entry, ok := lookupError(err)
+	if !ok {
+		return "ErrUnknown"
 	}
+	return entry.messageKey
 }
@@ -0,0 +1,67 @@
+package helper
+
+import "sync"
+
+// messageCatalog berisi pesan error untuk client per messageKey, lihat
+// Register. Saat ini hanya bahasa Inggris; terjemahan cukup menambah
+// katalog per locale tanpa mengubah pemanggil GetErrorMessage.
+var (
+	messageCatalogMu sync.RWMutex
+	messageCatalog   = map[string]string{
+		"ErrNotFound":               "record not found",
+		"ErrUnauthorized":           "unauthorized",
+		"ErrForbidden":              "forbidden",
+		"ErrBadRequest":             "bad request",
+		"ErrConflict":               "data conflict",
+		"ErrInvalidDepartmentId":    "invalid department id",
+		"ErrConflictIdentityNumber": "identity number conflict",
+		"ErrInvalidEmployeeImage":   "employeeImageUri must be a file you uploaded through POST /v1/file",
+		"ErrPayloadTooLarge":        "payload too large",
+		"ErrImportRowLimit":         "import row limit exceeded",
+		"ErrImportDeadline":         "import deadline exceeded",
+		"ErrInvalidImportFile":      "invalid import file",
+		"ErrExportRowLimit":         "too many employees for an xlsx export, use format=csv instead",
+		"ErrInvalidFileType":        "file must be a jpeg or png image",
+		"ErrFileTypeMismatch":       "file name or content type does not match the file content",
+		"ErrInvalidImage":           "file is not a readable jpeg or png image",
+		"ErrImageTooLarge":          "image width or height is larger than allowed",
+		"ErrInvalidImageSize":       "w and h must be one of the allowed image sizes",
+		"ErrUploadExpired":          "upload url has expired, request a new one",
+		"ErrUploadIncomplete":       "file has not been uploaded yet",
+		"ErrFileReferenced":         "file is still used by an employee or manager profile",
+		"ErrInvalidCursor":          "invalid cursor",
+		"ErrQueryTimeout":           "query timeout",
+		"ErrServiceUnavailable":     "service unavailable",
+		"ErrInternalServer":         "internal server error",
+		"ErrNotLeader":              "purge is already running on another instance",
+		"ErrQueueFull":              "server is busy, try again later",
+		"ErrUnknownCleanupTable":    "unknown cleanup table",
+		"ErrStorageUnavailable":     "file storage is unavailable, try again later",
+		"ErrStorageFailed":          "file storage request failed",
+		"ErrInvalidStorageKey":      "invalid file storage key",
+		"ErrInvalidSignature":       "missing or invalid request signature",
+		"ErrSignatureExpired":       "request timestamp is too old or too far in the future",
+		"ErrCallbackReplayed":       "callback has already been received",
+		"ErrInvalidAPIKey":          "invalid api key",
+		"ErrAPIKeyExpired":          "api key has expired",
+		"ErrInsufficientScope":      "api key does not have the scope required by this endpoint",
+	}
+)
+
+// RegisterMessage menambah atau mengganti pesan untuk messageKey.
+func RegisterMessage(messageKey, message string) {
+	messageCatalogMu.Lock()
+	defer messageCatalogMu.Unlock()
+	messageCatalog[messageKey] = message
+}
+
+// Message mengembalikan pesan untuk messageKey, atau fallback jika key belum
+// ada di katalog.
+func Message(messageKey, fallback string) string {
+	messageCatalogMu.RLock()
+	defer messageCatalogMu.RUnlock()
+	if message, ok := messageCatalog[messageKey]; ok {
+		return message
+	}
+	return fallback
+}
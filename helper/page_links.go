@@ -0,0 +1,40 @@
+package helper
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// PageLinks menyusun header Link (RFC 8288) untuk halaman first, prev, next
+// dan last. URL-nya absolut dengan scheme dan host dari base (lihat
+// middleware.ResolveBaseURL), dan mempertahankan query lain (filter dan
+// sortBy) selain limit dan offset. prev tidak ada di halaman pertama dan
+// next tidak ada di halaman terakhir.
+func PageLinks(base url.URL, u *url.URL, limit, offset int, total int64) string {
+	link := func(rel string, offset int64) string {
+		query := u.Query()
+		query.Set("limit", strconv.Itoa(limit))
+		query.Set("offset", strconv.FormatInt(offset, 10))
+		target := base
+		target.Path = u.Path
+		target.RawQuery = query.Encode()
+		return fmt.Sprintf(`<%s>; rel="%s"`, target.String(), rel)
+	}
+
+	pageSize, current := int64(limit), int64(offset)
+	var last int64
+	if total > 0 {
+		last = (total - 1) / pageSize * pageSize
+	}
+	links := []string{link("first", 0)}
+	if current > 0 {
+		links = append(links, link("prev", max(current-pageSize, 0)))
+	}
+	if current+pageSize < total {
+		links = append(links, link("next", current+pageSize))
+	}
+	links = append(links, link("last", last))
+	return strings.Join(links, ", ")
+}
@@ -0,0 +1,84 @@
+package helper
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestPageLinks(t *testing.T) {
+	base := url.URL{Scheme: "https", Host: "api.example.com"}
+	tests := []struct {
+		name          string
+		rawURL        string
+		limit, offset int
+		total         int64
+		want          string
+	}{
+		{
+			name:   "first page",
+			rawURL: "/v1/employee?limit=5&offset=0",
+			limit:  5, offset: 0, total: 12,
+			want: `<https://api.example.com/v1/employee?limit=5&offset=0>; rel="first", ` +
+				`<https://api.example.com/v1/employee?limit=5&offset=5>; rel="next", ` +
+				`<https://api.example.com/v1/employee?limit=5&offset=10>; rel="last"`,
+		},
+		{
+			name:   "middle page keeps filters",
+			rawURL: "/v1/employee?gender=female&limit=5&offset=5&sortBy=name",
+			limit:  5, offset: 5, total: 12,
+			want: `<https://api.example.com/v1/employee?gender=female&limit=5&offset=0&sortBy=name>; rel="first", ` +
+				`<https://api.example.com/v1/employee?gender=female&limit=5&offset=0&sortBy=name>; rel="prev", ` +
+				`<https://api.example.com/v1/employee?gender=female&limit=5&offset=10&sortBy=name>; rel="next", ` +
+				`<https://api.example.com/v1/employee?gender=female&limit=5&offset=10&sortBy=name>; rel="last"`,
+		},
+		{
+			name:   "last page",
+			rawURL: "/v1/employee?limit=5&offset=10",
+			limit:  5, offset: 10, total: 12,
+			want: `<https://api.example.com/v1/employee?limit=5&offset=0>; rel="first", ` +
+				`<https://api.example.com/v1/employee?limit=5&offset=5>; rel="prev", ` +
+				`<https://api.example.com/v1/employee?limit=5&offset=10>; rel="last"`,
+		},
+		{
+			name:   "total is a multiple of limit",
+			rawURL: "/v1/department",
+			limit:  5, offset: 0, total: 10,
+			want: `<https://api.example.com/v1/department?limit=5&offset=0>; rel="first", ` +
+				`<https://api.example.com/v1/department?limit=5&offset=5>; rel="next", ` +
+				`<https://api.example.com/v1/department?limit=5&offset=5>; rel="last"`,
+		},
+		{
+			name:   "empty result",
+			rawURL: "/v1/department?name=abc",
+			limit:  5, offset: 0, total: 0,
+			want: `<https://api.example.com/v1/department?limit=5&name=abc&offset=0>; rel="first", ` +
+				`<https://api.example.com/v1/department?limit=5&name=abc&offset=0>; rel="last"`,
+		},
+		{
+			name:   "offset past the end",
+			rawURL: "/v1/employee?limit=5&offset=3",
+			limit:  5, offset: 3, total: 2,
+			want: `<https://api.example.com/v1/employee?limit=5&offset=0>; rel="first", ` +
+				`<https://api.example.com/v1/employee?limit=5&offset=0>; rel="prev", ` +
+				`<https://api.example.com/v1/employee?limit=5&offset=0>; rel="last"`,
+		},
+		{
+			name:   "values are encoded",
+			rawURL: "/v1/employee?name=a%26b+c",
+			limit:  5, offset: 0, total: 1,
+			want: `<https://api.example.com/v1/employee?limit=5&name=a%26b+c&offset=0>; rel="first", ` +
+				`<https://api.example.com/v1/employee?limit=5&name=a%26b+c&offset=0>; rel="last"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.rawURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := PageLinks(base, u, tt.limit, tt.offset, tt.total); got != tt.want {
+				t.Fatalf("PageLinks() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
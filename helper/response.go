@@ -1,33 +1,115 @@
 package helper
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+
 	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/reporting"
 )
 
+// Response adalah envelope semua response API. Response sukses berisi data
+// (dan meta jika ada), response gagal berisi errors, ditambah data hanya
+// jika ada hasil parsial (mis. laporan import yang berhenti di tengah).
+//
+//	{ "data": ..., "meta": ... }
+//	{ "errors": { "code": 400, "message": "...", "fields": { "name": "..." } } }
 type Response struct {
-	Data  interface{} `json:"data,omitempty"`
-	Error interface{} `json:"error,omitempty"`
+	Data   interface{}    `json:"data,omitempty"`
+	Errors *ErrorResponse `json:"errors,omitempty"`
+	Meta   interface{}    `json:"meta,omitempty"`
+}
+
+// fieldErrorer diimplementasikan error validasi per field, lihat
+// validation.FieldErrors.
+type fieldErrorer interface {
+	FieldErrors() map[string]string
+}
+
+// OK membuat response sukses berisi data.
+func OK(data interface{}) *Response {
+	return &Response{Data: data}
+}
+
+// Created sama dengan OK, untuk response 201.
+func Created(data interface{}) *Response {
+	return &Response{Data: data}
+}
+
+// WithMeta menambahkan meta (mis. total dan cursor) ke response.
+func (r *Response) WithMeta(meta interface{}) *Response {
+	r.Meta = meta
+	return r
 }
 
-func NewResponse(data interface{}, error error) *Response {
+// WithData menambahkan hasil parsial ke response gagal.
+func (r *Response) WithData(data interface{}) *Response {
+	r.Data = data
+	return r
+}
+
+// FieldErrors membuat response 400 dengan pesan per field.
+func FieldErrors(fields map[string]string) *Response {
+	return &Response{Errors: &ErrorResponse{
+		Code:    http.StatusBadRequest,
+		Message: ErrBadRequest.Error(),
+		Fields:  fields,
+	}}
+}
 
-	if error != nil {
-		return &Response{
-			Data:  data,
-			Error: error.Error(),
-		}
+// Error membuat response gagal dengan status tertentu. Error validasi per
+// field ikut menyertakan fields. Error terdaftar memakai pesan dari katalog,
+// dan untuk status 5xx pesan error asli tidak pernah dikirim ke client,
+// lihat GetErrorMessage.
+func Error(status int, err error) *Response {
+	var fieldErrors fieldErrorer
+	if errors.As(err, &fieldErrors) {
+		response := FieldErrors(fieldErrors.FieldErrors())
+		response.Errors.Code = status
+		return response
 	}
 
-	return &Response{
-		data,
-		error,
+	message := err.Error()
+	if _, registered := lookupError(err); registered || status >= http.StatusInternalServerError {
+		message = GetErrorMessage(err)
 	}
+	return &Response{Errors: &ErrorResponse{Code: status, Message: message}}
+}
+
+// FromError memetakan err ke status dan response-nya: error validasi per
+// field menjadi 400, selain itu lihat GetErrorStatusCode. Dipakai langsung
+// sebagai argumen ctx.JSON(helper.FromError(err)).
+func FromError(err error) (int, *Response) {
+	var fieldErrors fieldErrorer
+	if errors.As(err, &fieldErrors) {
+		return http.StatusBadRequest, Error(http.StatusBadRequest, err)
+	}
+	status := GetErrorStatusCode(err)
+	return status, Error(status, err)
 }
 
 func FallbackResponse(ctx *gin.Context) {
 	err := recover()
 	if err != nil {
-		ctx.JSON(GetErrorStatusCode(ErrInternalServer), NewResponse(nil, ErrInternalServer))
+		ReportPanic(ctx, err)
+		ctx.JSON(FromError(ErrInternalServer))
 		return
 	}
 }
+
+// ReportPanic mengirim panic yang di-recover ke error reporter beserta
+// metadata request.
+func ReportPanic(ctx *gin.Context, recovered any) {
+	err, ok := recovered.(error)
+	if !ok {
+		err = fmt.Errorf("panic: %v", recovered)
+	}
+	reporting.Default().Report(ctx, err, map[string]any{
+		"route":      ctx.FullPath(),
+		"method":     ctx.Request.Method,
+		"manager_id": ctx.GetString(ContextKeyUserID),
+		"request_id": ctx.GetString(ContextKeyRequestID),
+		"panic":      true,
+	})
+}
@@ -0,0 +1,150 @@
+package helper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/reporting"
+)
+
+// fieldError meniru validation.FieldErrors tanpa import cycle.
+type fieldError map[string]string
+
+func (e fieldError) FieldErrors() map[string]string { return e }
+func (e fieldError) Error() string                  { return "validation failed" }
+
+func encode(t *testing.T, response *Response) string {
+	t.Helper()
+	body, err := json.Marshal(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestResponseEnvelope(t *testing.T) {
+	tests := []struct {
+		name     string
+		response *Response
+		want     string
+	}{
+		{name: "ok", response: OK(map[string]string{"id": "1"}), want: `{"data":{"id":"1"}}`},
+		{name: "created", response: Created([]int{1}), want: `{"data":[1]}`},
+		{name: "ok with meta", response: OK([]int{}).WithMeta(map[string]int{"total": 0}), want: `{"data":[],"meta":{"total":0}}`},
+		{
+			name:     "field errors",
+			response: FieldErrors(map[string]string{"name": "name is a required field"}),
+			want:     `{"errors":{"code":400,"message":"` + ErrBadRequest.Error() + `","fields":{"name":"name is a required field"}}}`,
+		},
+		{
+			name:     "client error keeps its message",
+			response: Error(http.StatusUnauthorized, errors.New("token is expired")),
+			want:     `{"errors":{"code":401,"message":"token is expired"}}`,
+		},
+		{
+			name:     "partial data on error",
+			response: Error(http.StatusConflict, errors.New("partly failed")).WithData([]int{1}),
+			want:     `{"data":[1],"errors":{"code":409,"message":"partly failed"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := encode(t, tt.response); got != tt.want {
+				t.Fatalf("body = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromError(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantMessage string
+		wantFields  bool
+	}{
+		{name: "field errors", err: fieldError{"gender": "gender is a required field"}, wantStatus: http.StatusBadRequest, wantMessage: ErrBadRequest.Error(), wantFields: true},
+		{name: "wrapped field errors", err: fmt.Errorf("create: %w", fieldError{"gender": "x"}), wantStatus: http.StatusBadRequest, wantMessage: ErrBadRequest.Error(), wantFields: true},
+		{name: "registered", err: fmt.Errorf("employee EMP-1: %w", ErrNotFound), wantStatus: http.StatusNotFound, wantMessage: GetErrorMessage(ErrNotFound)},
+		{name: "conflict", err: ErrConflict, wantStatus: http.StatusConflict, wantMessage: GetErrorMessage(ErrConflict)},
+		{name: "unregistered", err: errors.New(`pq: relation "employees" does not exist`), wantStatus: http.StatusInternalServerError, wantMessage: GetErrorMessage(ErrInternalServer)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, response := FromError(tt.err)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", status, tt.wantStatus)
+			}
+			if response.Data != nil || response.Errors == nil {
+				t.Fatalf("response = %+v, want only errors", response)
+			}
+			if response.Errors.Code != status || response.Errors.Message != tt.wantMessage {
+				t.Fatalf("errors = %+v, want code %d message %q", response.Errors, status, tt.wantMessage)
+			}
+			if (response.Errors.Fields != nil) != tt.wantFields {
+				t.Fatalf("fields = %v, want fields %t", response.Errors.Fields, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestServerErrorDoesNotLeakMessage(t *testing.T) {
+	response := Error(http.StatusBadGateway, errors.New("dial tcp 10.0.0.5:5432: connection refused"))
+	if response.Errors.Message != GetErrorMessage(ErrInternalServer) {
+		t.Fatalf("message = %q, want the generic internal error message", response.Errors.Message)
+	}
+}
+
+// panicReporter menyimpan error dan fields terakhir yang dilaporkan.
+type panicReporter struct {
+	err    error
+	fields map[string]any
+}
+
+func (r *panicReporter) Report(ctx context.Context, err error, fields map[string]any) {
+	r.err, r.fields = err, fields
+}
+
+func TestFallbackResponseReportsPanic(t *testing.T) {
+	reporter := &panicReporter{}
+	previous := reporting.Default()
+	reporting.SetDefault(reporter)
+	t.Cleanup(func() { reporting.SetDefault(previous) })
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/v1/employee/:identityNumber", func(c *gin.Context) {
+		c.Set(ContextKeyUserID, "manager-1")
+		c.Set(ContextKeyRequestID, "req-1")
+		defer FallbackResponse(c)
+		panic("nil map")
+	})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/employee/EMP-1", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+	if reporter.err == nil || reporter.err.Error() != "panic: nil map" {
+		t.Fatalf("reported error = %v, want panic: nil map", reporter.err)
+	}
+	want := map[string]any{
+		"route":      "/v1/employee/:identityNumber",
+		"method":     http.MethodGet,
+		"manager_id": "manager-1",
+		"request_id": "req-1",
+		"panic":      true,
+	}
+	for key, value := range want {
+		if reporter.fields[key] != value {
+			t.Fatalf("fields[%s] = %v, want %v", key, reporter.fields[key], value)
+		}
+	}
+}
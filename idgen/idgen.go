@@ -0,0 +1,29 @@
+package idgen
+
+import (
+	"github.com/google/uuid"
+	"github.com/samber/do/v2"
+)
+
+// IDGenerator membuat id baru untuk entity yang id-nya dibuat server.
+type IDGenerator interface {
+	NewID() string
+}
+
+// uuidV7 membuat UUIDv7: diawali timestamp milidetik sehingga id terurut
+// berdasarkan waktu pembuatan, dan monoton dalam satu proses walau dibuat
+// di milidetik yang sama.
+type uuidV7 struct{}
+
+func NewUUIDv7() IDGenerator {
+	return uuidV7{}
+}
+
+func NewInject(i do.Injector) (IDGenerator, error) {
+	return NewUUIDv7(), nil
+}
+
+// NewID panic jika sumber random sistem gagal, sama seperti uuid.New.
+func (uuidV7) NewID() string {
+	return uuid.Must(uuid.NewV7()).String()
+}
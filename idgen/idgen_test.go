@@ -0,0 +1,40 @@
+package idgen
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNewIDIsUUIDv7(t *testing.T) {
+	id := NewUUIDv7().NewID()
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		t.Fatalf("NewID() = %q, not a UUID: %v", id, err)
+	}
+	if parsed.Version() != 7 || parsed.Variant() != uuid.RFC4122 {
+		t.Fatalf("NewID() = %q, version %d variant %s, want version 7 RFC4122", id, parsed.Version(), parsed.Variant())
+	}
+	if parsed.String() != id {
+		t.Fatalf("NewID() = %q, want canonical lowercase form %q", id, parsed.String())
+	}
+}
+
+func TestNewIDIsMonotonic(t *testing.T) {
+	generator := NewUUIDv7()
+	// Cukup banyak agar sebagian besar id dibuat di milidetik yang sama
+	const n = 10_000
+	previous := generator.NewID()
+	seen := map[string]bool{previous: true}
+	for range n {
+		id := generator.NewID()
+		if id <= previous {
+			t.Fatalf("NewID() = %q after %q, want strictly increasing ids", id, previous)
+		}
+		if seen[id] {
+			t.Fatalf("NewID() returned %q twice", id)
+		}
+		seen[id] = true
+		previous = id
+	}
+}
@@ -2,30 +2,21 @@ package infrastructure
 
 import (
 	"context"
+
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsConfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
-	"os"
-)
-
-var (
-	AWS_ACCESS_KEY_ID     = os.Getenv("AWS_ACCESS_KEY_ID")
-	AWS_SECRET_ACCESS_KEY = os.Getenv("AWS_SECRET_ACCESS_KEY")
-	AWS_REGION            = os.Getenv("AWS_REGION")
 )
 
-func NewAws() aws.Config {
-	sdkConfig, err := awsConfig.LoadDefaultConfig(
-		context.Background(),
-		awsConfig.WithRegion(AWS_REGION),
-		awsConfig.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
-			AWS_ACCESS_KEY_ID,
-			AWS_SECRET_ACCESS_KEY,
-			"",
-		)),
-	)
-	if err != nil {
-		panic(err)
+// NewAws memuat konfigurasi AWS SDK untuk region. Jika accessKeyID kosong,
+// credential diambil dari default chain AWS (env, shared config, atau IAM
+// role).
+func NewAws(ctx context.Context, region, accessKeyID, secretAccessKey string) (aws.Config, error) {
+	options := []func(*awsConfig.LoadOptions) error{awsConfig.WithRegion(region)}
+	if accessKeyID != "" {
+		options = append(options, awsConfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, ""),
+		))
 	}
-	return sdkConfig
+	return awsConfig.LoadDefaultConfig(ctx, options...)
 }
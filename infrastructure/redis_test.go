@@ -0,0 +1,29 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestRedisClientShutdown(t *testing.T) {
+	client := &RedisClient{Client: redis.NewClient(&redis.Options{Addr: "127.0.0.1:1"})}
+	if err := client.Shutdown(); err != nil {
+		t.Fatalf("Shutdown error = %v", err)
+	}
+	if err := client.HealthCheck(context.Background()); !errors.Is(err, redis.ErrClosed) {
+		t.Fatalf("HealthCheck after Shutdown error = %v, want %v", err, redis.ErrClosed)
+	}
+}
+
+func TestRedisClientWithoutRedisURL(t *testing.T) {
+	client := &RedisClient{}
+	if err := client.Shutdown(); err != nil {
+		t.Fatalf("Shutdown error = %v", err)
+	}
+	if err := client.HealthCheck(context.Background()); err != nil {
+		t.Fatalf("HealthCheck error = %v", err)
+	}
+}
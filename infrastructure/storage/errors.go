@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+var (
+	// ErrUnavailable dikembalikan jika storage tidak bisa dihubungi, timeout,
+	// atau sedang membatasi request. Request yang sama boleh diulang.
+	ErrUnavailable = errors.New("file storage is unavailable")
+	// ErrFailed dikembalikan jika storage menolak request, mis. credential
+	// atau bucket salah. Mengulang request tidak akan membantu.
+	ErrFailed = errors.New("file storage request failed")
+	// ErrInvalidKey dikembalikan jika key kosong, absolut, atau keluar dari
+	// direktori storage lewat "..".
+	ErrInvalidKey = errors.New("invalid file storage key")
+)
+
+func init() {
+	helper.Register(ErrUnavailable, http.StatusServiceUnavailable, "ErrStorageUnavailable")
+	helper.Register(ErrFailed, http.StatusBadGateway, "ErrStorageFailed")
+	helper.Register(ErrInvalidKey, http.StatusBadRequest, "ErrInvalidStorageKey")
+}
+
+// mapError memetakan error AWS SDK ke error terdaftar. Error aslinya hanya
+// ikut sebagai teks untuk log, sehingga detail AWS tidak sampai ke client.
+func mapError(op, key string, err error) error {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && (apiErr.ErrorCode() == "NoSuchKey" || apiErr.ErrorCode() == "NotFound") {
+		return fmt.Errorf("%w: storage %s %s: %v", helper.ErrNotFound, op, key, err)
+	}
+
+	// Status 0 berarti tidak ada response: koneksi gagal, DNS, atau deadline
+	status := 0
+	var responseErr *awshttp.ResponseError
+	if errors.As(err, &responseErr) {
+		status = responseErr.HTTPStatusCode()
+	}
+	switch {
+	case status == 0, status == http.StatusTooManyRequests, status >= http.StatusInternalServerError:
+		return fmt.Errorf("%w: storage %s %s: %v", ErrUnavailable, op, key, err)
+	default:
+		return fmt.Errorf("%w: storage %s %s: %v", ErrFailed, op, key, err)
+	}
+}
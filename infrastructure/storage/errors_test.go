@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+// responseError meniru error AWS SDK untuk response dengan status tersebut.
+func responseError(status int, err error) error {
+	return &awshttp.ResponseError{
+		ResponseError: &smithyhttp.ResponseError{
+			Response: &smithyhttp.Response{Response: &http.Response{StatusCode: status}},
+			Err:      err,
+		},
+	}
+}
+
+func TestMapError(t *testing.T) {
+	errDial := errors.New("dial tcp: connection refused")
+	for _, tt := range []struct {
+		name string
+		err  error
+		want error
+	}{
+		{name: "nil", err: nil, want: nil},
+		{name: "cancelled", err: context.Canceled, want: context.Canceled},
+		{name: "no such key", err: responseError(http.StatusNotFound, &smithy.GenericAPIError{Code: "NoSuchKey"}), want: helper.ErrNotFound},
+		{name: "head not found", err: responseError(http.StatusNotFound, &smithy.GenericAPIError{Code: "NotFound"}), want: helper.ErrNotFound},
+		{name: "no response", err: errDial, want: ErrUnavailable},
+		{name: "deadline", err: context.DeadlineExceeded, want: ErrUnavailable},
+		{name: "slow down", err: responseError(http.StatusServiceUnavailable, &smithy.GenericAPIError{Code: "SlowDown"}), want: ErrUnavailable},
+		{name: "too many requests", err: responseError(http.StatusTooManyRequests, errDial), want: ErrUnavailable},
+		{name: "internal error", err: responseError(http.StatusInternalServerError, &smithy.GenericAPIError{Code: "InternalError"}), want: ErrUnavailable},
+		{name: "access denied", err: responseError(http.StatusForbidden, &smithy.GenericAPIError{Code: "AccessDenied"}), want: ErrFailed},
+		{name: "no such bucket", err: responseError(http.StatusNotFound, &smithy.GenericAPIError{Code: "NoSuchBucket"}), want: ErrFailed},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mapError("get", "a.txt", tt.err)
+			if tt.want == nil {
+				if got != nil {
+					t.Fatalf("mapError = %v, want nil", got)
+				}
+				return
+			}
+			if !errors.Is(got, tt.want) {
+				t.Fatalf("mapError = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
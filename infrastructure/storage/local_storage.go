@@ -0,0 +1,201 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/levensspel/go-gin-template/domain"
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+// LocalStorage menyimpan object sebagai file di bawah satu direktori, untuk
+// development dan deployment satu instance tanpa S3. Object dibaca client
+// lewat GET /files/:id, bukan langsung dari direktorinya.
+type LocalStorage struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalStorage membuat dir jika belum ada. baseURL adalah awal URL
+// object, lihat URL.
+func NewLocalStorage(dir string, baseURL string) (*LocalStorage, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(absDir, 0o750); err != nil {
+		return nil, err
+	}
+	return &LocalStorage{dir: absDir, baseURL: baseURL}, nil
+}
+
+// Put menulis body ke file sementara di direktori yang sama lalu me-rename
+// file tersebut, sehingga pembaca tidak pernah melihat file setengah jadi
+// dan Put bersamaan ke key yang sama berakhir dengan salah satu isi utuh.
+// contentType tidak disimpan; Get menentukannya dari ekstensi key.
+func (l *LocalStorage) Put(
+	ctx context.Context,
+	key string,
+	contentType string,
+	body io.Reader,
+	size int64,
+	isPublic bool,
+) (string, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return "", fmt.Errorf("%w: storage put %s: %v", ErrUnavailable, key, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return "", fmt.Errorf("%w: storage put %s: %v", ErrUnavailable, key, err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err := io.Copy(tmp, contextReader{ctx: ctx, r: body}); err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", fmt.Errorf("%w: storage put %s: %v", ErrUnavailable, key, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return "", fmt.Errorf("%w: storage put %s: %v", ErrUnavailable, key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("%w: storage put %s: %v", ErrUnavailable, key, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("%w: storage put %s: %v", ErrUnavailable, key, err)
+	}
+	committed = true
+	return l.URL(key), nil
+}
+
+func (l *LocalStorage) Get(ctx context.Context, key string) (*domain.Object, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: storage get %s", helper.ErrNotFound, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: storage get %s: %v", ErrUnavailable, key, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("%w: storage get %s: %v", ErrUnavailable, key, err)
+	}
+	if !info.Mode().IsRegular() {
+		file.Close()
+		return nil, fmt.Errorf("%w: storage get %s", helper.ErrNotFound, key)
+	}
+	return &domain.Object{Body: file, ObjectInfo: objectInfo(path, info)}, nil
+}
+
+func (l *LocalStorage) Stat(ctx context.Context, key string) (*domain.ObjectInfo, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && !info.Mode().IsRegular()) {
+		return nil, fmt.Errorf("%w: storage stat %s", helper.ErrNotFound, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: storage stat %s: %v", ErrUnavailable, key, err)
+	}
+	result := objectInfo(path, info)
+	return &result, nil
+}
+
+func objectInfo(path string, info fs.FileInfo) domain.ObjectInfo {
+	contentType := mime.TypeByExtension(filepath.Ext(path))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return domain.ObjectInfo{
+		ContentType: contentType,
+		Size:        info.Size(),
+		// File hanya berubah lewat rename, sehingga waktu dan ukuran cukup
+		// untuk membedakan isinya
+		ETag:    fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()),
+		ModTime: info.ModTime(),
+	}
+}
+
+func (l *LocalStorage) Delete(ctx context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: storage delete %s: %v", ErrUnavailable, key, err)
+	}
+	return nil
+}
+
+// SignedURL tidak didukung; object LocalStorage hanya dibaca lewat
+// GET /files/:id.
+func (l *LocalStorage) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	return "", fmt.Errorf("%w: storage sign %s: signed URLs are not supported by the local driver", ErrFailed, key)
+}
+
+func (l *LocalStorage) URL(key string) string {
+	if l.baseURL == "" {
+		return "file://" + filepath.ToSlash(filepath.Join(l.dir, filepath.FromSlash(key)))
+	}
+	return strings.TrimSuffix(l.baseURL, "/") + "/" + escapeKey(key)
+}
+
+// path mengubah key menjadi path di bawah l.dir. Key memakai "/" sebagai
+// pemisah; key absolut, kosong, berisi "\", NUL, atau segmen ".." ditolak
+// sebelum di-join, sehingga tidak bisa keluar dari l.dir.
+func (l *LocalStorage) path(key string) (string, error) {
+	if key == "" || strings.ContainsAny(key, "\\\x00") {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+		}
+	}
+	local := filepath.FromSlash(key)
+	if !filepath.IsLocal(local) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return filepath.Join(l.dir, local), nil
+}
+
+// contextReader berhenti membaca saat ctx selesai, sehingga upload yang
+// dibatalkan tidak terus ditulis ke disk.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
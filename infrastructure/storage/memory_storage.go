@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/levensspel/go-gin-template/domain"
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+// MemoryStorage menyimpan object di memori. Isinya hilang saat restart,
+// sehingga hanya untuk development (MODE=DEBUG) dan test.
+type MemoryStorage struct {
+	baseURL string
+
+	mu      sync.RWMutex
+	objects map[string]memoryObject
+}
+
+type memoryObject struct {
+	content     []byte
+	contentType string
+	etag        string
+	modTime     time.Time
+}
+
+func (o memoryObject) info() domain.ObjectInfo {
+	return domain.ObjectInfo{
+		ContentType: o.contentType,
+		Size:        int64(len(o.content)),
+		ETag:        o.etag,
+		ModTime:     o.modTime,
+	}
+}
+
+// readSeekNopCloser membuat Body bisa di-Seek untuk Range request.
+type readSeekNopCloser struct {
+	*bytes.Reader
+}
+
+func (readSeekNopCloser) Close() error { return nil }
+
+// NewMemoryStorage membuat storage kosong. baseURL adalah awal URL object,
+// default memory://.
+func NewMemoryStorage(baseURL string) *MemoryStorage {
+	if baseURL == "" {
+		baseURL = "memory://"
+	}
+	return &MemoryStorage{
+		baseURL: strings.TrimSuffix(baseURL, "/") + "/",
+		objects: make(map[string]memoryObject),
+	}
+}
+
+func (m *MemoryStorage) Put(
+	ctx context.Context,
+	key string,
+	contentType string,
+	body io.Reader,
+	size int64,
+	isPublic bool,
+) (string, error) {
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(content)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[key] = memoryObject{
+		content:     content,
+		contentType: contentType,
+		etag:        `"` + hex.EncodeToString(sum[:16]) + `"`,
+		modTime:     time.Now(),
+	}
+	return m.URL(key), nil
+}
+
+func (m *MemoryStorage) Get(ctx context.Context, key string) (*domain.Object, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	object, ok := m.objects[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: storage get %s", helper.ErrNotFound, key)
+	}
+	return &domain.Object{
+		Body:       readSeekNopCloser{bytes.NewReader(object.content)},
+		ObjectInfo: object.info(),
+	}, nil
+}
+
+func (m *MemoryStorage) Stat(ctx context.Context, key string) (*domain.ObjectInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	object, ok := m.objects[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: storage stat %s", helper.ErrNotFound, key)
+	}
+	info := object.info()
+	return &info, nil
+}
+
+func (m *MemoryStorage) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.objects, key)
+	return nil
+}
+
+// SignedURL tidak benar-benar ditandatangani; expires hanya informasi.
+func (m *MemoryStorage) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	m.mu.RLock()
+	_, ok := m.objects[key]
+	m.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("%w: storage sign %s", helper.ErrNotFound, key)
+	}
+	return fmt.Sprintf("%s?expires=%d", m.URL(key), time.Now().Add(expiry).Unix()), nil
+}
+
+func (m *MemoryStorage) URL(key string) string {
+	return m.baseURL + escapeKey(key)
+}
@@ -0,0 +1,210 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/domain"
+	"github.com/levensspel/go-gin-template/infrastructure"
+)
+
+// S3Storage menyimpan object di AWS S3 atau storage S3-compatible seperti
+// MinIO, lihat config.StorageConfig.Endpoint.
+type S3Storage struct {
+	client   *s3.Client
+	uploader *manager.Uploader
+	presign  *s3.PresignClient
+	config   *config.StorageConfig
+}
+
+func NewS3Storage(ctx context.Context, config *config.StorageConfig) (*S3Storage, error) {
+	if config.Bucket == "" {
+		return nil, errors.New("AWS_BUCKET is required for STORAGE_DRIVER=s3")
+	}
+	region := config.Region
+	if region == "" {
+		// MinIO tetap butuh region untuk signature
+		region = "us-east-1"
+	}
+	sdkConfig, err := infrastructure.NewAws(ctx, region, config.AccessKeyID, config.SecretAccessKey)
+	if err != nil {
+		return nil, err
+	}
+
+	client := s3.NewFromConfig(sdkConfig, func(o *s3.Options) {
+		if config.Endpoint != "" {
+			o.BaseEndpoint = aws.String(config.Endpoint)
+		}
+		o.UsePathStyle = config.UsePathStyle
+	})
+	return &S3Storage{
+		client: client,
+		// Uploader membaca body per part (default 5MiB) dan memakai
+		// multipart upload untuk file yang lebih besar, sehingga file tidak
+		// pernah ditampung seluruhnya di memori.
+		uploader: manager.NewUploader(client),
+		presign:  s3.NewPresignClient(client),
+		config:   config,
+	}, nil
+}
+
+func (s *S3Storage) Put(
+	ctx context.Context,
+	key string,
+	contentType string,
+	body io.Reader,
+	size int64,
+	isPublic bool,
+) (string, error) {
+	input := &s3.PutObjectInput{
+		Bucket:      aws.String(s.config.Bucket),
+		Key:         aws.String(s.objectKey(key)),
+		Body:        body,
+		ContentType: aws.String(contentType),
+	}
+	if size >= 0 {
+		input.ContentLength = aws.Int64(size)
+	}
+	if isPublic {
+		input.ACL = types.ObjectCannedACLPublicRead
+	}
+	if _, err := s.uploader.Upload(ctx, input); err != nil {
+		return "", mapError("put", key, err)
+	}
+	return s.URL(key), nil
+}
+
+func (s *S3Storage) Get(ctx context.Context, key string) (*domain.Object, error) {
+	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(s.objectKey(key)),
+	})
+	if err != nil {
+		return nil, mapError("get", key, err)
+	}
+	return &domain.Object{
+		Body: output.Body,
+		ObjectInfo: domain.ObjectInfo{
+			ContentType: aws.ToString(output.ContentType),
+			Size:        aws.ToInt64(output.ContentLength),
+			ETag:        aws.ToString(output.ETag),
+			ModTime:     aws.ToTime(output.LastModified),
+		},
+	}, nil
+}
+
+func (s *S3Storage) Stat(ctx context.Context, key string) (*domain.ObjectInfo, error) {
+	output, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(s.objectKey(key)),
+	})
+	if err != nil {
+		return nil, mapError("stat", key, err)
+	}
+	return &domain.ObjectInfo{
+		ContentType: aws.ToString(output.ContentType),
+		Size:        aws.ToInt64(output.ContentLength),
+		ETag:        aws.ToString(output.ETag),
+		ModTime:     aws.ToTime(output.LastModified),
+	}, nil
+}
+
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(s.objectKey(key)),
+	})
+	return mapError("delete", key, err)
+}
+
+func (s *S3Storage) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	if expiry <= 0 {
+		expiry = s.config.SignedURLExpiry
+	}
+	request, err := s.presign.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(s.objectKey(key)),
+	}, s3.WithPresignExpires(expiry))
+	if err != nil {
+		return "", mapError("sign", key, err)
+	}
+	return request.URL, nil
+}
+
+// SignedPutURL menandatangani Content-Type, Content-Length, dan ACL, sehingga
+// client tidak bisa meng-upload tipe atau ukuran lain dengan URL yang sama.
+func (s *S3Storage) SignedPutURL(
+	ctx context.Context,
+	key string,
+	contentType string,
+	size int64,
+	expiry time.Duration,
+) (*domain.PresignedRequest, error) {
+	request, err := s.presign.PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(s.config.Bucket),
+		Key:           aws.String(s.objectKey(key)),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(size),
+		ACL:           types.ObjectCannedACLPublicRead,
+	}, s3.WithPresignExpires(expiry))
+	if err != nil {
+		return nil, mapError("sign", key, err)
+	}
+
+	headers := make(map[string]string, len(request.SignedHeader))
+	for name, values := range request.SignedHeader {
+		// Host dan Content-Length diisi sendiri oleh HTTP client
+		if strings.EqualFold(name, "Host") || strings.EqualFold(name, "Content-Length") || len(values) == 0 {
+			continue
+		}
+		headers[name] = values[0]
+	}
+	return &domain.PresignedRequest{Method: request.Method, URL: request.URL, Headers: headers}, nil
+}
+
+func (s *S3Storage) URL(key string) string {
+	objectKey := escapeKey(s.objectKey(key))
+	switch {
+	case s.config.PublicBaseURL != "":
+		return strings.TrimSuffix(s.config.PublicBaseURL, "/") + "/" + objectKey
+	case s.config.Endpoint != "" && s.config.UsePathStyle:
+		return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(s.config.Endpoint, "/"), s.config.Bucket, objectKey)
+	case s.config.Endpoint != "":
+		endpoint, err := url.Parse(s.config.Endpoint)
+		if err != nil {
+			return ""
+		}
+		return fmt.Sprintf("%s://%s.%s/%s", endpoint.Scheme, s.config.Bucket, endpoint.Host, objectKey)
+	default:
+		return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.config.Bucket, s.config.Region, objectKey)
+	}
+}
+
+// Client mengembalikan client S3 untuk operasi yang tidak ada di
+// domain.Storage, mis. membuat bucket di test.
+func (s *S3Storage) Client() *s3.Client {
+	return s.client
+}
+
+func (s *S3Storage) objectKey(key string) string {
+	return s.config.Prefix + key
+}
+
+// escapeKey meng-escape setiap segmen key tanpa mengubah pemisah "/".
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,215 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/infrastructure/storage"
+)
+
+// s3Request adalah request yang diterima fakeS3.
+type s3Request struct {
+	method string
+	path   string
+	header http.Header
+	body   string
+}
+
+// fakeS3 menjawab setiap request dengan respond dan mencatatnya. Dipakai
+// untuk memeriksa request dan pemetaan error S3Storage tanpa MinIO.
+type fakeS3 struct {
+	mu       sync.Mutex
+	requests []s3Request
+	respond  func(w http.ResponseWriter, r *http.Request)
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	f.mu.Lock()
+	f.requests = append(f.requests, s3Request{method: r.Method, path: r.URL.EscapedPath(), header: r.Header.Clone(), body: string(body)})
+	f.mu.Unlock()
+	f.respond(w, r)
+}
+
+// s3Error menulis error S3 dalam format XML-nya.
+func s3Error(status int, code string) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(status)
+		if r.Method != http.MethodHead {
+			io.WriteString(w, "<?xml version=\"1.0\"?><Error><Code>"+code+"</Code><Message>internal detail "+code+"</Message><RequestId>req-1</RequestId></Error>")
+		}
+	}
+}
+
+// isolateAWS mencegah SDK membaca credential atau config milik mesin ini,
+// dan mematikan retry agar setiap error langsung terlihat.
+func isolateAWS(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_MAX_ATTEMPTS", "1")
+}
+
+func newFakeS3Storage(t *testing.T, respond func(w http.ResponseWriter, r *http.Request)) (*storage.S3Storage, *fakeS3, string) {
+	t.Helper()
+	isolateAWS(t)
+	fake := &fakeS3{respond: respond}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	s, err := storage.NewS3Storage(context.Background(), &config.StorageConfig{
+		Endpoint:        server.URL,
+		Bucket:          "uploads",
+		Prefix:          "staging/",
+		AccessKeyID:     "test",
+		SecretAccessKey: "test-secret",
+		UsePathStyle:    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, fake, server.URL
+}
+
+func TestS3PutRequest(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		isPublic bool
+		size     int64
+		wantACL  string
+	}{
+		{name: "public", isPublic: true, size: 7, wantACL: "public-read"},
+		{name: "private unknown size", isPublic: false, size: -1, wantACL: ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s, fake, endpoint := newFakeS3Storage(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("ETag", `"etag"`)
+			})
+
+			url, err := s.Put(context.Background(), "file/a b.png", "image/png", strings.NewReader("content"), tt.size, tt.isPublic)
+			if err != nil {
+				t.Fatalf("Put error = %v", err)
+			}
+			if want := endpoint + "/uploads/staging/file/a%20b.png"; url != want {
+				t.Errorf("Put url = %q, want %q", url, want)
+			}
+			if len(fake.requests) != 1 {
+				t.Fatalf("got %d requests, want one PutObject", len(fake.requests))
+			}
+			request := fake.requests[0]
+			if request.method != http.MethodPut || request.path != "/uploads/staging/file/a%20b.png" {
+				t.Errorf("request = %s %s, want PUT /uploads/staging/file/a%%20b.png", request.method, request.path)
+			}
+			if got := request.header.Get("Content-Type"); got != "image/png" {
+				t.Errorf("Content-Type = %q, want image/png", got)
+			}
+			if got := request.header.Get("X-Amz-Acl"); got != tt.wantACL {
+				t.Errorf("x-amz-acl = %q, want %q", got, tt.wantACL)
+			}
+			if request.body != "content" {
+				t.Errorf("body = %q, want %q", request.body, "content")
+			}
+		})
+	}
+}
+
+func TestS3ErrorsAreMapped(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		respond    func(w http.ResponseWriter, r *http.Request)
+		want       error
+		wantStatus int
+	}{
+		{name: "no such key", respond: s3Error(http.StatusNotFound, "NoSuchKey"), want: helper.ErrNotFound, wantStatus: http.StatusNotFound},
+		{name: "access denied", respond: s3Error(http.StatusForbidden, "AccessDenied"), want: storage.ErrFailed, wantStatus: http.StatusBadGateway},
+		{name: "no such bucket", respond: s3Error(http.StatusNotFound, "NoSuchBucket"), want: storage.ErrFailed, wantStatus: http.StatusBadGateway},
+		{name: "slow down", respond: s3Error(http.StatusServiceUnavailable, "SlowDown"), want: storage.ErrUnavailable, wantStatus: http.StatusServiceUnavailable},
+		{name: "internal error", respond: s3Error(http.StatusInternalServerError, "InternalError"), want: storage.ErrUnavailable, wantStatus: http.StatusServiceUnavailable},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, _ := newFakeS3Storage(t, tt.respond)
+
+			_, err := s.Get(context.Background(), "missing.png")
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Get error = %v, want %v", err, tt.want)
+			}
+			// Detail AWS hanya untuk log, tidak sampai ke response client
+			status, response := helper.FromError(err)
+			if status != tt.wantStatus {
+				t.Errorf("status = %d, want %d", status, tt.wantStatus)
+			}
+			if strings.Contains(response.Errors.Message, "internal detail") || strings.Contains(response.Errors.Message, "req-1") {
+				t.Errorf("response message %q leaks the storage error", response.Errors.Message)
+			}
+		})
+	}
+}
+
+// TestS3StatNotFound memeriksa HEAD 404 tanpa body XML, yang oleh SDK
+// dilaporkan sebagai NotFound, bukan NoSuchKey.
+func TestS3StatNotFound(t *testing.T) {
+	s, _, _ := newFakeS3Storage(t, s3Error(http.StatusNotFound, "NoSuchKey"))
+	if _, err := s.Stat(context.Background(), "missing.png"); !errors.Is(err, helper.ErrNotFound) {
+		t.Fatalf("Stat error = %v, want %v", err, helper.ErrNotFound)
+	}
+}
+
+func TestS3Unreachable(t *testing.T) {
+	isolateAWS(t)
+	// Port 1 tidak pernah menerima koneksi
+	unreachable, err := storage.NewS3Storage(context.Background(), &config.StorageConfig{
+		Endpoint:        "http://127.0.0.1:1",
+		Bucket:          "uploads",
+		AccessKeyID:     "test",
+		SecretAccessKey: "test-secret",
+		UsePathStyle:    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := unreachable.Put(context.Background(), "a.png", "image/png", strings.NewReader("x"), 1, false); !errors.Is(err, storage.ErrUnavailable) {
+		t.Fatalf("Put error = %v, want %v", err, storage.ErrUnavailable)
+	}
+}
+
+func TestNewS3StorageRequiresBucket(t *testing.T) {
+	isolateAWS(t)
+	if _, err := storage.NewS3Storage(context.Background(), &config.StorageConfig{}); err == nil {
+		t.Fatal("NewS3Storage accepted a config without a bucket")
+	}
+}
+
+func TestS3URL(t *testing.T) {
+	isolateAWS(t)
+	for _, tt := range []struct {
+		name   string
+		config config.StorageConfig
+		want   string
+	}{
+		{name: "aws", config: config.StorageConfig{Region: "ap-southeast-1", Bucket: "uploads"}, want: "https://uploads.s3.ap-southeast-1.amazonaws.com/a%20b.png"},
+		{name: "path style", config: config.StorageConfig{Endpoint: "http://localhost:9000/", Bucket: "uploads", UsePathStyle: true}, want: "http://localhost:9000/uploads/a%20b.png"},
+		{name: "virtual host", config: config.StorageConfig{Endpoint: "https://storage.example.com", Bucket: "uploads"}, want: "https://uploads.storage.example.com/a%20b.png"},
+		{name: "public base url with prefix", config: config.StorageConfig{Bucket: "uploads", Prefix: "prod/", PublicBaseURL: "https://cdn.example.com/"}, want: "https://cdn.example.com/prod/a%20b.png"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.AccessKeyID, tt.config.SecretAccessKey = "test", "test-secret"
+			s, err := storage.NewS3Storage(context.Background(), &tt.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.URL("a b.png"); got != tt.want {
+				t.Fatalf("URL = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/infrastructure/storage/storagetest"
+)
+
+func TestS3Storage(t *testing.T) {
+	testDriver(t, storagetest.New(t))
+}
+
+func TestS3SignedURL(t *testing.T) {
+	s := storagetest.New(t)
+	ctx := context.Background()
+	if _, err := s.Put(ctx, "private.txt", "text/plain", strings.NewReader("secret"), 6, false); err != nil {
+		t.Fatalf("Put error = %v", err)
+	}
+
+	signed, err := s.SignedURL(ctx, "private.txt", time.Minute)
+	if err != nil {
+		t.Fatalf("SignedURL error = %v", err)
+	}
+	response, err := http.Get(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	body, _ := io.ReadAll(response.Body)
+	if response.StatusCode != http.StatusOK || string(body) != "secret" {
+		t.Fatalf("GET signed URL = %d %q, want 200 %q", response.StatusCode, body, "secret")
+	}
+}
+
+// putSigned mengirim request presigned apa adanya dengan body tersebut.
+func putSigned(t *testing.T, method, url string, headers map[string]string, body string) int {
+	t.Helper()
+	request, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, response.Body)
+	return response.StatusCode
+}
+
+func TestS3SignedPutURL(t *testing.T) {
+	s := storagetest.New(t)
+	ctx := context.Background()
+	content := "direct upload"
+
+	request, err := s.SignedPutURL(ctx, "uploads/direct.txt", "text/plain", int64(len(content)), time.Minute)
+	if err != nil {
+		t.Fatalf("SignedPutURL error = %v", err)
+	}
+	if request.Method != http.MethodPut {
+		t.Fatalf("Method = %q, want PUT", request.Method)
+	}
+
+	t.Run("other content type", func(t *testing.T) {
+		headers := map[string]string{}
+		for name, value := range request.Headers {
+			headers[name] = value
+		}
+		headers["Content-Type"] = "text/html"
+		if status := putSigned(t, request.Method, request.URL, headers, content); status != http.StatusForbidden {
+			t.Fatalf("PUT with another Content-Type = %d, want 403", status)
+		}
+	})
+
+	t.Run("other size", func(t *testing.T) {
+		if status := putSigned(t, request.Method, request.URL, request.Headers, content+" and more"); status == http.StatusOK {
+			t.Fatal("PUT with another size succeeded")
+		}
+	})
+
+	if status := putSigned(t, request.Method, request.URL, request.Headers, content); status != http.StatusOK {
+		t.Fatalf("PUT signed URL = %d, want 200", status)
+	}
+	info, err := s.Stat(ctx, "uploads/direct.txt")
+	if err != nil {
+		t.Fatalf("Stat error = %v", err)
+	}
+	if info.Size != int64(len(content)) || info.ContentType != "text/plain" {
+		t.Fatalf("Stat = %+v", info)
+	}
+}
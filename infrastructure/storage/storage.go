@@ -0,0 +1,36 @@
+// Package storage berisi driver domain.Storage: S3Storage untuk AWS S3 dan
+// MinIO, LocalStorage untuk direktori lokal, dan MemoryStorage untuk
+// development dan test. Driver dipilih dari
+// STORAGE_DRIVER, lihat config.StorageConfig.
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/domain"
+	"github.com/samber/do/v2"
+)
+
+var (
+	_ domain.Storage = (*S3Storage)(nil)
+	_ domain.Storage = (*LocalStorage)(nil)
+	_ domain.Storage = (*MemoryStorage)(nil)
+
+	_ domain.PresignedUploader = (*S3Storage)(nil)
+)
+
+func NewInject(i do.Injector) (domain.Storage, error) {
+	storageConfig := config.LoadStorageConfig()
+	switch storageConfig.Driver {
+	case config.StorageDriverS3:
+		return NewS3Storage(context.Background(), storageConfig)
+	case config.StorageDriverLocal:
+		return NewLocalStorage(storageConfig.LocalDir, storageConfig.PublicBaseURL)
+	case config.StorageDriverMemory:
+		return NewMemoryStorage(storageConfig.PublicBaseURL), nil
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_DRIVER %q", storageConfig.Driver)
+	}
+}
@@ -0,0 +1,289 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+
+	"github.com/levensspel/go-gin-template/domain"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/infrastructure/storage"
+)
+
+// testDriver menguji perilaku domain.Storage yang harus sama di semua
+// driver. Dipakai juga oleh integration test S3Storage.
+func testDriver(t *testing.T, s domain.Storage) {
+	ctx := context.Background()
+
+	t.Run("put then get", func(t *testing.T) {
+		content := "hello storage"
+		url, err := s.Put(ctx, "avatars/user-1.txt", "text/plain", strings.NewReader(content), int64(len(content)), true)
+		if err != nil {
+			t.Fatalf("Put error = %v", err)
+		}
+		if url != s.URL("avatars/user-1.txt") {
+			t.Errorf("Put url = %q, want %q", url, s.URL("avatars/user-1.txt"))
+		}
+
+		object, err := s.Get(ctx, "avatars/user-1.txt")
+		if err != nil {
+			t.Fatalf("Get error = %v", err)
+		}
+		defer object.Body.Close()
+		got, err := io.ReadAll(object.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != content {
+			t.Errorf("Get body = %q, want %q", got, content)
+		}
+		if object.Size != int64(len(content)) || !strings.HasPrefix(object.ContentType, "text/plain") || object.ETag == "" {
+			t.Errorf("Get info = %+v", object.ObjectInfo)
+		}
+
+		info, err := s.Stat(ctx, "avatars/user-1.txt")
+		if err != nil {
+			t.Fatalf("Stat error = %v", err)
+		}
+		if info.Size != object.Size || info.ETag != object.ETag {
+			t.Errorf("Stat = %+v, want size and etag of Get %+v", info, object.ObjectInfo)
+		}
+	})
+
+	t.Run("unknown size is streamed", func(t *testing.T) {
+		content := bytes.Repeat([]byte("0123456789"), 10_000)
+		// OneByteReader memastikan driver membaca sampai habis, bukan sekali Read
+		body := iotest.OneByteReader(bytes.NewReader(content))
+		if _, err := s.Put(ctx, "streamed.bin", "application/octet-stream", body, -1, false); err != nil {
+			t.Fatalf("Put error = %v", err)
+		}
+		info, err := s.Stat(ctx, "streamed.bin")
+		if err != nil {
+			t.Fatalf("Stat error = %v", err)
+		}
+		if info.Size != int64(len(content)) {
+			t.Fatalf("Stat size = %d, want %d", info.Size, len(content))
+		}
+	})
+
+	t.Run("put overwrites", func(t *testing.T) {
+		for _, content := range []string{"first version", "second"} {
+			if _, err := s.Put(ctx, "overwrite.txt", "text/plain", strings.NewReader(content), int64(len(content)), false); err != nil {
+				t.Fatalf("Put error = %v", err)
+			}
+		}
+		object, err := s.Get(ctx, "overwrite.txt")
+		if err != nil {
+			t.Fatalf("Get error = %v", err)
+		}
+		defer object.Body.Close()
+		if got, _ := io.ReadAll(object.Body); string(got) != "second" {
+			t.Fatalf("Get body = %q, want %q", got, "second")
+		}
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		if _, err := s.Get(ctx, "missing.txt"); !errors.Is(err, helper.ErrNotFound) {
+			t.Errorf("Get error = %v, want %v", err, helper.ErrNotFound)
+		}
+		if _, err := s.Stat(ctx, "missing.txt"); !errors.Is(err, helper.ErrNotFound) {
+			t.Errorf("Stat error = %v, want %v", err, helper.ErrNotFound)
+		}
+		if err := s.Delete(ctx, "missing.txt"); err != nil {
+			t.Errorf("Delete error = %v, want nil", err)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if _, err := s.Put(ctx, "deleted.txt", "text/plain", strings.NewReader("x"), 1, false); err != nil {
+			t.Fatalf("Put error = %v", err)
+		}
+		if err := s.Delete(ctx, "deleted.txt"); err != nil {
+			t.Fatalf("Delete error = %v", err)
+		}
+		if _, err := s.Stat(ctx, "deleted.txt"); !errors.Is(err, helper.ErrNotFound) {
+			t.Fatalf("Stat after Delete error = %v, want %v", err, helper.ErrNotFound)
+		}
+	})
+
+	t.Run("failed body", func(t *testing.T) {
+		errRead := errors.New("client went away")
+		body := io.MultiReader(strings.NewReader("partial"), iotest.ErrReader(errRead))
+		if _, err := s.Put(ctx, "failed.txt", "text/plain", body, -1, false); err == nil {
+			t.Fatal("Put succeeded with a failing body")
+		}
+		if _, err := s.Stat(ctx, "failed.txt"); !errors.Is(err, helper.ErrNotFound) {
+			t.Fatalf("Stat after failed Put error = %v, want %v", err, helper.ErrNotFound)
+		}
+	})
+}
+
+func TestMemoryStorage(t *testing.T) {
+	testDriver(t, storage.NewMemoryStorage(""))
+}
+
+func TestLocalStorage(t *testing.T) {
+	local, err := storage.NewLocalStorage(t.TempDir(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testDriver(t, local)
+}
+
+func TestLocalStorageRejectsInvalidKeys(t *testing.T) {
+	local, err := storage.NewLocalStorage(t.TempDir(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for _, key := range []string{"", "/etc/passwd", "../outside.txt", "a/../../outside.txt", "a//b", "./a", "a\\b", "a\x00b"} {
+		if _, err := local.Put(ctx, key, "text/plain", strings.NewReader("x"), 1, false); !errors.Is(err, storage.ErrInvalidKey) {
+			t.Errorf("Put(%q) error = %v, want %v", key, err, storage.ErrInvalidKey)
+		}
+		if _, err := local.Get(ctx, key); !errors.Is(err, storage.ErrInvalidKey) {
+			t.Errorf("Get(%q) error = %v, want %v", key, err, storage.ErrInvalidKey)
+		}
+	}
+}
+
+func TestLocalStorageCancelledPut(t *testing.T) {
+	local, err := storage.NewLocalStorage(t.TempDir(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := local.Put(ctx, "cancelled.txt", "text/plain", strings.NewReader("x"), 1, false); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Put error = %v, want %v", err, context.Canceled)
+	}
+	if _, err := local.Stat(context.Background(), "cancelled.txt"); !errors.Is(err, helper.ErrNotFound) {
+		t.Fatalf("Stat after cancelled Put error = %v, want %v", err, helper.ErrNotFound)
+	}
+}
+
+// TestLocalStorageConcurrentPut menulis key yang sama dari banyak goroutine
+// sambil membacanya: pembaca hanya boleh melihat salah satu isi utuh, dan
+// tidak ada file sementara yang tertinggal.
+func TestLocalStorageConcurrentPut(t *testing.T) {
+	dir := t.TempDir()
+	local, err := storage.NewLocalStorage(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	contents := map[string]bool{}
+	for i := range 8 {
+		contents[strings.Repeat(string(rune('a'+i)), 64<<10)] = true
+	}
+
+	var wg sync.WaitGroup
+	for content := range contents {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 5 {
+				if _, err := local.Put(ctx, "avatars/same.txt", "text/plain", strings.NewReader(content), int64(len(content)), false); err != nil {
+					t.Errorf("Put error = %v", err)
+					return
+				}
+			}
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	for reading := true; reading; {
+		select {
+		case <-done:
+			reading = false
+		default:
+		}
+		object, err := local.Get(ctx, "avatars/same.txt")
+		if errors.Is(err, helper.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Get error = %v", err)
+		}
+		got, err := io.ReadAll(object.Body)
+		object.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !contents[string(got)] {
+			t.Fatalf("Get returned %d bytes that are not one complete Put", len(got))
+		}
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, "avatars"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "same.txt" {
+		names := []string{}
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		t.Fatalf("directory has %v, want only same.txt", names)
+	}
+}
+
+func TestURLEscapesSegments(t *testing.T) {
+	local, err := storage.NewLocalStorage(t.TempDir(), "https://api.example.com/files/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name string
+		s    domain.Storage
+		want string
+	}{
+		{name: "memory default", s: storage.NewMemoryStorage(""), want: "memory://a%20b/c%3Fd.png"},
+		{name: "memory base url", s: storage.NewMemoryStorage("https://cdn.example.com"), want: "https://cdn.example.com/a%20b/c%3Fd.png"},
+		{name: "local base url", s: local, want: "https://api.example.com/files/a%20b/c%3Fd.png"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.URL("a b/c?d.png"); got != tt.want {
+				t.Fatalf("URL = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewInjectPicksDriver(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		env     map[string]string
+		want    string
+		wantErr bool
+	}{
+		{name: "debug default", env: map[string]string{"MODE": "DEBUG", "STORAGE_DRIVER": ""}, want: "*storage.MemoryStorage"},
+		{name: "memory", env: map[string]string{"STORAGE_DRIVER": "memory"}, want: "*storage.MemoryStorage"},
+		{name: "local", env: map[string]string{"STORAGE_DRIVER": "local", "STORAGE_LOCAL_DIR": t.TempDir()}, want: "*storage.LocalStorage"},
+		{name: "s3 without bucket", env: map[string]string{"STORAGE_DRIVER": "s3", "AWS_BUCKET": ""}, wantErr: true},
+		{name: "unknown", env: map[string]string{"STORAGE_DRIVER": "ftp"}, wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			s, err := storage.NewInject(nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewInject error = %v, want error %t", err, tt.wantErr)
+			}
+			if got := fmt.Sprintf("%T", s); !tt.wantErr && got != tt.want {
+				t.Fatalf("NewInject = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
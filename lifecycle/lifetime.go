@@ -0,0 +1,40 @@
+package lifecycle
+
+import (
+	"context"
+
+	"github.com/samber/do/v2"
+)
+
+// Lifetime adalah context yang hidup selama server berjalan. Worker
+// background (dispatcher, purge, listener) menurunkan context-nya dari sini
+// alih-alih context.Background, sehingga semuanya ikut dibatalkan saat
+// shutdown.
+type Lifetime struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func NewLifetime() *Lifetime {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Lifetime{ctx: ctx, cancel: cancel}
+}
+
+func NewLifetimeInject(i do.Injector) (*Lifetime, error) {
+	return NewLifetime(), nil
+}
+
+func (l *Lifetime) Context() context.Context {
+	return l.ctx
+}
+
+// Cancel membatalkan context server. Dipanggil setelah request yang sedang
+// berjalan selesai, sebelum dependensi di-shutdown.
+func (l *Lifetime) Cancel() {
+	l.cancel()
+}
+
+// Shutdown memastikan context dibatalkan walau Cancel tidak dipanggil.
+func (l *Lifetime) Shutdown() {
+	l.cancel()
+}
@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// asyncWriter adalah zapcore.WriteSyncer yang menaruh setiap entry di
+// antrian berkapasitas tetap dan menulisnya ke out dari satu goroutine.
+// Jika antrian penuh, entry tertua dibuang dan onDrop dipanggil.
+type asyncWriter struct {
+	out    zapcore.WriteSyncer
+	queue  chan []byte
+	onDrop func()
+
+	// mu menjaga agar tidak ada entry yang masuk antrian setelah Close
+	// mulai menguras antrian. Write memegang read lock.
+	mu     sync.RWMutex
+	closed bool
+
+	flush chan chan error
+	stop  chan struct{}
+	done  chan struct{}
+	once  sync.Once
+}
+
+func newAsyncWriter(out zapcore.WriteSyncer, size int, onDrop func()) *asyncWriter {
+	w := &asyncWriter{
+		out:    out,
+		queue:  make(chan []byte, max(size, 1)),
+		onDrop: onDrop,
+		flush:  make(chan chan error),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+func (w *asyncWriter) run() {
+	defer close(w.done)
+	for {
+		select {
+		case entry := <-w.queue:
+			_, _ = w.out.Write(entry)
+		case reply := <-w.flush:
+			w.drain()
+			reply <- w.out.Sync()
+		case <-w.stop:
+			w.drain()
+			return
+		}
+	}
+}
+
+// drain menulis semua entry yang sudah ada di antrian.
+func (w *asyncWriter) drain() {
+	for {
+		select {
+		case entry := <-w.queue:
+			_, _ = w.out.Write(entry)
+		default:
+			return
+		}
+	}
+}
+
+// Write menyalin p karena buffer zap dipakai ulang setelah Write kembali.
+// Setelah Close, entry ditulis langsung ke out.
+func (w *asyncWriter) Write(p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return w.out.Write(p)
+	}
+
+	entry := make([]byte, len(p))
+	copy(entry, p)
+	for {
+		select {
+		case w.queue <- entry:
+			return len(p), nil
+		default:
+		}
+		// Antrian penuh: buang entry tertua lalu coba lagi
+		select {
+		case <-w.queue:
+			w.onDrop()
+		default:
+		}
+	}
+}
+
+// Sync menunggu antrian saat ini tertulis lalu mem-flush out.
+func (w *asyncWriter) Sync() error {
+	w.mu.RLock()
+	if w.closed {
+		w.mu.RUnlock()
+		return w.out.Sync()
+	}
+	reply := make(chan error, 1)
+	w.flush <- reply
+	w.mu.RUnlock()
+	return <-reply
+}
+
+// Close menulis sisa antrian dan menghentikan goroutine writer. Log yang
+// ditulis setelahnya langsung ke out.
+func (w *asyncWriter) Close() error {
+	w.once.Do(func() {
+		w.mu.Lock()
+		w.closed = true
+		w.mu.Unlock()
+		close(w.stop)
+	})
+	<-w.done
+	return w.out.Sync()
+}
@@ -0,0 +1,153 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/levensspel/go-gin-template/clock"
+	"github.com/levensspel/go-gin-template/config"
+	"go.uber.org/zap/zapcore"
+)
+
+// lockedBuffer adalah sink yang aman ditulis dari banyak goroutine.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// gatedWriter menahan setiap Write sampai release ditutup dan memberi tahu
+// started saat Write pertama dimulai.
+type gatedWriter struct {
+	out     lockedBuffer
+	started chan struct{}
+	once    sync.Once
+	release chan struct{}
+}
+
+func (w *gatedWriter) Write(p []byte) (int, error) {
+	w.once.Do(func() { close(w.started) })
+	<-w.release
+	return w.out.Write(p)
+}
+
+func TestAsyncWriterDropsOldest(t *testing.T) {
+	sink := &gatedWriter{started: make(chan struct{}), release: make(chan struct{})}
+	var dropped atomic.Int64
+	w := newAsyncWriter(zapcore.AddSync(sink), 2, func() { dropped.Add(1) })
+
+	// a sedang ditulis, sehingga antrian berisi b dan c sebelum d masuk
+	w.Write([]byte("a\n"))
+	<-sink.started
+	for _, entry := range []string{"b\n", "c\n", "d\n"} {
+		w.Write([]byte(entry))
+	}
+	close(sink.release)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := sink.out.String(); got != "a\nc\nd\n" {
+		t.Fatalf("written = %q, want the oldest queued entry dropped", got)
+	}
+	if got := dropped.Load(); got != 1 {
+		t.Fatalf("dropped = %d, want 1", got)
+	}
+}
+
+func TestAsyncWriterSyncFlushesQueue(t *testing.T) {
+	var sink lockedBuffer
+	w := newAsyncWriter(zapcore.AddSync(&sink), 100, func() {})
+	defer w.Close()
+
+	for i := range 10 {
+		w.Write([]byte(fmt.Sprintf("%d\n", i)))
+	}
+	if err := w.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(sink.String(), "\n"); got != 10 {
+		t.Fatalf("written %d entries after Sync, want 10", got)
+	}
+}
+
+// TestAsyncWriterConcurrentWrites sebaiknya dijalankan dengan -race. Setiap
+// entry harus tertulis utuh atau dihitung sebagai dibuang.
+func TestAsyncWriterConcurrentWrites(t *testing.T) {
+	var sink lockedBuffer
+	var dropped atomic.Int64
+	w := newAsyncWriter(zapcore.AddSync(&sink), 16, func() { dropped.Add(1) })
+
+	const writers, entries = 50, 200
+	var wg sync.WaitGroup
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Buffer yang sama dipakai ulang seperti buffer zap
+			buf := make([]byte, 0, 32)
+			for j := range entries {
+				buf = fmt.Appendf(buf[:0], "writer-%d entry-%d\n", i, j)
+				if _, err := w.Write(buf); err != nil {
+					t.Error(err)
+				}
+				if j%50 == 0 {
+					w.Sync()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(sink.String(), "\n"), "\n")
+	if got := int64(len(lines)) + dropped.Load(); got != writers*entries {
+		t.Fatalf("written %d + dropped %d = %d, want %d", len(lines), dropped.Load(), got, writers*entries)
+	}
+	for _, line := range lines {
+		var writer, entry int
+		if _, err := fmt.Sscanf(line, "writer-%d entry-%d", &writer, &entry); err != nil {
+			t.Fatalf("corrupted entry %q", line)
+		}
+	}
+}
+
+func TestAsyncErrorsBypassQueue(t *testing.T) {
+	dir := inTempDir(t)
+	handler := NewlogHandler(
+		&config.LoggerConfig{Async: true, BufferSize: 100, SyncErrors: true},
+		NewLevel(zapcore.InfoLevel, clock.Real()),
+		clock.Real(),
+		func() {},
+	)
+	defer handler.Shutdown()
+
+	handler.Error("direct", "logger.TestAsyncErrorsBypassQueue")
+	// Belum ada Sync atau Shutdown, tetapi error sudah ada di file
+	content, err := os.ReadFile(filepath.Join(dir, "logs", "app.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(content, []byte(`"msg":"direct"`)) {
+		t.Fatalf("app.log = %q, want the error written directly", content)
+	}
+}
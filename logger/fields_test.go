@@ -0,0 +1,182 @@
+package logger
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/clock"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/reporting"
+	"go.uber.org/zap/zapcore"
+)
+
+// readEntries mematikan handler lalu membaca setiap baris app.log sebagai
+// JSON.
+func readEntries(t *testing.T, dir string, handler *LogHandler) []map[string]any {
+	t.Helper()
+	if err := handler.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(filepath.Join(dir, "logs", "app.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var entries []map[string]any
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("decode %s: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestLevelFiltersDebug(t *testing.T) {
+	for _, tt := range []struct {
+		level zapcore.Level
+		want  []string
+	}{
+		{level: zapcore.DebugLevel, want: []string{"debug", "info", "warn", "error"}},
+		{level: zapcore.InfoLevel, want: []string{"info", "warn", "error"}},
+		{level: zapcore.ErrorLevel, want: []string{"error"}},
+	} {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			dir := inTempDir(t)
+			handler := NewlogHandler(&config.LoggerConfig{}, NewLevel(tt.level, clock.Real()), clock.Real(), func() {})
+			handler.Debug("debug", "logger.TestLevelFiltersDebug")
+			handler.Info("info", "logger.TestLevelFiltersDebug")
+			handler.Warn("warn", "logger.TestLevelFiltersDebug")
+			handler.Error("error", "logger.TestLevelFiltersDebug")
+
+			entries := readEntries(t, dir, handler)
+			if len(entries) != len(tt.want) {
+				t.Fatalf("logged %d entries, want %v", len(entries), tt.want)
+			}
+			for i, entry := range entries {
+				if entry["msg"] != tt.want[i] || entry["level"] != tt.want[i] {
+					t.Errorf("entry %d = %v, want level and msg %q", i, entry, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWithBindsFields(t *testing.T) {
+	dir := inTempDir(t)
+	handler := NewlogHandler(&config.LoggerConfig{}, NewLevel(zapcore.InfoLevel, clock.Real()), clock.Real(), func() {})
+
+	child := handler.With(map[string]any{"called_by": "handler.Create", "request_id": "req-1"})
+	grandchild := child.With(map[string]any{"request_id": "req-2", "manager_id": "manager-1"})
+	child.Info("child", Bound, "payload")
+	grandchild.Info("grandchild", Bound)
+	handler.Info("root", "logger.TestWithBindsFields")
+
+	entries := readEntries(t, dir, handler)
+	if len(entries) != 3 {
+		t.Fatalf("logged %d entries, want 3", len(entries))
+	}
+	if got := entries[0]; got["called_by"] != "handler.Create" || got["request_id"] != "req-1" || got["manager_id"] != nil {
+		t.Errorf("child entry = %v", got)
+	}
+	if data, _ := entries[0]["data"].([]any); len(data) != 1 || data[0] != "payload" {
+		t.Errorf("child data = %v, want [payload]", entries[0]["data"])
+	}
+	// Field child menimpa field parent dengan nama yang sama
+	if got := entries[1]; got["called_by"] != "handler.Create" || got["manager_id"] != "manager-1" {
+		t.Errorf("grandchild entry = %v", got)
+	}
+	// With tidak mengubah parent-nya
+	if got := entries[2]; got["called_by"] != "logger.TestWithBindsFields" || got["request_id"] != nil {
+		t.Errorf("root entry = %v", got)
+	}
+}
+
+// recordingReporter menyimpan fields dari setiap Report.
+type recordingReporter struct {
+	mu     sync.Mutex
+	fields []map[string]any
+	ctxs   []context.Context
+}
+
+func (r *recordingReporter) Report(ctx context.Context, err error, fields map[string]any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fields = append(r.fields, fields)
+	r.ctxs = append(r.ctxs, ctx)
+}
+
+func TestErrorReportsBoundFields(t *testing.T) {
+	inTempDir(t)
+	reporter := &recordingReporter{}
+	previous := reporting.Default()
+	reporting.SetDefault(reporter)
+	t.Cleanup(func() { reporting.SetDefault(previous) })
+
+	handler := NewlogHandler(&config.LoggerConfig{}, NewLevel(zapcore.InfoLevel, clock.Real()), clock.Real(), func() {})
+	defer handler.Shutdown()
+	handler.With(map[string]any{"called_by": "handler.Create", "manager_id": "manager-1"}).Error("insert failed", Bound)
+	handler.Warn("not reported", "logger.TestErrorReportsBoundFields")
+
+	if len(reporter.fields) != 1 {
+		t.Fatalf("reported %d errors, want 1", len(reporter.fields))
+	}
+	if got := reporter.fields[0]; got["called_by"] != "handler.Create" || got["manager_id"] != "manager-1" {
+		t.Fatalf("reported fields = %v", got)
+	}
+}
+
+type requestKey struct{}
+
+// TestErrorReportsWithBoundContext memastikan context dari WithContext
+// sampai ke reporter, juga setelah With.
+func TestErrorReportsWithBoundContext(t *testing.T) {
+	inTempDir(t)
+	reporter := &recordingReporter{}
+	previous := reporting.Default()
+	reporting.SetDefault(reporter)
+	t.Cleanup(func() { reporting.SetDefault(previous) })
+
+	handler := NewlogHandler(&config.LoggerConfig{}, NewLevel(zapcore.InfoLevel, clock.Real()), clock.Real(), func() {})
+	defer handler.Shutdown()
+	ctx := context.WithValue(context.Background(), requestKey{}, "req-1")
+	handler.WithContext(ctx).With(map[string]any{"called_by": "handler.Create"}).Error("insert failed", Bound)
+	handler.Error("no context", "logger.TestErrorReportsWithBoundContext")
+
+	if len(reporter.ctxs) != 2 {
+		t.Fatalf("reported %d errors, want 2", len(reporter.ctxs))
+	}
+	if got := reporter.ctxs[0].Value(requestKey{}); got != "req-1" {
+		t.Fatalf("reported ctx value = %v, want the bound context", got)
+	}
+	if reporter.ctxs[1] == nil {
+		t.Fatal("reported nil ctx for a logger without context")
+	}
+}
+
+func TestRequestFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx, _ := gin.CreateTestContext(nil)
+	const caller helper.FunctionCaller = "handler.Create"
+
+	if got := RequestFields(ctx, caller); len(got) != 1 || got["called_by"] != caller {
+		t.Fatalf("RequestFields without request = %v, want only called_by", got)
+	}
+
+	ctx.Set(helper.ContextKeyRequestID, "req-1")
+	ctx.Set(helper.ContextKeyUserID, "manager-1")
+	got := RequestFields(ctx, caller)
+	if got["request_id"] != "req-1" || got["manager_id"] != "manager-1" || got["called_by"] != caller {
+		t.Fatalf("RequestFields = %v", got)
+	}
+}
@@ -0,0 +1,112 @@
+package logger
+
+import (
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/levensspel/go-gin-template/clock"
+	"github.com/samber/do/v2"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Level adalah level log minimum yang bisa diubah saat runtime lewat
+// endpoint admin, dengan opsi kembali ke level default setelah durasi
+// tertentu. Semua logger hasil NewlogHandler membaca level ini.
+type Level struct {
+	level        zap.AtomicLevel
+	defaultLevel zapcore.Level
+	clock        clock.Clock
+
+	mu       sync.Mutex
+	revertAt time.Time
+	// cancel menghentikan revert yang dijadwalkan Set sebelumnya
+	cancel chan struct{}
+}
+
+func NewLevel(defaultLevel zapcore.Level, clock clock.Clock) *Level {
+	return &Level{
+		level:        zap.NewAtomicLevelAt(defaultLevel),
+		defaultLevel: defaultLevel,
+		clock:        clock,
+	}
+}
+
+// NewLevelInject memakai LOG_LEVEL sebagai level default.
+func NewLevelInject(i do.Injector) (*Level, error) {
+	return NewLevel(envLevel("LOG_LEVEL", zapcore.InfoLevel), do.MustInvoke[clock.Clock](i)), nil
+}
+
+// Enabled memenuhi zapcore.LevelEnabler.
+func (l *Level) Enabled(level zapcore.Level) bool {
+	return l.level.Enabled(level)
+}
+
+func (l *Level) Current() zapcore.Level {
+	return l.level.Level()
+}
+
+func (l *Level) Default() zapcore.Level {
+	return l.defaultLevel
+}
+
+// RevertAt adalah waktu level kembali ke default, zero jika tidak ada
+// revert yang dijadwalkan.
+func (l *Level) RevertAt() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.revertAt
+}
+
+// Set mengganti level aktif. Jika revertAfter > 0, level kembali ke default
+// setelah durasi tersebut. Set berikutnya membatalkan revert sebelumnya.
+func (l *Level) Set(level zapcore.Level, revertAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.cancel != nil {
+		close(l.cancel)
+		l.cancel = nil
+	}
+	l.revertAt = time.Time{}
+	l.level.SetLevel(level)
+	if revertAfter <= 0 {
+		return
+	}
+
+	cancel := make(chan struct{})
+	l.cancel = cancel
+	l.revertAt = l.clock.Now().Add(revertAfter)
+	expired := l.clock.After(revertAfter)
+	go func() {
+		select {
+		case <-cancel:
+		case <-expired:
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			// Set lain bisa terjadi bersamaan dengan timer yang habis
+			if l.cancel != cancel {
+				return
+			}
+			l.cancel = nil
+			l.revertAt = time.Time{}
+			l.level.SetLevel(l.defaultLevel)
+		}
+	}()
+}
+
+// envLevel membaca level log minimum. Debug hanya ditulis jika LOG_LEVEL=debug.
+func envLevel(key string, fallback zapcore.Level) zapcore.Level {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	level, err := zapcore.ParseLevel(value)
+	if err != nil {
+		log.Printf("Invalid log level for %s: %q, using default %s", key, value, fallback)
+		return fallback
+	}
+	return level
+}
@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/clock/clocktest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var levelStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// waitForLevel menunggu goroutine revert selesai mengganti level.
+func waitForLevel(t *testing.T, level *Level, want zapcore.Level) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for level.Current() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("level = %s, want %s", level.Current(), want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLevelRevertsOnTheClock(t *testing.T) {
+	fake := clocktest.NewFake(levelStart)
+	level := NewLevel(zapcore.InfoLevel, fake)
+	core, logs := observer.New(level)
+	log := zap.New(core).Sugar()
+
+	log.Debug("before")
+	level.Set(zapcore.DebugLevel, 15*time.Minute)
+	if got := level.RevertAt(); !got.Equal(levelStart.Add(15 * time.Minute)) {
+		t.Fatalf("RevertAt = %v, want %v", got, levelStart.Add(15*time.Minute))
+	}
+	log.Debug("while debug")
+
+	fake.BlockUntil(1)
+	fake.Advance(15*time.Minute - time.Second)
+	log.Debug("just before revert")
+	if level.Current() != zapcore.DebugLevel {
+		t.Fatalf("level = %s before revertAfter elapsed, want debug", level.Current())
+	}
+
+	fake.Advance(time.Second)
+	waitForLevel(t, level, zapcore.InfoLevel)
+	log.Debug("after revert")
+	if !level.RevertAt().IsZero() {
+		t.Fatalf("RevertAt = %v after revert, want zero", level.RevertAt())
+	}
+
+	var messages []string
+	for _, entry := range logs.All() {
+		messages = append(messages, entry.Message)
+	}
+	if len(messages) != 2 || messages[0] != "while debug" || messages[1] != "just before revert" {
+		t.Fatalf("debug entries = %q, want only those logged while debug was active", messages)
+	}
+}
+
+func TestLevelSetCancelsPreviousRevert(t *testing.T) {
+	fake := clocktest.NewFake(levelStart)
+	level := NewLevel(zapcore.InfoLevel, fake)
+
+	level.Set(zapcore.DebugLevel, time.Minute)
+	fake.BlockUntil(1)
+	// Tanpa revertAfter, level bertahan walau timer sebelumnya habis
+	level.Set(zapcore.WarnLevel, 0)
+	fake.Advance(time.Hour)
+	time.Sleep(10 * time.Millisecond)
+
+	if level.Current() != zapcore.WarnLevel || !level.RevertAt().IsZero() {
+		t.Fatalf("level = %s revertAt = %v, want warn without revert", level.Current(), level.RevertAt())
+	}
+	if level.Default() != zapcore.InfoLevel {
+		t.Fatalf("Default = %s, want info", level.Default())
+	}
+}
+
+func TestEnvLevel(t *testing.T) {
+	for _, tt := range []struct {
+		value string
+		want  zapcore.Level
+	}{
+		{value: "", want: zapcore.InfoLevel},
+		{value: "debug", want: zapcore.DebugLevel},
+		{value: "ERROR", want: zapcore.ErrorLevel},
+		{value: "verbose", want: zapcore.InfoLevel},
+	} {
+		t.Setenv("TEST_LOG_LEVEL", tt.value)
+		if got := envLevel("TEST_LOG_LEVEL", zapcore.InfoLevel); got != tt.want {
+			t.Errorf("envLevel(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
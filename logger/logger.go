@@ -2,7 +2,14 @@
 package logger
 
 import (
+	"context"
+	"errors"
+
+	"github.com/levensspel/go-gin-template/clock"
+	"github.com/levensspel/go-gin-template/config"
 	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/reporting"
 	"github.com/samber/do/v2"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -14,13 +21,36 @@ type Logger interface {
 	Error(msg string, function helper.FunctionCaller, data ...interface{})
 	Debug(msg string, function helper.FunctionCaller, data ...interface{})
 	Warn(msg string, function helper.FunctionCaller, data ...interface{})
+	// With mengembalikan child logger yang menyertakan fields di setiap
+	// log, mis. request_id dan manager_id. Jika fields berisi called_by,
+	// function boleh dikosongkan (Bound) pada log berikutnya.
+	With(fields map[string]any) Logger
+	// WithContext mengembalikan child logger yang meneruskan ctx ke
+	// reporter pada Error, mis. agar event membawa trace request.
+	WithContext(ctx context.Context) Logger
 }
 
+// Bound dipakai sebagai function untuk logger hasil With yang sudah membawa
+// called_by, lihat RequestFields.
+const Bound helper.FunctionCaller = ""
+
 type LogHandler struct {
 	logger *zap.SugaredLogger
+	// fields adalah field yang di-bind lewat With, ikut dikirim ke reporter
+	fields map[string]any
+	// ctx diteruskan ke reporter, nil berarti context.Background()
+	ctx context.Context
+	// file, async dan sampler hanya diisi di root logger, child logger
+	// tidak menutupnya
+	file    *lumberjack.Logger
+	async   *asyncWriter
+	sampler *sampler
 }
 
-func NewlogHandler() *LogHandler {
+// NewlogHandler membuat logger ke ./logs/app.log dengan level minimum
+// level. onDrop dipanggil setiap entry yang dibuang karena antrian mode
+// async penuh.
+func NewlogHandler(config *config.LoggerConfig, level *Level, clock clock.Clock, onDrop func()) *LogHandler {
 	lumberjackLogger := &lumberjack.Logger{
 		Filename:   "./logs/app.log",
 		MaxSize:    10, // Max megabytes before log is rotated
@@ -34,47 +64,136 @@ func NewlogHandler() *LogHandler {
 	encoderConfig.TimeKey = "timestamp"
 	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
 
-	core := zapcore.NewCore(
-		zapcore.NewJSONEncoder(encoderConfig),
-		writeSyncer,
-		zapcore.InfoLevel,
-	)
+	encoder := zapcore.NewJSONEncoder(encoderConfig)
 
-	logger := zap.New(core, zap.AddCaller())
-	return &LogHandler{
-		logger: logger.Sugar(),
+	handler := &LogHandler{file: lumberjackLogger}
+	core := zapcore.NewCore(encoder, writeSyncer, level)
+	if config.Async {
+		handler.async = newAsyncWriter(writeSyncer, config.BufferSize, onDrop)
+		queued := zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+			return level.Enabled(l) && !(config.SyncErrors && l >= zapcore.ErrorLevel)
+		})
+		core = zapcore.NewCore(encoder, handler.async, queued)
+		if config.SyncErrors {
+			direct := zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+				return level.Enabled(l) && l >= zapcore.ErrorLevel
+			})
+			core = zapcore.NewTee(core, zapcore.NewCore(encoder.Clone(), writeSyncer, direct))
+		}
+	}
+
+	if config.Sampling {
+		handler.sampler = newSampler(core, clock, config.SamplingPerSecond, config.SamplingErrorPerSecond, config.SamplingSummaryInterval)
+		core = &samplingCore{Core: core, sampler: handler.sampler}
+	}
+
+	handler.logger = zap.New(core, zap.AddCaller()).Sugar()
+	return handler
+}
+
+// Shutdown mem-flush log dan menutup file log, lihat do.ShutdownerWithError.
+// Injector memanggilnya setelah semua service yang memakai logger berhenti.
+func (l LogHandler) Shutdown() error {
+	if l.sampler != nil {
+		l.sampler.Close()
+	}
+	err := l.logger.Sync()
+	if l.async != nil {
+		err = errors.Join(err, l.async.Close())
 	}
+	if l.file != nil {
+		err = errors.Join(err, l.file.Close())
+	}
+	return err
 }
 
 func NewlogHandlerInject(i do.Injector) (LogHandler, error) {
-	logger := NewlogHandler()
+	// Reporter dipasang sebagai default sebelum log pertama ditulis
+	do.MustInvoke[reporting.Reporter](i)
+	metrics := do.MustInvoke[*metrics.Metrics](i)
+	level := do.MustInvoke[*Level](i)
+	logger := NewlogHandler(config.LoadLoggerConfig(), level, do.MustInvoke[clock.Clock](i), metrics.CountLogDropped)
 	return *logger, nil
 }
 
+// With mengembalikan child logger dengan fields tambahan. Field dengan nama
+// yang sama menimpa field parent.
+func (l *LogHandler) With(fields map[string]any) Logger {
+	merged := make(map[string]any, len(l.fields)+len(fields))
+	for key, value := range l.fields {
+		merged[key] = value
+	}
+	keysAndValues := make([]interface{}, 0, len(fields)*2)
+	for key, value := range fields {
+		merged[key] = value
+		keysAndValues = append(keysAndValues, key, value)
+	}
+	return &LogHandler{
+		logger: l.logger.With(keysAndValues...),
+		fields: merged,
+		ctx:    l.ctx,
+	}
+}
+
+// WithContext mengembalikan child logger yang memakai ctx saat melaporkan
+// Error. Context dari gin.Context tidak boleh dipakai karena di-reuse
+// setelah request selesai, gunakan c.Request.Context().
+func (l *LogHandler) WithContext(ctx context.Context) Logger {
+	return &LogHandler{
+		logger: l.logger,
+		fields: l.fields,
+		ctx:    ctx,
+	}
+}
+
 func (l *LogHandler) Info(msg string, function helper.FunctionCaller, data ...interface{}) {
-	l.logger.Infow(msg,
-		"called_by", function,
-		"data", data,
-	)
+	l.logger.Infow(msg, keysAndValues(function, data)...)
 }
 
 func (l *LogHandler) Error(msg string, function helper.FunctionCaller, data ...interface{}) {
-	l.logger.Errorw(msg,
-		"called_by", function,
-		"data", data,
-	)
+	l.logger.Errorw(msg, keysAndValues(function, data)...)
+
+	extra := make(map[string]any, len(l.fields)+2)
+	for key, value := range l.fields {
+		extra[key] = value
+	}
+	if function != Bound {
+		extra["called_by"] = function
+	}
+	extra["data"] = data
+	ctx := l.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	reporting.Default().Report(ctx, errors.New(msg), extra)
 }
 
 func (l *LogHandler) Debug(msg string, function helper.FunctionCaller, data ...interface{}) {
-	l.logger.Debugw(msg,
-		"called_by", function,
-		"data", data,
-	)
+	l.logger.Debugw(msg, keysAndValues(function, data)...)
 }
 
 func (l *LogHandler) Warn(msg string, function helper.FunctionCaller, data ...interface{}) {
-	l.logger.Warnw(msg,
-		"called_by", function,
-		"data", data,
-	)
+	l.logger.Warnw(msg, keysAndValues(function, data)...)
+}
+
+// keysAndValues menyusun field per log. called_by dilewati jika sudah
+// di-bind lewat With.
+func keysAndValues(function helper.FunctionCaller, data []interface{}) []interface{} {
+	if function == Bound {
+		return []interface{}{"data", data}
+	}
+	return []interface{}{"called_by", function, "data", data}
+}
+
+// RequestFields berisi field yang di-bind handler sekali per request:
+// called_by, request_id, dan manager_id jika sudah terautentikasi.
+func RequestFields(ctx context.Context, function helper.FunctionCaller) map[string]any {
+	fields := map[string]any{"called_by": function}
+	if requestID, ok := ctx.Value(helper.ContextKeyRequestID).(string); ok && requestID != "" {
+		fields["request_id"] = requestID
+	}
+	if managerID, ok := ctx.Value(helper.ContextKeyUserID).(string); ok && managerID != "" {
+		fields["manager_id"] = managerID
+	}
+	return fields
 }
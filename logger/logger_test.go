@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/levensspel/go-gin-template/clock"
+	"github.com/levensspel/go-gin-template/config"
+	"go.uber.org/zap/zapcore"
+)
+
+// inTempDir menjalankan test di direktori sementara, karena NewlogHandler
+// selalu menulis ke ./logs/app.log.
+func inTempDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	return dir
+}
+
+func TestShutdownFlushesLogs(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		config config.LoggerConfig
+	}{
+		{name: "sync", config: config.LoggerConfig{}},
+		// Antrian cukup besar agar tidak ada entry yang dibuang
+		{name: "async", config: config.LoggerConfig{Async: true, BufferSize: 1000}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := inTempDir(t)
+			dropped := 0
+			handler := NewlogHandler(&tt.config, NewLevel(zapcore.InfoLevel, clock.Real()), clock.Real(), func() { dropped++ })
+
+			const entries = 500
+			for range entries {
+				handler.Info("entry", "logger.TestShutdownFlushesLogs")
+			}
+			if err := handler.Shutdown(); err != nil {
+				t.Fatalf("Shutdown error = %v", err)
+			}
+
+			content, err := os.ReadFile(filepath.Join(dir, "logs", "app.log"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := bytes.Count(content, []byte("\n")); got != entries || dropped != 0 {
+				t.Fatalf("app.log has %d entries and %d were dropped, want %d written", got, dropped, entries)
+			}
+		})
+	}
+}
+
+func TestAsyncWriterCloseWritesQueuedEntries(t *testing.T) {
+	var out bytes.Buffer
+	release := make(chan struct{})
+	w := newAsyncWriter(zapcore.AddSync(&blockingWriter{out: &out, release: release}), 10, func() {})
+
+	for _, entry := range []string{"a\n", "b\n", "c\n"} {
+		if _, err := w.Write([]byte(entry)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Writer masih tertahan, jadi entry masih di antrian saat Close dimulai
+	closed := make(chan error, 1)
+	go func() { closed <- w.Close() }()
+	close(release)
+	if err := <-closed; err != nil {
+		t.Fatalf("Close error = %v", err)
+	}
+	if got := out.String(); got != "a\nb\nc\n" {
+		t.Fatalf("written = %q, want every queued entry", got)
+	}
+
+	// Setelah Close, entry ditulis langsung
+	if _, err := w.Write([]byte("d\n")); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != "a\nb\nc\nd\n" {
+		t.Fatalf("written after Close = %q", got)
+	}
+}
+
+// blockingWriter menahan Write pertama sampai release ditutup.
+type blockingWriter struct {
+	out     *bytes.Buffer
+	release chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.release
+	return w.out.Write(p)
+}
@@ -0,0 +1,142 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/levensspel/go-gin-template/clock"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type sampleKey struct {
+	level   zapcore.Level
+	message string
+}
+
+// sampler membatasi entry dengan level dan pesan yang sama per detik,
+// mis. error database yang sama ribuan kali saat koneksi putus. Jumlah
+// entry yang dibuang dilaporkan berkala lewat satu entry Warn per pesan.
+type sampler struct {
+	out        zapcore.Core
+	clock      clock.Clock
+	limit      int
+	errorLimit int
+
+	mu          sync.Mutex
+	windowStart time.Time
+	counts      map[sampleKey]int
+	suppressed  map[sampleKey]int
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// newSampler menjalankan goroutine yang menulis ringkasan setiap interval
+// ke out. limit berlaku untuk level di bawah Error, errorLimit untuk Error
+// ke atas.
+func newSampler(out zapcore.Core, clock clock.Clock, limit, errorLimit int, interval time.Duration) *sampler {
+	s := &sampler{
+		out:         out,
+		clock:       clock,
+		limit:       limit,
+		errorLimit:  errorLimit,
+		windowStart: clock.Now(),
+		counts:      make(map[sampleKey]int),
+		suppressed:  make(map[sampleKey]int),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	go s.run(interval)
+	return s
+}
+
+func (s *sampler) run(interval time.Duration) {
+	defer close(s.done)
+
+	ticker := s.clock.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			s.summarize()
+			return
+		case <-ticker.C():
+			s.summarize()
+		}
+	}
+}
+
+// allow menghitung entry pada window satu detik saat ini.
+func (s *sampler) allow(entry zapcore.Entry) bool {
+	limit := s.limit
+	if entry.Level >= zapcore.ErrorLevel {
+		limit = s.errorLimit
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now := s.clock.Now(); now.Sub(s.windowStart) >= time.Second {
+		s.windowStart = now
+		clear(s.counts)
+	}
+	key := sampleKey{level: entry.Level, message: entry.Message}
+	s.counts[key]++
+	if s.counts[key] <= limit {
+		return true
+	}
+	s.suppressed[key]++
+	return false
+}
+
+// summarize menulis satu entry per pesan yang dibuang sejak ringkasan
+// sebelumnya.
+func (s *sampler) summarize() {
+	s.mu.Lock()
+	suppressed := s.suppressed
+	s.suppressed = make(map[sampleKey]int)
+	s.mu.Unlock()
+
+	now := s.clock.Now()
+	for key, count := range suppressed {
+		entry := zapcore.Entry{
+			Level:   zapcore.WarnLevel,
+			Time:    now,
+			Message: fmt.Sprintf("Suppressed %d repeated log entries", count),
+		}
+		if checked := s.out.Check(entry, nil); checked != nil {
+			checked.Write(
+				zap.String("suppressed_level", key.level.String()),
+				zap.String("suppressed_message", key.message),
+				zap.Int("suppressed_count", count),
+			)
+		}
+	}
+}
+
+// Close menghentikan goroutine ringkasan setelah menulis ringkasan terakhir.
+func (s *sampler) Close() {
+	s.once.Do(func() { close(s.stop) })
+	<-s.done
+}
+
+// samplingCore adalah zapcore.Core yang membuang entry yang melewati batas
+// sampler. Core hasil With memakai sampler yang sama.
+type samplingCore struct {
+	zapcore.Core
+	sampler *sampler
+}
+
+func (c *samplingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &samplingCore{Core: c.Core.With(fields), sampler: c.sampler}
+}
+
+func (c *samplingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Core.Enabled(entry.Level) || !c.sampler.allow(entry) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
@@ -0,0 +1,149 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/clock/clocktest"
+	"github.com/levensspel/go-gin-template/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var samplerStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// newSampledLogger membuat logger dengan sampler di atas observer. Batas
+// 3 per detik untuk Warn ke bawah dan 5 untuk Error ke atas, ringkasan
+// setiap 10 detik.
+func newSampledLogger(t *testing.T) (*zap.Logger, *sampler, *clocktest.Fake, *observer.ObservedLogs) {
+	t.Helper()
+	fake := clocktest.NewFake(samplerStart)
+	out, logs := observer.New(zapcore.DebugLevel)
+	s := newSampler(out, fake, 3, 5, 10*time.Second)
+	t.Cleanup(s.Close)
+	// Tunggu ticker ringkasan terpasang sebelum waktu dimajukan
+	fake.BlockUntil(1)
+	return zap.New(&samplingCore{Core: out, sampler: s}), s, fake, logs
+}
+
+// waitForSummaries menunggu sampai ada n entry ringkasan.
+func waitForSummaries(t *testing.T, logs *observer.ObservedLogs, n int) []observer.LoggedEntry {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		summaries := logs.FilterFieldKey("suppressed_count").All()
+		if len(summaries) >= n {
+			return summaries
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("got %d summaries, want %d", len(summaries), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSamplerLimitsPerSecond(t *testing.T) {
+	log, _, fake, logs := newSampledLogger(t)
+
+	for range 10 {
+		log.Info("database unavailable")
+	}
+	log.Info("other message")
+	for range 10 {
+		log.Error("database unavailable")
+	}
+	if got := logs.FilterMessage("database unavailable").FilterLevelExact(zapcore.InfoLevel).Len(); got != 3 {
+		t.Errorf("info entries written = %d, want 3", got)
+	}
+	// Error punya batas sendiri yang lebih tinggi, bukan dikecualikan
+	if got := logs.FilterMessage("database unavailable").FilterLevelExact(zapcore.ErrorLevel).Len(); got != 5 {
+		t.Errorf("error entries written = %d, want 5", got)
+	}
+	if got := logs.FilterMessage("other message").Len(); got != 1 {
+		t.Errorf("other message written = %d, want 1", got)
+	}
+
+	// Window berikutnya dihitung dari awal
+	fake.Advance(time.Second)
+	for range 10 {
+		log.Info("database unavailable")
+	}
+	if got := logs.FilterMessage("database unavailable").FilterLevelExact(zapcore.InfoLevel).Len(); got != 6 {
+		t.Errorf("info entries written after the next second = %d, want 6", got)
+	}
+}
+
+func TestSamplerSummarizesSuppressedEntries(t *testing.T) {
+	log, _, fake, logs := newSampledLogger(t)
+
+	for range 10 {
+		log.Info("database unavailable")
+	}
+	fake.Advance(time.Second)
+	for range 4 {
+		log.Info("database unavailable")
+	}
+	for range 7 {
+		log.Error("query timeout")
+	}
+
+	fake.Advance(9 * time.Second)
+	summaries := waitForSummaries(t, logs, 2)
+	counts := map[string]int64{}
+	for _, entry := range summaries {
+		if entry.Level != zapcore.WarnLevel {
+			t.Errorf("summary level = %s, want warn", entry.Level)
+		}
+		fields := entry.ContextMap()
+		counts[fields["suppressed_level"].(string)+" "+fields["suppressed_message"].(string)] = fields["suppressed_count"].(int64)
+	}
+	// 7 + 1 info dibuang di dua window, 2 error di atas batas 5
+	if counts["info database unavailable"] != 8 || counts["error query timeout"] != 2 || len(counts) != 2 {
+		t.Fatalf("summaries = %v", counts)
+	}
+
+	// Tanpa entry yang dibuang, interval berikutnya tidak menulis ringkasan
+	fake.Advance(10 * time.Second)
+	time.Sleep(10 * time.Millisecond)
+	if got := logs.FilterFieldKey("suppressed_count").Len(); got != 2 {
+		t.Fatalf("summaries after a quiet interval = %d, want 2", got)
+	}
+}
+
+func TestSamplerWritesFinalSummaryOnClose(t *testing.T) {
+	log, s, _, logs := newSampledLogger(t)
+	for range 5 {
+		log.Warn("retrying")
+	}
+	s.Close()
+
+	summaries := logs.FilterFieldKey("suppressed_count").All()
+	if len(summaries) != 1 || summaries[0].ContextMap()["suppressed_count"] != int64(2) {
+		t.Fatalf("summaries after Close = %v, want one for 2 entries", summaries)
+	}
+}
+
+func TestNewlogHandlerSampling(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		config config.LoggerConfig
+		want   int
+	}{
+		// 2 entry lolos ditambah satu ringkasan saat Shutdown
+		{name: "enabled", config: config.LoggerConfig{Sampling: true, SamplingPerSecond: 2, SamplingErrorPerSecond: 2, SamplingSummaryInterval: time.Hour}, want: 3},
+		{name: "disabled", config: config.LoggerConfig{}, want: 10},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := inTempDir(t)
+			fake := clocktest.NewFake(samplerStart)
+			handler := NewlogHandler(&tt.config, NewLevel(zapcore.InfoLevel, fake), fake, func() {})
+			for range 10 {
+				handler.Info("database unavailable", "logger.TestNewlogHandlerSampling")
+			}
+			if got := len(readEntries(t, dir, handler)); got != tt.want {
+				t.Fatalf("logged %d entries, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"log"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	DefaultSlowRequestThreshold = time.Second
+	DefaultSlowQueryThreshold   = 500 * time.Millisecond
+)
+
+// Threshold disimpan secara atomic agar bisa diubah saat runtime
+// (mis. lewat endpoint admin) tanpa restart.
+var (
+	slowRequestThreshold atomic.Int64
+	slowQueryThreshold   atomic.Int64
+)
+
+func init() {
+	slowRequestThreshold.Store(int64(envDuration("SLOW_REQUEST_THRESHOLD", DefaultSlowRequestThreshold)))
+	slowQueryThreshold.Store(int64(envDuration("SLOW_QUERY_THRESHOLD", DefaultSlowQueryThreshold)))
+}
+
+func SlowRequestThreshold() time.Duration {
+	return time.Duration(slowRequestThreshold.Load())
+}
+
+func SetSlowRequestThreshold(d time.Duration) {
+	slowRequestThreshold.Store(int64(d))
+}
+
+func SlowQueryThreshold() time.Duration {
+	return time.Duration(slowQueryThreshold.Load())
+}
+
+func SetSlowQueryThreshold(d time.Duration) {
+	slowQueryThreshold.Store(int64(d))
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Printf("Invalid duration for %s: %q, using default %s", key, value, fallback)
+		return fallback
+	}
+	return d
+}
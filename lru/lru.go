@@ -0,0 +1,232 @@
+package lru
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/levensspel/go-gin-template/clock"
+)
+
+var errLoaderPanicked = errors.New("lru: loader panicked")
+
+type Settings struct {
+	// Name dipakai sebagai label metric.
+	Name string
+	// MaxEntries adalah jumlah entry maksimum. Entry yang paling lama tidak
+	// dipakai dikeluarkan saat cache penuh.
+	MaxEntries int
+	// TTL adalah umur entry sejak disimpan, bukan sejak terakhir dibaca.
+	TTL time.Duration
+	// OnHit, OnMiss dan OnEvict dipanggil di luar lock. OnEvict hanya untuk
+	// entry yang dikeluarkan karena cache penuh, bukan karena kedaluwarsa
+	// atau di-invalidate.
+	OnHit   func(name string)
+	OnMiss  func(name string)
+	OnEvict func(name string, count int)
+	// Clock adalah sumber waktu TTL. Default: clock.Real().
+	Clock clock.Clock
+}
+
+// Cache adalah cache in-process dengan TTL dan batas ukuran (LRU). Aman
+// dipakai dari banyak goroutine.
+type Cache[K comparable, V any] struct {
+	settings Settings
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[K]*list.Element
+	order   *list.List
+	loads   map[K]*load[V]
+	// generation naik setiap invalidasi, agar hasil loader yang dimulai
+	// sebelum invalidasi tidak disimpan.
+	generation uint64
+}
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+type load[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+func New[K comparable, V any](settings Settings) *Cache[K, V] {
+	if settings.MaxEntries < 1 {
+		settings.MaxEntries = 1
+	}
+	if settings.Clock == nil {
+		settings.Clock = clock.Real()
+	}
+	return &Cache[K, V]{
+		settings: settings,
+		now:      settings.Clock.Now,
+		entries:  make(map[K]*list.Element),
+		order:    list.New(),
+		loads:    make(map[K]*load[V]),
+	}
+}
+
+func (c *Cache[K, V]) Name() string {
+	return c.settings.Name
+}
+
+// Get mengembalikan value yang belum kedaluwarsa.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	value, ok := c.get(key)
+	c.mu.Unlock()
+
+	if ok {
+		c.notify(c.settings.OnHit)
+	} else {
+		c.notify(c.settings.OnMiss)
+	}
+	return value, ok
+}
+
+// Set menyimpan value dan memperbarui umurnya.
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	evicted := c.set(key, value)
+	c.mu.Unlock()
+
+	c.notifyEvict(evicted)
+}
+
+// GetOrLoad mengembalikan value dari cache, atau memanggil loader jika
+// tidak ada. Miss bersamaan untuk key yang sama hanya memanggil loader
+// sekali, goroutine lain menunggu hasilnya. Error tidak disimpan.
+func (c *Cache[K, V]) GetOrLoad(key K, loader func() (V, error)) (V, error) {
+	c.mu.Lock()
+	if value, ok := c.get(key); ok {
+		c.mu.Unlock()
+		c.notify(c.settings.OnHit)
+		return value, nil
+	}
+	if pending, ok := c.loads[key]; ok {
+		c.mu.Unlock()
+		c.notify(c.settings.OnMiss)
+		<-pending.done
+		return pending.value, pending.err
+	}
+	pending := &load[V]{done: make(chan struct{})}
+	c.loads[key] = pending
+	generation := c.generation
+	c.mu.Unlock()
+	c.notify(c.settings.OnMiss)
+
+	// Jika loader panic, goroutine yang menunggu menerima errLoaderPanicked
+	// dan tidak ada yang disimpan.
+	pending.err = errLoaderPanicked
+	evicted := 0
+	defer func() {
+		c.mu.Lock()
+		delete(c.loads, key)
+		if pending.err == nil && generation == c.generation {
+			evicted = c.set(key, pending.value)
+		}
+		c.mu.Unlock()
+		close(pending.done)
+		c.notifyEvict(evicted)
+	}()
+
+	value, err := loader()
+	pending.value, pending.err = value, err
+	return value, err
+}
+
+// Invalidate menghapus satu key.
+func (c *Cache[K, V]) Invalidate(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+}
+
+// InvalidateFunc menghapus semua key yang memenuhi match, mis. semua entry
+// milik satu manager.
+func (c *Cache[K, V]) InvalidateFunc(match func(key K) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	for key, element := range c.entries {
+		if match(key) {
+			c.remove(element)
+		}
+	}
+}
+
+// Len mengembalikan jumlah entry, termasuk yang sudah kedaluwarsa tetapi
+// belum dibaca.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// get harus dipanggil dengan lock.
+func (c *Cache[K, V]) get(key K) (V, bool) {
+	element, ok := c.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	item := element.Value.(*entry[K, V])
+	if !c.now().Before(item.expiresAt) {
+		c.remove(element)
+		var zero V
+		return zero, false
+	}
+	c.order.MoveToFront(element)
+	return item.value, true
+}
+
+// set harus dipanggil dengan lock. Mengembalikan jumlah entry yang
+// dikeluarkan karena cache penuh.
+func (c *Cache[K, V]) set(key K, value V) int {
+	expiresAt := c.now().Add(c.settings.TTL)
+	if element, ok := c.entries[key]; ok {
+		item := element.Value.(*entry[K, V])
+		item.value = value
+		item.expiresAt = expiresAt
+		c.order.MoveToFront(element)
+		return 0
+	}
+
+	c.entries[key] = c.order.PushFront(&entry[K, V]{key: key, value: value, expiresAt: expiresAt})
+
+	evicted := 0
+	for c.order.Len() > c.settings.MaxEntries {
+		c.remove(c.order.Back())
+		evicted++
+	}
+	return evicted
+}
+
+// remove harus dipanggil dengan lock.
+func (c *Cache[K, V]) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*entry[K, V]).key)
+}
+
+func (c *Cache[K, V]) notify(callback func(name string)) {
+	if callback != nil {
+		callback(c.settings.Name)
+	}
+}
+
+func (c *Cache[K, V]) notifyEvict(count int) {
+	if count > 0 && c.settings.OnEvict != nil {
+		c.settings.OnEvict(c.settings.Name, count)
+	}
+}
@@ -0,0 +1,342 @@
+package lru
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/clock/clocktest"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// counters mencatat callback Settings.
+type counters struct {
+	hits, misses, evictions atomic.Int64
+}
+
+func newTestCache(maxEntries int, ttl time.Duration) (*Cache[string, int], *clocktest.Fake, *counters) {
+	clock := clocktest.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	counted := &counters{}
+	c := New[string, int](Settings{
+		Name:       "test",
+		MaxEntries: maxEntries,
+		TTL:        ttl,
+		OnHit:      func(string) { counted.hits.Add(1) },
+		OnMiss:     func(string) { counted.misses.Add(1) },
+		OnEvict:    func(_ string, count int) { counted.evictions.Add(int64(count)) },
+		Clock:      clock,
+	})
+	return c, clock, counted
+}
+
+func wantValue(t *testing.T, c *Cache[string, int], key string, want int, wantOK bool) {
+	t.Helper()
+	got, ok := c.Get(key)
+	if ok != wantOK || got != want {
+		t.Fatalf("Get(%q) = %d, %t, want %d, %t", key, got, ok, want, wantOK)
+	}
+}
+
+func TestGetSet(t *testing.T) {
+	c, _, counted := newTestCache(10, time.Minute)
+
+	wantValue(t, c, "a", 0, false)
+	c.Set("a", 1)
+	wantValue(t, c, "a", 1, true)
+	c.Set("a", 2)
+	wantValue(t, c, "a", 2, true)
+
+	if hits, misses := counted.hits.Load(), counted.misses.Load(); hits != 2 || misses != 1 {
+		t.Fatalf("hits, misses = %d, %d, want 2, 1", hits, misses)
+	}
+	if c.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", c.Len())
+	}
+}
+
+func TestTTL(t *testing.T) {
+	c, clock, _ := newTestCache(10, time.Minute)
+	c.Set("a", 1)
+
+	clock.Advance(59 * time.Second)
+	wantValue(t, c, "a", 1, true)
+	// TTL dihitung sejak disimpan, membaca tidak memperpanjangnya
+	clock.Advance(time.Second)
+	wantValue(t, c, "a", 0, false)
+	if c.Len() != 0 {
+		t.Fatalf("Len() = %d, want the expired entry removed", c.Len())
+	}
+
+	c.Set("a", 2)
+	clock.Advance(30 * time.Second)
+	c.Set("a", 3)
+	clock.Advance(45 * time.Second)
+	wantValue(t, c, "a", 3, true)
+}
+
+func TestEvictsLeastRecentlyUsed(t *testing.T) {
+	c, _, counted := newTestCache(2, time.Minute)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	wantValue(t, c, "a", 1, true)
+	c.Set("c", 3)
+
+	wantValue(t, c, "b", 0, false)
+	wantValue(t, c, "a", 1, true)
+	wantValue(t, c, "c", 3, true)
+	if got := counted.evictions.Load(); got != 1 {
+		t.Fatalf("evictions = %d, want 1", got)
+	}
+}
+
+func TestNewMinimumOneEntry(t *testing.T) {
+	c := New[string, int](Settings{TTL: time.Minute})
+	c.Set("a", 1)
+	c.Set("b", 2)
+	if c.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", c.Len())
+	}
+}
+
+func TestInvalidate(t *testing.T) {
+	c, _, counted := newTestCache(10, time.Minute)
+	c.Set("manager-1:page-1", 1)
+	c.Set("manager-1:page-2", 2)
+	c.Set("manager-2:page-1", 3)
+
+	c.Invalidate("manager-2:page-1")
+	wantValue(t, c, "manager-2:page-1", 0, false)
+
+	c.InvalidateFunc(func(key string) bool { return key[:9] == "manager-1" })
+	wantValue(t, c, "manager-1:page-1", 0, false)
+	wantValue(t, c, "manager-1:page-2", 0, false)
+	if c.Len() != 0 {
+		t.Fatalf("Len() = %d, want 0", c.Len())
+	}
+	if got := counted.evictions.Load(); got != 0 {
+		t.Fatalf("evictions = %d, want invalidation not counted as eviction", got)
+	}
+}
+
+func TestGetOrLoad(t *testing.T) {
+	c, _, counted := newTestCache(10, time.Minute)
+	loads := 0
+	loader := func() (int, error) {
+		loads++
+		return 7, nil
+	}
+
+	for range 2 {
+		got, err := c.GetOrLoad("a", loader)
+		if err != nil || got != 7 {
+			t.Fatalf("GetOrLoad() = %d, %v, want 7, nil", got, err)
+		}
+	}
+	if loads != 1 {
+		t.Fatalf("loader called %d times, want 1", loads)
+	}
+	if hits, misses := counted.hits.Load(), counted.misses.Load(); hits != 1 || misses != 1 {
+		t.Fatalf("hits, misses = %d, %d, want 1, 1", hits, misses)
+	}
+}
+
+func TestGetOrLoadDoesNotCacheErrors(t *testing.T) {
+	c, _, _ := newTestCache(10, time.Minute)
+	errLoad := errors.New("load failed")
+
+	if _, err := c.GetOrLoad("a", func() (int, error) { return 0, errLoad }); !errors.Is(err, errLoad) {
+		t.Fatalf("GetOrLoad() error = %v, want %v", err, errLoad)
+	}
+	got, err := c.GetOrLoad("a", func() (int, error) { return 2, nil })
+	if err != nil || got != 2 {
+		t.Fatalf("GetOrLoad() after error = %d, %v, want 2, nil", got, err)
+	}
+}
+
+func TestGetOrLoadCollapsesConcurrentMisses(t *testing.T) {
+	c, _, _ := newTestCache(10, time.Minute)
+	const callers = 20
+
+	var loads atomic.Int64
+	release := make(chan struct{})
+	started := make(chan struct{})
+	loader := func() (int, error) {
+		if loads.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		return 42, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make(chan int, callers)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		value, _ := c.GetOrLoad("a", loader)
+		results <- value
+	}()
+	<-started
+	for range callers - 1 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, _ := c.GetOrLoad("a", loader)
+			results <- value
+		}()
+	}
+	// Beri waktu caller lain untuk ikut menunggu load yang sedang berjalan
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(results)
+
+	if got := loads.Load(); got != 1 {
+		t.Fatalf("loader called %d times, want 1", got)
+	}
+	for value := range results {
+		if value != 42 {
+			t.Fatalf("GetOrLoad() = %d, want 42", value)
+		}
+	}
+}
+
+func TestGetOrLoadPanicReleasesWaiters(t *testing.T) {
+	c, _, _ := newTestCache(10, time.Minute)
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	panicked := make(chan any, 1)
+	go func() {
+		defer func() { panicked <- recover() }()
+		c.GetOrLoad("a", func() (int, error) {
+			close(started)
+			<-release
+			panic("boom")
+		})
+	}()
+	<-started
+
+	waiter := make(chan error, 1)
+	go func() {
+		_, err := c.GetOrLoad("a", func() (int, error) { return 1, nil })
+		waiter <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	if recovered := <-panicked; recovered != "boom" {
+		t.Fatalf("recovered = %v, want the loader panic", recovered)
+	}
+	select {
+	case err := <-waiter:
+		// Waiter yang datang setelah load selesai memanggil loader-nya sendiri
+		if err != nil && !errors.Is(err, errLoaderPanicked) {
+			t.Fatalf("waiter error = %v, want nil or %v", err, errLoaderPanicked)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("waiter is still blocked after the loader panicked")
+	}
+	if c.Len() > 1 {
+		t.Fatalf("Len() = %d", c.Len())
+	}
+}
+
+// TestInvalidateDuringLoad memastikan hasil loader yang dimulai sebelum
+// invalidasi tidak disimpan, agar data lama tidak kembali ke cache.
+func TestInvalidateDuringLoad(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		invalidate func(c *Cache[string, int])
+	}{
+		{name: "Invalidate", invalidate: func(c *Cache[string, int]) { c.Invalidate("a") }},
+		{name: "InvalidateFunc", invalidate: func(c *Cache[string, int]) { c.InvalidateFunc(func(string) bool { return true }) }},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _, _ := newTestCache(10, time.Minute)
+			got, err := c.GetOrLoad("a", func() (int, error) {
+				tt.invalidate(c)
+				return 1, nil
+			})
+			if err != nil || got != 1 {
+				t.Fatalf("GetOrLoad() = %d, %v, want 1, nil", got, err)
+			}
+			wantValue(t, c, "a", 0, false)
+		})
+	}
+}
+
+func TestMetricsCallbacks(t *testing.T) {
+	m := metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{})
+	c := New[string, int](Settings{
+		Name:       "metrics_test",
+		MaxEntries: 1,
+		TTL:        time.Minute,
+		OnHit:      m.CountCacheHit,
+		OnMiss:     m.CountCacheMiss,
+		OnEvict:    m.CountCacheEvictions,
+	})
+	c.Get("a")
+	c.Set("a", 1)
+	c.Get("a")
+	c.Set("b", 2)
+
+	for _, tt := range []struct {
+		name      string
+		collector *prometheus.CounterVec
+	}{
+		{name: "hits", collector: m.CacheHits},
+		{name: "misses", collector: m.CacheMisses},
+		{name: "evictions", collector: m.CacheEvictions},
+	} {
+		if got := testutil.ToFloat64(tt.collector.WithLabelValues("metrics_test")); got != 1 {
+			t.Fatalf("%s = %v, want 1", tt.name, got)
+		}
+	}
+}
+
+// TestConcurrentAccess dijalankan dengan -race: Get, Set, GetOrLoad dan
+// invalidasi dari banyak goroutine tidak boleh balapan atau membuat cache
+// melebihi MaxEntries.
+func TestConcurrentAccess(t *testing.T) {
+	c := New[string, int](Settings{Name: "race", MaxEntries: 16, TTL: time.Millisecond})
+	const workers = 16
+	const iterations = 2000
+
+	var wg sync.WaitGroup
+	for worker := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range iterations {
+				key := fmt.Sprintf("key-%d", (worker+i)%32)
+				switch i % 5 {
+				case 0:
+					c.Set(key, i)
+				case 1:
+					c.Get(key)
+				case 2:
+					if _, err := c.GetOrLoad(key, func() (int, error) { return i, nil }); err != nil {
+						t.Errorf("GetOrLoad() error = %v", err)
+						return
+					}
+				case 3:
+					c.Invalidate(key)
+				case 4:
+					c.InvalidateFunc(func(k string) bool { return k == key })
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if c.Len() > 16 {
+		t.Fatalf("Len() = %d, want at most MaxEntries", c.Len())
+	}
+}
@@ -2,12 +2,9 @@ package main
 
 import (
 	"fmt"
-	"github.com/levensspel/go-gin-template/cache"
-	"github.com/levensspel/go-gin-template/di"
 	"log"
-	"os"
-	"os/signal"
-	"syscall"
+
+	"github.com/levensspel/go-gin-template/di"
 
 	_ "github.com/joho/godotenv/autoload"
 	"github.com/levensspel/go-gin-template/server"
@@ -16,17 +13,7 @@ import (
 func main() {
 	healthCheckDI()
 
-	// Handle graceful shutdown
-	sig := make(chan os.Signal, 1)
-	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
-	defer cache.Cache.Close()
-
-	go func() {
-		<-sig
-		cache.Cache.Close()
-		os.Exit(0)
-	}()
-
+	// Graceful shutdown (SIGTERM/SIGINT) ditangani di dalam server.Start
 	err := server.Start()
 	if err != nil {
 		log.Fatalln(err)
@@ -0,0 +1,414 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/samber/do/v2"
+)
+
+const namespace = "ps3t"
+
+// Stage untuk histogram durasi, dipakai sebagai label agar waktu validasi
+// dan waktu repository bisa dibedakan.
+const (
+	StageTotal  = "total"
+	StageInsert = "insert"
+	StageQuery  = "query"
+)
+
+// allManagers adalah label manager jika config.MetricsConfig.PerManager mati.
+const allManagers = "all"
+
+// Metrics menyimpan semua metric aplikasi dalam satu registry, sehingga
+// instance baru (mis. untuk test) tidak bentrok dengan registry global.
+type Metrics struct {
+	Registry *prometheus.Registry
+	// perManager mengisi label manager dengan manager id, lihat managerLabel
+	perManager bool
+
+	EmployeeCreateDuration *prometheus.HistogramVec
+	EmployeeListDuration   *prometheus.HistogramVec
+	ErrorsReturned         *prometheus.CounterVec
+	CircuitBreakerState    *prometheus.GaugeVec
+	QueryDuration          *prometheus.HistogramVec
+	QueryErrors            *prometheus.CounterVec
+	RequestQueries         *prometheus.HistogramVec
+	CacheHits              *prometheus.CounterVec
+	CacheMisses            *prometheus.CounterVec
+	CacheEvictions         *prometheus.CounterVec
+	PoolAcquiredConns      *prometheus.GaugeVec
+	PoolIdleConns          *prometheus.GaugeVec
+	PoolTotalConns         *prometheus.GaugeVec
+	PoolEmptyAcquires      *prometheus.CounterVec
+	PoolAcquireDuration    *prometheus.CounterVec
+	OutboxEvents           *prometheus.CounterVec
+	WebhookDeliveries      *prometheus.CounterVec
+	CleanedUpRows          *prometheus.CounterVec
+	CleanupDuration        *prometheus.HistogramVec
+	WorkerQueueDepth       prometheus.Gauge
+	WorkerTaskDuration     *prometheus.HistogramVec
+	WorkerTasksDropped     *prometheus.CounterVec
+	PurgedRows             *prometheus.CounterVec
+	LogsDropped            prometheus.Counter
+	GRPCRequests           *prometheus.CounterVec
+	GRPCRequestDuration    *prometheus.HistogramVec
+
+	// Metric bisnis, dihitung di service setelah operasi berhasil
+	EmployeesCreated   *prometheus.CounterVec
+	EmployeesUpdated   *prometheus.CounterVec
+	EmployeesDeleted   *prometheus.CounterVec
+	DepartmentsCreated *prometheus.CounterVec
+	AuthLogins         *prometheus.CounterVec
+}
+
+func New(registry *prometheus.Registry, config *config.MetricsConfig) *Metrics {
+	m := &Metrics{
+		Registry:   registry,
+		perManager: config.PerManager,
+		EmployeeCreateDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "employee_create_duration_seconds",
+			Help:      "Duration of EmployeeService.Create by stage.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"stage"}),
+		EmployeeListDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "employee_list_duration_seconds",
+			Help:      "Duration of EmployeeService.GetAll by stage.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"stage"}),
+		// Label hanya berisi error key dari helper, tidak pernah pesan error
+		// mentah, agar kardinalitas tetap terbatas.
+		ErrorsReturned: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "errors_returned_total",
+			Help:      "Errors returned by the service layer, labelled by helper error key.",
+		}, []string{"operation", "error"}),
+		CircuitBreakerState: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "circuit_breaker_state",
+			Help:      "Circuit breaker state: 0 closed, 1 half-open, 2 open.",
+		}, []string{"name"}),
+		// Label query berasal dari database.QueryName, bukan SQL mentah
+		QueryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "Duration of SQL statements by query name.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"query"}),
+		QueryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "db_query_errors_total",
+			Help:      "Failed SQL statements by query name and SQLSTATE.",
+		}, []string{"query", "sqlstate"}),
+		// Label route berasal dari pola route gin, bukan path mentah
+		RequestQueries: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_db_queries",
+			Help:      "SQL statements executed per HTTP request, by route.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 10),
+		}, []string{"route"}),
+		CacheHits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_hits_total",
+			Help:      "In-process cache hits by cache name.",
+		}, []string{"cache"}),
+		CacheMisses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_misses_total",
+			Help:      "In-process cache misses by cache name.",
+		}, []string{"cache"}),
+		CacheEvictions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_evictions_total",
+			Help:      "Entries evicted because the in-process cache was full, by cache name.",
+		}, []string{"cache"}),
+		// Label pool: primary atau replica
+		PoolAcquiredConns: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "db_pool_acquired_conns",
+			Help:      "Connections currently acquired from the pool.",
+		}, []string{"pool"}),
+		PoolIdleConns: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "db_pool_idle_conns",
+			Help:      "Idle connections in the pool.",
+		}, []string{"pool"}),
+		PoolTotalConns: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "db_pool_total_conns",
+			Help:      "Total connections in the pool, including those being opened.",
+		}, []string{"pool"}),
+		PoolEmptyAcquires: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "db_pool_empty_acquire_total",
+			Help:      "Acquires that had to wait because the pool had no idle connection.",
+		}, []string{"pool"}),
+		PoolAcquireDuration: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "db_pool_acquire_duration_seconds_total",
+			Help:      "Cumulative time spent acquiring connections from the pool.",
+		}, []string{"pool"}),
+		OutboxEvents: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "outbox_events_total",
+			Help:      "Outbox events handed to the publisher, by event type and result (sent or failed).",
+		}, []string{"event", "result"}),
+		WebhookDeliveries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "webhook_deliveries_total",
+			Help:      "Webhook delivery attempts, by event type and result (delivered, failed or dead).",
+		}, []string{"event", "result"}),
+		WorkerQueueDepth: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "worker_queue_depth",
+			Help:      "Tasks waiting in the background worker queue.",
+		}),
+		WorkerTaskDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "worker_task_duration_seconds",
+			Help:      "Duration of background tasks by task name and result (ok, error, timeout or panic).",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"task", "result"}),
+		WorkerTasksDropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "worker_tasks_dropped_total",
+			Help:      "Background tasks that never ran, by task name and reason (queue_full, closed or shutdown).",
+		}, []string{"task", "reason"}),
+		PurgedRows: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "purged_rows_total",
+			Help:      "Soft-deleted rows hard-deleted by the purge job, by table.",
+		}, []string{"table"}),
+		CleanedUpRows: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cleaned_up_rows_total",
+			Help:      "Expired rows deleted by the cleanup job, by table.",
+		}, []string{"table"}),
+		CleanupDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "cleanup_duration_seconds",
+			Help:      "Duration of one cleanup run, by table.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"table"}),
+		LogsDropped: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "logs_dropped_total",
+			Help:      "Log entries dropped because the async log buffer was full.",
+		}),
+		GRPCRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "grpc_requests_total",
+			Help:      "gRPC requests handled, by full method and status code.",
+		}, []string{"method", "code"}),
+		GRPCRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "grpc_request_duration_seconds",
+			Help:      "Duration of gRPC requests, by full method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		EmployeesCreated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "employees_created_total",
+			Help:      "Employees created, including bulk create and CSV import.",
+		}, []string{"manager"}),
+		EmployeesUpdated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "employees_updated_total",
+			Help:      "Employees updated.",
+		}, []string{"manager"}),
+		EmployeesDeleted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "employees_deleted_total",
+			Help:      "Employees deleted.",
+		}, []string{"manager"}),
+		DepartmentsCreated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "departments_created_total",
+			Help:      "Departments created.",
+		}, []string{"manager"}),
+		AuthLogins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "auth_logins_total",
+			Help:      "Login attempts that passed validation, by result (success or failure).",
+		}, []string{"result"}),
+	}
+
+	registry.MustRegister(
+		m.EmployeeCreateDuration,
+		m.EmployeeListDuration,
+		m.ErrorsReturned,
+		m.CircuitBreakerState,
+		m.QueryDuration,
+		m.QueryErrors,
+		m.RequestQueries,
+		m.CacheHits,
+		m.CacheMisses,
+		m.CacheEvictions,
+		m.PoolAcquiredConns,
+		m.PoolIdleConns,
+		m.PoolTotalConns,
+		m.PoolEmptyAcquires,
+		m.PoolAcquireDuration,
+		m.OutboxEvents,
+		m.WebhookDeliveries,
+		m.WorkerQueueDepth,
+		m.WorkerTaskDuration,
+		m.WorkerTasksDropped,
+		m.PurgedRows,
+		m.CleanedUpRows,
+		m.CleanupDuration,
+		m.LogsDropped,
+		m.GRPCRequests,
+		m.GRPCRequestDuration,
+		m.EmployeesCreated,
+		m.EmployeesUpdated,
+		m.EmployeesDeleted,
+		m.DepartmentsCreated,
+		m.AuthLogins,
+	)
+	return m
+}
+
+func NewInject(i do.Injector) (*Metrics, error) {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return New(registry, config.LoadMetricsConfig()), nil
+}
+
+// Since mencatat durasi sejak start ke histogram pada stage tertentu.
+func Since(histogram *prometheus.HistogramVec, stage string, start time.Time) {
+	histogram.WithLabelValues(stage).Observe(time.Since(start).Seconds())
+}
+
+// CountError menaikkan counter error berdasarkan error key helper.
+func (m *Metrics) CountError(operation helper.FunctionCaller, err error) {
+	if err == nil {
+		return
+	}
+	m.ErrorsReturned.WithLabelValues(string(operation), helper.GetErrorKey(err)).Inc()
+}
+
+// SetBreakerState mencatat state circuit breaker dengan nama tertentu.
+func (m *Metrics) SetBreakerState(name string, state int) {
+	m.CircuitBreakerState.WithLabelValues(name).Set(float64(state))
+}
+
+// CountCacheHit, CountCacheMiss dan CountCacheEvictions sesuai dengan
+// callback lru.Settings.
+func (m *Metrics) CountCacheHit(name string) {
+	m.CacheHits.WithLabelValues(name).Inc()
+}
+
+func (m *Metrics) CountCacheMiss(name string) {
+	m.CacheMisses.WithLabelValues(name).Inc()
+}
+
+func (m *Metrics) CountCacheEvictions(name string, count int) {
+	m.CacheEvictions.WithLabelValues(name).Add(float64(count))
+}
+
+// CountOutboxEvent mencatat hasil satu pengiriman event outbox.
+func (m *Metrics) CountOutboxEvent(eventType string, sent bool) {
+	result := "sent"
+	if !sent {
+		result = "failed"
+	}
+	m.OutboxEvents.WithLabelValues(eventType, result).Inc()
+}
+
+// CountWebhookDelivery mencatat hasil satu percobaan pengiriman webhook.
+func (m *Metrics) CountWebhookDelivery(eventType, result string) {
+	m.WebhookDeliveries.WithLabelValues(eventType, result).Inc()
+}
+
+// CountCleanedUp mencatat jumlah baris kedaluwarsa yang dihapus dari table.
+func (m *Metrics) CountCleanedUp(table string, rows int64) {
+	m.CleanedUpRows.WithLabelValues(table).Add(float64(rows))
+}
+
+// ObserveCleanup mencatat durasi satu cleanup table.
+func (m *Metrics) ObserveCleanup(table string, duration time.Duration) {
+	m.CleanupDuration.WithLabelValues(table).Observe(duration.Seconds())
+}
+
+// ObserveWorkerTask mencatat durasi dan hasil satu task background.
+func (m *Metrics) ObserveWorkerTask(task, result string, duration time.Duration) {
+	m.WorkerTaskDuration.WithLabelValues(task, result).Observe(duration.Seconds())
+}
+
+// CountWorkerTaskDropped mencatat task yang tidak pernah dijalankan.
+func (m *Metrics) CountWorkerTaskDropped(task, reason string) {
+	m.WorkerTasksDropped.WithLabelValues(task, reason).Inc()
+}
+
+// CountPurged mencatat jumlah baris yang dihapus permanen dari table.
+func (m *Metrics) CountPurged(table string, rows int64) {
+	m.PurgedRows.WithLabelValues(table).Add(float64(rows))
+}
+
+// CountLogDropped dipanggil logger async setiap membuang satu entry.
+func (m *Metrics) CountLogDropped() {
+	m.LogsDropped.Inc()
+}
+
+// ObserveGRPCRequest mencatat status dan durasi satu request gRPC.
+func (m *Metrics) ObserveGRPCRequest(method, code string, duration time.Duration) {
+	m.GRPCRequests.WithLabelValues(method, code).Inc()
+	m.GRPCRequestDuration.WithLabelValues(method).Observe(duration.Seconds())
+}
+
+// ObserveRequestQueries mencatat jumlah query satu request HTTP.
+func (m *Metrics) ObserveRequestQueries(route string, count int) {
+	m.RequestQueries.WithLabelValues(route).Observe(float64(count))
+}
+
+// managerLabel mengembalikan nilai label manager untuk metric bisnis.
+func (m *Metrics) managerLabel(managerID string) string {
+	if !m.perManager {
+		return allManagers
+	}
+	return managerID
+}
+
+// CountEmployeesCreated mencatat count employee baru milik manager.
+func (m *Metrics) CountEmployeesCreated(managerID string, count int) {
+	if count > 0 {
+		m.EmployeesCreated.WithLabelValues(m.managerLabel(managerID)).Add(float64(count))
+	}
+}
+
+func (m *Metrics) CountEmployeeUpdated(managerID string) {
+	m.EmployeesUpdated.WithLabelValues(m.managerLabel(managerID)).Inc()
+}
+
+func (m *Metrics) CountEmployeeDeleted(managerID string) {
+	m.EmployeesDeleted.WithLabelValues(m.managerLabel(managerID)).Inc()
+}
+
+func (m *Metrics) CountDepartmentCreated(managerID string) {
+	m.DepartmentsCreated.WithLabelValues(m.managerLabel(managerID)).Inc()
+}
+
+// CountLogin mencatat hasil login yang lolos validasi input.
+func (m *Metrics) CountLogin(success bool) {
+	result := "success"
+	if !success {
+		result = "failure"
+	}
+	m.AuthLogins.WithLabelValues(result).Inc()
+}
+
+// Handler mengekspos registry dalam format Prometheus.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{Registry: m.Registry})
+}
@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+const testManagerID = "0b7c2d2e-4f4a-4b8e-9c59-2f1d8f6a3e10"
+
+func TestManagerLabel(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		perManager bool
+		want       string
+	}{
+		{name: "aggregate", perManager: false, want: allManagers},
+		{name: "per manager", perManager: true, want: testManagerID},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(prometheus.NewRegistry(), &config.MetricsConfig{PerManager: tt.perManager})
+			m.CountEmployeesCreated(testManagerID, 2)
+			m.CountEmployeeUpdated(testManagerID)
+			m.CountEmployeeDeleted(testManagerID)
+			m.CountDepartmentCreated(testManagerID)
+
+			for name, counter := range map[string]*prometheus.CounterVec{
+				"employees created":   m.EmployeesCreated,
+				"employees updated":   m.EmployeesUpdated,
+				"employees deleted":   m.EmployeesDeleted,
+				"departments created": m.DepartmentsCreated,
+			} {
+				if got := testutil.CollectAndCount(counter); got != 1 {
+					t.Errorf("%s has %d series, want 1", name, got)
+				}
+				if got := testutil.ToFloat64(counter.WithLabelValues(tt.want)); got == 0 {
+					t.Errorf("%s{manager=%q} = 0", name, tt.want)
+				}
+			}
+			if got := testutil.ToFloat64(m.EmployeesCreated.WithLabelValues(tt.want)); got != 2 {
+				t.Errorf("employees created = %v, want 2", got)
+			}
+		})
+	}
+}
+
+func TestCountEmployeesCreatedSkipsZero(t *testing.T) {
+	m := New(prometheus.NewRegistry(), &config.MetricsConfig{})
+	m.CountEmployeesCreated(testManagerID, 0)
+	if got := testutil.CollectAndCount(m.EmployeesCreated); got != 0 {
+		t.Fatalf("employees created has %d series after a zero count, want 0", got)
+	}
+}
+
+func TestCountLogin(t *testing.T) {
+	m := New(prometheus.NewRegistry(), &config.MetricsConfig{})
+	m.CountLogin(true)
+	m.CountLogin(false)
+	m.CountLogin(false)
+	if got := testutil.ToFloat64(m.AuthLogins.WithLabelValues("success")); got != 1 {
+		t.Errorf("successful logins = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.AuthLogins.WithLabelValues("failure")); got != 2 {
+		t.Errorf("failed logins = %v, want 2", got)
+	}
+}
+
+func TestNewUsesItsOwnRegistry(t *testing.T) {
+	// Dua instance di registry berbeda tidak bentrok saat register
+	first := New(prometheus.NewRegistry(), &config.MetricsConfig{})
+	second := New(prometheus.NewRegistry(), &config.MetricsConfig{})
+	first.CountLogin(true)
+	if got := testutil.CollectAndCount(second.AuthLogins); got != 0 {
+		t.Fatalf("second instance sees %d login series, want 0", got)
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/metrics"
+)
+
+// AccessLog mencatat setiap request ke log aplikasi. Request yang lebih lama
+// dari logger.SlowRequestThreshold dinaikkan ke level Warn agar mudah dicari.
+// Jumlah query request ikut di-log dan dicatat ke histogram per route;
+// request yang melewati queryLimit (0 = mati) juga dinaikkan ke level Warn.
+func AccessLog(log logger.Logger, appMetrics *metrics.Metrics, skipPaths []string, queryLimit int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if slices.Contains(skipPaths, c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		ctx, queries := database.WithQueryCounter(c.Request.Context(), queryLimit)
+		c.Request = c.Request.WithContext(ctx)
+
+		start := time.Now()
+		c.Next()
+		latency := time.Since(start)
+
+		entry := map[string]any{
+			"method":     c.Request.Method,
+			"route":      c.FullPath(),
+			"path":       c.Request.URL.Path,
+			"status":     c.Writer.Status(),
+			"latency_ms": latency.Milliseconds(),
+			"queries":    queries.Count(),
+			"manager_id": c.GetString(helper.ContextKeyUserID),
+			"request_id": c.GetString(helper.ContextKeyRequestID),
+			"client_ip":  c.ClientIP(),
+		}
+		if apiKeyID := c.GetString(helper.ContextKeyAPIKeyID); apiKeyID != "" {
+			entry["api_key_id"] = apiKeyID
+		}
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		appMetrics.ObserveRequestQueries(route, queries.Count())
+
+		if queries.Exceeded() {
+			entry["query_limit"] = queries.Limit()
+			log.Warn("request exceeded query limit", helper.AccessLog, entry)
+			return
+		}
+		if threshold := logger.SlowRequestThreshold(); latency > threshold {
+			entry["threshold_ms"] = threshold.Milliseconds()
+			log.Warn("slow request", helper.AccessLog, entry)
+			return
+		}
+		log.Info("request", helper.AccessLog, entry)
+	}
+}
@@ -0,0 +1,179 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/mocks"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// accessLogger menyimpan level dan entry setiap log AccessLog.
+type accessLogger struct {
+	mocks.Logger
+	levels  []string
+	entries []map[string]any
+}
+
+func (l *accessLogger) Info(msg string, function helper.FunctionCaller, data ...interface{}) {
+	l.record("info", function, data)
+}
+
+func (l *accessLogger) Warn(msg string, function helper.FunctionCaller, data ...interface{}) {
+	l.record("warn", function, data)
+}
+
+func (l *accessLogger) record(level string, function helper.FunctionCaller, data []interface{}) {
+	if function != helper.AccessLog || len(data) == 0 {
+		return
+	}
+	entry, _ := data[0].(map[string]any)
+	l.levels = append(l.levels, level)
+	l.entries = append(l.entries, entry)
+}
+
+func (l *accessLogger) With(fields map[string]any) logger.Logger {
+	return l
+}
+
+func (l *accessLogger) WithContext(ctx context.Context) logger.Logger {
+	return l
+}
+
+// routeQueries mengembalikan jumlah sample dan total query histogram
+// http_request_db_queries untuk route.
+func routeQueries(t *testing.T, m *metrics.Metrics, route string) (uint64, float64) {
+	t.Helper()
+	var metric dto.Metric
+	if err := m.RequestQueries.WithLabelValues(route).(prometheus.Metric).Write(&metric); err != nil {
+		t.Fatal(err)
+	}
+	return metric.GetHistogram().GetSampleCount(), metric.GetHistogram().GetSampleSum()
+}
+
+func TestAccessLogCountsQueries(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name    string
+		limit   int
+		target  string
+		queries int
+		// wantRoute adalah label route histogram
+		wantRoute string
+		wantLevel string
+	}{
+		{name: "no queries", target: "/v1/employee/EMP-1", wantRoute: "/v1/employee/:identityNumber", wantLevel: "info"},
+		{name: "without limit", target: "/v1/employee/EMP-1", queries: 30, wantRoute: "/v1/employee/:identityNumber", wantLevel: "info"},
+		{name: "at limit", limit: 3, target: "/v1/employee/EMP-1", queries: 3, wantRoute: "/v1/employee/:identityNumber", wantLevel: "info"},
+		{name: "over limit", limit: 3, target: "/v1/employee/EMP-1", queries: 4, wantRoute: "/v1/employee/:identityNumber", wantLevel: "warn"},
+		{name: "unmatched route", target: "/v1/unknown", wantRoute: "unmatched", wantLevel: "info"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := &accessLogger{}
+			appMetrics := metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{})
+			tracer := database.NewQueryCountTracer(mocks.Logger{})
+			router := gin.New()
+			router.Use(AccessLog(log, appMetrics, nil, tt.limit))
+			router.GET("/v1/employee/:identityNumber", func(c *gin.Context) {
+				// Query dari handler memakai context request
+				for range tt.queries {
+					tracer.TraceQueryStart(c.Request.Context(), nil, pgx.TraceQueryStartData{SQL: "SELECT 1;"})
+				}
+				c.Status(http.StatusOK)
+			})
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if len(log.entries) != 1 {
+				t.Fatalf("logged %d access entries, want 1", len(log.entries))
+			}
+			entry := log.entries[0]
+			if log.levels[0] != tt.wantLevel || entry["queries"] != tt.queries {
+				t.Fatalf("level = %s, entry = %v, want %s with %d queries", log.levels[0], entry, tt.wantLevel, tt.queries)
+			}
+			if limit, ok := entry["query_limit"]; (tt.wantLevel == "warn") != ok || (ok && limit != tt.limit) {
+				t.Fatalf("query_limit = %v, want it only over the limit", limit)
+			}
+			if count, sum := routeQueries(t, appMetrics, tt.wantRoute); count != 1 || sum != float64(tt.queries) {
+				t.Fatalf("histogram %s = %d samples, sum %v, want 1 sample of %d", tt.wantRoute, count, sum, tt.queries)
+			}
+		})
+	}
+}
+
+// TestAccessLogSkipPaths memastikan path yang dilewati tidak di-log dan
+// tidak dicatat di histogram.
+func TestAccessLogSkipPaths(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := &accessLogger{}
+	appMetrics := metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{})
+	router := gin.New()
+	router.Use(AccessLog(log, appMetrics, []string{"/healthz"}, 0))
+	router.GET("/healthz", func(c *gin.Context) {
+		if database.QueryCounterFromContext(c.Request.Context()) != nil {
+			t.Error("skipped path has a query counter")
+		}
+		c.Status(http.StatusOK)
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if len(log.entries) != 0 {
+		t.Fatalf("access entries = %v, want none", log.entries)
+	}
+	if count, _ := routeQueries(t, appMetrics, "/healthz"); count != 0 {
+		t.Fatalf("histogram /healthz = %d samples, want 0", count)
+	}
+}
+
+// TestAccessLogSlowRequest memakai threshold pendek dan handler yang
+// sengaja diperlambat. Threshold dibaca per request, sehingga perubahan
+// saat runtime langsung berlaku.
+func TestAccessLogSlowRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	previous := logger.SlowRequestThreshold()
+	t.Cleanup(func() { logger.SetSlowRequestThreshold(previous) })
+
+	log := &accessLogger{}
+	appMetrics := metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{})
+	router := gin.New()
+	router.Use(AccessLog(log, appMetrics, nil, 0))
+	router.GET("/v1/employee", func(c *gin.Context) {
+		c.Set(helper.ContextKeyUserID, "manager-1")
+		time.Sleep(30 * time.Millisecond)
+		c.Status(http.StatusOK)
+	})
+	request := func() {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/employee?limit=5", nil))
+	}
+
+	logger.SetSlowRequestThreshold(10 * time.Millisecond)
+	request()
+	logger.SetSlowRequestThreshold(time.Minute)
+	request()
+
+	if want := []string{"warn", "info"}; !slices.Equal(log.levels, want) {
+		t.Fatalf("levels = %v, want %v", log.levels, want)
+	}
+	slow := log.entries[0]
+	if slow["threshold_ms"] != int64(10) || slow["route"] != "/v1/employee" || slow["manager_id"] != "manager-1" {
+		t.Fatalf("slow entry = %v, want threshold, route and manager", slow)
+	}
+	if latency, _ := slow["latency_ms"].(int64); latency < 30 {
+		t.Fatalf("latency_ms = %v, want at least 30", slow["latency_ms"])
+	}
+	if _, ok := log.entries[1]["threshold_ms"]; ok {
+		t.Fatalf("fast entry = %v, want no threshold", log.entries[1])
+	}
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+const (
+	DebugTokenHeader   = "X-Debug-Token"
+	DebugExplainHeader = "X-Debug-Explain"
+)
+
+// RequireAdmin hanya meneruskan request dari manager yang terdaftar sebagai
+// admin. Harus dipasang setelah Authorization.
+func RequireAdmin(adminIDs []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := GetIdUserFromContext(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, helper.ErrUnauthorized))
+			return
+		}
+		if !slices.Contains(adminIDs, id) {
+			c.AbortWithStatusJSON(http.StatusForbidden, helper.Error(http.StatusForbidden, helper.ErrForbidden))
+			return
+		}
+		c.Next()
+	}
+}
+
+// DebugAccess melindungi endpoint debug: request diterima jika membawa
+// header X-Debug-Token yang cocok dengan token, atau bearer token milik admin.
+// Tanpa kredensial sama sekali hasilnya 401, kredensial salah 403.
+func DebugAccess(token string, adminIDs []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if debugToken := c.GetHeader(DebugTokenHeader); debugToken != "" {
+			if token == "" || subtle.ConstantTimeCompare([]byte(debugToken), []byte(token)) != 1 {
+				c.AbortWithStatusJSON(http.StatusForbidden, helper.Error(http.StatusForbidden, helper.ErrForbidden))
+				return
+			}
+			c.Next()
+			return
+		}
+
+		id, err := authenticate(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
+			return
+		}
+		if !slices.Contains(adminIDs, id) {
+			c.AbortWithStatusJSON(http.StatusForbidden, helper.Error(http.StatusForbidden, helper.ErrForbidden))
+			return
+		}
+		c.Set(helper.ContextKeyUserID, id)
+		c.Next()
+	}
+}
+
+// DebugExplain meminta plan query lambat request ini di-log, lihat
+// database.ExplainTracer, jika request membawa header X-Debug-Explain dan
+// bearer token milik admin. Header dari non-admin diabaikan tanpa error.
+func DebugExplain(adminIDs []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader(DebugExplainHeader) == "" {
+			c.Next()
+			return
+		}
+		if id, err := authenticate(c); err == nil && slices.Contains(adminIDs, id) {
+			c.Request = c.Request.WithContext(database.WithExplain(c.Request.Context()))
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/levensspel/go-gin-template/auth"
+	"github.com/levensspel/go-gin-template/database"
+)
+
+// explainRequested melihat apakah ExplainTracer dengan flag deployment mati
+// akan meng-explain query yang dijalankan dengan ctx.
+func explainRequested(ctx context.Context) bool {
+	tracer := database.NewExplainTracer(nil, false, 0)
+	return tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT 1;"}) != ctx
+}
+
+func TestDebugExplain(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	token := func(userID string) string {
+		token, err := auth.Default().GenerateToken(userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return "Bearer " + token
+	}
+
+	tests := []struct {
+		name          string
+		explain       string
+		authorization string
+		want          bool
+	}{
+		{name: "admin", explain: "1", authorization: token("admin-1"), want: true},
+		{name: "admin without header", authorization: token("admin-1"), want: false},
+		{name: "non-admin header ignored", explain: "1", authorization: token("manager-1"), want: false},
+		{name: "header without token", explain: "1", want: false},
+		{name: "invalid token", explain: "1", authorization: "Bearer invalid", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requested bool
+			router := gin.New()
+			router.GET("/", DebugExplain([]string{"admin-1"}), func(c *gin.Context) {
+				requested = explainRequested(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.explain != "" {
+				req.Header.Set(DebugExplainHeader, tt.explain)
+			}
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			// Header dari non-admin tidak pernah menolak request
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+			}
+			if requested != tt.want {
+				t.Fatalf("explain requested = %t, want %t", requested, tt.want)
+			}
+		})
+	}
+}
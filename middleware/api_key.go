@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+var ErrInsufficientScope = errors.New("insufficient scope")
+
+func init() {
+	helper.Register(ErrInsufficientScope, http.StatusForbidden, "ErrInsufficientScope")
+}
+
+// APIKeyAuthenticator mencari API key yang masih berlaku, diimplementasikan
+// apiKeyService.APIKeyService.
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (entity.APIKey, error)
+}
+
+// APIKey seperti Authorization, tetapi juga menerima API key di header
+// X-API-Key. Request dengan API key diteruskan sebagai manager pemilik key,
+// dengan scope key di context untuk dicek RequireScope. Group yang memakai
+// APIKey harus memasang RequireScope setelahnya.
+func APIKey(keys APIKeyAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(APIKeyHeader)
+		if key == "" {
+			Authorization(c)
+			return
+		}
+
+		row, err := keys.Authenticate(c, key)
+		if err != nil {
+			c.AbortWithStatusJSON(helper.FromError(err))
+			return
+		}
+		c.Set(helper.ContextKeyUserID, row.ManagerId)
+		c.Set(helper.ContextKeyAPIKeyID, row.Id)
+		c.Set(helper.ContextKeyAPIKeyScopes, row.Scopes)
+		c.Next()
+	}
+}
+
+// RequireScope menolak request API key dengan 403 jika key tidak punya
+// scope, atau jika request mengubah data: semua scope API key hanya untuk
+// membaca. Request dengan bearer token selalu diteruskan.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, ok := c.Get(helper.ContextKeyAPIKeyScopes)
+		if !ok {
+			c.Next()
+			return
+		}
+		scopes, _ := value.([]string)
+		if !isReadMethod(c.Request.Method) || !slices.Contains(scopes, scope) {
+			c.AbortWithStatusJSON(helper.FromError(ErrInsufficientScope))
+			return
+		}
+		c.Next()
+	}
+}
+
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
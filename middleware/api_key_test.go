@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/auth"
+	"github.com/levensspel/go-gin-template/clock/clocktest"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/idgen"
+	"github.com/levensspel/go-gin-template/mocks"
+	apiKeyService "github.com/levensspel/go-gin-template/service/apikey"
+)
+
+// newAPIKeyService menyimpan key di memori, dengan waktu dari fake.
+func newAPIKeyService(fake *clocktest.Fake) apiKeyService.APIKeyService {
+	keys := map[string]entity.APIKey{}
+	return apiKeyService.New(&mocks.APIKeyRepository{
+		CreateFunc: func(ctx context.Context, key entity.APIKey) (entity.APIKey, error) {
+			keys[key.Hash] = key
+			return key, nil
+		},
+		GetByHashFunc: func(ctx context.Context, hash string) (entity.APIKey, error) {
+			key, ok := keys[hash]
+			if !ok {
+				return entity.APIKey{}, helper.ErrNotFound
+			}
+			return key, nil
+		},
+	}, idgen.NewUUIDv7(), fake)
+}
+
+func TestAPIKeyScopes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	token, err := auth.Default().GenerateToken("manager-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	fake := clocktest.NewFake(now)
+	service := newAPIKeyService(fake)
+	createKey := func(expiresAt *time.Time, scopes ...string) string {
+		t.Helper()
+		created, err := service.Create(context.Background(), "manager-1", dto.APIKeyRequest{Name: "test", Scopes: scopes, ExpiresAt: expiresAt})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return created.Key
+	}
+	expiresAt := now.Add(time.Hour)
+	employeesKey := createKey(nil, entity.APIKeyScopeEmployeesRead)
+	bothKey := createKey(nil, entity.APIKeyScopeEmployeesRead, entity.APIKeyScopeDepartmentsRead)
+	expiringKey := createKey(&expiresAt, entity.APIKeyScopeEmployeesRead)
+
+	// Sama dengan group /v1/employee dan /v1/department di server/route.go
+	router := gin.New()
+	ok := func(ctx *gin.Context) {
+		id, err := GetIdUserFromContext(ctx)
+		if err != nil {
+			t.Errorf("GetIdUserFromContext error = %v", err)
+		}
+		ctx.JSON(http.StatusOK, helper.OK(id))
+	}
+	apiKeyAuth := APIKey(service)
+	employee := router.Group("/v1/employee", apiKeyAuth, RequireScope(entity.APIKeyScopeEmployeesRead))
+	employee.GET("", ok)
+	employee.HEAD("", ok)
+	employee.POST("", ok)
+	department := router.Group("/v1/department", apiKeyAuth, RequireScope(entity.APIKeyScopeDepartmentsRead))
+	department.GET("", ok)
+	department.DELETE("/:id", ok)
+
+	tests := []struct {
+		name          string
+		method        string
+		path          string
+		apiKey        string
+		authorization string
+		advance       time.Duration
+		want          int
+	}{
+		{name: "read with scope", method: http.MethodGet, path: "/v1/employee", apiKey: employeesKey, want: http.StatusOK},
+		{name: "head with scope", method: http.MethodHead, path: "/v1/employee", apiKey: employeesKey, want: http.StatusOK},
+		{name: "read with both scopes", method: http.MethodGet, path: "/v1/department", apiKey: bothKey, want: http.StatusOK},
+		{name: "read without scope", method: http.MethodGet, path: "/v1/department", apiKey: employeesKey, want: http.StatusForbidden},
+		// Semua scope hanya untuk membaca
+		{name: "write with scope", method: http.MethodPost, path: "/v1/employee", apiKey: employeesKey, want: http.StatusForbidden},
+		{name: "delete with scope", method: http.MethodDelete, path: "/v1/department/1", apiKey: bothKey, want: http.StatusForbidden},
+		{name: "read before expiry", method: http.MethodGet, path: "/v1/employee", apiKey: expiringKey, advance: time.Hour - time.Second, want: http.StatusOK},
+		{name: "read after expiry", method: http.MethodGet, path: "/v1/employee", apiKey: expiringKey, advance: time.Hour, want: http.StatusUnauthorized},
+		{name: "write after expiry", method: http.MethodPost, path: "/v1/employee", apiKey: expiringKey, advance: time.Hour, want: http.StatusUnauthorized},
+		{name: "unknown key", method: http.MethodGet, path: "/v1/employee", apiKey: apiKeyService.KeyPrefix + "unknown", want: http.StatusUnauthorized},
+		// API key mendahului bearer token
+		{name: "unknown key with token", method: http.MethodGet, path: "/v1/employee", apiKey: "unknown", authorization: "Bearer " + token, want: http.StatusUnauthorized},
+		{name: "write with token", method: http.MethodPost, path: "/v1/employee", authorization: "Bearer " + token, want: http.StatusOK},
+		{name: "delete with token", method: http.MethodDelete, path: "/v1/department/1", authorization: "Bearer " + token, want: http.StatusOK},
+		{name: "without credentials", method: http.MethodGet, path: "/v1/employee", want: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake.Set(now.Add(tt.advance))
+			request := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.apiKey != "" {
+				request.Header.Set(APIKeyHeader, tt.apiKey)
+			}
+			if tt.authorization != "" {
+				request.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, request)
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, tt.want, w.Body)
+			}
+			if tt.method == http.MethodHead {
+				return
+			}
+			var response helper.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("decode body %s: %v", w.Body, err)
+			}
+			if tt.want == http.StatusOK {
+				if response.Data != "manager-1" {
+					t.Fatalf("data = %v, want manager-1", response.Data)
+				}
+				return
+			}
+			if response.Errors == nil || response.Errors.Code != tt.want {
+				t.Fatalf("body = %s, want a %d errors envelope", w.Body, tt.want)
+			}
+		})
+	}
+}
@@ -13,28 +13,34 @@ import (
 )
 
 func Authorization(c *gin.Context) {
+	id, err := authenticate(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, helper.Error(http.StatusUnauthorized, err))
+		return
+	}
+	c.Set(helper.ContextKeyUserID, id)
+	c.Next()
+}
+
+// authenticate membaca bearer token dari header Authorization dan
+// mengembalikan id manager pemilik token.
+func authenticate(c *gin.Context) (string, error) {
 	authorizationHeader := c.GetHeader("Authorization")
 	if !strings.Contains(authorizationHeader, "Bearer") {
-		c.JSON(http.StatusUnauthorized, helper.NewResponse(nil, errors.New("the request is allowed for logged in")))
-		c.AbortWithStatus(http.StatusUnauthorized)
-		return
+		return "", errors.New("the request is allowed for logged in")
 	}
 	bearerToken := strings.Replace(authorizationHeader, "Bearer ", "", -1)
-	id, err := auth.ParseToken(bearerToken)
-	fmt.Printf("ID Parsed Token: %s\n", id)
+	id, err := auth.ParseToken(c.Request.Context(), bearerToken)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, helper.NewResponse(nil, err))
-		c.AbortWithStatus(http.StatusUnauthorized)
-		return
+		return "", err
 	}
-	c.Set("user_id", id)
-	c.Next()
+	return id, nil
 }
 
 func GetIdUserFromContext(ctx *gin.Context) (string, error) {
-	id, ok := ctx.Value("user_id").(string)
+	id, ok := ctx.Value(helper.ContextKeyUserID).(string)
 	if !ok {
-		log.Printf(`Failed get data user context %v`, ctx.Value("user_id"))
+		log.Printf(`Failed get data user context %v`, ctx.Value(helper.ContextKeyUserID))
 		return ``, fmt.Errorf("invalid user context")
 	}
 	return id, nil
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/auth"
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+func TestAuthorization(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	token, err := auth.Default().GenerateToken("manager-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		want          int
+	}{
+		{name: "valid token", authorization: "Bearer " + token, want: http.StatusOK},
+		{name: "missing header", want: http.StatusUnauthorized},
+		{name: "not a bearer token", authorization: "Basic dXNlcjpwYXNz", want: http.StatusUnauthorized},
+		{name: "invalid token", authorization: "Bearer not-a-token", want: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/", Authorization, func(ctx *gin.Context) {
+				id, err := GetIdUserFromContext(ctx)
+				if err != nil {
+					t.Errorf("GetIdUserFromContext error = %v", err)
+				}
+				ctx.JSON(http.StatusOK, helper.OK(id))
+			})
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				request.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, request)
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, tt.want, w.Body)
+			}
+			var response helper.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("decode body %s: %v", w.Body, err)
+			}
+			if tt.want == http.StatusOK {
+				if response.Data != "manager-1" {
+					t.Fatalf("data = %v, want manager-1", response.Data)
+				}
+				return
+			}
+			if response.Data != nil || response.Errors == nil || response.Errors.Code != http.StatusUnauthorized || response.Errors.Message == "" {
+				t.Fatalf("body = %s, want a 401 errors envelope", w.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ResolveBaseURL mengembalikan scheme dan host yang dipakai client untuk
+// request ini, untuk URL absolut di response seperti header Link. Tanpa
+// proxy terpercaya nilainya berasal dari koneksi dan header Host. Dengan
+// trustedProxyDepth, X-Forwarded-Proto dan X-Forwarded-Host dibaca dari
+// entry proxy terluar yang dipercaya, seperti ResolveClientIP, sehingga
+// entry dari client diabaikan; nilai yang tidak valid juga diabaikan.
+func ResolveBaseURL(r *http.Request, trustedProxyDepth int) url.URL {
+	base := url.URL{Scheme: "http", Host: r.Host}
+	if r.TLS != nil {
+		base.Scheme = "https"
+	}
+	if trustedProxyDepth <= 0 {
+		return base
+	}
+
+	switch proto := strings.ToLower(forwardedValue(r, "X-Forwarded-Proto", trustedProxyDepth)); proto {
+	case "http", "https":
+		base.Scheme = proto
+	}
+	if host := forwardedValue(r, "X-Forwarded-Host", trustedProxyDepth); validHost(host) {
+		base.Host = host
+	}
+	return base
+}
+
+// forwardedValue mengambil entry header X-Forwarded-* yang ditambahkan
+// proxy terluar dari trustedProxyDepth proxy. Proxy yang menimpa header
+// alih-alih menambahkan entry menghasilkan satu entry, yang langsung dipakai.
+func forwardedValue(r *http.Request, header string, trustedProxyDepth int) string {
+	var chain []string
+	for _, value := range r.Header.Values(header) {
+		for _, entry := range strings.Split(value, ",") {
+			if entry = strings.TrimSpace(entry); entry != "" {
+				chain = append(chain, entry)
+			}
+		}
+	}
+	if len(chain) == 0 {
+		return ""
+	}
+	index := len(chain) - trustedProxyDepth
+	if index < 0 {
+		index = 0
+	}
+	return chain[index]
+}
+
+// validHost hanya menerima host[:port] tanpa path, userinfo, atau spasi.
+func validHost(host string) bool {
+	if host == "" || strings.ContainsAny(host, "/\\@ ") {
+		return false
+	}
+	parsed, err := url.Parse("//" + host)
+	return err == nil && parsed.Host == host
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResolveBaseURL(t *testing.T) {
+	tests := []struct {
+		name              string
+		tls               bool
+		headers           map[string][]string
+		trustedProxyDepth int
+		want              string
+	}{
+		{name: "plain connection", want: "http://service.local"},
+		{name: "tls connection", tls: true, want: "https://service.local"},
+		{
+			name:    "forwarded headers without trusted proxy",
+			headers: map[string][]string{"X-Forwarded-Proto": {"https"}, "X-Forwarded-Host": {"evil.example.com"}},
+			want:    "http://service.local",
+		},
+		{
+			name:              "one trusted proxy",
+			headers:           map[string][]string{"X-Forwarded-Proto": {"https"}, "X-Forwarded-Host": {"api.example.com"}},
+			trustedProxyDepth: 1,
+			want:              "https://api.example.com",
+		},
+		{
+			name: "client entry before the trusted proxy is ignored",
+			headers: map[string][]string{
+				"X-Forwarded-Proto": {"http, https"},
+				"X-Forwarded-Host":  {"evil.example.com, api.example.com"},
+			},
+			trustedProxyDepth: 1,
+			want:              "https://api.example.com",
+		},
+		{
+			name: "outermost of two trusted proxies",
+			headers: map[string][]string{
+				"X-Forwarded-Proto": {"https", "http"},
+				"X-Forwarded-Host":  {"evil.example.com", "api.example.com", "lb.internal"},
+			},
+			trustedProxyDepth: 2,
+			want:              "https://api.example.com",
+		},
+		{
+			name:              "invalid values are ignored",
+			headers:           map[string][]string{"X-Forwarded-Proto": {"javascript"}, "X-Forwarded-Host": {"api.example.com/path"}},
+			trustedProxyDepth: 1,
+			want:              "http://service.local",
+		},
+		{
+			name:              "host with userinfo is ignored",
+			headers:           map[string][]string{"X-Forwarded-Host": {"user@api.example.com"}},
+			trustedProxyDepth: 1,
+			want:              "http://service.local",
+		},
+		{
+			name:              "host with port",
+			headers:           map[string][]string{"X-Forwarded-Proto": {"HTTPS"}, "X-Forwarded-Host": {"api.example.com:8443"}},
+			trustedProxyDepth: 1,
+			want:              "https://api.example.com:8443",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/v1/employee", nil)
+			r.Host = "service.local"
+			if tt.tls {
+				r.TLS = &tls.ConnectionState{}
+			}
+			for name, values := range tt.headers {
+				for _, value := range values {
+					r.Header.Add(name, value)
+				}
+			}
+			base := ResolveBaseURL(r, tt.trustedProxyDepth)
+			if got := base.String(); got != tt.want {
+				t.Fatalf("ResolveBaseURL() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
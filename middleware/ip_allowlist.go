@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+// NewIPAllowlist returns a middleware that rejects requests whose client IP is
+// outside of the given CIDRs. An empty list means no restriction.
+//
+// trustedProxyDepth is the number of reverse proxies in front of the service.
+// The client IP is taken from the X-Forwarded-For entry appended by the
+// outermost trusted proxy, so entries injected by the client are ignored.
+func NewIPAllowlist(cidrs []string, trustedProxyDepth int) (gin.HandlerFunc, error) {
+	prefixes, err := parsePrefixes(cidrs)
+	if err != nil {
+		return nil, err
+	}
+	if trustedProxyDepth < 0 {
+		return nil, fmt.Errorf("trusted proxy depth must not be negative, got %d", trustedProxyDepth)
+	}
+
+	return func(c *gin.Context) {
+		if len(prefixes) == 0 {
+			c.Next()
+			return
+		}
+
+		ip, err := ResolveClientIP(c.Request, trustedProxyDepth)
+		if err != nil || !containsIP(prefixes, ip) {
+			c.AbortWithStatusJSON(http.StatusForbidden, helper.Error(http.StatusForbidden, helper.ErrForbidden))
+			return
+		}
+		c.Next()
+	}, nil
+}
+
+// ResolveClientIP resolves the IP of the client that sent the request,
+// trusting only the last trustedProxyDepth hops of X-Forwarded-For.
+func ResolveClientIP(r *http.Request, trustedProxyDepth int) (netip.Addr, error) {
+	remote, err := parseAddr(r.RemoteAddr)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	if trustedProxyDepth == 0 {
+		return remote, nil
+	}
+
+	var chain []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(header, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				chain = append(chain, hop)
+			}
+		}
+	}
+	chain = append(chain, remote.String())
+
+	// Setiap proxy terpercaya menambahkan satu entry di sebelah kanan,
+	// sehingga client berada tepat trustedProxyDepth entry dari ujung kanan.
+	index := len(chain) - 1 - trustedProxyDepth
+	if index < 0 {
+		index = 0
+	}
+	return parseAddr(chain[index])
+}
+
+func parsePrefixes(cidrs []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		if !strings.Contains(cidr, "/") {
+			addr, err := netip.ParseAddr(cidr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid allowlist entry %q: %w", cidr, err)
+			}
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowlist entry %q: %w", cidr, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+func parseAddr(value string) (netip.Addr, error) {
+	value = strings.TrimSpace(value)
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+	value = strings.Trim(value, "[]")
+
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("invalid ip address %q: %w", value, err)
+	}
+	// Zone (mis. fe80::1%eth0) tidak relevan untuk allowlist.
+	return addr.Unmap().WithZone(""), nil
+}
+
+func containsIP(prefixes []netip.Prefix, ip netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func allowlistStatus(t *testing.T, cidrs []string, depth int, remoteAddr string, forwardedFor ...string) int {
+	t.Helper()
+	allowlist, err := NewIPAllowlist(cidrs, depth)
+	if err != nil {
+		t.Fatalf("NewIPAllowlist() error = %v", err)
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin", allowlist, func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	r := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	r.RemoteAddr = remoteAddr
+	for _, value := range forwardedFor {
+		r.Header.Add("X-Forwarded-For", value)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	return w.Code
+}
+
+func TestIPAllowlist(t *testing.T) {
+	office := []string{"10.0.0.0/8", "2001:db8::/32", "192.0.2.10"}
+	tests := []struct {
+		name         string
+		cidrs        []string
+		depth        int
+		remoteAddr   string
+		forwardedFor []string
+		want         int
+	}{
+		{name: "empty allowlist", cidrs: nil, remoteAddr: "203.0.113.5:1234", want: http.StatusNoContent},
+		{name: "v4 inside range", cidrs: office, remoteAddr: "10.1.2.3:1234", want: http.StatusNoContent},
+		{name: "v4 outside range", cidrs: office, remoteAddr: "203.0.113.5:1234", want: http.StatusForbidden},
+		{name: "single v4 address", cidrs: office, remoteAddr: "192.0.2.10:1234", want: http.StatusNoContent},
+		{name: "next to single v4 address", cidrs: office, remoteAddr: "192.0.2.11:1234", want: http.StatusForbidden},
+		{name: "v4-mapped v6", cidrs: office, remoteAddr: "[::ffff:10.1.2.3]:1234", want: http.StatusNoContent},
+		{name: "v6 inside range", cidrs: office, remoteAddr: "[2001:db8:1::5]:1234", want: http.StatusNoContent},
+		{name: "v6 outside range", cidrs: office, remoteAddr: "[2001:db9::5]:1234", want: http.StatusForbidden},
+		{name: "v6 with zone", cidrs: []string{"fe80::/10"}, remoteAddr: "[fe80::1%eth0]:1234", want: http.StatusNoContent},
+		{
+			name:  "forwarded header ignored without trusted proxy",
+			cidrs: office, remoteAddr: "203.0.113.5:1234", forwardedFor: []string{"10.1.2.3"},
+			want: http.StatusForbidden,
+		},
+		{
+			name:  "client behind one trusted proxy",
+			cidrs: office, depth: 1, remoteAddr: "172.16.0.1:1234", forwardedFor: []string{"10.1.2.3"},
+			want: http.StatusNoContent,
+		},
+		{
+			name:  "spoofed entry before the trusted proxy entry",
+			cidrs: office, depth: 1, remoteAddr: "172.16.0.1:1234", forwardedFor: []string{"10.1.2.3, 203.0.113.5"},
+			want: http.StatusForbidden,
+		},
+		{
+			name:  "spoofed header line before the trusted proxy line",
+			cidrs: office, depth: 1, remoteAddr: "172.16.0.1:1234", forwardedFor: []string{"10.1.2.3", "203.0.113.5"},
+			want: http.StatusForbidden,
+		},
+		{
+			name:  "two trusted proxies",
+			cidrs: office, depth: 2, remoteAddr: "172.16.0.2:1234", forwardedFor: []string{"203.0.113.9, 10.1.2.3, 172.16.0.1"},
+			want: http.StatusNoContent,
+		},
+		{
+			name:  "v6 client behind trusted proxy",
+			cidrs: office, depth: 1, remoteAddr: "172.16.0.1:1234", forwardedFor: []string{"2001:db8::7"},
+			want: http.StatusNoContent,
+		},
+		{
+			name:  "invalid forwarded entry",
+			cidrs: office, depth: 1, remoteAddr: "172.16.0.1:1234", forwardedFor: []string{"not-an-ip"},
+			want: http.StatusForbidden,
+		},
+		{
+			// Header yang lebih pendek dari depth tidak boleh membuat
+			// alamat proxy dianggap sebagai client
+			name:  "missing forwarded header with trusted proxy",
+			cidrs: []string{"172.16.0.0/12"}, depth: 1, remoteAddr: "172.16.0.1:1234",
+			want: http.StatusNoContent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := allowlistStatus(t, tt.cidrs, tt.depth, tt.remoteAddr, tt.forwardedFor...); got != tt.want {
+				t.Fatalf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewIPAllowlistRejectsInvalidConfig(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		cidrs []string
+		depth int
+	}{
+		{name: "invalid cidr", cidrs: []string{"10.0.0.0/33"}},
+		{name: "invalid address", cidrs: []string{"office"}},
+		{name: "negative depth", cidrs: []string{"10.0.0.0/8"}, depth: -1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewIPAllowlist(tt.cidrs, tt.depth); err == nil {
+				t.Fatal("NewIPAllowlist() error = nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID memakai X-Request-ID dari client (atau membuat yang baru),
+// menyimpannya di context dan mengembalikannya di response header.
+func RequestID(c *gin.Context) {
+	requestID := c.GetHeader(RequestIDHeader)
+	if requestID == "" || len(requestID) > 128 {
+		requestID = newRequestID()
+	}
+	c.Set(helper.ContextKeyRequestID, requestID)
+	c.Header(RequestIDHeader, requestID)
+	c.Next()
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
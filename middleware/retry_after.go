@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RetryAfter menambahkan header Retry-After (dalam detik, dibulatkan ke atas)
+// ke setiap response 503 yang belum punya header tersebut, agar client
+// menunda retry saat database sedang penuh atau tidak tersedia.
+func RetryAfter(after time.Duration) gin.HandlerFunc {
+	seconds := strconv.Itoa(max(int(math.Ceil(after.Seconds())), 1))
+	return func(c *gin.Context) {
+		c.Writer = &retryAfterWriter{ResponseWriter: c.Writer, seconds: seconds}
+		c.Next()
+	}
+}
+
+type retryAfterWriter struct {
+	gin.ResponseWriter
+	seconds string
+}
+
+// WriteHeader dipanggil gin tepat sebelum body pertama ditulis, saat header
+// masih bisa diubah.
+func (w *retryAfterWriter) WriteHeader(code int) {
+	if code == http.StatusServiceUnavailable && w.Header().Get("Retry-After") == "" {
+		w.Header().Set("Retry-After", w.seconds)
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
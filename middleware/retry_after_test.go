@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRetryAfter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name   string
+		after  time.Duration
+		status int
+		header string
+		want   string
+	}{
+		{name: "service unavailable", after: 5 * time.Second, status: http.StatusServiceUnavailable, want: "5"},
+		{name: "rounded up", after: 1500 * time.Millisecond, status: http.StatusServiceUnavailable, want: "2"},
+		{name: "at least one second", after: 0, status: http.StatusServiceUnavailable, want: "1"},
+		{name: "handler header kept", after: 5 * time.Second, status: http.StatusServiceUnavailable, header: "30", want: "30"},
+		{name: "internal error", after: 5 * time.Second, status: http.StatusInternalServerError, want: ""},
+		{name: "ok", after: 5 * time.Second, status: http.StatusOK, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(RetryAfter(tt.after))
+			router.GET("/", func(c *gin.Context) {
+				if tt.header != "" {
+					c.Header("Retry-After", tt.header)
+				}
+				c.JSON(tt.status, gin.H{})
+			})
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if got := w.Header().Get("Retry-After"); got != tt.want {
+				t.Fatalf("Retry-After = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
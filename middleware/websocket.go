@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebSocketProtocolBearer adalah subprotocol WebSocket pembawa token.
+const WebSocketProtocolBearer = "bearer"
+
+// WebSocketToken memindahkan token dari header Sec-WebSocket-Protocol
+// ("bearer, <token>") ke header Authorization, karena WebSocket di browser
+// tidak bisa mengirim header. Dipasang sebelum Authorization; request yang
+// sudah membawa Authorization tidak diubah.
+func WebSocketToken(c *gin.Context) {
+	if c.GetHeader("Authorization") == "" {
+		protocols := strings.Split(c.GetHeader("Sec-WebSocket-Protocol"), ",")
+		if len(protocols) == 2 && strings.TrimSpace(protocols[0]) == WebSocketProtocolBearer {
+			c.Request.Header.Set("Authorization", "Bearer "+strings.TrimSpace(protocols[1]))
+		}
+	}
+	c.Next()
+}
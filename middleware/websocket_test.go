@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestWebSocketToken(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		protocol      string
+		want          string
+	}{
+		{name: "bearer subprotocol", protocol: "bearer, eyJhbGciOi.abc.def", want: "Bearer eyJhbGciOi.abc.def"},
+		{name: "without spaces", protocol: "bearer,eyJhbGciOi.abc.def", want: "Bearer eyJhbGciOi.abc.def"},
+		{name: "authorization header wins", authorization: "Bearer header-token", protocol: "bearer, protocol-token", want: "Bearer header-token"},
+		{name: "other subprotocol", protocol: "graphql-ws, token", want: ""},
+		{name: "bearer without token", protocol: "bearer", want: ""},
+		{name: "extra subprotocols", protocol: "bearer, token, chat", want: ""},
+		{name: "no subprotocol", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			var got string
+			router.GET("/v1/ws", WebSocketToken, func(c *gin.Context) {
+				got = c.GetHeader("Authorization")
+			})
+			request := httptest.NewRequest(http.MethodGet, "/v1/ws", nil)
+			if tt.authorization != "" {
+				request.Header.Set("Authorization", tt.authorization)
+			}
+			if tt.protocol != "" {
+				request.Header.Set("Sec-WebSocket-Protocol", tt.protocol)
+			}
+			router.ServeHTTP(httptest.NewRecorder(), request)
+			if got != tt.want {
+				t.Fatalf("Authorization = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
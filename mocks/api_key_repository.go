@@ -0,0 +1,55 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/entity"
+	repositories "github.com/levensspel/go-gin-template/repository/apikey"
+)
+
+type APIKeyRepository struct {
+	CreateFunc        func(ctx context.Context, key entity.APIKey) (entity.APIKey, error)
+	GetAllFunc        func(ctx context.Context, managerId string) ([]entity.APIKey, error)
+	GetByHashFunc     func(ctx context.Context, hash string) (entity.APIKey, error)
+	DeleteFunc        func(ctx context.Context, id, managerId string) error
+	DeleteExpiredFunc func(ctx context.Context, db database.Querier, retention time.Duration, limit int) (int64, error)
+}
+
+var _ repositories.APIKeyRepositoryInterface = (*APIKeyRepository)(nil)
+
+func (m *APIKeyRepository) Create(ctx context.Context, key entity.APIKey) (entity.APIKey, error) {
+	if m.CreateFunc == nil {
+		return entity.APIKey{}, ErrNotMocked
+	}
+	return m.CreateFunc(ctx, key)
+}
+
+func (m *APIKeyRepository) GetAll(ctx context.Context, managerId string) ([]entity.APIKey, error) {
+	if m.GetAllFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetAllFunc(ctx, managerId)
+}
+
+func (m *APIKeyRepository) GetByHash(ctx context.Context, hash string) (entity.APIKey, error) {
+	if m.GetByHashFunc == nil {
+		return entity.APIKey{}, ErrNotMocked
+	}
+	return m.GetByHashFunc(ctx, hash)
+}
+
+func (m *APIKeyRepository) Delete(ctx context.Context, id, managerId string) error {
+	if m.DeleteFunc == nil {
+		return ErrNotMocked
+	}
+	return m.DeleteFunc(ctx, id, managerId)
+}
+
+func (m *APIKeyRepository) DeleteExpired(ctx context.Context, db database.Querier, retention time.Duration, limit int) (int64, error) {
+	if m.DeleteExpiredFunc == nil {
+		return 0, ErrNotMocked
+	}
+	return m.DeleteExpiredFunc(ctx, db, retention, limit)
+}
@@ -0,0 +1,30 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	repositories "github.com/levensspel/go-gin-template/repository/audit"
+)
+
+type AuditRepository struct {
+	AddFunc    func(ctx context.Context, log entity.AuditLog) error
+	GetAllFunc func(ctx context.Context, input *dto.GetAuditLogsRequest) ([]entity.AuditLog, error)
+}
+
+var _ repositories.AuditRepositoryInterface = (*AuditRepository)(nil)
+
+func (m *AuditRepository) Add(ctx context.Context, log entity.AuditLog) error {
+	if m.AddFunc == nil {
+		return ErrNotMocked
+	}
+	return m.AddFunc(ctx, log)
+}
+
+func (m *AuditRepository) GetAll(ctx context.Context, input *dto.GetAuditLogsRequest) ([]entity.AuditLog, error) {
+	if m.GetAllFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetAllFunc(ctx, input)
+}
@@ -0,0 +1,95 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/entity"
+	repositories "github.com/levensspel/go-gin-template/repository/department"
+)
+
+type DepartmentRepository struct {
+	CreateFunc         func(ctx context.Context, id string, name string, managerID string) (*entity.Department, error)
+	GetAllFunc         func(ctx context.Context, name string, limit int, offset int, managerID string) ([]entity.Department, error)
+	CountFunc          func(ctx context.Context, name string, managerID string) (int64, error)
+	UpdateFunc         func(ctx context.Context, name string, deptID string, managerID string) (*entity.Department, error)
+	DeleteFunc         func(ctx context.Context, deptID string, managerID string) error
+	GetForUpdateFunc   func(ctx context.Context, deptID string, managerID string) (*entity.Department, error)
+	PurgeDeletedFunc   func(ctx context.Context, pool database.Querier, retention time.Duration, limit int) (int64, error)
+	GetByIDsFunc       func(ctx context.Context, deptIDs []string, managerID string) ([]entity.Department, error)
+	CountEmployeesFunc func(ctx context.Context, deptIDs []string, managerID string) (map[string]int, error)
+	ReassignFunc       func(ctx context.Context, deptID string, toManagerID string) (*entity.Department, string, error)
+}
+
+var _ repositories.DepartmentRepositoryInterface = (*DepartmentRepository)(nil)
+
+func (m *DepartmentRepository) Create(ctx context.Context, id string, name string, managerID string) (*entity.Department, error) {
+	if m.CreateFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.CreateFunc(ctx, id, name, managerID)
+}
+
+func (m *DepartmentRepository) GetAll(ctx context.Context, name string, limit int, offset int, managerID string) ([]entity.Department, error) {
+	if m.GetAllFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetAllFunc(ctx, name, limit, offset, managerID)
+}
+
+func (m *DepartmentRepository) Count(ctx context.Context, name string, managerID string) (int64, error) {
+	if m.CountFunc == nil {
+		return 0, ErrNotMocked
+	}
+	return m.CountFunc(ctx, name, managerID)
+}
+
+func (m *DepartmentRepository) Update(ctx context.Context, name string, deptID string, managerID string) (*entity.Department, error) {
+	if m.UpdateFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.UpdateFunc(ctx, name, deptID, managerID)
+}
+
+func (m *DepartmentRepository) Delete(ctx context.Context, deptID string, managerID string) error {
+	if m.DeleteFunc == nil {
+		return ErrNotMocked
+	}
+	return m.DeleteFunc(ctx, deptID, managerID)
+}
+
+func (m *DepartmentRepository) GetForUpdate(ctx context.Context, deptID string, managerID string) (*entity.Department, error) {
+	if m.GetForUpdateFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetForUpdateFunc(ctx, deptID, managerID)
+}
+
+func (m *DepartmentRepository) PurgeDeleted(ctx context.Context, pool database.Querier, retention time.Duration, limit int) (int64, error) {
+	if m.PurgeDeletedFunc == nil {
+		return 0, ErrNotMocked
+	}
+	return m.PurgeDeletedFunc(ctx, pool, retention, limit)
+}
+
+func (m *DepartmentRepository) GetByIDs(ctx context.Context, deptIDs []string, managerID string) ([]entity.Department, error) {
+	if m.GetByIDsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetByIDsFunc(ctx, deptIDs, managerID)
+}
+
+func (m *DepartmentRepository) CountEmployees(ctx context.Context, deptIDs []string, managerID string) (map[string]int, error) {
+	if m.CountEmployeesFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.CountEmployeesFunc(ctx, deptIDs, managerID)
+}
+
+func (m *DepartmentRepository) Reassign(ctx context.Context, deptID string, toManagerID string) (*entity.Department, string, error) {
+	if m.ReassignFunc == nil {
+		return nil, "", ErrNotMocked
+	}
+	return m.ReassignFunc(ctx, deptID, toManagerID)
+}
@@ -0,0 +1,8 @@
+// Package mocks berisi implementasi palsu interface repository dan service
+// untuk test service dan handler tanpa database. Setiap method memanggil field XxxFunc yang sesuai;
+// method yang field-nya tidak diisi mengembalikan ErrNotMocked.
+package mocks
+
+import "errors"
+
+var ErrNotMocked = errors.New("mocks: method not mocked")
@@ -0,0 +1,191 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/dto"
+	repositories "github.com/levensspel/go-gin-template/repository/employee"
+)
+
+type EmployeeRepository struct {
+	CreateFunc                    func(ctx context.Context, input *dto.EmployeePayload, managerId string) (dto.EmployeeResponse, error)
+	UpdateFunc                    func(ctx context.Context, identityNumber string, input *dto.EmployeeUpdatePayload, managerId string) (dto.EmployeeResponse, error)
+	IsIdentityNumberAvailableFunc func(ctx context.Context, identityNumber string) error
+	GetAllFunc                    func(ctx context.Context, input *dto.GetEmployeesRequest) ([]dto.EmployeeResponse, error)
+	InsertBatchFunc               func(ctx context.Context, pool database.Querier, inputs []dto.EmployeePayload, managerId string) ([]bool, error)
+	CopyEmployeesFunc             func(ctx context.Context, pool database.Querier, source pgx.CopyFromSource, managerId string) (int64, int64, error)
+	CreateManyFunc                func(ctx context.Context, pool database.Querier, inputs []dto.EmployeePayload, managerId string) ([]dto.EmployeeResponse, []error, error)
+	MoveDepartmentFunc            func(ctx context.Context, fromDepartmentId, toDepartmentId, managerId string) ([]dto.EmployeeResponse, error)
+	GetAllInDepartmentFunc        func(ctx context.Context, departmentId string, managerId string) ([]dto.EmployeeResponse, error)
+	DeleteFunc                    func(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error)
+	GetForUpdateFunc              func(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error)
+	GetAllByDepartmentsFunc       func(ctx context.Context, departmentIds []string, managerId string, limit int, offset int) ([]dto.EmployeeResponse, error)
+	ExportFunc                    func(ctx context.Context, input *dto.GetEmployeesRequest, fn func(dto.EmployeeResponse) error) error
+	CountFunc                     func(ctx context.Context, input *dto.GetEmployeesRequest) (int64, error)
+	MatchingIdentityNumbersFunc   func(ctx context.Context, input *dto.GetEmployeesRequest) ([]string, error)
+	SetStatusFunc                 func(ctx context.Context, identityNumber string, status string, managerId string) (dto.EmployeeResponse, error)
+	StatsFunc                     func(ctx context.Context, managerId string) ([]dto.DepartmentEmployeeStats, error)
+	AddVersionFunc                func(ctx context.Context, identityNumber string, action string, managerId string) error
+	GetVersionsFunc               func(ctx context.Context, input *dto.GetEmployeeHistoryRequest) ([]dto.EmployeeVersion, error)
+	GetVersionFunc                func(ctx context.Context, identityNumber string, version int, managerId string) (dto.EmployeeVersion, error)
+	GetForUpsertFunc              func(ctx context.Context, pool database.Querier, identityNumbers []string, lock bool) (map[string]repositories.ExistingEmployee, error)
+	UpdateBatchFunc               func(ctx context.Context, pool database.Querier, inputs []dto.EmployeePayload, managerId string) ([]bool, error)
+}
+
+var _ repositories.EmployeeRepositoryInterface = (*EmployeeRepository)(nil)
+
+func (m *EmployeeRepository) Create(ctx context.Context, input *dto.EmployeePayload, managerId string) (dto.EmployeeResponse, error) {
+	if m.CreateFunc == nil {
+		return dto.EmployeeResponse{}, ErrNotMocked
+	}
+	return m.CreateFunc(ctx, input, managerId)
+}
+
+func (m *EmployeeRepository) Update(ctx context.Context, identityNumber string, input *dto.EmployeeUpdatePayload, managerId string) (dto.EmployeeResponse, error) {
+	if m.UpdateFunc == nil {
+		return dto.EmployeeResponse{}, ErrNotMocked
+	}
+	return m.UpdateFunc(ctx, identityNumber, input, managerId)
+}
+
+func (m *EmployeeRepository) IsIdentityNumberAvailable(ctx context.Context, identityNumber string) error {
+	if m.IsIdentityNumberAvailableFunc == nil {
+		return ErrNotMocked
+	}
+	return m.IsIdentityNumberAvailableFunc(ctx, identityNumber)
+}
+
+func (m *EmployeeRepository) GetAll(ctx context.Context, input *dto.GetEmployeesRequest) ([]dto.EmployeeResponse, error) {
+	if m.GetAllFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetAllFunc(ctx, input)
+}
+
+func (m *EmployeeRepository) InsertBatch(ctx context.Context, pool database.Querier, inputs []dto.EmployeePayload, managerId string) ([]bool, error) {
+	if m.InsertBatchFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.InsertBatchFunc(ctx, pool, inputs, managerId)
+}
+
+func (m *EmployeeRepository) CopyEmployees(ctx context.Context, pool database.Querier, source pgx.CopyFromSource, managerId string) (int64, int64, error) {
+	if m.CopyEmployeesFunc == nil {
+		return 0, 0, ErrNotMocked
+	}
+	return m.CopyEmployeesFunc(ctx, pool, source, managerId)
+}
+
+func (m *EmployeeRepository) CreateMany(ctx context.Context, pool database.Querier, inputs []dto.EmployeePayload, managerId string) ([]dto.EmployeeResponse, []error, error) {
+	if m.CreateManyFunc == nil {
+		return nil, nil, ErrNotMocked
+	}
+	return m.CreateManyFunc(ctx, pool, inputs, managerId)
+}
+
+func (m *EmployeeRepository) MoveDepartment(ctx context.Context, fromDepartmentId, toDepartmentId, managerId string) ([]dto.EmployeeResponse, error) {
+	if m.MoveDepartmentFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.MoveDepartmentFunc(ctx, fromDepartmentId, toDepartmentId, managerId)
+}
+
+func (m *EmployeeRepository) GetAllInDepartment(ctx context.Context, departmentId string, managerId string) ([]dto.EmployeeResponse, error) {
+	if m.GetAllInDepartmentFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetAllInDepartmentFunc(ctx, departmentId, managerId)
+}
+
+func (m *EmployeeRepository) Delete(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error) {
+	if m.DeleteFunc == nil {
+		return dto.EmployeeResponse{}, ErrNotMocked
+	}
+	return m.DeleteFunc(ctx, identityNumber, managerId)
+}
+
+func (m *EmployeeRepository) GetForUpdate(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error) {
+	if m.GetForUpdateFunc == nil {
+		return dto.EmployeeResponse{}, ErrNotMocked
+	}
+	return m.GetForUpdateFunc(ctx, identityNumber, managerId)
+}
+
+func (m *EmployeeRepository) GetAllByDepartments(ctx context.Context, departmentIds []string, managerId string, limit int, offset int) ([]dto.EmployeeResponse, error) {
+	if m.GetAllByDepartmentsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetAllByDepartmentsFunc(ctx, departmentIds, managerId, limit, offset)
+}
+
+func (m *EmployeeRepository) Export(ctx context.Context, input *dto.GetEmployeesRequest, fn func(dto.EmployeeResponse) error) error {
+	if m.ExportFunc == nil {
+		return ErrNotMocked
+	}
+	return m.ExportFunc(ctx, input, fn)
+}
+
+func (m *EmployeeRepository) Count(ctx context.Context, input *dto.GetEmployeesRequest) (int64, error) {
+	if m.CountFunc == nil {
+		return 0, ErrNotMocked
+	}
+	return m.CountFunc(ctx, input)
+}
+
+func (m *EmployeeRepository) MatchingIdentityNumbers(ctx context.Context, input *dto.GetEmployeesRequest) ([]string, error) {
+	if m.MatchingIdentityNumbersFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.MatchingIdentityNumbersFunc(ctx, input)
+}
+
+func (m *EmployeeRepository) SetStatus(ctx context.Context, identityNumber string, status string, managerId string) (dto.EmployeeResponse, error) {
+	if m.SetStatusFunc == nil {
+		return dto.EmployeeResponse{}, ErrNotMocked
+	}
+	return m.SetStatusFunc(ctx, identityNumber, status, managerId)
+}
+
+func (m *EmployeeRepository) Stats(ctx context.Context, managerId string) ([]dto.DepartmentEmployeeStats, error) {
+	if m.StatsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.StatsFunc(ctx, managerId)
+}
+
+func (m *EmployeeRepository) AddVersion(ctx context.Context, identityNumber string, action string, managerId string) error {
+	if m.AddVersionFunc == nil {
+		return ErrNotMocked
+	}
+	return m.AddVersionFunc(ctx, identityNumber, action, managerId)
+}
+
+func (m *EmployeeRepository) GetVersions(ctx context.Context, input *dto.GetEmployeeHistoryRequest) ([]dto.EmployeeVersion, error) {
+	if m.GetVersionsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetVersionsFunc(ctx, input)
+}
+
+func (m *EmployeeRepository) GetVersion(ctx context.Context, identityNumber string, version int, managerId string) (dto.EmployeeVersion, error) {
+	if m.GetVersionFunc == nil {
+		return dto.EmployeeVersion{}, ErrNotMocked
+	}
+	return m.GetVersionFunc(ctx, identityNumber, version, managerId)
+}
+
+func (m *EmployeeRepository) GetForUpsert(ctx context.Context, pool database.Querier, identityNumbers []string, lock bool) (map[string]repositories.ExistingEmployee, error) {
+	if m.GetForUpsertFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetForUpsertFunc(ctx, pool, identityNumbers, lock)
+}
+
+func (m *EmployeeRepository) UpdateBatch(ctx context.Context, pool database.Querier, inputs []dto.EmployeePayload, managerId string) ([]bool, error) {
+	if m.UpdateBatchFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.UpdateBatchFunc(ctx, pool, inputs, managerId)
+}
@@ -0,0 +1,167 @@
+package mocks
+
+import (
+	"context"
+	"io"
+
+	"github.com/levensspel/go-gin-template/dto"
+	service "github.com/levensspel/go-gin-template/service/employee"
+	"github.com/levensspel/go-gin-template/validation"
+)
+
+type EmployeeService struct {
+	CreateFunc                    func(ctx context.Context, input dto.EmployeePayload, managerId string) (dto.EmployeeResponse, error)
+	UpdateFunc                    func(ctx context.Context, identityNumber string, input dto.EmployeeUpdatePayload, managerId string) (dto.EmployeeResponse, error)
+	DeleteFunc                    func(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error)
+	GetAllFunc                    func(ctx context.Context, input dto.GetEmployeesRequest) ([]dto.EmployeeResponse, error)
+	ImportFunc                    func(ctx context.Context, file io.Reader, managerId string) (dto.EmployeeImportReport, error)
+	ImportJSONLFunc               func(ctx context.Context, file io.Reader, managerId string, dryRun bool) (dto.EmployeeImportReport, error)
+	CreateManyFunc                func(ctx context.Context, inputs []dto.EmployeePayload, managerId string) ([]error, error)
+	DryRunCreateFunc              func(ctx context.Context, input dto.EmployeePayload, managerId string) (validation.FieldErrors, error)
+	DryRunCreateManyFunc          func(ctx context.Context, inputs []dto.EmployeePayload, managerId string) ([]validation.FieldErrors, error)
+	IsIdentityNumberAvailableFunc func(ctx context.Context, identityNumber string) (bool, error)
+	GetAllByDepartmentsFunc       func(ctx context.Context, managerId string, departmentIds []string, limit int, offset int) ([]dto.EmployeeResponse, error)
+	ExportFunc                    func(ctx context.Context, input dto.GetEmployeesRequest, fn func(dto.EmployeeResponse) error) error
+	CountFunc                     func(ctx context.Context, input dto.GetEmployeesRequest) (int64, error)
+	MissingIdentityNumbersFunc    func(ctx context.Context, input dto.GetEmployeesRequest) ([]string, error)
+	ArchiveFunc                   func(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error)
+	RestoreFunc                   func(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error)
+	StatsFunc                     func(ctx context.Context, managerId string) (dto.EmployeeStats, error)
+	HistoryFunc                   func(ctx context.Context, input dto.GetEmployeeHistoryRequest) ([]dto.EmployeeVersion, error)
+	RestoreVersionFunc            func(ctx context.Context, identityNumber string, version int, managerId string) (dto.EmployeeResponse, error)
+}
+
+var _ service.EmployeeService = (*EmployeeService)(nil)
+
+func (m *EmployeeService) Create(ctx context.Context, input dto.EmployeePayload, managerId string) (dto.EmployeeResponse, error) {
+	if m.CreateFunc == nil {
+		return dto.EmployeeResponse{}, ErrNotMocked
+	}
+	return m.CreateFunc(ctx, input, managerId)
+}
+
+func (m *EmployeeService) Update(ctx context.Context, identityNumber string, input dto.EmployeeUpdatePayload, managerId string) (dto.EmployeeResponse, error) {
+	if m.UpdateFunc == nil {
+		return dto.EmployeeResponse{}, ErrNotMocked
+	}
+	return m.UpdateFunc(ctx, identityNumber, input, managerId)
+}
+
+func (m *EmployeeService) Delete(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error) {
+	if m.DeleteFunc == nil {
+		return dto.EmployeeResponse{}, ErrNotMocked
+	}
+	return m.DeleteFunc(ctx, identityNumber, managerId)
+}
+
+func (m *EmployeeService) GetAll(ctx context.Context, input dto.GetEmployeesRequest) ([]dto.EmployeeResponse, error) {
+	if m.GetAllFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetAllFunc(ctx, input)
+}
+
+func (m *EmployeeService) Import(ctx context.Context, file io.Reader, managerId string) (dto.EmployeeImportReport, error) {
+	if m.ImportFunc == nil {
+		return dto.EmployeeImportReport{}, ErrNotMocked
+	}
+	return m.ImportFunc(ctx, file, managerId)
+}
+
+func (m *EmployeeService) ImportJSONL(ctx context.Context, file io.Reader, managerId string, dryRun bool) (dto.EmployeeImportReport, error) {
+	if m.ImportJSONLFunc == nil {
+		return dto.EmployeeImportReport{}, ErrNotMocked
+	}
+	return m.ImportJSONLFunc(ctx, file, managerId, dryRun)
+}
+
+func (m *EmployeeService) CreateMany(ctx context.Context, inputs []dto.EmployeePayload, managerId string) ([]error, error) {
+	if m.CreateManyFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.CreateManyFunc(ctx, inputs, managerId)
+}
+
+func (m *EmployeeService) DryRunCreate(ctx context.Context, input dto.EmployeePayload, managerId string) (validation.FieldErrors, error) {
+	if m.DryRunCreateFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.DryRunCreateFunc(ctx, input, managerId)
+}
+
+func (m *EmployeeService) DryRunCreateMany(ctx context.Context, inputs []dto.EmployeePayload, managerId string) ([]validation.FieldErrors, error) {
+	if m.DryRunCreateManyFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.DryRunCreateManyFunc(ctx, inputs, managerId)
+}
+
+func (m *EmployeeService) IsIdentityNumberAvailable(ctx context.Context, identityNumber string) (bool, error) {
+	if m.IsIdentityNumberAvailableFunc == nil {
+		return false, ErrNotMocked
+	}
+	return m.IsIdentityNumberAvailableFunc(ctx, identityNumber)
+}
+
+func (m *EmployeeService) GetAllByDepartments(ctx context.Context, managerId string, departmentIds []string, limit int, offset int) ([]dto.EmployeeResponse, error) {
+	if m.GetAllByDepartmentsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetAllByDepartmentsFunc(ctx, managerId, departmentIds, limit, offset)
+}
+
+func (m *EmployeeService) Export(ctx context.Context, input dto.GetEmployeesRequest, fn func(dto.EmployeeResponse) error) error {
+	if m.ExportFunc == nil {
+		return ErrNotMocked
+	}
+	return m.ExportFunc(ctx, input, fn)
+}
+
+func (m *EmployeeService) Count(ctx context.Context, input dto.GetEmployeesRequest) (int64, error) {
+	if m.CountFunc == nil {
+		return 0, ErrNotMocked
+	}
+	return m.CountFunc(ctx, input)
+}
+
+func (m *EmployeeService) MissingIdentityNumbers(ctx context.Context, input dto.GetEmployeesRequest) ([]string, error) {
+	if m.MissingIdentityNumbersFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.MissingIdentityNumbersFunc(ctx, input)
+}
+
+func (m *EmployeeService) Archive(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error) {
+	if m.ArchiveFunc == nil {
+		return dto.EmployeeResponse{}, ErrNotMocked
+	}
+	return m.ArchiveFunc(ctx, identityNumber, managerId)
+}
+
+func (m *EmployeeService) Restore(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error) {
+	if m.RestoreFunc == nil {
+		return dto.EmployeeResponse{}, ErrNotMocked
+	}
+	return m.RestoreFunc(ctx, identityNumber, managerId)
+}
+
+func (m *EmployeeService) Stats(ctx context.Context, managerId string) (dto.EmployeeStats, error) {
+	if m.StatsFunc == nil {
+		return dto.EmployeeStats{}, ErrNotMocked
+	}
+	return m.StatsFunc(ctx, managerId)
+}
+
+func (m *EmployeeService) History(ctx context.Context, input dto.GetEmployeeHistoryRequest) ([]dto.EmployeeVersion, error) {
+	if m.HistoryFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.HistoryFunc(ctx, input)
+}
+
+func (m *EmployeeService) RestoreVersion(ctx context.Context, identityNumber string, version int, managerId string) (dto.EmployeeResponse, error) {
+	if m.RestoreVersionFunc == nil {
+		return dto.EmployeeResponse{}, ErrNotMocked
+	}
+	return m.RestoreVersionFunc(ctx, identityNumber, version, managerId)
+}
@@ -0,0 +1,36 @@
+package mocks
+
+import (
+	"context"
+
+	featureFlagRepository "github.com/levensspel/go-gin-template/repository/featureflag"
+)
+
+type FeatureFlagRepository struct {
+	GetOverridesFunc   func(ctx context.Context, managerId string) (map[string]bool, error)
+	SetOverrideFunc    func(ctx context.Context, flag, managerId string, enabled bool) error
+	DeleteOverrideFunc func(ctx context.Context, flag, managerId string) error
+}
+
+var _ featureFlagRepository.FeatureFlagRepositoryInterface = (*FeatureFlagRepository)(nil)
+
+func (m *FeatureFlagRepository) GetOverrides(ctx context.Context, managerId string) (map[string]bool, error) {
+	if m.GetOverridesFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetOverridesFunc(ctx, managerId)
+}
+
+func (m *FeatureFlagRepository) SetOverride(ctx context.Context, flag, managerId string, enabled bool) error {
+	if m.SetOverrideFunc == nil {
+		return ErrNotMocked
+	}
+	return m.SetOverrideFunc(ctx, flag, managerId, enabled)
+}
+
+func (m *FeatureFlagRepository) DeleteOverride(ctx context.Context, flag, managerId string) error {
+	if m.DeleteOverrideFunc == nil {
+		return ErrNotMocked
+	}
+	return m.DeleteOverrideFunc(ctx, flag, managerId)
+}
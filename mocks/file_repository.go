@@ -0,0 +1,138 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/entity"
+	repositories "github.com/levensspel/go-gin-template/repository/file"
+)
+
+type FileRepository struct {
+	CreateFunc     func(ctx context.Context, file entity.File) (entity.File, error)
+	GetFunc        func(ctx context.Context, fileID string) (entity.File, error)
+	FindByURIsFunc func(ctx context.Context, uris []string) ([]entity.File, error)
+
+	AdjustReferencesFunc   func(ctx context.Context, db database.Querier, added []string, removed []string) ([]string, error)
+	DeleteUnreferencedFunc func(ctx context.Context, db database.Querier, gracePeriod time.Duration, limit int) ([]entity.File, error)
+	GetAllFunc             func(ctx context.Context, managerID string, limit int, offset int) ([]entity.File, error)
+	FindReferencesFunc     func(ctx context.Context, fileURI string, managerID string, limit int) (entity.FileReferences, error)
+	DeleteFunc             func(ctx context.Context, fileID string, managerID string) (entity.File, error)
+
+	ClaimThumbnailsFunc     func(ctx context.Context, lease time.Duration, limit int) ([]entity.File, error)
+	MarkThumbnailReadyFunc  func(ctx context.Context, fileID string, key string, uri string) error
+	MarkThumbnailFailedFunc func(ctx context.Context, fileID string, retryAfter time.Duration, final bool) error
+
+	CreateUploadFunc         func(ctx context.Context, upload entity.FileUpload, expiry time.Duration) error
+	GetUploadForUpdateFunc   func(ctx context.Context, uploadID string, managerID string) (entity.FileUpload, error)
+	DeleteUploadFunc         func(ctx context.Context, uploadID string) error
+	DeleteExpiredUploadsFunc func(ctx context.Context, db database.Querier, retention time.Duration, limit int) ([]string, error)
+}
+
+var _ repositories.FileRepositoryInterface = (*FileRepository)(nil)
+
+func (m *FileRepository) Create(ctx context.Context, file entity.File) (entity.File, error) {
+	if m.CreateFunc == nil {
+		return entity.File{}, ErrNotMocked
+	}
+	return m.CreateFunc(ctx, file)
+}
+
+func (m *FileRepository) Get(ctx context.Context, fileID string) (entity.File, error) {
+	if m.GetFunc == nil {
+		return entity.File{}, ErrNotMocked
+	}
+	return m.GetFunc(ctx, fileID)
+}
+
+func (m *FileRepository) FindByURIs(ctx context.Context, uris []string) ([]entity.File, error) {
+	if m.FindByURIsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.FindByURIsFunc(ctx, uris)
+}
+
+func (m *FileRepository) AdjustReferences(ctx context.Context, db database.Querier, added []string, removed []string) ([]string, error) {
+	if m.AdjustReferencesFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.AdjustReferencesFunc(ctx, db, added, removed)
+}
+
+func (m *FileRepository) DeleteUnreferenced(ctx context.Context, db database.Querier, gracePeriod time.Duration, limit int) ([]entity.File, error) {
+	if m.DeleteUnreferencedFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.DeleteUnreferencedFunc(ctx, db, gracePeriod, limit)
+}
+
+func (m *FileRepository) GetAll(ctx context.Context, managerID string, limit int, offset int) ([]entity.File, error) {
+	if m.GetAllFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetAllFunc(ctx, managerID, limit, offset)
+}
+
+func (m *FileRepository) FindReferences(ctx context.Context, fileURI string, managerID string, limit int) (entity.FileReferences, error) {
+	if m.FindReferencesFunc == nil {
+		return entity.FileReferences{}, ErrNotMocked
+	}
+	return m.FindReferencesFunc(ctx, fileURI, managerID, limit)
+}
+
+func (m *FileRepository) Delete(ctx context.Context, fileID string, managerID string) (entity.File, error) {
+	if m.DeleteFunc == nil {
+		return entity.File{}, ErrNotMocked
+	}
+	return m.DeleteFunc(ctx, fileID, managerID)
+}
+
+func (m *FileRepository) ClaimThumbnails(ctx context.Context, lease time.Duration, limit int) ([]entity.File, error) {
+	if m.ClaimThumbnailsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.ClaimThumbnailsFunc(ctx, lease, limit)
+}
+
+func (m *FileRepository) MarkThumbnailReady(ctx context.Context, fileID string, key string, uri string) error {
+	if m.MarkThumbnailReadyFunc == nil {
+		return ErrNotMocked
+	}
+	return m.MarkThumbnailReadyFunc(ctx, fileID, key, uri)
+}
+
+func (m *FileRepository) MarkThumbnailFailed(ctx context.Context, fileID string, retryAfter time.Duration, final bool) error {
+	if m.MarkThumbnailFailedFunc == nil {
+		return ErrNotMocked
+	}
+	return m.MarkThumbnailFailedFunc(ctx, fileID, retryAfter, final)
+}
+
+func (m *FileRepository) CreateUpload(ctx context.Context, upload entity.FileUpload, expiry time.Duration) error {
+	if m.CreateUploadFunc == nil {
+		return ErrNotMocked
+	}
+	return m.CreateUploadFunc(ctx, upload, expiry)
+}
+
+func (m *FileRepository) GetUploadForUpdate(ctx context.Context, uploadID string, managerID string) (entity.FileUpload, error) {
+	if m.GetUploadForUpdateFunc == nil {
+		return entity.FileUpload{}, ErrNotMocked
+	}
+	return m.GetUploadForUpdateFunc(ctx, uploadID, managerID)
+}
+
+func (m *FileRepository) DeleteUpload(ctx context.Context, uploadID string) error {
+	if m.DeleteUploadFunc == nil {
+		return ErrNotMocked
+	}
+	return m.DeleteUploadFunc(ctx, uploadID)
+}
+
+func (m *FileRepository) DeleteExpiredUploads(ctx context.Context, db database.Querier, retention time.Duration, limit int) ([]string, error) {
+	if m.DeleteExpiredUploadsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.DeleteExpiredUploadsFunc(ctx, db, retention, limit)
+}
@@ -0,0 +1,31 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/entity"
+	repositories "github.com/levensspel/go-gin-template/repository/integration"
+)
+
+type IntegrationRepository struct {
+	EnqueueFunc         func(ctx context.Context, callback entity.IntegrationCallback) (int64, error)
+	DeleteProcessedFunc func(ctx context.Context, db database.Querier, retention time.Duration, limit int) (int64, error)
+}
+
+var _ repositories.IntegrationRepositoryInterface = (*IntegrationRepository)(nil)
+
+func (m *IntegrationRepository) Enqueue(ctx context.Context, callback entity.IntegrationCallback) (int64, error) {
+	if m.EnqueueFunc == nil {
+		return 0, ErrNotMocked
+	}
+	return m.EnqueueFunc(ctx, callback)
+}
+
+func (m *IntegrationRepository) DeleteProcessed(ctx context.Context, db database.Querier, retention time.Duration, limit int) (int64, error) {
+	if m.DeleteProcessedFunc == nil {
+		return 0, ErrNotMocked
+	}
+	return m.DeleteProcessedFunc(ctx, db, retention, limit)
+}
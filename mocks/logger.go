@@ -0,0 +1,27 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+)
+
+// Logger membuang semua log, sehingga test tidak menulis ke logs/app.log
+// seperti logger dari DI.
+type Logger struct{}
+
+var _ logger.Logger = Logger{}
+
+func (Logger) Info(msg string, function helper.FunctionCaller, data ...interface{})  {}
+func (Logger) Error(msg string, function helper.FunctionCaller, data ...interface{}) {}
+func (Logger) Debug(msg string, function helper.FunctionCaller, data ...interface{}) {}
+func (Logger) Warn(msg string, function helper.FunctionCaller, data ...interface{})  {}
+
+func (l Logger) With(fields map[string]any) logger.Logger {
+	return l
+}
+
+func (l Logger) WithContext(ctx context.Context) logger.Logger {
+	return l
+}
@@ -0,0 +1,55 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/entity"
+	repositories "github.com/levensspel/go-gin-template/repository/outbox"
+)
+
+type OutboxRepository struct {
+	AddFunc        func(ctx context.Context, eventType, aggregateType, aggregateId string, payload any) error
+	ClaimFunc      func(ctx context.Context, limit int) ([]entity.OutboxEvent, error)
+	MarkSentFunc   func(ctx context.Context, id int64) error
+	MarkFailedFunc func(ctx context.Context, id int64, retryAfter time.Duration, lastError string) error
+	DeleteSentFunc func(ctx context.Context, db database.Querier, retention time.Duration, limit int) (int64, error)
+}
+
+var _ repositories.OutboxRepositoryInterface = (*OutboxRepository)(nil)
+
+func (m *OutboxRepository) Add(ctx context.Context, eventType, aggregateType, aggregateId string, payload any) error {
+	if m.AddFunc == nil {
+		return ErrNotMocked
+	}
+	return m.AddFunc(ctx, eventType, aggregateType, aggregateId, payload)
+}
+
+func (m *OutboxRepository) Claim(ctx context.Context, limit int) ([]entity.OutboxEvent, error) {
+	if m.ClaimFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.ClaimFunc(ctx, limit)
+}
+
+func (m *OutboxRepository) MarkSent(ctx context.Context, id int64) error {
+	if m.MarkSentFunc == nil {
+		return ErrNotMocked
+	}
+	return m.MarkSentFunc(ctx, id)
+}
+
+func (m *OutboxRepository) MarkFailed(ctx context.Context, id int64, retryAfter time.Duration, lastError string) error {
+	if m.MarkFailedFunc == nil {
+		return ErrNotMocked
+	}
+	return m.MarkFailedFunc(ctx, id, retryAfter, lastError)
+}
+
+func (m *OutboxRepository) DeleteSent(ctx context.Context, db database.Querier, retention time.Duration, limit int) (int64, error) {
+	if m.DeleteSentFunc == nil {
+		return 0, ErrNotMocked
+	}
+	return m.DeleteSentFunc(ctx, db, retention, limit)
+}
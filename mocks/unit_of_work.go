@@ -0,0 +1,30 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/levensspel/go-gin-template/repository"
+)
+
+// UnitOfWork memanggil fn langsung dengan Repositories, tanpa transaksi.
+// Setelah Do, Committed menunjukkan apakah fn berhasil dan transaksi asli
+// akan di-commit, dan TxOptions berisi opsi DoTx terakhir.
+type UnitOfWork struct {
+	Repositories repository.Repositories
+	Committed    bool
+	TxOptions    pgx.TxOptions
+}
+
+var _ repository.UnitOfWork = (*UnitOfWork)(nil)
+
+func (m *UnitOfWork) Do(ctx context.Context, fn func(repos repository.Repositories) error) error {
+	return m.DoTx(ctx, pgx.TxOptions{}, fn)
+}
+
+func (m *UnitOfWork) DoTx(ctx context.Context, txOptions pgx.TxOptions, fn func(repos repository.Repositories) error) error {
+	m.TxOptions = txOptions
+	err := fn(m.Repositories)
+	m.Committed = err == nil
+	return err
+}
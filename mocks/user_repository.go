@@ -0,0 +1,86 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/levensspel/go-gin-template/entity"
+	repositories "github.com/levensspel/go-gin-template/repository/user"
+)
+
+type UserRepository struct {
+	CreateFunc            func(ctx context.Context, user entity.User) (string, error)
+	UpdateFunc            func(ctx context.Context, user entity.User) error
+	GetUserbyEmailFunc    func(ctx context.Context, email string) ([]entity.User, error)
+	DeleteFunc            func(ctx context.Context, id string) error
+	GetProfileFunc        func(ctx context.Context, id string) (*entity.GetProfile, error)
+	UpdateProfileFunc     func(ctx context.Context, id string, data *entity.GetProfile) error
+	UpdatePasswordFunc    func(ctx context.Context, id string, passwordHash string) error
+	RevokeSessionsFunc    func(ctx context.Context, id string, at time.Time) error
+	SessionsRevokedAtFunc func(ctx context.Context, id string) (time.Time, error)
+}
+
+var _ repositories.UserRepositoryInterface = (*UserRepository)(nil)
+
+func (m *UserRepository) Create(ctx context.Context, user entity.User) (string, error) {
+	if m.CreateFunc == nil {
+		return "", ErrNotMocked
+	}
+	return m.CreateFunc(ctx, user)
+}
+
+func (m *UserRepository) Update(ctx context.Context, user entity.User) error {
+	if m.UpdateFunc == nil {
+		return ErrNotMocked
+	}
+	return m.UpdateFunc(ctx, user)
+}
+
+func (m *UserRepository) GetUserbyEmail(ctx context.Context, email string) ([]entity.User, error) {
+	if m.GetUserbyEmailFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetUserbyEmailFunc(ctx, email)
+}
+
+func (m *UserRepository) Delete(ctx context.Context, id string) error {
+	if m.DeleteFunc == nil {
+		return ErrNotMocked
+	}
+	return m.DeleteFunc(ctx, id)
+}
+
+func (m *UserRepository) GetProfile(ctx context.Context, id string) (*entity.GetProfile, error) {
+	if m.GetProfileFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetProfileFunc(ctx, id)
+}
+
+func (m *UserRepository) UpdateProfile(ctx context.Context, id string, data *entity.GetProfile) error {
+	if m.UpdateProfileFunc == nil {
+		return ErrNotMocked
+	}
+	return m.UpdateProfileFunc(ctx, id, data)
+}
+
+func (m *UserRepository) UpdatePassword(ctx context.Context, id string, passwordHash string) error {
+	if m.UpdatePasswordFunc == nil {
+		return ErrNotMocked
+	}
+	return m.UpdatePasswordFunc(ctx, id, passwordHash)
+}
+
+func (m *UserRepository) RevokeSessions(ctx context.Context, id string, at time.Time) error {
+	if m.RevokeSessionsFunc == nil {
+		return ErrNotMocked
+	}
+	return m.RevokeSessionsFunc(ctx, id, at)
+}
+
+func (m *UserRepository) SessionsRevokedAt(ctx context.Context, id string) (time.Time, error) {
+	if m.SessionsRevokedAtFunc == nil {
+		return time.Time{}, ErrNotMocked
+	}
+	return m.SessionsRevokedAtFunc(ctx, id)
+}
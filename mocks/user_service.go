@@ -0,0 +1,71 @@
+package mocks
+
+import (
+	"context"
+	"io"
+
+	"github.com/levensspel/go-gin-template/dto"
+	service "github.com/levensspel/go-gin-template/service/user"
+)
+
+type UserService struct {
+	RegisterUserFunc  func(ctx context.Context, input dto.UserRequestPayload) (dto.ResponseRegister, error)
+	LoginFunc         func(ctx context.Context, input dto.UserRequestPayload) (dto.ResponseLogin, error)
+	UpdateFunc        func(ctx context.Context, input dto.RequestRegister) (dto.Response, error)
+	DeleteByIDFunc    func(ctx context.Context, id string) error
+	GetProfileFunc    func(ctx context.Context, managerid string) (*dto.ResposneGetProfile, error)
+	UpdateProfileFunc func(ctx context.Context, managerid string, input dto.RequestUpdateProfile) (*dto.RequestUpdateProfile, error)
+
+	ReplaceProfileImageFunc func(ctx context.Context, managerid string, image service.ProfileImage, fileName string, claimedType string, body io.Reader) (*dto.ResposneGetProfile, error)
+}
+
+var _ service.IUserService = (*UserService)(nil)
+
+func (m *UserService) RegisterUser(ctx context.Context, input dto.UserRequestPayload) (dto.ResponseRegister, error) {
+	if m.RegisterUserFunc == nil {
+		return dto.ResponseRegister{}, ErrNotMocked
+	}
+	return m.RegisterUserFunc(ctx, input)
+}
+
+func (m *UserService) Login(ctx context.Context, input dto.UserRequestPayload) (dto.ResponseLogin, error) {
+	if m.LoginFunc == nil {
+		return dto.ResponseLogin{}, ErrNotMocked
+	}
+	return m.LoginFunc(ctx, input)
+}
+
+func (m *UserService) Update(ctx context.Context, input dto.RequestRegister) (dto.Response, error) {
+	if m.UpdateFunc == nil {
+		return dto.Response{}, ErrNotMocked
+	}
+	return m.UpdateFunc(ctx, input)
+}
+
+func (m *UserService) DeleteByID(ctx context.Context, id string) error {
+	if m.DeleteByIDFunc == nil {
+		return ErrNotMocked
+	}
+	return m.DeleteByIDFunc(ctx, id)
+}
+
+func (m *UserService) GetProfile(ctx context.Context, managerid string) (*dto.ResposneGetProfile, error) {
+	if m.GetProfileFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetProfileFunc(ctx, managerid)
+}
+
+func (m *UserService) UpdateProfile(ctx context.Context, managerid string, input dto.RequestUpdateProfile) (*dto.RequestUpdateProfile, error) {
+	if m.UpdateProfileFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.UpdateProfileFunc(ctx, managerid, input)
+}
+
+func (m *UserService) ReplaceProfileImage(ctx context.Context, managerid string, image service.ProfileImage, fileName string, claimedType string, body io.Reader) (*dto.ResposneGetProfile, error) {
+	if m.ReplaceProfileImageFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.ReplaceProfileImageFunc(ctx, managerid, image, fileName, claimedType, body)
+}
@@ -0,0 +1,103 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/entity"
+	repositories "github.com/levensspel/go-gin-template/repository/webhook"
+)
+
+type WebhookRepository struct {
+	CreateFunc          func(ctx context.Context, webhook entity.Webhook) (entity.Webhook, error)
+	GetAllFunc          func(ctx context.Context, managerId string) ([]entity.Webhook, error)
+	GetFunc             func(ctx context.Context, id, managerId string) (entity.Webhook, error)
+	DeleteFunc          func(ctx context.Context, id, managerId string) error
+	MatchingFunc        func(ctx context.Context, managerId, eventType string) ([]entity.Webhook, error)
+	AddDeliveryFunc     func(ctx context.Context, delivery entity.WebhookDelivery) (int64, error)
+	ClaimDeliveriesFunc func(ctx context.Context, limit int) ([]entity.WebhookDelivery, error)
+	MarkDeliveredFunc   func(ctx context.Context, id int64, statusCode int) error
+	MarkFailedFunc      func(ctx context.Context, id int64, retryAfter time.Duration, statusCode int, lastError string, dead bool) error
+	GetDeliveriesFunc   func(ctx context.Context, webhookId string, limit, offset int) ([]entity.WebhookDelivery, error)
+	DeleteFinishedFunc  func(ctx context.Context, db database.Querier, retention time.Duration, limit int) (int64, error)
+}
+
+var _ repositories.WebhookRepositoryInterface = (*WebhookRepository)(nil)
+
+func (m *WebhookRepository) Create(ctx context.Context, webhook entity.Webhook) (entity.Webhook, error) {
+	if m.CreateFunc == nil {
+		return entity.Webhook{}, ErrNotMocked
+	}
+	return m.CreateFunc(ctx, webhook)
+}
+
+func (m *WebhookRepository) GetAll(ctx context.Context, managerId string) ([]entity.Webhook, error) {
+	if m.GetAllFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetAllFunc(ctx, managerId)
+}
+
+func (m *WebhookRepository) Get(ctx context.Context, id, managerId string) (entity.Webhook, error) {
+	if m.GetFunc == nil {
+		return entity.Webhook{}, ErrNotMocked
+	}
+	return m.GetFunc(ctx, id, managerId)
+}
+
+func (m *WebhookRepository) Delete(ctx context.Context, id, managerId string) error {
+	if m.DeleteFunc == nil {
+		return ErrNotMocked
+	}
+	return m.DeleteFunc(ctx, id, managerId)
+}
+
+func (m *WebhookRepository) Matching(ctx context.Context, managerId, eventType string) ([]entity.Webhook, error) {
+	if m.MatchingFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.MatchingFunc(ctx, managerId, eventType)
+}
+
+func (m *WebhookRepository) AddDelivery(ctx context.Context, delivery entity.WebhookDelivery) (int64, error) {
+	if m.AddDeliveryFunc == nil {
+		return 0, ErrNotMocked
+	}
+	return m.AddDeliveryFunc(ctx, delivery)
+}
+
+func (m *WebhookRepository) ClaimDeliveries(ctx context.Context, limit int) ([]entity.WebhookDelivery, error) {
+	if m.ClaimDeliveriesFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.ClaimDeliveriesFunc(ctx, limit)
+}
+
+func (m *WebhookRepository) MarkDelivered(ctx context.Context, id int64, statusCode int) error {
+	if m.MarkDeliveredFunc == nil {
+		return ErrNotMocked
+	}
+	return m.MarkDeliveredFunc(ctx, id, statusCode)
+}
+
+func (m *WebhookRepository) MarkFailed(ctx context.Context, id int64, retryAfter time.Duration, statusCode int, lastError string, dead bool) error {
+	if m.MarkFailedFunc == nil {
+		return ErrNotMocked
+	}
+	return m.MarkFailedFunc(ctx, id, retryAfter, statusCode, lastError, dead)
+}
+
+func (m *WebhookRepository) GetDeliveries(ctx context.Context, webhookId string, limit, offset int) ([]entity.WebhookDelivery, error) {
+	if m.GetDeliveriesFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetDeliveriesFunc(ctx, webhookId, limit, offset)
+}
+
+func (m *WebhookRepository) DeleteFinished(ctx context.Context, db database.Querier, retention time.Duration, limit int) (int64, error) {
+	if m.DeleteFinishedFunc == nil {
+		return 0, ErrNotMocked
+	}
+	return m.DeleteFinishedFunc(ctx, db, retention, limit)
+}
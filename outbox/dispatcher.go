@@ -0,0 +1,185 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/events"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/lifecycle"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/metrics"
+	outboxRepository "github.com/levensspel/go-gin-template/repository/outbox"
+	"github.com/samber/do/v2"
+)
+
+// Dispatcher mengirim event outbox yang belum terkirim ke Publisher. Setiap
+// batch berjalan dalam satu transaksi yang mengunci event-nya, sehingga
+// aman dijalankan di banyak instance. Event yang sudah di-publish tetapi
+// gagal ditandai terkirim (mis. instance mati sebelum commit) akan dikirim
+// ulang.
+type Dispatcher struct {
+	db        database.DB
+	publisher Publisher
+	config    *config.OutboxConfig
+	timeouts  *config.QueryTimeoutConfig
+	metrics   *metrics.Metrics
+	logger    logger.Logger
+
+	// ctx diturunkan dari lifecycle.Lifetime. Batch yang dibatalkan
+	// di-rollback dan event-nya dikirim lagi setelah restart.
+	ctx    context.Context
+	cancel context.CancelFunc
+	stop   chan struct{}
+	done   chan struct{}
+	once   sync.Once
+}
+
+func NewDispatcher(
+	ctx context.Context,
+	db database.DB,
+	publisher Publisher,
+	config *config.OutboxConfig,
+	timeouts *config.QueryTimeoutConfig,
+	metrics *metrics.Metrics,
+	logger logger.Logger,
+) *Dispatcher {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Dispatcher{
+		db:        db,
+		publisher: publisher,
+		config:    config,
+		timeouts:  timeouts,
+		metrics:   metrics,
+		logger:    logger,
+		ctx:       ctx,
+		cancel:    cancel,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// NewDispatcherInject langsung menjalankan dispatcher. Dispatcher
+// dihentikan saat injector di-shutdown.
+func NewDispatcherInject(i do.Injector) (*Dispatcher, error) {
+	cluster := do.MustInvoke[*database.Cluster](i)
+	appLogger := do.MustInvoke[logger.LogHandler](i)
+	dispatcher := NewDispatcher(
+		do.MustInvoke[*lifecycle.Lifetime](i).Context(),
+		cluster.Writer(),
+		do.MustInvoke[Publisher](i),
+		config.LoadOutboxConfig(),
+		config.LoadQueryTimeoutConfig(),
+		do.MustInvoke[*metrics.Metrics](i),
+		&appLogger,
+	)
+	dispatcher.Start()
+	return dispatcher, nil
+}
+
+func (d *Dispatcher) Start() {
+	go d.run()
+}
+
+func (d *Dispatcher) run() {
+	defer close(d.done)
+
+	batchSize := max(d.config.BatchSize, 1)
+	for {
+		dispatched, err := d.DispatchOnce(d.ctx, batchSize)
+		if err != nil && d.ctx.Err() == nil {
+			d.logger.Warn(fmt.Sprintf("Outbox dispatch failed: %v", err), helper.OutboxDispatcher)
+		}
+
+		// Batch penuh berarti kemungkinan masih ada event lain yang menunggu
+		wait := d.config.PollInterval
+		if err == nil && dispatched == batchSize {
+			wait = 0
+		}
+		select {
+		case <-d.stop:
+			return
+		case <-d.ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// DispatchOnce mengirim paling banyak limit event dan mengembalikan jumlah
+// event yang diproses, baik berhasil maupun dijadwalkan ulang.
+func (d *Dispatcher) DispatchOnce(ctx context.Context, limit int) (int, error) {
+	var processed int
+	err := database.WithTx(ctx, d.db, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		repo := outboxRepository.New(tx, d.timeouts)
+
+		events, err := repo.Claim(ctx, limit)
+		if err != nil {
+			return err
+		}
+		processed = len(events)
+
+		for _, event := range events {
+			// Event yang belum di-publish tetap terkunci sampai rollback
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := d.publish(ctx, event); err != nil {
+				d.metrics.CountOutboxEvent(event.EventType, false)
+				if err := repo.MarkFailed(ctx, event.Id, d.retryAfter(event.Attempts), err.Error()); err != nil {
+					return err
+				}
+				continue
+			}
+			d.metrics.CountOutboxEvent(event.EventType, true)
+			if err := repo.MarkSent(ctx, event.Id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return processed, err
+}
+
+func (d *Dispatcher) publish(ctx context.Context, event entity.OutboxEvent) error {
+	payload, err := json.Marshal(events.NewEnvelope(event))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.config.PublishTimeout)
+	defer cancel()
+	return d.publisher.Publish(ctx, event.EventType, payload)
+}
+
+// retryAfter menghitung jeda eksponensial berdasarkan jumlah percobaan
+// sebelumnya.
+func (d *Dispatcher) retryAfter(attempts int) time.Duration {
+	backoff := d.config.RetryBackoff
+	for i := 0; i < attempts && backoff < d.config.MaxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, d.config.MaxRetryBackoff)
+}
+
+// Shutdown menunggu batch yang sedang berjalan selesai. Jika ctx habis lebih
+// dulu, batch dibatalkan dan event-nya tetap belum terkirim.
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	d.once.Do(func() { close(d.stop) })
+	select {
+	case <-d.done:
+		d.cancel()
+		return nil
+	case <-ctx.Done():
+		d.cancel()
+		<-d.done
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,217 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/mocks"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// unavailableDB gagal di setiap BeginTx, sehingga setiap DispatchOnce
+// langsung error tanpa database. begins menghitung percobaan batch.
+type unavailableDB struct {
+	database.DB
+	mu     sync.Mutex
+	begins int
+}
+
+var errUnavailable = errors.New("database unavailable")
+
+func (db *unavailableDB) BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
+	db.mu.Lock()
+	db.begins++
+	db.mu.Unlock()
+	return nil, errUnavailable
+}
+
+func (db *unavailableDB) Begins() int {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.begins
+}
+
+func testOutboxConfig() *config.OutboxConfig {
+	return &config.OutboxConfig{
+		PollInterval:    10 * time.Millisecond,
+		BatchSize:       10,
+		RetryBackoff:    time.Second,
+		MaxRetryBackoff: time.Minute,
+		PublishTimeout:  time.Second,
+	}
+}
+
+func newTestDispatcher(ctx context.Context, db database.DB, publisher Publisher, outboxConfig *config.OutboxConfig) *Dispatcher {
+	return NewDispatcher(
+		ctx,
+		db,
+		publisher,
+		outboxConfig,
+		config.LoadQueryTimeoutConfig(),
+		metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{}),
+		mocks.Logger{},
+	)
+}
+
+func TestRetryAfter(t *testing.T) {
+	d := newTestDispatcher(context.Background(), nil, NopPublisher{}, testOutboxConfig())
+	for _, tt := range []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 0, want: time.Second},
+		{attempts: 1, want: 2 * time.Second},
+		{attempts: 3, want: 8 * time.Second},
+		{attempts: 5, want: 32 * time.Second},
+		{attempts: 6, want: time.Minute},
+		{attempts: 1000, want: time.Minute},
+	} {
+		if got := d.retryAfter(tt.attempts); got != tt.want {
+			t.Errorf("retryAfter(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+type recordingPublisher struct {
+	mu       sync.Mutex
+	subjects []string
+	err      error
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, subject string, payload []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.subjects = append(p.subjects, subject)
+	return p.err
+}
+
+func TestPublishersSendsToAllAndJoinsErrors(t *testing.T) {
+	errFirst := errors.New("first failed")
+	first := &recordingPublisher{err: errFirst}
+	second := &recordingPublisher{}
+
+	err := Publishers{first, second}.Publish(context.Background(), "employee.created", []byte("{}"))
+	if !errors.Is(err, errFirst) {
+		t.Fatalf("Publish error = %v, want %v", err, errFirst)
+	}
+	if len(second.subjects) != 1 {
+		t.Fatalf("second publisher received %d events after the first failed, want 1", len(second.subjects))
+	}
+	if err := (Publishers{second}).Publish(context.Background(), "employee.created", []byte("{}")); err != nil {
+		t.Fatalf("Publish error = %v, want nil", err)
+	}
+}
+
+func TestShutdownStopsPolling(t *testing.T) {
+	db := &unavailableDB{}
+	d := newTestDispatcher(context.Background(), db, NopPublisher{}, testOutboxConfig())
+	d.Start()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for db.Begins() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if db.Begins() < 2 {
+		t.Fatal("dispatcher did not keep polling after a failed batch")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := d.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown error = %v", err)
+	}
+	begins := db.Begins()
+	time.Sleep(50 * time.Millisecond)
+	if got := db.Begins(); got != begins {
+		t.Fatalf("dispatcher started %d batches after Shutdown", got-begins)
+	}
+	// Shutdown kedua tidak boleh panic karena stop sudah ditutup
+	if err := d.Shutdown(ctx); err != nil {
+		t.Fatalf("second Shutdown error = %v", err)
+	}
+}
+
+func TestLifetimeCancelStopsDispatcher(t *testing.T) {
+	lifetime, cancel := context.WithCancel(context.Background())
+	d := newTestDispatcher(lifetime, &unavailableDB{}, NopPublisher{}, testOutboxConfig())
+	d.Start()
+	cancel()
+
+	select {
+	case <-d.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("dispatcher is still running after the lifetime context was cancelled")
+	}
+}
+
+// busyDB menahan BeginTx sampai context batch dibatalkan, seperti batch
+// yang sedang menunggu database.
+type busyDB struct {
+	database.DB
+	began chan struct{}
+}
+
+func (db *busyDB) BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
+	select {
+	case db.began <- struct{}{}:
+	default:
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestShutdownCancelsRunningBatchPromptly(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		stop func(d *Dispatcher, cancelLifetime context.CancelFunc) error
+	}{
+		{
+			// Shutdown yang waktunya habis membatalkan batch yang berjalan
+			name: "Shutdown deadline",
+			stop: func(d *Dispatcher, cancelLifetime context.CancelFunc) error {
+				ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+				defer cancel()
+				if err := d.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+					return fmt.Errorf("Shutdown error = %v, want %v", err, context.DeadlineExceeded)
+				}
+				return nil
+			},
+		},
+		{
+			name: "lifetime cancelled",
+			stop: func(d *Dispatcher, cancelLifetime context.CancelFunc) error {
+				cancelLifetime()
+				<-d.done
+				return nil
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			lifetime, cancelLifetime := context.WithCancel(context.Background())
+			defer cancelLifetime()
+			db := &busyDB{began: make(chan struct{}, 1)}
+			d := newTestDispatcher(lifetime, db, NopPublisher{}, testOutboxConfig())
+			d.Start()
+			<-db.began
+
+			stopped := make(chan error, 1)
+			go func() { stopped <- tt.stop(d, cancelLifetime) }()
+			select {
+			case err := <-stopped:
+				if err != nil {
+					t.Fatal(err)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("dispatcher did not stop promptly")
+			}
+		})
+	}
+}
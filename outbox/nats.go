@@ -0,0 +1,156 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/samber/do/v2"
+)
+
+// NATSPublisher mengirim event ke JetStream dengan subject
+// "<NATSSubjectPrefix>.<tipe event>", mis. events.employee.created. Header
+// Nats-Msg-Id diisi dengan id event, sehingga event yang dikirim ulang dalam
+// duplicate window stream tidak disimpan dua kali.
+type NATSPublisher struct {
+	conn   *nats.Conn
+	js     jetstream.JetStream
+	config *config.EventPublisherConfig
+	logger logger.Logger
+
+	// streamReady diisi setelah stream dipastikan ada. Jika gagal (mis.
+	// server belum tersambung), pengecekan diulang di Publish berikutnya.
+	mu          sync.Mutex
+	streamReady bool
+	closed      chan struct{}
+}
+
+func NewNATSPublisher(config *config.EventPublisherConfig, logger logger.Logger) (*NATSPublisher, error) {
+	if config.NATSSubjectPrefix == "" || config.NATSStream == "" {
+		return nil, errors.New("NATS_STREAM and NATS_SUBJECT_PREFIX must be set")
+	}
+
+	p := &NATSPublisher{config: config, logger: logger, closed: make(chan struct{})}
+	options := []nats.Option{
+		nats.Name("go-gin-template"),
+		nats.Timeout(config.NATSConnectTimeout),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+		nats.CustomReconnectDelay(p.reconnectDelay),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				logger.Warn(fmt.Sprintf("NATS disconnected: %v", err), helper.OutboxNATSPublisher)
+			}
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			logger.Info(fmt.Sprintf("NATS reconnected to %s", conn.ConnectedUrlRedacted()), helper.OutboxNATSPublisher)
+		}),
+		nats.ClosedHandler(func(*nats.Conn) { close(p.closed) }),
+	}
+	if config.NATSCredsFile != "" {
+		options = append(options, nats.UserCredentials(config.NATSCredsFile))
+	}
+	if config.NATSUser != "" {
+		options = append(options, nats.UserInfo(config.NATSUser, config.NATSPassword))
+	}
+
+	// Dengan RetryOnFailedConnect, server yang belum siap tidak membuat
+	// Connect gagal; koneksi dicoba di background dan Publish gagal sampai
+	// tersambung (event tetap di outbox).
+	conn, err := nats.Connect(config.NATSURL, options...)
+	if err != nil {
+		return nil, err
+	}
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	p.conn = conn
+	p.js = js
+	return p, nil
+}
+
+// NewNATSPublisherInject membuat koneksi NATS. Koneksi ditutup saat injector
+// di-shutdown, setelah Dispatcher berhenti.
+func NewNATSPublisherInject(i do.Injector) (*NATSPublisher, error) {
+	appLogger := do.MustInvoke[logger.LogHandler](i)
+	return NewNATSPublisher(config.LoadEventPublisherConfig(), &appLogger)
+}
+
+func (p *NATSPublisher) Publish(ctx context.Context, subject string, payload []byte) error {
+	if err := p.ensureStream(ctx); err != nil {
+		return err
+	}
+
+	var envelope struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return err
+	}
+	_, err := p.js.Publish(
+		ctx,
+		p.config.NATSSubjectPrefix+"."+subject,
+		payload,
+		jetstream.WithMsgID(strconv.FormatInt(envelope.ID, 10)),
+	)
+	return err
+}
+
+func (p *NATSPublisher) ensureStream(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.streamReady {
+		return nil
+	}
+
+	_, err := p.js.Stream(ctx, p.config.NATSStream)
+	if errors.Is(err, jetstream.ErrStreamNotFound) {
+		_, err = p.js.CreateStream(ctx, jetstream.StreamConfig{
+			Name:     p.config.NATSStream,
+			Subjects: []string{p.config.NATSSubjectPrefix + ".>"},
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("ensure NATS stream %s: %w", p.config.NATSStream, err)
+	}
+	p.streamReady = true
+	return nil
+}
+
+// reconnectDelay menghitung jeda eksponensial berdasarkan jumlah percobaan
+// reconnect sebelumnya.
+func (p *NATSPublisher) reconnectDelay(attempts int) time.Duration {
+	backoff := p.config.NATSReconnectWait
+	for i := 0; i < attempts && backoff < p.config.NATSMaxReconnectWait; i++ {
+		backoff *= 2
+	}
+	return min(backoff, p.config.NATSMaxReconnectWait)
+}
+
+// Shutdown mengirim pesan yang masih di-buffer lalu menutup koneksi, lihat
+// do.ShutdownerWithContextAndError. Jika ctx habis lebih dulu, koneksi
+// langsung ditutup.
+func (p *NATSPublisher) Shutdown(ctx context.Context) error {
+	if err := p.conn.Drain(); err != nil {
+		p.conn.Close()
+		return err
+	}
+	select {
+	case <-p.closed:
+		return nil
+	case <-ctx.Done():
+		p.conn.Close()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,59 @@
+//go:build integration
+
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/events"
+)
+
+// TestDispatcherPublishesToNATSInAggregateOrder menjalankan Dispatcher ke
+// server NATS di dalam proses dan memastikan event setiap aggregate masuk
+// stream sesuai urutan outbox.
+func TestDispatcherPublishesToNATSInAggregateOrder(t *testing.T) {
+	pool := dbtest.New(t)
+	ns := runNATS(t, 0)
+	addEvents(t, pool,
+		[2]string{entity.EventEmployeeCreated, "EMP-1"},
+		[2]string{entity.EventEmployeeCreated, "EMP-2"},
+		[2]string{entity.EventEmployeeUpdated, "EMP-1"},
+		[2]string{entity.EventEmployeeUpdated, "EMP-2"},
+		[2]string{entity.EventEmployeeDeleted, "EMP-1"},
+	)
+
+	d := newTestDispatcher(context.Background(), pool, newTestNATSPublisher(t, testNATSConfig(ns.ClientURL())), testOutboxConfig())
+	for dispatch(t, d) > 0 {
+	}
+
+	perAggregate := map[string][]string{}
+	var lastID int64
+	for _, msg := range streamMessages(t, ns.ClientURL()) {
+		var envelope events.Envelope
+		if err := json.Unmarshal(msg.Data(), &envelope); err != nil {
+			t.Fatal(err)
+		}
+		if envelope.ID <= lastID {
+			t.Errorf("envelope id %d after %d", envelope.ID, lastID)
+		}
+		lastID = envelope.ID
+		perAggregate[envelope.AggregateID] = append(perAggregate[envelope.AggregateID], envelope.Type)
+	}
+	want := map[string][]string{
+		"EMP-1": {entity.EventEmployeeCreated, entity.EventEmployeeUpdated, entity.EventEmployeeDeleted},
+		"EMP-2": {entity.EventEmployeeCreated, entity.EventEmployeeUpdated},
+	}
+	for aggregate, types := range want {
+		if !slices.Equal(perAggregate[aggregate], types) {
+			t.Errorf("%s events = %v, want %v", aggregate, perAggregate[aggregate], types)
+		}
+	}
+	if got := unsent(t, pool); got != 0 {
+		t.Fatalf("unsent events = %d, want 0", got)
+	}
+}
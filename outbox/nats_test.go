@@ -0,0 +1,321 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/events"
+	"github.com/levensspel/go-gin-template/mocks"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// runNATS menjalankan server NATS dengan JetStream di dalam proses test pada
+// port. Port 0 berarti port acak.
+func runNATS(t *testing.T, port int) *server.Server {
+	t.Helper()
+	if port == 0 {
+		port = server.RANDOM_PORT
+	}
+	ns, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      port,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go ns.Start()
+	if !ns.ReadyForConnections(5 * time.Second) {
+		t.Fatal("NATS server is not ready")
+	}
+	t.Cleanup(ns.Shutdown)
+	return ns
+}
+
+func testNATSConfig(url string) *config.EventPublisherConfig {
+	return &config.EventPublisherConfig{
+		Publisher:            config.EventPublisherNATS,
+		NATSURL:              url,
+		NATSStream:           "EVENTS",
+		NATSSubjectPrefix:    "events",
+		NATSConnectTimeout:   time.Second,
+		NATSReconnectWait:    10 * time.Millisecond,
+		NATSMaxReconnectWait: 50 * time.Millisecond,
+	}
+}
+
+func newTestNATSPublisher(t *testing.T, eventConfig *config.EventPublisherConfig) *NATSPublisher {
+	t.Helper()
+	publisher, err := NewNATSPublisher(eventConfig, mocks.Logger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { publisher.conn.Close() })
+	return publisher
+}
+
+func envelopePayload(t *testing.T, id int64, eventType, aggregateID string) []byte {
+	t.Helper()
+	payload, err := json.Marshal(events.Envelope{
+		ID:            id,
+		Type:          eventType,
+		Version:       events.Version,
+		AggregateType: entity.AggregateEmployee,
+		AggregateID:   aggregateID,
+		Data:          json.RawMessage(`{}`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return payload
+}
+
+// streamMessages membaca semua pesan stream EVENTS sesuai urutan sequence.
+func streamMessages(t *testing.T, url string) []jetstream.Msg {
+	t.Helper()
+	conn, err := nats.Connect(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	js, err := jetstream.New(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	consumer, err := js.OrderedConsumer(ctx, "EVENTS", jetstream.OrderedConsumerConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := consumer.Info(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.NumPending == 0 {
+		return nil
+	}
+	batch, err := consumer.Fetch(int(info.NumPending), jetstream.FetchMaxWait(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var messages []jetstream.Msg
+	for msg := range batch.Messages() {
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+func TestNATSPublisherPublishesInOrder(t *testing.T) {
+	ns := runNATS(t, 0)
+	publisher := newTestNATSPublisher(t, testNATSConfig(ns.ClientURL()))
+	ctx := context.Background()
+
+	published := []struct {
+		id          int64
+		eventType   string
+		aggregateID string
+	}{
+		{id: 1, eventType: entity.EventEmployeeCreated, aggregateID: "EMP-1"},
+		{id: 2, eventType: entity.EventEmployeeCreated, aggregateID: "EMP-2"},
+		{id: 3, eventType: entity.EventEmployeeUpdated, aggregateID: "EMP-1"},
+		{id: 4, eventType: entity.EventEmployeeDeleted, aggregateID: "EMP-1"},
+	}
+	for _, event := range published {
+		if err := publisher.Publish(ctx, event.eventType, envelopePayload(t, event.id, event.eventType, event.aggregateID)); err != nil {
+			t.Fatalf("Publish %d error = %v", event.id, err)
+		}
+	}
+
+	messages := streamMessages(t, ns.ClientURL())
+	if len(messages) != len(published) {
+		t.Fatalf("stream has %d messages, want %d", len(messages), len(published))
+	}
+	for i, msg := range messages {
+		want := published[i]
+		if msg.Subject() != "events."+want.eventType {
+			t.Errorf("message %d subject = %s, want events.%s", i, msg.Subject(), want.eventType)
+		}
+		if id := msg.Headers().Get(jetstream.MsgIDHeader); id != strconv.FormatInt(want.id, 10) {
+			t.Errorf("message %d %s = %q, want %d", i, jetstream.MsgIDHeader, id, want.id)
+		}
+		var envelope events.Envelope
+		if err := json.Unmarshal(msg.Data(), &envelope); err != nil {
+			t.Fatal(err)
+		}
+		if envelope.ID != want.id || envelope.AggregateID != want.aggregateID || envelope.Version != events.Version {
+			t.Errorf("message %d envelope = %+v", i, envelope)
+		}
+	}
+}
+
+func TestNATSPublisherDeduplicatesRedeliveredEvents(t *testing.T) {
+	ns := runNATS(t, 0)
+	publisher := newTestNATSPublisher(t, testNATSConfig(ns.ClientURL()))
+	payload := envelopePayload(t, 7, entity.EventEmployeeCreated, "EMP-1")
+
+	// Outbox mengirim ulang event yang sama jika batch-nya gagal di-commit
+	for range 3 {
+		if err := publisher.Publish(context.Background(), entity.EventEmployeeCreated, payload); err != nil {
+			t.Fatalf("Publish error = %v", err)
+		}
+	}
+	if messages := streamMessages(t, ns.ClientURL()); len(messages) != 1 {
+		t.Fatalf("stream has %d messages, want 1", len(messages))
+	}
+}
+
+func TestNATSPublisherKeepsExistingStream(t *testing.T) {
+	ns := runNATS(t, 0)
+	conn, err := nats.Connect(ns.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	js, err := jetstream.New(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if _, err := js.CreateStream(ctx, jetstream.StreamConfig{Name: "EVENTS", Subjects: []string{"events.>"}, MaxMsgs: 100}); err != nil {
+		t.Fatal(err)
+	}
+
+	publisher := newTestNATSPublisher(t, testNATSConfig(ns.ClientURL()))
+	if err := publisher.Publish(ctx, entity.EventEmployeeCreated, envelopePayload(t, 1, entity.EventEmployeeCreated, "EMP-1")); err != nil {
+		t.Fatalf("Publish error = %v", err)
+	}
+	stream, err := js.Stream(ctx, "EVENTS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := stream.Info(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Config.MaxMsgs != 100 || info.State.Msgs != 1 {
+		t.Fatalf("stream config max msgs = %d, msgs = %d, want the existing stream", info.Config.MaxMsgs, info.State.Msgs)
+	}
+}
+
+// freePort mengembalikan port TCP yang sedang tidak dipakai.
+func freePort(t *testing.T) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestNATSPublisherConnectsAfterServerStarts(t *testing.T) {
+	port := freePort(t)
+	url := "nats://127.0.0.1:" + strconv.Itoa(port)
+	// Server belum jalan: NewNATSPublisher tetap berhasil dan Publish gagal
+	publisher := newTestNATSPublisher(t, testNATSConfig(url))
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if err := publisher.Publish(ctx, entity.EventEmployeeCreated, envelopePayload(t, 1, entity.EventEmployeeCreated, "EMP-1")); err == nil {
+		t.Fatal("Publish succeeded without a server")
+	}
+
+	runNATS(t, port)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		err := publisher.Publish(ctx, entity.EventEmployeeCreated, envelopePayload(t, 1, entity.EventEmployeeCreated, "EMP-1"))
+		cancel()
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Publish after the server started error = %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if messages := streamMessages(t, url); len(messages) != 1 {
+		t.Fatalf("stream has %d messages, want 1", len(messages))
+	}
+}
+
+func TestNATSPublisherShutdownClosesConnection(t *testing.T) {
+	ns := runNATS(t, 0)
+	publisher := newTestNATSPublisher(t, testNATSConfig(ns.ClientURL()))
+	if err := publisher.Publish(context.Background(), entity.EventEmployeeCreated, envelopePayload(t, 1, entity.EventEmployeeCreated, "EMP-1")); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := publisher.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown error = %v", err)
+	}
+	if !publisher.conn.IsClosed() {
+		t.Fatal("connection is still open after Shutdown")
+	}
+	if err := publisher.Publish(context.Background(), entity.EventEmployeeCreated, envelopePayload(t, 2, entity.EventEmployeeCreated, "EMP-1")); err == nil {
+		t.Fatal("Publish succeeded after Shutdown")
+	}
+}
+
+func TestNewNATSPublisherRequiresStream(t *testing.T) {
+	for _, modify := range []func(c *config.EventPublisherConfig){
+		func(c *config.EventPublisherConfig) { c.NATSStream = "" },
+		func(c *config.EventPublisherConfig) { c.NATSSubjectPrefix = "" },
+	} {
+		eventConfig := testNATSConfig("nats://127.0.0.1:1")
+		modify(eventConfig)
+		if _, err := NewNATSPublisher(eventConfig, mocks.Logger{}); err == nil {
+			t.Errorf("NewNATSPublisher(%+v) succeeded", eventConfig)
+		}
+	}
+}
+
+func TestNATSReconnectDelay(t *testing.T) {
+	p := &NATSPublisher{config: &config.EventPublisherConfig{NATSReconnectWait: time.Second, NATSMaxReconnectWait: 30 * time.Second}}
+	for attempts, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 30 * time.Second} {
+		if got := p.reconnectDelay(attempts); got != want {
+			t.Errorf("reconnectDelay(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
+
+func TestPublishersJoinsErrors(t *testing.T) {
+	errFirst := errors.New("first failed")
+	errSecond := errors.New("second failed")
+	var calls int
+	failing := func(err error) Publisher {
+		return publisherFunc(func(ctx context.Context, subject string, payload []byte) error {
+			calls++
+			return err
+		})
+	}
+
+	err := Publishers{failing(errFirst), failing(nil), failing(errSecond)}.Publish(context.Background(), entity.EventEmployeeCreated, nil)
+	// Publisher setelah yang gagal tetap dipanggil
+	if calls != 3 || !errors.Is(err, errFirst) || !errors.Is(err, errSecond) {
+		t.Fatalf("Publishers.Publish = %v after %d calls", err, calls)
+	}
+	if err := (Publishers{failing(nil)}).Publish(context.Background(), entity.EventEmployeeCreated, nil); err != nil {
+		t.Fatalf("Publishers.Publish = %v, want nil", err)
+	}
+}
+
+type publisherFunc func(ctx context.Context, subject string, payload []byte) error
+
+func (f publisherFunc) Publish(ctx context.Context, subject string, payload []byte) error {
+	return f(ctx, subject, payload)
+}
@@ -0,0 +1,76 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/samber/do/v2"
+)
+
+// Publisher mengirim satu event ke sistem luar. subject adalah tipe event
+// (mis. employee.created) dan payload adalah events.Envelope dalam JSON.
+// Pengiriman bersifat at-least-once, sehingga consumer harus bisa menerima
+// event yang sama lebih dari sekali (dedupe dengan Envelope.ID).
+type Publisher interface {
+	Publish(ctx context.Context, subject string, payload []byte) error
+}
+
+// NewPublisherInject memilih Publisher berdasarkan EVENT_PUBLISHER.
+func NewPublisherInject(i do.Injector) (Publisher, error) {
+	eventConfig := config.LoadEventPublisherConfig()
+	switch eventConfig.Publisher {
+	case config.EventPublisherNone:
+		return NopPublisher{}, nil
+	case config.EventPublisherLog:
+		return NewLogPublisherInject(i)
+	case config.EventPublisherNATS:
+		return do.Invoke[*NATSPublisher](i)
+	default:
+		return nil, fmt.Errorf("unknown EVENT_PUBLISHER %q", eventConfig.Publisher)
+	}
+}
+
+// NopPublisher membuang semua event, dipakai jika tidak ada consumer.
+type NopPublisher struct{}
+
+func (NopPublisher) Publish(ctx context.Context, subject string, payload []byte) error {
+	return nil
+}
+
+// LogPublisher hanya menulis event ke log, untuk development.
+type LogPublisher struct {
+	logger logger.Logger
+}
+
+func NewLogPublisher(logger logger.Logger) *LogPublisher {
+	return &LogPublisher{logger: logger}
+}
+
+func NewLogPublisherInject(i do.Injector) (Publisher, error) {
+	appLogger := do.MustInvoke[logger.LogHandler](i)
+	return NewLogPublisher(&appLogger), nil
+}
+
+func (p *LogPublisher) Publish(ctx context.Context, subject string, payload []byte) error {
+	p.logger.Info(fmt.Sprintf("Publish %s", subject), helper.OutboxDispatcher, string(payload))
+	return nil
+}
+
+// Publishers mengirim event ke semua Publisher dan gagal jika salah satunya
+// gagal. Event yang gagal dikirim ulang ke semuanya, sehingga setiap
+// Publisher harus tahan terhadap event ganda.
+type Publishers []Publisher
+
+func (p Publishers) Publish(ctx context.Context, subject string, payload []byte) error {
+	var errs []error
+	for _, publisher := range p {
+		if err := publisher.Publish(ctx, subject, payload); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
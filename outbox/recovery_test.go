@@ -0,0 +1,255 @@
+//go:build integration
+
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/events"
+	"github.com/levensspel/go-gin-template/repository"
+)
+
+// envelopePublisher mencatat Envelope yang di-publish. Jika fail mengembalikan
+// error untuk sebuah envelope, pengirimannya dianggap gagal.
+type envelopePublisher struct {
+	mu        sync.Mutex
+	envelopes []events.Envelope
+	fail      func(envelope events.Envelope) error
+}
+
+func (p *envelopePublisher) Publish(ctx context.Context, subject string, payload []byte) error {
+	var envelope events.Envelope
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.envelopes = append(p.envelopes, envelope)
+	if p.fail != nil {
+		return p.fail(envelope)
+	}
+	return nil
+}
+
+func (p *envelopePublisher) published() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	result := make([]string, len(p.envelopes))
+	for i, envelope := range p.envelopes {
+		result[i] = envelope.Type + ":" + envelope.AggregateID
+	}
+	return result
+}
+
+// addEvents menulis event lewat UnitOfWork, seperti service menulisnya
+// bersama perubahan data.
+func addEvents(t *testing.T, pool *pgxpool.Pool, added ...[2]string) {
+	t.Helper()
+	uow := repository.NewUnitOfWork(pool, config.LoadQueryTimeoutConfig(), &config.SearchConfig{})
+	err := uow.Do(context.Background(), func(repos repository.Repositories) error {
+		for _, event := range added {
+			if err := repos.Outbox.Add(context.Background(), event[0], entity.AggregateEmployee, event[1], map[string]string{"identityNumber": event[1]}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("add outbox events: %v", err)
+	}
+}
+
+func unsent(t *testing.T, pool *pgxpool.Pool) int {
+	t.Helper()
+	var count int
+	if err := pool.QueryRow(context.Background(), "SELECT COUNT(*) FROM outbox WHERE sent_at IS NULL;").Scan(&count); err != nil {
+		t.Fatalf("count unsent events: %v", err)
+	}
+	return count
+}
+
+func dispatch(t *testing.T, d *Dispatcher) int {
+	t.Helper()
+	processed, err := d.DispatchOnce(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("DispatchOnce error = %v", err)
+	}
+	return processed
+}
+
+func TestRolledBackWriteHasNoEvent(t *testing.T) {
+	pool := dbtest.New(t)
+	uow := repository.NewUnitOfWork(pool, config.LoadQueryTimeoutConfig(), &config.SearchConfig{})
+	errFailed := errors.New("mutation failed")
+	err := uow.Do(context.Background(), func(repos repository.Repositories) error {
+		if err := repos.Outbox.Add(context.Background(), entity.EventEmployeeCreated, entity.AggregateEmployee, "EMP-1", nil); err != nil {
+			return err
+		}
+		return errFailed
+	})
+	if !errors.Is(err, errFailed) {
+		t.Fatalf("Do error = %v, want %v", err, errFailed)
+	}
+
+	publisher := &envelopePublisher{}
+	if processed := dispatch(t, newTestDispatcher(context.Background(), pool, publisher, testOutboxConfig())); processed != 0 {
+		t.Fatalf("dispatched %d events from a rolled back transaction", processed)
+	}
+}
+
+// TestRecoversEventsAfterCrash mensimulasikan instance yang mati setelah
+// mutasi di-commit: sebelum event di-publish, dan setelah publish tetapi
+// sebelum batch-nya di-commit. Dispatcher berikutnya harus tetap mengirim
+// event tersebut.
+func TestRecoversEventsAfterCrash(t *testing.T) {
+	pool := dbtest.New(t)
+	addEvents(t, pool, [2]string{entity.EventEmployeeCreated, "EMP-1"})
+
+	// Crash setelah publish: context batch dibatalkan di dalam Publish,
+	// sehingga MarkSent gagal dan batch di-rollback
+	crashCtx, crash := context.WithCancel(context.Background())
+	crashing := &envelopePublisher{fail: func(events.Envelope) error {
+		crash()
+		return nil
+	}}
+	if _, err := newTestDispatcher(context.Background(), pool, crashing, testOutboxConfig()).DispatchOnce(crashCtx, 10); err == nil {
+		t.Fatal("DispatchOnce after the crash error = nil, want the batch to fail")
+	}
+	if got := unsent(t, pool); got != 1 {
+		t.Fatalf("unsent events after crash = %d, want 1", got)
+	}
+
+	restarted := &envelopePublisher{}
+	d := newTestDispatcher(context.Background(), pool, restarted, testOutboxConfig())
+	if processed := dispatch(t, d); processed != 1 {
+		t.Fatalf("dispatched %d events after restart, want 1", processed)
+	}
+	// Pengiriman ulang memakai id yang sama agar consumer bisa dedupe
+	if crashing.envelopes[0].ID != restarted.envelopes[0].ID {
+		t.Fatalf("redelivered envelope id = %d, want %d", restarted.envelopes[0].ID, crashing.envelopes[0].ID)
+	}
+	if got := unsent(t, pool); got != 0 {
+		t.Fatalf("unsent events after restart = %d, want 0", got)
+	}
+	if processed := dispatch(t, d); processed != 0 {
+		t.Fatalf("dispatched %d events twice after they were marked sent", processed)
+	}
+}
+
+func TestFailedEventIsRetriedLaterInOrder(t *testing.T) {
+	pool := dbtest.New(t)
+	addEvents(t, pool,
+		[2]string{entity.EventEmployeeCreated, "EMP-1"},
+		[2]string{entity.EventEmployeeUpdated, "EMP-1"},
+		[2]string{entity.EventEmployeeCreated, "EMP-2"},
+	)
+
+	errBroker := errors.New("broker unavailable")
+	failing := &envelopePublisher{fail: func(envelope events.Envelope) error {
+		if envelope.AggregateID == "EMP-1" {
+			return errBroker
+		}
+		return nil
+	}}
+	outboxConfig := testOutboxConfig()
+	outboxConfig.RetryBackoff = time.Hour
+	outboxConfig.MaxRetryBackoff = time.Hour
+	d := newTestDispatcher(context.Background(), pool, failing, outboxConfig)
+
+	// Event kedua EMP-1 tidak diambil selama event pertamanya belum terkirim
+	if processed := dispatch(t, d); processed != 2 {
+		t.Fatalf("first batch processed %d events, want 2", processed)
+	}
+	want := []string{"employee.created:EMP-1", "employee.created:EMP-2"}
+	if got := failing.published(); !slices.Equal(got, want) {
+		t.Fatalf("published = %v, want %v", got, want)
+	}
+	// Event yang gagal ditunda RetryBackoff
+	if processed := dispatch(t, d); processed != 0 {
+		t.Fatalf("dispatched %d events before the retry backoff passed", processed)
+	}
+
+	var attempts int
+	var lastError string
+	err := pool.QueryRow(
+		context.Background(),
+		"SELECT attempts, last_error FROM outbox WHERE aggregate_id = 'EMP-1' AND event_type = $1;",
+		entity.EventEmployeeCreated,
+	).Scan(&attempts, &lastError)
+	if err != nil {
+		t.Fatalf("get failed event: %v", err)
+	}
+	if attempts != 1 || lastError != errBroker.Error() {
+		t.Fatalf("failed event attempts, last_error = %d, %q", attempts, lastError)
+	}
+
+	// Setelah jedanya lewat, urutan EMP-1 tetap created lalu updated
+	if _, err := pool.Exec(context.Background(), "UPDATE outbox SET next_attempt_at = CURRENT_TIMESTAMP WHERE sent_at IS NULL;"); err != nil {
+		t.Fatalf("expire retry backoff: %v", err)
+	}
+	recovered := &envelopePublisher{}
+	d = newTestDispatcher(context.Background(), pool, recovered, outboxConfig)
+	dispatch(t, d)
+	dispatch(t, d)
+	want = []string{"employee.created:EMP-1", "employee.updated:EMP-1"}
+	if got := recovered.published(); !slices.Equal(got, want) {
+		t.Fatalf("published after retry = %v, want %v", got, want)
+	}
+}
+
+// TestConcurrentDispatchersDoNotDuplicate menjalankan beberapa dispatcher
+// bersamaan; SKIP LOCKED membuat setiap event hanya dikirim sekali.
+func TestConcurrentDispatchersDoNotDuplicate(t *testing.T) {
+	pool := dbtest.New(t)
+	var all [][2]string
+	for i := range 50 {
+		all = append(all, [2]string{entity.EventEmployeeCreated, fmt.Sprintf("EMP-%d", i)})
+	}
+	addEvents(t, pool, all...)
+
+	publisher := &envelopePublisher{}
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d := newTestDispatcher(context.Background(), pool, publisher, testOutboxConfig())
+			for {
+				processed, err := d.DispatchOnce(context.Background(), 5)
+				if err != nil {
+					t.Errorf("DispatchOnce error = %v", err)
+					return
+				}
+				if processed == 0 {
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := unsent(t, pool); got != 0 {
+		t.Fatalf("unsent events = %d, want 0", got)
+	}
+	seen := map[int64]bool{}
+	for _, envelope := range publisher.envelopes {
+		if seen[envelope.ID] {
+			t.Fatalf("event %d published more than once", envelope.ID)
+		}
+		seen[envelope.ID] = true
+	}
+	if len(seen) != len(all) {
+		t.Fatalf("published %d events, want %d", len(seen), len(all))
+	}
+}
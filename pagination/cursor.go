@@ -0,0 +1,90 @@
+package pagination
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+type Direction string
+
+const (
+	// Forward mengambil baris setelah cursor dengan urutan naik.
+	Forward Direction = "next"
+	// Backward mengambil baris sebelum cursor, untuk halaman sebelumnya.
+	Backward Direction = "prev"
+)
+
+// Cursor adalah posisi keyset (createdAt, identityNumber). identityNumber
+// unik, sehingga baris dengan createdAt yang sama tetap punya urutan pasti.
+// Cursor kosong berarti halaman pertama.
+type Cursor struct {
+	CreatedAt      time.Time `json:"c"`
+	IdentityNumber string    `json:"i"`
+	Direction      Direction `json:"d"`
+}
+
+func (c Cursor) IsZero() bool {
+	return c.CreatedAt.IsZero() && c.IdentityNumber == ""
+}
+
+// IsBackward juga menangani cursor tanpa Direction sebagai Forward.
+func (c Cursor) IsBackward() bool {
+	return c.Direction == Backward
+}
+
+// Codec meng-encode cursor menjadi string opaque
+// base64(json).base64(hmac-sha256(json)).
+type Codec struct {
+	secret []byte
+}
+
+func NewCodec(secret []byte) *Codec {
+	return &Codec{secret: secret}
+}
+
+func (c *Codec) Encode(cursor Cursor) string {
+	payload, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(c.sign(payload))
+}
+
+// Decode mengembalikan helper.ErrInvalidCursor jika format atau tanda
+// tangan cursor tidak valid.
+func (c *Codec) Decode(token string) (Cursor, error) {
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return Cursor{}, helper.ErrInvalidCursor
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return Cursor{}, helper.ErrInvalidCursor
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, c.sign(payload)) {
+		return Cursor{}, helper.ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cursor); err != nil {
+		return Cursor{}, helper.ErrInvalidCursor
+	}
+	if cursor.Direction != "" && cursor.Direction != Forward && cursor.Direction != Backward {
+		return Cursor{}, helper.ErrInvalidCursor
+	}
+	return cursor, nil
+}
+
+func (c *Codec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
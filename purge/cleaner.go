@@ -0,0 +1,329 @@
+package purge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/clock"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/domain"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/lifecycle"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/metrics"
+	apiKeyRepository "github.com/levensspel/go-gin-template/repository/apikey"
+	fileRepository "github.com/levensspel/go-gin-template/repository/file"
+	integrationRepository "github.com/levensspel/go-gin-template/repository/integration"
+	outboxRepository "github.com/levensspel/go-gin-template/repository/outbox"
+	webhookRepository "github.com/levensspel/go-gin-template/repository/webhook"
+	"github.com/levensspel/go-gin-template/thumbnail"
+	"github.com/samber/do/v2"
+)
+
+// ErrUnknownCleanupTable dikembalikan RunOnce jika table bukan salah satu
+// target cleanup.
+var ErrUnknownCleanupTable = errors.New("unknown cleanup table")
+
+func init() {
+	helper.Register(ErrUnknownCleanupTable, http.StatusBadRequest, "ErrUnknownCleanupTable")
+}
+
+// CleanupTarget adalah satu tabel yang dibersihkan Cleaner. Delete
+// menghapus paling banyak limit baris yang lebih tua dari retention lewat
+// db (koneksi pemegang lock) dan mengembalikan jumlahnya.
+type CleanupTarget struct {
+	Table     string
+	Interval  time.Duration
+	Retention time.Duration
+	Delete    func(ctx context.Context, db database.Querier, retention time.Duration, limit int) (int64, error)
+}
+
+type CleanupReport struct {
+	// Tables berisi jumlah baris yang dihapus per tabel.
+	Tables map[string]int64 `json:"tables"`
+}
+
+// Cleaner menghapus baris kedaluwarsa dari beberapa tabel, masing-masing
+// dengan jadwal sendiri. Seperti Purger, setiap tabel hanya dibersihkan
+// satu instance pada satu waktu (advisory lock per tabel), dalam batch
+// kecil dengan jeda agar tidak menahan write di tabel yang sama.
+type Cleaner struct {
+	pool    *pgxpool.Pool
+	targets []CleanupTarget
+	config  *config.CleanupConfig
+	metrics *metrics.Metrics
+	logger  logger.Logger
+	clock   clock.Clock
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	stop   chan struct{}
+	done   chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+func NewCleaner(
+	ctx context.Context,
+	pool *pgxpool.Pool,
+	targets []CleanupTarget,
+	config *config.CleanupConfig,
+	metrics *metrics.Metrics,
+	logger logger.Logger,
+	clock clock.Clock,
+) *Cleaner {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Cleaner{
+		pool:    pool,
+		targets: targets,
+		config:  config,
+		metrics: metrics,
+		logger:  logger,
+		clock:   clock,
+		ctx:     ctx,
+		cancel:  cancel,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// NewCleanerInject langsung menjalankan cleanup terjadwal. Cleaner
+// dihentikan saat injector di-shutdown.
+func NewCleanerInject(i do.Injector) (*Cleaner, error) {
+	cleanupConfig := config.LoadCleanupConfig()
+	pool := do.MustInvoke[*database.Pool](i).Pool
+	outboxRepo := outboxRepository.New(pool, config.LoadQueryTimeoutConfig())
+	webhookRepo := do.MustInvoke[webhookRepository.WebhookRepositoryInterface](i)
+	fileRepo := do.MustInvoke[fileRepository.FileRepositoryInterface](i)
+	appLogger := do.MustInvoke[logger.LogHandler](i)
+
+	cleaner := NewCleaner(
+		do.MustInvoke[*lifecycle.Lifetime](i).Context(),
+		pool,
+		[]CleanupTarget{
+			{
+				Table:     "outbox",
+				Interval:  cleanupConfig.OutboxInterval,
+				Retention: cleanupConfig.OutboxRetention,
+				Delete:    outboxRepo.DeleteSent,
+			},
+			{
+				Table:     "webhook_delivery",
+				Interval:  cleanupConfig.WebhookDeliveryInterval,
+				Retention: cleanupConfig.WebhookDeliveryRetention,
+				Delete:    webhookRepo.DeleteFinished,
+			},
+			{
+				Table:     "file_upload",
+				Interval:  cleanupConfig.FileUploadInterval,
+				Retention: cleanupConfig.FileUploadRetention,
+				Delete:    deleteExpiredUploads(fileRepo, do.MustInvoke[domain.Storage](i), &appLogger),
+			},
+			{
+				Table:     "file",
+				Interval:  cleanupConfig.FileInterval,
+				Retention: cleanupConfig.FileGracePeriod,
+				Delete:    deleteUnreferencedFiles(fileRepo, do.MustInvoke[domain.Storage](i), config.LoadResizeConfig(), &appLogger),
+			},
+			{
+				Table:     "api_key",
+				Interval:  cleanupConfig.APIKeyInterval,
+				Retention: cleanupConfig.APIKeyRetention,
+				Delete:    do.MustInvoke[apiKeyRepository.APIKeyRepositoryInterface](i).DeleteExpired,
+			},
+			{
+				Table:    "integration_callback",
+				Interval: cleanupConfig.IntegrationCallbackInterval,
+				// Callback yang dihapus sebelum toleransi lewat bisa diterima ulang
+				Retention: max(cleanupConfig.IntegrationCallbackRetention, config.LoadIntegrationConfig().SignatureTolerance),
+				Delete:    do.MustInvoke[integrationRepository.IntegrationRepositoryInterface](i).DeleteProcessed,
+			},
+		},
+		cleanupConfig,
+		do.MustInvoke[*metrics.Metrics](i),
+		&appLogger,
+		do.MustInvoke[clock.Clock](i),
+	)
+	cleaner.Start()
+	return cleaner, nil
+}
+
+// deleteExpiredUploads menghapus upload presign yang kedaluwarsa beserta
+// object-nya. Object yang gagal dihapus hanya di-log, karena barisnya sudah
+// terhapus dan object tanpa file tidak pernah dilayani.
+func deleteExpiredUploads(
+	repo fileRepository.FileRepositoryInterface,
+	storage domain.Storage,
+	logger logger.Logger,
+) func(ctx context.Context, db database.Querier, retention time.Duration, limit int) (int64, error) {
+	return func(ctx context.Context, db database.Querier, retention time.Duration, limit int) (int64, error) {
+		keys, err := repo.DeleteExpiredUploads(ctx, db, retention, limit)
+		if err != nil {
+			return 0, err
+		}
+		for _, key := range keys {
+			if err := storage.Delete(ctx, key); err != nil && !errors.Is(err, helper.ErrNotFound) {
+				logger.Warn(fmt.Sprintf("Failed to delete expired upload %s: %v", key, err), helper.CleanupJob)
+			}
+		}
+		return int64(len(keys)), nil
+	}
+}
+
+// deleteUnreferencedFiles menghapus file yang tidak dirujuk lebih dari grace
+// period beserta object file, thumbnail, dan hasil resize untuk ukuran di
+// IMAGE_RESIZE_SIZES. Object yang sudah tidak ada dilewati, dan yang gagal
+// dihapus hanya di-log seperti deleteExpiredUploads.
+func deleteUnreferencedFiles(
+	repo fileRepository.FileRepositoryInterface,
+	storage domain.Storage,
+	resizeConfig *config.ResizeConfig,
+	logger logger.Logger,
+) func(ctx context.Context, db database.Querier, gracePeriod time.Duration, limit int) (int64, error) {
+	return func(ctx context.Context, db database.Querier, gracePeriod time.Duration, limit int) (int64, error) {
+		files, err := repo.DeleteUnreferenced(ctx, db, gracePeriod, limit)
+		if err != nil {
+			return 0, err
+		}
+		for _, file := range files {
+			for _, key := range thumbnail.ObjectKeys(file, resizeConfig.Sizes) {
+				if err := storage.Delete(ctx, key); err != nil && !errors.Is(err, helper.ErrNotFound) {
+					logger.Warn(fmt.Sprintf("Failed to delete unreferenced file %s: %v", key, err), helper.CleanupJob)
+				}
+			}
+		}
+		return int64(len(files)), nil
+	}
+}
+
+func (c *Cleaner) Start() {
+	for _, target := range c.targets {
+		if target.Interval <= 0 {
+			continue
+		}
+		c.wg.Add(1)
+		go c.run(target)
+	}
+	go func() {
+		c.wg.Wait()
+		close(c.done)
+	}()
+}
+
+func (c *Cleaner) run(target CleanupTarget) {
+	defer c.wg.Done()
+
+	ticker := c.clock.NewTicker(target.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C():
+		}
+
+		deleted, err := c.clean(c.ctx, target)
+		switch {
+		case errors.Is(err, ErrNotLeader), c.ctx.Err() != nil:
+		case err != nil:
+			c.logger.Warn(fmt.Sprintf("Cleanup of %s failed: %v", target.Table, err), helper.CleanupJob)
+		case deleted > 0:
+			c.logger.Info(fmt.Sprintf("Cleaned up %d rows from %s", deleted, target.Table), helper.CleanupJob)
+		}
+	}
+}
+
+// RunOnce membersihkan satu tabel, atau semua tabel jika table kosong,
+// sampai tidak ada baris kedaluwarsa yang tersisa. Report berisi jumlah
+// baris yang sudah terhapus walau err tidak nil; tabel yang sedang
+// dibersihkan instance lain dilewati dan membuat err berisi ErrNotLeader.
+func (c *Cleaner) RunOnce(ctx context.Context, table string) (CleanupReport, error) {
+	report := CleanupReport{Tables: make(map[string]int64, len(c.targets))}
+	var errs []error
+	found := false
+	for _, target := range c.targets {
+		if table != "" && target.Table != table {
+			continue
+		}
+		found = true
+		deleted, err := c.clean(ctx, target)
+		report.Tables[target.Table] = deleted
+		if err != nil && !errors.Is(err, ErrNotLeader) {
+			return report, err
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if !found {
+		return report, ErrUnknownCleanupTable
+	}
+	if len(errs) > 0 {
+		return report, ErrNotLeader
+	}
+	return report, nil
+}
+
+func (c *Cleaner) clean(ctx context.Context, target CleanupTarget) (deleted int64, err error) {
+	err = withLeaderLock(ctx, c.pool, cleanupLockKey(target.Table), func(conn *pgxpool.Conn) error {
+		start := time.Now()
+		defer func() { c.metrics.ObserveCleanup(target.Table, time.Since(start)) }()
+
+		batchSize := max(c.config.BatchSize, 1)
+		for {
+			// Setiap batch adalah satu statement (transaksi sendiri), jadi
+			// lock baris dilepas setiap batch
+			rows, err := target.Delete(ctx, conn, target.Retention, batchSize)
+			deleted += rows
+			c.metrics.CountCleanedUp(target.Table, rows)
+			if err != nil {
+				return err
+			}
+			if rows < int64(batchSize) {
+				return nil
+			}
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-c.stop:
+				return context.Canceled
+			case <-c.clock.After(c.config.BatchPause):
+			}
+		}
+	})
+	return deleted, err
+}
+
+// cleanupLockKey adalah key advisory lock per tabel, berbeda dari
+// advisoryLockKey milik Purger.
+func cleanupLockKey(table string) int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte("cleanup:" + table))
+	return int64(hash.Sum64())
+}
+
+// Shutdown menghentikan cleanup terjadwal dan membatalkan batch yang sedang
+// berjalan, lalu menunggu goroutine-nya selesai.
+func (c *Cleaner) Shutdown(ctx context.Context) error {
+	c.once.Do(func() {
+		close(c.stop)
+		c.cancel()
+	})
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
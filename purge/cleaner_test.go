@@ -0,0 +1,392 @@
+//go:build integration
+
+package purge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/clock/clocktest"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/mocks"
+	apiKeyRepository "github.com/levensspel/go-gin-template/repository/apikey"
+	integrationRepository "github.com/levensspel/go-gin-template/repository/integration"
+	outboxRepository "github.com/levensspel/go-gin-template/repository/outbox"
+	webhookRepository "github.com/levensspel/go-gin-template/repository/webhook"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// cleanupFixture berisi baris outbox, webhook_delivery, api_key dan
+// integration_callback: expired sudah lewat retention dan harus dihapus,
+// live tidak boleh tersentuh (belum terkirim, masih pending, belum
+// diproses, tidak kedaluwarsa, atau belum lewat retention).
+type cleanupFixture struct {
+	pool    *pgxpool.Pool
+	metrics *metrics.Metrics
+	clock   *clocktest.Fake
+	webhook string
+	// seq membuat id dan signature unik untuk api_key dan integration_callback
+	seq     int
+	expired map[string][]any
+	live    map[string][]any
+}
+
+// cleanupTables adalah semua tabel yang dibersihkan cleaner fixture.
+var cleanupTables = []string{"outbox", "webhook_delivery", "api_key", "integration_callback"}
+
+func newCleanupFixture(t *testing.T) *cleanupFixture {
+	t.Helper()
+	pool := dbtest.New(t)
+	f := &cleanupFixture{
+		pool:    pool,
+		metrics: metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{}),
+		clock:   clocktest.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+		webhook: "8a0f8b1e-3f7c-4f57-9d8e-1c2b3a4d5e6f",
+		expired: map[string][]any{},
+		live:    map[string][]any{},
+	}
+	_, err := pool.Exec(
+		context.Background(),
+		"INSERT INTO webhook (id, manager_id, url, secret) VALUES ($1, 'manager', 'https://example.com/hook', 'secret');",
+		f.webhook,
+	)
+	if err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+
+	for range 3 {
+		f.expired["outbox"] = append(f.expired["outbox"], f.outbox(t, "2 hours"))
+	}
+	f.live["outbox"] = []any{f.outbox(t, ""), f.outbox(t, "0 seconds")}
+
+	for _, status := range []string{"delivered", "dead", "delivered"} {
+		f.expired["webhook_delivery"] = append(f.expired["webhook_delivery"], f.delivery(t, status, "2 hours"))
+	}
+	f.live["webhook_delivery"] = []any{f.delivery(t, "pending", "2 hours"), f.delivery(t, "delivered", "0 seconds")}
+
+	for range 3 {
+		f.expired["api_key"] = append(f.expired["api_key"], f.apiKey(t, "-2 hours"))
+	}
+	f.live["api_key"] = []any{f.apiKey(t, ""), f.apiKey(t, "0 seconds"), f.apiKey(t, "24 hours")}
+
+	for range 3 {
+		f.expired["integration_callback"] = append(f.expired["integration_callback"], f.callback(t, true, "2 hours"))
+	}
+	f.live["integration_callback"] = []any{f.callback(t, false, "2 hours"), f.callback(t, true, "0 seconds")}
+	return f
+}
+
+// outbox meng-insert event yang terkirim age yang lalu, atau belum terkirim
+// jika age kosong.
+func (f *cleanupFixture) outbox(t *testing.T, age string) int64 {
+	t.Helper()
+	var id int64
+	err := f.pool.QueryRow(
+		context.Background(),
+		`INSERT INTO outbox (event_type, aggregate_type, aggregate_id, payload, sent_at)
+		VALUES ('employee.created', 'employee', 'EMP-1', '{}', CURRENT_TIMESTAMP - NULLIF($1, '')::interval)
+		RETURNING id;`,
+		age,
+	).Scan(&id)
+	if err != nil {
+		t.Fatalf("create outbox event: %v", err)
+	}
+	return id
+}
+
+// delivery meng-insert delivery dengan status yang dibuat age yang lalu.
+func (f *cleanupFixture) delivery(t *testing.T, status, age string) int64 {
+	t.Helper()
+	var id int64
+	err := f.pool.QueryRow(
+		context.Background(),
+		`INSERT INTO webhook_delivery (webhook_id, event_type, payload, status, created_at)
+		VALUES ($1, 'employee.created', '{}', $2, CURRENT_TIMESTAMP - $3::interval)
+		RETURNING id;`,
+		f.webhook,
+		status,
+		age,
+	).Scan(&id)
+	if err != nil {
+		t.Fatalf("create webhook delivery: %v", err)
+	}
+	return id
+}
+
+// apiKey meng-insert API key yang kedaluwarsa expiresIn dari sekarang
+// (negatif berarti sudah lewat), atau tanpa expires_at jika kosong.
+func (f *cleanupFixture) apiKey(t *testing.T, expiresIn string) string {
+	t.Helper()
+	f.seq++
+	id := fmt.Sprintf("key-%d", f.seq)
+	_, err := f.pool.Exec(
+		context.Background(),
+		`INSERT INTO api_key (id, manager_id, name, key_prefix, key_hash, scopes, expires_at)
+		VALUES ($1, 'manager', 'ci', 'ak_test', $2, '{employee:read}', CURRENT_TIMESTAMP + NULLIF($3, '')::interval);`,
+		id,
+		fmt.Sprintf("%064d", f.seq),
+		expiresIn,
+	)
+	if err != nil {
+		t.Fatalf("create api key: %v", err)
+	}
+	return id
+}
+
+// callback meng-insert callback integrasi yang ditandatangani age yang lalu,
+// sudah diproses atau belum.
+func (f *cleanupFixture) callback(t *testing.T, processed bool, age string) int64 {
+	t.Helper()
+	f.seq++
+	var id int64
+	err := f.pool.QueryRow(
+		context.Background(),
+		`INSERT INTO integration_callback (integration, signature, signed_at, payload, processed_at)
+		VALUES ('partner-a', $1, CURRENT_TIMESTAMP - $2::interval, '{}', CASE WHEN $3 THEN CURRENT_TIMESTAMP END)
+		RETURNING id;`,
+		fmt.Sprintf("sha256=%d", f.seq),
+		age,
+		processed,
+	).Scan(&id)
+	if err != nil {
+		t.Fatalf("create integration callback: %v", err)
+	}
+	return id
+}
+
+func (f *cleanupFixture) exists(t *testing.T, table string, id any) bool {
+	t.Helper()
+	var exists bool
+	// table hanya berasal dari fixture ini
+	err := f.pool.QueryRow(context.Background(), "SELECT EXISTS (SELECT 1 FROM "+table+" WHERE id = $1);", id).Scan(&exists)
+	if err != nil {
+		t.Fatalf("check %s: %v", table, err)
+	}
+	return exists
+}
+
+// assertCleaned memastikan hanya baris expired milik tables yang terhapus.
+func (f *cleanupFixture) assertCleaned(t *testing.T, tables ...string) {
+	t.Helper()
+	cleaned := map[string]bool{}
+	for _, table := range tables {
+		cleaned[table] = true
+	}
+	for table, ids := range f.expired {
+		for _, id := range ids {
+			if exists := f.exists(t, table, id); exists == cleaned[table] {
+				t.Errorf("expired %s row %v exists = %t, want %t", table, id, exists, !exists)
+			}
+		}
+	}
+	for table, ids := range f.live {
+		for _, id := range ids {
+			if !f.exists(t, table, id) {
+				t.Errorf("live %s row %v was deleted", table, id)
+			}
+		}
+	}
+}
+
+func (f *cleanupFixture) cleaner(cleanupConfig *config.CleanupConfig) *Cleaner {
+	timeouts := config.LoadQueryTimeoutConfig()
+	outbox := outboxRepository.New(f.pool, timeouts)
+	webhooks := webhookRepository.New(f.pool, timeouts)
+	apiKeys := apiKeyRepository.New(f.pool, timeouts)
+	integrations := integrationRepository.New(f.pool, timeouts)
+	return NewCleaner(
+		context.Background(),
+		f.pool,
+		[]CleanupTarget{
+			{Table: "outbox", Interval: cleanupConfig.OutboxInterval, Retention: time.Hour, Delete: outbox.DeleteSent},
+			{Table: "webhook_delivery", Interval: cleanupConfig.WebhookDeliveryInterval, Retention: time.Hour, Delete: webhooks.DeleteFinished},
+			{Table: "api_key", Interval: cleanupConfig.APIKeyInterval, Retention: time.Hour, Delete: apiKeys.DeleteExpired},
+			{Table: "integration_callback", Interval: cleanupConfig.IntegrationCallbackInterval, Retention: time.Hour, Delete: integrations.DeleteProcessed},
+		},
+		cleanupConfig,
+		f.metrics,
+		mocks.Logger{},
+		f.clock,
+	)
+}
+
+func TestCleanerRunOnceDeletesOnlyExpiredRows(t *testing.T) {
+	f := newCleanupFixture(t)
+	// Jeda 0 agar batch berikutnya langsung berjalan pada fake clock
+	cleaner := f.cleaner(&config.CleanupConfig{BatchSize: 2})
+
+	report, err := cleaner.RunOnce(context.Background(), "")
+	if err != nil {
+		t.Fatalf("RunOnce error = %v", err)
+	}
+	for _, table := range cleanupTables {
+		if report.Tables[table] != 3 {
+			t.Errorf("Report.Tables[%s] = %d, want 3", table, report.Tables[table])
+		}
+		if got := testutil.ToFloat64(f.metrics.CleanedUpRows.WithLabelValues(table)); got != 3 {
+			t.Errorf("cleaned up rows for %s = %v, want 3", table, got)
+		}
+	}
+	f.assertCleaned(t, cleanupTables...)
+	if got := testutil.CollectAndCount(f.metrics.CleanupDuration); got != len(cleanupTables) {
+		t.Errorf("cleanup duration series = %d, want %d", got, len(cleanupTables))
+	}
+
+	// Run berikutnya tidak menemukan apa-apa lagi
+	report, err = cleaner.RunOnce(context.Background(), "")
+	if err != nil {
+		t.Fatalf("second RunOnce error = %v", err)
+	}
+	for _, table := range cleanupTables {
+		if report.Tables[table] != 0 {
+			t.Errorf("second RunOnce Report.Tables[%s] = %d, want 0", table, report.Tables[table])
+		}
+	}
+}
+
+func TestCleanerRunOnceWaitsForBatchPause(t *testing.T) {
+	f := newCleanupFixture(t)
+	cleaner := f.cleaner(&config.CleanupConfig{BatchSize: 2, BatchPause: time.Minute})
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := cleaner.RunOnce(context.Background(), "outbox")
+		result <- err
+	}()
+	f.clock.BlockUntil(1)
+	remaining := 0
+	for _, id := range f.expired["outbox"] {
+		if f.exists(t, "outbox", id) {
+			remaining++
+		}
+	}
+	if remaining != 1 {
+		t.Fatalf("%d expired outbox rows left during the pause, want 1", remaining)
+	}
+	f.clock.Advance(time.Minute)
+	if err := <-result; err != nil {
+		t.Fatalf("RunOnce error = %v", err)
+	}
+	f.assertCleaned(t, "outbox")
+}
+
+func TestCleanerRunOnceTable(t *testing.T) {
+	f := newCleanupFixture(t)
+	cleaner := f.cleaner(&config.CleanupConfig{BatchSize: 10})
+
+	report, err := cleaner.RunOnce(context.Background(), "webhook_delivery")
+	if err != nil {
+		t.Fatalf("RunOnce error = %v", err)
+	}
+	if _, ok := report.Tables["outbox"]; ok || report.Tables["webhook_delivery"] != 3 {
+		t.Fatalf("Report = %+v, want only webhook_delivery", report)
+	}
+	f.assertCleaned(t, "webhook_delivery")
+
+	if _, err := cleaner.RunOnce(context.Background(), "employees"); !errors.Is(err, ErrUnknownCleanupTable) {
+		t.Fatalf("RunOnce unknown table error = %v, want %v", err, ErrUnknownCleanupTable)
+	}
+}
+
+// TestCleanerSkipsLockedTable memegang lock cleanup outbox seperti instance
+// lain; tabel lain tetap dibersihkan dan RunOnce melaporkan ErrNotLeader.
+func TestCleanerSkipsLockedTable(t *testing.T) {
+	f := newCleanupFixture(t)
+	conn, err := f.pool.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Release()
+	if _, err := conn.Exec(context.Background(), "SELECT pg_advisory_lock($1);", cleanupLockKey("outbox")); err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+
+	report, err := f.cleaner(&config.CleanupConfig{BatchSize: 10}).RunOnce(context.Background(), "")
+	if !errors.Is(err, ErrNotLeader) {
+		t.Fatalf("RunOnce error = %v, want %v", err, ErrNotLeader)
+	}
+	if report.Tables["outbox"] != 0 || report.Tables["webhook_delivery"] != 3 {
+		t.Fatalf("Report = %+v, want outbox skipped", report)
+	}
+	f.assertCleaned(t, "webhook_delivery", "api_key", "integration_callback")
+
+	// Lock cleanup berbeda dari lock Purger
+	if cleanupLockKey("outbox") == advisoryLockKey || cleanupLockKey("outbox") == cleanupLockKey("webhook_delivery") {
+		t.Fatal("cleanup lock keys collide")
+	}
+}
+
+// TestCleanerDeleteDoesNotBlockWrites mengunci satu event yang expired di
+// transaksi lain; cleanup melewatinya tanpa menunggu.
+func TestCleanerDeleteDoesNotBlockWrites(t *testing.T) {
+	f := newCleanupFixture(t)
+	tx, err := f.pool.Begin(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback(context.Background())
+	locked := f.expired["outbox"][0]
+	if _, err := tx.Exec(context.Background(), "SELECT 1 FROM outbox WHERE id = $1 FOR UPDATE;", locked); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	report, err := f.cleaner(&config.CleanupConfig{BatchSize: 10}).RunOnce(ctx, "outbox")
+	if err != nil {
+		t.Fatalf("RunOnce error = %v", err)
+	}
+	if report.Tables["outbox"] != 2 || !f.exists(t, "outbox", locked) {
+		t.Fatalf("Report = %+v, locked row exists = %t, want the locked row skipped", report, f.exists(t, "outbox", locked))
+	}
+}
+
+func TestCleanerShutdownStopsMidBatch(t *testing.T) {
+	f := newCleanupFixture(t)
+	started := make(chan struct{})
+	cleanupConfig := &config.CleanupConfig{OutboxInterval: time.Hour, BatchSize: 10}
+	cleaner := NewCleaner(
+		context.Background(),
+		f.pool,
+		[]CleanupTarget{{
+			Table:    "outbox",
+			Interval: time.Hour,
+			Delete: func(ctx context.Context, db database.Querier, retention time.Duration, limit int) (int64, error) {
+				close(started)
+				<-ctx.Done()
+				return 0, ctx.Err()
+			},
+		}},
+		cleanupConfig,
+		f.metrics,
+		mocks.Logger{},
+		f.clock,
+	)
+	cleaner.Start()
+
+	f.clock.BlockUntil(1)
+	f.clock.Advance(time.Hour)
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("scheduled cleanup did not start")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := cleaner.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown error = %v", err)
+	}
+	// Lock ikut dilepas, sehingga cleanup lain bisa berjalan
+	if _, err := f.cleaner(&config.CleanupConfig{BatchSize: 10}).RunOnce(context.Background(), "outbox"); err != nil {
+		t.Fatalf("RunOnce after Shutdown error = %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package purge
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/infrastructure/storage"
+	"github.com/levensspel/go-gin-template/mocks"
+)
+
+func TestDeleteExpiredUploadsRemovesObjects(t *testing.T) {
+	ctx := context.Background()
+	memory := storage.NewMemoryStorage("")
+	for _, key := range []string{"file/m/expired.png", "file/m/live.png"} {
+		if _, err := memory.Put(ctx, key, "image/png", strings.NewReader("x"), 1, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var gotRetention time.Duration
+	repo := &mocks.FileRepository{
+		DeleteExpiredUploadsFunc: func(ctx context.Context, db database.Querier, retention time.Duration, limit int) ([]string, error) {
+			gotRetention = retention
+			// Client tidak pernah meng-upload never.png
+			return []string{"file/m/expired.png", "file/m/never.png"}, nil
+		},
+	}
+
+	deleted, err := deleteExpiredUploads(repo, memory, mocks.Logger{})(ctx, nil, time.Hour, 10)
+	if err != nil {
+		t.Fatalf("delete error = %v", err)
+	}
+	if deleted != 2 || gotRetention != time.Hour {
+		t.Fatalf("deleted = %d with retention %v, want 2 with 1h", deleted, gotRetention)
+	}
+	if _, err := memory.Stat(ctx, "file/m/expired.png"); !errors.Is(err, helper.ErrNotFound) {
+		t.Fatalf("expired upload object still exists: %v", err)
+	}
+	if _, err := memory.Stat(ctx, "file/m/live.png"); err != nil {
+		t.Fatalf("live object was deleted: %v", err)
+	}
+}
+
+func TestDeleteExpiredUploadsRepositoryError(t *testing.T) {
+	errDelete := errors.New("delete failed")
+	repo := &mocks.FileRepository{
+		DeleteExpiredUploadsFunc: func(ctx context.Context, db database.Querier, retention time.Duration, limit int) ([]string, error) {
+			return nil, errDelete
+		},
+	}
+	if _, err := deleteExpiredUploads(repo, storage.NewMemoryStorage(""), mocks.Logger{})(context.Background(), nil, time.Hour, 10); !errors.Is(err, errDelete) {
+		t.Fatalf("delete error = %v, want %v", err, errDelete)
+	}
+}
+
+// TestDeleteUnreferencedFilesRemovesObjects memastikan object file, hasil
+// resize, dan thumbnail ikut dihapus, dan object yang sudah tidak ada tidak
+// menggagalkan penghapusan.
+func TestDeleteUnreferencedFilesRemovesObjects(t *testing.T) {
+	ctx := context.Background()
+	memory := storage.NewMemoryStorage("")
+	for _, key := range []string{"file/m/a.png", "file/m/a_64x64.png", "file/m/a_thumb.png", "file/m/live.png"} {
+		if _, err := memory.Put(ctx, key, "image/png", strings.NewReader("x"), 1, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var gotGracePeriod time.Duration
+	repo := &mocks.FileRepository{
+		DeleteUnreferencedFunc: func(ctx context.Context, db database.Querier, gracePeriod time.Duration, limit int) ([]entity.File, error) {
+			gotGracePeriod = gracePeriod
+			return []entity.File{
+				{FileKey: "file/m/a.png", ThumbnailKey: "file/m/a_thumb.png"},
+				// Object-nya sudah dihapus sebelumnya
+				{FileKey: "file/m/gone.png", ThumbnailKey: "file/m/gone_thumb.png"},
+			}, nil
+		},
+	}
+
+	resizeConfig := &config.ResizeConfig{Sizes: []string{"64x64", "128x128"}}
+	deleted, err := deleteUnreferencedFiles(repo, memory, resizeConfig, mocks.Logger{})(ctx, nil, 24*time.Hour, 10)
+	if err != nil {
+		t.Fatalf("delete error = %v", err)
+	}
+	if deleted != 2 || gotGracePeriod != 24*time.Hour {
+		t.Fatalf("deleted = %d with grace period %v, want 2 with 24h", deleted, gotGracePeriod)
+	}
+	for _, key := range []string{"file/m/a.png", "file/m/a_64x64.png", "file/m/a_thumb.png"} {
+		if _, err := memory.Stat(ctx, key); !errors.Is(err, helper.ErrNotFound) {
+			t.Errorf("object %s still exists: %v", key, err)
+		}
+	}
+	if _, err := memory.Stat(ctx, "file/m/live.png"); err != nil {
+		t.Fatalf("live object was deleted: %v", err)
+	}
+}
+
+func TestDeleteUnreferencedFilesRepositoryError(t *testing.T) {
+	errDelete := errors.New("delete failed")
+	repo := &mocks.FileRepository{
+		DeleteUnreferencedFunc: func(ctx context.Context, db database.Querier, gracePeriod time.Duration, limit int) ([]entity.File, error) {
+			return nil, errDelete
+		},
+	}
+	_, err := deleteUnreferencedFiles(repo, storage.NewMemoryStorage(""), &config.ResizeConfig{}, mocks.Logger{})(context.Background(), nil, time.Hour, 10)
+	if !errors.Is(err, errDelete) {
+		t.Fatalf("delete error = %v, want %v", err, errDelete)
+	}
+}
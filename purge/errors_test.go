@@ -0,0 +1,19 @@
+package purge
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+func TestErrNotLeaderIsConflict(t *testing.T) {
+	err := fmt.Errorf("purge departments: %w", ErrNotLeader)
+	if got := helper.GetErrorStatusCode(err); got != http.StatusConflict {
+		t.Fatalf("GetErrorStatusCode = %d, want %d", got, http.StatusConflict)
+	}
+	if got := helper.GetErrorMessage(err); got != ErrNotLeader.Error() {
+		t.Fatalf("GetErrorMessage = %q, want %q", got, ErrNotLeader.Error())
+	}
+}
@@ -0,0 +1,38 @@
+package purge
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+// withLeaderLock menjalankan fn jika pg_try_advisory_lock(key) berhasil,
+// sehingga hanya satu instance yang menjalankan job dengan key yang sama
+// pada satu waktu. Jika lock dipegang instance lain, ErrNotLeader
+// dikembalikan tanpa menunggu. fn menerima koneksi pemegang lock.
+func withLeaderLock(ctx context.Context, pool *pgxpool.Pool, key int64, fn func(conn *pgxpool.Conn) error) error {
+	// Tanpa koneksi sendiri, advisory lock bisa terlepas ke koneksi lain
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	var locked bool
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1);", key).Scan(&locked); err != nil {
+		return helper.QueryError(ctx, err)
+	}
+	if !locked {
+		return ErrNotLeader
+	}
+	defer func() {
+		_, unlockErr := conn.Exec(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1);", key)
+		if unlockErr != nil {
+			// Koneksi ditutup agar lock ikut terlepas dan tidak kembali ke pool
+			_ = conn.Conn().Close(context.WithoutCancel(ctx))
+		}
+	}()
+
+	return fn(conn)
+}
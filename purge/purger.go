@@ -0,0 +1,178 @@
+package purge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/clock"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/lifecycle"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/metrics"
+	departmentRepository "github.com/levensspel/go-gin-template/repository/department"
+	"github.com/samber/do/v2"
+)
+
+// advisoryLockKey adalah key pg_try_advisory_lock untuk memilih satu
+// instance yang menjalankan purge ("purge" dalam ASCII).
+const advisoryLockKey int64 = 0x7075726765
+
+// ErrNotLeader dikembalikan RunOnce jika instance lain sedang menjalankan
+// purge.
+var ErrNotLeader = errors.New("purge is already running on another instance")
+
+func init() {
+	helper.Register(ErrNotLeader, http.StatusConflict, "ErrNotLeader")
+}
+
+type Report struct {
+	Departments int64 `json:"departments"`
+}
+
+// Purger menghapus permanen department yang di-soft-delete lebih lama dari
+// retention. Hanya satu instance yang menjalankan purge pada satu waktu,
+// dipilih lewat advisory lock Postgres yang dipegang selama purge berjalan.
+type Purger struct {
+	pool           *pgxpool.Pool
+	departmentRepo departmentRepository.DepartmentRepositoryInterface
+	config         *config.PurgeConfig
+	metrics        *metrics.Metrics
+	logger         logger.Logger
+	// clock mengatur jadwal dan jeda antar batch. Umur department tetap
+	// dihitung dengan jam database, karena deletedon tanpa zona waktu.
+	clock clock.Clock
+
+	// ctx diturunkan dari lifecycle.Lifetime dan dibatalkan saat Shutdown,
+	// sehingga purge berhenti di tengah batch dan batch yang sedang berjalan
+	// di-rollback.
+	ctx    context.Context
+	cancel context.CancelFunc
+	stop   chan struct{}
+	done   chan struct{}
+	once   sync.Once
+}
+
+func NewPurger(
+	ctx context.Context,
+	pool *pgxpool.Pool,
+	departmentRepo departmentRepository.DepartmentRepositoryInterface,
+	config *config.PurgeConfig,
+	metrics *metrics.Metrics,
+	logger logger.Logger,
+	clock clock.Clock,
+) *Purger {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Purger{
+		pool:           pool,
+		departmentRepo: departmentRepo,
+		config:         config,
+		metrics:        metrics,
+		logger:         logger,
+		clock:          clock,
+		ctx:            ctx,
+		cancel:         cancel,
+		stop:           make(chan struct{}),
+		done:           make(chan struct{}),
+	}
+}
+
+// NewPurgerInject langsung menjalankan purge terjadwal jika PURGE_INTERVAL
+// lebih dari 0. Purger dihentikan saat injector di-shutdown.
+func NewPurgerInject(i do.Injector) (*Purger, error) {
+	appLogger := do.MustInvoke[logger.LogHandler](i)
+	purger := NewPurger(
+		do.MustInvoke[*lifecycle.Lifetime](i).Context(),
+		do.MustInvoke[*database.Pool](i).Pool,
+		do.MustInvoke[departmentRepository.DepartmentRepositoryInterface](i),
+		config.LoadPurgeConfig(),
+		do.MustInvoke[*metrics.Metrics](i),
+		&appLogger,
+		do.MustInvoke[clock.Clock](i),
+	)
+	purger.Start()
+	return purger, nil
+}
+
+func (p *Purger) Start() {
+	if p.config.Interval <= 0 {
+		close(p.done)
+		return
+	}
+	go p.run()
+}
+
+func (p *Purger) run() {
+	defer close(p.done)
+
+	ticker := p.clock.NewTicker(p.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C():
+		}
+
+		report, err := p.RunOnce(p.ctx)
+		switch {
+		case errors.Is(err, ErrNotLeader), p.ctx.Err() != nil:
+		case err != nil:
+			p.logger.Warn(fmt.Sprintf("Purge failed: %v", err), helper.PurgeJob)
+		case report.Departments > 0:
+			p.logger.Info(fmt.Sprintf("Purged %d departments", report.Departments), helper.PurgeJob)
+		}
+	}
+}
+
+// RunOnce menjalankan satu purge sampai tidak ada baris yang tersisa atau
+// ctx dibatalkan, dan mengembalikan ErrNotLeader jika instance lain sedang
+// purge. Report berisi jumlah baris yang sudah terhapus walau err tidak nil.
+func (p *Purger) RunOnce(ctx context.Context) (report Report, err error) {
+	err = withLeaderLock(ctx, p.pool, advisoryLockKey, func(conn *pgxpool.Conn) error {
+		batchSize := max(p.config.BatchSize, 1)
+		for {
+			purged, err := p.departmentRepo.PurgeDeleted(ctx, conn, p.config.Retention, batchSize)
+			report.Departments += purged
+			p.metrics.CountPurged("department", purged)
+			if err != nil {
+				return err
+			}
+			if purged < int64(batchSize) {
+				return nil
+			}
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-p.stop:
+				return context.Canceled
+			case <-p.clock.After(p.config.BatchPause):
+			}
+		}
+	})
+	return report, err
+}
+
+// Shutdown menghentikan purge terjadwal dan membatalkan batch yang sedang
+// berjalan, lalu menunggu goroutine-nya selesai.
+func (p *Purger) Shutdown(ctx context.Context) error {
+	p.once.Do(func() {
+		close(p.stop)
+		p.cancel()
+	})
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,237 @@
+//go:build integration
+
+package purge
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/clock/clocktest"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/mocks"
+	departmentRepository "github.com/levensspel/go-gin-template/repository/department"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// purgeFixture berisi department milik satu manager: expired sudah lewat
+// retention, recent baru dihapus, referenced masih dipakai employee, dan
+// active belum dihapus.
+type purgeFixture struct {
+	pool       *pgxpool.Pool
+	metrics    *metrics.Metrics
+	clock      *clocktest.Fake
+	expired    []string
+	recent     string
+	referenced string
+	active     string
+}
+
+func newPurgeFixture(t *testing.T, expired int) purgeFixture {
+	t.Helper()
+	pool := dbtest.New(t)
+	f := purgeFixture{
+		pool:    pool,
+		metrics: metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{}),
+		clock:   clocktest.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+	}
+	manager := dbtest.CreateManager(t, pool, "purge@example.com")
+	for range expired {
+		f.expired = append(f.expired, f.deleted(t, manager, "Expired", "1 hour"))
+	}
+	f.recent = f.deleted(t, manager, "Recent", "0 seconds")
+	f.referenced = dbtest.CreateDepartment(t, pool, manager, "Referenced")
+	dbtest.CreateEmployee(t, pool, dbtest.Employee{IdentityNumber: "EMP-1", Name: "Employee", Gender: "male", DepartmentID: f.referenced})
+	f.expire(t, f.referenced, "1 hour")
+	f.active = dbtest.CreateDepartment(t, pool, manager, "Active")
+	return f
+}
+
+// deleted membuat department yang di-soft-delete age yang lalu.
+func (f purgeFixture) deleted(t *testing.T, manager, name, age string) string {
+	t.Helper()
+	departmentId := dbtest.CreateDepartment(t, f.pool, manager, name)
+	f.expire(t, departmentId, age)
+	return departmentId
+}
+
+func (f purgeFixture) expire(t *testing.T, departmentId, age string) {
+	t.Helper()
+	dbtest.DeleteDepartment(t, f.pool, departmentId)
+	_, err := f.pool.Exec(
+		context.Background(),
+		"UPDATE department SET deletedon = CURRENT_TIMESTAMP - $2::interval WHERE departmentid = $1;",
+		departmentId,
+		age,
+	)
+	if err != nil {
+		t.Fatalf("set deletedon: %v", err)
+	}
+}
+
+func (f purgeFixture) exists(t *testing.T, departmentId string) bool {
+	t.Helper()
+	var exists bool
+	err := f.pool.QueryRow(context.Background(), "SELECT EXISTS (SELECT 1 FROM department WHERE departmentid = $1);", departmentId).Scan(&exists)
+	if err != nil {
+		t.Fatalf("check department: %v", err)
+	}
+	return exists
+}
+
+func (f purgeFixture) purger(repo departmentRepository.DepartmentRepositoryInterface, purgeConfig *config.PurgeConfig) *Purger {
+	if repo == nil {
+		departments := departmentRepository.New(f.pool, f.pool, config.LoadQueryTimeoutConfig())
+		repo = &departments
+	}
+	return NewPurger(context.Background(), f.pool, repo, purgeConfig, f.metrics, mocks.Logger{}, f.clock)
+}
+
+func TestRunOnceDeletesExpiredInBatches(t *testing.T) {
+	f := newPurgeFixture(t, 5)
+	purgeConfig := &config.PurgeConfig{Retention: time.Minute, BatchSize: 2, BatchPause: time.Second}
+	p := f.purger(nil, purgeConfig)
+
+	result := make(chan Report, 1)
+	go func() {
+		report, err := p.RunOnce(context.Background())
+		if err != nil {
+			t.Errorf("RunOnce error = %v", err)
+		}
+		result <- report
+	}()
+	// 5 baris dengan batch 2: dua jeda BatchPause sebelum batch terakhir
+	for range 2 {
+		f.clock.BlockUntil(1)
+		f.clock.Advance(purgeConfig.BatchPause)
+	}
+	report := <-result
+
+	if report.Departments != 5 {
+		t.Fatalf("Report.Departments = %d, want 5", report.Departments)
+	}
+	for _, departmentId := range f.expired {
+		if f.exists(t, departmentId) {
+			t.Fatalf("expired department %s was not purged", departmentId)
+		}
+	}
+	for name, departmentId := range map[string]string{"recent": f.recent, "referenced": f.referenced, "active": f.active} {
+		if !f.exists(t, departmentId) {
+			t.Fatalf("%s department was purged", name)
+		}
+	}
+	if got := testutil.ToFloat64(f.metrics.PurgedRows.WithLabelValues("department")); got != 5 {
+		t.Fatalf("purged_rows_total = %v, want 5", got)
+	}
+}
+
+func TestRunOnceWaitsForBatchPause(t *testing.T) {
+	f := newPurgeFixture(t, 3)
+	p := f.purger(nil, &config.PurgeConfig{Retention: time.Minute, BatchSize: 2, BatchPause: time.Minute})
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := p.RunOnce(context.Background())
+		result <- err
+	}()
+	f.clock.BlockUntil(1)
+	remaining := 0
+	for _, departmentId := range f.expired {
+		if f.exists(t, departmentId) {
+			remaining++
+		}
+	}
+	if remaining != 1 {
+		t.Fatalf("%d expired departments left during the pause, want 1", remaining)
+	}
+	select {
+	case err := <-result:
+		t.Fatalf("RunOnce returned %v before BatchPause passed", err)
+	default:
+	}
+	f.clock.Advance(time.Minute)
+	if err := <-result; err != nil {
+		t.Fatalf("RunOnce error = %v", err)
+	}
+}
+
+func TestRunOnceNotLeader(t *testing.T) {
+	f := newPurgeFixture(t, 1)
+	conn, err := f.pool.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Release()
+	if _, err := conn.Exec(context.Background(), "SELECT pg_advisory_lock($1);", advisoryLockKey); err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+
+	_, err = f.purger(nil, &config.PurgeConfig{Retention: time.Minute, BatchSize: 10}).RunOnce(context.Background())
+	if !errors.Is(err, ErrNotLeader) {
+		t.Fatalf("RunOnce error = %v, want %v", err, ErrNotLeader)
+	}
+	if !f.exists(t, f.expired[0]) {
+		t.Fatal("department was purged while another instance held the lock")
+	}
+
+	// Setelah lock dilepas, instance ini bisa menjadi leader
+	if _, err := conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1);", advisoryLockKey); err != nil {
+		t.Fatalf("unlock: %v", err)
+	}
+	if _, err := f.purger(nil, &config.PurgeConfig{Retention: time.Minute, BatchSize: 10}).RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce after unlock error = %v", err)
+	}
+}
+
+// TestShutdownStopsMidBatch menjalankan purge terjadwal dengan batch yang
+// baru selesai saat context-nya dibatalkan; Shutdown harus membatalkannya
+// dan menunggu goroutine purge selesai.
+func TestShutdownStopsMidBatch(t *testing.T) {
+	f := newPurgeFixture(t, 1)
+	started := make(chan struct{})
+	repo := &mocks.DepartmentRepository{
+		PurgeDeletedFunc: func(ctx context.Context, pool database.Querier, retention time.Duration, limit int) (int64, error) {
+			close(started)
+			<-ctx.Done()
+			return 0, ctx.Err()
+		},
+	}
+	p := f.purger(repo, &config.PurgeConfig{Interval: time.Hour, Retention: time.Minute, BatchSize: 10})
+	p.Start()
+
+	f.clock.BlockUntil(1)
+	f.clock.Advance(time.Hour)
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("scheduled purge did not start")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := p.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown error = %v", err)
+	}
+	// Lock ikut dilepas, sehingga purge lain bisa berjalan
+	if _, err := f.purger(nil, &config.PurgeConfig{Retention: time.Minute, BatchSize: 10}).RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce after Shutdown error = %v", err)
+	}
+}
+
+func TestStartWithoutIntervalDoesNotSchedule(t *testing.T) {
+	f := newPurgeFixture(t, 1)
+	p := f.purger(nil, &config.PurgeConfig{Retention: time.Minute, BatchSize: 10})
+	p.Start()
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown error = %v", err)
+	}
+	if !f.exists(t, f.expired[0]) {
+		t.Fatal("department was purged without a schedule")
+	}
+}
@@ -0,0 +1,330 @@
+// Package realtime mengirim event employee ke client WebSocket
+// (GET /v1/ws) dari eventbus.Bus, dengan filter department per koneksi.
+//
+// Protokol, semua pesan berupa JSON:
+//
+//	client: {"type": "subscribe", "departments": ["<id>", ...]}
+//	server: {"type": "subscribed", "departments": [...]}
+//	server: {"type": "event", "event": { ...events.Envelope... }}
+//	server: {"type": "dropped", "count": 3}
+//	server: {"type": "error", "message": "..."}
+//
+// Client belum menerima event sebelum subscribe; departments kosong berarti
+// semua department. Subscribe berikutnya mengganti filter sebelumnya. Jika
+// antrian koneksi penuh, event dibuang dan pesan dropped dikirim sebelum
+// event berikutnya, tanda client perlu memuat ulang GET /v1/employee.
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/levensspel/go-gin-template/clock"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/eventbus"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/validation"
+	"github.com/samber/do/v2"
+)
+
+// Tipe pesan, lihat dokumentasi package
+const (
+	MessageSubscribe  = "subscribe"
+	MessageSubscribed = "subscribed"
+	MessageEvent      = "event"
+	MessageDropped    = "dropped"
+	MessageError      = "error"
+)
+
+// maxDepartments membatasi jumlah department dalam satu subscribe.
+const maxDepartments = 100
+
+type ClientMessage struct {
+	Type        string   `json:"type"`
+	Departments []string `json:"departments"`
+}
+
+type ServerMessage struct {
+	Type        string          `json:"type"`
+	Departments []string        `json:"departments,omitempty"`
+	Event       json.RawMessage `json:"event,omitempty"`
+	Count       int64           `json:"count,omitempty"`
+	Message     string          `json:"message,omitempty"`
+}
+
+// Hub menjalankan koneksi WebSocket dan menunggu semuanya selesai saat
+// shutdown. Koneksi ditutup saat Bus di-shutdown, karena koneksi yang sudah
+// di-hijack tidak ditunggu http.Server.Shutdown.
+type Hub struct {
+	bus    *eventbus.Bus
+	clock  clock.Clock
+	config *config.WebSocketConfig
+	logger logger.Logger
+	conns  sync.WaitGroup
+}
+
+func NewHub(bus *eventbus.Bus, clock clock.Clock, config *config.WebSocketConfig, logger logger.Logger) *Hub {
+	return &Hub{bus: bus, clock: clock, config: config, logger: logger}
+}
+
+func NewHubInject(i do.Injector) (*Hub, error) {
+	appLogger := do.MustInvoke[logger.LogHandler](i)
+	return NewHub(
+		do.MustInvoke[*eventbus.Bus](i),
+		do.MustInvoke[clock.Clock](i),
+		config.LoadWebSocketConfig(),
+		&appLogger,
+	), nil
+}
+
+// Serve melayani satu koneksi sampai ditutup client, gagal ditulis, atau
+// Bus di-shutdown. ws ditutup sebelum Serve kembali.
+func (h *Hub) Serve(ws *websocket.Conn, managerID string) {
+	h.conns.Add(1)
+	defer h.conns.Done()
+
+	c := &conn{
+		hub:          h,
+		ws:           ws,
+		subscription: h.bus.Subscribe(managerID, 0),
+		queue:        make(chan ServerMessage, max(h.config.QueueSize, 1)),
+		done:         make(chan struct{}),
+		readDone:     make(chan struct{}),
+		closeCode:    websocket.CloseNormalClosure,
+	}
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() { defer wg.Done(); c.read() }()
+	go func() { defer wg.Done(); c.pump() }()
+	go func() { defer wg.Done(); c.write() }()
+	wg.Wait()
+	_ = ws.Close()
+}
+
+// Shutdown menunggu koneksi yang sedang berjalan selesai mengirim close
+// frame.
+func (h *Hub) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		h.conns.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// conn adalah satu koneksi WebSocket. Hanya write yang menulis ke ws,
+// kecuali close frame yang boleh dikirim bersamaan (WriteControl).
+type conn struct {
+	hub          *Hub
+	ws           *websocket.Conn
+	subscription *eventbus.Subscription
+	queue        chan ServerMessage
+	// filter berisi department yang dilanggan; nil sebelum subscribe, map
+	// kosong untuk semua department
+	filter  atomic.Pointer[map[string]struct{}]
+	dropped atomic.Int64
+
+	done chan struct{}
+	// readDone ditutup read setelah close frame client diterima atau
+	// koneksi putus
+	readDone  chan struct{}
+	stopOnce  sync.Once
+	closeCode int
+	closeText string
+}
+
+// stop menghentikan ketiga goroutine koneksi. Alasan pertama yang menang.
+func (c *conn) stop(code int, text string) {
+	c.stopOnce.Do(func() {
+		c.closeCode, c.closeText = code, text
+		c.subscription.Close()
+		close(c.done)
+	})
+}
+
+// read membaca pesan client dan memperpanjang read deadline setiap pong.
+// Setelah koneksi berhenti, read tetap berjalan sampai close frame balasan
+// client diterima.
+func (c *conn) read() {
+	defer close(c.readDone)
+	pongWait := 2 * c.hub.config.PingInterval
+	c.ws.SetReadLimit(c.hub.config.MaxMessageBytes)
+	_ = c.ws.SetReadDeadline(time.Now().Add(pongWait))
+	c.ws.SetPongHandler(func(string) error {
+		return c.ws.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := c.ws.ReadMessage()
+		if err != nil {
+			c.stop(closeCode(err), "")
+			return
+		}
+		var message ClientMessage
+		response := ServerMessage{Type: MessageError, Message: "message must be a JSON object"}
+		if err := json.Unmarshal(data, &message); err == nil {
+			response = c.handle(message)
+		}
+		if !c.reply(response) {
+			return
+		}
+	}
+}
+
+func (c *conn) handle(message ClientMessage) ServerMessage {
+	switch message.Type {
+	case MessageSubscribe, "":
+	default:
+		return ServerMessage{Type: MessageError, Message: fmt.Sprintf("unknown message type %q", message.Type)}
+	}
+	if len(message.Departments) > maxDepartments {
+		return ServerMessage{Type: MessageError, Message: fmt.Sprintf("departments must have at most %d items", maxDepartments)}
+	}
+	filter := make(map[string]struct{}, len(message.Departments))
+	departments := make([]string, 0, len(message.Departments))
+	for _, id := range message.Departments {
+		if err := validation.ValidateDepartmentID(id); err != nil {
+			return ServerMessage{Type: MessageError, Message: fmt.Sprintf("invalid department id %q", id)}
+		}
+		if _, ok := filter[id]; ok {
+			continue
+		}
+		filter[id] = struct{}{}
+		departments = append(departments, id)
+	}
+	c.filter.Store(&filter)
+	return ServerMessage{Type: MessageSubscribed, Departments: departments}
+}
+
+// reply mengantrikan balasan pesan client. Balasan tidak boleh dibuang,
+// jadi client yang antriannya masih penuh diputus.
+func (c *conn) reply(message ServerMessage) bool {
+	select {
+	case c.queue <- message:
+		return true
+	case <-c.done:
+		return false
+	default:
+		c.stop(websocket.CloseTryAgainLater, "client is too slow")
+		return false
+	}
+}
+
+// pump meneruskan event Bus yang lolos filter ke antrian. pump tidak
+// pernah menunggu antrian, sehingga Bus tidak memutus koneksi ini.
+func (c *conn) pump() {
+	for {
+		select {
+		case <-c.done:
+			return
+		case event, ok := <-c.subscription.Events():
+			if !ok {
+				// Bus di-shutdown
+				c.stop(websocket.CloseGoingAway, "server is shutting down")
+				return
+			}
+			if !c.accepts(event) {
+				continue
+			}
+			select {
+			case c.queue <- ServerMessage{Type: MessageEvent, Event: event.Payload}:
+			default:
+				c.dropped.Add(1)
+			}
+		}
+	}
+}
+
+func (c *conn) accepts(event eventbus.Event) bool {
+	if !strings.HasPrefix(event.Type, "employee.") {
+		return false
+	}
+	filter := c.filter.Load()
+	if filter == nil {
+		return false
+	}
+	if len(*filter) == 0 {
+		return true
+	}
+	_, ok := (*filter)[event.DepartmentID]
+	return ok
+}
+
+// write menulis antrian dan ping ke client, lalu close frame saat koneksi
+// berhenti.
+func (c *conn) write() {
+	ping := c.hub.clock.NewTicker(c.hub.config.PingInterval)
+	defer ping.Stop()
+	defer func() {
+		// Close handshake: kirim close frame lalu tunggu balasan client
+		// sebelum koneksi TCP ditutup
+		message := websocket.FormatCloseMessage(c.closeCode, c.closeText)
+		if err := c.ws.WriteControl(websocket.CloseMessage, message, time.Now().Add(c.hub.config.WriteTimeout)); err == nil {
+			select {
+			case <-c.readDone:
+			case <-c.hub.clock.After(c.hub.config.WriteTimeout):
+			}
+		}
+		_ = c.ws.Close()
+	}()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case message := <-c.queue:
+			if message.Type == MessageEvent {
+				if dropped := c.dropped.Swap(0); dropped > 0 {
+					if !c.send(ServerMessage{Type: MessageDropped, Count: dropped}) {
+						return
+					}
+				}
+			}
+			if !c.send(message) {
+				return
+			}
+		case <-ping.C():
+			if err := c.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(c.hub.config.WriteTimeout)); err != nil {
+				c.stop(websocket.CloseGoingAway, "")
+				return
+			}
+		}
+	}
+}
+
+func (c *conn) send(message ServerMessage) bool {
+	_ = c.ws.SetWriteDeadline(time.Now().Add(c.hub.config.WriteTimeout))
+	if err := c.ws.WriteJSON(message); err != nil {
+		c.hub.logger.Debug(err.Error(), helper.WebSocketHub)
+		c.stop(websocket.CloseGoingAway, "")
+		return false
+	}
+	return true
+}
+
+// closeCode mengembalikan CloseNormalClosure jika client menutup koneksi,
+// CloseMessageTooBig untuk pesan yang melebihi MaxMessageBytes, dan
+// CloseGoingAway untuk koneksi yang putus atau tidak membalas ping.
+func closeCode(err error) int {
+	var closeErr *websocket.CloseError
+	if errors.As(err, &closeErr) && closeErr.Code != websocket.CloseNoStatusReceived {
+		return websocket.CloseNormalClosure
+	}
+	if errors.Is(err, websocket.ErrReadLimit) {
+		return websocket.CloseMessageTooBig
+	}
+	return websocket.CloseGoingAway
+}
@@ -0,0 +1,547 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/levensspel/go-gin-template/clock/clocktest"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/eventbus"
+	"github.com/levensspel/go-gin-template/events"
+	"github.com/levensspel/go-gin-template/mocks"
+)
+
+const (
+	testManagerID  = "0b7c2d2e-4f4a-4b8e-9c59-2f1d8f6a3e10"
+	otherManagerID = "5d1f0c7a-2b3e-4c4d-8e9f-0a1b2c3d4e5f"
+	departmentA    = "6a1f0d0e-9c1b-4c3e-8f0a-5b2d7e4c9a11"
+	departmentB    = "7b2a1e1f-0d2c-4d4f-9a1b-6c3e8f5d0b22"
+)
+
+// hubFixture menjalankan Hub di belakang server HTTP sungguhan. Manager
+// koneksi diambil dari query managerId, pengganti middleware Authorization.
+type hubFixture struct {
+	bus   *eventbus.Bus
+	clock *clocktest.Fake
+	hub   *Hub
+	srv   *httptest.Server
+}
+
+func newHubFixture(t *testing.T, streamConfig *config.StreamConfig, wsConfig *config.WebSocketConfig) *hubFixture {
+	t.Helper()
+	f := &hubFixture{
+		bus:   eventbus.New(streamConfig),
+		clock: clocktest.NewFake(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)),
+	}
+	f.hub = NewHub(f.bus, f.clock, wsConfig, mocks.Logger{})
+	upgrader := websocket.Upgrader{}
+	f.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		f.hub.Serve(ws, r.URL.Query().Get("managerId"))
+	}))
+	t.Cleanup(func() {
+		f.bus.Shutdown()
+		f.srv.Close()
+	})
+	return f
+}
+
+func defaultHubFixture(t *testing.T) *hubFixture {
+	return newHubFixture(t,
+		&config.StreamConfig{BufferSize: 100, SubscriberBuffer: 100},
+		&config.WebSocketConfig{PingInterval: time.Minute, WriteTimeout: 5 * time.Second, QueueSize: 16, MaxMessageBytes: 4096},
+	)
+}
+
+func (f *hubFixture) dial(t *testing.T, managerID string) *websocket.Conn {
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(f.srv.URL, "http") + "/?managerId=" + managerID
+	ws, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ws.Close() })
+	return ws
+}
+
+// publish mengirim event employee department departmentID seperti
+// dispatcher outbox. Aman dipanggil dari goroutine lain.
+func (f *hubFixture) publish(id int64, eventType, managerID, departmentID string, name string) error {
+	data, err := json.Marshal(events.Employee{ManagerID: managerID, Employee: dto.EmployeeResponse{
+		EmployeePayload: dto.EmployeePayload{Name: name, DepartmentID: departmentID},
+	}})
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(events.Envelope{ID: id, Type: eventType, Data: data})
+	if err != nil {
+		return err
+	}
+	return f.bus.Publish(context.Background(), eventType, payload)
+}
+
+func send(t *testing.T, ws *websocket.Conn, message any) {
+	t.Helper()
+	if err := ws.WriteJSON(message); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func receive(t *testing.T, ws *websocket.Conn) ServerMessage {
+	t.Helper()
+	_ = ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var message ServerMessage
+	if err := ws.ReadJSON(&message); err != nil {
+		t.Fatalf("read message: %v", err)
+	}
+	return message
+}
+
+// eventID mengembalikan id envelope dari pesan event.
+func eventID(t *testing.T, message ServerMessage) int64 {
+	t.Helper()
+	var envelope events.Envelope
+	if message.Type != MessageEvent || json.Unmarshal(message.Event, &envelope) != nil {
+		t.Fatalf("message = %+v, want an event", message)
+	}
+	return envelope.ID
+}
+
+// subscribe mengirim subscribe dan menunggu balasan subscribed, sehingga
+// filter sudah berlaku untuk event berikutnya.
+func subscribe(t *testing.T, ws *websocket.Conn, departments ...string) {
+	t.Helper()
+	send(t, ws, ClientMessage{Type: MessageSubscribe, Departments: departments})
+	if got := receive(t, ws); got.Type != MessageSubscribed {
+		t.Fatalf("subscribe reply = %+v", got)
+	}
+}
+
+// wantClose membaca sampai close frame server dan mengembalikan kodenya.
+func wantClose(t *testing.T, ws *websocket.Conn) *websocket.CloseError {
+	t.Helper()
+	_ = ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		_, _, err := ws.ReadMessage()
+		if err == nil {
+			continue
+		}
+		var closeErr *websocket.CloseError
+		if !errors.As(err, &closeErr) {
+			t.Fatalf("read error = %v, want a close frame", err)
+		}
+		return closeErr
+	}
+}
+
+func TestSubscribeFiltersDepartments(t *testing.T) {
+	f := defaultHubFixture(t)
+	ws := f.dial(t, testManagerID)
+
+	// Sebelum subscribe tidak ada event yang dikirim
+	if err := f.publish(1, entity.EventEmployeeCreated, testManagerID, departmentA, ""); err != nil {
+		t.Fatal(err)
+	}
+	send(t, ws, ClientMessage{Type: MessageSubscribe, Departments: []string{departmentA, departmentA}})
+	if got := receive(t, ws); got.Type != MessageSubscribed || !slices.Equal(got.Departments, []string{departmentA}) {
+		t.Fatalf("subscribed = %+v, want duplicates removed", got)
+	}
+
+	for _, event := range []struct {
+		id         int64
+		eventType  string
+		manager    string
+		department string
+	}{
+		{2, entity.EventEmployeeCreated, testManagerID, departmentB},
+		{3, entity.EventEmployeeCreated, otherManagerID, departmentA},
+		{4, entity.EventDepartmentUpdated, testManagerID, departmentA},
+		{5, entity.EventEmployeeUpdated, testManagerID, departmentA},
+	} {
+		if err := f.publish(event.id, event.eventType, event.manager, event.department, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if id := eventID(t, receive(t, ws)); id != 5 {
+		t.Fatalf("first event = %d, want 5", id)
+	}
+
+	// Subscribe tanpa department mengganti filter menjadi semua department
+	subscribe(t, ws)
+	if err := f.publish(6, entity.EventEmployeeDeleted, testManagerID, departmentB, ""); err != nil {
+		t.Fatal(err)
+	}
+	if id := eventID(t, receive(t, ws)); id != 6 {
+		t.Fatalf("event after resubscribe = %d, want 6", id)
+	}
+}
+
+func TestInvalidClientMessages(t *testing.T) {
+	f := defaultHubFixture(t)
+	ws := f.dial(t, testManagerID)
+
+	tooMany := make([]string, maxDepartments+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("00000000-0000-4000-8000-%012d", i)
+	}
+	for _, tt := range []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{name: "not json", message: `subscribe`, want: "message must be a JSON object"},
+		{name: "unknown type", message: `{"type":"ack"}`, want: `unknown message type "ack"`},
+		{name: "invalid department", message: `{"type":"subscribe","departments":["engineering"]}`, want: `invalid department id "engineering"`},
+		{name: "too many departments", message: fmt.Sprintf(`{"type":"subscribe","departments":["%s"]}`, strings.Join(tooMany, `","`)), want: "departments must have at most 100 items"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ws.WriteMessage(websocket.TextMessage, []byte(tt.message)); err != nil {
+				t.Fatal(err)
+			}
+			if got := receive(t, ws); got.Type != MessageError || got.Message != tt.want {
+				t.Fatalf("reply = %+v, want error %q", got, tt.want)
+			}
+		})
+	}
+
+	// Pesan yang salah tidak mengubah filter maupun memutus koneksi
+	subscribe(t, ws, departmentA)
+}
+
+func TestMessageTooBig(t *testing.T) {
+	f := defaultHubFixture(t)
+	ws := f.dial(t, testManagerID)
+
+	send(t, ws, ClientMessage{Type: MessageSubscribe, Departments: []string{strings.Repeat("a", 5000)}})
+	if closeErr := wantClose(t, ws); closeErr.Code != websocket.CloseMessageTooBig {
+		t.Fatalf("close code = %d, want %d", closeErr.Code, websocket.CloseMessageTooBig)
+	}
+}
+
+// TestDroppedEvents membuat client berhenti membaca sampai antrian koneksi
+// penuh. Setiap event harus sampai ke client atau dihitung di pesan
+// dropped yang dikirim sebelum event berikutnya.
+func TestDroppedEvents(t *testing.T) {
+	const count = 2000
+	f := newHubFixture(t,
+		&config.StreamConfig{BufferSize: 10, SubscriberBuffer: 2 * count},
+		&config.WebSocketConfig{PingInterval: time.Minute, WriteTimeout: 10 * time.Second, QueueSize: 4, MaxMessageBytes: 4096},
+	)
+	ws := f.dial(t, testManagerID)
+	subscribe(t, ws)
+
+	// Payload besar memenuhi buffer socket, sehingga write tertahan dan
+	// antrian koneksi penuh
+	name := strings.Repeat("a", 4096)
+	for id := range int64(count) {
+		if err := f.publish(id+1, entity.EventEmployeeCreated, testManagerID, departmentA, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Pesan dropped hanya dikirim sebelum event, jadi setiap pesan yang
+	// dibaca diikuti event penanda baru sampai salah satunya diterima
+	var delivered, dropped int64
+	marker, lastID := int64(count), int64(0)
+	for lastID <= count {
+		message := receive(t, ws)
+		switch message.Type {
+		case MessageDropped:
+			dropped += message.Count
+		case MessageEvent:
+			id := eventID(t, message)
+			if id <= lastID {
+				t.Fatalf("event %d after %d", id, lastID)
+			}
+			lastID = id
+			if id <= count {
+				delivered++
+			}
+		default:
+			t.Fatalf("message = %+v", message)
+		}
+		marker++
+		if err := f.publish(marker, entity.EventEmployeeCreated, testManagerID, departmentA, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if dropped == 0 {
+		t.Fatal("no event was dropped, the client never fell behind")
+	}
+	// dropped bisa ikut menghitung penanda yang dibuang
+	if delivered+dropped < count || delivered+dropped > marker {
+		t.Fatalf("delivered %d + dropped %d, want every one of %d events accounted for", delivered, dropped, count)
+	}
+}
+
+func TestReplyToSlowClientDisconnects(t *testing.T) {
+	f := defaultHubFixture(t)
+	subscription := f.bus.Subscribe(testManagerID, 0)
+	c := &conn{
+		hub:          f.hub,
+		subscription: subscription,
+		queue:        make(chan ServerMessage, 1),
+		done:         make(chan struct{}),
+	}
+	if !c.reply(ServerMessage{Type: MessageSubscribed}) {
+		t.Fatal("reply with room in the queue failed")
+	}
+	// Balasan tidak dibuang seperti event; koneksi diputus
+	if c.reply(ServerMessage{Type: MessageSubscribed}) {
+		t.Fatal("reply to a full queue succeeded")
+	}
+	select {
+	case <-c.done:
+	default:
+		t.Fatal("connection was not stopped")
+	}
+	if c.closeCode != websocket.CloseTryAgainLater {
+		t.Fatalf("close code = %d, want %d", c.closeCode, websocket.CloseTryAgainLater)
+	}
+	if _, ok := <-subscription.Events(); ok {
+		t.Fatal("subscription is still open after the connection stopped")
+	}
+}
+
+func TestPingKeepsConnectionOpen(t *testing.T) {
+	const pingInterval = 100 * time.Millisecond
+	f := newHubFixture(t,
+		&config.StreamConfig{BufferSize: 10, SubscriberBuffer: 10},
+		&config.WebSocketConfig{PingInterval: pingInterval, WriteTimeout: time.Second, QueueSize: 4, MaxMessageBytes: 4096},
+	)
+	ws := f.dial(t, testManagerID)
+	pings := make(chan struct{}, 100)
+	ws.SetPingHandler(func(data string) error {
+		pings <- struct{}{}
+		return ws.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+	// Ping handler hanya berjalan selama client membaca
+	read := make(chan error, 1)
+	go func() {
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				read <- err
+				return
+			}
+		}
+	}()
+
+	// Pong memperpanjang deadline 2*pingInterval jauh melewati deadline
+	// pertama
+	f.clock.BlockUntil(1)
+	for range 10 {
+		f.clock.Advance(pingInterval)
+		select {
+		case <-pings:
+		case err := <-read:
+			t.Fatalf("connection closed while answering pings: %v", err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("no ping received")
+		}
+		time.Sleep(pingInterval / 2)
+	}
+}
+
+func TestUnansweredPingsClose(t *testing.T) {
+	f := newHubFixture(t,
+		&config.StreamConfig{BufferSize: 10, SubscriberBuffer: 10},
+		&config.WebSocketConfig{PingInterval: 50 * time.Millisecond, WriteTimeout: time.Second, QueueSize: 4, MaxMessageBytes: 4096},
+	)
+	ws := f.dial(t, testManagerID)
+
+	// Tanpa pong, read deadline 2*PingInterval habis
+	if closeErr := wantClose(t, ws); closeErr.Code != websocket.CloseGoingAway {
+		t.Fatalf("close code = %d, want %d", closeErr.Code, websocket.CloseGoingAway)
+	}
+}
+
+func TestShutdownClosesConnections(t *testing.T) {
+	f := defaultHubFixture(t)
+	clients := make([]*websocket.Conn, 3)
+	for i := range clients {
+		clients[i] = f.dial(t, testManagerID)
+		subscribe(t, clients[i])
+	}
+
+	f.bus.Shutdown()
+	var wg sync.WaitGroup
+	for _, ws := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Membalas close frame seperti browser
+			_, _, err := ws.ReadMessage()
+			var closeErr *websocket.CloseError
+			if !errors.As(err, &closeErr) || closeErr.Code != websocket.CloseGoingAway || closeErr.Text != "server is shutting down" {
+				t.Errorf("read error = %v, want close 1001", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := f.hub.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown error = %v, want every connection finished", err)
+	}
+}
+
+func TestShutdownTimesOut(t *testing.T) {
+	f := defaultHubFixture(t)
+	f.dial(t, testManagerID)
+	// Pastikan Serve sudah berjalan sebelum Shutdown dipanggil
+	f.clock.BlockUntil(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := f.hub.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown with an open connection error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestClientCloseFinishesServe(t *testing.T) {
+	f := defaultHubFixture(t)
+	ws := f.dial(t, testManagerID)
+	f.clock.BlockUntil(1)
+
+	message := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "bye")
+	if err := ws.WriteControl(websocket.CloseMessage, message, time.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if closeErr := wantClose(t, ws); closeErr.Code != websocket.CloseNormalClosure {
+		t.Fatalf("close code = %d, want %d", closeErr.Code, websocket.CloseNormalClosure)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := f.hub.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown error = %v, want Serve finished", err)
+	}
+}
+
+// TestManyConnections menjalankan banyak koneksi dengan filter berbeda
+// sementara event di-publish dan filter diganti bersamaan. Jalankan
+// dengan -race.
+func TestManyConnections(t *testing.T) {
+	const (
+		connections = 50
+		count       = 200
+	)
+	f := newHubFixture(t,
+		&config.StreamConfig{BufferSize: 10, SubscriberBuffer: count},
+		&config.WebSocketConfig{PingInterval: time.Minute, WriteTimeout: 5 * time.Second, QueueSize: count, MaxMessageBytes: 4096},
+	)
+
+	departments := []string{departmentA, departmentB}
+	// Koneksi genap melanggan departmentA, ganjil semua department
+	filter := func(i int) []string {
+		if i%2 == 0 {
+			return []string{departmentA}
+		}
+		return nil
+	}
+	clients := make([]*websocket.Conn, connections)
+	for i := range clients {
+		clients[i] = f.dial(t, testManagerID)
+		subscribe(t, clients[i], filter(i)...)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for id := range int64(count) {
+			if err := f.publish(id+1, entity.EventEmployeeCreated, testManagerID, departments[id%2], ""); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	for i, ws := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			want := count
+			if i%2 == 0 {
+				want = count / 2
+			}
+			// Subscribe ulang dengan filter yang sama di tengah stream
+			// tidak kehilangan event
+			if err := ws.WriteJSON(ClientMessage{Type: MessageSubscribe, Departments: filter(i)}); err != nil {
+				t.Error(err)
+				return
+			}
+			received, last := 0, int64(0)
+			_ = ws.SetReadDeadline(time.Now().Add(10 * time.Second))
+			for received < want {
+				var message ServerMessage
+				if err := ws.ReadJSON(&message); err != nil {
+					t.Errorf("connection %d after %d events: %v", i, received, err)
+					return
+				}
+				switch message.Type {
+				case MessageSubscribed:
+					continue
+				case MessageEvent:
+				default:
+					t.Errorf("connection %d message = %+v", i, message)
+					return
+				}
+				var envelope events.Envelope
+				if err := json.Unmarshal(message.Event, &envelope); err != nil {
+					t.Error(err)
+					return
+				}
+				if envelope.ID <= last || (i%2 == 0 && envelope.ID%2 == 0) {
+					t.Errorf("connection %d received event %d after %d", i, envelope.ID, last)
+					return
+				}
+				last = envelope.ID
+				received++
+			}
+		}()
+	}
+	wg.Wait()
+
+	f.bus.Shutdown()
+	for _, ws := range clients {
+		ws.Close()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := f.hub.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown error = %v", err)
+	}
+}
+
+func TestCloseCode(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "client closed", err: &websocket.CloseError{Code: websocket.CloseNormalClosure}, want: websocket.CloseNormalClosure},
+		{name: "client going away", err: &websocket.CloseError{Code: websocket.CloseGoingAway}, want: websocket.CloseNormalClosure},
+		{name: "close without status", err: &websocket.CloseError{Code: websocket.CloseNoStatusReceived}, want: websocket.CloseGoingAway},
+		{name: "read limit", err: websocket.ErrReadLimit, want: websocket.CloseMessageTooBig},
+		{name: "connection lost", err: errors.New("i/o timeout"), want: websocket.CloseGoingAway},
+	} {
+		if got := closeCode(tt.err); got != tt.want {
+			t.Errorf("%s: closeCode = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package reporting
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/samber/do/v2"
+)
+
+// Reporter mengirim error ke layanan alerting (mis. Sentry).
+type Reporter interface {
+	Report(ctx context.Context, err error, fields map[string]any)
+}
+
+type noopReporter struct{}
+
+func (noopReporter) Report(ctx context.Context, err error, fields map[string]any) {}
+
+// NewNoopReporter dipakai ketika tidak ada sink yang dikonfigurasi.
+func NewNoopReporter() Reporter {
+	return noopReporter{}
+}
+
+var (
+	defaultMu       sync.RWMutex
+	defaultReporter Reporter = noopReporter{}
+)
+
+// SetDefault mengganti reporter yang dipakai oleh logger dan panic recovery.
+func SetDefault(r Reporter) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultReporter = r
+}
+
+func Default() Reporter {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultReporter
+}
+
+const DefaultQueueSize = 256
+
+type event struct {
+	ctx    context.Context
+	err    error
+	fields map[string]any
+}
+
+// AsyncReporter meneruskan error ke sink lewat antrian terbatas, sehingga
+// sink yang lambat tidak menambah latency request. Event dibuang (dan
+// dihitung) ketika antrian penuh.
+type AsyncReporter struct {
+	sink    Reporter
+	queue   chan event
+	done    chan struct{}
+	dropped atomic.Int64
+
+	mu     sync.RWMutex
+	closed bool
+	flush  func(ctx context.Context) error
+}
+
+func NewAsyncReporter(sink Reporter, queueSize int, flush func(ctx context.Context) error) *AsyncReporter {
+	if queueSize <= 0 {
+		queueSize = DefaultQueueSize
+	}
+	r := &AsyncReporter{
+		sink:  sink,
+		queue: make(chan event, queueSize),
+		done:  make(chan struct{}),
+		flush: flush,
+	}
+	go r.run()
+	return r
+}
+
+func (r *AsyncReporter) run() {
+	defer close(r.done)
+	for e := range r.queue {
+		r.sink.Report(e.ctx, e.err, e.fields)
+	}
+}
+
+func (r *AsyncReporter) Report(ctx context.Context, err error, fields map[string]any) {
+	if err == nil {
+		return
+	}
+	// Context request bisa sudah dibatalkan saat event diproses
+	e := event{ctx: context.WithoutCancel(ctx), err: err, fields: fields}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		return
+	}
+	select {
+	case r.queue <- e:
+	default:
+		r.dropped.Add(1)
+	}
+}
+
+// Dropped mengembalikan jumlah event yang dibuang karena antrian penuh.
+func (r *AsyncReporter) Dropped() int64 {
+	return r.dropped.Load()
+}
+
+// Shutdown menghentikan penerimaan event, menunggu antrian habis, lalu
+// mem-flush sink. Dipanggil oleh injector saat shutdown.
+func (r *AsyncReporter) Shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.queue)
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if r.flush != nil {
+		return r.flush(ctx)
+	}
+	return nil
+}
+
+// NewReporterInject memilih sink berdasarkan env: Sentry jika SENTRY_DSN
+// diset, selain itu no-op. Reporter yang dibuat juga dijadikan default.
+func NewReporterInject(i do.Injector) (Reporter, error) {
+	dsn := os.Getenv("SENTRY_DSN")
+	if dsn == "" {
+		reporter := NewNoopReporter()
+		SetDefault(reporter)
+		return reporter, nil
+	}
+
+	sink, err := NewSentryReporter(dsn, os.Getenv("SENTRY_ENVIRONMENT"))
+	if err != nil {
+		return nil, err
+	}
+	reporter := NewAsyncReporter(sink, DefaultQueueSize, sink.Flush)
+	SetDefault(reporter)
+	log.Println("Error reporting to Sentry enabled")
+	return reporter, nil
+}
@@ -0,0 +1,123 @@
+package reporting
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSink menyimpan error yang dilaporkan. Jika block tidak nil, Report
+// menunggu channel tersebut ditutup, meniru sink yang lambat.
+type fakeSink struct {
+	block chan struct{}
+
+	mu     sync.Mutex
+	errors []error
+	fields []map[string]any
+}
+
+func (s *fakeSink) Report(ctx context.Context, err error, fields map[string]any) {
+	if s.block != nil {
+		<-s.block
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors = append(s.errors, err)
+	s.fields = append(s.fields, fields)
+}
+
+func (s *fakeSink) reported() []error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]error(nil), s.errors...)
+}
+
+func TestAsyncReporterDeliversAndFlushes(t *testing.T) {
+	sink := &fakeSink{}
+	var flushed bool
+	reporter := NewAsyncReporter(sink, 4, func(ctx context.Context) error {
+		flushed = true
+		return nil
+	})
+
+	boom := errors.New("boom")
+	reporter.Report(context.Background(), boom, map[string]any{"route": "/v1/employee"})
+	// Error nil tidak dilaporkan
+	reporter.Report(context.Background(), nil, nil)
+
+	if err := reporter.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if got := sink.reported(); len(got) != 1 || got[0] != boom {
+		t.Fatalf("reported = %v, want [%v]", got, boom)
+	}
+	if sink.fields[0]["route"] != "/v1/employee" {
+		t.Fatalf("fields = %v", sink.fields[0])
+	}
+	if !flushed {
+		t.Fatal("Shutdown did not flush the sink")
+	}
+
+	// Setelah shutdown, Report diabaikan tanpa panic
+	reporter.Report(context.Background(), boom, nil)
+	if got := sink.reported(); len(got) != 1 {
+		t.Fatalf("reported %d errors after shutdown, want 1", len(got))
+	}
+}
+
+// TestAsyncReporterSlowSinkDoesNotBlock memastikan sink yang lambat tidak
+// menambah latency pemanggil: event di luar kapasitas antrian dibuang.
+func TestAsyncReporterSlowSinkDoesNotBlock(t *testing.T) {
+	sink := &fakeSink{block: make(chan struct{})}
+	const queueSize = 2
+	reporter := NewAsyncReporter(sink, queueSize, nil)
+
+	start := time.Now()
+	// Satu event sedang diproses sink, queueSize event menunggu di antrian
+	const reports = 10
+	for range reports {
+		reporter.Report(context.Background(), errors.New("boom"), nil)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Report blocked for %s with a slow sink", elapsed)
+	}
+	if dropped := reporter.Dropped(); dropped < reports-queueSize-1 {
+		t.Fatalf("Dropped() = %d, want at least %d", dropped, reports-queueSize-1)
+	}
+
+	close(sink.block)
+	if err := reporter.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if got := int64(len(sink.reported())) + reporter.Dropped(); got != reports {
+		t.Fatalf("reported + dropped = %d, want %d", got, reports)
+	}
+}
+
+// TestAsyncReporterReportAfterCancel memastikan event tetap terkirim walau
+// context request sudah selesai saat sink memprosesnya.
+func TestAsyncReporterReportAfterCancel(t *testing.T) {
+	var sinkCtxErr error
+	sink := reporterFunc(func(ctx context.Context, err error, fields map[string]any) {
+		sinkCtxErr = ctx.Err()
+	})
+	reporter := NewAsyncReporter(sink, 1, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reporter.Report(ctx, errors.New("boom"), nil)
+	cancel()
+	if err := reporter.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if sinkCtxErr != nil {
+		t.Fatalf("sink context error = %v, want nil", sinkCtxErr)
+	}
+}
+
+type reporterFunc func(ctx context.Context, err error, fields map[string]any)
+
+func (f reporterFunc) Report(ctx context.Context, err error, fields map[string]any) {
+	f(ctx, err, fields)
+}
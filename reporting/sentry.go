@@ -0,0 +1,46 @@
+package reporting
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+)
+
+type SentryReporter struct{}
+
+func NewSentryReporter(dsn string, environment string) (*SentryReporter, error) {
+	err := sentry.Init(sentry.ClientOptions{
+		Dsn:         dsn,
+		Environment: environment,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &SentryReporter{}, nil
+}
+
+func (s *SentryReporter) Report(ctx context.Context, err error, fields map[string]any) {
+	hub := sentry.GetHubFromContext(ctx)
+	if hub == nil {
+		hub = sentry.CurrentHub().Clone()
+	}
+	hub.WithScope(func(scope *sentry.Scope) {
+		for key, value := range fields {
+			scope.SetExtra(key, value)
+		}
+		hub.CaptureException(err)
+	})
+}
+
+func (s *SentryReporter) Flush(ctx context.Context) error {
+	timeout := 2 * time.Second
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	if !sentry.Flush(timeout) {
+		return errors.New("timed out flushing sentry events")
+	}
+	return nil
+}
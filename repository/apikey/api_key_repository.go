@@ -0,0 +1,159 @@
+package apiKeyRepository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/samber/do/v2"
+)
+
+// Nama query untuk log dan metric, lihat database.QueryLogTracer
+const (
+	queryAPIKeyCreate        database.QueryName = "api_key.create"
+	queryAPIKeyGetAll        database.QueryName = "api_key.get_all"
+	queryAPIKeyGetByHash     database.QueryName = "api_key.get_by_hash"
+	queryAPIKeyDelete        database.QueryName = "api_key.delete"
+	queryAPIKeyDeleteExpired database.QueryName = "api_key.delete_expired"
+	apiKeyColumns                               = "id, manager_id, name, key_prefix, key_hash, scopes, expires_at, created_at"
+)
+
+// APIKeyRepository membaca dari primary, agar key yang baru dibuat langsung
+// bisa dipakai dan key yang dihapus langsung ditolak.
+type APIKeyRepository struct {
+	db       database.Querier
+	timeouts *config.QueryTimeoutConfig
+}
+
+type APIKeyRepositoryInterface interface {
+	Create(ctx context.Context, key entity.APIKey) (entity.APIKey, error)
+	GetAll(ctx context.Context, managerId string) ([]entity.APIKey, error)
+	// GetByHash mengembalikan helper.ErrNotFound jika tidak ada key dengan
+	// hash tersebut.
+	GetByHash(ctx context.Context, hash string) (entity.APIKey, error)
+	Delete(ctx context.Context, id, managerId string) error
+	DeleteExpired(ctx context.Context, db database.Querier, retention time.Duration, limit int) (int64, error)
+}
+
+func New(db database.Querier, timeouts *config.QueryTimeoutConfig) APIKeyRepository {
+	return APIKeyRepository{db: db, timeouts: timeouts}
+}
+
+func NewInject(i do.Injector) (APIKeyRepositoryInterface, error) {
+	cluster := do.MustInvoke[*database.Cluster](i)
+	repo := New(cluster.Writer(), config.LoadQueryTimeoutConfig())
+	return &repo, nil
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, key entity.APIKey) (entity.APIKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryAPIKeyCreate)
+
+	query := `
+		INSERT INTO api_key (id, manager_id, name, key_prefix, key_hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + apiKeyColumns + `;
+	`
+	row := r.db.QueryRow(ctx, query, key.Id, key.ManagerId, key.Name, key.Prefix, key.Hash, key.Scopes, key.ExpiresAt)
+	created, err := scanAPIKey(row)
+	if err != nil {
+		return entity.APIKey{}, database.QueryError(ctx, err)
+	}
+	return created, nil
+}
+
+func (r *APIKeyRepository) GetAll(ctx context.Context, managerId string) ([]entity.APIKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryAPIKeyGetAll)
+
+	query := "SELECT " + apiKeyColumns + " FROM api_key WHERE manager_id = $1 ORDER BY created_at, id;"
+	rows, err := r.db.Query(ctx, query, managerId)
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	keys, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.APIKey, error) {
+		return scanAPIKey(row)
+	})
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	return keys, nil
+}
+
+func (r *APIKeyRepository) GetByHash(ctx context.Context, hash string) (entity.APIKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Lookup)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryAPIKeyGetByHash)
+
+	query := "SELECT " + apiKeyColumns + " FROM api_key WHERE key_hash = $1;"
+	key, err := scanAPIKey(r.db.QueryRow(ctx, query, hash))
+	if err == pgx.ErrNoRows {
+		return entity.APIKey{}, database.QueryError(ctx, helper.ErrNotFound)
+	}
+	if err != nil {
+		return entity.APIKey{}, database.QueryError(ctx, err)
+	}
+	return key, nil
+}
+
+func (r *APIKeyRepository) Delete(ctx context.Context, id, managerId string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryAPIKeyDelete)
+
+	tag, err := r.db.Exec(ctx, "DELETE FROM api_key WHERE id = $1 AND manager_id = $2;", id, managerId)
+	if err != nil {
+		return database.QueryError(ctx, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return database.QueryError(ctx, helper.ErrNotFound)
+	}
+	return nil
+}
+
+func scanAPIKey(row pgx.Row) (entity.APIKey, error) {
+	var key entity.APIKey
+	err := row.Scan(
+		&key.Id,
+		&key.ManagerId,
+		&key.Name,
+		&key.Prefix,
+		&key.Hash,
+		&key.Scopes,
+		&key.ExpiresAt,
+		&key.CreatedAt,
+	)
+	return key, err
+}
+
+// DeleteExpired menghapus paling banyak limit key yang kedaluwarsa lebih
+// lama dari retention, lewat db milik pemanggil seperti
+// outboxRepository.DeleteSent. Key tanpa expires_at tidak pernah dihapus.
+func (r *APIKeyRepository) DeleteExpired(ctx context.Context, db database.Querier, retention time.Duration, limit int) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryAPIKeyDeleteExpired)
+
+	query := `
+		DELETE FROM api_key
+		WHERE id IN (
+			SELECT id
+			FROM api_key
+			WHERE expires_at < CURRENT_TIMESTAMP - $1::interval
+			ORDER BY expires_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		);
+	`
+	tag, err := db.Exec(ctx, query, retention, limit)
+	if err != nil {
+		return 0, database.QueryError(ctx, err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,100 @@
+//go:build integration
+
+package apiKeyRepository
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+// testHash mengisi name sampai 64 karakter, sama dengan panjang key_hash.
+func testHash(name string) string {
+	return name + strings.Repeat("0", 64-len(name))
+}
+
+func TestCreateAndGet(t *testing.T) {
+	pool := dbtest.New(t)
+	repo := New(pool, config.LoadQueryTimeoutConfig())
+	ctx := context.Background()
+	manager := dbtest.CreateManager(t, pool, "keys@example.com")
+	other := dbtest.CreateManager(t, pool, "other@example.com")
+
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	keys := []entity.APIKey{
+		{Id: "0192d3a0-0000-7000-8000-000000000001", ManagerId: manager, Name: "warehouse", Prefix: "psk_aaaaaaaa", Hash: testHash("hash-1"), Scopes: []string{entity.APIKeyScopeEmployeesRead}, ExpiresAt: &expiresAt},
+		{Id: "0192d3a0-0000-7000-8000-000000000002", ManagerId: manager, Name: "reports", Prefix: "psk_bbbbbbbb", Hash: testHash("hash-2"), Scopes: []string{entity.APIKeyScopeEmployeesRead, entity.APIKeyScopeDepartmentsRead}},
+		{Id: "0192d3a0-0000-7000-8000-000000000003", ManagerId: other, Name: "other", Prefix: "psk_cccccccc", Hash: testHash("hash-3"), Scopes: []string{entity.APIKeyScopeDepartmentsRead}},
+	}
+	for _, key := range keys {
+		created, err := repo.Create(ctx, key)
+		if err != nil {
+			t.Fatalf("Create(%s) error = %v", key.Name, err)
+		}
+		if created.CreatedAt.IsZero() || created.Hash != key.Hash || !slices.Equal(created.Scopes, key.Scopes) {
+			t.Fatalf("Create = %+v", created)
+		}
+	}
+	// key_hash unik
+	duplicate := keys[0]
+	duplicate.Id = "0192d3a0-0000-7000-8000-000000000004"
+	if _, err := repo.Create(ctx, duplicate); err == nil {
+		t.Fatal("Create with a duplicate hash succeeded")
+	}
+
+	got, err := repo.GetByHash(ctx, testHash("hash-1"))
+	if err != nil {
+		t.Fatalf("GetByHash error = %v", err)
+	}
+	if got.Id != keys[0].Id || got.ManagerId != manager || got.ExpiresAt == nil || !got.ExpiresAt.Equal(expiresAt) {
+		t.Fatalf("GetByHash = %+v", got)
+	}
+	if got, err := repo.GetByHash(ctx, testHash("hash-2")); err != nil || got.ExpiresAt != nil {
+		t.Fatalf("GetByHash without expiry = %+v, %v", got, err)
+	}
+	if _, err := repo.GetByHash(ctx, testHash("hash-404")); !errors.Is(err, helper.ErrNotFound) {
+		t.Fatalf("GetByHash unknown error = %v, want %v", err, helper.ErrNotFound)
+	}
+
+	all, err := repo.GetAll(ctx, manager)
+	if err != nil {
+		t.Fatalf("GetAll error = %v", err)
+	}
+	if len(all) != 2 || all[0].Id != keys[0].Id || all[1].Id != keys[1].Id {
+		t.Fatalf("GetAll = %+v, want the two keys of the manager in creation order", all)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	pool := dbtest.New(t)
+	repo := New(pool, config.LoadQueryTimeoutConfig())
+	ctx := context.Background()
+	manager := dbtest.CreateManager(t, pool, "delete@example.com")
+	other := dbtest.CreateManager(t, pool, "other@example.com")
+	key, err := repo.Create(ctx, entity.APIKey{Id: "0192d3a0-0000-7000-8000-000000000010", ManagerId: manager, Name: "warehouse", Prefix: "psk_dddddddd", Hash: testHash("hash-delete"), Scopes: []string{entity.APIKeyScopeEmployeesRead}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Manager lain tidak bisa menghapus key
+	if err := repo.Delete(ctx, key.Id, other); !errors.Is(err, helper.ErrNotFound) {
+		t.Fatalf("Delete by another manager error = %v, want %v", err, helper.ErrNotFound)
+	}
+	if err := repo.Delete(ctx, key.Id, manager); err != nil {
+		t.Fatalf("Delete error = %v", err)
+	}
+	if _, err := repo.GetByHash(ctx, testHash("hash-delete")); !errors.Is(err, helper.ErrNotFound) {
+		t.Fatalf("GetByHash after Delete error = %v, want %v", err, helper.ErrNotFound)
+	}
+	if err := repo.Delete(ctx, key.Id, manager); !errors.Is(err, helper.ErrNotFound) {
+		t.Fatalf("second Delete error = %v, want %v", err, helper.ErrNotFound)
+	}
+}
@@ -0,0 +1,114 @@
+package auditRepository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/samber/do/v2"
+)
+
+// Nama query untuk log dan metric, lihat database.QueryLogTracer
+const (
+	queryAuditAdd    database.QueryName = "audit.add"
+	queryAuditGetAll database.QueryName = "audit.get_all"
+)
+
+type AuditRepository struct {
+	db database.Querier
+	// reader dipakai untuk query daftar yang boleh dilayani read replica
+	reader   database.Querier
+	timeouts *config.QueryTimeoutConfig
+}
+
+// AuditRepositoryInterface berisi method AuditRepository. Add harus
+// dipanggil dengan repository yang terikat ke transaksi perubahan datanya,
+// lihat repository.UnitOfWork.
+type AuditRepositoryInterface interface {
+	Add(ctx context.Context, log entity.AuditLog) error
+	GetAll(ctx context.Context, input *dto.GetAuditLogsRequest) ([]entity.AuditLog, error)
+}
+
+func New(db database.Querier, reader database.Querier, timeouts *config.QueryTimeoutConfig) AuditRepository {
+	return AuditRepository{db: db, reader: reader, timeouts: timeouts}
+}
+
+func NewInject(i do.Injector) (AuditRepositoryInterface, error) {
+	cluster := do.MustInvoke[*database.Cluster](i)
+	repo := New(cluster.Writer(), cluster.Reader(), config.LoadQueryTimeoutConfig())
+	return &repo, nil
+}
+
+func (r *AuditRepository) Add(ctx context.Context, log entity.AuditLog) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryAuditAdd)
+
+	query := `
+		INSERT INTO audit_log (actor_id, action, entity_type, entity_id, changes, request_id)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''));
+	`
+	_, err := r.db.Exec(ctx, query, log.ActorId, log.Action, log.EntityType, log.EntityId, log.Changes, log.RequestId)
+	return database.QueryError(ctx, err)
+}
+
+// GetAll mengembalikan audit log terbaru lebih dulu. ActorID kosong berarti
+// semua manager; pembatasan per manager dilakukan di service.
+func (r *AuditRepository) GetAll(ctx context.Context, input *dto.GetAuditLogsRequest) ([]entity.AuditLog, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryAuditGetAll)
+
+	filter := &database.Filter{}
+	if input.ActorID != "" {
+		filter.Where("actor_id = $%d", input.ActorID)
+	}
+	if input.EntityType != "" {
+		filter.Where("entity_type = $%d", input.EntityType)
+	}
+	if input.EntityID != "" {
+		filter.Where("entity_id = $%d", input.EntityID)
+	}
+	if input.From != nil {
+		filter.Where("created_at >= $%d", *input.From)
+	}
+	if input.To != nil {
+		filter.Where("created_at < $%d", *input.To)
+	}
+	query := fmt.Sprintf(
+		`SELECT id, actor_id, action, entity_type, entity_id, changes, COALESCE(request_id, ''), created_at
+		FROM audit_log %s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d;`,
+		filter.SQL(),
+		filter.Arg(input.Limit),
+		filter.Arg(input.Offset),
+	)
+
+	rows, err := r.reader.Query(ctx, query, filter.Args()...)
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	logs, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.AuditLog, error) {
+		var log entity.AuditLog
+		err := row.Scan(
+			&log.Id,
+			&log.ActorId,
+			&log.Action,
+			&log.EntityType,
+			&log.EntityId,
+			&log.Changes,
+			&log.RequestId,
+			&log.CreatedAt,
+		)
+		return log, err
+	})
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	return logs, nil
+}
@@ -0,0 +1,120 @@
+//go:build integration
+
+package departmentRepository
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/cache"
+	"github.com/levensspel/go-gin-template/clock"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+)
+
+// discardLogger membuang log Listener; package mocks tidak bisa dipakai di
+// sini karena mengimpor repository.
+type discardLogger struct{}
+
+func (discardLogger) Info(msg string, function helper.FunctionCaller, data ...interface{})  {}
+func (discardLogger) Error(msg string, function helper.FunctionCaller, data ...interface{}) {}
+func (discardLogger) Debug(msg string, function helper.FunctionCaller, data ...interface{}) {}
+func (discardLogger) Warn(msg string, function helper.FunctionCaller, data ...interface{})  {}
+func (l discardLogger) With(fields map[string]any) logger.Logger                            { return l }
+func (l discardLogger) WithContext(ctx context.Context) logger.Logger                       { return l }
+
+// otherInstance adalah cache kepemilikan department milik instance lain:
+// pool dan Listener sendiri seperti NewOwnershipListenerInject, tetapi
+// dengan cache.DepartmentOwners terpisah dari cache proses ini.
+func otherInstance(t *testing.T, shared *pgxpool.Pool) *cache.DepartmentOwners {
+	t.Helper()
+	pool, err := pgxpool.NewWithConfig(context.Background(), shared.Config())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+
+	owners := cache.NewDepartmentOwners(clock.Real())
+	listening := make(chan struct{})
+	var once sync.Once
+	listener := database.NewListener(pool, DepartmentChangedChannel, owners.Delete, func() {
+		owners.Reset()
+		once.Do(func() { close(listening) })
+	}, discardLogger{})
+	listener.Start(context.Background())
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := listener.Shutdown(ctx); err != nil {
+			t.Errorf("listener Shutdown error = %v", err)
+		}
+	})
+
+	select {
+	case <-listening:
+	case <-time.After(10 * time.Second):
+		t.Fatal("listener did not start listening")
+	}
+	return owners
+}
+
+// TestOwnershipEvictedAcrossInstances mengubah department lewat repository
+// instance ini dan memastikan cache kepemilikan di instance lain ikut
+// dihapus lewat NOTIFY, sedangkan department lain tetap di-cache.
+func TestOwnershipEvictedAcrossInstances(t *testing.T) {
+	pool := dbtest.New(t)
+	repo := New(pool, pool, config.LoadQueryTimeoutConfig())
+	ctx := context.Background()
+	manager := dbtest.CreateManager(t, pool, "listener@example.com")
+	other := dbtest.CreateManager(t, pool, "other@example.com")
+
+	tests := []struct {
+		name   string
+		change func(departmentId string) error
+	}{
+		{name: "update", change: func(departmentId string) error {
+			_, err := repo.Update(ctx, "Platform", departmentId, manager)
+			return err
+		}},
+		{name: "reassign", change: func(departmentId string) error {
+			_, _, err := repo.Reassign(ctx, departmentId, other)
+			return err
+		}},
+		{name: "delete", change: func(departmentId string) error {
+			return repo.Delete(ctx, departmentId, manager)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := dbtest.CreateDepartment(t, pool, manager, "Engineering")
+			unchanged := dbtest.CreateDepartment(t, pool, manager, "Finance")
+			second := otherInstance(t, pool)
+			second.Set(changed, manager)
+			second.Set(unchanged, manager)
+
+			if err := tt.change(changed); err != nil {
+				t.Fatalf("%s error = %v", tt.name, err)
+			}
+
+			deadline := time.Now().Add(5 * time.Second)
+			for {
+				if _, found := second.Get(changed); !found {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("department %s is still cached on the other instance", changed)
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+			if owner, found := second.Get(unchanged); !found || owner != manager {
+				t.Fatalf("unchanged department owner = %q, %t, want it still cached", owner, found)
+			}
+		})
+	}
+}
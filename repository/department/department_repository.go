@@ -4,48 +4,98 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"fmt"
+	"log"
+	"time"
 
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5"
+	"github.com/levensspel/go-gin-template/cache"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
 	"github.com/levensspel/go-gin-template/entity"
 	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/lifecycle"
+	"github.com/levensspel/go-gin-template/logger"
 	"github.com/samber/do/v2"
 )
 
+// DepartmentChangedChannel adalah channel LISTEN/NOTIFY dengan payload
+// departmentId setiap kali department dibuat, diubah, atau dihapus.
+const DepartmentChangedChannel = "department_changed"
+
+// Nama query untuk log dan metric, lihat database.QueryLogTracer
+const (
+	queryDepartmentCreate               database.QueryName = "department.create"
+	queryDepartmentGetAll               database.QueryName = "department.get_all"
+	queryDepartmentCount                database.QueryName = "department.count"
+	queryDepartmentUpdate               database.QueryName = "department.update"
+	queryDepartmentDeleteFind           database.QueryName = "department.delete_find"
+	queryDepartmentDeleteCountEmployees database.QueryName = "department.delete_count_employees"
+	queryDepartmentDelete               database.QueryName = "department.delete"
+	queryDepartmentNotifyChanged        database.QueryName = "department.notify_changed"
+	queryDepartmentGetForUpdate         database.QueryName = "department.get_for_update"
+	queryDepartmentPurgeDeleted         database.QueryName = "department.purge_deleted"
+	queryDepartmentGetByIDs             database.QueryName = "department.get_by_ids"
+	queryDepartmentCountEmployees       database.QueryName = "department.count_employees"
+	queryDepartmentReassign             database.QueryName = "department.reassign"
+)
+
 type DepartmentRepository struct {
-	db *pgxpool.Pool
+	db database.Querier
+	// reader dipakai untuk query daftar yang boleh dilayani read replica
+	reader   database.Querier
+	timeouts *config.QueryTimeoutConfig
 }
 
-func New(db *pgxpool.Pool) DepartmentRepository {
-	return DepartmentRepository{db: db}
+// DepartmentRepositoryInterface berisi method DepartmentRepository yang
+// dipakai service, sehingga service bisa dites dengan
+// mocks.DepartmentRepository.
+type DepartmentRepositoryInterface interface {
+	Create(ctx context.Context, id string, name string, managerID string) (*entity.Department, error)
+	GetAll(ctx context.Context, name string, limit int, offset int, managerID string) ([]entity.Department, error)
+	Count(ctx context.Context, name string, managerID string) (int64, error)
+	Update(ctx context.Context, name string, deptID string, managerID string) (*entity.Department, error)
+	Delete(ctx context.Context, deptID string, managerID string) error
+	GetForUpdate(ctx context.Context, deptID string, managerID string) (*entity.Department, error)
+	PurgeDeleted(ctx context.Context, pool database.Querier, retention time.Duration, limit int) (int64, error)
+	GetByIDs(ctx context.Context, deptIDs []string, managerID string) ([]entity.Department, error)
+	CountEmployees(ctx context.Context, deptIDs []string, managerID string) (map[string]int, error)
+	Reassign(ctx context.Context, deptID string, toManagerID string) (dept *entity.Department, fromManagerID string, err error)
 }
 
-func NewInject(i do.Injector) (DepartmentRepository, error) {
-	db := do.MustInvoke[*pgxpool.Pool](i)
-	return New(db), nil
+func New(db database.Querier, reader database.Querier, timeouts *config.QueryTimeoutConfig) DepartmentRepository {
+	return DepartmentRepository{db: db, reader: reader, timeouts: timeouts}
 }
 
+func NewInject(i do.Injector) (DepartmentRepositoryInterface, error) {
+	cluster := do.MustInvoke[*database.Cluster](i)
+	repo := New(cluster.Writer(), cluster.Reader(), config.LoadQueryTimeoutConfig())
+	return &repo, nil
+}
+
+// Create menyimpan department dengan id dari pemanggil, lihat
+// idgen.IDGenerator.
 func (r *DepartmentRepository) Create(
 	ctx context.Context,
+	id string,
 	name string,
 	managerID string,
 ) (*entity.Department, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryDepartmentCreate)
+
 	query := `
 		INSERT INTO department (departmentid, departmentname, managerid)
-		VALUES (DEFAULT, $1, $2)
+		VALUES ($1, $2, $3)
 		RETURNING departmentid, departmentname
 	`
-	row := r.db.QueryRow(ctx, query, name, managerID)
-	var departmentID int
-	var departmentName string
-	err := row.Scan(&departmentID, &departmentName)
+	row := r.db.QueryRow(ctx, query, id, name, managerID)
+	var result entity.Department
+	err := row.Scan(&result.Id, &result.Name)
 	if err != nil {
-		return nil, err
-	}
-	result := entity.Department{
-		Id:   fmt.Sprintf("%d", departmentID),
-		Name: departmentName,
+		return nil, database.QueryError(ctx, err)
 	}
+	r.notifyChanged(ctx, result.Id)
 	return &result, nil
 }
 
@@ -56,6 +106,10 @@ func (r *DepartmentRepository) GetAll(
 	offset int,
 	managerID string,
 ) ([]entity.Department, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryDepartmentGetAll)
+
 	query := `
 		SELECT departmentid, departmentname
 		FROM department
@@ -65,32 +119,58 @@ func (r *DepartmentRepository) GetAll(
 			AND isdeleted = FALSE
 		LIMIT $3 OFFSET $4;
 	`
-	rows, err := r.db.Query(ctx, query, managerID, name, limit, offset)
+	rows, err := r.reader.Query(ctx, query, managerID, name, limit, offset)
 	if err != nil {
-		return nil, err
+		return nil, database.QueryError(ctx, err)
 	}
 	defer rows.Close()
 	var departments []entity.Department
 	for rows.Next() {
-		var departmentID int
-		var departmentName string
-		if err := rows.Scan(&departmentID, &departmentName); err != nil {
-			return departments, err
-		}
 		var dept entity.Department
-		dept.Id = fmt.Sprintf("%d", departmentID)
-		dept.Name = departmentName
+		if err := rows.Scan(&dept.Id, &dept.Name); err != nil {
+			return departments, database.QueryError(ctx, err)
+		}
 		departments = append(departments, dept)
 	}
 	return departments, nil
 }
 
+// Count menghitung department dengan filter nama GetAll, tanpa limit dan
+// offset.
+func (r *DepartmentRepository) Count(
+	ctx context.Context,
+	name string,
+	managerID string,
+) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryDepartmentCount)
+
+	query := `
+		SELECT COUNT(*)
+		FROM department
+		WHERE
+			managerid = $1
+			AND departmentname ILIKE $2
+			AND isdeleted = FALSE;
+	`
+	var count int64
+	if err := r.reader.QueryRow(ctx, query, managerID, name).Scan(&count); err != nil {
+		return 0, database.QueryError(ctx, err)
+	}
+	return count, nil
+}
+
 func (r *DepartmentRepository) Update(
 	ctx context.Context,
 	name string,
-	deptID int,
+	deptID string,
 	managerID string,
 ) (*entity.Department, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryDepartmentUpdate)
+
 	query := `
 		UPDATE department
 		SET departmentname = $1,
@@ -108,19 +188,23 @@ func (r *DepartmentRepository) Update(
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, sql.ErrNoRows
 		}
-		return nil, err
+		return nil, database.QueryError(ctx, err)
 	}
 	result := entity.Department{}
 	result.Id = returnedID
 	result.Name = returnedName
+	r.notifyChanged(ctx, result.Id)
 	return &result, nil
 }
 
 func (r *DepartmentRepository) Delete(
 	ctx context.Context,
-	deptID int,
+	deptID string,
 	managerID string,
 ) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
 	// check if the department exists
 	var deptName string
 	query := `
@@ -132,38 +216,258 @@ func (r *DepartmentRepository) Delete(
 			AND managerid = $2
 			AND isdeleted = FALSE;
 	`
-	err := r.db.QueryRow(ctx, query, deptID, managerID).Scan(&deptName)
+	err := r.db.QueryRow(database.WithQueryName(ctx, queryDepartmentDeleteFind), query, deptID, managerID).Scan(&deptName)
 	if err != nil {
-		return err
+		return database.QueryError(ctx, err)
 	}
 	if deptName == "" {
-		return helper.ErrNotFound
+		return database.QueryError(ctx, helper.ErrNotFound)
 	}
 	// check if the department has employees assigned
 	var employeeCount int64
 	query = `
 		SELECT COUNT(*)
-		FROM employee
-		WHERE 
-			departmentid = $1
-			AND isdeleted = FALSE;
+		FROM employees
+		WHERE departmentid = $1;
 	`
-	err = r.db.QueryRow(ctx, query, deptID).Scan(&employeeCount)
+	err = r.db.QueryRow(database.WithQueryName(ctx, queryDepartmentDeleteCountEmployees), query, deptID).Scan(&employeeCount)
 	if err != nil {
-		return err
+		return database.QueryError(ctx, err)
 	}
 	if employeeCount > 0 {
-		return helper.ErrConflict
+		return database.QueryError(ctx, helper.ErrConflict)
 	}
 	// update the isdeleted flag
 	query = `
 		UPDATE department
-		SET isdeleted = TRUE
+		SET isdeleted = TRUE, deletedon = CURRENT_TIMESTAMP
 		WHERE 
 			departmentid = $1
 			AND managerid = $2
 			AND isdeleted = FALSE;
 	`
-	_, err = r.db.Exec(ctx, query, deptID, managerID)
-	return err
+	_, err = r.db.Exec(database.WithQueryName(ctx, queryDepartmentDelete), query, deptID, managerID)
+	if err != nil {
+		return database.QueryError(ctx, err)
+	}
+	r.notifyChanged(ctx, deptID)
+	return nil
+}
+
+// GetForUpdate mengambil dan mengunci department milik manager sampai
+// transaksinya selesai, atau helper.ErrNotFound.
+func (r *DepartmentRepository) GetForUpdate(
+	ctx context.Context,
+	deptID string,
+	managerID string,
+) (*entity.Department, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Lookup)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryDepartmentGetForUpdate)
+
+	query := `
+		SELECT departmentid, departmentname
+		FROM department
+		WHERE
+			departmentid = $1
+			AND managerid = $2
+			AND isdeleted = FALSE
+		FOR UPDATE;
+	`
+	result := entity.Department{}
+	err := r.db.QueryRow(ctx, query, deptID, managerID).Scan(&result.Id, &result.Name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, database.QueryError(ctx, helper.ErrNotFound)
+	}
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	return &result, nil
+}
+
+// GetByIDs mengambil department milik manager dengan id di deptIDs dalam
+// satu query. Id yang tidak ditemukan atau milik manager lain dilewati.
+func (r *DepartmentRepository) GetByIDs(
+	ctx context.Context,
+	deptIDs []string,
+	managerID string,
+) ([]entity.Department, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryDepartmentGetByIDs)
+
+	query := `
+		SELECT departmentid, departmentname
+		FROM department
+		WHERE
+			departmentid = ANY($1)
+			AND managerid = $2
+			AND isdeleted = FALSE;
+	`
+	rows, err := r.reader.Query(ctx, query, deptIDs, managerID)
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	departments, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.Department, error) {
+		var dept entity.Department
+		err := row.Scan(&dept.Id, &dept.Name)
+		return dept, err
+	})
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	return departments, nil
+}
+
+// CountEmployees menghitung employee aktif per department milik manager
+// dengan id di deptIDs dalam satu query. Department tanpa employee aktif
+// tidak ada di hasilnya.
+func (r *DepartmentRepository) CountEmployees(
+	ctx context.Context,
+	deptIDs []string,
+	managerID string,
+) (map[string]int, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryDepartmentCountEmployees)
+
+	query := `
+		SELECT e.departmentid, COUNT(*)
+		FROM employees e
+		JOIN department d ON d.departmentid = e.departmentid
+		WHERE
+			e.departmentid = ANY($1)
+			AND d.managerid = $2
+			AND d.isdeleted = FALSE
+			AND e.status = 'active'
+		GROUP BY e.departmentid;
+	`
+	rows, err := r.reader.Query(ctx, query, deptIDs, managerID)
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	defer rows.Close()
+	counts := make(map[string]int, len(deptIDs))
+	for rows.Next() {
+		var deptID string
+		var count int
+		if err := rows.Scan(&deptID, &count); err != nil {
+			return nil, database.QueryError(ctx, err)
+		}
+		counts[deptID] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	return counts, nil
+}
+
+// Reassign memindahkan department beserta employee-nya ke manager lain dan
+// mengembalikan department serta manager sebelumnya, atau helper.ErrNotFound.
+// Tidak dibatasi manager, hanya untuk tugas operasional (cmd/admin).
+func (r *DepartmentRepository) Reassign(
+	ctx context.Context,
+	deptID string,
+	toManagerID string,
+) (*entity.Department, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryDepartmentReassign)
+
+	// Subquery mengunci baris dan membaca managerid sebelum diubah
+	query := `
+		UPDATE department d
+		SET managerid = $2, updatedon = CURRENT_TIMESTAMP
+		FROM (
+			SELECT departmentid, managerid
+			FROM department
+			WHERE departmentid = $1 AND isdeleted = FALSE
+			FOR UPDATE
+		) previous
+		WHERE d.departmentid = previous.departmentid
+		RETURNING d.departmentid, d.departmentname, COALESCE(previous.managerid, '');
+	`
+	result := entity.Department{}
+	var fromManagerID string
+	err := r.db.QueryRow(ctx, query, deptID, toManagerID).Scan(&result.Id, &result.Name, &fromManagerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, "", database.QueryError(ctx, helper.ErrNotFound)
+	}
+	if err != nil {
+		return nil, "", database.QueryError(ctx, err)
+	}
+	r.notifyChanged(ctx, deptID)
+	return &result, fromManagerID, nil
+}
+
+// PurgeDeleted menghapus permanen paling banyak limit department yang
+// di-soft-delete lebih dari retention lalu, dihitung dari jam database.
+// Department yang masih dirujuk employee dilewati.
+func (r *DepartmentRepository) PurgeDeleted(
+	ctx context.Context,
+	pool database.Querier,
+	retention time.Duration,
+	limit int,
+) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryDepartmentPurgeDeleted)
+
+	query := `
+		DELETE FROM department
+		WHERE departmentid IN (
+			SELECT d.departmentid
+			FROM department d
+			WHERE
+				d.isdeleted = TRUE
+				AND d.deletedon < CURRENT_TIMESTAMP - $1::interval
+				AND NOT EXISTS (SELECT 1 FROM employees e WHERE e.departmentid = d.departmentid)
+			ORDER BY d.deletedon
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		);
+	`
+	tag, err := pool.Exec(ctx, query, retention, limit)
+	if err != nil {
+		return 0, database.QueryError(ctx, err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// notifyChanged memberi tahu semua instance bahwa department berubah agar
+// cache kepemilikan department-nya dihapus. Kegagalan NOTIFY hanya di-log,
+// karena perubahan datanya sudah tersimpan dan cache tetap punya TTL.
+func (r *DepartmentRepository) notifyChanged(ctx context.Context, departmentId string) {
+	cache.DeleteDepartmentOwner(departmentId)
+
+	_, err := r.db.Exec(
+		database.WithQueryName(ctx, queryDepartmentNotifyChanged),
+		"SELECT pg_notify($1, $2);",
+		DepartmentChangedChannel,
+		departmentId,
+	)
+	if err != nil {
+		log.Printf("Failed to notify %s for department %s: %v", DepartmentChangedChannel, departmentId, err)
+	}
+}
+
+// OwnershipListener menghapus cache kepemilikan department saat instance
+// lain mengubah department.
+type OwnershipListener struct {
+	*database.Listener
+}
+
+func NewOwnershipListenerInject(i do.Injector) (*OwnershipListener, error) {
+	pool := do.MustInvoke[*database.Pool](i).Pool
+	appLogger := do.MustInvoke[logger.LogHandler](i)
+
+	listener := database.NewListener(
+		pool,
+		DepartmentChangedChannel,
+		cache.DeleteDepartmentOwner,
+		cache.ResetDepartmentOwners,
+		&appLogger,
+	)
+	listener.Start(do.MustInvoke[*lifecycle.Lifetime](i).Context())
+	return &OwnershipListener{Listener: listener}, nil
 }
@@ -0,0 +1,147 @@
+//go:build integration
+
+package departmentRepository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/levensspel/go-gin-template/cache"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+func TestCountMatchesGetAllFilter(t *testing.T) {
+	pool := dbtest.New(t)
+	repo := New(pool, pool, config.LoadQueryTimeoutConfig())
+	ctx := context.Background()
+
+	manager := dbtest.CreateManager(t, pool, "count@example.com")
+	other := dbtest.CreateManager(t, pool, "other@example.com")
+	for _, name := range []string{"Engineering", "Sales Engineering", "Finance"} {
+		dbtest.CreateDepartment(t, pool, manager, name)
+	}
+	deleted := dbtest.CreateDepartment(t, pool, manager, "Old Engineering")
+	dbtest.DeleteDepartment(t, pool, deleted)
+	dbtest.CreateDepartment(t, pool, other, "Engineering B")
+
+	for _, tt := range []struct {
+		name string
+		want int64
+	}{
+		{name: "%", want: 3},
+		{name: "%engineering%", want: 2},
+		{name: "%marketing%", want: 0},
+	} {
+		count, err := repo.Count(ctx, tt.name, manager)
+		if err != nil {
+			t.Fatalf("Count(%q) error = %v", tt.name, err)
+		}
+		if count != tt.want {
+			t.Errorf("Count(%q) = %d, want %d", tt.name, count, tt.want)
+		}
+		departments, err := repo.GetAll(ctx, tt.name, 100, 0, manager)
+		if err != nil {
+			t.Fatalf("GetAll(%q) error = %v", tt.name, err)
+		}
+		if int64(len(departments)) != count {
+			t.Errorf("GetAll(%q) returned %d departments, Count = %d", tt.name, len(departments), count)
+		}
+	}
+}
+
+func TestGetByIDsAndCountEmployees(t *testing.T) {
+	pool := dbtest.New(t)
+	repo := New(pool, pool, config.LoadQueryTimeoutConfig())
+	ctx := context.Background()
+
+	manager := dbtest.CreateManager(t, pool, "batch@example.com")
+	other := dbtest.CreateManager(t, pool, "other@example.com")
+	engineering := dbtest.CreateDepartment(t, pool, manager, "Engineering")
+	finance := dbtest.CreateDepartment(t, pool, manager, "Finance")
+	deleted := dbtest.CreateDepartment(t, pool, manager, "Old")
+	dbtest.DeleteDepartment(t, pool, deleted)
+	foreign := dbtest.CreateDepartment(t, pool, other, "Engineering B")
+	for _, e := range []dbtest.Employee{
+		{IdentityNumber: "BATCH-1", Name: "Budi", Gender: "male", DepartmentID: engineering},
+		{IdentityNumber: "BATCH-2", Name: "Sari", Gender: "female", DepartmentID: engineering},
+		{IdentityNumber: "BATCH-3", Name: "Tono", Gender: "male", DepartmentID: engineering, Status: dto.EmployeeStatusArchived},
+		{IdentityNumber: "BATCH-4", Name: "Ani", Gender: "female", DepartmentID: foreign},
+	} {
+		dbtest.CreateEmployee(t, pool, e)
+	}
+	ids := []string{engineering, finance, deleted, foreign}
+
+	departments, err := repo.GetByIDs(ctx, ids, manager)
+	if err != nil {
+		t.Fatalf("GetByIDs error = %v", err)
+	}
+	names := map[string]string{}
+	for _, department := range departments {
+		names[department.Id] = department.Name
+	}
+	// Department terhapus dan milik manager lain tidak dikembalikan
+	if len(names) != 2 || names[engineering] != "Engineering" || names[finance] != "Finance" {
+		t.Fatalf("GetByIDs = %+v, want Engineering and Finance", departments)
+	}
+
+	counts, err := repo.CountEmployees(ctx, ids, manager)
+	if err != nil {
+		t.Fatalf("CountEmployees error = %v", err)
+	}
+	// Employee arsip tidak dihitung, department tanpa employee tidak ada
+	if len(counts) != 1 || counts[engineering] != 2 {
+		t.Fatalf("CountEmployees = %v, want %s: 2", counts, engineering)
+	}
+}
+
+func TestReassign(t *testing.T) {
+	pool := dbtest.New(t)
+	repo := New(pool, pool, config.LoadQueryTimeoutConfig())
+	ctx := context.Background()
+
+	from := dbtest.CreateManager(t, pool, "from@example.com")
+	to := dbtest.CreateManager(t, pool, "to@example.com")
+	engineering := dbtest.CreateDepartment(t, pool, from, "Engineering")
+	dbtest.CreateEmployee(t, pool, dbtest.Employee{IdentityNumber: "MOVE-1", Name: "Budi", Gender: "male", DepartmentID: engineering})
+	cache.SetDepartmentOwner(engineering, from)
+
+	department, fromManagerID, err := repo.Reassign(ctx, engineering, to)
+	if err != nil {
+		t.Fatalf("Reassign error = %v", err)
+	}
+	if department.Id != engineering || department.Name != "Engineering" || fromManagerID != from {
+		t.Fatalf("Reassign = %+v, %q, want Engineering from %s", department, fromManagerID, from)
+	}
+	// Cache kepemilikan lokal dihapus, instance lain lewat pg_notify
+	if owner, found := cache.GetDepartmentOwner(engineering); found {
+		t.Fatalf("department owner %s is still cached", owner)
+	}
+	// Employee ikut berpindah karena dimiliki lewat department
+	departments, err := repo.GetByIDs(ctx, []string{engineering}, to)
+	if err != nil || len(departments) != 1 {
+		t.Fatalf("GetByIDs for the new manager = %+v, %v", departments, err)
+	}
+	if counts, err := repo.CountEmployees(ctx, []string{engineering}, to); err != nil || counts[engineering] != 1 {
+		t.Fatalf("CountEmployees for the new manager = %v, %v, want 1", counts, err)
+	}
+	if departments, _ := repo.GetByIDs(ctx, []string{engineering}, from); len(departments) != 0 {
+		t.Fatalf("old manager still owns %+v", departments)
+	}
+
+	// Reassign ke manager yang sama mengembalikan manager tersebut
+	if _, fromManagerID, err := repo.Reassign(ctx, engineering, to); err != nil || fromManagerID != to {
+		t.Fatalf("repeated Reassign = %q, %v, want %s", fromManagerID, err, to)
+	}
+
+	deleted := dbtest.CreateDepartment(t, pool, from, "Old")
+	dbtest.DeleteDepartment(t, pool, deleted)
+	for _, id := range []string{deleted, "5d1f0c7a-2b3e-4c4d-8e9f-0a1b2c3d4e5f"} {
+		if _, _, err := repo.Reassign(ctx, id, to); !errors.Is(err, helper.ErrNotFound) {
+			t.Fatalf("Reassign(%s) error = %v, want %v", id, err, helper.ErrNotFound)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+// twoManagers berisi dua manager dengan department dan employee masing-
+// masing, untuk memastikan setiap query hanya melihat data manager yang
+// meminta. ownDeleted adalah department milik managerA yang sudah dihapus.
+type twoManagers struct {
+	pool *pgxpool.Pool
+	repo *EmployeeRepository
+
+	managerA, managerB       string
+	departmentA, departmentB string
+	ownDeleted               string
+	employeeA, employeeB     string
+}
+
+func newTwoManagers(t *testing.T) twoManagers {
+	t.Helper()
+	pool, repo := newTestRepository(t)
+	f := twoManagers{pool: pool, repo: repo, employeeA: "EMP-A-1", employeeB: "EMP-B-1"}
+	f.managerA = dbtest.CreateManager(t, pool, "a@example.com")
+	f.managerB = dbtest.CreateManager(t, pool, "b@example.com")
+	f.departmentA = dbtest.CreateDepartment(t, pool, f.managerA, "Engineering A")
+	f.departmentB = dbtest.CreateDepartment(t, pool, f.managerB, "Engineering B")
+	f.ownDeleted = dbtest.CreateDepartment(t, pool, f.managerA, "Deleted A")
+	dbtest.DeleteDepartment(t, pool, f.ownDeleted)
+	dbtest.CreateEmployee(t, pool, dbtest.Employee{IdentityNumber: f.employeeA, Name: "Employee A", Gender: "male", DepartmentID: f.departmentA})
+	dbtest.CreateEmployee(t, pool, dbtest.Employee{IdentityNumber: f.employeeB, Name: "Employee B", Gender: "female", DepartmentID: f.departmentB})
+	return f
+}
+
+func TestIsDepartmentOwnedByManagerTwoManagers(t *testing.T) {
+	f := newTwoManagers(t)
+	ctx := context.Background()
+
+	if err := f.repo.IsDepartmentOwnedByManager(ctx, f.pool, f.departmentA, f.managerA); err != nil {
+		t.Fatalf("own department error = %v, want nil", err)
+	}
+	wantError(t, "other manager's department", f.repo.IsDepartmentOwnedByManager(ctx, f.pool, f.departmentB, f.managerA), helper.ErrInvalidDepartmentId)
+	wantError(t, "deleted department", f.repo.IsDepartmentOwnedByManager(ctx, f.pool, f.ownDeleted, f.managerA), helper.ErrInvalidDepartmentId)
+	// Kepemilikan managerA yang sudah di-cache tidak berlaku untuk managerB
+	wantError(t, "cached owner", f.repo.IsDepartmentOwnedByManager(ctx, f.pool, f.departmentA, f.managerB), helper.ErrInvalidDepartmentId)
+}
+
+func TestCreateIntoDepartmentOfAnotherManager(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		department func(f twoManagers) string
+	}{
+		{name: "other manager", department: func(f twoManagers) string { return f.departmentB }},
+		{name: "deleted", department: func(f twoManagers) string { return f.ownDeleted }},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTwoManagers(t)
+			ctx := context.Background()
+			departmentId := tt.department(f)
+
+			input := employeePayload("NEW-1", departmentId)
+			_, err := f.repo.Create(ctx, &input, f.managerA)
+			wantError(t, "Create", err, helper.ErrInvalidDepartmentId)
+
+			input = employeePayload("NEW-2", departmentId)
+			wantError(t, "Insert", f.repo.Insert(ctx, f.pool, &input, f.managerA), helper.ErrInvalidDepartmentId)
+
+			_, errs, err := f.repo.CreateMany(ctx, f.pool, []dto.EmployeePayload{
+				employeePayload("NEW-3", departmentId),
+				employeePayload("NEW-4", f.departmentA),
+			}, f.managerA)
+			if err != nil {
+				t.Fatalf("CreateMany error = %v", err)
+			}
+			wantError(t, "CreateMany[0]", errs[0], helper.ErrInvalidDepartmentId)
+			if errs[1] != nil {
+				t.Fatalf("CreateMany[1] error = %v, want nil", errs[1])
+			}
+
+			inserted, err := f.repo.InsertBatch(ctx, f.pool, []dto.EmployeePayload{
+				employeePayload("NEW-5", departmentId),
+				employeePayload("NEW-6", f.departmentA),
+			}, f.managerA)
+			if err != nil {
+				t.Fatalf("InsertBatch error = %v", err)
+			}
+			if !slices.Equal(inserted, []bool{false, true}) {
+				t.Fatalf("InsertBatch = %v, want [false true]", inserted)
+			}
+
+			for _, identityNumber := range []string{"NEW-1", "NEW-2", "NEW-3", "NEW-5"} {
+				if got := employeeDepartment(t, f.pool, identityNumber); got != "" {
+					t.Errorf("employee %s was inserted into %s", identityNumber, got)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateAcrossManagers(t *testing.T) {
+	f := newTwoManagers(t)
+	ctx := context.Background()
+
+	name := "Renamed"
+	_, err := f.repo.Update(ctx, f.employeeB, &dto.EmployeeUpdatePayload{Name: &name}, f.managerA)
+	wantError(t, "update other manager's employee", err, helper.ErrNotFound)
+
+	for _, departmentId := range []string{f.departmentB, f.ownDeleted} {
+		_, err = f.repo.Update(ctx, f.employeeA, &dto.EmployeeUpdatePayload{DepartmentID: &departmentId}, f.managerA)
+		wantError(t, "move into "+departmentId, err, helper.ErrInvalidDepartmentId)
+	}
+	if got := employeeDepartment(t, f.pool, f.employeeA); got != f.departmentA {
+		t.Fatalf("employee A department = %s, want %s", got, f.departmentA)
+	}
+
+	payload := employeePayload(f.employeeB, f.departmentA)
+	updated, err := f.repo.UpdateBatch(ctx, f.pool, []dto.EmployeePayload{payload}, f.managerA)
+	if err != nil {
+		t.Fatalf("UpdateBatch error = %v", err)
+	}
+	if updated[0] {
+		t.Fatal("UpdateBatch updated another manager's employee")
+	}
+	payload = employeePayload(f.employeeA, f.departmentB)
+	updated, err = f.repo.UpdateBatch(ctx, f.pool, []dto.EmployeePayload{payload}, f.managerA)
+	if err != nil {
+		t.Fatalf("UpdateBatch error = %v", err)
+	}
+	if updated[0] {
+		t.Fatal("UpdateBatch moved an employee into another manager's department")
+	}
+	if got := employeeDepartment(t, f.pool, f.employeeB); got != f.departmentB {
+		t.Fatalf("employee B department = %s, want %s", got, f.departmentB)
+	}
+}
+
+func TestOtherManagersEmployeeIsNotFound(t *testing.T) {
+	f := newTwoManagers(t)
+	ctx := context.Background()
+
+	_, err := f.repo.GetForUpdate(ctx, f.employeeB, f.managerA)
+	wantError(t, "GetForUpdate", err, helper.ErrNotFound)
+	_, err = f.repo.SetStatus(ctx, f.employeeB, dto.EmployeeStatusArchived, f.managerA)
+	wantError(t, "SetStatus", err, helper.ErrNotFound)
+	_, err = f.repo.Delete(ctx, f.employeeB, f.managerA)
+	wantError(t, "Delete", err, helper.ErrNotFound)
+
+	if got := employeeDepartment(t, f.pool, f.employeeB); got != f.departmentB {
+		t.Fatalf("employee B department = %q, want it untouched", got)
+	}
+}
+
+func TestListOnlyReturnsOwnEmployees(t *testing.T) {
+	f := newTwoManagers(t)
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		name  string
+		input dto.GetEmployeesRequest
+		want  []string
+	}{
+		{name: "all", input: dto.GetEmployeesRequest{}, want: []string{f.employeeA}},
+		{name: "other manager's department", input: dto.GetEmployeesRequest{DepartmentID: f.departmentB}, want: []string{}},
+		{name: "other manager's identity number", input: dto.GetEmployeesRequest{IdentityNumbers: []string{f.employeeB}}, want: []string{}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			input := tt.input
+			input.Limit = 10
+			input.ManagerID = f.managerA
+			input.Status = dto.EmployeeStatusActive
+			employees, err := f.repo.GetAll(ctx, &input)
+			if err != nil {
+				t.Fatalf("GetAll error = %v", err)
+			}
+			if got := identityNumbers(employees); !slices.Equal(got, tt.want) {
+				t.Fatalf("GetAll = %v, want %v", got, tt.want)
+			}
+			count, err := f.repo.Count(ctx, &input)
+			if err != nil {
+				t.Fatalf("Count error = %v", err)
+			}
+			if count != int64(len(tt.want)) {
+				t.Fatalf("Count = %d, want %d", count, len(tt.want))
+			}
+		})
+	}
+
+	stats, err := f.repo.Stats(ctx, f.managerA)
+	if err != nil {
+		t.Fatalf("Stats error = %v", err)
+	}
+	if len(stats) != 1 || stats[0].DepartmentID != f.departmentA {
+		t.Fatalf("Stats = %+v, want only department %s", stats, f.departmentA)
+	}
+}
+
+func TestMoveDepartmentAcrossManagers(t *testing.T) {
+	f := newTwoManagers(t)
+	ctx := context.Background()
+
+	_, err := f.repo.MoveDepartment(ctx, f.departmentA, f.departmentB, f.managerA)
+	wantError(t, "move into other manager's department", err, helper.ErrInvalidDepartmentId)
+	_, err = f.repo.MoveDepartment(ctx, f.departmentA, f.ownDeleted, f.managerA)
+	wantError(t, "move into deleted department", err, helper.ErrInvalidDepartmentId)
+
+	moved, err := f.repo.MoveDepartment(ctx, f.departmentB, f.departmentA, f.managerA)
+	if err != nil {
+		t.Fatalf("move out of other manager's department error = %v", err)
+	}
+	if len(moved) != 0 {
+		t.Fatalf("moved %d employees out of another manager's department", len(moved))
+	}
+
+	if got := employeeDepartment(t, f.pool, f.employeeA); got != f.departmentA {
+		t.Fatalf("employee A department = %s, want %s", got, f.departmentA)
+	}
+	if got := employeeDepartment(t, f.pool, f.employeeB); got != f.departmentB {
+		t.Fatalf("employee B department = %s, want %s", got, f.departmentB)
+	}
+}
@@ -0,0 +1,115 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/cache"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/dto"
+)
+
+// roundTripTracer menghitung round trip ke database: setiap query biasa dan
+// setiap pgx.Batch dihitung satu, berapa pun statement di dalam batch.
+type roundTripTracer struct {
+	count atomic.Int64
+}
+
+func (t *roundTripTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	t.count.Add(1)
+	return ctx
+}
+
+func (t *roundTripTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+}
+
+func (t *roundTripTracer) TraceBatchStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceBatchStartData) context.Context {
+	t.count.Add(1)
+	return ctx
+}
+
+func (t *roundTripTracer) TraceBatchQuery(ctx context.Context, conn *pgx.Conn, data pgx.TraceBatchQueryData) {
+}
+
+func (t *roundTripTracer) TraceBatchEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceBatchEndData) {
+}
+
+// BenchmarkCreateMany membandingkan bulk create 50 employee lewat
+// pengecekan department dan Insert per employee (alur sebelum CreateMany)
+// dengan CreateMany dalam satu pgx.Batch. Jumlah round trip per operasi
+// dilaporkan sebagai metrik round-trips/op.
+func BenchmarkCreateMany(b *testing.B) {
+	const employees = 50
+
+	shared := dbtest.New(b)
+	manager := dbtest.CreateManager(b, shared, "batch-bench@example.com")
+	department := dbtest.CreateDepartment(b, shared, manager, "Engineering")
+
+	tracer := &roundTripTracer{}
+	poolConfig := shared.Config()
+	poolConfig.ConnConfig.Tracer = tracer
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer pool.Close()
+	repo := NewEmployeeRepository(pool, pool, config.LoadQueryTimeoutConfig(), &config.SearchConfig{})
+	ctx := context.Background()
+
+	run := func(b *testing.B, prefix string, create func(tx pgx.Tx, inputs []dto.EmployeePayload) error) {
+		var roundTrips int64
+		for i := range b.N {
+			b.StopTimer()
+			truncateEmployees(b, pool)
+			// Tanpa cache, cek kepemilikan department benar-benar ke database
+			cache.DeleteDepartmentOwner(department)
+			inputs := importRows(fmt.Sprintf("%s%d", prefix, i), employees, department)
+			before := tracer.count.Load()
+			b.StartTimer()
+
+			if err := pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error { return create(tx, inputs) }); err != nil {
+				b.Fatal(err)
+			}
+
+			b.StopTimer()
+			roundTrips += tracer.count.Load() - before
+			b.StartTimer()
+		}
+		b.ReportMetric(float64(roundTrips)/float64(b.N), "round-trips/op")
+	}
+
+	b.Run("sequential", func(b *testing.B) {
+		run(b, "S", func(tx pgx.Tx, inputs []dto.EmployeePayload) error {
+			for i := range inputs {
+				if err := repo.IsDepartmentOwnedByManager(ctx, tx, inputs[i].DepartmentID, manager); err != nil {
+					return err
+				}
+				if err := repo.Insert(ctx, tx, &inputs[i], manager); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	b.Run("batch", func(b *testing.B) {
+		run(b, "M", func(tx pgx.Tx, inputs []dto.EmployeePayload) error {
+			_, errs, err := repo.CreateMany(ctx, tx, inputs, manager)
+			if err != nil {
+				return err
+			}
+			for _, err := range errs {
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
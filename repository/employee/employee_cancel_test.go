@@ -0,0 +1,77 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/dto"
+)
+
+// TestCancelAbortsGetAll menahan GetAll di belakang lock table, lalu
+// membatalkan context request-nya. Query harus hilang dari
+// pg_stat_activity alih-alih berjalan sampai timeout repository.
+func TestCancelAbortsGetAll(t *testing.T) {
+	pool, repo := newTestRepository(t)
+	manager := dbtest.CreateManager(t, pool, "cancel@example.com")
+
+	lock, err := pool.Begin(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lock.Rollback(context.Background())
+	if _, err := lock.Exec(context.Background(), "LOCK TABLE employees IN ACCESS EXCLUSIVE MODE;"); err != nil {
+		t.Fatalf("lock employees: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		_, err := repo.GetAll(ctx, &dto.GetEmployeesRequest{Limit: 10, ManagerID: manager})
+		result <- err
+	}()
+
+	waitForQuery(t, pool, true)
+	cancel()
+
+	select {
+	case err := <-result:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("GetAll error = %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("GetAll did not return after its context was cancelled")
+	}
+	// Lock masih dipegang, jadi query hanya hilang jika benar-benar dibatalkan
+	waitForQuery(t, pool, false)
+}
+
+// waitForQuery menunggu sampai GetAll terlihat (atau tidak lagi terlihat)
+// aktif di pg_stat_activity.
+func waitForQuery(t *testing.T, pool *pgxpool.Pool, active bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var running bool
+		err := pool.QueryRow(
+			context.Background(),
+			"SELECT EXISTS (SELECT 1 FROM pg_stat_activity WHERE state = 'active' AND pid <> pg_backend_pid() AND query LIKE $1);",
+			"%"+employeeFromClause+"%",
+		).Scan(&running)
+		if err != nil {
+			t.Fatalf("read pg_stat_activity: %v", err)
+		}
+		if running == active {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("GetAll active in pg_stat_activity = %t, want %t", running, active)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,99 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/dto"
+)
+
+// importRows membuat rows employee valid dengan identityNumber berawalan
+// prefix, agar setiap iterasi benchmark tidak bentrok.
+func importRows(prefix string, rows int, departmentId string) []dto.EmployeePayload {
+	inputs := make([]dto.EmployeePayload, rows)
+	for i := range inputs {
+		inputs[i] = dto.EmployeePayload{
+			IdentityNumber:   fmt.Sprintf("%s-%05d", prefix, i),
+			Name:             "Budi Santoso",
+			EmployeeImageUri: "https://example.com/budi.png",
+			Gender:           "male",
+			DepartmentID:     departmentId,
+		}
+	}
+	return inputs
+}
+
+// truncateEmployees mengosongkan employees di antara iterasi benchmark.
+func truncateEmployees(b *testing.B, pool *pgxpool.Pool) {
+	b.Helper()
+	if _, err := pool.Exec(context.Background(), "TRUNCATE employees CASCADE;"); err != nil {
+		b.Fatal(err)
+	}
+}
+
+// BenchmarkImport10k membandingkan jalur import batch insert (per 500
+// baris, satu transaksi per batch seperti service) dengan COPY ke staging
+// table untuk 10.000 baris.
+func BenchmarkImport10k(b *testing.B) {
+	const rows = 10_000
+	const batchSize = 500
+
+	pool := dbtest.New(b)
+	repo := NewEmployeeRepository(pool, pool, config.LoadQueryTimeoutConfig(), &config.SearchConfig{})
+	ctx := context.Background()
+	manager := dbtest.CreateManager(b, pool, "import-bench@example.com")
+	department := dbtest.CreateDepartment(b, pool, manager, "Engineering")
+
+	b.Run("batch insert", func(b *testing.B) {
+		for i := range b.N {
+			b.StopTimer()
+			truncateEmployees(b, pool)
+			inputs := importRows(fmt.Sprintf("B%d", i), rows, department)
+			b.StartTimer()
+
+			for start := 0; start < rows; start += batchSize {
+				err := pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+					inserted, err := repo.InsertBatch(ctx, tx, inputs[start:start+batchSize], manager)
+					if err == nil && len(inserted) != batchSize {
+						err = fmt.Errorf("inserted %d rows, want %d", len(inserted), batchSize)
+					}
+					return err
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+		b.ReportMetric(float64(rows), "rows/op")
+	})
+	b.Run("copy", func(b *testing.B) {
+		for i := range b.N {
+			b.StopTimer()
+			truncateEmployees(b, pool)
+			inputs := importRows(fmt.Sprintf("C%d", i), rows, department)
+			b.StartTimer()
+
+			err := pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+				copied, inserted, err := repo.CopyEmployees(ctx, tx, pgx.CopyFromSlice(len(inputs), func(i int) ([]any, error) {
+					input := inputs[i]
+					return []any{input.IdentityNumber, input.Name, input.EmployeeImageUri, input.Gender, input.DepartmentID}, nil
+				}), manager)
+				if err == nil && (copied != rows || inserted != rows) {
+					err = fmt.Errorf("copied %d and inserted %d rows, want %d", copied, inserted, rows)
+				}
+				return err
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(rows), "rows/op")
+	})
+}
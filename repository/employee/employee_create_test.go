@@ -0,0 +1,231 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+// TestConcurrentCreateSameIdentityNumber memastikan ON CONFLICT membuat
+// tepat satu create berhasil dan sisanya mendapat conflict, tanpa
+// pengecekan ketersediaan terpisah.
+func TestConcurrentCreateSameIdentityNumber(t *testing.T) {
+	const creates = 50
+	for _, tt := range []struct {
+		name   string
+		create func(repo *EmployeeRepository, pool *pgxpool.Pool, input *dto.EmployeePayload, managerId string) error
+	}{
+		{name: "Create", create: func(repo *EmployeeRepository, pool *pgxpool.Pool, input *dto.EmployeePayload, managerId string) error {
+			_, err := repo.Create(context.Background(), input, managerId)
+			return err
+		}},
+		{name: "Insert", create: func(repo *EmployeeRepository, pool *pgxpool.Pool, input *dto.EmployeePayload, managerId string) error {
+			return repo.Insert(context.Background(), pool, input, managerId)
+		}},
+		{
+			// Pengecekan konflik berjalan di transaksi pemanggil dan tetap
+			// melihat baris yang di-commit transaksi lain
+			name: "Insert in transaction",
+			create: func(repo *EmployeeRepository, pool *pgxpool.Pool, input *dto.EmployeePayload, managerId string) error {
+				return pgx.BeginFunc(context.Background(), pool, func(tx pgx.Tx) error {
+					return repo.Insert(context.Background(), tx, input, managerId)
+				})
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			pool, repo := newTestRepository(t)
+			manager := dbtest.CreateManager(t, pool, "race@example.com")
+			department := dbtest.CreateDepartment(t, pool, manager, "Engineering")
+
+			var wg sync.WaitGroup
+			start := make(chan struct{})
+			errs := make([]error, creates)
+			for i := range creates {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					input := employeePayload("RACE-1", department)
+					<-start
+					errs[i] = tt.create(repo, pool, &input, manager)
+				}()
+			}
+			close(start)
+			wg.Wait()
+
+			var succeeded, conflicts int
+			for _, err := range errs {
+				switch {
+				case err == nil:
+					succeeded++
+				case errors.Is(err, helper.ErrConflictIdentityNumber):
+					conflicts++
+				default:
+					t.Fatalf("error = %v, want nil or %v", err, helper.ErrConflictIdentityNumber)
+				}
+			}
+			if succeeded != 1 || conflicts != creates-1 {
+				t.Fatalf("succeeded = %d, conflicts = %d, want 1 and %d", succeeded, conflicts, creates-1)
+			}
+
+			var rows int
+			err := pool.QueryRow(context.Background(), "SELECT COUNT(*) FROM employees WHERE identitynumber = $1;", "RACE-1").Scan(&rows)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rows != 1 {
+				t.Fatalf("employees with RACE-1 = %d, want 1", rows)
+			}
+		})
+	}
+}
+
+func TestInsertAndCreateManyConflicts(t *testing.T) {
+	pool, repo := newTestRepository(t)
+	ctx := context.Background()
+	manager := dbtest.CreateManager(t, pool, "conflict@example.com")
+	department := dbtest.CreateDepartment(t, pool, manager, "Engineering")
+	dbtest.CreateEmployee(t, pool, dbtest.Employee{IdentityNumber: "TAKEN-1", Name: "Taken", Gender: "male", DepartmentID: department})
+
+	input := employeePayload("TAKEN-1", department)
+	_, err := repo.Create(ctx, &input, manager)
+	wantError(t, "Create", err, helper.ErrConflictIdentityNumber)
+	wantError(t, "Insert", repo.Insert(ctx, pool, &input, manager), helper.ErrConflictIdentityNumber)
+
+	// Duplikat di dalam batch yang sama dan dengan baris yang sudah ada
+	employees, errs, err := repo.CreateMany(ctx, pool, []dto.EmployeePayload{
+		employeePayload("TAKEN-1", department),
+		employeePayload("NEW-1", department),
+		employeePayload("NEW-1", department),
+	}, manager)
+	if err != nil {
+		t.Fatalf("CreateMany error = %v", err)
+	}
+	wantError(t, "CreateMany[0]", errs[0], helper.ErrConflictIdentityNumber)
+	if errs[1] != nil {
+		t.Fatalf("CreateMany[1] error = %v, want nil", errs[1])
+	}
+	wantError(t, "CreateMany[2]", errs[2], helper.ErrConflictIdentityNumber)
+
+	// Baris yang ter-insert dikembalikan seperti yang tersimpan
+	stored, err := repo.GetForUpdate(ctx, "NEW-1", manager)
+	if err != nil {
+		t.Fatalf("GetForUpdate error = %v", err)
+	}
+	if !reflect.DeepEqual(employees[1], stored) || stored.Status != dto.EmployeeStatusActive || stored.CreatedAt.IsZero() {
+		t.Fatalf("CreateMany[1] = %+v, want stored %+v", employees[1], stored)
+	}
+}
+
+// TestInsertDuplicateInSameTransaction memastikan duplikat yang di-insert
+// sebelumnya oleh transaksi yang sama tetap dilaporkan sebagai konflik,
+// bukan department yang tidak valid.
+func TestInsertDuplicateInSameTransaction(t *testing.T) {
+	pool, repo := newTestRepository(t)
+	ctx := context.Background()
+	manager := dbtest.CreateManager(t, pool, "same-tx@example.com")
+	department := dbtest.CreateDepartment(t, pool, manager, "Engineering")
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	input := employeePayload("TX-1", department)
+	if err := repo.Insert(ctx, tx, &input, manager); err != nil {
+		t.Fatalf("first Insert error = %v", err)
+	}
+	err = repo.Insert(ctx, tx, &input, manager)
+	wantError(t, "second Insert", err, helper.ErrConflictIdentityNumber)
+	if got := helper.GetErrorStatusCode(err); got != http.StatusConflict {
+		t.Fatalf("status = %d, want 409", got)
+	}
+}
+
+func TestUpdateConflicts(t *testing.T) {
+	pool, repo := newTestRepository(t)
+	ctx := context.Background()
+	manager := dbtest.CreateManager(t, pool, "update-conflict@example.com")
+	department := dbtest.CreateDepartment(t, pool, manager, "Engineering")
+	deleted := dbtest.CreateDepartment(t, pool, manager, "Deleted")
+	dbtest.DeleteDepartment(t, pool, deleted)
+	dbtest.CreateEmployee(t, pool, dbtest.Employee{IdentityNumber: "TAKEN-1", Name: "Taken", Gender: "male", DepartmentID: department})
+	dbtest.CreateEmployee(t, pool, dbtest.Employee{IdentityNumber: "TAKEN-2", Name: "Taken", Gender: "male", DepartmentID: department})
+
+	taken := "TAKEN-2"
+	_, err := repo.Update(ctx, "TAKEN-1", &dto.EmployeeUpdatePayload{IdentityNumber: &taken}, manager)
+	wantError(t, "Update to a taken identity number", err, helper.ErrConflictIdentityNumber)
+	_, err = repo.Update(ctx, "TAKEN-1", &dto.EmployeeUpdatePayload{DepartmentID: &deleted}, manager)
+	wantError(t, "Update into a deleted department", err, helper.ErrInvalidDepartmentId)
+	_, err = repo.Update(ctx, "MISSING-1", &dto.EmployeeUpdatePayload{IdentityNumber: &taken}, manager)
+	wantError(t, "Update a missing employee", err, helper.ErrNotFound)
+
+	if got := employeeDepartment(t, pool, "TAKEN-1"); got != department {
+		t.Fatalf("TAKEN-1 department = %q, want it untouched", got)
+	}
+}
+
+func TestUpdateOnlySentFields(t *testing.T) {
+	pool, repo := newTestRepository(t)
+	ctx := context.Background()
+	manager := dbtest.CreateManager(t, pool, "patch@example.com")
+	engineering := dbtest.CreateDepartment(t, pool, manager, "Engineering")
+	finance := dbtest.CreateDepartment(t, pool, manager, "Finance")
+	dbtest.CreateEmployee(t, pool, dbtest.Employee{IdentityNumber: "PATCH-1", Name: "Budi", Gender: "male", DepartmentID: engineering, HiredAt: "2024-03-01"})
+
+	name, gender, hiredAt := "Budi Santoso", "female", "2023-01-02"
+	imageUri := "https://example.com/budi.png"
+	original := dto.EmployeePayload{
+		IdentityNumber:   "PATCH-1",
+		Name:             "Budi",
+		EmployeeImageUri: "https://example.com/image.png",
+		Gender:           "male",
+		DepartmentID:     engineering,
+	}
+	originalHiredAt := "2024-03-01"
+	original.HiredAt = &originalHiredAt
+	// Setiap langkah memakai hasil langkah sebelumnya
+	for _, tt := range []struct {
+		name  string
+		input dto.EmployeeUpdatePayload
+		apply func(p *dto.EmployeePayload)
+	}{
+		{name: "name only", input: dto.EmployeeUpdatePayload{Name: &name}, apply: func(p *dto.EmployeePayload) { p.Name = name }},
+		{name: "gender and department", input: dto.EmployeeUpdatePayload{Gender: &gender, DepartmentID: &finance}, apply: func(p *dto.EmployeePayload) {
+			p.Gender, p.DepartmentID = gender, finance
+		}},
+		{name: "image", input: dto.EmployeeUpdatePayload{EmployeeImageUri: &imageUri}, apply: func(p *dto.EmployeePayload) { p.EmployeeImageUri = imageUri }},
+		{name: "hiredAt", input: dto.EmployeeUpdatePayload{HiredAt: &hiredAt}, apply: func(p *dto.EmployeePayload) { p.HiredAt = &hiredAt }},
+		// null menghapus gambar dan tanggal
+		{name: "clear image", input: dto.EmployeeUpdatePayload{Nulls: []string{"employeeImageUri"}}, apply: func(p *dto.EmployeePayload) { p.EmployeeImageUri = "" }},
+		{name: "clear hiredAt", input: dto.EmployeeUpdatePayload{Nulls: []string{"hiredAt"}}, apply: func(p *dto.EmployeePayload) { p.HiredAt = nil }},
+	} {
+		tt.apply(&original)
+		updated, err := repo.Update(ctx, "PATCH-1", &tt.input, manager)
+		if err != nil {
+			t.Fatalf("%s: Update error = %v", tt.name, err)
+		}
+		if !updated.EmployeePayload.Equal(original) {
+			t.Fatalf("%s: Update = %+v, want %+v", tt.name, updated.EmployeePayload, original)
+		}
+		existing, err := repo.GetForUpsert(ctx, pool, []string{"PATCH-1"}, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stored := existing["PATCH-1"].Employee.EmployeePayload; !stored.Equal(original) {
+			t.Fatalf("%s: stored = %+v, want %+v", tt.name, stored, original)
+		}
+	}
+}
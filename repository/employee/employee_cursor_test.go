@@ -0,0 +1,146 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/pagination"
+)
+
+// TestGetAllAfterWalk berjalan maju sampai halaman terakhir lalu mundur
+// lagi ke halaman pertama. Beberapa employee punya createdAt yang sama dan
+// batas halaman jatuh di tengah createdAt yang sama, sehingga urutan
+// bergantung pada identityNumber sebagai tiebreaker.
+func TestGetAllAfterWalk(t *testing.T) {
+	pool, repo := newTestRepository(t)
+	ctx := context.Background()
+	manager := dbtest.CreateManager(t, pool, "cursor@example.com")
+	department := dbtest.CreateDepartment(t, pool, manager, "Engineering")
+
+	base := time.Date(2026, 1, 2, 3, 4, 5, 123456000, time.UTC)
+	createdAt := map[string]time.Time{
+		"EMP-1": base,
+		"EMP-2": base,
+		"EMP-3": base,
+		"EMP-4": base,
+		"EMP-5": base.Add(time.Second),
+		"EMP-6": base.Add(time.Second),
+		"EMP-7": base.Add(2 * time.Second),
+	}
+	// Insert tidak urut agar urutan hasil tidak kebetulan sama dengan urutan insert
+	for _, identityNumber := range []string{"EMP-6", "EMP-3", "EMP-7", "EMP-1", "EMP-5", "EMP-4", "EMP-2"} {
+		dbtest.CreateEmployee(t, pool, dbtest.Employee{
+			IdentityNumber: identityNumber,
+			Name:           "Budi",
+			Gender:         "male",
+			DepartmentID:   department,
+			CreatedAt:      createdAt[identityNumber],
+		})
+	}
+
+	// Cursor dilewatkan lewat Codec seperti ke client, agar presisi
+	// createdAt ikut teruji
+	codec := pagination.NewCodec([]byte("cursor-test-secret"))
+	page := func(cursor pagination.Cursor) []dto.EmployeeResponse {
+		t.Helper()
+		decoded, err := codec.Decode(codec.Encode(cursor))
+		if err != nil {
+			t.Fatalf("Decode cursor %+v: %v", cursor, err)
+		}
+		employees, err := repo.GetAllAfter(ctx, &dto.GetEmployeesRequest{Limit: 3, ManagerID: manager}, decoded)
+		if err != nil {
+			t.Fatalf("GetAllAfter(%+v) error = %v", cursor, err)
+		}
+		return employees
+	}
+	cursorAt := func(employee dto.EmployeeResponse, direction pagination.Direction) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: employee.CreatedAt, IdentityNumber: employee.IdentityNumber, Direction: direction}
+	}
+
+	want := [][]string{
+		{"EMP-1", "EMP-2", "EMP-3"},
+		{"EMP-4", "EMP-5", "EMP-6"},
+		{"EMP-7"},
+	}
+
+	var pages [][]dto.EmployeeResponse
+	cursor := pagination.Cursor{}
+	for i := range want {
+		employees := page(cursor)
+		if got := identityNumbers(employees); !slices.Equal(got, want[i]) {
+			t.Fatalf("forward page %d = %v, want %v", i+1, got, want[i])
+		}
+		pages = append(pages, employees)
+		cursor = cursorAt(employees[len(employees)-1], pagination.Forward)
+	}
+	if got := identityNumbers(page(cursor)); len(got) != 0 {
+		t.Fatalf("page after the last = %v, want empty", got)
+	}
+
+	for i := len(want) - 1; i > 0; i-- {
+		previous := page(cursorAt(pages[i][0], pagination.Backward))
+		if got := identityNumbers(previous); !slices.Equal(got, want[i-1]) {
+			t.Fatalf("backward from page %d = %v, want %v", i+1, got, want[i-1])
+		}
+		for j, employee := range previous {
+			if !employee.CreatedAt.Equal(pages[i-1][j].CreatedAt) {
+				t.Fatalf("backward page %d createdAt[%d] = %v, want %v", i, j, employee.CreatedAt, pages[i-1][j].CreatedAt)
+			}
+		}
+	}
+	if got := identityNumbers(page(cursorAt(pages[0][0], pagination.Backward))); len(got) != 0 {
+		t.Fatalf("page before the first = %v, want empty", got)
+	}
+
+	// Mundur dari tengah tie: sebelum EMP-3 hanya EMP-1 dan EMP-2 walau
+	// EMP-4 punya createdAt yang sama
+	middle := dto.EmployeeResponse{EmployeePayload: dto.EmployeePayload{IdentityNumber: "EMP-3"}, CreatedAt: base}
+	if got, want := identityNumbers(page(cursorAt(middle, pagination.Backward))), []string{"EMP-1", "EMP-2"}; !slices.Equal(got, want) {
+		t.Fatalf("backward from EMP-3 = %v, want %v", got, want)
+	}
+}
+
+// TestGetAllDefaultOrder memastikan GET tanpa sortBy berhalaman offset
+// diurutkan berdasarkan createdAt lalu identityNumber, sehingga setiap
+// employee muncul tepat sekali walau createdAt-nya sama.
+func TestGetAllDefaultOrder(t *testing.T) {
+	pool, repo := newTestRepository(t)
+	ctx := context.Background()
+	manager := dbtest.CreateManager(t, pool, "order@example.com")
+	department := dbtest.CreateDepartment(t, pool, manager, "Engineering")
+
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	createdAt := map[string]time.Time{
+		"EMP-1": base,
+		"EMP-2": base,
+		"EMP-3": base,
+		"EMP-4": base.Add(-time.Second),
+		"EMP-5": base.Add(time.Second),
+	}
+	for _, identityNumber := range []string{"EMP-3", "EMP-5", "EMP-1", "EMP-4", "EMP-2"} {
+		dbtest.CreateEmployee(t, pool, dbtest.Employee{
+			IdentityNumber: identityNumber,
+			Name:           "Budi",
+			Gender:         "male",
+			DepartmentID:   department,
+			CreatedAt:      createdAt[identityNumber],
+		})
+	}
+
+	want := [][]string{{"EMP-4", "EMP-1"}, {"EMP-2", "EMP-3"}, {"EMP-5"}}
+	for i := range want {
+		employees, err := repo.GetAll(ctx, &dto.GetEmployeesRequest{Limit: 2, Offset: i * 2, ManagerID: manager})
+		if err != nil {
+			t.Fatalf("GetAll page %d error = %v", i+1, err)
+		}
+		if got := identityNumbers(employees); !slices.Equal(got, want[i]) {
+			t.Fatalf("page %d = %v, want %v", i+1, got, want[i])
+		}
+	}
+}
@@ -0,0 +1,109 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/dto"
+)
+
+// exportFixture membuat count employee aktif di satu department, cukup
+// banyak agar cursor Export masih menyimpan baris saat iterasi dihentikan.
+func exportFixture(t *testing.T, count int) (*pgxpool.Pool, *EmployeeRepository, string) {
+	t.Helper()
+	pool, repo := newTestRepository(t)
+	managerId := dbtest.CreateManager(t, pool, "export@example.com")
+	departmentId := dbtest.CreateDepartment(t, pool, managerId, "Engineering")
+	_, err := pool.Exec(context.Background(),
+		`INSERT INTO employees (identityNumber, name, employeeImageUri, gender, departmentId, status)
+		SELECT 'EMP-' || lpad(i::text, 5, '0'), 'Budi', 'https://example.com/image.png', 'male', $1, 'active'
+		FROM generate_series(1, $2) AS i;`,
+		departmentId, count,
+	)
+	if err != nil {
+		t.Fatalf("insert employees: %v", err)
+	}
+	return pool, repo, managerId
+}
+
+// wantReleased memastikan koneksi cursor Export sudah kembali ke pool.
+func wantReleased(t *testing.T, pool *pgxpool.Pool) {
+	t.Helper()
+	if acquired := pool.Stat().AcquiredConns(); acquired != 0 {
+		t.Fatalf("acquired connections = %d after Export returned, want 0", acquired)
+	}
+}
+
+func TestExportStreamsAllRows(t *testing.T) {
+	pool, repo, managerId := exportFixture(t, 250)
+
+	var got []string
+	err := repo.Export(context.Background(), &dto.GetEmployeesRequest{ManagerID: managerId, Status: dto.EmployeeStatusActive}, func(employee dto.EmployeeResponse) error {
+		got = append(got, employee.IdentityNumber)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Export error = %v", err)
+	}
+	// Tanpa limit, urut identityNumber
+	if len(got) != 250 || got[0] != "EMP-00001" || got[249] != "EMP-00250" {
+		t.Fatalf("exported %d rows, first %v", len(got), got[:min(len(got), 3)])
+	}
+	wantReleased(t, pool)
+}
+
+func TestExportStopsOnCallbackError(t *testing.T) {
+	pool, repo, managerId := exportFixture(t, 250)
+	errWrite := errors.New("broken pipe")
+
+	rows := 0
+	err := repo.Export(context.Background(), &dto.GetEmployeesRequest{ManagerID: managerId}, func(employee dto.EmployeeResponse) error {
+		rows++
+		if rows == 10 {
+			return errWrite
+		}
+		return nil
+	})
+	if err != errWrite {
+		t.Fatalf("Export error = %v, want %v as is", err, errWrite)
+	}
+	if rows != 10 {
+		t.Fatalf("fn called %d times, want 10", rows)
+	}
+	wantReleased(t, pool)
+}
+
+func TestExportStopsOnCancel(t *testing.T) {
+	// Jauh lebih banyak dari buffer baca pgx, sehingga cursor masih
+	// menunggu data dari server saat ctx dibatalkan
+	pool, repo, managerId := exportFixture(t, 20000)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rows := 0
+	err := repo.Export(ctx, &dto.GetEmployeesRequest{ManagerID: managerId}, func(employee dto.EmployeeResponse) error {
+		rows++
+		// Client memutus koneksi di tengah export
+		if rows == 10 {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Export error = %v, want %v", err, context.Canceled)
+	}
+	if rows >= 20000 {
+		t.Fatalf("fn called %d times, export did not stop after cancel", rows)
+	}
+	wantReleased(t, pool)
+
+	// Pool masih bisa dipakai setelah cursor dibatalkan
+	if err := pool.Ping(context.Background()); err != nil {
+		t.Fatalf("ping after cancelled export: %v", err)
+	}
+}
@@ -0,0 +1,396 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/dto"
+)
+
+// listFixture berisi employee dengan kombinasi gender, department, status
+// dan hiredAt yang berbeda, ditambah satu employee milik manager lain yang
+// tidak boleh muncul di filter mana pun.
+type listFixture struct {
+	repo      *EmployeeRepository
+	trigram   bool
+	managerId string
+	// departments[0] dan departments[1] milik managerId
+	departments [2]string
+	employees   []dbtest.Employee
+}
+
+func newListFixture(t *testing.T) listFixture {
+	t.Helper()
+	pool, repo := newTestRepository(t)
+	f := listFixture{repo: repo, trigram: repo.search.TrigramAvailable}
+	f.managerId = dbtest.CreateManager(t, pool, "list@example.com")
+	f.departments[0] = dbtest.CreateDepartment(t, pool, f.managerId, "Engineering")
+	f.departments[1] = dbtest.CreateDepartment(t, pool, f.managerId, "Finance")
+	otherManager := dbtest.CreateManager(t, pool, "other@example.com")
+	otherDepartment := dbtest.CreateDepartment(t, pool, otherManager, "Engineering")
+
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	f.employees = []dbtest.Employee{
+		{IdentityNumber: "EMP-0001", Name: "Alice Smith", Gender: "female", DepartmentID: f.departments[0], HiredAt: "2020-01-15"},
+		{IdentityNumber: "EMP-0002", Name: "Bob Stone", Gender: "male", DepartmentID: f.departments[0], HiredAt: "2021-06-01"},
+		{IdentityNumber: "EMP-0003", Name: "Carol Smith", Gender: "female", DepartmentID: f.departments[1]},
+		{IdentityNumber: "EMP-0004", Name: "David Brown", Gender: "male", DepartmentID: f.departments[1], Status: dto.EmployeeStatusArchived, HiredAt: "2022-03-10"},
+		{IdentityNumber: "XYZ-0005", Name: "Eve Smithers", Gender: "female", DepartmentID: f.departments[0], Status: dto.EmployeeStatusArchived, HiredAt: "2019-12-31"},
+		{IdentityNumber: "xyz-0006", Name: "Frank Stone", Gender: "male", DepartmentID: f.departments[1], HiredAt: "2021-06-01"},
+	}
+	for i := range f.employees {
+		f.employees[i].CreatedAt = created.Add(time.Duration(len(f.employees)-i) * time.Minute)
+		dbtest.CreateEmployee(t, pool, f.employees[i])
+	}
+	dbtest.CreateEmployee(t, pool, dbtest.Employee{
+		IdentityNumber: "EMP-0009", Name: "Alice Smith", Gender: "female", DepartmentID: otherDepartment, HiredAt: "2020-01-15",
+	})
+	return f
+}
+
+// listFilter adalah satu filter GetEmployeesRequest beserta versi Go dari
+// kondisinya di employeeFilter.
+type listFilter struct {
+	name    string
+	apply   func(input *dto.GetEmployeesRequest)
+	matches func(e dbtest.Employee) bool
+}
+
+func employeeStatus(e dbtest.Employee) string {
+	if e.Status == "" {
+		return dto.EmployeeStatusActive
+	}
+	return e.Status
+}
+
+func listFilters(f listFixture) []listFilter {
+	return []listFilter{
+		{
+			name:    "identityNumber",
+			apply:   func(input *dto.GetEmployeesRequest) { input.IdentityNumber = "Xyz" },
+			matches: func(e dbtest.Employee) bool { return strings.HasPrefix(strings.ToLower(e.IdentityNumber), "xyz") },
+		},
+		{
+			name: "identityNumbers",
+			apply: func(input *dto.GetEmployeesRequest) {
+				input.IdentityNumbers = []string{"EMP-0001", "EMP-0004", "xyz-0006", "EMP-0009"}
+			},
+			matches: func(e dbtest.Employee) bool {
+				return slices.Contains([]string{"EMP-0001", "EMP-0004", "xyz-0006"}, e.IdentityNumber)
+			},
+		},
+		{
+			name:    "name",
+			apply:   func(input *dto.GetEmployeesRequest) { input.Name = "SMITH" },
+			matches: func(e dbtest.Employee) bool { return strings.Contains(strings.ToLower(e.Name), "smith") },
+		},
+		{
+			// Konfigurasi 'simple' mencocokkan kata utuh: Smithers tidak ikut
+			name:    "q",
+			apply:   func(input *dto.GetEmployeesRequest) { input.Query = "smith" },
+			matches: func(e dbtest.Employee) bool { return slices.Contains(strings.Fields(strings.ToLower(e.Name)), "smith") },
+		},
+		{
+			name:    "gender",
+			apply:   func(input *dto.GetEmployeesRequest) { input.Gender = "female" },
+			matches: func(e dbtest.Employee) bool { return e.Gender == "female" },
+		},
+		{
+			name:    "departmentId",
+			apply:   func(input *dto.GetEmployeesRequest) { input.DepartmentID = f.departments[1] },
+			matches: func(e dbtest.Employee) bool { return e.DepartmentID == f.departments[1] },
+		},
+		{
+			name:    "status",
+			apply:   func(input *dto.GetEmployeesRequest) { input.Status = dto.EmployeeStatusArchived },
+			matches: func(e dbtest.Employee) bool { return employeeStatus(e) == dto.EmployeeStatusArchived },
+		},
+		{
+			name:    "hiredFrom",
+			apply:   func(input *dto.GetEmployeesRequest) { input.HiredFrom = "2020-01-15" },
+			matches: func(e dbtest.Employee) bool { return e.HiredAt != "" && e.HiredAt >= "2020-01-15" },
+		},
+		{
+			name:    "hiredTo",
+			apply:   func(input *dto.GetEmployeesRequest) { input.HiredTo = "2021-06-01" },
+			matches: func(e dbtest.Employee) bool { return e.HiredAt != "" && e.HiredAt <= "2021-06-01" },
+		},
+	}
+}
+
+// TestGetAllFilterCombinations menjalankan GetAll dan Count untuk setiap
+// kombinasi filter dan membandingkannya dengan filter yang sama di Go.
+// Status kosong berarti tanpa batasan status, seperti pemanggil internal.
+func TestGetAllFilterCombinations(t *testing.T) {
+	f := newListFixture(t)
+	ctx := context.Background()
+	filters := listFilters(f)
+
+	for mask := 0; mask < 1<<len(filters); mask++ {
+		input := dto.GetEmployeesRequest{Limit: 100, ManagerID: f.managerId}
+		var names []string
+		var active []listFilter
+		for i, filter := range filters {
+			if mask&(1<<i) != 0 {
+				filter.apply(&input)
+				names = append(names, filter.name)
+				active = append(active, filter)
+			}
+		}
+		want := []string{}
+		for _, e := range f.employees {
+			if !slices.ContainsFunc(active, func(filter listFilter) bool { return !filter.matches(e) }) {
+				want = append(want, e.IdentityNumber)
+			}
+		}
+		slices.Sort(want)
+
+		name := strings.Join(names, "+")
+		if name == "" {
+			name = "none"
+		}
+		t.Run(name, func(t *testing.T) {
+			employees, err := f.repo.GetAll(ctx, &input)
+			if err != nil {
+				t.Fatalf("GetAll error = %v", err)
+			}
+			got := identityNumbers(employees)
+			slices.Sort(got)
+			if !slices.Equal(got, want) {
+				t.Fatalf("GetAll = %v, want %v", got, want)
+			}
+			count, err := f.repo.Count(ctx, &input)
+			if err != nil {
+				t.Fatalf("Count error = %v", err)
+			}
+			if count != int64(len(want)) {
+				t.Fatalf("Count = %d, want %d", count, len(want))
+			}
+		})
+	}
+}
+
+func TestGetAllStatus(t *testing.T) {
+	f := newListFixture(t)
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		status string
+		want   []string
+	}{
+		{status: dto.EmployeeStatusActive, want: []string{"EMP-0001", "EMP-0002", "EMP-0003", "xyz-0006"}},
+		{status: dto.EmployeeStatusArchived, want: []string{"EMP-0004", "XYZ-0005"}},
+		{status: dto.EmployeeStatusAll, want: []string{"EMP-0001", "EMP-0002", "EMP-0003", "EMP-0004", "XYZ-0005", "xyz-0006"}},
+	} {
+		t.Run(tt.status, func(t *testing.T) {
+			input := dto.GetEmployeesRequest{Limit: 100, ManagerID: f.managerId, Status: tt.status, SortBy: "identityNumber"}
+			employees, err := f.repo.GetAll(ctx, &input)
+			if err != nil {
+				t.Fatalf("GetAll error = %v", err)
+			}
+			if got := identityNumbers(employees); !slices.Equal(got, tt.want) {
+				t.Fatalf("GetAll = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetAllOrderAndPagination(t *testing.T) {
+	f := newListFixture(t)
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		name  string
+		input dto.GetEmployeesRequest
+		want  []string
+	}{
+		{
+			name:  "sortBy identityNumber",
+			input: dto.GetEmployeesRequest{SortBy: "identityNumber"},
+			want:  []string{"EMP-0001", "EMP-0002", "EMP-0003", "EMP-0004", "XYZ-0005", "xyz-0006"},
+		},
+		{
+			name:  "sortBy name",
+			input: dto.GetEmployeesRequest{SortBy: "name"},
+			want:  []string{"EMP-0001", "EMP-0002", "EMP-0003", "EMP-0004", "XYZ-0005", "xyz-0006"},
+		},
+		{
+			// CreatedAt fixture menurun, jadi urutannya terbalik
+			name:  "sortBy createdAt",
+			input: dto.GetEmployeesRequest{SortBy: "createdAt"},
+			want:  []string{"xyz-0006", "XYZ-0005", "EMP-0004", "EMP-0003", "EMP-0002", "EMP-0001"},
+		},
+		{
+			// hiredAt sama diurutkan dengan identityNumber, NULL paling akhir
+			name:  "sortBy hiredAt",
+			input: dto.GetEmployeesRequest{SortBy: "hiredAt"},
+			want:  []string{"XYZ-0005", "EMP-0001", "EMP-0002", "xyz-0006", "EMP-0004", "EMP-0003"},
+		},
+		{
+			name:  "limit and offset",
+			input: dto.GetEmployeesRequest{SortBy: "identityNumber", Limit: 2, Offset: 2},
+			want:  []string{"EMP-0003", "EMP-0004"},
+		},
+		{
+			name:  "offset past the end",
+			input: dto.GetEmployeesRequest{SortBy: "identityNumber", Limit: 2, Offset: 6},
+			want:  []string{},
+		},
+		{
+			name:  "sortBy replaces q relevance",
+			input: dto.GetEmployeesRequest{Query: "smith", SortBy: "createdAt"},
+			want:  []string{"EMP-0003", "EMP-0001"},
+		},
+		{
+			name:  "q relevance ties by identityNumber",
+			input: dto.GetEmployeesRequest{Query: "smith"},
+			want:  []string{"EMP-0001", "EMP-0003"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			input := tt.input
+			if input.Limit == 0 {
+				input.Limit = 100
+			}
+			input.ManagerID = f.managerId
+			input.Status = dto.EmployeeStatusAll
+			employees, err := f.repo.GetAll(ctx, &input)
+			if err != nil {
+				t.Fatalf("GetAll error = %v", err)
+			}
+			if got := identityNumbers(employees); !slices.Equal(got, tt.want) {
+				t.Fatalf("GetAll = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestGetAllFuzzyName memastikan fuzzy memakai similarity pg_trgm dan
+// mengurutkan yang paling mirip lebih dulu. Tanpa pg_trgm, fuzzy menjadi
+// pencarian contains biasa.
+func TestGetAllFuzzyName(t *testing.T) {
+	f := newListFixture(t)
+	ctx := context.Background()
+
+	input := dto.GetEmployeesRequest{Limit: 100, ManagerID: f.managerId, Status: dto.EmployeeStatusAll, Name: "Alise Smith", Fuzzy: true}
+	want := []string{"EMP-0001", "EMP-0003"}
+	if !f.trigram {
+		want = []string{}
+	}
+	employees, err := f.repo.GetAll(ctx, &input)
+	if err != nil {
+		t.Fatalf("GetAll error = %v", err)
+	}
+	if got := identityNumbers(employees); !slices.Equal(got, want) {
+		t.Fatalf("GetAll(trigram=%t) = %v, want %v", f.trigram, got, want)
+	}
+	count, err := f.repo.Count(ctx, &input)
+	if err != nil {
+		t.Fatalf("Count error = %v", err)
+	}
+	if count != int64(len(want)) {
+		t.Fatalf("Count = %d, want %d", count, len(want))
+	}
+}
+
+func TestGetAllReturnsEmployeeFields(t *testing.T) {
+	f := newListFixture(t)
+	input := dto.GetEmployeesRequest{Limit: 1, ManagerID: f.managerId, IdentityNumbers: []string{"EMP-0004"}}
+	employees, err := f.repo.GetAll(context.Background(), &input)
+	if err != nil {
+		t.Fatalf("GetAll error = %v", err)
+	}
+	if len(employees) != 1 {
+		t.Fatalf("GetAll returned %d employees, want 1", len(employees))
+	}
+	got := employees[0]
+	want := f.employees[3]
+	if got.Name != want.Name || got.Gender != want.Gender || got.DepartmentID != want.DepartmentID ||
+		got.Status != dto.EmployeeStatusArchived || got.HiredAt == nil || *got.HiredAt != want.HiredAt ||
+		!got.CreatedAt.Equal(want.CreatedAt) {
+		t.Fatalf("GetAll = %+v, want %+v", got, want)
+	}
+}
+
+// TestMatchingIdentityNumbers memastikan identityNumbers dicocokkan persis
+// di semua halaman, dengan filter lain tetap berlaku.
+func TestMatchingIdentityNumbers(t *testing.T) {
+	f := newListFixture(t)
+	requested := []string{"xyz-0006", "EMP-0001", "emp-0002", "EMP-0004", "EMP-0009", "EMP-0404"}
+	for _, tt := range []struct {
+		status string
+		want   []string
+	}{
+		// emp-0002 beda huruf, EMP-0009 milik manager lain
+		{status: dto.EmployeeStatusActive, want: []string{"EMP-0001", "xyz-0006"}},
+		{status: dto.EmployeeStatusArchived, want: []string{"EMP-0004"}},
+		{status: dto.EmployeeStatusAll, want: []string{"EMP-0001", "EMP-0004", "xyz-0006"}},
+	} {
+		t.Run(tt.status, func(t *testing.T) {
+			// Limit dan offset tidak berpengaruh
+			input := dto.GetEmployeesRequest{Limit: 1, Offset: 1, ManagerID: f.managerId, Status: tt.status, IdentityNumbers: requested}
+			got, err := f.repo.MatchingIdentityNumbers(context.Background(), &input)
+			if err != nil {
+				t.Fatalf("MatchingIdentityNumbers error = %v", err)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("MatchingIdentityNumbers = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestGetAllByDepartments memastikan setiap department mendapat halaman
+// employee aktifnya sendiri, diurutkan berdasarkan identityNumber.
+func TestGetAllByDepartments(t *testing.T) {
+	f := newListFixture(t)
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		name          string
+		limit, offset int
+		want          map[string][]string
+	}{
+		{name: "first page", limit: 1, want: map[string][]string{
+			f.departments[0]: {"EMP-0001"},
+			f.departments[1]: {"EMP-0003"},
+		}},
+		{name: "second page", limit: 5, offset: 1, want: map[string][]string{
+			f.departments[0]: {"EMP-0002"},
+			f.departments[1]: {"xyz-0006"},
+		}},
+		{name: "past the end", limit: 5, offset: 2, want: map[string][]string{}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			employees, err := f.repo.GetAllByDepartments(ctx, f.departments[:], f.managerId, tt.limit, tt.offset)
+			if err != nil {
+				t.Fatalf("GetAllByDepartments error = %v", err)
+			}
+			got := map[string][]string{}
+			for _, employee := range employees {
+				got[employee.DepartmentID] = append(got[employee.DepartmentID], employee.IdentityNumber)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("GetAllByDepartments = %v, want %v", got, tt.want)
+			}
+			for department, want := range tt.want {
+				if !slices.Equal(got[department], want) {
+					t.Fatalf("GetAllByDepartments = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	// Department milik manager lain tidak mengembalikan employee
+	employees, err := f.repo.GetAllByDepartments(ctx, f.departments[:], "0b7c2d2e-4f4a-4b8e-9c59-000000000000", 5, 0)
+	if err != nil || len(employees) != 0 {
+		t.Fatalf("GetAllByDepartments for another manager = %v, %v, want none", identityNumbers(employees), err)
+	}
+}
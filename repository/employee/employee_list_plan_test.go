@@ -0,0 +1,259 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/dto"
+)
+
+// joinListQuery adalah filter manager GetAll sebelum memakai EXISTS, untuk
+// membandingkan hasil dan waktunya.
+const joinListQuery = `
+	SELECT e.identityNumber
+	FROM employees AS e
+	LEFT JOIN department d ON e.departmentId = d.departmentId
+	LEFT JOIN manager m ON d.managerId = m.managerId
+	WHERE m.managerId = $1 AND e.status = 'active'
+	ORDER BY e.identityNumber
+	LIMIT $2 OFFSET 0;
+`
+
+// seedEmployees membuat managers manager, masing-masing dengan departments
+// department berisi perDepartment employee, lalu ANALYZE agar planner
+// memakai statistik data tersebut. Mengembalikan managerId urut email.
+func seedEmployees(tb testing.TB, pool *pgxpool.Pool, managers, departments, perDepartment int) []string {
+	tb.Helper()
+	ctx := context.Background()
+	_, err := pool.Exec(ctx, `
+		WITH m AS (
+			INSERT INTO manager (email, password)
+			SELECT 'seed-' || lpad(i::text, 4, '0') || '@example.com', 'password' FROM generate_series(1, $1::int) i
+			RETURNING managerid
+		), d AS (
+			INSERT INTO department (departmentname, managerid)
+			SELECT 'Department ' || j, m.managerid FROM m, generate_series(1, $2::int) j
+			RETURNING departmentid
+		)
+		INSERT INTO employees (identitynumber, name, employeeimageuri, gender, departmentid)
+		SELECT
+			'SEED-' || lpad((row_number() OVER ())::text, 8, '0'),
+			'Employee ' || k,
+			'https://example.com/image.png',
+			CASE WHEN k % 2 = 0 THEN 'male' ELSE 'female' END,
+			d.departmentid
+		FROM d, generate_series(1, $3::int) k;
+	`, managers, departments, perDepartment)
+	if err != nil {
+		tb.Fatalf("seed employees: %v", err)
+	}
+	if _, err := pool.Exec(ctx, "ANALYZE manager, department, employees;"); err != nil {
+		tb.Fatal(err)
+	}
+	rows, err := pool.Query(ctx, "SELECT managerid FROM manager WHERE email LIKE 'seed-%' ORDER BY email;")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return ids
+}
+
+func TestManagerFilterMatchesJoin(t *testing.T) {
+	pool, repo := newTestRepository(t)
+	ctx := context.Background()
+	managers := seedEmployees(t, pool, 3, 2, 5)
+	// Department tanpa manager tidak pernah cocok, baik dengan join maupun
+	// EXISTS
+	var orphan string
+	if err := pool.QueryRow(ctx, "INSERT INTO department (departmentname) VALUES ('Orphan') RETURNING departmentid;").Scan(&orphan); err != nil {
+		t.Fatal(err)
+	}
+	dbtest.CreateEmployee(t, pool, dbtest.Employee{IdentityNumber: "ORPHAN-1", Name: "Orphan", Gender: "male", DepartmentID: orphan})
+	// Department yang dihapus tetap ikut, sama dengan query sebelumnya
+	deleted := dbtest.CreateDepartment(t, pool, managers[0], "Old")
+	dbtest.CreateEmployee(t, pool, dbtest.Employee{IdentityNumber: "DELETED-1", Name: "Old", Gender: "female", DepartmentID: deleted})
+	dbtest.DeleteDepartment(t, pool, deleted)
+	const unknown = "5d1f0c7a-2b3e-4c4d-8e9f-0a1b2c3d4e5f"
+
+	for _, managerId := range append(managers, unknown) {
+		rows, err := pool.Query(ctx, joinListQuery, managerId, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		input := dto.GetEmployeesRequest{ManagerID: managerId, Limit: 100, Status: dto.EmployeeStatusActive, SortBy: "identityNumber"}
+		employees, err := repo.GetAll(ctx, &input)
+		if err != nil {
+			t.Fatalf("GetAll error = %v", err)
+		}
+		if got := identityNumbers(employees); !slices.Equal(got, want) {
+			t.Fatalf("manager %s: GetAll = %v, join = %v", managerId, got, want)
+		}
+		count, err := repo.Count(ctx, &input)
+		if err != nil || count != int64(len(want)) {
+			t.Fatalf("manager %s: Count = %d, %v, want %d", managerId, count, err, len(want))
+		}
+	}
+}
+
+// planNode adalah node EXPLAIN (FORMAT JSON) yang dibutuhkan test.
+type planNode struct {
+	NodeType     string     `json:"Node Type"`
+	RelationName string     `json:"Relation Name"`
+	IndexName    string     `json:"Index Name"`
+	Plans        []planNode `json:"Plans"`
+}
+
+// scans mengembalikan node scan untuk relation, termasuk yang ada di
+// subplan.
+func (n planNode) scans(relation string) []planNode {
+	var nodes []planNode
+	if n.RelationName == relation {
+		nodes = append(nodes, n)
+	}
+	for _, child := range n.Plans {
+		nodes = append(nodes, child.scans(relation)...)
+	}
+	return nodes
+}
+
+// indexes mengembalikan nama index yang dipakai node ini dan subplan-nya.
+// Bitmap Index Scan tidak punya Relation Name, jadi index GIN hanya
+// terlihat di sini, bukan di scans.
+func (n planNode) indexes() []string {
+	var names []string
+	if n.IndexName != "" {
+		names = append(names, n.IndexName)
+	}
+	for _, child := range n.Plans {
+		names = append(names, child.indexes()...)
+	}
+	return names
+}
+
+// explainCount menjalankan EXPLAIN untuk COUNT dengan filter GetAll dari
+// input dan mengembalikan plan beserta JSON mentahnya untuk pesan error.
+func explainCount(t *testing.T, pool *pgxpool.Pool, repo *EmployeeRepository, input *dto.GetEmployeesRequest) (planNode, []byte) {
+	t.Helper()
+	filter := repo.employeeFilter(input)
+	var plan []struct {
+		Plan planNode `json:"Plan"`
+	}
+	query := fmt.Sprintf("EXPLAIN (FORMAT JSON) SELECT COUNT(*) %s %s", employeeFromClause, filter.SQL())
+	var raw []byte
+	if err := pool.QueryRow(context.Background(), query, filter.Args()...).Scan(&raw); err != nil {
+		t.Fatalf("explain: %v", err)
+	}
+	if err := json.Unmarshal(raw, &plan); err != nil || len(plan) != 1 {
+		t.Fatalf("decode plan %s: %v", raw, err)
+	}
+	return plan[0].Plan, raw
+}
+
+func TestManagerFilterUsesDepartmentIndex(t *testing.T) {
+	pool, repo := newTestRepository(t)
+	// Satu manager hanya memiliki 1/50 employee
+	managers := seedEmployees(t, pool, 50, 4, 100)
+
+	plan, raw := explainCount(t, pool, repo, &dto.GetEmployeesRequest{ManagerID: managers[0], Status: dto.EmployeeStatusActive})
+	scans := plan.scans("employees")
+	if len(scans) == 0 {
+		t.Fatalf("plan %s has no scan on employees", raw)
+	}
+	for _, scan := range scans {
+		if scan.NodeType == "Seq Scan" {
+			t.Fatalf("plan %s scans employees sequentially", raw)
+		}
+	}
+	if !slices.ContainsFunc(scans, func(n planNode) bool { return n.IndexName == "employees_departmentid_idx" }) {
+		t.Fatalf("plan %s does not use employees_departmentid_idx", raw)
+	}
+}
+
+// TestSearchFiltersUseIndexes memastikan pencarian prefix identityNumber
+// memakai index text_pattern_ops dari migration 0001 dan pencarian q
+// memakai index GIN name_tsv dari migration 0002. Satu manager memiliki
+// semua employee agar filter manager tidak selektif dan hanya index
+// pencarian yang bisa menghindari seq scan.
+func TestSearchFiltersUseIndexes(t *testing.T) {
+	pool, repo := newTestRepository(t)
+	managers := seedEmployees(t, pool, 1, 4, 5000)
+
+	tests := []struct {
+		name  string
+		input dto.GetEmployeesRequest
+		index string
+	}{
+		{
+			name:  "identityNumber prefix",
+			input: dto.GetEmployeesRequest{IdentityNumber: "seed-0000012"},
+			index: "employees_identitynumber_lower_pattern_idx",
+		},
+		{
+			name:  "full-text name",
+			input: dto.GetEmployeesRequest{Query: "17"},
+			index: "employees_name_tsv_idx",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.input.ManagerID = managers[0]
+			tt.input.Status = dto.EmployeeStatusActive
+			plan, raw := explainCount(t, pool, repo, &tt.input)
+
+			for _, scan := range plan.scans("employees") {
+				if scan.NodeType == "Seq Scan" {
+					t.Fatalf("plan %s scans employees sequentially", raw)
+				}
+			}
+			if !slices.Contains(plan.indexes(), tt.index) {
+				t.Fatalf("plan %s does not use %s", raw, tt.index)
+			}
+		})
+	}
+}
+
+// BenchmarkGetAllManagerFilter membandingkan query join sebelumnya dengan
+// GetAll pada 50 manager x 4 department x 100 employee.
+func BenchmarkGetAllManagerFilter(b *testing.B) {
+	pool := dbtest.New(b)
+	repo := NewEmployeeRepository(pool, pool, config.LoadQueryTimeoutConfig(), &config.SearchConfig{})
+	ctx := context.Background()
+	managers := seedEmployees(b, pool, 50, 4, 100)
+
+	b.Run("join", func(b *testing.B) {
+		for i := range b.N {
+			rows, err := pool.Query(ctx, joinListQuery, managers[i%len(managers)], dto.DefaultLimit)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err := pgx.CollectRows(rows, pgx.RowTo[string]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("exists", func(b *testing.B) {
+		for i := range b.N {
+			input := dto.GetEmployeesRequest{ManagerID: managers[i%len(managers)], Limit: dto.DefaultLimit, Status: dto.EmployeeStatusActive, SortBy: "identityNumber"}
+			if _, err := repo.GetAll(ctx, &input); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
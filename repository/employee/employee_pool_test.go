@@ -0,0 +1,71 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/breaker"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+// TestSaturatedPoolIsServiceUnavailable memenuhi pool berukuran 1 dengan
+// query yang sedang sleep. Request berikutnya yang habis waktunya saat
+// menunggu koneksi harus menjadi ErrServiceUnavailable (503), bukan 500 atau
+// ErrQueryTimeout. Pool dibungkus database.GuardedPool seperti di aplikasi,
+// karena GuardedPool yang menandai timeout saat Acquire.
+func TestSaturatedPoolIsServiceUnavailable(t *testing.T) {
+	shared := dbtest.New(t)
+	manager := dbtest.CreateManager(t, shared, "pool@example.com")
+
+	poolConfig := shared.Config()
+	poolConfig.MaxConns = 1
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	guarded := database.NewGuardedPool(pool, breaker.New(breaker.Settings{
+		Name:             "postgres",
+		FailureThreshold: 5,
+		Cooldown:         time.Minute,
+		IsSuccessful:     func(err error) bool { return !helper.IsConnectionError(err) },
+	}))
+	repo := NewEmployeeRepository(guarded, guarded, config.LoadQueryTimeoutConfig(), &config.SearchConfig{})
+
+	sleepCtx, stopSleep := context.WithCancel(context.Background())
+	sleeping := make(chan error, 1)
+	go func() {
+		_, err := pool.Exec(sleepCtx, "SELECT pg_sleep(30);")
+		sleeping <- err
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for pool.Stat().AcquiredConns() < 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("sleeping query did not acquire the only connection")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err = repo.GetAll(ctx, &dto.GetEmployeesRequest{Limit: 10, ManagerID: manager})
+	if !errors.Is(err, helper.ErrServiceUnavailable) {
+		t.Fatalf("GetAll on a saturated pool error = %v, want %v", err, helper.ErrServiceUnavailable)
+	}
+
+	stopSleep()
+	<-sleeping
+	// Setelah koneksi kembali ke pool, query yang sama berhasil
+	if _, err := repo.GetAll(context.Background(), &dto.GetEmployeesRequest{Limit: 10, ManagerID: manager}); err != nil {
+		t.Fatalf("GetAll after the pool freed up error = %v", err)
+	}
+}
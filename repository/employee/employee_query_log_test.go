@@ -0,0 +1,70 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	clientmodel "github.com/prometheus/client_model/go"
+)
+
+// discardLogger membuang log QueryLogTracer; package mocks tidak bisa
+// dipakai di sini karena mengimpor repository.
+type discardLogger struct{}
+
+func (discardLogger) Info(msg string, function helper.FunctionCaller, data ...interface{})  {}
+func (discardLogger) Error(msg string, function helper.FunctionCaller, data ...interface{}) {}
+func (discardLogger) Debug(msg string, function helper.FunctionCaller, data ...interface{}) {}
+func (discardLogger) Warn(msg string, function helper.FunctionCaller, data ...interface{})  {}
+func (l discardLogger) With(fields map[string]any) logger.Logger                            { return l }
+func (l discardLogger) WithContext(ctx context.Context) logger.Logger                       { return l }
+
+// TestQueryLogTracerNamesGetAll menjalankan GetAll di pool yang memakai
+// QueryLogTracer dan memastikan durasinya tercatat dengan nama query
+// GetAll, bukan sebagai query tanpa nama.
+func TestQueryLogTracerNamesGetAll(t *testing.T) {
+	shared := dbtest.New(t)
+	manager := dbtest.CreateManager(t, shared, "query-log@example.com")
+	department := dbtest.CreateDepartment(t, shared, manager, "Engineering")
+	dbtest.CreateEmployee(t, shared, dbtest.Employee{IdentityNumber: "EMP-1", DepartmentID: department})
+
+	appMetrics := metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{})
+	poolConfig := shared.Config()
+	poolConfig.ConnConfig.Tracer = database.NewQueryLogTracer(discardLogger{}, appMetrics, 1)
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	repo := NewEmployeeRepository(pool, pool, config.LoadQueryTimeoutConfig(), &config.SearchConfig{})
+
+	employees, err := repo.GetAll(context.Background(), &dto.GetEmployeesRequest{Limit: 10, ManagerID: manager})
+	if err != nil || len(employees) != 1 {
+		t.Fatalf("GetAll = %d employees, %v", len(employees), err)
+	}
+
+	if count := testutil.CollectAndCount(appMetrics.QueryDuration); count != 1 {
+		t.Fatalf("query duration has %d series, want only %s", count, queryEmployeeGetAll)
+	}
+	var metric clientmodel.Metric
+	if err := appMetrics.QueryDuration.WithLabelValues(string(queryEmployeeGetAll)).(prometheus.Metric).Write(&metric); err != nil {
+		t.Fatal(err)
+	}
+	if got := metric.GetHistogram().GetSampleCount(); got != 1 {
+		t.Fatalf("duration samples for %s = %d, want 1", queryEmployeeGetAll, got)
+	}
+	if count := testutil.CollectAndCount(appMetrics.QueryErrors); count != 0 {
+		t.Fatalf("query errors has %d series after a successful GetAll", count)
+	}
+}
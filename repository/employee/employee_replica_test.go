@@ -0,0 +1,100 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/dto"
+)
+
+// countingQuerier meneruskan query ke database dan menghitung berapa
+// query yang dijalankan lewat dirinya.
+type countingQuerier struct {
+	database.Querier
+	queries int
+}
+
+func (q *countingQuerier) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	q.queries++
+	return q.Querier.Exec(ctx, sql, args...)
+}
+
+func (q *countingQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	q.queries++
+	return q.Querier.Query(ctx, sql, args...)
+}
+
+func (q *countingQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	q.queries++
+	return q.Querier.QueryRow(ctx, sql, args...)
+}
+
+// TestReplicaRouting memastikan query daftar dilayani reader, sedangkan
+// write dan read-after-write tetap di primary.
+func TestReplicaRouting(t *testing.T) {
+	pool := dbtest.New(t)
+	manager := dbtest.CreateManager(t, pool, "replica@example.com")
+	department := dbtest.CreateDepartment(t, pool, manager, "Engineering")
+	dbtest.CreateEmployee(t, pool, dbtest.Employee{IdentityNumber: "EMP-1", DepartmentID: department})
+
+	tests := []struct {
+		name       string
+		call       func(ctx context.Context, repo *EmployeeRepository) error
+		wantReader bool
+	}{
+		{name: "GetAll", wantReader: true, call: func(ctx context.Context, repo *EmployeeRepository) error {
+			_, err := repo.GetAll(ctx, &dto.GetEmployeesRequest{Limit: 10, ManagerID: manager})
+			return err
+		}},
+		{name: "Count", wantReader: true, call: func(ctx context.Context, repo *EmployeeRepository) error {
+			_, err := repo.Count(ctx, &dto.GetEmployeesRequest{ManagerID: manager})
+			return err
+		}},
+		{name: "Stats", wantReader: true, call: func(ctx context.Context, repo *EmployeeRepository) error {
+			_, err := repo.Stats(ctx, manager)
+			return err
+		}},
+		{name: "GetForUpdate", wantReader: false, call: func(ctx context.Context, repo *EmployeeRepository) error {
+			_, err := repo.GetForUpdate(ctx, "EMP-1", manager)
+			return err
+		}},
+		{name: "IsIdentityNumberAvailable", wantReader: false, call: func(ctx context.Context, repo *EmployeeRepository) error {
+			return repo.IsIdentityNumberAvailable(ctx, "EMP-2")
+		}},
+		{name: "Create", wantReader: false, call: func(ctx context.Context, repo *EmployeeRepository) error {
+			input := dto.EmployeePayload{
+				IdentityNumber:   "EMP-3",
+				Name:             "Budi",
+				EmployeeImageUri: "https://example.com/budi.png",
+				Gender:           "male",
+				DepartmentID:     department,
+			}
+			_, err := repo.Create(ctx, &input, manager)
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &countingQuerier{Querier: pool}
+			reader := &countingQuerier{Querier: pool}
+			repo := NewEmployeeRepository(writer, reader, config.LoadQueryTimeoutConfig(), &config.SearchConfig{})
+
+			if err := tt.call(context.Background(), &repo); err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if tt.wantReader && (reader.queries == 0 || writer.queries != 0) {
+				t.Fatalf("reader queries = %d, writer queries = %d, want reader only", reader.queries, writer.queries)
+			}
+			if !tt.wantReader && (writer.queries == 0 || reader.queries != 0) {
+				t.Fatalf("reader queries = %d, writer queries = %d, want writer only", reader.queries, writer.queries)
+			}
+		})
+	}
+}
@@ -2,67 +2,174 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"slices"
 	"strings"
+	"time"
 
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/levensspel/go-gin-template/cache"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
 	"github.com/levensspel/go-gin-template/dto"
 	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/pagination"
 	"github.com/samber/do/v2"
 )
 
+// Nama query untuk log dan metric, lihat database.QueryLogTracer
+const (
+	queryEmployeeIsDepartmentOwnedByManager database.QueryName = "employee.is_department_owned_by_manager"
+	queryEmployeeIsIdentityNumberAvailable  database.QueryName = "employee.is_identity_number_available"
+	queryEmployeeInsert                     database.QueryName = "employee.insert"
+	queryEmployeeCreate                     database.QueryName = "employee.create"
+	queryEmployeeUpdate                     database.QueryName = "employee.update"
+	queryEmployeeMoveDepartment             database.QueryName = "employee.move_department"
+	queryEmployeeDelete                     database.QueryName = "employee.delete"
+	queryEmployeeGetForUpdate               database.QueryName = "employee.get_for_update"
+	queryEmployeeGetAll                     database.QueryName = "employee.get_all"
+	queryEmployeeCount                      database.QueryName = "employee.count"
+	queryEmployeeMatchingIdentityNumbers    database.QueryName = "employee.matching_identity_numbers"
+	queryEmployeeGetAllAfter                database.QueryName = "employee.get_all_after"
+	queryEmployeeInsertBatch                database.QueryName = "employee.insert_batch"
+	queryEmployeeCopy                       database.QueryName = "employee.copy"
+	queryEmployeeCreateMany                 database.QueryName = "employee.create_many"
+	queryEmployeeGetAllByDepartments        database.QueryName = "employee.get_all_by_departments"
+	queryEmployeeGetAllInDepartment         database.QueryName = "employee.get_all_in_department"
+	queryEmployeeExport                     database.QueryName = "employee.export"
+	queryEmployeeGetForUpsert               database.QueryName = "employee.get_for_upsert"
+	queryEmployeeUpdateBatch                database.QueryName = "employee.update_batch"
+	queryEmployeeSetStatus                  database.QueryName = "employee.set_status"
+	queryEmployeeStats                      database.QueryName = "employee.stats"
+	queryEmployeeAddVersion                 database.QueryName = "employee.add_version"
+	queryEmployeeGetVersions                database.QueryName = "employee.get_versions"
+	queryEmployeeGetVersion                 database.QueryName = "employee.get_version"
+)
+
 type EmployeeRepository struct {
-	db *pgxpool.Pool
+	db database.Querier
+	// reader dipakai untuk query daftar yang boleh dilayani read replica
+	reader   database.Querier
+	timeouts *config.QueryTimeoutConfig
+	search   *config.SearchConfig
+}
+
+// EmployeeRepositoryInterface berisi method EmployeeRepository yang dipakai
+// service, sehingga service bisa dites dengan mocks.EmployeeRepository.
+type EmployeeRepositoryInterface interface {
+	Create(ctx context.Context, input *dto.EmployeePayload, managerId string) (dto.EmployeeResponse, error)
+	Update(ctx context.Context, identityNumber string, input *dto.EmployeeUpdatePayload, managerId string) (dto.EmployeeResponse, error)
+	IsIdentityNumberAvailable(ctx context.Context, identityNumber string) error
+	GetAll(ctx context.Context, input *dto.GetEmployeesRequest) ([]dto.EmployeeResponse, error)
+	Count(ctx context.Context, input *dto.GetEmployeesRequest) (int64, error)
+	MatchingIdentityNumbers(ctx context.Context, input *dto.GetEmployeesRequest) ([]string, error)
+	InsertBatch(ctx context.Context, pool database.Querier, inputs []dto.EmployeePayload, managerId string) ([]bool, error)
+	CopyEmployees(ctx context.Context, pool database.Querier, source pgx.CopyFromSource, managerId string) (copied int64, inserted int64, err error)
+	CreateMany(ctx context.Context, pool database.Querier, inputs []dto.EmployeePayload, managerId string) ([]dto.EmployeeResponse, []error, error)
+	MoveDepartment(ctx context.Context, fromDepartmentId, toDepartmentId, managerId string) ([]dto.EmployeeResponse, error)
+	GetAllInDepartment(ctx context.Context, departmentId string, managerId string) ([]dto.EmployeeResponse, error)
+	Delete(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error)
+	SetStatus(ctx context.Context, identityNumber string, status string, managerId string) (dto.EmployeeResponse, error)
+	Stats(ctx context.Context, managerId string) ([]dto.DepartmentEmployeeStats, error)
+	AddVersion(ctx context.Context, identityNumber string, action string, managerId string) error
+	GetVersions(ctx context.Context, input *dto.GetEmployeeHistoryRequest) ([]dto.EmployeeVersion, error)
+	GetVersion(ctx context.Context, identityNumber string, version int, managerId string) (dto.EmployeeVersion, error)
+	GetForUpdate(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error)
+	GetAllByDepartments(ctx context.Context, departmentIds []string, managerId string, limit int, offset int) ([]dto.EmployeeResponse, error)
+	Export(ctx context.Context, input *dto.GetEmployeesRequest, fn func(dto.EmployeeResponse) error) error
+	GetForUpsert(ctx context.Context, pool database.Querier, identityNumbers []string, lock bool) (map[string]ExistingEmployee, error)
+	UpdateBatch(ctx context.Context, pool database.Querier, inputs []dto.EmployeePayload, managerId string) ([]bool, error)
 }
 
-func NewEmployeeRepository(db *pgxpool.Pool) EmployeeRepository {
-	return EmployeeRepository{db: db}
+// ExistingEmployee adalah employee yang sudah ada beserta manager pemilik
+// department-nya, lihat GetForUpsert.
+type ExistingEmployee struct {
+	Employee  dto.EmployeeResponse
+	ManagerID string
 }
 
-func NewEmployeeRepositoryInject(i do.Injector) (EmployeeRepository, error) {
-	db := do.MustInvoke[*pgxpool.Pool](i)
-	return NewEmployeeRepository(db), nil
+func NewEmployeeRepository(
+	db database.Querier,
+	reader database.Querier,
+	timeouts *config.QueryTimeoutConfig,
+	search *config.SearchConfig,
+) EmployeeRepository {
+	return EmployeeRepository{db: db, reader: reader, timeouts: timeouts, search: search}
 }
 
-func (r *EmployeeRepository) IsDepartmentOwnedByManager(ctx context.Context, pool *pgxpool.Tx, departmentId, managerId string) error {
-	query := "SELECT 1 FROM department WHERE departmentId = $1 AND managerId = $2;"
+func NewEmployeeRepositoryInject(i do.Injector) (EmployeeRepositoryInterface, error) {
+	cluster := do.MustInvoke[*database.Cluster](i)
+	search := do.MustInvoke[*config.SearchConfig](i)
+	repo := NewEmployeeRepository(cluster.Writer(), cluster.Reader(), config.LoadQueryTimeoutConfig(), search)
+	return &repo, nil
+}
+
+// IsDepartmentOwnedByManager memakai cache kepemilikan department yang
+// di-invalidate lewat NOTIFY, lihat departmentRepository.NewOwnershipListenerInject.
+// Department yang sudah dihapus dianggap bukan milik manager.
+func (r *EmployeeRepository) IsDepartmentOwnedByManager(ctx context.Context, pool database.Querier, departmentId, managerId string) error {
+	if owner, found := cache.GetDepartmentOwner(departmentId); found && owner == managerId {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Lookup)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryEmployeeIsDepartmentOwnedByManager)
+
+	query := "SELECT 1 FROM department WHERE departmentId = $1 AND managerId = $2 AND isdeleted = FALSE;"
 
 	rows, err := pool.Exec(ctx, query, departmentId, managerId)
 	if err != nil {
-		return err
+		return database.QueryError(ctx, err)
 	}
 
 	if rows.RowsAffected() < 1 {
-		return helper.ErrInvalidDepartmentId
+		return database.QueryError(ctx, helper.ErrInvalidDepartmentId)
 	}
 
+	cache.SetDepartmentOwner(departmentId, managerId)
 	return nil
 }
 
-func (r *EmployeeRepository) IsIdentityNumberAvailable(ctx context.Context, pool *pgxpool.Tx, identityNumber, managerId string) error {
-	query := `
-		SELECT 1 
-		FROM employees e
-		JOIN department d
-		ON e.departmentId = d.departmentId
-		WHERE
-			identityNumber = $1
-			AND managerId = $2;
-	`
-	rows, err := r.db.Exec(ctx, query, identityNumber, managerId)
+// IsIdentityNumberAvailable mengecek apakah identity number belum dipakai.
+// Unique constraint identityNumber berlaku global, sehingga pengecekan ini
+// tidak dibatasi per manager. Jangan dipakai sebelum insert, karena hasilnya
+// bisa basi saat insert dijalankan; Insert sudah menangani konflik sendiri.
+func (r *EmployeeRepository) IsIdentityNumberAvailable(ctx context.Context, identityNumber string) error {
+	return r.isIdentityNumberAvailable(ctx, r.db, identityNumber)
+}
+
+// isIdentityNumberAvailable seperti IsIdentityNumberAvailable, tetapi
+// dijalankan di pool, mis. transaksi pemanggil.
+func (r *EmployeeRepository) isIdentityNumberAvailable(ctx context.Context, pool database.Querier, identityNumber string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Lookup)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryEmployeeIsIdentityNumberAvailable)
+
+	query := "SELECT 1 FROM employees WHERE identityNumber = $1;"
+	rows, err := pool.Exec(ctx, query, identityNumber)
 	if err != nil {
-		return err
+		return database.QueryError(ctx, err)
 	}
 
 	if rows.RowsAffected() > 0 {
-		return helper.ErrConflictIdentityNumber
+		return database.QueryError(ctx, helper.ErrConflictIdentityNumber)
 	}
 
 	return nil
 }
 
-func (r *EmployeeRepository) Insert(ctx context.Context, pool *pgxpool.Tx, input *dto.EmployeePayload, managerId string) error {
+// Insert mengandalkan unique constraint identityNumber alih-alih pengecekan
+// terpisah, sehingga dua insert bersamaan tidak bisa sama-sama lolos. Jika
+// tidak ada baris yang di-insert, identity number dicek sekali lagi untuk
+// membedakan konflik dengan department yang tidak valid.
+func (r *EmployeeRepository) Insert(ctx context.Context, pool database.Querier, input *dto.EmployeePayload, managerId string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryEmployeeInsert)
+
 	// Check if department ID is owned by the valid manager
 	// altogether with the insertion only if its valid within single query.
 	query := `
@@ -71,11 +178,18 @@ func (r *EmployeeRepository) Insert(ctx context.Context, pool *pgxpool.Tx, input
 			name,
 			employeeImageUri,
 			gender,
-			departmentId
+			departmentId,
+			hired_at
+		)
+		SELECT $1, $2, $3, $4, $5, $7::date
+		WHERE EXISTS (
+			SELECT 1 FROM department WHERE departmentId = $5 AND managerId = $6 AND isdeleted = FALSE
 		)
-		VALUES ($1, $2, $3, $4, $5);
+		ON CONFLICT (identityNumber) DO NOTHING
+		RETURNING id;
 	`
-	rows, err := pool.Exec(
+	var id string
+	err := pool.QueryRow(
 		ctx,
 		query,
 		input.IdentityNumber,
@@ -83,41 +197,69 @@ func (r *EmployeeRepository) Insert(ctx context.Context, pool *pgxpool.Tx, input
 		input.EmployeeImageUri,
 		input.Gender,
 		input.DepartmentID,
-	)
+		managerId,
+		input.HiredAt,
+	).Scan(&id)
 
-	if err != nil {
-		return err
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Dicek di transaksi yang sama, agar baris yang di-insert sebelumnya
+		// oleh transaksi ini ikut terlihat. Statement baru di READ COMMITTED
+		// juga melihat baris yang baru di-commit transaksi lain.
+		if err := r.isIdentityNumberAvailable(ctx, pool, input.IdentityNumber); err != nil {
+			return err
+		}
+		return database.QueryError(ctx, helper.ErrInvalidDepartmentId)
 	}
-
-	if rows.RowsAffected() < 1 {
-		return helper.ErrInvalidDepartmentId
+	if err != nil {
+		return database.QueryError(ctx, err)
 	}
 
 	return nil
 }
 
-func (r *EmployeeRepository) Create(ctx context.Context, input *dto.EmployeePayload, managerId string) error {
+// Create memvalidasi department dan meng-insert employee dalam satu
+// statement. Statement mengembalikan hasil pengecekan department dan status
+// insert, sehingga nol baris ter-insert bisa dibedakan: department bukan
+// milik manager (ErrInvalidDepartmentId) atau identity number sudah dipakai
+// (ErrConflictIdentityNumber). Error lain dari database dikembalikan apa
+// adanya.
+func (r *EmployeeRepository) Create(ctx context.Context, input *dto.EmployeePayload, managerId string) (dto.EmployeeResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryEmployeeCreate)
+
 	// Check if department ID is owned by the valid manager
 	// altogether with the insertion only if its valid within single query.
 	query := `
 		WITH valid_department AS (
+			SELECT EXISTS (
 				SELECT 1
 				FROM department
-				WHERE departmentId = $5 AND managerId = $6
-		)
-		INSERT INTO employees (
-			identityNumber,
-			name,
-			employeeImageUri,
-			gender,
-			departmentId
+				WHERE departmentId = $5 AND managerId = $6 AND isdeleted = FALSE
+			) AS ok
+		), inserted AS (
+			INSERT INTO employees (
+				identityNumber,
+				name,
+				employeeImageUri,
+				gender,
+				departmentId,
+				hired_at
+			)
+			SELECT $1, $2, $3, $4, $5, $7::date
+			FROM valid_department
+			WHERE valid_department.ok
+			ON CONFLICT (identityNumber) DO NOTHING
+			RETURNING created_at, updated_at
 		)
-		SELECT $1, $2, $3, $4, $5
-		FROM valid_department
-		WHERE EXISTS (SELECT 1 FROM valid_department);
+		SELECT v.ok, i.created_at, i.updated_at
+		FROM valid_department v
+		LEFT JOIN inserted i ON TRUE;
 	`
 
-	rows, err := r.db.Exec(
+	var departmentValid bool
+	var createdAt, updatedAt *time.Time
+	err := r.db.QueryRow(
 		ctx,
 		query,
 		input.IdentityNumber,
@@ -126,97 +268,1241 @@ func (r *EmployeeRepository) Create(ctx context.Context, input *dto.EmployeePayl
 		input.Gender,
 		input.DepartmentID,
 		managerId,
+		input.HiredAt,
+	).Scan(&departmentValid, &createdAt, &updatedAt)
+
+	if err != nil {
+		return dto.EmployeeResponse{}, database.QueryError(ctx, err)
+	}
+
+	if !departmentValid {
+		return dto.EmployeeResponse{}, database.QueryError(ctx, helper.ErrInvalidDepartmentId)
+	}
+	// Department valid tetapi tidak ada baris yang masuk hanya mungkin
+	// karena ON CONFLICT
+	if createdAt == nil {
+		return dto.EmployeeResponse{}, database.QueryError(ctx, helper.ErrConflictIdentityNumber)
+	}
+
+	return dto.EmployeeResponse{
+		EmployeePayload: *input,
+		CreatedAt:       *createdAt,
+		UpdatedAt:       *updatedAt,
+		Status:          dto.EmployeeStatusActive,
+	}, nil
+}
+
+// Update mengubah field yang dikirim pada employee milik manager dan selalu
+// memperbarui updated_at. Klausa SET hanya berisi field yang tidak nil;
+// employeeImageUri yang dikirim null dikosongkan dan hiredAt yang dikirim
+// null menjadi NULL. Jika departmentId ikut
+// diubah, department baru juga harus milik manager dan belum dihapus.
+func (r *EmployeeRepository) Update(
+	ctx context.Context,
+	identityNumber string,
+	input *dto.EmployeeUpdatePayload,
+	managerId string,
+) (dto.EmployeeResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryEmployeeUpdate)
+
+	filter := &database.Filter{}
+	var sets []string
+	set := func(column string, value *string) {
+		if value != nil {
+			sets = append(sets, fmt.Sprintf("%s = $%d", column, filter.Arg(*value)))
+		}
+	}
+	set("identityNumber", input.IdentityNumber)
+	set("name", input.Name)
+	if input.EmployeeImageUri == nil && input.IsNull("employeeImageUri") {
+		set("employeeImageUri", new(string))
+	} else {
+		set("employeeImageUri", input.EmployeeImageUri)
+	}
+	set("gender", input.Gender)
+	set("departmentId", input.DepartmentID)
+	if input.HiredAt != nil {
+		sets = append(sets, fmt.Sprintf("hired_at = $%d::date", filter.Arg(*input.HiredAt)))
+	} else if input.IsNull("hiredAt") {
+		sets = append(sets, "hired_at = NULL")
+	}
+	sets = append(sets, "updated_at = CURRENT_TIMESTAMP")
+
+	filter.Where("e.departmentId = d.departmentId AND d.managerId = $%d", managerId)
+	filter.Where("e.identityNumber = $%d", identityNumber)
+	if input.DepartmentID != nil {
+		filter.Where("EXISTS (SELECT 1 FROM department WHERE departmentId = $%d AND managerId = $%d AND isdeleted = FALSE)", *input.DepartmentID, managerId)
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE employees e
+		SET %s
+		FROM department d
+		%s
+		RETURNING
+			e.identityNumber,
+			e.name,
+			e.employeeImageUri,
+			e.gender,
+			e.departmentId,
+			e.created_at,
+			e.updated_at,
+			e.status,
+			to_char(e.hired_at, 'YYYY-MM-DD');
+	`, strings.Join(sets, ", "), filter.SQL())
+	var employee dto.EmployeeResponse
+	err := r.db.QueryRow(ctx, query, filter.Args()...).Scan(
+		&employee.IdentityNumber,
+		&employee.Name,
+		&employee.EmployeeImageUri,
+		&employee.Gender,
+		&employee.DepartmentID,
+		&employee.CreatedAt,
+		&employee.UpdatedAt,
+		&employee.Status,
+		&employee.HiredAt,
 	)
 
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return dto.EmployeeResponse{}, database.QueryError(ctx, helper.ErrConflictIdentityNumber)
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Bedakan employee yang tidak ditemukan dengan department baru yang
+		// bukan milik manager
+		if input.DepartmentID != nil {
+			if err := r.isDepartmentOwned(ctx, *input.DepartmentID, managerId); err != nil {
+				return dto.EmployeeResponse{}, err
+			}
+		}
+		return dto.EmployeeResponse{}, database.QueryError(ctx, helper.ErrNotFound)
+	}
 	if err != nil {
-		return err
+		return dto.EmployeeResponse{}, database.QueryError(ctx, err)
+	}
+
+	return employee, nil
+}
+
+// GetForUpdate mengambil dan mengunci employee milik manager sampai
+// transaksinya selesai, mis. untuk data sebelum update di audit log.
+func (r *EmployeeRepository) GetForUpdate(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Lookup)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryEmployeeGetForUpdate)
+
+	query := `
+		SELECT
+			e.identityNumber,
+			e.name,
+			e.employeeImageUri,
+			e.gender,
+			e.departmentId,
+			e.created_at,
+			e.updated_at,
+			e.status,
+			to_char(e.hired_at, 'YYYY-MM-DD')
+		FROM employees e
+		JOIN department d ON e.departmentId = d.departmentId
+		WHERE
+			d.managerId = $1
+			AND e.identityNumber = $2
+		FOR UPDATE OF e;
+	`
+	var employee dto.EmployeeResponse
+	err := r.db.QueryRow(ctx, query, managerId, identityNumber).Scan(
+		&employee.IdentityNumber,
+		&employee.Name,
+		&employee.EmployeeImageUri,
+		&employee.Gender,
+		&employee.DepartmentID,
+		&employee.CreatedAt,
+		&employee.UpdatedAt,
+		&employee.Status,
+		&employee.HiredAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return dto.EmployeeResponse{}, database.QueryError(ctx, helper.ErrNotFound)
+	}
+	if err != nil {
+		return dto.EmployeeResponse{}, database.QueryError(ctx, err)
+	}
+	return employee, nil
+}
+
+// Delete menghapus employee milik manager dan mengembalikan data yang
+// dihapus, atau helper.ErrNotFound.
+func (r *EmployeeRepository) Delete(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryEmployeeDelete)
+
+	query := `
+		DELETE FROM employees e
+		USING department d
+		WHERE
+			e.departmentId = d.departmentId
+			AND d.managerId = $1
+			AND e.identityNumber = $2
+		RETURNING
+			e.identityNumber,
+			e.name,
+			e.employeeImageUri,
+			e.gender,
+			e.departmentId,
+			e.created_at,
+			e.updated_at,
+			e.status,
+			to_char(e.hired_at, 'YYYY-MM-DD');
+	`
+	var employee dto.EmployeeResponse
+	err := r.db.QueryRow(ctx, query, managerId, identityNumber).Scan(
+		&employee.IdentityNumber,
+		&employee.Name,
+		&employee.EmployeeImageUri,
+		&employee.Gender,
+		&employee.DepartmentID,
+		&employee.CreatedAt,
+		&employee.UpdatedAt,
+		&employee.Status,
+		&employee.HiredAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return dto.EmployeeResponse{}, database.QueryError(ctx, helper.ErrNotFound)
+	}
+	if err != nil {
+		return dto.EmployeeResponse{}, database.QueryError(ctx, err)
+	}
+	return employee, nil
+}
+
+// SetStatus mengubah status employee milik manager, lihat
+// dto.EmployeeStatusActive, dan mengembalikan datanya atau helper.ErrNotFound.
+func (r *EmployeeRepository) SetStatus(ctx context.Context, identityNumber string, status string, managerId string) (dto.EmployeeResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryEmployeeSetStatus)
+
+	query := `
+		UPDATE employees e
+		SET
+			status = $3,
+			updated_at = CURRENT_TIMESTAMP
+		FROM department d
+		WHERE
+			e.departmentId = d.departmentId
+			AND d.managerId = $1
+			AND e.identityNumber = $2
+		RETURNING
+			e.identityNumber,
+			e.name,
+			e.employeeImageUri,
+			e.gender,
+			e.departmentId,
+			e.created_at,
+			e.updated_at,
+			e.status,
+			to_char(e.hired_at, 'YYYY-MM-DD');
+	`
+	rows, err := r.db.Query(ctx, query, managerId, identityNumber, status)
+	if err != nil {
+		return dto.EmployeeResponse{}, database.QueryError(ctx, err)
+	}
+	employee, err := pgx.CollectExactlyOneRow(rows, scanEmployeeResponse)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return dto.EmployeeResponse{}, database.QueryError(ctx, helper.ErrNotFound)
+	}
+	if err != nil {
+		return dto.EmployeeResponse{}, database.QueryError(ctx, err)
+	}
+	return employee, nil
+}
+
+// Stats menghitung employee aktif dan yang diarsipkan per department milik
+// manager, termasuk department tanpa employee, diurutkan berdasarkan id.
+func (r *EmployeeRepository) Stats(ctx context.Context, managerId string) ([]dto.DepartmentEmployeeStats, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryEmployeeStats)
+
+	query := `
+		SELECT
+			d.departmentId,
+			COUNT(e.id) FILTER (WHERE e.status = 'active'),
+			COUNT(e.id) FILTER (WHERE e.status = 'archived')
+		FROM department d
+		LEFT JOIN employees e ON e.departmentId = d.departmentId
+		WHERE
+			d.managerId = $1
+			AND d.isdeleted = FALSE
+		GROUP BY d.departmentId
+		ORDER BY d.departmentId;
+	`
+	rows, err := r.reader.Query(ctx, query, managerId)
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	stats, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (dto.DepartmentEmployeeStats, error) {
+		var stat dto.DepartmentEmployeeStats
+		err := row.Scan(&stat.DepartmentID, &stat.Active, &stat.Archived)
+		return stat, err
+	})
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	return stats, nil
+}
+
+// AddVersion menyimpan data employee milik manager saat ini sebagai versi
+// berikutnya di employee_versions, dengan changedBy manager tersebut. Baris
+// employee dikunci sampai transaksi selesai, sehingga nomor versi yang sama
+// tidak bisa dipakai dua kali. Mengembalikan helper.ErrNotFound jika
+// employee tidak ditemukan.
+func (r *EmployeeRepository) AddVersion(ctx context.Context, identityNumber string, action string, managerId string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryEmployeeAddVersion)
+
+	query := `
+		INSERT INTO employee_versions (
+			employee_id,
+			version,
+			action,
+			identityNumber,
+			name,
+			employeeImageUri,
+			gender,
+			departmentId,
+			hired_at,
+			status,
+			changed_by
+		)
+		SELECT
+			e.id,
+			COALESCE((SELECT MAX(v.version) FROM employee_versions v WHERE v.employee_id = e.id), 0) + 1,
+			$3,
+			e.identityNumber,
+			e.name,
+			e.employeeImageUri,
+			e.gender,
+			e.departmentId,
+			e.hired_at,
+			e.status,
+			$1
+		FROM employees e
+		JOIN department d ON e.departmentId = d.departmentId
+		WHERE
+			d.managerId = $1
+			AND e.identityNumber = $2
+		FOR UPDATE OF e;
+	`
+	tag, err := r.db.Exec(ctx, query, managerId, identityNumber, action)
+	if err != nil {
+		return database.QueryError(ctx, err)
+	}
+	if tag.RowsAffected() < 1 {
+		return database.QueryError(ctx, helper.ErrNotFound)
+	}
+	return nil
+}
+
+// employeeVersionColumns adalah kolom employee_versions dengan urutan
+// scanEmployeeVersion.
+const employeeVersionColumns = `
+	v.version,
+	v.action,
+	v.identityNumber,
+	v.name,
+	v.employeeImageUri,
+	v.gender,
+	v.departmentId,
+	to_char(v.hired_at, 'YYYY-MM-DD'),
+	v.status,
+	v.changed_by,
+	v.changed_at`
+
+// GetVersions mengambil versi employee milik manager, terbaru lebih dulu.
+// Versi mengikuti employee walau identityNumber-nya berubah. Mengembalikan
+// helper.ErrNotFound jika employee tidak ditemukan; employee yang dibuat
+// sebelum riwayat dicatat bisa tidak punya versi.
+func (r *EmployeeRepository) GetVersions(ctx context.Context, input *dto.GetEmployeeHistoryRequest) ([]dto.EmployeeVersion, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryEmployeeGetVersions)
+
+	var employeeId string
+	err := r.reader.QueryRow(ctx, `
+		SELECT e.id
+		FROM employees e
+		JOIN department d ON e.departmentId = d.departmentId
+		WHERE
+			d.managerId = $1
+			AND e.identityNumber = $2;
+	`, input.ManagerID, input.IdentityNumber).Scan(&employeeId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, database.QueryError(ctx, helper.ErrNotFound)
+	}
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM employee_versions v
+		WHERE v.employee_id = $1
+		ORDER BY v.version DESC
+		LIMIT $2 OFFSET $3;
+	`, employeeVersionColumns)
+	rows, err := r.reader.Query(ctx, query, employeeId, input.Limit, input.Offset)
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	versions, err := pgx.CollectRows(rows, scanEmployeeVersion)
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	return versions, nil
+}
+
+// GetVersion mengambil satu versi employee milik manager, atau
+// helper.ErrNotFound. Versi tidak pernah diubah, jadi tidak dikunci.
+func (r *EmployeeRepository) GetVersion(ctx context.Context, identityNumber string, version int, managerId string) (dto.EmployeeVersion, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Lookup)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryEmployeeGetVersion)
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM employee_versions v
+		JOIN employees e ON e.id = v.employee_id
+		JOIN department d ON e.departmentId = d.departmentId
+		WHERE
+			d.managerId = $1
+			AND e.identityNumber = $2
+			AND v.version = $3;
+	`, employeeVersionColumns)
+	rows, err := r.db.Query(ctx, query, managerId, identityNumber, version)
+	if err != nil {
+		return dto.EmployeeVersion{}, database.QueryError(ctx, err)
+	}
+	employeeVersion, err := pgx.CollectExactlyOneRow(rows, scanEmployeeVersion)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return dto.EmployeeVersion{}, database.QueryError(ctx, helper.ErrNotFound)
+	}
+	if err != nil {
+		return dto.EmployeeVersion{}, database.QueryError(ctx, err)
+	}
+	return employeeVersion, nil
+}
+
+func scanEmployeeVersion(row pgx.CollectableRow) (dto.EmployeeVersion, error) {
+	var version dto.EmployeeVersion
+	err := row.Scan(
+		&version.Version,
+		&version.Action,
+		&version.IdentityNumber,
+		&version.Name,
+		&version.EmployeeImageUri,
+		&version.Gender,
+		&version.DepartmentID,
+		&version.HiredAt,
+		&version.Status,
+		&version.ChangedBy,
+		&version.ChangedAt,
+	)
+	return version, err
+}
+
+// MoveDepartment memindahkan semua employee dari satu department ke
+// department lain milik manager yang sama dan mengembalikan employee yang
+// dipindahkan, dengan data setelah dipindahkan. Kedua department harus
+// belum dihapus.
+func (r *EmployeeRepository) MoveDepartment(ctx context.Context, fromDepartmentId, toDepartmentId, managerId string) ([]dto.EmployeeResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	if err := r.isDepartmentOwned(ctx, toDepartmentId, managerId); err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE employees e
+		SET
+			departmentId = $2,
+			updated_at = CURRENT_TIMESTAMP
+		FROM department d
+		WHERE
+			e.departmentId = d.departmentId
+			AND d.departmentId = $1
+			AND d.managerId = $3
+			AND d.isdeleted = FALSE
+		RETURNING
+			e.identityNumber,
+			e.name,
+			e.employeeImageUri,
+			e.gender,
+			e.departmentId,
+			e.created_at,
+			e.updated_at,
+			e.status,
+			to_char(e.hired_at, 'YYYY-MM-DD');
+	`
+	ctx = database.WithQueryName(ctx, queryEmployeeMoveDepartment)
+	rows, err := r.db.Query(ctx, query, fromDepartmentId, toDepartmentId, managerId)
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	employees, err := pgx.CollectRows(rows, scanEmployeeResponse)
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	return employees, nil
+}
+
+// GetAllInDepartment mengambil dan mengunci semua employee di department
+// milik manager sampai transaksinya selesai, termasuk yang diarsipkan,
+// diurutkan berdasarkan identityNumber.
+func (r *EmployeeRepository) GetAllInDepartment(ctx context.Context, departmentId string, managerId string) ([]dto.EmployeeResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryEmployeeGetAllInDepartment)
+
+	query := `
+		SELECT
+			e.identityNumber,
+			e.name,
+			e.employeeImageUri,
+			e.gender,
+			e.departmentId,
+			e.created_at,
+			e.updated_at,
+			e.status,
+			to_char(e.hired_at, 'YYYY-MM-DD')
+		FROM employees e
+		JOIN department d ON e.departmentId = d.departmentId
+		WHERE
+			d.departmentId = $1
+			AND d.managerId = $2
+		ORDER BY e.identityNumber
+		FOR UPDATE OF e;
+	`
+	rows, err := r.db.Query(ctx, query, departmentId, managerId)
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	employees, err := pgx.CollectRows(rows, scanEmployeeResponse)
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	return employees, nil
+}
+
+func (r *EmployeeRepository) isDepartmentOwned(ctx context.Context, departmentId, managerId string) error {
+	if owner, found := cache.GetDepartmentOwner(departmentId); found && owner == managerId {
+		return nil
+	}
+	ctx = database.WithQueryName(ctx, queryEmployeeIsDepartmentOwnedByManager)
+
+	query := "SELECT 1 FROM department WHERE departmentId = $1 AND managerId = $2 AND isdeleted = FALSE;"
+	rows, err := r.db.Exec(ctx, query, departmentId, managerId)
+	if err != nil {
+		return database.QueryError(ctx, err)
 	}
 
 	if rows.RowsAffected() < 1 {
-		return helper.ErrInvalidDepartmentId
+		return database.QueryError(ctx, helper.ErrInvalidDepartmentId)
 	}
 
+	cache.SetDepartmentOwner(departmentId, managerId)
 	return nil
 }
 
-func (r *EmployeeRepository) GetAll(ctx context.Context, input *dto.GetEmployeesRequest) ([]dto.EmployeePayload, error) {
-	// Membuat query dinamis
-	query := "SELECT e.identityNumber, e.name, e.employeeImageUri, e.gender, e.departmentId" // 'e' refer to 'employee e' which will be appended later
-	conditions := "WHERE m.managerId = $1"                                                   // 'u' refer to 'manager u' which will be appended later
-	argIndex := 2
-	var args []interface{}
-	args = append(args, input.ManagerID)
-
-	// `SELECT
-	// 	e.identity_number,
-	// 	e.name,
-	// 	e.image_uri,
-	// 	e.gender,
-	// 	e.department_id
-	// FROM employees
-	// WHERE
-	//  manager_id = $1
-	// 	identity_number ILIKE $2%
-	// 	AND name ILIKE %$3%
-	// 	AND gender = $4
-	// 	AND department_id = $5
-	// LIMIT $5
-	// OFFSET $6`
+// employeeFromClause dipakai bersama oleh GetAll dan Count. 'e' refer to
+// 'employee e'. Kepemilikan manager dicek employeeFilter lewat EXISTS ke
+// department, tanpa join ke manager.
+const employeeFromClause = "FROM employees AS e"
+
+// employeeFilter menyusun kondisi WHERE untuk GetAll dan Count. EXISTS
+// memakai primary key department, lalu employees_departmentid_idx untuk
+// mengambil employee per department milik manager.
+//
+//	WHERE
+//		EXISTS (SELECT 1 FROM department d WHERE d.departmentId = e.departmentId AND d.managerId = $1)
+//		AND e.identitynumber_lower LIKE $2 || '%'
+//		AND e.identityNumber = ANY($3)
+//		AND e.name ILIKE '%' || $3 || '%'
+//		AND e.name_tsv @@ websearch_to_tsquery('simple', $4)
+//		AND e.gender = $5
+//		AND e.departmentId = $6
+//		AND e.status = $7
+//		AND e.hired_at >= $8::date
+//		AND e.hired_at <= $9::date
+func (r *EmployeeRepository) employeeFilter(input *dto.GetEmployeesRequest) *database.Filter {
+	filter := &database.Filter{}
+	filter.Where("EXISTS (SELECT 1 FROM department d WHERE d.departmentId = e.departmentId AND d.managerId = $%d)", input.ManagerID)
 
 	if input.IdentityNumber != "" {
-		args = append(args, input.IdentityNumber)
-		conditions += fmt.Sprintf(" AND LOWER(e.identityNumber) ILIKE $%d || '%s'", argIndex, "%") // eg. AND identity_number ILIKE $2 || '%'
-		argIndex++
+		// identitynumber_lower memakai index text_pattern_ops, lihat
+		// database/migrations/0001_employees_identity_number_prefix_index.sql
+		filter.Where("e.identitynumber_lower LIKE $%d || '%%'", strings.ToLower(input.IdentityNumber))
+	}
+	if len(input.IdentityNumbers) > 0 {
+		filter.Where("e.identityNumber = ANY($%d)", input.IdentityNumbers)
 	}
-	if input.Name != "" {
-		args = append(args, input.Name)
-		conditions += fmt.Sprintf(" AND e.name ILIKE '%s' || $%d || '%s'", "%", argIndex, "%") // eg. AND name ILIKE %$2%
-		argIndex++
+	if input.Name != "" && r.fuzzyName(input) {
+		filter.Where("e.name %% $%d AND similarity(e.name, $%d) >= $%d", input.Name, input.Name, r.search.FuzzyThreshold)
+	} else if input.Name != "" {
+		filter.Where("e.name ILIKE '%%' || $%d || '%%'", input.Name)
+	}
+	if input.Query != "" {
+		filter.Where("e.name_tsv @@ websearch_to_tsquery('simple', $%d)", input.Query)
 	}
 	if input.Gender != "" {
-		args = append(args, input.Gender)
-		conditions += fmt.Sprintf(" AND e.gender = $%d", argIndex)
-		argIndex++
+		filter.Where("e.gender = $%d", input.Gender)
 	}
 	if input.DepartmentID != "" {
-		args = append(args, input.DepartmentID)
-		conditions += fmt.Sprintf(" AND e.departmentId = $%d", argIndex)
-		argIndex++
+		filter.Where("e.departmentId = $%d", input.DepartmentID)
+	}
+	if input.Status != "" && input.Status != dto.EmployeeStatusAll {
+		filter.Where("e.status = $%d", input.Status)
+	}
+	// Employee tanpa hired_at tidak lolos filter hiredFrom/hiredTo
+	if input.HiredFrom != "" {
+		filter.Where("e.hired_at >= $%d::date", input.HiredFrom)
 	}
-	query = strings.TrimRight(query, ",") + " FROM employees AS e LEFT JOIN department d ON e.departmentId = d.departmentId LEFT JOIN manager m ON d.managerId = m.managerId "
+	if input.HiredTo != "" {
+		filter.Where("e.hired_at <= $%d::date", input.HiredTo)
+	}
+	return filter
+}
+
+// employeeSortColumns memetakan dto.EmployeeSortFields ke kolomnya. Nilai
+// sortBy sudah divalidasi, tetapi hanya kolom di map ini yang masuk ke SQL.
+// Employee tanpa hired_at diurutkan paling akhir.
+var employeeSortColumns = map[string]string{
+	"identityNumber": "e.identityNumber",
+	"name":           "e.name",
+	"createdAt":      "e.created_at",
+	"hiredAt":        "e.hired_at NULLS LAST",
+}
+
+// fuzzyName bernilai true jika pencarian nama memakai pg_trgm. Tanpa
+// pg_trgm, fuzzy=true diperlakukan sebagai pencarian contains biasa.
+func (r *EmployeeRepository) fuzzyName(input *dto.GetEmployeesRequest) bool {
+	return input.Fuzzy && r.search.TrigramAvailable
+}
+
+// Count menghitung employee dengan filter yang sama persis dengan GetAll,
+// tanpa limit dan offset.
+func (r *EmployeeRepository) Count(ctx context.Context, input *dto.GetEmployeesRequest) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryEmployeeCount)
+
+	filter := r.employeeFilter(input)
+	query := fmt.Sprintf("SELECT COUNT(*) %s %s;", employeeFromClause, filter.SQL())
 
-	args = append(args, input.Limit)
-	conditions += fmt.Sprintf(" LIMIT $%d", argIndex)
-	argIndex++
+	var count int64
+	if err := r.reader.QueryRow(ctx, query, filter.Args()...).Scan(&count); err != nil {
+		return 0, database.QueryError(ctx, err)
+	}
+	return count, nil
+}
 
-	args = append(args, input.Offset)
-	conditions += fmt.Sprintf(" OFFSET $%d;", argIndex)
+// MatchingIdentityNumbers mengembalikan identityNumber semua employee yang
+// cocok dengan filter GetAll, tanpa limit dan offset. Hanya dipakai dengan
+// IdentityNumbers, sehingga hasilnya paling banyak sebanyak nilai tersebut.
+func (r *EmployeeRepository) MatchingIdentityNumbers(ctx context.Context, input *dto.GetEmployeesRequest) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryEmployeeMatchingIdentityNumbers)
 
-	query += conditions
+	filter := r.employeeFilter(input)
+	query := fmt.Sprintf("SELECT e.identityNumber %s %s;", employeeFromClause, filter.SQL())
 
-	rows, err := r.db.Query(ctx, query, args...)
+	rows, err := r.reader.Query(ctx, query, filter.Args()...)
 	if err != nil {
-		log.Fatalf("Query failed: %v\n", err)
-		return nil, err
+		return nil, database.QueryError(ctx, err)
+	}
+	identityNumbers, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	return identityNumbers, nil
+}
+
+func (r *EmployeeRepository) GetAll(ctx context.Context, input *dto.GetEmployeesRequest) ([]dto.EmployeeResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryEmployeeGetAll)
+
+	// Membuat query dinamis
+	filter := r.employeeFilter(input)
+	// Dengan q atau fuzzy, hasil yang paling relevan ditampilkan lebih dulu
+	var ranks []string
+	if input.Query != "" {
+		ranks = append(ranks, fmt.Sprintf("ts_rank(e.name_tsv, websearch_to_tsquery('simple', $%d)) DESC", filter.Arg(input.Query)))
+	}
+	if input.Name != "" && r.fuzzyName(input) {
+		ranks = append(ranks, fmt.Sprintf("similarity(e.name, $%d) DESC", filter.Arg(input.Name)))
+	}
+	// Tanpa sortBy dan relevansi, urutan default sama dengan GetAllAfter.
+	// identityNumber unik sebagai tiebreaker, sehingga halaman berbasis
+	// offset (dan cache halaman pertama) selalu berisi baris yang sama
+	orderBy := "ORDER BY e.created_at, e.identityNumber"
+	if column, ok := employeeSortColumns[input.SortBy]; ok {
+		// sortBy eksplisit menggantikan urutan relevansi
+		orderBy = "ORDER BY " + column + ", e.identityNumber"
+	} else if len(ranks) > 0 {
+		orderBy = "ORDER BY " + strings.Join(ranks, ", ") + ", e.identityNumber"
+	}
+	query := fmt.Sprintf(
+		"SELECT e.identityNumber, e.name, e.employeeImageUri, e.gender, e.departmentId, e.created_at, e.updated_at, e.status, to_char(e.hired_at, 'YYYY-MM-DD') %s %s %s LIMIT $%d OFFSET $%d;",
+		employeeFromClause,
+		filter.SQL(),
+		orderBy,
+		filter.Arg(input.Limit),
+		filter.Arg(input.Offset),
+	)
+	args := filter.Args()
+
+	rows, err := r.reader.Query(ctx, query, args...)
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
 	}
 	defer rows.Close()
 
-	var employees []dto.EmployeePayload
+	var employees []dto.EmployeeResponse
 	for rows.Next() {
-		var employee dto.EmployeePayload
+		var employee dto.EmployeeResponse
 		err := rows.Scan(
 			&employee.IdentityNumber,
 			&employee.Name,
 			&employee.EmployeeImageUri,
 			&employee.Gender,
 			&employee.DepartmentID,
+			&employee.CreatedAt,
+			&employee.UpdatedAt,
+			&employee.Status,
+			&employee.HiredAt,
 		)
 		if err != nil {
-			log.Printf("Failed to scan row: %v\n", err)
-			return nil, err
+			return nil, database.QueryError(ctx, err)
 		}
 		employees = append(employees, employee)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+
+	return employees, nil
+}
+
+// GetAllAfter adalah GetAll dengan keyset pagination: offset diabaikan dan
+// baris diurutkan berdasarkan (created_at, identityNumber). Cursor Backward
+// mengambil baris sebelum cursor; hasilnya tetap dikembalikan dengan urutan
+// naik agar halaman sebelumnya tampil sama seperti saat maju.
+func (r *EmployeeRepository) GetAllAfter(
+	ctx context.Context,
+	input *dto.GetEmployeesRequest,
+	cursor pagination.Cursor,
+) ([]dto.EmployeeResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryEmployeeGetAllAfter)
+
+	comparison, order := ">", "ASC"
+	if cursor.IsBackward() {
+		comparison, order = "<", "DESC"
+	}
+
+	filter := r.employeeFilter(input)
+	if !cursor.IsZero() {
+		filter.Where("(e.created_at, e.identityNumber) "+comparison+" ($%d, $%d)", cursor.CreatedAt, cursor.IdentityNumber)
+	}
+	query := fmt.Sprintf(
+		"SELECT e.identityNumber, e.name, e.employeeImageUri, e.gender, e.departmentId, e.created_at, e.updated_at, e.status, to_char(e.hired_at, 'YYYY-MM-DD') %s %s ORDER BY e.created_at %s, e.identityNumber %s LIMIT $%d;",
+		employeeFromClause,
+		filter.SQL(),
+		order,
+		order,
+		filter.Arg(input.Limit),
+	)
+
+	rows, err := r.reader.Query(ctx, query, filter.Args()...)
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	employees, err := pgx.CollectRows(rows, scanEmployeeResponse)
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+
+	if cursor.IsBackward() {
+		slices.Reverse(employees)
+	}
+	return employees, nil
+}
+
+// GetAllByDepartments mengambil satu halaman employee untuk setiap
+// department di departmentIds dalam satu query, diurutkan per department
+// berdasarkan identityNumber. limit dan offset berlaku per department.
+// Employee yang diarsipkan tidak diambil.
+func (r *EmployeeRepository) GetAllByDepartments(
+	ctx context.Context,
+	departmentIds []string,
+	managerId string,
+	limit int,
+	offset int,
+) ([]dto.EmployeeResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryEmployeeGetAllByDepartments)
 
+	query := `
+		SELECT identityNumber, name, employeeImageUri, gender, departmentId, created_at, updated_at, status, hired_at
+		FROM (
+			SELECT
+				e.identityNumber, e.name, e.employeeImageUri, e.gender, e.departmentId, e.created_at, e.updated_at, e.status,
+				to_char(e.hired_at, 'YYYY-MM-DD') AS hired_at,
+				ROW_NUMBER() OVER (PARTITION BY e.departmentId ORDER BY e.identityNumber) AS position
+			FROM employees AS e
+			JOIN department d ON e.departmentId = d.departmentId
+			WHERE
+				e.departmentId = ANY($1)
+				AND d.managerId = $2
+				AND e.status = 'active'
+		) AS ranked
+		WHERE position > $3 AND position <= $3 + $4
+		ORDER BY departmentId, position;
+	`
+	rows, err := r.reader.Query(ctx, query, departmentIds, managerId, offset, limit)
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	employees, err := pgx.CollectRows(rows, scanEmployeeResponse)
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
 	return employees, nil
 }
+
+// Export menjalankan filter GetAll tanpa limit dan offset, lalu memanggil fn
+// untuk setiap baris langsung dari cursor, sehingga seluruh hasil tidak
+// pernah ditampung di memori. Iterasi berhenti dan rows ditutup jika fn
+// mengembalikan error (error tersebut dikembalikan apa adanya) atau ctx
+// dibatalkan, mis. client export memutus koneksi.
+func (r *EmployeeRepository) Export(ctx context.Context, input *dto.GetEmployeesRequest, fn func(dto.EmployeeResponse) error) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Export)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryEmployeeExport)
+
+	filter := r.employeeFilter(input)
+	orderBy := "e.identityNumber"
+	if column, ok := employeeSortColumns[input.SortBy]; ok {
+		orderBy = column + ", e.identityNumber"
+	}
+	query := fmt.Sprintf(
+		"SELECT e.identityNumber, e.name, e.employeeImageUri, e.gender, e.departmentId, e.created_at, e.updated_at, e.status, to_char(e.hired_at, 'YYYY-MM-DD') %s %s ORDER BY %s;",
+		employeeFromClause,
+		filter.SQL(),
+		orderBy,
+	)
+
+	rows, err := r.reader.Query(ctx, query, filter.Args()...)
+	if err != nil {
+		return database.QueryError(ctx, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		employee, err := scanEmployeeResponse(rows)
+		if err != nil {
+			return database.QueryError(ctx, err)
+		}
+		if err := fn(employee); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return database.QueryError(ctx, err)
+	}
+	return nil
+}
+
+// scanEmployeeResponse membaca kolom employee dengan urutan SELECT daftar,
+// diakhiri status dan hired_at. hired_at dibaca sebagai teks YYYY-MM-DD
+// lewat to_char, dan NULL menjadi HiredAt nil.
+func scanEmployeeResponse(row pgx.CollectableRow) (dto.EmployeeResponse, error) {
+	var employee dto.EmployeeResponse
+	err := row.Scan(
+		&employee.IdentityNumber,
+		&employee.Name,
+		&employee.EmployeeImageUri,
+		&employee.Gender,
+		&employee.DepartmentID,
+		&employee.CreatedAt,
+		&employee.UpdatedAt,
+		&employee.Status,
+		&employee.HiredAt,
+	)
+	return employee, err
+}
+
+// InsertBatch meng-insert beberapa employee dalam satu round trip lewat
+// pgx.Batch. Baris dengan department yang bukan milik manager atau identity
+// number yang sudah terpakai tidak di-insert, ditandai false pada hasilnya.
+func (r *EmployeeRepository) InsertBatch(ctx context.Context, pool database.Querier, inputs []dto.EmployeePayload, managerId string) ([]bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryEmployeeInsertBatch)
+
+	query := `
+		INSERT INTO employees (
+			identityNumber,
+			name,
+			employeeImageUri,
+			gender,
+			departmentId,
+			hired_at
+		)
+		SELECT $1, $2, $3, $4, $5, $7::date
+		WHERE EXISTS (
+			SELECT 1
+			FROM department
+			WHERE departmentId = $5 AND managerId = $6 AND isdeleted = FALSE
+		)
+		ON CONFLICT (identityNumber) DO NOTHING;
+	`
+
+	batch := &pgx.Batch{}
+	for i := range inputs {
+		batch.Queue(
+			query,
+			inputs[i].IdentityNumber,
+			inputs[i].Name,
+			inputs[i].EmployeeImageUri,
+			inputs[i].Gender,
+			inputs[i].DepartmentID,
+			managerId,
+			inputs[i].HiredAt,
+		)
+	}
+
+	results := pool.SendBatch(ctx, batch)
+	defer results.Close()
+
+	inserted := make([]bool, len(inputs))
+	for i := range inputs {
+		tag, err := results.Exec()
+		if err != nil {
+			return nil, database.QueryError(ctx, err)
+		}
+		inserted[i] = tag.RowsAffected() > 0
+	}
+
+	return inserted, database.QueryError(ctx, results.Close())
+}
+
+// CopyEmployees mengirim baris dari source lewat protokol COPY ke tabel
+// staging sementara, lalu memindahkannya ke employees dengan validasi
+// department dan identity number yang sama seperti InsertBatch. Tidak
+// memakai timeout repository; durasinya dibatasi deadline import di ctx.
+func (r *EmployeeRepository) CopyEmployees(ctx context.Context, pool database.Querier, source pgx.CopyFromSource, managerId string) (copied int64, inserted int64, err error) {
+	ctx = database.WithQueryName(ctx, queryEmployeeCopy)
+
+	_, err = pool.Exec(ctx, `
+		CREATE TEMP TABLE employees_import (
+			identityNumber varchar(255) NOT NULL,
+			name varchar(255) NOT NULL,
+			employeeImageUri varchar(255) NOT NULL,
+			gender varchar(6) NOT NULL,
+			departmentId varchar NOT NULL
+		) ON COMMIT DROP;
+	`)
+	if err != nil {
+		return 0, 0, database.QueryError(ctx, err)
+	}
+
+	copied, err = pool.CopyFrom(
+		ctx,
+		pgx.Identifier{"employees_import"},
+		[]string{"identitynumber", "name", "employeeimageuri", "gender", "departmentid"},
+		source,
+	)
+	if err != nil {
+		return copied, 0, database.QueryError(ctx, err)
+	}
+
+	// Baris yang masuk tidak dikembalikan ke service, jadi rujukan file
+	// ditambah di statement yang sama seperti FileRepository.AdjustReferences
+	err = pool.QueryRow(ctx, `
+		WITH inserted AS (
+			INSERT INTO employees (
+				identityNumber,
+				name,
+				employeeImageUri,
+				gender,
+				departmentId
+			)
+			SELECT s.identityNumber, s.name, s.employeeImageUri, s.gender, s.departmentId
+			FROM employees_import s
+			JOIN department d ON d.departmentId = s.departmentId
+			WHERE d.managerId = $1 AND d.isdeleted = FALSE
+			ON CONFLICT (identityNumber) DO NOTHING
+			RETURNING employeeImageUri
+		), referenced AS (
+			UPDATE file f
+			SET referencecount = f.referencecount + i.count, unreferencedsince = NULL
+			FROM (
+				SELECT employeeImageUri, count(*) AS count
+				FROM inserted
+				GROUP BY employeeImageUri
+			) i
+			WHERE f.fileuri = i.employeeImageUri
+		)
+		SELECT count(*) FROM inserted;
+	`, managerId).Scan(&inserted)
+	if err != nil {
+		return copied, 0, database.QueryError(ctx, err)
+	}
+
+	return copied, inserted, nil
+}
+
+// GetForUpsert mengambil employee dengan identityNumber di identityNumbers,
+// tanpa dibatasi manager karena identityNumber unik global; ManagerID
+// dipakai pemanggil untuk menolak employee milik manager lain. Jika lock,
+// barisnya dikunci sampai transaksi selesai.
+func (r *EmployeeRepository) GetForUpsert(ctx context.Context, pool database.Querier, identityNumbers []string, lock bool) (map[string]ExistingEmployee, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryEmployeeGetForUpsert)
+
+	query := `
+		SELECT
+			e.identityNumber,
+			e.name,
+			e.employeeImageUri,
+			e.gender,
+			e.departmentId,
+			e.created_at,
+			e.updated_at,
+			to_char(e.hired_at, 'YYYY-MM-DD'),
+			COALESCE(d.managerId, '')
+		FROM employees e
+		LEFT JOIN department d ON d.departmentId = e.departmentId
+		WHERE e.identityNumber = ANY($1)
+	`
+	if lock {
+		query += " FOR UPDATE OF e"
+	}
+	rows, err := pool.Query(ctx, query, identityNumbers)
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	defer rows.Close()
+
+	existing := make(map[string]ExistingEmployee, len(identityNumbers))
+	for rows.Next() {
+		var row ExistingEmployee
+		err := rows.Scan(
+			&row.Employee.IdentityNumber,
+			&row.Employee.Name,
+			&row.Employee.EmployeeImageUri,
+			&row.Employee.Gender,
+			&row.Employee.DepartmentID,
+			&row.Employee.CreatedAt,
+			&row.Employee.UpdatedAt,
+			&row.Employee.HiredAt,
+			&row.ManagerID,
+		)
+		if err != nil {
+			return nil, database.QueryError(ctx, err)
+		}
+		existing[row.Employee.IdentityNumber] = row
+	}
+	if err := rows.Err(); err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	return existing, nil
+}
+
+// UpdateBatch mengganti name, employeeImageUri, gender, departmentId, dan hiredAt
+// beberapa employee milik manager dalam satu round trip lewat pgx.Batch,
+// berdasarkan identityNumber. Employee yang bukan milik manager atau dengan
+// department baru yang bukan milik manager tidak diubah, ditandai false
+// pada hasilnya.
+func (r *EmployeeRepository) UpdateBatch(ctx context.Context, pool database.Querier, inputs []dto.EmployeePayload, managerId string) ([]bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryEmployeeUpdateBatch)
+
+	query := `
+		UPDATE employees e
+		SET
+			name = $2,
+			employeeImageUri = $3,
+			gender = $4,
+			departmentId = $5,
+			hired_at = $7::date,
+			updated_at = CURRENT_TIMESTAMP
+		FROM department d
+		WHERE
+			e.departmentId = d.departmentId
+			AND d.managerId = $6
+			AND e.identityNumber = $1
+			AND EXISTS (SELECT 1 FROM department WHERE departmentId = $5 AND managerId = $6 AND isdeleted = FALSE);
+	`
+
+	batch := &pgx.Batch{}
+	for i := range inputs {
+		batch.Queue(
+			query,
+			inputs[i].IdentityNumber,
+			inputs[i].Name,
+			inputs[i].EmployeeImageUri,
+			inputs[i].Gender,
+			inputs[i].DepartmentID,
+			managerId,
+			inputs[i].HiredAt,
+		)
+	}
+
+	results := pool.SendBatch(ctx, batch)
+	defer results.Close()
+
+	updated := make([]bool, len(inputs))
+	for i := range inputs {
+		tag, err := results.Exec()
+		if err != nil {
+			return nil, database.QueryError(ctx, err)
+		}
+		updated[i] = tag.RowsAffected() > 0
+	}
+
+	return updated, database.QueryError(ctx, results.Close())
+}
+
+// ValidateAndInsert menjalankan pengecekan department, pengecekan identity
+// number, dan insert dalam satu round trip. Hasilnya sama dengan
+// IsDepartmentOwnedByManager lalu Insert.
+func (r *EmployeeRepository) ValidateAndInsert(ctx context.Context, pool database.Querier, input *dto.EmployeePayload, managerId string) error {
+	_, errs, err := r.CreateMany(ctx, pool, []dto.EmployeePayload{*input}, managerId)
+	if err != nil {
+		return err
+	}
+	return errs[0]
+}
+
+// CreateMany meng-insert beberapa employee dalam satu pgx.Batch. Setiap
+// employee mengantrikan tiga statement (cek department, cek identity
+// number, insert bersyarat) yang hasilnya dibaca berurutan. Employee yang
+// ter-insert dikembalikan seperti yang tersimpan (termasuk createdAt,
+// updatedAt, dan status) di posisi yang sama dengan inputs; error per
+// employee dikembalikan di slice kedua, dan error terakhir berarti batch
+// gagal total.
+func (r *EmployeeRepository) CreateMany(ctx context.Context, pool database.Querier, inputs []dto.EmployeePayload, managerId string) ([]dto.EmployeeResponse, []error, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryEmployeeCreateMany)
+
+	departmentQuery := `
+		SELECT EXISTS (
+			SELECT 1 FROM department WHERE departmentId = $1 AND managerId = $2 AND isdeleted = FALSE
+		);
+	`
+	identityNumberQuery := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM employees e
+			JOIN department d
+			ON e.departmentId = d.departmentId
+			WHERE
+				identityNumber = $1
+				AND managerId = $2
+		);
+	`
+	// Insert hanya terjadi jika kedua pengecekan di atas lolos, karena
+	// statement dalam batch tetap dijalankan walau hasil sebelumnya gagal.
+	insertQuery := `
+		INSERT INTO employees (
+			identityNumber,
+			name,
+			employeeImageUri,
+			gender,
+			departmentId,
+			hired_at
+		)
+		SELECT $1, $2, $3, $4, $5, $7::date
+		WHERE EXISTS (
+			SELECT 1 FROM department WHERE departmentId = $5 AND managerId = $6 AND isdeleted = FALSE
+		)
+		AND NOT EXISTS (
+			SELECT 1
+			FROM employees e
+			JOIN department d
+			ON e.departmentId = d.departmentId
+			WHERE
+				identityNumber = $1
+				AND managerId = $6
+		)
+		ON CONFLICT (identityNumber) DO NOTHING
+		RETURNING identityNumber, name, employeeImageUri, gender, departmentId, created_at, updated_at, status, to_char(hired_at, 'YYYY-MM-DD');
+	`
+
+	batch := &pgx.Batch{}
+	for i := range inputs {
+		batch.Queue(departmentQuery, inputs[i].DepartmentID, managerId)
+		batch.Queue(identityNumberQuery, inputs[i].IdentityNumber, managerId)
+		batch.Queue(
+			insertQuery,
+			inputs[i].IdentityNumber,
+			inputs[i].Name,
+			inputs[i].EmployeeImageUri,
+			inputs[i].Gender,
+			inputs[i].DepartmentID,
+			managerId,
+			inputs[i].HiredAt,
+		)
+	}
+
+	results := pool.SendBatch(ctx, batch)
+	defer results.Close()
+
+	employees := make([]dto.EmployeeResponse, len(inputs))
+	errs := make([]error, len(inputs))
+	for i := range inputs {
+		var departmentOwned, identityNumberTaken bool
+		if err := results.QueryRow().Scan(&departmentOwned); err != nil {
+			return nil, nil, database.QueryError(ctx, err)
+		}
+		if err := results.QueryRow().Scan(&identityNumberTaken); err != nil {
+			return nil, nil, database.QueryError(ctx, err)
+		}
+		rows, err := results.Query()
+		if err != nil {
+			return nil, nil, database.QueryError(ctx, err)
+		}
+		inserted, err := pgx.CollectRows(rows, scanEmployeeResponse)
+		if err != nil {
+			return nil, nil, database.QueryError(ctx, err)
+		}
+
+		switch {
+		case !departmentOwned:
+			errs[i] = helper.ErrInvalidDepartmentId
+		case identityNumberTaken:
+			errs[i] = helper.ErrConflictIdentityNumber
+		case len(inserted) < 1:
+			// Identity number dipakai manager lain (unique global)
+			errs[i] = helper.ErrConflict
+		default:
+			employees[i] = inserted[0]
+		}
+	}
+
+	return employees, errs, database.QueryError(ctx, results.Close())
+}
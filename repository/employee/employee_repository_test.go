@@ -0,0 +1,109 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/dto"
+)
+
+// newTestRepository membuat EmployeeRepository di atas database baru dari
+// dbtest, dengan pool yang sama untuk writer dan reader.
+func newTestRepository(t *testing.T) (*pgxpool.Pool, *EmployeeRepository) {
+	t.Helper()
+	pool := dbtest.New(t)
+	var trigram bool
+	err := pool.QueryRow(
+		context.Background(),
+		"SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'pg_trgm');",
+	).Scan(&trigram)
+	if err != nil {
+		t.Fatalf("check pg_trgm: %v", err)
+	}
+	search := &config.SearchConfig{FuzzyThreshold: 0.3, TrigramAvailable: trigram}
+	repo := NewEmployeeRepository(pool, pool, config.LoadQueryTimeoutConfig(), search)
+	return pool, &repo
+}
+
+func employeePayload(identityNumber, departmentId string) dto.EmployeePayload {
+	return dto.EmployeePayload{
+		IdentityNumber:   identityNumber,
+		Name:             "Employee " + identityNumber,
+		EmployeeImageUri: "https://example.com/image.png",
+		Gender:           "male",
+		DepartmentID:     departmentId,
+	}
+}
+
+// employeeDepartment mengembalikan departmentId employee, atau "" jika
+// employee tidak ada.
+func employeeDepartment(t *testing.T, pool *pgxpool.Pool, identityNumber string) string {
+	t.Helper()
+	var departmentId string
+	err := pool.QueryRow(
+		context.Background(),
+		"SELECT departmentid FROM employees WHERE identitynumber = $1;",
+		identityNumber,
+	).Scan(&departmentId)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("get employee %s: %v", identityNumber, err)
+	}
+	return departmentId
+}
+
+func identityNumbers(employees []dto.EmployeeResponse) []string {
+	result := make([]string, len(employees))
+	for i, employee := range employees {
+		result[i] = employee.IdentityNumber
+	}
+	return result
+}
+
+func wantError(t *testing.T, name string, err, want error) {
+	t.Helper()
+	if !errors.Is(err, want) {
+		t.Fatalf("%s error = %v, want %v", name, err, want)
+	}
+}
+
+func TestMoveDepartmentReturnsMovedEmployees(t *testing.T) {
+	pool, repo := newTestRepository(t)
+	ctx := context.Background()
+	manager := dbtest.CreateManager(t, pool, "move@example.com")
+	from := dbtest.CreateDepartment(t, pool, manager, "From")
+	to := dbtest.CreateDepartment(t, pool, manager, "To")
+	for _, identityNumber := range []string{"EMP-2", "EMP-1"} {
+		dbtest.CreateEmployee(t, pool, dbtest.Employee{IdentityNumber: identityNumber, Name: "Employee", Gender: "male", DepartmentID: from})
+	}
+
+	inDepartment, err := repo.GetAllInDepartment(ctx, from, manager)
+	if err != nil {
+		t.Fatalf("GetAllInDepartment error = %v", err)
+	}
+	if got := identityNumbers(inDepartment); !slices.Equal(got, []string{"EMP-1", "EMP-2"}) {
+		t.Fatalf("GetAllInDepartment = %v, want [EMP-1 EMP-2]", got)
+	}
+
+	moved, err := repo.MoveDepartment(ctx, from, to, manager)
+	if err != nil {
+		t.Fatalf("MoveDepartment error = %v", err)
+	}
+	got := identityNumbers(moved)
+	slices.Sort(got)
+	if !slices.Equal(got, []string{"EMP-1", "EMP-2"}) {
+		t.Fatalf("MoveDepartment = %v, want [EMP-1 EMP-2]", got)
+	}
+	for _, employee := range moved {
+		if employee.DepartmentID != to {
+			t.Fatalf("moved employee %s department = %s, want %s", employee.IdentityNumber, employee.DepartmentID, to)
+		}
+	}
+}
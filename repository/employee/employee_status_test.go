@@ -0,0 +1,123 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+func TestArchivedEmployees(t *testing.T) {
+	pool, repo := newTestRepository(t)
+	ctx := context.Background()
+	manager := dbtest.CreateManager(t, pool, "archive@example.com")
+	department := dbtest.CreateDepartment(t, pool, manager, "Engineering")
+	dbtest.CreateEmployee(t, pool, dbtest.Employee{IdentityNumber: "EMP-1", Name: "Budi", Gender: "male", DepartmentID: department})
+	dbtest.CreateEmployee(t, pool, dbtest.Employee{IdentityNumber: "EMP-2", Name: "Sari", Gender: "female", DepartmentID: department})
+
+	archived, err := repo.SetStatus(ctx, "EMP-1", dto.EmployeeStatusArchived, manager)
+	if err != nil {
+		t.Fatalf("SetStatus error = %v", err)
+	}
+	if archived.Status != dto.EmployeeStatusArchived || archived.IdentityNumber != "EMP-1" {
+		t.Fatalf("SetStatus = %+v", archived)
+	}
+
+	list := func(status string) []string {
+		t.Helper()
+		input := dto.GetEmployeesRequest{Limit: 100, ManagerID: manager, Status: status, SortBy: "identityNumber"}
+		employees, err := repo.GetAll(ctx, &input)
+		if err != nil {
+			t.Fatalf("GetAll(%s) error = %v", status, err)
+		}
+		count, err := repo.Count(ctx, &input)
+		if err != nil || count != int64(len(employees)) {
+			t.Fatalf("Count(%s) = %d, %v, want %d", status, count, err, len(employees))
+		}
+		return identityNumbers(employees)
+	}
+	// Status active adalah default dari ValidateEmployeeGet
+	if got := list(dto.EmployeeStatusActive); !slices.Equal(got, []string{"EMP-2"}) {
+		t.Fatalf("active = %v, want [EMP-2]", got)
+	}
+	if got := list(dto.EmployeeStatusArchived); !slices.Equal(got, []string{"EMP-1"}) {
+		t.Fatalf("archived = %v, want [EMP-1]", got)
+	}
+
+	// identityNumber employee yang diarsipkan tetap terpakai
+	input := employeePayload("EMP-1", department)
+	_, err = repo.Create(ctx, &input, manager)
+	wantError(t, "Create over an archived employee", err, helper.ErrConflictIdentityNumber)
+	taken := "EMP-1"
+	_, err = repo.Update(ctx, "EMP-2", &dto.EmployeeUpdatePayload{IdentityNumber: &taken}, manager)
+	wantError(t, "Update to an archived identity number", err, helper.ErrConflictIdentityNumber)
+
+	// Employee yang diarsipkan tetap bisa di-update dan statusnya tidak berubah
+	name := "Budi Santoso"
+	updated, err := repo.Update(ctx, "EMP-1", &dto.EmployeeUpdatePayload{Name: &name}, manager)
+	if err != nil {
+		t.Fatalf("Update archived error = %v", err)
+	}
+	if updated.Name != name || updated.Status != dto.EmployeeStatusArchived {
+		t.Fatalf("Update archived = %+v", updated)
+	}
+
+	restored, err := repo.SetStatus(ctx, "EMP-1", dto.EmployeeStatusActive, manager)
+	if err != nil || restored.Status != dto.EmployeeStatusActive {
+		t.Fatalf("SetStatus restore = %+v, %v", restored, err)
+	}
+	if got := list(dto.EmployeeStatusActive); !slices.Equal(got, []string{"EMP-1", "EMP-2"}) {
+		t.Fatalf("active after restore = %v", got)
+	}
+
+	other := dbtest.CreateManager(t, pool, "other@example.com")
+	_, err = repo.SetStatus(ctx, "EMP-1", dto.EmployeeStatusArchived, other)
+	wantError(t, "SetStatus by another manager", err, helper.ErrNotFound)
+	_, err = repo.SetStatus(ctx, "EMP-404", dto.EmployeeStatusArchived, manager)
+	wantError(t, "SetStatus missing employee", err, helper.ErrNotFound)
+}
+
+func TestStats(t *testing.T) {
+	pool, repo := newTestRepository(t)
+	ctx := context.Background()
+	manager := dbtest.CreateManager(t, pool, "stats@example.com")
+	other := dbtest.CreateManager(t, pool, "other@example.com")
+	first := dbtest.CreateDepartment(t, pool, manager, "Engineering")
+	second := dbtest.CreateDepartment(t, pool, manager, "Empty")
+	deleted := dbtest.CreateDepartment(t, pool, manager, "Deleted")
+	foreign := dbtest.CreateDepartment(t, pool, other, "Foreign")
+	for _, e := range []dbtest.Employee{
+		{IdentityNumber: "EMP-1", Name: "Budi", Gender: "male", DepartmentID: first},
+		{IdentityNumber: "EMP-2", Name: "Sari", Gender: "female", DepartmentID: first},
+		{IdentityNumber: "EMP-3", Name: "Andi", Gender: "male", DepartmentID: first, Status: dto.EmployeeStatusArchived},
+		{IdentityNumber: "EMP-4", Name: "Dewi", Gender: "female", DepartmentID: deleted},
+		{IdentityNumber: "EMP-5", Name: "Eko", Gender: "male", DepartmentID: foreign, Status: dto.EmployeeStatusArchived},
+	} {
+		dbtest.CreateEmployee(t, pool, e)
+	}
+	dbtest.DeleteDepartment(t, pool, deleted)
+
+	stats, err := repo.Stats(ctx, manager)
+	if err != nil {
+		t.Fatalf("Stats error = %v", err)
+	}
+	// Department tanpa employee tetap dihitung nol, department yang
+	// dihapus dan milik manager lain tidak ikut
+	want := []dto.DepartmentEmployeeStats{
+		{DepartmentID: first, Active: 2, Archived: 1},
+		{DepartmentID: second},
+	}
+	slices.SortFunc(want, func(a, b dto.DepartmentEmployeeStats) int {
+		return strings.Compare(a.DepartmentID, b.DepartmentID)
+	})
+	if !reflect.DeepEqual(stats, want) {
+		t.Fatalf("Stats = %+v, want %+v", stats, want)
+	}
+}
@@ -0,0 +1,142 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+// sleepingQuerier menjalankan pg_sleep dengan context query sebelum setiap
+// query repository, sehingga query terlihat seperti runaway query di
+// database tanpa perlu data berukuran besar.
+type sleepingQuerier struct {
+	database.Querier
+	sleep time.Duration
+}
+
+func (q sleepingQuerier) pgSleep(ctx context.Context) error {
+	_, err := q.Querier.Exec(ctx, "SELECT pg_sleep($1);", q.sleep.Seconds())
+	return err
+}
+
+func (q sleepingQuerier) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	if err := q.pgSleep(ctx); err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	return q.Querier.Exec(ctx, sql, args...)
+}
+
+func (q sleepingQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	if err := q.pgSleep(ctx); err != nil {
+		return nil, err
+	}
+	return q.Querier.Query(ctx, sql, args...)
+}
+
+func TestStatementTimeouts(t *testing.T) {
+	pool := dbtest.New(t)
+	manager := dbtest.CreateManager(t, pool, "timeout@example.com")
+	timeouts := &config.QueryTimeoutConfig{
+		Lookup: 100 * time.Millisecond,
+		Write:  100 * time.Millisecond,
+		List:   200 * time.Millisecond,
+		Export: time.Minute,
+	}
+
+	tests := []struct {
+		name  string
+		sleep time.Duration
+		call  func(ctx context.Context, repo *EmployeeRepository) error
+		// parentTimeout membatalkan context request lebih dulu jika diisi
+		parentTimeout time.Duration
+		wantErr       error
+	}{
+		{
+			name:  "point lookup",
+			sleep: 5 * time.Second,
+			call: func(ctx context.Context, repo *EmployeeRepository) error {
+				return repo.IsIdentityNumberAvailable(ctx, "EMP-1")
+			},
+			wantErr: helper.ErrQueryTimeout,
+		},
+		{
+			name:  "list",
+			sleep: 5 * time.Second,
+			call: func(ctx context.Context, repo *EmployeeRepository) error {
+				_, err := repo.GetAll(ctx, &dto.GetEmployeesRequest{Limit: 10, ManagerID: manager})
+				return err
+			},
+			wantErr: helper.ErrQueryTimeout,
+		},
+		{
+			// Lookup (100ms) sudah habis, tetapi timeout list (200ms) belum
+			name:  "list outlives lookup timeout",
+			sleep: 150 * time.Millisecond,
+			call: func(ctx context.Context, repo *EmployeeRepository) error {
+				_, err := repo.Stats(ctx, manager)
+				return err
+			},
+		},
+		{
+			// Timeout repository diturunkan dari context request, sehingga
+			// pembatalan oleh client tetap terbawa dan bukan 504
+			name:  "client cancellation",
+			sleep: 5 * time.Second,
+			call: func(ctx context.Context, repo *EmployeeRepository) error {
+				_, err := repo.GetAll(ctx, &dto.GetEmployeesRequest{Limit: 10, ManagerID: manager})
+				return err
+			},
+			parentTimeout: 50 * time.Millisecond,
+			wantErr:       context.Canceled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			querier := sleepingQuerier{Querier: pool, sleep: tt.sleep}
+			repo := NewEmployeeRepository(querier, querier, timeouts, &config.SearchConfig{})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.parentTimeout > 0 {
+				time.AfterFunc(tt.parentTimeout, cancel)
+			}
+
+			start := time.Now()
+			err := tt.call(ctx, &repo)
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Fatalf("call took %s, want pg_sleep cancelled by the timeout", elapsed)
+			}
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if errors.Is(tt.wantErr, helper.ErrQueryTimeout) {
+				if got := helper.GetErrorStatusCode(err); got != http.StatusGatewayTimeout {
+					t.Fatalf("status = %d, want 504", got)
+				}
+			}
+		})
+	}
+
+	// Query yang dibatalkan tidak meninggalkan koneksi yang rusak di pool
+	if err := pool.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping after timeouts error = %v", err)
+	}
+}
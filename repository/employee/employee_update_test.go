@@ -0,0 +1,69 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/dto"
+)
+
+// TestUpdateAdvancesUpdatedAt memastikan PATCH memajukan updatedAt, tidak
+// mengubah createdAt, dan field yang tidak dikirim tetap seperti semula,
+// baik di hasil RETURNING maupun saat dibaca ulang.
+func TestUpdateAdvancesUpdatedAt(t *testing.T) {
+	pool, repo := newTestRepository(t)
+	ctx := context.Background()
+	manager := dbtest.CreateManager(t, pool, "update@example.com")
+	department := dbtest.CreateDepartment(t, pool, manager, "Engineering")
+	// Postgres menyimpan timestamp sampai mikrodetik
+	createdAt := time.Now().Add(-time.Hour).UTC().Truncate(time.Microsecond)
+	dbtest.CreateEmployee(t, pool, dbtest.Employee{
+		IdentityNumber: "EMP-1",
+		Name:           "Budi",
+		Gender:         "male",
+		DepartmentID:   department,
+		CreatedAt:      createdAt,
+		HiredAt:        "2024-03-01",
+	})
+
+	before, err := repo.GetForUpdate(ctx, "EMP-1", manager)
+	if err != nil {
+		t.Fatalf("GetForUpdate before error = %v", err)
+	}
+	if !before.UpdatedAt.Equal(createdAt) {
+		t.Fatalf("updatedAt before = %v, want %v", before.UpdatedAt, createdAt)
+	}
+
+	name := "Budi Santoso"
+	updated, err := repo.Update(ctx, "EMP-1", &dto.EmployeeUpdatePayload{Name: &name}, manager)
+	if err != nil {
+		t.Fatalf("Update error = %v", err)
+	}
+	after, err := repo.GetForUpdate(ctx, "EMP-1", manager)
+	if err != nil {
+		t.Fatalf("GetForUpdate after error = %v", err)
+	}
+
+	for label, got := range map[string]dto.EmployeeResponse{"returned": updated, "stored": after} {
+		if !got.UpdatedAt.After(before.UpdatedAt) {
+			t.Errorf("%s updatedAt = %v, want after %v", label, got.UpdatedAt, before.UpdatedAt)
+		}
+		if !got.CreatedAt.Equal(createdAt) {
+			t.Errorf("%s createdAt = %v, want %v", label, got.CreatedAt, createdAt)
+		}
+		if got.Name != name {
+			t.Errorf("%s name = %q, want %q", label, got.Name, name)
+		}
+
+		// Selain name, semua field payload dan status sama dengan sebelum PATCH
+		payload := got.EmployeePayload
+		payload.Name = before.Name
+		if !payload.Equal(before.EmployeePayload) || got.Status != before.Status {
+			t.Errorf("%s = %+v, want unchanged fields of %+v", label, got, before)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/dto"
+)
+
+func TestGetForUpsert(t *testing.T) {
+	pool, repo := newTestRepository(t)
+	ctx := context.Background()
+	manager := dbtest.CreateManager(t, pool, "upsert@example.com")
+	other := dbtest.CreateManager(t, pool, "other@example.com")
+	department := dbtest.CreateDepartment(t, pool, manager, "Engineering")
+	foreign := dbtest.CreateDepartment(t, pool, other, "Finance")
+	dbtest.CreateEmployee(t, pool, dbtest.Employee{IdentityNumber: "UPS-1", Name: "Budi", Gender: "male", DepartmentID: department, HiredAt: "2024-03-01"})
+	dbtest.CreateEmployee(t, pool, dbtest.Employee{IdentityNumber: "UPS-2", Name: "Sari", Gender: "female", DepartmentID: foreign})
+
+	existing, err := repo.GetForUpsert(ctx, pool, []string{"UPS-1", "UPS-2", "UPS-404"}, false)
+	if err != nil {
+		t.Fatalf("GetForUpsert error = %v", err)
+	}
+	// Employee manager lain ikut dikembalikan karena identityNumber unik
+	// global
+	if len(existing) != 2 || existing["UPS-1"].ManagerID != manager || existing["UPS-2"].ManagerID != other {
+		t.Fatalf("GetForUpsert = %+v", existing)
+	}
+	own := existing["UPS-1"].Employee
+	if own.Name != "Budi" || own.DepartmentID != department || own.HiredAt == nil || *own.HiredAt != "2024-03-01" {
+		t.Fatalf("UPS-1 = %+v", own)
+	}
+	if existing["UPS-2"].Employee.HiredAt != nil {
+		t.Fatalf("UPS-2 hiredAt = %q, want nil", *existing["UPS-2"].Employee.HiredAt)
+	}
+}
+
+func TestGetForUpsertLocksRows(t *testing.T) {
+	pool, repo := newTestRepository(t)
+	ctx := context.Background()
+	manager := dbtest.CreateManager(t, pool, "lock@example.com")
+	department := dbtest.CreateDepartment(t, pool, manager, "Engineering")
+	dbtest.CreateEmployee(t, pool, dbtest.Employee{IdentityNumber: "LOCK-1", Name: "Budi", Gender: "male", DepartmentID: department})
+
+	for _, lock := range []bool{true, false} {
+		tx, err := pool.Begin(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.GetForUpsert(ctx, tx, []string{"LOCK-1"}, lock); err != nil {
+			t.Fatalf("GetForUpsert(lock=%t) error = %v", lock, err)
+		}
+		// Koneksi lain tidak bisa mengunci baris yang sudah dikunci, sehingga NOWAIT gagal
+		_, err = pool.Exec(ctx, "SELECT 1 FROM employees WHERE identitynumber = 'LOCK-1' FOR UPDATE NOWAIT;")
+		if locked := err != nil; locked != lock {
+			t.Fatalf("lock=%t: row locked = %t (%v)", lock, locked, err)
+		}
+		_ = tx.Rollback(ctx)
+	}
+}
+
+func TestUpdateBatch(t *testing.T) {
+	pool, repo := newTestRepository(t)
+	ctx := context.Background()
+	manager := dbtest.CreateManager(t, pool, "batch@example.com")
+	other := dbtest.CreateManager(t, pool, "other@example.com")
+	engineering := dbtest.CreateDepartment(t, pool, manager, "Engineering")
+	finance := dbtest.CreateDepartment(t, pool, manager, "Finance")
+	foreign := dbtest.CreateDepartment(t, pool, other, "Sales")
+	deleted := dbtest.CreateDepartment(t, pool, manager, "Old")
+	dbtest.DeleteDepartment(t, pool, deleted)
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, e := range []dbtest.Employee{
+		{IdentityNumber: "BATCH-1", Name: "Budi", Gender: "male", DepartmentID: engineering, CreatedAt: createdAt},
+		{IdentityNumber: "BATCH-2", Name: "Sari", Gender: "female", DepartmentID: engineering, CreatedAt: createdAt},
+		{IdentityNumber: "BATCH-3", Name: "Tono", Gender: "male", DepartmentID: engineering, CreatedAt: createdAt},
+		{IdentityNumber: "BATCH-4", Name: "Ani", Gender: "female", DepartmentID: foreign, CreatedAt: createdAt},
+	} {
+		dbtest.CreateEmployee(t, pool, e)
+	}
+
+	hiredAt := "2024-03-01"
+	payload := func(identityNumber, departmentId string) dto.EmployeePayload {
+		return dto.EmployeePayload{
+			IdentityNumber:   identityNumber,
+			Name:             "Updated " + identityNumber,
+			EmployeeImageUri: "https://example.com/updated.png",
+			Gender:           "female",
+			DepartmentID:     departmentId,
+			HiredAt:          &hiredAt,
+		}
+	}
+	updated, err := repo.UpdateBatch(ctx, pool, []dto.EmployeePayload{
+		payload("BATCH-1", finance),
+		// Department tujuan milik manager lain atau sudah dihapus
+		payload("BATCH-2", foreign),
+		payload("BATCH-3", deleted),
+		// Employee milik manager lain
+		payload("BATCH-4", foreign),
+		payload("BATCH-404", engineering),
+	}, manager)
+	if err != nil {
+		t.Fatalf("UpdateBatch error = %v", err)
+	}
+	if want := []bool{true, false, false, false, false}; !slices.Equal(updated, want) {
+		t.Fatalf("UpdateBatch = %v, want %v", updated, want)
+	}
+
+	existing, err := repo.GetForUpsert(ctx, pool, []string{"BATCH-1", "BATCH-2", "BATCH-4"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := existing["BATCH-1"].Employee
+	if !got.EmployeePayload.Equal(payload("BATCH-1", finance)) || !got.UpdatedAt.After(createdAt) {
+		t.Fatalf("BATCH-1 = %+v, want the new payload", got)
+	}
+	for _, identityNumber := range []string{"BATCH-2", "BATCH-4"} {
+		if name := existing[identityNumber].Employee.Name; name == "Updated "+identityNumber {
+			t.Fatalf("%s was updated", identityNumber)
+		}
+	}
+
+	// Error dari salah satu query dikembalikan sebagai error batch
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback(ctx)
+	invalid := payload("BATCH-1", engineering)
+	notADate := "not-a-date"
+	invalid.HiredAt = &notADate
+	if _, err := repo.UpdateBatch(ctx, tx, []dto.EmployeePayload{invalid}, manager); err == nil {
+		t.Fatalf("UpdateBatch with an invalid date error = %v, want a query error", err)
+	}
+}
@@ -0,0 +1,92 @@
+package featureFlagRepository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/samber/do/v2"
+)
+
+// Nama query untuk log dan metric, lihat database.QueryLogTracer
+const (
+	queryFeatureFlagGetOverrides   database.QueryName = "feature_flag.get_overrides"
+	queryFeatureFlagSetOverride    database.QueryName = "feature_flag.set_override"
+	queryFeatureFlagDeleteOverride database.QueryName = "feature_flag.delete_override"
+)
+
+// FeatureFlagRepository membaca override dari primary, agar override yang
+// baru diubah admin langsung terbaca setelah cache di-invalidate.
+type FeatureFlagRepository struct {
+	db       database.Querier
+	timeouts *config.QueryTimeoutConfig
+}
+
+type FeatureFlagRepositoryInterface interface {
+	GetOverrides(ctx context.Context, managerId string) (map[string]bool, error)
+	SetOverride(ctx context.Context, flag, managerId string, enabled bool) error
+	DeleteOverride(ctx context.Context, flag, managerId string) error
+}
+
+func New(db database.Querier, timeouts *config.QueryTimeoutConfig) FeatureFlagRepository {
+	return FeatureFlagRepository{db: db, timeouts: timeouts}
+}
+
+func NewInject(i do.Injector) (FeatureFlagRepositoryInterface, error) {
+	cluster := do.MustInvoke[*database.Cluster](i)
+	repo := New(cluster.Writer(), config.LoadQueryTimeoutConfig())
+	return &repo, nil
+}
+
+// GetOverrides mengembalikan semua override milik manager, per nama flag.
+func (r *FeatureFlagRepository) GetOverrides(ctx context.Context, managerId string) (map[string]bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Lookup)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryFeatureFlagGetOverrides)
+
+	query := "SELECT flag, enabled FROM feature_flag_override WHERE manager_id = $1;"
+	rows, err := r.db.Query(ctx, query, managerId)
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+
+	overrides := make(map[string]bool)
+	var flag string
+	var enabled bool
+	_, err = pgx.ForEachRow(rows, []any{&flag, &enabled}, func() error {
+		overrides[flag] = enabled
+		return nil
+	})
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	return overrides, nil
+}
+
+func (r *FeatureFlagRepository) SetOverride(ctx context.Context, flag, managerId string, enabled bool) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryFeatureFlagSetOverride)
+
+	query := `
+		INSERT INTO feature_flag_override (flag, manager_id, enabled)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (manager_id, flag)
+		DO UPDATE SET enabled = EXCLUDED.enabled, updated_at = CURRENT_TIMESTAMP;
+	`
+	_, err := r.db.Exec(ctx, query, flag, managerId, enabled)
+	return database.QueryError(ctx, err)
+}
+
+// DeleteOverride mengembalikan flag manager ke default. Menghapus override
+// yang tidak ada bukan error.
+func (r *FeatureFlagRepository) DeleteOverride(ctx context.Context, flag, managerId string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryFeatureFlagDeleteOverride)
+
+	query := "DELETE FROM feature_flag_override WHERE flag = $1 AND manager_id = $2;"
+	_, err := r.db.Exec(ctx, query, flag, managerId)
+	return database.QueryError(ctx, err)
+}
@@ -2,80 +2,577 @@ package fileRepository
 
 import (
 	"context"
+	"errors"
+	"time"
 
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
 	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/samber/do/v2"
+)
+
+// Nama query untuk log dan metric, lihat database.QueryLogTracer
+const (
+	queryFileCreate             database.QueryName = "file.create"
+	queryFileGet                database.QueryName = "file.get"
+	queryFileFindByURIs         database.QueryName = "file.find_by_uris"
+	queryFileThumbnailClaim     database.QueryName = "file.thumbnail_claim"
+	queryFileThumbnailReady     database.QueryName = "file.thumbnail_ready"
+	queryFileThumbnailFailed    database.QueryName = "file.thumbnail_failed"
+	queryFileAdjustReferences   database.QueryName = "file.adjust_references"
+	queryFileDeleteUnreferenced database.QueryName = "file.delete_unreferenced"
+	queryFileGetAll             database.QueryName = "file.get_all"
+	queryFileFindReferences     database.QueryName = "file.find_references"
+	queryFileDelete             database.QueryName = "file.delete"
+
+	queryFileUploadCreate        database.QueryName = "file_upload.create"
+	queryFileUploadGetForUpdate  database.QueryName = "file_upload.get_for_update"
+	queryFileUploadDelete        database.QueryName = "file_upload.delete"
+	queryFileUploadDeleteExpired database.QueryName = "file_upload.delete_expired"
 )
 
 type FileRepository struct {
-	db *pgxpool.Pool
+	db       database.Querier
+	timeouts *config.QueryTimeoutConfig
+}
+
+// FileRepositoryInterface berisi method FileRepository yang dipakai
+// service, sehingga service bisa dites dengan mocks.FileRepository.
+type FileRepositoryInterface interface {
+	Create(ctx context.Context, file entity.File) (entity.File, error)
+	Get(ctx context.Context, fileID string) (entity.File, error)
+	FindByURIs(ctx context.Context, uris []string) ([]entity.File, error)
+	AdjustReferences(ctx context.Context, db database.Querier, added []string, removed []string) ([]string, error)
+	DeleteUnreferenced(ctx context.Context, db database.Querier, gracePeriod time.Duration, limit int) ([]entity.File, error)
+	GetAll(ctx context.Context, managerID string, limit int, offset int) ([]entity.File, error)
+	FindReferences(ctx context.Context, fileURI string, managerID string, limit int) (entity.FileReferences, error)
+	Delete(ctx context.Context, fileID string, managerID string) (entity.File, error)
+
+	ClaimThumbnails(ctx context.Context, lease time.Duration, limit int) ([]entity.File, error)
+	MarkThumbnailReady(ctx context.Context, fileID string, key string, uri string) error
+	MarkThumbnailFailed(ctx context.Context, fileID string, retryAfter time.Duration, final bool) error
+
+	CreateUpload(ctx context.Context, upload entity.FileUpload, expiry time.Duration) error
+	GetUploadForUpdate(ctx context.Context, uploadID string, managerID string) (entity.FileUpload, error)
+	DeleteUpload(ctx context.Context, uploadID string) error
+	DeleteExpiredUploads(ctx context.Context, db database.Querier, retention time.Duration, limit int) ([]string, error)
 }
 
-func NewFileRepository(db *pgxpool.Pool) FileRepository {
-	return FileRepository{db: db}
+func New(db database.Querier, timeouts *config.QueryTimeoutConfig) FileRepository {
+	return FileRepository{db: db, timeouts: timeouts}
+}
+
+func NewInject(i do.Injector) (FileRepositoryInterface, error) {
+	cluster := do.MustInvoke[*database.Cluster](i)
+	repo := New(cluster.Writer(), config.LoadQueryTimeoutConfig())
+	return &repo, nil
+}
+
+// Create menyimpan metadata file yang sudah di-upload ke storage, dengan id
+// dari pemanggil, lihat idgen.IDGenerator. Purpose kosong berarti
+// entity.FilePurposeEmployee. File dengan ThumbnailStatus pending langsung
+// bisa diambil ClaimThumbnails.
+func (r *FileRepository) Create(ctx context.Context, file entity.File) (entity.File, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryFileCreate)
+
+	query := `
+		INSERT INTO file (
+			fileid, managerid, filename, fileuri, filekey, filesize, contenttype,
+			thumbnailstatus, thumbnailnextattemptat, purpose
+		)
+		VALUES (
+			$1, $2, $3, $4, $5, $6, $7,
+			NULLIF($8::varchar, ''),
+			CASE WHEN $8::varchar = 'pending' THEN CURRENT_TIMESTAMP END,
+			COALESCE(NULLIF($9::varchar, ''), 'employee')
+		)
+		RETURNING createdon, purpose;
+	`
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		file.FileId,
+		file.ManagerId,
+		file.FileName,
+		file.FileURI,
+		file.FileKey,
+		file.Size,
+		file.ContentType,
+		file.ThumbnailStatus,
+		file.Purpose,
+	).Scan(&file.CreatedOn, &file.Purpose)
+	if err != nil {
+		return entity.File{}, database.QueryError(ctx, err)
+	}
+	return file, nil
 }
 
-func (r *FileRepository) Create(ctx context.Context, e entity.File) error {
+// Get mengambil file berdasarkan id tanpa memeriksa pemilik, atau
+// helper.ErrNotFound. File tanpa key (dibuat sebelum kolom filekey ada)
+// juga dianggap tidak ada.
+func (r *FileRepository) Get(ctx context.Context, fileID string) (entity.File, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Lookup)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryFileGet)
+
 	query := `
-		INSERT INTO users (filename, fileuri)
-		VALUES ($1, $2)
+		SELECT
+			fileid, managerid, filename, fileuri, filekey, filesize, contenttype, purpose, createdon,
+			COALESCE(thumbnailkey, ''), COALESCE(thumbnailuri, ''), COALESCE(thumbnailstatus, '')
+		FROM file
+		WHERE fileid = $1 AND filekey IS NOT NULL;
 	`
-	_, err := r.db.Exec(ctx, query,
-		e.FileName, // Name
-		e.FileURI,  // URI
+	var file entity.File
+	err := r.db.QueryRow(ctx, query, fileID).Scan(
+		&file.FileId,
+		&file.ManagerId,
+		&file.FileName,
+		&file.FileURI,
+		&file.FileKey,
+		&file.Size,
+		&file.ContentType,
+		&file.Purpose,
+		&file.CreatedOn,
+		&file.ThumbnailKey,
+		&file.ThumbnailURI,
+		&file.ThumbnailStatus,
 	)
-	return err
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.File{}, database.QueryError(ctx, helper.ErrNotFound)
+	}
+	if err != nil {
+		return entity.File{}, database.QueryError(ctx, err)
+	}
+	return file, nil
 }
 
-func (r *FileRepository) GetAllUsers(ctx context.Context) ([]entity.User, error) {
-	query := `SELECT identitynumber, name, email FROM users`
-	rows, err := r.db.Query(ctx, query)
+// FindByURIs mengambil id, pemilik, kegunaan, uri, dan thumbnail file yang
+// fileuri-nya ada di uris. Uri yang bukan file hasil upload tidak ada di
+// hasil.
+func (r *FileRepository) FindByURIs(ctx context.Context, uris []string) ([]entity.File, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryFileFindByURIs)
+
+	query := `
+		SELECT fileid, COALESCE(managerid, ''), purpose, fileuri, COALESCE(thumbnailuri, ''), COALESCE(thumbnailstatus, '')
+		FROM file
+		WHERE fileuri = ANY($1);
+	`
+	rows, err := r.db.Query(ctx, query, uris)
 	if err != nil {
-		return nil, err
+		return nil, database.QueryError(ctx, err)
 	}
-	defer rows.Close()
+	files, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.File, error) {
+		var file entity.File
+		err := row.Scan(&file.FileId, &file.ManagerId, &file.Purpose, &file.FileURI, &file.ThumbnailURI, &file.ThumbnailStatus)
+		return file, err
+	})
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	return files, nil
+}
 
-	var users []entity.User
-	for rows.Next() {
-		var user entity.User
-		err := rows.Scan(&user.Id, &user.Name, &user.Email)
-		if err != nil {
-			return nil, err
+// AdjustReferences menambah satu rujukan untuk setiap uri di added dan
+// mengurangi satu untuk setiap uri di removed, lewat db milik pemanggil agar
+// ikut transaksi perubahan employee atau profil manager. Uri yang bukan file
+// hasil upload dilewati. Hasilnya adalah uri di added yang ada di tabel
+// file; file yang baru saja dihapus DeleteUnreferenced tidak ada di hasil.
+func (r *FileRepository) AdjustReferences(ctx context.Context, db database.Querier, added []string, removed []string) ([]string, error) {
+	uris := make([]string, 0, len(added)+len(removed))
+	changes := make([]int32, 0, len(added)+len(removed))
+	for _, uri := range added {
+		if uri != "" {
+			uris = append(uris, uri)
+			changes = append(changes, 1)
+		}
+	}
+	for _, uri := range removed {
+		if uri != "" {
+			uris = append(uris, uri)
+			changes = append(changes, -1)
 		}
-		users = append(users, user)
 	}
-	return users, nil
+	if len(uris) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryFileAdjustReferences)
+
+	query := `
+		WITH delta AS (
+			SELECT uri, SUM(change) AS change
+			FROM unnest($1::text[], $2::int[]) AS t(uri, change)
+			GROUP BY uri
+		)
+		UPDATE file f
+		SET
+			referencecount = GREATEST(f.referencecount + delta.change, 0),
+			unreferencedsince = CASE
+				WHEN f.referencecount + delta.change > 0 THEN NULL
+				ELSE COALESCE(f.unreferencedsince, CURRENT_TIMESTAMP)
+			END
+		FROM delta
+		WHERE f.fileuri = delta.uri
+		RETURNING f.fileuri;
+	`
+	rows, err := db.Query(ctx, query, uris, changes)
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	matched, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	return matched, nil
 }
 
-func (r *FileRepository) GetFile(ctx context.Context, uri string) ([]entity.File, error) {
-	// Menggunakan Query bukan Exec karena kita mengambil hasil dari SELECT
-	query := `SELECT f.fileid, f.filename, f.fileuri FROM file f WHERE fileuri = $1`
-	rows, err := r.db.Query(ctx, query, uri)
+// DeleteUnreferenced menghapus paling banyak limit file yang tidak dirujuk
+// lebih dari gracePeriod lalu dan mengembalikan FileKey dan ThumbnailKey-nya,
+// yang object-nya harus dihapus pemanggil dari storage. Rujukan di
+// employees dan manager dicek sekali lagi, sehingga hitungan yang meleset
+// tidak membuat file yang masih dipakai terhapus. Dijalankan lewat db milik
+// pemanggil seperti DeleteExpiredUploads.
+func (r *FileRepository) DeleteUnreferenced(ctx context.Context, db database.Querier, gracePeriod time.Duration, limit int) ([]entity.File, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryFileDeleteUnreferenced)
+
+	query := `
+		DELETE FROM file
+		WHERE fileid IN (
+			SELECT f.fileid
+			FROM file f
+			WHERE
+				f.referencecount = 0
+				AND f.unreferencedsince < CURRENT_TIMESTAMP - $1::interval
+				AND NOT EXISTS (SELECT 1 FROM employees e WHERE e.employeeimageuri = f.fileuri)
+				AND NOT EXISTS (
+					SELECT 1 FROM manager m WHERE f.fileuri IN (m.userimageuri, m.companyimageuri)
+				)
+			ORDER BY f.unreferencedsince
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING COALESCE(filekey, ''), COALESCE(thumbnailkey, '');
+	`
+	rows, err := db.Query(ctx, query, gracePeriod, limit)
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	files, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.File, error) {
+		var file entity.File
+		err := row.Scan(&file.FileKey, &file.ThumbnailKey)
+		return file, err
+	})
 	if err != nil {
-		return nil, err
+		return nil, database.QueryError(ctx, err)
 	}
-	defer rows.Close()
+	return files, nil
+}
 
-	var files []entity.File
-	for rows.Next() {
+// GetAll mengambil file milik manager, dari yang terbaru.
+func (r *FileRepository) GetAll(ctx context.Context, managerID string, limit int, offset int) ([]entity.File, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryFileGetAll)
+
+	query := `
+		SELECT
+			fileid, managerid, filename, fileuri, filesize, contenttype, purpose, createdon,
+			referencecount, COALESCE(thumbnailuri, ''), COALESCE(thumbnailstatus, '')
+		FROM file
+		WHERE managerid = $1 AND filekey IS NOT NULL
+		ORDER BY createdon DESC, fileid
+		LIMIT $2 OFFSET $3;
+	`
+	rows, err := r.db.Query(ctx, query, managerID, limit, offset)
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	files, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.File, error) {
 		var file entity.File
-		// Menyimpan data hasil query ke dalam struct user
-		if err := rows.Scan(&file.FileId, &file.FileName, &file.FileURI); err != nil {
-			return nil, err
-		}
-		files = append(files, file)
+		err := row.Scan(
+			&file.FileId,
+			&file.ManagerId,
+			&file.FileName,
+			&file.FileURI,
+			&file.Size,
+			&file.ContentType,
+			&file.Purpose,
+			&file.CreatedOn,
+			&file.ReferenceCount,
+			&file.ThumbnailURI,
+			&file.ThumbnailStatus,
+		)
+		return file, err
+	})
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
 	}
+	return files, nil
+}
 
-	// Memastikan tidak ada error saat iterasi
-	if err := rows.Err(); err != nil {
-		return nil, err
+// FindReferences mencari employee dan profil manager yang memakai fileURI
+// langsung dari tabelnya, bukan dari referencecount. IdentityNumbers hanya
+// berisi employee managerID, paling banyak limit.
+func (r *FileRepository) FindReferences(ctx context.Context, fileURI string, managerID string, limit int) (entity.FileReferences, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Lookup)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryFileFindReferences)
+
+	query := `
+		SELECT
+			(SELECT count(*) FROM employees WHERE employeeimageuri = $1)
+				+ (SELECT count(*) FROM manager WHERE userimageuri = $1)
+				+ (SELECT count(*) FROM manager WHERE companyimageuri = $1),
+			ARRAY(
+				SELECT e.identitynumber
+				FROM employees e
+				JOIN department d ON d.departmentid = e.departmentid
+				WHERE e.employeeimageuri = $1 AND d.managerid = $2
+				ORDER BY e.identitynumber
+				LIMIT $3
+			),
+			COALESCE((
+				SELECT array_remove(ARRAY[
+					CASE WHEN m.userimageuri = $1 THEN 'userImageUri' END,
+					CASE WHEN m.companyimageuri = $1 THEN 'companyImageUri' END
+				]::text[], NULL)
+				FROM manager m
+				WHERE m.managerid = $2
+			), '{}');
+	`
+	var refs entity.FileReferences
+	err := r.db.QueryRow(ctx, query, fileURI, managerID, limit).Scan(
+		&refs.Count,
+		&refs.IdentityNumbers,
+		&refs.ProfileFields,
+	)
+	if err != nil {
+		return entity.FileReferences{}, database.QueryError(ctx, err)
 	}
+	return refs, nil
+}
+
+// Delete menghapus file milik manager yang tidak dipakai employee maupun
+// profil manager mana pun dan mengembalikan key object-nya. File yang tidak
+// ada, milik manager lain, atau masih dipakai menghasilkan helper.ErrNotFound.
+func (r *FileRepository) Delete(ctx context.Context, fileID string, managerID string) (entity.File, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryFileDelete)
 
+	query := `
+		DELETE FROM file f
+		WHERE
+			f.fileid = $1
+			AND f.managerid = $2
+			AND f.filekey IS NOT NULL
+			AND NOT EXISTS (SELECT 1 FROM employees e WHERE e.employeeimageuri = f.fileuri)
+			AND NOT EXISTS (
+				SELECT 1 FROM manager m WHERE f.fileuri IN (m.userimageuri, m.companyimageuri)
+			)
+		RETURNING fileid, filekey, COALESCE(thumbnailkey, '');
+	`
+	var file entity.File
+	err := r.db.QueryRow(ctx, query, fileID, managerID).Scan(&file.FileId, &file.FileKey, &file.ThumbnailKey)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.File{}, database.QueryError(ctx, helper.ErrNotFound)
+	}
+	if err != nil {
+		return entity.File{}, database.QueryError(ctx, err)
+	}
+	return file, nil
+}
+
+// ClaimThumbnails mengambil paling banyak limit file yang thumbnail-nya
+// perlu dibuat dan menundanya selama lease, sehingga file yang sama tidak
+// diambil instance lain selama diproses. File yang tidak ditandai ready
+// atau failed sebelum lease habis diambil lagi.
+func (r *FileRepository) ClaimThumbnails(ctx context.Context, lease time.Duration, limit int) ([]entity.File, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryFileThumbnailClaim)
+
+	query := `
+		UPDATE file
+		SET thumbnailnextattemptat = CURRENT_TIMESTAMP + $1::interval
+		WHERE fileid IN (
+			SELECT fileid
+			FROM file
+			WHERE
+				thumbnailstatus = 'pending'
+				AND thumbnailnextattemptat <= CURRENT_TIMESTAMP
+			ORDER BY thumbnailnextattemptat
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING fileid, COALESCE(managerid, ''), filekey, contenttype, thumbnailattempts;
+	`
+	rows, err := r.db.Query(ctx, query, lease, limit)
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	files, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.File, error) {
+		file := entity.File{ThumbnailStatus: entity.ThumbnailPending}
+		err := row.Scan(&file.FileId, &file.ManagerId, &file.FileKey, &file.ContentType, &file.ThumbnailAttempts)
+		return file, err
+	})
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
 	return files, nil
 }
 
-func (r *FileRepository) Delete(ctx context.Context, id string) error {
-	query := `DELETE FROM file WHERE file.fileid = ?`
-	_, err := r.db.Query(ctx, query, id)
-	return err
+func (r *FileRepository) MarkThumbnailReady(ctx context.Context, fileID string, key string, uri string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryFileThumbnailReady)
+
+	query := `
+		UPDATE file
+		SET thumbnailstatus = 'ready', thumbnailkey = $2, thumbnailuri = $3, thumbnailnextattemptat = NULL
+		WHERE fileid = $1;
+	`
+	if _, err := r.db.Exec(ctx, query, fileID, key, uri); err != nil {
+		return database.QueryError(ctx, err)
+	}
+	return nil
+}
+
+// MarkThumbnailFailed menambah jumlah percobaan dan menunda percobaan
+// berikutnya selama retryAfter, atau menandai thumbnail failed jika final.
+func (r *FileRepository) MarkThumbnailFailed(ctx context.Context, fileID string, retryAfter time.Duration, final bool) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryFileThumbnailFailed)
+
+	query := `
+		UPDATE file
+		SET
+			thumbnailattempts = thumbnailattempts + 1,
+			thumbnailstatus = CASE WHEN $3 THEN 'failed' ELSE 'pending' END,
+			thumbnailnextattemptat = CASE WHEN $3 THEN NULL ELSE CURRENT_TIMESTAMP + $2::interval END
+		WHERE fileid = $1 AND thumbnailstatus = 'pending';
+	`
+	if _, err := r.db.Exec(ctx, query, fileID, retryAfter, final); err != nil {
+		return database.QueryError(ctx, err)
+	}
+	return nil
+}
+
+// CreateUpload mencatat upload presign yang berlaku selama expiry, dihitung
+// dari jam database seperti pengecekannya di GetUploadForUpdate.
+func (r *FileRepository) CreateUpload(ctx context.Context, upload entity.FileUpload, expiry time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryFileUploadCreate)
+
+	query := `
+		INSERT INTO file_upload (id, manager_id, file_key, file_name, content_type, size, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP + $7::interval);
+	`
+	_, err := r.db.Exec(
+		ctx,
+		query,
+		upload.Id,
+		upload.ManagerId,
+		upload.FileKey,
+		upload.FileName,
+		upload.ContentType,
+		upload.Size,
+		expiry,
+	)
+	if err != nil {
+		return database.QueryError(ctx, err)
+	}
+	return nil
+}
+
+// GetUploadForUpdate mengambil dan mengunci upload presign milik manager
+// sampai transaksinya selesai, atau helper.ErrNotFound. Upload yang sudah
+// kedaluwarsa tetap dikembalikan dengan Expired true.
+func (r *FileRepository) GetUploadForUpdate(ctx context.Context, uploadID string, managerID string) (entity.FileUpload, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Lookup)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryFileUploadGetForUpdate)
+
+	query := `
+		SELECT
+			id, manager_id, file_key, file_name, content_type, size, expires_at,
+			expires_at <= CURRENT_TIMESTAMP, created_at
+		FROM file_upload
+		WHERE id = $1 AND manager_id = $2
+		FOR UPDATE;
+	`
+	var upload entity.FileUpload
+	err := r.db.QueryRow(ctx, query, uploadID, managerID).Scan(
+		&upload.Id,
+		&upload.ManagerId,
+		&upload.FileKey,
+		&upload.FileName,
+		&upload.ContentType,
+		&upload.Size,
+		&upload.ExpiresAt,
+		&upload.Expired,
+		&upload.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.FileUpload{}, database.QueryError(ctx, helper.ErrNotFound)
+	}
+	if err != nil {
+		return entity.FileUpload{}, database.QueryError(ctx, err)
+	}
+	return upload, nil
+}
+
+func (r *FileRepository) DeleteUpload(ctx context.Context, uploadID string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryFileUploadDelete)
+
+	if _, err := r.db.Exec(ctx, "DELETE FROM file_upload WHERE id = $1;", uploadID); err != nil {
+		return database.QueryError(ctx, err)
+	}
+	return nil
+}
+
+// DeleteExpiredUploads menghapus paling banyak limit upload presign yang
+// kedaluwarsa lebih dari retention lalu dan mengembalikan key object-nya,
+// yang harus dihapus pemanggil dari storage. Dijalankan lewat db milik
+// pemanggil seperti OutboxRepository.DeleteSent.
+func (r *FileRepository) DeleteExpiredUploads(ctx context.Context, db database.Querier, retention time.Duration, limit int) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryFileUploadDeleteExpired)
+
+	query := `
+		DELETE FROM file_upload
+		WHERE id IN (
+			SELECT id
+			FROM file_upload
+			WHERE expires_at < CURRENT_TIMESTAMP - $1::interval
+			ORDER BY expires_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING file_key;
+	`
+	rows, err := db.Query(ctx, query, retention, limit)
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	keys, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	return keys, nil
 }
@@ -0,0 +1,370 @@
+//go:build integration
+
+package fileRepository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+func TestCreateThenGet(t *testing.T) {
+	pool := dbtest.New(t)
+	repo := New(pool, config.LoadQueryTimeoutConfig())
+	ctx := context.Background()
+	manager := dbtest.CreateManager(t, pool, "file@example.com")
+
+	file := entity.File{
+		FileId:      "0190a8f2-7c1e-7d3a-9b4f-0a1b2c3d4e5f",
+		ManagerId:   manager,
+		FileName:    "avatar.png",
+		FileURI:     "https://api.example.com/files/0190a8f2-7c1e-7d3a-9b4f-0a1b2c3d4e5f",
+		FileKey:     "file/" + manager + "/0190a8f2-7c1e-7d3a-9b4f-0a1b2c3d4e5f.png",
+		Size:        1234,
+		ContentType: "image/png",
+	}
+	created, err := repo.Create(ctx, file)
+	if err != nil {
+		t.Fatalf("Create error = %v", err)
+	}
+	if created.CreatedOn.IsZero() || created.Purpose != entity.FilePurposeEmployee {
+		t.Fatalf("Create = %+v, want createdOn and the default purpose", created)
+	}
+
+	got, err := repo.Get(ctx, file.FileId)
+	if err != nil {
+		t.Fatalf("Get error = %v", err)
+	}
+	if got.ManagerId != manager || got.FileKey != file.FileKey || got.FileURI != file.FileURI ||
+		got.Size != file.Size || got.ContentType != file.ContentType || got.FileName != file.FileName ||
+		!got.CreatedOn.Equal(created.CreatedOn) {
+		t.Fatalf("Get = %+v, want %+v", got, created)
+	}
+
+	if _, err := repo.Get(ctx, "0190a8f2-0000-7000-8000-000000000000"); !errors.Is(err, helper.ErrNotFound) {
+		t.Fatalf("Get unknown file error = %v, want %v", err, helper.ErrNotFound)
+	}
+	if _, err := repo.Create(ctx, file); err == nil {
+		t.Fatal("Create accepted a duplicate fileId")
+	}
+}
+
+// TestGetSkipsFilesWithoutKey memastikan file lama tanpa filekey tidak
+// dilayani GET /files/:id.
+func TestGetSkipsFilesWithoutKey(t *testing.T) {
+	pool := dbtest.New(t)
+	repo := New(pool, config.LoadQueryTimeoutConfig())
+	var fileID string
+	err := pool.QueryRow(
+		context.Background(),
+		"INSERT INTO file (filename, fileuri) VALUES ('old.png', 'https://example.com/old.png') RETURNING fileid;",
+	).Scan(&fileID)
+	if err != nil {
+		t.Fatalf("create file: %v", err)
+	}
+
+	if _, err := repo.Get(context.Background(), fileID); !errors.Is(err, helper.ErrNotFound) {
+		t.Fatalf("Get error = %v, want %v", err, helper.ErrNotFound)
+	}
+}
+
+func TestDeleteExpiredUploads(t *testing.T) {
+	pool := dbtest.New(t)
+	repo := New(pool, config.LoadQueryTimeoutConfig())
+	ctx := context.Background()
+	for id, expiry := range map[string]time.Duration{
+		"0190a8f2-7c1e-7d3a-9b4f-00000000000a": -2 * time.Hour,
+		"0190a8f2-7c1e-7d3a-9b4f-00000000000b": -time.Minute,
+		"0190a8f2-7c1e-7d3a-9b4f-00000000000c": time.Hour,
+	} {
+		upload := entity.FileUpload{Id: id, ManagerId: "manager", FileKey: "file/manager/" + id + ".png", ContentType: "image/png", Size: 1}
+		if err := repo.CreateUpload(ctx, upload, expiry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Hanya upload yang kedaluwarsa lebih dari retention yang dihapus
+	keys, err := repo.DeleteExpiredUploads(ctx, pool, time.Hour, 10)
+	if err != nil {
+		t.Fatalf("DeleteExpiredUploads error = %v", err)
+	}
+	if len(keys) != 1 || keys[0] != "file/manager/0190a8f2-7c1e-7d3a-9b4f-00000000000a.png" {
+		t.Fatalf("DeleteExpiredUploads = %v, want only the upload expired 2h ago", keys)
+	}
+
+	upload, err := repo.GetUploadForUpdate(ctx, "0190a8f2-7c1e-7d3a-9b4f-00000000000b", "manager")
+	if err != nil || !upload.Expired {
+		t.Fatalf("GetUploadForUpdate = %+v, %v, want the expired upload", upload, err)
+	}
+	upload, err = repo.GetUploadForUpdate(ctx, "0190a8f2-7c1e-7d3a-9b4f-00000000000c", "manager")
+	if err != nil || upload.Expired {
+		t.Fatalf("GetUploadForUpdate = %+v, %v, want the live upload", upload, err)
+	}
+}
+
+// TestClaimThumbnails memastikan file yang sudah diambil tidak diambil lagi
+// selama lease, dan status thumbnail mengikuti MarkThumbnailReady dan
+// MarkThumbnailFailed.
+func TestClaimThumbnails(t *testing.T) {
+	pool := dbtest.New(t)
+	repo := New(pool, config.LoadQueryTimeoutConfig())
+	ctx := context.Background()
+	manager := dbtest.CreateManager(t, pool, "thumbnail@example.com")
+
+	ids := []string{
+		"0190a8f2-7c1e-7d3a-9b4f-000000000001",
+		"0190a8f2-7c1e-7d3a-9b4f-000000000002",
+		"0190a8f2-7c1e-7d3a-9b4f-000000000003",
+	}
+	for i, id := range ids {
+		file := entity.File{
+			FileId:      id,
+			ManagerId:   manager,
+			FileName:    "avatar.png",
+			FileURI:     "https://api.example.com/files/" + id,
+			FileKey:     "file/" + manager + "/" + id + ".png",
+			Size:        1,
+			ContentType: "image/png",
+		}
+		// File terakhir tidak memiliki thumbnail
+		if i < 2 {
+			file.ThumbnailStatus = entity.ThumbnailPending
+		}
+		if _, err := repo.Create(ctx, file); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	claimed, err := repo.ClaimThumbnails(ctx, time.Hour, 10)
+	if err != nil {
+		t.Fatalf("ClaimThumbnails error = %v", err)
+	}
+	if len(claimed) != 2 {
+		t.Fatalf("ClaimThumbnails = %+v, want the two pending files", claimed)
+	}
+	if again, err := repo.ClaimThumbnails(ctx, time.Hour, 10); err != nil || len(again) != 0 {
+		t.Fatalf("ClaimThumbnails during lease = %+v, %v, want none", again, err)
+	}
+
+	if err := repo.MarkThumbnailReady(ctx, ids[0], "thumb.png", "https://api.example.com/files/"+ids[0]+"/thumbnail"); err != nil {
+		t.Fatalf("MarkThumbnailReady error = %v", err)
+	}
+	// Percobaan ulang tanpa jeda langsung bisa diambil lagi
+	if err := repo.MarkThumbnailFailed(ctx, ids[1], 0, false); err != nil {
+		t.Fatalf("MarkThumbnailFailed error = %v", err)
+	}
+	retried, err := repo.ClaimThumbnails(ctx, time.Hour, 10)
+	if err != nil || len(retried) != 1 || retried[0].FileId != ids[1] || retried[0].ThumbnailAttempts != 1 {
+		t.Fatalf("ClaimThumbnails after retry = %+v, %v, want %s with one attempt", retried, err, ids[1])
+	}
+	if err := repo.MarkThumbnailFailed(ctx, ids[1], 0, true); err != nil {
+		t.Fatalf("MarkThumbnailFailed final error = %v", err)
+	}
+	if again, err := repo.ClaimThumbnails(ctx, time.Hour, 10); err != nil || len(again) != 0 {
+		t.Fatalf("ClaimThumbnails after final failure = %+v, %v, want none", again, err)
+	}
+
+	for id, want := range map[string]string{ids[0]: entity.ThumbnailReady, ids[1]: entity.ThumbnailFailed, ids[2]: ""} {
+		got, err := repo.Get(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.ThumbnailStatus != want {
+			t.Errorf("thumbnail status of %s = %q, want %q", id, got.ThumbnailStatus, want)
+		}
+	}
+	files, err := repo.FindByURIs(ctx, []string{"https://api.example.com/files/" + ids[0]})
+	if err != nil || len(files) != 1 || files[0].ThumbnailURI != "https://api.example.com/files/"+ids[0]+"/thumbnail" {
+		t.Fatalf("FindByURIs = %+v, %v, want the ready thumbnail uri", files, err)
+	}
+}
+
+// TestFileReferenceLifecycle mengikuti satu file dari upload sampai
+// dihapus: file yang dirujuk tidak dihapus, file yang dirujuk lagi tepat
+// sebelum dihapus dilewati, dan rujukan yang tidak tercatat di
+// referencecount tetap dicek sebelum file dihapus.
+func TestFileReferenceLifecycle(t *testing.T) {
+	pool := dbtest.New(t)
+	repo := New(pool, config.LoadQueryTimeoutConfig())
+	ctx := context.Background()
+	manager := dbtest.CreateManager(t, pool, "gc@example.com")
+	const fileID = "0190a8f2-7c1e-7d3a-9b4f-0000000000aa"
+	uri := "https://api.example.com/files/" + fileID
+	file := entity.File{
+		FileId:      fileID,
+		ManagerId:   manager,
+		FileName:    "avatar.png",
+		FileURI:     uri,
+		FileKey:     "file/" + manager + "/" + fileID + ".png",
+		Size:        1,
+		ContentType: "image/png",
+	}
+	if _, err := repo.Create(ctx, file); err != nil {
+		t.Fatal(err)
+	}
+
+	// backdate memindahkan unreferencedsince melewati grace period
+	backdate := func() {
+		t.Helper()
+		if _, err := pool.Exec(ctx, "UPDATE file SET unreferencedsince = CURRENT_TIMESTAMP - interval '2 hours' WHERE fileid = $1 AND unreferencedsince IS NOT NULL;", fileID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	collect := func() []entity.File {
+		t.Helper()
+		files, err := repo.DeleteUnreferenced(ctx, pool, time.Hour, 10)
+		if err != nil {
+			t.Fatalf("DeleteUnreferenced error = %v", err)
+		}
+		return files
+	}
+	adjust := func(added, removed []string) {
+		t.Helper()
+		if _, err := repo.AdjustReferences(ctx, pool, added, removed); err != nil {
+			t.Fatalf("AdjustReferences error = %v", err)
+		}
+	}
+
+	// File baru masih di dalam grace period
+	if files := collect(); len(files) != 0 {
+		t.Fatalf("DeleteUnreferenced = %+v, want none within the grace period", files)
+	}
+
+	// Dirujuk dua kali, lalu satu rujukan dilepas
+	adjust([]string{uri, uri}, nil)
+	adjust(nil, []string{uri})
+	backdate()
+	if files := collect(); len(files) != 0 {
+		t.Fatalf("DeleteUnreferenced = %+v, want none while referenced", files)
+	}
+
+	// Rujukan terakhir dilepas, lalu file dirujuk lagi tepat sebelum dihapus.
+	// Transaksi yang merujuk mengunci baris file, sehingga dilewati
+	adjust(nil, []string{uri})
+	backdate()
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if matched, err := repo.AdjustReferences(ctx, tx, []string{uri}, nil); err != nil || len(matched) != 1 {
+		t.Fatalf("AdjustReferences in transaction = %v, %v", matched, err)
+	}
+	if files := collect(); len(files) != 0 {
+		t.Fatalf("DeleteUnreferenced = %+v, want the locked file skipped", files)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if files := collect(); len(files) != 0 {
+		t.Fatalf("DeleteUnreferenced = %+v, want none after re-reference", files)
+	}
+
+	// referencecount yang meleset tidak membuat file yang masih dipakai
+	// employee terhapus
+	adjust(nil, []string{uri})
+	backdate()
+	department := dbtest.CreateDepartment(t, pool, manager, "Engineering")
+	dbtest.CreateEmployee(t, pool, dbtest.Employee{IdentityNumber: "EMP-1", Name: "Budi", Gender: "male", DepartmentID: department})
+	if _, err := pool.Exec(ctx, "UPDATE employees SET employeeimageuri = $1 WHERE identitynumber = 'EMP-1';", uri); err != nil {
+		t.Fatal(err)
+	}
+	if files := collect(); len(files) != 0 {
+		t.Fatalf("DeleteUnreferenced = %+v, want none while an employee uses the file", files)
+	}
+
+	if _, err := pool.Exec(ctx, "DELETE FROM employees WHERE identitynumber = 'EMP-1';"); err != nil {
+		t.Fatal(err)
+	}
+	files := collect()
+	if len(files) != 1 || files[0].FileKey != file.FileKey {
+		t.Fatalf("DeleteUnreferenced = %+v, want %s", files, file.FileKey)
+	}
+	if _, err := repo.Get(ctx, fileID); !errors.Is(err, helper.ErrNotFound) {
+		t.Fatalf("Get after DeleteUnreferenced error = %v, want %v", err, helper.ErrNotFound)
+	}
+	// Rujukan ke file yang sudah dihapus tidak cocok dengan baris mana pun
+	if matched, err := repo.AdjustReferences(ctx, pool, []string{uri}, nil); err != nil || len(matched) != 0 {
+		t.Fatalf("AdjustReferences after delete = %v, %v, want no match", matched, err)
+	}
+}
+
+// TestFindReferencesAndDelete memastikan rujukan dihitung dari tabel
+// employees dan manager, identityNumber manager lain tidak terlihat, dan
+// Delete menolak file yang masih dipakai atau milik manager lain.
+func TestFindReferencesAndDelete(t *testing.T) {
+	pool := dbtest.New(t)
+	repo := New(pool, config.LoadQueryTimeoutConfig())
+	ctx := context.Background()
+	owner := dbtest.CreateManager(t, pool, "owner@example.com")
+	other := dbtest.CreateManager(t, pool, "other@example.com")
+	const fileID = "0190a8f2-7c1e-7d3a-9b4f-0000000000bb"
+	uri := "https://api.example.com/files/" + fileID
+	file := entity.File{
+		FileId:      fileID,
+		ManagerId:   owner,
+		FileName:    "avatar.png",
+		FileURI:     uri,
+		FileKey:     "file/" + owner + "/" + fileID + ".png",
+		Size:        1,
+		ContentType: "image/png",
+	}
+	if _, err := repo.Create(ctx, file); err != nil {
+		t.Fatal(err)
+	}
+	exec := func(query string, args ...any) {
+		t.Helper()
+		if _, err := pool.Exec(ctx, query, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dbtest.CreateEmployee(t, pool, dbtest.Employee{IdentityNumber: "REF-OWNER", Name: "Budi", Gender: "male", DepartmentID: dbtest.CreateDepartment(t, pool, owner, "Owner")})
+	dbtest.CreateEmployee(t, pool, dbtest.Employee{IdentityNumber: "REF-OTHER", Name: "Sari", Gender: "female", DepartmentID: dbtest.CreateDepartment(t, pool, other, "Other")})
+	exec("UPDATE employees SET employeeimageuri = $1 WHERE identitynumber IN ('REF-OWNER', 'REF-OTHER');", uri)
+	exec("UPDATE manager SET userimageuri = $1 WHERE managerid = $2;", uri, owner)
+
+	refs, err := repo.FindReferences(ctx, uri, owner, 10)
+	if err != nil {
+		t.Fatalf("FindReferences error = %v", err)
+	}
+	if refs.Count != 3 || len(refs.IdentityNumbers) != 1 || refs.IdentityNumbers[0] != "REF-OWNER" ||
+		len(refs.ProfileFields) != 1 || refs.ProfileFields[0] != "userImageUri" {
+		t.Fatalf("FindReferences = %+v", refs)
+	}
+
+	if _, err := repo.Delete(ctx, fileID, other); !errors.Is(err, helper.ErrNotFound) {
+		t.Fatalf("Delete by another manager error = %v, want %v", err, helper.ErrNotFound)
+	}
+	// Setiap rujukan yang tersisa tetap mencegah file dihapus, termasuk
+	// employee manager lain
+	for _, release := range []string{
+		"UPDATE manager SET userimageuri = NULL WHERE email = 'owner@example.com';",
+		"UPDATE employees SET employeeimageuri = 'https://example.com/image.png' WHERE identitynumber = 'REF-OWNER';",
+		"UPDATE employees SET employeeimageuri = 'https://example.com/image.png' WHERE identitynumber = 'REF-OTHER';",
+	} {
+		if _, err := repo.Delete(ctx, fileID, owner); !errors.Is(err, helper.ErrNotFound) {
+			t.Fatalf("Delete referenced file error = %v, want %v", err, helper.ErrNotFound)
+		}
+		exec(release)
+	}
+
+	files, err := repo.GetAll(ctx, owner, 10, 0)
+	if err != nil || len(files) != 1 || files[0].FileId != fileID {
+		t.Fatalf("GetAll = %+v, %v, want the owner's file", files, err)
+	}
+	deleted, err := repo.Delete(ctx, fileID, owner)
+	if err != nil || deleted.FileKey != file.FileKey {
+		t.Fatalf("Delete = %+v, %v, want key %s", deleted, err, file.FileKey)
+	}
+	if _, err := repo.Get(ctx, fileID); !errors.Is(err, helper.ErrNotFound) {
+		t.Fatalf("Get after Delete error = %v, want %v", err, helper.ErrNotFound)
+	}
+	if files, err := repo.GetAll(ctx, other, 10, 0); err != nil || len(files) != 0 {
+		t.Fatalf("GetAll for another manager = %+v, %v, want none", files, err)
+	}
+}
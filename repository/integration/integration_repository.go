@@ -0,0 +1,92 @@
+package integrationRepository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/samber/do/v2"
+)
+
+// Nama query untuk log dan metric, lihat database.QueryLogTracer
+const (
+	queryIntegrationEnqueue         database.QueryName = "integration.enqueue_callback"
+	queryIntegrationDeleteProcessed database.QueryName = "integration.delete_processed"
+)
+
+type IntegrationRepository struct {
+	db       database.Querier
+	timeouts *config.QueryTimeoutConfig
+}
+
+type IntegrationRepositoryInterface interface {
+	// Enqueue menyimpan callback dan mengembalikan id-nya. Callback dengan
+	// signature yang sudah pernah diterima dari integrasi yang sama tidak
+	// disimpan (id 0).
+	Enqueue(ctx context.Context, callback entity.IntegrationCallback) (int64, error)
+	DeleteProcessed(ctx context.Context, db database.Querier, retention time.Duration, limit int) (int64, error)
+}
+
+func New(db database.Querier, timeouts *config.QueryTimeoutConfig) IntegrationRepository {
+	return IntegrationRepository{db: db, timeouts: timeouts}
+}
+
+func NewInject(i do.Injector) (IntegrationRepositoryInterface, error) {
+	cluster := do.MustInvoke[*database.Cluster](i)
+	repo := New(cluster.Writer(), config.LoadQueryTimeoutConfig())
+	return &repo, nil
+}
+
+func (r *IntegrationRepository) Enqueue(ctx context.Context, callback entity.IntegrationCallback) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryIntegrationEnqueue)
+
+	query := `
+		INSERT INTO integration_callback (integration, signature, signed_at, payload)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT ON CONSTRAINT integration_callback_signature_key DO NOTHING
+		RETURNING id;
+	`
+	var id int64
+	err := r.db.QueryRow(ctx, query, callback.Integration, callback.Signature, callback.SignedAt.UTC(), callback.Payload).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, database.QueryError(ctx, err)
+	}
+	return id, nil
+}
+
+// DeleteProcessed menghapus paling banyak limit callback yang sudah diproses
+// dan ditandatangani lebih lama dari retention, lewat db milik pemanggil
+// seperti outboxRepository.DeleteSent. Callback yang belum diproses tidak
+// pernah dihapus. retention harus lebih lama dari toleransi timestamp
+// signature, agar replay callback yang dihapus tetap ditolak.
+func (r *IntegrationRepository) DeleteProcessed(ctx context.Context, db database.Querier, retention time.Duration, limit int) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryIntegrationDeleteProcessed)
+
+	query := `
+		DELETE FROM integration_callback
+		WHERE id IN (
+			SELECT id
+			FROM integration_callback
+			WHERE processed_at IS NOT NULL AND signed_at < CURRENT_TIMESTAMP - $1::interval
+			ORDER BY signed_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		);
+	`
+	tag, err := db.Exec(ctx, query, retention, limit)
+	if err != nil {
+		return 0, database.QueryError(ctx, err)
+	}
+	return tag.RowsAffected(), nil
+}
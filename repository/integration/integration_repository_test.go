@@ -0,0 +1,52 @@
+//go:build integration
+
+package integrationRepository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/entity"
+)
+
+func TestEnqueueRejectsDuplicateSignature(t *testing.T) {
+	pool := dbtest.New(t)
+	repo := New(pool, config.LoadQueryTimeoutConfig())
+	ctx := context.Background()
+	signedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	callback := entity.IntegrationCallback{
+		Integration: "partner-a",
+		Signature:   "sha256=ab12",
+		SignedAt:    signedAt,
+		Payload:     []byte{0x00, '{', '}', 0xff},
+	}
+
+	first, err := repo.Enqueue(ctx, callback)
+	if err != nil || first == 0 {
+		t.Fatalf("Enqueue = %d, %v", first, err)
+	}
+	// Replay tidak disimpan dan tidak dianggap error
+	if id, err := repo.Enqueue(ctx, callback); err != nil || id != 0 {
+		t.Fatalf("Enqueue replay = %d, %v, want 0, nil", id, err)
+	}
+	// Signature yang sama dari integrasi lain tetap disimpan
+	other := callback
+	other.Integration = "partner-b"
+	if id, err := repo.Enqueue(ctx, other); err != nil || id == 0 || id == first {
+		t.Fatalf("Enqueue other integration = %d, %v", id, err)
+	}
+
+	var payload []byte
+	var storedAt time.Time
+	err = pool.QueryRow(ctx, "SELECT payload, signed_at FROM integration_callback WHERE id = $1;", first).Scan(&payload, &storedAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Byte body disimpan apa adanya untuk verifikasi ulang
+	if string(payload) != string(callback.Payload) || !storedAt.Equal(signedAt) {
+		t.Fatalf("stored payload = %v, signed_at = %v", payload, storedAt)
+	}
+}
@@ -0,0 +1,171 @@
+package outboxRepository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/entity"
+)
+
+// Nama query untuk log dan metric, lihat database.QueryLogTracer
+const (
+	queryOutboxAdd        database.QueryName = "outbox.add"
+	queryOutboxClaim      database.QueryName = "outbox.claim"
+	queryOutboxMarkSent   database.QueryName = "outbox.mark_sent"
+	queryOutboxMarkFailed database.QueryName = "outbox.mark_failed"
+	queryOutboxDeleteSent database.QueryName = "outbox.delete_sent"
+)
+
+type OutboxRepository struct {
+	db       database.Querier
+	timeouts *config.QueryTimeoutConfig
+}
+
+// OutboxRepositoryInterface berisi method OutboxRepository. Add harus
+// dipanggil dengan repository yang terikat ke transaksi perubahan datanya,
+// lihat repository.UnitOfWork.
+type OutboxRepositoryInterface interface {
+	Add(ctx context.Context, eventType, aggregateType, aggregateId string, payload any) error
+	Claim(ctx context.Context, limit int) ([]entity.OutboxEvent, error)
+	MarkSent(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, retryAfter time.Duration, lastError string) error
+	DeleteSent(ctx context.Context, db database.Querier, retention time.Duration, limit int) (int64, error)
+}
+
+func New(db database.Querier, timeouts *config.QueryTimeoutConfig) OutboxRepository {
+	return OutboxRepository{db: db, timeouts: timeouts}
+}
+
+func (r *OutboxRepository) Add(ctx context.Context, eventType, aggregateType, aggregateId string, payload any) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryOutboxAdd)
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO outbox (event_type, aggregate_type, aggregate_id, payload)
+		VALUES ($1, $2, $3, $4);
+	`
+	if _, err := r.db.Exec(ctx, query, eventType, aggregateType, aggregateId, data); err != nil {
+		return database.QueryError(ctx, err)
+	}
+	return nil
+}
+
+// Claim mengunci event yang siap dikirim, paling banyak satu per aggregate:
+// event yang masih punya event lebih lama yang belum terkirim di aggregate
+// yang sama tidak diambil, sehingga urutan per aggregate terjaga walau
+// pengiriman gagal dan diulang. SKIP LOCKED membuat beberapa instance bisa
+// menjalankan dispatcher bersamaan tanpa mengirim event yang sama. Harus
+// dipanggil di dalam transaksi; lock dilepas saat commit.
+func (r *OutboxRepository) Claim(ctx context.Context, limit int) ([]entity.OutboxEvent, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryOutboxClaim)
+
+	query := `
+		SELECT o.id, o.event_type, o.aggregate_type, o.aggregate_id, o.payload, o.created_at, o.attempts
+		FROM outbox o
+		WHERE
+			o.sent_at IS NULL
+			AND o.next_attempt_at <= CURRENT_TIMESTAMP
+			AND NOT EXISTS (
+				SELECT 1
+				FROM outbox p
+				WHERE
+					p.aggregate_type = o.aggregate_type
+					AND p.aggregate_id = o.aggregate_id
+					AND p.sent_at IS NULL
+					AND p.id < o.id
+			)
+		ORDER BY o.id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED;
+	`
+	rows, err := r.db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	events, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.OutboxEvent, error) {
+		var event entity.OutboxEvent
+		err := row.Scan(
+			&event.Id,
+			&event.EventType,
+			&event.AggregateType,
+			&event.AggregateId,
+			&event.Payload,
+			&event.CreatedAt,
+			&event.Attempts,
+		)
+		return event, err
+	})
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	return events, nil
+}
+
+func (r *OutboxRepository) MarkSent(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryOutboxMarkSent)
+
+	query := "UPDATE outbox SET sent_at = CURRENT_TIMESTAMP, last_error = NULL WHERE id = $1;"
+	if _, err := r.db.Exec(ctx, query, id); err != nil {
+		return database.QueryError(ctx, err)
+	}
+	return nil
+}
+
+// MarkFailed menunda event selama retryAfter, dihitung dari jam database
+// karena kolomnya tanpa zona waktu. Event berikutnya di aggregate yang sama
+// ikut tertunda, lihat Claim.
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id int64, retryAfter time.Duration, lastError string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryOutboxMarkFailed)
+
+	query := `
+		UPDATE outbox
+		SET attempts = attempts + 1, next_attempt_at = CURRENT_TIMESTAMP + $2::interval, last_error = $3
+		WHERE id = $1;
+	`
+	if _, err := r.db.Exec(ctx, query, id, retryAfter, lastError); err != nil {
+		return database.QueryError(ctx, err)
+	}
+	return nil
+}
+
+// DeleteSent menghapus paling banyak limit event yang sudah terkirim lebih
+// lama dari retention. Dijalankan lewat db milik pemanggil (koneksi yang
+// memegang lock cleanup), bukan r.db. Baris yang sedang dikunci dilewati.
+func (r *OutboxRepository) DeleteSent(ctx context.Context, db database.Querier, retention time.Duration, limit int) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryOutboxDeleteSent)
+
+	query := `
+		DELETE FROM outbox
+		WHERE id IN (
+			SELECT id
+			FROM outbox
+			WHERE sent_at < CURRENT_TIMESTAMP - $1::interval
+			ORDER BY sent_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		);
+	`
+	tag, err := db.Exec(ctx, query, retention, limit)
+	if err != nil {
+		return 0, database.QueryError(ctx, err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	auditRepository "github.com/levensspel/go-gin-template/repository/audit"
+	departmentRepository "github.com/levensspel/go-gin-template/repository/department"
+	employeeRepository "github.com/levensspel/go-gin-template/repository/employee"
+	fileRepository "github.com/levensspel/go-gin-template/repository/file"
+	outboxRepository "github.com/levensspel/go-gin-template/repository/outbox"
+	userRepository "github.com/levensspel/go-gin-template/repository/user"
+	"github.com/samber/do/v2"
+)
+
+// Repositories berisi repository yang terikat ke satu transaksi. Semua
+// query, termasuk query baca, berjalan di transaksi tersebut.
+type Repositories struct {
+	Employee   employeeRepository.EmployeeRepositoryInterface
+	Department departmentRepository.DepartmentRepositoryInterface
+	User       userRepository.UserRepositoryInterface
+	Outbox     outboxRepository.OutboxRepositoryInterface
+	Audit      auditRepository.AuditRepositoryInterface
+	File       fileRepository.FileRepositoryInterface
+	// Tx adalah transaksinya sendiri, untuk method repository yang menerima
+	// database.Querier (mis. CreateMany).
+	Tx database.Querier
+}
+
+// UnitOfWork menjalankan beberapa repository dalam satu transaksi. Jika fn
+// mengembalikan error atau panic, semua perubahan di-rollback.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(repos Repositories) error) error
+	DoTx(ctx context.Context, txOptions pgx.TxOptions, fn func(repos Repositories) error) error
+}
+
+type unitOfWork struct {
+	db       database.DB
+	timeouts *config.QueryTimeoutConfig
+	search   *config.SearchConfig
+}
+
+func NewUnitOfWork(db database.DB, timeouts *config.QueryTimeoutConfig, search *config.SearchConfig) UnitOfWork {
+	return &unitOfWork{db: db, timeouts: timeouts, search: search}
+}
+
+func NewUnitOfWorkInject(i do.Injector) (UnitOfWork, error) {
+	cluster := do.MustInvoke[*database.Cluster](i)
+	search := do.MustInvoke[*config.SearchConfig](i)
+	return NewUnitOfWork(cluster.Writer(), config.LoadQueryTimeoutConfig(), search), nil
+}
+
+func (u *unitOfWork) Do(ctx context.Context, fn func(repos Repositories) error) error {
+	return u.DoTx(ctx, pgx.TxOptions{}, fn)
+}
+
+// DoTx seperti Do dengan opsi transaksi tertentu. Transaksi serializable
+// yang gagal karena serialization failure diulang satu kali, lihat
+// database.WithTx.
+func (u *unitOfWork) DoTx(ctx context.Context, txOptions pgx.TxOptions, fn func(repos Repositories) error) error {
+	return database.WithTx(ctx, u.db, txOptions, func(tx pgx.Tx) error {
+		employee := employeeRepository.NewEmployeeRepository(tx, tx, u.timeouts, u.search)
+		department := departmentRepository.New(tx, tx, u.timeouts)
+		user := userRepository.NewUserRepository(tx, tx, u.timeouts)
+		outbox := outboxRepository.New(tx, u.timeouts)
+		audit := auditRepository.New(tx, tx, u.timeouts)
+		file := fileRepository.New(tx, u.timeouts)
+		return fn(Repositories{
+			Employee:   &employee,
+			Department: &department,
+			User:       &user,
+			Outbox:     &outbox,
+			Audit:      &audit,
+			File:       &file,
+			Tx:         tx,
+		})
+	})
+}
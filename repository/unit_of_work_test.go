@@ -0,0 +1,150 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+type unitOfWorkFixture struct {
+	pool       *pgxpool.Pool
+	uow        UnitOfWork
+	manager    string
+	department string
+}
+
+func newUnitOfWorkFixture(t *testing.T) unitOfWorkFixture {
+	t.Helper()
+	pool := dbtest.New(t)
+	f := unitOfWorkFixture{pool: pool, uow: NewUnitOfWork(pool, config.LoadQueryTimeoutConfig(), &config.SearchConfig{})}
+	f.manager = dbtest.CreateManager(t, pool, "uow@example.com")
+	f.department = dbtest.CreateDepartment(t, pool, f.manager, "Engineering")
+	return f
+}
+
+// createWithVersion meng-insert employee beserta versi pertamanya, seperti
+// EmployeeService.Create.
+func (f unitOfWorkFixture) createWithVersion(ctx context.Context, repos Repositories, identityNumber string) error {
+	input := dto.EmployeePayload{
+		IdentityNumber:   identityNumber,
+		Name:             "Employee " + identityNumber,
+		EmployeeImageUri: "https://example.com/image.png",
+		Gender:           "male",
+		DepartmentID:     f.department,
+	}
+	if _, err := repos.Employee.Create(ctx, &input, f.manager); err != nil {
+		return err
+	}
+	return repos.Employee.AddVersion(ctx, identityNumber, entity.AuditActionCreate, f.manager)
+}
+
+// rows menghitung employee dan versinya dengan identityNumber tersebut.
+func (f unitOfWorkFixture) rows(t *testing.T, identityNumber string) (employees, versions int) {
+	t.Helper()
+	ctx := context.Background()
+	if err := f.pool.QueryRow(ctx, "SELECT COUNT(*) FROM employees WHERE identitynumber = $1;", identityNumber).Scan(&employees); err != nil {
+		t.Fatalf("count employees: %v", err)
+	}
+	if err := f.pool.QueryRow(ctx, "SELECT COUNT(*) FROM employee_versions WHERE identitynumber = $1;", identityNumber).Scan(&versions); err != nil {
+		t.Fatalf("count employee versions: %v", err)
+	}
+	return employees, versions
+}
+
+func TestUnitOfWorkCommits(t *testing.T) {
+	f := newUnitOfWorkFixture(t)
+	err := f.uow.Do(context.Background(), func(repos Repositories) error {
+		return f.createWithVersion(context.Background(), repos, "COMMIT-1")
+	})
+	if err != nil {
+		t.Fatalf("Do error = %v", err)
+	}
+	if employees, versions := f.rows(t, "COMMIT-1"); employees != 1 || versions != 1 {
+		t.Fatalf("after commit: %d employees, %d versions, want 1 and 1", employees, versions)
+	}
+}
+
+func TestUnitOfWorkRollsBack(t *testing.T) {
+	errFailed := errors.New("failed after insert")
+	for _, tt := range []struct {
+		name    string
+		fn      func(ctx context.Context, f unitOfWorkFixture, repos Repositories) error
+		wantErr error
+	}{
+		{
+			name: "fn returns an error",
+			fn: func(ctx context.Context, f unitOfWorkFixture, repos Repositories) error {
+				if err := f.createWithVersion(ctx, repos, "ROLLBACK-1"); err != nil {
+					return err
+				}
+				return errFailed
+			},
+			wantErr: errFailed,
+		},
+		{
+			// Conflict di statement kedua membatalkan insert pertama
+			name: "later statement conflicts",
+			fn: func(ctx context.Context, f unitOfWorkFixture, repos Repositories) error {
+				if err := f.createWithVersion(ctx, repos, "ROLLBACK-1"); err != nil {
+					return err
+				}
+				return f.createWithVersion(ctx, repos, "ROLLBACK-1")
+			},
+			wantErr: helper.ErrConflictIdentityNumber,
+		},
+		{
+			name: "later statement uses an invalid department",
+			fn: func(ctx context.Context, f unitOfWorkFixture, repos Repositories) error {
+				if err := f.createWithVersion(ctx, repos, "ROLLBACK-1"); err != nil {
+					return err
+				}
+				department := "00000000-0000-0000-0000-000000000000"
+				_, err := repos.Employee.Update(ctx, "ROLLBACK-1", &dto.EmployeeUpdatePayload{DepartmentID: &department}, f.manager)
+				return err
+			},
+			wantErr: helper.ErrInvalidDepartmentId,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newUnitOfWorkFixture(t)
+			ctx := context.Background()
+			err := f.uow.Do(ctx, func(repos Repositories) error { return tt.fn(ctx, f, repos) })
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Do error = %v, want %v", err, tt.wantErr)
+			}
+			if employees, versions := f.rows(t, "ROLLBACK-1"); employees != 0 || versions != 0 {
+				t.Fatalf("after rollback: %d employees, %d versions, want none", employees, versions)
+			}
+		})
+	}
+}
+
+func TestUnitOfWorkRollsBackOnPanic(t *testing.T) {
+	f := newUnitOfWorkFixture(t)
+	ctx := context.Background()
+	func() {
+		defer func() {
+			if recovered := recover(); recovered != "boom" {
+				t.Fatalf("recovered = %v, want the original panic", recovered)
+			}
+		}()
+		_ = f.uow.Do(ctx, func(repos Repositories) error {
+			if err := f.createWithVersion(ctx, repos, "PANIC-1"); err != nil {
+				t.Errorf("createWithVersion error = %v", err)
+			}
+			panic("boom")
+		})
+	}()
+	if employees, versions := f.rows(t, "PANIC-1"); employees != 0 || versions != 0 {
+		t.Fatalf("after panic: %d employees, %d versions, want none", employees, versions)
+	}
+}
@@ -2,33 +2,74 @@ package userRepository
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"time"
 
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
 	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
 	"github.com/samber/do/v2"
 )
 
+// Nama query untuk log dan metric, lihat database.QueryLogTracer
+const (
+	queryUserCreate         database.QueryName = "user.create"
+	queryUserUpdate         database.QueryName = "user.update"
+	queryUserUpsertUser     database.QueryName = "user.upsert"
+	queryUserGetAllUsers    database.QueryName = "user.get_all"
+	queryUserGetUserbyEmail database.QueryName = "user.get_by_email"
+	queryUserDelete         database.QueryName = "user.delete"
+	queryUserGetProfile     database.QueryName = "user.get_profile"
+	queryUserUpdateProfile  database.QueryName = "user.update_profile"
+	queryUserUpdatePassword database.QueryName = "user.update_password"
+	queryUserRevokeSessions database.QueryName = "user.revoke_sessions"
+	queryUserRevokedAt      database.QueryName = "user.sessions_revoked_at"
+)
+
 type UserRepository struct {
-	db *pgxpool.Pool
+	db database.Querier
+	// reader dipakai untuk query daftar yang boleh dilayani read replica
+	reader   database.Querier
+	timeouts *config.QueryTimeoutConfig
+}
+
+// UserRepositoryInterface berisi method UserRepository yang dipakai service,
+// sehingga service bisa dites dengan mocks.UserRepository.
+type UserRepositoryInterface interface {
+	Create(ctx context.Context, user entity.User) (managerId string, err error)
+	Update(ctx context.Context, user entity.User) error
+	GetUserbyEmail(ctx context.Context, email string) ([]entity.User, error)
+	Delete(ctx context.Context, id string) error
+	GetProfile(ctx context.Context, id string) (*entity.GetProfile, error)
+	UpdateProfile(ctx context.Context, id string, data *entity.GetProfile) error
+	UpdatePassword(ctx context.Context, id string, passwordHash string) error
+	RevokeSessions(ctx context.Context, id string, at time.Time) error
+	SessionsRevokedAt(ctx context.Context, id string) (time.Time, error)
 }
 
-func NewUserRepository(db *pgxpool.Pool) UserRepository {
-	return UserRepository{db: db}
+func NewUserRepository(db database.Querier, reader database.Querier, timeouts *config.QueryTimeoutConfig) UserRepository {
+	return UserRepository{db: db, reader: reader, timeouts: timeouts}
 }
 
-func NewUserRepositoryInject(i do.Injector) (UserRepository, error) {
-	db := do.MustInvoke[*pgxpool.Pool](i)
-	return NewUserRepository(db), nil
+func NewUserRepositoryInject(i do.Injector) (UserRepositoryInterface, error) {
+	cluster := do.MustInvoke[*database.Cluster](i)
+	repo := NewUserRepository(cluster.Writer(), cluster.Reader(), config.LoadQueryTimeoutConfig())
+	return &repo, nil
 }
 
 func (r *UserRepository) Create(ctx context.Context, user entity.User) (managerId string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryUserCreate)
+
 	query := `
 		INSERT INTO manager (email, password)
 		VALUES ($1, $2)
 		RETURNING manager.managerid
 	`
-	fmt.Printf("email %s, password %s", user.Email.String, user.Password)
 	row := r.db.QueryRow(ctx, query,
 		user.Email.String, // Email yang unik
 		user.Password,     // Kata sandi
@@ -36,12 +77,16 @@ func (r *UserRepository) Create(ctx context.Context, user entity.User) (managerI
 
 	err = row.Scan(&managerId)
 	if err != nil {
-		return "", err
+		return "", database.QueryError(ctx, err)
 	}
 
 	return managerId, err
 }
 func (r *UserRepository) Update(ctx context.Context, user entity.User) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryUserUpdate)
+
 	query := `
 		UPDATE manager
 		SET name = $2, password = $3, updated_at = $4
@@ -53,10 +98,14 @@ func (r *UserRepository) Update(ctx context.Context, user entity.User) error {
 		user.Password,    // Kata sandi
 		user.UpdatedAt,   // Timestamp saat ini
 	)
-	return err
+	return database.QueryError(ctx, err)
 }
 
 func (r *UserRepository) UpsertUser(ctx context.Context, user entity.User) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryUserUpsertUser)
+
 	query := `
 		INSERT INTO manager (identitynumber, name, username, email, password, updated_at, created_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
@@ -78,14 +127,18 @@ func (r *UserRepository) UpsertUser(ctx context.Context, user entity.User) error
 		user.UpdatedAt,       // Timestamp saat ini
 		user.CreatedAt,       // Timestamp saat dibuat
 	)
-	return err
+	return database.QueryError(ctx, err)
 }
 
 func (r *UserRepository) GetAllUsers(ctx context.Context) ([]entity.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryUserGetAllUsers)
+
 	query := `SELECT managerid, name, email FROM manager`
-	rows, err := r.db.Query(ctx, query)
+	rows, err := r.reader.Query(ctx, query)
 	if err != nil {
-		return nil, err
+		return nil, database.QueryError(ctx, err)
 	}
 	defer rows.Close()
 
@@ -94,7 +147,7 @@ func (r *UserRepository) GetAllUsers(ctx context.Context) ([]entity.User, error)
 		var user entity.User
 		err := rows.Scan(&user.Id, &user.Name, &user.Email)
 		if err != nil {
-			return nil, err
+			return nil, database.QueryError(ctx, err)
 		}
 		users = append(users, user)
 	}
@@ -102,12 +155,15 @@ func (r *UserRepository) GetAllUsers(ctx context.Context) ([]entity.User, error)
 }
 
 func (r *UserRepository) GetUserbyEmail(ctx context.Context, email string) ([]entity.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Lookup)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryUserGetUserbyEmail)
+
 	// Menggunakan Query bukan Exec karena kita mengambil hasil dari SELECT
 	query := `SELECT u.managerid, u.name, u.email, u.password FROM manager u WHERE u.email = $1`
-	fmt.Printf("\nthefuck email %s\n", email)
 	rows, err := r.db.Query(ctx, query, email)
 	if err != nil {
-		return nil, err
+		return nil, database.QueryError(ctx, err)
 	}
 	defer rows.Close()
 
@@ -116,26 +172,34 @@ func (r *UserRepository) GetUserbyEmail(ctx context.Context, email string) ([]en
 		var user entity.User
 		// Menyimpan data hasil query ke dalam struct user
 		if err := rows.Scan(&user.Id, &user.Name, &user.Email, &user.Password); err != nil {
-			return nil, err
+			return nil, database.QueryError(ctx, err)
 		}
 		users = append(users, user)
 	}
 
 	// Memastikan tidak ada error saat iterasi
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, database.QueryError(ctx, err)
 	}
 
 	return users, nil
 }
 
 func (r *UserRepository) Delete(ctx context.Context, id string) error {
-	query := `DELETE FROM manager WHERE managerid = ?`
-	_, err := r.db.Query(ctx, query, id)
-	return err
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryUserDelete)
+
+	query := `DELETE FROM manager WHERE managerid = $1`
+	_, err := r.db.Exec(ctx, query, id)
+	return database.QueryError(ctx, err)
 }
 
 func (r *UserRepository) GetProfile(ctx context.Context, id string) (*entity.GetProfile, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Lookup)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryUserGetProfile)
+
 	row := r.db.QueryRow(
 		ctx,
 		`SELECT email, name, userImageUri, companyName, companyImageUri FROM manager WHERE managerid = $1`,
@@ -145,7 +209,7 @@ func (r *UserRepository) GetProfile(ctx context.Context, id string) (*entity.Get
 	var user entity.GetProfile
 	err := row.Scan(&user.Email, &user.Name, &user.UserImageUri, &user.CompanyName, &user.CompanyImageUri)
 	if err != nil {
-		return nil, err
+		return nil, database.QueryError(ctx, err)
 	}
 
 	return &user, nil
@@ -153,6 +217,10 @@ func (r *UserRepository) GetProfile(ctx context.Context, id string) (*entity.Get
 }
 
 func (r *UserRepository) UpdateProfile(ctx context.Context, id string, data *entity.GetProfile) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryUserUpdateProfile)
+
 	_, err := r.db.Exec(
 		ctx,
 		`UPDATE manager SET 
@@ -169,5 +237,70 @@ func (r *UserRepository) UpdateProfile(ctx context.Context, id string, data *ent
 		data.CompanyImageUri.String,
 		id,
 	)
-	return err
+	return database.QueryError(ctx, err)
+}
+
+// UpdatePassword mengganti hash password manager, atau helper.ErrNotFound.
+func (r *UserRepository) UpdatePassword(ctx context.Context, id string, passwordHash string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryUserUpdatePassword)
+
+	tag, err := r.db.Exec(
+		ctx,
+		`UPDATE manager SET password = $2, updated_at = CURRENT_TIMESTAMP WHERE managerid = $1`,
+		id,
+		passwordHash,
+	)
+	if err != nil {
+		return database.QueryError(ctx, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return database.QueryError(ctx, helper.ErrNotFound)
+	}
+	return nil
+}
+
+// RevokeSessions mencatat bahwa semua token manager yang diterbitkan
+// sampai at tidak berlaku lagi. at disimpan dalam UTC.
+func (r *UserRepository) RevokeSessions(ctx context.Context, id string, at time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryUserRevokeSessions)
+
+	tag, err := r.db.Exec(
+		ctx,
+		`UPDATE manager SET sessions_revoked_at = $2 WHERE managerid = $1`,
+		id,
+		at.UTC(),
+	)
+	if err != nil {
+		return database.QueryError(ctx, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return database.QueryError(ctx, helper.ErrNotFound)
+	}
+	return nil
+}
+
+// SessionsRevokedAt mengembalikan waktu terakhir sesi manager dicabut, atau
+// zero time jika belum pernah atau manager tidak ditemukan. Dibaca dari
+// primary agar pencabutan tidak tertunda replication lag.
+func (r *UserRepository) SessionsRevokedAt(ctx context.Context, id string) (time.Time, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Lookup)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryUserRevokedAt)
+
+	var revokedAt *time.Time
+	err := r.db.QueryRow(ctx, `SELECT sessions_revoked_at FROM manager WHERE managerid = $1`, id).Scan(&revokedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, database.QueryError(ctx, err)
+	}
+	if revokedAt == nil {
+		return time.Time{}, nil
+	}
+	return *revokedAt, nil
 }
@@ -0,0 +1,76 @@
+//go:build integration
+
+package userRepository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+const unknownManagerID = "5d1f0c7a-2b3e-4c4d-8e9f-0a1b2c3d4e5f"
+
+func TestCreateAndUpdatePassword(t *testing.T) {
+	pool := dbtest.New(t)
+	repo := NewUserRepository(pool, pool, config.LoadQueryTimeoutConfig())
+	ctx := context.Background()
+
+	user := entity.User{Password: "hash-1"}
+	user.Email.String = "create@example.com"
+	id, err := repo.Create(ctx, user)
+	if err != nil || id == "" {
+		t.Fatalf("Create = %q, %v", id, err)
+	}
+	// Email unik, cmd/admin memetakan 23505 ke helper.ErrConflict
+	var pgErr *pgconn.PgError
+	if _, err := repo.Create(ctx, user); !errors.As(err, &pgErr) || pgErr.Code != "23505" {
+		t.Fatalf("duplicate Create error = %v, want unique violation", err)
+	}
+
+	if err := repo.UpdatePassword(ctx, id, "hash-2"); err != nil {
+		t.Fatalf("UpdatePassword error = %v", err)
+	}
+	users, err := repo.GetUserbyEmail(ctx, "create@example.com")
+	if err != nil || len(users) != 1 || users[0].Id != id || users[0].Password != "hash-2" {
+		t.Fatalf("GetUserbyEmail = %+v, %v, want the new password hash", users, err)
+	}
+	if err := repo.UpdatePassword(ctx, unknownManagerID, "hash-3"); !errors.Is(err, helper.ErrNotFound) {
+		t.Fatalf("UpdatePassword unknown manager error = %v, want %v", err, helper.ErrNotFound)
+	}
+}
+
+func TestRevokeSessions(t *testing.T) {
+	pool := dbtest.New(t)
+	repo := NewUserRepository(pool, pool, config.LoadQueryTimeoutConfig())
+	ctx := context.Background()
+	id := dbtest.CreateManager(t, pool, "revoke@example.com")
+
+	if revokedAt, err := repo.SessionsRevokedAt(ctx, id); err != nil || !revokedAt.IsZero() {
+		t.Fatalf("SessionsRevokedAt before revoke = %v, %v, want zero", revokedAt, err)
+	}
+
+	// Waktu di zona lain disimpan sebagai UTC
+	at := time.Date(2026, 1, 2, 10, 4, 5, 0, time.FixedZone("WIB", 7*60*60))
+	if err := repo.RevokeSessions(ctx, id, at); err != nil {
+		t.Fatalf("RevokeSessions error = %v", err)
+	}
+	revokedAt, err := repo.SessionsRevokedAt(ctx, id)
+	if err != nil || !revokedAt.Equal(at) {
+		t.Fatalf("SessionsRevokedAt = %v, %v, want %v", revokedAt, err, at)
+	}
+
+	if err := repo.RevokeSessions(ctx, unknownManagerID, at); !errors.Is(err, helper.ErrNotFound) {
+		t.Fatalf("RevokeSessions unknown manager error = %v, want %v", err, helper.ErrNotFound)
+	}
+	// Manager yang tidak ada dianggap belum pernah dicabut
+	if revokedAt, err := repo.SessionsRevokedAt(ctx, unknownManagerID); err != nil || !revokedAt.IsZero() {
+		t.Fatalf("SessionsRevokedAt unknown manager = %v, %v, want zero", revokedAt, err)
+	}
+}
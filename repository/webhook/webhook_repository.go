@@ -0,0 +1,330 @@
+package webhookRepository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/samber/do/v2"
+)
+
+// Nama query untuk log dan metric, lihat database.QueryLogTracer
+const (
+	queryWebhookCreate         database.QueryName = "webhook.create"
+	queryWebhookGetAll         database.QueryName = "webhook.get_all"
+	queryWebhookGet            database.QueryName = "webhook.get"
+	queryWebhookDelete         database.QueryName = "webhook.delete"
+	queryWebhookMatching       database.QueryName = "webhook.matching"
+	queryWebhookAddDelivery    database.QueryName = "webhook.add_delivery"
+	queryWebhookClaim          database.QueryName = "webhook.claim_deliveries"
+	queryWebhookMarkDelivered  database.QueryName = "webhook.mark_delivered"
+	queryWebhookMarkFailed     database.QueryName = "webhook.mark_failed"
+	queryWebhookGetDeliveries  database.QueryName = "webhook.get_deliveries"
+	queryWebhookDeleteFinished database.QueryName = "webhook.delete_finished"
+	webhookColumns                                = "id, manager_id, url, secret, events, active, created_at"
+	deliveryColumns                               = "d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.status, d.attempts, d.next_attempt_at, d.last_status_code, d.last_error, d.created_at, d.delivered_at"
+)
+
+// WebhookRepository membaca dari primary, agar webhook yang baru dibuat
+// langsung ikut menerima event.
+type WebhookRepository struct {
+	db       database.Querier
+	timeouts *config.QueryTimeoutConfig
+}
+
+type WebhookRepositoryInterface interface {
+	Create(ctx context.Context, webhook entity.Webhook) (entity.Webhook, error)
+	GetAll(ctx context.Context, managerId string) ([]entity.Webhook, error)
+	Get(ctx context.Context, id, managerId string) (entity.Webhook, error)
+	Delete(ctx context.Context, id, managerId string) error
+	// Matching mengembalikan webhook aktif milik manager yang melanggan
+	// eventType.
+	Matching(ctx context.Context, managerId, eventType string) ([]entity.Webhook, error)
+	// AddDelivery mengantrikan delivery dan mengembalikan id-nya. Delivery
+	// event yang sudah ada untuk webhook yang sama dilewati (id 0).
+	AddDelivery(ctx context.Context, delivery entity.WebhookDelivery) (int64, error)
+	ClaimDeliveries(ctx context.Context, limit int) ([]entity.WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, id int64, statusCode int) error
+	MarkFailed(ctx context.Context, id int64, retryAfter time.Duration, statusCode int, lastError string, dead bool) error
+	GetDeliveries(ctx context.Context, webhookId string, limit, offset int) ([]entity.WebhookDelivery, error)
+	DeleteFinished(ctx context.Context, db database.Querier, retention time.Duration, limit int) (int64, error)
+}
+
+func New(db database.Querier, timeouts *config.QueryTimeoutConfig) WebhookRepository {
+	return WebhookRepository{db: db, timeouts: timeouts}
+}
+
+func NewInject(i do.Injector) (WebhookRepositoryInterface, error) {
+	cluster := do.MustInvoke[*database.Cluster](i)
+	repo := New(cluster.Writer(), config.LoadQueryTimeoutConfig())
+	return &repo, nil
+}
+
+func (r *WebhookRepository) Create(ctx context.Context, webhook entity.Webhook) (entity.Webhook, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryWebhookCreate)
+
+	query := `
+		INSERT INTO webhook (id, manager_id, url, secret, events)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + webhookColumns + `;
+	`
+	row := r.db.QueryRow(ctx, query, webhook.Id, webhook.ManagerId, webhook.Url, webhook.Secret, webhook.Events)
+	created, err := scanWebhook(row)
+	if err != nil {
+		return entity.Webhook{}, database.QueryError(ctx, err)
+	}
+	return created, nil
+}
+
+func (r *WebhookRepository) GetAll(ctx context.Context, managerId string) ([]entity.Webhook, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryWebhookGetAll)
+
+	query := "SELECT " + webhookColumns + " FROM webhook WHERE manager_id = $1 ORDER BY created_at, id;"
+	return r.queryWebhooks(ctx, query, managerId)
+}
+
+// Get mengembalikan helper.ErrNotFound jika webhook tidak ada atau milik
+// manager lain.
+func (r *WebhookRepository) Get(ctx context.Context, id, managerId string) (entity.Webhook, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Lookup)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryWebhookGet)
+
+	query := "SELECT " + webhookColumns + " FROM webhook WHERE id = $1 AND manager_id = $2;"
+	webhook, err := scanWebhook(r.db.QueryRow(ctx, query, id, managerId))
+	if err == pgx.ErrNoRows {
+		return entity.Webhook{}, database.QueryError(ctx, helper.ErrNotFound)
+	}
+	if err != nil {
+		return entity.Webhook{}, database.QueryError(ctx, err)
+	}
+	return webhook, nil
+}
+
+// Delete ikut menghapus riwayat delivery webhook tersebut.
+func (r *WebhookRepository) Delete(ctx context.Context, id, managerId string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryWebhookDelete)
+
+	tag, err := r.db.Exec(ctx, "DELETE FROM webhook WHERE id = $1 AND manager_id = $2;", id, managerId)
+	if err != nil {
+		return database.QueryError(ctx, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return database.QueryError(ctx, helper.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *WebhookRepository) Matching(ctx context.Context, managerId, eventType string) ([]entity.Webhook, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Lookup)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryWebhookMatching)
+
+	query := `
+		SELECT ` + webhookColumns + `
+		FROM webhook
+		WHERE manager_id = $1 AND active AND (cardinality(events) = 0 OR $2 = ANY(events));
+	`
+	return r.queryWebhooks(ctx, query, managerId, eventType)
+}
+
+func (r *WebhookRepository) AddDelivery(ctx context.Context, delivery entity.WebhookDelivery) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryWebhookAddDelivery)
+
+	query := `
+		INSERT INTO webhook_delivery (webhook_id, event_id, event_type, payload)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (webhook_id, event_id) DO NOTHING
+		RETURNING id;
+	`
+	var id int64
+	err := r.db.QueryRow(ctx, query, delivery.WebhookId, delivery.EventId, delivery.EventType, delivery.Payload).Scan(&id)
+	if err == pgx.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, database.QueryError(ctx, err)
+	}
+	return id, nil
+}
+
+// ClaimDeliveries mengunci delivery yang siap dikirim beserta URL dan secret
+// webhook-nya. Seperti outbox, SKIP LOCKED membuat beberapa instance bisa
+// mengirim bersamaan; harus dipanggil di dalam transaksi.
+func (r *WebhookRepository) ClaimDeliveries(ctx context.Context, limit int) ([]entity.WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryWebhookClaim)
+
+	query := `
+		SELECT ` + deliveryColumns + `, w.url, w.secret
+		FROM webhook_delivery d
+		JOIN webhook w ON w.id = d.webhook_id
+		WHERE d.status = 'pending' AND d.next_attempt_at <= CURRENT_TIMESTAMP AND w.active
+		ORDER BY d.next_attempt_at, d.id
+		LIMIT $1
+		FOR UPDATE OF d SKIP LOCKED;
+	`
+	rows, err := r.db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	deliveries, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.WebhookDelivery, error) {
+		var delivery entity.WebhookDelivery
+		err := row.Scan(append(deliveryFields(&delivery), &delivery.Url, &delivery.Secret)...)
+		return delivery, err
+	})
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	return deliveries, nil
+}
+
+func (r *WebhookRepository) MarkDelivered(ctx context.Context, id int64, statusCode int) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryWebhookMarkDelivered)
+
+	query := `
+		UPDATE webhook_delivery
+		SET status = 'delivered', attempts = attempts + 1, last_status_code = $2, last_error = NULL,
+			delivered_at = CURRENT_TIMESTAMP
+		WHERE id = $1;
+	`
+	_, err := r.db.Exec(ctx, query, id, statusCode)
+	return database.QueryError(ctx, err)
+}
+
+// MarkFailed menunda delivery selama retryAfter, atau menandainya dead jika
+// percobaannya sudah habis. statusCode 0 berarti tidak ada response.
+func (r *WebhookRepository) MarkFailed(ctx context.Context, id int64, retryAfter time.Duration, statusCode int, lastError string, dead bool) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryWebhookMarkFailed)
+
+	query := `
+		UPDATE webhook_delivery
+		SET
+			status = CASE WHEN $5 THEN 'dead' ELSE 'pending' END,
+			attempts = attempts + 1,
+			next_attempt_at = CURRENT_TIMESTAMP + $2::interval,
+			last_status_code = NULLIF($3, 0),
+			last_error = $4
+		WHERE id = $1;
+	`
+	_, err := r.db.Exec(ctx, query, id, retryAfter, statusCode, lastError, dead)
+	return database.QueryError(ctx, err)
+}
+
+// GetDeliveries mengembalikan delivery terbaru lebih dulu. Kepemilikan
+// webhook dicek di service.
+func (r *WebhookRepository) GetDeliveries(ctx context.Context, webhookId string, limit, offset int) ([]entity.WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryWebhookGetDeliveries)
+
+	query := `
+		SELECT ` + deliveryColumns + `
+		FROM webhook_delivery d
+		WHERE d.webhook_id = $1
+		ORDER BY d.id DESC
+		LIMIT $2 OFFSET $3;
+	`
+	rows, err := r.db.Query(ctx, query, webhookId, limit, offset)
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	deliveries, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.WebhookDelivery, error) {
+		var delivery entity.WebhookDelivery
+		err := row.Scan(deliveryFields(&delivery)...)
+		return delivery, err
+	})
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	return deliveries, nil
+}
+
+// DeleteFinished menghapus paling banyak limit delivery delivered atau dead
+// yang dibuat lebih lama dari retention, lewat db milik pemanggil seperti
+// outboxRepository.DeleteSent. Delivery pending tidak pernah dihapus.
+func (r *WebhookRepository) DeleteFinished(ctx context.Context, db database.Querier, retention time.Duration, limit int) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	ctx = database.WithQueryName(ctx, queryWebhookDeleteFinished)
+
+	query := `
+		DELETE FROM webhook_delivery
+		WHERE id IN (
+			SELECT id
+			FROM webhook_delivery
+			WHERE status <> 'pending' AND created_at < CURRENT_TIMESTAMP - $1::interval
+			ORDER BY created_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		);
+	`
+	tag, err := db.Exec(ctx, query, retention, limit)
+	if err != nil {
+		return 0, database.QueryError(ctx, err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *WebhookRepository) queryWebhooks(ctx context.Context, query string, args ...any) ([]entity.Webhook, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	webhooks, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.Webhook, error) {
+		return scanWebhook(row)
+	})
+	if err != nil {
+		return nil, database.QueryError(ctx, err)
+	}
+	return webhooks, nil
+}
+
+func scanWebhook(row pgx.Row) (entity.Webhook, error) {
+	var webhook entity.Webhook
+	err := row.Scan(
+		&webhook.Id,
+		&webhook.ManagerId,
+		&webhook.Url,
+		&webhook.Secret,
+		&webhook.Events,
+		&webhook.Active,
+		&webhook.CreatedAt,
+	)
+	return webhook, err
+}
+
+// deliveryFields adalah tujuan Scan untuk deliveryColumns.
+func deliveryFields(delivery *entity.WebhookDelivery) []any {
+	return []any{
+		&delivery.Id,
+		&delivery.WebhookId,
+		&delivery.EventId,
+		&delivery.EventType,
+		&delivery.Payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.NextAttemptAt,
+		&delivery.LastStatusCode,
+		&delivery.LastError,
+		&delivery.CreatedAt,
+		&delivery.DeliveredAt,
+	}
+}
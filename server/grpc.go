@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/di"
+	"github.com/levensspel/go-gin-template/grpcserver"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// startGRPC membuka listener gRPC di GRPC_PORT jika GRPC_ENABLED aktif,
+// dengan host dan TLS yang sama dengan server HTTP. Error setelah listener
+// terbuka dikirim ke serveErr. Mengembalikan nil jika gRPC tidak aktif.
+func startGRPC(serverConfig *config.ServerConfig, tlsConfig *tls.Config, serveErr chan<- error) (*grpc.Server, error) {
+	grpcConfig := config.LoadGRPCConfig()
+	if !grpcConfig.Enabled {
+		return nil, nil
+	}
+
+	var opts []grpc.ServerOption
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	grpcServer := grpcserver.NewInject(di.Injector, opts...)
+
+	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%s", serverConfig.Host, grpcConfig.Port))
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil {
+			serveErr <- fmt.Errorf("grpc: %w", err)
+		}
+	}()
+	log.Printf("gRPC server listening on %s", lis.Addr())
+	return grpcServer, nil
+}
+
+// stopGRPC menunggu RPC yang sedang berjalan selesai seperti
+// http.Server.Shutdown, dan memutus sisanya jika ctx habis lebih dulu.
+// Channel yang dikembalikan ditutup setelah server berhenti.
+func stopGRPC(ctx context.Context, grpcServer *grpc.Server) <-chan struct{} {
+	stopped := make(chan struct{})
+	if grpcServer == nil {
+		close(stopped)
+		return stopped
+	}
+
+	graceful := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(graceful)
+	}()
+	go func() {
+		defer close(stopped)
+		select {
+		case <-graceful:
+		case <-ctx.Done():
+			log.Println("gRPC graceful stop timed out, closing remaining connections")
+			grpcServer.Stop()
+			<-graceful
+		}
+	}()
+	return stopped
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// slowGRPC menjalankan server gRPC yang setiap RPC-nya baru selesai
+// setelah release ditutup atau RPC-nya dibatalkan. Stop menunggu handler
+// berhenti, sehingga handler harus mengikuti context seperti service layer.
+type slowGRPC struct {
+	srv     *grpc.Server
+	conn    *grpc.ClientConn
+	started chan struct{}
+	release chan struct{}
+}
+
+func newSlowGRPC(t *testing.T) *slowGRPC {
+	t.Helper()
+	s := &slowGRPC{started: make(chan struct{}, 1), release: make(chan struct{})}
+	s.srv = grpc.NewServer(grpc.UnknownServiceHandler(func(_ any, stream grpc.ServerStream) error {
+		if err := stream.RecvMsg(&emptypb.Empty{}); err != nil {
+			return err
+		}
+		s.started <- struct{}{}
+		select {
+		case <-s.release:
+			return stream.SendMsg(&emptypb.Empty{})
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}))
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go s.srv.Serve(listener)
+
+	s.conn, err = grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		s.conn.Close()
+		s.srv.Stop()
+	})
+	return s
+}
+
+// call menjalankan satu RPC dan menunggu sampai server menerimanya.
+func (s *slowGRPC) call(t *testing.T) <-chan error {
+	t.Helper()
+	done := make(chan error, 1)
+	go func() {
+		done <- s.conn.Invoke(context.Background(), "/ps3t.test.Slow/Call", &emptypb.Empty{}, &emptypb.Empty{})
+	}()
+	select {
+	case <-s.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("RPC did not reach the server")
+	}
+	return done
+}
+
+func TestStopGRPCWaitsForInFlightRPC(t *testing.T) {
+	s := newSlowGRPC(t)
+	done := s.call(t)
+
+	stopped := stopGRPC(context.Background(), s.srv)
+	select {
+	case <-stopped:
+		t.Fatal("stopGRPC returned while an RPC was in flight")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(s.release)
+	if err := <-done; err != nil {
+		t.Fatalf("in-flight RPC error = %v", err)
+	}
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stopGRPC did not finish after the RPC completed")
+	}
+}
+
+func TestStopGRPCClosesAfterGracePeriod(t *testing.T) {
+	s := newSlowGRPC(t)
+	defer close(s.release)
+	done := s.call(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	select {
+	case <-stopGRPC(ctx, s.srv):
+	case <-time.After(5 * time.Second):
+		t.Fatal("stopGRPC did not stop the server after the grace period")
+	}
+	if err := <-done; status.Code(err) != codes.Unavailable {
+		t.Fatalf("RPC cut off by Stop error = %v, want Unavailable", err)
+	}
+}
+
+func TestStopGRPCDisabled(t *testing.T) {
+	select {
+	case <-stopGRPC(context.Background(), nil):
+	default:
+		t.Fatal("stopGRPC without a server did not return a closed channel")
+	}
+}
@@ -0,0 +1,41 @@
+package server
+
+import (
+	"net/http/pprof"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/middleware"
+)
+
+const pprofPrefix = "/debug/pprof"
+
+// registerPprof memasang handler net/http/pprof di bawah /debug/pprof jika
+// PprofEnabled, hanya untuk admin atau pemegang PprofToken. Handler profile
+// CPU dan trace berhenti ketika context request selesai, sehingga client
+// yang membatalkan request juga menghentikan profiling.
+func registerPprof(r *gin.Engine, adminConfig *config.AdminConfig) {
+	if !adminConfig.PprofEnabled {
+		return
+	}
+	debug := r.Group(pprofPrefix, middleware.DebugAccess(adminConfig.PprofToken, adminConfig.ManagerIDs))
+	debug.GET("/*name", servePprof)
+	debug.POST("/*name", servePprof)
+}
+
+func servePprof(c *gin.Context) {
+	switch strings.TrimPrefix(c.Param("name"), "/") {
+	case "cmdline":
+		pprof.Cmdline(c.Writer, c.Request)
+	case "profile":
+		pprof.Profile(c.Writer, c.Request)
+	case "symbol":
+		pprof.Symbol(c.Writer, c.Request)
+	case "trace":
+		pprof.Trace(c.Writer, c.Request)
+	default:
+		// Index juga melayani profile bernama seperti heap, goroutine, allocs
+		pprof.Index(c.Writer, c.Request)
+	}
+}
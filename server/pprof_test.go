@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levensspel/go-gin-template/auth"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/middleware"
+)
+
+const (
+	pprofAdminID = "admin-1"
+	pprofToken   = "debug-token"
+)
+
+func newPprofRouter(enabled bool) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	registerPprof(r, &config.AdminConfig{
+		ManagerIDs:   []string{pprofAdminID},
+		PprofEnabled: enabled,
+		PprofToken:   pprofToken,
+	})
+	return r
+}
+
+func TestPprofAccess(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	bearer := func(userID string) string {
+		token, err := auth.Default().GenerateToken(userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return "Bearer " + token
+	}
+
+	tests := []struct {
+		name          string
+		enabled       bool
+		authorization string
+		debugToken    string
+		want          int
+	}{
+		{name: "disabled", enabled: false, want: http.StatusNotFound},
+		{name: "disabled with token", enabled: false, debugToken: pprofToken, want: http.StatusNotFound},
+		{name: "no credentials", enabled: true, want: http.StatusUnauthorized},
+		{name: "invalid bearer", enabled: true, authorization: "Bearer invalid", want: http.StatusUnauthorized},
+		{name: "non-admin", enabled: true, authorization: bearer("manager-1"), want: http.StatusForbidden},
+		{name: "wrong token", enabled: true, debugToken: "wrong", want: http.StatusForbidden},
+		{name: "admin", enabled: true, authorization: bearer(pprofAdminID), want: http.StatusOK},
+		{name: "token", enabled: true, debugToken: pprofToken, want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newPprofRouter(tt.enabled)
+			req := httptest.NewRequest(http.MethodGet, pprofPrefix+"/heap?debug=1", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			if tt.debugToken != "" {
+				req.Header.Set(middleware.DebugTokenHeader, tt.debugToken)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
+
+// TestPprofProfileStopsWithRequest memastikan profile CPU yang diminta 30
+// detik berhenti begitu client membatalkan request.
+func TestPprofProfileStopsWithRequest(t *testing.T) {
+	router := newPprofRouter(true)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequest(http.MethodGet, pprofPrefix+"/profile?seconds=30", nil).WithContext(ctx)
+	req.Header.Set(middleware.DebugTokenHeader, pprofToken)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("CPU profile kept running after the request was cancelled")
+	}
+}
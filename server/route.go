@@ -1,18 +1,30 @@
 package server
 
 import (
+	"log"
+
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/config"
 	"github.com/levensspel/go-gin-template/di"
+	"github.com/levensspel/go-gin-template/entity"
+	adminHandler "github.com/levensspel/go-gin-template/handler/admin"
+	apiKeyHandler "github.com/levensspel/go-gin-template/handler/apikey"
+	auditHandler "github.com/levensspel/go-gin-template/handler/audit"
 	authHandler "github.com/levensspel/go-gin-template/handler/auth"
 	departmentHandler "github.com/levensspel/go-gin-template/handler/department"
 	employeeHandler "github.com/levensspel/go-gin-template/handler/employee"
 	fileHandler "github.com/levensspel/go-gin-template/handler/file"
+	graphqlHandler "github.com/levensspel/go-gin-template/handler/graphql"
+	healthHandler "github.com/levensspel/go-gin-template/handler/health"
+	integrationHandler "github.com/levensspel/go-gin-template/handler/integration"
+	streamHandler "github.com/levensspel/go-gin-template/handler/stream"
 	userHandler "github.com/levensspel/go-gin-template/handler/user"
-	"github.com/levensspel/go-gin-template/logger"
+	webhookHandler "github.com/levensspel/go-gin-template/handler/webhook"
+	websocketHandler "github.com/levensspel/go-gin-template/handler/websocket"
+	"github.com/levensspel/go-gin-template/metrics"
 	"github.com/levensspel/go-gin-template/middleware"
-	fileRepository "github.com/levensspel/go-gin-template/repository/file"
-	fileService "github.com/levensspel/go-gin-template/service/file"
+	apiKeyService "github.com/levensspel/go-gin-template/service/apikey"
 	"github.com/samber/do/v2"
 
 	_ "github.com/levensspel/go-gin-template/docs"
@@ -21,21 +33,53 @@ import (
 )
 
 func NewRouter(r *gin.Engine, db *pgxpool.Pool) {
-	logger := logger.NewlogHandler()
-
 	// api := r.Group("/v1")
 	// {
 	// 	// untuk memanfaatkan api versioning, uncomment dan pakai ini
 	// }
 
-	fileRepo := fileRepository.NewFileRepository(db)
-	fileService := fileService.NewFileService(fileRepo, logger)
-
 	userHandler := do.MustInvoke[userHandler.UserHandler](di.Injector)
 	authHandler := do.MustInvoke[authHandler.AuthorizationHandler](di.Injector)
-	fileHandler := fileHandler.NewHandler(fileService, logger)
+	fileHandler := do.MustInvoke[fileHandler.FileHandler](di.Injector)
 	deptHandler := do.MustInvoke[departmentHandler.DepartmentHandler](di.Injector)
 	employeeHdlr := do.MustInvoke[employeeHandler.EmployeeHandler](di.Injector)
+	auditHdlr := do.MustInvoke[auditHandler.AuditHandler](di.Injector)
+	adminHdlr := do.MustInvoke[adminHandler.AdminHandler](di.Injector)
+	healthHdlr := do.MustInvoke[healthHandler.HealthHandler](di.Injector)
+	webhookHdlr := do.MustInvoke[webhookHandler.WebhookHandler](di.Injector)
+	graphqlHdlr := do.MustInvoke[graphqlHandler.GraphQLHandler](di.Injector)
+	integrationHdlr := do.MustInvoke[integrationHandler.IntegrationHandler](di.Injector)
+	streamHdlr := do.MustInvoke[streamHandler.StreamHandler](di.Injector)
+	websocketHdlr := do.MustInvoke[websocketHandler.WebSocketHandler](di.Injector)
+	apiKeyHdlr := do.MustInvoke[apiKeyHandler.APIKeyHandler](di.Injector)
+
+	// Group yang boleh dibaca tool eksternal menerima API key selain bearer
+	// token; scope-nya dicek RequireScope di setiap group
+	apiKeyAuth := middleware.APIKey(do.MustInvoke[apiKeyService.APIKeyService](di.Injector))
+
+	adminConfig := config.LoadAdminConfig()
+	adminIPAllowlist, err := middleware.NewIPAllowlist(adminConfig.IPAllowlist, adminConfig.TrustedProxyDepth)
+	if err != nil {
+		log.Fatalf("Invalid admin IP allowlist: %v", err)
+	}
+
+	// Probe load balancer, sengaja di luar group /v1 dan tanpa auth
+	r.GET(HealthzPath, healthHdlr.Healthz)
+	r.GET(LivezPath, healthHdlr.Livez)
+	r.GET(ReadyzPath, healthHdlr.Readyz)
+
+	appMetrics := do.MustInvoke[*metrics.Metrics](di.Injector)
+	r.GET(MetricsPath, gin.WrapH(appMetrics.Handler()))
+
+	registerPprof(r, adminConfig)
+
+	// File hasil upload, tanpa auth agar bisa dipakai langsung di tag img
+	r.GET("/files/:id", fileHandler.Serve)
+	r.HEAD("/files/:id", fileHandler.Serve)
+	r.GET("/files/:id/thumbnail", fileHandler.ServeThumbnail)
+	r.HEAD("/files/:id/thumbnail", fileHandler.ServeThumbnail)
+	r.GET("/files/:id/resize", fileHandler.ServeResized)
+	r.HEAD("/files/:id/resize", fileHandler.ServeResized)
 
 	swaggerRoute := r.Group("/")
 	{
@@ -43,7 +87,8 @@ func NewRouter(r *gin.Engine, db *pgxpool.Pool) {
 		swaggerRoute.GET("swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	}
 
-	controllers := r.Group("/v1")
+	// Plan query lambat untuk request admin yang membawa X-Debug-Explain
+	controllers := r.Group("/v1", middleware.DebugExplain(adminConfig.ManagerIDs))
 	{
 		auth := controllers.Group("/auth")
 		{
@@ -53,6 +98,11 @@ func NewRouter(r *gin.Engine, db *pgxpool.Pool) {
 		file := controllers.Group("/file")
 		{
 			file.POST("", middleware.Authorization, fileHandler.Upload)
+			file.GET("", middleware.Authorization, fileHandler.GetAll)
+			file.GET("/:id", middleware.Authorization, fileHandler.Get)
+			file.DELETE("/:id", middleware.Authorization, fileHandler.Delete)
+			file.POST("/presign", middleware.Authorization, fileHandler.Presign)
+			file.POST("/:id/complete", middleware.Authorization, fileHandler.Complete)
 		}
 
 		user := controllers.Group("/user")
@@ -60,19 +110,86 @@ func NewRouter(r *gin.Engine, db *pgxpool.Pool) {
 			user.GET("", middleware.Authorization, userHandler.GetProfile)
 			user.PATCH("", middleware.Authorization, userHandler.UpdateProfile)
 			user.DELETE("", middleware.Authorization, userHandler.Delete)
+			user.PUT("/avatar", middleware.Authorization, userHandler.ReplaceAvatar)
+			user.PUT("/company-logo", middleware.Authorization, userHandler.ReplaceCompanyLogo)
 		}
-		department := controllers.Group("/department")
+		department := controllers.Group("/department", apiKeyAuth, middleware.RequireScope(entity.APIKeyScopeDepartmentsRead))
 		{
-			department.POST("", middleware.Authorization, deptHandler.Create)
-			department.GET("", middleware.Authorization, deptHandler.GetAll)
-			department.PATCH("/:id", middleware.Authorization, deptHandler.Update)
-			department.DELETE("/:id", middleware.Authorization, deptHandler.Delete)
+			department.POST("", deptHandler.Create)
+			department.GET("", deptHandler.GetAll)
+			department.PATCH("/:id", deptHandler.Update)
+			department.DELETE("/:id", deptHandler.Delete)
 		}
 
-		employee := controllers.Group("/employee")
+		employee := controllers.Group("/employee", apiKeyAuth, middleware.RequireScope(entity.APIKeyScopeEmployeesRead))
+		{
+			employee.POST("", employeeHdlr.Create)
+			employee.GET("", employeeHdlr.GetAll)
+			employee.POST("/import", employeeHdlr.Import)
+			employee.GET("/export", employeeHdlr.Export)
+			employee.GET("/stream", streamHdlr.Employees)
+			employee.POST("/bulk", employeeHdlr.BulkCreate)
+			employee.GET("/identity-number/:identityNumber", employeeHdlr.CheckIdentityNumber)
+			employee.GET("/stats", employeeHdlr.Stats)
+			employee.PATCH("/:identityNumber", employeeHdlr.Update)
+			employee.DELETE("/:identityNumber", employeeHdlr.Delete)
+			employee.POST("/:identityNumber/archive", employeeHdlr.Archive)
+			employee.POST("/:identityNumber/restore", employeeHdlr.Restore)
+			employee.GET("/:identityNumber/history", employeeHdlr.History)
+			employee.POST("/:identityNumber/history/:version/restore", employeeHdlr.RestoreVersion)
+		}
+
+		// Hanya dengan bearer token, API key tidak bisa membuat key baru
+		apiKeys := controllers.Group("/api-keys")
+		{
+			apiKeys.POST("", middleware.Authorization, apiKeyHdlr.Create)
+			apiKeys.GET("", middleware.Authorization, apiKeyHdlr.GetAll)
+			apiKeys.DELETE("/:id", middleware.Authorization, apiKeyHdlr.Delete)
+		}
+
+		audit := controllers.Group("/audit")
+		{
+			audit.GET("", middleware.Authorization, auditHdlr.GetAll)
+		}
+
+		webhooks := controllers.Group("/webhooks")
+		{
+			webhooks.POST("", middleware.Authorization, webhookHdlr.Create)
+			webhooks.GET("", middleware.Authorization, webhookHdlr.GetAll)
+			webhooks.DELETE("/:id", middleware.Authorization, webhookHdlr.Delete)
+			webhooks.POST("/:id/test", middleware.Authorization, webhookHdlr.Test)
+			webhooks.GET("/:id/deliveries", middleware.Authorization, webhookHdlr.GetDeliveries)
+		}
+
+		// Callback partner, diautentikasi dengan signature HMAC, bukan JWT
+		integrations := controllers.Group("/integrations")
+		{
+			integrations.POST("/callback", integrationHdlr.Callback)
+		}
+
+		// Notifikasi WebSocket; browser mengirim token lewat subprotocol
+		controllers.GET("/ws", middleware.WebSocketToken, middleware.Authorization, websocketHdlr.Connect)
+
+		// Query GraphQL, GET juga diterima untuk query yang bisa di-cache
+		controllers.POST("/graphql", middleware.Authorization, graphqlHdlr.Query)
+		controllers.GET("/graphql", middleware.Authorization, graphqlHdlr.Query)
+
+		// Route admin hanya bisa diakses dari range IP kantor/VPN
+		admin := controllers.Group(
+			"/admin",
+			adminIPAllowlist,
+			middleware.Authorization,
+			middleware.RequireAdmin(adminConfig.ManagerIDs),
+		)
 		{
-			employee.POST("", middleware.Authorization, employeeHdlr.Create)
-			employee.GET("", middleware.Authorization, employeeHdlr.GetAll)
+			admin.GET("/audit", auditHdlr.GetAllAdmin)
+			admin.POST("/purge", adminHdlr.Purge)
+			admin.POST("/cleanup", adminHdlr.Cleanup)
+			admin.PUT("/feature-flags/:flag/overrides/:managerId", adminHdlr.SetFeatureFlag)
+			admin.DELETE("/feature-flags/:flag/overrides/:managerId", adminHdlr.DeleteFeatureFlag)
+			admin.GET("/log-level", adminHdlr.GetLogLevel)
+			admin.PUT("/log-level", adminHdlr.SetLogLevel)
+			// tambah route admin disini
 		}
 		// tambah route lainnya disini
 	}
@@ -1,19 +1,57 @@
 package server
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/cache"
 	"github.com/levensspel/go-gin-template/config"
-	dbcontext "github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/di"
+	"github.com/levensspel/go-gin-template/eventbus"
+	"github.com/levensspel/go-gin-template/health"
 	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/lifecycle"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/metrics"
 	"github.com/levensspel/go-gin-template/middleware"
+	"github.com/levensspel/go-gin-template/outbox"
+	"github.com/levensspel/go-gin-template/purge"
+	departmentRepository "github.com/levensspel/go-gin-template/repository/department"
+	"github.com/levensspel/go-gin-template/telemetry"
+	"github.com/levensspel/go-gin-template/thumbnail"
+	"github.com/levensspel/go-gin-template/webhook"
+	"github.com/samber/do/v2"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"google.golang.org/grpc"
 )
 
+const (
+	HealthzPath = "/healthz"
+	LivezPath   = "/livez"
+	ReadyzPath  = "/readyz"
+	MetricsPath = "/metrics"
+)
+
+// probePaths dikecualikan dari access log.
+var probePaths = []string{HealthzPath, LivezPath, ReadyzPath, MetricsPath}
+
+// shutdownSignals memulai graceful shutdown, lihat shutdown.
+var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
 func Start() error {
-	config := config.LoadConfig()
+	serverConfig := config.LoadServerConfig()
 
 	wd, err := os.Getwd()
 	if err != nil {
@@ -21,46 +59,218 @@ func Start() error {
 	}
 	helper.WORK_DIR = wd
 
-	r := gin.Default()
+	if serverConfig.Mode == config.ModeProduction {
+		gin.SetMode(gin.ReleaseMode)
+		if !serverConfig.TLSEnabled() {
+			return errors.New("SSL certificates not configured")
+		}
+	} else {
+		gin.SetMode(gin.DebugMode)
+	}
+
+	var tlsConfig *tls.Config
+	if serverConfig.TLSEnabled() {
+		tlsConfig, err = newTLSConfig(serverConfig)
+		if err != nil {
+			return fmt.Errorf("invalid TLS configuration: %w", err)
+		}
+	}
+
+	r := gin.New()
+	// gin.Context meneruskan Value ke context request, sehingga span dari
+	// otelgin ikut terbawa lewat parameter ctx ke service dan repository.
+	r.ContextWithFallback = true
+	r.Use(middleware.RequestID)
+	// Probe tidak perlu masuk access log karena dipanggil setiap beberapa detik
+	appLogger := do.MustInvoke[logger.LogHandler](di.Injector)
+	appMetrics := do.MustInvoke[*metrics.Metrics](di.Injector)
+	r.Use(middleware.AccessLog(&appLogger, appMetrics, probePaths, config.LoadQueryTraceConfig().LimitPerRequest))
+	r.Use(gin.CustomRecovery(func(c *gin.Context, recovered any) {
+		helper.ReportPanic(c, recovered)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, helper.Error(http.StatusInternalServerError, helper.ErrInternalServer))
+	}))
+	r.Use(otelgin.Middleware(telemetry.ServiceName(), otelgin.WithFilter(func(req *http.Request) bool {
+		return !slices.Contains(probePaths, req.URL.Path)
+	})))
 	r.Use(middleware.EnableCORS)
+	r.Use(middleware.RetryAfter(serverConfig.RetryAfter))
 
-	NewRouter(r, dbcontext.Connect(config.DatabaseURL))
+	db := do.MustInvoke[*database.Pool](di.Injector).Pool
+	readiness := do.MustInvoke[*health.Readiness](di.Injector)
+	// Migrasi dijalankan sebelum router dibuat, karena beberapa dependency
+	// (mis. pengecekan pg_trgm) membaca skema saat dibuat.
+	if config.LoadConfig().AutoMigrate {
+		if err := migrate(db); err != nil {
+			return fmt.Errorf("failed to run database migrations: %w", err)
+		}
+	}
+	// Listener dan collector berjalan di background sampai injector di-shutdown
+	do.MustInvoke[*departmentRepository.OwnershipListener](di.Injector)
+	do.MustInvoke[*database.PoolStatsCollector](di.Injector)
+	do.MustInvoke[*outbox.Dispatcher](di.Injector)
+	do.MustInvoke[*webhook.Deliverer](di.Injector)
+	do.MustInvoke[*purge.Purger](di.Injector)
+	do.MustInvoke[*purge.Cleaner](di.Injector)
+	do.MustInvoke[*thumbnail.Generator](di.Injector)
+	NewRouter(r, db)
 
 	r.Use(gin.Recovery())
 
-	port := os.Getenv("PORT")
-
+	port := serverConfig.Port
 	if len(port) == 0 {
 		port = "8080"
 	}
 
-	appEnv := os.Getenv("MODE")
+	srv := newHTTPServer(fmt.Sprintf("%s:%s", serverConfig.Host, port), r, tlsConfig, serverConfig)
+	// Stream SSE tidak pernah selesai sendiri; tutup saat Shutdown dimulai
+	// agar tidak menahan drain request lain sampai grace period habis
+	srv.RegisterOnShutdown(do.MustInvoke[*eventbus.Bus](di.Injector).Shutdown)
 
-	switch appEnv {
-	case "PRODUCTION":
-		gin.SetMode(gin.ReleaseMode)
+	// HTTP dan gRPC mengirim error start ke channel yang sama
+	serveErr := make(chan error, 2)
+	grpcServer, err := startGRPC(serverConfig, tlsConfig, serveErr)
+	if err != nil {
+		return fmt.Errorf("failed to start grpc server: %w", err)
+	}
+
+	go func() {
+		var err error
+		if tlsConfig != nil {
+			// Sertifikat sudah ada di TLSConfig
+			err = srv.ListenAndServeTLS("", "")
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- err
+		}
+	}()
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, shutdownSignals...)
+	defer signal.Stop(sig)
 
-		sslCert := os.Getenv("SSL_CERT_PATH")
-		sslKey := os.Getenv("SSL_KEY_PATH")
+	// Listener sudah jalan sehingga /livez bisa dijawab, tetapi /readyz
+	// tetap 503 sampai pool selesai warm-up.
+	warmUpErr := make(chan error, 1)
+	go func() {
+		warmUpErr <- warmUp(db)
+	}()
 
-		if sslCert == "" || sslKey == "" {
-			log.Fatal("SSL certificates not configured")
+	for {
+		select {
+		case err := <-warmUpErr:
+			if err != nil {
+				return fmt.Errorf("failed to warm up database pool: %w", err)
+			}
+			readiness.MarkReady()
+			log.Println("Server is ready to accept traffic")
+		case err := <-serveErr:
+			return fmt.Errorf("failed to start server: %w", err)
+		case s := <-sig:
+			log.Printf("Received %s, shutting down", s)
+			return shutdown(srv, grpcServer, readiness, serverConfig)
 		}
+	}
+}
+
+// newHTTPServer memasang timeout dari serverConfig, yang tidak diisi
+// http.Server secara default.
+func newHTTPServer(addr string, handler http.Handler, tlsConfig *tls.Config, serverConfig *config.ServerConfig) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		TLSConfig:         tlsConfig,
+		ReadHeaderTimeout: serverConfig.ReadHeaderTimeout,
+		IdleTimeout:       serverConfig.IdleTimeout,
+	}
+}
+
+func migrate(db *pgxpool.Pool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	return database.Migrate(ctx, db)
+}
+
+// drain mengeluarkan instance dari rotasi load balancer, lalu menunggu
+// request HTTP dan gRPC yang sedang berjalan selesai sampai ctx habis.
+// Koneksi baru ditolak sejak srv.Shutdown dipanggil.
+func drain(
+	ctx context.Context,
+	srv *http.Server,
+	grpcServer *grpc.Server,
+	readiness *health.Readiness,
+	readinessDrainDelay time.Duration,
+) error {
+	// Keluarkan instance dari rotasi load balancer sebelum koneksi ditutup
+	readiness.MarkNotReady()
+	select {
+	case <-time.After(readinessDrainDelay):
+	case <-ctx.Done():
+	}
+
+	grpcStopped := stopGRPC(ctx, grpcServer)
+	var err error
+	if shutdownErr := srv.Shutdown(ctx); shutdownErr != nil {
+		err = fmt.Errorf("failed to drain in-flight requests: %w", shutdownErr)
+	}
+	<-grpcStopped
+	return err
+}
+
+// warmUp memastikan database bisa dijangkau dan membuka koneksi minimum
+// pool sebelum instance dinyatakan siap.
+func warmUp(db *pgxpool.Pool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
 
-		host := os.Getenv("PROD_HOST")
-		err := r.RunTLS(
-			fmt.Sprintf("%s:%s", host, port),
-			sslCert,
-			sslKey,
-		)
+	if err := db.Ping(ctx); err != nil {
+		return err
+	}
+
+	minConns := int(db.Config().MinConns)
+	conns := make([]*pgxpool.Conn, 0, minConns)
+	defer func() {
+		for _, conn := range conns {
+			conn.Release()
+		}
+	}()
+	for len(conns) < minConns {
+		conn, err := db.Acquire(ctx)
 		if err != nil {
-			log.Fatalf("Failed to start HTTPS server: %v", err)
+			return err
 		}
-	default:
-		gin.SetMode(gin.DebugMode)
-		host := os.Getenv("DEBUG_HOST")
-		r.Run(fmt.Sprintf("%s:%s", host, port))
+		conns = append(conns, conn)
 	}
-
 	return nil
 }
+
+// shutdown berhenti menerima koneksi baru, menunggu request yang sedang
+// berjalan selesai, lalu menutup dependensi dari injector. Urutannya:
+// request HTTP dan gRPC, worker background (lewat Lifetime), lalu injector
+// yang mematikan consumer sebelum resource yang dipakainya (pool database,
+// Redis, logger, reporter).
+func shutdown(
+	srv *http.Server,
+	grpcServer *grpc.Server,
+	readiness *health.Readiness,
+	serverConfig *config.ServerConfig,
+) error {
+	ctx, cancel := context.WithTimeout(context.Background(), serverConfig.ShutdownGracePeriod)
+	defer cancel()
+
+	shutdownErr := drain(ctx, srv, grpcServer, readiness, serverConfig.ReadinessDrainDelay)
+
+	// Request sudah selesai; worker background dihentikan tanpa menunggu
+	// batch berikutnya
+	do.MustInvoke[*lifecycle.Lifetime](di.Injector).Cancel()
+
+	if errs := di.Injector.ShutdownWithContext(ctx); errs != nil && errs.Len() > 0 {
+		log.Printf("Injector shutdown errors: %v", errs)
+	}
+	// Cache auth global, bukan bagian dari injector
+	cache.Cache.Close()
+
+	log.Println("Server stopped")
+	return shutdownErr
+}
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/health"
+)
+
+// slowServer menjalankan http.Server dengan handler /slow yang baru selesai
+// setelah release ditutup.
+type slowServer struct {
+	srv     *http.Server
+	addr    string
+	started chan struct{}
+	release chan struct{}
+}
+
+func newSlowServer(t *testing.T) *slowServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &slowServer{addr: listener.Addr().String(), started: make(chan struct{}), release: make(chan struct{})}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		close(s.started)
+		<-s.release
+		io.WriteString(w, "done")
+	})
+	s.srv = &http.Server{Handler: mux}
+	go s.srv.Serve(listener)
+	t.Cleanup(func() { s.srv.Close() })
+	return s
+}
+
+type slowResult struct {
+	status int
+	body   string
+	err    error
+}
+
+// get mengirim request ke /slow dan menunggu handler mulai berjalan.
+func (s *slowServer) get(t *testing.T) <-chan slowResult {
+	t.Helper()
+	result := make(chan slowResult, 1)
+	go func() {
+		client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+		resp, err := client.Get("http://" + s.addr + "/slow")
+		if err != nil {
+			result <- slowResult{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		result <- slowResult{status: resp.StatusCode, body: string(body), err: err}
+	}()
+	select {
+	case <-s.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("slow request did not reach the handler")
+	}
+	return result
+}
+
+// waitRefused menunggu sampai listener ditutup oleh Shutdown.
+func (s *slowServer) waitRefused(t *testing.T) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		conn, err := net.DialTimeout("tcp", s.addr, 100*time.Millisecond)
+		if err != nil {
+			return
+		}
+		conn.Close()
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("new connections are still accepted during drain")
+}
+
+func TestSIGTERMDrainsInFlightRequest(t *testing.T) {
+	s := newSlowServer(t)
+	readiness := health.NewReadiness()
+	readiness.MarkReady()
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, shutdownSignals...)
+	defer signal.Stop(sig)
+
+	response := s.get(t)
+
+	process, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := process.Signal(syscall.SIGTERM); err != nil {
+		t.Skipf("cannot send SIGTERM on this platform: %v", err)
+	}
+	select {
+	case <-sig:
+	case <-time.After(5 * time.Second):
+		t.Fatal("SIGTERM was not delivered to the shutdown channel")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	drained := make(chan error, 1)
+	go func() { drained <- drain(ctx, s.srv, nil, readiness, 0) }()
+
+	s.waitRefused(t)
+	if readiness.IsReady() {
+		t.Fatal("readiness is still ready during drain")
+	}
+	select {
+	case err := <-drained:
+		t.Fatalf("drain returned %v before the in-flight request finished", err)
+	default:
+	}
+
+	close(s.release)
+	result := <-response
+	if result.err != nil || result.status != http.StatusOK || result.body != "done" {
+		t.Fatalf("in-flight request = %+v, want 200 done", result)
+	}
+	if err := <-drained; err != nil {
+		t.Fatalf("drain() error = %v", err)
+	}
+}
+
+func TestDrainStopsWaitingAfterGracePeriod(t *testing.T) {
+	s := newSlowServer(t)
+	defer close(s.release)
+	readiness := health.NewReadiness()
+
+	s.get(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err := drain(ctx, s.srv, nil, readiness, 0)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("drain() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestDrainWaitsForReadinessDelay(t *testing.T) {
+	s := newSlowServer(t)
+	readiness := health.NewReadiness()
+	readiness.MarkReady()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	if err := drain(ctx, s.srv, nil, readiness, 50*time.Millisecond); err != nil {
+		t.Fatalf("drain() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Fatalf("drain finished after %s, want at least the readiness drain delay", elapsed)
+	}
+	if readiness.IsReady() {
+		t.Fatal("readiness is still ready after drain")
+	}
+}
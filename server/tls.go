@@ -0,0 +1,49 @@
+package server
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+
+	"github.com/levensspel/go-gin-template/config"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// newTLSConfig menyiapkan konfigurasi TLS dari file sertifikat atau
+// autocert. Sertifikat file langsung di-load agar path yang salah gagal saat
+// startup, bukan saat handshake pertama.
+func newTLSConfig(serverConfig *config.ServerConfig) (*tls.Config, error) {
+	if len(serverConfig.AutocertDomains) > 0 {
+		if serverConfig.SSLCertPath != "" || serverConfig.SSLKeyPath != "" {
+			return nil, errors.New("SSL_CERT_PATH/SSL_KEY_PATH and AUTOCERT_DOMAINS cannot be used together")
+		}
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(serverConfig.AutocertDomains...),
+			Cache:      autocert.DirCache(serverConfig.AutocertCacheDir),
+		}
+		// Challenge diselesaikan lewat TLS-ALPN-01 pada listener yang sama,
+		// sehingga tidak perlu membuka port 80.
+		tlsConfig := manager.TLSConfig()
+		tlsConfig.MinVersion = tls.VersionTLS12
+		tlsConfig.NextProtos = append([]string{"h2", "http/1.1"}, tlsConfig.NextProtos...)
+		return tlsConfig, nil
+	}
+
+	if serverConfig.SSLCertPath == "" || serverConfig.SSLKeyPath == "" {
+		return nil, errors.New("both SSL_CERT_PATH and SSL_KEY_PATH must be set")
+	}
+	cert, err := tls.LoadX509KeyPair(serverConfig.SSLCertPath, serverConfig.SSLKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load SSL certificate %q and key %q: %w",
+			serverConfig.SSLCertPath, serverConfig.SSLKeyPath, err)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+		// http.Server hanya mengaktifkan HTTP/2 otomatis jika TLSConfig kosong,
+		// jadi "h2" harus didaftarkan manual.
+		NextProtos: []string{"h2", "http/1.1"},
+	}, nil
+}
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/config"
+)
+
+// writeSelfSignedCert membuat sertifikat self-signed untuk 127.0.0.1 dan
+// mengembalikan path sertifikat, path key, dan sertifikatnya.
+func writeSelfSignedCert(t *testing.T) (string, string, *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "cert.pem")
+	keyPath := filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certPath, keyPath, cert
+}
+
+func TestServerTimeoutDefaults(t *testing.T) {
+	t.Setenv("READ_HEADER_TIMEOUT", "")
+	t.Setenv("IDLE_TIMEOUT", "")
+	srv := newHTTPServer(":0", http.NotFoundHandler(), nil, config.LoadServerConfig())
+	if srv.ReadHeaderTimeout != 5*time.Second || srv.IdleTimeout != 120*time.Second {
+		t.Fatalf("timeouts = %s/%s, want 5s/2m0s", srv.ReadHeaderTimeout, srv.IdleTimeout)
+	}
+
+	t.Setenv("READ_HEADER_TIMEOUT", "2s")
+	t.Setenv("IDLE_TIMEOUT", "30s")
+	srv = newHTTPServer(":0", http.NotFoundHandler(), nil, config.LoadServerConfig())
+	if srv.ReadHeaderTimeout != 2*time.Second || srv.IdleTimeout != 30*time.Second {
+		t.Fatalf("timeouts = %s/%s, want 2s/30s", srv.ReadHeaderTimeout, srv.IdleTimeout)
+	}
+}
+
+// TestReadHeaderTimeoutClosesSlowClient meniru slowloris: client yang tidak
+// pernah menyelesaikan header diputus setelah ReadHeaderTimeout.
+func TestReadHeaderTimeoutClosesSlowClient(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := newHTTPServer("", http.NotFoundHandler(), nil, &config.ServerConfig{ReadHeaderTimeout: 100 * time.Millisecond})
+	go srv.Serve(listener)
+	t.Cleanup(func() { srv.Close() })
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := io.WriteString(conn, "GET / HTTP/1.1\r\nHost: localhost\r\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	start := time.Now()
+	_, err = io.ReadAll(conn)
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		t.Fatal("server kept the connection open with incomplete headers")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("connection closed after %s, want about 100ms", elapsed)
+	}
+}
+
+func TestTLSRoundTrip(t *testing.T) {
+	certPath, keyPath, cert := writeSelfSignedCert(t)
+	serverConfig := &config.ServerConfig{SSLCertPath: certPath, SSLKeyPath: keyPath, ReadHeaderTimeout: time.Second}
+	tlsConfig, err := newTLSConfig(serverConfig)
+	if err != nil {
+		t.Fatalf("newTLSConfig error = %v", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.Proto)
+	})
+	srv := newHTTPServer("", handler, tlsConfig, serverConfig)
+	go srv.ServeTLS(listener, "", "")
+	t.Cleanup(func() { srv.Close() })
+
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	client := &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig:   &tls.Config{RootCAs: roots},
+			ForceAttemptHTTP2: true,
+		},
+	}
+	resp, err := client.Get("https://" + listener.Addr().String() + "/")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || resp.TLS == nil {
+		t.Fatalf("response = %d, TLS = %v", resp.StatusCode, resp.TLS != nil)
+	}
+	// HTTP/2 dinegosiasikan lewat ALPN
+	if resp.ProtoMajor != 2 || string(body) != "HTTP/2.0" {
+		t.Fatalf("protocol = %s, server saw %q, want HTTP/2.0", resp.Proto, body)
+	}
+}
+
+func TestNewTLSConfigFailsFast(t *testing.T) {
+	certPath, keyPath, _ := writeSelfSignedCert(t)
+	missingKey := filepath.Join(t.TempDir(), "missing.pem")
+
+	tests := []struct {
+		name         string
+		serverConfig config.ServerConfig
+		wantMessage  string
+	}{
+		{
+			name:         "missing key file",
+			serverConfig: config.ServerConfig{SSLCertPath: certPath, SSLKeyPath: missingKey},
+			wantMessage:  missingKey,
+		},
+		{
+			name:         "key path not set",
+			serverConfig: config.ServerConfig{SSLCertPath: certPath},
+			wantMessage:  "both SSL_CERT_PATH and SSL_KEY_PATH must be set",
+		},
+		{
+			name:         "key does not match certificate",
+			serverConfig: config.ServerConfig{SSLCertPath: certPath, SSLKeyPath: certPath},
+			wantMessage:  "failed to load SSL certificate",
+		},
+		{
+			name:         "files and autocert",
+			serverConfig: config.ServerConfig{SSLCertPath: certPath, SSLKeyPath: keyPath, AutocertDomains: []string{"example.com"}},
+			wantMessage:  "cannot be used together",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTLSConfig(&tt.serverConfig)
+			if err == nil || !strings.Contains(err.Error(), tt.wantMessage) {
+				t.Fatalf("newTLSConfig error = %v, want it to mention %q", err, tt.wantMessage)
+			}
+		})
+	}
+}
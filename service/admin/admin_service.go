@@ -0,0 +1,324 @@
+package adminService
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/levensspel/go-gin-template/audit"
+	"github.com/levensspel/go-gin-template/cache"
+	"github.com/levensspel/go-gin-template/clock"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/events"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/repository"
+	"github.com/levensspel/go-gin-template/validation"
+	"github.com/samber/do/v2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ActorPrefix menandai actor audit log dari cmd/admin. Actor-nya adalah
+// ActorPrefix diikuti nama operator, sehingga tidak tertukar dengan id
+// manager.
+const ActorPrefix = "cli:"
+
+// generatedPasswordBytes menghasilkan password 24 karakter base64.
+const generatedPasswordBytes = 18
+
+// AdminService berisi tugas operasional yang dijalankan lewat cmd/admin,
+// di luar batasan kepemilikan manager pada API. Setiap perubahan dicatat di
+// audit log dengan actor operator.
+type AdminService interface {
+	CreateUser(ctx context.Context, input dto.AdminUserCreateRequest) (dto.AdminUserResponse, error)
+	// ResetPassword mengganti password dengan password acak dan mencabut
+	// semua sesi manager.
+	ResetPassword(ctx context.Context, input dto.AdminUserRequest) (dto.AdminPasswordResetResponse, error)
+	// RevokeSessions membuat semua token yang sudah diterbitkan untuk
+	// manager ditolak, lihat auth.Revocations.
+	RevokeSessions(ctx context.Context, input dto.AdminUserRequest) (dto.AdminSessionsRevokedResponse, error)
+	// ReassignDepartment memindahkan department beserta employee-nya ke
+	// manager lain.
+	ReassignDepartment(ctx context.Context, input dto.AdminDepartmentReassignRequest) (dto.AdminDepartmentReassignResponse, error)
+}
+
+type service struct {
+	uow       repository.UnitOfWork
+	clock     clock.Clock
+	firstPage *cache.EmployeeFirstPage
+}
+
+func New(uow repository.UnitOfWork, clock clock.Clock, firstPage *cache.EmployeeFirstPage) AdminService {
+	return &service{uow: uow, clock: clock, firstPage: firstPage}
+}
+
+func NewInject(i do.Injector) (AdminService, error) {
+	_uow := do.MustInvoke[repository.UnitOfWork](i)
+	_clock := do.MustInvoke[clock.Clock](i)
+	_firstPage := do.MustInvoke[*cache.EmployeeFirstPage](i)
+	return New(_uow, _clock, _firstPage), nil
+}
+
+// userAudit adalah data manager yang dicatat di audit log. Password selalu
+// dilewati audit.Diff, jadi reset password dicatat lewat CredentialsResetAt.
+type userAudit struct {
+	Email              string     `json:"email"`
+	CredentialsResetAt *time.Time `json:"credentialsResetAt,omitempty"`
+	SessionsRevokedAt  *time.Time `json:"sessionsRevokedAt,omitempty"`
+}
+
+type departmentAudit struct {
+	ManagerID string `json:"managerId"`
+}
+
+// employeeAudit adalah data employee yang berubah saat department-nya
+// dipindahkan ke manager lain.
+type employeeAudit struct {
+	ManagerID string `json:"managerId"`
+}
+
+func (s *service) CreateUser(ctx context.Context, input dto.AdminUserCreateRequest) (dto.AdminUserResponse, error) {
+	if err := validation.ValidateAdminUserCreate(&input); err != nil {
+		return dto.AdminUserResponse{}, err
+	}
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.MinCost)
+	if err != nil {
+		return dto.AdminUserResponse{}, err
+	}
+
+	user := entity.User{Password: string(passwordHash)}
+	user.Email.String = input.Email
+	err = s.uow.Do(ctx, func(repos repository.Repositories) error {
+		user.Id, err = repos.User.Create(ctx, user)
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return helper.ErrConflict
+		}
+		if err != nil {
+			return err
+		}
+		return recordUser(ctx, repos, input.Operator, entity.AuditActionCreate, user.Id, nil, &userAudit{Email: input.Email})
+	})
+	if err != nil {
+		return dto.AdminUserResponse{}, err
+	}
+	return dto.AdminUserResponse{ManagerID: user.Id, Email: input.Email}, nil
+}
+
+func (s *service) ResetPassword(ctx context.Context, input dto.AdminUserRequest) (dto.AdminPasswordResetResponse, error) {
+	if err := validation.ValidateAdminUser(&input); err != nil {
+		return dto.AdminPasswordResetResponse{}, err
+	}
+	password, err := generatePassword()
+	if err != nil {
+		return dto.AdminPasswordResetResponse{}, err
+	}
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		return dto.AdminPasswordResetResponse{}, err
+	}
+
+	now := s.revocationTime()
+	var id string
+	err = s.uow.Do(ctx, func(repos repository.Repositories) error {
+		id, err = findUser(ctx, repos, input.Email)
+		if err != nil {
+			return err
+		}
+		if err := repos.User.UpdatePassword(ctx, id, string(passwordHash)); err != nil {
+			return err
+		}
+		// Token lama tetap berlaku sampai kedaluwarsa jika tidak dicabut
+		if err := repos.User.RevokeSessions(ctx, id, now); err != nil {
+			return err
+		}
+		after := &userAudit{Email: input.Email, CredentialsResetAt: &now, SessionsRevokedAt: &now}
+		return recordUser(ctx, repos, input.Operator, entity.AuditActionUpdate, id, &userAudit{Email: input.Email}, after)
+	})
+	if err != nil {
+		return dto.AdminPasswordResetResponse{}, err
+	}
+	return dto.AdminPasswordResetResponse{
+		ManagerID:         id,
+		Email:             input.Email,
+		Password:          password,
+		SessionsRevokedAt: now,
+	}, nil
+}
+
+func (s *service) RevokeSessions(ctx context.Context, input dto.AdminUserRequest) (dto.AdminSessionsRevokedResponse, error) {
+	if err := validation.ValidateAdminUser(&input); err != nil {
+		return dto.AdminSessionsRevokedResponse{}, err
+	}
+
+	now := s.revocationTime()
+	var id string
+	err := s.uow.Do(ctx, func(repos repository.Repositories) (err error) {
+		id, err = findUser(ctx, repos, input.Email)
+		if err != nil {
+			return err
+		}
+		if err := repos.User.RevokeSessions(ctx, id, now); err != nil {
+			return err
+		}
+		after := &userAudit{Email: input.Email, SessionsRevokedAt: &now}
+		return recordUser(ctx, repos, input.Operator, entity.AuditActionUpdate, id, &userAudit{Email: input.Email}, after)
+	})
+	if err != nil {
+		return dto.AdminSessionsRevokedResponse{}, err
+	}
+	return dto.AdminSessionsRevokedResponse{ManagerID: id, Email: input.Email, SessionsRevokedAt: now}, nil
+}
+
+// ReassignDepartment mengirim department.deleted ke manager lama dan
+// department.created ke manager baru, karena event dan webhook dikirim per
+// manager. Employee di department tersebut ikut berpindah manager dan
+// dicatat dengan cara yang sama, lihat reassignEmployee.
+func (s *service) ReassignDepartment(ctx context.Context, input dto.AdminDepartmentReassignRequest) (dto.AdminDepartmentReassignResponse, error) {
+	if err := validation.ValidateAdminDepartmentReassign(&input); err != nil {
+		return dto.AdminDepartmentReassignResponse{}, err
+	}
+
+	var response dto.AdminDepartmentReassignResponse
+	err := s.uow.Do(ctx, func(repos repository.Repositories) error {
+		// Manager tujuan harus ada; foreign key saja akan gagal dengan 23503
+		if _, err := repos.User.GetProfile(ctx, input.ToManagerID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return helper.ErrNotFound
+			}
+			return err
+		}
+		department, fromManagerID, err := repos.Department.Reassign(ctx, input.DepartmentID, input.ToManagerID)
+		if err != nil {
+			return err
+		}
+		response = dto.AdminDepartmentReassignResponse{
+			DepartmentID:   department.Id,
+			DepartmentName: department.Name,
+			FromManagerID:  fromManagerID,
+			ToManagerID:    input.ToManagerID,
+		}
+		if fromManagerID == input.ToManagerID {
+			return nil
+		}
+
+		err = audit.Record(
+			ctx,
+			repos.Audit,
+			ActorPrefix+input.Operator,
+			entity.AuditActionUpdate,
+			entity.AuditEntityDepartment,
+			department.Id,
+			&departmentAudit{ManagerID: fromManagerID},
+			&departmentAudit{ManagerID: input.ToManagerID},
+		)
+		if err != nil {
+			return err
+		}
+		employees, err := repos.Employee.GetAllInDepartment(ctx, department.Id, input.ToManagerID)
+		if err != nil {
+			return err
+		}
+		for _, employee := range employees {
+			if err := reassignEmployee(ctx, repos, input.Operator, fromManagerID, input.ToManagerID, employee); err != nil {
+				return err
+			}
+		}
+		if fromManagerID != "" {
+			err := repos.Outbox.Add(ctx, entity.EventDepartmentDeleted, entity.AggregateDepartment, department.Id, events.Department{
+				ManagerID:      fromManagerID,
+				DepartmentID:   department.Id,
+				DepartmentName: department.Name,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return repos.Outbox.Add(ctx, entity.EventDepartmentCreated, entity.AggregateDepartment, department.Id, events.Department{
+			ManagerID:      input.ToManagerID,
+			DepartmentID:   department.Id,
+			DepartmentName: department.Name,
+		})
+	})
+	if err != nil {
+		return dto.AdminDepartmentReassignResponse{}, err
+	}
+	if response.FromManagerID != response.ToManagerID {
+		if response.FromManagerID != "" {
+			s.firstPage.Invalidate(ctx, response.FromManagerID)
+		}
+		s.firstPage.Invalidate(ctx, response.ToManagerID)
+	}
+	return response, nil
+}
+
+// reassignEmployee mencatat employee yang department-nya dipindahkan dari
+// fromManagerID ke toManagerID: audit log dengan actor operator, versi
+// baru, employee.deleted ke manager lama, dan employee.created ke manager
+// baru. employee_versions hanya menyimpan id manager, sehingga changedBy
+// versinya adalah manager baru.
+func reassignEmployee(ctx context.Context, repos repository.Repositories, operator, fromManagerID, toManagerID string, employee dto.EmployeeResponse) error {
+	err := audit.Record(
+		ctx,
+		repos.Audit,
+		ActorPrefix+operator,
+		entity.AuditActionUpdate,
+		entity.AuditEntityEmployee,
+		employee.IdentityNumber,
+		&employeeAudit{ManagerID: fromManagerID},
+		&employeeAudit{ManagerID: toManagerID},
+	)
+	if err != nil {
+		return err
+	}
+	if err := repos.Employee.AddVersion(ctx, employee.IdentityNumber, entity.AuditActionUpdate, toManagerID); err != nil {
+		return err
+	}
+	if fromManagerID != "" {
+		err := repos.Outbox.Add(ctx, entity.EventEmployeeDeleted, entity.AggregateEmployee, employee.IdentityNumber, events.Employee{
+			ManagerID: fromManagerID,
+			Employee:  employee,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return repos.Outbox.Add(ctx, entity.EventEmployeeCreated, entity.AggregateEmployee, employee.IdentityNumber, events.Employee{
+		ManagerID: toManagerID,
+		Employee:  employee,
+	})
+}
+
+// revocationTime dibulatkan ke detik, sama dengan presisi claim iat.
+func (s *service) revocationTime() time.Time {
+	return s.clock.Now().UTC().Truncate(time.Second)
+}
+
+// findUser mengembalikan id manager dengan email tersebut, atau
+// helper.ErrNotFound.
+func findUser(ctx context.Context, repos repository.Repositories, email string) (string, error) {
+	users, err := repos.User.GetUserbyEmail(ctx, email)
+	if err != nil {
+		return "", err
+	}
+	if len(users) == 0 {
+		return "", helper.ErrNotFound
+	}
+	return users[0].Id, nil
+}
+
+func recordUser(ctx context.Context, repos repository.Repositories, operator, action, id string, before, after *userAudit) error {
+	return audit.Record(ctx, repos.Audit, ActorPrefix+operator, action, entity.AuditEntityUser, id, before, after)
+}
+
+func generatePassword() (string, error) {
+	b := make([]byte, generatedPasswordBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
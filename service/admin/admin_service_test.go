@@ -0,0 +1,488 @@
+package adminService
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/levensspel/go-gin-template/cache"
+	"github.com/levensspel/go-gin-template/clock/clocktest"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/events"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/mocks"
+	"github.com/levensspel/go-gin-template/repository"
+	"github.com/levensspel/go-gin-template/validation"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	testFromManagerID = "0b7c2d2e-4f4a-4b8e-9c59-2f1d8f6a3e10"
+	testToManagerID   = "3eaf5a5b-7c7d-4ebc-8f8c-5c4fbc9d6b43"
+	testDeptID        = "1c8d3e3f-5a5b-4c9f-8d6a-3a2e9a7b4f21"
+)
+
+type outboxCall struct {
+	eventType   string
+	aggregateId string
+	managerID   string
+}
+
+// reassignFixture mencatat semua write di repository palsu untuk
+// ReassignDepartment.
+type reassignFixture struct {
+	uow      *mocks.UnitOfWork
+	employee *mocks.EmployeeRepository
+	audits   []entity.AuditLog
+	versions []string
+	events   []outboxCall
+}
+
+func newReassignFixture(fromManagerID string, employees ...dto.EmployeeResponse) *reassignFixture {
+	f := &reassignFixture{}
+	f.employee = &mocks.EmployeeRepository{
+		GetAllInDepartmentFunc: func(ctx context.Context, departmentId string, managerId string) ([]dto.EmployeeResponse, error) {
+			return employees, nil
+		},
+		AddVersionFunc: func(ctx context.Context, identityNumber string, action string, managerId string) error {
+			f.versions = append(f.versions, identityNumber+":"+managerId)
+			return nil
+		},
+	}
+	f.uow = &mocks.UnitOfWork{Repositories: repository.Repositories{
+		Employee: f.employee,
+		User: &mocks.UserRepository{
+			GetProfileFunc: func(ctx context.Context, id string) (*entity.GetProfile, error) {
+				return &entity.GetProfile{}, nil
+			},
+		},
+		Department: &mocks.DepartmentRepository{
+			ReassignFunc: func(ctx context.Context, deptID string, toManagerID string) (*entity.Department, string, error) {
+				return &entity.Department{Id: deptID, Name: "Engineering"}, fromManagerID, nil
+			},
+		},
+		Audit: &mocks.AuditRepository{
+			AddFunc: func(ctx context.Context, log entity.AuditLog) error {
+				f.audits = append(f.audits, log)
+				return nil
+			},
+		},
+		Outbox: &mocks.OutboxRepository{
+			AddFunc: func(ctx context.Context, eventType, aggregateType, aggregateId string, payload any) error {
+				call := outboxCall{eventType: eventType, aggregateId: aggregateId}
+				switch event := payload.(type) {
+				case events.Employee:
+					call.managerID = event.ManagerID
+				case events.Department:
+					call.managerID = event.ManagerID
+				}
+				f.events = append(f.events, call)
+				return nil
+			},
+		},
+	}}
+	return f
+}
+
+func (f *reassignFixture) service() AdminService {
+	appMetrics := metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{})
+	firstPage := cache.NewEmployeeFirstPage(nil, 0, mocks.Logger{}, appMetrics)
+	return New(f.uow, clocktest.NewFake(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)), firstPage)
+}
+
+func reassignRequest() dto.AdminDepartmentReassignRequest {
+	return dto.AdminDepartmentReassignRequest{Operator: "ops", DepartmentID: testDeptID, ToManagerID: testToManagerID}
+}
+
+func employee(identityNumber string) dto.EmployeeResponse {
+	return dto.EmployeeResponse{EmployeePayload: dto.EmployeePayload{IdentityNumber: identityNumber, DepartmentID: testDeptID}}
+}
+
+func TestReassignDepartmentRecordsEmployees(t *testing.T) {
+	f := newReassignFixture(testFromManagerID, employee("EMP-1"), employee("EMP-2"))
+
+	response, err := f.service().ReassignDepartment(context.Background(), reassignRequest())
+	if err != nil {
+		t.Fatalf("ReassignDepartment() error = %v", err)
+	}
+	if !f.uow.Committed {
+		t.Fatal("unit of work was not committed")
+	}
+	if response.FromManagerID != testFromManagerID || response.ToManagerID != testToManagerID {
+		t.Fatalf("response = %+v", response)
+	}
+
+	// changedBy versi adalah manager baru, lihat reassignEmployee
+	if want := []string{"EMP-1:" + testToManagerID, "EMP-2:" + testToManagerID}; !slices.Equal(f.versions, want) {
+		t.Errorf("versions = %v, want %v", f.versions, want)
+	}
+
+	wantEvents := []outboxCall{
+		{entity.EventEmployeeDeleted, "EMP-1", testFromManagerID},
+		{entity.EventEmployeeCreated, "EMP-1", testToManagerID},
+		{entity.EventEmployeeDeleted, "EMP-2", testFromManagerID},
+		{entity.EventEmployeeCreated, "EMP-2", testToManagerID},
+		{entity.EventDepartmentDeleted, testDeptID, testFromManagerID},
+		{entity.EventDepartmentCreated, testDeptID, testToManagerID},
+	}
+	if !slices.Equal(f.events, wantEvents) {
+		t.Errorf("events = %+v, want %+v", f.events, wantEvents)
+	}
+
+	// Satu audit department, lalu satu per employee
+	if len(f.audits) != 3 {
+		t.Fatalf("audit logs = %d, want 3", len(f.audits))
+	}
+	for i, identityNumber := range []string{"EMP-1", "EMP-2"} {
+		log := f.audits[i+1]
+		if log.EntityType != entity.AuditEntityEmployee || log.EntityId != identityNumber || log.ActorId != ActorPrefix+"ops" {
+			t.Fatalf("employee audit = %+v", log)
+		}
+		var changes map[string]dto.AuditChange
+		if err := json.Unmarshal(log.Changes, &changes); err != nil {
+			t.Fatalf("decode changes: %v", err)
+		}
+		if change := changes["managerId"]; change.Before != testFromManagerID || change.After != testToManagerID {
+			t.Fatalf("changes = %+v, want managerId %s -> %s", changes, testFromManagerID, testToManagerID)
+		}
+	}
+}
+
+func TestReassignDepartmentWithoutPreviousManager(t *testing.T) {
+	f := newReassignFixture("", employee("EMP-1"))
+
+	if _, err := f.service().ReassignDepartment(context.Background(), reassignRequest()); err != nil {
+		t.Fatalf("ReassignDepartment() error = %v", err)
+	}
+	wantEvents := []outboxCall{
+		{entity.EventEmployeeCreated, "EMP-1", testToManagerID},
+		{entity.EventDepartmentCreated, testDeptID, testToManagerID},
+	}
+	if !slices.Equal(f.events, wantEvents) {
+		t.Errorf("events = %+v, want %+v", f.events, wantEvents)
+	}
+}
+
+func TestReassignDepartmentToSameManager(t *testing.T) {
+	f := newReassignFixture(testToManagerID, employee("EMP-1"))
+	f.employee.GetAllInDepartmentFunc = nil
+
+	if _, err := f.service().ReassignDepartment(context.Background(), reassignRequest()); err != nil {
+		t.Fatalf("ReassignDepartment() error = %v", err)
+	}
+	if len(f.audits) != 0 || len(f.versions) != 0 || len(f.events) != 0 {
+		t.Fatalf("audits = %d, versions = %v, events = %v, want nothing recorded", len(f.audits), f.versions, f.events)
+	}
+}
+
+func TestReassignDepartmentRollsBackWhenHistoryFails(t *testing.T) {
+	f := newReassignFixture(testFromManagerID, employee("EMP-1"))
+	errVersion := errors.New("version failed")
+	f.employee.AddVersionFunc = func(ctx context.Context, identityNumber string, action string, managerId string) error {
+		return errVersion
+	}
+
+	_, err := f.service().ReassignDepartment(context.Background(), reassignRequest())
+	if !errors.Is(err, errVersion) {
+		t.Fatalf("ReassignDepartment() error = %v, want %v", err, errVersion)
+	}
+	if f.uow.Committed {
+		t.Fatal("unit of work was committed")
+	}
+}
+
+const testEmail = "manager@example.com"
+
+var testNow = time.Date(2026, 1, 2, 3, 4, 5, 600, time.UTC)
+
+// userFixture mencatat write ke repository user dan audit palsu untuk
+// perintah user dan sessions.
+type userFixture struct {
+	uow       *mocks.UnitOfWork
+	user      *mocks.UserRepository
+	audits    []entity.AuditLog
+	passwords []string
+	revoked   []time.Time
+}
+
+func newUserFixture() *userFixture {
+	f := &userFixture{}
+	f.user = &mocks.UserRepository{
+		CreateFunc: func(ctx context.Context, user entity.User) (string, error) {
+			f.passwords = append(f.passwords, user.Password)
+			return testToManagerID, nil
+		},
+		GetUserbyEmailFunc: func(ctx context.Context, email string) ([]entity.User, error) {
+			if email != testEmail {
+				return nil, nil
+			}
+			return []entity.User{{Id: testToManagerID}}, nil
+		},
+		UpdatePasswordFunc: func(ctx context.Context, id string, passwordHash string) error {
+			f.passwords = append(f.passwords, passwordHash)
+			return nil
+		},
+		RevokeSessionsFunc: func(ctx context.Context, id string, at time.Time) error {
+			f.revoked = append(f.revoked, at)
+			return nil
+		},
+	}
+	f.uow = &mocks.UnitOfWork{Repositories: repository.Repositories{
+		User: f.user,
+		Audit: &mocks.AuditRepository{
+			AddFunc: func(ctx context.Context, log entity.AuditLog) error {
+				f.audits = append(f.audits, log)
+				return nil
+			},
+		},
+	}}
+	return f
+}
+
+func (f *userFixture) service() AdminService {
+	appMetrics := metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{})
+	firstPage := cache.NewEmployeeFirstPage(nil, 0, mocks.Logger{}, appMetrics)
+	return New(f.uow, clocktest.NewFake(testNow), firstPage)
+}
+
+// wantUserAudit memastikan f mencatat tepat satu audit user dengan actor
+// operator dan field yang berubah sesuai wantChanged.
+func (f *userFixture) wantUserAudit(t *testing.T, action string, wantChanged ...string) map[string]dto.AuditChange {
+	t.Helper()
+	if len(f.audits) != 1 {
+		t.Fatalf("audit logs = %d, want 1", len(f.audits))
+	}
+	log := f.audits[0]
+	if log.ActorId != ActorPrefix+"ops" || log.Action != action || log.EntityType != entity.AuditEntityUser || log.EntityId != testToManagerID {
+		t.Fatalf("audit = %+v", log)
+	}
+	var changes map[string]dto.AuditChange
+	if err := json.Unmarshal(log.Changes, &changes); err != nil {
+		t.Fatalf("decode changes: %v", err)
+	}
+	var changed []string
+	for field := range changes {
+		changed = append(changed, field)
+	}
+	slices.Sort(changed)
+	if !slices.Equal(changed, wantChanged) {
+		t.Fatalf("changed fields = %v, want %v", changed, wantChanged)
+	}
+	return changes
+}
+
+func TestCreateUser(t *testing.T) {
+	f := newUserFixture()
+	response, err := f.service().CreateUser(context.Background(), dto.AdminUserCreateRequest{
+		Operator: "ops", Email: testEmail, Password: "correct-horse",
+	})
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	if response.ManagerID != testToManagerID || response.Email != testEmail || !f.uow.Committed {
+		t.Fatalf("response = %+v, committed = %v", response, f.uow.Committed)
+	}
+	if len(f.passwords) != 1 || bcrypt.CompareHashAndPassword([]byte(f.passwords[0]), []byte("correct-horse")) != nil {
+		t.Fatalf("stored password %v is not a bcrypt hash of the input", f.passwords)
+	}
+	// Hash password tidak pernah masuk audit log
+	f.wantUserAudit(t, entity.AuditActionCreate, "email")
+}
+
+func TestCreateUserErrors(t *testing.T) {
+	errInsert := errors.New("insert failed")
+	for _, tt := range []struct {
+		name      string
+		input     dto.AdminUserCreateRequest
+		createErr error
+		want      error
+		// wantFields berarti error validasi dengan pesan per field
+		wantFields []string
+	}{
+		{name: "without operator", input: dto.AdminUserCreateRequest{Email: testEmail, Password: "correct-horse"}, wantFields: []string{"operator"}},
+		{name: "invalid email and short password", input: dto.AdminUserCreateRequest{Operator: "ops", Email: "nope", Password: "short"}, wantFields: []string{"email", "password"}},
+		{
+			name:      "email already registered",
+			input:     dto.AdminUserCreateRequest{Operator: "ops", Email: testEmail, Password: "correct-horse"},
+			createErr: &pgconn.PgError{Code: "23505"},
+			want:      helper.ErrConflict,
+		},
+		{name: "repository error", input: dto.AdminUserCreateRequest{Operator: "ops", Email: testEmail, Password: "correct-horse"}, createErr: errInsert, want: errInsert},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newUserFixture()
+			f.user.CreateFunc = func(ctx context.Context, user entity.User) (string, error) {
+				return "", tt.createErr
+			}
+
+			_, err := f.service().CreateUser(context.Background(), tt.input)
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Fatalf("CreateUser() error = %v, want %v", err, tt.want)
+			}
+			if tt.wantFields != nil {
+				wantFieldErrors(t, err, tt.wantFields)
+			}
+			if f.uow.Committed || len(f.audits) != 0 {
+				t.Fatalf("committed = %v, audits = %d, want a rollback", f.uow.Committed, len(f.audits))
+			}
+		})
+	}
+}
+
+// wantFieldErrors memastikan err adalah error validasi untuk field
+// tersebut, yang ditulis cmd/admin per field.
+func wantFieldErrors(t *testing.T, err error, fields []string) {
+	t.Helper()
+	var fieldErrors validation.FieldErrors
+	if !errors.As(err, &fieldErrors) {
+		t.Fatalf("error = %v, want validation.FieldErrors", err)
+	}
+	var got []string
+	for field := range fieldErrors {
+		got = append(got, field)
+	}
+	slices.Sort(got)
+	if !slices.Equal(got, fields) {
+		t.Fatalf("invalid fields = %v, want %v", got, fields)
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	f := newUserFixture()
+	response, err := f.service().ResetPassword(context.Background(), dto.AdminUserRequest{Operator: "ops", Email: testEmail})
+	if err != nil {
+		t.Fatalf("ResetPassword() error = %v", err)
+	}
+	// Waktu pencabutan dibulatkan ke detik, sama dengan presisi iat
+	wantRevokedAt := testNow.Truncate(time.Second)
+	if response.ManagerID != testToManagerID || !response.SessionsRevokedAt.Equal(wantRevokedAt) {
+		t.Fatalf("response = %+v", response)
+	}
+	if len(response.Password) != 24 {
+		t.Fatalf("generated password %q has %d characters, want 24", response.Password, len(response.Password))
+	}
+	if len(f.passwords) != 1 || bcrypt.CompareHashAndPassword([]byte(f.passwords[0]), []byte(response.Password)) != nil {
+		t.Fatalf("stored password %v is not a bcrypt hash of the generated password", f.passwords)
+	}
+	if len(f.revoked) != 1 || !f.revoked[0].Equal(wantRevokedAt) {
+		t.Fatalf("revoked at %v, want %v", f.revoked, wantRevokedAt)
+	}
+	changes := f.wantUserAudit(t, entity.AuditActionUpdate, "credentialsResetAt", "sessionsRevokedAt")
+	if strings.Contains(string(f.audits[0].Changes), response.Password) {
+		t.Fatalf("audit changes %s contain the generated password", f.audits[0].Changes)
+	}
+	if change := changes["credentialsResetAt"]; change.Before != nil || change.After == nil {
+		t.Fatalf("credentialsResetAt change = %+v", change)
+	}
+
+	// Setiap reset menghasilkan password yang berbeda
+	again, err := f.service().ResetPassword(context.Background(), dto.AdminUserRequest{Operator: "ops", Email: testEmail})
+	if err != nil || again.Password == response.Password {
+		t.Fatalf("second ResetPassword() = %q, %v, want a new password", again.Password, err)
+	}
+}
+
+func TestRevokeSessions(t *testing.T) {
+	f := newUserFixture()
+	response, err := f.service().RevokeSessions(context.Background(), dto.AdminUserRequest{Operator: "ops", Email: testEmail})
+	if err != nil {
+		t.Fatalf("RevokeSessions() error = %v", err)
+	}
+	wantRevokedAt := testNow.Truncate(time.Second)
+	if response.ManagerID != testToManagerID || !response.SessionsRevokedAt.Equal(wantRevokedAt) {
+		t.Fatalf("response = %+v", response)
+	}
+	if len(f.passwords) != 0 || len(f.revoked) != 1 {
+		t.Fatalf("passwords = %v, revoked = %v, want only a revocation", f.passwords, f.revoked)
+	}
+	f.wantUserAudit(t, entity.AuditActionUpdate, "sessionsRevokedAt")
+}
+
+func TestUserCommandErrors(t *testing.T) {
+	errRevoke := errors.New("revoke failed")
+	commands := map[string]func(s AdminService, input dto.AdminUserRequest) error{
+		"ResetPassword": func(s AdminService, input dto.AdminUserRequest) error {
+			_, err := s.ResetPassword(context.Background(), input)
+			return err
+		},
+		"RevokeSessions": func(s AdminService, input dto.AdminUserRequest) error {
+			_, err := s.RevokeSessions(context.Background(), input)
+			return err
+		},
+	}
+	for name, command := range commands {
+		for _, tt := range []struct {
+			name       string
+			input      dto.AdminUserRequest
+			revokeErr  error
+			want       error
+			wantFields []string
+		}{
+			{name: "without operator", input: dto.AdminUserRequest{Email: testEmail}, wantFields: []string{"operator"}},
+			{name: "unknown email", input: dto.AdminUserRequest{Operator: "ops", Email: "unknown@example.com"}, want: helper.ErrNotFound},
+			{name: "repository error", input: dto.AdminUserRequest{Operator: "ops", Email: testEmail}, revokeErr: errRevoke, want: errRevoke},
+		} {
+			t.Run(name+"/"+tt.name, func(t *testing.T) {
+				f := newUserFixture()
+				f.user.RevokeSessionsFunc = func(ctx context.Context, id string, at time.Time) error {
+					return tt.revokeErr
+				}
+
+				err := command(f.service(), tt.input)
+				if tt.want != nil && !errors.Is(err, tt.want) {
+					t.Fatalf("error = %v, want %v", err, tt.want)
+				}
+				if tt.wantFields != nil {
+					wantFieldErrors(t, err, tt.wantFields)
+				}
+				if f.uow.Committed || len(f.audits) != 0 {
+					t.Fatalf("committed = %v, audits = %d, want a rollback", f.uow.Committed, len(f.audits))
+				}
+			})
+		}
+	}
+}
+
+func TestReassignDepartmentErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		input      dto.AdminDepartmentReassignRequest
+		profileErr error
+		want       error
+		wantFields []string
+	}{
+		{name: "invalid ids", input: dto.AdminDepartmentReassignRequest{Operator: "ops", DepartmentID: "42", ToManagerID: "nope"}, wantFields: []string{"departmentId", "toManagerId"}},
+		// Manager tujuan yang tidak ada menjadi not found, bukan error foreign key
+		{name: "unknown manager", input: reassignRequest(), profileErr: pgx.ErrNoRows, want: helper.ErrNotFound},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newReassignFixture(testFromManagerID, employee("EMP-1"))
+			f.uow.Repositories.User = &mocks.UserRepository{
+				GetProfileFunc: func(ctx context.Context, id string) (*entity.GetProfile, error) {
+					return nil, tt.profileErr
+				},
+			}
+
+			_, err := f.service().ReassignDepartment(context.Background(), tt.input)
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Fatalf("ReassignDepartment() error = %v, want %v", err, tt.want)
+			}
+			if tt.wantFields != nil {
+				wantFieldErrors(t, err, tt.wantFields)
+			}
+			if f.uow.Committed || len(f.audits) != 0 || len(f.events) != 0 {
+				t.Fatalf("committed = %v, audits = %d, events = %v, want nothing recorded", f.uow.Committed, len(f.audits), f.events)
+			}
+		})
+	}
+}
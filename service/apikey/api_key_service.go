@@ -0,0 +1,160 @@
+package apiKeyService
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/levensspel/go-gin-template/clock"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/idgen"
+	repositories "github.com/levensspel/go-gin-template/repository/apikey"
+	"github.com/levensspel/go-gin-template/validation"
+	"github.com/samber/do/v2"
+)
+
+const (
+	// KeyPrefix mengawali setiap API key agar mudah dikenali, misalnya oleh
+	// secret scanner.
+	KeyPrefix = "psk_"
+
+	keyBytes = 32
+	// displayPrefixLength adalah jumlah karakter awal key yang disimpan
+	// apa adanya untuk ditampilkan di daftar key.
+	displayPrefixLength = len(KeyPrefix) + 8
+)
+
+var (
+	ErrInvalidAPIKey = errors.New("invalid api key")
+	ErrAPIKeyExpired = errors.New("api key expired")
+)
+
+func init() {
+	helper.Register(ErrInvalidAPIKey, http.StatusUnauthorized, "ErrInvalidAPIKey")
+	helper.Register(ErrAPIKeyExpired, http.StatusUnauthorized, "ErrAPIKeyExpired")
+}
+
+type APIKeyService interface {
+	Create(ctx context.Context, managerID string, input dto.APIKeyRequest) (dto.APIKeyResponse, error)
+	GetAll(ctx context.Context, managerID string) ([]dto.APIKeyResponse, error)
+	Delete(ctx context.Context, id string, managerID string) error
+	// Authenticate mengembalikan key yang cocok, ErrInvalidAPIKey jika key
+	// tidak dikenal, atau ErrAPIKeyExpired jika sudah kedaluwarsa.
+	Authenticate(ctx context.Context, key string) (entity.APIKey, error)
+}
+
+type service struct {
+	repo  repositories.APIKeyRepositoryInterface
+	ids   idgen.IDGenerator
+	clock clock.Clock
+}
+
+func New(repo repositories.APIKeyRepositoryInterface, ids idgen.IDGenerator, clock clock.Clock) APIKeyService {
+	return &service{repo: repo, ids: ids, clock: clock}
+}
+
+func NewInject(i do.Injector) (APIKeyService, error) {
+	_repo := do.MustInvoke[repositories.APIKeyRepositoryInterface](i)
+	_ids := do.MustInvoke[idgen.IDGenerator](i)
+	_clock := do.MustInvoke[clock.Clock](i)
+	return New(_repo, _ids, _clock), nil
+}
+
+func (s *service) Create(ctx context.Context, managerID string, input dto.APIKeyRequest) (dto.APIKeyResponse, error) {
+	if input.ExpiresAt != nil {
+		if !input.ExpiresAt.After(s.clock.Now()) {
+			return dto.APIKeyResponse{}, validation.FieldErrors{"expiresAt": "expiresAt must be in the future"}
+		}
+		expiresAt := input.ExpiresAt.UTC()
+		input.ExpiresAt = &expiresAt
+	}
+	key, err := newKey()
+	if err != nil {
+		return dto.APIKeyResponse{}, err
+	}
+
+	row, err := s.repo.Create(ctx, entity.APIKey{
+		Id:        s.ids.NewID(),
+		ManagerId: managerID,
+		Name:      input.Name,
+		Prefix:    key[:displayPrefixLength],
+		Hash:      hashKey(key),
+		Scopes:    input.Scopes,
+		ExpiresAt: input.ExpiresAt,
+	})
+	if err != nil {
+		return dto.APIKeyResponse{}, err
+	}
+	response := toAPIKeyResponse(row)
+	// Key hanya bisa dilihat sekali, saat dibuat
+	response.Key = key
+	return response, nil
+}
+
+func (s *service) GetAll(ctx context.Context, managerID string) ([]dto.APIKeyResponse, error) {
+	rows, err := s.repo.GetAll(ctx, managerID)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]dto.APIKeyResponse, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, toAPIKeyResponse(row))
+	}
+	return results, nil
+}
+
+func (s *service) Delete(ctx context.Context, id string, managerID string) error {
+	return s.repo.Delete(ctx, id, managerID)
+}
+
+func (s *service) Authenticate(ctx context.Context, key string) (entity.APIKey, error) {
+	if !strings.HasPrefix(key, KeyPrefix) {
+		return entity.APIKey{}, ErrInvalidAPIKey
+	}
+	// Key dicari berdasarkan hash, sehingga perbandingan key tidak
+	// bergantung pada waktu eksekusi string compare
+	row, err := s.repo.GetByHash(ctx, hashKey(key))
+	if errors.Is(err, helper.ErrNotFound) {
+		return entity.APIKey{}, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return entity.APIKey{}, err
+	}
+	if row.ExpiresAt != nil && !s.clock.Now().Before(*row.ExpiresAt) {
+		return entity.APIKey{}, ErrAPIKeyExpired
+	}
+	return row, nil
+}
+
+func newKey() (string, error) {
+	b := make([]byte, keyBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return KeyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashKey cukup SHA-256 tanpa salt: key adalah 32 byte acak, bukan password
+// yang bisa ditebak.
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func toAPIKeyResponse(row entity.APIKey) dto.APIKeyResponse {
+	return dto.APIKeyResponse{
+		Id:        row.Id,
+		Name:      row.Name,
+		Prefix:    row.Prefix,
+		Scopes:    row.Scopes,
+		ExpiresAt: row.ExpiresAt,
+		CreatedAt: row.CreatedAt,
+	}
+}
@@ -0,0 +1,166 @@
+package apiKeyService
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/clock/clocktest"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/idgen"
+	"github.com/levensspel/go-gin-template/mocks"
+	"github.com/levensspel/go-gin-template/validation"
+)
+
+const testManagerID = "0b7c2d2e-4f4a-4b8e-9c59-2f1d8f6a3e10"
+
+var testNow = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// memoryRepository menyimpan key yang dibuat berdasarkan hash-nya.
+func memoryRepository() (*mocks.APIKeyRepository, map[string]entity.APIKey) {
+	keys := map[string]entity.APIKey{}
+	return &mocks.APIKeyRepository{
+		CreateFunc: func(ctx context.Context, key entity.APIKey) (entity.APIKey, error) {
+			key.CreatedAt = testNow
+			keys[key.Hash] = key
+			return key, nil
+		},
+		GetByHashFunc: func(ctx context.Context, hash string) (entity.APIKey, error) {
+			key, ok := keys[hash]
+			if !ok {
+				return entity.APIKey{}, helper.ErrNotFound
+			}
+			return key, nil
+		},
+	}, keys
+}
+
+func TestCreate(t *testing.T) {
+	repo, keys := memoryRepository()
+	s := New(repo, idgen.NewUUIDv7(), clocktest.NewFake(testNow))
+	expiresAt := testNow.Add(time.Hour).In(time.FixedZone("WIB", 7*60*60))
+
+	response, err := s.Create(context.Background(), testManagerID, dto.APIKeyRequest{
+		Name:      "warehouse",
+		Scopes:    []string{entity.APIKeyScopeEmployeesRead},
+		ExpiresAt: &expiresAt,
+	})
+	if err != nil {
+		t.Fatalf("Create error = %v", err)
+	}
+	if !strings.HasPrefix(response.Key, KeyPrefix) || len(response.Key) != len(KeyPrefix)+43 {
+		t.Fatalf("key = %q, want %s and 32 random bytes", response.Key, KeyPrefix)
+	}
+	if response.Prefix != response.Key[:displayPrefixLength] || !slices.Equal(response.Scopes, []string{entity.APIKeyScopeEmployeesRead}) {
+		t.Fatalf("response = %+v", response)
+	}
+	// Hanya hash yang disimpan, dan expiresAt dalam UTC
+	stored, ok := keys[hashKey(response.Key)]
+	if !ok || stored.ManagerId != testManagerID || strings.Contains(stored.Hash, response.Key) {
+		t.Fatalf("stored keys = %+v", keys)
+	}
+	if stored.ExpiresAt.Location() != time.UTC || !stored.ExpiresAt.Equal(expiresAt) {
+		t.Fatalf("stored expiresAt = %v, want %v in UTC", stored.ExpiresAt, expiresAt)
+	}
+
+	again, err := s.Create(context.Background(), testManagerID, dto.APIKeyRequest{Name: "again", Scopes: []string{entity.APIKeyScopeEmployeesRead}})
+	if err != nil || again.Key == response.Key || again.Id == response.Id {
+		t.Fatalf("second Create = %+v, %v, want a new key", again, err)
+	}
+}
+
+func TestCreateRejectsPastExpiry(t *testing.T) {
+	repo, keys := memoryRepository()
+	s := New(repo, idgen.NewUUIDv7(), clocktest.NewFake(testNow))
+	for _, expiresAt := range []time.Time{testNow, testNow.Add(-time.Second)} {
+		_, err := s.Create(context.Background(), testManagerID, dto.APIKeyRequest{
+			Name:      "warehouse",
+			Scopes:    []string{entity.APIKeyScopeEmployeesRead},
+			ExpiresAt: &expiresAt,
+		})
+		var fieldErrors validation.FieldErrors
+		if !errors.As(err, &fieldErrors) || fieldErrors["expiresAt"] == "" {
+			t.Fatalf("Create(expiresAt=%v) error = %v, want an expiresAt field error", expiresAt, err)
+		}
+	}
+	if len(keys) != 0 {
+		t.Fatalf("stored %d keys", len(keys))
+	}
+}
+
+func TestGetAllDoesNotReturnKeys(t *testing.T) {
+	repo := &mocks.APIKeyRepository{
+		GetAllFunc: func(ctx context.Context, managerId string) ([]entity.APIKey, error) {
+			return []entity.APIKey{{Id: "key-1", Prefix: "psk_abcdefgh", Hash: "hash", Scopes: []string{entity.APIKeyScopeDepartmentsRead}}}, nil
+		},
+	}
+	keys, err := New(repo, idgen.NewUUIDv7(), clocktest.NewFake(testNow)).GetAll(context.Background(), testManagerID)
+	if err != nil {
+		t.Fatalf("GetAll error = %v", err)
+	}
+	if len(keys) != 1 || keys[0].Key != "" || !slices.Equal(keys[0].Scopes, []string{entity.APIKeyScopeDepartmentsRead}) {
+		t.Fatalf("GetAll = %+v", keys)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	repo, _ := memoryRepository()
+	fake := clocktest.NewFake(testNow)
+	s := New(repo, idgen.NewUUIDv7(), fake)
+	expiresAt := testNow.Add(time.Hour)
+	created, err := s.Create(context.Background(), testManagerID, dto.APIKeyRequest{
+		Name:      "warehouse",
+		Scopes:    []string{entity.APIKeyScopeEmployeesRead},
+		ExpiresAt: &expiresAt,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := s.Authenticate(context.Background(), created.Key)
+	if err != nil || key.Id != created.Id || key.ManagerId != testManagerID {
+		t.Fatalf("Authenticate = %+v, %v", key, err)
+	}
+	for _, tt := range []struct {
+		name string
+		key  string
+		want error
+	}{
+		{name: "without prefix", key: strings.TrimPrefix(created.Key, KeyPrefix), want: ErrInvalidAPIKey},
+		{name: "unknown key", key: created.Key + "x", want: ErrInvalidAPIKey},
+		{name: "bearer token", key: "eyJhbGciOi.abc.def", want: ErrInvalidAPIKey},
+	} {
+		if _, err := s.Authenticate(context.Background(), tt.key); !errors.Is(err, tt.want) {
+			t.Errorf("%s: Authenticate error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	// Key kedaluwarsa tepat pada expiresAt
+	fake.Advance(time.Hour - time.Second)
+	if _, err := s.Authenticate(context.Background(), created.Key); err != nil {
+		t.Fatalf("Authenticate before expiry error = %v", err)
+	}
+	fake.Advance(time.Second)
+	if _, err := s.Authenticate(context.Background(), created.Key); !errors.Is(err, ErrAPIKeyExpired) {
+		t.Fatalf("Authenticate at expiresAt error = %v, want %v", err, ErrAPIKeyExpired)
+	}
+}
+
+func TestAuthenticateRepositoryError(t *testing.T) {
+	errLookup := errors.New("connection reset")
+	repo := &mocks.APIKeyRepository{
+		GetByHashFunc: func(ctx context.Context, hash string) (entity.APIKey, error) {
+			return entity.APIKey{}, errLookup
+		},
+	}
+	s := New(repo, idgen.NewUUIDv7(), clocktest.NewFake(testNow))
+	// Error database bukan 401, agar key yang valid tidak dianggap salah
+	if _, err := s.Authenticate(context.Background(), KeyPrefix+"anything"); !errors.Is(err, errLookup) {
+		t.Fatalf("Authenticate error = %v, want %v", err, errLookup)
+	}
+}
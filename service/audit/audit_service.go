@@ -0,0 +1,62 @@
+package auditService
+
+import (
+	"context"
+
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	repositories "github.com/levensspel/go-gin-template/repository/audit"
+	"github.com/samber/do/v2"
+)
+
+type AuditService interface {
+	// GetAll mengembalikan audit log milik managerID saja.
+	GetAll(ctx context.Context, managerID string, input dto.GetAuditLogsRequest) ([]dto.AuditLogResponse, error)
+	// GetAllAdmin mengembalikan audit log semua manager, atau satu manager
+	// jika input.ActorID diisi.
+	GetAllAdmin(ctx context.Context, input dto.GetAuditLogsRequest) ([]dto.AuditLogResponse, error)
+}
+
+type service struct {
+	repo   repositories.AuditRepositoryInterface
+	logger logger.Logger
+}
+
+func New(repo repositories.AuditRepositoryInterface, logger logger.Logger) AuditService {
+	return &service{repo: repo, logger: logger}
+}
+
+func NewInject(i do.Injector) (AuditService, error) {
+	_repo := do.MustInvoke[repositories.AuditRepositoryInterface](i)
+	_logger := do.MustInvoke[logger.LogHandler](i)
+	return New(_repo, &_logger), nil
+}
+
+func (s *service) GetAll(ctx context.Context, managerID string, input dto.GetAuditLogsRequest) ([]dto.AuditLogResponse, error) {
+	input.ActorID = managerID
+	return s.GetAllAdmin(ctx, input)
+}
+
+func (s *service) GetAllAdmin(ctx context.Context, input dto.GetAuditLogsRequest) ([]dto.AuditLogResponse, error) {
+	logs, err := s.repo.GetAll(ctx, &input)
+	if err != nil {
+		s.logger.Error(err.Error(), helper.AuditServiceGetAll, err)
+		return nil, err
+	}
+
+	results := make([]dto.AuditLogResponse, 0, len(logs))
+	for _, log := range logs {
+		results = append(results, dto.AuditLogResponse{
+			Id:         log.Id,
+			ActorID:    log.ActorId,
+			Action:     log.Action,
+			EntityType: log.EntityType,
+			EntityID:   log.EntityId,
+			Changes:    log.Changes,
+			RequestID:  log.RequestId,
+			CreatedAt:  log.CreatedAt,
+		})
+	}
+	return results, nil
+}
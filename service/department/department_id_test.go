@@ -0,0 +1,76 @@
+package departmentService
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/mocks"
+	"github.com/levensspel/go-gin-template/repository"
+)
+
+func TestCreateGeneratesDepartmentID(t *testing.T) {
+	var ids []string
+	department := &mocks.DepartmentRepository{
+		CreateFunc: func(ctx context.Context, id string, name string, managerID string) (*entity.Department, error) {
+			ids = append(ids, id)
+			return &entity.Department{Id: id, Name: name}, nil
+		},
+	}
+	uow := &mocks.UnitOfWork{Repositories: repository.Repositories{
+		Department: department,
+		Audit:      &mocks.AuditRepository{AddFunc: func(ctx context.Context, log entity.AuditLog) error { return nil }},
+		Outbox: &mocks.OutboxRepository{AddFunc: func(ctx context.Context, eventType, aggregateType, aggregateId string, payload any) error {
+			return nil
+		}},
+	}}
+	service := newTestService(department, uow)
+
+	for range 2 {
+		result, err := service.Create(context.Background(), testManagerID, dto.RequestDepartment{DepartmentName: "Engineering"})
+		if err != nil {
+			t.Fatalf("Create error = %v", err)
+		}
+		if result.DepartmentID != ids[len(ids)-1] {
+			t.Fatalf("DepartmentID = %q, want the generated id %q", result.DepartmentID, ids[len(ids)-1])
+		}
+	}
+	for _, id := range ids {
+		if parsed, err := uuid.Parse(id); err != nil || parsed.Version() != 7 {
+			t.Fatalf("generated id %q is not a UUIDv7", id)
+		}
+	}
+	if ids[0] >= ids[1] {
+		t.Fatalf("generated ids %q, %q are not increasing", ids[0], ids[1])
+	}
+}
+
+func TestMalformedDepartmentIDIsRejectedBeforeQuery(t *testing.T) {
+	// UnitOfWork tanpa repository: query apa pun akan panic karena nil
+	service := newTestService(nil, &mocks.UnitOfWork{})
+	ctx := context.Background()
+	const valid = testDeptID
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{name: "update", call: func() error {
+			_, err := service.Update(ctx, "Engineering", "42", testManagerID)
+			return err
+		}},
+		{name: "delete", call: func() error { return service.Delete(ctx, "not-a-uuid", testManagerID, "") }},
+		{name: "delete moveTo", call: func() error { return service.Delete(ctx, valid, testManagerID, "engineering") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, helper.ErrInvalidDepartmentId) {
+				t.Fatalf("error = %v, want %v", err, helper.ErrInvalidDepartmentId)
+			}
+		})
+	}
+}
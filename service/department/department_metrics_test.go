@@ -0,0 +1,65 @@
+package departmentService
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/mocks"
+	"github.com/levensspel/go-gin-template/repository"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCreateCountsDepartments(t *testing.T) {
+	errInsert := errors.New("insert failed")
+	tests := []struct {
+		name      string
+		input     string
+		insertErr error
+		want      int
+	}{
+		{name: "created", input: "Engineering", want: 1},
+		{name: "name too short", input: "HR", want: 0},
+		{name: "insert fails", input: "Engineering", insertErr: errInsert, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			department := &mocks.DepartmentRepository{
+				CreateFunc: func(ctx context.Context, id string, name string, managerID string) (*entity.Department, error) {
+					if tt.insertErr != nil {
+						return nil, tt.insertErr
+					}
+					return &entity.Department{Id: id, Name: name}, nil
+				},
+			}
+			uow := &mocks.UnitOfWork{Repositories: repository.Repositories{
+				Department: department,
+				Audit:      &mocks.AuditRepository{AddFunc: func(ctx context.Context, log entity.AuditLog) error { return nil }},
+				Outbox: &mocks.OutboxRepository{AddFunc: func(ctx context.Context, eventType, aggregateType, aggregateId string, payload any) error {
+					return nil
+				}},
+			}}
+			appMetrics := metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{})
+			service := newTestServiceWithMetrics(department, uow, appMetrics)
+
+			_, err := service.Create(context.Background(), testManagerID, dto.RequestDepartment{DepartmentName: tt.input})
+			if (err == nil) != (tt.want == 1) {
+				t.Fatalf("Create error = %v", err)
+			}
+			if tt.want == 0 {
+				if got := testutil.CollectAndCount(appMetrics.DepartmentsCreated); got != 0 {
+					t.Fatalf("departments created has %d series, want 0", got)
+				}
+				return
+			}
+			if got := testutil.ToFloat64(appMetrics.DepartmentsCreated.WithLabelValues("all")); got != 1 {
+				t.Fatalf("departments created = %v, want 1", got)
+			}
+		})
+	}
+}
@@ -3,48 +3,153 @@ package departmentService
 import (
 	"context"
 	"fmt"
-	"strconv"
 
+	"github.com/levensspel/go-gin-template/audit"
+	"github.com/levensspel/go-gin-template/cache"
+	"github.com/levensspel/go-gin-template/clock"
+	"github.com/levensspel/go-gin-template/config"
 	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/events"
 	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/idgen"
 	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/lru"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/repository"
 	repositories "github.com/levensspel/go-gin-template/repository/department"
+	"github.com/levensspel/go-gin-template/validation"
 	"github.com/samber/do/v2"
 )
 
+// DepartmentService selalu dibatasi pada department milik managerID.
+// Department manager lain diperlakukan sama dengan department yang tidak
+// ada: tidak muncul di hasil list dan GetByIDs, helper.ErrNotFound untuk
+// Update dan Delete, dan helper.ErrInvalidDepartmentId jika dipakai sebagai
+// tujuan (moveTo atau departmentId employee).
 type DepartmentService interface {
-	Create(managerID string, input dto.RequestDepartment) (dto.ResponseSingleDepartment, error)
-	GetAll(managerID string, input dto.RequestDepartment) ([]dto.ResponseSingleDepartment, error)
-	Update(name string, id string, managerID string) (dto.ResponseSingleDepartment, error)
-	Delete(id string, managerID string) error
+	Create(ctx context.Context, managerID string, input dto.RequestDepartment) (dto.ResponseSingleDepartment, error)
+	GetAll(ctx context.Context, managerID string, input dto.RequestDepartment) ([]dto.ResponseSingleDepartment, error)
+	// Count menghitung department dengan filter nama GetAll, untuk header
+	// Link daftar department.
+	Count(ctx context.Context, managerID string, input dto.RequestDepartment) (int64, error)
+	Update(ctx context.Context, name string, id string, managerID string) (dto.ResponseSingleDepartment, error)
+	Delete(ctx context.Context, id string, managerID string, moveTo string) error
+	// GetByIDs dan CountEmployees dipakai dataloader GraphQL untuk mengambil
+	// banyak department sekaligus, lihat package graph.
+	GetByIDs(ctx context.Context, managerID string, ids []string) ([]dto.ResponseSingleDepartment, error)
+	CountEmployees(ctx context.Context, managerID string, ids []string) (map[string]int, error)
 }
 
 type service struct {
-	repo   repositories.DepartmentRepository
-	logger logger.Logger
+	repo      repositories.DepartmentRepositoryInterface
+	uow       repository.UnitOfWork
+	ids       idgen.IDGenerator
+	logger    logger.Logger
+	metrics   *metrics.Metrics
+	listCache *lru.Cache[departmentListKey, []dto.ResponseSingleDepartment]
+	// countCache memakai departmentListKey tanpa limit dan offset
+	countCache *lru.Cache[departmentListKey, int64]
+	// firstPage di-invalidate saat employee dipindahkan dari department yang
+	// dihapus
+	firstPage *cache.EmployeeFirstPage
 }
 
-func New(repo repositories.DepartmentRepository, logger logger.Logger) DepartmentService {
+// departmentListKey berisi semua parameter GetAll, sehingga setiap halaman
+// dan filter nama disimpan terpisah.
+type departmentListKey struct {
+	managerID string
+	name      string
+	limit     int
+	offset    int
+}
+
+func New(
+	repo repositories.DepartmentRepositoryInterface,
+	uow repository.UnitOfWork,
+	ids idgen.IDGenerator,
+	logger logger.Logger,
+	metrics *metrics.Metrics,
+	listCache *lru.Cache[departmentListKey, []dto.ResponseSingleDepartment],
+	countCache *lru.Cache[departmentListKey, int64],
+	firstPage *cache.EmployeeFirstPage,
+) DepartmentService {
 	return &service{
-		repo:   repo,
-		logger: logger,
+		repo:       repo,
+		uow:        uow,
+		ids:        ids,
+		logger:     logger,
+		metrics:    metrics,
+		listCache:  listCache,
+		countCache: countCache,
+		firstPage:  firstPage,
 	}
 }
 
 func NewInject(i do.Injector) (DepartmentService, error) {
-	_repo := do.MustInvoke[repositories.DepartmentRepository](i)
+	_repo := do.MustInvoke[repositories.DepartmentRepositoryInterface](i)
+	_uow := do.MustInvoke[repository.UnitOfWork](i)
+	_ids := do.MustInvoke[idgen.IDGenerator](i)
 	_logger := do.MustInvoke[logger.LogHandler](i)
-	return New(_repo, &_logger), nil
+	_metrics := do.MustInvoke[*metrics.Metrics](i)
+	_firstPage := do.MustInvoke[*cache.EmployeeFirstPage](i)
+	_clock := do.MustInvoke[clock.Clock](i)
+	cacheConfig := config.LoadCacheConfig()
+	listCache := lru.New[departmentListKey, []dto.ResponseSingleDepartment](lru.Settings{
+		Name:       "department_list",
+		MaxEntries: cacheConfig.MaxEntries,
+		TTL:        cacheConfig.DepartmentListTTL,
+		OnHit:      _metrics.CountCacheHit,
+		OnMiss:     _metrics.CountCacheMiss,
+		OnEvict:    _metrics.CountCacheEvictions,
+		Clock:      _clock,
+	})
+	countCache := lru.New[departmentListKey, int64](lru.Settings{
+		Name:       "department_count",
+		MaxEntries: cacheConfig.MaxEntries,
+		TTL:        cacheConfig.DepartmentListTTL,
+		OnHit:      _metrics.CountCacheHit,
+		OnMiss:     _metrics.CountCacheMiss,
+		OnEvict:    _metrics.CountCacheEvictions,
+		Clock:      _clock,
+	})
+	return New(_repo, _uow, _ids, &_logger, _metrics, listCache, countCache, _firstPage), nil
+}
+
+// invalidateList menghapus semua halaman daftar department milik manager
+// beserta totalnya.
+func (s *service) invalidateList(managerID string) {
+	match := func(key departmentListKey) bool {
+		return key.managerID == managerID
+	}
+	s.listCache.InvalidateFunc(match)
+	s.countCache.InvalidateFunc(match)
 }
 
 func (s *service) Create(
+	ctx context.Context,
 	managerID string,
 	input dto.RequestDepartment,
 ) (dto.ResponseSingleDepartment, error) {
 	if len(input.DepartmentName) < 4 || len(input.DepartmentName) > 33 {
 		return dto.ResponseSingleDepartment{}, helper.ErrBadRequest
 	}
-	row, err := s.repo.Create(context.Background(), input.DepartmentName, managerID)
+	var row *entity.Department
+	err := s.uow.Do(ctx, func(repos repository.Repositories) error {
+		var err error
+		row, err = repos.Department.Create(ctx, s.ids.NewID(), input.DepartmentName, managerID)
+		if err != nil {
+			return err
+		}
+		if err := recordDepartment(ctx, repos, entity.AuditActionCreate, managerID, row.Id, nil, row); err != nil {
+			return err
+		}
+		return addDepartmentEvent(ctx, repos, entity.EventDepartmentCreated, events.Department{
+			ManagerID:      managerID,
+			DepartmentID:   row.Id,
+			DepartmentName: row.Name,
+		})
+	})
 	if err != nil {
 		s.logger.Error(
 			fmt.Sprintf("Error fetching rows: %v", err),
@@ -53,6 +158,8 @@ func (s *service) Create(
 		)
 		return dto.ResponseSingleDepartment{}, err
 	}
+	s.invalidateList(managerID)
+	s.metrics.CountDepartmentCreated(managerID)
 	result := dto.ResponseSingleDepartment{
 		DepartmentID:   row.Id,
 		DepartmentName: row.Name,
@@ -61,11 +168,76 @@ func (s *service) Create(
 }
 
 func (s *service) GetAll(
+	ctx context.Context,
+	managerID string,
+	input dto.RequestDepartment,
+) ([]dto.ResponseSingleDepartment, error) {
+	key := departmentListKey{
+		managerID: managerID,
+		name:      input.DepartmentName,
+		limit:     input.Limit,
+		offset:    input.Offset,
+	}
+	return s.listCache.GetOrLoad(key, func() ([]dto.ResponseSingleDepartment, error) {
+		// Load dipakai bersama pemanggil lain dengan key yang sama, sehingga
+		// tidak ikut batal jika request pertama batal
+		return s.getAll(context.WithoutCancel(ctx), managerID, input)
+	})
+}
+
+func (s *service) Count(
+	ctx context.Context,
+	managerID string,
+	input dto.RequestDepartment,
+) (int64, error) {
+	key := departmentListKey{managerID: managerID, name: input.DepartmentName}
+	return s.countCache.GetOrLoad(key, func() (int64, error) {
+		count, err := s.repo.Count(context.WithoutCancel(ctx), input.DepartmentName, managerID)
+		if err != nil {
+			s.logger.Error(err.Error(), helper.DepartmentServiceCount, err)
+			return 0, err
+		}
+		return count, nil
+	})
+}
+
+func (s *service) GetByIDs(
+	ctx context.Context,
+	managerID string,
+	ids []string,
+) ([]dto.ResponseSingleDepartment, error) {
+	rows, err := s.repo.GetByIDs(ctx, ids, managerID)
+	if err != nil {
+		s.logger.Error(err.Error(), helper.DepartmentServiceGetByIDs, err)
+		return nil, err
+	}
+	results := make([]dto.ResponseSingleDepartment, len(rows))
+	for i, item := range rows {
+		results[i] = dto.ResponseSingleDepartment{DepartmentID: item.Id, DepartmentName: item.Name}
+	}
+	return results, nil
+}
+
+func (s *service) CountEmployees(
+	ctx context.Context,
+	managerID string,
+	ids []string,
+) (map[string]int, error) {
+	counts, err := s.repo.CountEmployees(ctx, ids, managerID)
+	if err != nil {
+		s.logger.Error(err.Error(), helper.DepartmentServiceCountEmployees, err)
+		return nil, err
+	}
+	return counts, nil
+}
+
+func (s *service) getAll(
+	ctx context.Context,
 	managerID string,
 	input dto.RequestDepartment,
 ) ([]dto.ResponseSingleDepartment, error) {
 	rows, err := s.repo.GetAll(
-		context.Background(),
+		ctx,
 		input.DepartmentName,
 		input.Limit,
 		input.Offset,
@@ -92,20 +264,33 @@ func (s *service) GetAll(
 }
 
 func (s *service) Update(
+	ctx context.Context,
 	name string,
 	id string,
 	managerID string,
 ) (dto.ResponseSingleDepartment, error) {
-	deptID, err := strconv.Atoi(id)
-	if err != nil {
-		s.logger.Error(
-			fmt.Sprintf("Invalid int conversion: %v", err),
-			helper.DepartmentServicePatch,
-			err,
-		)
+	if err := validation.ValidateDepartmentID(id); err != nil {
 		return dto.ResponseSingleDepartment{}, err
 	}
-	row, err := s.repo.Update(context.Background(), name, deptID, managerID)
+	var row *entity.Department
+	err := s.uow.Do(ctx, func(repos repository.Repositories) error {
+		before, err := repos.Department.GetForUpdate(ctx, id, managerID)
+		if err != nil {
+			return err
+		}
+		row, err = repos.Department.Update(ctx, name, id, managerID)
+		if err != nil {
+			return err
+		}
+		if err := recordDepartment(ctx, repos, entity.AuditActionUpdate, managerID, row.Id, before, row); err != nil {
+			return err
+		}
+		return addDepartmentEvent(ctx, repos, entity.EventDepartmentUpdated, events.Department{
+			ManagerID:      managerID,
+			DepartmentID:   row.Id,
+			DepartmentName: row.Name,
+		})
+	})
 	if err != nil {
 		s.logger.Error(
 			fmt.Sprintf("Error fetching rows: %v", err),
@@ -114,6 +299,8 @@ func (s *service) Update(
 		)
 		return dto.ResponseSingleDepartment{}, err
 	}
+	s.invalidateList(managerID)
+	s.metrics.CountDepartmentCreated(managerID)
 	result := dto.ResponseSingleDepartment{
 		DepartmentID:   row.Id,
 		DepartmentName: row.Name,
@@ -121,23 +308,105 @@ func (s *service) Update(
 	return result, nil
 }
 
-func (s *service) Delete(id string, managerID string) error {
-	deptID, err := strconv.Atoi(id)
-	if err != nil {
-		s.logger.Error(
-			fmt.Sprintf("Invalid int conversion: %v", err),
-			helper.DepartmentServiceDelete,
-			err,
-		)
+// Delete menghapus department. Jika moveTo diisi, employee di department
+// tersebut dipindahkan dulu ke department moveTo dalam transaksi yang sama,
+// sehingga department yang masih berisi employee tetap bisa dihapus. Setiap
+// employee yang dipindahkan dicatat seperti update employee: versi, audit
+// log, dan event employee.updated.
+func (s *service) Delete(ctx context.Context, id string, managerID string, moveTo string) error {
+	if err := validation.ValidateDepartmentID(id); err != nil {
 		return err
 	}
-	err = s.repo.Delete(context.Background(), deptID, managerID)
+
+	var err error
+	if moveTo == "" {
+		err = s.uow.Do(ctx, func(repos repository.Repositories) error {
+			return deleteDepartment(ctx, repos, id, managerID, "")
+		})
+	} else {
+		err = s.deleteAndMove(ctx, id, moveTo, managerID)
+	}
 	if err != nil {
 		s.logger.Error(
 			err.Error(),
 			helper.DepartmentServiceDelete,
 			err,
 		)
+		return err
+	}
+	s.invalidateList(managerID)
+	if moveTo != "" {
+		s.firstPage.Invalidate(ctx, managerID)
+	}
+	return nil
+}
+
+func (s *service) deleteAndMove(ctx context.Context, deptID string, moveTo string, managerID string) error {
+	if err := validation.ValidateDepartmentID(moveTo); err != nil {
+		return err
+	}
+	if moveTo == deptID {
+		return helper.ErrBadRequest
+	}
+
+	return s.uow.Do(ctx, func(repos repository.Repositories) error {
+		employees, err := repos.Employee.MoveDepartment(ctx, deptID, moveTo, managerID)
+		if err != nil {
+			return err
+		}
+		for _, employee := range employees {
+			if err := recordMovedEmployee(ctx, repos, managerID, deptID, employee); err != nil {
+				return err
+			}
+		}
+		return deleteDepartment(ctx, repos, deptID, managerID, moveTo)
+	})
+}
+
+// deleteDepartment menghapus department beserta audit log dan event-nya di
+// transaksi repos.
+func deleteDepartment(ctx context.Context, repos repository.Repositories, deptID string, managerID string, moveTo string) error {
+	before, err := repos.Department.GetForUpdate(ctx, deptID, managerID)
+	if err != nil {
+		return err
+	}
+	if err := repos.Department.Delete(ctx, deptID, managerID); err != nil {
+		return err
 	}
-	return err
+	if err := recordDepartment(ctx, repos, entity.AuditActionDelete, managerID, before.Id, before, nil); err != nil {
+		return err
+	}
+	return addDepartmentEvent(ctx, repos, entity.EventDepartmentDeleted, events.Department{
+		ManagerID:    managerID,
+		DepartmentID: before.Id,
+		MovedTo:      moveTo,
+	})
+}
+
+// recordMovedEmployee mencatat employee yang dipindahkan dari department
+// fromDeptID sama dengan EmployeeService.Update yang mengubah departmentId.
+func recordMovedEmployee(ctx context.Context, repos repository.Repositories, managerID, fromDeptID string, employee dto.EmployeeResponse) error {
+	before := employee
+	before.DepartmentID = fromDeptID
+	if err := audit.Record(ctx, repos.Audit, managerID, entity.AuditActionUpdate, entity.AuditEntityEmployee, employee.IdentityNumber, before, employee); err != nil {
+		return err
+	}
+	if err := repos.Employee.AddVersion(ctx, employee.IdentityNumber, entity.AuditActionUpdate, managerID); err != nil {
+		return err
+	}
+	return repos.Outbox.Add(ctx, entity.EventEmployeeUpdated, entity.AggregateEmployee, employee.IdentityNumber, events.Employee{
+		ManagerID: managerID,
+		Employee:  employee,
+	})
+}
+
+// recordDepartment mencatat perubahan department di audit log.
+func recordDepartment(ctx context.Context, repos repository.Repositories, action, managerID, departmentID string, before, after *entity.Department) error {
+	return audit.Record(ctx, repos.Audit, managerID, action, entity.AuditEntityDepartment, departmentID, before, after)
+}
+
+// addDepartmentEvent mencatat event department di outbox dalam transaksi
+// yang sama dengan perubahannya.
+func addDepartmentEvent(ctx context.Context, repos repository.Repositories, eventType string, event events.Department) error {
+	return repos.Outbox.Add(ctx, eventType, entity.AggregateDepartment, event.DepartmentID, event)
 }
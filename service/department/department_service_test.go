@@ -0,0 +1,241 @@
+package departmentService
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/cache"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/idgen"
+	"github.com/levensspel/go-gin-template/lru"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/mocks"
+	"github.com/levensspel/go-gin-template/repository"
+	repositories "github.com/levensspel/go-gin-template/repository/department"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	testManagerID  = "0b7c2d2e-4f4a-4b8e-9c59-2f1d8f6a3e10"
+	testDeptID     = "1c8d3e3f-5a5b-4c9f-8d6a-3a2e9a7b4f21"
+	testMoveToID   = "2d9e4f4a-6b6c-4dab-9e7b-4b3fab8c5a32"
+	testEmployeeID = "EMP-1"
+)
+
+// deleteFixture mencatat semua write di repository palsu untuk Delete.
+type deleteFixture struct {
+	uow      *mocks.UnitOfWork
+	employee *mocks.EmployeeRepository
+	audits   []entity.AuditLog
+	versions []string
+	events   []string
+	deleted  bool
+}
+
+func newDeleteFixture(moved ...dto.EmployeeResponse) *deleteFixture {
+	f := &deleteFixture{}
+	f.employee = &mocks.EmployeeRepository{
+		MoveDepartmentFunc: func(ctx context.Context, fromDepartmentId, toDepartmentId, managerId string) ([]dto.EmployeeResponse, error) {
+			return moved, nil
+		},
+		AddVersionFunc: func(ctx context.Context, identityNumber string, action string, managerId string) error {
+			f.versions = append(f.versions, action+":"+identityNumber)
+			return nil
+		},
+	}
+	department := &mocks.DepartmentRepository{
+		GetForUpdateFunc: func(ctx context.Context, deptID string, managerID string) (*entity.Department, error) {
+			return &entity.Department{Id: deptID, Name: "Engineering"}, nil
+		},
+		DeleteFunc: func(ctx context.Context, deptID string, managerID string) error {
+			f.deleted = true
+			return nil
+		},
+	}
+	auditRepo := &mocks.AuditRepository{
+		AddFunc: func(ctx context.Context, log entity.AuditLog) error {
+			f.audits = append(f.audits, log)
+			return nil
+		},
+	}
+	outbox := &mocks.OutboxRepository{
+		AddFunc: func(ctx context.Context, eventType, aggregateType, aggregateId string, payload any) error {
+			f.events = append(f.events, eventType+":"+aggregateId)
+			return nil
+		},
+	}
+	f.uow = &mocks.UnitOfWork{Repositories: repository.Repositories{
+		Employee:   f.employee,
+		Department: department,
+		Audit:      auditRepo,
+		Outbox:     outbox,
+	}}
+	return f
+}
+
+func (f *deleteFixture) service() DepartmentService {
+	return newTestService(nil, f.uow)
+}
+
+func newTestService(repo *mocks.DepartmentRepository, uow *mocks.UnitOfWork) DepartmentService {
+	return newTestServiceWithMetrics(repo, uow, metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{}))
+}
+
+func newTestServiceWithMetrics(repo *mocks.DepartmentRepository, uow *mocks.UnitOfWork, appMetrics *metrics.Metrics) DepartmentService {
+	settings := lru.Settings{MaxEntries: 10, TTL: time.Minute}
+	listCache := lru.New[departmentListKey, []dto.ResponseSingleDepartment](settings)
+	countCache := lru.New[departmentListKey, int64](settings)
+	firstPage := cache.NewEmployeeFirstPage(nil, 0, mocks.Logger{}, appMetrics)
+	var repoInterface repositories.DepartmentRepositoryInterface
+	if repo != nil {
+		repoInterface = repo
+	}
+	return New(repoInterface, uow, idgen.NewUUIDv7(), mocks.Logger{}, appMetrics, listCache, countCache, firstPage)
+}
+
+func TestDeleteAndMoveRecordsMovedEmployees(t *testing.T) {
+	moved := dto.EmployeeResponse{EmployeePayload: dto.EmployeePayload{
+		IdentityNumber: testEmployeeID,
+		Name:           "Budi",
+		Gender:         "male",
+		DepartmentID:   testMoveToID,
+	}}
+	f := newDeleteFixture(moved)
+
+	if err := f.service().Delete(context.Background(), testDeptID, testManagerID, testMoveToID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if !f.uow.Committed || !f.deleted {
+		t.Fatalf("committed = %v, deleted = %v, want both true", f.uow.Committed, f.deleted)
+	}
+
+	if want := []string{"update:" + testEmployeeID}; !slices.Equal(f.versions, want) {
+		t.Errorf("versions = %v, want %v", f.versions, want)
+	}
+	wantEvents := []string{entity.EventEmployeeUpdated + ":" + testEmployeeID, entity.EventDepartmentDeleted + ":" + testDeptID}
+	if !slices.Equal(f.events, wantEvents) {
+		t.Errorf("events = %v, want %v", f.events, wantEvents)
+	}
+
+	if len(f.audits) != 2 {
+		t.Fatalf("audit logs = %d, want employee and department", len(f.audits))
+	}
+	employeeAudit := f.audits[0]
+	if employeeAudit.EntityType != entity.AuditEntityEmployee || employeeAudit.EntityId != testEmployeeID || employeeAudit.Action != entity.AuditActionUpdate {
+		t.Fatalf("employee audit = %+v", employeeAudit)
+	}
+	var changes map[string]dto.AuditChange
+	if err := json.Unmarshal(employeeAudit.Changes, &changes); err != nil {
+		t.Fatalf("decode changes: %v", err)
+	}
+	change, ok := changes["departmentId"]
+	if len(changes) != 1 || !ok || change.Before != testDeptID || change.After != testMoveToID {
+		t.Fatalf("changes = %+v, want only departmentId %s -> %s", changes, testDeptID, testMoveToID)
+	}
+}
+
+func TestDeleteAndMoveWithoutEmployees(t *testing.T) {
+	f := newDeleteFixture()
+
+	if err := f.service().Delete(context.Background(), testDeptID, testManagerID, testMoveToID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if len(f.versions) != 0 {
+		t.Errorf("versions = %v, want none", f.versions)
+	}
+	if want := []string{entity.EventDepartmentDeleted + ":" + testDeptID}; !slices.Equal(f.events, want) {
+		t.Errorf("events = %v, want %v", f.events, want)
+	}
+}
+
+func TestDeleteAndMoveRollsBackWhenHistoryFails(t *testing.T) {
+	f := newDeleteFixture(dto.EmployeeResponse{EmployeePayload: dto.EmployeePayload{IdentityNumber: testEmployeeID}})
+	errVersion := errors.New("version failed")
+	f.employee.AddVersionFunc = func(ctx context.Context, identityNumber string, action string, managerId string) error {
+		return errVersion
+	}
+
+	err := f.service().Delete(context.Background(), testDeptID, testManagerID, testMoveToID)
+	if !errors.Is(err, errVersion) {
+		t.Fatalf("Delete() error = %v, want %v", err, errVersion)
+	}
+	if f.uow.Committed || f.deleted {
+		t.Fatalf("committed = %v, deleted = %v, want the move rolled back", f.uow.Committed, f.deleted)
+	}
+	if len(f.events) != 0 {
+		t.Errorf("events = %v, want none", f.events)
+	}
+}
+
+func TestDeleteAndMoveRejectsSameDepartment(t *testing.T) {
+	f := newDeleteFixture()
+	f.employee.MoveDepartmentFunc = nil
+
+	err := f.service().Delete(context.Background(), testDeptID, testManagerID, testDeptID)
+	if err == nil {
+		t.Fatal("Delete() error = nil, want an error for moveTo equal to the deleted department")
+	}
+	if f.deleted {
+		t.Fatal("department was deleted")
+	}
+}
+
+func TestCountIsCachedUntilDepartmentChanges(t *testing.T) {
+	var counted int
+	total := int64(3)
+	repo := &mocks.DepartmentRepository{
+		CountFunc: func(ctx context.Context, name string, managerID string) (int64, error) {
+			counted++
+			return total, nil
+		},
+	}
+	f := newDeleteFixture()
+	s := newTestService(repo, f.uow)
+	ctx := context.Background()
+	input := dto.RequestDepartment{DepartmentName: "%", Limit: 5}
+
+	for offset := range 2 {
+		input.Offset = offset * 5
+		got, err := s.Count(ctx, testManagerID, input)
+		if err != nil || got != 3 {
+			t.Fatalf("Count() = %d, %v, want 3", got, err)
+		}
+	}
+	if counted != 1 {
+		t.Fatalf("repository Count called %d times, want 1 for every page", counted)
+	}
+
+	total = 2
+	if err := s.Delete(ctx, testDeptID, testManagerID, ""); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	got, err := s.Count(ctx, testManagerID, input)
+	if err != nil || got != 2 || counted != 2 {
+		t.Fatalf("Count() after delete = %d, %v (counted %d), want a fresh 2", got, err, counted)
+	}
+}
+
+func TestCountErrorIsNotCached(t *testing.T) {
+	var counted int
+	repo := &mocks.DepartmentRepository{
+		CountFunc: func(ctx context.Context, name string, managerID string) (int64, error) {
+			counted++
+			return 0, errors.New("count failed")
+		},
+	}
+	s := newTestService(repo, nil)
+	for range 2 {
+		if _, err := s.Count(context.Background(), testManagerID, dto.RequestDepartment{DepartmentName: "%"}); err == nil {
+			t.Fatal("Count() error = nil")
+		}
+	}
+	if counted != 2 {
+		t.Fatalf("repository Count called %d times, want 2", counted)
+	}
+}
@@ -0,0 +1,86 @@
+package user_service
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+// listCoalescer menggabungkan GetAll identik yang berjalan bersamaan menjadi
+// satu query. Berbeda dengan singleflight, query bersama dibatalkan begitu
+// semua pemanggilnya batal, sehingga request yang ditinggal client tidak
+// terus memakai koneksi database.
+type listCoalescer struct {
+	mu    sync.Mutex
+	calls map[string]*listCall
+}
+
+type listCall struct {
+	done    chan struct{}
+	val     []dto.EmployeeResponse
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// do menjalankan fn sekali per key. Context fn membawa value dari pemanggil
+// pertama (mis. span) tetapi tidak deadline-nya; fn tetap dibatasi timeout
+// repository.
+func (c *listCoalescer) do(
+	ctx context.Context,
+	key string,
+	fn func(ctx context.Context) ([]dto.EmployeeResponse, error),
+) ([]dto.EmployeeResponse, error) {
+	c.mu.Lock()
+	if c.calls == nil {
+		c.calls = make(map[string]*listCall)
+	}
+	call, ok := c.calls[key]
+	if !ok {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &listCall{done: make(chan struct{}), cancel: cancel}
+		c.calls[key] = call
+		go func() {
+			defer close(call.done)
+			defer cancel()
+			call.val, call.err = fn(callCtx)
+			c.forget(key, call)
+		}()
+	}
+	call.waiters++
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.val, call.err
+	case <-ctx.Done():
+		c.mu.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			// Pemanggil berikutnya dengan key yang sama memulai query baru
+			c.forgetLocked(key, call)
+			call.cancel()
+		}
+		c.mu.Unlock()
+		// ctx.Err() apa adanya akan dianggap acquire timeout oleh QueryError
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, helper.ErrQueryTimeout
+		}
+		return nil, ctx.Err()
+	}
+}
+
+func (c *listCoalescer) forget(key string, call *listCall) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.forgetLocked(key, call)
+}
+
+func (c *listCoalescer) forgetLocked(key string, call *listCall) {
+	if c.calls[key] == call {
+		delete(c.calls, key)
+	}
+}
@@ -0,0 +1,183 @@
+package user_service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+// blockingList adalah fn listCoalescer yang menunggu release atau context
+// query-nya dibatalkan.
+type blockingList struct {
+	calls    atomic.Int64
+	started  chan struct{}
+	release  chan struct{}
+	canceled chan struct{}
+}
+
+func newBlockingList() *blockingList {
+	return &blockingList{started: make(chan struct{}, 10), release: make(chan struct{}), canceled: make(chan struct{}, 10)}
+}
+
+func (b *blockingList) fn(ctx context.Context) ([]dto.EmployeeResponse, error) {
+	b.calls.Add(1)
+	b.started <- struct{}{}
+	select {
+	case <-b.release:
+		return []dto.EmployeeResponse{{EmployeePayload: dto.EmployeePayload{IdentityNumber: "EMP-1"}}}, nil
+	case <-ctx.Done():
+		b.canceled <- struct{}{}
+		return nil, ctx.Err()
+	}
+}
+
+func waitFor(t *testing.T, c <-chan struct{}, what string) {
+	t.Helper()
+	select {
+	case <-c:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for %s", what)
+	}
+}
+
+func TestCoalescerSharesIdenticalCalls(t *testing.T) {
+	var c listCoalescer
+	list := newBlockingList()
+
+	const callers = 5
+	var wg sync.WaitGroup
+	results := make(chan []dto.EmployeeResponse, callers)
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			val, err := c.do(context.Background(), "key", list.fn)
+			if err != nil {
+				t.Errorf("do error = %v", err)
+			}
+			results <- val
+		}()
+	}
+	waitFor(t, list.started, "the shared query")
+	// Beri waktu pemanggil lain bergabung sebelum query selesai
+	time.Sleep(20 * time.Millisecond)
+	close(list.release)
+	wg.Wait()
+	close(results)
+
+	if got := list.calls.Load(); got != 1 {
+		t.Fatalf("fn called %d times, want 1", got)
+	}
+	for val := range results {
+		if len(val) != 1 || val[0].IdentityNumber != "EMP-1" {
+			t.Fatalf("do = %+v", val)
+		}
+	}
+}
+
+func TestCoalescerKeepsQueryWhileAnyCallerWaits(t *testing.T) {
+	var c listCoalescer
+	list := newBlockingList()
+
+	first, cancelFirst := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := c.do(first, "key", list.fn)
+		firstErr <- err
+	}()
+	waitFor(t, list.started, "the shared query")
+
+	second := make(chan error, 1)
+	go func() {
+		_, err := c.do(context.Background(), "key", list.fn)
+		second <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancelFirst()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled caller error = %v, want %v", err, context.Canceled)
+	}
+	select {
+	case <-list.canceled:
+		t.Fatal("shared query was cancelled while another caller was still waiting")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(list.release)
+	if err := <-second; err != nil {
+		t.Fatalf("remaining caller error = %v", err)
+	}
+}
+
+func TestCoalescerCancelsQueryWhenAllCallersLeave(t *testing.T) {
+	var c listCoalescer
+	list := newBlockingList()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		_, err := c.do(ctx, "key", list.fn)
+		result <- err
+	}()
+	waitFor(t, list.started, "the shared query")
+	cancel()
+
+	if err := <-result; !errors.Is(err, context.Canceled) {
+		t.Fatalf("do error = %v, want %v", err, context.Canceled)
+	}
+	waitFor(t, list.canceled, "the abandoned query to be cancelled")
+
+	// Pemanggil berikutnya tidak bergabung ke query yang sudah dibatalkan
+	next := make(chan error, 1)
+	go func() {
+		_, err := c.do(context.Background(), "key", list.fn)
+		next <- err
+	}()
+	waitFor(t, list.started, "a new query")
+	close(list.release)
+	if err := <-next; err != nil {
+		t.Fatalf("next caller error = %v", err)
+	}
+	if got := list.calls.Load(); got != 2 {
+		t.Fatalf("fn called %d times, want 2", got)
+	}
+}
+
+func TestCoalescerDeadlineIsQueryTimeout(t *testing.T) {
+	var c listCoalescer
+	list := newBlockingList()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.do(ctx, "key", list.fn); !errors.Is(err, helper.ErrQueryTimeout) {
+		t.Fatalf("do error = %v, want %v", err, helper.ErrQueryTimeout)
+	}
+	waitFor(t, list.canceled, "the timed out query to be cancelled")
+}
+
+func TestCoalescerQueryKeepsCallerValues(t *testing.T) {
+	type key struct{}
+	var c listCoalescer
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), key{}, "span"), time.Hour)
+	defer cancel()
+
+	_, err := c.do(ctx, "key", func(ctx context.Context) ([]dto.EmployeeResponse, error) {
+		if ctx.Value(key{}) != "span" {
+			t.Error("query context lost the caller's values")
+		}
+		if _, ok := ctx.Deadline(); ok {
+			t.Error("query context inherited the caller's deadline")
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("do error = %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+//go:build integration
+
+package user_service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/mocks"
+	service "github.com/levensspel/go-gin-template/service/employee"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// TestCountFirstPageUsesCachedTotal memastikan halaman pertama dari cache
+// Redis tidak menjalankan query Count untuk header Link, dan total dihitung
+// ulang setelah Invalidate.
+func TestCountFirstPageUsesCachedTotal(t *testing.T) {
+	var counted int
+	repo := &mocks.EmployeeRepository{
+		CountFunc: func(ctx context.Context, input *dto.GetEmployeesRequest) (int64, error) {
+			counted++
+			return 12, nil
+		},
+	}
+	appMetrics := metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{})
+	firstPage := newTestFirstPage(t, appMetrics)
+	s := service.NewEmployeeService(nil, repo, nil, nil, mocks.Logger{}, appMetrics, noop.NewTracerProvider().Tracer(""), nil, nil, firstPage)
+
+	ctx := context.Background()
+	input := dto.GetEmployeesRequest{
+		ManagerID: uuid.NewString(),
+		Limit:     dto.DefaultLimit,
+		Offset:    dto.DefaultOffset,
+		Status:    dto.EmployeeStatusActive,
+	}
+	t.Cleanup(func() { firstPage.Invalidate(ctx, input.ManagerID) })
+
+	for range 2 {
+		if total, err := s.Count(ctx, input); err != nil || total != 12 {
+			t.Fatalf("Count() = %d, %v, want 12", total, err)
+		}
+	}
+	if counted != 1 {
+		t.Fatalf("repository Count called %d times, want 1", counted)
+	}
+
+	filtered := input
+	filtered.Gender = "female"
+	if _, err := s.Count(ctx, filtered); err != nil {
+		t.Fatalf("Count() filtered error = %v", err)
+	}
+	if counted != 2 {
+		t.Fatalf("repository Count called %d times, want filtered counts uncached", counted)
+	}
+
+	firstPage.Invalidate(ctx, input.ManagerID)
+	if _, err := s.Count(ctx, input); err != nil {
+		t.Fatalf("Count() error = %v", err)
+	}
+	if counted != 3 {
+		t.Fatalf("repository Count called %d times, want a fresh count after Invalidate", counted)
+	}
+}
@@ -0,0 +1,162 @@
+package user_service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/repository"
+	"github.com/levensspel/go-gin-template/telemetry"
+	"github.com/levensspel/go-gin-template/validation"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// errDryRun dikembalikan fn transaksi dry run agar transaksinya selalu
+// di-rollback, bukan di-commit.
+var errDryRun = errors.New("dry run")
+
+func (s *service) DryRunCreate(ctx context.Context, input dto.EmployeePayload, managerId string) (fieldErrors validation.FieldErrors, err error) {
+	ctx, span := s.tracer.Start(ctx, "EmployeeService.DryRunCreate")
+	defer func() { telemetry.End(span, err) }()
+
+	// Create menolak identityNumber milik manager mana pun dengan error yang
+	// sama
+	results, err := s.dryRunCreate(ctx, []dto.EmployeePayload{input}, managerId, helper.ErrConflictIdentityNumber)
+	if err != nil {
+		s.metrics.CountError(helper.EmployeeServiceDryRunCreate, err)
+		s.logger.Error(err.Error(), helper.EmployeeServiceDryRunCreate, err)
+		return nil, err
+	}
+	return results[0], nil
+}
+
+func (s *service) DryRunCreateMany(ctx context.Context, inputs []dto.EmployeePayload, managerId string) (fieldErrors []validation.FieldErrors, err error) {
+	ctx, span := s.tracer.Start(ctx, "EmployeeService.DryRunCreateMany", trace.WithAttributes(
+		attribute.Int("employee.count", len(inputs)),
+	))
+	defer func() { telemetry.End(span, err) }()
+
+	// CreateMany membedakan identityNumber milik manager lain, lihat
+	// EmployeeRepository.CreateMany
+	fieldErrors, err = s.dryRunCreate(ctx, inputs, managerId, helper.ErrConflict)
+	if err != nil {
+		s.metrics.CountError(helper.EmployeeServiceDryRunCreateMany, err)
+		s.logger.Error(err.Error(), helper.EmployeeServiceDryRunCreateMany, err)
+		return nil, err
+	}
+	return fieldErrors, nil
+}
+
+// dryRunCreate menjalankan validasi dan pengecekan database Create untuk
+// setiap input, lalu mengumpulkan semua error per field dengan pesan yang
+// sama dengan response create yang gagal. Pengecekan database hanya
+// dijalankan untuk field yang lolos validasi. Query berjalan di transaksi
+// read-only yang selalu di-rollback, sehingga tidak ada yang tersimpan.
+// otherManagerErr adalah error untuk identityNumber milik manager lain.
+func (s *service) dryRunCreate(
+	ctx context.Context,
+	inputs []dto.EmployeePayload,
+	managerId string,
+	otherManagerErr error,
+) ([]validation.FieldErrors, error) {
+	results := make([]validation.FieldErrors, len(inputs))
+	for i := range inputs {
+		results[i] = validation.FieldErrors{}
+		if err := validation.ValidateEmployeeCreate(&inputs[i]); err != nil {
+			var fieldErrors validation.FieldErrors
+			if !errors.As(err, &fieldErrors) {
+				return nil, err
+			}
+			results[i] = fieldErrors
+		}
+	}
+	checked := func(i int, field string) bool {
+		_, failed := results[i][field]
+		return !failed
+	}
+	reject := func(i int, field string, err error) {
+		if checked(i, field) {
+			results[i][field] = helper.GetErrorMessage(err)
+		}
+	}
+
+	var uris []string
+	var uriIndexes []int
+	for i, input := range inputs {
+		if checked(i, "employeeImageUri") {
+			uris = append(uris, input.EmployeeImageUri)
+			uriIndexes = append(uriIndexes, i)
+		}
+	}
+	imageErrs, err := s.checkEmployeeImages(ctx, managerId, uris)
+	if err != nil {
+		return nil, helper.QueryError(ctx, err)
+	}
+	for j, imageErr := range imageErrs {
+		if imageErr != nil {
+			reject(uriIndexes[j], "employeeImageUri", imageErr)
+		}
+	}
+
+	identityNumbers := make([]string, 0, len(inputs))
+	departmentIds := make([]string, 0, len(inputs))
+	for i, input := range inputs {
+		if checked(i, "identityNumber") {
+			identityNumbers = append(identityNumbers, input.IdentityNumber)
+		}
+		if checked(i, "departmentId") {
+			departmentIds = append(departmentIds, input.DepartmentID)
+		}
+	}
+	err = s.uow.DoTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly}, func(repos repository.Repositories) error {
+		existing, err := repos.Employee.GetForUpsert(ctx, repos.Tx, identityNumbers, false)
+		if err != nil {
+			return err
+		}
+		departments, err := repos.Department.GetByIDs(ctx, departmentIds, managerId)
+		if err != nil {
+			return err
+		}
+		owned := make(map[string]bool, len(departments))
+		for _, department := range departments {
+			owned[department.Id] = true
+		}
+
+		// Input sebelumnya yang lolos semua pengecekan akan sudah di-insert
+		// CreateMany, sehingga identityNumber-nya dianggap terpakai
+		created := make(map[string]bool, len(inputs))
+		for i, input := range inputs {
+			if checked(i, "departmentId") && !owned[input.DepartmentID] {
+				reject(i, "departmentId", helper.ErrInvalidDepartmentId)
+			}
+			if checked(i, "identityNumber") {
+				current, found := existing[input.IdentityNumber]
+				switch {
+				case created[input.IdentityNumber]:
+					reject(i, "identityNumber", helper.ErrConflictIdentityNumber)
+				case found && current.ManagerID != managerId:
+					reject(i, "identityNumber", otherManagerErr)
+				case found:
+					reject(i, "identityNumber", helper.ErrConflictIdentityNumber)
+				}
+			}
+			if len(results[i]) == 0 {
+				created[input.IdentityNumber] = true
+			}
+		}
+		return errDryRun
+	})
+	if !errors.Is(err, errDryRun) {
+		return nil, helper.QueryError(ctx, err)
+	}
+
+	for i := range results {
+		if len(results[i]) == 0 {
+			results[i] = nil
+		}
+	}
+	return results, nil
+}
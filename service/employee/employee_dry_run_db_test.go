@@ -0,0 +1,201 @@
+//go:build integration
+
+package user_service_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/cache"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/mocks"
+	"github.com/levensspel/go-gin-template/repository"
+	repositories "github.com/levensspel/go-gin-template/repository/employee"
+	fileRepository "github.com/levensspel/go-gin-template/repository/file"
+	service "github.com/levensspel/go-gin-template/service/employee"
+	"github.com/levensspel/go-gin-template/validation"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// dryRunFixture menjalankan EmployeeService dengan repository dan unit of
+// work asli. department milik manager, foreign milik manager lain yang
+// sudah memiliki employee FOREIGN-1, deleted sudah dihapus.
+type dryRunFixture struct {
+	pool       *pgxpool.Pool
+	service    service.EmployeeService
+	manager    string
+	department string
+	foreign    string
+	deleted    string
+}
+
+func newDryRunFixture(t *testing.T) dryRunFixture {
+	t.Helper()
+	pool := dbtest.New(t)
+	timeouts := config.LoadQueryTimeoutConfig()
+	search := &config.SearchConfig{}
+	employees := repositories.NewEmployeeRepository(pool, pool, timeouts, search)
+	files := fileRepository.New(pool, timeouts)
+	appMetrics := metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{})
+	f := dryRunFixture{
+		pool: pool,
+		service: service.NewEmployeeService(
+			pool, &employees, &files, repository.NewUnitOfWork(pool, timeouts, search), mocks.Logger{}, appMetrics,
+			noop.NewTracerProvider().Tracer(""), &config.ImportConfig{}, &config.FileConfig{},
+			cache.NewEmployeeFirstPage(nil, 0, mocks.Logger{}, appMetrics),
+		),
+		manager: dbtest.CreateManager(t, pool, "dry-run@example.com"),
+	}
+	f.department = dbtest.CreateDepartment(t, pool, f.manager, "Engineering")
+	f.deleted = dbtest.CreateDepartment(t, pool, f.manager, "Deleted")
+	dbtest.DeleteDepartment(t, pool, f.deleted)
+	other := dbtest.CreateManager(t, pool, "other@example.com")
+	f.foreign = dbtest.CreateDepartment(t, pool, other, "Foreign")
+	dbtest.CreateEmployee(t, pool, dbtest.Employee{IdentityNumber: "TAKEN-1", Name: "Taken", Gender: "male", DepartmentID: f.department})
+	dbtest.CreateEmployee(t, pool, dbtest.Employee{IdentityNumber: "FOREIGN-1", Name: "Foreign", Gender: "male", DepartmentID: f.foreign})
+	return f
+}
+
+func (f dryRunFixture) payload(identityNumber, departmentId string) dto.EmployeePayload {
+	return dto.EmployeePayload{
+		IdentityNumber:   identityNumber,
+		Name:             "Employee " + identityNumber,
+		EmployeeImageUri: "https://example.com/image.png",
+		Gender:           "male",
+		DepartmentID:     departmentId,
+	}
+}
+
+// rowCounts menghitung baris setiap tabel yang ditulis Create.
+func (f dryRunFixture) rowCounts(t *testing.T) map[string]int {
+	t.Helper()
+	counts := map[string]int{}
+	for _, table := range []string{"employees", "employee_versions", "audit_log", "outbox"} {
+		var count int
+		if err := f.pool.QueryRow(context.Background(), "SELECT COUNT(*) FROM "+table+";").Scan(&count); err != nil {
+			t.Fatalf("count %s: %v", table, err)
+		}
+		counts[table] = count
+	}
+	return counts
+}
+
+func TestDryRunWritesNothing(t *testing.T) {
+	f := newDryRunFixture(t)
+	ctx := context.Background()
+	before := f.rowCounts(t)
+
+	fieldErrors, err := f.service.DryRunCreate(ctx, f.payload("NEW-00001", f.department), f.manager)
+	if err != nil || fieldErrors != nil {
+		t.Fatalf("DryRunCreate = %v, %v, want valid", fieldErrors, err)
+	}
+	results, err := f.service.DryRunCreateMany(ctx, []dto.EmployeePayload{
+		f.payload("NEW-00001", f.department),
+		f.payload("NEW-00002", f.department),
+	}, f.manager)
+	if err != nil || len(results) != 2 || results[0] != nil || results[1] != nil {
+		t.Fatalf("DryRunCreateMany = %v, %v, want both valid", results, err)
+	}
+
+	if after := f.rowCounts(t); !reflect.DeepEqual(after, before) {
+		t.Fatalf("rows after dry run = %v, want %v", after, before)
+	}
+
+	// Input yang sama benar-benar bisa dibuat setelah dry run
+	if _, err := f.service.Create(ctx, f.payload("NEW-00001", f.department), f.manager); err != nil {
+		t.Fatalf("Create after dry run error = %v", err)
+	}
+}
+
+// TestDryRunMatchesCreate memastikan error dry run sama dengan response
+// create yang gagal untuk input yang sama.
+func TestDryRunMatchesCreate(t *testing.T) {
+	f := newDryRunFixture(t)
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		name  string
+		input dto.EmployeePayload
+		field string
+		// want adalah error Create; nil berarti ditolak validasi
+		want error
+	}{
+		{name: "taken identity number", input: f.payload("TAKEN-1", f.department), field: "identityNumber", want: helper.ErrConflictIdentityNumber},
+		{name: "identity number of another manager", input: f.payload("FOREIGN-1", f.department), field: "identityNumber", want: helper.ErrConflictIdentityNumber},
+		{name: "department of another manager", input: f.payload("NEW-00001", f.foreign), field: "departmentId", want: helper.ErrInvalidDepartmentId},
+		{name: "deleted department", input: f.payload("NEW-00001", f.deleted), field: "departmentId", want: helper.ErrInvalidDepartmentId},
+		{name: "invalid gender", input: func() dto.EmployeePayload { p := f.payload("NEW-00001", f.department); p.Gender = "other"; return p }(), field: "gender"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fieldErrors, err := f.service.DryRunCreate(ctx, tt.input, f.manager)
+			if err != nil {
+				t.Fatalf("DryRunCreate error = %v", err)
+			}
+
+			want := validation.FieldErrors{}
+			if tt.want == nil {
+				input := tt.input
+				if !errors.As(validation.ValidateEmployeeCreate(&input), &want) {
+					t.Fatal("ValidateEmployeeCreate accepted the input")
+				}
+			} else {
+				_, createErr := f.service.Create(ctx, tt.input, f.manager)
+				if !errors.Is(createErr, tt.want) {
+					t.Fatalf("Create error = %v, want %v", createErr, tt.want)
+				}
+				want[tt.field] = helper.GetErrorMessage(createErr)
+			}
+			if !reflect.DeepEqual(fieldErrors, want) {
+				t.Fatalf("DryRunCreate = %v, want %v", fieldErrors, want)
+			}
+		})
+	}
+}
+
+// TestDryRunManyMatchesCreateMany membandingkan error per item dengan
+// CreateMany, termasuk identityNumber yang dipakai dua kali dalam satu
+// batch.
+func TestDryRunManyMatchesCreateMany(t *testing.T) {
+	f := newDryRunFixture(t)
+	ctx := context.Background()
+	inputs := []dto.EmployeePayload{
+		f.payload("NEW-00001", f.department),
+		f.payload("TAKEN-1", f.department),
+		f.payload("FOREIGN-1", f.department),
+		f.payload("NEW-00002", f.foreign),
+		f.payload("NEW-00001", f.department),
+	}
+	fields := []string{"", "identityNumber", "identityNumber", "departmentId", "identityNumber"}
+
+	results, err := f.service.DryRunCreateMany(ctx, inputs, f.manager)
+	if err != nil {
+		t.Fatalf("DryRunCreateMany error = %v", err)
+	}
+	errs, err := f.service.CreateMany(ctx, inputs, f.manager)
+	if err != nil {
+		t.Fatalf("CreateMany error = %v", err)
+	}
+	for i, field := range fields {
+		if field == "" {
+			if results[i] != nil || errs[i] != nil {
+				t.Fatalf("item %d: dry run = %v, create = %v, want both valid", i, results[i], errs[i])
+			}
+			continue
+		}
+		if errs[i] == nil {
+			t.Fatalf("item %d: CreateMany succeeded, dry run = %v", i, results[i])
+		}
+		want := validation.FieldErrors{field: helper.GetErrorMessage(errs[i])}
+		if !reflect.DeepEqual(results[i], want) {
+			t.Fatalf("item %d: dry run = %v, want %v", i, results[i], want)
+		}
+	}
+}
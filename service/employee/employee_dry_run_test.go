@@ -0,0 +1,126 @@
+package user_service_test
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/mocks"
+	repositories "github.com/levensspel/go-gin-template/repository/employee"
+	"github.com/levensspel/go-gin-template/validation"
+)
+
+// dryRunRepositories mengisi fixture dengan EMP-TAKEN milik manager,
+// EMP-OTHER milik manager lain, dan testDeptID sebagai satu-satunya
+// department manager.
+func dryRunRepositories(f *employeeFixture) {
+	f.employee.GetForUpsertFunc = func(ctx context.Context, pool database.Querier, identityNumbers []string, lock bool) (map[string]repositories.ExistingEmployee, error) {
+		found := map[string]repositories.ExistingEmployee{}
+		for _, identityNumber := range identityNumbers {
+			switch identityNumber {
+			case "EMP-TAKEN":
+				found[identityNumber] = repositories.ExistingEmployee{ManagerID: testManagerID}
+			case "EMP-OTHER":
+				found[identityNumber] = repositories.ExistingEmployee{ManagerID: "other"}
+			}
+		}
+		return found, nil
+	}
+	f.uow.Repositories.Department = &mocks.DepartmentRepository{
+		GetByIDsFunc: func(ctx context.Context, deptIDs []string, managerID string) ([]entity.Department, error) {
+			if slices.Contains(deptIDs, testDeptID) {
+				return []entity.Department{{Id: testDeptID}}, nil
+			}
+			return nil, nil
+		},
+	}
+}
+
+func TestDryRunCreate(t *testing.T) {
+	const foreignDept = "2d9e4f40-6b6c-4d0a-9e7b-4b3f0b8c5a32"
+	for _, tt := range []struct {
+		name  string
+		input func(p *dto.EmployeePayload)
+		// want berisi pesan per field; "" berarti pesan validator apa pun
+		want validation.FieldErrors
+	}{
+		{name: "valid", input: func(p *dto.EmployeePayload) {}},
+		{name: "taken", input: func(p *dto.EmployeePayload) { p.IdentityNumber = "EMP-TAKEN" }, want: validation.FieldErrors{
+			"identityNumber": helper.GetErrorMessage(helper.ErrConflictIdentityNumber),
+		}},
+		// Create tidak membedakan manager pemilik identityNumber
+		{name: "taken by another manager", input: func(p *dto.EmployeePayload) { p.IdentityNumber = "EMP-OTHER" }, want: validation.FieldErrors{
+			"identityNumber": helper.GetErrorMessage(helper.ErrConflictIdentityNumber),
+		}},
+		{name: "every failing field", input: func(p *dto.EmployeePayload) {
+			p.IdentityNumber = "EMP-TAKEN"
+			p.DepartmentID = foreignDept
+			p.Gender = "other"
+		}, want: validation.FieldErrors{
+			"identityNumber": helper.GetErrorMessage(helper.ErrConflictIdentityNumber),
+			"departmentId":   helper.GetErrorMessage(helper.ErrInvalidDepartmentId),
+			"gender":         "",
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newEmployeeFixture()
+			dryRunRepositories(f)
+			input := createPayload("EMP-1")
+			tt.input(&input)
+
+			fieldErrors, err := f.service().DryRunCreate(context.Background(), input, testManagerID)
+			if err != nil {
+				t.Fatalf("DryRunCreate error = %v", err)
+			}
+			if len(fieldErrors) != len(tt.want) || (len(tt.want) == 0 && fieldErrors != nil) {
+				t.Fatalf("DryRunCreate = %#v, want %#v", fieldErrors, tt.want)
+			}
+			for field, message := range tt.want {
+				if got := fieldErrors[field]; got == "" || (message != "" && got != message) {
+					t.Fatalf("DryRunCreate = %#v, want %#v", fieldErrors, tt.want)
+				}
+			}
+			if f.uow.Committed || f.uow.TxOptions.AccessMode != pgx.ReadOnly {
+				t.Fatalf("committed = %t, access mode = %q, want a rolled back read-only transaction", f.uow.Committed, f.uow.TxOptions.AccessMode)
+			}
+			if len(f.audits) != 0 || len(f.versions) != 0 || len(f.events) != 0 {
+				t.Fatalf("audits = %d, versions = %v, events = %v, want no writes", len(f.audits), f.versions, f.events)
+			}
+		})
+	}
+}
+
+func TestDryRunCreateMany(t *testing.T) {
+	f := newEmployeeFixture()
+	dryRunRepositories(f)
+	inputs := []dto.EmployeePayload{createPayload("EMP-1"), createPayload("EMP-OTHER"), createPayload("EMP-1"), createPayload("EMP-2")}
+	inputs[3].Gender = "other"
+
+	results, err := f.service().DryRunCreateMany(context.Background(), inputs, testManagerID)
+	if err != nil {
+		t.Fatalf("DryRunCreateMany error = %v", err)
+	}
+	if len(results) != 4 || results[0] != nil {
+		t.Fatalf("DryRunCreateMany = %v, want the first item valid", results)
+	}
+	// CreateMany memakai ErrConflict untuk identityNumber milik manager lain,
+	// dan item yang sudah lolos sebelumnya memakai identityNumber-nya
+	if !reflect.DeepEqual(results[1], validation.FieldErrors{"identityNumber": helper.GetErrorMessage(helper.ErrConflict)}) {
+		t.Fatalf("item 1 = %v", results[1])
+	}
+	if !reflect.DeepEqual(results[2], validation.FieldErrors{"identityNumber": helper.GetErrorMessage(helper.ErrConflictIdentityNumber)}) {
+		t.Fatalf("item 2 = %v", results[2])
+	}
+	if len(results[3]) != 1 || results[3]["gender"] == "" {
+		t.Fatalf("item 3 = %v, want only a gender error", results[3])
+	}
+	if f.uow.Committed || f.uow.TxOptions.AccessMode != pgx.ReadOnly {
+		t.Fatalf("committed = %t, access mode = %q", f.uow.Committed, f.uow.TxOptions.AccessMode)
+	}
+}
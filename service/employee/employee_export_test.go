@@ -0,0 +1,70 @@
+package user_service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestExport(t *testing.T) {
+	errWrite := errors.New("broken pipe")
+	errQuery := errors.New("connection reset")
+	for _, tt := range []struct {
+		name string
+		// fnErr dikembalikan callback handler pada row kedua
+		fnErr     error
+		repoErr   error
+		cancel    bool
+		wantErr   error
+		wantRows  int
+		wantCount float64
+	}{
+		{name: "all rows", wantRows: 3},
+		{name: "callback error", fnErr: errWrite, wantErr: errWrite, wantRows: 2},
+		{name: "repository error", repoErr: errQuery, wantErr: errQuery, wantRows: 3, wantCount: 1},
+		{name: "client disconnected", cancel: true, repoErr: context.Canceled, wantErr: context.Canceled, wantRows: 3},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newEmployeeFixture()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			var gotInput *dto.GetEmployeesRequest
+			f.employee.ExportFunc = func(ctx context.Context, input *dto.GetEmployeesRequest, fn func(dto.EmployeeResponse) error) error {
+				gotInput = input
+				for _, id := range []string{"EMP-1", "EMP-2", "EMP-3"} {
+					if err := fn(dto.EmployeeResponse{EmployeePayload: createPayload(id)}); err != nil {
+						return err
+					}
+				}
+				if tt.cancel {
+					cancel()
+				}
+				return tt.repoErr
+			}
+
+			rows := 0
+			err := f.service().Export(ctx, dto.GetEmployeesRequest{ManagerID: testManagerID}, func(employee dto.EmployeeResponse) error {
+				rows++
+				if rows == 2 {
+					return tt.fnErr
+				}
+				return nil
+			})
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("Export error = %v, want %v", err, tt.wantErr)
+			}
+			if rows != tt.wantRows || gotInput == nil || gotInput.ManagerID != testManagerID {
+				t.Fatalf("rows = %d, input = %+v", rows, gotInput)
+			}
+			// Hanya error repository yang dihitung sebagai error server
+			key := helper.GetErrorKey(tt.wantErr)
+			if got := testutil.ToFloat64(f.metrics.ErrorsReturned.WithLabelValues(string(helper.EmployeeServiceExport), key)); got != tt.wantCount {
+				t.Fatalf("errors returned = %v, want %v", got, tt.wantCount)
+			}
+		})
+	}
+}
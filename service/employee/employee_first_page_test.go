@@ -0,0 +1,131 @@
+//go:build integration
+
+package user_service_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/levensspel/go-gin-template/cache"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/mocks"
+	service "github.com/levensspel/go-gin-template/service/employee"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestFirstPage memakai Redis dari TEST_REDIS_URL, mis.
+//
+//	TEST_REDIS_URL=redis://localhost:6379/15 go test -tags integration ./service/employee/
+func newTestFirstPage(t *testing.T, appMetrics *metrics.Metrics) *cache.EmployeeFirstPage {
+	t.Helper()
+	redisURL := os.Getenv("TEST_REDIS_URL")
+	if redisURL == "" {
+		t.Skip("TEST_REDIS_URL is not set")
+	}
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		t.Fatalf("parse TEST_REDIS_URL: %v", err)
+	}
+	client := redis.NewClient(opts)
+	t.Cleanup(func() { client.Close() })
+	return cache.NewEmployeeFirstPage(client, time.Minute, mocks.Logger{}, appMetrics)
+}
+
+// TestWritesInvalidateFirstPage mengisi cache halaman pertama manager lalu
+// menjalankan setiap write employee. Write yang berhasil harus menghapus
+// halaman dan totalnya; write yang gagal tidak mengubah apa pun sehingga
+// cache tetap dipakai.
+func TestWritesInvalidateFirstPage(t *testing.T) {
+	errWrite := errors.New("write failed")
+	stored := dto.EmployeeResponse{EmployeePayload: createPayload("EMP-1"), Status: dto.EmployeeStatusActive}
+	name := "Budi Santoso"
+
+	tests := []struct {
+		name  string
+		write func(ctx context.Context, s service.EmployeeService, managerId string) error
+		// fail membuat write di repository gagal
+		fail func(f *employeeFixture)
+		// wantHit berarti cache masih berisi halaman lama setelah write
+		wantHit bool
+	}{
+		{name: "create", write: func(ctx context.Context, s service.EmployeeService, managerId string) error {
+			_, err := s.Create(ctx, createPayload("EMP-2"), managerId)
+			return err
+		}},
+		{name: "update", write: func(ctx context.Context, s service.EmployeeService, managerId string) error {
+			_, err := s.Update(ctx, "EMP-1", dto.EmployeeUpdatePayload{Name: &name}, managerId)
+			return err
+		}},
+		{name: "archive", write: func(ctx context.Context, s service.EmployeeService, managerId string) error {
+			_, err := s.Archive(ctx, "EMP-1", managerId)
+			return err
+		}},
+		{name: "delete", write: func(ctx context.Context, s service.EmployeeService, managerId string) error {
+			_, err := s.Delete(ctx, "EMP-1", managerId)
+			return err
+		}},
+		{
+			name: "failed create",
+			write: func(ctx context.Context, s service.EmployeeService, managerId string) error {
+				_, err := s.Create(ctx, createPayload("EMP-2"), managerId)
+				return err
+			},
+			fail: func(f *employeeFixture) {
+				f.employee.CreateFunc = func(ctx context.Context, input *dto.EmployeePayload, managerId string) (dto.EmployeeResponse, error) {
+					return dto.EmployeeResponse{}, errWrite
+				}
+			},
+			wantHit: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newEmployeeFixture()
+			f.firstPage = newTestFirstPage(t, f.metrics)
+			f.employee.CreateFunc = func(ctx context.Context, input *dto.EmployeePayload, managerId string) (dto.EmployeeResponse, error) {
+				return dto.EmployeeResponse{EmployeePayload: *input, Status: dto.EmployeeStatusActive}, nil
+			}
+			f.employee.GetForUpdateFunc = func(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error) {
+				return stored, nil
+			}
+			f.employee.UpdateFunc = func(ctx context.Context, identityNumber string, input *dto.EmployeeUpdatePayload, managerId string) (dto.EmployeeResponse, error) {
+				updated := stored
+				updated.Name = *input.Name
+				return updated, nil
+			}
+			f.employee.SetStatusFunc = func(ctx context.Context, identityNumber string, status string, managerId string) (dto.EmployeeResponse, error) {
+				updated := stored
+				updated.Status = status
+				return updated, nil
+			}
+			f.employee.DeleteFunc = func(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error) {
+				return stored, nil
+			}
+			if tt.fail != nil {
+				tt.fail(f)
+			}
+
+			ctx := context.Background()
+			managerId := uuid.NewString()
+			t.Cleanup(func() { f.firstPage.Invalidate(ctx, managerId) })
+			f.firstPage.Set(ctx, managerId, []dto.EmployeeResponse{stored})
+			f.firstPage.SetTotal(ctx, managerId, 1)
+
+			err := tt.write(ctx, f.service(), managerId)
+			if (err != nil) != (tt.fail != nil) {
+				t.Fatalf("%s error = %v", tt.name, err)
+			}
+
+			_, pageHit := f.firstPage.Get(ctx, managerId)
+			_, totalHit := f.firstPage.GetTotal(ctx, managerId)
+			if pageHit != tt.wantHit || totalHit != tt.wantHit {
+				t.Fatalf("cached page = %t, total = %t after %s, want %t", pageHit, totalHit, tt.name, tt.wantHit)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package user_service
+
+import (
+	"context"
+
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/repository"
+	"github.com/levensspel/go-gin-template/telemetry"
+	"github.com/levensspel/go-gin-template/validation"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func (s *service) History(ctx context.Context, input dto.GetEmployeeHistoryRequest) (versions []dto.EmployeeVersion, err error) {
+	ctx, span := s.tracer.Start(ctx, "EmployeeService.History")
+	defer func() { telemetry.End(span, err) }()
+
+	versions, err = s.employeeRepo.GetVersions(ctx, &input)
+	if err != nil {
+		s.metrics.CountError(helper.EmployeeServiceHistory, err)
+		s.logger.Error(err.Error(), helper.EmployeeServiceHistory, input)
+		return nil, err
+	}
+	if versions == nil {
+		versions = []dto.EmployeeVersion{}
+	}
+	return versions, nil
+}
+
+// RestoreVersion memvalidasi isi versi dengan rule PATCH saat ini, karena
+// rule bisa berubah setelah versi disimpan. Ownership, identity number yang
+// sudah dipakai, dan department baru dicek Update seperti biasa.
+func (s *service) RestoreVersion(ctx context.Context, identityNumber string, version int, managerId string) (employee dto.EmployeeResponse, err error) {
+	ctx, span := s.tracer.Start(ctx, "EmployeeService.RestoreVersion", trace.WithAttributes(
+		attribute.Int("employee.version", version),
+	))
+	defer func() { telemetry.End(span, err) }()
+
+	defer func() {
+		s.metrics.CountError(helper.EmployeeServiceRestoreVersion, err)
+	}()
+
+	// Versi tidak pernah diubah, jadi boleh dibaca di luar transaksi
+	snapshot, err := s.employeeRepo.GetVersion(ctx, identityNumber, version, managerId)
+	if err != nil {
+		s.logger.Error(err.Error(), helper.EmployeeServiceRestoreVersion, err)
+		return dto.EmployeeResponse{}, employeeWriteError(helper.QueryError(ctx, err))
+	}
+	input := versionUpdatePayload(snapshot)
+	if err := validation.ValidateEmployeePatch(&input); err != nil {
+		return dto.EmployeeResponse{}, err
+	}
+	if input.EmployeeImageUri != nil {
+		if err := s.checkEmployeeImage(ctx, managerId, *input.EmployeeImageUri); err != nil {
+			return dto.EmployeeResponse{}, employeeWriteError(helper.QueryError(ctx, err))
+		}
+	}
+
+	err = s.uow.Do(ctx, func(repos repository.Repositories) (err error) {
+		employee, err = s.update(ctx, repos, identityNumber, &input, managerId)
+		return err
+	})
+	if err != nil {
+		s.logger.Error(err.Error(), helper.EmployeeServiceRestoreVersion, err)
+		return dto.EmployeeResponse{}, employeeWriteError(helper.QueryError(ctx, err))
+	}
+	s.firstPage.Invalidate(ctx, managerId)
+	s.metrics.CountEmployeeUpdated(managerId)
+
+	return employee, nil
+}
+
+// versionUpdatePayload mengubah versi menjadi PATCH yang mengganti semua
+// field. Gambar dan hiredAt yang kosong di versi dikirim sebagai null.
+// Status tidak ikut dipulihkan, lihat Archive dan Restore.
+func versionUpdatePayload(version dto.EmployeeVersion) dto.EmployeeUpdatePayload {
+	input := dto.EmployeeUpdatePayload{
+		IdentityNumber: &version.IdentityNumber,
+		Name:           &version.Name,
+		Gender:         &version.Gender,
+		DepartmentID:   &version.DepartmentID,
+		HiredAt:        version.HiredAt,
+	}
+	if version.EmployeeImageUri != "" {
+		input.EmployeeImageUri = &version.EmployeeImageUri
+	} else {
+		input.Nulls = append(input.Nulls, "employeeImageUri")
+	}
+	if version.HiredAt == nil {
+		input.Nulls = append(input.Nulls, "hiredAt")
+	}
+	return input
+}
@@ -0,0 +1,143 @@
+//go:build integration
+
+package user_service_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+// history mengembalikan semua versi employee identityNumber, terbaru lebih
+// dulu.
+func (f dryRunFixture) history(t *testing.T, identityNumber string) []dto.EmployeeVersion {
+	t.Helper()
+	versions, err := f.service.History(context.Background(), dto.GetEmployeeHistoryRequest{
+		IdentityNumber: identityNumber,
+		ManagerID:      f.manager,
+		Limit:          100,
+	})
+	if err != nil {
+		t.Fatalf("History(%s) error = %v", identityNumber, err)
+	}
+	return versions
+}
+
+func versionActions(versions []dto.EmployeeVersion) []string {
+	actions := make([]string, len(versions))
+	for i, version := range versions {
+		actions[i] = version.Action
+	}
+	return actions
+}
+
+// TestHistoryWrittenWithUpdate memastikan create dan update menambah versi,
+// dan update yang gagal tidak menambah versi apa pun.
+func TestHistoryWrittenWithUpdate(t *testing.T) {
+	f := newDryRunFixture(t)
+	ctx := context.Background()
+
+	if _, err := f.service.Create(ctx, f.payload("EMP-1", f.department), f.manager); err != nil {
+		t.Fatalf("Create error = %v", err)
+	}
+	name := "Budi Santoso"
+	if _, err := f.service.Update(ctx, "EMP-1", dto.EmployeeUpdatePayload{Name: &name}, f.manager); err != nil {
+		t.Fatalf("Update error = %v", err)
+	}
+
+	versions := f.history(t, "EMP-1")
+	if got, want := versionActions(versions), []string{"update", "create"}; !slices.Equal(got, want) {
+		t.Fatalf("actions = %v, want %v", got, want)
+	}
+	if versions[0].Version != 2 || versions[0].Name != name || versions[1].Name != "Employee EMP-1" {
+		t.Fatalf("versions = %+v, want version 2 with the new name over version 1", versions)
+	}
+
+	// Identity number yang sudah dipakai ditolak, tanpa versi baru
+	taken := "TAKEN-1"
+	_, err := f.service.Update(ctx, "EMP-1", dto.EmployeeUpdatePayload{IdentityNumber: &taken}, f.manager)
+	if !errors.Is(err, helper.ErrConflictIdentityNumber) {
+		t.Fatalf("Update to TAKEN-1 error = %v, want %v", err, helper.ErrConflictIdentityNumber)
+	}
+	if after := f.history(t, "EMP-1"); !reflect.DeepEqual(after, versions) {
+		t.Fatalf("versions after failed update = %+v, want %+v", after, versions)
+	}
+}
+
+// TestHistoryRollsBackWithUpdate membuat insert versi gagal dan memastikan
+// perubahan employee, audit log, dan outbox ikut dibatalkan, artinya versi
+// ditulis di transaksi yang sama dengan update.
+func TestHistoryRollsBackWithUpdate(t *testing.T) {
+	f := newDryRunFixture(t)
+	ctx := context.Background()
+
+	if _, err := f.service.Create(ctx, f.payload("EMP-1", f.department), f.manager); err != nil {
+		t.Fatalf("Create error = %v", err)
+	}
+	// Setiap test memakai database sendiri, jadi constraint ini tidak
+	// memengaruhi test lain
+	_, err := f.pool.Exec(ctx, "ALTER TABLE employee_versions ADD CONSTRAINT test_reject_update CHECK (action <> 'update');")
+	if err != nil {
+		t.Fatalf("add constraint: %v", err)
+	}
+	before := f.rowCounts(t)
+
+	name := "Budi Santoso"
+	if _, err := f.service.Update(ctx, "EMP-1", dto.EmployeeUpdatePayload{Name: &name}, f.manager); err == nil {
+		t.Fatal("Update succeeded, want the version insert to fail")
+	}
+
+	if after := f.rowCounts(t); !reflect.DeepEqual(after, before) {
+		t.Fatalf("rows after failed update = %v, want %v", after, before)
+	}
+	var stored string
+	if err := f.pool.QueryRow(ctx, "SELECT name FROM employees WHERE identityNumber = 'EMP-1';").Scan(&stored); err != nil {
+		t.Fatalf("read employee: %v", err)
+	}
+	if stored != "Employee EMP-1" {
+		t.Fatalf("stored name = %q, want the update rolled back", stored)
+	}
+}
+
+// TestRestoreVersionAddsVersion memastikan restore menerapkan isi versi
+// lama sebagai versi baru dan versi sebelumnya tidak berubah.
+func TestRestoreVersionAddsVersion(t *testing.T) {
+	f := newDryRunFixture(t)
+	ctx := context.Background()
+
+	if _, err := f.service.Create(ctx, f.payload("EMP-1", f.department), f.manager); err != nil {
+		t.Fatalf("Create error = %v", err)
+	}
+	name := "Budi Santoso"
+	identityNumber := "EMP-2"
+	_, err := f.service.Update(ctx, "EMP-1", dto.EmployeeUpdatePayload{Name: &name, IdentityNumber: &identityNumber}, f.manager)
+	if err != nil {
+		t.Fatalf("Update error = %v", err)
+	}
+	before := f.history(t, "EMP-2")
+
+	restored, err := f.service.RestoreVersion(ctx, "EMP-2", 1, f.manager)
+	if err != nil {
+		t.Fatalf("RestoreVersion error = %v", err)
+	}
+	if restored.IdentityNumber != "EMP-1" || restored.Name != "Employee EMP-1" {
+		t.Fatalf("restored = %+v, want the fields of version 1", restored)
+	}
+
+	versions := f.history(t, "EMP-1")
+	if got, want := versionActions(versions), []string{"update", "update", "create"}; !slices.Equal(got, want) {
+		t.Fatalf("actions = %v, want %v", got, want)
+	}
+	latest := versions[0]
+	if latest.Version != 3 || !latest.EmployeePayload.Equal(before[1].EmployeePayload) {
+		t.Fatalf("latest version = %+v, want version 3 equal to version 1 %+v", latest, before[1])
+	}
+	if !reflect.DeepEqual(versions[1:], before) {
+		t.Fatalf("older versions = %+v, want unchanged %+v", versions[1:], before)
+	}
+}
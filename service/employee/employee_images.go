@@ -0,0 +1,135 @@
+package user_service
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/repository"
+)
+
+// expandImages mengisi EmployeeImage dengan thumbnail file hasil upload
+// yang dirujuk employeeImageUri. Hasilnya slice baru, karena employees bisa
+// berasal dari cache atau coalescing yang dipakai bersama. Jika file gagal
+// dibaca, employeeImage tetap berisi gambar asli.
+func (s *service) expandImages(ctx context.Context, employees []dto.EmployeeResponse) []dto.EmployeeResponse {
+	if len(employees) == 0 {
+		return employees
+	}
+
+	uris := make([]string, 0, len(employees))
+	for _, employee := range employees {
+		uris = append(uris, employee.EmployeeImageUri)
+	}
+	files, err := s.fileRepo.FindByURIs(ctx, uris)
+	if err != nil {
+		s.logger.Warn(err.Error(), helper.EmployeeServiceGet, "expand images")
+	}
+	byURI := make(map[string]entity.File, len(files))
+	for _, file := range files {
+		byURI[file.FileURI] = file
+	}
+
+	expanded := make([]dto.EmployeeResponse, len(employees))
+	for i, employee := range employees {
+		image := dto.EmployeeImage{
+			Uri:         employee.EmployeeImageUri,
+			OriginalUri: employee.EmployeeImageUri,
+		}
+		if file, ok := byURI[employee.EmployeeImageUri]; ok {
+			image.ThumbnailStatus = file.ThumbnailStatus
+			if file.ThumbnailStatus == entity.ThumbnailReady && file.ThumbnailURI != "" {
+				image.ThumbnailUri = file.ThumbnailURI
+				image.Uri = file.ThumbnailURI
+			}
+		}
+		employee.EmployeeImage = &image
+		expanded[i] = employee
+	}
+	return expanded
+}
+
+// checkEmployeeImages mengembalikan helper.ErrInvalidEmployeeImage di posisi
+// setiap uri yang bukan gambar employee hasil upload milik manager, jika
+// FILE_RESTRICT_EMPLOYEE_IMAGES aktif. Gambar profil manager tidak bisa
+// dipakai sebagai gambar employee. Bentuk uri sudah diperiksa
+// validation.ValidateEmployeeCreate; di sini file dicari dengan uri yang
+// persis sama dengan yang dibuat saat upload, sehingga uri dengan host lain
+// tetap ditolak. File manager lain dan file yang tidak ada tidak dibedakan.
+func (s *service) checkEmployeeImages(ctx context.Context, managerId string, uris []string) ([]error, error) {
+	errs := make([]error, len(uris))
+	if !s.fileConfig.RestrictEmployeeImages || len(uris) == 0 {
+		return errs, nil
+	}
+
+	files, err := s.fileRepo.FindByURIs(ctx, uris)
+	if err != nil {
+		return nil, err
+	}
+	owned := make(map[string]bool, len(files))
+	for _, file := range files {
+		if file.ManagerId == managerId && file.Purpose == entity.FilePurposeEmployee {
+			owned[file.FileURI] = true
+		}
+	}
+	for i, uri := range uris {
+		if !owned[uri] {
+			errs[i] = helper.ErrInvalidEmployeeImage
+		}
+	}
+	return errs, nil
+}
+
+func (s *service) checkEmployeeImage(ctx context.Context, managerId string, uri string) error {
+	errs, err := s.checkEmployeeImages(ctx, managerId, []string{uri})
+	if err != nil {
+		return err
+	}
+	return errs[0]
+}
+
+// employeeImageChecker mengembalikan checkEmployeeImage dengan cache per uri
+// untuk import, yang sering memakai gambar yang sama di banyak baris, atau
+// nil jika FILE_RESTRICT_EMPLOYEE_IMAGES tidak aktif. Error database tidak
+// di-cache.
+func (s *service) employeeImageChecker(ctx context.Context, managerId string) func(uri string) error {
+	if !s.fileConfig.RestrictEmployeeImages {
+		return nil
+	}
+	checked := make(map[string]error)
+	return func(uri string) error {
+		if err, ok := checked[uri]; ok {
+			return err
+		}
+		err := s.checkEmployeeImage(ctx, managerId, uri)
+		if err == nil || errors.Is(err, helper.ErrInvalidEmployeeImage) {
+			checked[uri] = err
+		}
+		return err
+	}
+}
+
+// referenceEmployeeImages mencatat rujukan employee ke file di transaksi
+// perubahan employee, sehingga file yang dipakai tidak dihapus
+// purge.Cleaner. Jika FILE_RESTRICT_EMPLOYEE_IMAGES aktif, file di added
+// yang sudah dihapus sejak checkEmployeeImages ditolak dengan
+// helper.ErrInvalidEmployeeImage.
+func (s *service) referenceEmployeeImages(ctx context.Context, repos repository.Repositories, added []string, removed []string) error {
+	matched, err := repos.File.AdjustReferences(ctx, repos.Tx, added, removed)
+	if err != nil {
+		return err
+	}
+	if !s.fileConfig.RestrictEmployeeImages {
+		return nil
+	}
+	for _, uri := range added {
+		// uri kosong berarti gambar dihapus, sama seperti di AdjustReferences
+		if uri != "" && !slices.Contains(matched, uri) {
+			return helper.ErrInvalidEmployeeImage
+		}
+	}
+	return nil
+}
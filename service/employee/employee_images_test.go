@@ -0,0 +1,358 @@
+package user_service_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+func imageRows(uris ...string) []dto.EmployeeResponse {
+	rows := make([]dto.EmployeeResponse, 0, len(uris))
+	for i, uri := range uris {
+		rows = append(rows, dto.EmployeeResponse{EmployeePayload: dto.EmployeePayload{
+			IdentityNumber:   string(rune('A' + i)),
+			EmployeeImageUri: uri,
+		}})
+	}
+	return rows
+}
+
+func TestGetAllExpandImages(t *testing.T) {
+	f := newEmployeeFixture()
+	rows := imageRows(
+		"https://api.example.com/files/ready",
+		"https://api.example.com/files/pending",
+		"https://api.example.com/files/failed",
+		"https://example.com/external.png",
+	)
+	f.employee.GetAllFunc = func(ctx context.Context, input *dto.GetEmployeesRequest) ([]dto.EmployeeResponse, error) {
+		return rows, nil
+	}
+	f.file.FindByURIsFunc = func(ctx context.Context, uris []string) ([]entity.File, error) {
+		if len(uris) != len(rows) {
+			t.Errorf("FindByURIs(%v), want every employeeImageUri", uris)
+		}
+		return []entity.File{
+			{FileURI: "https://api.example.com/files/ready", ThumbnailStatus: entity.ThumbnailReady, ThumbnailURI: "https://api.example.com/files/ready/thumbnail"},
+			{FileURI: "https://api.example.com/files/pending", ThumbnailStatus: entity.ThumbnailPending},
+			{FileURI: "https://api.example.com/files/failed", ThumbnailStatus: entity.ThumbnailFailed},
+		}, nil
+	}
+
+	input := listRequest()
+	input.Expand = dto.ExpandImages
+	employees, err := f.service().GetAll(context.Background(), input)
+	if err != nil {
+		t.Fatalf("GetAll() error = %v", err)
+	}
+	want := []dto.EmployeeImage{
+		{Uri: "https://api.example.com/files/ready/thumbnail", OriginalUri: rows[0].EmployeeImageUri, ThumbnailUri: "https://api.example.com/files/ready/thumbnail", ThumbnailStatus: entity.ThumbnailReady},
+		{Uri: rows[1].EmployeeImageUri, OriginalUri: rows[1].EmployeeImageUri, ThumbnailStatus: entity.ThumbnailPending},
+		{Uri: rows[2].EmployeeImageUri, OriginalUri: rows[2].EmployeeImageUri, ThumbnailStatus: entity.ThumbnailFailed},
+		{Uri: rows[3].EmployeeImageUri, OriginalUri: rows[3].EmployeeImageUri},
+	}
+	if len(employees) != len(want) {
+		t.Fatalf("GetAll() = %+v", employees)
+	}
+	for i, employee := range employees {
+		if employee.EmployeeImage == nil || *employee.EmployeeImage != want[i] {
+			t.Errorf("employees[%d].EmployeeImage = %+v, want %+v", i, employee.EmployeeImage, want[i])
+		}
+		// employeeImageUri tetap gambar asli
+		if employee.EmployeeImageUri != rows[i].EmployeeImageUri {
+			t.Errorf("employees[%d].EmployeeImageUri = %s", i, employee.EmployeeImageUri)
+		}
+	}
+	// Slice repository, yang bisa berasal dari cache, tidak diubah
+	for _, row := range rows {
+		if row.EmployeeImage != nil {
+			t.Fatal("expand changed the repository rows")
+		}
+	}
+}
+
+func TestGetAllExpandImagesFallsBackToOriginal(t *testing.T) {
+	f := newEmployeeFixture()
+	rows := imageRows("https://api.example.com/files/ready")
+	f.employee.GetAllFunc = func(ctx context.Context, input *dto.GetEmployeesRequest) ([]dto.EmployeeResponse, error) {
+		return rows, nil
+	}
+	f.file.FindByURIsFunc = func(ctx context.Context, uris []string) ([]entity.File, error) {
+		return nil, errors.New("file lookup failed")
+	}
+
+	input := listRequest()
+	input.Expand = dto.ExpandImages
+	employees, err := f.service().GetAll(context.Background(), input)
+	if err != nil {
+		t.Fatalf("GetAll() error = %v, want the list without thumbnails", err)
+	}
+	want := dto.EmployeeImage{Uri: rows[0].EmployeeImageUri, OriginalUri: rows[0].EmployeeImageUri}
+	if len(employees) != 1 || employees[0].EmployeeImage == nil || *employees[0].EmployeeImage != want {
+		t.Fatalf("GetAll() = %+v, want employeeImage %+v", employees, want)
+	}
+}
+
+func TestGetAllWithoutExpandSkipsFiles(t *testing.T) {
+	f := newEmployeeFixture()
+	f.employee.GetAllFunc = func(ctx context.Context, input *dto.GetEmployeesRequest) ([]dto.EmployeeResponse, error) {
+		return imageRows("https://api.example.com/files/ready"), nil
+	}
+	f.file.FindByURIsFunc = func(ctx context.Context, uris []string) ([]entity.File, error) {
+		t.Error("FindByURIs called without expand=images")
+		return nil, nil
+	}
+
+	employees, err := f.service().GetAll(context.Background(), listRequest())
+	if err != nil {
+		t.Fatalf("GetAll() error = %v", err)
+	}
+	if len(employees) != 1 || employees[0].EmployeeImage != nil {
+		t.Fatalf("GetAll() = %+v, want no employeeImage", employees)
+	}
+}
+
+// TestCreateUnrestrictedEmployeeImage memastikan URL apa pun diterima tanpa
+// membaca file jika FILE_RESTRICT_EMPLOYEE_IMAGES tidak aktif.
+func TestCreateUnrestrictedEmployeeImage(t *testing.T) {
+	f := newEmployeeFixture()
+	f.file.FindByURIsFunc = func(ctx context.Context, uris []string) ([]entity.File, error) {
+		t.Error("FindByURIs called with FILE_RESTRICT_EMPLOYEE_IMAGES=false")
+		return nil, nil
+	}
+	f.employee.CreateFunc = func(ctx context.Context, input *dto.EmployeePayload, managerId string) (dto.EmployeeResponse, error) {
+		return dto.EmployeeResponse{EmployeePayload: *input}, nil
+	}
+
+	input := createPayload("EMP-1")
+	input.EmployeeImageUri = "https://external.example.org/photo.jpg"
+	if _, err := f.service().Create(context.Background(), input, testManagerID); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+}
+
+func TestUpdateRestrictedEmployeeImage(t *testing.T) {
+	const owned = "https://api.example.com/files/owned"
+	for _, tt := range []struct {
+		name string
+		uri  string
+		// clear mengirim employeeImageUri sebagai null
+		clear bool
+		want  error
+	}{
+		{name: "owned file", uri: owned},
+		{name: "foreign file", uri: "https://api.example.com/files/foreign", want: helper.ErrInvalidEmployeeImage},
+		{name: "nonexistent file", uri: "https://api.example.com/files/missing", want: helper.ErrInvalidEmployeeImage},
+		{name: "clear image", clear: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newEmployeeFixture()
+			f.fileConfig.RestrictEmployeeImages = true
+			f.file.FindByURIsFunc = func(ctx context.Context, uris []string) ([]entity.File, error) {
+				return []entity.File{
+					{FileURI: owned, ManagerId: testManagerID, Purpose: entity.FilePurposeEmployee},
+					{FileURI: "https://api.example.com/files/foreign", ManagerId: "other", Purpose: entity.FilePurposeEmployee},
+				}, nil
+			}
+			// Seperti FileRepository.AdjustReferences, uri kosong tidak
+			// pernah cocok dengan file
+			f.file.AdjustReferencesFunc = func(ctx context.Context, db database.Querier, added []string, removed []string) ([]string, error) {
+				var matched []string
+				for _, uri := range added {
+					if uri != "" {
+						matched = append(matched, uri)
+					}
+				}
+				return matched, nil
+			}
+			f.employee.GetForUpdateFunc = func(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error) {
+				employee := createPayload(identityNumber)
+				employee.EmployeeImageUri = owned
+				return dto.EmployeeResponse{EmployeePayload: employee}, nil
+			}
+			var updated bool
+			f.employee.UpdateFunc = func(ctx context.Context, identityNumber string, input *dto.EmployeeUpdatePayload, managerId string) (dto.EmployeeResponse, error) {
+				updated = true
+				employee := createPayload(identityNumber)
+				employee.EmployeeImageUri = ""
+				if input.EmployeeImageUri != nil {
+					employee.EmployeeImageUri = *input.EmployeeImageUri
+				}
+				return dto.EmployeeResponse{EmployeePayload: employee}, nil
+			}
+
+			input := dto.EmployeeUpdatePayload{Nulls: []string{"employeeImageUri"}}
+			if !tt.clear {
+				uri := tt.uri
+				input = dto.EmployeeUpdatePayload{EmployeeImageUri: &uri}
+			}
+			_, err := f.service().Update(context.Background(), "EMP-1", input, testManagerID)
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Fatalf("Update() error = %v, want %v", err, tt.want)
+			}
+			if updated != (tt.want == nil) {
+				t.Fatalf("employee updated = %t, want %t", updated, tt.want == nil)
+			}
+		})
+	}
+}
+
+// TestCreateManyRestrictedEmployeeImages memastikan gambar yang ditolak
+// hanya menggagalkan employee-nya sendiri.
+func TestCreateManyRestrictedEmployeeImages(t *testing.T) {
+	f := newEmployeeFixture()
+	f.fileConfig.RestrictEmployeeImages = true
+	f.file.FindByURIsFunc = func(ctx context.Context, uris []string) ([]entity.File, error) {
+		return []entity.File{{FileURI: "https://api.example.com/files/owned", ManagerId: testManagerID, Purpose: entity.FilePurposeEmployee}}, nil
+	}
+	var inserted []string
+	f.employee.CreateManyFunc = func(ctx context.Context, pool database.Querier, inputs []dto.EmployeePayload, managerId string) ([]dto.EmployeeResponse, []error, error) {
+		for _, input := range inputs {
+			inserted = append(inserted, input.IdentityNumber)
+		}
+		return make([]dto.EmployeeResponse, len(inputs)), make([]error, len(inputs)), nil
+	}
+
+	inputs := []dto.EmployeePayload{createPayload("EMP-1"), createPayload("EMP-2")}
+	inputs[0].EmployeeImageUri = "https://api.example.com/files/owned"
+	inputs[1].EmployeeImageUri = "https://external.example.org/photo.jpg"
+	errs, err := f.service().CreateMany(context.Background(), inputs, testManagerID)
+	if err != nil {
+		t.Fatalf("CreateMany() error = %v", err)
+	}
+	if len(errs) != 2 || errs[0] != nil || !errors.Is(errs[1], helper.ErrInvalidEmployeeImage) {
+		t.Fatalf("CreateMany() errs = %v, want only EMP-2 rejected", errs)
+	}
+	if len(inserted) != 1 || inserted[0] != "EMP-1" {
+		t.Fatalf("inserted = %v, want only EMP-1", inserted)
+	}
+}
+
+// recordReferences mengganti AdjustReferences agar mencatat rujukan yang
+// diubah di unit of work.
+func (f *employeeFixture) recordReferences() (added, removed *[]string) {
+	added, removed = &[]string{}, &[]string{}
+	f.file.AdjustReferencesFunc = func(ctx context.Context, db database.Querier, a []string, r []string) ([]string, error) {
+		*added = append(*added, a...)
+		*removed = append(*removed, r...)
+		return a, nil
+	}
+	return added, removed
+}
+
+func TestEmployeeImageReferences(t *testing.T) {
+	const (
+		oldImage = "https://api.example.com/files/old"
+		newImage = "https://api.example.com/files/new"
+	)
+	for _, tt := range []struct {
+		name        string
+		write       func(f *employeeFixture) error
+		wantAdded   []string
+		wantRemoved []string
+	}{
+		{
+			name: "create",
+			write: func(f *employeeFixture) error {
+				f.employee.CreateFunc = func(ctx context.Context, input *dto.EmployeePayload, managerId string) (dto.EmployeeResponse, error) {
+					return dto.EmployeeResponse{EmployeePayload: *input}, nil
+				}
+				input := createPayload("EMP-1")
+				input.EmployeeImageUri = newImage
+				_, err := f.service().Create(context.Background(), input, testManagerID)
+				return err
+			},
+			wantAdded: []string{newImage},
+		},
+		{
+			name: "update image",
+			write: func(f *employeeFixture) error {
+				f.employee.GetForUpdateFunc = func(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error) {
+					employee := createPayload(identityNumber)
+					employee.EmployeeImageUri = oldImage
+					return dto.EmployeeResponse{EmployeePayload: employee}, nil
+				}
+				f.employee.UpdateFunc = func(ctx context.Context, identityNumber string, input *dto.EmployeeUpdatePayload, managerId string) (dto.EmployeeResponse, error) {
+					employee := createPayload(identityNumber)
+					employee.EmployeeImageUri = *input.EmployeeImageUri
+					return dto.EmployeeResponse{EmployeePayload: employee}, nil
+				}
+				uri := newImage
+				_, err := f.service().Update(context.Background(), "EMP-1", dto.EmployeeUpdatePayload{EmployeeImageUri: &uri}, testManagerID)
+				return err
+			},
+			wantAdded:   []string{newImage},
+			wantRemoved: []string{oldImage},
+		},
+		{
+			// Rujukan tidak berubah jika gambarnya tetap
+			name: "update name",
+			write: func(f *employeeFixture) error {
+				employee := createPayload("EMP-1")
+				employee.EmployeeImageUri = oldImage
+				f.employee.GetForUpdateFunc = func(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error) {
+					return dto.EmployeeResponse{EmployeePayload: employee}, nil
+				}
+				f.employee.UpdateFunc = func(ctx context.Context, identityNumber string, input *dto.EmployeeUpdatePayload, managerId string) (dto.EmployeeResponse, error) {
+					after := employee
+					after.Name = *input.Name
+					return dto.EmployeeResponse{EmployeePayload: after}, nil
+				}
+				name := "Budi Santoso"
+				_, err := f.service().Update(context.Background(), "EMP-1", dto.EmployeeUpdatePayload{Name: &name}, testManagerID)
+				return err
+			},
+		},
+		{
+			name: "delete",
+			write: func(f *employeeFixture) error {
+				f.employee.DeleteFunc = func(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error) {
+					employee := createPayload(identityNumber)
+					employee.EmployeeImageUri = oldImage
+					return dto.EmployeeResponse{EmployeePayload: employee}, nil
+				}
+				_, err := f.service().Delete(context.Background(), "EMP-1", testManagerID)
+				return err
+			},
+			wantRemoved: []string{oldImage},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newEmployeeFixture()
+			added, removed := f.recordReferences()
+
+			if err := tt.write(f); err != nil {
+				t.Fatalf("%s error = %v", tt.name, err)
+			}
+			if !slices.Equal(*added, tt.wantAdded) || !slices.Equal(*removed, tt.wantRemoved) {
+				t.Fatalf("references added %v, removed %v, want added %v, removed %v", *added, *removed, tt.wantAdded, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+// TestDeleteRollsBackWhenReferencesFail memastikan employee tidak terhapus
+// tanpa rujukan file-nya ikut dilepas.
+func TestDeleteRollsBackWhenReferencesFail(t *testing.T) {
+	f := newEmployeeFixture()
+	errReferences := errors.New("adjust failed")
+	f.file.AdjustReferencesFunc = func(ctx context.Context, db database.Querier, added []string, removed []string) ([]string, error) {
+		return nil, errReferences
+	}
+	f.employee.DeleteFunc = func(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error) {
+		return dto.EmployeeResponse{EmployeePayload: createPayload(identityNumber)}, nil
+	}
+
+	if _, err := f.service().Delete(context.Background(), "EMP-1", testManagerID); err == nil {
+		t.Fatal("Delete succeeded")
+	}
+	if f.uow.Committed {
+		t.Fatal("unit of work was committed")
+	}
+}
@@ -0,0 +1,388 @@
+package user_service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/telemetry"
+	"github.com/levensspel/go-gin-template/validation"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// importColumns adalah header CSV yang wajib ada, urutannya bebas.
+var importColumns = []string{"identityNumber", "name", "employeeImageUri", "gender", "departmentId"}
+
+// Import membaca CSV baris per baris dan meng-insert employee per batch,
+// sehingga memori yang dipakai tidak bergantung pada ukuran file. Setiap
+// batch memakai transaksinya sendiri; jika import dihentikan (batas baris,
+// deadline, atau error database) batch yang sedang berjalan di-rollback dan
+// laporan berisi progres batch yang sudah ter-commit.
+//
+// Setelah CopyThreshold baris valid, sisa file dikirim lewat COPY dalam satu
+// transaksi karena jauh lebih cepat untuk file besar.
+func (s *service) Import(ctx context.Context, file io.Reader, managerId string) (report dto.EmployeeImportReport, err error) {
+	ctx, span := s.tracer.Start(ctx, "EmployeeService.Import")
+	defer func() {
+		span.SetAttributes(
+			attribute.Int("import.processed_rows", report.ProcessedRows),
+			attribute.Int("import.imported_rows", report.ImportedRows),
+			attribute.Int("import.failed_rows", report.FailedRows),
+		)
+		telemetry.End(span, err)
+	}()
+
+	defer func() {
+		s.metrics.CountError(helper.EmployeeServiceImport, err)
+		// Batch yang sudah ter-commit tetap tersimpan walau import dihentikan
+		if report.ImportedRows > 0 {
+			s.firstPage.Invalidate(ctx, managerId)
+			s.metrics.CountEmployeesCreated(managerId, report.ImportedRows)
+		}
+		if err != nil {
+			report.Aborted = true
+			report.AbortReason = helper.GetErrorMessage(err)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, s.importConfig.Deadline)
+	defer cancel()
+
+	rows, err := newImportReader(ctx, file, s.importConfig.MaxRows, s.employeeImageChecker(ctx, managerId), &report)
+	if err != nil {
+		return report, err
+	}
+
+	batchSize := max(s.importConfig.BatchSize, 1)
+	batch := make([]dto.EmployeePayload, 0, batchSize)
+	batchRows := make([]int, 0, batchSize)
+	validRows := 0
+
+	for {
+		input, row, err := rows.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return report, err
+		}
+
+		batch = append(batch, input)
+		batchRows = append(batchRows, row)
+		validRows++
+
+		if s.importConfig.CopyThreshold > 0 && validRows >= s.importConfig.CopyThreshold {
+			return report, s.copyImportRows(ctx, batch, rows, managerId, &report)
+		}
+		if len(batch) < batchSize {
+			continue
+		}
+
+		if err := s.flushImportBatch(ctx, batch, batchRows, managerId, &report); err != nil {
+			return report, err
+		}
+		batch = batch[:0]
+		batchRows = batchRows[:0]
+	}
+
+	if len(batch) > 0 {
+		if err := s.flushImportBatch(ctx, batch, batchRows, managerId, &report); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// flushImportBatch meng-insert satu batch dalam satu transaksi. Import
+// dihentikan jika batch gagal, karena semua baris di batch ikut di-rollback.
+func (s *service) flushImportBatch(
+	ctx context.Context,
+	batch []dto.EmployeePayload,
+	batchRows []int,
+	managerId string,
+	report *dto.EmployeeImportReport,
+) error {
+	tx, err := s.dbPool.Begin(ctx)
+	if err != nil {
+		return importDatabaseError(ctx, err)
+	}
+	// Rollback tetap dijalankan walau deadline import sudah lewat, dan
+	// tidak berpengaruh apa-apa setelah Commit berhasil.
+	defer tx.Rollback(context.WithoutCancel(ctx))
+
+	inserted, err := s.employeeRepo.InsertBatch(ctx, tx, batch, managerId)
+	if err != nil {
+		s.logger.Error(err.Error(), helper.EmployeeServiceImport, err)
+		return importDatabaseError(ctx, err)
+	}
+	images := make([]string, 0, len(batch))
+	for i, ok := range inserted {
+		if ok {
+			images = append(images, batch[i].EmployeeImageUri)
+		}
+	}
+	// Gambar sudah dicek importReader; file yang terhapus di antaranya
+	// tidak menggagalkan batch
+	if _, err := s.fileRepo.AdjustReferences(ctx, tx, images, nil); err != nil {
+		s.logger.Error(err.Error(), helper.EmployeeServiceImport, err)
+		return importDatabaseError(ctx, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		s.logger.Error(err.Error(), helper.EmployeeServiceImport, err)
+		return importDatabaseError(ctx, err)
+	}
+
+	for i, ok := range inserted {
+		if ok {
+			report.ImportedRows++
+			report.CreatedRows++
+			continue
+		}
+		report.AddSkipped(batchRows[i], "invalid department id or identity number conflict")
+	}
+	return nil
+}
+
+// copyImportRows mengirim baris yang sudah terkumpul dan sisa file lewat
+// COPY dalam satu transaksi. Jika import dihentikan di tengah COPY, seluruh
+// baris pada tahap ini di-rollback.
+func (s *service) copyImportRows(
+	ctx context.Context,
+	pending []dto.EmployeePayload,
+	rows *importReader,
+	managerId string,
+	report *dto.EmployeeImportReport,
+) error {
+	tx, err := s.dbPool.Begin(ctx)
+	if err != nil {
+		return importDatabaseError(ctx, err)
+	}
+	defer tx.Rollback(context.WithoutCancel(ctx))
+
+	source := &importCopySource{pending: pending, rows: rows}
+	copied, inserted, err := s.employeeRepo.CopyEmployees(ctx, tx, source, managerId)
+	if source.err != nil {
+		// Error dari pembacaan file (batas baris, deadline) lebih informatif
+		// daripada error COPY yang dibatalkan karenanya.
+		return source.err
+	}
+	if err != nil {
+		s.logger.Error(err.Error(), helper.EmployeeServiceImport, err)
+		return importDatabaseError(ctx, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		s.logger.Error(err.Error(), helper.EmployeeServiceImport, err)
+		return importDatabaseError(ctx, err)
+	}
+
+	report.CopiedRows += int(copied)
+	report.ImportedRows += int(inserted)
+	report.CreatedRows += int(inserted)
+	// Baris yang ditolak saat INSERT ... SELECT tidak diketahui nomornya,
+	// hanya jumlahnya.
+	report.SkippedRows += int(copied - inserted)
+	return nil
+}
+
+// importReader membaca CSV dan hanya mengembalikan baris yang valid. Baris
+// yang gagal parsing atau validasi langsung dicatat ke laporan.
+type importReader struct {
+	ctx     context.Context
+	csv     *csv.Reader
+	columns []int
+	row     int
+	maxRows int
+	// checkImage nil jika employeeImageUri tidak dibatasi, lihat
+	// service.employeeImageChecker
+	checkImage func(uri string) error
+	report     *dto.EmployeeImportReport
+}
+
+func newImportReader(
+	ctx context.Context,
+	file io.Reader,
+	maxRows int,
+	checkImage func(uri string) error,
+	report *dto.EmployeeImportReport,
+) (*importReader, error) {
+	reader := csv.NewReader(file)
+	reader.ReuseRecord = true
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, importReadError(err)
+	}
+	columns, err := importColumnIndex(header)
+	if err != nil {
+		return nil, err
+	}
+
+	// Baris 1 adalah header
+	return &importReader{
+		ctx:        ctx,
+		csv:        reader,
+		columns:    columns,
+		row:        1,
+		maxRows:    maxRows,
+		checkImage: checkImage,
+		report:     report,
+	}, nil
+}
+
+// next mengembalikan baris valid berikutnya beserta nomor barisnya, atau
+// io.EOF jika file sudah habis.
+func (r *importReader) next() (dto.EmployeePayload, int, error) {
+	for {
+		record, err := r.csv.Read()
+		if err == io.EOF {
+			return dto.EmployeePayload{}, 0, io.EOF
+		}
+		r.row++
+
+		if r.ctx.Err() != nil {
+			return dto.EmployeePayload{}, 0, helper.ErrImportDeadline
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			r.report.ProcessedRows++
+			r.report.AddFailure(r.row, parseErr.Err.Error())
+			continue
+		}
+		if err != nil {
+			return dto.EmployeePayload{}, 0, importReadError(err)
+		}
+
+		if r.report.ProcessedRows >= r.maxRows {
+			return dto.EmployeePayload{}, 0, helper.ErrImportRowLimit
+		}
+		r.report.ProcessedRows++
+
+		input := dto.EmployeePayload{
+			IdentityNumber:   record[r.columns[0]],
+			Name:             record[r.columns[1]],
+			EmployeeImageUri: record[r.columns[2]],
+			Gender:           record[r.columns[3]],
+			DepartmentID:     record[r.columns[4]],
+		}
+		valid, err := checkImportRow(r.ctx, &input, r.row, r.checkImage, r.report)
+		if err != nil {
+			return dto.EmployeePayload{}, 0, err
+		}
+		if valid {
+			return input, r.row, nil
+		}
+	}
+}
+
+// checkImportRow memvalidasi satu baris import. Baris yang tidak valid
+// dicatat ke report dan mengembalikan false; error berarti import harus
+// dihentikan.
+func checkImportRow(
+	ctx context.Context,
+	input *dto.EmployeePayload,
+	row int,
+	checkImage func(uri string) error,
+	report *dto.EmployeeImportReport,
+) (bool, error) {
+	if err := validation.ValidateEmployeeCreate(input); err != nil {
+		report.AddFailure(row, err.Error())
+		return false, nil
+	}
+	if checkImage != nil {
+		err := checkImage(input.EmployeeImageUri)
+		if errors.Is(err, helper.ErrInvalidEmployeeImage) {
+			report.AddFailure(row, helper.GetErrorMessage(err))
+			return false, nil
+		}
+		if err != nil {
+			return false, importDatabaseError(ctx, err)
+		}
+	}
+	return true, nil
+}
+
+// importCopySource adalah pgx.CopyFromSource yang mengirim baris pending
+// terlebih dahulu, lalu lanjut membaca file secara streaming.
+type importCopySource struct {
+	pending []dto.EmployeePayload
+	rows    *importReader
+	current dto.EmployeePayload
+	err     error
+}
+
+func (c *importCopySource) Next() bool {
+	if len(c.pending) > 0 {
+		c.current = c.pending[0]
+		c.pending = c.pending[1:]
+		return true
+	}
+
+	input, _, err := c.rows.next()
+	if err != nil {
+		if err != io.EOF {
+			c.err = err
+		}
+		return false
+	}
+	c.current = input
+	return true
+}
+
+func (c *importCopySource) Values() ([]any, error) {
+	return []any{
+		c.current.IdentityNumber,
+		c.current.Name,
+		c.current.EmployeeImageUri,
+		c.current.Gender,
+		c.current.DepartmentID,
+	}, nil
+}
+
+func (c *importCopySource) Err() error {
+	return c.err
+}
+
+func importColumnIndex(header []string) ([]int, error) {
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		// Header boleh diawali BOM jika file disimpan dari Excel
+		name = strings.TrimPrefix(strings.TrimSpace(name), "\ufeff")
+		positions[strings.ToLower(name)] = i
+	}
+
+	columns := make([]int, len(importColumns))
+	for i, name := range importColumns {
+		position, ok := positions[strings.ToLower(name)]
+		if !ok {
+			return nil, helper.ErrInvalidImportFile
+		}
+		columns[i] = position
+	}
+	return columns, nil
+}
+
+func importReadError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return helper.ErrPayloadTooLarge
+	}
+	return helper.ErrInvalidImportFile
+}
+
+func importDatabaseError(ctx context.Context, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return helper.ErrImportDeadline
+	}
+	if errors.Is(err, helper.ErrServiceUnavailable) {
+		return helper.ErrServiceUnavailable
+	}
+	return helper.ErrInternalServer
+}
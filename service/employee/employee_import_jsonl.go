@@ -0,0 +1,392 @@
+package user_service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/events"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/repository"
+	"github.com/levensspel/go-gin-template/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// importJSONLMaxLineBytes membatasi panjang satu baris JSONL. Baris yang
+// lebih panjang dibuang tanpa dibaca ke memori dan dicatat sebagai gagal.
+const importJSONLMaxLineBytes = 64 << 10
+
+// ImportJSONL membaca JSON Lines (satu dto.EmployeePayload per baris) dan
+// meng-upsert employee berdasarkan identityNumber per batch: employee baru
+// dibuat, employee milik manager yang datanya berbeda di-update. Setiap
+// batch memakai transaksinya sendiri seperti Import, dengan audit log dan
+// event outbox seperti Create dan Update.
+//
+// Dengan dryRun semua baris divalidasi dan dicocokkan dengan database dalam
+// transaksi read only, dan laporan berisi hasil yang akan terjadi tanpa ada
+// yang disimpan.
+func (s *service) ImportJSONL(ctx context.Context, file io.Reader, managerId string, dryRun bool) (report dto.EmployeeImportReport, err error) {
+	ctx, span := s.tracer.Start(ctx, "EmployeeService.ImportJSONL", trace.WithAttributes(
+		attribute.Bool("import.dry_run", dryRun),
+	))
+	defer func() {
+		span.SetAttributes(
+			attribute.Int("import.processed_rows", report.ProcessedRows),
+			attribute.Int("import.created_rows", report.CreatedRows),
+			attribute.Int("import.updated_rows", report.UpdatedRows),
+			attribute.Int("import.failed_rows", report.FailedRows),
+		)
+		telemetry.End(span, err)
+	}()
+
+	report.DryRun = dryRun
+	defer func() {
+		s.metrics.CountError(helper.EmployeeServiceImport, err)
+		if !dryRun && report.ImportedRows > 0 {
+			s.firstPage.Invalidate(ctx, managerId)
+			s.metrics.CountEmployeesCreated(managerId, report.CreatedRows)
+			for range report.UpdatedRows {
+				s.metrics.CountEmployeeUpdated(managerId)
+			}
+		}
+		if err != nil {
+			report.Aborted = true
+			report.AbortReason = helper.GetErrorMessage(err)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, s.importConfig.Deadline)
+	defer cancel()
+
+	rows := newJSONLImportReader(ctx, file, s.importConfig.MaxRows, s.employeeImageChecker(ctx, managerId), &report)
+
+	batchSize := max(s.importConfig.BatchSize, 1)
+	batch := make([]dto.EmployeePayload, 0, batchSize)
+	batchRows := make([]int, 0, batchSize)
+
+	for {
+		input, row, err := rows.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return report, err
+		}
+
+		batch = append(batch, input)
+		batchRows = append(batchRows, row)
+		if len(batch) < batchSize {
+			continue
+		}
+
+		if err := s.upsertImportBatch(ctx, batch, batchRows, managerId, dryRun, &report); err != nil {
+			return report, err
+		}
+		batch = batch[:0]
+		batchRows = batchRows[:0]
+	}
+
+	if len(batch) > 0 {
+		if err := s.upsertImportBatch(ctx, batch, batchRows, managerId, dryRun, &report); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// importOutcome adalah hasil satu baris JSONL dalam satu batch.
+type importOutcome int
+
+const (
+	importCreate importOutcome = iota
+	importUpdate
+	importUnchanged
+	importSkip
+)
+
+// upsertImportBatch menyimpan satu batch dalam satu transaksi. Laporan baru
+// diubah setelah transaksi ter-commit, sehingga batch yang di-rollback
+// tidak ikut terhitung.
+func (s *service) upsertImportBatch(
+	ctx context.Context,
+	batch []dto.EmployeePayload,
+	batchRows []int,
+	managerId string,
+	dryRun bool,
+	report *dto.EmployeeImportReport,
+) error {
+	outcomes := make([]importOutcome, len(batch))
+	reasons := make([]string, len(batch))
+	skip := func(i int, reason string) {
+		outcomes[i] = importSkip
+		reasons[i] = reason
+	}
+
+	txOptions := pgx.TxOptions{}
+	if dryRun {
+		txOptions.AccessMode = pgx.ReadOnly
+	}
+	err := s.uow.DoTx(ctx, txOptions, func(repos repository.Repositories) error {
+		identityNumbers := make([]string, len(batch))
+		departmentIds := make([]string, 0, len(batch))
+		for i, input := range batch {
+			identityNumbers[i] = input.IdentityNumber
+			departmentIds = append(departmentIds, input.DepartmentID)
+		}
+		// Employee yang akan di-update dikunci agar tidak berubah di antara
+		// pengecekan dan update
+		existing, err := repos.Employee.GetForUpsert(ctx, repos.Tx, identityNumbers, !dryRun)
+		if err != nil {
+			return err
+		}
+		departments, err := repos.Department.GetByIDs(ctx, departmentIds, managerId)
+		if err != nil {
+			return err
+		}
+		owned := make(map[string]bool, len(departments))
+		for _, department := range departments {
+			owned[department.Id] = true
+		}
+
+		var creates, updates []dto.EmployeePayload
+		var createIndexes, updateIndexes []int
+		for i, input := range batch {
+			current, found := existing[input.IdentityNumber]
+			switch {
+			case !owned[input.DepartmentID]:
+				skip(i, helper.GetErrorMessage(helper.ErrInvalidDepartmentId))
+			case !found:
+				outcomes[i] = importCreate
+				creates = append(creates, input)
+				createIndexes = append(createIndexes, i)
+			case current.ManagerID != managerId:
+				// identityNumber unik global, employee manager lain tidak
+				// boleh ditimpa
+				skip(i, helper.GetErrorMessage(helper.ErrConflictIdentityNumber))
+			case current.Employee.EmployeePayload.Equal(input):
+				outcomes[i] = importUnchanged
+			default:
+				outcomes[i] = importUpdate
+				updates = append(updates, input)
+				updateIndexes = append(updateIndexes, i)
+			}
+		}
+		if dryRun {
+			return nil
+		}
+
+		var added, removed []string
+		if len(creates) > 0 {
+			inserted, err := repos.Employee.InsertBatch(ctx, repos.Tx, creates, managerId)
+			if err != nil {
+				return err
+			}
+			for j, ok := range inserted {
+				if !ok {
+					// Employee dibuat oleh request lain setelah GetForUpsert
+					skip(createIndexes[j], "identity number conflict")
+					continue
+				}
+				added = append(added, creates[j].EmployeeImageUri)
+			}
+		}
+		if len(updates) > 0 {
+			updated, err := repos.Employee.UpdateBatch(ctx, repos.Tx, updates, managerId)
+			if err != nil {
+				return err
+			}
+			for j, ok := range updated {
+				if !ok {
+					skip(updateIndexes[j], "invalid department id or identity number conflict")
+					continue
+				}
+				before := existing[updates[j].IdentityNumber].Employee
+				if before.EmployeeImageUri != updates[j].EmployeeImageUri {
+					added = append(added, updates[j].EmployeeImageUri)
+					removed = append(removed, before.EmployeeImageUri)
+				}
+			}
+		}
+		if err := s.referenceEmployeeImages(ctx, repos, added, removed); err != nil {
+			return err
+		}
+
+		for i, input := range batch {
+			switch outcomes[i] {
+			case importCreate:
+				employee := dto.EmployeeResponse{EmployeePayload: input}
+				if err := s.recordEmployee(ctx, repos, entity.AuditActionCreate, managerId, input.IdentityNumber, nil, employee); err != nil {
+					return err
+				}
+				if err := s.recordEmployeeVersion(ctx, repos, entity.AuditActionCreate, managerId, input.IdentityNumber); err != nil {
+					return err
+				}
+				err := s.addEmployeeEvent(ctx, repos, entity.EventEmployeeCreated, input.IdentityNumber, events.Employee{
+					ManagerID: managerId,
+					Employee:  employee,
+				})
+				if err != nil {
+					return err
+				}
+			case importUpdate:
+				before := existing[input.IdentityNumber].Employee
+				employee := dto.EmployeeResponse{EmployeePayload: input, CreatedAt: before.CreatedAt}
+				if err := s.recordEmployee(ctx, repos, entity.AuditActionUpdate, managerId, input.IdentityNumber, before, employee); err != nil {
+					return err
+				}
+				if err := s.recordEmployeeVersion(ctx, repos, entity.AuditActionUpdate, managerId, input.IdentityNumber); err != nil {
+					return err
+				}
+				err := s.addEmployeeEvent(ctx, repos, entity.EventEmployeeUpdated, input.IdentityNumber, events.Employee{
+					ManagerID: managerId,
+					Employee:  employee,
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, helper.ErrInvalidEmployeeImage) {
+		// File gambar dihapus setelah dicek jsonlImportReader
+		return err
+	}
+	if err != nil {
+		s.logger.Error(err.Error(), helper.EmployeeServiceImport, err)
+		return importDatabaseError(ctx, err)
+	}
+
+	for i, outcome := range outcomes {
+		switch outcome {
+		case importCreate:
+			report.CreatedRows++
+		case importUpdate:
+			report.UpdatedRows++
+		case importUnchanged:
+			report.UnchangedRows++
+		case importSkip:
+			report.AddSkipped(batchRows[i], reasons[i])
+		}
+	}
+	report.ImportedRows = report.CreatedRows + report.UpdatedRows
+	return nil
+}
+
+// jsonlImportReader membaca JSON Lines dan hanya mengembalikan baris yang
+// valid, seperti importReader untuk CSV. Nomor baris dimulai dari 1 dan
+// baris kosong ikut dihitung.
+type jsonlImportReader struct {
+	ctx     context.Context
+	reader  *bufio.Reader
+	line    int
+	maxRows int
+	// seen berisi baris pertama setiap identityNumber, identityNumber yang
+	// muncul lagi di file yang sama dianggap gagal
+	seen       map[string]int
+	checkImage func(uri string) error
+	report     *dto.EmployeeImportReport
+}
+
+func newJSONLImportReader(
+	ctx context.Context,
+	file io.Reader,
+	maxRows int,
+	checkImage func(uri string) error,
+	report *dto.EmployeeImportReport,
+) *jsonlImportReader {
+	return &jsonlImportReader{
+		ctx:        ctx,
+		reader:     bufio.NewReaderSize(file, importJSONLMaxLineBytes),
+		maxRows:    maxRows,
+		seen:       map[string]int{},
+		checkImage: checkImage,
+		report:     report,
+	}
+}
+
+// next mengembalikan baris valid berikutnya beserta nomor barisnya, atau
+// io.EOF jika file sudah habis.
+func (r *jsonlImportReader) next() (dto.EmployeePayload, int, error) {
+	for {
+		line, tooLong, err := r.readLine()
+		if err == io.EOF {
+			return dto.EmployeePayload{}, 0, io.EOF
+		}
+		if err != nil {
+			return dto.EmployeePayload{}, 0, importReadError(err)
+		}
+		r.line++
+
+		if r.ctx.Err() != nil {
+			return dto.EmployeePayload{}, 0, helper.ErrImportDeadline
+		}
+		if r.line == 1 {
+			line = bytes.TrimPrefix(line, []byte("\ufeff"))
+		}
+		if !tooLong && len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		if r.report.ProcessedRows >= r.maxRows {
+			return dto.EmployeePayload{}, 0, helper.ErrImportRowLimit
+		}
+		r.report.ProcessedRows++
+
+		if tooLong {
+			r.report.AddFailure(r.line, fmt.Sprintf("line is longer than %d bytes", importJSONLMaxLineBytes))
+			continue
+		}
+		var input dto.EmployeePayload
+		if err := json.Unmarshal(line, &input); err != nil {
+			r.report.AddFailure(r.line, "invalid JSON: "+err.Error())
+			continue
+		}
+		valid, err := checkImportRow(r.ctx, &input, r.line, r.checkImage, r.report)
+		if err != nil {
+			return dto.EmployeePayload{}, 0, err
+		}
+		if !valid {
+			continue
+		}
+		if first, ok := r.seen[input.IdentityNumber]; ok {
+			r.report.AddFailure(r.line, fmt.Sprintf("identityNumber already appears on line %d", first))
+			continue
+		}
+		r.seen[input.IdentityNumber] = r.line
+		return input, r.line, nil
+	}
+}
+
+// readLine mengembalikan satu baris tanpa newline. Isi baris yang lebih
+// panjang dari importJSONLMaxLineBytes dibuang dan tooLong bernilai true.
+// Slice hasilnya hanya berlaku sampai readLine dipanggil lagi.
+func (r *jsonlImportReader) readLine() (line []byte, tooLong bool, err error) {
+	for {
+		chunk, err := r.reader.ReadSlice('\n')
+		if errors.Is(err, bufio.ErrBufferFull) {
+			tooLong = true
+			continue
+		}
+		if err == io.EOF && (len(chunk) > 0 || tooLong) {
+			// Baris terakhir tanpa newline
+			err = nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		if tooLong {
+			return nil, true, nil
+		}
+		return bytes.TrimRight(chunk, "\r\n"), false, nil
+	}
+}
@@ -0,0 +1,294 @@
+package user_service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/mocks"
+	repositories "github.com/levensspel/go-gin-template/repository/employee"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+const (
+	otherManagerID = "5d1f0c7a-2b3e-4c4d-8e9f-0a1b2c3d4e5f"
+	otherDeptID    = "7e2a4b6c-8d0e-4f1a-9b3c-5d7e9f1a3b5c"
+)
+
+// jsonlLine mengembalikan payload sebagai satu baris JSONL.
+func jsonlLine(t *testing.T, payload dto.EmployeePayload) string {
+	t.Helper()
+	line, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(line)
+}
+
+// mixedJSONL berisi baris create, update, unchanged, gagal dan dilewati.
+// Baris valid ada di baris 1, 3, 5, 8, 9 dan 11.
+func mixedJSONL(t *testing.T) string {
+	updated := createPayload("EMP-UPD")
+	updated.Name = "Budi Baru"
+	invalid := createPayload("EMP-BAD")
+	invalid.Gender = "robot"
+	foreignDepartment := createPayload("EMP-DEPT")
+	foreignDepartment.DepartmentID = otherDeptID
+	return strings.Join([]string{
+		jsonlLine(t, createPayload("EMP-NEW1")),
+		"",
+		jsonlLine(t, updated),
+		`{"identityNumber": "EMP-JSON",`,
+		jsonlLine(t, createPayload("EMP-SAME")),
+		jsonlLine(t, invalid),
+		jsonlLine(t, createPayload("EMP-NEW1")),
+		jsonlLine(t, createPayload("EMP-OTHER")),
+		jsonlLine(t, foreignDepartment),
+		`{"name": "` + strings.Repeat("x", 64<<10) + `"}`,
+		// Baris terakhir tanpa newline
+		jsonlLine(t, createPayload("EMP-NEW2")),
+	}, "\n")
+}
+
+// importFixture menyiapkan employee yang sudah ada untuk ImportJSONL
+// dengan batch berisi 2 baris, dan mencatat isi setiap batch.
+type importFixture struct {
+	*employeeFixture
+	batches  [][]string
+	locked   []bool
+	inserted []string
+	updated  []string
+}
+
+func newImportFixture() *importFixture {
+	f := &importFixture{employeeFixture: newEmployeeFixture()}
+	f.importConfig = &config.ImportConfig{BatchSize: 2, MaxRows: 100, Deadline: time.Minute}
+	existing := map[string]repositories.ExistingEmployee{
+		"EMP-UPD":   {Employee: dto.EmployeeResponse{EmployeePayload: createPayload("EMP-UPD")}, ManagerID: testManagerID},
+		"EMP-SAME":  {Employee: dto.EmployeeResponse{EmployeePayload: createPayload("EMP-SAME")}, ManagerID: testManagerID},
+		"EMP-OTHER": {Employee: dto.EmployeeResponse{EmployeePayload: createPayload("EMP-OTHER")}, ManagerID: otherManagerID},
+	}
+	f.employee.GetForUpsertFunc = func(ctx context.Context, pool database.Querier, identityNumbers []string, lock bool) (map[string]repositories.ExistingEmployee, error) {
+		f.batches = append(f.batches, identityNumbers)
+		f.locked = append(f.locked, lock)
+		found := map[string]repositories.ExistingEmployee{}
+		for _, identityNumber := range identityNumbers {
+			if employee, ok := existing[identityNumber]; ok {
+				found[identityNumber] = employee
+			}
+		}
+		return found, nil
+	}
+	f.employee.InsertBatchFunc = func(ctx context.Context, pool database.Querier, inputs []dto.EmployeePayload, managerId string) ([]bool, error) {
+		return f.written(&f.inserted, inputs), nil
+	}
+	f.employee.UpdateBatchFunc = func(ctx context.Context, pool database.Querier, inputs []dto.EmployeePayload, managerId string) ([]bool, error) {
+		return f.written(&f.updated, inputs), nil
+	}
+	f.uow.Repositories.Department = &mocks.DepartmentRepository{
+		GetByIDsFunc: func(ctx context.Context, deptIDs []string, managerID string) ([]entity.Department, error) {
+			return []entity.Department{{Id: testDeptID, Name: "Engineering"}}, nil
+		},
+	}
+	return f
+}
+
+func (f *importFixture) written(rows *[]string, inputs []dto.EmployeePayload) []bool {
+	ok := make([]bool, len(inputs))
+	for i, input := range inputs {
+		*rows = append(*rows, input.IdentityNumber)
+		ok[i] = true
+	}
+	return ok
+}
+
+// failureRows mengembalikan alasan setiap baris yang gagal atau dilewati.
+func failureRows(report dto.EmployeeImportReport) map[int]string {
+	rows := make(map[int]string, len(report.Failures))
+	for _, failure := range report.Failures {
+		rows[failure.Row] = failure.Reason
+	}
+	return rows
+}
+
+// wantMixedReport memastikan laporan mixedJSONL, sama dengan atau tanpa
+// dryRun.
+func wantMixedReport(t *testing.T, report dto.EmployeeImportReport, dryRun bool) {
+	t.Helper()
+	want := dto.EmployeeImportReport{
+		ProcessedRows: 10,
+		ImportedRows:  3,
+		CreatedRows:   2,
+		UpdatedRows:   1,
+		UnchangedRows: 1,
+		FailedRows:    4,
+		SkippedRows:   2,
+		DryRun:        dryRun,
+	}
+	got := report
+	got.Failures = nil
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("report = %+v, want %+v", got, want)
+	}
+	for row, reason := range map[int]string{
+		4:  "invalid JSON",
+		6:  "gender",
+		7:  "already appears on line 1",
+		8:  helper.GetErrorMessage(helper.ErrConflictIdentityNumber),
+		9:  helper.GetErrorMessage(helper.ErrInvalidDepartmentId),
+		10: "line is longer than",
+	} {
+		if got := failureRows(report)[row]; !strings.Contains(got, reason) {
+			t.Errorf("line %d reason = %q, want it to contain %q", row, got, reason)
+		}
+	}
+	if len(report.Failures) != 6 {
+		t.Errorf("failures = %+v, want 6 lines", report.Failures)
+	}
+}
+
+func TestImportJSONL(t *testing.T) {
+	f := newImportFixture()
+	report, err := f.service().ImportJSONL(context.Background(), strings.NewReader(mixedJSONL(t)), testManagerID, false)
+	if err != nil {
+		t.Fatalf("ImportJSONL error = %v", err)
+	}
+	wantMixedReport(t, report, false)
+
+	// Baris valid diproses per 2 baris, dan employee yang akan di-update
+	// dikunci
+	wantBatches := [][]string{{"EMP-NEW1", "EMP-UPD"}, {"EMP-SAME", "EMP-OTHER"}, {"EMP-DEPT", "EMP-NEW2"}}
+	if !slices.EqualFunc(f.batches, wantBatches, slices.Equal) || slices.Contains(f.locked, false) {
+		t.Fatalf("batches = %v, locked = %v, want %v locked", f.batches, f.locked, wantBatches)
+	}
+	if want := []string{"EMP-NEW1", "EMP-NEW2"}; !slices.Equal(f.inserted, want) {
+		t.Errorf("inserted = %v, want %v", f.inserted, want)
+	}
+	if want := []string{"EMP-UPD"}; !slices.Equal(f.updated, want) {
+		t.Errorf("updated = %v, want %v", f.updated, want)
+	}
+	wantEvents := []string{
+		entity.EventEmployeeCreated + ":EMP-NEW1",
+		entity.EventEmployeeUpdated + ":EMP-UPD",
+		entity.EventEmployeeCreated + ":EMP-NEW2",
+	}
+	if !slices.Equal(f.events, wantEvents) {
+		t.Errorf("events = %v, want %v", f.events, wantEvents)
+	}
+	wantVersions := []string{
+		entity.AuditActionCreate + ":EMP-NEW1",
+		entity.AuditActionUpdate + ":EMP-UPD",
+		entity.AuditActionCreate + ":EMP-NEW2",
+	}
+	if !slices.Equal(f.versions, wantVersions) || len(f.audits) != 3 {
+		t.Errorf("versions = %v, audits = %d, want %v and 3 audits", f.versions, len(f.audits), wantVersions)
+	}
+	if got := testutil.ToFloat64(f.metrics.EmployeesCreated.WithLabelValues(allManagers)); got != 2 {
+		t.Errorf("employees created = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(f.metrics.EmployeesUpdated.WithLabelValues(allManagers)); got != 1 {
+		t.Errorf("employees updated = %v, want 1", got)
+	}
+}
+
+func TestImportJSONLDryRun(t *testing.T) {
+	f := newImportFixture()
+	// Dry run tidak boleh menulis: write yang tidak di-mock gagal dengan
+	// mocks.ErrNotMocked
+	f.employee.InsertBatchFunc = nil
+	f.employee.UpdateBatchFunc = nil
+	f.employee.AddVersionFunc = nil
+	f.file.AdjustReferencesFunc = nil
+	f.uow.Repositories.Audit = &mocks.AuditRepository{}
+	f.uow.Repositories.Outbox = &mocks.OutboxRepository{}
+
+	report, err := f.service().ImportJSONL(context.Background(), strings.NewReader(mixedJSONL(t)), testManagerID, true)
+	if err != nil {
+		t.Fatalf("ImportJSONL dry run error = %v", err)
+	}
+	wantMixedReport(t, report, true)
+	if f.uow.TxOptions.AccessMode != pgx.ReadOnly {
+		t.Fatalf("transaction access mode = %q, want read only", f.uow.TxOptions.AccessMode)
+	}
+	if len(f.batches) != 3 || slices.Contains(f.locked, true) {
+		t.Fatalf("batches = %v, locked = %v, want 3 batches without row locks", f.batches, f.locked)
+	}
+	if got := testutil.CollectAndCount(f.metrics.EmployeesCreated) + testutil.CollectAndCount(f.metrics.EmployeesUpdated); got != 0 {
+		t.Fatalf("counted %d employee metrics after a dry run", got)
+	}
+}
+
+func TestImportJSONLAbortsOnDatabaseError(t *testing.T) {
+	f := newImportFixture()
+	calls := 0
+	upsert := f.employee.GetForUpsertFunc
+	f.employee.GetForUpsertFunc = func(ctx context.Context, pool database.Querier, identityNumbers []string, lock bool) (map[string]repositories.ExistingEmployee, error) {
+		if calls++; calls == 2 {
+			return nil, errors.New("connection reset")
+		}
+		return upsert(ctx, pool, identityNumbers, lock)
+	}
+
+	report, err := f.service().ImportJSONL(context.Background(), strings.NewReader(mixedJSONL(t)), testManagerID, false)
+	if !errors.Is(err, helper.ErrInternalServer) {
+		t.Fatalf("ImportJSONL error = %v, want %v", err, helper.ErrInternalServer)
+	}
+	// Hanya batch pertama yang ter-commit yang dihitung
+	if !report.Aborted || report.CreatedRows != 1 || report.UpdatedRows != 1 || report.ImportedRows != 2 || report.SkippedRows != 0 {
+		t.Fatalf("report = %+v, want only the first batch", report)
+	}
+}
+
+func TestImportJSONLRowLimit(t *testing.T) {
+	f := newImportFixture()
+	f.importConfig.MaxRows = 2
+	file := strings.Join([]string{
+		jsonlLine(t, createPayload("EMP-1")),
+		"",
+		jsonlLine(t, createPayload("EMP-2")),
+		jsonlLine(t, createPayload("EMP-3")),
+	}, "\n")
+
+	report, err := f.service().ImportJSONL(context.Background(), strings.NewReader(file), testManagerID, false)
+	if !errors.Is(err, helper.ErrImportRowLimit) {
+		t.Fatalf("ImportJSONL error = %v, want %v", err, helper.ErrImportRowLimit)
+	}
+	// Baris kosong tidak dihitung, dan batch yang sudah ter-commit tetap
+	// tersimpan
+	if report.ProcessedRows != 2 || report.ImportedRows != 2 || !report.Aborted || !slices.Equal(f.inserted, []string{"EMP-1", "EMP-2"}) {
+		t.Fatalf("report = %+v, inserted = %v", report, f.inserted)
+	}
+}
+
+func TestImportJSONLConcurrentWrites(t *testing.T) {
+	f := newImportFixture()
+	// Employee dibuat atau dipindahkan request lain setelah GetForUpsert
+	f.employee.InsertBatchFunc = func(ctx context.Context, pool database.Querier, inputs []dto.EmployeePayload, managerId string) ([]bool, error) {
+		return make([]bool, len(inputs)), nil
+	}
+	f.employee.UpdateBatchFunc = func(ctx context.Context, pool database.Querier, inputs []dto.EmployeePayload, managerId string) ([]bool, error) {
+		return make([]bool, len(inputs)), nil
+	}
+	updated := createPayload("EMP-UPD")
+	updated.Name = "Budi Baru"
+	file := jsonlLine(t, createPayload("EMP-NEW1")) + "\n" + jsonlLine(t, updated) + "\n"
+
+	report, err := f.service().ImportJSONL(context.Background(), strings.NewReader(file), testManagerID, false)
+	if err != nil {
+		t.Fatalf("ImportJSONL error = %v", err)
+	}
+	if report.SkippedRows != 2 || report.ImportedRows != 0 || len(f.audits) != 0 || len(f.events) != 0 {
+		t.Fatalf("report = %+v, audits = %d, events = %v, want both lines skipped", report, len(f.audits), f.events)
+	}
+}
@@ -0,0 +1,209 @@
+package user_service_test
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+// importDB mencatat transaksi yang dibuka Import. Query dijalankan lewat
+// mock repository, sehingga tx hanya perlu Commit dan Rollback.
+type importDB struct {
+	database.DB
+	txs []*importTx
+}
+
+func (db *importDB) Begin(ctx context.Context) (pgx.Tx, error) {
+	tx := &importTx{}
+	db.txs = append(db.txs, tx)
+	return tx, nil
+}
+
+// outcomes mengembalikan hasil setiap transaksi secara berurutan.
+func (db *importDB) outcomes() []string {
+	outcomes := make([]string, len(db.txs))
+	for i, tx := range db.txs {
+		outcomes[i] = tx.outcome
+	}
+	return outcomes
+}
+
+type importTx struct {
+	pgx.Tx
+	// outcome "commit" atau "rollback", kosong jika belum selesai
+	outcome string
+}
+
+func (tx *importTx) Commit(ctx context.Context) error {
+	tx.outcome = "commit"
+	return nil
+}
+
+func (tx *importTx) Rollback(ctx context.Context) error {
+	// Seperti pgx, Rollback setelah Commit tidak berpengaruh
+	if tx.outcome == "" {
+		tx.outcome = "rollback"
+	}
+	return nil
+}
+
+// syntheticCSV menghasilkan file import berisi rows baris valid saat dibaca,
+// tanpa pernah menyimpan seluruh isinya di memori.
+type syntheticCSV struct {
+	rows    int
+	written int
+	pending []byte
+}
+
+func newSyntheticCSV(rows int) *syntheticCSV {
+	return &syntheticCSV{
+		rows:    rows,
+		pending: []byte("identityNumber,name,employeeImageUri,gender,departmentId\n"),
+	}
+}
+
+func (r *syntheticCSV) Read(p []byte) (int, error) {
+	if len(r.pending) == 0 {
+		if r.written == r.rows {
+			return 0, io.EOF
+		}
+		r.written++
+		r.pending = fmt.Appendf(r.pending[:0], "EMP-%07d,Budi Santoso,https://example.com/budi.png,male,%s\n", r.written, testDeptID)
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// liveHeap mengembalikan ukuran heap yang masih dipakai setelah GC.
+func liveHeap() uint64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
+
+// TestImportMemoryStaysFlat mengimpor file sintetis sekitar 20 MB dan
+// memastikan heap yang dipakai selama import tidak tumbuh mengikuti ukuran
+// file.
+func TestImportMemoryStaysFlat(t *testing.T) {
+	if testing.Short() {
+		t.Skip("imports 200k rows")
+	}
+	const rows = 200_000
+	const maxGrowth = 4 << 20
+
+	f := newEmployeeFixture()
+	f.db = &importDB{}
+	f.importConfig = &config.ImportConfig{BatchSize: 500, MaxRows: rows, Deadline: time.Minute}
+	baseline := liveHeap()
+	var peak uint64
+	f.employee.InsertBatchFunc = func(ctx context.Context, pool database.Querier, inputs []dto.EmployeePayload, managerId string) ([]bool, error) {
+		// Diukur saat batch penuh, ketika memori import paling besar
+		peak = max(peak, liveHeap())
+		inserted := make([]bool, len(inputs))
+		for i := range inserted {
+			inserted[i] = true
+		}
+		return inserted, nil
+	}
+
+	report, err := f.service().Import(context.Background(), newSyntheticCSV(rows), testManagerID)
+	if err != nil {
+		t.Fatalf("Import error = %v", err)
+	}
+	if report.ProcessedRows != rows || report.ImportedRows != rows {
+		t.Fatalf("report processed %d and imported %d rows, want %d", report.ProcessedRows, report.ImportedRows, rows)
+	}
+	if peak > baseline && peak-baseline > maxGrowth {
+		t.Fatalf("live heap grew by %d KiB during the import, want at most %d KiB", (peak-baseline)>>10, maxGrowth>>10)
+	}
+}
+
+// TestImportAbortRollsBackBatch memastikan batch yang sedang berjalan saat
+// import dihentikan di-rollback, sedangkan batch sebelumnya tetap
+// ter-commit dan dilaporkan.
+func TestImportAbortRollsBackBatch(t *testing.T) {
+	errInsert := errors.New("insert failed")
+	tests := []struct {
+		name string
+		// insert dipanggil untuk batch kedua
+		insert       func(ctx context.Context) error
+		deadline     time.Duration
+		maxRows      int
+		wantErr      error
+		wantOutcomes []string
+	}{
+		{
+			name:         "database error",
+			insert:       func(ctx context.Context) error { return errInsert },
+			wantErr:      helper.ErrInternalServer,
+			wantOutcomes: []string{"commit", "rollback"},
+		},
+		{
+			name: "deadline",
+			insert: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			deadline:     50 * time.Millisecond,
+			wantErr:      helper.ErrImportDeadline,
+			wantOutcomes: []string{"commit", "rollback"},
+		},
+		{
+			// Baris ke-5 melewati batas sebelum batch kedua penuh, sehingga
+			// transaksinya tidak pernah dibuka
+			name:         "row limit",
+			maxRows:      4,
+			wantErr:      helper.ErrImportRowLimit,
+			wantOutcomes: []string{"commit"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &importDB{}
+			f := newEmployeeFixture()
+			f.db = db
+			f.importConfig = &config.ImportConfig{
+				BatchSize: 3,
+				MaxRows:   cmp.Or(tt.maxRows, 100),
+				Deadline:  cmp.Or(tt.deadline, time.Minute),
+			}
+			f.employee.InsertBatchFunc = func(ctx context.Context, pool database.Querier, inputs []dto.EmployeePayload, managerId string) ([]bool, error) {
+				if len(db.txs) > 1 && tt.insert != nil {
+					if err := tt.insert(ctx); err != nil {
+						return nil, err
+					}
+				}
+				return []bool{true, true, true}, nil
+			}
+
+			report, err := f.service().Import(context.Background(), newSyntheticCSV(7), testManagerID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Import error = %v, want %v", err, tt.wantErr)
+			}
+			if got := db.outcomes(); !slices.Equal(got, tt.wantOutcomes) {
+				t.Fatalf("transactions = %v, want %v", got, tt.wantOutcomes)
+			}
+			if !report.Aborted || report.AbortReason != helper.GetErrorMessage(tt.wantErr) {
+				t.Errorf("report aborted = %t with %q, want %q", report.Aborted, report.AbortReason, helper.GetErrorMessage(tt.wantErr))
+			}
+			// Hanya batch pertama yang tersimpan
+			if report.ImportedRows != 3 {
+				t.Errorf("imported rows = %d, want 3", report.ImportedRows)
+			}
+		})
+	}
+}
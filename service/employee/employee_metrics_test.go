@@ -0,0 +1,168 @@
+package user_service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	clientmodel "github.com/prometheus/client_model/go"
+)
+
+// allManagers adalah label manager saat METRICS_PER_MANAGER mati.
+const allManagers = "all"
+
+func TestLifecycleCountersMoveOnSuccess(t *testing.T) {
+	f := newEmployeeFixture()
+	f.employee.CreateFunc = func(ctx context.Context, input *dto.EmployeePayload, managerId string) (dto.EmployeeResponse, error) {
+		return dto.EmployeeResponse{EmployeePayload: *input}, nil
+	}
+	f.employee.GetForUpdateFunc = func(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error) {
+		return dto.EmployeeResponse{EmployeePayload: createPayload(identityNumber)}, nil
+	}
+	f.employee.UpdateFunc = func(ctx context.Context, identityNumber string, input *dto.EmployeeUpdatePayload, managerId string) (dto.EmployeeResponse, error) {
+		return dto.EmployeeResponse{EmployeePayload: createPayload(identityNumber)}, nil
+	}
+	f.employee.DeleteFunc = func(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error) {
+		return dto.EmployeeResponse{EmployeePayload: createPayload(identityNumber)}, nil
+	}
+	f.employee.CreateManyFunc = func(ctx context.Context, pool database.Querier, inputs []dto.EmployeePayload, managerId string) ([]dto.EmployeeResponse, []error, error) {
+		// Employee kedua gagal dan tidak ikut dihitung
+		return make([]dto.EmployeeResponse, 3), []error{nil, helper.ErrConflictIdentityNumber, nil}, nil
+	}
+	s := f.service()
+	ctx := context.Background()
+
+	if _, err := s.Create(ctx, createPayload("EMP-1"), testManagerID); err != nil {
+		t.Fatalf("Create error = %v", err)
+	}
+	errs, err := s.CreateMany(ctx, []dto.EmployeePayload{createPayload("EMP-2"), createPayload("EMP-1"), createPayload("EMP-3")}, testManagerID)
+	if err != nil || errs[1] == nil {
+		t.Fatalf("CreateMany = %v, %v", errs, err)
+	}
+	name := "Budi Santoso"
+	if _, err := s.Update(ctx, "EMP-1", dto.EmployeeUpdatePayload{Name: &name}, testManagerID); err != nil {
+		t.Fatalf("Update error = %v", err)
+	}
+	if _, err := s.Delete(ctx, "EMP-1", testManagerID); err != nil {
+		t.Fatalf("Delete error = %v", err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{name: "created", got: testutil.ToFloat64(f.metrics.EmployeesCreated.WithLabelValues(allManagers)), want: 3},
+		{name: "updated", got: testutil.ToFloat64(f.metrics.EmployeesUpdated.WithLabelValues(allManagers)), want: 1},
+		{name: "deleted", got: testutil.ToFloat64(f.metrics.EmployeesDeleted.WithLabelValues(allManagers)), want: 1},
+	} {
+		if tt.got != tt.want {
+			t.Errorf("employees %s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestLifecycleCountersIgnoreFailures(t *testing.T) {
+	errWrite := errors.New("write failed")
+	f := newEmployeeFixture()
+	f.employee.CreateFunc = func(ctx context.Context, input *dto.EmployeePayload, managerId string) (dto.EmployeeResponse, error) {
+		return dto.EmployeeResponse{}, helper.ErrInvalidDepartmentId
+	}
+	f.employee.GetForUpdateFunc = func(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error) {
+		return dto.EmployeeResponse{}, helper.ErrNotFound
+	}
+	f.employee.AddVersionFunc = func(ctx context.Context, identityNumber string, action string, managerId string) error {
+		return errWrite
+	}
+	f.employee.CreateManyFunc = func(ctx context.Context, pool database.Querier, inputs []dto.EmployeePayload, managerId string) ([]dto.EmployeeResponse, []error, error) {
+		return make([]dto.EmployeeResponse, 1), []error{helper.ErrConflictIdentityNumber}, nil
+	}
+	s := f.service()
+	ctx := context.Background()
+
+	if _, err := s.Create(ctx, createPayload("EMP-1"), testManagerID); err == nil {
+		t.Fatal("Create succeeded")
+	}
+	if errs, err := s.CreateMany(ctx, []dto.EmployeePayload{createPayload("EMP-1")}, testManagerID); err != nil || errs[0] == nil {
+		t.Fatalf("CreateMany = %v, %v, want a per-employee error", errs, err)
+	}
+	name := "Budi Santoso"
+	if _, err := s.Update(ctx, "EMP-1", dto.EmployeeUpdatePayload{Name: &name}, testManagerID); err == nil {
+		t.Fatal("Update succeeded")
+	}
+	if _, err := s.Delete(ctx, "EMP-1", testManagerID); err == nil {
+		t.Fatal("Delete succeeded")
+	}
+
+	// Tidak ada series sama sekali, bukan hanya nilai 0
+	for name, count := range map[string]int{
+		"created": testutil.CollectAndCount(f.metrics.EmployeesCreated),
+		"updated": testutil.CollectAndCount(f.metrics.EmployeesUpdated),
+		"deleted": testutil.CollectAndCount(f.metrics.EmployeesDeleted),
+	} {
+		if count != 0 {
+			t.Errorf("employees %s has %d series after failed calls, want 0", name, count)
+		}
+	}
+}
+
+// stageSamples mengembalikan jumlah sample histogram durasi untuk stage.
+func stageSamples(t *testing.T, histogram *prometheus.HistogramVec, stage string) uint64 {
+	t.Helper()
+	var metric clientmodel.Metric
+	if err := histogram.WithLabelValues(stage).(prometheus.Metric).Write(&metric); err != nil {
+		t.Fatal(err)
+	}
+	return metric.GetHistogram().GetSampleCount()
+}
+
+func TestCreateConflictMetrics(t *testing.T) {
+	f := newEmployeeFixture()
+	f.employee.CreateFunc = func(ctx context.Context, input *dto.EmployeePayload, managerId string) (dto.EmployeeResponse, error) {
+		return dto.EmployeeResponse{}, helper.ErrConflictIdentityNumber
+	}
+
+	_, err := f.service().Create(context.Background(), createPayload("EMP-1"), testManagerID)
+	if !errors.Is(err, helper.ErrConflictIdentityNumber) {
+		t.Fatalf("Create error = %v, want %v", err, helper.ErrConflictIdentityNumber)
+	}
+
+	errorKey := helper.GetErrorKey(helper.ErrConflictIdentityNumber)
+	if got := testutil.ToFloat64(f.metrics.ErrorsReturned.WithLabelValues(string(helper.EmployeeServiceCreate), errorKey)); got != 1 {
+		t.Errorf("errors returned %s = %v, want 1", errorKey, got)
+	}
+	if count := testutil.CollectAndCount(f.metrics.ErrorsReturned); count != 1 {
+		t.Errorf("errors returned has %d series, want 1", count)
+	}
+	for _, stage := range []string{metrics.StageTotal, metrics.StageInsert} {
+		if got := stageSamples(t, f.metrics.EmployeeCreateDuration, stage); got != 1 {
+			t.Errorf("create duration %s samples = %d, want 1", stage, got)
+		}
+	}
+}
+
+func TestListMetrics(t *testing.T) {
+	f := newEmployeeFixture()
+	f.employee.GetAllFunc = func(ctx context.Context, input *dto.GetEmployeesRequest) ([]dto.EmployeeResponse, error) {
+		return []dto.EmployeeResponse{{EmployeePayload: createPayload("EMP-1")}}, nil
+	}
+
+	if _, err := f.service().GetAll(context.Background(), listRequest()); err != nil {
+		t.Fatalf("GetAll error = %v", err)
+	}
+
+	for _, stage := range []string{metrics.StageTotal, metrics.StageQuery} {
+		if got := stageSamples(t, f.metrics.EmployeeListDuration, stage); got != 1 {
+			t.Errorf("list duration %s samples = %d, want 1", stage, got)
+		}
+	}
+	if count := testutil.CollectAndCount(f.metrics.ErrorsReturned); count != 0 {
+		t.Errorf("errors returned has %d series after a successful list, want 0", count)
+	}
+}
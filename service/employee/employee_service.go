@@ -2,85 +2,684 @@ package user_service
 
 import (
 	"context"
-	"strings"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"time"
 
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/audit"
+	"github.com/levensspel/go-gin-template/cache"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
 	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/events"
 	"github.com/levensspel/go-gin-template/helper"
 	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/repository"
 	repositories "github.com/levensspel/go-gin-template/repository/employee"
+	fileRepository "github.com/levensspel/go-gin-template/repository/file"
+	"github.com/levensspel/go-gin-template/telemetry"
+	"github.com/levensspel/go-gin-template/validation"
 	"github.com/samber/do/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type EmployeeService interface {
-	Create(ctx context.Context, input dto.EmployeePayload, managerId string) error
-	GetAll(ctx context.Context, input dto.GetEmployeesRequest) ([]dto.EmployeePayload, error)
+	Create(ctx context.Context, input dto.EmployeePayload, managerId string) (dto.EmployeeResponse, error)
+	Update(ctx context.Context, identityNumber string, input dto.EmployeeUpdatePayload, managerId string) (dto.EmployeeResponse, error)
+	Delete(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error)
+	// Archive dan Restore mengubah status employee; employee yang sudah
+	// berstatus tersebut dikembalikan tanpa perubahan.
+	Archive(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error)
+	Restore(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error)
+	// Stats menghitung employee aktif dan yang diarsipkan.
+	Stats(ctx context.Context, managerId string) (dto.EmployeeStats, error)
+	// History mengembalikan versi employee, terbaru lebih dulu. Setiap
+	// create, update, dan delete menyimpan satu versi.
+	History(ctx context.Context, input dto.GetEmployeeHistoryRequest) ([]dto.EmployeeVersion, error)
+	// RestoreVersion menerapkan isi versi sebagai update biasa, sehingga
+	// menyimpan versi baru.
+	RestoreVersion(ctx context.Context, identityNumber string, version int, managerId string) (dto.EmployeeResponse, error)
+	GetAll(ctx context.Context, input dto.GetEmployeesRequest) ([]dto.EmployeeResponse, error)
+	// Count menghitung employee dengan filter GetAll, tanpa limit dan
+	// offset. Total halaman pertama tanpa filter di-cache bersama halamannya.
+	Count(ctx context.Context, input dto.GetEmployeesRequest) (int64, error)
+	// MissingIdentityNumbers mengembalikan input.IdentityNumbers yang tidak
+	// cocok dengan filter GetAll di halaman mana pun.
+	MissingIdentityNumbers(ctx context.Context, input dto.GetEmployeesRequest) ([]string, error)
+	Import(ctx context.Context, file io.Reader, managerId string) (dto.EmployeeImportReport, error)
+	// ImportJSONL meng-upsert employee dari JSON Lines berdasarkan
+	// identityNumber; dengan dryRun tidak ada yang disimpan.
+	ImportJSONL(ctx context.Context, file io.Reader, managerId string, dryRun bool) (dto.EmployeeImportReport, error)
+	CreateMany(ctx context.Context, inputs []dto.EmployeePayload, managerId string) ([]error, error)
+	// DryRunCreate dan DryRunCreateMany menjalankan validasi dan pengecekan
+	// database Create dan CreateMany tanpa menyimpan apa pun. Hasilnya
+	// berisi semua error per field (nil jika input valid).
+	DryRunCreate(ctx context.Context, input dto.EmployeePayload, managerId string) (validation.FieldErrors, error)
+	DryRunCreateMany(ctx context.Context, inputs []dto.EmployeePayload, managerId string) ([]validation.FieldErrors, error)
+	IsIdentityNumberAvailable(ctx context.Context, identityNumber string) (bool, error)
+	// GetAllByDepartments mengambil satu halaman employee per department
+	// dalam satu query, dipakai dataloader GraphQL.
+	GetAllByDepartments(ctx context.Context, managerId string, departmentIds []string, limit int, offset int) ([]dto.EmployeeResponse, error)
+	// Export memanggil fn untuk setiap employee yang cocok dengan filter
+	// input (limit dan offset diabaikan) langsung dari cursor database.
+	Export(ctx context.Context, input dto.GetEmployeesRequest, fn func(dto.EmployeeResponse) error) error
 }
 
 type service struct {
-	dbPool       *pgxpool.Pool
-	employeeRepo repositories.EmployeeRepository
+	dbPool       database.DB
+	employeeRepo repositories.EmployeeRepositoryInterface
+	fileRepo     fileRepository.FileRepositoryInterface
+	uow          repository.UnitOfWork
 	logger       logger.Logger
+	metrics      *metrics.Metrics
+	tracer       trace.Tracer
+	importConfig *config.ImportConfig
+	fileConfig   *config.FileConfig
+	firstPage    *cache.EmployeeFirstPage
+	// listGroup menggabungkan GetAll identik yang berjalan bersamaan
+	listGroup listCoalescer
 }
 
 func NewEmployeeService(
-	dbPool *pgxpool.Pool,
-	employeeRepo repositories.EmployeeRepository,
+	dbPool database.DB,
+	employeeRepo repositories.EmployeeRepositoryInterface,
+	fileRepo fileRepository.FileRepositoryInterface,
+	uow repository.UnitOfWork,
 	logger logger.Logger,
+	metrics *metrics.Metrics,
+	tracer trace.Tracer,
+	importConfig *config.ImportConfig,
+	fileConfig *config.FileConfig,
+	firstPage *cache.EmployeeFirstPage,
 ) EmployeeService {
 	return &service{
 		dbPool:       dbPool,
 		employeeRepo: employeeRepo,
+		fileRepo:     fileRepo,
+		uow:          uow,
 		logger:       logger,
+		metrics:      metrics,
+		tracer:       tracer,
+		importConfig: importConfig,
+		fileConfig:   fileConfig,
+		firstPage:    firstPage,
 	}
 }
 
 func NewEmployeeServiceInject(i do.Injector) (EmployeeService, error) {
-	_dbPool := do.MustInvoke[*pgxpool.Pool](i)
-	_repo := do.MustInvoke[repositories.EmployeeRepository](i)
+	_dbPool := do.MustInvoke[database.DB](i)
+	_repo := do.MustInvoke[repositories.EmployeeRepositoryInterface](i)
+	_fileRepo := do.MustInvoke[fileRepository.FileRepositoryInterface](i)
+	_uow := do.MustInvoke[repository.UnitOfWork](i)
 	_logger := do.MustInvoke[logger.LogHandler](i)
-	return NewEmployeeService(_dbPool, _repo, &_logger), nil
+	_metrics := do.MustInvoke[*metrics.Metrics](i)
+	_tracer := do.MustInvoke[trace.Tracer](i)
+	_firstPage := do.MustInvoke[*cache.EmployeeFirstPage](i)
+	return NewEmployeeService(_dbPool, _repo, _fileRepo, _uow, &_logger, _metrics, _tracer, config.LoadImportConfig(), config.LoadFileConfig(), _firstPage), nil
 }
 
-func (s *service) Create(ctx context.Context, input dto.EmployeePayload, managerId string) error {
-	pool, err := s.dbPool.Begin(ctx)
-	if err != nil {
-		return helper.ErrInternalServer
+func (s *service) Create(ctx context.Context, input dto.EmployeePayload, managerId string) (employee dto.EmployeeResponse, err error) {
+	ctx, span := s.tracer.Start(ctx, "EmployeeService.Create", trace.WithAttributes(
+		attribute.String("employee.department_id", input.DepartmentID),
+	))
+	defer func() { telemetry.End(span, err) }()
+
+	defer metrics.Since(s.metrics.EmployeeCreateDuration, metrics.StageTotal, time.Now())
+	defer func() {
+		s.metrics.CountError(helper.EmployeeServiceCreate, err)
+	}()
+
+	if err := s.checkEmployeeImage(ctx, managerId, input.EmployeeImageUri); err != nil {
+		return dto.EmployeeResponse{}, employeeWriteError(helper.QueryError(ctx, err))
 	}
-	txPool := pool.(*pgxpool.Tx)
-	defer helper.RollbackOrCommit(ctx, txPool)
 
-	err = s.employeeRepo.IsDepartmentOwnedByManager(ctx, txPool, input.DepartmentID, managerId)
+	// Validasi department dan insert berjalan dalam satu statement, audit log
+	// dan event outbox ditulis di transaksi yang sama
+	start := time.Now()
+	err = s.uow.Do(ctx, func(repos repository.Repositories) error {
+		// Ownership department dicek di statement insert yang sama
+		err := telemetry.Step(ctx, s.tracer, "employee.insert", func(ctx context.Context) (err error) {
+			employee, err = repos.Employee.Create(ctx, &input, managerId)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if err := s.referenceEmployeeImages(ctx, repos, []string{employee.EmployeeImageUri}, nil); err != nil {
+			return err
+		}
+		if err := s.recordEmployee(ctx, repos, entity.AuditActionCreate, managerId, employee.IdentityNumber, nil, employee); err != nil {
+			return err
+		}
+		if err := s.recordEmployeeVersion(ctx, repos, entity.AuditActionCreate, managerId, employee.IdentityNumber); err != nil {
+			return err
+		}
+		return s.addEmployeeEvent(ctx, repos, entity.EventEmployeeCreated, employee.IdentityNumber, events.Employee{
+			ManagerID: managerId,
+			Employee:  employee,
+		})
+	})
+	metrics.Since(s.metrics.EmployeeCreateDuration, metrics.StageInsert, start)
 	if err != nil {
-		s.logger.Error(err.Error(), helper.EmployeeServiceGet, err)
+		s.logger.Error(err.Error(), helper.EmployeeServiceCreate, err)
+		return dto.EmployeeResponse{}, employeeWriteError(helper.QueryError(ctx, err))
+	}
+	s.firstPage.Invalidate(ctx, managerId)
+	s.metrics.CountEmployeesCreated(managerId, 1)
+
+	return employee, nil
+}
+
+func (s *service) Update(
+	ctx context.Context,
+	identityNumber string,
+	input dto.EmployeeUpdatePayload,
+	managerId string,
+) (employee dto.EmployeeResponse, err error) {
+	ctx, span := s.tracer.Start(ctx, "EmployeeService.Update")
+	defer func() { telemetry.End(span, err) }()
+
+	defer func() {
+		s.metrics.CountError(helper.EmployeeServiceUpdate, err)
+	}()
+
+	if input.EmployeeImageUri != nil {
+		if err := s.checkEmployeeImage(ctx, managerId, *input.EmployeeImageUri); err != nil {
+			return dto.EmployeeResponse{}, employeeWriteError(helper.QueryError(ctx, err))
+		}
+	}
+
+	err = s.uow.Do(ctx, func(repos repository.Repositories) (err error) {
+		employee, err = s.update(ctx, repos, identityNumber, &input, managerId)
 		return err
+	})
+	if err != nil {
+		s.logger.Error(err.Error(), helper.EmployeeServiceUpdate, err)
+		return dto.EmployeeResponse{}, employeeWriteError(helper.QueryError(ctx, err))
 	}
+	s.firstPage.Invalidate(ctx, managerId)
+	s.metrics.CountEmployeeUpdated(managerId)
+
+	return employee, nil
+}
 
-	err = s.employeeRepo.IsIdentityNumberAvailable(ctx, txPool, input.IdentityNumber, managerId)
+// update mengubah employee di transaksi repos beserta rujukan gambar, audit
+// log, versi, dan event outbox-nya. Dipakai Update dan RestoreVersion.
+func (s *service) update(
+	ctx context.Context,
+	repos repository.Repositories,
+	identityNumber string,
+	input *dto.EmployeeUpdatePayload,
+	managerId string,
+) (employee dto.EmployeeResponse, err error) {
+	// GetForUpdate sekaligus mengecek employee milik manager
+	var before dto.EmployeeResponse
+	err = telemetry.Step(ctx, s.tracer, "employee.lock", func(ctx context.Context) (err error) {
+		before, err = repos.Employee.GetForUpdate(ctx, identityNumber, managerId)
+		return err
+	})
 	if err != nil {
-		s.logger.Error(err.Error(), helper.EmployeeServiceGet, err)
+		return dto.EmployeeResponse{}, err
+	}
+	err = telemetry.Step(ctx, s.tracer, "employee.update", func(ctx context.Context) (err error) {
+		employee, err = repos.Employee.Update(ctx, identityNumber, input, managerId)
 		return err
+	})
+	if err != nil {
+		return dto.EmployeeResponse{}, err
+	}
+	if employee.EmployeeImageUri != before.EmployeeImageUri {
+		err := s.referenceEmployeeImages(ctx, repos, []string{employee.EmployeeImageUri}, []string{before.EmployeeImageUri})
+		if err != nil {
+			return dto.EmployeeResponse{}, err
+		}
+	}
+	if err := s.recordEmployee(ctx, repos, entity.AuditActionUpdate, managerId, identityNumber, before, employee); err != nil {
+		return dto.EmployeeResponse{}, err
+	}
+	if err := s.recordEmployeeVersion(ctx, repos, entity.AuditActionUpdate, managerId, employee.IdentityNumber); err != nil {
+		return dto.EmployeeResponse{}, err
+	}
+	event := events.Employee{ManagerID: managerId, Employee: employee}
+	if employee.IdentityNumber != identityNumber {
+		event.PreviousIdentityNumber = identityNumber
+	}
+	return employee, s.addEmployeeEvent(ctx, repos, entity.EventEmployeeUpdated, identityNumber, event)
+}
+
+func (s *service) Archive(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error) {
+	return s.setStatus(ctx, identityNumber, dto.EmployeeStatusArchived, managerId)
+}
+
+func (s *service) Restore(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error) {
+	return s.setStatus(ctx, identityNumber, dto.EmployeeStatusActive, managerId)
+}
+
+// setStatus dicatat di audit log dan dikirim sebagai employee.updated,
+// sama seperti Update.
+func (s *service) setStatus(ctx context.Context, identityNumber string, status string, managerId string) (employee dto.EmployeeResponse, err error) {
+	ctx, span := s.tracer.Start(ctx, "EmployeeService.SetStatus", trace.WithAttributes(
+		attribute.String("employee.status", status),
+	))
+	defer func() { telemetry.End(span, err) }()
+
+	defer func() {
+		s.metrics.CountError(helper.EmployeeServiceSetStatus, err)
+	}()
+
+	err = s.uow.Do(ctx, func(repos repository.Repositories) error {
+		before, err := repos.Employee.GetForUpdate(ctx, identityNumber, managerId)
+		if err != nil {
+			return err
+		}
+		if before.Status == status {
+			employee = before
+			return nil
+		}
+		employee, err = repos.Employee.SetStatus(ctx, identityNumber, status, managerId)
+		if err != nil {
+			return err
+		}
+		if err := s.recordEmployee(ctx, repos, entity.AuditActionUpdate, managerId, identityNumber, before, employee); err != nil {
+			return err
+		}
+		if err := s.recordEmployeeVersion(ctx, repos, entity.AuditActionUpdate, managerId, identityNumber); err != nil {
+			return err
+		}
+		event := events.Employee{ManagerID: managerId, Employee: employee}
+		return s.addEmployeeEvent(ctx, repos, entity.EventEmployeeUpdated, identityNumber, event)
+	})
+	if err != nil {
+		s.logger.Error(err.Error(), helper.EmployeeServiceSetStatus, err)
+		return dto.EmployeeResponse{}, employeeWriteError(helper.QueryError(ctx, err))
 	}
+	s.firstPage.Invalidate(ctx, managerId)
+	return employee, nil
+}
 
-	err = s.employeeRepo.Insert(ctx, txPool, &input, managerId)
+func (s *service) Stats(ctx context.Context, managerId string) (stats dto.EmployeeStats, err error) {
+	ctx, span := s.tracer.Start(ctx, "EmployeeService.Stats")
+	defer func() { telemetry.End(span, err) }()
+
+	departments, err := s.employeeRepo.Stats(ctx, managerId)
 	if err != nil {
-		s.logger.Error(err.Error(), helper.EmployeeServiceGet, err)
-		if strings.Contains(err.Error(), "23505") {
-			return helper.ErrConflict
+		s.metrics.CountError(helper.EmployeeServiceStats, err)
+		s.logger.Error(err.Error(), helper.EmployeeServiceStats, err)
+		return dto.EmployeeStats{}, err
+	}
+	stats.Departments = departments
+	if stats.Departments == nil {
+		stats.Departments = []dto.DepartmentEmployeeStats{}
+	}
+	for _, department := range departments {
+		stats.Active += department.Active
+		stats.Archived += department.Archived
+	}
+	return stats, nil
+}
+
+// Delete menghapus employee milik manager dan mengembalikan data terakhirnya.
+func (s *service) Delete(ctx context.Context, identityNumber string, managerId string) (employee dto.EmployeeResponse, err error) {
+	ctx, span := s.tracer.Start(ctx, "EmployeeService.Delete")
+	defer func() { telemetry.End(span, err) }()
+
+	defer func() {
+		s.metrics.CountError(helper.EmployeeServiceDelete, err)
+	}()
+
+	err = s.uow.Do(ctx, func(repos repository.Repositories) error {
+		// Versi delete berisi data terakhir, jadi disimpan sebelum employee
+		// dihapus; employee yang tidak ditemukan berhenti di sini
+		if err := s.recordEmployeeVersion(ctx, repos, entity.AuditActionDelete, managerId, identityNumber); err != nil {
+			return err
+		}
+		err := telemetry.Step(ctx, s.tracer, "employee.delete", func(ctx context.Context) (err error) {
+			employee, err = repos.Employee.Delete(ctx, identityNumber, managerId)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if err := s.referenceEmployeeImages(ctx, repos, nil, []string{employee.EmployeeImageUri}); err != nil {
+			return err
 		}
+		if err := s.recordEmployee(ctx, repos, entity.AuditActionDelete, managerId, identityNumber, employee, nil); err != nil {
+			return err
+		}
+		return s.addEmployeeEvent(ctx, repos, entity.EventEmployeeDeleted, identityNumber, events.Employee{
+			ManagerID: managerId,
+			Employee:  employee,
+		})
+	})
+	if err != nil {
+		s.logger.Error(err.Error(), helper.EmployeeServiceDelete, err)
+		return dto.EmployeeResponse{}, employeeWriteError(helper.QueryError(ctx, err))
+	}
+	s.firstPage.Invalidate(ctx, managerId)
+	s.metrics.CountEmployeeDeleted(managerId)
+
+	return employee, nil
+}
+
+// addEmployeeEvent menulis event employee ke outbox. Aggregate id adalah
+// identityNumber sebelum perubahan, agar urutan event satu employee terjaga.
+func (s *service) addEmployeeEvent(ctx context.Context, repos repository.Repositories, eventType string, identityNumber string, event events.Employee) error {
+	return telemetry.Step(ctx, s.tracer, "outbox.add", func(ctx context.Context) error {
+		return repos.Outbox.Add(ctx, eventType, entity.AggregateEmployee, identityNumber, event)
+	}, attribute.String("outbox.event", eventType))
+}
+
+// recordEmployee mencatat perubahan employee di audit log. before nil untuk
+// create dan after nil untuk delete.
+func (s *service) recordEmployee(ctx context.Context, repos repository.Repositories, action, managerId, identityNumber string, before, after any) error {
+	return telemetry.Step(ctx, s.tracer, "audit.record", func(ctx context.Context) error {
+		return audit.Record(ctx, repos.Audit, managerId, action, entity.AuditEntityEmployee, identityNumber, before, after)
+	}, attribute.String("audit.action", action))
+}
+
+// recordEmployeeVersion menyimpan data employee identityNumber saat ini
+// sebagai versi baru di transaksi repos.
+func (s *service) recordEmployeeVersion(ctx context.Context, repos repository.Repositories, action, managerId, identityNumber string) error {
+	return telemetry.Step(ctx, s.tracer, "employee.version", func(ctx context.Context) error {
+		return repos.Employee.AddVersion(ctx, identityNumber, action, managerId)
+	}, attribute.String("audit.action", action))
+}
 
+// employeeWriteError meneruskan error yang punya status code sendiri dan
+// menyembunyikan error database lainnya sebagai ErrInternalServer.
+func employeeWriteError(err error) error {
+	switch {
+	case errors.Is(err, helper.ErrInvalidDepartmentId),
+		errors.Is(err, helper.ErrInvalidEmployeeImage),
+		errors.Is(err, helper.ErrConflictIdentityNumber),
+		errors.Is(err, helper.ErrNotFound),
+		errors.Is(err, helper.ErrQueryTimeout),
+		errors.Is(err, helper.ErrServiceUnavailable):
 		return err
+	default:
+		return helper.ErrInternalServer
 	}
+}
+
+// IsIdentityNumberAvailable hanya untuk pengecekan eksplisit dari client,
+// Create tidak bergantung pada hasilnya.
+func (s *service) IsIdentityNumberAvailable(ctx context.Context, identityNumber string) (available bool, err error) {
+	ctx, span := s.tracer.Start(ctx, "EmployeeService.IsIdentityNumberAvailable")
+	defer func() {
+		span.SetAttributes(attribute.Bool("employee.identity_number_available", available))
+		telemetry.End(span, err)
+	}()
+
+	err = s.employeeRepo.IsIdentityNumberAvailable(ctx, identityNumber)
+	if errors.Is(err, helper.ErrConflictIdentityNumber) {
+		return false, nil
+	}
+	if err != nil {
+		s.logger.Error(err.Error(), helper.EmployeeServiceIdentityNumber, err)
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *service) GetAllByDepartments(ctx context.Context, managerId string, departmentIds []string, limit int, offset int) (employees []dto.EmployeeResponse, err error) {
+	ctx, span := s.tracer.Start(ctx, "EmployeeService.GetAllByDepartments", trace.WithAttributes(
+		attribute.Int("employee.departments", len(departmentIds)),
+		attribute.Int("employee.limit", limit),
+		attribute.Int("employee.offset", offset),
+	))
+	defer func() {
+		span.SetAttributes(attribute.Int("employee.rows", len(employees)))
+		telemetry.End(span, err)
+	}()
+
+	employees, err = s.employeeRepo.GetAllByDepartments(ctx, departmentIds, managerId, limit, offset)
+	if err != nil {
+		s.metrics.CountError(helper.EmployeeServiceByDepartments, err)
+		s.logger.Error(err.Error(), helper.EmployeeServiceByDepartments, err)
+		return nil, err
+	}
+	return employees, nil
+}
+
+func (s *service) Export(ctx context.Context, input dto.GetEmployeesRequest, fn func(dto.EmployeeResponse) error) (err error) {
+	ctx, span := s.tracer.Start(ctx, "EmployeeService.Export")
+	rows := 0
+	defer func() {
+		span.SetAttributes(attribute.Int("employee.rows", rows))
+		telemetry.End(span, err)
+	}()
+
+	var fnErr error
+	err = s.employeeRepo.Export(ctx, &input, func(employee dto.EmployeeResponse) error {
+		rows++
+		fnErr = fn(employee)
+		return fnErr
+	})
+	// Error dari fn (mis. gagal menulis response) dan client yang memutus
+	// koneksi di tengah export bukan error server
+	if err != nil && err != fnErr && ctx.Err() == nil {
+		s.metrics.CountError(helper.EmployeeServiceExport, err)
+		s.logger.Error(err.Error(), helper.EmployeeServiceExport, err)
+	}
+	return err
+}
+
+// CreateMany membuat beberapa employee dalam satu transaksi dan satu round
+// trip. Kegagalan per employee dikembalikan di slice tanpa membatalkan
+// employee lain.
+func (s *service) CreateMany(ctx context.Context, inputs []dto.EmployeePayload, managerId string) (errs []error, err error) {
+	ctx, span := s.tracer.Start(ctx, "EmployeeService.CreateMany", trace.WithAttributes(
+		attribute.Int("employee.count", len(inputs)),
+	))
+	defer func() { telemetry.End(span, err) }()
+
+	defer func() {
+		s.metrics.CountError(helper.EmployeeServiceCreateMany, err)
+	}()
+
+	uris := make([]string, len(inputs))
+	for i, input := range inputs {
+		uris[i] = input.EmployeeImageUri
+	}
+	errs, err = s.checkEmployeeImages(ctx, managerId, uris)
+	if err != nil {
+		s.logger.Error(err.Error(), helper.EmployeeServiceCreateMany, err)
+		return nil, employeeWriteError(helper.QueryError(ctx, err))
+	}
+	// Hanya employee dengan gambar yang valid yang di-insert; accepted[j]
+	// adalah inputs[indexes[j]]
+	accepted := make([]dto.EmployeePayload, 0, len(inputs))
+	indexes := make([]int, 0, len(inputs))
+	for i, imageErr := range errs {
+		if imageErr == nil {
+			accepted = append(accepted, inputs[i])
+			indexes = append(indexes, i)
+		}
+	}
+	if len(accepted) == 0 {
+		return errs, nil
+	}
+
+	err = s.uow.Do(ctx, func(repos repository.Repositories) error {
+		var employees []dto.EmployeeResponse
+		var createErrs []error
+		err := telemetry.Step(ctx, s.tracer, "employee.insert", func(ctx context.Context) (err error) {
+			employees, createErrs, err = repos.Employee.CreateMany(ctx, repos.Tx, accepted, managerId)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		created := make([]string, 0, len(accepted))
+		for j, createErr := range createErrs {
+			if createErr == nil {
+				created = append(created, accepted[j].EmployeeImageUri)
+			}
+		}
+		if err := s.referenceEmployeeImages(ctx, repos, created, nil); err != nil {
+			return err
+		}
+		for j, createErr := range createErrs {
+			errs[indexes[j]] = createErr
+			if createErr != nil {
+				continue
+			}
+			// Audit log dan event memakai baris yang tersimpan, sama seperti
+			// Create
+			employee := employees[j]
+			if err := s.recordEmployee(ctx, repos, entity.AuditActionCreate, managerId, employee.IdentityNumber, nil, employee); err != nil {
+				return err
+			}
+			if err := s.recordEmployeeVersion(ctx, repos, entity.AuditActionCreate, managerId, employee.IdentityNumber); err != nil {
+				return err
+			}
+			err := s.addEmployeeEvent(ctx, repos, entity.EventEmployeeCreated, employee.IdentityNumber, events.Employee{
+				ManagerID: managerId,
+				Employee:  employee,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Error(err.Error(), helper.EmployeeServiceCreateMany, err)
+		return nil, employeeWriteError(helper.QueryError(ctx, err))
+	}
+	s.firstPage.Invalidate(ctx, managerId)
+	created := 0
+	for _, createErr := range errs {
+		if createErr == nil {
+			created++
+		}
+	}
+	s.metrics.CountEmployeesCreated(managerId, created)
 
-	return nil
+	return errs, nil
 }
 
-func (s *service) GetAll(ctx context.Context, input dto.GetEmployeesRequest) ([]dto.EmployeePayload, error) {
-	employees, err := s.employeeRepo.GetAll(ctx, &input)
+func (s *service) GetAll(ctx context.Context, input dto.GetEmployeesRequest) (employees []dto.EmployeeResponse, err error) {
+	ctx, span := s.tracer.Start(ctx, "EmployeeService.GetAll", trace.WithAttributes(
+		attribute.Int("employee.limit", input.Limit),
+		attribute.Int("employee.offset", input.Offset),
+		attribute.String("employee.department_id", input.DepartmentID),
+		attribute.String("employee.sort_by", input.SortBy),
+	))
+	defer func() {
+		span.SetAttributes(attribute.Int("employee.rows", len(employees)))
+		telemetry.End(span, err)
+	}()
+
+	defer metrics.Since(s.metrics.EmployeeListDuration, metrics.StageTotal, time.Now())
+
+	// Dijalankan setelah cache dan coalescing, sehingga keduanya tetap
+	// menyimpan daftar tanpa expand
+	if input.Expand == dto.ExpandImages {
+		defer func() {
+			if err == nil {
+				employees = s.expandImages(ctx, employees)
+			}
+		}()
+	}
+
+	firstPage := cache.IsEmployeeFirstPage(input)
+	if firstPage {
+		cached, ok := s.firstPage.Get(ctx, input.ManagerID)
+		span.SetAttributes(attribute.Bool("cache.hit", ok))
+		if ok {
+			return cached, nil
+		}
+	}
+
+	start := time.Now()
+	err = telemetry.Step(ctx, s.tracer, "employee.query", func(ctx context.Context) (err error) {
+		employees, err = s.getAllCoalesced(ctx, input)
+		return err
+	})
+	metrics.Since(s.metrics.EmployeeListDuration, metrics.StageQuery, start)
 	if err != nil {
+		s.metrics.CountError(helper.EmployeeServiceGet, err)
 		s.logger.Error(err.Error(), helper.EmployeeServiceGet, input)
-		return []dto.EmployeePayload{}, err
+		return []dto.EmployeeResponse{}, err
 	}
 
+	if firstPage {
+		s.firstPage.Set(ctx, input.ManagerID, employees)
+	}
 	return employees, nil
 }
+
+func (s *service) Count(ctx context.Context, input dto.GetEmployeesRequest) (count int64, err error) {
+	ctx, span := s.tracer.Start(ctx, "EmployeeService.Count", trace.WithAttributes(
+		attribute.String("employee.department_id", input.DepartmentID),
+	))
+	defer func() {
+		span.SetAttributes(attribute.Int64("employee.count", count))
+		telemetry.End(span, err)
+	}()
+
+	firstPage := cache.IsEmployeeFirstPage(input)
+	if firstPage {
+		if total, ok := s.firstPage.GetTotal(ctx, input.ManagerID); ok {
+			return total, nil
+		}
+	}
+	count, err = s.employeeRepo.Count(ctx, &input)
+	if err != nil {
+		s.metrics.CountError(helper.EmployeeServiceCount, err)
+		s.logger.Error(err.Error(), helper.EmployeeServiceCount, input)
+		return 0, err
+	}
+	if firstPage {
+		s.firstPage.SetTotal(ctx, input.ManagerID, count)
+	}
+	return count, nil
+}
+
+func (s *service) MissingIdentityNumbers(ctx context.Context, input dto.GetEmployeesRequest) (missing []string, err error) {
+	ctx, span := s.tracer.Start(ctx, "EmployeeService.MissingIdentityNumbers", trace.WithAttributes(
+		attribute.Int("employee.identity_numbers", len(input.IdentityNumbers)),
+	))
+	defer func() { telemetry.End(span, err) }()
+
+	found, err := s.employeeRepo.MatchingIdentityNumbers(ctx, &input)
+	if err != nil {
+		s.metrics.CountError(helper.EmployeeServiceMissing, err)
+		s.logger.Error(err.Error(), helper.EmployeeServiceMissing, input)
+		return nil, err
+	}
+	missing = []string{}
+	for _, identityNumber := range input.IdentityNumbers {
+		if !slices.Contains(found, identityNumber) && !slices.Contains(missing, identityNumber) {
+			missing = append(missing, identityNumber)
+		}
+	}
+	return missing, nil
+}
+
+// getAllCoalesced menjalankan satu query untuk request GetAll identik yang
+// datang bersamaan. Query bersama baru dibatalkan jika semua pemanggilnya
+// batal, lihat listCoalescer. Setiap pemanggil menerima salinan slice,
+// sehingga hasil bersama tidak bisa diubah oleh pemanggil lain.
+func (s *service) getAllCoalesced(ctx context.Context, input dto.GetEmployeesRequest) ([]dto.EmployeeResponse, error) {
+	// Urutan field struct tetap, sehingga JSON-nya kanonis. ManagerID
+	// termasuk di dalamnya.
+	key, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	employees, err := s.listGroup.do(ctx, string(key), func(ctx context.Context) ([]dto.EmployeeResponse, error) {
+		return s.employeeRepo.GetAll(ctx, &input)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return slices.Clone(employees), nil
+}
@@ -0,0 +1,563 @@
+package user_service_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/levensspel/go-gin-template/cache"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/events"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/mocks"
+	"github.com/levensspel/go-gin-template/repository"
+	service "github.com/levensspel/go-gin-template/service/employee"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const (
+	testManagerID = "0b7c2d2e-4f4a-4b8e-9c59-2f1d8f6a3e10"
+	testDeptID    = "1c8d3e3f-5a5b-4c9f-8d6a-3a2e9a7b4f21"
+)
+
+// employeeFixture menyusun EmployeeService di atas mocks dan mencatat
+// write di dalam unit of work.
+type employeeFixture struct {
+	// db nil kecuali test import CSV mengisinya
+	db         database.DB
+	employee   *mocks.EmployeeRepository
+	file       *mocks.FileRepository
+	uow        *mocks.UnitOfWork
+	fileConfig *config.FileConfig
+	// importConfig kosong kecuali test import mengisinya
+	importConfig *config.ImportConfig
+	metrics      *metrics.Metrics
+	// tracer no-op kecuali test span menggantinya dengan recorder
+	tracer trace.Tracer
+	// firstPage nil berarti cache halaman pertama tanpa Redis (selalu miss)
+	firstPage *cache.EmployeeFirstPage
+	audits    []entity.AuditLog
+	versions  []string
+	events    []string
+	// payloads adalah payload setiap event di events
+	payloads []any
+}
+
+func newEmployeeFixture() *employeeFixture {
+	f := &employeeFixture{
+		fileConfig:   &config.FileConfig{},
+		importConfig: &config.ImportConfig{},
+		metrics:      metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{}),
+		tracer:       noop.NewTracerProvider().Tracer(""),
+	}
+	f.employee = &mocks.EmployeeRepository{
+		AddVersionFunc: func(ctx context.Context, identityNumber string, action string, managerId string) error {
+			f.versions = append(f.versions, action+":"+identityNumber)
+			return nil
+		},
+	}
+	f.file = &mocks.FileRepository{
+		AdjustReferencesFunc: func(ctx context.Context, db database.Querier, added []string, removed []string) ([]string, error) {
+			return added, nil
+		},
+	}
+	f.uow = &mocks.UnitOfWork{Repositories: repository.Repositories{
+		Employee: f.employee,
+		File:     f.file,
+		Audit: &mocks.AuditRepository{
+			AddFunc: func(ctx context.Context, log entity.AuditLog) error {
+				f.audits = append(f.audits, log)
+				return nil
+			},
+		},
+		Outbox: &mocks.OutboxRepository{
+			AddFunc: func(ctx context.Context, eventType, aggregateType, aggregateId string, payload any) error {
+				f.events = append(f.events, eventType+":"+aggregateId)
+				f.payloads = append(f.payloads, payload)
+				return nil
+			},
+		},
+	}}
+	return f
+}
+
+func (f *employeeFixture) service() service.EmployeeService {
+	firstPage := f.firstPage
+	if firstPage == nil {
+		firstPage = cache.NewEmployeeFirstPage(nil, 0, mocks.Logger{}, f.metrics)
+	}
+	return service.NewEmployeeService(
+		f.db,
+		f.employee,
+		f.file,
+		f.uow,
+		mocks.Logger{},
+		f.metrics,
+		f.tracer,
+		f.importConfig,
+		f.fileConfig,
+		firstPage,
+	)
+}
+
+func createPayload(identityNumber string) dto.EmployeePayload {
+	return dto.EmployeePayload{
+		IdentityNumber:   identityNumber,
+		Name:             "Budi",
+		EmployeeImageUri: "https://example.com/budi.png",
+		Gender:           "male",
+		DepartmentID:     testDeptID,
+	}
+}
+
+func TestCreate(t *testing.T) {
+	f := newEmployeeFixture()
+	f.employee.CreateFunc = func(ctx context.Context, input *dto.EmployeePayload, managerId string) (dto.EmployeeResponse, error) {
+		if managerId != testManagerID {
+			t.Errorf("managerId = %s, want %s", managerId, testManagerID)
+		}
+		return dto.EmployeeResponse{EmployeePayload: *input}, nil
+	}
+
+	employee, err := f.service().Create(context.Background(), createPayload("EMP-1"), testManagerID)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if employee.IdentityNumber != "EMP-1" {
+		t.Fatalf("Create() = %+v", employee)
+	}
+	if !f.uow.Committed {
+		t.Fatal("unit of work was not committed")
+	}
+	if len(f.audits) != 1 || f.audits[0].Action != entity.AuditActionCreate {
+		t.Errorf("audits = %+v, want one create", f.audits)
+	}
+	if want := []string{"create:EMP-1"}; !slices.Equal(f.versions, want) {
+		t.Errorf("versions = %v, want %v", f.versions, want)
+	}
+	if want := []string{entity.EventEmployeeCreated + ":EMP-1"}; !slices.Equal(f.events, want) {
+		t.Errorf("events = %v, want %v", f.events, want)
+	}
+}
+
+// TestCreateManyRecordsStoredEmployees memastikan audit log dan event
+// employee.created dari CreateMany memakai baris yang tersimpan, sehingga
+// bentuknya sama dengan event Create (termasuk createdAt, updatedAt, dan
+// status).
+func TestCreateManyRecordsStoredEmployees(t *testing.T) {
+	f := newEmployeeFixture()
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	f.employee.CreateManyFunc = func(ctx context.Context, pool database.Querier, inputs []dto.EmployeePayload, managerId string) ([]dto.EmployeeResponse, []error, error) {
+		employees := make([]dto.EmployeeResponse, len(inputs))
+		employees[0] = dto.EmployeeResponse{EmployeePayload: inputs[0], CreatedAt: createdAt, UpdatedAt: createdAt, Status: dto.EmployeeStatusActive}
+		return employees, []error{nil, helper.ErrConflictIdentityNumber}, nil
+	}
+
+	errs, err := f.service().CreateMany(context.Background(), []dto.EmployeePayload{createPayload("EMP-1"), createPayload("EMP-2")}, testManagerID)
+	if err != nil || errs[0] != nil || !errors.Is(errs[1], helper.ErrConflictIdentityNumber) {
+		t.Fatalf("CreateMany() = %v, %v, want only EMP-2 rejected", errs, err)
+	}
+	if want := []string{entity.EventEmployeeCreated + ":EMP-1"}; !slices.Equal(f.events, want) {
+		t.Fatalf("events = %v, want %v", f.events, want)
+	}
+	want := events.Employee{
+		ManagerID: testManagerID,
+		Employee:  dto.EmployeeResponse{EmployeePayload: createPayload("EMP-1"), CreatedAt: createdAt, UpdatedAt: createdAt, Status: dto.EmployeeStatusActive},
+	}
+	if got, ok := f.payloads[0].(events.Employee); !ok || !reflect.DeepEqual(got, want) {
+		t.Fatalf("event payload = %+v, want %+v", f.payloads[0], want)
+	}
+	// Audit log mencatat field yang berubah tanpa timestamp; status hanya
+	// ada di baris yang tersimpan
+	if len(f.audits) != 1 || !bytes.Contains(f.audits[0].Changes, []byte(`"status":{"before":null,"after":"active"}`)) {
+		t.Fatalf("audits = %+v, want one create with the stored status", f.audits)
+	}
+}
+
+func TestCreateErrorMapping(t *testing.T) {
+	errDatabase := errors.New("syntax error at or near")
+	tests := []struct {
+		name    string
+		repoErr error
+		want    error
+	}{
+		{name: "invalid department", repoErr: helper.ErrInvalidDepartmentId, want: helper.ErrInvalidDepartmentId},
+		{name: "wrapped invalid department", repoErr: fmt.Errorf("employee.create: %w", helper.ErrInvalidDepartmentId), want: helper.ErrInvalidDepartmentId},
+		{name: "identity number conflict", repoErr: helper.ErrConflictIdentityNumber, want: helper.ErrConflictIdentityNumber},
+		// database.GuardedPool menandai ctx.Err() dari pgxpool saat menunggu koneksi
+		{name: "pool acquire timeout", repoErr: &helper.AcquireTimeoutError{Err: context.DeadlineExceeded}, want: helper.ErrServiceUnavailable},
+		{name: "query timeout", repoErr: fmt.Errorf("timeout: %w", context.DeadlineExceeded), want: helper.ErrQueryTimeout},
+		{name: "connection failure", repoErr: &pgconn.ConnectError{Config: &pgconn.Config{Host: "db"}}, want: helper.ErrServiceUnavailable},
+		{name: "too many connections", repoErr: &pgconn.PgError{Code: "53300"}, want: helper.ErrServiceUnavailable},
+		{name: "unique violation from another manager", repoErr: &pgconn.PgError{Code: "23505"}, want: helper.ErrInternalServer},
+		{name: "other database error", repoErr: errDatabase, want: helper.ErrInternalServer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newEmployeeFixture()
+			f.employee.CreateFunc = func(ctx context.Context, input *dto.EmployeePayload, managerId string) (dto.EmployeeResponse, error) {
+				return dto.EmployeeResponse{}, tt.repoErr
+			}
+
+			_, err := f.service().Create(context.Background(), createPayload("EMP-1"), testManagerID)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Create() error = %v, want %v", err, tt.want)
+			}
+			if errors.Is(err, errDatabase) {
+				t.Fatal("database error leaked to the caller")
+			}
+			if f.uow.Committed || len(f.events) != 0 {
+				t.Fatalf("committed = %v, events = %v, want nothing written", f.uow.Committed, f.events)
+			}
+		})
+	}
+}
+
+func TestCreateRollsBackWhenHistoryFails(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		fail func(f *employeeFixture)
+	}{
+		{name: "audit", fail: func(f *employeeFixture) {
+			f.uow.Repositories.Audit = &mocks.AuditRepository{}
+		}},
+		{name: "version", fail: func(f *employeeFixture) {
+			f.employee.AddVersionFunc = nil
+		}},
+		{name: "outbox", fail: func(f *employeeFixture) {
+			f.uow.Repositories.Outbox = &mocks.OutboxRepository{}
+		}},
+		{name: "file references", fail: func(f *employeeFixture) {
+			f.file.AdjustReferencesFunc = nil
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newEmployeeFixture()
+			f.employee.CreateFunc = func(ctx context.Context, input *dto.EmployeePayload, managerId string) (dto.EmployeeResponse, error) {
+				return dto.EmployeeResponse{EmployeePayload: *input}, nil
+			}
+			tt.fail(f)
+
+			_, err := f.service().Create(context.Background(), createPayload("EMP-1"), testManagerID)
+			if !errors.Is(err, helper.ErrInternalServer) {
+				t.Fatalf("Create() error = %v, want %v", err, helper.ErrInternalServer)
+			}
+			if f.uow.Committed {
+				t.Fatal("unit of work was committed")
+			}
+		})
+	}
+}
+
+func TestCreateRestrictedEmployeeImage(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []entity.File
+		err   error
+		want  error
+	}{
+		{
+			name:  "uploaded by the manager",
+			files: []entity.File{{FileURI: "https://example.com/budi.png", ManagerId: testManagerID, Purpose: entity.FilePurposeEmployee}},
+		},
+		{
+			name:  "uploaded by another manager",
+			files: []entity.File{{FileURI: "https://example.com/budi.png", ManagerId: "other", Purpose: entity.FilePurposeEmployee}},
+			want:  helper.ErrInvalidEmployeeImage,
+		},
+		{
+			name:  "uploaded as avatar",
+			files: []entity.File{{FileURI: "https://example.com/budi.png", ManagerId: testManagerID, Purpose: entity.FilePurposeProfile}},
+			want:  helper.ErrInvalidEmployeeImage,
+		},
+		{name: "not uploaded", want: helper.ErrInvalidEmployeeImage},
+		{name: "lookup fails", err: errors.New("lookup failed"), want: helper.ErrInternalServer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newEmployeeFixture()
+			f.fileConfig.RestrictEmployeeImages = true
+			f.file.FindByURIsFunc = func(ctx context.Context, uris []string) ([]entity.File, error) {
+				return tt.files, tt.err
+			}
+			var created bool
+			f.employee.CreateFunc = func(ctx context.Context, input *dto.EmployeePayload, managerId string) (dto.EmployeeResponse, error) {
+				created = true
+				return dto.EmployeeResponse{EmployeePayload: *input}, nil
+			}
+
+			_, err := f.service().Create(context.Background(), createPayload("EMP-1"), testManagerID)
+			if tt.want == nil {
+				if err != nil || !created {
+					t.Fatalf("Create() error = %v, created = %v", err, created)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Create() error = %v, want %v", err, tt.want)
+			}
+			if created {
+				t.Fatal("employee was created")
+			}
+		})
+	}
+}
+
+func listRequest() dto.GetEmployeesRequest {
+	return dto.GetEmployeesRequest{
+		ManagerID: testManagerID,
+		Limit:     dto.DefaultLimit,
+		Offset:    dto.DefaultOffset,
+		Status:    dto.EmployeeStatusActive,
+	}
+}
+
+func TestGetAll(t *testing.T) {
+	f := newEmployeeFixture()
+	rows := []dto.EmployeeResponse{{EmployeePayload: dto.EmployeePayload{IdentityNumber: "EMP-1"}}}
+	f.employee.GetAllFunc = func(ctx context.Context, input *dto.GetEmployeesRequest) ([]dto.EmployeeResponse, error) {
+		if input.ManagerID != testManagerID || input.Gender != "female" {
+			t.Errorf("input = %+v", input)
+		}
+		return rows, nil
+	}
+
+	input := listRequest()
+	input.Gender = "female"
+	employees, err := f.service().GetAll(context.Background(), input)
+	if err != nil {
+		t.Fatalf("GetAll() error = %v", err)
+	}
+	if len(employees) != 1 || employees[0].IdentityNumber != "EMP-1" {
+		t.Fatalf("GetAll() = %+v", employees)
+	}
+	// Hasil disalin, sehingga pemanggil tidak mengubah slice bersama
+	employees[0].IdentityNumber = "CHANGED"
+	if rows[0].IdentityNumber != "EMP-1" {
+		t.Fatal("GetAll() returned the repository slice")
+	}
+}
+
+func TestGetAllErrors(t *testing.T) {
+	for _, repoErr := range []error{
+		helper.ErrQueryTimeout,
+		helper.ErrServiceUnavailable,
+		errors.New("syntax error"),
+	} {
+		t.Run(repoErr.Error(), func(t *testing.T) {
+			f := newEmployeeFixture()
+			f.employee.GetAllFunc = func(ctx context.Context, input *dto.GetEmployeesRequest) ([]dto.EmployeeResponse, error) {
+				return nil, repoErr
+			}
+
+			employees, err := f.service().GetAll(context.Background(), listRequest())
+			// helper.FromError di handler yang memetakan error ke status
+			if !errors.Is(err, repoErr) {
+				t.Fatalf("GetAll() error = %v, want %v", err, repoErr)
+			}
+			if employees == nil || len(employees) != 0 {
+				t.Fatalf("GetAll() = %#v, want an empty slice", employees)
+			}
+		})
+	}
+}
+
+// TestUpdateAuditsOnlyChangedFields memastikan audit log PATCH hanya berisi
+// field yang diubah, tanpa updatedAt.
+func TestUpdateAuditsOnlyChangedFields(t *testing.T) {
+	f := newEmployeeFixture()
+	before := dto.EmployeeResponse{
+		EmployeePayload: createPayload("EMP-1"),
+		CreatedAt:       time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt:       time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Status:          dto.EmployeeStatusActive,
+	}
+	f.employee.GetForUpdateFunc = func(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error) {
+		return before, nil
+	}
+	f.employee.UpdateFunc = func(ctx context.Context, identityNumber string, input *dto.EmployeeUpdatePayload, managerId string) (dto.EmployeeResponse, error) {
+		after := before
+		after.Name = *input.Name
+		after.UpdatedAt = before.UpdatedAt.Add(time.Hour)
+		return after, nil
+	}
+
+	name := "Budi Santoso"
+	if _, err := f.service().Update(context.Background(), "EMP-1", dto.EmployeeUpdatePayload{Name: &name}, testManagerID); err != nil {
+		t.Fatalf("Update error = %v", err)
+	}
+	if len(f.audits) != 1 {
+		t.Fatalf("Update wrote %d audit logs, want 1", len(f.audits))
+	}
+	log := f.audits[0]
+	if log.Action != entity.AuditActionUpdate || log.EntityType != entity.AuditEntityEmployee || log.EntityId != "EMP-1" || log.ActorId != testManagerID {
+		t.Fatalf("audit log = %+v", log)
+	}
+	var changes map[string]dto.AuditChange
+	if err := json.Unmarshal(log.Changes, &changes); err != nil {
+		t.Fatalf("unmarshal changes: %v", err)
+	}
+	want := map[string]dto.AuditChange{"name": {Before: "Budi", After: "Budi Santoso"}}
+	if !reflect.DeepEqual(changes, want) {
+		t.Fatalf("audit changes = %+v, want %+v", changes, want)
+	}
+	if !slices.Equal(f.versions, []string{"update:EMP-1"}) || !slices.Equal(f.events, []string{"employee.updated:EMP-1"}) {
+		t.Fatalf("versions = %v, events = %v", f.versions, f.events)
+	}
+}
+
+func TestSetStatus(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		current string
+		archive bool
+		want    string
+		// changed berarti status disimpan dan dicatat
+		changed bool
+	}{
+		{name: "archive active", current: dto.EmployeeStatusActive, archive: true, want: dto.EmployeeStatusArchived, changed: true},
+		{name: "archive archived", current: dto.EmployeeStatusArchived, archive: true, want: dto.EmployeeStatusArchived},
+		{name: "restore archived", current: dto.EmployeeStatusArchived, want: dto.EmployeeStatusActive, changed: true},
+		{name: "restore active", current: dto.EmployeeStatusActive, want: dto.EmployeeStatusActive},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newEmployeeFixture()
+			before := dto.EmployeeResponse{EmployeePayload: createPayload("EMP-1"), Status: tt.current}
+			f.employee.GetForUpdateFunc = func(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error) {
+				return before, nil
+			}
+			var saved []string
+			f.employee.SetStatusFunc = func(ctx context.Context, identityNumber string, status string, managerId string) (dto.EmployeeResponse, error) {
+				saved = append(saved, status)
+				after := before
+				after.Status = status
+				return after, nil
+			}
+
+			set := f.service().Restore
+			if tt.archive {
+				set = f.service().Archive
+			}
+			employee, err := set(context.Background(), "EMP-1", testManagerID)
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if employee.Status != tt.want {
+				t.Fatalf("status = %q, want %q", employee.Status, tt.want)
+			}
+			if !tt.changed {
+				if len(saved) != 0 || len(f.audits) != 0 || len(f.versions) != 0 || len(f.events) != 0 {
+					t.Fatalf("saved = %v, audits = %d, versions = %v, events = %v, want no writes", saved, len(f.audits), f.versions, f.events)
+				}
+				return
+			}
+			if !slices.Equal(saved, []string{tt.want}) {
+				t.Fatalf("saved = %v, want [%s]", saved, tt.want)
+			}
+			if len(f.audits) != 1 || !slices.Equal(f.versions, []string{"update:EMP-1"}) || !slices.Equal(f.events, []string{"employee.updated:EMP-1"}) {
+				t.Fatalf("audits = %d, versions = %v, events = %v", len(f.audits), f.versions, f.events)
+			}
+			var changes map[string]dto.AuditChange
+			if err := json.Unmarshal(f.audits[0].Changes, &changes); err != nil {
+				t.Fatalf("unmarshal changes: %v", err)
+			}
+			want := map[string]dto.AuditChange{"status": {Before: tt.current, After: tt.want}}
+			if !reflect.DeepEqual(changes, want) {
+				t.Fatalf("audit changes = %+v, want %+v", changes, want)
+			}
+		})
+	}
+}
+
+func TestSetStatusNotFound(t *testing.T) {
+	f := newEmployeeFixture()
+	f.employee.GetForUpdateFunc = func(ctx context.Context, identityNumber string, managerId string) (dto.EmployeeResponse, error) {
+		return dto.EmployeeResponse{}, helper.ErrNotFound
+	}
+	if _, err := f.service().Archive(context.Background(), "EMP-404", testManagerID); !errors.Is(err, helper.ErrNotFound) {
+		t.Fatalf("Archive error = %v, want %v", err, helper.ErrNotFound)
+	}
+}
+
+func TestStats(t *testing.T) {
+	f := newEmployeeFixture()
+	f.employee.StatsFunc = func(ctx context.Context, managerId string) ([]dto.DepartmentEmployeeStats, error) {
+		return []dto.DepartmentEmployeeStats{
+			{DepartmentID: "dept-1", Active: 3, Archived: 1},
+			{DepartmentID: "dept-2", Active: 0, Archived: 2},
+		}, nil
+	}
+	stats, err := f.service().Stats(context.Background(), testManagerID)
+	if err != nil {
+		t.Fatalf("Stats error = %v", err)
+	}
+	if stats.Active != 3 || stats.Archived != 3 || len(stats.Departments) != 2 {
+		t.Fatalf("Stats = %+v", stats)
+	}
+
+	// Manager tanpa department tetap mendapat departments []
+	f.employee.StatsFunc = func(ctx context.Context, managerId string) ([]dto.DepartmentEmployeeStats, error) {
+		return nil, nil
+	}
+	stats, err = f.service().Stats(context.Background(), testManagerID)
+	if err != nil || stats.Departments == nil || stats.Active != 0 || stats.Archived != 0 {
+		t.Fatalf("Stats without departments = %+v, %v", stats, err)
+	}
+}
+
+func TestMissingIdentityNumbers(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		requested []string
+		found     []string
+		want      []string
+	}{
+		{name: "request order", requested: []string{"EMP-3", "EMP-1", "EMP-2"}, found: []string{"EMP-1"}, want: []string{"EMP-3", "EMP-2"}},
+		{name: "repeated value listed once", requested: []string{"EMP-2", "EMP-1", "EMP-2"}, found: []string{"EMP-1"}, want: []string{"EMP-2"}},
+		// Dikirim sebagai [] di meta.missing
+		{name: "all found", requested: []string{"EMP-1", "EMP-2"}, found: []string{"EMP-2", "EMP-1"}, want: []string{}},
+		{name: "none found", requested: []string{"EMP-1"}, want: []string{"EMP-1"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newEmployeeFixture()
+			f.employee.MatchingIdentityNumbersFunc = func(ctx context.Context, input *dto.GetEmployeesRequest) ([]string, error) {
+				if !slices.Equal(input.IdentityNumbers, tt.requested) {
+					t.Errorf("MatchingIdentityNumbers input = %v, want %v", input.IdentityNumbers, tt.requested)
+				}
+				return tt.found, nil
+			}
+			input := listRequest()
+			input.IdentityNumbers = tt.requested
+			missing, err := f.service().MissingIdentityNumbers(context.Background(), input)
+			if err != nil {
+				t.Fatalf("MissingIdentityNumbers error = %v", err)
+			}
+			if missing == nil || !slices.Equal(missing, tt.want) {
+				t.Fatalf("MissingIdentityNumbers = %#v, want %#v", missing, tt.want)
+			}
+		})
+	}
+
+	f := newEmployeeFixture()
+	f.employee.MatchingIdentityNumbersFunc = func(ctx context.Context, input *dto.GetEmployeesRequest) ([]string, error) {
+		return nil, helper.ErrInternalServer
+	}
+	input := listRequest()
+	input.IdentityNumbers = []string{"EMP-1"}
+	if _, err := f.service().MissingIdentityNumbers(context.Background(), input); !errors.Is(err, helper.ErrInternalServer) {
+		t.Fatalf("MissingIdentityNumbers error = %v, want %v", err, helper.ErrInternalServer)
+	}
+}
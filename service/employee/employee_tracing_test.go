@@ -0,0 +1,171 @@
+package user_service_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/telemetry"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans mengganti tracer fixture dengan tracer yang menyimpan span
+// yang sudah selesai.
+func recordSpans(f *employeeFixture) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	f.tracer = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer(telemetry.TracerName)
+	return recorder
+}
+
+// spanTree mengembalikan nama span beserta nama parent-nya, mis.
+// "employee.insert < EmployeeService.Create", dan span berdasarkan nama.
+func spanTree(spans []sdktrace.ReadOnlySpan) ([]string, map[string]sdktrace.ReadOnlySpan) {
+	byID := map[string]string{}
+	byName := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range spans {
+		byID[span.SpanContext().SpanID().String()] = span.Name()
+		byName[span.Name()] = span
+	}
+	var tree []string
+	for _, span := range spans {
+		name := span.Name()
+		if parent, ok := byID[span.Parent().SpanID().String()]; ok {
+			name += " < " + parent
+		}
+		tree = append(tree, name)
+	}
+	slices.Sort(tree)
+	return tree, byName
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	values := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		values[kv.Key] = kv.Value
+	}
+	return values
+}
+
+func TestCreateSpans(t *testing.T) {
+	f := newEmployeeFixture()
+	recorder := recordSpans(f)
+	f.employee.CreateFunc = func(ctx context.Context, input *dto.EmployeePayload, managerId string) (dto.EmployeeResponse, error) {
+		return dto.EmployeeResponse{EmployeePayload: *input}, nil
+	}
+
+	if _, err := f.service().Create(context.Background(), createPayload("EMP-1"), testManagerID); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	tree, spans := spanTree(recorder.Ended())
+	want := []string{
+		"EmployeeService.Create",
+		"audit.record < EmployeeService.Create",
+		"employee.insert < EmployeeService.Create",
+		"employee.version < EmployeeService.Create",
+		"outbox.add < EmployeeService.Create",
+	}
+	if !slices.Equal(tree, want) {
+		t.Fatalf("spans = %q, want %q", tree, want)
+	}
+	if got := spanAttributes(spans["EmployeeService.Create"])["employee.department_id"].AsString(); got != testDeptID {
+		t.Errorf("employee.department_id = %q, want %q", got, testDeptID)
+	}
+	if got := spanAttributes(spans["audit.record"])["audit.action"].AsString(); got != "create" {
+		t.Errorf("audit.action = %q, want create", got)
+	}
+	// Span tidak membawa data pribadi employee
+	for _, span := range recorder.Ended() {
+		for _, kv := range span.Attributes() {
+			if kv.Value.AsString() == "EMP-1" || kv.Value.AsString() == "Budi" {
+				t.Errorf("span %s attribute %s = %q", span.Name(), kv.Key, kv.Value.AsString())
+			}
+		}
+	}
+}
+
+func TestGetAllSpans(t *testing.T) {
+	f := newEmployeeFixture()
+	recorder := recordSpans(f)
+	f.employee.GetAllFunc = func(ctx context.Context, input *dto.GetEmployeesRequest) ([]dto.EmployeeResponse, error) {
+		return []dto.EmployeeResponse{{}, {}}, nil
+	}
+
+	input := listRequest()
+	input.DepartmentID = testDeptID
+	if _, err := f.service().GetAll(context.Background(), input); err != nil {
+		t.Fatalf("GetAll() error = %v", err)
+	}
+
+	tree, spans := spanTree(recorder.Ended())
+	if want := []string{"EmployeeService.GetAll", "employee.query < EmployeeService.GetAll"}; !slices.Equal(tree, want) {
+		t.Fatalf("spans = %q, want %q", tree, want)
+	}
+	values := spanAttributes(spans["EmployeeService.GetAll"])
+	if values["employee.rows"].AsInt64() != 2 ||
+		values["employee.limit"].AsInt64() != int64(dto.DefaultLimit) ||
+		values["employee.department_id"].AsString() != testDeptID {
+		t.Fatalf("GetAll attributes = %v", values)
+	}
+}
+
+// TestGetAllRequestSpanHierarchy menyusun span satu request GET
+// /v1/employee: span otelgin, span service, span langkah query, lalu span
+// SQL dari telemetry.PgxTracer yang dipanggil seperti oleh pgx.
+func TestGetAllRequestSpanHierarchy(t *testing.T) {
+	f := newEmployeeFixture()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	f.tracer = provider.Tracer(telemetry.TracerName)
+	// PgxTracer memakai tracer global
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	pgxTracer := telemetry.NewPgxTracer()
+	f.employee.GetAllFunc = func(ctx context.Context, input *dto.GetEmployeesRequest) ([]dto.EmployeeResponse, error) {
+		ctx = pgxTracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT e.identityNumber FROM employees AS e"})
+		pgxTracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 1")})
+		return []dto.EmployeeResponse{{}}, nil
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(otelgin.Middleware(telemetry.DefaultServiceName, otelgin.WithTracerProvider(provider)))
+	svc := f.service()
+	router.GET("/v1/employee", func(c *gin.Context) {
+		if _, err := svc.GetAll(c.Request.Context(), listRequest()); err != nil {
+			t.Errorf("GetAll() error = %v", err)
+		}
+		c.Status(http.StatusOK)
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/employee", nil))
+
+	spans := recorder.Ended()
+	tree, _ := spanTree(spans)
+	want := []string{
+		"/v1/employee",
+		"EmployeeService.GetAll < /v1/employee",
+		"db.query SELECT < employee.query",
+		"employee.query < EmployeeService.GetAll",
+	}
+	if !slices.Equal(tree, want) {
+		t.Fatalf("spans = %q, want %q", tree, want)
+	}
+	// Semua span berada dalam satu trace
+	for _, span := range spans {
+		if span.SpanContext().TraceID() != spans[0].SpanContext().TraceID() {
+			t.Fatalf("span %s has trace %s, want %s", span.Name(), span.SpanContext().TraceID(), spans[0].SpanContext().TraceID())
+		}
+	}
+}
@@ -0,0 +1,155 @@
+//go:build integration
+
+package fileService_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/clock"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/infrastructure/storage"
+	"github.com/levensspel/go-gin-template/mocks"
+	repositories "github.com/levensspel/go-gin-template/repository/file"
+	fileService "github.com/levensspel/go-gin-template/service/file"
+	"github.com/levensspel/go-gin-template/thumbnail"
+)
+
+// completeFixture menjalankan Complete dengan repository dan transaksi
+// asli di atas MemoryStorage.
+type completeFixture struct {
+	pool    *pgxpool.Pool
+	repo    *repositories.FileRepository
+	storage *storage.MemoryStorage
+	service fileService.FileService
+	manager string
+}
+
+func newCompleteFixture(t *testing.T) *completeFixture {
+	t.Helper()
+	pool := dbtest.New(t)
+	repo := repositories.New(pool, config.LoadQueryTimeoutConfig())
+	memory := storage.NewMemoryStorage("")
+	fileConfig := testFileConfig()
+	thumbnails := thumbnail.NewGenerator(context.Background(), &repo, memory, nil, &config.ThumbnailConfig{}, fileConfig, mocks.Logger{})
+	return &completeFixture{
+		pool:    pool,
+		repo:    &repo,
+		storage: memory,
+		service: fileService.NewFileService(&repo, pool, memory, thumbnails, nil, &sequentialIDs{}, clock.Real(), fileConfig, &config.ResizeConfig{}, config.LoadQueryTimeoutConfig(), mocks.Logger{}),
+		manager: dbtest.CreateManager(t, pool, "presign@example.com"),
+	}
+}
+
+// presigned mencatat upload presign id yang berlaku selama expiry (negatif
+// berarti sudah kedaluwarsa), dan jika content tidak nil, object hasil
+// upload client-nya.
+func (f *completeFixture) presigned(t *testing.T, id string, size int64, expiry time.Duration, content []byte) entity.FileUpload {
+	t.Helper()
+	upload := entity.FileUpload{
+		Id:          id,
+		ManagerId:   f.manager,
+		FileKey:     "file/" + f.manager + "/" + id + ".png",
+		FileName:    "avatar.png",
+		ContentType: "image/png",
+		Size:        size,
+	}
+	if err := f.repo.CreateUpload(context.Background(), upload, expiry); err != nil {
+		t.Fatal(err)
+	}
+	if content != nil {
+		if _, err := f.storage.Put(context.Background(), upload.FileKey, "image/png", bytes.NewReader(content), int64(len(content)), true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return upload
+}
+
+func (f *completeFixture) uploadExists(t *testing.T, id string) bool {
+	t.Helper()
+	var exists bool
+	if err := f.pool.QueryRow(context.Background(), "SELECT EXISTS (SELECT 1 FROM file_upload WHERE id = $1);", id).Scan(&exists); err != nil {
+		t.Fatal(err)
+	}
+	return exists
+}
+
+func TestCompleteRecordsFile(t *testing.T) {
+	f := newCompleteFixture(t)
+	content := encodeImage(t, "png", 8, 8)
+	upload := f.presigned(t, "0190a8f2-7c1e-7d3a-9b4f-000000000001", int64(len(content)), time.Minute, content)
+
+	response, err := f.service.Complete(context.Background(), f.manager, upload.Id)
+	if err != nil {
+		t.Fatalf("Complete error = %v", err)
+	}
+	if response.FileId != upload.Id || response.Uri != f.storage.URL(upload.FileKey) {
+		t.Fatalf("Complete = %+v, want %s at %s", response, upload.Id, f.storage.URL(upload.FileKey))
+	}
+	file, err := f.repo.Get(context.Background(), upload.Id)
+	if err != nil {
+		t.Fatalf("Get error = %v", err)
+	}
+	if file.ManagerId != f.manager || file.Size != int64(len(content)) || file.ContentType != "image/png" || file.FileKey != upload.FileKey {
+		t.Fatalf("recorded file = %+v", file)
+	}
+	if f.uploadExists(t, upload.Id) {
+		t.Fatal("completed upload is still pending")
+	}
+
+	// Upload yang sama tidak bisa dikonfirmasi dua kali
+	if _, err := f.service.Complete(context.Background(), f.manager, upload.Id); !errors.Is(err, helper.ErrNotFound) {
+		t.Fatalf("second Complete error = %v, want %v", err, helper.ErrNotFound)
+	}
+}
+
+func TestCompleteRejects(t *testing.T) {
+	content := encodeImage(t, "png", 8, 8)
+	for _, tt := range []struct {
+		name        string
+		size        int64
+		expiry      time.Duration
+		content     []byte
+		manager     string
+		want        error
+		keepsUpload bool
+		keepsObject bool
+	}{
+		// Upload kedaluwarsa dibiarkan untuk purge.Cleaner
+		{name: "expired", size: int64(len(content)), expiry: -time.Minute, content: content, want: helper.ErrUploadExpired, keepsUpload: true, keepsObject: true},
+		{name: "not uploaded yet", size: int64(len(content)), expiry: time.Minute, want: helper.ErrUploadIncomplete, keepsUpload: true},
+		{name: "other manager", size: int64(len(content)), expiry: time.Minute, content: content, manager: "someone-else", want: helper.ErrNotFound, keepsUpload: true, keepsObject: true},
+		{name: "size differs", size: int64(len(content)) + 1, expiry: time.Minute, content: content, want: helper.ErrPayloadTooLarge},
+		{name: "not an image", size: 9, expiry: time.Minute, content: []byte("not image"), want: helper.ErrInvalidFileType},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newCompleteFixture(t)
+			upload := f.presigned(t, "0190a8f2-7c1e-7d3a-9b4f-000000000002", tt.size, tt.expiry, tt.content)
+			manager := f.manager
+			if tt.manager != "" {
+				manager = tt.manager
+			}
+
+			if _, err := f.service.Complete(context.Background(), manager, upload.Id); !errors.Is(err, tt.want) {
+				t.Fatalf("Complete error = %v, want %v", err, tt.want)
+			}
+			if _, err := f.repo.Get(context.Background(), upload.Id); !errors.Is(err, helper.ErrNotFound) {
+				t.Fatalf("rejected upload was recorded as a file: %v", err)
+			}
+			if got := f.uploadExists(t, upload.Id); got != tt.keepsUpload {
+				t.Errorf("upload row exists = %t, want %t", got, tt.keepsUpload)
+			}
+			_, err := f.storage.Stat(context.Background(), upload.FileKey)
+			if got := err == nil; got != tt.keepsObject {
+				t.Errorf("object exists = %t, want %t", got, tt.keepsObject)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package fileService
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+// sniffLen adalah jumlah byte yang dibaca http.DetectContentType.
+const sniffLen = 512
+
+// peekLimit adalah jumlah byte awal upload yang paling banyak ditampung di
+// memori untuk inspectImage. Header JPEG bisa didahului metadata EXIF
+// (paling besar 64KiB per segmen); gambar yang header-nya belum terbaca
+// sampai batas ini ditolak sebagai helper.ErrInvalidImage.
+const peekLimit = 128 << 10
+
+// magicNumbers adalah awal isi file untuk setiap tipe di allowedContentTypes.
+// http.DetectContentType juga memakai pola ini; dicek lagi agar penerimaan
+// file tidak bergantung pada urutan aturan sniffing di standard library.
+var magicNumbers = map[string][]byte{
+	"image/jpeg": {0xFF, 0xD8, 0xFF},
+	"image/png":  {0x89, 'P', 'N', 'G', '\r', '\n', 0x1A, '\n'},
+}
+
+// fileExtensions adalah ekstensi nama file yang boleh dipakai untuk setiap
+// tipe.
+var fileExtensions = map[string][]string{
+	"image/jpeg": {".jpg", ".jpeg", ".jpe"},
+	"image/png":  {".png"},
+}
+
+// inspectImage menentukan tipe file dari isinya dan mengembalikan error
+// terdaftar sesuai alasan penolakan:
+//   - helper.ErrInvalidFileType jika isinya bukan JPEG atau PNG
+//   - helper.ErrFileTypeMismatch jika ekstensi fileName atau claimedType
+//     (Content-Type dari client) menyebut tipe lain
+//   - helper.ErrInvalidImage jika header gambar tidak bisa dibaca
+//   - helper.ErrImageTooLarge jika dimensinya melebihi FILE_MAX_IMAGE_*
+//
+// Hanya header gambar yang di-decode, tidak pernah pixel-nya. Error selain
+// itu berasal dari r.
+func (s *fileService) inspectImage(r io.Reader, fileName string, claimedType string) (contentType string, err error) {
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	head = head[:n]
+
+	contentType = http.DetectContentType(head)
+	magic, ok := magicNumbers[contentType]
+	if !ok || !bytes.HasPrefix(head, magic) {
+		return "", helper.ErrInvalidFileType
+	}
+	if !matchesClaim(contentType, fileName, claimedType) {
+		return "", helper.ErrFileTypeMismatch
+	}
+
+	config, format, err := image.DecodeConfig(io.MultiReader(bytes.NewReader(head), r))
+	if err != nil || "image/"+format != contentType {
+		return "", helper.ErrInvalidImage
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return "", helper.ErrInvalidImage
+	}
+	if config.Width > s.config.MaxImageDimension ||
+		config.Height > s.config.MaxImageDimension ||
+		config.Width*config.Height > s.config.MaxImagePixels {
+		return "", helper.ErrImageTooLarge
+	}
+	return contentType, nil
+}
+
+// matchesClaim melaporkan apakah ekstensi fileName dan claimedType sesuai
+// dengan contentType hasil sniffing. Nama tanpa ekstensi dan Content-Type
+// kosong atau application/octet-stream (bawaan banyak client) tidak
+// dianggap klaim.
+func matchesClaim(contentType string, fileName string, claimedType string) bool {
+	ext := strings.ToLower(path.Ext(fileName))
+	if ext != "" && !slices.Contains(fileExtensions[contentType], ext) {
+		return false
+	}
+	if claimedType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(claimedType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/octet-stream" || mediaType == contentType
+}
+
+// isRejection melaporkan apakah err adalah alasan penolakan dari
+// inspectImage, bukan error saat membaca file.
+func isRejection(err error) bool {
+	return errors.Is(err, helper.ErrInvalidFileType) ||
+		errors.Is(err, helper.ErrFileTypeMismatch) ||
+		errors.Is(err, helper.ErrInvalidImage) ||
+		errors.Is(err, helper.ErrImageTooLarge)
+}
+
+// limitedReader membaca paling banyak limit byte dari r dan mengembalikan
+// helper.ErrPayloadTooLarge begitu body ternyata lebih panjang, sehingga
+// upload berhenti di tengah stream tanpa menunggu body habis.
+type limitedReader struct {
+	r     io.Reader
+	limit int64
+	// read adalah jumlah byte yang sudah dikembalikan, paling banyak limit
+	read int64
+	err  error
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.err != nil {
+		return 0, l.err
+	}
+	// Satu byte lebih dari sisa limit cukup untuk tahu body terlalu besar
+	if remaining := l.limit - l.read + 1; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	n, err := l.r.Read(p)
+	if l.read+int64(n) > l.limit {
+		n = int(l.limit - l.read)
+		l.err = helper.ErrPayloadTooLarge
+		err = l.err
+	} else if err != nil && err != io.EOF {
+		l.err = err
+	}
+	l.read += int64(n)
+	return n, err
+}
+
+// bodyError mengembalikan alasan body berhenti dibaca selain habis:
+// helper.ErrPayloadTooLarge jika melebihi limit atau batas body request,
+// helper.ErrBadRequest jika body gagal dibaca (mis. client memutus
+// koneksi), atau nil.
+func (l *limitedReader) bodyError() error {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case l.err == nil:
+		return nil
+	case errors.Is(l.err, helper.ErrPayloadTooLarge), errors.As(l.err, &maxBytesErr):
+		return helper.ErrPayloadTooLarge
+	default:
+		return helper.ErrBadRequest
+	}
+}
@@ -0,0 +1,116 @@
+package fileService_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+// pngWithSize mengubah lebar dan tinggi di chunk IHDR PNG tanpa menambah
+// pixel, seperti decompression bomb yang header-nya mengaku sangat besar.
+func pngWithSize(t *testing.T, width, height uint32) []byte {
+	t.Helper()
+	content := encodeImage(t, "png", 4, 4)
+	// Signature 8 byte, lalu length (4), "IHDR" (4), data (13), CRC (4)
+	binary.BigEndian.PutUint32(content[16:20], width)
+	binary.BigEndian.PutUint32(content[20:24], height)
+	binary.BigEndian.PutUint32(content[29:33], crc32.ChecksumIEEE(content[12:29]))
+	return content
+}
+
+// jpegWithExif menyisipkan segments segment APP1 Exif masing-masing sebesar
+// size byte setelah SOI, sehingga header gambar baru terbaca setelah
+// metadata.
+func jpegWithExif(t *testing.T, segments, size int) []byte {
+	t.Helper()
+	content := encodeImage(t, "jpeg", 8, 8)
+	payload := append([]byte("Exif\x00\x00"), make([]byte, size-6)...)
+	segment := append([]byte{0xFF, 0xE1, byte((len(payload) + 2) >> 8), byte(len(payload) + 2)}, payload...)
+	parts := [][]byte{content[:2]}
+	for range segments {
+		parts = append(parts, segment)
+	}
+	return slices.Concat(append(parts, content[2:])...)
+}
+
+func TestUploadInspectsContent(t *testing.T) {
+	pngContent := encodeImage(t, "png", 16, 16)
+	jpegContent := encodeImage(t, "jpeg", 16, 16)
+	for _, tt := range []struct {
+		name        string
+		fileName    string
+		claimedType string
+		content     []byte
+		want        error
+		wantType    string
+	}{
+		{name: "png", fileName: "avatar.png", claimedType: "image/png", content: pngContent, wantType: "image/png"},
+		{name: "jpeg uppercase extension", fileName: "photo.JPEG", claimedType: "image/jpeg", content: jpegContent, wantType: "image/jpeg"},
+		{name: "claim with parameters", fileName: "photo.jpg", claimedType: "image/jpeg; q=1", content: jpegContent, wantType: "image/jpeg"},
+		{name: "octet-stream claim", fileName: "avatar.png", claimedType: "application/octet-stream", content: pngContent, wantType: "image/png"},
+		{name: "no extension or claim", fileName: "upload", content: jpegContent, wantType: "image/jpeg"},
+		{name: "exif before header", fileName: "photo.jpg", content: jpegWithExif(t, 1, 60<<10), wantType: "image/jpeg"},
+
+		{name: "html named jpg", fileName: "photo.jpg", claimedType: "image/jpeg", content: []byte("<!DOCTYPE html><script>alert(1)</script>"), want: helper.ErrInvalidFileType},
+		{name: "svg", fileName: "logo.png", content: []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`), want: helper.ErrInvalidFileType},
+
+		{name: "png named jpg", fileName: "avatar.jpg", content: pngContent, want: helper.ErrFileTypeMismatch},
+		{name: "png named gif", fileName: "avatar.gif", content: pngContent, want: helper.ErrFileTypeMismatch},
+		{name: "png claimed as jpeg", fileName: "avatar.png", claimedType: "image/jpeg", content: pngContent, want: helper.ErrFileTypeMismatch},
+		{name: "png claimed as html", fileName: "avatar", claimedType: "text/html", content: pngContent, want: helper.ErrFileTypeMismatch},
+		{name: "malformed claim", fileName: "avatar.png", claimedType: "image/png;;", content: pngContent, want: helper.ErrFileTypeMismatch},
+
+		{name: "png signature only", fileName: "avatar.png", content: pngContent[:8], want: helper.ErrInvalidImage},
+		{name: "jpeg magic then garbage", fileName: "photo.jpg", content: append([]byte{0xFF, 0xD8, 0xFF, 0xE0}, bytes.Repeat([]byte{0x00}, 64)...), want: helper.ErrInvalidImage},
+		{name: "header after peek limit", fileName: "photo.jpg", content: jpegWithExif(t, 3, 60<<10), want: helper.ErrInvalidImage},
+
+		{name: "too wide", fileName: "avatar.png", content: pngWithSize(t, 10_001, 1), want: helper.ErrImageTooLarge},
+		{name: "too tall", fileName: "avatar.png", content: pngWithSize(t, 1, 10_001), want: helper.ErrImageTooLarge},
+		{name: "decompression bomb", fileName: "avatar.png", content: pngWithSize(t, 10_000, 10_000), want: helper.ErrImageTooLarge},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFileFixture(t, testFileConfig())
+
+			_, err := f.service.Upload(context.Background(), testManagerID, entity.FilePurposeEmployee, tt.fileName, tt.claimedType, bytes.NewReader(tt.content))
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Upload error = %v", err)
+				}
+				if len(f.created) != 1 || f.created[0].ContentType != tt.wantType {
+					t.Fatalf("recorded %+v, want content type %s", f.created, tt.wantType)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Upload error = %v, want %v", err, tt.want)
+			}
+			if len(f.created) != 0 {
+				t.Fatalf("rejected upload was recorded: %+v", f.created)
+			}
+		})
+	}
+}
+
+// TestInspectRejectionsHaveDistinctMessages memastikan setiap alasan
+// penolakan upload memiliki pesan 400 sendiri.
+func TestInspectRejectionsHaveDistinctMessages(t *testing.T) {
+	messages := map[string]error{}
+	for _, err := range []error{helper.ErrInvalidFileType, helper.ErrFileTypeMismatch, helper.ErrInvalidImage, helper.ErrImageTooLarge} {
+		if status := helper.GetErrorStatusCode(err); status != http.StatusBadRequest {
+			t.Errorf("status of %v = %d, want 400", err, status)
+		}
+		message := helper.GetErrorMessage(err)
+		if other, ok := messages[message]; ok {
+			t.Errorf("%v and %v share the message %q", err, other, message)
+		}
+		messages[message] = err
+	}
+}
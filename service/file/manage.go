@@ -0,0 +1,106 @@
+package fileService
+
+import (
+	"context"
+	"errors"
+
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/thumbnail"
+)
+
+// maxReferencedBy adalah jumlah identityNumber paling banyak di
+// dto.FileReferencesResponse.
+const maxReferencedBy = 100
+
+func (s *fileService) GetAll(ctx context.Context, managerID string, input dto.GetFilesRequest) ([]dto.FileResponse, error) {
+	files, err := s.repo.GetAll(ctx, managerID, input.Limit, input.Offset)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]dto.FileResponse, 0, len(files))
+	for _, file := range files {
+		results = append(results, toFileResponse(file))
+	}
+	return results, nil
+}
+
+func (s *fileService) Get(ctx context.Context, managerID string, fileID string) (dto.FileResponse, error) {
+	file, err := s.getOwned(ctx, managerID, fileID)
+	if err != nil {
+		return dto.FileResponse{}, err
+	}
+	refs, err := s.repo.FindReferences(ctx, file.FileURI, managerID, maxReferencedBy)
+	if err != nil {
+		return dto.FileResponse{}, err
+	}
+	file.ReferenceCount = refs.Count
+	response := toFileResponse(file)
+	response.ReferencedBy = &dto.FileReferencesResponse{
+		IdentityNumbers: refs.IdentityNumbers,
+		ProfileFields:   refs.ProfileFields,
+	}
+	return response, nil
+}
+
+func (s *fileService) Delete(ctx context.Context, managerID string, fileID string) error {
+	file, err := s.getOwned(ctx, managerID, fileID)
+	if err != nil {
+		return err
+	}
+	refs, err := s.repo.FindReferences(ctx, file.FileURI, managerID, 0)
+	if err != nil {
+		return err
+	}
+	if refs.Count > 0 {
+		return helper.ErrFileReferenced
+	}
+
+	deleted, err := s.repo.Delete(ctx, fileID, managerID)
+	if errors.Is(err, helper.ErrNotFound) {
+		// File baru saja dipakai (atau dihapus) setelah FindReferences
+		return helper.ErrFileReferenced
+	}
+	if err != nil {
+		return err
+	}
+
+	// Barisnya sudah terhapus, sehingga object yang gagal dihapus tidak
+	// pernah dilayani lagi; cukup di-log seperti purge.Cleaner
+	ctx = context.WithoutCancel(ctx)
+	for _, key := range thumbnail.ObjectKeys(deleted, s.resize.Sizes) {
+		if err := s.storage.Delete(ctx, key); err != nil && !errors.Is(err, helper.ErrNotFound) {
+			s.logger.Warn("Failed to delete file object", helper.FileServiceDelete, key, err)
+		}
+	}
+	return nil
+}
+
+// getOwned mengambil file milik managerID. File manager lain dianggap tidak
+// ada, agar keberadaannya tidak terlihat.
+func (s *fileService) getOwned(ctx context.Context, managerID string, fileID string) (entity.File, error) {
+	file, err := s.repo.Get(ctx, fileID)
+	if err != nil {
+		return entity.File{}, err
+	}
+	if file.ManagerId != managerID {
+		return entity.File{}, helper.ErrNotFound
+	}
+	return file, nil
+}
+
+func toFileResponse(file entity.File) dto.FileResponse {
+	return dto.FileResponse{
+		FileId:          file.FileId,
+		Uri:             file.FileURI,
+		FileName:        file.FileName,
+		Size:            file.Size,
+		ContentType:     file.ContentType,
+		Purpose:         file.Purpose,
+		ThumbnailUri:    file.ThumbnailURI,
+		ThumbnailStatus: file.ThumbnailStatus,
+		ReferenceCount:  file.ReferenceCount,
+		CreatedAt:       file.CreatedOn,
+	}
+}
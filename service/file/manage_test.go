@@ -0,0 +1,182 @@
+package fileService_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/levensspel/go-gin-template/clock"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/infrastructure/storage"
+	"github.com/levensspel/go-gin-template/mocks"
+	fileService "github.com/levensspel/go-gin-template/service/file"
+	"github.com/levensspel/go-gin-template/thumbnail"
+)
+
+const otherManagerID = "5d1f0c7a-2b3e-4c4d-8e9f-0a1b2c3d4e5f"
+
+// manageFixture menjalankan GET/DELETE /v1/file di atas MemoryStorage.
+// refs adalah rujukan per fileURI, dan deleteErr menggantikan hasil Delete
+// repository, misalnya saat file dirujuk setelah FindReferences.
+type manageFixture struct {
+	service   fileService.FileService
+	storage   *storage.MemoryStorage
+	files     map[string]entity.File
+	refs      map[string]entity.FileReferences
+	deleteErr error
+}
+
+func newManageFixture(t *testing.T) *manageFixture {
+	t.Helper()
+	f := &manageFixture{storage: storage.NewMemoryStorage(""), files: map[string]entity.File{}, refs: map[string]entity.FileReferences{}}
+	repo := &mocks.FileRepository{
+		GetFunc: func(ctx context.Context, fileID string) (entity.File, error) {
+			file, ok := f.files[fileID]
+			if !ok {
+				return entity.File{}, helper.ErrNotFound
+			}
+			return file, nil
+		},
+		FindReferencesFunc: func(ctx context.Context, fileURI string, managerID string, limit int) (entity.FileReferences, error) {
+			return f.refs[fileURI], nil
+		},
+		DeleteFunc: func(ctx context.Context, fileID string, managerID string) (entity.File, error) {
+			if f.deleteErr != nil {
+				return entity.File{}, f.deleteErr
+			}
+			file := f.files[fileID]
+			delete(f.files, fileID)
+			return entity.File{FileId: fileID, FileKey: file.FileKey, ThumbnailKey: file.ThumbnailKey}, nil
+		},
+	}
+	fileConfig := testFileConfig()
+	thumbnails := thumbnail.NewGenerator(context.Background(), repo, f.storage, nil, &config.ThumbnailConfig{}, fileConfig, mocks.Logger{})
+	resizeConfig := &config.ResizeConfig{Sizes: []string{"64x64"}}
+	f.service = fileService.NewFileService(repo, nil, f.storage, thumbnails, nil, &sequentialIDs{}, clock.Real(), fileConfig, resizeConfig, &config.QueryTimeoutConfig{}, mocks.Logger{})
+	return f
+}
+
+// put menyimpan file fileID milik testManagerID beserta thumbnail dan hasil
+// resize 64x64, dan mengembalikan semua key object-nya.
+func (f *manageFixture) put(t *testing.T, fileID string) []string {
+	t.Helper()
+	key := "file/" + testManagerID + "/" + fileID + ".png"
+	file := entity.File{
+		FileId:       fileID,
+		ManagerId:    testManagerID,
+		FileURI:      "https://api.example.com/files/" + fileID,
+		FileKey:      key,
+		ThumbnailKey: thumbnail.Key(key),
+		ContentType:  "image/png",
+	}
+	f.files[fileID] = file
+	keys := []string{key, thumbnail.VariantKey(key, 64, 64), file.ThumbnailKey}
+	for _, key := range keys {
+		if _, err := f.storage.Put(context.Background(), key, "image/png", bytes.NewReader([]byte("x")), 1, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return keys
+}
+
+func TestGetFileReferences(t *testing.T) {
+	f := newManageFixture(t)
+	f.put(t, "file-1")
+	f.refs["https://api.example.com/files/file-1"] = entity.FileReferences{
+		Count:           3,
+		IdentityNumbers: []string{"EMP-1"},
+		ProfileFields:   []string{"userImageUri"},
+	}
+
+	file, err := f.service.Get(context.Background(), testManagerID, "file-1")
+	if err != nil {
+		t.Fatalf("Get error = %v", err)
+	}
+	// Count termasuk employee manager lain yang tidak ikut ditampilkan
+	if file.ReferenceCount != 3 || file.ReferencedBy == nil ||
+		!slices.Equal(file.ReferencedBy.IdentityNumbers, []string{"EMP-1"}) ||
+		!slices.Equal(file.ReferencedBy.ProfileFields, []string{"userImageUri"}) {
+		t.Fatalf("Get = %+v, referencedBy %+v", file, file.ReferencedBy)
+	}
+}
+
+func TestGetAllFiles(t *testing.T) {
+	var gotManager string
+	var gotLimit, gotOffset int
+	repo := &mocks.FileRepository{
+		GetAllFunc: func(ctx context.Context, managerID string, limit int, offset int) ([]entity.File, error) {
+			gotManager, gotLimit, gotOffset = managerID, limit, offset
+			return []entity.File{{FileId: "file-2", ReferenceCount: 1}, {FileId: "file-1"}}, nil
+		},
+	}
+	s := fileService.NewFileService(repo, nil, storage.NewMemoryStorage(""), nil, nil, &sequentialIDs{}, clock.Real(), testFileConfig(), &config.ResizeConfig{}, &config.QueryTimeoutConfig{}, mocks.Logger{})
+
+	files, err := s.GetAll(context.Background(), testManagerID, dto.GetFilesRequest{Limit: 5, Offset: 10})
+	if err != nil {
+		t.Fatalf("GetAll error = %v", err)
+	}
+	if gotManager != testManagerID || gotLimit != 5 || gotOffset != 10 {
+		t.Fatalf("repository GetAll(%s, %d, %d)", gotManager, gotLimit, gotOffset)
+	}
+	if len(files) != 2 || files[0].FileId != "file-2" || files[0].ReferenceCount != 1 || files[0].ReferencedBy != nil {
+		t.Fatalf("GetAll = %+v", files)
+	}
+}
+
+func TestDeleteFileRemovesObjects(t *testing.T) {
+	f := newManageFixture(t)
+	keys := f.put(t, "file-1")
+
+	if err := f.service.Delete(context.Background(), testManagerID, "file-1"); err != nil {
+		t.Fatalf("Delete error = %v", err)
+	}
+	for _, key := range keys {
+		if _, err := f.storage.Stat(context.Background(), key); !errors.Is(err, helper.ErrNotFound) {
+			t.Errorf("object %s still exists: %v", key, err)
+		}
+	}
+}
+
+func TestDeleteFileRejects(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		managerID string
+		fileID    string
+		refs      entity.FileReferences
+		deleteErr error
+		want      error
+	}{
+		{name: "referenced by an employee", managerID: testManagerID, fileID: "file-1", refs: entity.FileReferences{Count: 1, IdentityNumbers: []string{"EMP-1"}}, want: helper.ErrFileReferenced},
+		// Rujukan dari employee manager lain juga mencegah file dihapus
+		{name: "referenced by another manager", managerID: testManagerID, fileID: "file-1", refs: entity.FileReferences{Count: 1}, want: helper.ErrFileReferenced},
+		{name: "referenced after the check", managerID: testManagerID, fileID: "file-1", deleteErr: helper.ErrNotFound, want: helper.ErrFileReferenced},
+		{name: "other manager", managerID: otherManagerID, fileID: "file-1", want: helper.ErrNotFound},
+		{name: "unknown file", managerID: testManagerID, fileID: "missing", want: helper.ErrNotFound},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newManageFixture(t)
+			keys := f.put(t, "file-1")
+			f.refs["https://api.example.com/files/file-1"] = tt.refs
+			f.deleteErr = tt.deleteErr
+
+			if err := f.service.Delete(context.Background(), tt.managerID, tt.fileID); !errors.Is(err, tt.want) {
+				t.Fatalf("Delete error = %v, want %v", err, tt.want)
+			}
+			for _, key := range keys {
+				if _, err := f.storage.Stat(context.Background(), key); err != nil {
+					t.Errorf("object %s was removed: %v", key, err)
+				}
+			}
+			if tt.managerID != testManagerID {
+				if _, err := f.service.Get(context.Background(), tt.managerID, tt.fileID); !errors.Is(err, helper.ErrNotFound) {
+					t.Fatalf("Get by another manager error = %v, want %v", err, helper.ErrNotFound)
+				}
+			}
+		})
+	}
+}
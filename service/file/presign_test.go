@@ -0,0 +1,137 @@
+package fileService_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/domain"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/infrastructure/storage"
+)
+
+// signedPut adalah argumen SignedPutURL yang diterima signingStorage.
+type signedPut struct {
+	key         string
+	contentType string
+	size        int64
+	expiry      time.Duration
+}
+
+// signingStorage adalah MemoryStorage yang bisa membuat URL upload
+// presign, seperti S3Storage.
+type signingStorage struct {
+	*storage.MemoryStorage
+	signed []signedPut
+	err    error
+}
+
+func (s *signingStorage) SignedPutURL(ctx context.Context, key string, contentType string, size int64, expiry time.Duration) (*domain.PresignedRequest, error) {
+	s.signed = append(s.signed, signedPut{key: key, contentType: contentType, size: size, expiry: expiry})
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &domain.PresignedRequest{
+		Method:  http.MethodPut,
+		URL:     "https://storage.example.com/" + key + "?X-Amz-Signature=signed",
+		Headers: map[string]string{"Content-Type": contentType},
+	}, nil
+}
+
+func newPresignFixture(t *testing.T) (*fileFixture, *signingStorage, *[]entity.FileUpload, *[]time.Duration) {
+	t.Helper()
+	fileConfig := testFileConfig()
+	fileConfig.PresignExpiry = 15 * time.Minute
+	signer := &signingStorage{MemoryStorage: storage.NewMemoryStorage("")}
+	f := newFileFixtureWithStorage(t, fileConfig, signer)
+	uploads, expiries := &[]entity.FileUpload{}, &[]time.Duration{}
+	f.repo.CreateUploadFunc = func(ctx context.Context, upload entity.FileUpload, expiry time.Duration) error {
+		*uploads = append(*uploads, upload)
+		*expiries = append(*expiries, expiry)
+		return nil
+	}
+	return f, signer, uploads, expiries
+}
+
+func TestPresignSignsUpload(t *testing.T) {
+	f, signer, uploads, expiries := newPresignFixture(t)
+	input := dto.FilePresignRequest{ContentType: "image/png", Size: 1234, FileName: "dir/avatar.png"}
+
+	response, err := f.service.Presign(context.Background(), testManagerID, input)
+	if err != nil {
+		t.Fatalf("Presign error = %v", err)
+	}
+
+	wantKey := "file/" + testManagerID + "/file-1.png"
+	if len(signer.signed) != 1 || signer.signed[0] != (signedPut{key: wantKey, contentType: "image/png", size: 1234, expiry: 15 * time.Minute}) {
+		t.Fatalf("SignedPutURL calls = %+v, want one for %s expiring in 15m", signer.signed, wantKey)
+	}
+	if !response.Presigned || response.FileId != "file-1" || response.Method != http.MethodPut ||
+		response.UploadUrl != "https://storage.example.com/"+wantKey+"?X-Amz-Signature=signed" ||
+		response.Headers["Content-Type"] != "image/png" {
+		t.Fatalf("Presign = %+v", response)
+	}
+	// expiresAt yang dilaporkan sama dengan masa berlaku URL dan baris upload
+	if want := f.clock.Now().Add(15 * time.Minute); response.ExpiresAt == nil || !response.ExpiresAt.Equal(want) {
+		t.Fatalf("ExpiresAt = %v, want 15m from now", response.ExpiresAt)
+	}
+	if len(*uploads) != 1 || (*expiries)[0] != 15*time.Minute {
+		t.Fatalf("CreateUpload calls = %+v %v, want one expiring in 15m", *uploads, *expiries)
+	}
+	upload := (*uploads)[0]
+	if upload.Id != "file-1" || upload.ManagerId != testManagerID || upload.FileKey != wantKey ||
+		upload.FileName != "avatar.png" || upload.ContentType != "image/png" || upload.Size != 1234 {
+		t.Fatalf("CreateUpload = %+v", upload)
+	}
+	// Belum ada file sampai Complete
+	if len(f.created) != 0 {
+		t.Fatalf("Presign recorded a file: %+v", f.created)
+	}
+}
+
+func TestPresignWithoutSigner(t *testing.T) {
+	fileConfig := testFileConfig()
+	fileConfig.BaseURL = "https://api.example.com/"
+	f := newFileFixture(t, fileConfig)
+
+	response, err := f.service.Presign(context.Background(), testManagerID, dto.FilePresignRequest{ContentType: "image/jpeg", Size: 10})
+	if err != nil {
+		t.Fatalf("Presign error = %v", err)
+	}
+	want := dto.FilePresignResponse{Presigned: false, Method: http.MethodPost, UploadUrl: "https://api.example.com/v1/file"}
+	if response.Presigned != want.Presigned || response.Method != want.Method || response.UploadUrl != want.UploadUrl ||
+		response.FileId != "" || response.ExpiresAt != nil {
+		t.Fatalf("Presign = %+v, want %+v", response, want)
+	}
+}
+
+func TestPresignRejects(t *testing.T) {
+	errSign := errors.New("sign failed")
+	for _, tt := range []struct {
+		name    string
+		input   dto.FilePresignRequest
+		signErr error
+		want    error
+	}{
+		{name: "too large", input: dto.FilePresignRequest{ContentType: "image/png", Size: 2<<20 + 1}, want: helper.ErrPayloadTooLarge},
+		{name: "not an image type", input: dto.FilePresignRequest{ContentType: "image/gif", Size: 10}, want: helper.ErrInvalidFileType},
+		{name: "name names another type", input: dto.FilePresignRequest{ContentType: "image/png", Size: 10, FileName: "avatar.jpg"}, want: helper.ErrFileTypeMismatch},
+		{name: "signer error", input: dto.FilePresignRequest{ContentType: "image/png", Size: 10}, signErr: errSign, want: errSign},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f, signer, uploads, _ := newPresignFixture(t)
+			signer.err = tt.signErr
+
+			if _, err := f.service.Presign(context.Background(), testManagerID, tt.input); !errors.Is(err, tt.want) {
+				t.Fatalf("Presign error = %v, want %v", err, tt.want)
+			}
+			if len(*uploads) != 0 {
+				t.Fatalf("rejected presign recorded an upload: %+v", *uploads)
+			}
+		})
+	}
+}
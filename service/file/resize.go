@@ -0,0 +1,98 @@
+package fileService
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/levensspel/go-gin-template/domain"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/thumbnail"
+	"github.com/levensspel/go-gin-template/worker"
+)
+
+// resizeTaskName adalah nama task resize di worker.Pool.
+const resizeTaskName = "image_resize"
+
+func (s *fileService) OpenResized(ctx context.Context, fileID string, width int, height int) (entity.File, *domain.Object, error) {
+	if !slices.Contains(s.resize.Sizes, fmt.Sprintf("%dx%d", width, height)) {
+		return entity.File{}, nil, helper.ErrInvalidImageSize
+	}
+	file, err := s.repo.Get(ctx, fileID)
+	if err != nil {
+		return entity.File{}, nil, err
+	}
+
+	key := thumbnail.VariantKey(file.FileKey, width, height)
+	object, err := s.storage.Get(ctx, key)
+	if err == nil {
+		return file, object, nil
+	}
+	if !errors.Is(err, helper.ErrNotFound) {
+		return entity.File{}, nil, err
+	}
+
+	if err := s.createVariant(ctx, file, key, width, height); err != nil {
+		return entity.File{}, nil, err
+	}
+	object, err = s.storage.Get(ctx, key)
+	if err != nil {
+		return entity.File{}, nil, err
+	}
+	return file, object, nil
+}
+
+// createVariant me-resize file asli dan menyimpannya ke key. File kecil
+// di-resize langsung; file yang lebih besar dari IMAGE_RESIZE_INLINE_MAX_BYTES
+// di-resize di worker.Pool dan ditunggu sampai IMAGE_RESIZE_TIMEOUT. Task
+// yang sudah berjalan tetap menyimpan hasilnya walau request dibatalkan,
+// sehingga request berikutnya langsung memakai cache.
+func (s *fileService) createVariant(ctx context.Context, file entity.File, key string, width int, height int) error {
+	ctx, cancel := context.WithTimeout(ctx, s.resize.Timeout)
+	defer cancel()
+
+	resize := func(ctx context.Context) error {
+		original, err := s.storage.Get(ctx, file.FileKey)
+		if err != nil {
+			return err
+		}
+		defer original.Body.Close()
+
+		data, contentType, err := thumbnail.ResizeToFit(original.Body, width, height, s.config.MaxImagePixels)
+		if errors.Is(err, thumbnail.ErrUnsupportedImage) {
+			return helper.ErrInvalidImage
+		}
+		if err != nil {
+			return err
+		}
+		_, err = s.storage.Put(ctx, key, contentType, bytes.NewReader(data), int64(len(data)), true)
+		return err
+	}
+	if file.Size <= s.resize.InlineMaxBytes {
+		return resize(ctx)
+	}
+
+	done := make(chan error, 1)
+	err := s.pool.Submit(worker.Task{
+		Name:    resizeTaskName,
+		Timeout: s.resize.Timeout,
+		Run: func(ctx context.Context) error {
+			err := resize(ctx)
+			done <- err
+			return err
+		},
+	})
+	if err != nil {
+		return err
+	}
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		s.logger.Warn("Image resize did not finish in time", helper.FileServiceOpenResized, key)
+		return helper.ErrServiceUnavailable
+	}
+}
@@ -0,0 +1,189 @@
+package fileService_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/clock"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/domain"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/infrastructure/storage"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/mocks"
+	fileService "github.com/levensspel/go-gin-template/service/file"
+	"github.com/levensspel/go-gin-template/thumbnail"
+	"github.com/levensspel/go-gin-template/worker"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// countingStorage menghitung Get dan Put per key.
+type countingStorage struct {
+	domain.Storage
+	mu   sync.Mutex
+	gets map[string]int
+	puts map[string]int
+}
+
+func newCountingStorage() *countingStorage {
+	return &countingStorage{Storage: storage.NewMemoryStorage(""), gets: map[string]int{}, puts: map[string]int{}}
+}
+
+func (s *countingStorage) Get(ctx context.Context, key string) (*domain.Object, error) {
+	s.mu.Lock()
+	s.gets[key]++
+	s.mu.Unlock()
+	return s.Storage.Get(ctx, key)
+}
+
+func (s *countingStorage) Put(ctx context.Context, key string, contentType string, body io.Reader, size int64, public bool) (string, error) {
+	s.mu.Lock()
+	s.puts[key]++
+	s.mu.Unlock()
+	return s.Storage.Put(ctx, key, contentType, body, size, public)
+}
+
+// resizeFixture menjalankan OpenResized dengan ukuran 64x64 dan 128x32,
+// dan worker.Pool untuk file di atas InlineMaxBytes.
+type resizeFixture struct {
+	service fileService.FileService
+	storage *countingStorage
+	files   map[string]entity.File
+}
+
+func newResizeFixture(t *testing.T, pool *worker.Pool) *resizeFixture {
+	t.Helper()
+	f := &resizeFixture{storage: newCountingStorage(), files: map[string]entity.File{}}
+	repo := &mocks.FileRepository{
+		GetFunc: func(ctx context.Context, fileID string) (entity.File, error) {
+			file, ok := f.files[fileID]
+			if !ok {
+				return entity.File{}, helper.ErrNotFound
+			}
+			return file, nil
+		},
+	}
+	fileConfig := testFileConfig()
+	resizeConfig := &config.ResizeConfig{Sizes: []string{"64x64", "128x32"}, InlineMaxBytes: 4 << 10, Timeout: 5 * time.Second}
+	thumbnails := thumbnail.NewGenerator(context.Background(), repo, f.storage, nil, &config.ThumbnailConfig{}, fileConfig, mocks.Logger{})
+	f.service = fileService.NewFileService(repo, nil, f.storage, thumbnails, pool, &sequentialIDs{}, clock.Real(), fileConfig, resizeConfig, &config.QueryTimeoutConfig{}, mocks.Logger{})
+	return f
+}
+
+// put menyimpan data sebagai file fileID.
+func (f *resizeFixture) put(t *testing.T, fileID string, format string, data []byte) entity.File {
+	t.Helper()
+	key := "file/" + testManagerID + "/" + fileID + "." + format
+	if _, err := f.storage.Storage.Put(context.Background(), key, "image/"+format, bytes.NewReader(data), int64(len(data)), true); err != nil {
+		t.Fatal(err)
+	}
+	file := entity.File{FileId: fileID, FileKey: key, Size: int64(len(data)), ContentType: "image/" + format}
+	f.files[fileID] = file
+	return file
+}
+
+// openResized membaca hasil OpenResized dan mengembalikan format dan
+// ukurannya.
+func (f *resizeFixture) openResized(t *testing.T, fileID string, width, height int) (string, image.Config) {
+	t.Helper()
+	_, object, err := f.service.OpenResized(context.Background(), fileID, width, height)
+	if err != nil {
+		t.Fatalf("OpenResized error = %v", err)
+	}
+	defer object.Body.Close()
+	config, format, err := image.DecodeConfig(object.Body)
+	if err != nil {
+		t.Fatalf("decode variant: %v", err)
+	}
+	return format, config
+}
+
+func TestOpenResizedCachesVariant(t *testing.T) {
+	for _, format := range []string{"png", "jpeg"} {
+		t.Run(format, func(t *testing.T) {
+			f := newResizeFixture(t, nil)
+			file := f.put(t, "file-1", format, encodeImage(t, format, 40, 20))
+
+			for range 2 {
+				gotFormat, config := f.openResized(t, "file-1", 128, 32)
+				// 40x20 sudah muat lebarnya, tingginya dibatasi 32: tidak diperbesar
+				if gotFormat != format || config.Width != 40 || config.Height != 20 {
+					t.Fatalf("variant = %s %dx%d, want %s 40x20", gotFormat, config.Width, config.Height, format)
+				}
+			}
+			// Request kedua membaca hasil yang sudah disimpan tanpa decode ulang
+			key := thumbnail.VariantKey(file.FileKey, 128, 32)
+			if f.storage.gets[file.FileKey] != 1 || f.storage.puts[key] != 1 {
+				t.Fatalf("original read %d times, variant stored %d times, want 1 and 1", f.storage.gets[file.FileKey], f.storage.puts[key])
+			}
+			object, err := f.storage.Stat(context.Background(), key)
+			if err != nil || object.ContentType != "image/"+format {
+				t.Fatalf("variant object = %+v, %v, want content type image/%s", object, err, format)
+			}
+		})
+	}
+}
+
+func TestOpenResizedScalesDown(t *testing.T) {
+	f := newResizeFixture(t, nil)
+	f.put(t, "file-1", "png", encodeImage(t, "png", 200, 100))
+
+	if _, config := f.openResized(t, "file-1", 64, 64); config.Width != 64 || config.Height != 32 {
+		t.Fatalf("variant = %dx%d, want 64x32", config.Width, config.Height)
+	}
+}
+
+func TestOpenResizedOnPool(t *testing.T) {
+	pool := worker.New(&config.WorkerConfig{Size: 1, QueueDepth: 1, TaskTimeout: 5 * time.Second}, metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{}), mocks.Logger{})
+	pool.Start()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		pool.Shutdown(ctx)
+	})
+	f := newResizeFixture(t, pool)
+	// Ukuran di baris file yang menentukan jalur resize, bukan isi object
+	file := f.put(t, "file-1", "png", encodeImage(t, "png", 200, 100))
+	file.Size = 1 << 20
+	f.files["file-1"] = file
+
+	if _, config := f.openResized(t, "file-1", 64, 64); config.Width != 64 || config.Height != 32 {
+		t.Fatalf("variant = %dx%d, want 64x32", config.Width, config.Height)
+	}
+}
+
+func TestOpenResizedRejects(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		fileID        string
+		width, height int
+		want          error
+	}{
+		{name: "size not allowed", fileID: "file-1", width: 65, height: 64, want: helper.ErrInvalidImageSize},
+		{name: "swapped size", fileID: "file-1", width: 32, height: 128, want: helper.ErrInvalidImageSize},
+		{name: "zero size", fileID: "file-1", want: helper.ErrInvalidImageSize},
+		{name: "unknown file", fileID: "missing", width: 64, height: 64, want: helper.ErrNotFound},
+		{name: "not an image", fileID: "broken", width: 64, height: 64, want: helper.ErrInvalidImage},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newResizeFixture(t, nil)
+			f.put(t, "file-1", "png", encodeImage(t, "png", 40, 20))
+			f.put(t, "broken", "png", []byte("not really a png"))
+
+			if _, _, err := f.service.OpenResized(context.Background(), tt.fileID, tt.width, tt.height); !errors.Is(err, tt.want) {
+				t.Fatalf("OpenResized error = %v, want %v", err, tt.want)
+			}
+			// put tidak lewat penghitung, sehingga semua Put berasal dari resize
+			if len(f.storage.puts) != 0 {
+				t.Fatalf("rejected resize stored %v", f.storage.puts)
+			}
+		})
+	}
+}
@@ -1,73 +1,382 @@
 package fileService
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
-	"mime/multipart"
-	"os"
+	"net/http"
+	"path"
+	"strings"
 
+	"github.com/jackc/pgx/v5"
+
+	"github.com/levensspel/go-gin-template/clock"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/domain"
 	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
 	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/idgen"
 	"github.com/levensspel/go-gin-template/logger"
 	repositories "github.com/levensspel/go-gin-template/repository/file"
+	"github.com/levensspel/go-gin-template/thumbnail"
+	"github.com/levensspel/go-gin-template/worker"
+	"github.com/samber/do/v2"
 )
 
+// allowedContentTypes adalah tipe file yang boleh di-upload beserta
+// ekstensi key-nya di storage.
+var allowedContentTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
 type FileService interface {
-	Upload(file multipart.File, header *multipart.FileHeader) (dto.FileUploadRespondPayload, error)
-	DeleteByID(fileid string) error
+	// Upload menyimpan body ke storage secara streaming dan mencatat
+	// pemilik dan kegunaannya (entity.FilePurpose*). Tipe file ditentukan dari isi file; nama dan Content-Type
+	// dari client hanya harus sesuai dengannya, lihat inspectImage. Body
+	// yang lebih dari FILE_MAX_UPLOAD_BYTES ditolak dengan
+	// helper.ErrPayloadTooLarge.
+	Upload(ctx context.Context, managerID string, purpose string, fileName string, claimedType string, body io.Reader) (dto.FileUploadRespondPayload, error)
+	// Presign membuat URL upload langsung ke storage jika driver-nya
+	// mendukung, lihat domain.PresignedUploader. Upload baru menjadi file
+	// setelah Complete.
+	Presign(ctx context.Context, managerID string, input dto.FilePresignRequest) (dto.FilePresignResponse, error)
+	// Complete memeriksa object hasil upload presign dan mencatatnya sebagai
+	// file. Object yang ukuran atau tipenya tidak sesuai dihapus.
+	Complete(ctx context.Context, managerID string, fileID string) (dto.FileUploadRespondPayload, error)
+	// Open membuka isi file untuk dilayani GET /files/:id. Pemanggil wajib
+	// menutup object.Body.
+	Open(ctx context.Context, fileID string) (entity.File, *domain.Object, error)
+	// OpenThumbnail seperti Open untuk GET /files/:id/thumbnail, atau
+	// helper.ErrNotFound jika thumbnail-nya belum siap.
+	OpenThumbnail(ctx context.Context, fileID string) (entity.File, *domain.Object, error)
+	// OpenResized seperti Open untuk GET /files/:id/resize, dengan gambar
+	// yang dikecilkan agar muat di width x height. Hasil resize disimpan di
+	// storage dan dipakai lagi untuk ukuran yang sama. Ukuran yang tidak ada
+	// di IMAGE_RESIZE_SIZES ditolak dengan helper.ErrInvalidImageSize.
+	OpenResized(ctx context.Context, fileID string, width int, height int) (entity.File, *domain.Object, error)
+	// GetAll mengambil file yang di-upload manager, dari yang terbaru.
+	GetAll(ctx context.Context, managerID string, input dto.GetFilesRequest) ([]dto.FileResponse, error)
+	// Get mengambil metadata file milik manager beserta employee dan profil
+	// yang memakainya. File manager lain menghasilkan helper.ErrNotFound.
+	Get(ctx context.Context, managerID string, fileID string) (dto.FileResponse, error)
+	// Delete menghapus file milik manager beserta object-nya di storage.
+	// File yang masih dipakai employee atau profil manager ditolak dengan
+	// helper.ErrFileReferenced.
+	Delete(ctx context.Context, managerID string, fileID string) error
 }
 
 type fileService struct {
-	repo   repositories.FileRepository
-	logger logger.Logger
+	repo       repositories.FileRepositoryInterface
+	db         database.DB
+	storage    domain.Storage
+	thumbnails *thumbnail.Generator
+	pool       *worker.Pool
+	ids        idgen.IDGenerator
+	clock      clock.Clock
+	config     *config.FileConfig
+	resize     *config.ResizeConfig
+	timeouts   *config.QueryTimeoutConfig
+	logger     logger.Logger
 }
 
 func NewFileService(
-	repo repositories.FileRepository,
+	repo repositories.FileRepositoryInterface,
+	db database.DB,
+	storage domain.Storage,
+	thumbnails *thumbnail.Generator,
+	pool *worker.Pool,
+	ids idgen.IDGenerator,
+	clock clock.Clock,
+	config *config.FileConfig,
+	resize *config.ResizeConfig,
+	timeouts *config.QueryTimeoutConfig,
 	logger logger.Logger,
 ) FileService {
 	return &fileService{
-		repo:   repo,
-		logger: logger,
+		repo:       repo,
+		db:         db,
+		storage:    storage,
+		thumbnails: thumbnails,
+		pool:       pool,
+		ids:        ids,
+		clock:      clock,
+		config:     config,
+		resize:     resize,
+		timeouts:   timeouts,
+		logger:     logger,
+	}
+}
+
+func NewFileServiceInject(i do.Injector) (FileService, error) {
+	_repo := do.MustInvoke[repositories.FileRepositoryInterface](i)
+	_cluster := do.MustInvoke[*database.Cluster](i)
+	_storage := do.MustInvoke[domain.Storage](i)
+	_ids := do.MustInvoke[idgen.IDGenerator](i)
+	_logger := do.MustInvoke[logger.LogHandler](i)
+	return NewFileService(
+		_repo,
+		_cluster.Writer(),
+		_storage,
+		do.MustInvoke[*thumbnail.Generator](i),
+		do.MustInvoke[*worker.Pool](i),
+		_ids,
+		do.MustInvoke[clock.Clock](i),
+		config.LoadFileConfig(),
+		config.LoadResizeConfig(),
+		config.LoadQueryTimeoutConfig(),
+		&_logger,
+	), nil
+}
+
+func (s *fileService) Upload(
+	ctx context.Context,
+	managerID string,
+	purpose string,
+	fileName string,
+	claimedType string,
+	body io.Reader,
+) (dto.FileUploadRespondPayload, error) {
+	limited := &limitedReader{r: body, limit: s.config.MaxUploadBytes}
+	// Byte yang dibaca inspectImage ditampung di peek lalu dikirim ulang ke
+	// storage di depan sisa body
+	var peek bytes.Buffer
+	contentType, err := s.inspectImage(io.TeeReader(io.LimitReader(limited, peekLimit), &peek), fileName, claimedType)
+	if err := limited.bodyError(); err != nil {
+		return dto.FileUploadRespondPayload{}, err
+	}
+	if err != nil {
+		return dto.FileUploadRespondPayload{}, err
+	}
+	ext := allowedContentTypes[contentType]
+
+	fileID := s.ids.NewID()
+	key := fileKey(managerID, fileID, ext)
+	_, err = s.storage.Put(ctx, key, contentType, io.MultiReader(&peek, limited), -1, true)
+	if bodyErr := limited.bodyError(); bodyErr != nil {
+		// Driver biasanya tidak menyimpan object yang gagal di tengah
+		// jalan; tetap dihapus agar potongan file tidak tertinggal
+		if err := s.storage.Delete(context.WithoutCancel(ctx), key); err != nil && !errors.Is(err, helper.ErrNotFound) {
+			s.logger.Warn("Failed to delete partial upload", helper.FileServiceUpload, key, err)
+		}
+		return dto.FileUploadRespondPayload{}, bodyErr
+	}
+	if err != nil {
+		return dto.FileUploadRespondPayload{}, err
+	}
+
+	file, err := s.repo.Create(ctx, entity.File{
+		FileId:          fileID,
+		ManagerId:       managerID,
+		FileName:        path.Base(fileName),
+		FileURI:         s.fileURI(fileID, key),
+		FileKey:         key,
+		Size:            limited.read,
+		ContentType:     contentType,
+		Purpose:         purpose,
+		ThumbnailStatus: s.thumbnailStatus(),
+	})
+	if err != nil {
+		// Object di storage tidak dihapus; tanpa baris file, object itu
+		// tidak pernah dirujuk
+		s.logger.Warn("File uploaded but not recorded", helper.FileServiceUpload, key, err)
+		return dto.FileUploadRespondPayload{}, err
+	}
+	s.thumbnails.Notify()
+	return dto.FileUploadRespondPayload{FileId: file.FileId, Uri: file.FileURI}, nil
+}
+
+func (s *fileService) Open(ctx context.Context, fileID string) (entity.File, *domain.Object, error) {
+	file, err := s.repo.Get(ctx, fileID)
+	if err != nil {
+		return entity.File{}, nil, err
+	}
+	object, err := s.storage.Get(ctx, file.FileKey)
+	if err != nil {
+		return entity.File{}, nil, err
+	}
+	return file, object, nil
+}
+
+func (s *fileService) OpenThumbnail(ctx context.Context, fileID string) (entity.File, *domain.Object, error) {
+	file, err := s.repo.Get(ctx, fileID)
+	if err != nil {
+		return entity.File{}, nil, err
+	}
+	if file.ThumbnailStatus != entity.ThumbnailReady || file.ThumbnailKey == "" {
+		return entity.File{}, nil, helper.ErrNotFound
+	}
+	object, err := s.storage.Get(ctx, file.ThumbnailKey)
+	if err != nil {
+		return entity.File{}, nil, err
+	}
+	return file, object, nil
+}
+
+func (s *fileService) Presign(
+	ctx context.Context,
+	managerID string,
+	input dto.FilePresignRequest,
+) (dto.FilePresignResponse, error) {
+	if input.Size > s.config.MaxUploadBytes {
+		return dto.FilePresignResponse{}, helper.ErrPayloadTooLarge
+	}
+	ext, ok := allowedContentTypes[input.ContentType]
+	if !ok {
+		return dto.FilePresignResponse{}, helper.ErrInvalidFileType
+	}
+	if !matchesClaim(input.ContentType, input.FileName, "") {
+		return dto.FilePresignResponse{}, helper.ErrFileTypeMismatch
+	}
+
+	uploader, ok := s.storage.(domain.PresignedUploader)
+	if !ok {
+		return dto.FilePresignResponse{
+			Presigned: false,
+			Method:    http.MethodPost,
+			UploadUrl: strings.TrimSuffix(s.config.BaseURL, "/") + "/v1/file",
+		}, nil
+	}
+
+	fileID := s.ids.NewID()
+	key := fileKey(managerID, fileID, ext)
+	expiresAt := s.clock.Now().Add(s.config.PresignExpiry).UTC()
+	request, err := uploader.SignedPutURL(ctx, key, input.ContentType, input.Size, s.config.PresignExpiry)
+	if err != nil {
+		return dto.FilePresignResponse{}, err
+	}
+	err = s.repo.CreateUpload(ctx, entity.FileUpload{
+		Id:          fileID,
+		ManagerId:   managerID,
+		FileKey:     key,
+		FileName:    path.Base(input.FileName),
+		ContentType: input.ContentType,
+		Size:        input.Size,
+	}, s.config.PresignExpiry)
+	if err != nil {
+		return dto.FilePresignResponse{}, err
 	}
+
+	return dto.FilePresignResponse{
+		Presigned: true,
+		FileId:    fileID,
+		Method:    request.Method,
+		UploadUrl: request.URL,
+		Headers:   request.Headers,
+		ExpiresAt: &expiresAt,
+	}, nil
 }
 
-func (s *fileService) Upload(file multipart.File, header *multipart.FileHeader) (dto.FileUploadRespondPayload, error) {
-	// Simpan file ke server lokal
-	file, err := header.Open()
+func (s *fileService) Complete(ctx context.Context, managerID string, fileID string) (dto.FileUploadRespondPayload, error) {
+	var result dto.FileUploadRespondPayload
+	// rejected tidak dikembalikan dari transaksi, agar penghapusan upload
+	// yang tidak valid tetap di-commit
+	var rejected error
+	err := database.WithTx(ctx, s.db, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		repo := repositories.New(tx, s.timeouts)
+		upload, err := repo.GetUploadForUpdate(ctx, fileID, managerID)
+		if err != nil {
+			return err
+		}
+		// Upload kedaluwarsa dan object-nya dihapus purge.Cleaner
+		if upload.Expired {
+			return helper.ErrUploadExpired
+		}
+
+		info, err := s.storage.Stat(ctx, upload.FileKey)
+		if errors.Is(err, helper.ErrNotFound) {
+			return helper.ErrUploadIncomplete
+		}
+		if err != nil {
+			return err
+		}
+
+		rejected, err = s.checkUpload(ctx, upload, info)
+		if err != nil {
+			return err
+		}
+		if rejected != nil {
+			if err := s.storage.Delete(ctx, upload.FileKey); err != nil {
+				return err
+			}
+			return repo.DeleteUpload(ctx, upload.Id)
+		}
+
+		file, err := repo.Create(ctx, entity.File{
+			FileId:          upload.Id,
+			ManagerId:       upload.ManagerId,
+			FileName:        upload.FileName,
+			FileURI:         s.fileURI(upload.Id, upload.FileKey),
+			FileKey:         upload.FileKey,
+			Size:            info.Size,
+			ContentType:     upload.ContentType,
+			ThumbnailStatus: s.thumbnailStatus(),
+		})
+		if err != nil {
+			return err
+		}
+		if err := repo.DeleteUpload(ctx, upload.Id); err != nil {
+			return err
+		}
+		result = dto.FileUploadRespondPayload{FileId: file.FileId, Uri: file.FileURI}
+		return nil
+	})
 	if err != nil {
-		s.logger.Warn(err.Error(), helper.FunctionCaller("FileHandler.Upload"), header)
-		return dto.FileUploadRespondPayload{}, helper.ErrInternalServer
+		return dto.FileUploadRespondPayload{}, err
 	}
-	defer file.Close()
+	if rejected != nil {
+		s.logger.Warn(rejected.Error(), helper.FileServiceComplete, fileID)
+		return dto.FileUploadRespondPayload{}, rejected
+	}
+	s.thumbnails.Notify()
+	return result, nil
+}
 
-	// Tentukan lokasi penyimpanan file
-	savePath := fmt.Sprintf("./.uploads/%s", header.Filename)
+// thumbnailStatus adalah status thumbnail file baru. Thumbnail dibuat
+// thumbnail.Generator setelah file tersimpan.
+func (s *fileService) thumbnailStatus() string {
+	if !s.thumbnails.Enabled() {
+		return ""
+	}
+	return entity.ThumbnailPending
+}
 
-	// Buat direktori jika belum ada
-	if err := os.MkdirAll("./.uploads", os.ModePerm); err != nil {
-		s.logger.Error(err.Error(), helper.FunctionCaller("FileHandler.Upload"))
-		return dto.FileUploadRespondPayload{}, helper.ErrInternalServer
+// checkUpload mengembalikan alasan penolakan object hasil upload presign,
+// atau err jika object tidak bisa dibaca. Isi object diperiksa seperti
+// Upload, dengan contentType dari Presign sebagai tipe yang diklaim client.
+func (s *fileService) checkUpload(ctx context.Context, upload entity.FileUpload, info *domain.ObjectInfo) (rejected error, err error) {
+	if info.Size > s.config.MaxUploadBytes || info.Size != upload.Size {
+		return helper.ErrPayloadTooLarge, nil
 	}
 
-	// Simpan file
-	out, err := os.Create(savePath)
+	object, err := s.storage.Get(ctx, upload.FileKey)
 	if err != nil {
-		s.logger.Error(err.Error(), helper.FunctionCaller("FileHandler.Upload"))
-		s.logger.Error("Failed to save file", helper.FunctionCaller("FileHandler.Upload"))
-		return dto.FileUploadRespondPayload{}, helper.ErrInternalServer
+		return nil, err
 	}
-	defer out.Close()
+	defer object.Body.Close()
 
-	if _, err := io.Copy(out, file); err != nil {
-		s.logger.Error(err.Error(), helper.FunctionCaller("FileHandler.Upload"))
-		s.logger.Error("Failed to write file", helper.FunctionCaller("FileHandler.Upload"))
-		return dto.FileUploadRespondPayload{}, helper.ErrInternalServer
+	_, err = s.inspectImage(object.Body, upload.FileName, upload.ContentType)
+	if isRejection(err) {
+		return err, nil
 	}
+	return nil, err
+}
 
-	return dto.FileUploadRespondPayload{}, nil
+// fileKey adalah key object file di storage. Key dibuat server, sehingga
+// upload dengan nama yang sama tidak saling menimpa.
+func fileKey(managerID string, fileID string, ext string) string {
+	return fmt.Sprintf("file/%s/%s%s", managerID, fileID, ext)
 }
 
-func (s *fileService) DeleteByID(fileid string) error {
-	return nil
+// fileURI mengarah ke GET /files/:id jika FILE_BASE_URL diisi, atau langsung
+// ke storage.
+func (s *fileService) fileURI(fileID string, key string) string {
+	if s.config.BaseURL != "" {
+		return fmt.Sprintf("%s/files/%s", strings.TrimSuffix(s.config.BaseURL, "/"), fileID)
+	}
+	return s.storage.URL(key)
 }
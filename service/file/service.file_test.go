@@ -0,0 +1,240 @@
+package fileService_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/clock/clocktest"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/domain"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/infrastructure/storage"
+	"github.com/levensspel/go-gin-template/mocks"
+	fileService "github.com/levensspel/go-gin-template/service/file"
+	"github.com/levensspel/go-gin-template/thumbnail"
+)
+
+const testManagerID = "0b7c2d2e-4f4a-4b8e-9c59-2f1d8f6a3e10"
+
+// sequentialIDs membuat id file-1, file-2, dst.
+type sequentialIDs struct{ n int }
+
+func (s *sequentialIDs) NewID() string {
+	s.n++
+	return fmt.Sprintf("file-%d", s.n)
+}
+
+// fileFixture menjalankan FileService di atas MemoryStorage dan repository
+// di memori. created berisi file yang dicatat lewat Create.
+type fileFixture struct {
+	service fileService.FileService
+	storage domain.Storage
+	repo    *mocks.FileRepository
+	clock   *clocktest.Fake
+	created []entity.File
+}
+
+func newFileFixture(t *testing.T, fileConfig *config.FileConfig) *fileFixture {
+	t.Helper()
+	return newFileFixtureWithStorage(t, fileConfig, storage.NewMemoryStorage(""))
+}
+
+func newFileFixtureWithStorage(t *testing.T, fileConfig *config.FileConfig, s domain.Storage) *fileFixture {
+	t.Helper()
+	f := &fileFixture{storage: s, clock: clocktest.NewFake(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))}
+	f.repo = &mocks.FileRepository{
+		CreateFunc: func(ctx context.Context, file entity.File) (entity.File, error) {
+			f.created = append(f.created, file)
+			return file, nil
+		},
+	}
+	// THUMBNAIL_SIZE=0: generator tidak dijalankan
+	thumbnails := thumbnail.NewGenerator(context.Background(), f.repo, f.storage, nil, &config.ThumbnailConfig{}, fileConfig, mocks.Logger{})
+	f.service = fileService.NewFileService(
+		f.repo,
+		nil,
+		f.storage,
+		thumbnails,
+		nil,
+		&sequentialIDs{},
+		f.clock,
+		fileConfig,
+		&config.ResizeConfig{},
+		&config.QueryTimeoutConfig{},
+		mocks.Logger{},
+	)
+	return f
+}
+
+func testFileConfig() *config.FileConfig {
+	return &config.FileConfig{MaxUploadBytes: 2 << 20, MaxImageDimension: 10_000, MaxImagePixels: 40_000_000}
+}
+
+// encodeImage membuat gambar width x height dengan format png, jpeg, atau
+// gif.
+func encodeImage(t *testing.T, format string, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := range width {
+		for y := range height {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "png":
+		err = png.Encode(&buf, img)
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, nil)
+	case "gif":
+		err = gif.Encode(&buf, img, nil)
+	default:
+		t.Fatalf("unknown format %s", format)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func (f *fileFixture) stored(t *testing.T, key string) []byte {
+	t.Helper()
+	object, err := f.storage.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Get %s error = %v", key, err)
+	}
+	defer object.Body.Close()
+	content, err := io.ReadAll(object.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return content
+}
+
+func TestUploadStoresAndRecordsFile(t *testing.T) {
+	for _, tt := range []struct {
+		format      string
+		fileName    string
+		contentType string
+		wantKey     string
+	}{
+		{format: "png", fileName: "avatar.png", contentType: "image/png", wantKey: "file/" + testManagerID + "/file-1.png"},
+		{format: "jpeg", fileName: "avatar.jpg", contentType: "image/jpeg", wantKey: "file/" + testManagerID + "/file-1.jpg"},
+	} {
+		t.Run(tt.format, func(t *testing.T) {
+			f := newFileFixture(t, testFileConfig())
+			content := encodeImage(t, tt.format, 16, 8)
+
+			response, err := f.service.Upload(context.Background(), testManagerID, entity.FilePurposeEmployee, tt.fileName, tt.contentType, bytes.NewReader(content))
+			if err != nil {
+				t.Fatalf("Upload error = %v", err)
+			}
+			if response.FileId != "file-1" || response.Uri != f.storage.URL(tt.wantKey) {
+				t.Fatalf("Upload = %+v, want file-1 at %s", response, f.storage.URL(tt.wantKey))
+			}
+			if got := f.stored(t, tt.wantKey); !bytes.Equal(got, content) {
+				t.Fatalf("stored %d bytes, want the %d uploaded bytes", len(got), len(content))
+			}
+
+			if len(f.created) != 1 {
+				t.Fatalf("Create called %d times, want 1", len(f.created))
+			}
+			file := f.created[0]
+			if file.FileId != "file-1" || file.ManagerId != testManagerID || file.FileKey != tt.wantKey ||
+				file.Size != int64(len(content)) || file.ContentType != tt.contentType ||
+				file.FileName != tt.fileName || file.Purpose != entity.FilePurposeEmployee {
+				t.Fatalf("Create = %+v", file)
+			}
+		})
+	}
+}
+
+func TestUploadURIUsesBaseURL(t *testing.T) {
+	fileConfig := testFileConfig()
+	fileConfig.BaseURL = "https://api.example.com/"
+	f := newFileFixture(t, fileConfig)
+
+	response, err := f.service.Upload(context.Background(), testManagerID, entity.FilePurposeEmployee, "avatar.png", "", bytes.NewReader(encodeImage(t, "png", 4, 4)))
+	if err != nil {
+		t.Fatalf("Upload error = %v", err)
+	}
+	if response.Uri != "https://api.example.com/files/file-1" || f.created[0].FileURI != response.Uri {
+		t.Fatalf("Upload uri = %q, recorded %q, want https://api.example.com/files/file-1", response.Uri, f.created[0].FileURI)
+	}
+}
+
+func TestUploadRejectsContent(t *testing.T) {
+	pngContent := encodeImage(t, "png", 16, 16)
+	for _, tt := range []struct {
+		name     string
+		maxBytes int64
+		content  []byte
+		want     error
+	}{
+		{name: "gif", content: encodeImage(t, "gif", 4, 4), want: helper.ErrInvalidFileType},
+		{name: "text", content: []byte("hello, this is not an image"), want: helper.ErrInvalidFileType},
+		{name: "empty", content: nil, want: helper.ErrInvalidFileType},
+		{name: "html claiming png", content: []byte("<html><body>\x89PNG</body></html>"), want: helper.ErrInvalidFileType},
+		{name: "one byte too large", maxBytes: int64(len(pngContent)) - 1, content: pngContent, want: helper.ErrPayloadTooLarge},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fileConfig := testFileConfig()
+			if tt.maxBytes > 0 {
+				fileConfig.MaxUploadBytes = tt.maxBytes
+			}
+			f := newFileFixture(t, fileConfig)
+
+			_, err := f.service.Upload(context.Background(), testManagerID, entity.FilePurposeEmployee, "upload", "", bytes.NewReader(tt.content))
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Upload error = %v, want %v", err, tt.want)
+			}
+			if len(f.created) != 0 {
+				t.Fatalf("rejected upload was recorded: %+v", f.created)
+			}
+			for _, ext := range []string{".png", ".jpg"} {
+				if _, err := f.storage.Stat(context.Background(), "file/"+testManagerID+"/file-1"+ext); !errors.Is(err, helper.ErrNotFound) {
+					t.Fatalf("rejected upload left an object: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestUploadExactlyMaxBytes(t *testing.T) {
+	content := encodeImage(t, "png", 16, 16)
+	fileConfig := testFileConfig()
+	fileConfig.MaxUploadBytes = int64(len(content))
+	f := newFileFixture(t, fileConfig)
+
+	if _, err := f.service.Upload(context.Background(), testManagerID, entity.FilePurposeEmployee, "avatar.png", "", bytes.NewReader(content)); err != nil {
+		t.Fatalf("Upload error = %v", err)
+	}
+	if f.created[0].Size != int64(len(content)) {
+		t.Fatalf("recorded size = %d, want %d", f.created[0].Size, len(content))
+	}
+}
+
+func TestUploadRecordError(t *testing.T) {
+	f := newFileFixture(t, testFileConfig())
+	errCreate := errors.New("insert failed")
+	f.repo.CreateFunc = func(ctx context.Context, file entity.File) (entity.File, error) {
+		return entity.File{}, errCreate
+	}
+
+	_, err := f.service.Upload(context.Background(), testManagerID, entity.FilePurposeEmployee, "avatar.png", "", bytes.NewReader(encodeImage(t, "png", 4, 4)))
+	if !errors.Is(err, errCreate) {
+		t.Fatalf("Upload error = %v, want %v", err, errCreate)
+	}
+}
@@ -0,0 +1,140 @@
+package fileService_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"runtime"
+	"testing"
+	"testing/iotest"
+
+	"github.com/levensspel/go-gin-template/domain"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/infrastructure/storage"
+)
+
+// discardStorage membaca body Put sampai habis tanpa menyimpannya, sehingga
+// memori yang dipakai upload hanya milik FileService.
+type discardStorage struct {
+	domain.Storage
+	written int64
+}
+
+func (s *discardStorage) Put(ctx context.Context, key string, contentType string, body io.Reader, size int64, public bool) (string, error) {
+	n, err := io.Copy(io.Discard, body)
+	s.written += n
+	if err != nil {
+		return "", err
+	}
+	return s.URL(key), nil
+}
+
+// partialStorage menyimpan byte yang sempat terbaca walau body gagal di
+// tengah jalan, seperti driver yang tidak membatalkan object setengah jadi.
+type partialStorage struct {
+	*storage.MemoryStorage
+	deleted []string
+}
+
+func (s *partialStorage) Put(ctx context.Context, key string, contentType string, body io.Reader, size int64, public bool) (string, error) {
+	data, readErr := io.ReadAll(body)
+	url, err := s.MemoryStorage.Put(ctx, key, contentType, bytes.NewReader(data), int64(len(data)), public)
+	if readErr != nil {
+		return "", readErr
+	}
+	return url, err
+}
+
+func (s *partialStorage) Delete(ctx context.Context, key string) error {
+	s.deleted = append(s.deleted, key)
+	return s.MemoryStorage.Delete(ctx, key)
+}
+
+// zeros adalah body sebesar n byte nol yang dibuat saat dibaca.
+func zeros(n int64) io.Reader {
+	return io.LimitReader(zeroReader{}, n)
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+// TestUploadStreamsWithoutBuffering meng-upload 64MiB dan memastikan
+// alokasi selama upload jauh lebih kecil dari ukuran file.
+func TestUploadStreamsWithoutBuffering(t *testing.T) {
+	const size = 64 << 20
+	fileConfig := testFileConfig()
+	fileConfig.MaxUploadBytes = 2 * size
+	discard := &discardStorage{Storage: storage.NewMemoryStorage("")}
+	f := newFileFixtureWithStorage(t, fileConfig, discard)
+	// Header PNG asli lalu sisa file; isinya tidak diperiksa setelah header
+	header := encodeImage(t, "png", 16, 16)
+	body := io.MultiReader(bytes.NewReader(header), zeros(size))
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	if _, err := f.service.Upload(context.Background(), testManagerID, entity.FilePurposeEmployee, "avatar.png", "image/png", body); err != nil {
+		t.Fatalf("Upload error = %v", err)
+	}
+	runtime.ReadMemStats(&after)
+
+	want := int64(len(header)) + size
+	if discard.written != want || f.created[0].Size != want {
+		t.Fatalf("streamed %d bytes, recorded %d, want %d", discard.written, f.created[0].Size, want)
+	}
+	// Peek buffer inspectImage dan buffer io.Copy, bukan seluruh file
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 4<<20 {
+		t.Fatalf("Upload allocated %d bytes for a %d byte file", allocated, want)
+	}
+}
+
+func TestUploadCutOffMidStream(t *testing.T) {
+	header := encodeImage(t, "png", 16, 16)
+	for _, tt := range []struct {
+		name string
+		body func() io.Reader
+		want error
+	}{
+		{
+			// Batas ukuran terlewati jauh setelah peek buffer inspectImage
+			name: "size cap",
+			body: func() io.Reader { return io.MultiReader(bytes.NewReader(header), zeros(1<<20)) },
+			want: helper.ErrPayloadTooLarge,
+		},
+		{
+			name: "client read error",
+			body: func() io.Reader {
+				return io.MultiReader(bytes.NewReader(header), zeros(300<<10), iotest.ErrReader(io.ErrUnexpectedEOF))
+			},
+			want: helper.ErrBadRequest,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fileConfig := testFileConfig()
+			fileConfig.MaxUploadBytes = 512 << 10
+			partial := &partialStorage{MemoryStorage: storage.NewMemoryStorage("")}
+			f := newFileFixtureWithStorage(t, fileConfig, partial)
+
+			_, err := f.service.Upload(context.Background(), testManagerID, entity.FilePurposeEmployee, "avatar.png", "", tt.body())
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Upload error = %v, want %v", err, tt.want)
+			}
+			key := "file/" + testManagerID + "/file-1.png"
+			if len(partial.deleted) != 1 || partial.deleted[0] != key {
+				t.Fatalf("deleted %v, want the partial object %s", partial.deleted, key)
+			}
+			if _, err := partial.Stat(context.Background(), key); !errors.Is(err, helper.ErrNotFound) {
+				t.Fatalf("partial object still exists: %v", err)
+			}
+			if len(f.created) != 0 {
+				t.Fatalf("cut off upload was recorded: %+v", f.created)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package integrationService
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/levensspel/go-gin-template/clock"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	repositories "github.com/levensspel/go-gin-template/repository/integration"
+	"github.com/levensspel/go-gin-template/signing"
+	"github.com/samber/do/v2"
+)
+
+// ErrCallbackReplayed berarti callback dengan signature yang sama sudah
+// pernah diterima, yaitu request yang dikirim ulang dalam tolerance.
+var ErrCallbackReplayed = errors.New("callback replayed")
+
+func init() {
+	helper.Register(ErrCallbackReplayed, http.StatusConflict, "ErrCallbackReplayed")
+}
+
+type IntegrationService interface {
+	// ReceiveCallback memverifikasi signature callback dengan secret
+	// integrasi lalu menyimpannya ke antrian, dan mengembalikan id-nya.
+	ReceiveCallback(ctx context.Context, integration, signature, timestamp string, body []byte) (int64, error)
+}
+
+type service struct {
+	repo   repositories.IntegrationRepositoryInterface
+	clock  clock.Clock
+	config *config.IntegrationConfig
+}
+
+func New(
+	repo repositories.IntegrationRepositoryInterface,
+	clock clock.Clock,
+	config *config.IntegrationConfig,
+) IntegrationService {
+	return &service{repo: repo, clock: clock, config: config}
+}
+
+func NewInject(i do.Injector) (IntegrationService, error) {
+	_repo := do.MustInvoke[repositories.IntegrationRepositoryInterface](i)
+	_clock := do.MustInvoke[clock.Clock](i)
+	return New(_repo, _clock, config.LoadIntegrationConfig()), nil
+}
+
+func (s *service) ReceiveCallback(ctx context.Context, integration, signature, timestamp string, body []byte) (int64, error) {
+	secret, ok := s.config.Secrets[integration]
+	if !ok {
+		// Sama dengan signature salah, agar id integrasi yang terdaftar
+		// tidak bisa ditebak
+		return 0, signing.ErrSignatureMismatch
+	}
+	signedAt, err := signing.Verify(secret, signature, timestamp, body, s.clock.Now(), s.config.SignatureTolerance)
+	if err != nil {
+		return 0, err
+	}
+
+	// Timestamp lama sudah ditolak Verify, replay dalam tolerance ditolak
+	// unique (integration, signature) di tabel. Signature disimpan dalam
+	// bentuk kanonis agar hex huruf besar tidak lolos sebagai callback baru.
+	id, err := s.repo.Enqueue(ctx, entity.IntegrationCallback{
+		Integration: integration,
+		Signature:   signing.Sign(secret, signedAt, body),
+		SignedAt:    signedAt,
+		Payload:     body,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if id == 0 {
+		return 0, ErrCallbackReplayed
+	}
+	return id, nil
+}
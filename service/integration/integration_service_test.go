@@ -0,0 +1,143 @@
+package integrationService
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/clock/clocktest"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/mocks"
+	"github.com/levensspel/go-gin-template/signing"
+)
+
+const (
+	testIntegration = "partner-a"
+	testSecret      = "partner-a-secret"
+)
+
+var testNow = time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+// callbackFixture menyimpan callback di queue dan menolak signature yang
+// sudah ada seperti unique constraint integration_callback.
+type callbackFixture struct {
+	clock *clocktest.Fake
+	queue []entity.IntegrationCallback
+	err   error
+}
+
+func (f *callbackFixture) service() IntegrationService {
+	repo := &mocks.IntegrationRepository{
+		EnqueueFunc: func(ctx context.Context, callback entity.IntegrationCallback) (int64, error) {
+			if f.err != nil {
+				return 0, f.err
+			}
+			for _, queued := range f.queue {
+				if queued.Integration == callback.Integration && queued.Signature == callback.Signature {
+					return 0, nil
+				}
+			}
+			f.queue = append(f.queue, callback)
+			return int64(len(f.queue)), nil
+		},
+	}
+	return New(repo, f.clock, &config.IntegrationConfig{
+		Secrets:            map[string]string{testIntegration: testSecret, "partner-b": "partner-b-secret"},
+		SignatureTolerance: 5 * time.Minute,
+	})
+}
+
+func newCallbackFixture() *callbackFixture {
+	return &callbackFixture{clock: clocktest.NewFake(testNow)}
+}
+
+func signed(secret string, at time.Time, body []byte) (signature, timestamp string) {
+	return signing.Sign(secret, at, body), strconv.FormatInt(at.Unix(), 10)
+}
+
+func TestReceiveCallback(t *testing.T) {
+	f := newCallbackFixture()
+	body := []byte(`{"event":"sync.completed"}`)
+	signature, timestamp := signed(testSecret, testNow.Add(-time.Minute), body)
+
+	id, err := f.service().ReceiveCallback(context.Background(), testIntegration, signature, timestamp, body)
+	if err != nil || id != 1 {
+		t.Fatalf("ReceiveCallback = %d, %v", id, err)
+	}
+	got := f.queue[0]
+	if got.Integration != testIntegration || got.Signature != signature || string(got.Payload) != string(body) ||
+		!got.SignedAt.Equal(testNow.Add(-time.Minute)) {
+		t.Fatalf("queued = %+v", got)
+	}
+}
+
+func TestReceiveCallbackRejectsReplay(t *testing.T) {
+	f := newCallbackFixture()
+	s := f.service()
+	body := []byte(`{"event":"sync.completed"}`)
+	signature, timestamp := signed(testSecret, testNow, body)
+
+	if _, err := s.ReceiveCallback(context.Background(), testIntegration, signature, timestamp, body); err != nil {
+		t.Fatalf("first callback error = %v", err)
+	}
+	// Dikirim ulang dalam tolerance, dengan hex huruf besar
+	f.clock.Advance(time.Minute)
+	upper := signing.Prefix + strings.ToUpper(strings.TrimPrefix(signature, signing.Prefix))
+	for _, resent := range []string{signature, upper} {
+		if _, err := s.ReceiveCallback(context.Background(), testIntegration, resent, timestamp, body); !errors.Is(err, ErrCallbackReplayed) {
+			t.Fatalf("resent %q error = %v, want %v", resent, err, ErrCallbackReplayed)
+		}
+	}
+	// Setelah tolerance, replay ditolak oleh timestamp
+	f.clock.Advance(5 * time.Minute)
+	if _, err := s.ReceiveCallback(context.Background(), testIntegration, signature, timestamp, body); !errors.Is(err, signing.ErrTimestampOutOfRange) {
+		t.Fatalf("resent after tolerance error = %v, want %v", err, signing.ErrTimestampOutOfRange)
+	}
+	if len(f.queue) != 1 {
+		t.Fatalf("queued %d callbacks, want 1", len(f.queue))
+	}
+
+	// Body yang sama dari integrasi lain adalah callback yang berbeda
+	signature, timestamp = signed("partner-b-secret", f.clock.Now(), body)
+	if _, err := s.ReceiveCallback(context.Background(), "partner-b", signature, timestamp, body); err != nil {
+		t.Fatalf("partner-b callback error = %v", err)
+	}
+}
+
+func TestReceiveCallbackErrors(t *testing.T) {
+	body := []byte(`{"event":"sync.completed"}`)
+	errDB := errors.New("insert failed")
+	for _, tt := range []struct {
+		name        string
+		integration string
+		secret      string
+		signedAt    time.Time
+		repoErr     error
+		want        error
+	}{
+		// Integrasi tidak dikenal tidak bisa dibedakan dari signature salah
+		{name: "unknown integration", integration: "partner-x", secret: testSecret, signedAt: testNow, want: signing.ErrSignatureMismatch},
+		{name: "secret of another integration", integration: testIntegration, secret: "partner-b-secret", signedAt: testNow, want: signing.ErrSignatureMismatch},
+		{name: "expired", integration: testIntegration, secret: testSecret, signedAt: testNow.Add(-6 * time.Minute), want: signing.ErrTimestampOutOfRange},
+		{name: "sender clock ahead", integration: testIntegration, secret: testSecret, signedAt: testNow.Add(6 * time.Minute), want: signing.ErrTimestampOutOfRange},
+		{name: "repository error", integration: testIntegration, secret: testSecret, signedAt: testNow, repoErr: errDB, want: errDB},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newCallbackFixture()
+			f.err = tt.repoErr
+			signature, timestamp := signed(tt.secret, tt.signedAt, body)
+
+			id, err := f.service().ReceiveCallback(context.Background(), tt.integration, signature, timestamp, body)
+			if !errors.Is(err, tt.want) || id != 0 {
+				t.Fatalf("ReceiveCallback = %d, %v, want %v", id, err, tt.want)
+			}
+			if len(f.queue) != 0 {
+				t.Fatalf("queued %d callbacks after an error", len(f.queue))
+			}
+		})
+	}
+}
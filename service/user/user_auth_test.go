@@ -0,0 +1,178 @@
+package userService_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const testPassword = "correct-horse"
+
+func registerPayload(email string) dto.UserRequestPayload {
+	return dto.UserRequestPayload{Email: email, Password: testPassword, Action: dto.Create}
+}
+
+func TestRegisterUser(t *testing.T) {
+	f := newUserFixture()
+	var created entity.User
+	f.user.CreateFunc = func(ctx context.Context, user entity.User) (string, error) {
+		created = user
+		return testManagerID, nil
+	}
+
+	response, err := f.service().RegisterUser(context.Background(), registerPayload("register@example.com"))
+	if err != nil {
+		t.Fatalf("RegisterUser error = %v", err)
+	}
+	if response.Email != "register@example.com" || response.Token != "token:"+testManagerID {
+		t.Fatalf("RegisterUser = %+v, want a token for the created user", response)
+	}
+	if created.Email.String != "register@example.com" {
+		t.Fatalf("created email = %q", created.Email.String)
+	}
+	if now := f.clock.Now().Unix(); created.CreatedAt != now || created.UpdatedAt != now {
+		t.Fatalf("created createdAt = %d, updatedAt = %d, want both %d", created.CreatedAt, created.UpdatedAt, now)
+	}
+	// Password disimpan sebagai hash bcrypt, bukan teks asli
+	if created.Password == testPassword || bcrypt.CompareHashAndPassword([]byte(created.Password), []byte(testPassword)) != nil {
+		t.Fatalf("created password %q is not a bcrypt hash of the input", created.Password)
+	}
+}
+
+func TestRegisterUserErrors(t *testing.T) {
+	errInsert := errors.New("insert failed")
+	tests := []struct {
+		name      string
+		input     dto.UserRequestPayload
+		createErr error
+		want      error
+		// wantCreate berarti repository Create harus dipanggil
+		wantCreate bool
+	}{
+		{name: "invalid email", input: registerPayload("not-an-email"), want: nil},
+		{
+			name:  "short password",
+			input: dto.UserRequestPayload{Email: "register@example.com", Password: "short", Action: dto.Create},
+			want:  nil,
+		},
+		{
+			name:       "email already registered",
+			input:      registerPayload("register@example.com"),
+			createErr:  &pgconn.PgError{Code: "23505", Message: "duplicate key value violates unique constraint"},
+			want:       helper.ErrConflict,
+			wantCreate: true,
+		},
+		{name: "repository error", input: registerPayload("register@example.com"), createErr: errInsert, want: errInsert, wantCreate: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newUserFixture()
+			created := false
+			f.user.CreateFunc = func(ctx context.Context, user entity.User) (string, error) {
+				created = true
+				return "", tt.createErr
+			}
+
+			_, err := f.service().RegisterUser(context.Background(), tt.input)
+			if err == nil {
+				t.Fatal("RegisterUser error = nil")
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Fatalf("RegisterUser error = %v, want %v", err, tt.want)
+			}
+			// Error validasi menjadi 400
+			if tt.want == nil {
+				if status, _ := helper.FromError(err); status != http.StatusBadRequest {
+					t.Fatalf("RegisterUser error %v maps to %d, want %d", err, status, http.StatusBadRequest)
+				}
+			}
+			if created != tt.wantCreate {
+				t.Fatalf("repository Create called = %t, want %t", created, tt.wantCreate)
+			}
+		})
+	}
+}
+
+// loginFixture mendaftarkan satu user dengan testPassword.
+func loginFixture(t *testing.T) *userFixture {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := newUserFixture()
+	f.user.GetUserbyEmailFunc = func(ctx context.Context, email string) ([]entity.User, error) {
+		if email != "login@example.com" {
+			return nil, nil
+		}
+		user := entity.User{Id: testManagerID, Password: string(hash)}
+		user.Email.String = email
+		return []entity.User{user}, nil
+	}
+	return f
+}
+
+func loginPayload(email, password string) dto.UserRequestPayload {
+	return dto.UserRequestPayload{Email: email, Password: password, Action: dto.Login}
+}
+
+func TestLogin(t *testing.T) {
+	f := loginFixture(t)
+	response, err := f.service().Login(context.Background(), loginPayload("login@example.com", testPassword))
+	if err != nil {
+		t.Fatalf("Login error = %v", err)
+	}
+	if response.Email != "login@example.com" || response.Token != "token:"+testManagerID {
+		t.Fatalf("Login = %+v", response)
+	}
+	if got := testutil.ToFloat64(f.metrics.AuthLogins.WithLabelValues("success")); got != 1 {
+		t.Fatalf("successful logins = %v, want 1", got)
+	}
+}
+
+func TestLoginErrors(t *testing.T) {
+	errLookup := errors.New("lookup failed")
+	tests := []struct {
+		name      string
+		input     dto.UserRequestPayload
+		lookupErr error
+		want      error
+		// wantCounted berarti percobaan dihitung sebagai login gagal
+		wantCounted bool
+	}{
+		{name: "invalid input", input: loginPayload("not-an-email", testPassword), wantCounted: false},
+		{name: "unknown email", input: loginPayload("unknown@example.com", testPassword), want: helper.ErrNotFound, wantCounted: true},
+		{name: "wrong password", input: loginPayload("login@example.com", "wrong-password"), want: helper.ErrorInvalidLogin, wantCounted: true},
+		{name: "repository error", input: loginPayload("login@example.com", testPassword), lookupErr: errLookup, want: errLookup, wantCounted: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := loginFixture(t)
+			if tt.lookupErr != nil {
+				f.user.GetUserbyEmailFunc = func(ctx context.Context, email string) ([]entity.User, error) {
+					return nil, tt.lookupErr
+				}
+			}
+
+			response, err := f.service().Login(context.Background(), tt.input)
+			if err == nil || response.Token != "" {
+				t.Fatalf("Login = %+v, %v, want an error and no token", response, err)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Fatalf("Login error = %v, want %v", err, tt.want)
+			}
+			failures := testutil.ToFloat64(f.metrics.AuthLogins.WithLabelValues("failure"))
+			if counted := failures == 1; counted != tt.wantCounted {
+				t.Fatalf("failed logins = %v, want counted %t", failures, tt.wantCounted)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package userService
+
+import (
+	"context"
+	"database/sql"
+	"io"
+
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/repository"
+	"github.com/levensspel/go-gin-template/telemetry"
+)
+
+// ProfileImage adalah gambar profil manager yang bisa diganti lewat upload.
+type ProfileImage string
+
+const (
+	// ProfileImageAvatar adalah userImageUri, lihat PUT /v1/user/avatar
+	ProfileImageAvatar ProfileImage = "avatar"
+	// ProfileImageCompanyLogo adalah companyImageUri, lihat
+	// PUT /v1/user/company-logo
+	ProfileImageCompanyLogo ProfileImage = "company_logo"
+)
+
+func (s *UserService) ReplaceProfileImage(
+	ctx context.Context,
+	id string,
+	image ProfileImage,
+	fileName string,
+	claimedType string,
+	body io.Reader,
+) (_ *dto.ResposneGetProfile, err error) {
+	ctx, span := s.tracer.Start(ctx, "UserService.ReplaceProfileImage")
+	defer func() { telemetry.End(span, err) }()
+
+	upload, err := s.files.Upload(ctx, id, entity.FilePurposeProfile, fileName, claimedType, body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Jika transaksi gagal, file baru tidak pernah dirujuk dan dihapus
+	// purge.Cleaner setelah CLEANUP_FILE_GRACE_PERIOD
+	var after *dto.ResposneGetProfile
+	err = s.uow.Do(ctx, func(repos repository.Repositories) error {
+		profile, err := repos.User.GetProfile(ctx, id)
+		if err != nil {
+			return err
+		}
+		before := profileResponse(profile)
+
+		uri := sql.NullString{String: upload.Uri, Valid: true}
+		switch image {
+		case ProfileImageAvatar:
+			profile.UserImageUri = uri
+		case ProfileImageCompanyLogo:
+			profile.CompanyImageUri = uri
+		}
+		if err := repos.User.UpdateProfile(ctx, id, profile); err != nil {
+			return err
+		}
+		after = profileResponse(profile)
+		if err := referenceProfileImages(ctx, repos, before, after); err != nil {
+			return err
+		}
+		return recordUser(ctx, repos, entity.AuditActionUpdate, id, before, after)
+	})
+	if err != nil {
+		s.logger.Error(err.Error(), helper.UserServiceReplaceProfileImage, upload.FileId)
+		return nil, err
+	}
+	s.profileCache.Invalidate(id)
+	return after, nil
+}
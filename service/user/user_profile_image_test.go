@@ -0,0 +1,150 @@
+package userService_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"image"
+	"image/png"
+	"slices"
+	"testing"
+
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/infrastructure/storage"
+	"github.com/levensspel/go-gin-template/mocks"
+	fileService "github.com/levensspel/go-gin-template/service/file"
+	service "github.com/levensspel/go-gin-template/service/user"
+	"github.com/levensspel/go-gin-template/thumbnail"
+)
+
+// fixedID selalu memberi id file yang sama.
+type fixedID string
+
+func (id fixedID) NewID() string { return string(id) }
+
+// installFiles memasang FileService asli di atas MemoryStorage dengan
+// FILE_BASE_URL https://api.example.com, dan mengembalikan file yang dicatat
+// lewat Create.
+func (f *userFixture) installFiles(t *testing.T) *[]entity.File {
+	t.Helper()
+	var created []entity.File
+	repo := &mocks.FileRepository{
+		CreateFunc: func(ctx context.Context, file entity.File) (entity.File, error) {
+			created = append(created, file)
+			return file, nil
+		},
+	}
+	memory := storage.NewMemoryStorage("")
+	fileConfig := &config.FileConfig{BaseURL: "https://api.example.com", MaxUploadBytes: 1 << 20, MaxImageDimension: 1000, MaxImagePixels: 1_000_000}
+	thumbnails := thumbnail.NewGenerator(context.Background(), repo, memory, nil, &config.ThumbnailConfig{}, fileConfig, mocks.Logger{})
+	f.files = fileService.NewFileService(repo, nil, memory, thumbnails, nil, fixedID("new-image"), f.clock, fileConfig, &config.ResizeConfig{}, &config.QueryTimeoutConfig{}, mocks.Logger{})
+	return &created
+}
+
+func pngContent(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 8, 8))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReplaceProfileImage(t *testing.T) {
+	const newURI = "https://api.example.com/files/new-image"
+	for _, tt := range []struct {
+		name        string
+		image       service.ProfileImage
+		wantAvatar  string
+		wantLogo    string
+		wantRemoved string
+	}{
+		{
+			name:        "avatar",
+			image:       service.ProfileImageAvatar,
+			wantAvatar:  newURI,
+			wantLogo:    "https://api.example.com/files/logo",
+			wantRemoved: "https://api.example.com/files/avatar",
+		},
+		{
+			name:        "company logo",
+			image:       service.ProfileImageCompanyLogo,
+			wantAvatar:  "https://api.example.com/files/avatar",
+			wantLogo:    newURI,
+			wantRemoved: "https://api.example.com/files/logo",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newUserFixture()
+			f.profile.UserImageUri = sql.NullString{String: "https://api.example.com/files/avatar", Valid: true}
+			f.profile.CompanyImageUri = sql.NullString{String: "https://api.example.com/files/logo", Valid: true}
+			created := f.installFiles(t)
+			var refs referenceRecorder
+			refs.install(f)
+			s := f.service()
+			// Profil lama masuk cache sebelum gambar diganti
+			getProfile(t, s)
+
+			profile, err := s.ReplaceProfileImage(context.Background(), testManagerID, tt.image, "image.png", "image/png", bytes.NewReader(pngContent(t)))
+			if err != nil {
+				t.Fatalf("ReplaceProfileImage error = %v", err)
+			}
+			if profile.UserImageUri != tt.wantAvatar || profile.CompanyImageUri != tt.wantLogo {
+				t.Fatalf("profile = %+v, want avatar %s and logo %s", profile, tt.wantAvatar, tt.wantLogo)
+			}
+			if len(*created) != 1 || (*created)[0].Purpose != entity.FilePurposeProfile || (*created)[0].ManagerId != testManagerID {
+				t.Fatalf("recorded %+v, want one profile file", *created)
+			}
+			// Gambar lama dilepas agar purge.Cleaner bisa menghapusnya
+			if !slices.Equal(refs.added, []string{newURI}) || !slices.Equal(refs.removed, []string{tt.wantRemoved}) {
+				t.Fatalf("references added %v, removed %v", refs.added, refs.removed)
+			}
+			if !f.uow.Committed || len(f.audits) != 1 || f.audits[0].Action != entity.AuditActionUpdate {
+				t.Fatalf("committed = %v, audits = %+v", f.uow.Committed, f.audits)
+			}
+			if cached := getProfile(t, s); cached.UserImageUri != tt.wantAvatar || cached.CompanyImageUri != tt.wantLogo {
+				t.Fatalf("GetProfile after replace = %+v, cache was not invalidated", cached)
+			}
+		})
+	}
+}
+
+func TestReplaceProfileImageRejectsUpload(t *testing.T) {
+	f := newUserFixture()
+	f.profile.UserImageUri = sql.NullString{String: "https://api.example.com/files/avatar", Valid: true}
+	created := f.installFiles(t)
+	var refs referenceRecorder
+	refs.install(f)
+
+	_, err := f.service().ReplaceProfileImage(context.Background(), testManagerID, service.ProfileImageAvatar, "avatar.png", "image/png", bytes.NewReader([]byte("not an image")))
+	if !errors.Is(err, helper.ErrInvalidFileType) {
+		t.Fatalf("ReplaceProfileImage error = %v, want %v", err, helper.ErrInvalidFileType)
+	}
+	if len(*created) != 0 || len(refs.added) != 0 || len(refs.removed) != 0 {
+		t.Fatalf("rejected upload recorded %+v, references added %v, removed %v", *created, refs.added, refs.removed)
+	}
+	if f.profile.UserImageUri.String != "https://api.example.com/files/avatar" {
+		t.Fatalf("avatar = %q after a rejected upload", f.profile.UserImageUri.String)
+	}
+}
+
+func TestReplaceProfileImageRollsBack(t *testing.T) {
+	f := newUserFixture()
+	f.profile.UserImageUri = sql.NullString{String: "https://api.example.com/files/avatar", Valid: true}
+	f.installFiles(t)
+	errUpdate := errors.New("update failed")
+	f.user.UpdateProfileFunc = func(ctx context.Context, id string, data *entity.GetProfile) error {
+		return errUpdate
+	}
+
+	_, err := f.service().ReplaceProfileImage(context.Background(), testManagerID, service.ProfileImageAvatar, "avatar.png", "image/png", bytes.NewReader(pngContent(t)))
+	if !errors.Is(err, errUpdate) {
+		t.Fatalf("ReplaceProfileImage error = %v, want %v", err, errUpdate)
+	}
+	if f.uow.Committed || len(f.audits) != 0 {
+		t.Fatalf("committed = %v, audits = %+v after a failed update", f.uow.Committed, f.audits)
+	}
+}
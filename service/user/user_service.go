@@ -5,54 +5,111 @@ import (
 	"database/sql"
 	"fmt"
 	"github.com/levensspel/go-gin-template/cache"
+	"github.com/levensspel/go-gin-template/clock"
+	"io"
 	"strings"
-	"time"
 
+	"github.com/levensspel/go-gin-template/audit"
 	"github.com/levensspel/go-gin-template/auth"
+	"github.com/levensspel/go-gin-template/config"
 	"github.com/levensspel/go-gin-template/dto"
 	"github.com/levensspel/go-gin-template/entity"
 	"github.com/levensspel/go-gin-template/helper"
 	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/lru"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/repository"
 	repositories "github.com/levensspel/go-gin-template/repository/user"
+	fileService "github.com/levensspel/go-gin-template/service/file"
+	"github.com/levensspel/go-gin-template/telemetry"
 	"github.com/levensspel/go-gin-template/validation"
 	"github.com/samber/do/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/crypto/bcrypt"
 )
 
 const IsUserReadHeavy = true // Caching is suitable for read heavy operations
 
 type IUserService interface {
-	RegisterUser(input dto.UserRequestPayload) (dto.ResponseRegister, error)
-	Login(input dto.UserRequestPayload) (dto.ResponseLogin, error)
-	Update(input dto.RequestRegister) (dto.Response, error)
-	DeleteByID(id string) error
-	GetProfile(managerid string) (*dto.ResposneGetProfile, error)
-	UpdateProfile(managerid string, input dto.RequestUpdateProfile) (*dto.RequestUpdateProfile, error)
+	RegisterUser(ctx context.Context, input dto.UserRequestPayload) (dto.ResponseRegister, error)
+	Login(ctx context.Context, input dto.UserRequestPayload) (dto.ResponseLogin, error)
+	Update(ctx context.Context, input dto.RequestRegister) (dto.Response, error)
+	DeleteByID(ctx context.Context, id string) error
+	GetProfile(ctx context.Context, managerid string) (*dto.ResposneGetProfile, error)
+	UpdateProfile(ctx context.Context, managerid string, input dto.RequestUpdateProfile) (*dto.RequestUpdateProfile, error)
+	// ReplaceProfileImage meng-upload body seperti POST /v1/file lalu
+	// memasang uri-nya sebagai gambar profil image dalam satu transaksi.
+	// Gambar lama tidak lagi dirujuk dan nantinya dihapus purge.Cleaner.
+	ReplaceProfileImage(ctx context.Context, managerid string, image ProfileImage, fileName string, claimedType string, body io.Reader) (*dto.ResposneGetProfile, error)
 }
 
 type UserService struct {
-	userRepo repositories.UserRepository
-	logger   logger.LogHandler
+	userRepo     repositories.UserRepositoryInterface
+	uow          repository.UnitOfWork
+	files        fileService.FileService
+	tokens       auth.Service
+	clock        clock.Clock
+	logger       logger.Logger
+	tracer       trace.Tracer
+	metrics      *metrics.Metrics
+	profileCache *lru.Cache[string, dto.ResposneGetProfile]
 }
 
 func NewUserService(
-	userRepo repositories.UserRepository,
-	logger logger.LogHandler,
-) UserService {
-	return UserService{
-		userRepo: userRepo,
-		logger:   logger,
+	userRepo repositories.UserRepositoryInterface,
+	uow repository.UnitOfWork,
+	files fileService.FileService,
+	tokens auth.Service,
+	clock clock.Clock,
+	logger logger.Logger,
+	tracer trace.Tracer,
+	metrics *metrics.Metrics,
+	profileCache *lru.Cache[string, dto.ResposneGetProfile],
+) IUserService {
+	return &UserService{
+		userRepo:     userRepo,
+		uow:          uow,
+		files:        files,
+		tokens:       tokens,
+		clock:        clock,
+		logger:       logger,
+		tracer:       tracer,
+		metrics:      metrics,
+		profileCache: profileCache,
 	}
 }
 
-func NewUserServiceInject(i do.Injector) (UserService, error) {
-	_userRepo := do.MustInvoke[repositories.UserRepository](i)
+func NewUserServiceInject(i do.Injector) (IUserService, error) {
+	_userRepo := do.MustInvoke[repositories.UserRepositoryInterface](i)
+	_uow := do.MustInvoke[repository.UnitOfWork](i)
+	_files := do.MustInvoke[fileService.FileService](i)
+	_tokens := do.MustInvoke[auth.Service](i)
+	_clock := do.MustInvoke[clock.Clock](i)
 	_logger := do.MustInvoke[logger.LogHandler](i)
-	return NewUserService(_userRepo, _logger), nil
+	_metrics := do.MustInvoke[*metrics.Metrics](i)
+	cacheConfig := config.LoadCacheConfig()
+	profileCache := lru.New[string, dto.ResposneGetProfile](lru.Settings{
+		Name:       "manager_profile",
+		MaxEntries: cacheConfig.MaxEntries,
+		TTL:        cacheConfig.ProfileTTL,
+		OnHit:      _metrics.CountCacheHit,
+		OnMiss:     _metrics.CountCacheMiss,
+		OnEvict:    _metrics.CountCacheEvictions,
+		Clock:      _clock,
+	})
+	_tracer := do.MustInvoke[trace.Tracer](i)
+	return NewUserService(_userRepo, _uow, _files, _tokens, _clock, &_logger, _tracer, _metrics, profileCache), nil
 }
 
-func (s *UserService) RegisterUser(input dto.UserRequestPayload) (dto.ResponseRegister, error) {
-	err := validation.ValidateUserCreate(input, s.userRepo)
+func (s *UserService) RegisterUser(ctx context.Context, input dto.UserRequestPayload) (response dto.ResponseRegister, err error) {
+	ctx, span := s.tracer.Start(ctx, "UserService.RegisterUser")
+	defer func() { telemetry.End(span, err) }()
+
+	// Validasi termasuk pengecekan email yang sudah terdaftar di database
+	err = telemetry.Step(ctx, s.tracer, "user.validate", func(ctx context.Context) error {
+		return validation.ValidateUserCreate(input, s.userRepo)
+	})
 	if err != nil {
 		return dto.ResponseRegister{}, err
 	}
@@ -65,10 +122,15 @@ func (s *UserService) RegisterUser(input dto.UserRequestPayload) (dto.ResponseRe
 	user := entity.User{}
 
 	user.Email.String = input.Email
-	user.CreatedAt = time.Now().Unix()
-	user.UpdatedAt = time.Now().Unix()
+	now := s.clock.Now().Unix()
+	user.CreatedAt = now
+	user.UpdatedAt = now
 
-	passwordHash, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.MinCost)
+	var passwordHash []byte
+	err = telemetry.Step(ctx, s.tracer, "user.hash_password", func(ctx context.Context) (err error) {
+		passwordHash, err = bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.MinCost)
+		return err
+	})
 
 	if err != nil {
 		s.logger.Error(err.Error(), helper.GenerateFromPassword, passwordHash)
@@ -76,7 +138,10 @@ func (s *UserService) RegisterUser(input dto.UserRequestPayload) (dto.ResponseRe
 	}
 	user.Password = string(passwordHash)
 
-	user.Id, err = s.userRepo.Create(context.Background(), user)
+	err = telemetry.Step(ctx, s.tracer, "user.insert", func(ctx context.Context) (err error) {
+		user.Id, err = s.userRepo.Create(ctx, user)
+		return err
+	})
 
 	if err != nil {
 		if strings.Contains(err.Error(), "23505") {
@@ -87,15 +152,14 @@ func (s *UserService) RegisterUser(input dto.UserRequestPayload) (dto.ResponseRe
 		}
 	}
 
-	jwtService := auth.NewJWTService()
-	token, err := jwtService.GenerateToken(user.Id)
+	token, err := s.generateToken(ctx, user.Id)
 
 	if err != nil {
 		s.logger.Error(err.Error(), helper.UserServiceRegister, err)
 		return dto.ResponseRegister{}, err
 	}
 
-	response := dto.ResponseRegister{
+	response = dto.ResponseRegister{
 		Email: user.Email.String,
 		Token: token,
 	}
@@ -103,14 +167,28 @@ func (s *UserService) RegisterUser(input dto.UserRequestPayload) (dto.ResponseRe
 	return response, nil
 }
 
-func (s *UserService) Login(input dto.UserRequestPayload) (dto.ResponseLogin, error) {
-	err := validation.ValidateUserLogin(input)
+func (s *UserService) Login(ctx context.Context, input dto.UserRequestPayload) (response dto.ResponseLogin, err error) {
+	ctx, span := s.tracer.Start(ctx, "UserService.Login")
+	defer func() { telemetry.End(span, err) }()
+
+	err = validation.ValidateUserLogin(input)
 	if err != nil {
 		return dto.ResponseLogin{}, err
 	}
+	// Input yang tidak valid tidak dihitung sebagai percobaan login
+	defer func() { s.metrics.CountLogin(err == nil) }()
 
-	// Check cache first
-	cachedToken, found := cache.Get(fmt.Sprintf(cache.CacheAuthEmailToToken, input.Email))
+	// Check cache first. Token di cache bisa saja sudah dicabut (admin
+	// sessions revoke) atau kedaluwarsa, jadi divalidasi ulang.
+	tokenKey := fmt.Sprintf(cache.CacheAuthEmailToToken, input.Email)
+	cachedToken, found := cache.Get(tokenKey)
+	if found {
+		if _, err := s.tokens.ParseToken(ctx, cachedToken); err != nil {
+			cache.Delete(tokenKey)
+			found = false
+		}
+	}
+	span.SetAttributes(attribute.Bool("cache.hit", found))
 	if found {
 		return dto.ResponseLogin{
 			Email: input.Email,
@@ -120,7 +198,11 @@ func (s *UserService) Login(input dto.UserRequestPayload) (dto.ResponseLogin, er
 
 	//get user
 	fmt.Printf("email %s", input.Email)
-	user, err := s.userRepo.GetUserbyEmail(context.Background(), input.Email)
+	var user []entity.User
+	err = telemetry.Step(ctx, s.tracer, "user.lookup", func(ctx context.Context) (err error) {
+		user, err = s.userRepo.GetUserbyEmail(ctx, input.Email)
+		return err
+	})
 	if err != nil {
 		s.logger.Error(err.Error(), helper.FunctionCaller("UserService.Login.GetUserbyEmail"), input)
 		return dto.ResponseLogin{}, err
@@ -130,14 +212,15 @@ func (s *UserService) Login(input dto.UserRequestPayload) (dto.ResponseLogin, er
 	}
 
 	// password compared
-	err = bcrypt.CompareHashAndPassword([]byte(user[0].Password), []byte(input.Password))
+	err = telemetry.Step(ctx, s.tracer, "user.compare_password", func(ctx context.Context) error {
+		return bcrypt.CompareHashAndPassword([]byte(user[0].Password), []byte(input.Password))
+	})
 	if err != nil {
 		s.logger.Error(err.Error(), helper.FunctionCaller("UserService.Login.CompareHashAndPassword"), err)
 		return dto.ResponseLogin{}, helper.ErrorInvalidLogin
 	}
 
-	jwtService := auth.NewJWTService()
-	token, err := jwtService.GenerateToken(user[0].Id)
+	token, err := s.generateToken(ctx, user[0].Id)
 
 	if err != nil {
 		s.logger.Error(err.Error(), helper.UserServiceLogin, err)
@@ -145,15 +228,23 @@ func (s *UserService) Login(input dto.UserRequestPayload) (dto.ResponseLogin, er
 	}
 
 	// Put to cache
-	cache.Set(fmt.Sprintf(cache.CacheAuthEmailToToken, input.Email), token)
+	cache.Set(tokenKey, token)
 
-	response := dto.ResponseLogin{}
 	response.Email = user[0].Email.String
 	response.Token = token
 	return response, nil
 }
 
-func (s *UserService) Update(input dto.RequestRegister) (dto.Response, error) {
+// generateToken membuat JWT dalam child span "auth.token".
+func (s *UserService) generateToken(ctx context.Context, id string) (token string, err error) {
+	err = telemetry.Step(ctx, s.tracer, "auth.token", func(ctx context.Context) (err error) {
+		token, err = s.tokens.GenerateToken(id)
+		return err
+	})
+	return token, err
+}
+
+func (s *UserService) Update(ctx context.Context, input dto.RequestRegister) (dto.Response, error) {
 	user := entity.User{}
 	user.Id = input.Id
 	user.Username.String = input.Username
@@ -166,24 +257,13 @@ func (s *UserService) Update(input dto.RequestRegister) (dto.Response, error) {
 	}
 
 	user.Password = string(passwordHash)
-	user.UpdatedAt = time.Now().Unix()
-	err = s.userRepo.Update(context.Background(), user)
+	user.UpdatedAt = s.clock.Now().Unix()
+	err = s.userRepo.Update(ctx, user)
 	if err != nil {
 		s.logger.Error(err.Error(), helper.UserServiceUpdate, err)
 		return dto.Response{}, err
 	}
-
-	if IsUserReadHeavy {
-		// Put to cache
-		profileToCache := map[string]string{
-			"email":           user.Email.String,
-			"name":            user.Name.String,
-			"userImageUri":    "", // TODO: Handle once retrieved in request
-			"companyName":     "", // TODO: Handle once retrieved in request
-			"companyImageUri": "", // TODO: Handle once retrieved in request
-		}
-		cache.SetAsMap(fmt.Sprintf(cache.CacheUserIdToProfile, user.Id), profileToCache)
-	}
+	s.profileCache.Invalidate(user.Id)
 
 	response := dto.Response{}
 	response.Id = input.Id
@@ -193,75 +273,153 @@ func (s *UserService) Update(input dto.RequestRegister) (dto.Response, error) {
 	return response, nil
 }
 
-func (s *UserService) DeleteByID(id string) error {
-	cache.Delete(fmt.Sprintf(cache.CacheUserIdToProfile, id))
+func (s *UserService) DeleteByID(ctx context.Context, id string) (err error) {
+	ctx, span := s.tracer.Start(ctx, "UserService.DeleteByID")
+	defer func() { telemetry.End(span, err) }()
 
-	err := s.userRepo.Delete(context.Background(), id)
+	err = s.uow.Do(ctx, func(repos repository.Repositories) error {
+		before, err := repos.User.GetProfile(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := repos.User.Delete(ctx, id); err != nil {
+			return err
+		}
+		removed := []string{before.UserImageUri.String, before.CompanyImageUri.String}
+		if _, err := repos.File.AdjustReferences(ctx, repos.Tx, nil, removed); err != nil {
+			return err
+		}
+		return recordUser(ctx, repos, entity.AuditActionDelete, id, profileResponse(before), nil)
+	})
 	if err != nil {
 		s.logger.Error(err.Error(), helper.UserServiceUpdate, err)
 		return err
 	}
+	s.profileCache.Invalidate(id)
 	return err
 }
 
 // Get manager profile by their id
-func (s *UserService) GetProfile(id string) (*dto.ResposneGetProfile, error) {
+func (s *UserService) GetProfile(ctx context.Context, id string) (profile *dto.ResposneGetProfile, err error) {
+	ctx, span := s.tracer.Start(ctx, "UserService.GetProfile")
+	defer func() { telemetry.End(span, err) }()
 
-	// Get from cache
-	cachedProfile, found := cache.GetAsMap(fmt.Sprintf(cache.CacheUserIdToProfile, id))
-	if found {
-		return &dto.ResposneGetProfile{
-			Email:           cachedProfile["email"],
-			Name:            cachedProfile["name"],
-			UserImageUri:    cachedProfile["userImageUri"],
-			CompanyName:     cachedProfile["companyName"],
-			CompanyImageUri: cachedProfile["companyImageUri"],
-		}, nil
+	if !IsUserReadHeavy {
+		return s.getProfile(ctx, id)
 	}
 
-	profile, err := s.userRepo.GetProfile(context.Background(), id)
+	loaded := false
+	result, err := s.profileCache.GetOrLoad(id, func() (dto.ResposneGetProfile, error) {
+		loaded = true
+		// Load dipakai bersama pemanggil lain dengan id yang sama, sehingga
+		// tidak ikut batal jika request pertama batal; tetap dibatasi timeout
+		// repository
+		profile, err := s.getProfile(context.WithoutCancel(ctx), id)
+		if err != nil {
+			return dto.ResposneGetProfile{}, err
+		}
+		return *profile, nil
+	})
+	span.SetAttributes(attribute.Bool("cache.hit", !loaded))
 	if err != nil {
-		s.logger.Error(err.Error(), helper.UserServiceGetProfile, err)
 		return nil, err
 	}
+	return &result, nil
+}
 
-	if IsUserReadHeavy {
-		// Put to cache
-		profileToCache := map[string]string{
-			"email":           profile.Email,
-			"name":            profile.Name.String,
-			"userImageUri":    profile.UserImageUri.String,
-			"companyName":     profile.CompanyName.String,
-			"companyImageUri": profile.CompanyImageUri.String,
-		}
-		cache.SetAsMap(fmt.Sprintf(cache.CacheUserIdToProfile, id), profileToCache)
+func (s *UserService) getProfile(ctx context.Context, id string) (*dto.ResposneGetProfile, error) {
+	profile, err := s.userRepo.GetProfile(ctx, id)
+	if err != nil {
+		s.logger.Error(err.Error(), helper.UserServiceGetProfile, err)
+		return nil, err
 	}
 
-	result := dto.ResposneGetProfile{
+	result := profileResponse(profile)
+	return result, nil
+}
+
+func profileResponse(profile *entity.GetProfile) *dto.ResposneGetProfile {
+	return &dto.ResposneGetProfile{
 		Email:           profile.Email,
 		Name:            profile.Name.String,
 		UserImageUri:    profile.UserImageUri.String,
 		CompanyName:     profile.CompanyName.String,
 		CompanyImageUri: profile.CompanyImageUri.String,
 	}
-	return &result, nil
+}
+
+// recordUser mencatat perubahan profil manager di audit log. Password tidak
+// termasuk di profil dan selalu dilewati oleh audit.Diff.
+func recordUser(ctx context.Context, repos repository.Repositories, action, id string, before, after *dto.ResposneGetProfile) error {
+	return audit.Record(ctx, repos.Audit, id, action, entity.AuditEntityUser, id, before, after)
 }
 
 // Update manager profile by their id
-func (s *UserService) UpdateProfile(id string, req dto.RequestUpdateProfile) (*dto.RequestUpdateProfile, error) {
-	profile, err := s.userRepo.GetProfile(context.Background(), id)
+func (s *UserService) UpdateProfile(ctx context.Context, id string, req dto.RequestUpdateProfile) (_ *dto.RequestUpdateProfile, err error) {
+	ctx, span := s.tracer.Start(ctx, "UserService.UpdateProfile")
+	defer func() { telemetry.End(span, err) }()
+
+	var profile *entity.GetProfile
+	err = s.uow.Do(ctx, func(repos repository.Repositories) error {
+		var err error
+		profile, err = repos.User.GetProfile(ctx, id)
+		if err != nil {
+			s.logger.Error(err.Error(), helper.UserServiceGetProfile, err)
+			return err
+		}
+		before := profileResponse(profile)
+
+		if req.Email != nil && *req.Email != profile.Email {
+			user, err := repos.User.GetUserbyEmail(ctx, *req.Email)
+			if err != nil || len(user) != 0 {
+				return helper.ErrConflict
+			}
+		}
+		applyProfileUpdate(profile, req)
+
+		if err := repos.User.UpdateProfile(ctx, id, profile); err != nil {
+			return err
+		}
+		if err := referenceProfileImages(ctx, repos, before, profileResponse(profile)); err != nil {
+			return err
+		}
+		return recordUser(ctx, repos, entity.AuditActionUpdate, id, before, profileResponse(profile))
+	})
 	if err != nil {
-		s.logger.Error(err.Error(), helper.UserServiceGetProfile, err)
 		return nil, err
 	}
+	s.profileCache.Invalidate(id)
 
-	if req.Email != nil && *req.Email != profile.Email {
-		user, err := s.userRepo.GetUserbyEmail(context.Background(), *req.Email)
-		if err != nil || len(user) != 0 {
-			return nil, helper.ErrConflict
-		}
+	result := dto.RequestUpdateProfile{
+		Email:           &profile.Email,
+		Name:            &profile.Name.String,
+		UserImageUri:    &profile.UserImageUri.String,
+		CompanyName:     &profile.CompanyName.String,
+		CompanyImageUri: &profile.CompanyImageUri.String,
 	}
 
+	return &result, nil
+}
+
+// referenceProfileImages memindahkan rujukan file dari gambar profil lama ke
+// yang baru di transaksi yang sama, sehingga file yang masih dipakai tidak
+// dihapus purge.Cleaner.
+func referenceProfileImages(ctx context.Context, repos repository.Repositories, before, after *dto.ResposneGetProfile) error {
+	var added, removed []string
+	if after.UserImageUri != before.UserImageUri {
+		added = append(added, after.UserImageUri)
+		removed = append(removed, before.UserImageUri)
+	}
+	if after.CompanyImageUri != before.CompanyImageUri {
+		added = append(added, after.CompanyImageUri)
+		removed = append(removed, before.CompanyImageUri)
+	}
+	_, err := repos.File.AdjustReferences(ctx, repos.Tx, added, removed)
+	return err
+}
+
+func applyProfileUpdate(profile *entity.GetProfile, req dto.RequestUpdateProfile) {
+
 	if req.Email != nil {
 		profile.Email = *req.Email
 	}
@@ -277,18 +435,6 @@ func (s *UserService) UpdateProfile(id string, req dto.RequestUpdateProfile) (*d
 	if req.CompanyImageUri != nil {
 		profile.CompanyImageUri = ToNullString(req.CompanyImageUri)
 	}
-
-	s.userRepo.UpdateProfile(context.Background(), id, profile)
-
-	result := dto.RequestUpdateProfile{
-		Email:           &profile.Email,
-		Name:            &profile.Name.String,
-		UserImageUri:    &profile.UserImageUri.String,
-		CompanyName:     &profile.CompanyName.String,
-		CompanyImageUri: &profile.CompanyImageUri.String,
-	}
-
-	return &result, nil
 }
 
 func ToNullString(s *string) sql.NullString {
@@ -0,0 +1,357 @@
+package userService_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/clock/clocktest"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/lru"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/mocks"
+	"github.com/levensspel/go-gin-template/repository"
+	fileService "github.com/levensspel/go-gin-template/service/file"
+	service "github.com/levensspel/go-gin-template/service/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const testManagerID = "0b7c2d2e-4f4a-4b8e-9c59-2f1d8f6a3e10"
+
+// userFixture menyusun UserService di atas mocks. Repository yang sama
+// dipakai di luar dan di dalam unit of work, dan profileLoads menghitung
+// GetProfile ke repository.
+type userFixture struct {
+	user *mocks.UserRepository
+	uow  *mocks.UnitOfWork
+	// files nil kecuali test upload gambar profil memasangnya
+	files   fileService.FileService
+	metrics *metrics.Metrics
+	// tracer no-op kecuali test span menggantinya dengan recorder
+	tracer       trace.Tracer
+	clock        *clocktest.Fake
+	profile      entity.GetProfile
+	profileLoads atomic.Int64
+	audits       []entity.AuditLog
+}
+
+func newUserFixture() *userFixture {
+	f := &userFixture{
+		metrics: metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{}),
+		tracer:  noop.NewTracerProvider().Tracer(""),
+		clock:   clocktest.NewFake(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)),
+		profile: entity.GetProfile{
+			Email: "manager@example.com",
+			Name:  sql.NullString{String: "Manager", Valid: true},
+		},
+	}
+	f.user = &mocks.UserRepository{
+		GetProfileFunc: func(ctx context.Context, id string) (*entity.GetProfile, error) {
+			f.profileLoads.Add(1)
+			profile := f.profile
+			return &profile, nil
+		},
+		UpdateProfileFunc: func(ctx context.Context, id string, data *entity.GetProfile) error {
+			f.profile = *data
+			return nil
+		},
+		GetUserbyEmailFunc: func(ctx context.Context, email string) ([]entity.User, error) {
+			return nil, nil
+		},
+	}
+	f.uow = &mocks.UnitOfWork{Repositories: repository.Repositories{
+		User: f.user,
+		File: &mocks.FileRepository{
+			AdjustReferencesFunc: func(ctx context.Context, db database.Querier, added []string, removed []string) ([]string, error) {
+				return added, nil
+			},
+		},
+		Audit: &mocks.AuditRepository{
+			AddFunc: func(ctx context.Context, log entity.AuditLog) error {
+				f.audits = append(f.audits, log)
+				return nil
+			},
+		},
+	}}
+	return f
+}
+
+func (f *userFixture) service() service.IUserService {
+	profileCache := lru.New[string, dto.ResposneGetProfile](lru.Settings{
+		Name:       "manager_profile",
+		MaxEntries: 10,
+		TTL:        time.Minute,
+		Clock:      f.clock,
+	})
+	return service.NewUserService(f.user, f.uow, f.files, fakeTokens{}, f.clock, mocks.Logger{}, f.tracer, f.metrics, profileCache)
+}
+
+// fakeTokens menerbitkan token "token:<id>" dan hanya menerima token
+// dengan bentuk itu.
+type fakeTokens struct{}
+
+func (fakeTokens) GenerateToken(userID string) (string, error) {
+	return "token:" + userID, nil
+}
+
+func (fakeTokens) ParseToken(ctx context.Context, encodedToken string) (string, error) {
+	id, ok := strings.CutPrefix(encodedToken, "token:")
+	if !ok {
+		return "", errors.New("invalid token")
+	}
+	return id, nil
+}
+
+func getProfile(t *testing.T, s service.IUserService) *dto.ResposneGetProfile {
+	t.Helper()
+	profile, err := s.GetProfile(context.Background(), testManagerID)
+	if err != nil {
+		t.Fatalf("GetProfile error = %v", err)
+	}
+	return profile
+}
+
+func TestGetProfileIsCached(t *testing.T) {
+	f := newUserFixture()
+	s := f.service()
+
+	first := getProfile(t, s)
+	second := getProfile(t, s)
+	if first.Email != "manager@example.com" || second.Name != "Manager" {
+		t.Fatalf("GetProfile = %+v, %+v", first, second)
+	}
+	if got := f.profileLoads.Load(); got != 1 {
+		t.Fatalf("repository GetProfile called %d times, want 1", got)
+	}
+
+	// Hasil cache adalah salinan, bukan pointer bersama
+	first.Name = "Changed"
+	if got := getProfile(t, s).Name; got != "Manager" {
+		t.Fatalf("cached Name = %q after changing a returned profile", got)
+	}
+}
+
+func TestGetProfileCollapsesConcurrentMisses(t *testing.T) {
+	f := newUserFixture()
+	release := make(chan struct{})
+	get := f.user.GetProfileFunc
+	f.user.GetProfileFunc = func(ctx context.Context, id string) (*entity.GetProfile, error) {
+		<-release
+		return get(ctx, id)
+	}
+	s := f.service()
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.GetProfile(context.Background(), testManagerID); err != nil {
+				t.Errorf("GetProfile error = %v", err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := f.profileLoads.Load(); got != 1 {
+		t.Fatalf("repository GetProfile called %d times, want 1", got)
+	}
+}
+
+func TestGetProfileErrorIsNotCached(t *testing.T) {
+	f := newUserFixture()
+	errDatabase := errors.New("database unavailable")
+	get := f.user.GetProfileFunc
+	f.user.GetProfileFunc = func(ctx context.Context, id string) (*entity.GetProfile, error) {
+		if f.profileLoads.Load() == 0 {
+			f.profileLoads.Add(1)
+			return nil, errDatabase
+		}
+		return get(ctx, id)
+	}
+	s := f.service()
+
+	if _, err := s.GetProfile(context.Background(), testManagerID); !errors.Is(err, errDatabase) {
+		t.Fatalf("GetProfile error = %v, want %v", err, errDatabase)
+	}
+	getProfile(t, s)
+	if got := f.profileLoads.Load(); got != 2 {
+		t.Fatalf("repository GetProfile called %d times, want 2", got)
+	}
+}
+
+// TestWritesInvalidateProfile memastikan setiap write profil menghapus
+// cache, sehingga GetProfile berikutnya membaca dari repository.
+func TestWritesInvalidateProfile(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		setup func(f *userFixture)
+		write func(s service.IUserService) error
+	}{
+		{
+			name: "UpdateProfile",
+			write: func(s service.IUserService) error {
+				name := "Renamed"
+				_, err := s.UpdateProfile(context.Background(), testManagerID, dto.RequestUpdateProfile{Name: &name})
+				return err
+			},
+		},
+		{
+			name: "Update",
+			setup: func(f *userFixture) {
+				f.user.UpdateFunc = func(ctx context.Context, user entity.User) error { return nil }
+			},
+			write: func(s service.IUserService) error {
+				_, err := s.Update(context.Background(), dto.RequestRegister{Id: testManagerID, Email: "new@example.com", Password: "password"})
+				return err
+			},
+		},
+		{
+			name: "DeleteByID",
+			setup: func(f *userFixture) {
+				f.user.DeleteFunc = func(ctx context.Context, id string) error { return nil }
+			},
+			write: func(s service.IUserService) error {
+				return s.DeleteByID(context.Background(), testManagerID)
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newUserFixture()
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+			s := f.service()
+
+			getProfile(t, s)
+			if err := tt.write(s); err != nil {
+				t.Fatalf("%s error = %v", tt.name, err)
+			}
+			loadsAfterWrite := f.profileLoads.Load()
+			getProfile(t, s)
+			if got := f.profileLoads.Load(); got != loadsAfterWrite+1 {
+				t.Fatalf("GetProfile after %s was served from cache", tt.name)
+			}
+		})
+	}
+}
+
+func TestUpdateProfileReturnsNewValueAfterCachedRead(t *testing.T) {
+	f := newUserFixture()
+	s := f.service()
+
+	getProfile(t, s)
+	name := "Renamed"
+	if _, err := s.UpdateProfile(context.Background(), testManagerID, dto.RequestUpdateProfile{Name: &name}); err != nil {
+		t.Fatalf("UpdateProfile error = %v", err)
+	}
+	if got := getProfile(t, s).Name; got != "Renamed" {
+		t.Fatalf("GetProfile Name = %q, want the updated name", got)
+	}
+}
+
+func TestFailedWriteKeepsProfileCached(t *testing.T) {
+	f := newUserFixture()
+	errWrite := errors.New("update failed")
+	f.user.UpdateProfileFunc = func(ctx context.Context, id string, data *entity.GetProfile) error { return errWrite }
+	s := f.service()
+
+	getProfile(t, s)
+	name := "Renamed"
+	if _, err := s.UpdateProfile(context.Background(), testManagerID, dto.RequestUpdateProfile{Name: &name}); !errors.Is(err, errWrite) {
+		t.Fatalf("UpdateProfile error = %v, want %v", err, errWrite)
+	}
+	loads := f.profileLoads.Load()
+	if got := getProfile(t, s).Name; got != "Manager" {
+		t.Fatalf("GetProfile Name = %q, want the unchanged name", got)
+	}
+	if got := f.profileLoads.Load(); got != loads {
+		t.Fatal("failed UpdateProfile invalidated the cached profile")
+	}
+	if f.uow.Committed {
+		t.Fatal("failed UpdateProfile was committed")
+	}
+}
+
+// referenceRecorder mencatat rujukan file yang diubah di unit of work.
+type referenceRecorder struct {
+	added, removed []string
+}
+
+func (r *referenceRecorder) install(f *userFixture) {
+	f.uow.Repositories.File = &mocks.FileRepository{
+		AdjustReferencesFunc: func(ctx context.Context, db database.Querier, added []string, removed []string) ([]string, error) {
+			r.added = append(r.added, added...)
+			r.removed = append(r.removed, removed...)
+			return added, nil
+		},
+	}
+}
+
+func TestUpdateProfileMovesImageReferences(t *testing.T) {
+	f := newUserFixture()
+	f.profile.UserImageUri = sql.NullString{String: "https://api.example.com/files/old-avatar", Valid: true}
+	f.profile.CompanyImageUri = sql.NullString{String: "https://api.example.com/files/logo", Valid: true}
+	var refs referenceRecorder
+	refs.install(f)
+
+	uri := "https://api.example.com/files/new-avatar"
+	if _, err := f.service().UpdateProfile(context.Background(), testManagerID, dto.RequestUpdateProfile{UserImageUri: &uri}); err != nil {
+		t.Fatalf("UpdateProfile error = %v", err)
+	}
+	// Gambar perusahaan tidak berubah, sehingga rujukannya tetap
+	if !slices.Equal(refs.added, []string{uri}) || !slices.Equal(refs.removed, []string{"https://api.example.com/files/old-avatar"}) {
+		t.Fatalf("references added %v, removed %v", refs.added, refs.removed)
+	}
+}
+
+func TestDeleteByIDReleasesImageReferences(t *testing.T) {
+	f := newUserFixture()
+	f.profile.UserImageUri = sql.NullString{String: "https://api.example.com/files/avatar", Valid: true}
+	f.profile.CompanyImageUri = sql.NullString{String: "https://api.example.com/files/logo", Valid: true}
+	f.user.DeleteFunc = func(ctx context.Context, id string) error { return nil }
+	var refs referenceRecorder
+	refs.install(f)
+
+	if err := f.service().DeleteByID(context.Background(), testManagerID); err != nil {
+		t.Fatalf("DeleteByID error = %v", err)
+	}
+	want := []string{"https://api.example.com/files/avatar", "https://api.example.com/files/logo"}
+	if len(refs.added) != 0 || !slices.Equal(refs.removed, want) {
+		t.Fatalf("references added %v, removed %v, want removed %v", refs.added, refs.removed, want)
+	}
+	if !f.uow.Committed {
+		t.Fatal("unit of work was not committed")
+	}
+}
+
+func TestUpdateProfileRollsBackWhenReferencesFail(t *testing.T) {
+	f := newUserFixture()
+	errReferences := errors.New("adjust failed")
+	f.uow.Repositories.File = &mocks.FileRepository{
+		AdjustReferencesFunc: func(ctx context.Context, db database.Querier, added []string, removed []string) ([]string, error) {
+			return nil, errReferences
+		},
+	}
+
+	uri := "https://api.example.com/files/new-avatar"
+	if _, err := f.service().UpdateProfile(context.Background(), testManagerID, dto.RequestUpdateProfile{UserImageUri: &uri}); !errors.Is(err, errReferences) {
+		t.Fatalf("UpdateProfile error = %v, want %v", err, errReferences)
+	}
+	if f.uow.Committed {
+		t.Fatal("unit of work was committed")
+	}
+}
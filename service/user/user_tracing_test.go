@@ -0,0 +1,110 @@
+package userService_test
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans mengganti tracer fixture dengan tracer yang menyimpan span
+// yang sudah selesai.
+func recordSpans(f *userFixture) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	f.tracer = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer(telemetry.TracerName)
+	return recorder
+}
+
+func spanNames(spans []sdktrace.ReadOnlySpan) []string {
+	var names []string
+	for _, span := range spans {
+		names = append(names, span.Name())
+	}
+	return names
+}
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestLoginSpans(t *testing.T) {
+	f := loginFixture(t)
+	recorder := recordSpans(f)
+	if _, err := f.service().Login(context.Background(), loginPayload("login@example.com", testPassword)); err != nil {
+		t.Fatalf("Login error = %v", err)
+	}
+
+	spans := recorder.Ended()
+	want := []string{"user.lookup", "user.compare_password", "auth.token", "UserService.Login"}
+	if got := spanNames(spans); !slices.Equal(got, want) {
+		t.Fatalf("spans = %q, want %q", got, want)
+	}
+	root := spans[len(spans)-1]
+	for _, span := range spans[:len(spans)-1] {
+		if span.Parent().SpanID() != root.SpanContext().SpanID() {
+			t.Errorf("span %s is not a child of UserService.Login", span.Name())
+		}
+	}
+	// Email dan password tidak pernah menjadi attribute span
+	for _, span := range spans {
+		for _, kv := range span.Attributes() {
+			if value := kv.Value.Emit(); value == "login@example.com" || value == testPassword {
+				t.Errorf("span %s attribute %s = %q", span.Name(), kv.Key, value)
+			}
+		}
+	}
+}
+
+func TestLoginUnknownEmailSpan(t *testing.T) {
+	f := loginFixture(t)
+	recorder := recordSpans(f)
+	if _, err := f.service().Login(context.Background(), loginPayload("unknown@example.com", testPassword)); err == nil {
+		t.Fatal("Login succeeded with an unknown email")
+	}
+
+	spans := recorder.Ended()
+	root := spans[len(spans)-1]
+	if root.Name() != "UserService.Login" {
+		t.Fatalf("last span = %s, want UserService.Login", root.Name())
+	}
+	if got := spanAttribute(root, "error.status_code").AsInt64(); got != http.StatusNotFound {
+		t.Errorf("error.status_code = %d, want %d", got, http.StatusNotFound)
+	}
+	if got := spanAttribute(root, "error.key").AsString(); got != helper.GetErrorKey(helper.ErrNotFound) {
+		t.Errorf("error.key = %q, want %q", got, helper.GetErrorKey(helper.ErrNotFound))
+	}
+	// Kesalahan client tidak menandai span sebagai gagal
+	if root.Status().Code == codes.Error {
+		t.Error("span status is Error for a client error")
+	}
+}
+
+func TestGetProfileSpanRecordsCacheHit(t *testing.T) {
+	f := newUserFixture()
+	recorder := recordSpans(f)
+	s := f.service()
+	getProfile(t, s)
+	getProfile(t, s)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("spans = %q, want two GetProfile spans", spanNames(spans))
+	}
+	for i, want := range []bool{false, true} {
+		if got := spanAttribute(spans[i], "cache.hit").AsBool(); got != want {
+			t.Errorf("call %d cache.hit = %t, want %t", i+1, got, want)
+		}
+	}
+}
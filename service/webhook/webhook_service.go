@@ -0,0 +1,220 @@
+package webhookService
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/levensspel/go-gin-template/clock"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/events"
+	"github.com/levensspel/go-gin-template/idgen"
+	repositories "github.com/levensspel/go-gin-template/repository/webhook"
+	"github.com/levensspel/go-gin-template/validation"
+	"github.com/levensspel/go-gin-template/webhook"
+	"github.com/samber/do/v2"
+)
+
+type WebhookService interface {
+	Create(ctx context.Context, managerID string, input dto.WebhookRequest) (dto.WebhookResponse, error)
+	GetAll(ctx context.Context, managerID string) ([]dto.WebhookResponse, error)
+	Delete(ctx context.Context, id string, managerID string) error
+	// Test mengirim event webhook.test secara langsung dan mengembalikan
+	// hasilnya. Test yang gagal tidak dikirim ulang.
+	Test(ctx context.Context, id string, managerID string) (dto.WebhookDeliveryResponse, error)
+	GetDeliveries(ctx context.Context, id string, managerID string, input dto.GetWebhookDeliveriesRequest) ([]dto.WebhookDeliveryResponse, error)
+}
+
+type service struct {
+	repo      repositories.WebhookRepositoryInterface
+	db        database.DB
+	deliverer *webhook.Deliverer
+	ids       idgen.IDGenerator
+	clock     clock.Clock
+	config    *config.WebhookConfig
+	timeouts  *config.QueryTimeoutConfig
+}
+
+func New(
+	repo repositories.WebhookRepositoryInterface,
+	db database.DB,
+	deliverer *webhook.Deliverer,
+	ids idgen.IDGenerator,
+	clock clock.Clock,
+	config *config.WebhookConfig,
+	timeouts *config.QueryTimeoutConfig,
+) WebhookService {
+	return &service{
+		repo:      repo,
+		db:        db,
+		deliverer: deliverer,
+		ids:       ids,
+		clock:     clock,
+		config:    config,
+		timeouts:  timeouts,
+	}
+}
+
+func NewInject(i do.Injector) (WebhookService, error) {
+	_repo := do.MustInvoke[repositories.WebhookRepositoryInterface](i)
+	_cluster := do.MustInvoke[*database.Cluster](i)
+	_deliverer := do.MustInvoke[*webhook.Deliverer](i)
+	_ids := do.MustInvoke[idgen.IDGenerator](i)
+	return New(_repo, _cluster.Writer(), _deliverer, _ids, do.MustInvoke[clock.Clock](i), config.LoadWebhookConfig(), config.LoadQueryTimeoutConfig()), nil
+}
+
+func (s *service) Create(ctx context.Context, managerID string, input dto.WebhookRequest) (dto.WebhookResponse, error) {
+	if err := webhook.CheckURL(ctx, input.Url, s.config.AllowPrivateURLs); err != nil {
+		return dto.WebhookResponse{}, validation.FieldErrors{"url": err.Error()}
+	}
+	if input.Secret == "" {
+		secret, err := webhook.NewSecret()
+		if err != nil {
+			return dto.WebhookResponse{}, err
+		}
+		input.Secret = secret
+	}
+	if input.Events == nil {
+		input.Events = []string{}
+	}
+
+	row, err := s.repo.Create(ctx, entity.Webhook{
+		Id:        s.ids.NewID(),
+		ManagerId: managerID,
+		Url:       input.Url,
+		Secret:    input.Secret,
+		Events:    input.Events,
+	})
+	if err != nil {
+		return dto.WebhookResponse{}, err
+	}
+	response := toWebhookResponse(row)
+	// Secret hanya bisa dilihat sekali, saat webhook dibuat
+	response.Secret = row.Secret
+	return response, nil
+}
+
+func (s *service) GetAll(ctx context.Context, managerID string) ([]dto.WebhookResponse, error) {
+	rows, err := s.repo.GetAll(ctx, managerID)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]dto.WebhookResponse, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, toWebhookResponse(row))
+	}
+	return results, nil
+}
+
+func (s *service) Delete(ctx context.Context, id string, managerID string) error {
+	return s.repo.Delete(ctx, id, managerID)
+}
+
+func (s *service) Test(ctx context.Context, id string, managerID string) (dto.WebhookDeliveryResponse, error) {
+	row, err := s.repo.Get(ctx, id, managerID)
+	if err != nil {
+		return dto.WebhookDeliveryResponse{}, err
+	}
+	data, err := json.Marshal(map[string]string{"managerId": managerID, "webhookId": row.Id})
+	if err != nil {
+		return dto.WebhookDeliveryResponse{}, err
+	}
+	payload, err := json.Marshal(events.Envelope{
+		Type:          entity.EventWebhookTest,
+		Version:       events.Version,
+		AggregateType: "webhook",
+		AggregateID:   row.Id,
+		CreatedAt:     s.clock.Now().UTC(),
+		Data:          data,
+	})
+	if err != nil {
+		return dto.WebhookDeliveryResponse{}, err
+	}
+
+	delivery := entity.WebhookDelivery{
+		WebhookId: row.Id,
+		EventType: entity.EventWebhookTest,
+		Payload:   payload,
+		Url:       row.Url,
+		Secret:    row.Secret,
+		CreatedAt: s.clock.Now().UTC(),
+		Attempts:  1,
+	}
+	// Delivery belum di-commit selama dikirim, sehingga tidak ikut diambil
+	// Deliverer dan tidak dikirim dua kali
+	err = database.WithTx(ctx, s.db, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		repo := repositories.New(tx, s.timeouts)
+		deliveryID, err := repo.AddDelivery(ctx, delivery)
+		if err != nil {
+			return err
+		}
+		delivery.Id = deliveryID
+
+		statusCode, sendErr := s.deliverer.Send(ctx, delivery)
+		if statusCode != 0 {
+			delivery.LastStatusCode = &statusCode
+		}
+		if sendErr != nil {
+			lastError := sendErr.Error()
+			delivery.Status = entity.WebhookDeliveryDead
+			delivery.LastError = &lastError
+			return repo.MarkFailed(ctx, delivery.Id, 0, statusCode, lastError, true)
+		}
+		deliveredAt := s.clock.Now().UTC()
+		delivery.Status = entity.WebhookDeliveryDelivered
+		delivery.DeliveredAt = &deliveredAt
+		return repo.MarkDelivered(ctx, delivery.Id, statusCode)
+	})
+	if err != nil {
+		return dto.WebhookDeliveryResponse{}, err
+	}
+	return toDeliveryResponse(delivery), nil
+}
+
+func (s *service) GetDeliveries(ctx context.Context, id string, managerID string, input dto.GetWebhookDeliveriesRequest) ([]dto.WebhookDeliveryResponse, error) {
+	if _, err := s.repo.Get(ctx, id, managerID); err != nil {
+		return nil, err
+	}
+	rows, err := s.repo.GetDeliveries(ctx, id, input.Limit, input.Offset)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]dto.WebhookDeliveryResponse, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, toDeliveryResponse(row))
+	}
+	return results, nil
+}
+
+func toWebhookResponse(row entity.Webhook) dto.WebhookResponse {
+	return dto.WebhookResponse{
+		Id:        row.Id,
+		Url:       row.Url,
+		Events:    row.Events,
+		Active:    row.Active,
+		CreatedAt: row.CreatedAt,
+	}
+}
+
+func toDeliveryResponse(row entity.WebhookDelivery) dto.WebhookDeliveryResponse {
+	response := dto.WebhookDeliveryResponse{
+		Id:             row.Id,
+		EventId:        row.EventId,
+		EventType:      row.EventType,
+		Status:         row.Status,
+		Attempts:       row.Attempts,
+		LastStatusCode: row.LastStatusCode,
+		LastError:      row.LastError,
+		CreatedAt:      row.CreatedAt,
+		DeliveredAt:    row.DeliveredAt,
+	}
+	// nextAttemptAt hanya bermakna untuk delivery yang masih menunggu
+	if row.Status == entity.WebhookDeliveryPending {
+		nextAttemptAt := row.NextAttemptAt
+		response.NextAttemptAt = &nextAttemptAt
+	}
+	return response
+}
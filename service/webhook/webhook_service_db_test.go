@@ -0,0 +1,79 @@
+//go:build integration
+
+package webhookService
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/clock"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/idgen"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/mocks"
+	repositories "github.com/levensspel/go-gin-template/repository/webhook"
+	"github.com/levensspel/go-gin-template/signing"
+	"github.com/levensspel/go-gin-template/webhook"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestTestRecordsDelivery(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		status     int
+		wantStatus string
+	}{
+		{name: "delivered", status: http.StatusOK, wantStatus: entity.WebhookDeliveryDelivered},
+		// Test yang gagal langsung dead, tidak dikirim ulang Deliverer
+		{name: "failed", status: http.StatusInternalServerError, wantStatus: entity.WebhookDeliveryDead},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := dbtest.New(t)
+			var verifyErr error
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				_, verifyErr = signing.Verify("client-provided-secret", r.Header.Get(webhook.SignatureHeader), r.Header.Get(webhook.TimestampHeader), body, time.Now(), time.Minute)
+				w.WriteHeader(tt.status)
+			}))
+			defer receiver.Close()
+
+			webhookConfig := &config.WebhookConfig{Timeout: time.Second, MaxAttempts: 3, AllowPrivateURLs: true}
+			timeouts := config.LoadQueryTimeoutConfig()
+			repo := repositories.New(pool, timeouts)
+			appMetrics := metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{})
+			deliverer := webhook.NewDeliverer(context.Background(), pool, webhook.NewClient(time.Second, true), clock.Real(), webhookConfig, timeouts, appMetrics, mocks.Logger{})
+			s := New(&repo, pool, deliverer, idgen.NewUUIDv7(), clock.Real(), webhookConfig, timeouts)
+			ctx := context.Background()
+
+			created, err := s.Create(ctx, "manager-1", dto.WebhookRequest{Url: receiver.URL, Secret: "client-provided-secret"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := s.Test(ctx, created.Id, "manager-1")
+			if err != nil {
+				t.Fatalf("Test error = %v", err)
+			}
+			if verifyErr != nil {
+				t.Fatalf("receiver could not verify the signature: %v", verifyErr)
+			}
+			if result.Status != tt.wantStatus || result.EventType != entity.EventWebhookTest || result.LastStatusCode == nil || *result.LastStatusCode != tt.status {
+				t.Fatalf("Test = %+v", result)
+			}
+
+			deliveries, err := s.GetDeliveries(ctx, created.Id, "manager-1", dto.GetWebhookDeliveriesRequest{Limit: 10})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(deliveries) != 1 || deliveries[0].Id != result.Id || deliveries[0].Status != tt.wantStatus || deliveries[0].Attempts != 1 {
+				t.Fatalf("deliveries = %+v, want the test delivery", deliveries)
+			}
+		})
+	}
+}
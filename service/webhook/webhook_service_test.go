@@ -0,0 +1,154 @@
+package webhookService
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/levensspel/go-gin-template/clock"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/idgen"
+	"github.com/levensspel/go-gin-template/mocks"
+	"github.com/levensspel/go-gin-template/validation"
+)
+
+const testManagerID = "0b7c2d2e-4f4a-4b8e-9c59-2f1d8f6a3e10"
+
+// newTestService membuat service tanpa database dan deliverer, cukup untuk
+// method yang hanya memakai repository.
+func newTestService(repo *mocks.WebhookRepository, allowPrivate bool) WebhookService {
+	return New(repo, nil, nil, idgen.NewUUIDv7(), clock.Real(), &config.WebhookConfig{AllowPrivateURLs: allowPrivate}, &config.QueryTimeoutConfig{})
+}
+
+// storingRepository menyimpan webhook yang dibuat dan mengembalikannya di
+// GetAll.
+func storingRepository(created *[]entity.Webhook) *mocks.WebhookRepository {
+	return &mocks.WebhookRepository{
+		CreateFunc: func(ctx context.Context, webhook entity.Webhook) (entity.Webhook, error) {
+			webhook.Active = true
+			*created = append(*created, webhook)
+			return webhook, nil
+		},
+		GetAllFunc: func(ctx context.Context, managerId string) ([]entity.Webhook, error) {
+			return *created, nil
+		},
+	}
+}
+
+func TestCreateGeneratesSecret(t *testing.T) {
+	var created []entity.Webhook
+	s := newTestService(storingRepository(&created), false)
+
+	response, err := s.Create(context.Background(), testManagerID, dto.WebhookRequest{Url: "https://93.184.216.34/hooks"})
+	if err != nil {
+		t.Fatalf("Create error = %v", err)
+	}
+	if len(created) != 1 {
+		t.Fatalf("created %d webhooks, want 1", len(created))
+	}
+	row := created[0]
+	if secret, err := hex.DecodeString(row.Secret); err != nil || len(secret) != 32 {
+		t.Errorf("generated secret %q is not 32 random bytes in hex", row.Secret)
+	}
+	if row.ManagerId != testManagerID || row.Id == "" || row.Events == nil {
+		t.Errorf("created = %+v", row)
+	}
+	if response.Secret != row.Secret || response.Id != row.Id || response.Events == nil {
+		t.Errorf("Create response = %+v", response)
+	}
+
+	// Secret hanya dikembalikan saat create
+	webhooks, err := s.GetAll(context.Background(), testManagerID)
+	if err != nil {
+		t.Fatalf("GetAll error = %v", err)
+	}
+	if len(webhooks) != 1 || webhooks[0].Secret != "" {
+		t.Fatalf("GetAll = %+v, want one webhook without secret", webhooks)
+	}
+}
+
+func TestCreateKeepsClientSecret(t *testing.T) {
+	var created []entity.Webhook
+	s := newTestService(storingRepository(&created), false)
+	input := dto.WebhookRequest{Url: "https://93.184.216.34/hooks", Secret: "client-provided-secret", Events: []string{entity.EventEmployeeCreated}}
+
+	response, err := s.Create(context.Background(), testManagerID, input)
+	if err != nil {
+		t.Fatalf("Create error = %v", err)
+	}
+	if created[0].Secret != input.Secret || response.Secret != input.Secret {
+		t.Fatalf("secret = %q, response %q, want %q", created[0].Secret, response.Secret, input.Secret)
+	}
+}
+
+func TestCreateRejectsPrivateURLs(t *testing.T) {
+	tests := []struct {
+		name         string
+		url          string
+		allowPrivate bool
+		wantField    bool
+	}{
+		{name: "loopback", url: "http://127.0.0.1:8080/hooks", wantField: true},
+		{name: "metadata", url: "http://169.254.169.254/latest", wantField: true},
+		{name: "scheme", url: "gopher://93.184.216.34/hooks", wantField: true},
+		{name: "loopback allowed", url: "http://127.0.0.1:8080/hooks", allowPrivate: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created []entity.Webhook
+			s := newTestService(storingRepository(&created), tt.allowPrivate)
+
+			_, err := s.Create(context.Background(), testManagerID, dto.WebhookRequest{Url: tt.url})
+			if !tt.wantField {
+				if err != nil || len(created) != 1 {
+					t.Fatalf("Create = %v, created %d", err, len(created))
+				}
+				return
+			}
+			// Dikembalikan sebagai error field url agar response-nya 400
+			var fields validation.FieldErrors
+			if !errors.As(err, &fields) || fields["url"] == "" {
+				t.Fatalf("Create error = %v, want a url field error", err)
+			}
+			if len(created) != 0 {
+				t.Fatal("webhook was created")
+			}
+		})
+	}
+}
+
+func TestGetDeliveriesChecksOwnership(t *testing.T) {
+	s := newTestService(&mocks.WebhookRepository{
+		GetFunc: func(ctx context.Context, id, managerId string) (entity.Webhook, error) {
+			return entity.Webhook{}, helper.ErrNotFound
+		},
+	}, false)
+
+	// GetDeliveries tidak di-mock: query delivery tidak boleh dijalankan
+	_, err := s.GetDeliveries(context.Background(), "webhook-1", "other-manager", dto.GetWebhookDeliveriesRequest{Limit: 10})
+	if !errors.Is(err, helper.ErrNotFound) {
+		t.Fatalf("GetDeliveries error = %v, want %v", err, helper.ErrNotFound)
+	}
+}
+
+func TestDeliveryResponseNextAttempt(t *testing.T) {
+	for _, tt := range []struct {
+		status string
+		want   bool
+	}{
+		{status: entity.WebhookDeliveryPending, want: true},
+		{status: entity.WebhookDeliveryDelivered, want: false},
+		{status: entity.WebhookDeliveryDead, want: false},
+	} {
+		t.Run(tt.status, func(t *testing.T) {
+			response := toDeliveryResponse(entity.WebhookDelivery{Status: tt.status})
+			if got := response.NextAttemptAt != nil; got != tt.want {
+				t.Fatalf("nextAttemptAt present = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
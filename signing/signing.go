@@ -0,0 +1,82 @@
+// Package signing menghitung dan memverifikasi signature HMAC-SHA256 atas
+// payload bertimestamp, dipakai webhook keluar (webhook.Deliverer) dan
+// callback masuk dari partner (POST /v1/integrations/callback).
+//
+// Signature dihitung dari "<timestamp>.<body>", dengan timestamp berupa
+// detik Unix yang dikirim di header terpisah, dan ditulis sebagai
+// "sha256=<hex>". Timestamp ikut ditandatangani, sehingga penerima bisa
+// menolak request lama yang dikirim ulang tanpa bisa dipalsukan.
+package signing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/levensspel/go-gin-template/helper"
+)
+
+// Prefix adalah awalan nilai signature, menandai algoritmanya.
+const Prefix = "sha256="
+
+var (
+	ErrMissingSignature = errors.New("missing signature")
+	ErrInvalidTimestamp = errors.New("invalid signature timestamp")
+	// ErrTimestampOutOfRange berarti timestamp terlalu jauh dari waktu
+	// penerima, baik di masa lalu (replay) maupun di masa depan (clock skew).
+	ErrTimestampOutOfRange = errors.New("signature timestamp outside tolerance")
+	ErrSignatureMismatch   = errors.New("signature mismatch")
+)
+
+func init() {
+	helper.Register(ErrMissingSignature, http.StatusUnauthorized, "ErrInvalidSignature")
+	helper.Register(ErrInvalidTimestamp, http.StatusUnauthorized, "ErrInvalidSignature")
+	helper.Register(ErrSignatureMismatch, http.StatusUnauthorized, "ErrInvalidSignature")
+	helper.Register(ErrTimestampOutOfRange, http.StatusUnauthorized, "ErrSignatureExpired")
+}
+
+// Sign mengembalikan signature body dengan timestamp, mis. "sha256=ab12...".
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	return Prefix + hex.EncodeToString(mac(secret, timestamp.Unix(), body))
+}
+
+// Verify memeriksa signature dan timestamp (detik Unix) dari header request.
+// Timestamp harus berada dalam tolerance dari now ke dua arah, dan
+// signature dibandingkan dalam waktu konstan. Mengembalikan waktu dari
+// timestamp jika valid.
+func Verify(secret, signature, timestamp string, body []byte, now time.Time, tolerance time.Duration) (time.Time, error) {
+	if signature == "" || timestamp == "" {
+		return time.Time{}, ErrMissingSignature
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return time.Time{}, ErrInvalidTimestamp
+	}
+	signedAt := time.Unix(seconds, 0)
+	if skew := now.Sub(signedAt); skew > tolerance || skew < -tolerance {
+		return time.Time{}, ErrTimestampOutOfRange
+	}
+
+	encoded, ok := strings.CutPrefix(signature, Prefix)
+	if !ok {
+		return time.Time{}, ErrSignatureMismatch
+	}
+	given, err := hex.DecodeString(encoded)
+	if err != nil || !hmac.Equal(given, mac(secret, seconds, body)) {
+		return time.Time{}, ErrSignatureMismatch
+	}
+	return signedAt, nil
+}
+
+func mac(secret string, timestamp int64, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}
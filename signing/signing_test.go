@@ -0,0 +1,126 @@
+package signing
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSecret = "partner-secret"
+
+var (
+	signedAt = time.Unix(1700000000, 0)
+	body     = []byte(`{"event":"employee.synced"}`)
+)
+
+func TestSignIsDeterministic(t *testing.T) {
+	signature := Sign(testSecret, signedAt, body)
+	if !strings.HasPrefix(signature, Prefix) || len(signature) != len(Prefix)+64 {
+		t.Fatalf("Sign = %q, want sha256= and 64 hex characters", signature)
+	}
+	if again := Sign(testSecret, signedAt, body); again != signature {
+		t.Fatalf("Sign is not deterministic: %q, %q", signature, again)
+	}
+	// Hanya detik yang ditandatangani, sama dengan header timestamp
+	if sub := Sign(testSecret, signedAt.Add(999*time.Millisecond), body); sub != signature {
+		t.Fatalf("Sign with sub-second timestamp = %q, want %q", sub, signature)
+	}
+	for name, other := range map[string]string{
+		"secret":    Sign("other-secret", signedAt, body),
+		"timestamp": Sign(testSecret, signedAt.Add(time.Second), body),
+		"body":      Sign(testSecret, signedAt, []byte(`{"event":"employee.deleted"}`)),
+	} {
+		if other == signature {
+			t.Errorf("changing the %s did not change the signature", name)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+	signature := Sign(testSecret, signedAt, body)
+	const tolerance = 5 * time.Minute
+
+	for _, tt := range []struct {
+		name   string
+		secret string
+		// Kosong berarti nilai yang valid, "-" berarti header tidak dikirim
+		signature string
+		timestamp string
+		body      string
+		now       time.Time
+		want      error
+	}{
+		{name: "valid", now: signedAt},
+		{name: "uppercase hex", signature: Prefix + strings.ToUpper(strings.TrimPrefix(signature, Prefix)), now: signedAt},
+		{name: "missing signature", signature: "-", now: signedAt, want: ErrMissingSignature},
+		{name: "missing timestamp", timestamp: "-", now: signedAt, want: ErrMissingSignature},
+		{name: "timestamp not a number", timestamp: "2023-11-14T22:13:20Z", now: signedAt, want: ErrInvalidTimestamp},
+		{name: "timestamp in milliseconds", timestamp: timestamp + "000", now: signedAt, want: ErrTimestampOutOfRange},
+		// Batas tolerance masih diterima ke dua arah
+		{name: "old within tolerance", now: signedAt.Add(tolerance)},
+		{name: "future within tolerance", now: signedAt.Add(-tolerance)},
+		{name: "replayed after tolerance", now: signedAt.Add(tolerance + time.Second), want: ErrTimestampOutOfRange},
+		{name: "sender clock ahead", now: signedAt.Add(-tolerance - time.Second), want: ErrTimestampOutOfRange},
+		{name: "wrong secret", secret: "other-secret", now: signedAt, want: ErrSignatureMismatch},
+		{name: "tampered body", body: `{"event":"employee.deleted"}`, now: signedAt, want: ErrSignatureMismatch},
+		{name: "timestamp changed", timestamp: strconv.FormatInt(signedAt.Unix()+1, 10), now: signedAt, want: ErrSignatureMismatch},
+		{name: "without prefix", signature: strings.TrimPrefix(signature, Prefix), now: signedAt, want: ErrSignatureMismatch},
+		{name: "other algorithm", signature: "sha1=" + strings.TrimPrefix(signature, Prefix), now: signedAt, want: ErrSignatureMismatch},
+		{name: "not hex", signature: Prefix + strings.Repeat("z", 64), now: signedAt, want: ErrSignatureMismatch},
+		{name: "truncated", signature: signature[:len(signature)-2], now: signedAt, want: ErrSignatureMismatch},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			secret, sig, ts, payload := testSecret, signature, timestamp, body
+			if tt.secret != "" {
+				secret = tt.secret
+			}
+			if tt.signature != "" {
+				sig = strings.TrimPrefix(tt.signature, "-")
+			}
+			if tt.timestamp != "" {
+				ts = strings.TrimPrefix(tt.timestamp, "-")
+			}
+			if tt.body != "" {
+				payload = []byte(tt.body)
+			}
+
+			got, err := Verify(secret, sig, ts, payload, tt.now, tolerance)
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Fatalf("Verify error = %v, want %v", err, tt.want)
+			}
+			if tt.want == nil && !got.Equal(signedAt) {
+				t.Fatalf("Verify = %v, want %v", got, signedAt)
+			}
+			if tt.want != nil && !got.IsZero() {
+				t.Fatalf("Verify = %v on error, want zero time", got)
+			}
+		})
+	}
+}
+
+// TestVerifyComparesWholeSignature memastikan byte mana pun yang berbeda
+// ditolak dengan error yang sama, tanpa membedakan posisi byte pertama
+// yang salah. Perbandingannya memakai hmac.Equal, yang waktunya tidak
+// bergantung pada posisi tersebut.
+func TestVerifyComparesWholeSignature(t *testing.T) {
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+	signature := Sign(testSecret, signedAt, body)
+	for i := len(Prefix); i < len(signature); i++ {
+		flipped := []byte(signature)
+		if flipped[i] == '0' {
+			flipped[i] = '1'
+		} else {
+			flipped[i] = '0'
+		}
+		if _, err := Verify(testSecret, string(flipped), timestamp, body, signedAt, time.Minute); err != ErrSignatureMismatch {
+			t.Fatalf("signature changed at %d: Verify error = %v, want %v", i, err, ErrSignatureMismatch)
+		}
+	}
+	// Signature yang lebih panjang dengan awalan benar juga ditolak
+	if _, err := Verify(testSecret, signature+"00", timestamp, body, signedAt, time.Minute); err != ErrSignatureMismatch {
+		t.Fatalf("signature with extra bytes: Verify error = %v, want %v", err, ErrSignatureMismatch)
+	}
+}
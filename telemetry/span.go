@@ -0,0 +1,39 @@
+package telemetry
+
+import (
+	"context"
+
+	"github.com/levensspel/go-gin-template/helper"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// End mengakhiri span. Jika err tidak nil, error dicatat beserta status code
+// dan error key hasil mapping helper. Span hanya ditandai Error untuk status
+// 5xx, karena 4xx adalah kesalahan client dan bukan kegagalan service.
+func End(span trace.Span, err error) {
+	if err != nil {
+		// Status sama dengan yang dikirim ke client, termasuk 400 untuk
+		// error validasi per field
+		status, _ := helper.FromError(err)
+		span.SetAttributes(
+			attribute.Int("error.status_code", status),
+			attribute.String("error.key", helper.GetErrorKey(err)),
+		)
+		span.RecordError(err)
+		if status >= 500 {
+			span.SetStatus(codes.Error, helper.GetErrorKey(err))
+		}
+	}
+	span.End()
+}
+
+// Step menjalankan fn dalam child span bernama name, mis. insert atau
+// pengecekan ownership di dalam satu method service.
+func Step(ctx context.Context, tracer trace.Tracer, name string, fn func(ctx context.Context) error, attributes ...attribute.KeyValue) error {
+	ctx, span := tracer.Start(ctx, name, trace.WithAttributes(attributes...))
+	err := fn(ctx)
+	End(span, err)
+	return err
+}
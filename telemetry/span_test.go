@@ -0,0 +1,99 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/levensspel/go-gin-template/helper"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newRecorder() (*tracetest.SpanRecorder, *sdktrace.TracerProvider) {
+	recorder := tracetest.NewSpanRecorder()
+	return recorder, sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+}
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	values := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		values[kv.Key] = kv.Value
+	}
+	return values
+}
+
+func TestEnd(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int64
+		wantKey    string
+		wantCode   codes.Code
+	}{
+		{name: "success", wantCode: codes.Unset},
+		// 4xx dicatat tapi span tidak ditandai gagal
+		{name: "client error", err: fmt.Errorf("lookup: %w", helper.ErrNotFound), wantStatus: 404, wantKey: "ErrNotFound", wantCode: codes.Unset},
+		{name: "server error", err: helper.ErrInternalServer, wantStatus: 500, wantKey: "ErrInternalServer", wantCode: codes.Error},
+		{name: "unregistered error", err: errors.New("boom"), wantStatus: 500, wantKey: "ErrUnknown", wantCode: codes.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder, provider := newRecorder()
+			_, span := provider.Tracer(TracerName).Start(context.Background(), "operation")
+			End(span, tt.err)
+
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("ended %d spans, want 1", len(spans))
+			}
+			got := spans[0]
+			if got.Status().Code != tt.wantCode {
+				t.Errorf("status = %v, want %v", got.Status().Code, tt.wantCode)
+			}
+			values := attributes(got)
+			if tt.err == nil {
+				if len(values) != 0 || len(got.Events()) != 0 {
+					t.Fatalf("successful span has attributes %v and events %v", values, got.Events())
+				}
+				return
+			}
+			if values["error.status_code"].AsInt64() != tt.wantStatus || values["error.key"].AsString() != tt.wantKey {
+				t.Errorf("attributes = %v", values)
+			}
+			if events := got.Events(); len(events) != 1 || events[0].Name != "exception" {
+				t.Errorf("events = %v, want one exception", events)
+			}
+		})
+	}
+}
+
+func TestStepStartsChildSpan(t *testing.T) {
+	recorder, provider := newRecorder()
+	tracer := provider.Tracer(TracerName)
+	errStep := errors.New("insert failed")
+
+	ctx, parent := tracer.Start(context.Background(), "EmployeeService.Create")
+	err := Step(ctx, tracer, "employee.insert", func(ctx context.Context) error {
+		return errStep
+	}, attribute.String("employee.department_id", "dept-1"))
+	parent.End()
+	if !errors.Is(err, errStep) {
+		t.Fatalf("Step error = %v, want %v", err, errStep)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 || spans[0].Name() != "employee.insert" {
+		t.Fatalf("ended spans = %v", spans)
+	}
+	child := spans[0]
+	if child.Parent().SpanID() != spans[1].SpanContext().SpanID() {
+		t.Fatal("step span is not a child of the caller span")
+	}
+	if values := attributes(child); values["employee.department_id"].AsString() != "dept-1" || values["error.key"].AsString() != "ErrUnknown" {
+		t.Fatalf("step attributes = %v", values)
+	}
+}
@@ -0,0 +1,114 @@
+package telemetry
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/samber/do/v2"
+)
+
+const (
+	// TracerName dipakai oleh semua span yang dibuat aplikasi ini.
+	TracerName = "github.com/levensspel/go-gin-template"
+
+	DefaultServiceName = "go-gin-template"
+)
+
+// Tracing membungkus tracer provider beserta fungsi shutdown-nya sehingga
+// span yang masih di-buffer ikut terkirim saat injector di-shutdown.
+type Tracing struct {
+	Provider trace.TracerProvider
+	shutdown func(ctx context.Context) error
+}
+
+// Enabled bernilai true jika exporter OTLP dikonfigurasi lewat env OTEL_*.
+func Enabled() bool {
+	if os.Getenv("OTEL_SDK_DISABLED") == "true" || os.Getenv("OTEL_TRACES_EXPORTER") == "none" {
+		return false
+	}
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// NewTracing membuat tracer provider dari env OTEL_* standar
+// (OTEL_EXPORTER_OTLP_ENDPOINT, OTEL_SERVICE_NAME, OTEL_TRACES_SAMPLER, dst).
+// Jika exporter tidak dikonfigurasi, provider no-op dipakai sehingga
+// tidak ada overhead di local dev.
+func NewTracing(ctx context.Context) (*Tracing, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if !Enabled() {
+		provider := noop.NewTracerProvider()
+		otel.SetTracerProvider(provider)
+		return &Tracing{
+			Provider: provider,
+			shutdown: func(ctx context.Context) error { return nil },
+		}, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(ServiceName())),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	log.Println("OpenTelemetry tracing enabled")
+
+	return &Tracing{
+		Provider: provider,
+		shutdown: provider.Shutdown,
+	}, nil
+}
+
+func NewTracingInject(i do.Injector) (*Tracing, error) {
+	return NewTracing(context.Background())
+}
+
+// NewTracerInject mengembalikan tracer aplikasi dari provider Tracing, no-op
+// jika exporter OTLP tidak dikonfigurasi.
+func NewTracerInject(i do.Injector) (trace.Tracer, error) {
+	return do.MustInvoke[*Tracing](i).Provider.Tracer(TracerName), nil
+}
+
+// Shutdown mem-flush span yang tersisa, dipanggil oleh injector saat shutdown.
+func (t *Tracing) Shutdown(ctx context.Context) error {
+	return t.shutdown(ctx)
+}
+
+// ServiceName mengikuti OTEL_SERVICE_NAME jika diset.
+func ServiceName() string {
+	if name := os.Getenv("OTEL_SERVICE_NAME"); name != "" {
+		return name
+	}
+	return DefaultServiceName
+}
+
+// Tracer mengembalikan tracer aplikasi dari provider global.
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
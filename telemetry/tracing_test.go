@@ -0,0 +1,54 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestEnabled(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{name: "unset", want: false},
+		{name: "endpoint", env: map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318"}, want: true},
+		{name: "traces endpoint", env: map[string]string{"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "http://collector:4318/v1/traces"}, want: true},
+		{name: "sdk disabled", env: map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318", "OTEL_SDK_DISABLED": "true"}, want: false},
+		{name: "exporter none", env: map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318", "OTEL_TRACES_EXPORTER": "none"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"OTEL_EXPORTER_OTLP_ENDPOINT", "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "OTEL_SDK_DISABLED", "OTEL_TRACES_EXPORTER"} {
+				t.Setenv(key, tt.env[key])
+			}
+			if got := Enabled(); got != tt.want {
+				t.Fatalf("Enabled() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNewTracingNoopWithoutExporter memastikan local dev tanpa env OTEL_*
+// memakai provider no-op yang tidak merekam span.
+func TestNewTracingNoopWithoutExporter(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+
+	tracing, err := NewTracing(context.Background())
+	if err != nil {
+		t.Fatalf("NewTracing() error = %v", err)
+	}
+	if _, ok := tracing.Provider.(noop.TracerProvider); !ok {
+		t.Fatalf("Provider = %T, want noop.TracerProvider", tracing.Provider)
+	}
+	_, span := tracing.Provider.Tracer(TracerName).Start(context.Background(), "operation")
+	if span.IsRecording() {
+		t.Fatal("span from the no-op provider is recording")
+	}
+	if err := tracing.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+}
@@ -0,0 +1,229 @@
+package thumbnail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/domain"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/lifecycle"
+	"github.com/levensspel/go-gin-template/logger"
+	fileRepository "github.com/levensspel/go-gin-template/repository/file"
+	"github.com/levensspel/go-gin-template/worker"
+	"github.com/samber/do/v2"
+)
+
+// taskName adalah nama task di worker.Pool dan label metric-nya.
+const taskName = "thumbnail"
+
+// Generator membuat thumbnail file yang berstatus pending di worker.Pool.
+// File diambil dari database (lihat FileRepository.ClaimThumbnails), bukan
+// dari antrean di memori, sehingga thumbnail yang gagal, tertinggal karena
+// antrean penuh, atau hilang saat instance mati dicoba lagi. Kegagalan
+// thumbnail tidak memengaruhi file aslinya.
+type Generator struct {
+	repo       fileRepository.FileRepositoryInterface
+	storage    domain.Storage
+	pool       *worker.Pool
+	config     *config.ThumbnailConfig
+	fileConfig *config.FileConfig
+	logger     logger.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	// wake membangunkan loop tanpa menunggu PollInterval, lihat Notify
+	wake chan struct{}
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+func NewGenerator(
+	ctx context.Context,
+	repo fileRepository.FileRepositoryInterface,
+	storage domain.Storage,
+	pool *worker.Pool,
+	config *config.ThumbnailConfig,
+	fileConfig *config.FileConfig,
+	logger logger.Logger,
+) *Generator {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Generator{
+		repo:       repo,
+		storage:    storage,
+		pool:       pool,
+		config:     config,
+		fileConfig: fileConfig,
+		logger:     logger,
+		ctx:        ctx,
+		cancel:     cancel,
+		wake:       make(chan struct{}, 1),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+// NewGeneratorInject langsung menjalankan generator. Generator dihentikan
+// saat injector di-shutdown.
+func NewGeneratorInject(i do.Injector) (*Generator, error) {
+	appLogger := do.MustInvoke[logger.LogHandler](i)
+	generator := NewGenerator(
+		do.MustInvoke[*lifecycle.Lifetime](i).Context(),
+		do.MustInvoke[fileRepository.FileRepositoryInterface](i),
+		do.MustInvoke[domain.Storage](i),
+		do.MustInvoke[*worker.Pool](i),
+		config.LoadThumbnailConfig(),
+		config.LoadFileConfig(),
+		&appLogger,
+	)
+	generator.Start()
+	return generator, nil
+}
+
+// Enabled mengembalikan false jika THUMBNAIL_SIZE=0. File baru sebaiknya
+// tidak diberi status pending jika generator tidak berjalan.
+func (g *Generator) Enabled() bool {
+	return g.config.Size > 0
+}
+
+// Notify meminta generator segera mengambil thumbnail pending, mis. setelah
+// upload. Tidak pernah menunggu.
+func (g *Generator) Notify() {
+	select {
+	case g.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (g *Generator) Start() {
+	if !g.Enabled() {
+		close(g.done)
+		return
+	}
+	go g.run()
+}
+
+func (g *Generator) run() {
+	defer close(g.done)
+
+	batchSize := max(g.config.BatchSize, 1)
+	for {
+		submitted, err := g.SubmitOnce(g.ctx, batchSize)
+		if err != nil && g.ctx.Err() == nil {
+			g.logger.Warn(fmt.Sprintf("Thumbnail submit failed: %v", err), helper.ThumbnailGenerator)
+		}
+
+		// Batch penuh berarti kemungkinan masih ada thumbnail lain yang menunggu
+		wait := g.config.PollInterval
+		if err == nil && submitted == batchSize {
+			wait = 0
+		}
+		select {
+		case <-g.stop:
+			return
+		case <-g.ctx.Done():
+			return
+		case <-g.wake:
+		case <-time.After(wait):
+		}
+	}
+}
+
+// SubmitOnce mengambil paling banyak limit thumbnail pending dan
+// mengantrikannya ke worker.Pool. Jika antrean penuh, sisa file yang sudah
+// diambil dicoba lagi setelah Lease habis.
+func (g *Generator) SubmitOnce(ctx context.Context, limit int) (int, error) {
+	files, err := g.repo.ClaimThumbnails(ctx, g.config.Lease, limit)
+	if err != nil {
+		return 0, err
+	}
+	for i, file := range files {
+		err := g.pool.Submit(worker.Task{
+			Name:    taskName,
+			Timeout: g.config.Timeout,
+			Run: func(ctx context.Context) error {
+				return g.Generate(ctx, file)
+			},
+		})
+		if err != nil {
+			return i, err
+		}
+	}
+	return len(files), nil
+}
+
+// Generate membuat thumbnail satu file lalu menandainya ready. Jika gagal,
+// thumbnail dijadwalkan ulang dengan backoff, atau ditandai failed setelah
+// MaxAttempts atau jika gambarnya memang tidak bisa dibuatkan thumbnail.
+func (g *Generator) Generate(ctx context.Context, file entity.File) error {
+	err := g.generate(ctx, file)
+	if err == nil {
+		return nil
+	}
+
+	final := errors.Is(err, ErrUnsupportedImage) ||
+		errors.Is(err, helper.ErrNotFound) ||
+		file.ThumbnailAttempts+1 >= g.config.MaxAttempts
+	// Status tetap dicatat walau ctx task sudah habis
+	markCtx := context.WithoutCancel(ctx)
+	if markErr := g.repo.MarkThumbnailFailed(markCtx, file.FileId, g.retryAfter(file.ThumbnailAttempts), final); markErr != nil {
+		return errors.Join(err, markErr)
+	}
+	return err
+}
+
+func (g *Generator) generate(ctx context.Context, file entity.File) error {
+	object, err := g.storage.Get(ctx, file.FileKey)
+	if err != nil {
+		return err
+	}
+	defer object.Body.Close()
+
+	data, contentType, err := Resize(object.Body, g.config.Size, g.config.MaxPixels)
+	if err != nil {
+		return err
+	}
+
+	key := Key(file.FileKey)
+	uri, err := g.storage.Put(ctx, key, contentType, bytes.NewReader(data), int64(len(data)), true)
+	if err != nil {
+		return err
+	}
+	if g.fileConfig.BaseURL != "" {
+		uri = fmt.Sprintf("%s/files/%s/thumbnail", strings.TrimSuffix(g.fileConfig.BaseURL, "/"), file.FileId)
+	}
+	return g.repo.MarkThumbnailReady(ctx, file.FileId, key, uri)
+}
+
+// retryAfter menghitung jeda eksponensial berdasarkan jumlah percobaan
+// sebelumnya.
+func (g *Generator) retryAfter(attempts int) time.Duration {
+	backoff := g.config.RetryBackoff
+	for i := 0; i < attempts && backoff < g.config.MaxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, g.config.MaxRetryBackoff)
+}
+
+// Shutdown menghentikan loop pengambilan thumbnail. Thumbnail yang sudah di
+// worker.Pool diselesaikan oleh Pool.Shutdown; yang tidak sempat selesai
+// diambil lagi setelah restart.
+func (g *Generator) Shutdown(ctx context.Context) error {
+	g.once.Do(func() { close(g.stop) })
+	select {
+	case <-g.done:
+		g.cancel()
+		return nil
+	case <-ctx.Done():
+		g.cancel()
+		<-g.done
+		return ctx.Err()
+	}
+}
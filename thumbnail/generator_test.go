@@ -0,0 +1,334 @@
+package thumbnail_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/domain"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/infrastructure/storage"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/mocks"
+	"github.com/levensspel/go-gin-template/thumbnail"
+	"github.com/levensspel/go-gin-template/worker"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// failingStorage menggagalkan Put, mis. saat storage sedang tidak bisa
+// dihubungi.
+type failingStorage struct {
+	domain.Storage
+	err error
+}
+
+func (s failingStorage) Put(ctx context.Context, key string, contentType string, body io.Reader, size int64, public bool) (string, error) {
+	return "", s.err
+}
+
+// failure adalah satu panggilan MarkThumbnailFailed.
+type failure struct {
+	fileID     string
+	retryAfter time.Duration
+	final      bool
+}
+
+// generatorFixture mencatat status thumbnail yang ditulis Generator.
+type generatorFixture struct {
+	storage  domain.Storage
+	repo     *mocks.FileRepository
+	config   *config.ThumbnailConfig
+	fileCfg  *config.FileConfig
+	mu       sync.Mutex
+	ready    map[string][2]string
+	failures []failure
+}
+
+func newGeneratorFixture() *generatorFixture {
+	f := &generatorFixture{
+		storage: storage.NewMemoryStorage(""),
+		config: &config.ThumbnailConfig{
+			Size:            128,
+			MaxPixels:       1_000_000,
+			Timeout:         5 * time.Second,
+			MaxAttempts:     3,
+			RetryBackoff:    time.Second,
+			MaxRetryBackoff: 3 * time.Second,
+			Lease:           time.Minute,
+			PollInterval:    time.Hour,
+			BatchSize:       10,
+		},
+		fileCfg: &config.FileConfig{},
+		ready:   map[string][2]string{},
+	}
+	f.repo = &mocks.FileRepository{
+		MarkThumbnailReadyFunc: func(ctx context.Context, fileID string, key string, uri string) error {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			f.ready[fileID] = [2]string{key, uri}
+			return nil
+		},
+		MarkThumbnailFailedFunc: func(ctx context.Context, fileID string, retryAfter time.Duration, final bool) error {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			f.failures = append(f.failures, failure{fileID: fileID, retryAfter: retryAfter, final: final})
+			return nil
+		},
+	}
+	return f
+}
+
+func (f *generatorFixture) generator(pool *worker.Pool) *thumbnail.Generator {
+	return thumbnail.NewGenerator(context.Background(), f.repo, f.storage, pool, f.config, f.fileCfg, mocks.Logger{})
+}
+
+// put menyimpan gambar asli dan mengembalikan file pending-nya.
+func (f *generatorFixture) put(t *testing.T, id string, format string, data []byte) entity.File {
+	t.Helper()
+	key := "file/manager/" + id + "." + format
+	if _, err := f.storage.Put(context.Background(), key, "image/"+format, bytes.NewReader(data), int64(len(data)), true); err != nil {
+		t.Fatal(err)
+	}
+	return entity.File{FileId: id, FileKey: key, ContentType: "image/" + format, ThumbnailStatus: entity.ThumbnailPending}
+}
+
+func TestGenerateStoresThumbnail(t *testing.T) {
+	for _, format := range []string{"jpeg", "png"} {
+		t.Run(format, func(t *testing.T) {
+			f := newGeneratorFixture()
+			file := f.put(t, "file-1", format, encodeImage(t, format, 400, 200))
+
+			if err := f.generator(nil).Generate(context.Background(), file); err != nil {
+				t.Fatalf("Generate error = %v", err)
+			}
+			wantKey := thumbnail.Key(file.FileKey)
+			if got := f.ready["file-1"]; got != [2]string{wantKey, f.storage.URL(wantKey)} {
+				t.Fatalf("MarkThumbnailReady = %v, want key %s and its storage url", got, wantKey)
+			}
+			object, err := f.storage.Get(context.Background(), wantKey)
+			if err != nil {
+				t.Fatalf("thumbnail not stored: %v", err)
+			}
+			defer object.Body.Close()
+			config, gotFormat, err := image.DecodeConfig(object.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gotFormat != format || config.Width != 128 || config.Height != 64 || object.ContentType != "image/"+format {
+				t.Fatalf("thumbnail = %s %dx%d (%s), want %s 128x64", gotFormat, config.Width, config.Height, object.ContentType, format)
+			}
+			if len(f.failures) != 0 {
+				t.Fatalf("MarkThumbnailFailed called: %+v", f.failures)
+			}
+		})
+	}
+}
+
+func TestGenerateURIUsesBaseURL(t *testing.T) {
+	f := newGeneratorFixture()
+	f.fileCfg.BaseURL = "https://api.example.com/"
+	file := f.put(t, "file-1", "png", encodeImage(t, "png", 400, 200))
+
+	if err := f.generator(nil).Generate(context.Background(), file); err != nil {
+		t.Fatalf("Generate error = %v", err)
+	}
+	if got := f.ready["file-1"][1]; got != "https://api.example.com/files/file-1/thumbnail" {
+		t.Fatalf("thumbnail uri = %s", got)
+	}
+}
+
+func TestGenerateFailures(t *testing.T) {
+	errStorage := errors.New("storage unavailable")
+	for _, tt := range []struct {
+		name     string
+		file     func(t *testing.T, f *generatorFixture) entity.File
+		attempts int
+		want     failure
+	}{
+		{
+			// Percobaan pertama dijadwalkan ulang setelah RetryBackoff
+			name: "transient",
+			file: func(t *testing.T, f *generatorFixture) entity.File {
+				file := f.put(t, "file-1", "png", encodeImage(t, "png", 400, 200))
+				f.storage = failingStorage{Storage: f.storage, err: errStorage}
+				return file
+			},
+			want: failure{fileID: "file-1", retryAfter: time.Second},
+		},
+		{
+			name: "backoff is capped",
+			file: func(t *testing.T, f *generatorFixture) entity.File {
+				f.config.MaxAttempts = 10
+				file := f.put(t, "file-1", "png", encodeImage(t, "png", 400, 200))
+				f.storage = failingStorage{Storage: f.storage, err: errStorage}
+				return file
+			},
+			attempts: 5,
+			want:     failure{fileID: "file-1", retryAfter: 3 * time.Second},
+		},
+		{
+			name: "last attempt",
+			file: func(t *testing.T, f *generatorFixture) entity.File {
+				file := f.put(t, "file-1", "png", encodeImage(t, "png", 400, 200))
+				f.storage = failingStorage{Storage: f.storage, err: errStorage}
+				return file
+			},
+			attempts: 2,
+			want:     failure{fileID: "file-1", retryAfter: 3 * time.Second, final: true},
+		},
+		{
+			name: "unsupported image",
+			file: func(t *testing.T, f *generatorFixture) entity.File {
+				return f.put(t, "file-1", "gif", encodeImage(t, "gif", 10, 10))
+			},
+			want: failure{fileID: "file-1", retryAfter: time.Second, final: true},
+		},
+		{
+			name: "missing original",
+			file: func(t *testing.T, f *generatorFixture) entity.File {
+				return entity.File{FileId: "file-1", FileKey: "file/manager/missing.png"}
+			},
+			want: failure{fileID: "file-1", retryAfter: time.Second, final: true},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newGeneratorFixture()
+			file := tt.file(t, f)
+			file.ThumbnailAttempts = tt.attempts
+
+			if err := f.generator(nil).Generate(context.Background(), file); err == nil {
+				t.Fatal("Generate succeeded")
+			}
+			if len(f.failures) != 1 || f.failures[0] != tt.want {
+				t.Fatalf("MarkThumbnailFailed = %+v, want %+v", f.failures, tt.want)
+			}
+			if len(f.ready) != 0 {
+				t.Fatalf("MarkThumbnailReady called: %v", f.ready)
+			}
+		})
+	}
+}
+
+func TestGenerateMarksFailedAfterCancel(t *testing.T) {
+	f := newGeneratorFixture()
+	file := f.put(t, "file-1", "png", encodeImage(t, "png", 400, 200))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	f.repo.MarkThumbnailFailedFunc = func(ctx context.Context, fileID string, retryAfter time.Duration, final bool) error {
+		// Status tetap dicatat walau context task sudah habis
+		if ctx.Err() != nil {
+			t.Errorf("MarkThumbnailFailed context error = %v", ctx.Err())
+		}
+		return nil
+	}
+	f.storage = failingStorage{Storage: f.storage, err: context.Canceled}
+
+	if err := f.generator(nil).Generate(ctx, file); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Generate error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestSubmitOnceRunsOnPool(t *testing.T) {
+	f := newGeneratorFixture()
+	files := []entity.File{
+		f.put(t, "file-1", "png", encodeImage(t, "png", 400, 200)),
+		f.put(t, "file-2", "jpeg", encodeImage(t, "jpeg", 200, 400)),
+	}
+	f.repo.ClaimThumbnailsFunc = func(ctx context.Context, lease time.Duration, limit int) ([]entity.File, error) {
+		if lease != f.config.Lease || limit != 5 {
+			t.Errorf("ClaimThumbnails(%s, %d), want (%s, 5)", lease, limit, f.config.Lease)
+		}
+		return files, nil
+	}
+	pool := worker.New(&config.WorkerConfig{Size: 2, QueueDepth: 2, TaskTimeout: time.Second}, metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{}), mocks.Logger{})
+	pool.Start()
+
+	submitted, err := f.generator(pool).SubmitOnce(context.Background(), 5)
+	if err != nil || submitted != 2 {
+		t.Fatalf("SubmitOnce = %d, %v, want 2", submitted, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := pool.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.ready) != 2 {
+		t.Fatalf("MarkThumbnailReady = %v, want both files", f.ready)
+	}
+}
+
+func TestSubmitOnceClaimError(t *testing.T) {
+	f := newGeneratorFixture()
+	errClaim := errors.New("claim failed")
+	f.repo.ClaimThumbnailsFunc = func(ctx context.Context, lease time.Duration, limit int) ([]entity.File, error) {
+		return nil, errClaim
+	}
+	if submitted, err := f.generator(nil).SubmitOnce(context.Background(), 5); !errors.Is(err, errClaim) || submitted != 0 {
+		t.Fatalf("SubmitOnce = %d, %v, want %v", submitted, err, errClaim)
+	}
+}
+
+// TestStartGeneratesPendingThumbnails menjalankan loop generator: Notify
+// membangunkannya tanpa menunggu PollInterval.
+func TestStartGeneratesPendingThumbnails(t *testing.T) {
+	f := newGeneratorFixture()
+	file := f.put(t, "file-1", "png", encodeImage(t, "png", 400, 200))
+	var claimed sync.Once
+	f.repo.ClaimThumbnailsFunc = func(ctx context.Context, lease time.Duration, limit int) ([]entity.File, error) {
+		files := []entity.File{}
+		claimed.Do(func() { files = append(files, file) })
+		return files, nil
+	}
+	markedReady := make(chan string, 1)
+	f.repo.MarkThumbnailReadyFunc = func(ctx context.Context, fileID string, key string, uri string) error {
+		markedReady <- fileID
+		return nil
+	}
+	pool := worker.New(&config.WorkerConfig{Size: 1, QueueDepth: 1, TaskTimeout: time.Second}, metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{}), mocks.Logger{})
+	pool.Start()
+	generator := f.generator(pool)
+	generator.Start()
+	generator.Notify()
+
+	select {
+	case id := <-markedReady:
+		if id != "file-1" {
+			t.Fatalf("MarkThumbnailReady(%s), want file-1", id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("thumbnail was not generated")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := generator.Shutdown(ctx); err != nil {
+		t.Fatalf("generator Shutdown error = %v", err)
+	}
+	if err := pool.Shutdown(ctx); err != nil {
+		t.Fatalf("pool Shutdown error = %v", err)
+	}
+}
+
+func TestDisabledGenerator(t *testing.T) {
+	f := newGeneratorFixture()
+	f.config.Size = 0
+	generator := f.generator(nil)
+	if generator.Enabled() {
+		t.Fatal("Enabled() = true with THUMBNAIL_SIZE=0")
+	}
+	// Start tidak menjalankan loop, sehingga ClaimThumbnails tidak dipanggil
+	generator.Start()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := generator.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown error = %v", err)
+	}
+	if err := generator.Shutdown(ctx); err != nil {
+		t.Fatalf("second Shutdown error = %v", err)
+	}
+}
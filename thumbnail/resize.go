@@ -0,0 +1,123 @@
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/levensspel/go-gin-template/entity"
+	"golang.org/x/image/draw"
+)
+
+// ErrUnsupportedImage dikembalikan jika gambar tidak bisa dibuatkan
+// thumbnail. Percobaan ulang tidak akan berhasil, sehingga thumbnail langsung
+// ditandai failed.
+var ErrUnsupportedImage = errors.New("unsupported image")
+
+// jpegQuality adalah kualitas encode thumbnail JPEG.
+const jpegQuality = 85
+
+// Resize mengecilkan gambar JPEG atau PNG sehingga sisi terpanjangnya paling
+// besar size pixel, dengan format yang sama dengan aslinya. Gambar yang
+// sudah lebih kecil tidak diperbesar. Gambar lebih dari maxPixels ditolak
+// sebelum di-decode. Thumbnail di-encode ulang dari pixel-nya, sehingga
+// metadata gambar asli (EXIF termasuk lokasi GPS, chunk teks PNG) tidak
+// ikut tersimpan.
+func Resize(r io.Reader, size int, maxPixels int) (data []byte, contentType string, err error) {
+	return ResizeToFit(r, size, size, maxPixels)
+}
+
+// ResizeToFit seperti Resize, tetapi gambar dikecilkan agar muat di kotak
+// width x height dengan rasio aspek yang sama, mis. untuk
+// GET /files/:id/resize.
+func ResizeToFit(r io.Reader, width int, height int, maxPixels int) (data []byte, contentType string, err error) {
+	original, err := io.ReadAll(r)
+	if err != nil {
+		return nil, "", err
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(original))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxPixels {
+		return nil, "", fmt.Errorf("%w: %dx%d pixels", ErrUnsupportedImage, config.Width, config.Height)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(original))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+	dstWidth, dstHeight := fit(config.Width, config.Height, width, height)
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Src, nil)
+
+	var buf bytes.Buffer
+	switch format {
+	case "jpeg":
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: jpegQuality})
+		contentType = "image/jpeg"
+	case "png":
+		err = png.Encode(&buf, dst)
+		contentType = "image/png"
+	default:
+		return nil, "", fmt.Errorf("%w: format %s", ErrUnsupportedImage, format)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), contentType, nil
+}
+
+// fit menghitung ukuran terbesar yang muat di maxWidth x maxHeight dengan
+// rasio aspek yang sama. Gambar yang sudah muat tidak diperbesar.
+func fit(width int, height int, maxWidth int, maxHeight int) (int, int) {
+	if width <= maxWidth && height <= maxHeight {
+		return width, height
+	}
+	// Sisi yang paling jauh melebihi batasnya menentukan skala
+	if width*maxHeight >= height*maxWidth {
+		return maxWidth, max(1, height*maxWidth/width)
+	}
+	return max(1, width*maxHeight/height), maxHeight
+}
+
+// Key adalah key object thumbnail, di samping object aslinya.
+func Key(originalKey string) string {
+	ext := path.Ext(originalKey)
+	return strings.TrimSuffix(originalKey, ext) + "_thumb" + ext
+}
+
+// VariantKey adalah key object hasil ResizeToFit width x height, di samping
+// object aslinya, sehingga ukuran yang sama selalu memakai object yang sama.
+func VariantKey(originalKey string, width int, height int) string {
+	ext := path.Ext(originalKey)
+	return fmt.Sprintf("%s_%dx%d%s", strings.TrimSuffix(originalKey, ext), width, height, ext)
+}
+
+// ObjectKeys adalah semua object milik file: file asli, thumbnail, dan
+// hasil resize untuk setiap ukuran WxH di sizes (IMAGE_RESIZE_SIZES).
+// Dipakai saat file dihapus; hasil resize untuk ukuran yang sudah tidak ada
+// di sizes tidak ikut terhapus.
+func ObjectKeys(file entity.File, sizes []string) []string {
+	keys := make([]string, 0, 2+len(sizes))
+	if file.FileKey != "" {
+		keys = append(keys, file.FileKey)
+		for _, size := range sizes {
+			var width, height int
+			if _, err := fmt.Sscanf(size, "%dx%d", &width, &height); err == nil {
+				keys = append(keys, VariantKey(file.FileKey, width, height))
+			}
+		}
+	}
+	if file.ThumbnailKey != "" {
+		keys = append(keys, file.ThumbnailKey)
+	}
+	return keys
+}
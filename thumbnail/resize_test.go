@@ -0,0 +1,153 @@
+package thumbnail_test
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"slices"
+	"testing"
+
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/thumbnail"
+)
+
+// encodeImage membuat gambar width x height dalam format png, jpeg, atau gif.
+func encodeImage(t *testing.T, format string, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := range width {
+		for y := range height {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "png":
+		err = png.Encode(&buf, img)
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, nil)
+	case "gif":
+		err = gif.Encode(&buf, img, nil)
+	default:
+		t.Fatalf("unknown format %s", format)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestResize(t *testing.T) {
+	for _, tt := range []struct {
+		name            string
+		format          string
+		width, height   int
+		wantContentType string
+		wantWidth       int
+		wantHeight      int
+	}{
+		{name: "jpeg landscape", format: "jpeg", width: 400, height: 200, wantContentType: "image/jpeg", wantWidth: 128, wantHeight: 64},
+		{name: "png portrait", format: "png", width: 200, height: 400, wantContentType: "image/png", wantWidth: 64, wantHeight: 128},
+		{name: "png square", format: "png", width: 300, height: 300, wantContentType: "image/png", wantWidth: 128, wantHeight: 128},
+		// Gambar yang sudah kecil tidak diperbesar
+		{name: "smaller than size", format: "jpeg", width: 100, height: 50, wantContentType: "image/jpeg", wantWidth: 100, wantHeight: 50},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			data, contentType, err := thumbnail.Resize(bytes.NewReader(encodeImage(t, tt.format, tt.width, tt.height)), 128, 1_000_000)
+			if err != nil {
+				t.Fatalf("Resize error = %v", err)
+			}
+			if contentType != tt.wantContentType {
+				t.Errorf("Resize content type = %s, want %s", contentType, tt.wantContentType)
+			}
+			config, format, err := image.DecodeConfig(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("decode thumbnail: %v", err)
+			}
+			if format != tt.format || config.Width != tt.wantWidth || config.Height != tt.wantHeight {
+				t.Fatalf("thumbnail = %s %dx%d, want %s %dx%d", format, config.Width, config.Height, tt.format, tt.wantWidth, tt.wantHeight)
+			}
+		})
+	}
+}
+
+func TestResizeToFit(t *testing.T) {
+	data, _, err := thumbnail.ResizeToFit(bytes.NewReader(encodeImage(t, "png", 400, 200)), 100, 100, 1_000_000)
+	if err != nil {
+		t.Fatalf("ResizeToFit error = %v", err)
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Width != 100 || config.Height != 50 {
+		t.Fatalf("ResizeToFit = %dx%d, want 100x50", config.Width, config.Height)
+	}
+}
+
+func TestResizeRejectsUnsupportedImages(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		data      []byte
+		maxPixels int
+	}{
+		{name: "gif", data: encodeImage(t, "gif", 10, 10), maxPixels: 1_000_000},
+		{name: "not an image", data: []byte("not an image"), maxPixels: 1_000_000},
+		{name: "truncated", data: encodeImage(t, "png", 200, 200)[:100], maxPixels: 1_000_000},
+		// Batas pixel diperiksa dari header, sebelum di-decode
+		{name: "too many pixels", data: encodeImage(t, "png", 200, 100), maxPixels: 200*100 - 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := thumbnail.Resize(bytes.NewReader(tt.data), 128, tt.maxPixels); !errors.Is(err, thumbnail.ErrUnsupportedImage) {
+				t.Fatalf("Resize error = %v, want %v", err, thumbnail.ErrUnsupportedImage)
+			}
+		})
+	}
+}
+
+// TestResizeStripsExif menyisipkan segment APP1 Exif ke JPEG asli dan
+// memastikan thumbnail tidak membawanya.
+func TestResizeStripsExif(t *testing.T) {
+	original := encodeImage(t, "jpeg", 400, 200)
+	payload := append([]byte("Exif\x00\x00"), []byte("GPS 52.3676 4.9041")...)
+	segment := append([]byte{0xFF, 0xE1, byte((len(payload) + 2) >> 8), byte(len(payload) + 2)}, payload...)
+	withExif := slices.Concat(original[:2], segment, original[2:])
+	if _, _, err := image.DecodeConfig(bytes.NewReader(withExif)); err != nil {
+		t.Fatalf("JPEG with EXIF does not decode: %v", err)
+	}
+
+	data, _, err := thumbnail.Resize(bytes.NewReader(withExif), 128, 1_000_000)
+	if err != nil {
+		t.Fatalf("Resize error = %v", err)
+	}
+	if bytes.Contains(data, []byte("Exif")) || bytes.Contains(data, []byte("GPS")) {
+		t.Fatal("thumbnail kept the EXIF segment of the original")
+	}
+}
+
+func TestKeys(t *testing.T) {
+	if got := thumbnail.Key("file/manager/a.png"); got != "file/manager/a_thumb.png" {
+		t.Errorf("Key = %s", got)
+	}
+	if got := thumbnail.Key("file/manager/noext"); got != "file/manager/noext_thumb" {
+		t.Errorf("Key without extension = %s", got)
+	}
+	if got := thumbnail.VariantKey("file/manager/a.jpg", 64, 32); got != "file/manager/a_64x32.jpg" {
+		t.Errorf("VariantKey = %s", got)
+	}
+
+	file := entity.File{FileKey: "file/m/a.png", ThumbnailKey: "file/m/a_thumb.png"}
+	want := []string{"file/m/a.png", "file/m/a_64x64.png", "file/m/a_thumb.png"}
+	if got := thumbnail.ObjectKeys(file, []string{"64x64", "invalid"}); !slices.Equal(got, want) {
+		t.Errorf("ObjectKeys = %v, want %v", got, want)
+	}
+	// File lama tanpa filekey hanya memiliki thumbnail jika ada
+	if got := thumbnail.ObjectKeys(entity.File{}, []string{"64x64"}); len(got) != 0 {
+		t.Errorf("ObjectKeys without keys = %v, want none", got)
+	}
+}
@@ -0,0 +1,15 @@
+package validation
+
+import "github.com/levensspel/go-gin-template/dto"
+
+func ValidateAdminUserCreate(input *dto.AdminUserCreateRequest) error {
+	return Default().Struct(input)
+}
+
+func ValidateAdminUser(input *dto.AdminUserRequest) error {
+	return Default().Struct(input)
+}
+
+func ValidateAdminDepartmentReassign(input *dto.AdminDepartmentReassignRequest) error {
+	return Default().Struct(input)
+}
@@ -0,0 +1,7 @@
+package validation
+
+import "github.com/levensspel/go-gin-template/dto"
+
+func ValidateAPIKeyCreate(input *dto.APIKeyRequest) error {
+	return Default().Struct(input)
+}
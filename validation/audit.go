@@ -0,0 +1,15 @@
+package validation
+
+import (
+	"errors"
+
+	"github.com/levensspel/go-gin-template/dto"
+)
+
+func ValidateAuditGet(input *dto.GetAuditLogsRequest) error {
+	if input.From != nil && input.To != nil && !input.From.Before(*input.To) {
+		return errors.New("from must be before to")
+	}
+
+	return Default().Struct(input)
+}
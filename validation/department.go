@@ -0,0 +1,13 @@
+package validation
+
+import "github.com/levensspel/go-gin-template/helper"
+
+// ValidateDepartmentID menolak departmentId yang bukan UUID dengan
+// helper.ErrInvalidDepartmentId (400) sebelum query dijalankan, dengan
+// aturan yang sama seperti tag uuid di DTO employee.
+func ValidateDepartmentID(id string) error {
+	if err := Default().Var(id, "required,uuid"); err != nil {
+		return helper.ErrInvalidDepartmentId
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/idgen"
+)
+
+func TestValidateDepartmentID(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		wantErr bool
+	}{
+		{name: "generated", id: idgen.NewUUIDv7().NewID()},
+		{name: "uuid v4", id: "0b7c2d2e-4f4a-4b8e-9c59-2f1d8f6a3e10"},
+		{name: "empty", id: "", wantErr: true},
+		{name: "numeric", id: "42", wantErr: true},
+		{name: "not a uuid", id: "engineering", wantErr: true},
+		{name: "too short", id: "0b7c2d2e-4f4a-4b8e-9c59-2f1d8f6a3e1", wantErr: true},
+		{name: "without dashes", id: "0b7c2d2e4f4a4b8e9c592f1d8f6a3e10", wantErr: true},
+		{name: "sql", id: "' OR 1=1 --", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDepartmentID(tt.id)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("ValidateDepartmentID(%q) error = %v, want nil", tt.id, err)
+				}
+				return
+			}
+			if !errors.Is(err, helper.ErrInvalidDepartmentId) {
+				t.Fatalf("ValidateDepartmentID(%q) error = %v, want %v", tt.id, err, helper.ErrInvalidDepartmentId)
+			}
+		})
+	}
+}
@@ -2,60 +2,140 @@ package validation
 
 import (
 	"errors"
+	"slices"
 	"strings"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/levensspel/go-gin-template/dto"
 )
 
+// ValidateEmployeeCreate menormalkan gender ke huruf kecil lalu memvalidasi
+// input dengan tag di dto.EmployeePayload. Jika
+// FILE_RESTRICT_EMPLOYEE_IMAGES aktif, employeeImageUri juga harus uri file
+// hasil upload.
 func ValidateEmployeeCreate(input *dto.EmployeePayload) error {
-	gender := strings.ToLower(input.Gender)
-	// fmt.Println("gender:", strings.ToLower(gender))
-	switch gender {
-	case "":
-		input.Gender = ""
-	case dto.GenderMale:
-		input.Gender = dto.GenderMale
-		break
-	case dto.GenderFemale:
-		input.Gender = dto.GenderFemale
-		break
-	default:
-		return errors.New("Invalid gender param")
-	}
-
-	err := validate.Struct(input)
+	input.Gender = strings.ToLower(input.Gender)
+	if err := Default().Struct(input); err != nil {
+		return err
+	}
+	return validateEmployeeImage(input.EmployeeImageUri)
+}
+
+// ValidateEmployeePatch hanya memvalidasi field yang dikirim. Field yang
+// tidak dikirim tidak diubah, sedangkan string kosong divalidasi seperti
+// nilai lain dan ditolak rule masing-masing field. null hanya diterima untuk
+// employeeImageUri, yang berarti menghapus gambar, dan hiredAt, yang berarti
+// menghapus tanggal; field lain wajib diisi sehingga null ditolak. Payload
+// tanpa field yang dikirim juga ditolak.
+func ValidateEmployeePatch(input *dto.EmployeeUpdatePayload) error {
+	fieldErrors := FieldErrors{}
+	present := 0
+	for field, value := range map[string]*string{
+		"identityNumber":   input.IdentityNumber,
+		"name":             input.Name,
+		"employeeImageUri": input.EmployeeImageUri,
+		"gender":           input.Gender,
+		"departmentId":     input.DepartmentID,
+		"hiredAt":          input.HiredAt,
+	} {
+		switch {
+		case value != nil:
+			present++
+		case !input.IsNull(field):
+		case slices.Contains(nullableEmployeeFields, field):
+			present++
+		default:
+			fieldErrors[field] = field + " must not be null"
+		}
+	}
+	if present == 0 && len(fieldErrors) == 0 {
+		return errors.New("No field to update")
+	}
+
+	if input.Gender != nil {
+		gender := strings.ToLower(*input.Gender)
+		input.Gender = &gender
+	}
+
+	if err := mergeFieldErrors(fieldErrors, Default().Struct(input)); err != nil {
+		return err
+	}
+	if input.EmployeeImageUri != nil {
+		return validateEmployeeImage(*input.EmployeeImageUri)
+	}
+	return nil
+}
+
+// nullableEmployeeFields adalah field PATCH employee yang boleh dikirim null.
+var nullableEmployeeFields = []string{"employeeImageUri", "hiredAt"}
+
+// mergeFieldErrors menggabungkan hasil Default().Struct ke fieldErrors, dengan
+// pesan yang sudah ada di fieldErrors didahulukan. Error selain FieldErrors
+// dikembalikan apa adanya.
+func mergeFieldErrors(fieldErrors FieldErrors, err error) error {
 	if err != nil {
-		validationErrors := err.(validator.ValidationErrors)
-		for _, fieldError := range validationErrors {
-			return fieldError
+		var structErrors FieldErrors
+		if !errors.As(err, &structErrors) {
+			return err
 		}
+		for field, message := range structErrors {
+			if _, ok := fieldErrors[field]; !ok {
+				fieldErrors[field] = message
+			}
+		}
+	}
+	if len(fieldErrors) > 0 {
+		return fieldErrors
 	}
 	return nil
 }
 
+// ValidateEmployeeGet menormalkan filter sesuai cara repository
+// membandingkannya: gender adalah enum huruf kecil, dan q diabaikan spasi di
+// awal dan akhirnya. name (ILIKE, trigram, dan full-text) dan identityNumber
+// (prefix pada kolom huruf kecil) sudah case-insensitive di query, sehingga
+// dikirim apa adanya. departmentId boleh uuid dengan huruf besar maupun
+// kecil dan dibandingkan persis, sehingga id yang hurufnya berbeda dari id
+// department tidak menemukan employee apa pun.
+//
+// limit harus 1 atau lebih dan offset tidak boleh negatif; nilai default
+// untuk parameter yang tidak dikirim diisi pemanggil. Filter yang dikirim
+// kosong (lihat EmptyFilters) ditolak untuk filter dengan nilai tetap, yaitu
+// strictEmployeeFilters, dan berarti tanpa filter untuk pencarian teks.
+// Tanpa status hanya employee aktif yang diambil. hiredTo tidak boleh
+// sebelum hiredFrom.
 func ValidateEmployeeGet(input *dto.GetEmployeesRequest) error {
-	gender := strings.ToLower(input.Gender)
-	// fmt.Println("gender:", strings.ToLower(gender))
-	switch gender {
-	case "":
-		input.Gender = ""
-	case dto.GenderMale:
-		input.Gender = dto.GenderMale
-		break
-	case dto.GenderFemale:
-		input.Gender = dto.GenderFemale
-		break
-	default:
-		return errors.New("Invalid gender param")
-	}
-
-	err := validate.Struct(input)
-	if err != nil {
-		validationErrors := err.(validator.ValidationErrors)
-		for _, fieldError := range validationErrors {
-			return fieldError
+	input.Gender = strings.ToLower(input.Gender)
+	input.Query = strings.TrimSpace(input.Query)
+	if input.Status == "" {
+		input.Status = dto.EmployeeStatusActive
+	}
+
+	fieldErrors := FieldErrors{}
+	for _, filter := range input.EmptyFilters {
+		if slices.Contains(strictEmployeeFilters, filter) {
+			fieldErrors[filter] = filter + " must not be empty"
 		}
 	}
+	// identityNumber adalah prefix, sehingga gabungannya dengan daftar
+	// identityNumbers yang persis tidak jelas maksudnya
+	if len(input.IdentityNumbers) > 0 && input.IdentityNumber != "" {
+		fieldErrors["identityNumbers"] = "identityNumbers cannot be combined with identityNumber"
+	}
+	if err := mergeFieldErrors(fieldErrors, Default().Struct(input)); err != nil {
+		return err
+	}
+	// Dengan format yang sama, urutan string sama dengan urutan tanggal
+	if input.HiredFrom != "" && input.HiredTo != "" && input.HiredTo < input.HiredFrom {
+		return FieldErrors{"hiredTo": "hiredTo must not be before hiredFrom"}
+	}
 	return nil
 }
+
+// strictEmployeeFilters adalah filter GET /v1/employee yang tidak boleh
+// dikirim kosong.
+var strictEmployeeFilters = []string{"gender", "departmentId", "sortBy", "expand", "status", "hiredFrom", "hiredTo", "identityNumbers"}
+
+// ValidateEmployeeHistoryGet memvalidasi limit (1 sampai 100) dan offset.
+func ValidateEmployeeHistoryGet(input *dto.GetEmployeeHistoryRequest) error {
+	return Default().Struct(input)
+}
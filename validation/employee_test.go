@@ -0,0 +1,330 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/levensspel/go-gin-template/dto"
+)
+
+func TestValidateEmployeeGetDepartmentID(t *testing.T) {
+	tests := []struct {
+		name         string
+		departmentID string
+		wantErr      bool
+	}{
+		{name: "empty", departmentID: ""},
+		{name: "lowercase", departmentID: "0b7c2d2e-4f4a-4b8e-9c59-2f1d8f6a3e10"},
+		{name: "uppercase", departmentID: "0B7C2D2E-4F4A-4B8E-9C59-2F1D8F6A3E10"},
+		{name: "mixed case", departmentID: "0b7C2d2E-4f4A-4b8E-9c59-2F1d8F6a3E10"},
+		{name: "not a uuid", departmentID: "engineering", wantErr: true},
+		{name: "braces", departmentID: "{0b7c2d2e-4f4a-4b8e-9c59-2f1d8f6a3e10}", wantErr: true},
+		{name: "without dashes", departmentID: "0b7c2d2e4f4a4b8e9c592f1d8f6a3e10", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := dto.GetEmployeesRequest{Limit: 5, DepartmentID: tt.departmentID}
+			err := ValidateEmployeeGet(&input)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("ValidateEmployeeGet() error = %v, want nil", err)
+				}
+				// Dibandingkan persis di SQL, sehingga huruf tidak boleh diubah
+				if input.DepartmentID != tt.departmentID {
+					t.Fatalf("DepartmentID = %q, want %q unchanged", input.DepartmentID, tt.departmentID)
+				}
+				return
+			}
+			var fieldErrors FieldErrors
+			if !errors.As(err, &fieldErrors) {
+				t.Fatalf("ValidateEmployeeGet() error = %v, want FieldErrors", err)
+			}
+			if got, want := fieldErrors["departmentId"], "departmentId must be a valid UUID"; got != want {
+				t.Fatalf("departmentId error = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestValidateEmployeeGetNormalizesFilters(t *testing.T) {
+	input := dto.GetEmployeesRequest{
+		Limit:          5,
+		Gender:         "FeMale",
+		Query:          "  budi  ",
+		Name:           "Budi",
+		IdentityNumber: "AB-12",
+	}
+	if err := ValidateEmployeeGet(&input); err != nil {
+		t.Fatalf("ValidateEmployeeGet() error = %v", err)
+	}
+	if input.Gender != "female" {
+		t.Errorf("Gender = %q, want female", input.Gender)
+	}
+	if input.Query != "budi" {
+		t.Errorf("Query = %q, want budi", input.Query)
+	}
+	if input.Name != "Budi" || input.IdentityNumber != "AB-12" {
+		t.Errorf("Name, IdentityNumber = %q, %q, want them unchanged", input.Name, input.IdentityNumber)
+	}
+	if input.Status != dto.EmployeeStatusActive {
+		t.Errorf("Status = %q, want %q", input.Status, dto.EmployeeStatusActive)
+	}
+}
+
+func TestValidateEmployeeGetLimitsAndEmptyFilters(t *testing.T) {
+	tests := []struct {
+		name  string
+		input dto.GetEmployeesRequest
+		// wantFields adalah field yang harus ditolak; kosong berarti valid
+		wantFields []string
+	}{
+		{name: "defaults", input: dto.GetEmployeesRequest{Limit: dto.DefaultLimit, Offset: dto.DefaultOffset}},
+		{name: "limit 1", input: dto.GetEmployeesRequest{Limit: 1}},
+		{name: "limit 0", input: dto.GetEmployeesRequest{Limit: 0}, wantFields: []string{"limit"}},
+		{name: "negative limit", input: dto.GetEmployeesRequest{Limit: -1}, wantFields: []string{"limit"}},
+		{name: "negative offset", input: dto.GetEmployeesRequest{Limit: 5, Offset: -1}, wantFields: []string{"offset"}},
+		{name: "limit 0 and negative offset", input: dto.GetEmployeesRequest{Limit: 0, Offset: -5}, wantFields: []string{"limit", "offset"}},
+		{name: "empty gender", input: dto.GetEmployeesRequest{Limit: 5, EmptyFilters: []string{"gender"}}, wantFields: []string{"gender"}},
+		{name: "empty departmentId", input: dto.GetEmployeesRequest{Limit: 5, EmptyFilters: []string{"departmentId"}}, wantFields: []string{"departmentId"}},
+		{
+			name:       "every strict filter empty",
+			input:      dto.GetEmployeesRequest{Limit: 5, EmptyFilters: strictEmployeeFilters},
+			wantFields: strictEmployeeFilters,
+		},
+		// Pencarian teks kosong berarti tanpa filter
+		{name: "empty text search", input: dto.GetEmployeesRequest{Limit: 5, EmptyFilters: []string{"name", "identityNumber", "q"}}},
+		{
+			name:       "empty text search with empty gender",
+			input:      dto.GetEmployeesRequest{Limit: 5, EmptyFilters: []string{"name", "gender"}},
+			wantFields: []string{"gender"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateEmployeeGet(&tt.input)
+			if len(tt.wantFields) == 0 {
+				if err != nil {
+					t.Fatalf("ValidateEmployeeGet() error = %v, want nil", err)
+				}
+				return
+			}
+			var fieldErrors FieldErrors
+			if !errors.As(err, &fieldErrors) {
+				t.Fatalf("ValidateEmployeeGet() error = %v, want FieldErrors", err)
+			}
+			if len(fieldErrors) != len(tt.wantFields) {
+				t.Fatalf("field errors = %v, want %v", fieldErrors, tt.wantFields)
+			}
+			for _, field := range tt.wantFields {
+				if fieldErrors[field] == "" {
+					t.Fatalf("field errors = %v, want %s", fieldErrors, field)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateEmployeeGetIdentityNumbers(t *testing.T) {
+	hundred := make([]string, 100)
+	for i := range hundred {
+		hundred[i] = fmt.Sprintf("EMP-%05d", i)
+	}
+	tests := []struct {
+		name  string
+		input dto.GetEmployeesRequest
+		// wantField adalah field yang harus ditolak; kosong berarti valid
+		wantField string
+	}{
+		{name: "one", input: dto.GetEmployeesRequest{IdentityNumbers: []string{"EMP-00001"}}},
+		{name: "100 values", input: dto.GetEmployeesRequest{IdentityNumbers: hundred}},
+		{name: "101 values", input: dto.GetEmployeesRequest{IdentityNumbers: append(slices.Clone(hundred), "EMP-00100")}, wantField: "identityNumbers"},
+		{name: "too short", input: dto.GetEmployeesRequest{IdentityNumbers: []string{"EMP-00001", "E-1"}}, wantField: "identityNumbers[1]"},
+		{name: "too long", input: dto.GetEmployeesRequest{IdentityNumbers: []string{strings.Repeat("A", 34)}}, wantField: "identityNumbers[0]"},
+		{name: "invalid characters", input: dto.GetEmployeesRequest{IdentityNumbers: []string{"EMP_00001"}}, wantField: "identityNumbers[0]"},
+		{name: "empty value", input: dto.GetEmployeesRequest{IdentityNumbers: []string{"EMP-00001", ""}}, wantField: "identityNumbers[1]"},
+		{
+			name:      "with identityNumber prefix",
+			input:     dto.GetEmployeesRequest{IdentityNumber: "EMP", IdentityNumbers: []string{"EMP-00001"}},
+			wantField: "identityNumbers",
+		},
+		{name: "sent empty", input: dto.GetEmployeesRequest{EmptyFilters: []string{"identityNumbers"}}, wantField: "identityNumbers"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.input.Limit = dto.DefaultLimit
+			err := ValidateEmployeeGet(&tt.input)
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("ValidateEmployeeGet() error = %v, want nil", err)
+				}
+				return
+			}
+			var fieldErrors FieldErrors
+			if !errors.As(err, &fieldErrors) || len(fieldErrors) != 1 || fieldErrors[tt.wantField] == "" {
+				t.Fatalf("ValidateEmployeeGet() error = %v, want only %s", err, tt.wantField)
+			}
+		})
+	}
+}
+
+// TestValidateEmployeePatch menguji setiap field PATCH dalam keadaan tidak
+// dikirim, string kosong, null, valid, dan tidak valid. Body di-decode
+// seperti di handler agar null tercatat di Nulls.
+func TestValidateEmployeePatch(t *testing.T) {
+	type state struct {
+		value string
+		// wantErr berarti field harus punya field error
+		wantErr bool
+	}
+	fields := []struct {
+		name    string
+		empty   state
+		null    state
+		valid   []string
+		invalid []string
+	}{
+		{
+			name:    "identityNumber",
+			empty:   state{value: `""`, wantErr: true},
+			null:    state{wantErr: true},
+			valid:   []string{`"EMP-00001"`},
+			invalid: []string{`"AB"`, `"` + strings.Repeat("A", 34) + `"`},
+		},
+		{
+			name:    "name",
+			empty:   state{value: `""`, wantErr: true},
+			null:    state{wantErr: true},
+			valid:   []string{`"Budi Santoso"`},
+			invalid: []string{`"Bud"`, `"` + strings.Repeat("a", 34) + `"`},
+		},
+		{
+			name:  "employeeImageUri",
+			empty: state{value: `""`, wantErr: true},
+			// null menghapus gambar
+			null:    state{},
+			valid:   []string{`"https://example.com/budi.png"`},
+			invalid: []string{`"not a url"`},
+		},
+		{
+			name:    "gender",
+			empty:   state{value: `""`, wantErr: true},
+			null:    state{wantErr: true},
+			valid:   []string{`"male"`, `"FEMALE"`},
+			invalid: []string{`"other"`},
+		},
+		{
+			name:    "departmentId",
+			empty:   state{value: `""`, wantErr: true},
+			null:    state{wantErr: true},
+			valid:   []string{`"0b7c2d2e-4f4a-4b8e-9c59-2f1d8f6a3e10"`},
+			invalid: []string{`"engineering"`},
+		},
+		{
+			name:  "hiredAt",
+			empty: state{value: `""`, wantErr: true},
+			// null menghapus tanggal
+			null:    state{},
+			valid:   []string{`"2024-03-01"`},
+			invalid: []string{`"2024-13-01"`, `"01-03-2024"`, `"2999-01-01"`},
+		},
+	}
+
+	type patchCase struct {
+		name    string
+		body    string
+		field   string
+		wantErr bool
+	}
+	var cases []patchCase
+	for _, f := range fields {
+		// Field lain yang valid memastikan hanya field ini yang diuji
+		other := `"name": "Budi Santoso"`
+		if f.name == "name" {
+			other = `"gender": "male"`
+		}
+		add := func(state, value string, wantErr bool) {
+			cases = append(cases, patchCase{
+				name:    f.name + "/" + state,
+				body:    `{` + other + `, "` + f.name + `": ` + value + `}`,
+				field:   f.name,
+				wantErr: wantErr,
+			})
+		}
+		cases = append(cases, patchCase{name: f.name + "/absent", body: `{` + other + `}`, field: f.name})
+		add("empty", f.empty.value, f.empty.wantErr)
+		add("null", "null", f.null.wantErr)
+		for _, value := range f.valid {
+			add("valid "+value, value, false)
+		}
+		for _, value := range f.invalid {
+			add("invalid "+value, value, true)
+		}
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var input dto.EmployeeUpdatePayload
+			if err := json.Unmarshal([]byte(tt.body), &input); err != nil {
+				t.Fatalf("decode %s: %v", tt.body, err)
+			}
+			err := ValidateEmployeePatch(&input)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("ValidateEmployeePatch(%s) error = %v, want nil", tt.body, err)
+				}
+				return
+			}
+			var fieldErrors FieldErrors
+			if !errors.As(err, &fieldErrors) {
+				t.Fatalf("ValidateEmployeePatch(%s) error = %v, want FieldErrors", tt.body, err)
+			}
+			if len(fieldErrors) != 1 || fieldErrors[tt.field] == "" {
+				t.Fatalf("field errors = %v, want only %s", fieldErrors, tt.field)
+			}
+		})
+	}
+}
+
+func TestValidateEmployeePatchNormalizes(t *testing.T) {
+	var input dto.EmployeeUpdatePayload
+	if err := json.Unmarshal([]byte(`{"gender": "FeMale", "employeeImageUri": null, "hiredAt": null}`), &input); err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateEmployeePatch(&input); err != nil {
+		t.Fatalf("ValidateEmployeePatch() error = %v", err)
+	}
+	if *input.Gender != "female" {
+		t.Errorf("Gender = %q, want female", *input.Gender)
+	}
+	if !slices.Equal(input.Nulls, []string{"employeeImageUri", "hiredAt"}) || input.EmployeeImageUri != nil || input.HiredAt != nil {
+		t.Errorf("input = %+v, want employeeImageUri and hiredAt in Nulls", input)
+	}
+}
+
+func TestValidateEmployeePatchRequiresAField(t *testing.T) {
+	for _, body := range []string{`{}`, `{"unknown": "value"}`} {
+		var input dto.EmployeeUpdatePayload
+		if err := json.Unmarshal([]byte(body), &input); err != nil {
+			t.Fatal(err)
+		}
+		err := ValidateEmployeePatch(&input)
+		var fieldErrors FieldErrors
+		if err == nil || errors.As(err, &fieldErrors) {
+			t.Fatalf("ValidateEmployeePatch(%s) error = %v, want No field to update", body, err)
+		}
+	}
+
+	// Null yang ditolak dilaporkan sebagai field error, bukan payload kosong
+	var input dto.EmployeeUpdatePayload
+	if err := json.Unmarshal([]byte(`{"name": null, "gender": null}`), &input); err != nil {
+		t.Fatal(err)
+	}
+	var fieldErrors FieldErrors
+	if err := ValidateEmployeePatch(&input); !errors.As(err, &fieldErrors) || fieldErrors["name"] == "" || fieldErrors["gender"] == "" {
+		t.Fatalf("ValidateEmployeePatch() error = %v, want name and gender field errors", err)
+	}
+}
@@ -0,0 +1,56 @@
+package validation
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/dto"
+)
+
+// fileIdPattern adalah format fileId, lihat idgen.IDGenerator.
+const fileIdPattern = `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`
+
+var (
+	// fileRoutePattern mencocokkan path GET /files/:id setelah FILE_BASE_URL
+	fileRoutePattern = regexp.MustCompile(`^/files/` + fileIdPattern + `$`)
+	// fileKeyPattern mencocokkan akhir URL storage dengan key file hasil
+	// upload, file/<managerId>/<fileId>.<ext>
+	fileKeyPattern = regexp.MustCompile(`/file/[^/]+/` + fileIdPattern + `\.(jpg|png)$`)
+)
+
+func ValidateFilePresign(input *dto.FilePresignRequest) error {
+	return Default().Struct(input)
+}
+
+func ValidateFilesGet(input *dto.GetFilesRequest) error {
+	return Default().Struct(input)
+}
+
+// validateEmployeeImage memeriksa bentuk employeeImageUri jika
+// FILE_RESTRICT_EMPLOYEE_IMAGES aktif. Keberadaan dan pemilik filenya
+// diperiksa service, karena butuh database.
+func validateEmployeeImage(uri string) error {
+	fileConfig := config.LoadFileConfig()
+	if !fileConfig.RestrictEmployeeImages || isFileURI(uri, fileConfig.BaseURL) {
+		return nil
+	}
+	return FieldErrors{"employeeImageUri": "employeeImageUri must be the uri of a file uploaded through POST /v1/file"}
+}
+
+// isFileURI melaporkan apakah uri berbentuk uri file yang dibuat
+// fileService: <baseURL>/files/<fileId>, atau URL storage yang diakhiri key
+// file.
+func isFileURI(uri string, baseURL string) bool {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.RawQuery != "" || parsed.Fragment != "" {
+		return false
+	}
+	if baseURL != "" {
+		if rest, ok := strings.CutPrefix(uri, strings.TrimSuffix(baseURL, "/")); ok && fileRoutePattern.MatchString(rest) {
+			return true
+		}
+	}
+	return fileKeyPattern.MatchString(parsed.Path)
+}
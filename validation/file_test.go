@@ -0,0 +1,82 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/levensspel/go-gin-template/dto"
+)
+
+const testFileID = "0190a8f2-7c1e-7d3a-9b4f-0a1b2c3d4e5f"
+
+func TestValidateEmployeeImage(t *testing.T) {
+	tests := []struct {
+		name string
+		uri  string
+		// wantRestricted adalah hasil saat FILE_RESTRICT_EMPLOYEE_IMAGES aktif;
+		// tanpa flag, semua uri di sini diterima
+		wantRestricted bool
+	}{
+		{name: "file route", uri: "https://api.example.com/files/" + testFileID, wantRestricted: true},
+		{name: "storage url", uri: "https://bucket.s3.amazonaws.com/file/manager-1/" + testFileID + ".png", wantRestricted: true},
+		{name: "external url", uri: "https://example.com/budi.png"},
+		{name: "other host file route", uri: "https://evil.example.com/files/" + testFileID},
+		{name: "file route with query", uri: "https://api.example.com/files/" + testFileID + "?x=1"},
+		{name: "file route with suffix", uri: "https://api.example.com/files/" + testFileID + "/thumbnail"},
+		{name: "storage url other extension", uri: "https://bucket.s3.amazonaws.com/file/manager-1/" + testFileID + ".gif"},
+	}
+	for _, restricted := range []bool{false, true} {
+		for _, tt := range tests {
+			name := tt.name
+			if restricted {
+				name = "restricted " + name
+			}
+			t.Run(name, func(t *testing.T) {
+				t.Setenv("FILE_BASE_URL", "https://api.example.com")
+				if restricted {
+					t.Setenv("FILE_RESTRICT_EMPLOYEE_IMAGES", "true")
+				} else {
+					t.Setenv("FILE_RESTRICT_EMPLOYEE_IMAGES", "false")
+				}
+				wantErr := restricted && !tt.wantRestricted
+
+				input := dto.EmployeePayload{
+					IdentityNumber:   "EMP-12345",
+					Name:             "Budi",
+					EmployeeImageUri: tt.uri,
+					Gender:           "male",
+					DepartmentID:     "1c8d3e3f-5a5b-4c9f-8d6a-3a2e9a7b4f21",
+				}
+				checkEmployeeImageError(t, "ValidateEmployeeCreate", ValidateEmployeeCreate(&input), wantErr)
+
+				uri := tt.uri
+				patch := dto.EmployeeUpdatePayload{EmployeeImageUri: &uri}
+				checkEmployeeImageError(t, "ValidateEmployeePatch", ValidateEmployeePatch(&patch), wantErr)
+			})
+		}
+	}
+}
+
+// TestValidateEmployeePatchRemovesRestrictedImage memastikan null, yang
+// menghapus gambar, tetap diterima walau gambar dibatasi.
+func TestValidateEmployeePatchRemovesRestrictedImage(t *testing.T) {
+	t.Setenv("FILE_RESTRICT_EMPLOYEE_IMAGES", "true")
+	patch := dto.EmployeeUpdatePayload{Nulls: []string{"employeeImageUri"}}
+	if err := ValidateEmployeePatch(&patch); err != nil {
+		t.Fatalf("ValidateEmployeePatch() error = %v, want nil", err)
+	}
+}
+
+func checkEmployeeImageError(t *testing.T, name string, err error, wantErr bool) {
+	t.Helper()
+	if !wantErr {
+		if err != nil {
+			t.Errorf("%s() error = %v, want nil", name, err)
+		}
+		return
+	}
+	var fieldErrors FieldErrors
+	if !errors.As(err, &fieldErrors) || fieldErrors["employeeImageUri"] == "" {
+		t.Errorf("%s() error = %v, want an employeeImageUri error", name, err)
+	}
+}
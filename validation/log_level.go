@@ -0,0 +1,50 @@
+package validation
+
+import (
+	"time"
+
+	"github.com/levensspel/go-gin-template/dto"
+)
+
+// LogLevelChange adalah durasi dari dto.LogLevelRequest yang sudah diparse.
+// Durasi 0 berarti field tidak dikirim.
+type LogLevelChange struct {
+	RevertAfter          time.Duration
+	SlowRequestThreshold time.Duration
+	SlowQueryThreshold   time.Duration
+}
+
+// ValidateLogLevel memeriksa level dan mem-parse semua durasi input. Setiap
+// durasi yang dikirim harus positif.
+func ValidateLogLevel(input *dto.LogLevelRequest) (LogLevelChange, error) {
+	if err := Default().Struct(input); err != nil {
+		return LogLevelChange{}, err
+	}
+
+	var change LogLevelChange
+	fieldErrors := FieldErrors{}
+	for _, field := range []struct {
+		name  string
+		value string
+		dest  *time.Duration
+		hint  string
+	}{
+		{name: "revertAfter", value: input.RevertAfter, dest: &change.RevertAfter, hint: "15m"},
+		{name: "slowRequestThreshold", value: input.SlowRequestThreshold, dest: &change.SlowRequestThreshold, hint: "2s"},
+		{name: "slowQueryThreshold", value: input.SlowQueryThreshold, dest: &change.SlowQueryThreshold, hint: "500ms"},
+	} {
+		if field.value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(field.value)
+		if err != nil || d <= 0 {
+			fieldErrors[field.name] = field.name + " must be a positive duration such as " + field.hint
+			continue
+		}
+		*field.dest = d
+	}
+	if len(fieldErrors) > 0 {
+		return LogLevelChange{}, fieldErrors
+	}
+	return change, nil
+}
@@ -0,0 +1,44 @@
+package validation
+
+import (
+	"net/url"
+	"slices"
+)
+
+// QueryParams adalah parameter query yang diterima satu endpoint. Scalars
+// hanya boleh dikirim sekali, sedangkan Lists boleh diulang dan semua
+// nilainya dipakai pemanggil.
+type QueryParams struct {
+	Scalars []string
+	Lists   []string
+}
+
+// With mengembalikan salinan params dengan parameter scalar tambahan, mis.
+// format untuk export yang memakai filter daftar.
+func (p QueryParams) With(scalars ...string) QueryParams {
+	return QueryParams{
+		Scalars: append(slices.Clone(p.Scalars), scalars...),
+		Lists:   p.Lists,
+	}
+}
+
+// ValidateQuery menolak parameter yang tidak ada di params, sehingga typo
+// seperti departmenId= tidak diam-diam diabaikan, dan parameter scalar yang
+// dikirim lebih dari sekali, karena tidak jelas nilai mana yang dipakai.
+// Setiap parameter yang ditolak dilaporkan dengan pesannya sendiri.
+func ValidateQuery(query url.Values, params QueryParams) error {
+	fieldErrors := FieldErrors{}
+	for name, values := range query {
+		switch {
+		case slices.Contains(params.Lists, name):
+		case !slices.Contains(params.Scalars, name):
+			fieldErrors[name] = name + " is not a supported query parameter"
+		case len(values) > 1:
+			fieldErrors[name] = name + " must not be repeated"
+		}
+	}
+	if len(fieldErrors) > 0 {
+		return fieldErrors
+	}
+	return nil
+}
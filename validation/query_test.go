@@ -0,0 +1,80 @@
+package validation
+
+import (
+	"net/url"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestValidateQuery(t *testing.T) {
+	params := QueryParams{Scalars: []string{"limit", "offset", "departmentId"}, Lists: []string{"identityNumbers"}}
+	tests := []struct {
+		name  string
+		query string
+		want  FieldErrors
+	}{
+		{name: "empty", query: ""},
+		{name: "known scalars", query: "limit=5&offset=0&departmentId="},
+		{name: "repeated list", query: "identityNumbers=EMP-1&identityNumbers=EMP-2,EMP-3"},
+		{name: "typo", query: "departmenId=abc", want: FieldErrors{
+			"departmenId": "departmenId is not a supported query parameter",
+		}},
+		// Nama parameter peka huruf besar-kecil
+		{name: "wrong case", query: "departmentID=abc", want: FieldErrors{
+			"departmentID": "departmentID is not a supported query parameter",
+		}},
+		{name: "duplicate scalar", query: "limit=5&limit=10", want: FieldErrors{
+			"limit": "limit must not be repeated",
+		}},
+		{name: "duplicate scalar with the same value", query: "limit=5&limit=5", want: FieldErrors{
+			"limit": "limit must not be repeated",
+		}},
+		{name: "repeated unknown parameter", query: "cache=1&cache=2", want: FieldErrors{
+			"cache": "cache is not a supported query parameter",
+		}},
+		{name: "mixed valid and invalid", query: "limit=5&offset=1&offset=2&departmenId=abc&identityNumbers=EMP-1&identityNumbers=EMP-2&q=budi", want: FieldErrors{
+			"offset":      "offset must not be repeated",
+			"departmenId": "departmenId is not a supported query parameter",
+			"q":           "q is not a supported query parameter",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = ValidateQuery(query, params)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("ValidateQuery() error = %v, want nil", err)
+				}
+				return
+			}
+			if got := fieldErrors(t, err); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ValidateQuery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryParamsWith(t *testing.T) {
+	params := QueryParams{Scalars: make([]string, 1, 4), Lists: []string{"identityNumbers"}}
+	params.Scalars[0] = "limit"
+	export := params.With("format")
+
+	if !slices.Equal(export.Scalars, []string{"limit", "format"}) || !slices.Equal(export.Lists, params.Lists) {
+		t.Fatalf("With = %+v", export)
+	}
+	// Kapasitas sisa tidak boleh membuat params ikut berubah
+	if !slices.Equal(params.Scalars, []string{"limit"}) || !slices.Equal(params.Scalars[:2], []string{"limit", ""}) {
+		t.Fatalf("params changed to %+v", params)
+	}
+	if err := ValidateQuery(url.Values{"format": {"csv"}}, params); err == nil {
+		t.Fatal("ValidateQuery accepted format without With")
+	}
+	if err := ValidateQuery(url.Values{"format": {"csv"}}, export); err != nil {
+		t.Fatalf("ValidateQuery with format error = %v", err)
+	}
+}
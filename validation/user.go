@@ -1,20 +1,14 @@
 package validation
 
 import (
-	"github.com/go-playground/validator/v10"
 	"github.com/levensspel/go-gin-template/dto"
 	repository "github.com/levensspel/go-gin-template/repository/user"
 )
 
-var validate = validator.New()
-
-func ValidateUserCreate(input dto.UserRequestPayload, r repository.UserRepository) error {
-	err := validate.Struct(input)
+func ValidateUserCreate(input dto.UserRequestPayload, r repository.UserRepositoryInterface) error {
+	err := Default().Struct(input)
 	if err != nil {
-		validationErrors := err.(validator.ValidationErrors)
-		for _, fieldError := range validationErrors {
-			return fieldError
-		}
+		return err
 	}
 	// (!) kedua dibawah ini transaksi db
 	// err = ValidateIsUsernameExist(input.Username, r)
@@ -29,24 +23,9 @@ func ValidateUserCreate(input dto.UserRequestPayload, r repository.UserRepositor
 }
 
 func ValidateUserLogin(input dto.UserRequestPayload) error {
-	err := validate.Struct(input)
-	if err != nil {
-		validationErrors := err.(validator.ValidationErrors)
-		for _, fieldError := range validationErrors {
-			return fieldError
-		}
-	}
-
-	return nil
+	return Default().Struct(input)
 }
 
 func ValidateUpdateProfile(input dto.RequestUpdateProfile) error {
-	err := validate.Struct(input)
-	if err != nil {
-		validationErrors := err.(validator.ValidationErrors)
-		for _, fieldError := range validationErrors {
-			return fieldError
-		}
-	}
-	return nil
+	return Default().Struct(input)
 }
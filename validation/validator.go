@@ -0,0 +1,193 @@
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-playground/locales/en"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	"github.com/levensspel/go-gin-template/clock"
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/samber/do/v2"
+)
+
+// identityNumberPattern adalah format identityNumber: huruf, angka, dan
+// tanda hubung, panjangnya dibatasi tag min/max di DTO.
+var identityNumberPattern = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+
+// anyCaseUUIDPattern adalah format uuid seperti tag uuid, tetapi menerima
+// huruf besar maupun kecil.
+var anyCaseUUIDPattern = regexp.MustCompile(`^(?i)[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// FieldErrors adalah hasil validasi per field, dengan key nama field JSON
+// atau query yang dikirim client dan value pesan yang sudah diterjemahkan.
+type FieldErrors map[string]string
+
+// FieldErrors dipakai helper.FromError untuk membuat response 400 dengan
+// pesan per field.
+func (e FieldErrors) FieldErrors() map[string]string {
+	return e
+}
+
+func (e FieldErrors) Error() string {
+	fields := make([]string, 0, len(e))
+	for field := range e {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	messages := make([]string, len(fields))
+	for i, field := range fields {
+		messages[i] = e[field]
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Validator membungkus validator.Validate dengan tag custom dan penerjemah
+// pesan error. Satu instance dipakai bersama, lihat NewInject.
+type Validator struct {
+	validate *validator.Validate
+	trans    ut.Translator
+	clock    clock.Clock
+}
+
+var (
+	defaultMu        sync.RWMutex
+	defaultValidator = New(clock.Real())
+)
+
+// SetDefault mengganti instance yang dipakai fungsi Validate* di package ini.
+func SetDefault(v *Validator) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultValidator = v
+}
+
+func Default() *Validator {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultValidator
+}
+
+// New membuat Validator dengan clock sebagai acuan "hari ini" untuk tag
+// notfuture.
+func New(clock clock.Clock) *Validator {
+	v := validator.New()
+	// Nama field di error mengikuti tag json/query, bukan nama field Go
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "query"} {
+			name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return field.Name
+	})
+
+	locale := en.New()
+	trans, _ := ut.New(locale, locale).GetTranslator("en")
+	if err := enTranslations.RegisterDefaultTranslations(v, trans); err != nil {
+		panic(err)
+	}
+
+	result := &Validator{validate: v, trans: trans, clock: clock}
+	result.register("identitynumber", "{0} may only contain letters, digits and dashes", func(fl validator.FieldLevel) bool {
+		return identityNumberPattern.MatchString(fl.Field().String())
+	})
+	result.register("uuidanycase", "{0} must be a valid UUID", func(fl validator.FieldLevel) bool {
+		return anyCaseUUIDPattern.MatchString(fl.Field().String())
+	})
+	result.register("employeesort", "{0} must be one of ["+strings.Join(dto.EmployeeSortFields, " ")+"]", func(fl validator.FieldLevel) bool {
+		return slices.Contains(dto.EmployeeSortFields, fl.Field().String())
+	})
+	result.register("notfuture", "{0} must not be in the future", func(fl validator.FieldLevel) bool {
+		return !isFutureDate(fl.Field().String(), result.clock.Now())
+	})
+	result.register("apikeyscope", "{0} must be one of ["+strings.Join(entity.APIKeyScopes, " ")+"]", func(fl validator.FieldLevel) bool {
+		return slices.Contains(entity.APIKeyScopes, fl.Field().String())
+	})
+	result.register("webhookevent", "{0} must be one of ["+strings.Join(entity.WebhookEvents, " ")+"]", func(fl validator.FieldLevel) bool {
+		return slices.Contains(entity.WebhookEvents, fl.Field().String())
+	})
+	return result
+}
+
+// isFutureDate bernilai true jika date (format dto.DateLayout) setelah
+// tanggal now. Format yang salah dilaporkan tag datetime.
+func isFutureDate(date string, now time.Time) bool {
+	parsed, err := time.Parse(dto.DateLayout, date)
+	if err != nil {
+		return false
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return parsed.After(today)
+}
+
+// NewInject membuat Validator dengan clock dari injector dan menjadikannya
+// default, sehingga fungsi Validate* dan pemakai *Validator memakai instance
+// yang sama.
+func NewInject(i do.Injector) (*Validator, error) {
+	v := New(do.MustInvoke[clock.Clock](i))
+	SetDefault(v)
+	return v, nil
+}
+
+func (v *Validator) register(tag, message string, fn validator.Func) {
+	if err := v.validate.RegisterValidation(tag, fn); err != nil {
+		panic(err)
+	}
+	err := v.validate.RegisterTranslation(
+		tag,
+		v.trans,
+		func(trans ut.Translator) error {
+			return trans.Add(tag, message, true)
+		},
+		func(trans ut.Translator, fe validator.FieldError) string {
+			message, _ := trans.T(tag, fe.Field())
+			return message
+		},
+	)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Struct memvalidasi input dan mengembalikan FieldErrors berisi semua field
+// yang gagal, bukan hanya yang pertama.
+func (v *Validator) Struct(input any) error {
+	return v.translate(v.validate.Struct(input))
+}
+
+// Var memvalidasi satu nilai dengan tag tertentu.
+func (v *Validator) Var(field any, tag string) error {
+	return v.validate.Var(field, tag)
+}
+
+func (v *Validator) translate(err error) error {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+
+	fieldErrors := make(FieldErrors, len(validationErrors))
+	for _, fieldError := range validationErrors {
+		field := fieldError.Field()
+		// Hanya rule pertama yang gagal per field yang dilaporkan
+		if _, ok := fieldErrors[field]; !ok {
+			fieldErrors[field] = fieldError.Translate(v.trans)
+		}
+	}
+	return fieldErrors
+}
@@ -0,0 +1,218 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/clock/clocktest"
+	"github.com/levensspel/go-gin-template/dto"
+)
+
+// testNow adalah "hari ini" untuk tag notfuture di test package ini, lihat
+// useFakeClock.
+var testNow = time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+// useFakeClock memasang Validator default dengan clock palsu di testNow
+// selama test berjalan.
+func useFakeClock(t *testing.T) *clocktest.Fake {
+	t.Helper()
+	fake := clocktest.NewFake(testNow)
+	previous := Default()
+	SetDefault(New(fake))
+	t.Cleanup(func() { SetDefault(previous) })
+	return fake
+}
+
+func validEmployee() dto.EmployeePayload {
+	return dto.EmployeePayload{
+		IdentityNumber:   "EMP-001",
+		Name:             "Budi Santoso",
+		EmployeeImageUri: "https://example.com/budi.png",
+		Gender:           "male",
+		DepartmentID:     "0b7c2d2e-4f4a-4b8e-9c59-2f1d8f6a3e10",
+	}
+}
+
+// fieldErrors memastikan err adalah FieldErrors dan mengembalikannya.
+func fieldErrors(t *testing.T, err error) FieldErrors {
+	t.Helper()
+	var result FieldErrors
+	if !errors.As(err, &result) {
+		t.Fatalf("error = %v (%T), want FieldErrors", err, err)
+	}
+	return result
+}
+
+func TestValidateEmployeeCreate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(p *dto.EmployeePayload)
+		want   FieldErrors
+	}{
+		{name: "valid", modify: func(p *dto.EmployeePayload) {}},
+		{name: "gender any case", modify: func(p *dto.EmployeePayload) { p.Gender = "FeMale" }},
+		{name: "missing identityNumber", modify: func(p *dto.EmployeePayload) { p.IdentityNumber = "" }, want: FieldErrors{
+			"identityNumber": "identityNumber is a required field",
+		}},
+		{name: "identityNumber format", modify: func(p *dto.EmployeePayload) { p.IdentityNumber = "EMP 001" }, want: FieldErrors{
+			"identityNumber": "identityNumber may only contain letters, digits and dashes",
+		}},
+		{name: "identityNumber too long", modify: func(p *dto.EmployeePayload) { p.IdentityNumber = strings.Repeat("A", 34) }, want: FieldErrors{
+			"identityNumber": "identityNumber must be a maximum of 33 characters in length",
+		}},
+		{name: "name too short", modify: func(p *dto.EmployeePayload) { p.Name = "Bud" }, want: FieldErrors{
+			"name": "name must be at least 4 characters in length",
+		}},
+		{name: "image not a url", modify: func(p *dto.EmployeePayload) { p.EmployeeImageUri = "budi.png" }, want: FieldErrors{
+			"employeeImageUri": "employeeImageUri must be a valid URL",
+		}},
+		{name: "unknown gender", modify: func(p *dto.EmployeePayload) { p.Gender = "other" }, want: FieldErrors{
+			"gender": "gender must be one of [male female]",
+		}},
+		{name: "hiredAt format", modify: func(p *dto.EmployeePayload) { hiredAt := "01-02-2024"; p.HiredAt = &hiredAt }, want: FieldErrors{
+			"hiredAt": "hiredAt does not match the 2006-01-02 format",
+		}},
+		{name: "hiredAt today", modify: func(p *dto.EmployeePayload) { hiredAt := "2026-03-01"; p.HiredAt = &hiredAt }},
+		{name: "hiredAt in the future", modify: func(p *dto.EmployeePayload) { hiredAt := "2026-03-02"; p.HiredAt = &hiredAt }, want: FieldErrors{
+			"hiredAt": "hiredAt must not be in the future",
+		}},
+		{
+			// Semua field yang gagal dilaporkan sekaligus
+			name: "several fields",
+			modify: func(p *dto.EmployeePayload) {
+				p.Name = ""
+				p.Gender = ""
+				p.DepartmentID = "engineering"
+			},
+			want: FieldErrors{
+				"name":         "name is a required field",
+				"gender":       "gender is a required field",
+				"departmentId": "departmentId must be a valid UUID",
+			},
+		},
+	}
+	useFakeClock(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := validEmployee()
+			tt.modify(&input)
+			err := ValidateEmployeeCreate(&input)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("ValidateEmployeeCreate() error = %v, want nil", err)
+				}
+				return
+			}
+			got := fieldErrors(t, err)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ValidateEmployeeCreate() = %v, want %v", got, tt.want)
+			}
+			for field, message := range tt.want {
+				if got[field] != message {
+					t.Errorf("%s = %q, want %q", field, got[field], message)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateEmployeeCreateLowercasesGender(t *testing.T) {
+	input := validEmployee()
+	input.Gender = "FEMALE"
+	if err := ValidateEmployeeCreate(&input); err != nil {
+		t.Fatal(err)
+	}
+	if input.Gender != "female" {
+		t.Fatalf("Gender = %q, want female", input.Gender)
+	}
+}
+
+func TestValidateEmployeeGetSortBy(t *testing.T) {
+	for _, sortBy := range dto.EmployeeSortFields {
+		input := dto.GetEmployeesRequest{Limit: 5, SortBy: sortBy}
+		if err := ValidateEmployeeGet(&input); err != nil {
+			t.Errorf("sortBy=%s error = %v", sortBy, err)
+		}
+	}
+
+	input := dto.GetEmployeesRequest{Limit: 5, SortBy: "password"}
+	got := fieldErrors(t, ValidateEmployeeGet(&input))
+	if want := "sortBy must be one of [identityNumber name createdAt hiredAt]"; got["sortBy"] != want {
+		t.Fatalf("sortBy = %q, want %q", got["sortBy"], want)
+	}
+}
+
+func TestValidateEmployeeGetLimitOffset(t *testing.T) {
+	input := dto.GetEmployeesRequest{Limit: 0, Offset: -1}
+	got := fieldErrors(t, ValidateEmployeeGet(&input))
+	if got["limit"] != "limit must be 1 or greater" || got["offset"] != "offset must be 0 or greater" {
+		t.Fatalf("ValidateEmployeeGet() = %v", got)
+	}
+}
+
+func TestValidateUserLogin(t *testing.T) {
+	tests := []struct {
+		name  string
+		input dto.UserRequestPayload
+		want  FieldErrors
+	}{
+		{name: "valid", input: dto.UserRequestPayload{Email: "budi@example.com", Password: "password", Action: dto.Login}},
+		{name: "invalid email", input: dto.UserRequestPayload{Email: "budi", Password: "password", Action: dto.Login}, want: FieldErrors{
+			"email": "email must be a valid email address",
+		}},
+		{name: "short password", input: dto.UserRequestPayload{Email: "budi@example.com", Password: "pass", Action: dto.Login}, want: FieldErrors{
+			"password": "password must be at least 8 characters in length",
+		}},
+		{name: "empty", input: dto.UserRequestPayload{}, want: FieldErrors{
+			"email":    "email is a required field",
+			"password": "password is a required field",
+			"action":   "action is a required field",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUserLogin(tt.input)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("ValidateUserLogin() error = %v, want nil", err)
+				}
+				return
+			}
+			got := fieldErrors(t, err)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ValidateUserLogin() = %v, want %v", got, tt.want)
+			}
+			for field, message := range tt.want {
+				if got[field] != message {
+					t.Errorf("%s = %q, want %q", field, got[field], message)
+				}
+			}
+		})
+	}
+}
+
+func TestFieldErrorsErrorIsSortedByField(t *testing.T) {
+	err := FieldErrors{"name": "name is a required field", "gender": "gender is a required field"}
+	if got, want := err.Error(), "gender is a required field; name is a required field"; got != want {
+		t.Fatalf("Error() = %q, want %q", got, want)
+	}
+}
+
+// TestNotFutureFollowsClock memastikan batas "hari ini" tag notfuture
+// mengikuti clock Validator, bukan jam sistem.
+func TestNotFutureFollowsClock(t *testing.T) {
+	fake := useFakeClock(t)
+	input := validEmployee()
+	hiredAt := "2026-03-02"
+	input.HiredAt = &hiredAt
+
+	if err := ValidateEmployeeCreate(&input); fieldErrors(t, err)["hiredAt"] != "hiredAt must not be in the future" {
+		t.Fatalf("ValidateEmployeeCreate() a day early error = %v, want hiredAt in the future", err)
+	}
+	fake.Advance(14 * time.Hour)
+	if err := ValidateEmployeeCreate(&input); err != nil {
+		t.Fatalf("ValidateEmployeeCreate() on the day error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,11 @@
+package validation
+
+import "github.com/levensspel/go-gin-template/dto"
+
+func ValidateWebhookCreate(input *dto.WebhookRequest) error {
+	return Default().Struct(input)
+}
+
+func ValidateWebhookDeliveriesGet(input *dto.GetWebhookDeliveriesRequest) error {
+	return Default().Struct(input)
+}
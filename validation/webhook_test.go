@@ -0,0 +1,44 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/levensspel/go-gin-template/dto"
+	"github.com/levensspel/go-gin-template/entity"
+)
+
+func TestValidateWebhookCreate(t *testing.T) {
+	eventsMessage := "events[0] must be one of [" + strings.Join(entity.WebhookEvents, " ") + "]"
+	tests := []struct {
+		name  string
+		input dto.WebhookRequest
+		want  FieldErrors
+	}{
+		{name: "all events", input: dto.WebhookRequest{Url: "https://example.com/hooks"}},
+		{name: "filtered events", input: dto.WebhookRequest{Url: "https://example.com/hooks", Events: []string{entity.EventEmployeeCreated, entity.EventDepartmentDeleted}}},
+		{name: "own secret", input: dto.WebhookRequest{Url: "https://example.com/hooks", Secret: strings.Repeat("s", 16)}},
+		{name: "missing url", input: dto.WebhookRequest{}, want: FieldErrors{"url": "url is a required field"}},
+		{name: "not a url", input: dto.WebhookRequest{Url: "example.com/hooks"}, want: FieldErrors{"url": "url must be a valid URL"}},
+		{name: "unknown event", input: dto.WebhookRequest{Url: "https://example.com/hooks", Events: []string{"employee.exploded"}}, want: FieldErrors{"events[0]": eventsMessage}},
+		// webhook.test hanya dikirim endpoint test, tidak bisa dilanggan
+		{name: "test event", input: dto.WebhookRequest{Url: "https://example.com/hooks", Events: []string{entity.EventWebhookTest}}, want: FieldErrors{"events[0]": eventsMessage}},
+		{name: "duplicate events", input: dto.WebhookRequest{Url: "https://example.com/hooks", Events: []string{entity.EventEmployeeCreated, entity.EventEmployeeCreated}}, want: FieldErrors{"events": "events must contain unique values"}},
+		{name: "short secret", input: dto.WebhookRequest{Url: "https://example.com/hooks", Secret: "short"}, want: FieldErrors{"secret": "secret must be at least 16 characters in length"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateWebhookCreate(&tt.input)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("ValidateWebhookCreate error = %v", err)
+				}
+				return
+			}
+			if got := fieldErrors(t, err); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ValidateWebhookCreate = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/levensspel/go-gin-template/clock"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/lifecycle"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/metrics"
+	webhookRepository "github.com/levensspel/go-gin-template/repository/webhook"
+	"github.com/levensspel/go-gin-template/signing"
+	"github.com/samber/do/v2"
+)
+
+// maxResponseBody adalah batas body response yang dibaca sebelum koneksi
+// dikembalikan ke pool. Isinya tidak dipakai.
+const maxResponseBody = 64 << 10
+
+// Deliverer mengirim delivery webhook yang menunggu dengan cara yang sama
+// seperti outbox.Dispatcher: setiap batch dikunci dalam satu transaksi,
+// sehingga aman dijalankan di banyak instance. Delivery yang gagal dikirim
+// ulang dengan jeda eksponensial dan ditandai dead setelah MaxAttempts.
+type Deliverer struct {
+	db       database.DB
+	client   *http.Client
+	clock    clock.Clock
+	config   *config.WebhookConfig
+	timeouts *config.QueryTimeoutConfig
+	metrics  *metrics.Metrics
+	logger   logger.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	stop   chan struct{}
+	done   chan struct{}
+	once   sync.Once
+}
+
+func NewDeliverer(
+	ctx context.Context,
+	db database.DB,
+	client *http.Client,
+	clock clock.Clock,
+	config *config.WebhookConfig,
+	timeouts *config.QueryTimeoutConfig,
+	metrics *metrics.Metrics,
+	logger logger.Logger,
+) *Deliverer {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Deliverer{
+		db:       db,
+		client:   client,
+		clock:    clock,
+		config:   config,
+		timeouts: timeouts,
+		metrics:  metrics,
+		logger:   logger,
+		ctx:      ctx,
+		cancel:   cancel,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// NewDelivererInject langsung menjalankan deliverer. Deliverer dihentikan
+// saat injector di-shutdown.
+func NewDelivererInject(i do.Injector) (*Deliverer, error) {
+	cluster := do.MustInvoke[*database.Cluster](i)
+	appLogger := do.MustInvoke[logger.LogHandler](i)
+	webhookConfig := config.LoadWebhookConfig()
+	deliverer := NewDeliverer(
+		do.MustInvoke[*lifecycle.Lifetime](i).Context(),
+		cluster.Writer(),
+		NewClient(webhookConfig.Timeout, webhookConfig.AllowPrivateURLs),
+		do.MustInvoke[clock.Clock](i),
+		webhookConfig,
+		config.LoadQueryTimeoutConfig(),
+		do.MustInvoke[*metrics.Metrics](i),
+		&appLogger,
+	)
+	deliverer.Start()
+	return deliverer, nil
+}
+
+func (d *Deliverer) Start() {
+	go d.run()
+}
+
+func (d *Deliverer) run() {
+	defer close(d.done)
+
+	batchSize := max(d.config.BatchSize, 1)
+	for {
+		delivered, err := d.DeliverOnce(d.ctx, batchSize)
+		if err != nil && d.ctx.Err() == nil {
+			d.logger.Warn(fmt.Sprintf("Webhook delivery failed: %v", err), helper.WebhookDeliverer)
+		}
+
+		wait := d.config.PollInterval
+		if err == nil && delivered == batchSize {
+			wait = 0
+		}
+		select {
+		case <-d.stop:
+			return
+		case <-d.ctx.Done():
+			return
+		case <-d.clock.After(wait):
+		}
+	}
+}
+
+// DeliverOnce mengirim paling banyak limit delivery dan mengembalikan jumlah
+// delivery yang diproses, baik berhasil maupun dijadwalkan ulang.
+func (d *Deliverer) DeliverOnce(ctx context.Context, limit int) (int, error) {
+	var processed int
+	err := database.WithTx(ctx, d.db, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		repo := webhookRepository.New(tx, d.timeouts)
+
+		deliveries, err := repo.ClaimDeliveries(ctx, limit)
+		if err != nil {
+			return err
+		}
+		processed = len(deliveries)
+
+		for _, delivery := range deliveries {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			statusCode, err := d.Send(ctx, delivery)
+			if err := d.record(ctx, &repo, delivery, statusCode, err); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return processed, err
+}
+
+func (d *Deliverer) record(ctx context.Context, repo webhookRepository.WebhookRepositoryInterface, delivery entity.WebhookDelivery, statusCode int, sendErr error) error {
+	if sendErr == nil {
+		d.metrics.CountWebhookDelivery(delivery.EventType, entity.WebhookDeliveryDelivered)
+		return repo.MarkDelivered(ctx, delivery.Id, statusCode)
+	}
+
+	dead := delivery.Attempts+1 >= d.config.MaxAttempts
+	if dead {
+		d.metrics.CountWebhookDelivery(delivery.EventType, entity.WebhookDeliveryDead)
+		d.logger.Warn(
+			fmt.Sprintf("Webhook delivery %d to %s is dead after %d attempts: %v", delivery.Id, delivery.Url, delivery.Attempts+1, sendErr),
+			helper.WebhookDeliverer,
+		)
+	} else {
+		d.metrics.CountWebhookDelivery(delivery.EventType, "failed")
+	}
+	return repo.MarkFailed(ctx, delivery.Id, d.retryAfter(delivery.Attempts), statusCode, sendErr.Error(), dead)
+}
+
+// Send mengirim satu delivery ke Url-nya tanpa menyimpan hasilnya.
+// statusCode 0 berarti tidak ada response; selain 2xx dianggap gagal.
+func (d *Deliverer) Send(ctx context.Context, delivery entity.WebhookDelivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.config.Timeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := d.clock.Now()
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(EventHeader, delivery.EventType)
+	request.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.Id, 10))
+	request.Header.Set(TimestampHeader, strconv.FormatInt(timestamp.Unix(), 10))
+	request.Header.Set(SignatureHeader, signing.Sign(delivery.Secret, timestamp, delivery.Payload))
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, maxResponseBody))
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return response.StatusCode, fmt.Errorf("unexpected response status %d", response.StatusCode)
+	}
+	return response.StatusCode, nil
+}
+
+// retryAfter menghitung jeda eksponensial berdasarkan jumlah percobaan
+// sebelumnya.
+func (d *Deliverer) retryAfter(attempts int) time.Duration {
+	backoff := d.config.RetryBackoff
+	for i := 0; i < attempts && backoff < d.config.MaxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, d.config.MaxRetryBackoff)
+}
+
+// Shutdown menunggu batch yang sedang berjalan selesai. Jika ctx habis lebih
+// dulu, batch dibatalkan dan delivery-nya dikirim lagi setelah restart.
+func (d *Deliverer) Shutdown(ctx context.Context) error {
+	d.once.Do(func() { close(d.stop) })
+	select {
+	case <-d.done:
+		d.cancel()
+		return nil
+	case <-ctx.Done():
+		d.cancel()
+		<-d.done
+		return ctx.Err()
+	}
+}
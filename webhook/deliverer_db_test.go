@@ -0,0 +1,133 @@
+//go:build integration
+
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levensspel/go-gin-template/clock"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/database/dbtest"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/mocks"
+	webhookRepository "github.com/levensspel/go-gin-template/repository/webhook"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// queueDelivery membuat webhook ke url dan satu delivery yang menunggu.
+func queueDelivery(t *testing.T, pool *pgxpool.Pool, url string) (webhookRepository.WebhookRepository, string) {
+	t.Helper()
+	ctx := context.Background()
+	repo := webhookRepository.New(pool, config.LoadQueryTimeoutConfig())
+	webhook, err := repo.Create(ctx, entity.Webhook{Id: "webhook-1", ManagerId: "manager-1", Url: url, Secret: testSecret, Events: []string{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	eventID := int64(1)
+	if _, err := repo.AddDelivery(ctx, entity.WebhookDelivery{
+		WebhookId: webhook.Id,
+		EventId:   &eventID,
+		EventType: entity.EventEmployeeCreated,
+		Payload:   []byte(`{"type":"employee.created"}`),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return repo, webhook.Id
+}
+
+func newDBDeliverer(pool *pgxpool.Pool, maxAttempts int) *Deliverer {
+	webhookConfig := testConfig()
+	webhookConfig.MaxAttempts = maxAttempts
+	// Jeda sangat pendek agar percobaan berikutnya bisa langsung diambil
+	webhookConfig.RetryBackoff = time.Millisecond
+	webhookConfig.MaxRetryBackoff = time.Millisecond
+	appMetrics := metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{})
+	client := NewClient(webhookConfig.Timeout, webhookConfig.AllowPrivateURLs)
+	return NewDeliverer(context.Background(), pool, client, clock.Real(), webhookConfig, config.LoadQueryTimeoutConfig(), appMetrics, mocks.Logger{})
+}
+
+// deliverUntilIdle memanggil DeliverOnce sampai tidak ada delivery yang
+// diproses, dan mengembalikan jumlah pemanggilan yang memproses delivery.
+func deliverUntilIdle(t *testing.T, d *Deliverer) int {
+	t.Helper()
+	var rounds int
+	for range 10 {
+		time.Sleep(10 * time.Millisecond)
+		processed, err := d.DeliverOnce(context.Background(), 10)
+		if err != nil {
+			t.Fatalf("DeliverOnce error = %v", err)
+		}
+		if processed == 0 {
+			return rounds
+		}
+		rounds++
+	}
+	t.Fatal("deliveries are still pending after 10 rounds")
+	return rounds
+}
+
+func TestDeliverOnceRetriesUntilDelivered(t *testing.T) {
+	pool := dbtest.New(t)
+	r := newReceiver(t, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusOK)
+	repo, webhookID := queueDelivery(t, pool, r.URL)
+
+	if rounds := deliverUntilIdle(t, newDBDeliverer(pool, 5)); rounds != 3 {
+		t.Fatalf("delivered after %d rounds, want 3", rounds)
+	}
+	deliveries, err := repo.GetDeliveries(context.Background(), webhookID, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 1 {
+		t.Fatalf("deliveries = %+v, want 1", deliveries)
+	}
+	got := deliveries[0]
+	if got.Status != entity.WebhookDeliveryDelivered || got.Attempts != 3 || got.DeliveredAt == nil ||
+		got.LastStatusCode == nil || *got.LastStatusCode != http.StatusOK || got.LastError != nil {
+		t.Fatalf("delivery = %+v", got)
+	}
+	if got := len(r.received()); got != 3 {
+		t.Fatalf("receiver got %d requests, want 3", got)
+	}
+}
+
+func TestDeliverOnceMarksDeadAfterMaxAttempts(t *testing.T) {
+	pool := dbtest.New(t)
+	r := newReceiver(t, http.StatusBadGateway)
+	repo, webhookID := queueDelivery(t, pool, r.URL)
+
+	if rounds := deliverUntilIdle(t, newDBDeliverer(pool, 2)); rounds != 2 {
+		t.Fatalf("gave up after %d rounds, want 2", rounds)
+	}
+	deliveries, err := repo.GetDeliveries(context.Background(), webhookID, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := deliveries[0]
+	if got.Status != entity.WebhookDeliveryDead || got.Attempts != 2 ||
+		got.LastStatusCode == nil || *got.LastStatusCode != http.StatusBadGateway || got.LastError == nil {
+		t.Fatalf("delivery = %+v", got)
+	}
+}
+
+func TestDeliverOnceWaitsForBackoff(t *testing.T) {
+	pool := dbtest.New(t)
+	r := newReceiver(t, http.StatusInternalServerError)
+	queueDelivery(t, pool, r.URL)
+
+	d := newDBDeliverer(pool, 5)
+	d.config.RetryBackoff = time.Hour
+	d.config.MaxRetryBackoff = time.Hour
+	if processed, err := d.DeliverOnce(context.Background(), 10); err != nil || processed != 1 {
+		t.Fatalf("DeliverOnce = %d, %v, want 1", processed, err)
+	}
+	// Delivery yang gagal baru diambil lagi setelah jedanya lewat
+	if processed, err := d.DeliverOnce(context.Background(), 10); err != nil || processed != 0 {
+		t.Fatalf("DeliverOnce during backoff = %d, %v, want 0", processed, err)
+	}
+}
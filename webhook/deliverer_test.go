@@ -0,0 +1,283 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/clock/clocktest"
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/mocks"
+	"github.com/levensspel/go-gin-template/signing"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+const testSecret = "test-secret"
+
+// receivedRequest adalah request yang diterima receiver httptest.
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// receiver menjalankan server httptest yang menjawab dengan status dari
+// statuses berurutan (status terakhir dipakai seterusnya) dan mencatat
+// request yang diterima.
+type receiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	requests []receivedRequest
+}
+
+func newReceiver(t *testing.T, statuses ...int) *receiver {
+	t.Helper()
+	r := &receiver{statuses: statuses}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		r.requests = append(r.requests, receivedRequest{header: req.Header.Clone(), body: body})
+		status := r.statuses[min(len(r.requests), len(r.statuses))-1]
+		r.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *receiver) received() []receivedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]receivedRequest(nil), r.requests...)
+}
+
+func testConfig() *config.WebhookConfig {
+	return &config.WebhookConfig{
+		Timeout:          time.Second,
+		MaxAttempts:      3,
+		RetryBackoff:     time.Second,
+		MaxRetryBackoff:  10 * time.Second,
+		PollInterval:     time.Second,
+		BatchSize:        10,
+		AllowPrivateURLs: true,
+	}
+}
+
+func newTestDeliverer(webhookConfig *config.WebhookConfig) (*Deliverer, *metrics.Metrics) {
+	appMetrics := metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{})
+	client := NewClient(webhookConfig.Timeout, webhookConfig.AllowPrivateURLs)
+	clock := clocktest.NewFake(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	return NewDeliverer(context.Background(), nil, client, clock, webhookConfig, &config.QueryTimeoutConfig{}, appMetrics, mocks.Logger{}), appMetrics
+}
+
+func testDelivery(url string) entity.WebhookDelivery {
+	return entity.WebhookDelivery{
+		Id:        42,
+		EventType: entity.EventEmployeeCreated,
+		Payload:   []byte(`{"type":"employee.created","data":{"managerId":"manager-1"}}`),
+		Url:       url,
+		Secret:    testSecret,
+	}
+}
+
+func TestSendSignsPayload(t *testing.T) {
+	r := newReceiver(t, http.StatusNoContent)
+	d, _ := newTestDeliverer(testConfig())
+	delivery := testDelivery(r.URL)
+
+	statusCode, err := d.Send(context.Background(), delivery)
+	if err != nil || statusCode != http.StatusNoContent {
+		t.Fatalf("Send = %d, %v, want %d", statusCode, err, http.StatusNoContent)
+	}
+
+	requests := r.received()
+	if len(requests) != 1 {
+		t.Fatalf("receiver got %d requests, want 1", len(requests))
+	}
+	got := requests[0]
+	if string(got.body) != string(delivery.Payload) {
+		t.Errorf("body = %s, want %s", got.body, delivery.Payload)
+	}
+	for header, want := range map[string]string{
+		"Content-Type": "application/json",
+		EventHeader:    entity.EventEmployeeCreated,
+		DeliveryHeader: strconv.FormatInt(delivery.Id, 10),
+	} {
+		if value := got.header.Get(header); value != want {
+			t.Errorf("%s = %q, want %q", header, value, want)
+		}
+	}
+	// Penerima memverifikasi signature dengan package signing, timestamp-nya
+	// dari clock Deliverer
+	if want := strconv.FormatInt(d.clock.Now().Unix(), 10); got.header.Get(TimestampHeader) != want {
+		t.Errorf("%s = %q, want %q", TimestampHeader, got.header.Get(TimestampHeader), want)
+	}
+	if _, err := signing.Verify(testSecret, got.header.Get(SignatureHeader), got.header.Get(TimestampHeader), got.body, d.clock.Now(), time.Minute); err != nil {
+		t.Fatalf("signature does not verify: %v", err)
+	}
+	if _, err := signing.Verify("other-secret", got.header.Get(SignatureHeader), got.header.Get(TimestampHeader), got.body, d.clock.Now(), time.Minute); !errors.Is(err, signing.ErrSignatureMismatch) {
+		t.Fatalf("signature verifies with another secret: %v", err)
+	}
+}
+
+func TestSendFailures(t *testing.T) {
+	redirect := httptest.NewServer(http.RedirectHandler("https://example.com/elsewhere", http.StatusFound))
+	defer redirect.Close()
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer slow.Close()
+	defer close(release)
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closedURL := closed.URL
+	closed.Close()
+
+	tests := []struct {
+		name       string
+		url        string
+		wantStatus int
+	}{
+		{name: "server error", url: newReceiver(t, http.StatusInternalServerError).URL, wantStatus: http.StatusInternalServerError},
+		{name: "client error", url: newReceiver(t, http.StatusGone).URL, wantStatus: http.StatusGone},
+		// Redirect tidak diikuti agar tidak bisa diarahkan ke alamat internal
+		{name: "redirect", url: redirect.URL, wantStatus: http.StatusFound},
+		{name: "timeout", url: slow.URL, wantStatus: 0},
+		{name: "connection refused", url: closedURL, wantStatus: 0},
+	}
+	webhookConfig := testConfig()
+	webhookConfig.Timeout = 50 * time.Millisecond
+	d, _ := newTestDeliverer(webhookConfig)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statusCode, err := d.Send(context.Background(), testDelivery(tt.url))
+			if err == nil {
+				t.Fatal("Send succeeded")
+			}
+			if statusCode != tt.wantStatus {
+				t.Fatalf("Send status = %d, want %d", statusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	d, _ := newTestDeliverer(testConfig())
+	for attempts, want := range []time.Duration{
+		time.Second,
+		2 * time.Second,
+		4 * time.Second,
+		8 * time.Second,
+		// Dibatasi MaxRetryBackoff
+		10 * time.Second,
+		10 * time.Second,
+	} {
+		if got := d.retryAfter(attempts); got != want {
+			t.Errorf("retryAfter(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+	if got := d.retryAfter(1000); got != 10*time.Second {
+		t.Errorf("retryAfter(1000) = %v, want the cap", got)
+	}
+}
+
+// markedDelivery adalah hasil satu delivery yang disimpan lewat repository.
+type markedDelivery struct {
+	delivered  bool
+	statusCode int
+	retryAfter time.Duration
+	dead       bool
+}
+
+func recordingRepository(marks *[]markedDelivery) *mocks.WebhookRepository {
+	return &mocks.WebhookRepository{
+		MarkDeliveredFunc: func(ctx context.Context, id int64, statusCode int) error {
+			*marks = append(*marks, markedDelivery{delivered: true, statusCode: statusCode})
+			return nil
+		},
+		MarkFailedFunc: func(ctx context.Context, id int64, retryAfter time.Duration, statusCode int, lastError string, dead bool) error {
+			if lastError == "" {
+				return errors.New("MarkFailed without an error message")
+			}
+			*marks = append(*marks, markedDelivery{statusCode: statusCode, retryAfter: retryAfter, dead: dead})
+			return nil
+		},
+	}
+}
+
+// TestRetriesUntilDelivered menjalankan Send dan record seperti DeliverOnce,
+// dengan receiver yang gagal dua kali sebelum menerima delivery.
+func TestRetriesUntilDelivered(t *testing.T) {
+	r := newReceiver(t, http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK)
+	d, appMetrics := newTestDeliverer(testConfig())
+	var marks []markedDelivery
+	repo := recordingRepository(&marks)
+
+	delivery := testDelivery(r.URL)
+	for attempts := range 3 {
+		delivery.Attempts = attempts
+		statusCode, err := d.Send(context.Background(), delivery)
+		if err := d.record(context.Background(), repo, delivery, statusCode, err); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []markedDelivery{
+		{statusCode: http.StatusServiceUnavailable, retryAfter: time.Second},
+		{statusCode: http.StatusBadGateway, retryAfter: 2 * time.Second},
+		{delivered: true, statusCode: http.StatusOK},
+	}
+	if len(marks) != len(want) {
+		t.Fatalf("marks = %+v, want %+v", marks, want)
+	}
+	for i := range want {
+		if marks[i] != want[i] {
+			t.Errorf("attempt %d = %+v, want %+v", i+1, marks[i], want[i])
+		}
+	}
+	if got := len(r.received()); got != 3 {
+		t.Errorf("receiver got %d requests, want 3", got)
+	}
+	for result, want := range map[string]float64{"failed": 2, entity.WebhookDeliveryDelivered: 1, entity.WebhookDeliveryDead: 0} {
+		if got := testutil.ToFloat64(appMetrics.WebhookDeliveries.WithLabelValues(entity.EventEmployeeCreated, result)); got != want {
+			t.Errorf("deliveries{result=%q} = %v, want %v", result, got, want)
+		}
+	}
+}
+
+func TestDeadAfterMaxAttempts(t *testing.T) {
+	r := newReceiver(t, http.StatusInternalServerError)
+	d, appMetrics := newTestDeliverer(testConfig())
+	var marks []markedDelivery
+	repo := recordingRepository(&marks)
+
+	delivery := testDelivery(r.URL)
+	for attempts := range 3 {
+		delivery.Attempts = attempts
+		statusCode, err := d.Send(context.Background(), delivery)
+		if err := d.record(context.Background(), repo, delivery, statusCode, err); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(marks) != 3 {
+		t.Fatalf("marks = %+v, want 3", marks)
+	}
+	for i, mark := range marks {
+		if wantDead := i == 2; mark.dead != wantDead || mark.delivered {
+			t.Errorf("attempt %d = %+v, want dead %t", i+1, mark, wantDead)
+		}
+	}
+	if got := testutil.ToFloat64(appMetrics.WebhookDeliveries.WithLabelValues(entity.EventEmployeeCreated, entity.WebhookDeliveryDead)); got != 1 {
+		t.Errorf("dead deliveries = %v, want 1", got)
+	}
+}
@@ -0,0 +1,108 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrURLScheme  = errors.New("url must use http or https")
+	ErrURLPrivate = errors.New("url must not point to a private, loopback or link-local address")
+)
+
+// blockedPrefixes adalah range yang tidak dicakup netip.Addr.IsPrivate dan
+// sejenisnya tetapi tetap tidak boleh dituju webhook.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+}
+
+// CheckURL memastikan URL webhook memakai http(s) dan host-nya tidak resolve
+// ke alamat internal. Pengecekan ini hanya untuk memberi error yang jelas
+// saat webhook dibuat; alamat yang dipakai saat kirim dicek ulang oleh
+// NewClient karena DNS bisa berubah.
+func CheckURL(ctx context.Context, rawURL string, allowPrivate bool) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return ErrURLScheme
+	}
+	if parsed.User != nil {
+		return errors.New("url must not contain credentials")
+	}
+	if parsed.Hostname() == "" {
+		return errors.New("url must have a host")
+	}
+	if allowPrivate {
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", parsed.Hostname())
+	if err != nil {
+		return fmt.Errorf("url host cannot be resolved: %w", err)
+	}
+	for _, addr := range addrs {
+		if isBlocked(addr) {
+			return ErrURLPrivate
+		}
+	}
+	return nil
+}
+
+func isBlocked(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() || addr.IsUnspecified() {
+		return true
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewClient membuat http.Client untuk pengiriman webhook. Alamat tujuan
+// dicek setelah DNS resolve, tepat sebelum koneksi dibuat, sehingga host
+// yang berganti ke alamat internal (DNS rebinding) tetap ditolak. Redirect
+// tidak diikuti; response 3xx dianggap gagal.
+func NewClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if isBlocked(addrPort.Addr()) {
+				return ErrURLPrivate
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// Proxy dari environment akan melewati pengecekan alamat di atas
+	transport.Proxy = nil
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,105 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestCheckURL(t *testing.T) {
+	tests := []struct {
+		name         string
+		url          string
+		allowPrivate bool
+		want         error
+		wantErr      bool
+	}{
+		{name: "public https", url: "https://93.184.216.34/hooks"},
+		{name: "public http with port", url: "http://93.184.216.34:8080/hooks"},
+		{name: "ftp", url: "ftp://93.184.216.34/hooks", want: ErrURLScheme},
+		{name: "no scheme", url: "93.184.216.34/hooks", want: ErrURLScheme},
+		{name: "credentials", url: "https://user:pw@93.184.216.34/hooks", wantErr: true},
+		{name: "no host", url: "https:///hooks", wantErr: true},
+		{name: "loopback", url: "http://127.0.0.1/hooks", want: ErrURLPrivate},
+		{name: "private", url: "http://10.0.0.5/hooks", want: ErrURLPrivate},
+		{name: "cloud metadata", url: "http://169.254.169.254/latest/meta-data", want: ErrURLPrivate},
+		{name: "carrier grade nat", url: "http://100.64.0.1/hooks", want: ErrURLPrivate},
+		{name: "ipv6 loopback", url: "http://[::1]/hooks", want: ErrURLPrivate},
+		{name: "ipv4 mapped ipv6", url: "http://[::ffff:192.168.1.1]/hooks", want: ErrURLPrivate},
+		{name: "unspecified", url: "http://0.0.0.0/hooks", want: ErrURLPrivate},
+		{name: "private allowed", url: "http://127.0.0.1/hooks", allowPrivate: true},
+		// Scheme tetap dicek walau alamat private diizinkan
+		{name: "scheme with private allowed", url: "file:///etc/passwd", allowPrivate: true, want: ErrURLScheme},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckURL(context.Background(), tt.url, tt.allowPrivate)
+			switch {
+			case tt.want != nil:
+				if !errors.Is(err, tt.want) {
+					t.Fatalf("CheckURL(%q) = %v, want %v", tt.url, err, tt.want)
+				}
+			case tt.wantErr:
+				if err == nil {
+					t.Fatalf("CheckURL(%q) succeeded", tt.url)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("CheckURL(%q) = %v", tt.url, err)
+				}
+			}
+		})
+	}
+}
+
+func TestIsBlocked(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"8.8.8.8":         false,
+		"2001:4860::8888": false,
+		"172.16.0.1":      true,
+		"192.168.0.1":     true,
+		"198.18.0.1":      true,
+		"224.0.0.1":       true,
+		"fd00::1":         true,
+		"fe80::1":         true,
+	} {
+		if got := isBlocked(netip.MustParseAddr(addr)); got != want {
+			t.Errorf("isBlocked(%s) = %t, want %t", addr, got, want)
+		}
+	}
+}
+
+// TestClientChecksDialedAddress memastikan URL yang lolos pengecekan saat
+// webhook dibuat tetap ditolak jika koneksinya menuju alamat internal.
+func TestClientChecksDialedAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	_, err := NewClient(time.Second, false).Get(server.URL)
+	if !errors.Is(err, ErrURLPrivate) {
+		t.Fatalf("Get loopback = %v, want %v", err, ErrURLPrivate)
+	}
+
+	response, err := NewClient(time.Second, true).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get loopback with private allowed = %v", err)
+	}
+	response.Body.Close()
+}
+
+func TestClientIgnoresProxyEnvironment(t *testing.T) {
+	// Proxy akan menerima koneksi atas nama URL internal
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("request went through the proxy: %s", r.URL)
+	}))
+	defer proxy.Close()
+	t.Setenv("HTTP_PROXY", proxy.URL)
+
+	if _, err := NewClient(time.Second, false).Get("http://10.0.0.5/hooks"); !errors.Is(err, ErrURLPrivate) {
+		t.Fatalf("Get = %v, want %v", err, ErrURLPrivate)
+	}
+}
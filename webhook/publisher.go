@@ -0,0 +1,71 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/eventbus"
+	"github.com/levensspel/go-gin-template/events"
+	"github.com/levensspel/go-gin-template/outbox"
+	webhookRepository "github.com/levensspel/go-gin-template/repository/webhook"
+	"github.com/samber/do/v2"
+)
+
+// Publisher mengantrikan event outbox ke setiap webhook manager yang
+// melanggannya. Pengiriman HTTP dilakukan Deliverer, sehingga URL yang
+// lambat atau mati tidak menahan dispatcher outbox. Event yang di-publish
+// ulang tidak membuat delivery ganda karena delivery unik per event.
+type Publisher struct {
+	repo webhookRepository.WebhookRepositoryInterface
+}
+
+func NewPublisher(repo webhookRepository.WebhookRepositoryInterface) *Publisher {
+	return &Publisher{repo: repo}
+}
+
+func NewPublisherInject(i do.Injector) (*Publisher, error) {
+	return NewPublisher(do.MustInvoke[webhookRepository.WebhookRepositoryInterface](i)), nil
+}
+
+// NewOutboxPublisherInject menggabungkan publisher pilihan EVENT_PUBLISHER
+// (lihat outbox.NewPublisherInject), Publisher, dan eventbus.Bus untuk
+// stream SSE sebagai publisher outbox.
+func NewOutboxPublisherInject(i do.Injector) (outbox.Publisher, error) {
+	publisher, err := outbox.NewPublisherInject(i)
+	if err != nil {
+		return nil, err
+	}
+	return outbox.Publishers{publisher, do.MustInvoke[*Publisher](i), do.MustInvoke[*eventbus.Bus](i)}, nil
+}
+
+func (p *Publisher) Publish(ctx context.Context, subject string, payload []byte) error {
+	var message events.Envelope
+	if err := json.Unmarshal(payload, &message); err != nil {
+		return err
+	}
+	// Semua event employee.* dan department.* membawa managerId
+	var data struct {
+		ManagerID string `json:"managerId"`
+	}
+	if err := json.Unmarshal(message.Data, &data); err != nil || data.ManagerID == "" {
+		return nil
+	}
+
+	webhooks, err := p.repo.Matching(ctx, data.ManagerID, subject)
+	if err != nil {
+		return err
+	}
+	for _, webhook := range webhooks {
+		_, err := p.repo.AddDelivery(ctx, entity.WebhookDelivery{
+			WebhookId: webhook.Id,
+			EventId:   &message.ID,
+			EventType: subject,
+			Payload:   payload,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
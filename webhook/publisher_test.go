@@ -0,0 +1,92 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/levensspel/go-gin-template/entity"
+	"github.com/levensspel/go-gin-template/events"
+	"github.com/levensspel/go-gin-template/mocks"
+)
+
+func envelope(t *testing.T, id int64, data any) []byte {
+	t.Helper()
+	raw, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := json.Marshal(events.Envelope{ID: id, Type: entity.EventEmployeeCreated, Data: raw})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return payload
+}
+
+func TestPublishQueuesMatchingWebhooks(t *testing.T) {
+	var matched [2]string
+	var deliveries []entity.WebhookDelivery
+	repo := &mocks.WebhookRepository{
+		MatchingFunc: func(ctx context.Context, managerId, eventType string) ([]entity.Webhook, error) {
+			matched = [2]string{managerId, eventType}
+			return []entity.Webhook{{Id: "webhook-1"}, {Id: "webhook-2"}}, nil
+		},
+		AddDeliveryFunc: func(ctx context.Context, delivery entity.WebhookDelivery) (int64, error) {
+			deliveries = append(deliveries, delivery)
+			return int64(len(deliveries)), nil
+		},
+	}
+	payload := envelope(t, 7, map[string]string{"managerId": "manager-1"})
+
+	if err := NewPublisher(repo).Publish(context.Background(), entity.EventEmployeeCreated, payload); err != nil {
+		t.Fatalf("Publish error = %v", err)
+	}
+	if matched != [2]string{"manager-1", entity.EventEmployeeCreated} {
+		t.Fatalf("Matching(%q, %q)", matched[0], matched[1])
+	}
+	var webhookIDs []string
+	for _, delivery := range deliveries {
+		webhookIDs = append(webhookIDs, delivery.WebhookId)
+		// Payload dikirim apa adanya; event id membuat publish ulang idempotent
+		if delivery.EventId == nil || *delivery.EventId != 7 || string(delivery.Payload) != string(payload) || delivery.EventType != entity.EventEmployeeCreated {
+			t.Errorf("delivery = %+v", delivery)
+		}
+	}
+	if !slices.Equal(webhookIDs, []string{"webhook-1", "webhook-2"}) {
+		t.Fatalf("deliveries for %v, want both webhooks", webhookIDs)
+	}
+}
+
+func TestPublishSkipsEventsWithoutManager(t *testing.T) {
+	// Repository tanpa mock: query apa pun mengembalikan ErrNotMocked
+	publisher := NewPublisher(&mocks.WebhookRepository{})
+	for name, data := range map[string]any{
+		"no managerId":    map[string]string{"identityNumber": "EMP-1"},
+		"empty managerId": map[string]string{"managerId": ""},
+		"not an object":   []string{"manager-1"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := publisher.Publish(context.Background(), entity.EventEmployeeCreated, envelope(t, 1, data)); err != nil {
+				t.Fatalf("Publish error = %v", err)
+			}
+		})
+	}
+}
+
+func TestPublishErrors(t *testing.T) {
+	errLookup := errors.New("lookup failed")
+	publisher := NewPublisher(&mocks.WebhookRepository{
+		MatchingFunc: func(ctx context.Context, managerId, eventType string) ([]entity.Webhook, error) {
+			return nil, errLookup
+		},
+	})
+	// Error dikembalikan agar outbox mengirim ulang event-nya
+	if err := publisher.Publish(context.Background(), entity.EventEmployeeCreated, envelope(t, 1, map[string]string{"managerId": "manager-1"})); !errors.Is(err, errLookup) {
+		t.Fatalf("Publish error = %v, want %v", err, errLookup)
+	}
+	if err := publisher.Publish(context.Background(), entity.EventEmployeeCreated, []byte("not json")); err == nil {
+		t.Fatal("Publish accepted a payload that is not an envelope")
+	}
+}
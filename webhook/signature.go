@@ -0,0 +1,28 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Header yang dikirim bersama setiap delivery. Penerima memverifikasi
+// SignatureHeader dengan menghitung ulang HMAC-SHA256 dari
+// "<TimestampHeader>.<body>" memakai secret webhook, lalu menolak timestamp
+// yang terlalu lama untuk mencegah replay. Lihat package signing, yang bisa
+// dipakai langsung oleh penerima yang ditulis dengan Go.
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// NewSecret membuat secret acak 32 byte dalam hex, dipakai jika client tidak
+// mengirim secret sendiri.
+func NewSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
@@ -0,0 +1,212 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/helper"
+	"github.com/levensspel/go-gin-template/logger"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/samber/do/v2"
+)
+
+var (
+	// ErrQueueFull dikembalikan Submit jika antrean penuh. Caller sebaiknya
+	// menurunkan fitur (mis. melewati thumbnail) atau membalas 503.
+	ErrQueueFull  = errors.New("worker queue is full")
+	ErrPoolClosed = errors.New("worker pool is shut down")
+)
+
+func init() {
+	helper.Register(ErrQueueFull, http.StatusServiceUnavailable, "ErrQueueFull")
+	helper.Register(ErrPoolClosed, http.StatusServiceUnavailable, "ErrServiceUnavailable")
+}
+
+// Task adalah satu pekerjaan yang dijalankan di background.
+type Task struct {
+	// Name dipakai sebagai label metric, jadi harus berupa nama jenis task
+	// (mis. "thumbnail"), bukan id.
+	Name string
+	Run  func(ctx context.Context) error
+	// Timeout 0 berarti config.WorkerConfig.TaskTimeout.
+	Timeout time.Duration
+}
+
+// Pool menjalankan Task dengan jumlah worker dan antrean yang terbatas.
+// Panic di dalam task di-recover dan dilaporkan tanpa menghentikan worker.
+//
+// Task di antrean hanya ada di memori: task yang belum berjalan saat
+// Shutdown kehabisan waktu dibuang dan dilaporkan. Pekerjaan yang tidak
+// boleh hilang harus ditulis ke database (mis. outbox) lebih dulu.
+type Pool struct {
+	config  *config.WorkerConfig
+	metrics *metrics.Metrics
+	logger  logger.Logger
+
+	queue chan Task
+	// mu menjaga agar Submit tidak mengirim ke queue yang sudah ditutup
+	mu     sync.RWMutex
+	closed bool
+
+	// ctx adalah induk context task, dibatalkan jika Shutdown kehabisan
+	// waktu. Sengaja tidak diturunkan dari lifecycle.Lifetime, yang sudah
+	// dibatalkan sebelum Shutdown dipanggil.
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	droppedMu sync.Mutex
+	dropped   map[string]int
+}
+
+func New(config *config.WorkerConfig, metrics *metrics.Metrics, logger logger.Logger) *Pool {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Pool{
+		config:  config,
+		metrics: metrics,
+		logger:  logger,
+		queue:   make(chan Task, max(config.QueueDepth, 0)),
+		ctx:     ctx,
+		cancel:  cancel,
+		dropped: make(map[string]int),
+	}
+}
+
+// NewInject langsung menjalankan worker. Pool di-drain saat injector
+// di-shutdown.
+func NewInject(i do.Injector) (*Pool, error) {
+	appLogger := do.MustInvoke[logger.LogHandler](i)
+	pool := New(config.LoadWorkerConfig(), do.MustInvoke[*metrics.Metrics](i), &appLogger)
+	pool.Start()
+	return pool, nil
+}
+
+func (p *Pool) Start() {
+	for range max(p.config.Size, 1) {
+		p.wg.Add(1)
+		go p.work()
+	}
+}
+
+// Submit mengantrikan task tanpa menunggu. ErrQueueFull dikembalikan jika
+// antrean penuh dan ErrPoolClosed setelah Shutdown dipanggil.
+func (p *Pool) Submit(task Task) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		p.metrics.CountWorkerTaskDropped(task.Name, "closed")
+		return ErrPoolClosed
+	}
+
+	select {
+	case p.queue <- task:
+		p.metrics.WorkerQueueDepth.Set(float64(len(p.queue)))
+		return nil
+	default:
+		p.metrics.CountWorkerTaskDropped(task.Name, "queue_full")
+		return ErrQueueFull
+	}
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+	for task := range p.queue {
+		p.metrics.WorkerQueueDepth.Set(float64(len(p.queue)))
+		// Shutdown kehabisan waktu, sisa antrean dibuang
+		if p.ctx.Err() != nil {
+			p.drop(task)
+			continue
+		}
+		p.run(task)
+	}
+}
+
+func (p *Pool) run(task Task) {
+	timeout := task.Timeout
+	if timeout <= 0 {
+		timeout = p.config.TaskTimeout
+	}
+	ctx, cancel := context.WithTimeout(p.ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	result := "ok"
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			result = "panic"
+			p.logger.Error(
+				fmt.Sprintf("Task %s panicked: %v", task.Name, recovered),
+				helper.WorkerPool,
+				string(debug.Stack()),
+			)
+		}
+		p.metrics.ObserveWorkerTask(task.Name, result, time.Since(start))
+	}()
+
+	if err := task.Run(ctx); err != nil {
+		result = "error"
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() != nil {
+			result = "timeout"
+		}
+		p.logger.Warn(fmt.Sprintf("Task %s failed: %v", task.Name, err), helper.WorkerPool)
+	}
+}
+
+func (p *Pool) drop(task Task) {
+	p.metrics.CountWorkerTaskDropped(task.Name, "shutdown")
+	p.droppedMu.Lock()
+	p.dropped[task.Name]++
+	p.droppedMu.Unlock()
+}
+
+// Shutdown menolak task baru lalu menunggu antrean habis. Jika ctx habis
+// lebih dulu, task yang sedang berjalan dibatalkan lewat context-nya dan
+// task yang belum berjalan dibuang; jumlahnya per nama task dicatat di log
+// dan dikembalikan sebagai error.
+func (p *Pool) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		<-done
+	}
+
+	p.droppedMu.Lock()
+	defer p.droppedMu.Unlock()
+	if len(p.dropped) == 0 {
+		return ctx.Err()
+	}
+	names := make([]string, 0, len(p.dropped))
+	total := 0
+	for name, count := range p.dropped {
+		names = append(names, fmt.Sprintf("%s=%d", name, count))
+		total += count
+	}
+	sort.Strings(names)
+	err := fmt.Errorf("worker pool dropped %d pending tasks (%s): %w", total, strings.Join(names, ", "), ctx.Err())
+	p.logger.Warn(err.Error(), helper.WorkerPool)
+	return err
+}
@@ -0,0 +1,279 @@
+package worker_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/levensspel/go-gin-template/config"
+	"github.com/levensspel/go-gin-template/metrics"
+	"github.com/levensspel/go-gin-template/mocks"
+	"github.com/levensspel/go-gin-template/worker"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func newTestPool(size, queueDepth int) (*worker.Pool, *metrics.Metrics) {
+	appMetrics := metrics.New(prometheus.NewRegistry(), &config.MetricsConfig{})
+	pool := worker.New(&config.WorkerConfig{Size: size, QueueDepth: queueDepth, TaskTimeout: time.Second}, appMetrics, mocks.Logger{})
+	pool.Start()
+	return pool, appMetrics
+}
+
+func shutdown(t *testing.T, pool *worker.Pool) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := pool.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown error = %v", err)
+	}
+}
+
+// taskCount mengembalikan jumlah task name yang selesai dengan result.
+func taskCount(t *testing.T, m *metrics.Metrics, name, result string) uint64 {
+	t.Helper()
+	var metric dto.Metric
+	if err := m.WorkerTaskDuration.WithLabelValues(name, result).(prometheus.Metric).Write(&metric); err != nil {
+		t.Fatal(err)
+	}
+	return metric.GetHistogram().GetSampleCount()
+}
+
+// blockingTask berjalan sampai release ditutup atau context-nya habis.
+func blockingTask(name string, started chan<- struct{}, release <-chan struct{}) worker.Task {
+	return worker.Task{Name: name, Run: func(ctx context.Context) error {
+		if started != nil {
+			started <- struct{}{}
+		}
+		select {
+		case <-release:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}}
+}
+
+func TestShutdownDrainsQueue(t *testing.T) {
+	pool, appMetrics := newTestPool(2, 50)
+	var ran atomic.Int64
+	for range 50 {
+		if err := pool.Submit(worker.Task{Name: "report", Run: func(ctx context.Context) error {
+			ran.Add(1)
+			return nil
+		}}); err != nil {
+			t.Fatalf("Submit error = %v", err)
+		}
+	}
+
+	shutdown(t, pool)
+	if got := ran.Load(); got != 50 {
+		t.Fatalf("ran %d tasks, want 50", got)
+	}
+	if got := taskCount(t, appMetrics, "report", "ok"); got != 50 {
+		t.Fatalf("ok tasks = %d, want 50", got)
+	}
+	if got := testutil.ToFloat64(appMetrics.WorkerQueueDepth); got != 0 {
+		t.Fatalf("queue depth after drain = %v, want 0", got)
+	}
+}
+
+func TestSubmitFailsFastWhenQueueIsFull(t *testing.T) {
+	pool, appMetrics := newTestPool(1, 1)
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	defer func() {
+		close(release)
+		shutdown(t, pool)
+	}()
+
+	if err := pool.Submit(blockingTask("thumbnail", started, release)); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	// Worker sibuk, satu task boleh menunggu di antrean
+	if err := pool.Submit(blockingTask("thumbnail", nil, release)); err != nil {
+		t.Fatalf("Submit to a queue with room error = %v", err)
+	}
+	if got := testutil.ToFloat64(appMetrics.WorkerQueueDepth); got != 1 {
+		t.Errorf("queue depth = %v, want 1", got)
+	}
+
+	done := make(chan error)
+	go func() { done <- pool.Submit(blockingTask("thumbnail", nil, release)) }()
+	select {
+	case err := <-done:
+		if !errors.Is(err, worker.ErrQueueFull) {
+			t.Fatalf("Submit to a full queue error = %v, want %v", err, worker.ErrQueueFull)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Submit blocked on a full queue")
+	}
+	if got := testutil.ToFloat64(appMetrics.WorkerTasksDropped.WithLabelValues("thumbnail", "queue_full")); got != 1 {
+		t.Errorf("queue_full drops = %v, want 1", got)
+	}
+}
+
+func TestSubmitAfterShutdown(t *testing.T) {
+	pool, appMetrics := newTestPool(1, 1)
+	shutdown(t, pool)
+
+	err := pool.Submit(worker.Task{Name: "email", Run: func(ctx context.Context) error { return nil }})
+	if !errors.Is(err, worker.ErrPoolClosed) {
+		t.Fatalf("Submit after Shutdown error = %v, want %v", err, worker.ErrPoolClosed)
+	}
+	if got := testutil.ToFloat64(appMetrics.WorkerTasksDropped.WithLabelValues("email", "closed")); got != 1 {
+		t.Errorf("closed drops = %v, want 1", got)
+	}
+	// Shutdown kedua tidak menutup queue lagi
+	shutdown(t, pool)
+}
+
+func TestPanicIsIsolated(t *testing.T) {
+	pool, appMetrics := newTestPool(1, 10)
+	var ran atomic.Bool
+	if err := pool.Submit(worker.Task{Name: "bad", Run: func(ctx context.Context) error { panic("boom") }}); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.Submit(worker.Task{Name: "good", Run: func(ctx context.Context) error {
+		ran.Store(true)
+		return nil
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	shutdown(t, pool)
+	// Worker yang sama tetap menjalankan task berikutnya
+	if !ran.Load() {
+		t.Fatal("task after the panic did not run")
+	}
+	if got := taskCount(t, appMetrics, "bad", "panic"); got != 1 {
+		t.Errorf("panicked tasks = %d, want 1", got)
+	}
+}
+
+func TestTaskResults(t *testing.T) {
+	pool, appMetrics := newTestPool(2, 10)
+	errTask := errors.New("send failed")
+	var deadline time.Duration
+	for _, task := range []worker.Task{
+		{Name: "failing", Run: func(ctx context.Context) error { return errTask }},
+		{Name: "slow", Timeout: 20 * time.Millisecond, Run: func(ctx context.Context) error {
+			if d, ok := ctx.Deadline(); ok {
+				deadline = time.Until(d)
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		}},
+		// Error deadline milik task sendiri, bukan timeout pool
+		{Name: "upstream", Run: func(ctx context.Context) error { return context.DeadlineExceeded }},
+	} {
+		if err := pool.Submit(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	shutdown(t, pool)
+
+	for _, tt := range []struct{ name, result string }{
+		{name: "failing", result: "error"},
+		{name: "slow", result: "timeout"},
+		{name: "upstream", result: "error"},
+	} {
+		if got := taskCount(t, appMetrics, tt.name, tt.result); got != 1 {
+			t.Errorf("%s %s = %d, want 1", tt.name, tt.result, got)
+		}
+	}
+	if deadline <= 0 || deadline > 20*time.Millisecond {
+		t.Errorf("slow task deadline = %v, want at most its own 20ms timeout", deadline)
+	}
+}
+
+func TestShutdownReportsDroppedTasks(t *testing.T) {
+	pool, appMetrics := newTestPool(1, 10)
+	started := make(chan struct{}, 1)
+	var cancelled atomic.Bool
+	if err := pool.Submit(worker.Task{Name: "purge", Run: func(ctx context.Context) error {
+		started <- struct{}{}
+		<-ctx.Done()
+		cancelled.Store(true)
+		return ctx.Err()
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	var ran atomic.Int64
+	for _, name := range []string{"email", "email", "thumbnail"} {
+		if err := pool.Submit(worker.Task{Name: name, Run: func(ctx context.Context) error {
+			ran.Add(1)
+			return nil
+		}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := pool.Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if !strings.Contains(err.Error(), "dropped 3 pending tasks (email=2, thumbnail=1)") {
+		t.Errorf("Shutdown error = %q, want the dropped counts", err)
+	}
+	// Task yang sedang berjalan dibatalkan, sisanya tidak pernah dijalankan
+	if !cancelled.Load() || ran.Load() != 0 {
+		t.Errorf("cancelled = %t, ran = %d, want the running task cancelled and none started", cancelled.Load(), ran.Load())
+	}
+	if got := testutil.ToFloat64(appMetrics.WorkerTasksDropped.WithLabelValues("email", "shutdown")); got != 2 {
+		t.Errorf("shutdown drops = %v, want 2", got)
+	}
+}
+
+// TestSubmitDuringShutdownUnderLoad dijalankan dengan -race: setiap task yang
+// diterima Submit harus berjalan tepat sekali, walau Shutdown dipanggil
+// di tengah Submit dari banyak goroutine.
+func TestSubmitDuringShutdownUnderLoad(t *testing.T) {
+	pool, _ := newTestPool(8, 64)
+	var accepted, rejected, ran atomic.Int64
+	var wg sync.WaitGroup
+	startShutdown := make(chan struct{})
+	for g := range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 500 {
+				if g == 0 && i == 250 {
+					close(startShutdown)
+				}
+				err := pool.Submit(worker.Task{Name: "load", Run: func(ctx context.Context) error {
+					ran.Add(1)
+					return nil
+				}})
+				switch {
+				case err == nil:
+					accepted.Add(1)
+				case errors.Is(err, worker.ErrQueueFull), errors.Is(err, worker.ErrPoolClosed):
+					rejected.Add(1)
+				default:
+					t.Errorf("Submit error = %v", err)
+				}
+			}
+		}()
+	}
+
+	<-startShutdown
+	shutdown(t, pool)
+	wg.Wait()
+
+	if accepted.Load()+rejected.Load() != 16*500 {
+		t.Fatalf("accepted %d + rejected %d, want %d", accepted.Load(), rejected.Load(), 16*500)
+	}
+	if ran.Load() != accepted.Load() {
+		t.Fatalf("ran %d tasks, accepted %d", ran.Load(), accepted.Load())
+	}
+}